@@ -0,0 +1,84 @@
+package anim
+
+import (
+	"slices"
+	"time"
+
+	"github.com/gogpu/ui/core"
+)
+
+// Clock advances the animations running on it, once a frame for the
+// clock of a window.
+type Clock struct {
+	ctx     *core.Context
+	now     time.Time
+	running []animation
+	ticking bool // Added as a ticker of ctx.
+}
+
+// animation is what runs on a clock: a Controller or a SpringValue.
+type animation interface {
+	// advance moves the animation to now, all the way to its end with
+	// reduced motion.
+	advance(now time.Time, reduced bool)
+	active() bool
+}
+
+type clockKey struct{}
+
+// ClockOf returns the clock of the frames of ctx, which ticks at the start
+// of each frame while an animation runs on it. The window runtime makes
+// it with the context, so that every widget finds the same one.
+func ClockOf(ctx *core.Context) *Clock {
+	if c, ok := ctx.Value(clockKey{}).(*Clock); ok {
+		return c
+	}
+	c := &Clock{ctx: ctx, now: ctx.Now()}
+	ctx.SetValue(clockKey{}, c)
+	return c
+}
+
+// NewClock returns a clock advanced only by Tick, for animating outside
+// of a window, such as in tests and offscreen renders.
+func NewClock() *Clock {
+	return &Clock{}
+}
+
+// Now returns the time of the last tick.
+func (c *Clock) Now() time.Time {
+	return c.now
+}
+
+// Running reports whether an animation is running on the clock.
+func (c *Clock) Running() bool {
+	return slices.ContainsFunc(c.running, animation.active)
+}
+
+// Tick advances the animations running on the clock to now.
+func (c *Clock) Tick(now time.Time) {
+	c.now = now
+	reduced := c.ctx != nil && c.ctx.ReducedMotion()
+	// Animations started by the callbacks of others join at the next
+	// tick, and those stopped by them are left out of this one.
+	for _, a := range slices.Clone(c.running) {
+		if a.active() {
+			a.advance(now, reduced)
+		}
+	}
+	c.running = slices.DeleteFunc(c.running, func(a animation) bool { return !a.active() })
+}
+
+// start adds a to the running animations.
+func (c *Clock) start(a animation) {
+	if !slices.Contains(c.running, a) {
+		c.running = append(c.running, a)
+	}
+	if c.ctx != nil && !c.ticking {
+		c.ticking = true
+		c.ctx.AddTicker(func(now time.Time) bool {
+			c.Tick(now)
+			c.ticking = c.Running()
+			return c.ticking
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package anim
+
+import "time"
+
+// Controller animates a progress from 0 to 1 and back over a duration.
+// Its Value is the progress eased by its curve, and Tweens map the value
+// to what is animated.
+//
+// An animation started between frames starts at the next one, so that
+// the time spent waiting for it is not skipped.
+type Controller struct {
+	clock *Clock
+	dur   time.Duration
+	curve Curve
+
+	t        float32 // The progress.
+	from, to float32
+	resume   float32 // Of the first cycle of Repeat already done.
+	begin    time.Time
+	running  bool
+	repeat   bool
+	mirror   bool
+
+	listeners map[int]func(value float32)
+	next      int
+	onEnd     func()
+}
+
+// NewController returns a controller at 0 running on clock, taking d to
+// go from 0 to 1, with the Linear curve.
+func NewController(clock *Clock, d time.Duration) *Controller {
+	return &Controller{clock: clock, dur: d, curve: Linear}
+}
+
+// Curve sets the curve easing the progress.
+func (c *Controller) Curve(curve Curve) *Controller {
+	if curve == nil {
+		curve = Linear
+	}
+	c.curve = curve
+	c.notify()
+	return c
+}
+
+// Duration sets the time going from 0 to 1 takes. A running animation
+// keeps its own.
+func (c *Controller) Duration(d time.Duration) *Controller {
+	c.dur = d
+	return c
+}
+
+// OnEnd sets fn to be called when an animation reaches where it was
+// going, and not when it is stopped.
+func (c *Controller) OnEnd(fn func()) *Controller {
+	c.onEnd = fn
+	return c
+}
+
+// Listen calls fn with the value at every change of it, until the
+// returned function is called.
+func (c *Controller) Listen(fn func(value float32)) (cancel func()) {
+	if c.listeners == nil {
+		c.listeners = make(map[int]func(float32))
+	}
+	id := c.next
+	c.next++
+	c.listeners[id] = fn
+	return func() { delete(c.listeners, id) }
+}
+
+// Progress returns how far the controller is from 0 to 1, before the
+// curve.
+func (c *Controller) Progress() float32 {
+	return c.t
+}
+
+// Value returns the progress eased by the curve.
+func (c *Controller) Value() float32 {
+	return c.curve(c.t)
+}
+
+// IsRunning reports whether an animation is running.
+func (c *Controller) IsRunning() bool {
+	return c.running
+}
+
+// Forward animates the progress to 1.
+func (c *Controller) Forward() {
+	c.AnimateTo(1)
+}
+
+// Reverse animates the progress to 0.
+func (c *Controller) Reverse() {
+	c.AnimateTo(0)
+}
+
+// Toggle animates the progress to 1 unless it is at or going to 1, and
+// to 0 then.
+func (c *Controller) Toggle() {
+	if (c.running && c.to == 1) || (!c.running && c.t == 1) {
+		c.Reverse()
+	} else {
+		c.Forward()
+	}
+}
+
+// AnimateTo animates the progress from where it is to target, in the part
+// of the duration the distance is of the whole way.
+func (c *Controller) AnimateTo(target float32) {
+	target = min(max(target, 0), 1)
+	c.repeat, c.from, c.to, c.resume = false, c.t, target, 0
+	c.run()
+}
+
+// Repeat animates the progress from 0 to 1 over and over, from where it
+// is, and back from 1 to 0 in between with mirror, until Stop is called,
+// for activity indicators and pulses.
+func (c *Controller) Repeat(mirror bool) {
+	c.repeat, c.mirror, c.from, c.to, c.resume = true, mirror, 0, 1, c.t
+	c.run()
+}
+
+func (c *Controller) run() {
+	c.begin = time.Time{}
+	c.running = true
+	c.clock.start(c)
+}
+
+// Stop stops the animation where it is.
+func (c *Controller) Stop() {
+	c.running = false
+}
+
+// SetProgress stops the animation and moves the progress to p, such as to
+// follow a drag.
+func (c *Controller) SetProgress(p float32) {
+	c.running = false
+	if p = min(max(p, 0), 1); p != c.t {
+		c.t = p
+		c.notify()
+	}
+}
+
+// advance moves the animation to now.
+func (c *Controller) advance(now time.Time, reduced bool) {
+	if c.begin.IsZero() {
+		c.begin = now
+	}
+	span := time.Duration(float32(c.dur) * abs(c.to-c.from))
+	elapsed := now.Sub(c.begin) + time.Duration(float32(span)*c.resume)
+	if span <= 0 || (reduced && !c.repeat) || (elapsed >= span && !c.repeat) {
+		c.t, c.running = c.to, false
+		c.notify()
+		if c.onEnd != nil {
+			c.onEnd()
+		}
+		return
+	}
+	if elapsed >= span {
+		n := elapsed / span
+		c.begin = c.begin.Add(n * span)
+		elapsed -= n * span
+		if c.mirror && n%2 == 1 {
+			c.from, c.to = c.to, c.from
+		}
+	}
+	c.t = c.from + (c.to-c.from)*float32(elapsed)/float32(span)
+	c.notify()
+}
+
+func (c *Controller) active() bool {
+	return c.running
+}
+
+func (c *Controller) notify() {
+	if len(c.listeners) == 0 {
+		return
+	}
+	v := c.Value()
+	for _, fn := range c.listeners {
+		fn(v)
+	}
+}
+
+func abs(v float32) float32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,155 @@
+package anim
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogpu/ui/core"
+)
+
+// at returns the time ms milliseconds after the first tick of the tests.
+func at(ms int) time.Time {
+	return time.Unix(0, 0).Add(time.Duration(ms) * time.Millisecond)
+}
+
+func TestController(t *testing.T) {
+	type step struct {
+		ms      int
+		action  func(c *Controller) // Before the tick, if not nil.
+		want    float32             // The progress after the tick.
+		running bool
+	}
+	tests := []struct {
+		name  string
+		start func(c *Controller)
+		steps []step
+		ended int
+	}{
+		{"forward", (*Controller).Forward, []step{
+			{0, nil, 0, true},
+			{50, nil, 0.5, true},
+			{100, nil, 1, false},
+			{150, nil, 1, false},
+		}, 1},
+		{"reverse from halfway", func(c *Controller) { c.SetProgress(0.5); c.Reverse() }, []step{
+			{0, nil, 0.5, true},
+			{25, nil, 0.25, true},
+			{50, nil, 0, false},
+		}, 1},
+		{"turned back", (*Controller).Forward, []step{
+			{0, nil, 0, true},
+			{50, nil, 0.5, true},
+			// The way back takes the part of the duration it is of the
+			// whole way.
+			{50, (*Controller).Toggle, 0.5, true},
+			{75, nil, 0.25, true},
+			{100, nil, 0, false},
+		}, 1},
+		{"stopped", (*Controller).Forward, []step{
+			{0, nil, 0, true},
+			{40, nil, 0.4, true},
+			{50, (*Controller).Stop, 0.4, false},
+			{100, nil, 0.4, false},
+		}, 0},
+		{"to a target", func(c *Controller) { c.AnimateTo(0.5) }, []step{
+			{0, nil, 0, true},
+			{25, nil, 0.25, true},
+			{50, nil, 0.5, false},
+		}, 1},
+		{"repeating", func(c *Controller) { c.Repeat(false) }, []step{
+			{0, nil, 0, true},
+			{50, nil, 0.5, true},
+			{130, nil, 0.3, true},
+			{250, nil, 0.5, true},
+		}, 0},
+		{"mirrored", func(c *Controller) { c.Repeat(true) }, []step{
+			{0, nil, 0, true},
+			{130, nil, 0.7, true},
+			{250, nil, 0.5, true},
+		}, 0},
+		{"set while running", (*Controller).Forward, []step{
+			{0, nil, 0, true},
+			{50, func(c *Controller) { c.SetProgress(0.9) }, 0.9, false},
+		}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := NewClock()
+			c := NewController(clock, 100*time.Millisecond)
+			ended := 0
+			c.OnEnd(func() { ended++ })
+			tt.start(c)
+			for _, s := range tt.steps {
+				if s.action != nil {
+					s.action(c)
+				}
+				clock.Tick(at(s.ms))
+				if got := c.Progress(); !near(got, s.want) || c.IsRunning() != s.running {
+					t.Fatalf("at %dms: progress %v, running %v, want %v, %v", s.ms, got, c.IsRunning(), s.want, s.running)
+				}
+			}
+			if ended != tt.ended {
+				t.Errorf("ended %d times, want %d", ended, tt.ended)
+			}
+		})
+	}
+}
+
+func TestControllerValue(t *testing.T) {
+	clock := NewClock()
+	c := NewController(clock, 100*time.Millisecond).Curve(EaseIn)
+	var heard []float32
+	cancel := c.Listen(func(v float32) { heard = append(heard, v) })
+	c.SetProgress(0.5)
+	if c.Value() != 0.125 || len(heard) != 1 || heard[0] != 0.125 {
+		t.Errorf("value %v, heard %v, want 0.125 eased", c.Value(), heard)
+	}
+	// Changing the curve changes the value.
+	c.Curve(nil)
+	if c.Value() != 0.5 || len(heard) != 2 {
+		t.Errorf("with no curve value %v, heard %v, want 0.5", c.Value(), heard)
+	}
+	cancel()
+	c.SetProgress(1)
+	if len(heard) != 2 {
+		t.Errorf("heard %v after cancel", heard)
+	}
+	// Setting the progress it has is not a change.
+	c.Listen(func(v float32) { heard = append(heard, v) })
+	c.SetProgress(1)
+	if len(heard) != 2 {
+		t.Errorf("heard %v setting the same progress", heard)
+	}
+}
+
+func TestClock(t *testing.T) {
+	ctx := core.NewContext()
+	clock := ClockOf(ctx)
+	if ClockOf(ctx) != clock {
+		t.Fatal("ClockOf returned another clock for the same context")
+	}
+	c := NewController(clock, 100*time.Millisecond)
+	c.Forward()
+	if !clock.Running() || !ctx.Ticking() {
+		t.Fatalf("running %v, ticking %v, want both once an animation starts", clock.Running(), ctx.Ticking())
+	}
+	ctx.RunTickers(at(0))
+	ctx.RunTickers(at(50))
+	if c.Progress() != 0.5 || clock.Now() != at(50) {
+		t.Errorf("progress %v at %v, want 0.5 at 50ms", c.Progress(), clock.Now())
+	}
+	ctx.RunTickers(at(100))
+	if clock.Running() || ctx.Ticking() {
+		t.Errorf("running %v, ticking %v after the end", clock.Running(), ctx.Ticking())
+	}
+
+	// With reduced motion animations jump to their end.
+	ctx.SetReducedMotion(true)
+	ended := false
+	c.OnEnd(func() { ended = true })
+	c.Reverse()
+	ctx.RunTickers(at(200))
+	if c.Progress() != 0 || !ended {
+		t.Errorf("with reduced motion progress %v, ended %v, want 0 at once", c.Progress(), ended)
+	}
+}
@@ -0,0 +1,129 @@
+package anim
+
+import "math"
+
+// Curve eases the progress of an animation: it maps progress from 0 to 1
+// to a value that starts at 0 and ends at 1, and may pass beyond them on
+// the way, as Overshoot does.
+type Curve func(t float32) float32
+
+// Standard curves.
+var (
+	// Linear moves at a constant speed.
+	Linear Curve = func(t float32) float32 { return t }
+
+	// EaseIn starts slowly and ends at full speed, for what leaves the
+	// screen.
+	EaseIn Curve = func(t float32) float32 { return t * t * t }
+
+	// EaseOut starts at full speed and slows into the end, for what
+	// enters the screen.
+	EaseOut Curve = func(t float32) float32 {
+		q := 1 - t
+		return 1 - q*q*q
+	}
+
+	// EaseInOut speeds up and slows down again, for what moves on the
+	// screen.
+	EaseInOut Curve = func(t float32) float32 {
+		if t < 0.5 {
+			return 4 * t * t * t
+		}
+		q := 2 - 2*t
+		return 1 - q*q*q/2
+	}
+
+	// Standard is the standard easing of Material Design, for most
+	// transitions.
+	Standard = CubicBezier(0.2, 0, 0, 1)
+
+	// Emphasized is the emphasized decelerating easing of Material
+	// Design, for transitions that draw attention.
+	Emphasized = CubicBezier(0.05, 0.7, 0.1, 1)
+
+	// Overshoot passes the end and settles back into it.
+	Overshoot Curve = func(t float32) float32 {
+		const s = 1.70158
+		q := t - 1
+		return q*q*((s+1)*q+s) + 1
+	}
+
+	// Bounce reaches the end and bounces on it, as a dropped ball does.
+	Bounce Curve = func(t float32) float32 {
+		const n, d = 7.5625, 2.75
+		switch {
+		case t < 1/d:
+			return n * t * t
+		case t < 2/d:
+			t -= 1.5 / d
+			return n*t*t + 0.75
+		case t < 2.5/d:
+			t -= 2.25 / d
+			return n*t*t + 0.9375
+		default:
+			t -= 2.625 / d
+			return n*t*t + 0.984375
+		}
+	}
+)
+
+// CubicBezier returns the curve of the CSS cubic-bezier timing function
+// through the control points (x1, y1) and (x2, y2), with x1 and x2 from 0
+// to 1.
+func CubicBezier(x1, y1, x2, y2 float32) Curve {
+	x1, x2 = min(max(x1, 0), 1), min(max(x2, 0), 1)
+	bezier := func(a, b, s float32) float32 {
+		q := 1 - s
+		return 3*q*q*s*a + 3*q*s*s*b + s*s*s
+	}
+	return func(t float32) float32 {
+		if t <= 0 || t >= 1 {
+			return t
+		}
+		// The curve is monotonic in x, so bisection finds the parameter
+		// of t.
+		lo, hi := float32(0), float32(1)
+		for range 24 {
+			mid := (lo + hi) / 2
+			if bezier(x1, x2, mid) < t {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		return bezier(y1, y2, (lo+hi)/2)
+	}
+}
+
+// Steps returns a curve jumping to the end in n equal steps, each at the
+// end of its part of the progress.
+func Steps(n int) Curve {
+	n = max(n, 1)
+	return func(t float32) float32 {
+		if t >= 1 {
+			return 1
+		}
+		return float32(math.Floor(float64(t*float32(n)))) / float32(n)
+	}
+}
+
+// Reversed returns c played backwards, so that an ease out of c becomes
+// an ease in.
+func (c Curve) Reversed() Curve {
+	return func(t float32) float32 { return 1 - c(1-t) }
+}
+
+// Interval returns c run within the part of the progress from begin to
+// end, from 0 to 1, and at 0 before and 1 after it, for animations of one
+// controller that start one after another.
+func (c Curve) Interval(begin, end float32) Curve {
+	return func(t float32) float32 {
+		switch {
+		case t <= begin:
+			return c(0)
+		case t >= end || end <= begin:
+			return c(1)
+		}
+		return c((t - begin) / (end - begin))
+	}
+}
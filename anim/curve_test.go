@@ -0,0 +1,72 @@
+package anim
+
+import "testing"
+
+func near(a, b float32) bool {
+	return abs(a-b) < 1e-3
+}
+
+func TestCurveEnds(t *testing.T) {
+	curves := map[string]Curve{
+		"Linear": Linear, "EaseIn": EaseIn, "EaseOut": EaseOut, "EaseInOut": EaseInOut,
+		"Standard": Standard, "Emphasized": Emphasized, "Overshoot": Overshoot, "Bounce": Bounce,
+		"CubicBezier": CubicBezier(0.4, 0, 0.6, 1), "Steps": Steps(4),
+		"Reversed": EaseIn.Reversed(), "Interval": Linear.Interval(0.25, 0.75),
+	}
+	for name, c := range curves {
+		if got := c(0); !near(got, 0) {
+			t.Errorf("%s(0) = %v, want 0", name, got)
+		}
+		if got := c(1); !near(got, 1) {
+			t.Errorf("%s(1) = %v, want 1", name, got)
+		}
+	}
+}
+
+func TestCurve(t *testing.T) {
+	tests := []struct {
+		name string
+		c    Curve
+		t    float32
+		want float32
+	}{
+		{"linear", Linear, 0.3, 0.3},
+		{"ease in starts slowly", EaseIn, 0.5, 0.125},
+		{"ease out starts fast", EaseOut, 0.5, 0.875},
+		{"ease in and out halfway", EaseInOut, 0.5, 0.5},
+		{"ease in and out late", EaseInOut, 0.75, 0.9375},
+		{"linear bezier", CubicBezier(0, 0, 1, 1), 0.3, 0.3},
+		{"symmetric bezier halfway", CubicBezier(0.4, 0, 0.6, 1), 0.5, 0.5},
+		{"bezier before the start", Standard, -0.5, -0.5},
+		{"overshoot passes the end", Overshoot, 0.7, 1.0802},
+		{"bounce lands first", Bounce, 1 / 2.75, 1},
+		{"steps hold", Steps(4), 0.49, 0.25},
+		{"steps jump at the end of a part", Steps(4), 0.5, 0.5},
+		{"no steps is one", Steps(0), 0.99, 0},
+		{"reversed", EaseIn.Reversed(), 0.5, 0.875},
+		{"before the interval", Linear.Interval(0.25, 0.75), 0.1, 0},
+		{"in the interval", Linear.Interval(0.25, 0.75), 0.5, 0.5},
+		{"after the interval", Linear.Interval(0.25, 0.75), 0.8, 1},
+		{"empty interval", Linear.Interval(0.5, 0.5), 0.6, 1},
+	}
+	for _, tt := range tests {
+		if got := tt.c(tt.t); !near(got, tt.want) {
+			t.Errorf("%s: curve(%v) = %v, want %v", tt.name, tt.t, got, tt.want)
+		}
+	}
+}
+
+// TestCubicBezierMonotonic checks that a bezier with its control points
+// inside the square rises steadily.
+func TestCubicBezierMonotonic(t *testing.T) {
+	for _, c := range []Curve{Standard, Emphasized, CubicBezier(0.9, 0.1, 0.1, 0.9)} {
+		prev := float32(0)
+		for i := range 101 {
+			v := c(float32(i) / 100)
+			if v < prev-1e-4 {
+				t.Fatalf("curve falls from %v to %v at %v", prev, v, float32(i)/100)
+			}
+			prev = v
+		}
+	}
+}
@@ -0,0 +1,29 @@
+// Package anim animates values with the frames of a window, without
+// goroutines of their own.
+//
+// A [Controller] runs from 0 to 1 and back over a duration, on the
+// [Clock] of a window, which ticks at the start of each frame while an
+// animation runs and not at all while none does. A [Curve] eases its
+// progress, and a [Tween] maps the eased value to a float, color, point
+// or rectangle. [Drive] writes that value into a signal at every tick, so
+// an animation can drive any widget property bound to a signal:
+//
+//	opacity := state.New[float32](0)
+//	panel := layout.NewComposite(content).Bind(opacity)
+//
+//	fade := anim.NewController(anim.ClockOf(ctx), 200*time.Millisecond).Curve(anim.EaseOut)
+//	anim.Drive(fade, anim.Float(0, 1), opacity)
+//	fade.Forward()
+//
+// A [Timeline] sequences and overlaps keyframed [Track]s of properties,
+// each keyframe with a curve of its own, and plays, pauses, seeks and
+// reverses them as one.
+//
+// A [SpringValue] instead moves toward a target on a [Spring], keeping
+// its velocity when the target changes and taking over that of a released
+// drag, measured by a [VelocityTracker].
+//
+// Controllers, clocks and their callbacks belong to the UI goroutine.
+// With reduced motion, animations jump to where they were going on the
+// next frame.
+package anim
@@ -0,0 +1,219 @@
+package anim
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	maxStep = 50 * time.Millisecond // Longest physics step, after a stall.
+	substep = 1.0 / 240             // Integration step, in seconds.
+
+	defaultStiffness float32 = 300
+	defaultPrecision float32 = 0.01
+
+	// maxStiffness is that of a spring settling in a millisecond, the
+	// quickest SpringFor makes.
+	maxStiffness float32 = 4e7
+)
+
+// Spring describes the motion of a value pulled toward a target. Stiffness
+// pulls toward the target, in 1/s²; Damping slows the motion, in 1/s. A
+// damping of 2√Stiffness arrives fastest without overshooting; less
+// overshoots and bounces.
+//
+// The zero Spring is a critically damped spring of stiffness 300, which
+// settles in about a third of a second.
+type Spring struct {
+	Stiffness float32
+	Damping   float32
+}
+
+// SpringFor returns the spring that takes about response to settle, with
+// a damping ratio from 0, bouncing forever, through 1, critically damped,
+// to above 1, creeping in slowly.
+func SpringFor(response time.Duration, ratio float32) Spring {
+	w := 2 * math.Pi / max(response.Seconds(), 0.001)
+	return Spring{Stiffness: float32(w * w), Damping: 2 * max(ratio, 0.001) * float32(w)}
+}
+
+// Coefficients returns the stiffness and damping of s, with those of the
+// zero Spring in place of zeros, and the stiffness of a spring settling
+// in a millisecond in place of more.
+func (s Spring) Coefficients() (stiffness, damping float32) {
+	k := min(s.Stiffness, maxStiffness)
+	if k <= 0 {
+		k = defaultStiffness
+	}
+	d := s.Damping
+	if d <= 0 {
+		d = 2 * float32(math.Sqrt(float64(k)))
+	}
+	return k, d
+}
+
+// Step advances a value at pos moving at vel, in units a second, toward
+// target by dt, and returns where it is and how fast it moves then. Long
+// steps are integrated in short ones, so that stiff springs stay stable.
+func (s Spring) Step(pos, vel, target float32, dt time.Duration) (float32, float32) {
+	k, d := s.Coefficients()
+	// The damping is integrated implicitly, which keeps the steps stable
+	// however strong it is while they are shorter than 2/√k.
+	step := min(substep, 1/float32(math.Sqrt(float64(k))))
+	rest := float32(min(max(dt, 0), maxStep).Seconds())
+	for rest > 0 {
+		h := min(rest, step)
+		rest -= h
+		vel = (vel - k*(pos-target)*h) / (1 + d*h)
+		pos += vel * h
+	}
+	return pos, vel
+}
+
+// SpringValue animates a value toward a target on a spring. The velocity
+// carries over when the target changes, and Fling hands the velocity of a
+// gesture to the spring, so that a panel released mid-drag keeps moving
+// as it was thrown and settles where it snaps:
+//
+//	offset := anim.NewSpringValue(anim.ClockOf(ctx), anim.SpringFor(400*time.Millisecond, 0.8), 0)
+//	offset.Listen(func(v float32) { panel.SetOffset(v) })
+//	// While dragging:
+//	offset.Set(dragged)
+//	// On release:
+//	offset.Fling(snapPoint, tracker.Velocity().X)
+type SpringValue struct {
+	clock     *Clock
+	spring    Spring
+	precision float32
+
+	pos, vel, target float32
+	last             time.Time
+	running          bool
+
+	listeners map[int]func(value float32)
+	next      int
+	onRest    func()
+}
+
+// NewSpringValue returns a value at rest at v, moving on spring on clock.
+func NewSpringValue(clock *Clock, spring Spring, v float32) *SpringValue {
+	return &SpringValue{clock: clock, spring: spring, precision: defaultPrecision, pos: v, target: v}
+}
+
+// Spring sets the spring the value moves on.
+func (s *SpringValue) Spring(spring Spring) *SpringValue {
+	s.spring = spring
+	return s
+}
+
+// Precision sets how near the target the value comes to rest, 0.01 by
+// default. Pixel offsets can rest further, such as at half a pixel.
+func (s *SpringValue) Precision(p float32) *SpringValue {
+	if p > 0 {
+		s.precision = p
+	}
+	return s
+}
+
+// OnRest sets fn to be called when the value comes to rest at the
+// target, and not when it is stopped.
+func (s *SpringValue) OnRest(fn func()) *SpringValue {
+	s.onRest = fn
+	return s
+}
+
+// Listen calls fn with the value at every change of it, until the
+// returned function is called.
+func (s *SpringValue) Listen(fn func(value float32)) (cancel func()) {
+	if s.listeners == nil {
+		s.listeners = make(map[int]func(float32))
+	}
+	id := s.next
+	s.next++
+	s.listeners[id] = fn
+	return func() { delete(s.listeners, id) }
+}
+
+// Value returns where the value is.
+func (s *SpringValue) Value() float32 {
+	return s.pos
+}
+
+// Velocity returns how fast the value moves, in units a second.
+func (s *SpringValue) Velocity() float32 {
+	return s.vel
+}
+
+// Target returns where the value is going.
+func (s *SpringValue) Target() float32 {
+	return s.target
+}
+
+// IsRunning reports whether the value is moving.
+func (s *SpringValue) IsRunning() bool {
+	return s.running
+}
+
+// AnimateTo moves the value to target, keeping its velocity.
+func (s *SpringValue) AnimateTo(target float32) {
+	s.target = target
+	s.run()
+}
+
+// Fling moves the value to target starting at velocity, in units a
+// second, such as that of a gesture that was released.
+func (s *SpringValue) Fling(target, velocity float32) {
+	s.vel = velocity
+	s.AnimateTo(target)
+}
+
+// Set moves the value to v at once and stops it there, with no velocity,
+// such as to follow a drag.
+func (s *SpringValue) Set(v float32) {
+	s.running, s.vel, s.target = false, 0, v
+	if v != s.pos {
+		s.pos = v
+		s.notify()
+	}
+}
+
+// Stop stops the value where it is.
+func (s *SpringValue) Stop() {
+	s.running, s.vel = false, 0
+}
+
+func (s *SpringValue) run() {
+	s.last = time.Time{}
+	s.running = true
+	s.clock.start(s)
+}
+
+func (s *SpringValue) active() bool {
+	return s.running
+}
+
+func (s *SpringValue) advance(now time.Time, reduced bool) {
+	dt := time.Duration(0)
+	if !s.last.IsZero() {
+		dt = now.Sub(s.last)
+	}
+	s.last = now
+	if !reduced {
+		s.pos, s.vel = s.spring.Step(s.pos, s.vel, s.target, dt)
+	}
+	if reduced || (abs(s.pos-s.target) < s.precision && abs(s.vel) < 10*s.precision) {
+		s.pos, s.vel, s.running = s.target, 0, false
+		s.notify()
+		if s.onRest != nil {
+			s.onRest()
+		}
+		return
+	}
+	s.notify()
+}
+
+func (s *SpringValue) notify() {
+	for _, fn := range s.listeners {
+		fn(s.pos)
+	}
+}
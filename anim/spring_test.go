@@ -0,0 +1,86 @@
+package anim
+
+import (
+	"testing"
+	"time"
+)
+
+// settle steps s from 0 toward 1 in frames of 60 Hz for d, and returns
+// where it is and the farthest it went from the target on the way.
+func settle(s Spring, d time.Duration) (pos, worst float32) {
+	const frame = time.Second / 60
+	var vel float32
+	for t := time.Duration(0); t < d; t += frame {
+		pos, vel = s.Step(pos, vel, 1, frame)
+		worst = max(worst, abs(pos-1))
+	}
+	return pos, worst
+}
+
+func TestSpringSettles(t *testing.T) {
+	tests := []struct {
+		name  string
+		s     Spring
+		after time.Duration
+	}{
+		{"zero", Spring{}, time.Second},
+		{"slow", SpringFor(500*time.Millisecond, 1), 2 * time.Second},
+		{"bouncy", SpringFor(300*time.Millisecond, 0.3), 3 * time.Second},
+		{"overdamped", SpringFor(100*time.Millisecond, 3), 2 * time.Second},
+		// Shorter than the integration step and a frame.
+		{"20ms", SpringFor(20*time.Millisecond, 1), 100 * time.Millisecond},
+		{"5ms", SpringFor(5*time.Millisecond, 0.5), 100 * time.Millisecond},
+		{"shortest", SpringFor(0, 1), 100 * time.Millisecond},
+		{"heavily damped", SpringFor(5*time.Millisecond, 50), 2 * time.Second},
+		{"stiffer than any", Spring{Stiffness: 1e12}, 100 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pos, worst := settle(tt.s, tt.after)
+			if abs(pos-1) > 0.01 {
+				t.Errorf("at %v after %v, want 1", pos, tt.after)
+			}
+			// Overshooting is fine; diverging is not.
+			if worst > 1 {
+				t.Errorf("went %v from the target", worst)
+			}
+		})
+	}
+}
+
+func TestSpringFor(t *testing.T) {
+	k, d := SpringFor(time.Second, 1).Coefficients()
+	// 2π/1s, squared.
+	if k < 39.4 || k > 39.5 || d < 12.5 || d > 12.6 {
+		t.Errorf("stiffness %v and damping %v, want 39.48 and 12.57", k, d)
+	}
+	if k, _ := (Spring{Stiffness: 1e12}).Coefficients(); k != maxStiffness {
+		t.Errorf("stiffness %v, want it capped at %v", k, maxStiffness)
+	}
+	if k, d := (Spring{}).Coefficients(); k != defaultStiffness || d*d < 4*k*0.999 || d*d > 4*k*1.001 {
+		t.Errorf("zero spring: stiffness %v and damping %v, want critically damped at %v", k, d, defaultStiffness)
+	}
+}
+
+func TestSpringValue(t *testing.T) {
+	clock := NewClock()
+	v := NewSpringValue(clock, SpringFor(10*time.Millisecond, 1), 0)
+	rested := 0
+	v.OnRest(func() { rested++ })
+	var heard []float32
+	v.Listen(func(x float32) { heard = append(heard, x) })
+	v.AnimateTo(10)
+	if !v.IsRunning() || v.Target() != 10 {
+		t.Fatalf("running %v to %v, want running to 10", v.IsRunning(), v.Target())
+	}
+	t0 := time.Unix(0, 0)
+	for i := range 30 {
+		clock.Tick(t0.Add(time.Duration(i) * time.Second / 60))
+	}
+	if v.IsRunning() || v.Value() != 10 || v.Velocity() != 0 || rested != 1 {
+		t.Errorf("after half a second: running %v at %v moving %v, rested %d times", v.IsRunning(), v.Value(), v.Velocity(), rested)
+	}
+	if len(heard) == 0 || heard[len(heard)-1] != 10 {
+		t.Errorf("listener heard %v, ending at 10", heard)
+	}
+}
@@ -0,0 +1,276 @@
+package anim
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// Timed is what a Timeline sequences: a Track, or another Timeline.
+type Timed interface {
+	// Duration returns how long it runs.
+	Duration() time.Duration
+
+	// Seek sets what it animates to where it is at, from 0 to Duration.
+	Seek(at time.Duration)
+}
+
+// Keyframe is a value a Track reaches at a time, eased from the keyframe
+// before it by Curve, or linearly if Curve is nil.
+type Keyframe[T any] struct {
+	At    time.Duration
+	Value T
+	Curve Curve
+}
+
+// Track animates a property through keyframes, setting it at every step
+// with a function, such as the Set of a signal bound to a widget. Before
+// its first keyframe the property has the first value, and after the last
+// the last.
+type Track[T any] struct {
+	lerp func(a, b T, t float32) T
+	set  func(T)
+	keys []Keyframe[T]
+}
+
+// NewTrack returns a track interpolating values with lerp, such as
+// LerpFloat or core.Color.Lerp, and setting them with set.
+func NewTrack[T any](lerp func(a, b T, t float32) T, set func(T)) *Track[T] {
+	return &Track[T]{lerp: lerp, set: set}
+}
+
+// Key adds a keyframe reaching v at time at, eased there by curve.
+func (t *Track[T]) Key(at time.Duration, v T, curve Curve) *Track[T] {
+	// A keyframe at the time of another comes after it, jumping there.
+	i := slices.IndexFunc(t.keys, func(k Keyframe[T]) bool { return k.At > at })
+	if i < 0 {
+		i = len(t.keys)
+	}
+	t.keys = slices.Insert(t.keys, i, Keyframe[T]{At: at, Value: v, Curve: curve})
+	return t
+}
+
+// Duration implements Timed, as the time of the last keyframe.
+func (t *Track[T]) Duration() time.Duration {
+	if len(t.keys) == 0 {
+		return 0
+	}
+	return t.keys[len(t.keys)-1].At
+}
+
+// Seek implements Timed.
+func (t *Track[T]) Seek(at time.Duration) {
+	if len(t.keys) == 0 {
+		return
+	}
+	i := slices.IndexFunc(t.keys, func(k Keyframe[T]) bool { return k.At > at })
+	switch i {
+	case 0:
+		t.set(t.keys[0].Value)
+		return
+	case -1:
+		t.set(t.keys[len(t.keys)-1].Value)
+		return
+	}
+	a, b := t.keys[i-1], t.keys[i]
+	p := float32(at-a.At) / float32(b.At-a.At)
+	if b.Curve != nil {
+		p = b.Curve(p)
+	}
+	t.set(t.lerp(a.Value, b.Value, p))
+}
+
+// Timeline sequences and overlaps tracks, and other timelines, and plays
+// them on a clock, forward or in reverse, for sequences such as those of
+// onboarding:
+//
+//	tl := anim.NewTimeline(anim.ClockOf(ctx)).
+//		Then(anim.NewTrack(anim.LerpFloat, titleOpacity.Set).Key(0, 0, nil).Key(300*time.Millisecond, 1, anim.EaseOut)).
+//		With(anim.NewTrack(anim.LerpPoint, titleOffset.Set).Key(0, core.Pt(0, 24), nil).Key(400*time.Millisecond, core.Point{}, anim.Standard)).
+//		At(200*time.Millisecond, bodyFade)
+//	tl.Play()
+//
+// A timeline is itself Timed, so that sequences can be built of smaller
+// ones. Each item is seeked at every step, to its start before it starts
+// and its end after it ends. Of items animating the same property, the
+// running one sets it, or else the last that ended, or else the next to
+// start, so that items take turns in the order they run.
+type Timeline struct {
+	clock *Clock
+	items []timelineItem
+
+	pos     time.Duration
+	from    time.Duration // Where playing started.
+	begin   time.Time
+	playing bool
+	reverse bool
+	onEnd   func()
+}
+
+type timelineItem struct {
+	start time.Duration
+	timed Timed
+}
+
+func (it timelineItem) end() time.Duration {
+	return it.start + it.timed.Duration()
+}
+
+// NewTimeline returns an empty timeline playing on clock.
+func NewTimeline(clock *Clock) *Timeline {
+	return &Timeline{clock: clock}
+}
+
+// At adds t starting at start.
+func (tl *Timeline) At(start time.Duration, t Timed) *Timeline {
+	tl.items = append(tl.items, timelineItem{start: max(start, 0), timed: t})
+	return tl
+}
+
+// Then adds t starting when all added before it have ended.
+func (tl *Timeline) Then(t Timed) *Timeline {
+	return tl.At(tl.Duration(), t)
+}
+
+// With adds t starting with the item added last.
+func (tl *Timeline) With(t Timed) *Timeline {
+	start := time.Duration(0)
+	if n := len(tl.items); n > 0 {
+		start = tl.items[n-1].start
+	}
+	return tl.At(start, t)
+}
+
+// OnEnd sets fn to be called when playing reaches the end, or the start
+// in reverse, and not when it is paused.
+func (tl *Timeline) OnEnd(fn func()) *Timeline {
+	tl.onEnd = fn
+	return tl
+}
+
+// Duration implements Timed, as the time the last item ends.
+func (tl *Timeline) Duration() time.Duration {
+	var d time.Duration
+	for _, it := range tl.items {
+		d = max(d, it.end())
+	}
+	return d
+}
+
+// Seek implements Timed, moving the position to at. A playing timeline
+// plays on from there.
+func (tl *Timeline) Seek(at time.Duration) {
+	tl.begin = time.Time{}
+	tl.seek(at)
+}
+
+func (tl *Timeline) seek(at time.Duration) {
+	tl.pos = min(max(at, 0), tl.Duration())
+	// Seeked last, an item sets what it animates: those yet to start go
+	// first, the next to start last of them, then those that ended, in
+	// the order they ended, and then those running.
+	items := slices.Clone(tl.items)
+	slices.SortStableFunc(items, func(a, b timelineItem) int {
+		pa, pb := tl.phase(a), tl.phase(b)
+		switch {
+		case pa != pb:
+			return cmp.Compare(pa, pb)
+		case pa == phasePending:
+			return cmp.Compare(b.start, a.start)
+		case pa == phaseEnded:
+			return cmp.Compare(a.end(), b.end())
+		}
+		return 0
+	})
+	for _, it := range items {
+		it.timed.Seek(min(max(tl.pos-it.start, 0), it.timed.Duration()))
+	}
+}
+
+// Phases of a timeline's items, in the order they are seeked.
+const (
+	phasePending = iota
+	phaseEnded
+	phaseRunning
+)
+
+func (tl *Timeline) phase(it timelineItem) int {
+	switch {
+	case tl.pos < it.start:
+		return phasePending
+	case tl.pos >= it.end():
+		return phaseEnded
+	}
+	return phaseRunning
+}
+
+// Position returns where the timeline is.
+func (tl *Timeline) Position() time.Duration {
+	return tl.pos
+}
+
+// IsPlaying reports whether the timeline is playing.
+func (tl *Timeline) IsPlaying() bool {
+	return tl.playing
+}
+
+// IsReversed reports whether the timeline plays, or last played, in
+// reverse.
+func (tl *Timeline) IsReversed() bool {
+	return tl.reverse
+}
+
+// Play plays the timeline forward from where it is, or from the start if
+// it is at the end.
+func (tl *Timeline) Play() {
+	if tl.pos >= tl.Duration() {
+		tl.Seek(0)
+	}
+	tl.play(false)
+}
+
+// Reverse plays the timeline backward from where it is, or from the end
+// if it is at the start.
+func (tl *Timeline) Reverse() {
+	if tl.pos <= 0 {
+		tl.Seek(tl.Duration())
+	}
+	tl.play(true)
+}
+
+func (tl *Timeline) play(reverse bool) {
+	tl.reverse, tl.playing, tl.begin = reverse, true, time.Time{}
+	tl.clock.start(tl)
+}
+
+// Pause stops the timeline where it is.
+func (tl *Timeline) Pause() {
+	tl.playing = false
+}
+
+func (tl *Timeline) active() bool {
+	return tl.playing
+}
+
+func (tl *Timeline) advance(now time.Time, reduced bool) {
+	if tl.begin.IsZero() {
+		tl.begin, tl.from = now, tl.pos
+	}
+	end := tl.Duration()
+	elapsed := now.Sub(tl.begin)
+	if reduced {
+		elapsed = end
+	}
+	pos := tl.from + elapsed
+	if tl.reverse {
+		pos = tl.from - elapsed
+	}
+	pos = min(max(pos, 0), end)
+	tl.seek(pos)
+	if (!tl.reverse && pos == end) || (tl.reverse && pos == 0) {
+		tl.playing = false
+		if tl.onEnd != nil {
+			tl.onEnd()
+		}
+	}
+}
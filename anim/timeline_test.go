@@ -0,0 +1,150 @@
+package anim
+
+import (
+	"testing"
+	"time"
+)
+
+const ms = time.Millisecond
+
+// fade returns a track setting *v from a to b over d.
+func fade(v *float32, a, b float32, d time.Duration) *Track[float32] {
+	return NewTrack(LerpFloat, func(f float32) { *v = f }).Key(0, a, nil).Key(d, b, nil)
+}
+
+func TestTrackSeek(t *testing.T) {
+	var v float32
+	tr := NewTrack(LerpFloat, func(f float32) { v = f }).
+		Key(100*ms, 10, nil).
+		Key(200*ms, 20, EaseIn).
+		Key(200*ms, 50, nil). // Jumps at 200ms.
+		Key(300*ms, 60, nil)
+	tests := []struct {
+		at   time.Duration
+		want float32
+	}{
+		{0, 10},
+		{100 * ms, 10},
+		{150 * ms, 10 + 10*EaseIn(0.5)},
+		{200 * ms, 50},
+		{250 * ms, 55},
+		{300 * ms, 60},
+		{400 * ms, 60},
+	}
+	for _, tt := range tests {
+		tr.Seek(tt.at)
+		if !near(v, tt.want) {
+			t.Errorf("Seek(%v) = %v, want %v", tt.at, v, tt.want)
+		}
+	}
+	if tr.Duration() != 300*ms {
+		t.Errorf("Duration = %v, want 300ms", tr.Duration())
+	}
+	// A track without keyframes sets nothing.
+	NewTrack(LerpFloat, func(float32) { t.Error("empty track set a value") }).Seek(0)
+}
+
+func TestTimelineSeek(t *testing.T) {
+	type want struct {
+		at   time.Duration
+		a, b float32
+	}
+	tests := []struct {
+		name  string
+		build func(a, b *float32) *Timeline
+		wants []want
+	}{
+		{
+			"sequenced on one property",
+			func(a, b *float32) *Timeline {
+				return NewTimeline(NewClock()).Then(fade(a, 0, 1, 100*ms)).Then(fade(a, 1, 0, 100*ms))
+			},
+			[]want{{0, 0, 0}, {50 * ms, 0.5, 0}, {100 * ms, 1, 0}, {150 * ms, 0.5, 0}, {200 * ms, 0, 0}, {50 * ms, 0.5, 0}},
+		},
+		{
+			"next to start",
+			func(a, b *float32) *Timeline {
+				return NewTimeline(NewClock()).At(200*ms, fade(a, 0.5, 1, 100*ms)).At(100*ms, fade(a, 0, 0.5, 100*ms))
+			},
+			[]want{{0, 0, 0}, {150 * ms, 0.25, 0}, {250 * ms, 0.75, 0}, {300 * ms, 1, 0}},
+		},
+		{
+			"with",
+			func(a, b *float32) *Timeline {
+				return NewTimeline(NewClock()).Then(fade(a, 0, 1, 100*ms)).With(fade(b, 1, 0, 200*ms))
+			},
+			[]want{{0, 0, 1}, {50 * ms, 0.5, 0.75}, {150 * ms, 1, 0.25}, {200 * ms, 1, 0}},
+		},
+		{
+			// The later item overlapping the earlier takes over while it
+			// runs, and the earlier, ending last, has the last word.
+			"overlapping on one property",
+			func(a, b *float32) *Timeline {
+				return NewTimeline(NewClock()).At(0, fade(a, 0, 1, 200*ms)).At(100*ms, fade(a, 0, 0.5, 50*ms))
+			},
+			[]want{{50 * ms, 0.25, 0}, {125 * ms, 0.25, 0}, {175 * ms, 0.875, 0}, {200 * ms, 1, 0}},
+		},
+		{
+			"nested",
+			func(a, b *float32) *Timeline {
+				inner := NewTimeline(NewClock()).Then(fade(a, 0, 1, 100*ms)).Then(fade(a, 1, 0, 100*ms))
+				return NewTimeline(NewClock()).At(100*ms, inner).With(fade(b, 0, 1, 100*ms))
+			},
+			[]want{{0, 0, 0}, {150 * ms, 0.5, 0.5}, {250 * ms, 0.5, 1}, {400 * ms, 0, 1}},
+		},
+		{
+			"clamped",
+			func(a, b *float32) *Timeline {
+				return NewTimeline(NewClock()).Then(fade(a, 0, 1, 100*ms))
+			},
+			[]want{{-50 * ms, 0, 0}, {500 * ms, 1, 0}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var a, b float32
+			tl := tt.build(&a, &b)
+			for _, w := range tt.wants {
+				tl.Seek(w.at)
+				if !near(a, w.a) || !near(b, w.b) {
+					t.Errorf("Seek(%v): %v, %v, want %v, %v", w.at, a, b, w.a, w.b)
+				}
+			}
+		})
+	}
+}
+
+func TestTimelinePlay(t *testing.T) {
+	var v float32
+	ended := 0
+	tl := NewTimeline(NewClock()).Then(fade(&v, 0, 1, 100*ms)).Then(fade(&v, 1, 0, 100*ms)).OnEnd(func() { ended++ })
+	tl.Play()
+	tl.clock.Tick(at(0))
+	tl.clock.Tick(at(50))
+	if !near(v, 0.5) || tl.Position() != 50*ms {
+		t.Errorf("playing: %v at %v, want 0.5 at 50ms", v, tl.Position())
+	}
+	tl.Reverse()
+	tl.clock.Tick(at(60))
+	tl.clock.Tick(at(85))
+	if !near(v, 0.25) || !tl.IsReversed() {
+		t.Errorf("reversed: %v at %v, want 0.25 at 25ms", v, tl.Position())
+	}
+	tl.clock.Tick(at(200))
+	if v != 0 || tl.IsPlaying() || ended != 1 {
+		t.Errorf("%v, playing %v, ended %d times, want 0 at the start, ended once", v, tl.IsPlaying(), ended)
+	}
+
+	// Played from the start, it runs to the end.
+	tl.Play()
+	tl.clock.Tick(at(300))
+	tl.clock.Tick(at(450))
+	if !near(v, 0.5) {
+		t.Errorf("in the second item: %v, want 0.5", v)
+	}
+	tl.Pause()
+	tl.clock.Tick(at(600))
+	if !near(v, 0.5) || tl.Position() != 150*ms {
+		t.Errorf("paused: %v at %v, want 0.5 at 150ms", v, tl.Position())
+	}
+}
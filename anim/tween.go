@@ -0,0 +1,75 @@
+package anim
+
+import (
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/state"
+)
+
+// Tween maps the value of a controller, from 0 to 1, to a value from From
+// to To, by Lerp.
+type Tween[T any] struct {
+	From, To T
+
+	// Lerp returns the value t of the way from a to b. Values of curves
+	// passing beyond 0 and 1 extrapolate.
+	Lerp func(a, b T, t float32) T
+}
+
+// At returns the value t of the way from From to To.
+func (tw Tween[T]) At(t float32) T {
+	return tw.Lerp(tw.From, tw.To, t)
+}
+
+// Float returns a tween of numbers, such as opacities and offsets.
+func Float(from, to float32) Tween[float32] {
+	return Tween[float32]{From: from, To: to, Lerp: LerpFloat}
+}
+
+// Color returns a tween of colors, interpolated in sRGB as
+// core.Color.Lerp does.
+func Color(from, to core.Color) Tween[core.Color] {
+	return Tween[core.Color]{From: from, To: to, Lerp: core.Color.Lerp}
+}
+
+// Point returns a tween of positions.
+func Point(from, to core.Point) Tween[core.Point] {
+	return Tween[core.Point]{From: from, To: to, Lerp: LerpPoint}
+}
+
+// Rect returns a tween of rectangles, moving their corners.
+func Rect(from, to core.Rect) Tween[core.Rect] {
+	return Tween[core.Rect]{From: from, To: to, Lerp: LerpRect}
+}
+
+// LerpFloat returns the number t of the way from a to b.
+func LerpFloat(a, b, t float32) float32 {
+	return a + (b-a)*t
+}
+
+// LerpPoint returns the point t of the way from a to b.
+func LerpPoint(a, b core.Point, t float32) core.Point {
+	return core.Pt(LerpFloat(a.X, b.X, t), LerpFloat(a.Y, b.Y, t))
+}
+
+// LerpRect returns the rectangle t of the way from a to b.
+func LerpRect(a, b core.Rect, t float32) core.Rect {
+	return core.Rect{
+		X: LerpFloat(a.X, b.X, t), Y: LerpFloat(a.Y, b.Y, t),
+		Width: LerpFloat(a.Width, b.Width, t), Height: LerpFloat(a.Height, b.Height, t),
+	}
+}
+
+// Drive sets sig to the value of tw at the value of c, now and whenever it
+// changes, until the returned function is called, so that an animation
+// drives whatever is bound to the signal.
+func Drive[T any](c *Controller, tw Tween[T], sig *state.Signal[T]) (cancel func()) {
+	sig.Set(tw.At(c.Value()))
+	return c.Listen(func(v float32) { sig.Set(tw.At(v)) })
+}
+
+// Animate returns a signal driven by c through tw, as Drive does.
+func Animate[T comparable](c *Controller, tw Tween[T]) *state.Signal[T] {
+	sig := state.New(tw.At(c.Value()))
+	Drive(c, tw, sig)
+	return sig
+}
@@ -0,0 +1,54 @@
+package anim
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/state"
+)
+
+func TestTween(t *testing.T) {
+	if got := Float(10, 20).At(0.25); got != 12.5 {
+		t.Errorf("Float At(0.25) = %v, want 12.5", got)
+	}
+	// Curves passing beyond the ends extrapolate.
+	if got := Float(10, 20).At(1.5); got != 25 {
+		t.Errorf("Float At(1.5) = %v, want 25", got)
+	}
+	if got, want := Point(core.Pt(0, 0), core.Pt(10, -10)).At(0.5), core.Pt(5, -5); got != want {
+		t.Errorf("Point At(0.5) = %v, want %v", got, want)
+	}
+	if got, want := Rect(core.R(0, 0, 10, 10), core.R(10, 20, 30, 40)).At(0.5), core.R(5, 10, 20, 25); got != want {
+		t.Errorf("Rect At(0.5) = %v, want %v", got, want)
+	}
+	black, white := core.RGB(0, 0, 0), core.RGB(255, 255, 255)
+	if got, want := Color(black, white).At(0.5), black.Lerp(white, 0.5); got != want {
+		t.Errorf("Color At(0.5) = %v, want %v", got, want)
+	}
+}
+
+func TestDrive(t *testing.T) {
+	clock := NewClock()
+	c := NewController(clock, 0)
+	c.SetProgress(0.5)
+	sig := state.New[float32](0)
+	cancel := Drive(c, Float(0, 100), sig)
+	if got := sig.Get(); got != 50 {
+		t.Errorf("driven to %v at first, want 50", got)
+	}
+	c.SetProgress(1)
+	if got := sig.Get(); got != 100 {
+		t.Errorf("driven to %v, want 100", got)
+	}
+	cancel()
+	c.SetProgress(0)
+	if got := sig.Get(); got != 100 {
+		t.Errorf("driven to %v after cancel, want it left at 100", got)
+	}
+
+	a := Animate(c, Point(core.Pt(0, 0), core.Pt(8, 8)))
+	c.SetProgress(0.25)
+	if got, want := a.Get(), core.Pt(2, 2); got != want {
+		t.Errorf("animated signal at %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,60 @@
+package anim
+
+import (
+	"time"
+
+	"github.com/gogpu/ui/core"
+)
+
+// velocityWindow is how far back a VelocityTracker looks.
+const velocityWindow = 100 * time.Millisecond
+
+// VelocityTracker estimates the velocity of a pointer from its recent
+// positions, for handing off to a spring or momentum when a drag is
+// released.
+type VelocityTracker struct {
+	samples []velocitySample
+}
+
+type velocitySample struct {
+	at  time.Time
+	pos core.Point
+}
+
+// Add records that the pointer was at p at time at, such as the time of
+// the event.
+func (t *VelocityTracker) Add(at time.Time, p core.Point) {
+	t.samples = append(t.samples, velocitySample{at, p})
+	i := 0
+	for i < len(t.samples)-1 && at.Sub(t.samples[i].at) > velocityWindow {
+		i++
+	}
+	t.samples = t.samples[i:]
+}
+
+// Velocity returns the velocity over the last tenth of a second, in
+// logical pixels a second, or zero if the pointer has not moved in it.
+func (t *VelocityTracker) Velocity() core.Point {
+	return t.VelocityAt(time.Now())
+}
+
+// VelocityAt returns the velocity as of now, which is zero if the last
+// sample is older than a tenth of a second, as for a pointer that was
+// held still before release.
+func (t *VelocityTracker) VelocityAt(now time.Time) core.Point {
+	if len(t.samples) < 2 || now.Sub(t.samples[len(t.samples)-1].at) > velocityWindow {
+		return core.Point{}
+	}
+	first, last := t.samples[0], t.samples[len(t.samples)-1]
+	dt := float32(last.at.Sub(first.at).Seconds())
+	if dt <= 0 {
+		return core.Point{}
+	}
+	d := last.pos.Sub(first.pos)
+	return core.Pt(d.X/dt, d.Y/dt)
+}
+
+// Reset forgets the samples, for the start of a new drag.
+func (t *VelocityTracker) Reset() {
+	t.samples = t.samples[:0]
+}
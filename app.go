@@ -0,0 +1,196 @@
+package ui
+
+import (
+	"slices"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/shortcuts"
+	"github.com/gogpu/ui/widgets"
+)
+
+// App runs the top-level windows of an application, such as a main window
+// with tool palettes, detached editors and inspectors beside it:
+//
+//	app := ui.NewApp(host, ui.WithTheme(theme.Fluent()))
+//	main := app.Open(editor, ui.WindowConfig{Title: "Editor", Size: core.Sz(1200, 800)})
+//	app.Open(inspector, ui.WindowConfig{Title: "Inspector", Kind: ui.WindowTool, Owner: main})
+//
+// Each window has a widget tree and Context of its own. The windows share
+// the options of the App and the cache of decoded images, and their
+// canvases share the GPU device of the platform, so textures are uploaded
+// once for all of them.
+type App struct {
+	host    WindowHost
+	opts    []Option
+	windows []*Window
+	active  *Window
+	shared  *core.Context // Holds what the windows share.
+	keep    bool          // Set to keep running without windows.
+	keys    *shortcuts.Registry
+	onClose func(w *Window) bool
+}
+
+// WindowHost is implemented by platform integrations that open native
+// windows, such as the gogpu App. It drives each window it opens as a
+// single Window is driven: it feeds it input with HandleEvent, resizes
+// it with Resize and calls Frame whenever it needs a new image, with
+// canvases drawing with one GPU device for all windows.
+type WindowHost interface {
+	// OpenWindow opens a native window for w as cfg describes.
+	OpenWindow(w *Window, cfg WindowConfig)
+
+	// CloseWindow closes the native window of w.
+	CloseWindow(w *Window)
+
+	// ActivateWindow brings the native window of w to the front and
+	// gives it keyboard input. The integration reports the change with
+	// Window.SetActive.
+	ActivateWindow(w *Window)
+
+	// Quit ends the event loop.
+	Quit()
+}
+
+// WindowConfig describes a native window opened by App.Open.
+type WindowConfig struct {
+	Title string
+	Size  core.Size
+	Kind  WindowKind
+
+	// Owner keeps the window above another, minimized and closed with
+	// it, as for palettes and inspectors. Nil opens an independent
+	// window.
+	Owner *Window
+}
+
+// WindowKind is the role of a native window, which platforms decorate
+// the windows of differently.
+type WindowKind uint8
+
+const (
+	WindowNormal WindowKind = iota // A document or main window.
+	WindowTool                     // A palette or inspector, with a small title bar and no taskbar entry.
+	WindowDialog                   // A dialog, without minimize and maximize buttons.
+)
+
+// NewApp returns an application opening windows through host, each
+// configured by opts before its own options.
+func NewApp(host WindowHost, opts ...Option) *App {
+	return &App{host: host, opts: opts, shared: core.NewContext(), keys: shortcuts.NewRegistry()}
+}
+
+// Shortcuts returns the registry of the key bindings and commands of the
+// application, which those of its windows fall back to.
+func (a *App) Shortcuts() *shortcuts.Registry {
+	return a.keys
+}
+
+// KeepRunning keeps the event loop running once the last window closes,
+// for applications living in the menu bar or tray. By default closing
+// it quits.
+func (a *App) KeepRunning(keep bool) *App {
+	a.keep = keep
+	return a
+}
+
+// OnCloseRequest sets fn to decide whether a window the user asks to
+// close, such as with its close button, closes, for asking to save
+// changes first.
+func (a *App) OnCloseRequest(fn func(w *Window) bool) *App {
+	a.onClose = fn
+	return a
+}
+
+// Open opens a native window displaying root, configured by the options
+// of the App and then opts.
+func (a *App) Open(root core.Widget, cfg WindowConfig, opts ...Option) *Window {
+	w := NewWindow(root, slices.Concat(a.opts, opts)...)
+	w.app, w.owner = a, cfg.Owner
+	w.size = cfg.Size
+	widgets.ShareImageCache(w.ctx, a.shared)
+	shortcuts.Of(w.ctx).SetParent(a.keys)
+	a.windows = append(a.windows, w)
+	a.host.OpenWindow(w, cfg)
+	return w
+}
+
+// Windows returns the open windows in the order they were opened.
+func (a *App) Windows() []*Window {
+	return a.windows
+}
+
+// Active returns the window receiving keyboard input, or nil while
+// another application has it.
+func (a *App) Active() *Window {
+	return a.active
+}
+
+// Activate brings w to the front and gives it keyboard input.
+func (a *App) Activate(w *Window) {
+	if slices.Contains(a.windows, w) {
+		a.host.ActivateWindow(w)
+	}
+}
+
+// Focus moves keyboard focus to target in whichever window displays it,
+// activating that window, and reports whether a window does.
+func (a *App) Focus(target core.Widget) bool {
+	for _, w := range a.windows {
+		if w.pathTo(target) != nil {
+			w.ctx.RequestFocus(target)
+			if w != a.active {
+				a.Activate(w)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// RequestClose closes w unless the OnCloseRequest handler refuses, and
+// reports whether it closed. Integrations call it when the user asks to
+// close a native window.
+func (a *App) RequestClose(w *Window) bool {
+	if a.onClose != nil && !a.onClose(w) {
+		return false
+	}
+	a.Close(w)
+	return true
+}
+
+// Close closes w and the windows it owns. Closing the last window quits
+// unless KeepRunning is set.
+func (a *App) Close(w *Window) {
+	if !slices.Contains(a.windows, w) {
+		return
+	}
+	for _, o := range slices.Clone(a.windows) {
+		if o.owner == w {
+			a.Close(o)
+		}
+	}
+	a.windows = slices.DeleteFunc(a.windows, func(o *Window) bool { return o == w })
+	w.ctx.RequestFocus(nil)
+	w.ctx.ReleasePointer()
+	for o := range w.popups {
+		w.host.HidePopup(o)
+		delete(w.popups, o)
+	}
+	if a.active == w {
+		a.active = nil
+	}
+	a.host.CloseWindow(w)
+	if len(a.windows) == 0 && !a.keep {
+		a.host.Quit()
+	}
+}
+
+// activated records a change of the window with keyboard input.
+func (a *App) activated(w *Window, active bool) {
+	switch {
+	case active:
+		a.active = w
+	case a.active == w:
+		a.active = nil
+	}
+}
@@ -0,0 +1,140 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/shortcuts"
+)
+
+// windowHost records what the app asks of it, activating windows as a
+// platform does.
+type windowHost struct {
+	log    []string
+	titles map[*Window]string
+	active *Window
+}
+
+func (h *windowHost) OpenWindow(w *Window, cfg WindowConfig) {
+	if h.titles == nil {
+		h.titles = map[*Window]string{}
+	}
+	h.titles[w] = cfg.Title
+	h.log = append(h.log, "open "+cfg.Title)
+}
+
+func (h *windowHost) CloseWindow(w *Window) {
+	h.log = append(h.log, "close "+h.titles[w])
+}
+
+func (h *windowHost) ActivateWindow(w *Window) {
+	h.log = append(h.log, "activate "+h.titles[w])
+	if h.active != nil {
+		h.active.SetActive(false)
+	}
+	h.active = w
+	w.SetActive(true)
+}
+
+func (h *windowHost) Quit() {
+	h.log = append(h.log, "quit")
+}
+
+func TestAppWindows(t *testing.T) {
+	host := &windowHost{}
+	app := NewApp(host, WithDirection(core.RightToLeft), WithScaleFactor(2))
+	main := app.Open(newPane(), WindowConfig{Title: "main", Size: core.Sz(800, 600)})
+	tool := app.Open(newPane(), WindowConfig{Title: "tool", Kind: WindowTool, Owner: main}, WithScaleFactor(1.5))
+	other := app.Open(newPane(), WindowConfig{Title: "other"})
+	if !slices.Equal(app.Windows(), []*Window{main, tool, other}) {
+		t.Fatalf("windows %v, want in the order opened", app.Windows())
+	}
+	if main.App() != app || tool.Owner() != main || main.Owner() != nil || main.Size() != core.Sz(800, 600) {
+		t.Error("a window does not know its app, owner or size")
+	}
+
+	// The options of the app come before those of each window.
+	if d := tool.Direction(); d != core.RightToLeft {
+		t.Errorf("direction %v, want the app's", d)
+	}
+	if s := tool.Context().ScaleFactor(); s != 1.5 || main.Context().ScaleFactor() != 2 {
+		t.Errorf("scale %v, want the window's over the app's", s)
+	}
+	if shortcuts.Of(tool.Context()).Parent() != app.Shortcuts() {
+		t.Error("the shortcuts of a window do not fall back to the app's")
+	}
+
+	// Closing a window closes those it owns; closing the last one quits
+	// unless the app keeps running.
+	app.Close(main)
+	app.Close(main)
+	if !slices.Equal(app.Windows(), []*Window{other}) {
+		t.Errorf("windows %v after closing the main one", app.Windows())
+	}
+	app.KeepRunning(true).Close(other)
+	app.KeepRunning(false)
+	app.Close(app.Open(newPane(), WindowConfig{Title: "last"}))
+	want := []string{"open main", "open tool", "open other", "close tool", "close main", "close other", "open last", "close last", "quit"}
+	if !slices.Equal(host.log, want) {
+		t.Errorf("host asked %q, want %q", host.log, want)
+	}
+}
+
+func TestAppRequestClose(t *testing.T) {
+	host := &windowHost{}
+	app := NewApp(host)
+	saved := false
+	app.OnCloseRequest(func(*Window) bool { return saved })
+	w := app.Open(newPane(), WindowConfig{Title: "doc"})
+	if app.RequestClose(w) || len(app.Windows()) != 1 {
+		t.Error("a window closed though its close request was refused")
+	}
+	saved = true
+	if !app.RequestClose(w) || len(app.Windows()) != 0 {
+		t.Error("a window stayed open though its close request was accepted")
+	}
+}
+
+func TestAppFocus(t *testing.T) {
+	host := &windowHost{}
+	app := NewApp(host)
+	field, other := newPane(), newPane()
+	a := app.Open(newPane(field), WindowConfig{Title: "a"})
+	b := app.Open(newPane(other), WindowConfig{Title: "b"})
+	for _, w := range app.Windows() {
+		w.Resize(core.Sz(100, 100))
+		w.Layout()
+	}
+	if app.Active() != nil {
+		t.Error("a window active before the platform said so")
+	}
+
+	// Focus goes to the window showing the widget, which is activated.
+	if !app.Focus(other) || !b.Context().IsFocused(other) || app.Active() != b || !b.Active() {
+		t.Fatalf("focus on %v, active %v", b.Context().Focused(), app.Active())
+	}
+	app.Focus(field)
+	if !a.Context().IsFocused(field) || app.Active() != a || b.Active() {
+		t.Errorf("active %v after focusing the other window", app.Active())
+	}
+	// Inactive, a window keeps its focus.
+	if !b.Context().IsFocused(other) {
+		t.Error("the inactive window lost its focus")
+	}
+	if app.Focus(newPane()) {
+		t.Error("a widget in no window was focused")
+	}
+
+	// Activating the active window asks the platform again; closing it
+	// leaves none active.
+	app.Activate(a)
+	app.Activate(NewWindow(newPane()))
+	app.Close(a)
+	if app.Active() != nil || a.Context().Focused() != nil {
+		t.Errorf("active %v after closing the active window", app.Active())
+	}
+	if got := host.log[len(host.log)-3:]; !slices.Equal(got, []string{"activate a", "activate a", "close a"}) {
+		t.Errorf("host asked %q", got)
+	}
+}
@@ -0,0 +1,109 @@
+package ui
+
+import (
+	"image"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/gogpu/ui/font"
+	"github.com/gogpu/ui/render/raster"
+	"github.com/gogpu/ui/theme"
+)
+
+// Backend selects what renders the frames of a window.
+type Backend uint8
+
+const (
+	// BackendAuto renders with the GPU, unless the GOGPU_UI_BACKEND
+	// environment variable is "cpu", for machines whose GPU drivers are
+	// broken, or OpenGraphics fell back to the CPU.
+	BackendAuto Backend = iota
+
+	// BackendGPU renders with the GPU.
+	BackendGPU
+
+	// BackendCPU renders into an image in memory with package raster,
+	// without a GPU, for screenshot tests in CI, servers and machines
+	// without working drivers. Text is drawn with the outlines of the
+	// glyphs of the fonts of WithFonts, and laid out with their advances
+	// unless WithTextMeasurer says otherwise. Platform integrations
+	// present the image, or none of it for headless windows.
+	BackendCPU
+)
+
+// backendEnv is the environment variable naming the graphics API to try
+// first, such as "vulkan", or "cpu" to make BackendAuto render on the
+// CPU.
+const backendEnv = "GOGPU_UI_BACKEND"
+
+// WithFonts sets the fonts text is drawn with on the CPU, by RenderFrame
+// and by captures without WithCaptureCanvas, so that they show what text
+// reads. By default these are the fonts installed on the system, loaded
+// when first needed. Characters no face covers are drawn as boxes.
+func WithFonts(m *font.Manager) Option {
+	return func(w *Window) {
+		w.fonts = m
+	}
+}
+
+// systemFonts are the fonts of windows without WithFonts.
+var systemFonts = sync.OnceValue(func() *font.Manager {
+	m := font.NewManager()
+	m.LoadSystem()
+	return m
+})
+
+// fontManager returns the fonts text is drawn with on the CPU.
+func (w *Window) fontManager() *font.Manager {
+	if w.fonts == nil {
+		w.fonts = systemFonts()
+	}
+	return w.fonts
+}
+
+// WithBackend selects what renders the window's frames, BackendAuto by
+// default.
+func WithBackend(b Backend) Option {
+	return func(w *Window) {
+		w.backend = b
+	}
+}
+
+// Backend returns what renders the window's frames: BackendCPU, or
+// BackendGPU unless BackendAuto found the CPU chosen by the
+// GOGPU_UI_BACKEND environment variable or by OpenGraphics.
+func (w *Window) Backend() Backend {
+	if w.backend == BackendAuto {
+		if strings.EqualFold(os.Getenv(backendEnv), "cpu") || GraphicsDiagnostics().API == GraphicsCPU {
+			return BackendCPU
+		}
+		return BackendGPU
+	}
+	return w.backend
+}
+
+// RenderFrame renders a frame on the CPU, as Frame does into an image the
+// window keeps of its device size, and returns the image. The image is
+// drawn into again by the next call, in only the regions of Damage with
+// WithPartialRedraw, so integrations presenting it copy just those. The
+// first call lays text out again by the fonts it is drawn with, unless
+// WithTextMeasurer installed a measurer.
+func (w *Window) RenderFrame() *image.RGBA {
+	width, height := w.DeviceSize()
+	if w.cpu == nil && !w.measured {
+		w.ctx.SetTextMeasurer(raster.NewMeasurer(w.fontManager()))
+		w.ctx.Invalidate()
+	}
+	if w.cpu == nil || w.cpu.Image().Bounds() != image.Rect(0, 0, width, height) || w.cpuScale != w.ctx.ScaleFactor() {
+		w.cpu = raster.New(image.NewRGBA(image.Rect(0, 0, width, height)), w.ctx.ScaleFactor()).TextMeasurer(w.ctx).Fonts(w.fontManager())
+		w.cpuScale = w.ctx.ScaleFactor()
+		w.RepaintAll()
+	}
+	if !w.partial {
+		// Integrations using the GPU clear the surface for each frame.
+		w.cpu.Clear(theme.From(w.ctx).Colors.Background)
+	}
+	w.Frame(w.cpu)
+	return w.cpu.Image()
+}
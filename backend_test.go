@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/font"
+)
+
+func TestBackend(t *testing.T) {
+	graphics.mu.Lock()
+	saved := graphics.diag
+	graphics.mu.Unlock()
+	t.Cleanup(func() {
+		graphics.mu.Lock()
+		graphics.diag = saved
+		graphics.mu.Unlock()
+	})
+	tests := []struct {
+		name    string
+		backend Backend
+		env     string
+		opened  GraphicsAPI
+		want    Backend
+	}{
+		{"auto on the GPU", BackendAuto, "", GraphicsVulkan, BackendGPU},
+		{"auto before opening", BackendAuto, "", GraphicsNone, BackendGPU},
+		{"auto from the environment", BackendAuto, "CPU", GraphicsVulkan, BackendCPU},
+		{"auto after falling back", BackendAuto, "", GraphicsCPU, BackendCPU},
+		{"another API in the environment", BackendAuto, "gl", GraphicsGL, BackendGPU},
+		{"CPU chosen", BackendCPU, "", GraphicsVulkan, BackendCPU},
+		{"GPU chosen", BackendGPU, "cpu", GraphicsCPU, BackendGPU},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(backendEnv, tt.env)
+			graphics.mu.Lock()
+			graphics.diag = Diagnostics{API: tt.opened}
+			graphics.mu.Unlock()
+			w := NewWindow(newPane(), WithBackend(tt.backend))
+			if got := w.Backend(); got != tt.want {
+				t.Errorf("Backend() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderFrame(t *testing.T) {
+	w := NewWindow(newPane(), WithBackend(BackendCPU), WithFonts(font.NewManager()))
+	w.Resize(core.Sz(100, 50))
+	img := w.RenderFrame()
+	if got := img.Bounds().Size(); got.X != 100 || got.Y != 50 {
+		t.Fatalf("image of %v, want 100x50", got)
+	}
+	if r, g, b, a := img.At(50, 25).RGBA(); r != 0xFFFF || g != 0xFFFF || b != 0xFFFF || a != 0xFFFF {
+		t.Errorf("pixel %v, want the white of the pane", img.At(50, 25))
+	}
+	if w.RenderFrame() != img {
+		t.Error("a frame of the same size drew into a new image")
+	}
+
+	tests := []struct {
+		name          string
+		change        func()
+		width, height int
+	}{
+		{"resized", func() { w.Resize(core.Sz(120, 50)) }, 120, 50},
+		{"scaled", func() { w.SetScaleFactor(1.5) }, 180, 75},
+		{"fractional size", func() { w.Resize(core.Sz(33.5, 10)) }, 51, 15},
+	}
+	for _, tt := range tests {
+		tt.change()
+		prev := img
+		img = w.RenderFrame()
+		if img == prev {
+			t.Errorf("%s: the image was not made again", tt.name)
+		}
+		if got := img.Bounds().Size(); got.X != tt.width || got.Y != tt.height {
+			t.Errorf("%s: image of %v, want %dx%d", tt.name, got, tt.width, tt.height)
+		}
+		if r, _, _, a := img.At(tt.width-1, tt.height-1).RGBA(); r != 0xFFFF || a != 0xFFFF {
+			t.Errorf("%s: the corner was not painted: %v", tt.name, img.At(tt.width-1, tt.height-1))
+		}
+	}
+}
+
+// fixedMeasurer measures every text 7 pixels wide.
+type fixedMeasurer struct{}
+
+func (fixedMeasurer) MeasureText(_ string, style core.TextStyle) core.Size {
+	return core.Sz(7, style.LineHeight())
+}
+
+func TestRenderFrameMeasurer(t *testing.T) {
+	style := core.TextStyle{Size: 10}
+	w := NewWindow(newPane(), WithBackend(BackendCPU), WithFonts(font.NewManager()), WithTextMeasurer(fixedMeasurer{}))
+	w.Resize(core.Sz(10, 10))
+	w.RenderFrame()
+	if got := w.Context().MeasureText("abc", style).Width; got != 7 {
+		t.Errorf("text measured %v wide after a frame on the CPU, want the 7 of the measurer given", got)
+	}
+}
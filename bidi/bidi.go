@@ -0,0 +1,697 @@
+// Package bidi implements the Unicode Bidirectional Algorithm (UAX #9),
+// which orders text that mixes left-to-right and right-to-left scripts
+// for display. Resolve computes the embedding level of every character of
+// a paragraph; Runs then breaks a line of it into the runs to draw, in
+// visual order:
+//
+//	text := []rune(s)
+//	p := bidi.Resolve(text, bidi.Auto)
+//	for _, r := range p.Runs(0, p.Len()) {
+//		// Measure and draw string(text[r.Start:r.End]) at the pen, in the
+//		// direction of r.Level.
+//	}
+//
+// Positions are rune indices into the paragraph. Text is one paragraph:
+// callers split it at paragraph separators, as text widgets already do at
+// line breaks.
+package bidi
+
+import "slices"
+
+// Level is an embedding level. Even levels are left to right and odd
+// levels right to left.
+type Level uint8
+
+// Base levels of a paragraph.
+const (
+	LeftToRight Level = 0
+	RightToLeft Level = 1
+
+	// Auto takes the base level from the first strong character of the
+	// paragraph, as rules P2 and P3 do, and is left to right if there is
+	// none.
+	Auto Level = 0xFF
+)
+
+// maxDepth is the deepest explicit embedding level (BD2).
+const maxDepth = 125
+
+// IsRTL reports whether l is a right-to-left level.
+func (l Level) IsRTL() bool {
+	return l&1 == 1
+}
+
+// Paragraph is the resolved embedding levels of a paragraph of text.
+type Paragraph struct {
+	classes []Class // Original classes.
+	levels  []Level
+	base    Level
+}
+
+// FirstStrong returns the direction of the first strong character of text,
+// skipping isolated text, and false if it has none. Text fields use it to
+// pick the base direction of what the user types.
+func FirstStrong(text []rune) (Level, bool) {
+	return firstStrong(classesOf(text), 0, len(text))
+}
+
+// HasRTL reports whether s has characters that can be displayed right to
+// left. Text without them displays as one left-to-right run in a
+// left-to-right paragraph, so callers can skip Resolve for it.
+func HasRTL(s string) bool {
+	for _, r := range s {
+		switch ClassOf(r) {
+		case R, AL, AN, RLE, RLO, RLI, FSI:
+			return true
+		}
+	}
+	return false
+}
+
+func classesOf(text []rune) []Class {
+	classes := make([]Class, len(text))
+	for i, r := range text {
+		classes[i] = ClassOf(r)
+	}
+	return classes
+}
+
+// firstStrong implements P2 over classes[start:end].
+func firstStrong(classes []Class, start, end int) (Level, bool) {
+	isolates := 0
+	for _, c := range classes[start:end] {
+		switch {
+		case c.isIsolateInitiator():
+			isolates++
+		case c == PDI:
+			if isolates > 0 {
+				isolates--
+			}
+		case c == B:
+			return 0, false
+		case isolates > 0:
+		case c == L:
+			return LeftToRight, true
+		case c == R, c == AL:
+			return RightToLeft, true
+		}
+	}
+	return 0, false
+}
+
+// Resolve resolves the embedding levels of a paragraph of text with base
+// level base, which is LeftToRight, RightToLeft or Auto.
+func Resolve(text []rune, base Level) *Paragraph {
+	p := &Paragraph{classes: classesOf(text)}
+	if base == Auto {
+		base, _ = firstStrong(p.classes, 0, len(text))
+	}
+	p.base = base & 1
+	r := resolver{
+		text:   text,
+		orig:   p.classes,
+		types:  slices.Clone(p.classes),
+		levels: make([]Level, len(text)),
+		base:   p.base,
+	}
+	r.matchIsolates()
+	r.explicit()
+	for _, seq := range r.sequences() {
+		r.resolveWeak(seq)
+		r.resolveBrackets(seq)
+		r.resolveNeutral(seq)
+		r.resolveImplicit(seq)
+	}
+	r.assignRemoved()
+	p.levels = r.levels
+	return p
+}
+
+// Len returns the number of characters in the paragraph.
+func (p *Paragraph) Len() int {
+	return len(p.levels)
+}
+
+// BaseLevel returns the paragraph embedding level.
+func (p *Paragraph) BaseLevel() Level {
+	return p.base
+}
+
+// Levels returns the resolved level of every character, before the line
+// rules. The slice must not be modified.
+func (p *Paragraph) Levels() []Level {
+	return p.levels
+}
+
+// IsMixed reports whether the paragraph has characters at more than one
+// level, and so needs reordering at all.
+func (p *Paragraph) IsMixed() bool {
+	for _, l := range p.levels {
+		if l != p.base {
+			return true
+		}
+	}
+	return false
+}
+
+// Run is a run of characters at one level, from Start up to End. The
+// characters of a run with an odd level are displayed right to left.
+type Run struct {
+	Start, End int
+	Level      Level
+}
+
+// IsRTL reports whether the run is displayed right to left.
+func (r Run) IsRTL() bool {
+	return r.Level.IsRTL()
+}
+
+// lineLevels returns the levels of the line start:end after rule L1.
+func (p *Paragraph) lineLevels(start, end int) []Level {
+	levels := slices.Clone(p.levels[start:end])
+	trailing := true
+	for i := end - 1; i >= start; i-- {
+		switch c := p.classes[i]; {
+		case c == S || c == B:
+			levels[i-start] = p.base
+			trailing = true
+		case trailing && (c == WS || c.isIsolateInitiator() || c == PDI || c.isRemoved()):
+			levels[i-start] = p.base
+		default:
+			trailing = false
+		}
+	}
+	return levels
+}
+
+// Runs returns the runs of the line from start up to end in visual order,
+// left to right. Runs of a line wrapped from the paragraph are taken from
+// its resolved levels, so a line break does not change their direction.
+func (p *Paragraph) Runs(start, end int) []Run {
+	start, end = max(0, start), min(end, len(p.levels))
+	if start >= end {
+		return nil
+	}
+	levels := p.lineLevels(start, end)
+	var runs []Run
+	var top, low Level = 0, 0xFF
+	for i, l := range levels {
+		if n := len(runs); n > 0 && runs[n-1].Level == l {
+			runs[n-1].End = start + i + 1
+			continue
+		}
+		runs = append(runs, Run{Start: start + i, End: start + i + 1, Level: l})
+		top, low = max(top, l), min(low, l)
+	}
+	// L2: from the highest level down to the lowest odd level, reverse
+	// every sequence of runs at that level or higher.
+	low |= 1
+	for l := top; l >= low && l > 0; l-- {
+		for i := 0; i < len(runs); {
+			if runs[i].Level < l {
+				i++
+				continue
+			}
+			j := i
+			for j < len(runs) && runs[j].Level >= l {
+				j++
+			}
+			slices.Reverse(runs[i:j])
+			i = j
+		}
+	}
+	return runs
+}
+
+// VisualOrder returns the logical index of the character displayed at
+// each position of the line from start up to end, left to right.
+func (p *Paragraph) VisualOrder(start, end int) []int {
+	var order []int
+	for _, r := range p.Runs(start, end) {
+		if r.IsRTL() {
+			for i := r.End - 1; i >= r.Start; i-- {
+				order = append(order, i)
+			}
+			continue
+		}
+		for i := r.Start; i < r.End; i++ {
+			order = append(order, i)
+		}
+	}
+	return order
+}
+
+// resolver holds the state of the algorithm for one paragraph.
+type resolver struct {
+	text   []rune
+	orig   []Class // The classes of the characters.
+	types  []Class // The classes as the rules change them.
+	levels []Level
+	base   Level
+
+	match   []int  // For an isolate initiator, its matching PDI or -1.
+	matched []bool // For a PDI, whether it matches an initiator.
+}
+
+// matchIsolates pairs isolate initiators with their PDIs (BD9).
+func (r *resolver) matchIsolates() {
+	r.match = make([]int, len(r.orig))
+	r.matched = make([]bool, len(r.orig))
+	var open []int
+	for i, c := range r.orig {
+		r.match[i] = -1
+		switch {
+		case c.isIsolateInitiator():
+			open = append(open, i)
+		case c == PDI && len(open) > 0:
+			r.match[open[len(open)-1]] = i
+			r.matched[i] = true
+			open = open[:len(open)-1]
+		}
+	}
+}
+
+type status struct {
+	level    Level
+	override Class // L, R, or ON for none.
+	isolate  bool
+}
+
+// explicit applies rules X1 to X8.
+func (r *resolver) explicit() {
+	stack := []status{{level: r.base, override: ON}}
+	overflowIsolates, overflowEmbeddings, validIsolates := 0, 0, 0
+	next := func(rtl bool) Level {
+		l := stack[len(stack)-1].level
+		if rtl {
+			return (l + 1) | 1
+		}
+		return (l + 2) &^ 1
+	}
+	overflowing := func() bool { return overflowIsolates > 0 || overflowEmbeddings > 0 }
+	for i, c := range r.orig {
+		top := stack[len(stack)-1]
+		switch c {
+		case RLE, LRE, RLO, LRO:
+			r.levels[i] = top.level
+			l := next(c == RLE || c == RLO)
+			switch {
+			case l <= maxDepth && !overflowing():
+				s := status{level: l, override: ON}
+				if c == RLO {
+					s.override = R
+				} else if c == LRO {
+					s.override = L
+				}
+				stack = append(stack, s)
+			case overflowIsolates == 0:
+				overflowEmbeddings++
+			}
+		case RLI, LRI, FSI:
+			r.levels[i] = top.level
+			if top.override != ON {
+				r.types[i] = top.override
+			}
+			rtl := c == RLI
+			if c == FSI {
+				end := r.match[i]
+				if end < 0 {
+					end = len(r.orig)
+				}
+				l, _ := firstStrong(r.orig, i+1, end)
+				rtl = l == RightToLeft
+			}
+			if l := next(rtl); l <= maxDepth && !overflowing() {
+				validIsolates++
+				stack = append(stack, status{level: l, override: ON, isolate: true})
+			} else {
+				overflowIsolates++
+			}
+		case PDI:
+			switch {
+			case overflowIsolates > 0:
+				overflowIsolates--
+			case validIsolates > 0:
+				overflowEmbeddings = 0
+				for !stack[len(stack)-1].isolate {
+					stack = stack[:len(stack)-1]
+				}
+				stack = stack[:len(stack)-1]
+				validIsolates--
+			}
+			top = stack[len(stack)-1]
+			r.levels[i] = top.level
+			if top.override != ON {
+				r.types[i] = top.override
+			}
+		case PDF:
+			switch {
+			case overflowIsolates > 0:
+			case overflowEmbeddings > 0:
+				overflowEmbeddings--
+			case !top.isolate && len(stack) > 1:
+				stack = stack[:len(stack)-1]
+			}
+			r.levels[i] = stack[len(stack)-1].level
+		case B:
+			r.levels[i] = r.base
+		case BN:
+			r.levels[i] = top.level
+		default:
+			r.levels[i] = top.level
+			if top.override != ON {
+				r.types[i] = top.override
+			}
+		}
+	}
+}
+
+// sequence is an isolating run sequence (BD13): the indices of its
+// characters and the types at its start and end.
+type sequence struct {
+	index    []int
+	level    Level
+	sos, eos Class
+}
+
+// sequences applies rules X9 and X10, returning the isolating run
+// sequences of the paragraph without the characters X9 removes.
+func (r *resolver) sequences() []sequence {
+	var runs [][]int
+	for i, c := range r.orig {
+		if c.isRemoved() {
+			continue
+		}
+		if n := len(runs); n > 0 {
+			last := runs[n-1]
+			if r.levels[last[len(last)-1]] == r.levels[i] {
+				runs[n-1] = append(last, i)
+				continue
+			}
+		}
+		runs = append(runs, []int{i})
+	}
+	startsAt := make(map[int]int, len(runs))
+	for k, run := range runs {
+		startsAt[run[0]] = k
+	}
+	var seqs []sequence
+	for _, run := range runs {
+		if first := run[0]; r.orig[first] == PDI && r.matched[first] {
+			continue // Continues the sequence of its initiator.
+		}
+		index := slices.Clone(run)
+		for {
+			last := index[len(index)-1]
+			if !r.orig[last].isIsolateInitiator() || r.match[last] < 0 {
+				break
+			}
+			k, ok := startsAt[r.match[last]]
+			if !ok {
+				break
+			}
+			index = append(index, runs[k]...)
+		}
+		seqs = append(seqs, r.sequence(index))
+	}
+	return seqs
+}
+
+func (r *resolver) sequence(index []int) sequence {
+	first, last := index[0], index[len(index)-1]
+	level := r.levels[first]
+	before, after := r.base, r.base
+	for i := first - 1; i >= 0; i-- {
+		if !r.orig[i].isRemoved() {
+			before = r.levels[i]
+			break
+		}
+	}
+	if !r.orig[last].isIsolateInitiator() || r.match[last] >= 0 {
+		for i := last + 1; i < len(r.orig); i++ {
+			if !r.orig[i].isRemoved() {
+				after = r.levels[i]
+				break
+			}
+		}
+	}
+	return sequence{
+		index: index,
+		level: level,
+		sos:   direction(max(level, before)),
+		eos:   direction(max(level, after)),
+	}
+}
+
+// direction returns the strong type of level l.
+func direction(l Level) Class {
+	if l.IsRTL() {
+		return R
+	}
+	return L
+}
+
+// strongBefore returns the first strong type before position k of seq,
+// or its sos.
+func (r *resolver) strongBefore(seq sequence, k int) Class {
+	for k--; k >= 0; k-- {
+		if t := r.types[seq.index[k]]; t.isStrong() {
+			return t
+		}
+	}
+	return seq.sos
+}
+
+// resolveWeak applies rules W1 to W7.
+func (r *resolver) resolveWeak(seq sequence) {
+	t := r.types
+	idx := seq.index
+	// W1.
+	for k, i := range idx {
+		if t[i] != NSM {
+			continue
+		}
+		switch {
+		case k == 0:
+			t[i] = seq.sos
+		case t[idx[k-1]].isIsolateInitiator() || t[idx[k-1]] == PDI:
+			t[i] = ON
+		default:
+			t[i] = t[idx[k-1]]
+		}
+	}
+	// W2 and W3.
+	last := seq.sos
+	for _, i := range idx {
+		switch t[i] {
+		case EN:
+			if last == AL {
+				t[i] = AN
+			}
+		case L, R:
+			last = t[i]
+		case AL:
+			last = AL
+			t[i] = R
+		}
+	}
+	// W4.
+	for k := 1; k+1 < len(idx); k++ {
+		prev, cur, next := t[idx[k-1]], t[idx[k]], t[idx[k+1]]
+		switch {
+		case cur == ES && prev == EN && next == EN:
+			t[idx[k]] = EN
+		case cur == CS && prev == next && (prev == EN || prev == AN):
+			t[idx[k]] = prev
+		}
+	}
+	// W5.
+	for k := 0; k < len(idx); {
+		if t[idx[k]] != ET {
+			k++
+			continue
+		}
+		j := k
+		for j < len(idx) && t[idx[j]] == ET {
+			j++
+		}
+		if (k > 0 && t[idx[k-1]] == EN) || (j < len(idx) && t[idx[j]] == EN) {
+			for ; k < j; k++ {
+				t[idx[k]] = EN
+			}
+		}
+		k = j
+	}
+	// W6.
+	for _, i := range idx {
+		switch t[i] {
+		case ES, ET, CS:
+			t[i] = ON
+		}
+	}
+	// W7.
+	last = seq.sos
+	for _, i := range idx {
+		switch t[i] {
+		case EN:
+			if last == L {
+				t[i] = L
+			}
+		case L, R:
+			last = t[i]
+		}
+	}
+}
+
+// maxBrackets is the depth of the bracket stack of BD16; pairing stops
+// for the rest of the sequence when it overflows.
+const maxBrackets = 63
+
+// resolveBrackets applies rule N0 to the bracket pairs of seq.
+func (r *resolver) resolveBrackets(seq sequence) {
+	t := r.types
+	idx := seq.index
+	type opener struct {
+		close rune
+		k     int
+	}
+	var stack []opener
+	var pairs [][2]int
+scan:
+	for k, i := range idx {
+		if t[i] != ON {
+			continue
+		}
+		ch := canonicalBracket(r.text[i])
+		if close, ok := openBracket(ch); ok {
+			if len(stack) == maxBrackets {
+				break scan
+			}
+			stack = append(stack, opener{close: canonicalBracket(close), k: k})
+			continue
+		}
+		if !isCloseBracket(ch) {
+			continue
+		}
+		for s := len(stack) - 1; s >= 0; s-- {
+			if stack[s].close == ch {
+				pairs = append(pairs, [2]int{stack[s].k, k})
+				stack = stack[:s]
+				break
+			}
+		}
+	}
+	slices.SortFunc(pairs, func(a, b [2]int) int { return a[0] - b[0] })
+
+	embedding := direction(seq.level)
+	strong := func(c Class) Class {
+		switch c {
+		case L:
+			return L
+		case R, AL, EN, AN:
+			return R
+		}
+		return ON
+	}
+	for _, pr := range pairs {
+		found := ON
+		for k := pr[0] + 1; k < pr[1]; k++ {
+			if s := strong(t[idx[k]]); s == embedding {
+				found = embedding
+				break
+			} else if s != ON {
+				found = s
+			}
+		}
+		if found == ON {
+			continue
+		}
+		if found != embedding {
+			context := seq.sos
+			for k := pr[0] - 1; k >= 0; k-- {
+				if s := strong(t[idx[k]]); s != ON {
+					context = s
+					break
+				}
+			}
+			if context != found {
+				found = embedding
+			}
+		}
+		for _, k := range pr {
+			t[idx[k]] = found
+			for j := k + 1; j < len(idx) && r.orig[idx[j]] == NSM; j++ {
+				t[idx[j]] = found
+			}
+		}
+	}
+}
+
+// resolveNeutral applies rules N1 and N2.
+func (r *resolver) resolveNeutral(seq sequence) {
+	t := r.types
+	idx := seq.index
+	side := func(c Class) Class {
+		if c == EN || c == AN {
+			return R
+		}
+		return c
+	}
+	embedding := direction(seq.level)
+	for k := 0; k < len(idx); {
+		if !t[idx[k]].isNeutral() {
+			k++
+			continue
+		}
+		j := k
+		for j < len(idx) && t[idx[j]].isNeutral() {
+			j++
+		}
+		before, after := seq.sos, seq.eos
+		if k > 0 {
+			before = side(t[idx[k-1]])
+		}
+		if j < len(idx) {
+			after = side(t[idx[j]])
+		}
+		dir := embedding
+		if before == after {
+			dir = before
+		}
+		for ; k < j; k++ {
+			t[idx[k]] = dir
+		}
+	}
+}
+
+// resolveImplicit applies rules I1 and I2.
+func (r *resolver) resolveImplicit(seq sequence) {
+	for _, i := range seq.index {
+		t := r.types[i]
+		if !r.levels[i].IsRTL() {
+			switch t {
+			case R:
+				r.levels[i]++
+			case AN, EN:
+				r.levels[i] += 2
+			}
+			continue
+		}
+		switch t {
+		case L, EN, AN:
+			r.levels[i]++
+		}
+	}
+}
+
+// assignRemoved gives the characters X9 removed the level of the
+// character before them, so that they stay with it when reordered.
+func (r *resolver) assignRemoved() {
+	prev := r.base
+	for i, c := range r.orig {
+		if c.isRemoved() {
+			r.levels[i] = prev
+		}
+		prev = r.levels[i]
+	}
+}
@@ -0,0 +1,195 @@
+package bidi
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// Characters for the tests: Hebrew letters are R, Arabic letters AL and
+// Arabic-Indic digits AN.
+const (
+	alef   = "א"
+	bet    = "ב"
+	gimel  = "ג"
+	ain    = "ع"
+	one    = "١"
+	two    = "٢"
+	lre    = "\u202A"
+	rle    = "\u202B"
+	pdf    = "\u202C"
+	lro    = "\u202D"
+	rlo    = "\u202E"
+	lri    = "\u2066"
+	rli    = "\u2067"
+	fsi    = "\u2068"
+	pdi    = "\u2069"
+	zwj    = "\u200D" // BN.
+	hebrew = alef + bet + gimel
+)
+
+// bidiTests are in the form of BidiCharacterTest.txt: the text, the
+// paragraph direction, the resolved paragraph level, the levels after
+// rule L1 with x for the characters X9 removes, and the visual order of
+// the characters not removed.
+var bidiTests = []struct {
+	text   string
+	base   Level
+	para   Level
+	levels string
+	order  string
+}{
+	// Plain runs.
+	{"abc", Auto, 0, "0 0 0", "0 1 2"},
+	{hebrew, Auto, 1, "1 1 1", "2 1 0"},
+	{"ab " + alef + bet + " cd", LeftToRight, 0, "0 0 0 1 1 0 0 0", "0 1 2 4 3 5 6 7"},
+	{"ab " + alef + bet + " cd", RightToLeft, 1, "2 2 1 1 1 1 2 2", "6 7 5 4 3 2 0 1"},
+
+	// Numbers: European digits after Latin are L (W7), after Hebrew they
+	// stay EN, after Arabic they become AN (W2); both raise a level.
+	{"ab 12", Auto, 0, "0 0 0 0 0", "0 1 2 3 4"},
+	{alef + bet + " 12", Auto, 1, "1 1 1 2 2", "3 4 2 1 0"},
+	{ain + " 1,2", Auto, 1, "1 1 2 2 2", "2 3 4 1 0"},
+	{"a " + one + two, LeftToRight, 0, "0 0 2 2", "0 1 2 3"},
+	{alef + " 1.5%", Auto, 1, "1 1 2 2 2 2", "2 3 4 5 1 0"},
+
+	// Paired brackets (N0) resolve to the direction of what they enclose
+	// or of the text before them; an unpaired one is a plain neutral.
+	{alef + "(" + bet + ")", Auto, 1, "1 1 1 1", "3 2 1 0"},
+	{"a(" + bet + ")c", LeftToRight, 0, "0 0 1 0 0", "0 1 2 3 4"},
+	{"ab(cd)", RightToLeft, 1, "2 2 2 2 2 2", "0 1 2 3 4 5"},
+	{alef + "(b", Auto, 1, "1 1 2", "2 1 0"},
+	{"a(b" + alef + "]", LeftToRight, 0, "0 0 0 1 0", "0 1 2 3 4"},
+
+	// Explicit embeddings and overrides.
+	{"a" + rle + "bc" + pdf + "d", LeftToRight, 0, "0 x 2 2 x 0", "0 2 3 5"},
+	{"a" + rlo + "bc" + pdf + "d", LeftToRight, 0, "0 x 1 1 x 0", "0 3 2 5"},
+	{alef + lre + bet + gimel + pdf, Auto, 1, "1 x 3 3 x", "3 2 0"},
+	{alef + lro + bet + gimel + pdf, Auto, 1, "1 x 2 2 x", "2 3 0"},
+	{"a" + zwj + "b", LeftToRight, 0, "0 x 0", "0 2"},
+	// An embedding left open ends with the paragraph, and its trailing
+	// whitespace goes back to the paragraph level (L1).
+	{"a" + rle + alef + " ", LeftToRight, 0, "0 x 1 0", "0 2 3"},
+
+	// Isolates.
+	{alef + " " + lri + "a" + bet + pdi + " " + gimel, Auto, 1, "1 1 1 2 3 1 1 1", "7 6 5 3 4 2 1 0"},
+	{lri + "a" + pdi + alef, Auto, 1, "1 2 1 1", "3 2 1 0"},
+	{"a " + rli + "bc" + pdi, LeftToRight, 0, "0 0 0 2 2 0", "0 1 2 3 4 5"},
+	{"a " + fsi + alef + "b" + pdi, LeftToRight, 0, "0 0 0 1 2 0", "0 1 2 4 3 5"},
+}
+
+func TestResolve(t *testing.T) {
+	for _, tt := range bidiTests {
+		text := []rune(tt.text)
+		name := fmt.Sprintf("%+q base %d", tt.text, tt.base)
+		p := Resolve(text, tt.base)
+		if p.BaseLevel() != tt.para {
+			t.Errorf("%s: paragraph level %d, want %d", name, p.BaseLevel(), tt.para)
+		}
+		want := strings.Fields(tt.levels)
+		if len(want) != len(text) {
+			t.Fatalf("%s: %d levels for %d characters", name, len(want), len(text))
+		}
+		levels := p.lineLevels(0, p.Len())
+		var got []string
+		var order []string
+		for i, l := range levels {
+			if p.classes[i].isRemoved() {
+				got = append(got, "x")
+				continue
+			}
+			got = append(got, fmt.Sprint(l))
+		}
+		for _, i := range p.VisualOrder(0, p.Len()) {
+			if !p.classes[i].isRemoved() {
+				order = append(order, fmt.Sprint(i))
+			}
+		}
+		if g := strings.Join(got, " "); g != strings.Join(want, " ") {
+			t.Errorf("%s: levels %s, want %s", name, g, tt.levels)
+		}
+		if g := strings.Join(order, " "); g != tt.order {
+			t.Errorf("%s: visual order %s, want %s", name, g, tt.order)
+		}
+	}
+}
+
+func TestRuns(t *testing.T) {
+	// "ab אב 12 cd" in a left-to-right paragraph: the number after the
+	// Hebrew is shown to its left, both read right to left.
+	p := Resolve([]rune("ab "+alef+bet+" 12 cd"), LeftToRight)
+	want := []Run{{0, 3, 0}, {6, 8, 2}, {3, 6, 1}, {8, 11, 0}}
+	got := p.Runs(0, p.Len())
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Runs() = %v, want %v", got, want)
+	}
+
+	// A line wrapped from the paragraph keeps the resolved levels, but
+	// its trailing whitespace goes back to the paragraph level.
+	if got, want := p.Runs(3, 6), []Run{{3, 5, 1}, {5, 6, 0}}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Runs(3, 6) = %v, want %v", got, want)
+	}
+	if got := p.Runs(4, 4); got != nil {
+		t.Errorf("Runs of an empty line = %v", got)
+	}
+}
+
+func TestFirstStrong(t *testing.T) {
+	tests := []struct {
+		text string
+		want Level
+		ok   bool
+	}{
+		{"abc", LeftToRight, true},
+		{"12 " + alef, RightToLeft, true},
+		{ain, RightToLeft, true},
+		{lri + "a" + pdi + alef, RightToLeft, true}, // Isolated text is skipped.
+		{"123 !", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := FirstStrong([]rune(tt.text))
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("FirstStrong(%+q) = %d, %v, want %d, %v", tt.text, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestHasRTL(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"plain text 123", false},
+		{"a " + alef, true},
+		{"1 " + one, true},
+		{"a" + rli + "b" + pdi, true},
+	}
+	for _, tt := range tests {
+		if got := HasRTL(tt.text); got != tt.want {
+			t.Errorf("HasRTL(%+q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestMirror(t *testing.T) {
+	tests := []struct {
+		r, want rune
+		ok      bool
+	}{
+		{'(', ')', true},
+		{')', '(', true},
+		{'[', ']', true},
+		{'<', '>', true},
+		{0x00AB, 0x00BB, true}, // Guillemets.
+		{0x2264, 0x2265, true}, // Less-than or equal.
+		{'a', 0, false},
+		{'-', 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := Mirror(tt.r)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Mirror(%q) = %q, %v, want %q, %v", tt.r, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package bidi
+
+// Class is the bidirectional character type of a character (Bidi_Class).
+type Class uint8
+
+// Bidirectional character types.
+const (
+	L   Class = iota // Left to right.
+	R                // Right to left.
+	AL               // Arabic letter.
+	EN               // European number.
+	ES               // European separator.
+	ET               // European terminator.
+	AN               // Arabic number.
+	CS               // Common separator.
+	NSM              // Nonspacing mark.
+	BN               // Boundary neutral.
+	B                // Paragraph separator.
+	S                // Segment separator.
+	WS               // Whitespace.
+	ON               // Other neutral.
+	LRE              // Left-to-right embedding.
+	LRO              // Left-to-right override.
+	RLE              // Right-to-left embedding.
+	RLO              // Right-to-left override.
+	PDF              // Pop directional format.
+	LRI              // Left-to-right isolate.
+	RLI              // Right-to-left isolate.
+	FSI              // First strong isolate.
+	PDI              // Pop directional isolate.
+)
+
+type classRange struct {
+	lo, hi rune
+	class  Class
+}
+
+func find(table []classRange, r rune) (Class, bool) {
+	lo, hi := 0, len(table)
+	for lo < hi {
+		m := (lo + hi) / 2
+		switch {
+		case r < table[m].lo:
+			hi = m
+		case r > table[m].hi:
+			lo = m + 1
+		default:
+			return table[m].class, true
+		}
+	}
+	return 0, false
+}
+
+// ClassOf returns the bidirectional type of r.
+func ClassOf(r rune) Class {
+	if c, ok := find(classes, r); ok {
+		return c
+	}
+	return L
+}
+
+func (c Class) isStrong() bool {
+	return c == L || c == R || c == AL
+}
+
+func (c Class) isIsolateInitiator() bool {
+	return c == LRI || c == RLI || c == FSI
+}
+
+// isRemoved reports whether rule X9 removes characters of class c.
+func (c Class) isRemoved() bool {
+	switch c {
+	case LRE, RLE, LRO, RLO, PDF, BN:
+		return true
+	}
+	return false
+}
+
+// isNeutral reports whether c is a neutral or isolate formatting
+// character for rules N1 and N2.
+func (c Class) isNeutral() bool {
+	switch c {
+	case B, S, WS, ON, LRI, RLI, FSI, PDI:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"errors"
+	"image"
+	"image/draw"
+	"time"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/render/raster"
+	"github.com/gogpu/ui/theme"
+)
+
+// ErrCaptureUnsupported is returned by captures when the layers of the
+// canvas set with WithCaptureCanvas cannot be read back.
+var ErrCaptureUnsupported = errors.New("ui: capture canvas layers are not readable")
+
+// WithCaptureCanvas makes CaptureFrame and Snapshot draw into layers of
+// cv, usually the canvas frames are painted into, so that captures match
+// them pixel for pixel. Its layers must implement core.ReadableLayer.
+// Without it captures are drawn by package raster, with text in the fonts
+// of WithFonts.
+func WithCaptureCanvas(cv core.LayerCanvas) Option {
+	return func(w *Window) {
+		w.capture = cv
+	}
+}
+
+// CaptureFrame returns an image of the window in device pixels, at its
+// scale factor: the tree over the theme's background and the overlays
+// inside the window, for bug reports, documentation and golden tests.
+// Overlays shown in surfaces of the PopupHost are left out.
+func (w *Window) CaptureFrame() (image.Image, error) {
+	w.layoutCapture()
+	client := w.clientRect()
+	return w.paintCapture(client.Size(), func(pc *core.PaintContext) {
+		pc.Canvas.DrawRect(client, core.Filled(theme.From(w.ctx).Colors.Background))
+		if w.root == nil {
+			return
+		}
+		w.root.Paint(pc)
+		w.paintOverlays(pc)
+	})
+}
+
+// Snapshot returns an image of wd and its subtree, which must be in the
+// window's tree or an overlay, on a transparent background. The image
+// covers the bounds of wd, so what it draws outside them is cut off.
+func (w *Window) Snapshot(wd core.Widget) (image.Image, error) {
+	w.layoutCapture()
+	b := wd.Bounds()
+	return w.paintCapture(b.Size(), func(pc *core.PaintContext) {
+		pc.Canvas.Translate(-b.X, -b.Y)
+		wd.Paint(pc)
+	})
+}
+
+// layoutCapture brings the layout up to date for a capture. The damage
+// the layout collects belongs to the next frame, which therefore paints
+// the whole window.
+func (w *Window) layoutCapture() {
+	w.layout(time.Now())
+	w.full = true
+}
+
+// paintCapture returns an image of size logical pixels drawn by paint.
+func (w *Window) paintCapture(size core.Size, paint func(pc *core.PaintContext)) (image.Image, error) {
+	if w.capture == nil {
+		cv := raster.NewImage(size, w.ctx.ScaleFactor()).TextMeasurer(w.ctx).Fonts(w.fontManager())
+		cv.SetAntialiasing(w.aa)
+		paint(&core.PaintContext{Context: w.ctx, Canvas: cv, Capture: true})
+		return cv.Image(), nil
+	}
+	l := w.capture.NewLayer(size)
+	defer l.Release()
+	rl, ok := l.(core.ReadableLayer)
+	if !ok {
+		return nil, ErrCaptureUnsupported
+	}
+	cv := l.Begin()
+	if ac, ok := cv.(core.AntialiasCanvas); ok {
+		ac.SetAntialiasing(w.aa)
+	}
+	paint(&core.PaintContext{Context: w.ctx, Canvas: cv, Capture: true})
+	l.End()
+	px, err := rl.ReadPixels()
+	if err != nil {
+		return nil, err
+	}
+	// The pixels belong to the layer, which is released.
+	img := image.NewRGBA(px.Bounds())
+	draw.Draw(img, img.Bounds(), px, px.Bounds().Min, draw.Src)
+	return img, nil
+}
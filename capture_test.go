@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"errors"
+	"image"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/font"
+	"github.com/gogpu/ui/render/raster"
+)
+
+// unreadable is a canvas whose layers cannot be read back.
+type unreadable struct {
+	*raster.Canvas
+}
+
+func (c unreadable) NewLayer(size core.Size) core.Layer {
+	return struct{ core.Layer }{c.Canvas.NewLayer(size)}
+}
+
+func TestCapture(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []Option
+		scale float32
+	}{
+		{"raster", nil, 1},
+		{"raster at the scale factor", []Option{WithScaleFactor(1.5)}, 1.5},
+		// Layers are made at the scale of the capture canvas.
+		{"capture canvas", []Option{WithScaleFactor(1.5), WithCaptureCanvas(raster.NewImage(core.Sz(1, 1), 2))}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := newPane(), newPane()
+			w := NewWindow(newPane(a, b), append(tt.opts, WithFonts(font.NewManager()))...)
+			w.Resize(core.Sz(200, 100))
+			frame, err := w.CaptureFrame()
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkCapture(t, "CaptureFrame()", frame, 200*tt.scale, 100*tt.scale)
+			snap, err := w.Snapshot(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkCapture(t, "Snapshot()", snap, 100*tt.scale, 100*tt.scale)
+		})
+	}
+}
+
+// checkCapture checks that img is width by height and painted white all
+// over by the panes.
+func checkCapture(t *testing.T, name string, img image.Image, width, height float32) {
+	t.Helper()
+	b := img.Bounds()
+	if float32(b.Dx()) != width || float32(b.Dy()) != height {
+		t.Fatalf("%s: image of %v, want %vx%v", name, b.Size(), width, height)
+	}
+	for _, p := range []image.Point{b.Min, b.Max.Sub(image.Pt(1, 1)), image.Pt(b.Dx()/2, b.Dy()/2)} {
+		if r, _, _, a := img.At(p.X, p.Y).RGBA(); r != 0xFFFF || a != 0xFFFF {
+			t.Errorf("%s: pixel at %v is %v, want white", name, p, img.At(p.X, p.Y))
+		}
+	}
+}
+
+func TestCaptureUnsupported(t *testing.T) {
+	a := newPane()
+	w := NewWindow(newPane(a), WithCaptureCanvas(unreadable{raster.NewImage(core.Sz(1, 1), 1)}))
+	w.Resize(core.Sz(50, 50))
+	if _, err := w.CaptureFrame(); !errors.Is(err, ErrCaptureUnsupported) {
+		t.Errorf("CaptureFrame() error = %v, want ErrCaptureUnsupported", err)
+	}
+	if _, err := w.Snapshot(a); !errors.Is(err, ErrCaptureUnsupported) {
+		t.Errorf("Snapshot() error = %v, want ErrCaptureUnsupported", err)
+	}
+}
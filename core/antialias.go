@@ -0,0 +1,57 @@
+package core
+
+// Antialiasing selects how a canvas smooths the edges of what it draws.
+// The zero Antialiasing is analytic. In every mode, lines thinner than a
+// device pixel are drawn a pixel wide, and fainter where edges are
+// smoothed, so that they do not break up.
+type Antialiasing struct {
+	Mode AntialiasMode
+
+	// Samples is the number of samples taken of each pixel by
+	// AntialiasMSAA: 2, 4 or 8, and 4 when 0.
+	Samples int
+}
+
+// AntialiasMode is the method of Antialiasing.
+type AntialiasMode uint8
+
+const (
+	// AntialiasAnalytic computes the coverage of each pixel from the
+	// edges of the shape, smooth at any angle.
+	AntialiasAnalytic AntialiasMode = iota
+
+	// AntialiasMSAA samples each pixel several times, which GPU backends
+	// do in hardware and which also smooths the edges of custom effects,
+	// at the cost of memory for the samples.
+	AntialiasMSAA
+
+	// AntialiasNone draws each pixel fully or not at all, for pixel art
+	// and for exact comparisons of captures.
+	AntialiasNone
+)
+
+// SampleCount returns the number of samples taken of each pixel, which
+// surfaces using a GPU must be created with: one unless MSAA.
+func (a Antialiasing) SampleCount() int {
+	if a.Mode != AntialiasMSAA {
+		return 1
+	}
+	switch {
+	case a.Samples <= 0:
+		return 4
+	case a.Samples <= 2:
+		return 2
+	case a.Samples <= 4:
+		return 4
+	}
+	return 8
+}
+
+// AntialiasCanvas is implemented by canvases whose antialiasing can be
+// chosen, which windows set before each frame.
+type AntialiasCanvas interface {
+	Canvas
+
+	// SetAntialiasing sets the antialiasing of what is drawn next.
+	SetAntialiasing(a Antialiasing)
+}
@@ -0,0 +1,23 @@
+package core
+
+import "testing"
+
+func TestAntialiasingSampleCount(t *testing.T) {
+	tests := []struct {
+		aa   Antialiasing
+		want int
+	}{
+		{Antialiasing{}, 1},
+		{Antialiasing{Mode: AntialiasNone, Samples: 8}, 1},
+		{Antialiasing{Mode: AntialiasMSAA}, 4},
+		{Antialiasing{Mode: AntialiasMSAA, Samples: 1}, 2},
+		{Antialiasing{Mode: AntialiasMSAA, Samples: 3}, 4},
+		{Antialiasing{Mode: AntialiasMSAA, Samples: 8}, 8},
+		{Antialiasing{Mode: AntialiasMSAA, Samples: 16}, 8},
+	}
+	for _, tt := range tests {
+		if got := tt.aa.SampleCount(); got != tt.want {
+			t.Errorf("%+v.SampleCount() = %d, want %d", tt.aa, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package core
+
+import "image"
+
+// Canvas is the drawing surface widgets paint into.
+//
+// Coordinates are logical pixels in window space. Implementations map them to
+// device pixels and may batch or record commands; widgets must not assume a
+// command is rasterized before Paint returns.
+type Canvas interface {
+	// DrawRect fills and/or strokes a rectangle.
+	DrawRect(rect Rect, style RectStyle)
+
+	// DrawRoundedRect fills and/or strokes a rectangle with rounded corners.
+	DrawRoundedRect(rect Rect, radius float32, style RectStyle)
+
+	// DrawText draws a single line of text. pos is the top-left corner of the
+	// line box; the baseline sits at pos.Y plus the font ascent.
+	DrawText(text string, pos Point, style TextStyle)
+
+	// DrawImage draws img scaled to fill rect.
+	DrawImage(img image.Image, rect Rect)
+
+	// DrawPath fills and/or strokes an arbitrary path.
+	DrawPath(path *Path, style PathStyle)
+
+	// Save pushes the current transform and clip onto a stack.
+	Save()
+
+	// Restore pops the state pushed by the matching Save.
+	Restore()
+
+	// Translate offsets subsequent drawing by (x, y).
+	Translate(x, y float32)
+
+	// Clip intersects the current clip with rect.
+	Clip(rect Rect)
+}
+
+// RectStyle describes how rectangles are filled and stroked. A transparent
+// Fill or a zero StrokeWidth disables the respective operation.
+//
+// FillGradient and StrokeGradient, if set, fill and stroke with a
+// gradient in place of the colors, which canvases without gradients use
+// instead; see GradientFilled.
+type RectStyle struct {
+	Fill        Color
+	Stroke      Color
+	StrokeWidth float32
+
+	FillGradient   *Gradient
+	StrokeGradient *Gradient
+}
+
+// Filled returns a RectStyle that only fills with c.
+func Filled(c Color) RectStyle {
+	return RectStyle{Fill: c}
+}
+
+// Stroked returns a RectStyle that only strokes with c at the given width.
+func Stroked(c Color, width float32) RectStyle {
+	return RectStyle{Stroke: c, StrokeWidth: width}
+}
+
+// PathStyle describes how paths are filled and stroked, with gradients
+// as in RectStyle.
+type PathStyle struct {
+	Fill        Color
+	Stroke      Color
+	StrokeWidth float32
+	FillRule    FillRule
+	LineCap     LineCap
+	LineJoin    LineJoin
+
+	FillGradient   *Gradient
+	StrokeGradient *Gradient
+}
+
+// FillRule selects how path interiors are determined.
+type FillRule uint8
+
+// Fill rules.
+const (
+	FillNonZero FillRule = iota
+	FillEvenOdd
+)
+
+// LineCap selects the shape of open stroke ends.
+type LineCap uint8
+
+// Line caps.
+const (
+	CapButt LineCap = iota
+	CapRound
+	CapSquare
+)
+
+// LineJoin selects the shape of stroke corners.
+type LineJoin uint8
+
+// Line joins.
+const (
+	JoinMiter LineJoin = iota
+	JoinRound
+	JoinBevel
+)
+
+// FontWeight is a CSS-style font weight in the range 100–900.
+type FontWeight uint16
+
+// Standard font weights.
+const (
+	WeightThin     FontWeight = 100
+	WeightLight    FontWeight = 300
+	WeightRegular  FontWeight = 400
+	WeightMedium   FontWeight = 500
+	WeightSemiBold FontWeight = 600
+	WeightBold     FontWeight = 700
+	WeightBlack    FontWeight = 900
+)
+
+// TextStyle describes how text is rendered and measured.
+type TextStyle struct {
+	// Family is the font family name. Empty selects the default UI font.
+	Family string
+
+	// Size is the font size in logical pixels.
+	Size float32
+
+	// Weight is the font weight. Zero means WeightRegular.
+	Weight FontWeight
+
+	// Italic selects the italic or oblique face.
+	Italic bool
+
+	// Stretch is the width of the glyphs as a percentage of normal, from
+	// 50 for ultra-condensed to 200 for ultra-expanded, set on the wdth
+	// axis of variable fonts. Zero means 100.
+	Stretch float32
+
+	// Slant is the angle in degrees glyphs lean to the right, set on the
+	// slnt axis of variable fonts.
+	Slant float32
+
+	// Variations sets further axes of variable fonts, in the syntax of
+	// CSS font-variation-settings without the quotes, such as
+	// "GRAD 50, opsz 14". See ParseVariations.
+	Variations string
+
+	// Color is the text color.
+	Color Color
+}
+
+// LineHeight returns the line box height for the style.
+func (s TextStyle) LineHeight() float32 {
+	return s.Size * 1.25
+}
+
+// Ascent returns the distance from the top of the line box to the
+// baseline, where DrawText puts the baseline.
+func (s TextStyle) Ascent() float32 {
+	return s.Size * 0.95
+}
@@ -0,0 +1,41 @@
+package core
+
+// PathClipCanvas is implemented by canvases that can clip to paths, with
+// antialiased edges.
+type PathClipCanvas interface {
+	Canvas
+
+	// ClipPath intersects the current clip with the inside of p by rule.
+	// As with Clip, nested clips intersect, and the clip lasts until the
+	// Restore matching the last Save.
+	ClipPath(p *Path, rule FillRule)
+}
+
+// ClipPath intersects the clip of cv with the inside of p by rule, and
+// reports whether the clip follows the path. Canvases that do not
+// implement PathClipCanvas clip to the bounds of p instead.
+func ClipPath(cv Canvas, p *Path, rule FillRule) bool {
+	if pc, ok := cv.(PathClipCanvas); ok {
+		pc.ClipPath(p, rule)
+		return true
+	}
+	cv.Clip(p.Bounds())
+	return false
+}
+
+// ClipRoundedRect intersects the clip of cv with r with corners rounded by
+// radius, as ClipPath does. Without rounded corners it clips to r, which
+// every canvas does exactly.
+func ClipRoundedRect(cv Canvas, r Rect, radius float32) bool {
+	if radius <= 0 {
+		cv.Clip(r)
+		return true
+	}
+	return ClipPath(cv, NewPath().AddRoundedRect(r, radius), FillNonZero)
+}
+
+// ClipEllipse intersects the clip of cv with the ellipse inscribed in r, as
+// ClipPath does.
+func ClipEllipse(cv Canvas, r Rect) bool {
+	return ClipPath(cv, NewPath().AddEllipse(r), FillNonZero)
+}
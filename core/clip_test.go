@@ -0,0 +1,37 @@
+package core
+
+import "testing"
+
+// clipped records the clips of a canvas without path clips.
+type clipped struct {
+	plainCanvas
+	clips []Rect
+}
+
+func (c *clipped) Clip(r Rect) { c.clips = append(c.clips, r) }
+
+func TestClipPath(t *testing.T) {
+	r := R(10, 10, 40, 20)
+	tests := []struct {
+		name  string
+		clip  func(cv Canvas) bool
+		exact bool     // On canvases without path clips.
+		kind  drawKind // Recorded by canvases with them.
+	}{
+		{"path", func(cv Canvas) bool { return ClipPath(cv, NewPath().AddRect(r), FillNonZero) }, false, opClipPath},
+		{"rounded", func(cv Canvas) bool { return ClipRoundedRect(cv, r, 4) }, false, opClipPath},
+		{"square", func(cv Canvas) bool { return ClipRoundedRect(cv, r, 0) }, true, opClip},
+		{"ellipse", func(cv Canvas) bool { return ClipEllipse(cv, r) }, false, opClipPath},
+	}
+	for _, tt := range tests {
+		// Canvases without path clips clip to the bounds of the shape.
+		plain := &clipped{}
+		if got := tt.clip(plain); got != tt.exact || len(plain.clips) != 1 || !near(plain.clips[0], r) {
+			t.Errorf("%s: clipped to %v, reporting %v", tt.name, plain.clips, got)
+		}
+		var rec Recording
+		if !tt.clip(&rec) || rec.Len() != 1 || rec.ops[0].kind != tt.kind {
+			t.Errorf("%s: recorded %v, want a clip of kind %v", tt.name, rec.ops, tt.kind)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package core
+
+// Color is a non-premultiplied RGBA color with components in [0, 1].
+type Color struct {
+	R, G, B, A float32
+}
+
+// Common colors.
+var (
+	Transparent = Color{}
+	Black       = Color{A: 1}
+	White       = Color{R: 1, G: 1, B: 1, A: 1}
+)
+
+// RGB returns an opaque color from 8-bit components.
+func RGB(r, g, b uint8) Color {
+	return RGBA(r, g, b, 255)
+}
+
+// RGBA returns a color from 8-bit components.
+func RGBA(r, g, b, a uint8) Color {
+	return Color{R: float32(r) / 255, G: float32(g) / 255, B: float32(b) / 255, A: float32(a) / 255}
+}
+
+// Hex returns an opaque color from a 0xRRGGBB value.
+func Hex(v uint32) Color {
+	return RGB(uint8(v>>16), uint8(v>>8), uint8(v))
+}
+
+// WithAlpha returns c with its alpha replaced by a.
+func (c Color) WithAlpha(a float32) Color {
+	c.A = a
+	return c
+}
+
+// IsTransparent reports whether the color has zero alpha.
+func (c Color) IsTransparent() bool {
+	return c.A <= 0
+}
+
+// Lerp linearly interpolates between c and to in sRGB space.
+func (c Color) Lerp(to Color, t float32) Color {
+	return Color{
+		R: c.R + (to.R-c.R)*t,
+		G: c.G + (to.G-c.G)*t,
+		B: c.B + (to.B-c.B)*t,
+		A: c.A + (to.A-c.A)*t,
+	}
+}
+
+// RGBA8 returns the color as 8-bit components.
+func (c Color) RGBA8() (r, g, b, a uint8) {
+	conv := func(v float32) uint8 {
+		return uint8(Clamp(v, 0, 1)*255 + 0.5)
+	}
+	return conv(c.R), conv(c.G), conv(c.B), conv(c.A)
+}
@@ -0,0 +1,42 @@
+package core
+
+import "testing"
+
+func TestColorConstructors(t *testing.T) {
+	tests := []struct {
+		name string
+		c    Color
+		want [4]uint8
+	}{
+		{"rgb", RGB(255, 128, 0), [4]uint8{255, 128, 0, 255}},
+		{"rgba", RGBA(1, 2, 3, 4), [4]uint8{1, 2, 3, 4}},
+		{"hex", Hex(0x336699), [4]uint8{0x33, 0x66, 0x99, 255}},
+		{"white", White, [4]uint8{255, 255, 255, 255}},
+		{"transparent", Transparent, [4]uint8{}},
+		{"out of range", Color{R: 2, G: -1, B: 0.5, A: 1}, [4]uint8{255, 0, 128, 255}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, g, b, a := tt.c.RGBA8()
+			if got := [4]uint8{r, g, b, a}; got != tt.want {
+				t.Errorf("RGBA8 = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColorLerpAlpha(t *testing.T) {
+	mid := Black.Lerp(White, 0.5)
+	if mid != (Color{R: 0.5, G: 0.5, B: 0.5, A: 1}) {
+		t.Errorf("Lerp = %v", mid)
+	}
+	if Black.Lerp(White, 0) != Black || Black.Lerp(White, 1) != White {
+		t.Error("Lerp ends are not the colors")
+	}
+	if c := White.WithAlpha(0.25); c.A != 0.25 || c.R != 1 {
+		t.Errorf("WithAlpha = %v", c)
+	}
+	if !Transparent.IsTransparent() || Black.IsTransparent() || !White.WithAlpha(0).IsTransparent() {
+		t.Error("IsTransparent wrong")
+	}
+}
@@ -0,0 +1,50 @@
+package core
+
+import "testing"
+
+func TestBlendMode(t *testing.T) {
+	tests := []struct {
+		mode       BlendMode
+		s, d, want float32
+	}{
+		{BlendNormal, 0.25, 0.5, 0.25},
+		{BlendMultiply, 0.25, 0.5, 0.125},
+		{BlendScreen, 0.25, 0.5, 0.625},
+		{BlendOverlay, 0.25, 0.5, 0.25},
+		{BlendOverlay, 0.25, 0.75, 0.625},
+		{BlendDarken, 0.25, 0.5, 0.25},
+		{BlendLighten, 0.25, 0.5, 0.5},
+	}
+	for _, tt := range tests {
+		if got := tt.mode.Blend(tt.s, tt.d); got != tt.want {
+			t.Errorf("mode %d: Blend(%v, %v) = %v, want %v", tt.mode, tt.s, tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestFade(t *testing.T) {
+	red := RGB(255, 0, 0)
+	g := LinearGradient(Pt(0, 0), Pt(1, 0), Stop(0, red), Stop(1, White.WithAlpha(0.5)))
+	p := NewPath().AddRect(R(0, 0, 5, 5))
+	var got, want Recording
+	cv := Fade(Fade(&got, 0.5), 0.5)
+	cv.DrawRect(R(0, 0, 10, 10), RectStyle{Fill: red, Stroke: Black, StrokeWidth: 1})
+	cv.DrawRoundedRect(R(0, 0, 10, 10), 2, GradientFilled(g))
+	cv.DrawText("a", Pt(1, 2), TextStyle{Size: 12, Color: Black})
+	cv.DrawPath(p, PathStyle{Stroke: red, StrokeWidth: 2, StrokeGradient: g})
+	ClipPath(cv, p, FillEvenOdd)
+
+	// Nested fades multiply, and every color is faded, of gradients too.
+	faded := LinearGradient(Pt(0, 0), Pt(1, 0), Stop(0, red.WithAlpha(0.25)), Stop(1, White.WithAlpha(0.125)))
+	want.DrawRect(R(0, 0, 10, 10), RectStyle{Fill: red.WithAlpha(0.25), Stroke: Black.WithAlpha(0.25), StrokeWidth: 1})
+	want.DrawRoundedRect(R(0, 0, 10, 10), 2, RectStyle{Fill: g.Average().WithAlpha(g.Average().A / 4), FillGradient: faded})
+	want.DrawText("a", Pt(1, 2), TextStyle{Size: 12, Color: Black.WithAlpha(0.25)})
+	want.DrawPath(p, PathStyle{Stroke: red.WithAlpha(0.25), StrokeWidth: 2, StrokeGradient: faded})
+	want.ClipPath(p, FillEvenOdd)
+	if !got.Equal(&want) {
+		t.Error("the faded drawing differs")
+	}
+	if g.Stops[0].Color != red {
+		t.Error("fading changed the gradient drawn")
+	}
+}
@@ -0,0 +1,88 @@
+package core
+
+// Constraints bound the size a widget may choose during layout.
+//
+// A maximum of [Infinity] means the axis is unbounded, for example the main
+// axis of a scroll container.
+type Constraints struct {
+	MinWidth, MaxWidth   float32
+	MinHeight, MaxHeight float32
+}
+
+// Tight returns constraints that only allow exactly s.
+func Tight(s Size) Constraints {
+	return Constraints{MinWidth: s.Width, MaxWidth: s.Width, MinHeight: s.Height, MaxHeight: s.Height}
+}
+
+// Loose returns constraints that allow any size up to s.
+func Loose(s Size) Constraints {
+	return Constraints{MaxWidth: s.Width, MaxHeight: s.Height}
+}
+
+// Unbounded returns constraints with no limits on either axis.
+func Unbounded() Constraints {
+	return Constraints{MaxWidth: Infinity, MaxHeight: Infinity}
+}
+
+// Constrain returns s clamped to the constraints.
+func (c Constraints) Constrain(s Size) Size {
+	return Size{
+		Width:  Clamp(s.Width, c.MinWidth, c.MaxWidth),
+		Height: Clamp(s.Height, c.MinHeight, c.MaxHeight),
+	}
+}
+
+// Max returns the largest size allowed by the constraints.
+func (c Constraints) Max() Size {
+	return Size{Width: c.MaxWidth, Height: c.MaxHeight}
+}
+
+// Min returns the smallest size allowed by the constraints.
+func (c Constraints) Min() Size {
+	return Size{Width: c.MinWidth, Height: c.MinHeight}
+}
+
+// HasBoundedWidth reports whether MaxWidth is finite.
+func (c Constraints) HasBoundedWidth() bool {
+	return c.MaxWidth < Infinity
+}
+
+// HasBoundedHeight reports whether MaxHeight is finite.
+func (c Constraints) HasBoundedHeight() bool {
+	return c.MaxHeight < Infinity
+}
+
+// IsTight reports whether only a single size satisfies the constraints.
+func (c Constraints) IsTight() bool {
+	return c.MinWidth >= c.MaxWidth && c.MinHeight >= c.MaxHeight
+}
+
+// Loosen returns the constraints with both minimums removed.
+func (c Constraints) Loosen() Constraints {
+	c.MinWidth = 0
+	c.MinHeight = 0
+	return c
+}
+
+// Deflate returns the constraints available to content inside the insets.
+func (c Constraints) Deflate(in Insets) Constraints {
+	h, v := in.Horizontal(), in.Vertical()
+	return Constraints{
+		MinWidth:  max(0, c.MinWidth-h),
+		MaxWidth:  max(0, c.MaxWidth-h),
+		MinHeight: max(0, c.MinHeight-v),
+		MaxHeight: max(0, c.MaxHeight-v),
+	}
+}
+
+// WithWidth returns the constraints with the width fixed to w.
+func (c Constraints) WithWidth(w float32) Constraints {
+	c.MinWidth, c.MaxWidth = w, w
+	return c
+}
+
+// WithHeight returns the constraints with the height fixed to h.
+func (c Constraints) WithHeight(h float32) Constraints {
+	c.MinHeight, c.MaxHeight = h, h
+	return c
+}
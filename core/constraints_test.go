@@ -0,0 +1,77 @@
+package core
+
+import "testing"
+
+func TestConstraintsConstrain(t *testing.T) {
+	tests := []struct {
+		name string
+		c    Constraints
+		s    Size
+		want Size
+	}{
+		{"tight", Tight(Sz(10, 20)), Sz(50, 1), Sz(10, 20)},
+		{"loose fits", Loose(Sz(100, 100)), Sz(50, 60), Sz(50, 60)},
+		{"loose clamps", Loose(Sz(100, 100)), Sz(150, 160), Sz(100, 100)},
+		{"unbounded", Unbounded(), Sz(1e6, 3), Sz(1e6, 3)},
+		{"minimum", Constraints{MinWidth: 30, MaxWidth: 40, MinHeight: 5, MaxHeight: 6}, Sz(0, 0), Sz(30, 5)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.c.Constrain(tt.s); got != tt.want {
+				t.Errorf("Constrain(%v) = %v, want %v", tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConstraintsQueries(t *testing.T) {
+	tests := []struct {
+		name                  string
+		c                     Constraints
+		tight, boundW, boundH bool
+		min, max              Size
+	}{
+		{"tight", Tight(Sz(10, 20)), true, true, true, Sz(10, 20), Sz(10, 20)},
+		{"loose", Loose(Sz(10, 20)), false, true, true, Sz(0, 0), Sz(10, 20)},
+		{"unbounded", Unbounded(), false, false, false, Sz(0, 0), Sz(Infinity, Infinity)},
+		{"tall", Constraints{MaxWidth: 10, MaxHeight: Infinity}, false, true, false, Sz(0, 0), Sz(10, Infinity)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.c.IsTight(); got != tt.tight {
+				t.Errorf("IsTight = %v, want %v", got, tt.tight)
+			}
+			if got := tt.c.HasBoundedWidth(); got != tt.boundW {
+				t.Errorf("HasBoundedWidth = %v, want %v", got, tt.boundW)
+			}
+			if got := tt.c.HasBoundedHeight(); got != tt.boundH {
+				t.Errorf("HasBoundedHeight = %v, want %v", got, tt.boundH)
+			}
+			if got := tt.c.Min(); got != tt.min {
+				t.Errorf("Min = %v, want %v", got, tt.min)
+			}
+			if got := tt.c.Max(); got != tt.max {
+				t.Errorf("Max = %v, want %v", got, tt.max)
+			}
+		})
+	}
+}
+
+func TestConstraintsDerived(t *testing.T) {
+	c := Constraints{MinWidth: 20, MaxWidth: 100, MinHeight: 10, MaxHeight: 50}
+	if got, want := c.Loosen(), (Constraints{MaxWidth: 100, MaxHeight: 50}); got != want {
+		t.Errorf("Loosen = %v, want %v", got, want)
+	}
+	if got, want := c.Deflate(Insets{Top: 5, Right: 10, Bottom: 5, Left: 15}), (Constraints{MaxWidth: 75, MinHeight: 0, MaxHeight: 40}); got != want {
+		t.Errorf("Deflate = %v, want %v", got, want)
+	}
+	if got, want := c.Deflate(UniformInsets(100)), (Constraints{}); got != want {
+		t.Errorf("Deflate past zero = %v, want %v", got, want)
+	}
+	if got := c.WithWidth(30); got.MinWidth != 30 || got.MaxWidth != 30 || got.MaxHeight != 50 {
+		t.Errorf("WithWidth = %v", got)
+	}
+	if got := c.WithHeight(30); got.MinHeight != 30 || got.MaxHeight != 30 || got.MaxWidth != 100 {
+		t.Errorf("WithHeight = %v", got)
+	}
+}
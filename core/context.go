@@ -0,0 +1,316 @@
+package core
+
+import (
+	"maps"
+	"sync"
+	"time"
+)
+
+// Context holds per-window services shared by every widget: the frame
+// clock, motion preferences, text measurement, keyboard focus, pointer
+// capture, overlays, redraw requests and injected values such as the
+// active theme.
+//
+// A Context is owned by the window runtime and, apart from Post and
+// SetWakeup, must only be used from the UI goroutine.
+type Context struct {
+	now      time.Time
+	scale    float32
+	reduced  bool
+	class    SizeClass
+	dir      Direction
+	inactive bool
+	measurer TextMeasurer
+	values   map[any]any
+
+	nodes     map[Widget]*layoutNode
+	pending   []Widget
+	relayout  bool
+	reuse     bool
+	pass      uint64
+	measuring int
+
+	focused  Widget
+	captured Widget
+	overlays []*Overlay
+
+	redraw      bool
+	damage      []Rect // Regions to paint in the next frame.
+	damageAll   bool
+	frameDamage []Rect // Those of the frame being produced.
+	frameAll    bool
+	backdrops   []Rect // Regions drawn from what lies beneath them.
+	lastDrops   []Rect // Those of the last frame that painted.
+	frame       uint64
+	afterFrame  []func()
+	tickers     []func(now time.Time) bool
+	routes      []func(ev PointerEvent) bool
+	pathAt      func(p Point) []Widget
+
+	mu     sync.Mutex
+	posted []func()
+	wakeup func()
+}
+
+// NewContext returns a Context with a scale factor of 1 and the default
+// text measurer.
+func NewContext() *Context {
+	return &Context{scale: 1, now: time.Now()}
+}
+
+// Now returns the timestamp of the frame being produced. Widgets should use
+// it instead of time.Now so that all animation in a frame is consistent.
+func (c *Context) Now() time.Time {
+	return c.now
+}
+
+// SetNow sets the frame timestamp. It is called by the window runtime at
+// the start of every frame.
+func (c *Context) SetNow(t time.Time) {
+	c.now = t
+}
+
+// ScaleFactor returns the ratio of device pixels to logical pixels.
+func (c *Context) ScaleFactor() float32 {
+	return c.scale
+}
+
+// SetScaleFactor sets the ratio of device pixels to logical pixels, which
+// may be fractional, such as 1.25 or 1.5. Changing it lays out and paints
+// everything again, so that what widgets rasterize or cache in device
+// pixels is made again at the new scale.
+func (c *Context) SetScaleFactor(s float32) {
+	if s <= 0 {
+		s = 1
+	}
+	if s != c.scale {
+		c.scale = s
+		c.Invalidate()
+	}
+}
+
+// ReducedMotion reports whether the user asked for less motion. Widgets
+// should then skip decorative animation and jump straight to the end
+// state.
+func (c *Context) ReducedMotion() bool {
+	return c.reduced
+}
+
+// SetReducedMotion records the user's motion preference, usually taken
+// from the platform's accessibility settings.
+func (c *Context) SetReducedMotion(reduced bool) {
+	if reduced != c.reduced {
+		c.reduced = reduced
+		c.Invalidate()
+	}
+}
+
+// SizeClass returns the size class of the widget being laid out or
+// painted. The window sets it from its width before each layout, and
+// layout.Responsive overrides it for its subtree; widgets that adapt to it
+// should read it in Layout.
+func (c *Context) SizeClass() SizeClass {
+	return c.class
+}
+
+// SetSizeClass sets the size class returned by SizeClass. Containers that
+// set it for their children restore the previous class afterwards.
+func (c *Context) SetSizeClass(s SizeClass) {
+	c.class = s
+}
+
+// Direction returns the reading direction of the widget being laid out or
+// painted. The window sets it before each layout, and layout.Directionality
+// overrides it for its subtree. Widgets that handle events in a direction
+// dependent way should record it in Layout.
+func (c *Context) Direction() Direction {
+	return c.dir
+}
+
+// SetDirection sets the direction returned by Direction. Containers that
+// set it for their children restore the previous direction afterwards.
+func (c *Context) SetDirection(d Direction) {
+	c.dir = d
+}
+
+// WindowActive reports whether the window receives keyboard input, as
+// the active one of the windows of an application. Widgets hide carets
+// in inactive windows.
+func (c *Context) WindowActive() bool {
+	return !c.inactive
+}
+
+// SetWindowActive records whether the window is active; windows are
+// active until the window runtime says otherwise.
+func (c *Context) SetWindowActive(active bool) {
+	if active == c.inactive {
+		c.inactive = !active
+		c.Invalidate()
+	}
+}
+
+// Value returns the value stored under key, or nil.
+func (c *Context) Value(key any) any {
+	return c.values[key]
+}
+
+// SetValue stores v under key. Packages should use an unexported key type
+// and expose typed accessors, as package theme does.
+func (c *Context) SetValue(key, v any) {
+	// The map is copied rather than changed in place, so that the values
+	// seen by a relayout boundary can be kept with it and restored when
+	// the boundary is laid out again on its own. A value set outside
+	// layout may be read anywhere, so the whole tree is laid out again.
+	values := maps.Clone(c.values)
+	if values == nil {
+		values = make(map[any]any)
+	}
+	values[key] = v
+	c.values = values
+	if c.measuring == 0 {
+		c.relayout = true
+	}
+}
+
+// Focused returns the widget with keyboard focus, or nil.
+func (c *Context) Focused() Widget {
+	return c.focused
+}
+
+// IsFocused reports whether w has keyboard focus.
+func (c *Context) IsFocused(w Widget) bool {
+	return w != nil && c.focused == w
+}
+
+// focusOutset is how far outside its bounds a widget may draw its focus
+// indicator, such as a ring or halo.
+const focusOutset float32 = 12
+
+// RequestFocus moves keyboard focus to w, blurring the previous holder,
+// and repaints both. Passing nil clears focus.
+func (c *Context) RequestFocus(w Widget) {
+	if c.focused == w {
+		return
+	}
+	if f, ok := c.focused.(Focusable); ok {
+		f.Blur()
+	}
+	c.repaintFocus(c.focused)
+	c.focused = w
+	if f, ok := w.(Focusable); ok {
+		f.Focus()
+	}
+	c.repaintFocus(w)
+}
+
+func (c *Context) repaintFocus(w Widget) {
+	if w != nil {
+		c.InvalidateRect(w.Bounds().Inset(UniformInsets(-focusOutset)))
+	}
+}
+
+// CapturePointer routes all pointer events to w until ReleasePointer is
+// called. Widgets capture on button press to keep receiving drag events
+// after the pointer leaves their bounds.
+func (c *Context) CapturePointer(w Widget) {
+	c.captured = w
+}
+
+// ReleasePointer ends pointer capture.
+func (c *Context) ReleasePointer() {
+	c.captured = nil
+}
+
+// PointerCapture returns the widget holding pointer capture, or nil.
+func (c *Context) PointerCapture() Widget {
+	return c.captured
+}
+
+// Invalidate requests that a new frame be produced, with the whole tree
+// laid out and painted again. Widgets whose size changed for a reason
+// only they know of, such as a new bound value, should call
+// MarkNeedsLayout instead, and widgets whose look alone changed Repaint.
+func (c *Context) Invalidate() {
+	c.redraw = true
+	c.relayout = true
+	c.damageAll = true
+}
+
+// NeedsRedraw reports whether Invalidate was called since the last
+// ClearRedraw.
+func (c *Context) NeedsRedraw() bool {
+	return c.redraw
+}
+
+// ClearRedraw resets the redraw request. It is called by the window
+// runtime after a frame is produced.
+func (c *Context) ClearRedraw() {
+	c.redraw = false
+}
+
+// Post schedules fn to run on the UI goroutine before the next frame. It is
+// safe to call from any goroutine and is the only sanctioned way for
+// background work to touch widgets.
+func (c *Context) Post(fn func()) {
+	c.mu.Lock()
+	c.posted = append(c.posted, fn)
+	wake := c.wakeup
+	c.mu.Unlock()
+	if wake != nil {
+		wake()
+	}
+}
+
+// SetWakeup installs fn to be called after Post, from the posting
+// goroutine, so the platform event loop can schedule a frame.
+func (c *Context) SetWakeup(fn func()) {
+	c.mu.Lock()
+	c.wakeup = fn
+	c.mu.Unlock()
+}
+
+// AfterFrame schedules fn to run once, on the UI goroutine, when the
+// frame being produced has been painted. Widgets backed by native surfaces
+// use it to learn that they were not painted, and hide them.
+func (c *Context) AfterFrame(fn func()) {
+	c.afterFrame = append(c.afterFrame, fn)
+}
+
+// RunAfterFrame runs the functions scheduled with AfterFrame. It is called
+// by the window runtime at the end of every frame.
+func (c *Context) RunAfterFrame() {
+	fns := c.afterFrame
+	c.afterFrame = nil
+	for _, fn := range fns {
+		fn()
+	}
+}
+
+// HasPosted reports whether functions are waiting to run.
+func (c *Context) HasPosted() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.posted) > 0
+}
+
+// RunPosted runs the functions queued by Post. It is called by the window
+// runtime on the UI goroutine at the start of every frame. The whole tree
+// is laid out again afterwards unless the functions asked for the widgets
+// they changed to be laid out with MarkNeedsLayout, or painted with
+// Repaint.
+func (c *Context) RunPosted() {
+	c.mu.Lock()
+	fns := c.posted
+	c.posted = nil
+	c.mu.Unlock()
+	marked, damaged := len(c.pending), len(c.damage)
+	for _, fn := range fns {
+		fn()
+	}
+	if len(fns) > 0 {
+		c.redraw = true
+		if len(c.pending) == marked && len(c.damage) == damaged {
+			c.relayout = true
+		}
+	}
+}
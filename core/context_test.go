@@ -0,0 +1,149 @@
+package core
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestContextFocus(t *testing.T) {
+	ctx := NewContext()
+	a, b := newNode("a", R(0, 0, 10, 10)), newNode("b", R(20, 0, 10, 10))
+	steps := []struct {
+		focus      Widget
+		aFocused   bool
+		bFocused   bool
+		wantHolder Widget
+	}{
+		{a, true, false, a},
+		{b, false, true, b},
+		{b, false, true, b},
+		{nil, false, false, nil},
+	}
+	for i, s := range steps {
+		ctx.RequestFocus(s.focus)
+		if ctx.Focused() != s.wantHolder {
+			t.Errorf("step %d: Focused = %v, want %v", i, ctx.Focused(), s.wantHolder)
+		}
+		if a.IsFocused() != s.aFocused || b.IsFocused() != s.bFocused {
+			t.Errorf("step %d: focused = %v, %v, want %v, %v", i, a.IsFocused(), b.IsFocused(), s.aFocused, s.bFocused)
+		}
+		if ctx.IsFocused(a) != s.aFocused {
+			t.Errorf("step %d: IsFocused(a) = %v", i, ctx.IsFocused(a))
+		}
+	}
+	if ctx.IsFocused(nil) {
+		t.Error("IsFocused(nil) with no focus")
+	}
+}
+
+func TestContextPointerCapture(t *testing.T) {
+	ctx := NewContext()
+	w := newNode("w", Rect{})
+	if ctx.PointerCapture() != nil {
+		t.Fatal("captured at start")
+	}
+	ctx.CapturePointer(w)
+	if ctx.PointerCapture() != w {
+		t.Error("CapturePointer did not capture")
+	}
+	ctx.ReleasePointer()
+	if ctx.PointerCapture() != nil {
+		t.Error("ReleasePointer did not release")
+	}
+}
+
+func TestContextInvalidate(t *testing.T) {
+	ctx := NewContext()
+	ctx.ClearRedraw()
+	if ctx.NeedsRedraw() {
+		t.Fatal("needs redraw after ClearRedraw")
+	}
+	ctx.Invalidate()
+	if !ctx.NeedsRedraw() {
+		t.Error("Invalidate did not request a redraw")
+	}
+	ctx.ClearRedraw()
+	if ctx.NeedsRedraw() {
+		t.Error("ClearRedraw did not reset")
+	}
+}
+
+func TestContextValuesAndTime(t *testing.T) {
+	type key struct{}
+	ctx := NewContext()
+	if ctx.Value(key{}) != nil {
+		t.Error("value before SetValue")
+	}
+	ctx.SetValue(key{}, 42)
+	if got := ctx.Value(key{}); got != 42 {
+		t.Errorf("Value = %v, want 42", got)
+	}
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	ctx.SetNow(now)
+	if !ctx.Now().Equal(now) {
+		t.Errorf("Now = %v, want %v", ctx.Now(), now)
+	}
+	if ctx.ScaleFactor() != 1 {
+		t.Errorf("default ScaleFactor = %v, want 1", ctx.ScaleFactor())
+	}
+}
+
+func TestContextPost(t *testing.T) {
+	ctx := NewContext()
+	woken := make(chan struct{}, 10)
+	ctx.SetWakeup(func() { woken <- struct{}{} })
+	var mu sync.Mutex
+	var ran []int
+	var wg sync.WaitGroup
+	for i := range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx.Post(func() {
+				mu.Lock()
+				ran = append(ran, i)
+				mu.Unlock()
+			})
+		}()
+	}
+	wg.Wait()
+	if !ctx.HasPosted() {
+		t.Fatal("HasPosted = false after Post")
+	}
+	if len(woken) != 5 {
+		t.Errorf("woken %d times, want 5", len(woken))
+	}
+	if len(ran) != 0 {
+		t.Fatal("posted functions ran before RunPosted")
+	}
+	ctx.RunPosted()
+	if len(ran) != 5 {
+		t.Errorf("%d posted functions ran, want 5", len(ran))
+	}
+	if ctx.HasPosted() {
+		t.Error("HasPosted = true after RunPosted")
+	}
+}
+
+func TestContextAfterFrame(t *testing.T) {
+	ctx := NewContext()
+	var ran []int
+	ctx.AfterFrame(func() { ran = append(ran, 1) })
+	ctx.AfterFrame(func() {
+		ran = append(ran, 2)
+		ctx.AfterFrame(func() { ran = append(ran, 3) })
+	})
+	ctx.RunAfterFrame()
+	if len(ran) != 2 || ran[0] != 1 || ran[1] != 2 {
+		t.Fatalf("ran %v after the first frame, want 1 and 2", ran)
+	}
+	ctx.RunAfterFrame()
+	if len(ran) != 3 {
+		t.Errorf("ran %v, want the function scheduled during a run to wait for the next frame", ran)
+	}
+	ctx.RunAfterFrame()
+	if len(ran) != 3 {
+		t.Errorf("ran %v, want each function run once", ran)
+	}
+}
@@ -0,0 +1,88 @@
+package core
+
+// Repaint requests a frame in which w is painted again, without laying
+// out the tree, for changes that leave its size and arrangement as they
+// are, such as a hover highlight or a step of an animation. Only the
+// bounds of w need to be drawn again; see Damage.
+func (c *Context) Repaint(w Widget) {
+	c.InvalidateRect(w.Bounds())
+}
+
+// InvalidateRect requests a frame in which r, in window coordinates, is
+// painted again, without laying out the tree.
+func (c *Context) InvalidateRect(r Rect) {
+	c.redraw = true
+	if !r.IsEmpty() {
+		c.damage = append(c.damage, r)
+	}
+}
+
+// Damage returns the parts of the window, in window coordinates, that
+// changed for the frame being produced: the regions passed to Repaint and
+// InvalidateRect before its layout pass, and the relayout boundaries that
+// pass laid out again, and the regions recorded by AddBackdrop that
+// they overlap. all is set when the whole window changed, after
+// Invalidate, a full layout or a frame with no other request, such as one
+// asked for by the platform.
+//
+// The window runtime reads it after LayoutRoot to paint only what
+// changed. Requests made during the layout and paint passes are for the
+// next frame.
+func (c *Context) Damage() (rects []Rect, all bool) {
+	rects = c.frameDamage
+	for _, b := range c.lastDrops {
+		for _, r := range rects {
+			if !r.Intersect(b).IsEmpty() {
+				rects = append(rects[:len(rects):len(rects)], b)
+				break
+			}
+		}
+	}
+	return rects, c.frameAll
+}
+
+// AddBackdrop records, while painting, that what is drawn in r, in
+// window coordinates, depends on all that was drawn beneath it, as with
+// a blur. A later frame painting any of r then paints all of it.
+func (c *Context) AddBackdrop(r Rect) {
+	if !r.IsEmpty() {
+		c.backdrops = append(c.backdrops, r)
+	}
+}
+
+// takeDamage makes the damage requested so far that of the frame being
+// laid out, and starts collecting that of the next frame.
+func (c *Context) takeDamage() {
+	// A frame with no damage painted nothing, and so recorded none of
+	// the backdrops still on the screen.
+	if c.frameAll || len(c.frameDamage) > 0 {
+		c.lastDrops, c.backdrops = c.backdrops, c.lastDrops[:0]
+	}
+	c.frameDamage, c.frameAll = c.damage, c.damageAll
+	c.damage, c.damageAll = nil, false
+	c.frame++
+}
+
+// Damaged reports whether r, in window coordinates, changed for the frame
+// being produced: whether the whole window did or any of Damage overlaps
+// r. Widgets keeping what they drew between frames check it to tell
+// whether to draw again.
+func (c *Context) Damaged(r Rect) bool {
+	rects, all := c.Damage()
+	if all {
+		return true
+	}
+	for _, d := range rects {
+		if !d.Intersect(r).IsEmpty() {
+			return true
+		}
+	}
+	return false
+}
+
+// FrameNumber returns the number of the frame being produced, counting
+// the layout passes of LayoutRoot from 1, for widgets to tell whether
+// they were painted in the frame before.
+func (c *Context) FrameNumber() uint64 {
+	return c.frame
+}
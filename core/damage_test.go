@@ -0,0 +1,142 @@
+package core
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDamage(t *testing.T) {
+	bounds := R(0, 0, 200, 200)
+	tests := []struct {
+		name    string
+		request func(ctx *Context, bs map[string]*block)
+		rects   []Rect
+		all     bool
+	}{
+		{"repaint", func(ctx *Context, bs map[string]*block) { ctx.Repaint(bs["leaf"]) }, []Rect{R(0, 0, 10, 10)}, false},
+		{"rects", func(ctx *Context, _ map[string]*block) {
+			ctx.InvalidateRect(R(5, 5, 10, 10))
+			ctx.InvalidateRect(R(100, 100, 0, 10))
+			ctx.InvalidateRect(R(50, 50, 10, 10))
+		}, []Rect{R(5, 5, 10, 10), R(50, 50, 10, 10)}, false},
+		{"relayout", func(ctx *Context, bs map[string]*block) { ctx.MarkNeedsLayout(bs["deep"]) }, []Rect{R(0, 50, 30, 30)}, false},
+		{"repaint and relayout", func(ctx *Context, bs map[string]*block) {
+			ctx.Repaint(bs["sibling"])
+			ctx.MarkNeedsLayout(bs["deep"])
+		}, []Rect{R(0, 80, 30, 20), R(0, 50, 30, 30)}, false},
+		{"relayout up to the root", func(ctx *Context, bs map[string]*block) { ctx.MarkNeedsLayout(bs["leaf"]) }, []Rect{bounds}, false},
+		{"invalidated", func(ctx *Context, bs map[string]*block) {
+			ctx.Repaint(bs["leaf"])
+			ctx.Invalidate()
+		}, []Rect{R(0, 0, 10, 10)}, true},
+		{"asked for by the platform", nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var log []string
+			bs := tree(&log)
+			ctx := NewContext()
+			ctx.LayoutRoot(bs["root"], bounds)
+			if _, all := ctx.Damage(); !all {
+				t.Fatal("the first frame is not damaged all over")
+			}
+			ctx.ClearRedraw()
+			if tt.request != nil {
+				tt.request(ctx, bs)
+				if !ctx.NeedsRedraw() {
+					t.Error("the request asks for no frame")
+				}
+			}
+			ctx.LayoutRoot(bs["root"], bounds)
+			rects, all := ctx.Damage()
+			if !slices.Equal(rects, tt.rects) || all != tt.all {
+				t.Errorf("Damage() = %v, %v, want %v, %v", rects, all, tt.rects, tt.all)
+			}
+		})
+	}
+}
+
+func TestDamageNextFrame(t *testing.T) {
+	var log []string
+	bs := tree(&log)
+	ctx := NewContext()
+	bounds := R(0, 0, 200, 200)
+	ctx.LayoutRoot(bs["root"], bounds)
+	ctx.Repaint(bs["leaf"])
+	ctx.LayoutRoot(bs["root"], bounds)
+	frame := ctx.FrameNumber()
+
+	// Requests made while the frame is produced are for the next one.
+	ctx.InvalidateRect(R(100, 100, 10, 10))
+	if rects, _ := ctx.Damage(); !slices.Equal(rects, []Rect{R(0, 0, 10, 10)}) {
+		t.Errorf("Damage() = %v, want the leaf's alone", rects)
+	}
+	ctx.LayoutRoot(bs["root"], bounds)
+	if rects, _ := ctx.Damage(); !slices.Equal(rects, []Rect{R(100, 100, 10, 10)}) {
+		t.Errorf("Damage() of the next frame = %v", rects)
+	}
+	if got := ctx.FrameNumber(); got != frame+1 {
+		t.Errorf("frame %d after frame %d", got, frame)
+	}
+
+	// A move of the root lays the whole window out again.
+	ctx.LayoutRoot(bs["root"], R(10, 0, 200, 200))
+	if _, all := ctx.Damage(); !all {
+		t.Error("a moved root does not damage the whole window")
+	}
+}
+
+func TestAddBackdrop(t *testing.T) {
+	var log []string
+	bs := tree(&log)
+	ctx := NewContext()
+	bounds := R(0, 0, 200, 200)
+	glass := R(100, 100, 50, 50)
+	// frame lays out a frame after request, painting the backdrop if
+	// painted is set.
+	frame := func(request func(), painted bool) {
+		request()
+		ctx.LayoutRoot(bs["root"], bounds)
+		if painted {
+			ctx.AddBackdrop(glass)
+			ctx.AddBackdrop(R(0, 0, 0, 10))
+		}
+	}
+	frame(func() {}, true)
+
+	tests := []struct {
+		name      string
+		repainted []Rect
+		rects     []Rect
+	}{
+		{"apart", []Rect{R(0, 0, 10, 10)}, []Rect{R(0, 0, 10, 10)}},
+		{"overlapping", []Rect{R(140, 140, 20, 20)}, []Rect{R(140, 140, 20, 20), glass}},
+		{"inside", []Rect{R(110, 110, 10, 10)}, []Rect{R(110, 110, 10, 10), glass}},
+		{"twice over", []Rect{R(110, 110, 10, 10), R(0, 0, 10, 10), R(90, 90, 20, 20)},
+			[]Rect{R(110, 110, 10, 10), R(0, 0, 10, 10), R(90, 90, 20, 20), glass}},
+	}
+	for _, tt := range tests {
+		frame(func() {
+			for _, r := range tt.repainted {
+				ctx.InvalidateRect(r)
+			}
+		}, true)
+		rects, _ := ctx.Damage()
+		if !slices.Equal(rects, tt.rects) {
+			t.Errorf("%s: Damage() = %v, want %v", tt.name, rects, tt.rects)
+		}
+		if again, _ := ctx.Damage(); !slices.Equal(again, rects) {
+			t.Errorf("%s: Damage() = %v when asked again", tt.name, again)
+		}
+		if got := ctx.Damaged(R(145, 100, 5, 5)); got != slices.Contains(tt.rects, glass) {
+			t.Errorf("%s: Damaged(a corner of the backdrop) = %v", tt.name, got)
+		}
+	}
+
+	// Backdrops no longer painted are forgotten.
+	frame(func() { ctx.InvalidateRect(R(0, 0, 10, 10)) }, false)
+	frame(func() { ctx.InvalidateRect(R(140, 140, 20, 20)) }, false)
+	if rects, _ := ctx.Damage(); !slices.Equal(rects, []Rect{R(140, 140, 20, 20)}) {
+		t.Errorf("Damage() = %v after the backdrop was no longer painted", rects)
+	}
+}
@@ -0,0 +1,85 @@
+package core
+
+import "strings"
+
+// Direction is the reading direction of a part of the UI. Containers lay
+// out rows from the start edge, which is the left edge in left-to-right
+// text and the right edge in right-to-left text, and widgets mirror
+// directional icons and place scrollbars on the end edge.
+type Direction uint8
+
+// Directions.
+const (
+	// LeftToRight is the direction of Latin, Cyrillic and most other
+	// scripts.
+	LeftToRight Direction = iota
+	// RightToLeft is the direction of Arabic, Hebrew and related scripts.
+	RightToLeft
+)
+
+// rtlLanguages are the languages written right to left, by ISO 639 code.
+var rtlLanguages = map[string]bool{
+	"ar": true, "arc": true, "ckb": true, "dv": true, "fa": true, "he": true,
+	"iw": true, "ks": true, "ku": true, "ps": true, "sd": true, "syr": true,
+	"ug": true, "ur": true, "yi": true,
+}
+
+// DirectionFor returns the direction of a BCP 47 locale tag such as
+// "en-US", "ar_EG" or "he". A script subtag overrides the language, as in
+// "az-Arab" or "ku-Latn".
+func DirectionFor(locale string) Direction {
+	parts := strings.FieldsFunc(strings.ToLower(locale), func(r rune) bool { return r == '-' || r == '_' || r == '.' || r == '@' })
+	if len(parts) == 0 {
+		return LeftToRight
+	}
+	for _, p := range parts[1:] {
+		switch p {
+		case "arab", "hebr", "thaa", "syrc", "nkoo", "adlm", "rohg":
+			return RightToLeft
+		case "latn", "cyrl":
+			return LeftToRight
+		}
+	}
+	if rtlLanguages[parts[0]] {
+		return RightToLeft
+	}
+	return LeftToRight
+}
+
+// IsRTL reports whether d is RightToLeft.
+func (d Direction) IsRTL() bool {
+	return d == RightToLeft
+}
+
+// String returns "ltr" or "rtl".
+func (d Direction) String() string {
+	if d == RightToLeft {
+		return "rtl"
+	}
+	return "ltr"
+}
+
+// Mirror returns r mirrored horizontally within container when d is
+// RightToLeft, and r unchanged otherwise. Containers compute child
+// positions left to right and mirror them on the way out.
+func (d Direction) Mirror(r, container Rect) Rect {
+	if d == RightToLeft {
+		r.X = container.X + container.Right() - r.Right()
+	}
+	return r
+}
+
+// DirectionalInsets describes spacing on each side of a rectangle with
+// the horizontal sides named by reading direction: Start is the left side
+// in left-to-right text and the right side in right-to-left text.
+type DirectionalInsets struct {
+	Top, End, Bottom, Start float32
+}
+
+// Resolve returns the insets for direction d.
+func (in DirectionalInsets) Resolve(d Direction) Insets {
+	if d == RightToLeft {
+		return Insets{Top: in.Top, Right: in.Start, Bottom: in.Bottom, Left: in.End}
+	}
+	return Insets{Top: in.Top, Right: in.End, Bottom: in.Bottom, Left: in.Start}
+}
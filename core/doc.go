@@ -0,0 +1,23 @@
+// Package core defines the widget protocol shared by every gogpu/ui package.
+//
+// The central type is the [Widget] interface. A frame is produced in three
+// passes over the widget tree:
+//
+//  1. Layout (measure): each widget receives [Constraints] through a
+//     [LayoutContext] and returns the [Size] it wants.
+//  2. Arrange: the parent positions each child by calling SetBounds with a
+//     rectangle in window coordinates. Containers override SetBounds to
+//     arrange their own children.
+//  3. Paint: each widget draws itself into a [Canvas] via [PaintContext].
+//
+// Layout is incremental: a widget that calls [Context.MarkNeedsLayout] is
+// laid out again together with its ancestors up to the nearest relayout
+// boundary, a widget whose parent gave it tight constraints, and the rest
+// of the tree keeps its last layout.
+//
+// All geometry is expressed in logical pixels in window coordinates, so hit
+// testing and event routing never need to translate positions.
+//
+// Widgets embed [WidgetBase] for bounds, children and focus bookkeeping and
+// add behavior through optional interfaces such as [Focusable].
+package core
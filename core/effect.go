@@ -0,0 +1,275 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Effect is a custom WGSL fragment shader that a layer is drawn through,
+// for effects such as ripples, dissolves and custom gradients. Its source
+// defines a single function returning the premultiplied color of each
+// pixel of the layer:
+//
+//	fn effect(uv: vec2<f32>) -> vec4<f32> {
+//		let c = sample(uv);
+//		let n = fract(sin(dot(floor(uv * u.size / 4.0), vec2(12.9898, 78.233))) * 43758.5453);
+//		return c * step(u.progress, n);
+//	}
+//
+// uv runs from 0 to 1 across the layer. The function reads the layer with
+// sample and the uniforms as fields of u: the layer size in device pixels
+// as u.size, a clock in seconds as u.time, and the uniforms declared with
+// the effect by their names. The renderer binds the shader, which cannot
+// declare bindings or entry points of its own and so reaches nothing but
+// the layer and its uniforms.
+type Effect struct {
+	source   string
+	uniforms []Uniform
+	offsets  []int // Of each uniform into the packed uniforms, in floats.
+	size     int   // Of the packed uniforms, in floats.
+	time     bool
+}
+
+// Uniform declares a value an Effect reads.
+type Uniform struct {
+	Name string
+	Type UniformType
+}
+
+// UniformType is the WGSL type of a Uniform.
+type UniformType uint8
+
+const (
+	UniformFloat UniformType = iota // f32
+	UniformVec2                     // vec2<f32>
+	UniformVec4                     // vec4<f32>, such as a premultiplied color
+)
+
+// wgsl returns the type in WGSL.
+func (t UniformType) wgsl() string {
+	switch t {
+	case UniformVec2:
+		return "vec2<f32>"
+	case UniformVec4:
+		return "vec4<f32>"
+	}
+	return "f32"
+}
+
+// floats returns the size of the type in floats, which is also its
+// alignment in uniform buffers.
+func (t UniformType) floats() int {
+	switch t {
+	case UniformVec2:
+		return 2
+	case UniformVec4:
+		return 4
+	}
+	return 1
+}
+
+// builtinUniforms are the uniforms every effect has.
+var builtinUniforms = []Uniform{{"size", UniformVec2}, {"time", UniformFloat}}
+
+// effectForbidden are the WGSL constructs effects may not use, as the
+// renderer binds their resources and entry point, by name and pattern.
+// WGSL allows blank space, and comments, after '@' and around '<'.
+var effectForbidden = []struct {
+	name string
+	re   *regexp.Regexp
+}{
+	{"@group", regexp.MustCompile(`@\s*group\b`)},
+	{"@binding", regexp.MustCompile(`@\s*binding\b`)},
+	{"@vertex", regexp.MustCompile(`@\s*vertex\b`)},
+	{"@fragment", regexp.MustCompile(`@\s*fragment\b`)},
+	{"@compute", regexp.MustCompile(`@\s*compute\b`)},
+	{"var<storage>", regexp.MustCompile(`\bvar\s*<\s*storage\b`)},
+	{"var<workgroup>", regexp.MustCompile(`\bvar\s*<\s*workgroup\b`)},
+	{"enable", regexp.MustCompile(`\benable\b`)},
+}
+
+var (
+	effectFunc = regexp.MustCompile(`\bfn\s+effect\s*\(`)
+	effectTime = regexp.MustCompile(`\bu\s*\.\s*time\b`)
+)
+
+// NewEffect returns the effect of the WGSL source, which defines fn effect
+// and reads the uniforms declared. It reports sources that define no
+// effect function or bind resources, and invalid uniform names; errors in
+// the WGSL itself are found by the renderer compiling it.
+func NewEffect(source string, uniforms ...Uniform) (*Effect, error) {
+	code := stripComments(source)
+	if !effectFunc.MatchString(code) {
+		return nil, errors.New("core: effect source defines no fn effect")
+	}
+	for _, f := range effectForbidden {
+		if f.re.MatchString(code) {
+			return nil, fmt.Errorf("core: effect source may not use %s", f.name)
+		}
+	}
+	e := &Effect{source: source, time: effectTime.MatchString(code)}
+	seen := map[string]bool{}
+	for _, u := range builtinUniforms {
+		seen[u.Name] = true
+		e.layout(u)
+	}
+	for _, u := range uniforms {
+		if !isIdent(u.Name) {
+			return nil, fmt.Errorf("core: invalid effect uniform name %q", u.Name)
+		}
+		if seen[u.Name] {
+			return nil, fmt.Errorf("core: duplicate effect uniform %q", u.Name)
+		}
+		seen[u.Name] = true
+		e.uniforms = append(e.uniforms, u)
+		e.offsets = append(e.offsets, e.layout(u))
+	}
+	e.size = (e.size + 3) &^ 3
+	return e, nil
+}
+
+// layout places u after the uniforms placed so far, at its alignment, and
+// returns its offset.
+func (e *Effect) layout(u Uniform) int {
+	n := u.Type.floats()
+	off := (e.size + n - 1) / n * n
+	e.size = off + n
+	return off
+}
+
+// stripComments returns the WGSL source with each comment replaced by a
+// space, as the language reads them. Block comments nest.
+func stripComments(src string) string {
+	var b strings.Builder
+	depth := 0
+	for i := 0; i < len(src); i++ {
+		switch {
+		case strings.HasPrefix(src[i:], "/*"):
+			if depth == 0 {
+				b.WriteByte(' ')
+			}
+			depth++
+			i++
+		case depth > 0 && strings.HasPrefix(src[i:], "*/"):
+			depth--
+			i++
+		case depth > 0:
+		case strings.HasPrefix(src[i:], "//"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(src[i])
+		}
+	}
+	return b.String()
+}
+
+func isIdent(s string) bool {
+	if s == "" || s == "_" || strings.HasPrefix(s, "__") {
+		return false
+	}
+	for i, r := range s {
+		if !(r == '_' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || i > 0 && '0' <= r && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// MustEffect is like NewEffect but panics on errors, for effects whose
+// source is a constant of the program.
+func MustEffect(source string, uniforms ...Uniform) *Effect {
+	e, err := NewEffect(source, uniforms...)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// Uniforms returns the uniforms declared with the effect.
+func (e *Effect) Uniforms() []Uniform {
+	return e.uniforms
+}
+
+// Animated reports whether the effect reads u.time, and so changes every
+// frame.
+func (e *Effect) Animated() bool {
+	return e.time
+}
+
+// Shader returns the WGSL module a renderer compiles for the effect: the
+// effect source with its uniforms and the layer texture bound, and the
+// fragment entry point fs_effect taking the uv interpolated at location 0.
+//
+// Bindings: group 0, binding 0 is the layer texture, binding 1 a linear
+// filtering sampler, and group 1, binding 0 the uniform buffer laid out by
+// Pack.
+func (e *Effect) Shader() string {
+	var b strings.Builder
+	b.WriteString("struct EffectUniforms {\n")
+	for _, u := range builtinUniforms {
+		fmt.Fprintf(&b, "\t%s: %s,\n", u.Name, u.Type.wgsl())
+	}
+	for _, u := range e.uniforms {
+		fmt.Fprintf(&b, "\t%s: %s,\n", u.Name, u.Type.wgsl())
+	}
+	b.WriteString("};\n")
+	b.WriteString(effectPrelude)
+	b.WriteString(e.source)
+	b.WriteString(effectEntry)
+	return b.String()
+}
+
+const effectPrelude = `
+@group(0) @binding(0) var effect_layer: texture_2d<f32>;
+@group(0) @binding(1) var effect_sampler: sampler;
+@group(1) @binding(0) var<uniform> u: EffectUniforms;
+
+fn sample(uv: vec2<f32>) -> vec4<f32> {
+	return textureSampleLevel(effect_layer, effect_sampler, uv, 0.0);
+}
+`
+
+const effectEntry = `
+struct EffectIn {
+	@builtin(position) position: vec4<f32>,
+	@location(0) uv: vec2<f32>,
+};
+
+@fragment
+fn fs_effect(in: EffectIn) -> @location(0) vec4<f32> {
+	return effect(in.uv);
+}
+`
+
+// Pack returns the uniforms of e as laid out in its uniform buffer, for a
+// layer of size device pixels at time seconds, with values holding the
+// value of each declared uniform, in order. Components past the size of
+// a uniform's type are ignored, and missing values are zero.
+func (e *Effect) Pack(size Size, time float32, values [][4]float32) []float32 {
+	out := make([]float32, e.size)
+	out[0], out[1], out[2] = size.Width, size.Height, time
+	for i, u := range e.uniforms {
+		if i < len(values) {
+			copy(out[e.offsets[i]:e.offsets[i]+u.Type.floats()], values[i][:])
+		}
+	}
+	return out
+}
+
+// EffectCanvas is implemented by canvases that can draw layers through an
+// Effect, those of GPU backends compiling Effect.Shader once for each
+// effect. The canvases of render/raster, which cannot run shaders, and of
+// render/batch do not implement it, and ShaderEffect paints its child
+// without the effect on them.
+type EffectCanvas interface {
+	LayerCanvas
+
+	// DrawLayerEffect draws l like DrawLayer, with the color of each pixel
+	// given by e for the uniform buffer u, laid out by e.Pack.
+	DrawLayerEffect(l Layer, pos Point, e *Effect, u []float32)
+}
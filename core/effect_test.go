@@ -0,0 +1,101 @@
+package core
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNewEffect(t *testing.T) {
+	const body = " -> vec4<f32> { return sample(uv); }"
+	tests := []struct {
+		name     string
+		source   string
+		uniforms []Uniform
+		err      string // A part of the error, or empty for none.
+	}{
+		{"effect", "fn effect(uv: vec2<f32>)" + body, nil, ""},
+		{"spaced entry point", "fn  effect (uv: vec2<f32>)" + body, nil, ""},
+		{"entry point on lines", "fn\neffect\n(uv: vec2<f32>)" + body, nil, ""},
+		{"helpers", "fn shade(c: vec4<f32>) -> vec4<f32> { return c; }\nfn effect(uv: vec2<f32>)" + body, nil, ""},
+		{"no effect", "fn shade(uv: vec2<f32>)" + body, nil, "no fn effect"},
+		{"longer name", "fn effects(uv: vec2<f32>)" + body, nil, "no fn effect"},
+		{"effect in a comment", "// fn effect(uv: vec2<f32>)\nfn shade(uv: vec2<f32>)" + body, nil, "no fn effect"},
+		{"effect in a block comment", "/* fn effect(uv: vec2<f32>) */ fn shade(uv: vec2<f32>)" + body, nil, "no fn effect"},
+		{"group", "@group(2) @binding(0) var t: texture_2d<f32>;\nfn effect(uv: vec2<f32>)" + body, nil, "@group"},
+		{"spaced attribute", "@ binding(2) var t: texture_2d<f32>;\nfn effect(uv: vec2<f32>)" + body, nil, "@binding"},
+		{"attribute after a comment", "@/**/group(0) var t: texture_2d<f32>;\nfn effect(uv: vec2<f32>)" + body, nil, "@group"},
+		{"attribute after a line", "@\ngroup(0) var t: texture_2d<f32>;\nfn effect(uv: vec2<f32>)" + body, nil, "@group"},
+		{"entry point", "@fragment fn effect(uv: vec2<f32>)" + body, nil, "@fragment"},
+		{"compute", "@ compute @workgroup_size(1) fn main() {}\nfn effect(uv: vec2<f32>)" + body, nil, "@compute"},
+		{"storage", "var < storage, read_write > buf: array<f32>;\nfn effect(uv: vec2<f32>)" + body, nil, "var<storage>"},
+		{"workgroup", "var<workgroup> w: f32;\nfn effect(uv: vec2<f32>)" + body, nil, "var<workgroup>"},
+		{"enable", "enable f16;\nfn effect(uv: vec2<f32>)" + body, nil, "enable"},
+		{"forbidden in comments", "// @group(0)\n/* @binding(1) /* nested */ var<storage> */\nfn effect(uv: vec2<f32>)" + body, nil, ""},
+		{"private var", "var<private> k: f32;\nfn effect(uv: vec2<f32>)" + body, nil, ""},
+		{"uniforms", "fn effect(uv: vec2<f32>)" + body, []Uniform{{"progress", UniformFloat}, {"tint", UniformVec4}}, ""},
+		{"invalid uniform", "fn effect(uv: vec2<f32>)" + body, []Uniform{{"2d", UniformFloat}}, "invalid"},
+		{"reserved uniform", "fn effect(uv: vec2<f32>)" + body, []Uniform{{"__x", UniformFloat}}, "invalid"},
+		{"builtin uniform", "fn effect(uv: vec2<f32>)" + body, []Uniform{{"time", UniformFloat}}, "duplicate"},
+		{"duplicate uniform", "fn effect(uv: vec2<f32>)" + body, []Uniform{{"a", UniformFloat}, {"a", UniformVec2}}, "duplicate"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := NewEffect(tt.source, tt.uniforms...)
+			switch {
+			case tt.err != "":
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("NewEffect = %v, want an error with %q", err, tt.err)
+				}
+			case err != nil:
+				t.Errorf("NewEffect: %v", err)
+			case !slices.Equal(e.Uniforms(), tt.uniforms):
+				t.Errorf("Uniforms = %v, want %v", e.Uniforms(), tt.uniforms)
+			}
+		})
+	}
+}
+
+func TestEffectAnimated(t *testing.T) {
+	tests := []struct {
+		source string
+		want   bool
+	}{
+		{"fn effect(uv: vec2<f32>) -> vec4<f32> { return sample(uv) * sin(u.time); }", true},
+		{"fn effect(uv: vec2<f32>) -> vec4<f32> { return sample(uv) * sin(u . time); }", true},
+		{"fn effect(uv: vec2<f32>) -> vec4<f32> { return sample(uv); } // u.time", false},
+		{"fn effect(uv: vec2<f32>) -> vec4<f32> { return sample(uv) * u.timescale; }", false},
+	}
+	for _, tt := range tests {
+		if got := MustEffect(tt.source).Animated(); got != tt.want {
+			t.Errorf("Animated of %q = %v, want %v", tt.source, got, tt.want)
+		}
+	}
+}
+
+func TestEffectPack(t *testing.T) {
+	e := MustEffect("fn effect(uv: vec2<f32>) -> vec4<f32> { return sample(uv); }",
+		Uniform{"progress", UniformFloat}, Uniform{"tint", UniformVec4}, Uniform{"dir", UniformVec2})
+	// size at 0, time at 2, progress at 3, tint aligned to 4 and dir to 8,
+	// padded to 12.
+	got := e.Pack(Sz(640, 480), 1.5, [][4]float32{{0.25, 9}, {1, 0, 0, 1}})
+	want := []float32{640, 480, 1.5, 0.25, 1, 0, 0, 1, 0, 0, 0, 0}
+	if !slices.Equal(got, want) {
+		t.Errorf("Pack = %v, want %v", got, want)
+	}
+	shader := e.Shader()
+	for _, s := range []string{"progress: f32", "tint: vec4<f32>", "dir: vec2<f32>", "fn fs_effect", "return effect(in.uv);"} {
+		if !strings.Contains(shader, s) {
+			t.Errorf("Shader has no %q", s)
+		}
+	}
+}
+
+func TestMustEffect(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("MustEffect of a source without fn effect did not panic")
+		}
+	}()
+	MustEffect("fn main() {}")
+}
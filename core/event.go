@@ -0,0 +1,125 @@
+package core
+
+// Event is implemented by every event passed to Widget.HandleEvent.
+// Concrete event types live in package event.
+type Event interface {
+	// String returns a short description for logging.
+	String() string
+}
+
+// PointerEvent is an Event that carries a pointer position. The event
+// router uses the position to hit-test the widget tree.
+type PointerEvent interface {
+	Event
+	PointerPosition() Point
+}
+
+// EventResult reports whether a widget consumed an event.
+type EventResult int
+
+// Event results.
+const (
+	// Ignored lets the event bubble to the widget's ancestors.
+	Ignored EventResult = iota
+
+	// Handled stops propagation.
+	Handled
+)
+
+// HitTest returns the path from root to the deepest widget containing p.
+// The path is empty if p is outside root. Children are tested last to first,
+// so widgets painted on top win.
+func HitTest(root Widget, p Point) []Widget {
+	var path []Widget
+	w := root
+	for w != nil && hits(w, p) {
+		path = append(path, w)
+		w = topmostChildAt(w, p)
+	}
+	return path
+}
+
+// PathAt returns the path to the deepest widget at p that a pointer event
+// there would go to, in the topmost overlay taking input or in the main
+// tree, as the window runtime hit-tests them. It is nil outside a window.
+func (c *Context) PathAt(p Point) []Widget {
+	if c.pathAt == nil {
+		return nil
+	}
+	return c.pathAt(p)
+}
+
+// SetPathAt installs fn as the hit test of PathAt. It is called by the
+// window runtime.
+func (c *Context) SetPathAt(fn func(p Point) []Widget) {
+	c.pathAt = fn
+}
+
+func topmostChildAt(w Widget, p Point) Widget {
+	parent, ok := w.(Parent)
+	if !ok {
+		return nil
+	}
+	children := parent.Children()
+	for i := len(children) - 1; i >= 0; i-- {
+		if hits(children[i], p) {
+			return children[i]
+		}
+	}
+	return nil
+}
+
+func hits(w Widget, p Point) bool {
+	if !w.Bounds().Contains(p) {
+		return false
+	}
+	if ht, ok := w.(HitTester); ok {
+		return ht.HitTest(p)
+	}
+	return true
+}
+
+// PathTo returns the path from root to target, or nil if target is not in
+// the tree.
+func PathTo(root, target Widget) []Widget {
+	if root == nil || target == nil {
+		return nil
+	}
+	if root == target {
+		return []Widget{root}
+	}
+	parent, ok := root.(Parent)
+	if !ok {
+		return nil
+	}
+	for _, c := range parent.Children() {
+		if sub := PathTo(c, target); sub != nil {
+			return append([]Widget{root}, sub...)
+		}
+	}
+	return nil
+}
+
+// Dispatch delivers ev to the widgets in path from the deepest one up to
+// the root, stopping at the first widget that returns Handled.
+func Dispatch(ctx *Context, path []Widget, ev Event) EventResult {
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i].HandleEvent(ctx, ev) == Handled {
+			return Handled
+		}
+	}
+	return Ignored
+}
+
+// Walk calls fn for w and each of its descendants in paint order. If fn
+// returns false the descendants of that widget are skipped.
+func Walk(w Widget, fn func(Widget) bool) {
+	if w == nil || !fn(w) {
+		return
+	}
+	if parent, ok := w.(Parent); ok {
+		for _, c := range parent.Children() {
+			Walk(c, fn)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package core
+
+import (
+	"slices"
+	"testing"
+)
+
+// testEvent is an event for tests.
+type testEvent string
+
+func (e testEvent) String() string { return string(e) }
+
+// node is a widget recording the events it receives, handling them if
+// handle is set.
+type node struct {
+	WidgetBase
+	FocusState
+	name   string
+	handle bool
+	got    []Event
+	hit    func(Point) bool
+}
+
+func newNode(name string, r Rect, children ...Widget) *node {
+	n := &node{name: name}
+	n.SetBounds(r)
+	n.SetChildren(children...)
+	return n
+}
+
+func (n *node) Layout(ctx *LayoutContext) Size {
+	for _, c := range n.Children() {
+		ctx.Measure(c, ctx.Constraints.Loosen())
+	}
+	return ctx.Constraints.Constrain(n.Bounds().Size())
+}
+
+func (n *node) Paint(ctx *PaintContext) { PaintChildren(ctx, n.Children()) }
+
+func (n *node) HandleEvent(_ *Context, ev Event) EventResult {
+	n.got = append(n.got, ev)
+	if n.handle {
+		return Handled
+	}
+	return Ignored
+}
+
+func (n *node) HitTest(p Point) bool {
+	return n.hit == nil || n.hit(p)
+}
+
+func names(path []Widget) []string {
+	var s []string
+	for _, w := range path {
+		s = append(s, w.(*node).name)
+	}
+	return s
+}
+
+func TestHitTest(t *testing.T) {
+	// Two overlapping children: the later one is painted on top.
+	a := newNode("a", R(0, 0, 50, 50))
+	b := newNode("b", R(40, 40, 50, 50))
+	round := newNode("round", R(100, 0, 20, 20))
+	round.hit = func(p Point) bool { return p.X < 110 }
+	root := newNode("root", R(0, 0, 200, 200), a, b, round)
+	tests := []struct {
+		name string
+		p    Point
+		want []string
+	}{
+		{"first child", Pt(10, 10), []string{"root", "a"}},
+		{"topmost of overlapping", Pt(45, 45), []string{"root", "b"}},
+		{"root only", Pt(150, 150), []string{"root"}},
+		{"outside", Pt(250, 10), nil},
+		{"hit tester accepts", Pt(105, 5), []string{"root", "round"}},
+		{"hit tester refuses", Pt(115, 5), []string{"root"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := names(HitTest(root, tt.p)); !slices.Equal(got, tt.want) {
+				t.Errorf("HitTest(%v) = %v, want %v", tt.p, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathTo(t *testing.T) {
+	leaf := newNode("leaf", R(0, 0, 1, 1))
+	mid := newNode("mid", R(0, 0, 1, 1), leaf)
+	root := newNode("root", R(0, 0, 1, 1), newNode("other", Rect{}), mid)
+	tests := []struct {
+		name   string
+		target Widget
+		want   []string
+	}{
+		{"leaf", leaf, []string{"root", "mid", "leaf"}},
+		{"root", root, []string{"root"}},
+		{"not in the tree", newNode("stray", Rect{}), nil},
+		{"nil", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := names(PathTo(root, tt.target)); !slices.Equal(got, tt.want) {
+				t.Errorf("PathTo = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDispatchBubbles(t *testing.T) {
+	tests := []struct {
+		name     string
+		handler  int // Index in the path, or -1.
+		want     EventResult
+		received []bool
+	}{
+		{"handled by the target", 2, Handled, []bool{false, false, true}},
+		{"bubbles to the parent", 1, Handled, []bool{false, true, true}},
+		{"ignored by all", -1, Ignored, []bool{true, true, true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := []*node{newNode("root", Rect{}), newNode("mid", Rect{}), newNode("leaf", Rect{})}
+			if tt.handler >= 0 {
+				path[tt.handler].handle = true
+			}
+			ws := []Widget{path[0], path[1], path[2]}
+			if got := Dispatch(NewContext(), ws, testEvent("ev")); got != tt.want {
+				t.Errorf("Dispatch = %v, want %v", got, tt.want)
+			}
+			for i, n := range path {
+				if got := len(n.got) > 0; got != tt.received[i] {
+					t.Errorf("%s received = %v, want %v", n.name, got, tt.received[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWalk(t *testing.T) {
+	c := newNode("c", Rect{})
+	b := newNode("b", Rect{}, c)
+	root := newNode("root", Rect{}, b, newNode("d", Rect{}))
+	var all, pruned []string
+	Walk(root, func(w Widget) bool {
+		all = append(all, w.(*node).name)
+		return true
+	})
+	Walk(root, func(w Widget) bool {
+		pruned = append(pruned, w.(*node).name)
+		return w != b
+	})
+	if want := []string{"root", "b", "c", "d"}; !slices.Equal(all, want) {
+		t.Errorf("Walk = %v, want %v", all, want)
+	}
+	if want := []string{"root", "b", "d"}; !slices.Equal(pruned, want) {
+		t.Errorf("Walk skipping b's children = %v, want %v", pruned, want)
+	}
+	Walk(nil, func(Widget) bool { t.Error("called for nil"); return true })
+}
@@ -0,0 +1,96 @@
+package core
+
+// Filter adjusts what lies behind a container, such as the blur of a
+// frosted glass panel or a dialog scrim. The zero Filter is no filter;
+// filters start from Blur:
+//
+//	f := core.Blur(12).WithSaturation(1.8)
+type Filter struct {
+	// Blur is the standard deviation of a gaussian blur, in logical
+	// pixels, as in the CSS blur() function.
+	Blur float32
+
+	// Saturation scales the saturation of colors: 0 turns them gray,
+	// 1 leaves them as they are and more makes them more vivid.
+	Saturation float32
+
+	// Brightness scales colors: 0 turns them black and 1 leaves them as
+	// they are.
+	Brightness float32
+}
+
+// Blur returns a filter blurring by the standard deviation sigma.
+func Blur(sigma float32) Filter {
+	return Filter{Blur: max(sigma, 0), Saturation: 1, Brightness: 1}
+}
+
+// WithSaturation returns f scaling saturation by s.
+func (f Filter) WithSaturation(s float32) Filter {
+	f.Saturation = max(s, 0)
+	return f
+}
+
+// WithBrightness returns f scaling colors by b.
+func (f Filter) WithBrightness(b float32) Filter {
+	f.Brightness = max(b, 0)
+	return f
+}
+
+// IsZero reports whether f is the zero Filter, which draws nothing.
+func (f Filter) IsZero() bool {
+	return f == Filter{}
+}
+
+// Reach returns how far outside a region the filter of it reads what
+// was drawn, in logical pixels: three deviations of the blur, past which
+// a gaussian is too small to matter.
+func (f Filter) Reach() float32 {
+	return 3 * f.Blur
+}
+
+// Downsample returns the factor a backend may reduce the resolution of
+// the blur by at scale device pixels to each logical pixel, which a
+// gaussian spread over several pixels hides: 1 below a deviation of two
+// device pixels, 2 below eight and 4 beyond. Blurring at a quarter of the
+// resolution reads a sixteenth of the pixels, which keeps large blurs
+// cheap on integrated GPUs.
+func (f Filter) Downsample(scale float32) int {
+	switch s := f.Blur * scale; {
+	case s < 2:
+		return 1
+	case s < 8:
+		return 2
+	}
+	return 4
+}
+
+// Apply returns the color c filtered by the saturation and brightness of
+// f, leaving alone its alpha. Colors are scaled in the sRGB values as in
+// CSS filters.
+func (f Filter) Apply(c Color) Color {
+	// The luminance weights of the CSS saturate() filter.
+	l := 0.2126*c.R + 0.7152*c.G + 0.0722*c.B
+	s, b := f.Saturation, f.Brightness
+	return Color{
+		R: Clamp((l+(c.R-l)*s)*b, 0, 1),
+		G: Clamp((l+(c.G-l)*s)*b, 0, 1),
+		B: Clamp((l+(c.B-l)*s)*b, 0, 1),
+		A: c.A,
+	}
+}
+
+// BackdropCanvas is implemented by canvases that can filter what they
+// have drawn, such as for frosted glass.
+//
+// GPU backends implement it as a compositor pass: the target beneath the
+// region and its reach is copied into a texture, blurred separably at
+// the resolution Filter.Downsample allows and drawn back through the
+// rounded rectangle.
+type BackdropCanvas interface {
+	Canvas
+
+	// DrawBackdrop replaces what was drawn inside the rounded rectangle
+	// r, within the clip, with it filtered by f, reading what lies up to
+	// f.Reach outside r.
+	DrawBackdrop(r Rect, radius float32, f Filter)
+}
@@ -0,0 +1,54 @@
+package core
+
+import "testing"
+
+func TestFilter(t *testing.T) {
+	f := Blur(-2).WithSaturation(-1).WithBrightness(-1)
+	if f != (Filter{}) || !f.IsZero() {
+		t.Errorf("%+v, want negatives clamped to 0", f)
+	}
+	if Blur(0).IsZero() {
+		t.Error("Blur(0) is the zero filter, which draws nothing")
+	}
+	if got := Blur(4).Reach(); got != 12 {
+		t.Errorf("Reach() = %v, want three deviations", got)
+	}
+	tests := []struct {
+		blur, scale float32
+		want        int
+	}{
+		{1, 1, 1},
+		{1, 2, 2},
+		{3, 2, 2},
+		{4, 2, 4},
+		{20, 1, 4},
+	}
+	for _, tt := range tests {
+		if got := Blur(tt.blur).Downsample(tt.scale); got != tt.want {
+			t.Errorf("Blur(%v).Downsample(%v) = %d, want %d", tt.blur, tt.scale, got, tt.want)
+		}
+	}
+}
+
+func TestFilterApply(t *testing.T) {
+	c := Color{R: 1, G: 0.5, B: 0, A: 0.5}
+	l := float32(0.2126 + 0.7152*0.5)
+	tests := []struct {
+		name string
+		f    Filter
+		want Color
+	}{
+		{"none", Blur(4), c},
+		{"gray", Blur(0).WithSaturation(0), Color{R: l, G: l, B: l, A: 0.5}},
+		{"black", Blur(0).WithBrightness(0), Color{A: 0.5}},
+		{"vivid", Blur(0).WithSaturation(2), Color{R: 1, G: Clamp(l+(0.5-l)*2, 0, 1), B: 0, A: 0.5}},
+		{"bright", Blur(0).WithBrightness(1.5), Color{R: 1, G: 0.75, B: 0, A: 0.5}},
+	}
+	d := func(x, y float32) bool { return x-y < 1e-6 && y-x < 1e-6 }
+	for _, tt := range tests {
+		got := tt.f.Apply(c)
+		if !d(got.R, tt.want.R) || !d(got.G, tt.want.G) || !d(got.B, tt.want.B) || got.A != tt.want.A {
+			t.Errorf("%s: Apply = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package core
+
+// FocusNext moves keyboard focus to the Focusable widget after the focused
+// one, or before it if backward is set, among the descendants of scope in
+// paint order, wrapping around at the ends. Focus that lies outside scope
+// moves to its first (or last) focusable widget, which is how modal overlays
+// keep focus inside. It reports whether focus moved.
+func FocusNext(ctx *Context, scope Widget, backward bool) bool {
+	var all []Widget
+	Walk(scope, func(w Widget) bool {
+		if _, ok := w.(Focusable); ok {
+			all = append(all, w)
+		}
+		return true
+	})
+	if len(all) == 0 {
+		return false
+	}
+	cur := -1
+	for i, w := range all {
+		if w == ctx.Focused() {
+			cur = i
+			break
+		}
+	}
+	next := 0
+	switch {
+	case cur < 0 && backward:
+		next = len(all) - 1
+	case cur >= 0 && backward:
+		next = (cur - 1 + len(all)) % len(all)
+	case cur >= 0:
+		next = (cur + 1) % len(all)
+	}
+	if all[next] == ctx.Focused() {
+		return false
+	}
+	ctx.RequestFocus(all[next])
+	return true
+}
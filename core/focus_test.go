@@ -0,0 +1,56 @@
+package core
+
+import "testing"
+
+func TestFocusNext(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     string
+		backward bool
+		want     string
+		moved    bool
+	}{
+		{"forward", "b", false, "c", true},
+		{"backward", "c", true, "b", true},
+		{"wraps forward", "d", false, "a", true},
+		{"wraps backward", "a", true, "d", true},
+		{"from outside the scope", "e", false, "a", true},
+		{"backward from outside the scope", "e", true, "d", true},
+		{"from nothing", "", false, "a", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes := map[string]*node{}
+			for _, n := range []string{"b", "d", "e"} {
+				nodes[n] = newNode(n, Rect{})
+			}
+			nodes["c"] = newNode("c", Rect{}, nodes["d"])
+			nodes["a"] = newNode("a", Rect{}, nodes["b"], nodes["c"])
+			ctx := NewContext()
+			if tt.from != "" {
+				ctx.RequestFocus(nodes[tt.from])
+			}
+			if got := FocusNext(ctx, nodes["a"], tt.backward); got != tt.moved {
+				t.Errorf("FocusNext() = %v, want %v", got, tt.moved)
+			}
+			if got := ctx.Focused().(*node); got.name != tt.want {
+				t.Errorf("focus on %s, want %s", got.name, tt.want)
+			}
+			if !nodes[tt.want].IsFocused() {
+				t.Error("the focused widget was not told")
+			}
+			if tt.from != "" && tt.from != tt.want && nodes[tt.from].IsFocused() {
+				t.Error("the widget losing focus was not told")
+			}
+		})
+	}
+}
+
+func TestFocusNextAlone(t *testing.T) {
+	ctx := NewContext()
+	only := newNode("only", Rect{})
+	ctx.RequestFocus(only)
+	if FocusNext(ctx, only, false) {
+		t.Error("focus moved with one focusable widget")
+	}
+}
@@ -0,0 +1,181 @@
+package core
+
+import "math"
+
+// Infinity is used for unbounded constraints and extents.
+var Infinity = float32(math.Inf(1))
+
+// Point is a position in logical pixels.
+type Point struct {
+	X, Y float32
+}
+
+// Pt is shorthand for Point{X: x, Y: y}.
+func Pt(x, y float32) Point {
+	return Point{X: x, Y: y}
+}
+
+// Add returns p+q.
+func (p Point) Add(q Point) Point {
+	return Point{X: p.X + q.X, Y: p.Y + q.Y}
+}
+
+// Sub returns p-q.
+func (p Point) Sub(q Point) Point {
+	return Point{X: p.X - q.X, Y: p.Y - q.Y}
+}
+
+// Scale returns p with both coordinates multiplied by s.
+func (p Point) Scale(s float32) Point {
+	return Point{X: p.X * s, Y: p.Y * s}
+}
+
+// Size is a width and height in logical pixels.
+type Size struct {
+	Width, Height float32
+}
+
+// Sz is shorthand for Size{Width: w, Height: h}.
+func Sz(w, h float32) Size {
+	return Size{Width: w, Height: h}
+}
+
+// IsEmpty reports whether the size has no area.
+func (s Size) IsEmpty() bool {
+	return s.Width <= 0 || s.Height <= 0
+}
+
+// Rect is an axis-aligned rectangle defined by its top-left corner and size.
+type Rect struct {
+	X, Y          float32
+	Width, Height float32
+}
+
+// R is shorthand for Rect{X: x, Y: y, Width: w, Height: h}.
+func R(x, y, w, h float32) Rect {
+	return Rect{X: x, Y: y, Width: w, Height: h}
+}
+
+// RectFromPoints returns the rectangle spanning min to max.
+func RectFromPoints(lo, hi Point) Rect {
+	return Rect{X: lo.X, Y: lo.Y, Width: hi.X - lo.X, Height: hi.Y - lo.Y}
+}
+
+// Origin returns the top-left corner.
+func (r Rect) Origin() Point {
+	return Point{X: r.X, Y: r.Y}
+}
+
+// Size returns the rectangle's size.
+func (r Rect) Size() Size {
+	return Size{Width: r.Width, Height: r.Height}
+}
+
+// Right returns the x coordinate of the right edge.
+func (r Rect) Right() float32 {
+	return r.X + r.Width
+}
+
+// Bottom returns the y coordinate of the bottom edge.
+func (r Rect) Bottom() float32 {
+	return r.Y + r.Height
+}
+
+// Center returns the center point.
+func (r Rect) Center() Point {
+	return Point{X: r.X + r.Width/2, Y: r.Y + r.Height/2}
+}
+
+// IsEmpty reports whether the rectangle has no area.
+func (r Rect) IsEmpty() bool {
+	return r.Width <= 0 || r.Height <= 0
+}
+
+// Contains reports whether p lies inside r. The right and bottom edges are
+// exclusive so that adjacent rectangles never both contain a point.
+func (r Rect) Contains(p Point) bool {
+	return p.X >= r.X && p.X < r.X+r.Width && p.Y >= r.Y && p.Y < r.Y+r.Height
+}
+
+// Translate returns r moved by d.
+func (r Rect) Translate(d Point) Rect {
+	r.X += d.X
+	r.Y += d.Y
+	return r
+}
+
+// Inset returns r shrunk by the given insets. The result never has a
+// negative size.
+func (r Rect) Inset(in Insets) Rect {
+	r.X += in.Left
+	r.Y += in.Top
+	r.Width = max(0, r.Width-in.Horizontal())
+	r.Height = max(0, r.Height-in.Vertical())
+	return r
+}
+
+// Intersect returns the overlapping area of r and o, or an empty rectangle
+// if they do not overlap.
+func (r Rect) Intersect(o Rect) Rect {
+	x0 := max(r.X, o.X)
+	y0 := max(r.Y, o.Y)
+	x1 := min(r.Right(), o.Right())
+	y1 := min(r.Bottom(), o.Bottom())
+	if x1 <= x0 || y1 <= y0 {
+		return Rect{X: x0, Y: y0}
+	}
+	return Rect{X: x0, Y: y0, Width: x1 - x0, Height: y1 - y0}
+}
+
+// Union returns the smallest rectangle containing both r and o. Empty
+// rectangles are ignored.
+func (r Rect) Union(o Rect) Rect {
+	if r.IsEmpty() {
+		return o
+	}
+	if o.IsEmpty() {
+		return r
+	}
+	x0 := min(r.X, o.X)
+	y0 := min(r.Y, o.Y)
+	x1 := max(r.Right(), o.Right())
+	y1 := max(r.Bottom(), o.Bottom())
+	return Rect{X: x0, Y: y0, Width: x1 - x0, Height: y1 - y0}
+}
+
+// Insets describes spacing on each side of a rectangle.
+type Insets struct {
+	Top, Right, Bottom, Left float32
+}
+
+// UniformInsets returns insets of v on every side.
+func UniformInsets(v float32) Insets {
+	return Insets{Top: v, Right: v, Bottom: v, Left: v}
+}
+
+// SymmetricInsets returns insets of h on the left and right and v on the
+// top and bottom.
+func SymmetricInsets(h, v float32) Insets {
+	return Insets{Top: v, Right: h, Bottom: v, Left: h}
+}
+
+// Horizontal returns Left+Right.
+func (in Insets) Horizontal() float32 {
+	return in.Left + in.Right
+}
+
+// Vertical returns Top+Bottom.
+func (in Insets) Vertical() float32 {
+	return in.Top + in.Bottom
+}
+
+// Clamp returns v limited to the range [lo, hi]. If hi < lo, lo wins.
+func Clamp(v, lo, hi float32) float32 {
+	if v > hi {
+		v = hi
+	}
+	if v < lo {
+		v = lo
+	}
+	return v
+}
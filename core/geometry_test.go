@@ -0,0 +1,128 @@
+package core
+
+import "testing"
+
+func TestRectContains(t *testing.T) {
+	r := R(10, 20, 30, 40)
+	tests := []struct {
+		name string
+		p    Point
+		want bool
+	}{
+		{"top left", Pt(10, 20), true},
+		{"inside", Pt(25, 40), true},
+		{"right edge", Pt(40, 30), false},
+		{"bottom edge", Pt(20, 60), false},
+		{"left of", Pt(9.9, 30), false},
+		{"above", Pt(20, 19), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.Contains(tt.p); got != tt.want {
+				t.Errorf("Contains(%v) = %v, want %v", tt.p, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRectIntersectUnion(t *testing.T) {
+	tests := []struct {
+		name      string
+		a, b      Rect
+		intersect Rect
+		union     Rect
+	}{
+		{"overlapping", R(0, 0, 10, 10), R(5, 5, 10, 10), R(5, 5, 5, 5), R(0, 0, 15, 15)},
+		{"contained", R(0, 0, 10, 10), R(2, 3, 4, 5), R(2, 3, 4, 5), R(0, 0, 10, 10)},
+		{"disjoint", R(0, 0, 10, 10), R(20, 0, 10, 10), R(20, 0, 0, 0), R(0, 0, 30, 10)},
+		{"touching", R(0, 0, 10, 10), R(10, 0, 10, 10), R(10, 0, 0, 0), R(0, 0, 20, 10)},
+		{"empty ignored", R(0, 0, 0, 0), R(5, 5, 10, 10), R(5, 5, 0, 0), R(5, 5, 10, 10)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.Intersect(tt.b); got != tt.intersect {
+				t.Errorf("Intersect = %v, want %v", got, tt.intersect)
+			}
+			if got := tt.a.Union(tt.b); got != tt.union {
+				t.Errorf("Union = %v, want %v", got, tt.union)
+			}
+		})
+	}
+}
+
+func TestRectAccessors(t *testing.T) {
+	r := R(10, 20, 30, 40)
+	if got := r.Origin(); got != Pt(10, 20) {
+		t.Errorf("Origin = %v", got)
+	}
+	if got := r.Size(); got != Sz(30, 40) {
+		t.Errorf("Size = %v", got)
+	}
+	if r.Right() != 40 || r.Bottom() != 60 {
+		t.Errorf("Right, Bottom = %v, %v, want 40, 60", r.Right(), r.Bottom())
+	}
+	if got := r.Center(); got != Pt(25, 40) {
+		t.Errorf("Center = %v", got)
+	}
+	if got := r.Translate(Pt(-10, 5)); got != R(0, 25, 30, 40) {
+		t.Errorf("Translate = %v", got)
+	}
+	if got := RectFromPoints(Pt(1, 2), Pt(4, 8)); got != R(1, 2, 3, 6) {
+		t.Errorf("RectFromPoints = %v", got)
+	}
+	if !R(0, 0, 0, 5).IsEmpty() || R(0, 0, 1, 1).IsEmpty() {
+		t.Error("IsEmpty wrong")
+	}
+	if !Sz(3, 0).IsEmpty() || Sz(3, 1).IsEmpty() {
+		t.Error("Size.IsEmpty wrong")
+	}
+}
+
+func TestRectInset(t *testing.T) {
+	tests := []struct {
+		name string
+		in   Insets
+		want Rect
+	}{
+		{"uniform", UniformInsets(5), R(5, 5, 90, 40)},
+		{"symmetric", SymmetricInsets(10, 2), R(10, 2, 80, 46)},
+		{"larger than the rect", UniformInsets(60), R(60, 60, 0, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := R(0, 0, 100, 50).Inset(tt.in); got != tt.want {
+				t.Errorf("Inset = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPointArithmetic(t *testing.T) {
+	p, q := Pt(1, 2), Pt(3, 5)
+	if got := p.Add(q); got != Pt(4, 7) {
+		t.Errorf("Add = %v", got)
+	}
+	if got := q.Sub(p); got != Pt(2, 3) {
+		t.Errorf("Sub = %v", got)
+	}
+	if got := p.Scale(2); got != Pt(2, 4) {
+		t.Errorf("Scale = %v", got)
+	}
+}
+
+func TestClamp(t *testing.T) {
+	tests := []struct {
+		v, lo, hi, want float32
+	}{
+		{5, 0, 10, 5},
+		{-1, 0, 10, 0},
+		{11, 0, 10, 10},
+		{5, 8, 2, 8}, // lo wins
+		{Infinity, 0, 10, 10},
+	}
+	for _, tt := range tests {
+		if got := Clamp(tt.v, tt.lo, tt.hi); got != tt.want {
+			t.Errorf("Clamp(%v, %v, %v) = %v, want %v", tt.v, tt.lo, tt.hi, got, tt.want)
+		}
+	}
+}
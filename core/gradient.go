@@ -0,0 +1,305 @@
+package core
+
+import (
+	"math"
+	"slices"
+)
+
+// Gradient is a brush whose color varies across what it fills, set as
+// the FillGradient or StrokeGradient of a style:
+//
+//	g := core.LinearGradient(core.Pt(0, 0), core.Pt(0, 1),
+//		core.Stop(0, top), core.Stop(1, bottom))
+//	g.Relative = true
+//	g.Space = core.SpaceOklab
+//	cv.DrawRoundedRect(r, 8, core.GradientFilled(g))
+//
+// A gradient must not be changed once drawn, as canvases may keep it
+// until the frame is rasterized.
+type Gradient struct {
+	Kind GradientKind
+
+	// Start and End are the line along which a linear gradient runs,
+	// from offset 0 at Start to 1 at End. Start is the center of radial
+	// and conic gradients.
+	Start, End Point
+
+	// Radius is the radius of the circle a radial gradient reaches
+	// offset 1 at.
+	Radius float32
+
+	// Angle is where conic gradients start, in radians clockwise from
+	// the positive x axis, and Sweep the angle they reach offset 1
+	// after: a full turn for conic gradients and less for sweeps.
+	Angle, Sweep float32
+
+	// Stops are the colors at offsets along the gradient, in increasing
+	// order. Between stops colors are interpolated in Space.
+	Stops []GradientStop
+
+	Spread SpreadMode
+	Space  ColorSpace
+
+	// Relative makes the points and radius fractions of the bounds of
+	// the shape drawn, from (0, 0) at its top left to (1, 1) at its
+	// bottom right, so that the gradient stretches with the shape, as
+	// for widget backgrounds. A radial gradient then fills an ellipse.
+	Relative bool
+}
+
+// GradientKind is the geometry of a Gradient.
+type GradientKind uint8
+
+const (
+	GradientLinear GradientKind = iota
+	GradientRadial
+	GradientConic // Conic and sweep gradients.
+)
+
+// SpreadMode selects the colors of a gradient beyond offsets 0 and 1.
+type SpreadMode uint8
+
+const (
+	SpreadPad     SpreadMode = iota // The colors of the first and last stops.
+	SpreadRepeat                    // The gradient repeated.
+	SpreadReflect                   // The gradient repeated, mirrored every other time.
+)
+
+// ColorSpace is the space a Gradient interpolates colors in.
+type ColorSpace uint8
+
+const (
+	// SpaceSRGB interpolates the sRGB components, as CSS gradients do
+	// by default.
+	SpaceSRGB ColorSpace = iota
+
+	// SpaceOklab interpolates in the perceptual Oklab space, which
+	// keeps the midpoints of gradients between saturated colors from
+	// going gray or dark.
+	SpaceOklab
+)
+
+// GradientStop is a color at an offset along a Gradient.
+type GradientStop struct {
+	Offset float32
+	Color  Color
+}
+
+// Stop returns the stop of c at offset.
+func Stop(offset float32, c Color) GradientStop {
+	return GradientStop{Offset: offset, Color: c}
+}
+
+// LinearGradient returns a gradient running from start to end.
+func LinearGradient(start, end Point, stops ...GradientStop) *Gradient {
+	return &Gradient{Kind: GradientLinear, Start: start, End: end, Stops: stops}
+}
+
+// RadialGradient returns a gradient running out from center to a circle
+// of radius.
+func RadialGradient(center Point, radius float32, stops ...GradientStop) *Gradient {
+	return &Gradient{Kind: GradientRadial, Start: center, Radius: radius, Stops: stops}
+}
+
+// ConicGradient returns a gradient running a full turn clockwise around
+// center from angle, in radians from the positive x axis.
+func ConicGradient(center Point, angle float32, stops ...GradientStop) *Gradient {
+	return SweepGradient(center, angle, 2*math.Pi, stops...)
+}
+
+// SweepGradient returns a gradient running clockwise around center from
+// angle over sweep radians. Beyond the sweep the spread mode applies.
+func SweepGradient(center Point, angle, sweep float32, stops ...GradientStop) *Gradient {
+	return &Gradient{Kind: GradientConic, Start: center, Angle: angle, Sweep: sweep, Stops: stops}
+}
+
+// GradientFilled returns a RectStyle filling with g. Its Fill is the
+// average color of g, which canvases without gradients fill with.
+func GradientFilled(g *Gradient) RectStyle {
+	return RectStyle{Fill: g.Average(), FillGradient: g}
+}
+
+// GradientStroked returns a RectStyle stroking with g at width, with
+// the average color of g as its Stroke.
+func GradientStroked(g *Gradient, width float32) RectStyle {
+	return RectStyle{Stroke: g.Average(), StrokeGradient: g, StrokeWidth: width}
+}
+
+// Offset returns the offset of the gradient at p, before the spread mode
+// applies, for a shape whose bounds are box.
+func (g *Gradient) Offset(p Point, box Rect) float32 {
+	a, b := g.Start, g.End
+	if g.Relative {
+		if box.Width == 0 || box.Height == 0 {
+			return 0
+		}
+		p = Pt((p.X-box.X)/box.Width, (p.Y-box.Y)/box.Height)
+	}
+	switch g.Kind {
+	case GradientRadial:
+		if g.Radius <= 0 {
+			return 1
+		}
+		return float32(math.Hypot(float64(p.X-a.X), float64(p.Y-a.Y))) / g.Radius
+	case GradientConic:
+		if g.Sweep == 0 {
+			return 0
+		}
+		angle := math.Atan2(float64(p.Y-a.Y), float64(p.X-a.X)) - float64(g.Angle)
+		angle = math.Mod(angle, 2*math.Pi)
+		if angle < 0 {
+			angle += 2 * math.Pi
+		}
+		return float32(angle) / g.Sweep
+	}
+	d := b.Sub(a)
+	l := d.X*d.X + d.Y*d.Y
+	if l == 0 {
+		return 0
+	}
+	return ((p.X-a.X)*d.X + (p.Y-a.Y)*d.Y) / l
+}
+
+// At returns the color of the gradient at p, for a shape whose bounds
+// are box.
+func (g *Gradient) At(p Point, box Rect) Color {
+	return g.ColorAt(g.Offset(p, box))
+}
+
+// ColorAt returns the color at offset t, after the spread mode applies.
+func (g *Gradient) ColorAt(t float32) Color {
+	switch n := len(g.Stops); {
+	case n == 0:
+		return Transparent
+	case n == 1:
+		return g.Stops[0].Color
+	}
+	switch g.Spread {
+	case SpreadRepeat:
+		t -= float32(math.Floor(float64(t)))
+	case SpreadReflect:
+		t = float32(math.Abs(math.Mod(float64(t), 2)))
+		if t > 1 {
+			t = 2 - t
+		}
+	}
+	first, last := g.Stops[0], g.Stops[len(g.Stops)-1]
+	if t <= first.Offset {
+		return first.Color
+	}
+	if t >= last.Offset {
+		return last.Color
+	}
+	i := 1
+	for g.Stops[i].Offset < t {
+		i++
+	}
+	a, b := g.Stops[i-1], g.Stops[i]
+	f := float32(0)
+	if b.Offset > a.Offset {
+		f = (t - a.Offset) / (b.Offset - a.Offset)
+	}
+	return interpolate(a.Color, b.Color, f, g.Space)
+}
+
+// Ramp returns n colors of the gradient evenly spaced from offset 0 to
+// 1, for GPU backends drawing gradients from a lookup texture.
+func (g *Gradient) Ramp(n int) []Color {
+	out := make([]Color, n)
+	for i := range out {
+		t := float32(0)
+		if n > 1 {
+			t = float32(i) / float32(n-1)
+		}
+		out[i] = g.ColorAt(t)
+	}
+	return out
+}
+
+// Average returns the average color of the gradient from offset 0 to 1.
+func (g *Gradient) Average() Color {
+	var r, gr, b, a float32
+	const n = 32
+	for _, c := range g.Ramp(n) {
+		r, gr, b, a = r+c.R*c.A, gr+c.G*c.A, b+c.B*c.A, a+c.A
+	}
+	if a == 0 {
+		return Transparent
+	}
+	return Color{R: r / a, G: gr / a, B: b / a, A: a / n}
+}
+
+// Equal reports whether g and o are the same gradient. Either may be nil.
+func (g *Gradient) Equal(o *Gradient) bool {
+	if g == nil || o == nil {
+		return g == o
+	}
+	return g.Kind == o.Kind && g.Start == o.Start && g.End == o.End && g.Radius == o.Radius &&
+		g.Angle == o.Angle && g.Sweep == o.Sweep && g.Spread == o.Spread && g.Space == o.Space &&
+		g.Relative == o.Relative && slices.Equal(g.Stops, o.Stops)
+}
+
+// interpolate returns the color f of the way from a to b in space, with
+// premultiplied alpha, as CSS interpolates, so that fading to transparent
+// does not pass through the color of the transparent stop.
+func interpolate(a, b Color, f float32, space ColorSpace) Color {
+	alpha := a.A + (b.A-a.A)*f
+	if alpha == 0 {
+		return Transparent
+	}
+	ca, cb := [3]float32{a.R, a.G, a.B}, [3]float32{b.R, b.G, b.B}
+	if space == SpaceOklab {
+		ca, cb = toOklab(ca), toOklab(cb)
+	}
+	var c [3]float32
+	for i := range c {
+		c[i] = (ca[i]*a.A + (cb[i]*b.A-ca[i]*a.A)*f) / alpha
+	}
+	if space == SpaceOklab {
+		c = fromOklab(c)
+	}
+	return Color{R: Clamp(c[0], 0, 1), G: Clamp(c[1], 0, 1), B: Clamp(c[2], 0, 1), A: alpha}
+}
+
+func srgbToLinear(v float32) float64 {
+	x := float64(v)
+	if x <= 0.04045 {
+		return x / 12.92
+	}
+	return math.Pow((x+0.055)/1.055, 2.4)
+}
+
+func linearToSRGB(x float64) float32 {
+	x = max(x, 0)
+	if x <= 0.0031308 {
+		return float32(x * 12.92)
+	}
+	return float32(1.055*math.Pow(x, 1/2.4) - 0.055)
+}
+
+// toOklab converts sRGB components to Oklab L, a and b.
+func toOklab(c [3]float32) [3]float32 {
+	r, g, b := srgbToLinear(c[0]), srgbToLinear(c[1]), srgbToLinear(c[2])
+	l := math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*b)
+	m := math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*b)
+	s := math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*b)
+	return [3]float32{
+		float32(0.2104542553*l + 0.7936177850*m - 0.0040720468*s),
+		float32(1.9779984951*l - 2.4285922050*m + 0.4505937099*s),
+		float32(0.0259040371*l + 0.7827717662*m - 0.8086757660*s),
+	}
+}
+
+// fromOklab converts Oklab L, a and b to sRGB components.
+func fromOklab(c [3]float32) [3]float32 {
+	L, A, B := float64(c[0]), float64(c[1]), float64(c[2])
+	l := L + 0.3963377774*A + 0.2158037573*B
+	m := L - 0.1055613458*A - 0.0638541728*B
+	s := L - 0.0894841775*A - 1.2914855480*B
+	l, m, s = l*l*l, m*m*m, s*s*s
+	return [3]float32{
+		linearToSRGB(+4.0767416621*l - 3.3077115913*m + 0.2309699292*s),
+		linearToSRGB(-1.2684380046*l + 2.6097574011*m - 0.3413193965*s),
+		linearToSRGB(-0.0041960863*l - 0.7034186147*m + 1.7076147010*s),
+	}
+}
@@ -0,0 +1,116 @@
+package core
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGradientOffset(t *testing.T) {
+	box := R(10, 20, 100, 50)
+	tests := []struct {
+		name string
+		g    *Gradient
+		p    Point
+		want float32
+	}{
+		{"linear", LinearGradient(Pt(0, 0), Pt(10, 0)), Pt(5, 7), 0.5},
+		{"linear aslant", LinearGradient(Pt(0, 0), Pt(10, 10)), Pt(10, 0), 0.5},
+		{"linear beyond", LinearGradient(Pt(0, 0), Pt(10, 0)), Pt(-5, 0), -0.5},
+		{"linear of a point", LinearGradient(Pt(3, 3), Pt(3, 3)), Pt(5, 0), 0},
+		{"radial", RadialGradient(Pt(0, 0), 10), Pt(6, 8), 1},
+		{"radial of no radius", RadialGradient(Pt(0, 0), 0), Pt(1, 1), 1},
+		{"conic", ConicGradient(Pt(0, 0), 0), Pt(0, 5), 0.25},
+		{"conic from an angle", ConicGradient(Pt(0, 0), math.Pi/2), Pt(5, 0), 0.75},
+		{"sweep", SweepGradient(Pt(0, 0), 0, math.Pi/2), Pt(-5, 0), 2},
+		{"sweep of nothing", SweepGradient(Pt(0, 0), 0, 0), Pt(5, 5), 0},
+		{"relative", &Gradient{End: Pt(1, 0), Relative: true}, Pt(35, 0), 0.25},
+		{"relative radial", &Gradient{Kind: GradientRadial, Start: Pt(0.5, 0.5), Radius: 0.5, Relative: true}, Pt(60, 20), 1},
+	}
+	for _, tt := range tests {
+		if got := tt.g.Offset(tt.p, box); math.Abs(float64(got-tt.want)) > 1e-5 {
+			t.Errorf("%s: Offset(%v) = %v, want %v", tt.name, tt.p, got, tt.want)
+		}
+	}
+	g := &Gradient{End: Pt(1, 0), Relative: true}
+	if got := g.Offset(Pt(5, 5), R(0, 0, 0, 10)); got != 0 {
+		t.Errorf("Offset = %v in an empty box, want 0", got)
+	}
+}
+
+func TestGradientColorAt(t *testing.T) {
+	red, blue := RGB(255, 0, 0), RGB(0, 0, 255)
+	two := func(spread SpreadMode) *Gradient {
+		g := LinearGradient(Pt(0, 0), Pt(1, 0), Stop(0.2, red), Stop(0.8, blue))
+		g.Spread = spread
+		return g
+	}
+	hard := LinearGradient(Pt(0, 0), Pt(1, 0), Stop(0, red), Stop(0.5, red), Stop(0.5, blue), Stop(1, blue))
+	purple := Color{R: 0.5, B: 0.5, A: 1}
+	tests := []struct {
+		name string
+		g    *Gradient
+		t    float32
+		want Color
+	}{
+		{"no stops", LinearGradient(Pt(0, 0), Pt(1, 0)), 0.5, Transparent},
+		{"one stop", LinearGradient(Pt(0, 0), Pt(1, 0), Stop(0.5, red)), 0, red},
+		{"before the first", two(SpreadPad), 0, red},
+		{"after the last", two(SpreadPad), 1.5, blue},
+		{"between", two(SpreadPad), 0.5, purple},
+		{"repeated", two(SpreadRepeat), 1.5, purple},
+		{"repeated backwards", two(SpreadRepeat), -0.35, Color{R: 0.25, B: 0.75, A: 1}},
+		{"reflected", two(SpreadReflect), 1.35, Color{R: 0.25, B: 0.75, A: 1}},
+		{"reflected backwards", two(SpreadReflect), -0.35, Color{R: 0.75, B: 0.25, A: 1}},
+		{"at a hard stop", hard, 0.5, red},
+		{"past a hard stop", hard, 0.51, blue},
+		{"to transparent", LinearGradient(Pt(0, 0), Pt(1, 0), Stop(0, red), Stop(1, Transparent)), 0.5, red.WithAlpha(0.5)},
+	}
+	for _, tt := range tests {
+		got := tt.g.ColorAt(tt.t)
+		if d := max(abs32(got.R-tt.want.R), abs32(got.G-tt.want.G), abs32(got.B-tt.want.B), abs32(got.A-tt.want.A)); d > 1e-5 {
+			t.Errorf("%s: ColorAt(%v) = %+v, want %+v", tt.name, tt.t, got, tt.want)
+		}
+	}
+
+	// In Oklab, the middle of red and green stays as light as they are
+	// rather than darkening as in sRGB.
+	g := LinearGradient(Pt(0, 0), Pt(1, 0), Stop(0, red), Stop(1, RGB(0, 255, 0)))
+	srgb := g.ColorAt(0.5)
+	g.Space = SpaceOklab
+	oklab := g.ColorAt(0.5)
+	if oklab.R+oklab.G <= srgb.R+srgb.G || oklab.B > 0.01 {
+		t.Errorf("middle %+v in Oklab, %+v in sRGB", oklab, srgb)
+	}
+	if end := g.ColorAt(1); abs32(end.G-1) > 1e-4 || end.R > 1e-4 {
+		t.Errorf("end %+v in Oklab, want green back", end)
+	}
+}
+
+func abs32(v float32) float32 {
+	return float32(math.Abs(float64(v)))
+}
+
+func TestGradientRampAverage(t *testing.T) {
+	g := LinearGradient(Pt(0, 0), Pt(1, 0), Stop(0, Black), Stop(1, White))
+	ramp := g.Ramp(3)
+	if len(ramp) != 3 || ramp[0] != Black || ramp[2] != White || abs32(ramp[1].R-0.5) > 1e-6 {
+		t.Errorf("Ramp(3) = %v", ramp)
+	}
+	if got := g.Ramp(1); got[0] != Black {
+		t.Errorf("Ramp(1) = %v, want the start", got)
+	}
+	if got := g.Average(); abs32(got.R-0.5) > 1e-6 || got.A != 1 {
+		t.Errorf("Average() = %+v, want mid gray", got)
+	}
+	// Transparent stops do not darken the average.
+	g = LinearGradient(Pt(0, 0), Pt(1, 0), Stop(0, White), Stop(1, Transparent))
+	if got := g.Average(); got.R != 1 || abs32(got.A-0.5) > 1e-6 {
+		t.Errorf("Average() = %+v, want half transparent white", got)
+	}
+	if got := LinearGradient(Pt(0, 0), Pt(1, 0)).Average(); got != Transparent {
+		t.Errorf("Average() = %+v without stops", got)
+	}
+	if !(*Gradient)(nil).Equal(nil) || g.Equal(nil) || !g.Equal(LinearGradient(Pt(0, 0), Pt(1, 0), Stop(0, White), Stop(1, Transparent))) {
+		t.Error("Equal compares wrongly")
+	}
+}
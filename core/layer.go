@@ -0,0 +1,95 @@
+package core
+
+import "image"
+
+// Layer is an offscreen image widgets draw into once and then draw from
+// in many frames, such as a cached subtree or a thumbnail. It is made by
+// NewLayer and drawn with DrawLayer.
+type Layer interface {
+	// Size returns the size of the layer in logical pixels.
+	Size() Size
+
+	// Begin clears the layer and returns a canvas drawing into it, with
+	// the origin at its top left and clipped to its size, until End.
+	Begin() Canvas
+
+	// End finishes drawing into the layer.
+	End()
+
+	// Release frees the layer, which must not be used again.
+	Release()
+}
+
+// LayerCanvas is implemented by canvases that can render into offscreen
+// textures, usually of a GPU backend. Layers hold the same device pixels
+// as the canvas, at its scale factor.
+type LayerCanvas interface {
+	Canvas
+
+	// NewLayer returns an offscreen texture of size logical pixels.
+	NewLayer(size Size) Layer
+
+	// DrawLayer draws l, made by NewLayer of the same canvas, with its
+	// top left at pos.
+	DrawLayer(l Layer, pos Point)
+}
+
+// ReadableLayer is a Layer whose pixels can be read back, as the layers of
+// canvases used for captures must be.
+type ReadableLayer interface {
+	Layer
+
+	// ReadPixels returns the pixels of the layer at the scale factor of
+	// its canvas, which stay valid until it is drawn into again.
+	ReadPixels() (*image.RGBA, error)
+}
+
+// NewLayer returns a layer of size for drawing into cv: a texture if cv
+// is a LayerCanvas, or else a Recording replayed when it is drawn, which
+// saves repeating the drawing but not rasterizing it.
+func NewLayer(cv Canvas, size Size) Layer {
+	if lc, ok := cv.(LayerCanvas); ok {
+		return lc.NewLayer(size)
+	}
+	return &recordedLayer{size: size}
+}
+
+// DrawLayer draws l, made by NewLayer for cv, into cv with its top left at
+// pos.
+func DrawLayer(cv Canvas, l Layer, pos Point) {
+	if r, ok := l.(*recordedLayer); ok {
+		cv.Save()
+		cv.Translate(pos.X, pos.Y)
+		r.rec.Replay(cv)
+		cv.Restore()
+		return
+	}
+	if lc, ok := cv.(LayerCanvas); ok {
+		lc.DrawLayer(l, pos)
+	}
+}
+
+// recordedLayer is the Layer of canvases without offscreen textures.
+type recordedLayer struct {
+	size Size
+	rec  Recording
+}
+
+func (l *recordedLayer) Size() Size {
+	return l.size
+}
+
+func (l *recordedLayer) Begin() Canvas {
+	l.rec.Reset()
+	l.rec.Save()
+	l.rec.Clip(Rect{Width: l.size.Width, Height: l.size.Height})
+	return &l.rec
+}
+
+func (l *recordedLayer) End() {
+	l.rec.Restore()
+}
+
+func (l *recordedLayer) Release() {
+	l.rec.Reset()
+}
@@ -0,0 +1,220 @@
+package core
+
+import "slices"
+
+// Overlay is a layer drawn above the widget tree, such as a menu, popup or
+// dialog. Overlays are shown with Context.ShowOverlay and are stacked in
+// the order they were shown; the window runtime lays them out, paints them
+// after the main tree and routes input to the topmost overlay under the
+// pointer first.
+type Overlay struct {
+	// Content is the overlay's widget.
+	Content Widget
+
+	// Placement positions the content, whose measured size is given, within
+	// area, the rectangle available to overlays in window coordinates. A
+	// nil Placement centers the content.
+	Placement func(content Size, area Rect) Point
+
+	// LightDismiss closes the overlay when the user presses outside it or
+	// presses Escape, as popup menus do.
+	LightDismiss bool
+
+	// Modal overlays block input to everything beneath them and keep
+	// keyboard focus inside their content.
+	Modal bool
+
+	// Scrim, if not transparent, is painted over everything beneath the
+	// overlay, typically a translucent dark color behind a modal dialog.
+	Scrim Color
+
+	// ScrimFilter, if not zero, filters everything beneath the overlay
+	// before the scrim is painted, such as to blur it, on canvases that
+	// implement BackdropCanvas.
+	ScrimFilter Filter
+
+	// CloseOnEscape closes the overlay when Escape is pressed and nothing
+	// inside it handled the key. LightDismiss implies it.
+	CloseOnEscape bool
+
+	// Owner is the widget that opened the overlay. Presses on the owner do
+	// not light-dismiss the overlay, so the owner can toggle it itself.
+	Owner Widget
+
+	// Popup marks transient overlays such as menus that may be shown in
+	// their own native surface, and so extend past the window, when the
+	// platform integration supports it.
+	Popup bool
+
+	// Passive overlays, such as tooltips, never receive input: pointer
+	// events pass through to whatever lies beneath them. They are closed
+	// when the user presses a pointer button or a key.
+	Passive bool
+
+	// OnClose is called after the overlay is closed for any reason.
+	OnClose func()
+
+	open      bool
+	prevFocus Widget
+}
+
+// IsOpen reports whether the overlay is shown.
+func (o *Overlay) IsOpen() bool {
+	return o.open
+}
+
+// Bounds returns the overlay's rectangle in window coordinates, as
+// assigned by the last layout pass.
+func (o *Overlay) Bounds() Rect {
+	if o.Content == nil {
+		return Rect{}
+	}
+	return o.Content.Bounds()
+}
+
+// ShowOverlay shows o above every open overlay. Showing an open overlay
+// moves it to the top. Showing a modal overlay cancels pointer capture held
+// beneath it.
+func (c *Context) ShowOverlay(o *Overlay) {
+	if o.Modal {
+		c.captured = nil
+	}
+	if o.open {
+		c.overlays = slices.DeleteFunc(c.overlays, func(x *Overlay) bool { return x == o })
+	} else {
+		o.prevFocus = c.focused
+	}
+	o.open = true
+	c.overlays = append(c.overlays, o)
+	c.Invalidate()
+}
+
+// CloseOverlay closes o together with the overlays it opened, that is
+// those whose Owner lies inside the content of an overlay being closed, so
+// that closing a menu also closes its submenus. If focus was inside a
+// closed overlay, or nowhere while a modal overlay is closed, it returns
+// to the widget focused before that overlay was shown.
+func (c *Context) CloseOverlay(o *Overlay) {
+	i := slices.Index(c.overlays, o)
+	if i < 0 {
+		return
+	}
+	closed := []*Overlay{o}
+	kept := c.overlays[:i:i]
+	for _, x := range c.overlays[i+1:] {
+		if x.Owner != nil && slices.ContainsFunc(closed, func(y *Overlay) bool { return PathTo(y.Content, x.Owner) != nil }) {
+			closed = append(closed, x)
+		} else {
+			kept = append(kept, x)
+		}
+	}
+	c.overlays = kept
+	for j := len(closed) - 1; j >= 0; j-- {
+		x := closed[j]
+		x.open = false
+		if c.focused == nil && x.Modal || c.focused != nil && PathTo(x.Content, c.focused) != nil {
+			c.RequestFocus(x.prevFocus)
+		}
+		if c.captured != nil && PathTo(x.Content, c.captured) != nil {
+			c.captured = nil
+		}
+		if x.OnClose != nil {
+			x.OnClose()
+		}
+	}
+	c.Invalidate()
+}
+
+// Overlays returns the open overlays from bottom to top.
+func (c *Context) Overlays() []*Overlay {
+	return c.overlays
+}
+
+// Side selects where a popup is placed relative to its anchor.
+type Side uint8
+
+const (
+	// SideBelow places the popup under the anchor, left edges aligned.
+	SideBelow Side = iota
+	// SideAbove places the popup over the anchor, left edges aligned.
+	SideAbove
+	// SideRight places the popup to the right of the anchor, top edges
+	// aligned, as cascading submenus are.
+	SideRight
+	// SideLeft places the popup to the left of the anchor, top edges
+	// aligned.
+	SideLeft
+)
+
+// PlaceAnchored returns a Placement that puts the content on side of
+// anchor. If the content does not fit on that side it flips to the
+// opposite side when there is more room there, and it is then shifted to
+// stay inside the area.
+func PlaceAnchored(anchor Rect, side Side) func(Size, Rect) Point {
+	return func(s Size, area Rect) Point {
+		var p Point
+		switch side {
+		case SideBelow, SideAbove:
+			p.X = anchor.X
+			below := area.Bottom() - anchor.Bottom()
+			above := anchor.Y - area.Y
+			if side == SideBelow && s.Height > below && above > below ||
+				side == SideAbove && s.Height > above && below > above {
+				side = flip(side)
+			}
+			if side == SideBelow {
+				p.Y = anchor.Bottom()
+			} else {
+				p.Y = anchor.Y - s.Height
+			}
+		default:
+			p.Y = anchor.Y
+			right := area.Right() - anchor.Right()
+			left := anchor.X - area.X
+			if side == SideRight && s.Width > right && left > right ||
+				side == SideLeft && s.Width > left && right > left {
+				side = flip(side)
+			}
+			if side == SideRight {
+				p.X = anchor.Right()
+			} else {
+				p.X = anchor.X - s.Width
+			}
+		}
+		return clampInto(p, s, area)
+	}
+}
+
+// PlaceAt returns a Placement that puts the content's top-left corner at p,
+// shifting it left or up when it would leave the area, as context menus
+// opened at the pointer do.
+func PlaceAt(p Point) func(Size, Rect) Point {
+	return func(s Size, area Rect) Point {
+		if p.X+s.Width > area.Right() && p.X-s.Width >= area.X {
+			p.X -= s.Width
+		}
+		if p.Y+s.Height > area.Bottom() && p.Y-s.Height >= area.Y {
+			p.Y -= s.Height
+		}
+		return clampInto(p, s, area)
+	}
+}
+
+func flip(s Side) Side {
+	switch s {
+	case SideBelow:
+		return SideAbove
+	case SideAbove:
+		return SideBelow
+	case SideRight:
+		return SideLeft
+	default:
+		return SideRight
+	}
+}
+
+func clampInto(p Point, s Size, area Rect) Point {
+	p.X = max(area.X, min(p.X, area.Right()-s.Width))
+	p.Y = max(area.Y, min(p.Y, area.Bottom()-s.Height))
+	return p
+}
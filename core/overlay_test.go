@@ -0,0 +1,125 @@
+package core
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPlaceAnchored(t *testing.T) {
+	area := R(0, 0, 200, 200)
+	tests := []struct {
+		name   string
+		anchor Rect
+		side   Side
+		size   Size
+		want   Point
+	}{
+		{"below", R(10, 10, 50, 20), SideBelow, Sz(80, 50), Pt(10, 30)},
+		{"above", R(10, 100, 50, 20), SideAbove, Sz(80, 50), Pt(10, 50)},
+		{"below flips up", R(10, 160, 50, 20), SideBelow, Sz(80, 50), Pt(10, 110)},
+		{"above flips down", R(10, 20, 50, 20), SideAbove, Sz(80, 50), Pt(10, 40)},
+		{"below clamped when no side fits", R(10, 90, 50, 20), SideBelow, Sz(80, 150), Pt(10, 50)},
+		{"shifted left into the area", R(170, 10, 20, 20), SideBelow, Sz(80, 50), Pt(120, 30)},
+		{"right", R(10, 10, 50, 20), SideRight, Sz(80, 50), Pt(60, 10)},
+		{"right flips left", R(100, 10, 50, 20), SideRight, Sz(80, 50), Pt(20, 10)},
+		{"left", R(100, 10, 50, 20), SideLeft, Sz(80, 50), Pt(20, 10)},
+		{"left flips right", R(20, 10, 50, 20), SideLeft, Sz(80, 50), Pt(70, 10)},
+		{"right shifted up", R(10, 180, 50, 20), SideRight, Sz(80, 50), Pt(60, 150)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PlaceAnchored(tt.anchor, tt.side)(tt.size, area); got != tt.want {
+				t.Errorf("placed at %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlaceAt(t *testing.T) {
+	area := R(0, 0, 200, 200)
+	tests := []struct {
+		name string
+		at   Point
+		size Size
+		want Point
+	}{
+		{"fits", Pt(20, 30), Sz(50, 50), Pt(20, 30)},
+		{"flips left", Pt(180, 30), Sz(50, 50), Pt(130, 30)},
+		{"flips up", Pt(20, 180), Sz(50, 50), Pt(20, 130)},
+		{"clamped when it cannot flip", Pt(20, 30), Sz(50, 190), Pt(20, 10)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PlaceAt(tt.at)(tt.size, area); got != tt.want {
+				t.Errorf("placed at %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShowOverlay(t *testing.T) {
+	c := NewContext()
+	a := &Overlay{Content: newNode("a", Rect{})}
+	b := &Overlay{Content: newNode("b", Rect{})}
+	c.ShowOverlay(a)
+	c.ShowOverlay(b)
+	if !slices.Equal(c.Overlays(), []*Overlay{a, b}) {
+		t.Errorf("Overlays() = %v, want a, b", c.Overlays())
+	}
+	c.ShowOverlay(a)
+	if !slices.Equal(c.Overlays(), []*Overlay{b, a}) {
+		t.Errorf("Overlays() = %v after showing a again, want b, a", c.Overlays())
+	}
+	if !a.IsOpen() || !b.IsOpen() {
+		t.Error("IsOpen() = false for a shown overlay")
+	}
+	c.CloseOverlay(a)
+	c.CloseOverlay(a) // Closing twice is harmless.
+	if a.IsOpen() || !slices.Equal(c.Overlays(), []*Overlay{b}) {
+		t.Errorf("Overlays() = %v after closing a, want b", c.Overlays())
+	}
+}
+
+func TestCloseOverlayCascades(t *testing.T) {
+	c := NewContext()
+	outside := newNode("outside", Rect{})
+	item := newNode("item", Rect{})
+	menu := &Overlay{Content: newNode("menu", Rect{}, item)}
+	sub := &Overlay{Content: newNode("sub", Rect{}), Owner: item}
+	other := &Overlay{Content: newNode("other", Rect{}), Owner: outside}
+	var closed []string
+	for _, o := range []*Overlay{menu, sub, other} {
+		name := o.Content.(*node).name
+		o.OnClose = func() { closed = append(closed, name) }
+	}
+
+	c.RequestFocus(outside)
+	c.ShowOverlay(menu)
+	c.RequestFocus(item)
+	c.ShowOverlay(sub)
+	c.ShowOverlay(other)
+	c.CloseOverlay(menu)
+
+	if !slices.Equal(c.Overlays(), []*Overlay{other}) {
+		t.Errorf("Overlays() = %v, want only the overlay owned outside", c.Overlays())
+	}
+	if want := []string{"sub", "menu"}; !slices.Equal(closed, want) {
+		t.Errorf("closed %v, want %v", closed, want)
+	}
+	if c.Focused() != outside {
+		t.Errorf("Focused() = %v, want the widget focused before the menu", c.Focused())
+	}
+}
+
+func TestCloseModalRestoresFocus(t *testing.T) {
+	c := NewContext()
+	before := newNode("before", Rect{})
+	c.RequestFocus(before)
+	dialog := &Overlay{Content: newNode("dialog", Rect{}), Modal: true}
+	c.ShowOverlay(dialog)
+	c.RequestFocus(nil)
+	c.CloseOverlay(dialog)
+	if c.Focused() != before {
+		t.Errorf("Focused() = %v, want the widget focused before the dialog", c.Focused())
+	}
+}
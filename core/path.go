@@ -0,0 +1,253 @@
+package core
+
+import "math"
+
+// PathVerb identifies a path segment type.
+type PathVerb uint8
+
+// Path verbs.
+const (
+	MoveTo PathVerb = iota
+	LineTo
+	QuadTo
+	CubicTo
+	Close
+)
+
+// PathSegment is a single path command. Points holds 1 point for MoveTo and
+// LineTo, 2 for QuadTo (control, end), 3 for CubicTo (c1, c2, end) and none
+// for Close.
+type PathSegment struct {
+	Verb   PathVerb
+	Points [3]Point
+}
+
+// Path is a sequence of contours built from lines and Bézier curves.
+// The zero value is an empty path ready to use.
+type Path struct {
+	Segments []PathSegment
+}
+
+// NewPath returns an empty path.
+func NewPath() *Path {
+	return &Path{}
+}
+
+// MoveTo starts a new contour at p.
+func (p *Path) MoveTo(pt Point) *Path {
+	p.Segments = append(p.Segments, PathSegment{Verb: MoveTo, Points: [3]Point{pt}})
+	return p
+}
+
+// LineTo adds a straight line to pt.
+func (p *Path) LineTo(pt Point) *Path {
+	p.Segments = append(p.Segments, PathSegment{Verb: LineTo, Points: [3]Point{pt}})
+	return p
+}
+
+// QuadTo adds a quadratic Bézier curve with control point c ending at pt.
+func (p *Path) QuadTo(c, pt Point) *Path {
+	p.Segments = append(p.Segments, PathSegment{Verb: QuadTo, Points: [3]Point{c, pt}})
+	return p
+}
+
+// CubicTo adds a cubic Bézier curve with control points c1, c2 ending at pt.
+func (p *Path) CubicTo(c1, c2, pt Point) *Path {
+	p.Segments = append(p.Segments, PathSegment{Verb: CubicTo, Points: [3]Point{c1, c2, pt}})
+	return p
+}
+
+// Close closes the current contour.
+func (p *Path) Close() *Path {
+	p.Segments = append(p.Segments, PathSegment{Verb: Close})
+	return p
+}
+
+// IsEmpty reports whether the path has no segments.
+func (p *Path) IsEmpty() bool {
+	return p == nil || len(p.Segments) == 0
+}
+
+// kappa is the control point distance for approximating a quarter circle
+// with a cubic Bézier curve.
+const kappa = 0.5522847498
+
+// AddRect appends a closed rectangle contour.
+func (p *Path) AddRect(r Rect) *Path {
+	return p.MoveTo(Pt(r.X, r.Y)).
+		LineTo(Pt(r.Right(), r.Y)).
+		LineTo(Pt(r.Right(), r.Bottom())).
+		LineTo(Pt(r.X, r.Bottom())).
+		Close()
+}
+
+// AddRoundedRect appends a closed rectangle contour with corner radius
+// radius, clamped to half the shorter side.
+func (p *Path) AddRoundedRect(r Rect, radius float32) *Path {
+	radius = min(radius, r.Width/2, r.Height/2)
+	if radius <= 0 {
+		return p.AddRect(r)
+	}
+	k := radius * kappa
+	x0, y0, x1, y1 := r.X, r.Y, r.Right(), r.Bottom()
+	return p.MoveTo(Pt(x0+radius, y0)).
+		LineTo(Pt(x1-radius, y0)).
+		CubicTo(Pt(x1-radius+k, y0), Pt(x1, y0+radius-k), Pt(x1, y0+radius)).
+		LineTo(Pt(x1, y1-radius)).
+		CubicTo(Pt(x1, y1-radius+k), Pt(x1-radius+k, y1), Pt(x1-radius, y1)).
+		LineTo(Pt(x0+radius, y1)).
+		CubicTo(Pt(x0+radius-k, y1), Pt(x0, y1-radius+k), Pt(x0, y1-radius)).
+		LineTo(Pt(x0, y0+radius)).
+		CubicTo(Pt(x0, y0+radius-k), Pt(x0+radius-k, y0), Pt(x0+radius, y0)).
+		Close()
+}
+
+// AddEllipse appends a closed ellipse contour inscribed in r.
+func (p *Path) AddEllipse(r Rect) *Path {
+	c := r.Center()
+	rx, ry := r.Width/2, r.Height/2
+	kx, ky := rx*kappa, ry*kappa
+	return p.MoveTo(Pt(c.X+rx, c.Y)).
+		CubicTo(Pt(c.X+rx, c.Y+ky), Pt(c.X+kx, c.Y+ry), Pt(c.X, c.Y+ry)).
+		CubicTo(Pt(c.X-kx, c.Y+ry), Pt(c.X-rx, c.Y+ky), Pt(c.X-rx, c.Y)).
+		CubicTo(Pt(c.X-rx, c.Y-ky), Pt(c.X-kx, c.Y-ry), Pt(c.X, c.Y-ry)).
+		CubicTo(Pt(c.X+kx, c.Y-ry), Pt(c.X+rx, c.Y-ky), Pt(c.X+rx, c.Y)).
+		Close()
+}
+
+// AddArc appends an arc of the ellipse inscribed in r from angle start
+// sweeping by sweep radians, approximated with cubic curves. The arc begins
+// with a LineTo if the path already has a current point, otherwise MoveTo.
+func (p *Path) AddArc(r Rect, start, sweep float64) *Path {
+	c := r.Center()
+	rx, ry := float64(r.Width/2), float64(r.Height/2)
+	at := func(a float64) Point {
+		return Pt(c.X+float32(rx*math.Cos(a)), c.Y+float32(ry*math.Sin(a)))
+	}
+	if len(p.Segments) == 0 || p.Segments[len(p.Segments)-1].Verb == Close {
+		p.MoveTo(at(start))
+	} else {
+		p.LineTo(at(start))
+	}
+	n := int(math.Ceil(math.Abs(sweep) / (math.Pi / 2)))
+	if n == 0 {
+		return p
+	}
+	step := sweep / float64(n)
+	h := 4.0 / 3.0 * math.Tan(step/4)
+	for i := range n {
+		a0 := start + float64(i)*step
+		a1 := a0 + step
+		p0, p3 := at(a0), at(a1)
+		c1 := Pt(p0.X-float32(h*rx*math.Sin(a0)), p0.Y+float32(h*ry*math.Cos(a0)))
+		c2 := Pt(p3.X+float32(h*rx*math.Sin(a1)), p3.Y-float32(h*ry*math.Cos(a1)))
+		p.CubicTo(c1, c2, p3)
+	}
+	return p
+}
+
+// Bounds returns the bounding box of all path points, including control
+// points. It is a conservative estimate of the painted area.
+func (p *Path) Bounds() Rect {
+	if p.IsEmpty() {
+		return Rect{}
+	}
+	first := true
+	var lo, hi Point
+	for _, s := range p.Segments {
+		n := segmentPointCount(s.Verb)
+		for i := range n {
+			pt := s.Points[i]
+			if first {
+				lo, hi, first = pt, pt, false
+				continue
+			}
+			lo.X, lo.Y = min(lo.X, pt.X), min(lo.Y, pt.Y)
+			hi.X, hi.Y = max(hi.X, pt.X), max(hi.Y, pt.Y)
+		}
+	}
+	return RectFromPoints(lo, hi)
+}
+
+// Transform returns a copy of the path with every point mapped through fn.
+func (p *Path) Transform(fn func(Point) Point) *Path {
+	out := &Path{Segments: make([]PathSegment, len(p.Segments))}
+	for i, s := range p.Segments {
+		for j := range segmentPointCount(s.Verb) {
+			s.Points[j] = fn(s.Points[j])
+		}
+		out.Segments[i] = s
+	}
+	return out
+}
+
+func segmentPointCount(v PathVerb) int {
+	switch v {
+	case MoveTo, LineTo:
+		return 1
+	case QuadTo:
+		return 2
+	case CubicTo:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// containsSteps is the number of lines curves are flattened into to test
+// points against them.
+const containsSteps = 16
+
+// Contains reports whether pt lies inside the path by rule, with curves
+// approximated by lines and open contours closed.
+func (p *Path) Contains(pt Point, rule FillRule) bool {
+	winding := 0
+	var start, last Point
+	cross := func(a, b Point) {
+		if (a.Y <= pt.Y) == (b.Y <= pt.Y) {
+			return
+		}
+		if x := a.X + (pt.Y-a.Y)/(b.Y-a.Y)*(b.X-a.X); x > pt.X {
+			if b.Y > a.Y {
+				winding++
+			} else {
+				winding--
+			}
+		}
+	}
+	for _, s := range p.Segments {
+		switch s.Verb {
+		case MoveTo:
+			cross(last, start)
+			start, last = s.Points[0], s.Points[0]
+		case LineTo:
+			cross(last, s.Points[0])
+			last = s.Points[0]
+		case QuadTo, CubicTo:
+			from := last
+			for i := 1; i <= containsSteps; i++ {
+				t := float32(i) / containsSteps
+				u := 1 - t
+				var q Point
+				if s.Verb == QuadTo {
+					c, to := s.Points[0], s.Points[1]
+					q = Pt(u*u*from.X+2*u*t*c.X+t*t*to.X, u*u*from.Y+2*u*t*c.Y+t*t*to.Y)
+				} else {
+					c1, c2, to := s.Points[0], s.Points[1], s.Points[2]
+					q = Pt(u*u*u*from.X+3*u*u*t*c1.X+3*u*t*t*c2.X+t*t*t*to.X,
+						u*u*u*from.Y+3*u*u*t*c1.Y+3*u*t*t*c2.Y+t*t*t*to.Y)
+				}
+				cross(last, q)
+				last = q
+			}
+		case Close:
+			cross(last, start)
+			last = start
+		}
+	}
+	cross(last, start)
+	if rule == FillEvenOdd {
+		return winding%2 != 0
+	}
+	return winding != 0
+}
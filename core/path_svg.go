@@ -0,0 +1,296 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// ParseSVGPath parses SVG path data, the d attribute of a <path> element,
+// into a Path. Every command is supported; elliptical arcs become cubic
+// curves. On a syntax error it returns the path up to the error along with
+// it, as SVG renderers draw what precedes an error.
+func ParseSVGPath(d string) (*Path, error) {
+	p := &pathParser{s: d, path: NewPath()}
+	err := p.parse()
+	return p.path, err
+}
+
+type pathParser struct {
+	s    string
+	i    int
+	path *Path
+
+	cur, start Point
+	// ctrl is the last control point, reflected by the smooth curve
+	// commands; last is the previous command.
+	ctrl Point
+	last byte
+}
+
+func (p *pathParser) parse() error {
+	var cmd byte
+	for {
+		p.skipSpace()
+		if p.i >= len(p.s) {
+			return nil
+		}
+		c := p.s[p.i]
+		switch {
+		case isPathCommand(c):
+			if cmd == 0 && c|0x20 != 'm' {
+				return p.errorf("expected a moveto")
+			}
+			cmd = c
+			p.i++
+		case cmd == 0:
+			return p.errorf("expected a command")
+		case cmd == 'M':
+			cmd = 'L' // further pairs after a moveto are linetos
+		case cmd == 'm':
+			cmd = 'l'
+		case cmd == 'Z' || cmd == 'z':
+			return p.errorf("unexpected number after closepath")
+		}
+		if err := p.command(cmd); err != nil {
+			return err
+		}
+		p.last = cmd
+	}
+}
+
+func isPathCommand(c byte) bool {
+	switch c | 0x20 {
+	case 'm', 'l', 'h', 'v', 'c', 's', 'q', 't', 'a', 'z':
+		return true
+	}
+	return false
+}
+
+func (p *pathParser) command(cmd byte) error {
+	rel := cmd >= 'a'
+	at := func(x, y float32) Point {
+		if rel {
+			return Pt(p.cur.X+x, p.cur.Y+y)
+		}
+		return Pt(x, y)
+	}
+	n := map[byte]int{'m': 2, 'l': 2, 'h': 1, 'v': 1, 'c': 6, 's': 4, 'q': 4, 't': 2, 'a': 7, 'z': 0}[cmd|0x20]
+	var v [7]float32
+	for k := range n {
+		var err error
+		if cmd|0x20 == 'a' && (k == 3 || k == 4) {
+			v[k], err = p.flag()
+		} else {
+			v[k], err = p.number()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	switch cmd | 0x20 {
+	case 'm':
+		p.cur = at(v[0], v[1])
+		p.start = p.cur
+		p.path.MoveTo(p.cur)
+	case 'l':
+		p.lineTo(at(v[0], v[1]))
+	case 'h':
+		x := v[0]
+		if rel {
+			x += p.cur.X
+		}
+		p.lineTo(Pt(x, p.cur.Y))
+	case 'v':
+		y := v[0]
+		if rel {
+			y += p.cur.Y
+		}
+		p.lineTo(Pt(p.cur.X, y))
+	case 'c':
+		c1, c2, end := at(v[0], v[1]), at(v[2], v[3]), at(v[4], v[5])
+		p.path.CubicTo(c1, c2, end)
+		p.ctrl, p.cur = c2, end
+	case 's':
+		c1 := p.reflect("cs")
+		c2, end := at(v[0], v[1]), at(v[2], v[3])
+		p.path.CubicTo(c1, c2, end)
+		p.ctrl, p.cur = c2, end
+	case 'q':
+		c, end := at(v[0], v[1]), at(v[2], v[3])
+		p.path.QuadTo(c, end)
+		p.ctrl, p.cur = c, end
+	case 't':
+		c := p.reflect("qt")
+		end := at(v[0], v[1])
+		p.path.QuadTo(c, end)
+		p.ctrl, p.cur = c, end
+	case 'a':
+		end := at(v[5], v[6])
+		p.arcTo(v[0], v[1], v[2], v[3] != 0, v[4] != 0, end)
+		p.cur = end
+	case 'z':
+		p.path.Close()
+		p.cur = p.start
+	}
+	if c := cmd | 0x20; c != 'c' && c != 's' && c != 'q' && c != 't' {
+		p.ctrl = p.cur
+	}
+	return nil
+}
+
+// reflect returns the first control point of a smooth curve: the previous
+// control point mirrored through the current point if the previous
+// command was one of kinds, and the current point otherwise.
+func (p *pathParser) reflect(kinds string) Point {
+	l := p.last | 0x20
+	if l != kinds[0] && l != kinds[1] {
+		return p.cur
+	}
+	return Pt(2*p.cur.X-p.ctrl.X, 2*p.cur.Y-p.ctrl.Y)
+}
+
+func (p *pathParser) lineTo(pt Point) {
+	p.path.LineTo(pt)
+	p.cur = pt
+}
+
+// arcTo appends an SVG elliptical arc from the current point to end, as
+// cubic curves, following the SVG implementation notes on converting from
+// endpoint to center parameterization.
+func (p *pathParser) arcTo(rx, ry, angle float32, large, sweep bool, end Point) {
+	x1, y1 := float64(p.cur.X), float64(p.cur.Y)
+	x2, y2 := float64(end.X), float64(end.Y)
+	if x1 == x2 && y1 == y2 {
+		return
+	}
+	a, b := math.Abs(float64(rx)), math.Abs(float64(ry))
+	if a == 0 || b == 0 {
+		p.path.LineTo(end)
+		return
+	}
+	phi := float64(angle) * math.Pi / 180
+	cos, sin := math.Cos(phi), math.Sin(phi)
+	dx, dy := (x1-x2)/2, (y1-y2)/2
+	x1p, y1p := cos*dx+sin*dy, -sin*dx+cos*dy
+	// Radii too small to reach end are scaled up.
+	if l := x1p*x1p/(a*a) + y1p*y1p/(b*b); l > 1 {
+		a, b = a*math.Sqrt(l), b*math.Sqrt(l)
+	}
+	num := a*a*b*b - a*a*y1p*y1p - b*b*x1p*x1p
+	den := a*a*y1p*y1p + b*b*x1p*x1p
+	k := math.Sqrt(math.Max(0, num/den))
+	if large == sweep {
+		k = -k
+	}
+	cxp, cyp := k*a*y1p/b, -k*b*x1p/a
+	cx := cos*cxp - sin*cyp + (x1+x2)/2
+	cy := sin*cxp + cos*cyp + (y1+y2)/2
+	angleOf := func(ux, uy float64) float64 { return math.Atan2(uy, ux) }
+	t1 := angleOf((x1p-cxp)/a, (y1p-cyp)/b)
+	dt := angleOf((-x1p-cxp)/a, (-y1p-cyp)/b) - t1
+	switch {
+	case sweep && dt < 0:
+		dt += 2 * math.Pi
+	case !sweep && dt > 0:
+		dt -= 2 * math.Pi
+	}
+	n := int(math.Ceil(math.Abs(dt) / (math.Pi / 2)))
+	step := dt / float64(n)
+	h := 4.0 / 3.0 * math.Tan(step/4)
+	point := func(t float64) (x, y, tx, ty float64) {
+		ex, ey := a*math.Cos(t), b*math.Sin(t)
+		dx, dy := -a*math.Sin(t), b*math.Cos(t)
+		return cx + cos*ex - sin*ey, cy + sin*ex + cos*ey, cos*dx - sin*dy, sin*dx + cos*dy
+	}
+	for i := range n {
+		ta, tb := t1+float64(i)*step, t1+float64(i+1)*step
+		ax, ay, adx, ady := point(ta)
+		bx, by, bdx, bdy := point(tb)
+		pb := Pt(float32(bx), float32(by))
+		if i == n-1 {
+			pb = end
+		}
+		p.path.CubicTo(
+			Pt(float32(ax+h*adx), float32(ay+h*ady)),
+			Pt(float32(bx-h*bdx), float32(by-h*bdy)),
+			pb)
+	}
+}
+
+func (p *pathParser) skipSpace() {
+	for p.i < len(p.s) {
+		switch p.s[p.i] {
+		case ' ', '\t', '\n', '\r', '\f':
+			p.i++
+		default:
+			return
+		}
+	}
+}
+
+// skipSeparator skips whitespace and at most one comma.
+func (p *pathParser) skipSeparator() {
+	p.skipSpace()
+	if p.i < len(p.s) && p.s[p.i] == ',' {
+		p.i++
+		p.skipSpace()
+	}
+}
+
+// number reads a coordinate. Numbers need no separator where the syntax
+// allows none, as in "1-2" or "0.5.5".
+func (p *pathParser) number() (float32, error) {
+	p.skipSeparator()
+	start := p.i
+	if p.i < len(p.s) && (p.s[p.i] == '+' || p.s[p.i] == '-') {
+		p.i++
+	}
+	digits, dot := 0, false
+	for p.i < len(p.s) {
+		c := p.s[p.i]
+		if c >= '0' && c <= '9' {
+			digits++
+		} else if c == '.' && !dot {
+			dot = true
+		} else {
+			break
+		}
+		p.i++
+	}
+	if digits == 0 {
+		p.i = start
+		return 0, p.errorf("expected a number")
+	}
+	if p.i < len(p.s) && (p.s[p.i] == 'e' || p.s[p.i] == 'E') {
+		j := p.i + 1
+		if j < len(p.s) && (p.s[j] == '+' || p.s[j] == '-') {
+			j++
+		}
+		if j < len(p.s) && p.s[j] >= '0' && p.s[j] <= '9' {
+			for j < len(p.s) && p.s[j] >= '0' && p.s[j] <= '9' {
+				j++
+			}
+			p.i = j
+		}
+	}
+	v, err := strconv.ParseFloat(p.s[start:p.i], 32)
+	if err != nil {
+		return 0, p.errorf("bad number %q", p.s[start:p.i])
+	}
+	return float32(v), nil
+}
+
+// flag reads an arc flag, a single 0 or 1 that needs no separator.
+func (p *pathParser) flag() (float32, error) {
+	p.skipSeparator()
+	if p.i < len(p.s) && (p.s[p.i] == '0' || p.s[p.i] == '1') {
+		p.i++
+		return float32(p.s[p.i-1] - '0'), nil
+	}
+	return 0, p.errorf("expected an arc flag")
+}
+
+func (p *pathParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("core: path data at offset %d: %s", p.i, fmt.Sprintf(format, args...))
+}
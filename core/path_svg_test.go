@@ -0,0 +1,156 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+// pathString writes p in absolute SVG syntax, for comparing paths.
+func pathString(p *Path) string {
+	var b strings.Builder
+	n := map[PathVerb]int{MoveTo: 1, LineTo: 1, QuadTo: 2, CubicTo: 3}
+	for i, s := range p.Segments {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteByte("MLQCZ"[s.Verb])
+		for k, pt := range s.Points[:n[s.Verb]] {
+			if k > 0 {
+				b.WriteByte(' ')
+			}
+			fmt.Fprintf(&b, "%v %v", pt.X, pt.Y)
+		}
+	}
+	return b.String()
+}
+
+func TestParseSVGPath(t *testing.T) {
+	tests := []struct {
+		name, d, want string
+	}{
+		{"lines", "M10 20 L30 40", "M10 20 L30 40"},
+		{"relative", "m10 20 l5 5 h10 v-5 z", "M10 20 L15 25 L25 25 L25 20 Z"},
+		{"absolute h and v", "M1 1 H5 V7", "M1 1 L5 1 L5 7"},
+		{"implicit lineto", "M0 0 10 0 10 10", "M0 0 L10 0 L10 10"},
+		{"implicit relative lineto", "m1 1 2 2", "M1 1 L3 3"},
+		{"restart after close", "M5 5 L10 5 Z l1 1", "M5 5 L10 5 Z L6 6"},
+		{"no separators", "M1-2L.5.5", "M1 -2 L0.5 0.5"},
+		{"commas and exponents", "M1e1,2E-1 L+3,4", "M10 0.2 L3 4"},
+		{"cubic", "M0 0 C0 10 10 10 10 0", "M0 0 C0 10 10 10 10 0"},
+		{"smooth cubic", "M0 0 C0 10 10 10 10 0 S20 -10 20 0", "M0 0 C0 10 10 10 10 0 C10 -10 20 -10 20 0"},
+		{"smooth cubic alone", "M0 0 S10 10 20 0", "M0 0 C0 0 10 10 20 0"},
+		{"relative smooth cubic", "M0 0 c0 10 10 10 10 0 s10 -10 10 0", "M0 0 C0 10 10 10 10 0 C10 -10 20 -10 20 0"},
+		{"quad", "M0 0 Q5 10 10 0 T20 0", "M0 0 Q5 10 10 0 Q15 -10 20 0"},
+		{"smooth quad after a line", "M0 0 L10 0 T20 0", "M0 0 L10 0 Q10 0 20 0"},
+		{"smooth quad after a cubic", "M0 0 C0 10 10 10 10 0 t10 0", "M0 0 C0 10 10 10 10 0 Q10 0 20 0"},
+		{"arc of zero radius", "M0 0 A0 5 0 0 1 10 0", "M0 0 L10 0"},
+		{"arc to itself", "M0 0 A5 5 0 0 1 0 0", "M0 0"},
+		{"empty", "  ", ""},
+	}
+	for _, tt := range tests {
+		p, err := ParseSVGPath(tt.d)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+		}
+		if got := pathString(p); got != tt.want {
+			t.Errorf("%s: ParseSVGPath(%q) = %q, want %q", tt.name, tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestParseSVGPathErrors(t *testing.T) {
+	tests := []struct {
+		d, path, err string
+	}{
+		{"L0 0", "", "offset 0: expected a moveto"},
+		{"1 2", "", "offset 0: expected a command"},
+		{"M0", "", "offset 2: expected a number"},
+		{"M0 0 L1 x", "M0 0", "offset 8: expected a number"},
+		{"M0 0 Z 1", "M0 0 Z", "offset 7: unexpected number after closepath"},
+		{"M0 0 A5 5 0 2 0 1 1", "M0 0", "offset 12: expected an arc flag"},
+		{"M0 0 L1 2 #", "M0 0 L1 2", "offset 10: expected a number"},
+	}
+	for _, tt := range tests {
+		p, err := ParseSVGPath(tt.d)
+		if err == nil || !strings.HasSuffix(err.Error(), tt.err) {
+			t.Errorf("ParseSVGPath(%q) error = %v, want %q", tt.d, err, tt.err)
+		}
+		if got := pathString(p); got != tt.path {
+			t.Errorf("ParseSVGPath(%q) kept %q, want %q", tt.d, got, tt.path)
+		}
+	}
+}
+
+func TestParseSVGPathArcs(t *testing.T) {
+	tests := []struct {
+		name   string
+		d      string
+		center Point
+		rx, ry float32
+		bounds Rect
+		curves int
+	}{
+		{"upper half", "M0 0 A5 5 0 0 1 10 0", Pt(5, 0), 5, 5, R(0, -5, 10, 5), 2},
+		{"lower half", "M0 0 A5 5 0 0 0 10 0", Pt(5, 0), 5, 5, R(0, 0, 10, 5), 2},
+		{"radii scaled up", "M0 0 A1 1 0 0 1 10 0", Pt(5, 0), 5, 5, R(0, -5, 10, 5), 2},
+		{"flags without separators", "M0 0a5 5 0 0110 0", Pt(5, 0), 5, 5, R(0, -5, 10, 5), 2},
+		{"quarter", "M0 0 A10 10 0 0 0 10 10", Pt(10, 0), 10, 10, R(0, 0, 10, 10), 1},
+		{"large three quarters", "M0 0 A10 10 0 1 1 10 10", Pt(10, 0), 10, 10, R(0, -10, 20, 20), 3},
+		{"ellipse", "M0 0 A10 5 0 0 1 20 0", Pt(10, 0), 10, 5, R(0, -5, 20, 5), 2},
+		{"rotated", "M0 0 A10 5 90 0 1 0 20", Pt(0, 10), 5, 10, R(0, 0, 5, 20), 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := ParseSVGPath(tt.d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(p.Segments) != tt.curves+1 {
+				t.Fatalf("%d segments, want %d curves", len(p.Segments)-1, tt.curves)
+			}
+			// The curves stay on the ellipse, through its ends and middles.
+			from := p.Segments[0].Points[0]
+			for _, s := range p.Segments[1:] {
+				if s.Verb != CubicTo {
+					t.Fatalf("a %v in an arc", s.Verb)
+				}
+				for _, u := range []float32{0.25, 0.5, 0.75, 1} {
+					q := cubicAt(from, s.Points, u)
+					dx, dy := (q.X-tt.center.X)/tt.rx, (q.Y-tt.center.Y)/tt.ry
+					if d := math.Hypot(float64(dx), float64(dy)); math.Abs(d-1) > 1e-3 {
+						t.Errorf("point %v is %v radii from the center", q, d)
+					}
+				}
+				from = s.Points[2]
+			}
+			if got := curveHull(p); !near(got, tt.bounds) {
+				t.Errorf("the arc spans %v, want %v", got, tt.bounds)
+			}
+		})
+	}
+}
+
+// cubicAt returns the point at u of the cubic from p0 through pts.
+func cubicAt(p0 Point, pts [3]Point, u float32) Point {
+	v := 1 - u
+	a, b, c, d := v*v*v, 3*v*v*u, 3*v*u*u, u*u*u
+	return Pt(a*p0.X+b*pts[0].X+c*pts[1].X+d*pts[2].X, a*p0.Y+b*pts[0].Y+c*pts[1].Y+d*pts[2].Y)
+}
+
+// curveHull returns the bounds of the points the curves of p pass through,
+// sampled finely, leaving out their control points.
+func curveHull(p *Path) Rect {
+	from := p.Segments[0].Points[0]
+	lo, hi := from, from
+	for _, s := range p.Segments[1:] {
+		for k := 1; k <= 64; k++ {
+			q := cubicAt(from, s.Points, float32(k)/64)
+			lo = Pt(min(lo.X, q.X), min(lo.Y, q.Y))
+			hi = Pt(max(hi.X, q.X), max(hi.Y, q.Y))
+		}
+		from = s.Points[2]
+	}
+	return R(lo.X, lo.Y, hi.X-lo.X, hi.Y-lo.Y)
+}
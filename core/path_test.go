@@ -0,0 +1,129 @@
+package core
+
+import "testing"
+
+func TestPathBuilders(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   *Path
+		verbs  []PathVerb
+		bounds Rect
+	}{
+		{
+			name:   "lines",
+			path:   NewPath().MoveTo(Pt(1, 2)).LineTo(Pt(5, 2)).LineTo(Pt(5, 8)).Close(),
+			verbs:  []PathVerb{MoveTo, LineTo, LineTo, Close},
+			bounds: R(1, 2, 4, 6),
+		},
+		{
+			name:   "curves include their control points",
+			path:   NewPath().MoveTo(Pt(0, 0)).QuadTo(Pt(5, 10), Pt(10, 0)).CubicTo(Pt(12, -4), Pt(14, 2), Pt(16, 0)),
+			verbs:  []PathVerb{MoveTo, QuadTo, CubicTo},
+			bounds: R(0, -4, 16, 14),
+		},
+		{
+			name:   "rect",
+			path:   NewPath().AddRect(R(2, 3, 10, 20)),
+			verbs:  []PathVerb{MoveTo, LineTo, LineTo, LineTo, Close},
+			bounds: R(2, 3, 10, 20),
+		},
+		{
+			name:   "ellipse",
+			path:   NewPath().AddEllipse(R(0, 0, 20, 10)),
+			verbs:  []PathVerb{MoveTo, CubicTo, CubicTo, CubicTo, CubicTo, Close},
+			bounds: R(0, 0, 20, 10),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if len(tt.path.Segments) != len(tt.verbs) {
+				t.Fatalf("%d segments, want %d", len(tt.path.Segments), len(tt.verbs))
+			}
+			for i, s := range tt.path.Segments {
+				if s.Verb != tt.verbs[i] {
+					t.Errorf("segment %d verb = %v, want %v", i, s.Verb, tt.verbs[i])
+				}
+			}
+			if got := tt.path.Bounds(); !near(got, tt.bounds) {
+				t.Errorf("Bounds = %v, want %v", got, tt.bounds)
+			}
+		})
+	}
+}
+
+func TestPathRoundedRect(t *testing.T) {
+	p := NewPath().AddRoundedRect(R(0, 0, 40, 20), 5)
+	if got := p.Bounds(); !near(got, R(0, 0, 40, 20)) {
+		t.Errorf("Bounds = %v", got)
+	}
+	curves := 0
+	for _, s := range p.Segments {
+		if s.Verb == CubicTo {
+			curves++
+		}
+	}
+	if curves != 4 {
+		t.Errorf("%d corner curves, want 4", curves)
+	}
+}
+
+func TestPathEmptyTransform(t *testing.T) {
+	var nilPath *Path
+	if !nilPath.IsEmpty() || !NewPath().IsEmpty() || NewPath().MoveTo(Pt(0, 0)).IsEmpty() {
+		t.Error("IsEmpty wrong")
+	}
+	if got := NewPath().Bounds(); got != (Rect{}) {
+		t.Errorf("empty Bounds = %v", got)
+	}
+	p := NewPath().MoveTo(Pt(1, 1)).LineTo(Pt(2, 3))
+	moved := p.Transform(func(q Point) Point { return q.Add(Pt(10, 0)) })
+	if got := moved.Bounds(); got != R(11, 1, 1, 2) {
+		t.Errorf("transformed Bounds = %v", got)
+	}
+	if got := p.Bounds(); got != R(1, 1, 1, 2) {
+		t.Errorf("Transform changed the path: %v", got)
+	}
+}
+
+// near reports whether a and b are equal to within rounding.
+func near(a, b Rect) bool {
+	d := func(x, y float32) bool { return x-y < 1e-3 && y-x < 1e-3 }
+	return d(a.X, b.X) && d(a.Y, b.Y) && d(a.Width, b.Width) && d(a.Height, b.Height)
+}
+
+func TestPathContains(t *testing.T) {
+	tri := NewPath().MoveTo(Pt(0, 0)).LineTo(Pt(10, 0)).LineTo(Pt(0, 10)).Close()
+	open := NewPath().MoveTo(Pt(0, 0)).LineTo(Pt(10, 0)).LineTo(Pt(0, 10))
+	// Two squares wound the same way, one inside the other, and a third
+	// wound the other way.
+	nested := NewPath().AddRect(R(0, 0, 30, 30)).AddRect(R(10, 10, 10, 10))
+	hole := NewPath().AddRect(R(0, 0, 30, 30)).
+		MoveTo(Pt(10, 10)).LineTo(Pt(10, 20)).LineTo(Pt(20, 20)).LineTo(Pt(20, 10)).Close()
+	round := NewPath().AddRoundedRect(R(0, 0, 40, 40), 10)
+	tests := []struct {
+		name string
+		p    *Path
+		pt   Point
+		rule FillRule
+		want bool
+	}{
+		{"triangle", tri, Pt(2, 2), FillNonZero, true},
+		{"past the diagonal", tri, Pt(6, 6), FillNonZero, false},
+		{"open contour", open, Pt(2, 2), FillNonZero, true},
+		{"nested, nonzero", nested, Pt(15, 15), FillNonZero, true},
+		{"nested, even-odd", nested, Pt(15, 15), FillEvenOdd, false},
+		{"in the outer square", nested, Pt(5, 15), FillEvenOdd, true},
+		{"hole, nonzero", hole, Pt(15, 15), FillNonZero, false},
+		{"rounded, inside", round, Pt(20, 2), FillNonZero, true},
+		{"rounded, off the corner", round, Pt(1, 1), FillNonZero, false},
+		{"rounded, in the corner", round, Pt(4, 4), FillNonZero, true},
+		{"ellipse", NewPath().AddEllipse(R(0, 0, 40, 20)), Pt(38, 10), FillNonZero, true},
+		{"off the ellipse", NewPath().AddEllipse(R(0, 0, 40, 20)), Pt(38, 2), FillNonZero, false},
+		{"empty", NewPath(), Pt(0, 0), FillNonZero, false},
+	}
+	for _, tt := range tests {
+		if got := tt.p.Contains(tt.pt, tt.rule); got != tt.want {
+			t.Errorf("%s: Contains(%v) = %v, want %v", tt.name, tt.pt, got, tt.want)
+		}
+	}
+}
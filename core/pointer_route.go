@@ -0,0 +1,29 @@
+package core
+
+// AddPointerRoute calls fn with every pointer event the window receives,
+// after it has been dispatched into the tree, until fn returns false.
+// Routes see the events of a pointer whatever widget it is over, so that
+// gesture recognizers keep following a pointer pressed on them. It must
+// be called on the UI goroutine.
+func (c *Context) AddPointerRoute(fn func(ev PointerEvent) bool) {
+	c.routes = append(c.routes, fn)
+}
+
+// RoutePointer calls the routes added with AddPointerRoute with ev, and
+// removes those that return false. It is called by the window runtime for
+// every pointer event.
+func (c *Context) RoutePointer(ev PointerEvent) {
+	if len(c.routes) == 0 {
+		return
+	}
+	run := c.routes
+	c.routes = nil
+	keep := run[:0]
+	for _, fn := range run {
+		if fn(ev) {
+			keep = append(keep, fn)
+		}
+	}
+	// Routes added while running come after those kept.
+	c.routes = append(keep, c.routes...)
+}
@@ -0,0 +1,241 @@
+package core
+
+import (
+	"image"
+	"reflect"
+	"slices"
+)
+
+// Recording is a Canvas that records what is drawn into it, to be
+// replayed into other canvases, such as a display list kept between
+// frames. Two recordings of the same drawing are Equal, so a widget can
+// tell whether what it would draw changed.
+type Recording struct {
+	ops []drawOp
+}
+
+type drawKind uint8
+
+const (
+	opRect drawKind = iota
+	opRoundedRect
+	opText
+	opImage
+	opPath
+	opSave
+	opRestore
+	opTranslate
+	opClip
+	opClipPath
+)
+
+// drawOp is a recorded command. Only the fields of its kind are set.
+type drawOp struct {
+	kind      drawKind
+	rect      Rect
+	radius    float32
+	rectStyle RectStyle
+	text      string
+	pos       Point
+	style     TextStyle
+	img       image.Image
+	path      []PathSegment
+	pathStyle PathStyle
+}
+
+// Reset removes what was recorded.
+func (r *Recording) Reset() {
+	clear(r.ops)
+	r.ops = r.ops[:0]
+}
+
+// Len returns the number of commands recorded.
+func (r *Recording) Len() int {
+	return len(r.ops)
+}
+
+// Replay draws what was recorded into cv.
+func (r *Recording) Replay(cv Canvas) {
+	for i := range r.ops {
+		op := &r.ops[i]
+		switch op.kind {
+		case opRect:
+			cv.DrawRect(op.rect, op.rectStyle)
+		case opRoundedRect:
+			cv.DrawRoundedRect(op.rect, op.radius, op.rectStyle)
+		case opText:
+			cv.DrawText(op.text, op.pos, op.style)
+		case opImage:
+			cv.DrawImage(op.img, op.rect)
+		case opPath:
+			cv.DrawPath(&Path{Segments: op.path}, op.pathStyle)
+		case opSave:
+			cv.Save()
+		case opRestore:
+			cv.Restore()
+		case opTranslate:
+			cv.Translate(op.pos.X, op.pos.Y)
+		case opClip:
+			cv.Clip(op.rect)
+		case opClipPath:
+			ClipPath(cv, &Path{Segments: op.path}, op.pathStyle.FillRule)
+		}
+	}
+}
+
+// Equal reports whether r and o recorded the same commands. Images are
+// the same if they are the same value, such as the same *image.RGBA, and
+// gradients if they are Equal.
+func (r *Recording) Equal(o *Recording) bool {
+	return slices.EqualFunc(r.ops, o.ops, sameOp)
+}
+
+// EqualMoved reports whether r recorded the commands of o moved by d, as
+// a widget records once laid out d further, so that what it draws can be
+// kept from its top left as it moves. A translation moved by d carries
+// the commands it applies to, which are then the same.
+func (r *Recording) EqualMoved(o *Recording, d Point) bool {
+	if len(r.ops) != len(o.ops) {
+		return false
+	}
+	var saved []Point
+	for i := range r.ops {
+		a, b := &r.ops[i], &o.ops[i]
+		if a.kind != b.kind {
+			return false
+		}
+		switch a.kind {
+		case opSave:
+			saved = append(saved, d)
+			continue
+		case opRestore:
+			if n := len(saved); n > 0 {
+				d, saved = saved[n-1], saved[:n-1]
+			}
+			continue
+		case opTranslate:
+			switch a.pos {
+			case b.pos:
+			case b.pos.Add(d):
+				d = Point{}
+			default:
+				return false
+			}
+			continue
+		}
+		if !sameOp(*a, b.moved(d)) {
+			return false
+		}
+	}
+	return true
+}
+
+func sameOp(a, b drawOp) bool {
+	return a.kind == b.kind && a.rect == b.rect && a.radius == b.radius && sameRectStyle(a.rectStyle, b.rectStyle) &&
+		a.text == b.text && a.pos == b.pos && a.style == b.style && samePathStyle(a.pathStyle, b.pathStyle) &&
+		sameImage(a.img, b.img) && slices.Equal(a.path, b.path)
+}
+
+// moved returns op drawn d further.
+func (op drawOp) moved(d Point) drawOp {
+	if d == (Point{}) {
+		return op
+	}
+	switch op.kind {
+	case opRect, opRoundedRect, opImage, opClip:
+		op.rect = op.rect.Translate(d)
+	case opText:
+		op.pos = op.pos.Add(d)
+	case opPath, opClipPath:
+		op.path = (&Path{Segments: op.path}).Transform(func(p Point) Point { return p.Add(d) }).Segments
+	}
+	op.rectStyle.FillGradient = movedGradient(op.rectStyle.FillGradient, d)
+	op.rectStyle.StrokeGradient = movedGradient(op.rectStyle.StrokeGradient, d)
+	op.pathStyle.FillGradient = movedGradient(op.pathStyle.FillGradient, d)
+	op.pathStyle.StrokeGradient = movedGradient(op.pathStyle.StrokeGradient, d)
+	return op
+}
+
+// movedGradient returns g moved by d, unless it is relative to the shape.
+func movedGradient(g *Gradient, d Point) *Gradient {
+	if g == nil || g.Relative {
+		return g
+	}
+	m := *g
+	m.Start, m.End = m.Start.Add(d), m.End.Add(d)
+	return &m
+}
+
+func sameRectStyle(a, b RectStyle) bool {
+	return a.Fill == b.Fill && a.Stroke == b.Stroke && a.StrokeWidth == b.StrokeWidth &&
+		a.FillGradient.Equal(b.FillGradient) && a.StrokeGradient.Equal(b.StrokeGradient)
+}
+
+func samePathStyle(a, b PathStyle) bool {
+	ga, gb := a.FillGradient, b.FillGradient
+	sa, sb := a.StrokeGradient, b.StrokeGradient
+	a.FillGradient, a.StrokeGradient, b.FillGradient, b.StrokeGradient = nil, nil, nil, nil
+	return a == b && ga.Equal(gb) && sa.Equal(sb)
+}
+
+func sameImage(a, b image.Image) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return reflect.TypeOf(a) == reflect.TypeOf(b) && reflect.TypeOf(a).Comparable() && a == b
+}
+
+// DrawRect implements Canvas.
+func (r *Recording) DrawRect(rect Rect, st RectStyle) {
+	r.ops = append(r.ops, drawOp{kind: opRect, rect: rect, rectStyle: st})
+}
+
+// DrawRoundedRect implements Canvas.
+func (r *Recording) DrawRoundedRect(rect Rect, radius float32, st RectStyle) {
+	r.ops = append(r.ops, drawOp{kind: opRoundedRect, rect: rect, radius: radius, rectStyle: st})
+}
+
+// DrawText implements Canvas.
+func (r *Recording) DrawText(text string, pos Point, st TextStyle) {
+	r.ops = append(r.ops, drawOp{kind: opText, text: text, pos: pos, style: st})
+}
+
+// DrawImage implements Canvas.
+func (r *Recording) DrawImage(img image.Image, rect Rect) {
+	r.ops = append(r.ops, drawOp{kind: opImage, img: img, rect: rect})
+}
+
+// DrawPath implements Canvas. The path is copied, as the caller may
+// reuse it.
+func (r *Recording) DrawPath(p *Path, st PathStyle) {
+	if p.IsEmpty() {
+		return
+	}
+	r.ops = append(r.ops, drawOp{kind: opPath, path: slices.Clone(p.Segments), pathStyle: st})
+}
+
+// Save implements Canvas.
+func (r *Recording) Save() {
+	r.ops = append(r.ops, drawOp{kind: opSave})
+}
+
+// Restore implements Canvas.
+func (r *Recording) Restore() {
+	r.ops = append(r.ops, drawOp{kind: opRestore})
+}
+
+// Translate implements Canvas.
+func (r *Recording) Translate(x, y float32) {
+	r.ops = append(r.ops, drawOp{kind: opTranslate, pos: Pt(x, y)})
+}
+
+// Clip implements Canvas.
+func (r *Recording) Clip(rect Rect) {
+	r.ops = append(r.ops, drawOp{kind: opClip, rect: rect})
+}
+
+// ClipPath implements PathClipCanvas. The path is copied, as the caller
+// may reuse it.
+func (r *Recording) ClipPath(p *Path, rule FillRule) {
+	r.ops = append(r.ops, drawOp{kind: opClipPath, path: slices.Clone(p.Segments), pathStyle: PathStyle{FillRule: rule}})
+}
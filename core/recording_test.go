@@ -0,0 +1,159 @@
+package core
+
+import (
+	"image"
+	"testing"
+)
+
+// record draws one command of each kind into cv.
+func record(cv Canvas, img image.Image, p *Path) {
+	cv.Save()
+	cv.Translate(5, 6)
+	cv.Clip(R(0, 0, 50, 50))
+	ClipPath(cv, p, FillEvenOdd)
+	cv.DrawRect(R(1, 2, 3, 4), Filled(RGB(255, 0, 0)))
+	cv.DrawRoundedRect(R(1, 2, 3, 4), 2, RectStyle{Stroke: RGB(0, 0, 255), StrokeWidth: 1})
+	cv.DrawText("hi", Pt(7, 8), TextStyle{Size: 12})
+	cv.DrawImage(img, R(0, 0, 2, 2))
+	cv.DrawPath(p, PathStyle{Fill: RGB(0, 255, 0)})
+	cv.Restore()
+}
+
+// plainCanvas hides the optional capabilities of its canvas.
+type plainCanvas struct{ Canvas }
+
+func TestRecordingReplay(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	p := NewPath().MoveTo(Pt(0, 0)).LineTo(Pt(10, 0)).LineTo(Pt(0, 10)).Close()
+	var a, b Recording
+	record(&a, img, p)
+	if a.Len() != 10 {
+		t.Fatalf("Len() = %d, want 10", a.Len())
+	}
+	a.Replay(&b)
+	if !a.Equal(&b) {
+		t.Error("the replay differs from the recording")
+	}
+
+	// Paths are copied, and empty ones are not recorded.
+	p.LineTo(Pt(20, 20))
+	var c Recording
+	record(&c, img, NewPath().MoveTo(Pt(0, 0)).LineTo(Pt(10, 0)).LineTo(Pt(0, 10)).Close())
+	if !a.Equal(&c) {
+		t.Error("changing a path changed the recording")
+	}
+	a.DrawPath(NewPath(), PathStyle{Fill: RGB(0, 255, 0)})
+	if a.Len() != 10 {
+		t.Errorf("an empty path was recorded")
+	}
+
+	// Canvases without path clips clip to the bounds of the path.
+	var d, want Recording
+	a.Replay(plainCanvas{&d})
+	record(plainCanvas{&want}, img, NewPath().MoveTo(Pt(0, 0)).LineTo(Pt(10, 0)).LineTo(Pt(0, 10)).Close())
+	if !d.Equal(&want) || d.ops[3].kind != opClip || d.ops[3].rect != R(0, 0, 10, 10) {
+		t.Errorf("replayed into a plain canvas, the path clip is %+v", d.ops[3])
+	}
+
+	a.Reset()
+	if a.Len() != 0 || !a.Equal(&Recording{}) {
+		t.Errorf("Len() = %d after Reset", a.Len())
+	}
+}
+
+func TestRecordingEqual(t *testing.T) {
+	grad := func(c Color) *Gradient { return LinearGradient(Pt(0, 0), Pt(10, 0), Stop(0, c), Stop(1, RGB(0, 0, 0))) }
+	rect := func(st RectStyle) func(Canvas) {
+		return func(cv Canvas) { cv.DrawRect(R(0, 0, 1, 1), st) }
+	}
+	line := func(st PathStyle) func(Canvas) {
+		return func(cv Canvas) { cv.DrawPath(NewPath().MoveTo(Pt(0, 0)).LineTo(Pt(1, 1)), st) }
+	}
+	picture := func(img image.Image) func(Canvas) {
+		return func(cv Canvas) { cv.DrawImage(img, R(0, 0, 2, 2)) }
+	}
+	text := func(s string) func(Canvas) {
+		return func(cv Canvas) { cv.DrawText(s, Pt(0, 0), TextStyle{}) }
+	}
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	tests := []struct {
+		name string
+		a, b func(cv Canvas)
+		want bool
+	}{
+		{"same rect", rect(Filled(RGB(1, 2, 3))), rect(Filled(RGB(1, 2, 3))), true},
+		{"other color", rect(Filled(RGB(1, 2, 3))), rect(Filled(RGB(3, 2, 1))), false},
+		{"other kind", rect(Filled(RGB(1, 2, 3))), func(cv Canvas) { cv.DrawRoundedRect(R(0, 0, 1, 1), 0, Filled(RGB(1, 2, 3))) }, false},
+		{"equal gradients", rect(RectStyle{FillGradient: grad(RGB(9, 9, 9))}), rect(RectStyle{FillGradient: grad(RGB(9, 9, 9))}), true},
+		{"other gradients", rect(RectStyle{FillGradient: grad(RGB(9, 9, 9))}), rect(RectStyle{FillGradient: grad(RGB(8, 9, 9))}), false},
+		{"path gradients", line(PathStyle{StrokeGradient: grad(RGB(9, 9, 9))}), line(PathStyle{StrokeGradient: grad(RGB(9, 9, 9))}), true},
+		{"other path styles", line(PathStyle{StrokeWidth: 1}), line(PathStyle{StrokeWidth: 2}), false},
+		{"same image", picture(img), picture(img), true},
+		{"equal pixels of another image", picture(img), picture(image.NewRGBA(image.Rect(0, 0, 2, 2))), false},
+		{"other text", text("a"), text("b"), false},
+		{"longer", func(cv Canvas) { cv.Save() }, func(cv Canvas) { cv.Save(); cv.Restore() }, false},
+	}
+	for _, tt := range tests {
+		var a, b Recording
+		tt.a(&a)
+		tt.b(&b)
+		if got := a.Equal(&b); got != tt.want {
+			t.Errorf("%s: Equal() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRecordingEqualMoved(t *testing.T) {
+	d := Pt(10, 20)
+	// draw returns a drawing of one command of most kinds at p, with an
+	// absolute gradient fill.
+	draw := func(p Point) func(Canvas) {
+		return func(cv Canvas) {
+			cv.Clip(R(p.X, p.Y, 50, 50))
+			cv.DrawRect(R(p.X, p.Y, 3, 4), RectStyle{FillGradient: LinearGradient(p, p.Add(Pt(10, 0)), Stop(0, White), Stop(1, Black))})
+			cv.DrawText("hi", p.Add(Pt(7, 8)), TextStyle{Size: 12})
+			cv.DrawPath(NewPath().MoveTo(p).LineTo(p.Add(Pt(10, 0))).LineTo(p.Add(Pt(0, 10))).Close(), PathStyle{Fill: Black})
+		}
+	}
+	// translated returns a drawing translated to p, then drawn at q.
+	translated := func(p, q Point) func(Canvas) {
+		return func(cv Canvas) {
+			cv.Save()
+			cv.Translate(p.X, p.Y)
+			cv.DrawRect(R(0, 0, 3, 4), Filled(Black))
+			cv.Restore()
+			cv.DrawRect(R(q.X, q.Y, 3, 4), Filled(Black))
+		}
+	}
+	relative := func(p Point) func(Canvas) {
+		return func(cv Canvas) {
+			g := LinearGradient(Pt(0, 0), Pt(1, 0), Stop(0, White), Stop(1, Black))
+			g.Relative = true
+			cv.DrawRect(R(p.X, p.Y, 3, 4), RectStyle{FillGradient: g})
+		}
+	}
+	tests := []struct {
+		name string
+		a, b func(cv Canvas)
+		want bool
+	}{
+		{"moved", draw(Pt(11, 22)), draw(Pt(1, 2)), true},
+		{"not moved", draw(Pt(1, 2)), draw(Pt(1, 2)), false},
+		{"moved elsewhere", draw(Pt(11, 2)), draw(Pt(1, 2)), false},
+		{"moved by a translation", translated(Pt(15, 26), Pt(11, 22)), translated(Pt(5, 6), Pt(1, 2)), true},
+		{"moved after a translation", translated(Pt(5, 6), Pt(11, 22)), translated(Pt(5, 6), Pt(1, 2)), false},
+		{"restored without the move", translated(Pt(15, 26), Pt(1, 2)), translated(Pt(5, 6), Pt(1, 2)), false},
+		{"a relative gradient", relative(Pt(11, 22)), relative(Pt(1, 2)), true},
+	}
+	for _, tt := range tests {
+		var a, b Recording
+		tt.a(&a)
+		tt.b(&b)
+		if got := a.EqualMoved(&b, d); got != tt.want {
+			t.Errorf("%s: EqualMoved() = %v, want %v", tt.name, got, tt.want)
+		}
+		if a.EqualMoved(&b, Point{}) != a.Equal(&b) {
+			t.Errorf("%s: EqualMoved by nothing differs from Equal", tt.name)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package core
+
+import (
+	"maps"
+	"slices"
+)
+
+// layoutNode is what a Context remembers of the last layout of a widget.
+type layoutNode struct {
+	parent      Widget
+	depth       int
+	constraints Constraints
+	size        Size
+	scope       layoutScope
+	dirty       bool
+	seen        uint64
+}
+
+// layoutScope is the part of a Context that containers change for their
+// subtree, such as the theme or the reading direction.
+type layoutScope struct {
+	values map[any]any
+	class  SizeClass
+	dir    Direction
+}
+
+func (c *Context) scope() layoutScope {
+	return layoutScope{values: c.values, class: c.class, dir: c.dir}
+}
+
+func (c *Context) restore(s layoutScope) {
+	c.values, c.class, c.dir = s.values, s.class, s.dir
+}
+
+// measure lays out w for owner under cons, or, during an incremental
+// layout, returns the size of its last layout if that is still valid.
+func (c *Context) measure(owner, w Widget, cons Constraints) Size {
+	n := c.nodes[w]
+	if n == nil {
+		if c.nodes == nil {
+			c.nodes = make(map[Widget]*layoutNode)
+		}
+		n = &layoutNode{dirty: true}
+		c.nodes[w] = n
+	}
+	n.parent, n.seen = owner, c.pass
+	if c.reuse && !n.dirty && n.constraints == cons {
+		return n.size
+	}
+	n.depth = 0
+	if p := c.nodes[owner]; p != nil {
+		n.depth = p.depth + 1
+	}
+	n.constraints, n.scope, n.dirty = cons, c.scope(), false
+	c.measuring++
+	n.size = w.Layout(&LayoutContext{Context: c, Constraints: cons, owner: w})
+	c.measuring--
+	return n.size
+}
+
+// MarkNeedsLayout requests a frame in which w is laid out again, for
+// widgets whose size or arrangement changed for a reason only they know
+// of, such as a new value of a signal they are bound to.
+//
+// Only w and its ancestors up to the nearest relayout boundary are laid
+// out again, and every other widget keeps its last layout. A relayout
+// boundary is a widget laid out under tight constraints: its parent fixed
+// its size, so nothing inside it can change the layout of the rest of the
+// tree. If w has not been laid out yet, the whole tree is.
+func (c *Context) MarkNeedsLayout(w Widget) {
+	c.redraw = true
+	for w != nil {
+		n := c.nodes[w]
+		if n == nil {
+			break
+		}
+		n.dirty = true
+		if n.constraints.IsTight() {
+			c.pending = append(c.pending, w)
+			return
+		}
+		w = n.parent
+	}
+	c.relayout = true
+}
+
+// LayoutRoot runs the layout and arrange passes for the tree under root,
+// giving it bounds. It is called by the window runtime once per frame, and
+// containers lay out their children with LayoutContext.Measure.
+//
+// If the frame was requested only with MarkNeedsLayout, the passes are
+// incremental: each relayout boundary with a marked widget inside is laid
+// out again under its last constraints, with the context values it saw
+// then, and arranged in its last bounds, while the rest of the tree is not
+// visited. Within the boundary, children that were not marked and are
+// measured under their last constraints keep their last size without being
+// laid out. After Invalidate, a change of bounds or a frame requested by
+// the platform, the whole tree is laid out again. A frame requested only
+// with Repaint and InvalidateRect is not laid out at all.
+func (c *Context) LayoutRoot(root Widget, bounds Rect) {
+	c.takeDamage()
+	pending, full := c.pending, c.relayout || len(c.pending) == 0
+	cons := Tight(bounds.Size())
+	if n := c.nodes[root]; n == nil || n.constraints != cons || root.Bounds() != bounds {
+		full = true
+	} else if !c.relayout && len(pending) == 0 && len(c.frameDamage) > 0 {
+		// Only repaints were requested.
+		return
+	}
+	c.pending, c.relayout = nil, false
+	if full {
+		c.frameAll = true
+		// Forget the widgets that were not laid out since the last full
+		// layout; they have left the tree.
+		maps.DeleteFunc(c.nodes, func(_ Widget, n *layoutNode) bool { return n.seen != c.pass })
+		c.pass++
+		c.measure(nil, root, cons)
+		root.SetBounds(bounds)
+		return
+	}
+	slices.SortFunc(pending, func(a, b Widget) int { return c.nodeDepth(a) - c.nodeDepth(b) })
+	top := c.scope()
+	c.reuse = true
+	for _, w := range pending {
+		// A boundary inside another one was laid out with it.
+		n := c.nodes[w]
+		if n == nil || !n.dirty {
+			continue
+		}
+		c.restore(n.scope)
+		c.measure(n.parent, w, n.constraints)
+		w.SetBounds(w.Bounds())
+		c.frameDamage = append(c.frameDamage, w.Bounds())
+	}
+	c.reuse = false
+	c.restore(top)
+}
+
+func (c *Context) nodeDepth(w Widget) int {
+	if n := c.nodes[w]; n != nil {
+		return n.depth
+	}
+	return 0
+}
@@ -0,0 +1,144 @@
+package core
+
+import (
+	"slices"
+	"testing"
+)
+
+// block is a widget of a fixed size stacking its children from the top,
+// under tight constraints of their own size if tight is set and loose ones
+// otherwise, and recording its layouts in log.
+type block struct {
+	WidgetBase
+	name  string
+	size  Size
+	tight bool
+	rtl   bool // Lay the children out right to left.
+	log   *[]string
+	dir   Direction // As of the last layout.
+}
+
+func (b *block) Layout(ctx *LayoutContext) Size {
+	*b.log = append(*b.log, b.name)
+	b.dir = ctx.Direction()
+	if b.rtl {
+		defer ctx.SetDirection(ctx.Direction())
+		ctx.SetDirection(RightToLeft)
+	}
+	for _, c := range b.Children() {
+		cons := Loose(b.size)
+		if b.tight {
+			cons = Tight(c.(*block).size)
+		}
+		ctx.Measure(c, cons)
+	}
+	return ctx.Constraints.Constrain(b.size)
+}
+
+func (b *block) SetBounds(r Rect) {
+	b.WidgetBase.SetBounds(r)
+	y := r.Y
+	for _, c := range b.Children() {
+		s := c.(*block).size
+		c.SetBounds(R(r.X, y, s.Width, s.Height))
+		y += s.Height
+	}
+}
+
+func (b *block) Paint(*PaintContext) {}
+
+// tree returns the blocks of a tree by name:
+//
+//	root
+//	├── outer (loose)
+//	│   └── inner
+//	│       └── leaf
+//	└── frame (tight, right to left)
+//	    ├── boxed
+//	    │   └── deep
+//	    └── sibling
+func tree(log *[]string) map[string]*block {
+	bs := map[string]*block{}
+	add := func(name string, size Size, children ...string) *block {
+		b := &block{name: name, size: size, log: log}
+		for _, c := range children {
+			b.AppendChild(bs[c])
+		}
+		bs[name] = b
+		return b
+	}
+	add("leaf", Sz(10, 10))
+	add("inner", Sz(40, 40), "leaf")
+	add("outer", Sz(50, 50), "inner")
+	add("deep", Sz(10, 10))
+	add("boxed", Sz(30, 30), "deep")
+	add("sibling", Sz(30, 20))
+	add("frame", Sz(60, 60), "boxed", "sibling").tight = true
+	bs["frame"].rtl = true
+	add("root", Sz(200, 200), "outer", "frame")
+	return bs
+}
+
+func TestMarkNeedsLayout(t *testing.T) {
+	bounds := R(0, 0, 200, 200)
+	tests := []struct {
+		name   string
+		marked []string
+		want   []string // The blocks laid out again, in order.
+	}{
+		{"below loose constraints", []string{"leaf"}, []string{"root", "outer", "inner", "leaf"}},
+		{"inside a boundary", []string{"deep"}, []string{"boxed", "deep"}},
+		{"a boundary", []string{"boxed"}, []string{"boxed"}},
+		{"the parent of boundaries", []string{"frame"}, []string{"root", "frame"}},
+		{"two boundaries", []string{"deep", "sibling"}, []string{"boxed", "deep", "sibling"}},
+		{"a boundary and one inside it", []string{"deep", "frame"}, []string{"root", "frame", "boxed", "deep"}},
+		{"nothing", nil, []string{"root", "outer", "inner", "leaf", "frame", "boxed", "deep", "sibling"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var log []string
+			bs := tree(&log)
+			ctx := NewContext()
+			ctx.LayoutRoot(bs["root"], bounds)
+			log = nil
+			for _, m := range tt.marked {
+				ctx.MarkNeedsLayout(bs[m])
+			}
+			ctx.LayoutRoot(bs["root"], bounds)
+			if !slices.Equal(log, tt.want) {
+				t.Errorf("laid out %q, want %q", log, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkNeedsLayoutBoundary(t *testing.T) {
+	var log []string
+	bs := tree(&log)
+	ctx := NewContext()
+	bounds := R(0, 0, 200, 200)
+	ctx.LayoutRoot(bs["root"], bounds)
+
+	// Laid out on its own, a boundary sees the direction it saw inside its
+	// parent, keeps its bounds and arranges its children again.
+	bs["deep"].size = Sz(20, 5)
+	ctx.MarkNeedsLayout(bs["deep"])
+	ctx.LayoutRoot(bs["root"], bounds)
+	if got := bs["boxed"].dir; got != RightToLeft {
+		t.Errorf("the boundary was laid out %v, want right to left", got)
+	}
+	if got := bs["boxed"].Bounds(); got != R(0, 50, 30, 30) {
+		t.Errorf("the boundary at %v, want where it was", got)
+	}
+	if got := bs["deep"].Bounds(); got != R(0, 50, 20, 5) {
+		t.Errorf("the marked block at %v, want its new size", got)
+	}
+
+	// A widget not laid out yet lays the whole tree out.
+	log = nil
+	ctx.MarkNeedsLayout(&block{log: &log})
+	ctx.LayoutRoot(bs["root"], bounds)
+	if len(log) != len(bs) {
+		t.Errorf("laid out %q, want the whole tree", log)
+	}
+}
@@ -0,0 +1,30 @@
+package core
+
+import "math"
+
+// SnapToPixel returns v, in logical pixels, rounded to the nearest device
+// pixel at scale device pixels to each logical one.
+func SnapToPixel(v, scale float32) float32 {
+	if scale <= 0 {
+		return v
+	}
+	return float32(math.Round(float64(v*scale))) / scale
+}
+
+// SnapRect returns r with its origin on the nearest device pixel and its
+// size a whole number of device pixels, at least one for a nonempty r.
+// Rounding the size apart from the origin keeps lines of equal width
+// equally wide wherever they fall at fractional scales such as 1.25,
+// where rounding both edges would make some a pixel wider than others.
+func SnapRect(r Rect, scale float32) Rect {
+	if scale <= 0 {
+		return r
+	}
+	size := func(v float32) float32 {
+		if v <= 0 {
+			return v
+		}
+		return max(float32(math.Round(float64(v*scale))), 1) / scale
+	}
+	return Rect{X: SnapToPixel(r.X, scale), Y: SnapToPixel(r.Y, scale), Width: size(r.Width), Height: size(r.Height)}
+}
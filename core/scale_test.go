@@ -0,0 +1,46 @@
+package core
+
+import "testing"
+
+func TestSnapToPixel(t *testing.T) {
+	tests := []struct {
+		v, scale, want float32
+	}{
+		{10.3, 1, 10},
+		{10.3, 2, 10.5},
+		{10.3, 1.25, 10.4},
+		{-0.3, 1, 0},
+		{10.3, 0, 10.3}, // no scale
+	}
+	for _, tt := range tests {
+		if got := SnapToPixel(tt.v, tt.scale); got != tt.want {
+			t.Errorf("SnapToPixel(%v, %v) = %v, want %v", tt.v, tt.scale, got, tt.want)
+		}
+	}
+}
+
+func TestSnapRect(t *testing.T) {
+	tests := []struct {
+		r     Rect
+		scale float32
+		want  Rect
+	}{
+		{R(0.4, 0.6, 10.2, 9.7), 1, R(0, 1, 10, 10)},
+		{R(0.4, 0.6, 10.2, 9.7), 2, R(0.5, 0.5, 10, 9.5)},
+		{R(1, 1, 0.1, 0), 1, R(1, 1, 1, 0)}, // at least a pixel, if any
+		{R(1.3, 1.3, 2, 2), 0, R(1.3, 1.3, 2, 2)},
+	}
+	for _, tt := range tests {
+		if got := SnapRect(tt.r, tt.scale); got != tt.want {
+			t.Errorf("SnapRect(%v, %v) = %v, want %v", tt.r, tt.scale, got, tt.want)
+		}
+	}
+
+	// At 1.25, rects of equal width are equally wide wherever they fall.
+	for x := float32(0); x < 4; x += 0.2 {
+		r := SnapRect(R(x, 0, 1, 1), 1.25)
+		if w := r.Width * 1.25; w != 1 {
+			t.Errorf("a rect at %v is %v device pixels wide, want 1", x, w)
+		}
+	}
+}
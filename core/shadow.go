@@ -0,0 +1,72 @@
+package core
+
+import "math"
+
+// Shadow is a drop shadow of a rounded rectangle: the rectangle moved by
+// Offset, grown by Spread on every side and blurred, as in the CSS
+// box-shadow property. The theme gives the shadows of each elevation.
+type Shadow struct {
+	Color  Color
+	Offset Point
+
+	// Blur is the standard deviation of the gaussian blur, in logical
+	// pixels. It is half the blur radius of CSS.
+	Blur float32
+
+	Spread float32
+}
+
+// ShadowCanvas is implemented by canvases that draw shadows themselves,
+// usually GPU backends evaluating the blurred rounded rectangle in closed
+// form for each pixel of a quad, from its signed distance field.
+type ShadowCanvas interface {
+	Canvas
+
+	// DrawShadow draws s of the rounded rectangle r.
+	DrawShadow(r Rect, radius float32, s Shadow)
+}
+
+// shadowLayers is the number of rounded rectangles the shadows of other
+// canvases are drawn with.
+const shadowLayers = 6
+
+// DrawShadow draws the shadows of the rounded rectangle r into cv, in
+// order, with the canvas's own shadows if it is a ShadowCanvas. Other
+// canvases get a few translucent rounded rectangles growing across the
+// blur, which come close to it at the sizes of UI shadows.
+func DrawShadow(cv Canvas, r Rect, radius float32, shadows ...Shadow) {
+	sc, native := cv.(ShadowCanvas)
+	for _, s := range shadows {
+		if s.Color.IsTransparent() {
+			continue
+		}
+		if native {
+			sc.DrawShadow(r, radius, s)
+			continue
+		}
+		base := r.Translate(s.Offset).Inset(UniformInsets(-s.Spread))
+		radius := max(radius+s.Spread, 0)
+		if s.Blur < 0.5 {
+			cv.DrawRoundedRect(base, radius, Filled(s.Color))
+			continue
+		}
+		// Layer k reaches e past the edge of the shape, and brings the
+		// alpha of the band inside it, over the layers outside, to that
+		// of the blurred edge at the middle of the band: 1 - Φ(d/σ) of
+		// the shadow's alpha at a distance d.
+		sigma, a := float64(s.Blur), float64(s.Color.A)
+		var prev float64
+		for k := range shadowLayers {
+			e := sigma * (2 - 4*float64(k)/(shadowLayers-1))
+			target := a
+			if k < shadowLayers-1 {
+				mid := e - 2*sigma/(shadowLayers-1)
+				target = a * 0.5 * math.Erfc(mid/sigma/math.Sqrt2)
+			}
+			alpha := 1 - (1-target)/(1-prev)
+			prev = target
+			rr := base.Inset(UniformInsets(-float32(e)))
+			cv.DrawRoundedRect(rr, max(radius+float32(e), 0), Filled(s.Color.WithAlpha(float32(alpha))))
+		}
+	}
+}
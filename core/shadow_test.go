@@ -0,0 +1,53 @@
+package core
+
+import "testing"
+
+// shadowed is a canvas drawing shadows itself, recording them.
+type shadowed struct {
+	Recording
+	shadows []Shadow
+}
+
+func (c *shadowed) DrawShadow(_ Rect, _ float32, s Shadow) {
+	c.shadows = append(c.shadows, s)
+}
+
+func TestDrawShadow(t *testing.T) {
+	r := R(10, 10, 40, 20)
+	sharp := Shadow{Color: Black, Offset: Pt(2, 3), Blur: 0.2, Spread: 1}
+	soft := Shadow{Color: Black.WithAlpha(0.5), Blur: 2}
+	none := Shadow{Blur: 4}
+
+	native := &shadowed{}
+	DrawShadow(native, r, 4, sharp, none, soft)
+	if len(native.shadows) != 2 || native.shadows[0] != sharp || native.shadows[1] != soft || native.Len() != 0 {
+		t.Errorf("drew %v itself, want the visible shadows in order", native.shadows)
+	}
+
+	// Elsewhere, a sharp shadow is its shape, and a soft one rounded
+	// rectangles from two deviations out to two in, together as opaque
+	// as the shadow inside.
+	var rec Recording
+	DrawShadow(&rec, r, 4, sharp, none, soft)
+	if rec.Len() != 1+shadowLayers {
+		t.Fatalf("%d rects drawn, want 1 and %d", rec.Len(), shadowLayers)
+	}
+	if op := rec.ops[0]; op.rect != R(11, 12, 42, 22) || op.radius != 5 || op.rectStyle.Fill != Black {
+		t.Errorf("sharp shadow %v of radius %v, want the rect moved and spread", op.rect, op.radius)
+	}
+	layers := rec.ops[1:]
+	if first, last := layers[0], layers[len(layers)-1]; first.rect != r.Inset(UniformInsets(-4)) || last.rect != r.Inset(UniformInsets(4)) || first.radius != 8 || last.radius != 0 {
+		t.Errorf("layers from %v to %v, want from two deviations out to two in", first.rect, last.rect)
+	}
+	through := float32(1)
+	for i, op := range layers {
+		a := op.rectStyle.Fill.A
+		if a <= 0 || a >= 0.5 {
+			t.Errorf("layer %d of alpha %v", i, a)
+		}
+		through *= 1 - a
+	}
+	if got := 1 - through; got < 0.5-1e-3 || got > 0.5+1e-3 {
+		t.Errorf("alpha %v inside, want the shadow's 0.5", got)
+	}
+}
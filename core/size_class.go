@@ -0,0 +1,46 @@
+package core
+
+// SizeClass is a coarse classification of the width available to a part
+// of the UI, for widgets that change their arrangement rather than just
+// stretch: a navigation rail instead of a drawer, two panes instead of
+// one.
+type SizeClass uint8
+
+// Size classes.
+const (
+	// SizeCompact is a phone in portrait or a narrow window.
+	SizeCompact SizeClass = iota
+	// SizeMedium is a tablet in portrait or a half-screen window.
+	SizeMedium
+	// SizeExpanded is a tablet in landscape or a desktop window.
+	SizeExpanded
+)
+
+// Default breakpoints between size classes, in logical pixels.
+const (
+	MediumBreakpoint   float32 = 600
+	ExpandedBreakpoint float32 = 840
+)
+
+// SizeClassFor returns the size class of width with the default
+// breakpoints. An unbounded width is expanded.
+func SizeClassFor(width float32) SizeClass {
+	switch {
+	case width >= ExpandedBreakpoint:
+		return SizeExpanded
+	case width >= MediumBreakpoint:
+		return SizeMedium
+	}
+	return SizeCompact
+}
+
+// String returns "compact", "medium" or "expanded".
+func (s SizeClass) String() string {
+	switch s {
+	case SizeMedium:
+		return "medium"
+	case SizeExpanded:
+		return "expanded"
+	}
+	return "compact"
+}
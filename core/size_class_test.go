@@ -0,0 +1,35 @@
+package core
+
+import "testing"
+
+func TestSizeClassFor(t *testing.T) {
+	tests := []struct {
+		width float32
+		want  SizeClass
+		name  string
+	}{
+		{0, SizeCompact, "compact"},
+		{599.5, SizeCompact, "compact"},
+		{MediumBreakpoint, SizeMedium, "medium"},
+		{839, SizeMedium, "medium"},
+		{ExpandedBreakpoint, SizeExpanded, "expanded"},
+		{Infinity, SizeExpanded, "expanded"},
+	}
+	for _, tt := range tests {
+		got := SizeClassFor(tt.width)
+		if got != tt.want || got.String() != tt.name {
+			t.Errorf("SizeClassFor(%v) = %v, want %v", tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestContextSizeClass(t *testing.T) {
+	ctx := NewContext()
+	if got := ctx.SizeClass(); got != SizeCompact {
+		t.Errorf("SizeClass() = %v at start, want compact", got)
+	}
+	ctx.SetSizeClass(SizeMedium)
+	if got := ctx.SizeClass(); got != SizeMedium {
+		t.Errorf("SizeClass() = %v, want medium", got)
+	}
+}
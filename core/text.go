@@ -0,0 +1,92 @@
+package core
+
+// TextMeasurer measures single lines of text. The rendering backend
+// installs a measurer backed by real font metrics so that layout matches
+// what is drawn.
+type TextMeasurer interface {
+	MeasureText(text string, style TextStyle) Size
+}
+
+// SetTextMeasurer installs m as the text measurer. Passing nil restores the
+// approximate default.
+func (c *Context) SetTextMeasurer(m TextMeasurer) {
+	c.measurer = m
+}
+
+// MeasureText returns the size of text rendered in style.
+func (c *Context) MeasureText(text string, style TextStyle) Size {
+	if c != nil && c.measurer != nil {
+		return c.measurer.MeasureText(text, style)
+	}
+	return ApproxTextMeasurer{}.MeasureText(text, style)
+}
+
+// ApproxTextMeasurer estimates text size from character classes without
+// font data. It is the fallback when no backend measurer is installed,
+// which keeps layout deterministic in headless environments.
+type ApproxTextMeasurer struct{}
+
+// MeasureText implements TextMeasurer.
+func (ApproxTextMeasurer) MeasureText(text string, style TextStyle) Size {
+	var w float32
+	joined := false
+	for _, r := range text {
+		// A character after a zero-width joiner is drawn with the one
+		// before it, as in emoji sequences.
+		if !joined {
+			w += approxAdvance(r) * style.Size
+		}
+		joined = r == 0x200D
+	}
+	// Heavier weights are up to 5% wider, reached at semibold, growing
+	// smoothly so weights animated on a variable font reflow smoothly.
+	if style.Weight > WeightRegular {
+		w *= 1 + 0.05*min(float32(style.Weight-WeightRegular)/float32(WeightSemiBold-WeightRegular), 1)
+	}
+	if style.Stretch > 0 {
+		w *= style.Stretch / 100
+	}
+	return Size{Width: w, Height: style.LineHeight()}
+}
+
+func approxAdvance(r rune) float32 {
+	switch {
+	case isZeroWidth(r):
+		return 0
+	case r == ' ' || r == 'i' || r == 'l' || r == 'j' || r == '.' || r == ',' || r == '\'' || r == '|':
+		return 0.3
+	case r == '\t':
+		return 1.2
+	case r >= 'A' && r <= 'Z', r == 'm' || r == 'w':
+		return 0.68
+	case r >= 0x1100 && isWide(r):
+		return 1.0
+	default:
+		return 0.55
+	}
+}
+
+// isZeroWidth reports whether r is drawn as part of the character before
+// it: combining marks, joiners, variation selectors, emoji skin tones and
+// tags.
+func isZeroWidth(r rune) bool {
+	return r >= 0x0300 && r <= 0x036F ||
+		r >= 0x200B && r <= 0x200F ||
+		r == 0x20E3 ||
+		r >= 0xFE00 && r <= 0xFE0F ||
+		r >= 0x1F3FB && r <= 0x1F3FF ||
+		r >= 0xE0000 && r <= 0xE01EF
+}
+
+// isWide reports whether r is an East Asian wide or fullwidth character.
+func isWide(r rune) bool {
+	return r <= 0x115F ||
+		(r >= 0x2E80 && r <= 0xA4CF) ||
+		(r >= 0xAC00 && r <= 0xD7A3) ||
+		(r >= 0xF900 && r <= 0xFAFF) ||
+		(r >= 0xFE30 && r <= 0xFE4F) ||
+		(r >= 0xFF00 && r <= 0xFF60) ||
+		(r >= 0xFFE0 && r <= 0xFFE6) ||
+		(r >= 0x1F300 && r <= 0x1FAFF) ||
+		(r >= 0x20000 && r <= 0x3FFFD)
+}
@@ -0,0 +1,37 @@
+package core
+
+import "time"
+
+// AddTicker calls fn on the UI goroutine at the start of every frame,
+// before the functions queued by Post run, with the time of the frame,
+// until fn returns false. Frames keep coming while a ticker is added, so
+// that animation clocks advance without goroutines of their own. It must
+// be called on the UI goroutine.
+func (c *Context) AddTicker(fn func(now time.Time) bool) {
+	c.tickers = append(c.tickers, fn)
+}
+
+// Ticking reports whether a ticker is added, so that the window runtime
+// produces the next frame.
+func (c *Context) Ticking() bool {
+	return len(c.tickers) > 0
+}
+
+// RunTickers calls the tickers added with AddTicker with now, and removes
+// those that return false. It is called by the window runtime at the start
+// of every frame.
+func (c *Context) RunTickers(now time.Time) {
+	if len(c.tickers) == 0 {
+		return
+	}
+	run := c.tickers
+	c.tickers = nil
+	keep := run[:0]
+	for _, fn := range run {
+		if fn(now) {
+			keep = append(keep, fn)
+		}
+	}
+	// Tickers added while running come after those kept.
+	c.tickers = append(keep, c.tickers...)
+}
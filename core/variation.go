@@ -0,0 +1,130 @@
+package core
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Variation is the value of an axis of a variable font, such as
+// Variation{"wght", 450}. Tags are the four-letter OpenType axis tags:
+// the registered wght, wdth, slnt, ital and opsz, and the upper-case
+// tags fonts define for their own axes.
+type Variation struct {
+	Tag   string
+	Value float32
+}
+
+// ParseVariations parses a list of axis tags and values, separated by
+// commas, as in the CSS font-variation-settings property: "wght 450,
+// GRAD 50". Tags may be quoted as in CSS. Entries that do not parse are
+// skipped, and a later value for a tag replaces an earlier one.
+func ParseVariations(s string) []Variation {
+	var out []Variation
+	for _, entry := range strings.Split(s, ",") {
+		f := strings.Fields(entry)
+		if len(f) != 2 {
+			continue
+		}
+		tag := strings.Trim(f[0], `"'`)
+		v, err := strconv.ParseFloat(f[1], 32)
+		if len(tag) != 4 || err != nil {
+			continue
+		}
+		out = setVariation(out, tag, float32(v))
+	}
+	return out
+}
+
+// FormatVariations formats variations for TextStyle.Variations, the
+// inverse of ParseVariations.
+func FormatVariations(vs []Variation) string {
+	parts := make([]string, len(vs))
+	for i, v := range vs {
+		parts[i] = v.Tag + " " + strconv.FormatFloat(float64(v.Value), 'f', -1, 32)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func setVariation(vs []Variation, tag string, v float32) []Variation {
+	for i := range vs {
+		if vs[i].Tag == tag {
+			vs[i].Value = v
+			return vs
+		}
+	}
+	return append(vs, Variation{tag, v})
+}
+
+// Axes returns the axis values the style sets for a variable font: wght
+// from Weight, wdth from Stretch, slnt from Slant and ital from Italic,
+// where they are set, followed by Variations, which override them.
+func (s TextStyle) Axes() []Variation {
+	weight := s.Weight
+	if weight == 0 {
+		weight = WeightRegular
+	}
+	out := []Variation{{"wght", float32(weight)}}
+	if s.Stretch != 0 {
+		out = append(out, Variation{"wdth", s.Stretch})
+	}
+	if s.Slant != 0 {
+		// The slnt axis counts degrees counter-clockwise.
+		out = append(out, Variation{"slnt", -s.Slant})
+	}
+	if s.Italic {
+		out = append(out, Variation{"ital", 1})
+	}
+	for _, v := range ParseVariations(s.Variations) {
+		out = setVariation(out, v.Tag, v.Value)
+	}
+	return out
+}
+
+// Lerp interpolates between s and to, as a weight, width or other axis
+// of a variable font is animated. Size, Weight, Stretch, Slant, Color
+// and the axes in Variations are interpolated, an axis set on only one
+// side keeping its value; Family and Italic switch halfway.
+func (s TextStyle) Lerp(to TextStyle, t float32) TextStyle {
+	lerp := func(a, b float32) float32 { return a + (b-a)*t }
+	out := s
+	if t >= 0.5 {
+		out.Family, out.Italic = to.Family, to.Italic
+	}
+	out.Size = lerp(s.Size, to.Size)
+	if s.Weight != to.Weight {
+		from, dest := s.Weight, to.Weight
+		if from == 0 {
+			from = WeightRegular
+		}
+		if dest == 0 {
+			dest = WeightRegular
+		}
+		out.Weight = FontWeight(math.Round(float64(lerp(float32(from), float32(dest)))))
+	}
+	stretch := func(v float32) float32 {
+		if v == 0 {
+			return 100
+		}
+		return v
+	}
+	if s.Stretch != to.Stretch {
+		out.Stretch = lerp(stretch(s.Stretch), stretch(to.Stretch))
+	}
+	out.Slant = lerp(s.Slant, to.Slant)
+	out.Color = s.Color.Lerp(to.Color, t)
+	if s.Variations != to.Variations {
+		vs := ParseVariations(s.Variations)
+		for _, b := range ParseVariations(to.Variations) {
+			a := b.Value
+			for _, v := range vs {
+				if v.Tag == b.Tag {
+					a = v.Value
+				}
+			}
+			vs = setVariation(vs, b.Tag, lerp(a, b.Value))
+		}
+		out.Variations = FormatVariations(vs)
+	}
+	return out
+}
@@ -0,0 +1,156 @@
+package core
+
+// Widget is the interface implemented by every element of the UI tree.
+//
+// Layout measures the widget under the constraints carried by ctx and
+// returns its desired size. The parent then calls SetBounds with the final
+// rectangle in window coordinates; containers override SetBounds to arrange
+// their children. Paint draws the widget inside Bounds().
+type Widget interface {
+	// Layout calculates size given constraints.
+	Layout(ctx *LayoutContext) Size
+
+	// SetBounds assigns the widget's final rectangle in window coordinates.
+	SetBounds(bounds Rect)
+
+	// Bounds returns the rectangle assigned by the last SetBounds call.
+	Bounds() Rect
+
+	// Paint renders the widget.
+	Paint(ctx *PaintContext)
+
+	// HandleEvent processes an input event. Pointer events carry window
+	// coordinates. Returning Handled stops propagation to ancestors.
+	HandleEvent(ctx *Context, ev Event) EventResult
+}
+
+// Parent is implemented by widgets that contain other widgets. The order of
+// Children is the paint order; later children are on top for hit testing.
+type Parent interface {
+	Children() []Widget
+}
+
+// Focusable is implemented by widgets that can receive keyboard focus.
+type Focusable interface {
+	Widget
+	Focus()
+	Blur()
+	IsFocused() bool
+}
+
+// HitTester lets a widget refine hit testing beyond its rectangular bounds,
+// for example to ignore transparent corners of a round button.
+type HitTester interface {
+	HitTest(p Point) bool
+}
+
+// IntrinsicSizer is implemented by widgets that can report their preferred
+// sizes without being laid out, for containers that size children by
+// their content, such as layout.IntrinsicWidth.
+//
+// IntrinsicWidth returns, for the given height, the smallest width the
+// widget can take without clipping or overflowing its content and the
+// width beyond which it stops growing, for text the longest word and the
+// longest line. IntrinsicHeight does the same for heights at a given
+// width. An unbounded extent is passed as Infinity. Widgets query their
+// children with LayoutContext.IntrinsicWidth and IntrinsicHeight.
+type IntrinsicSizer interface {
+	IntrinsicWidth(ctx *LayoutContext, height float32) (minWidth, maxWidth float32)
+	IntrinsicHeight(ctx *LayoutContext, width float32) (minHeight, maxHeight float32)
+}
+
+// Baseliner is implemented by widgets that show text, for containers that
+// align children on their first text baseline, such as rows of labels in
+// different font sizes.
+//
+// Baseline returns the distance from the top of the widget to the baseline
+// of its first line of text as of the last Layout, and false if the widget
+// shows no text. Containers that report the baseline of a child add the
+// child's offset.
+type Baseliner interface {
+	Baseline() (float32, bool)
+}
+
+// BaselineOf returns the baseline of w if it is a Baseliner.
+func BaselineOf(w Widget) (float32, bool) {
+	if b, ok := w.(Baseliner); ok {
+		return b.Baseline()
+	}
+	return 0, false
+}
+
+// TextInput is implemented by widgets that take typed text, so the window
+// can turn on the platform's input method while one has focus and place
+// its candidate window by the caret.
+type TextInput interface {
+	// TextInputRect returns the caret rectangle in window coordinates.
+	TextInputRect(ctx *Context) Rect
+}
+
+// ShortcutHandler is implemented by widgets that want keyboard events the
+// focused widget and its ancestors did not handle, such as a menu bar
+// responding to Alt+F wherever focus is.
+type ShortcutHandler interface {
+	HandleShortcut(ctx *Context, ev Event) EventResult
+}
+
+// LayoutContext carries constraints and shared services into Layout.
+type LayoutContext struct {
+	*Context
+
+	// Constraints bound the size the widget may return.
+	Constraints Constraints
+
+	owner Widget
+}
+
+// Measure lays out child under c and returns its size. During an
+// incremental layout (see Context.LayoutRoot) a child that was last laid
+// out under c and has no subtree marked with MarkNeedsLayout is not laid
+// out again, and the size it returned then is reused.
+func (lc *LayoutContext) Measure(child Widget, c Constraints) Size {
+	return lc.Context.measure(lc.owner, child, c)
+}
+
+// IntrinsicWidth returns the minimum and maximum intrinsic widths of child
+// at the given height. For widgets that do not implement IntrinsicSizer
+// both are the width child takes when laid out with an unbounded width, so
+// the child must be measured again before it is placed.
+func (lc *LayoutContext) IntrinsicWidth(child Widget, height float32) (minWidth, maxWidth float32) {
+	if s, ok := child.(IntrinsicSizer); ok {
+		return s.IntrinsicWidth(lc, height)
+	}
+	w := lc.Measure(child, Loose(Sz(Infinity, height))).Width
+	return w, w
+}
+
+// IntrinsicHeight returns the minimum and maximum intrinsic heights of
+// child at the given width, measuring it like IntrinsicWidth if it does
+// not implement IntrinsicSizer.
+func (lc *LayoutContext) IntrinsicHeight(child Widget, width float32) (minHeight, maxHeight float32) {
+	if s, ok := child.(IntrinsicSizer); ok {
+		return s.IntrinsicHeight(lc, width)
+	}
+	h := lc.Measure(child, Loose(Sz(width, Infinity))).Height
+	return h, h
+}
+
+// PaintContext carries the canvas and shared services into Paint.
+type PaintContext struct {
+	*Context
+
+	// Canvas is the surface to draw into.
+	Canvas Canvas
+
+	// Capture is set when painting an image of the window rather than a
+	// frame, into a canvas other than the window's. Widgets keeping layers
+	// of the window's canvas paint without them.
+	Capture bool
+}
+
+// PaintChildren paints each child in order.
+func PaintChildren(ctx *PaintContext, children []Widget) {
+	for _, c := range children {
+		c.Paint(ctx)
+	}
+}
@@ -0,0 +1,69 @@
+package core
+
+// WidgetBase provides bounds and children bookkeeping for widgets.
+// Embed it and implement Layout and Paint:
+//
+//	type MyWidget struct {
+//	    core.WidgetBase
+//	    customField string
+//	}
+//
+// WidgetBase implements Bounds, SetBounds, Children and a HandleEvent that
+// ignores every event.
+type WidgetBase struct {
+	bounds   Rect
+	children []Widget
+}
+
+// Bounds returns the rectangle assigned by the last SetBounds call.
+func (w *WidgetBase) Bounds() Rect {
+	return w.bounds
+}
+
+// SetBounds records the widget's rectangle. Containers that embed
+// WidgetBase override SetBounds to arrange their children and call this
+// method to record their own bounds.
+func (w *WidgetBase) SetBounds(bounds Rect) {
+	w.bounds = bounds
+}
+
+// Children returns the child widgets in paint order.
+func (w *WidgetBase) Children() []Widget {
+	return w.children
+}
+
+// SetChildren replaces the child widgets.
+func (w *WidgetBase) SetChildren(children ...Widget) {
+	w.children = children
+}
+
+// AppendChild adds a child on top of the existing ones.
+func (w *WidgetBase) AppendChild(child Widget) {
+	w.children = append(w.children, child)
+}
+
+// HandleEvent ignores the event.
+func (w *WidgetBase) HandleEvent(*Context, Event) EventResult {
+	return Ignored
+}
+
+// FocusState implements the focus half of [Focusable]. Embed it next to
+// WidgetBase in widgets that accept keyboard focus.
+type FocusState struct {
+	focused bool
+}
+
+// Focus marks the widget as focused.
+func (f *FocusState) Focus() {
+	f.focused = true
+}
+
+// Blur marks the widget as not focused.
+func (f *FocusState) Blur() {
+	f.focused = false
+}
+
+// IsFocused reports whether the widget has keyboard focus.
+func (f *FocusState) IsFocused() bool {
+	return f.focused
+}
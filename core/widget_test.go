@@ -0,0 +1,39 @@
+package core
+
+import "testing"
+
+// sized is a node reporting fixed intrinsic sizes.
+type sized struct {
+	node
+	lo, hi float32
+}
+
+func (s *sized) IntrinsicWidth(*LayoutContext, float32) (float32, float32) { return s.lo, s.hi }
+func (s *sized) IntrinsicHeight(*LayoutContext, float32) (float32, float32) {
+	return s.lo / 2, s.hi / 2
+}
+
+func TestLayoutContextIntrinsic(t *testing.T) {
+	lc := &LayoutContext{Context: NewContext()}
+	tests := []struct {
+		name       string
+		w          Widget
+		minW, maxW float32
+		minH, maxH float32
+	}{
+		{"sizer", &sized{lo: 20, hi: 80}, 20, 80, 10, 40},
+		{"measured", newNode("n", R(0, 0, 30, 12)), 30, 30, 12, 12},
+	}
+	for _, tt := range tests {
+		if lo, hi := lc.IntrinsicWidth(tt.w, Infinity); lo != tt.minW || hi != tt.maxW {
+			t.Errorf("%s: IntrinsicWidth = %v, %v, want %v, %v", tt.name, lo, hi, tt.minW, tt.maxW)
+		}
+		if lo, hi := lc.IntrinsicHeight(tt.w, Infinity); lo != tt.minH || hi != tt.maxH {
+			t.Errorf("%s: IntrinsicHeight = %v, %v, want %v, %v", tt.name, lo, hi, tt.minH, tt.maxH)
+		}
+	}
+	// Measuring falls back to the extent given on the other axis.
+	if lo, hi := lc.IntrinsicWidth(newNode("n", R(0, 0, 30, 12)), 5); lo != 30 || hi != 30 {
+		t.Errorf("IntrinsicWidth at a height of 5 = %v, %v", lo, hi)
+	}
+}
@@ -0,0 +1,148 @@
+package ui
+
+import (
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/theme"
+)
+
+// Dialog is a modal dialog opened with ShowDialog.
+type Dialog struct {
+	ctx     *core.Context
+	overlay *core.Overlay
+	frame   *dialogFrame
+}
+
+// DialogOption configures a Dialog.
+type DialogOption func(*Dialog)
+
+// DialogEscape sets whether Escape closes the dialog. It does by default;
+// disable it for dialogs that require an explicit choice.
+func DialogEscape(enabled bool) DialogOption {
+	return func(d *Dialog) {
+		d.overlay.CloseOnEscape = enabled
+	}
+}
+
+// DialogDismissible makes a press on the scrim, outside the dialog, close
+// it.
+func DialogDismissible(enabled bool) DialogOption {
+	return func(d *Dialog) {
+		d.overlay.LightDismiss = enabled
+	}
+}
+
+// DialogScrim replaces the theme's scrim color. A transparent color leaves
+// the content beneath visible, though still blocked.
+func DialogScrim(c core.Color) DialogOption {
+	return func(d *Dialog) {
+		d.overlay.Scrim = c
+	}
+}
+
+// DialogScrimFilter filters the content beneath the dialog, such as to
+// blur it with core.Blur, on canvases that implement core.BackdropCanvas.
+func DialogScrimFilter(f core.Filter) DialogOption {
+	return func(d *Dialog) {
+		d.overlay.ScrimFilter = f
+	}
+}
+
+// DialogPlacement positions the dialog within the window instead of
+// centering it. See core.Overlay.Placement.
+func DialogPlacement(fn func(content core.Size, area core.Rect) core.Point) DialogOption {
+	return func(d *Dialog) {
+		d.overlay.Placement = fn
+	}
+}
+
+// DialogOnClose registers fn to be called after the dialog closes, whether
+// by Close, Escape or a press on the scrim.
+func DialogOnClose(fn func()) DialogOption {
+	return func(d *Dialog) {
+		d.overlay.OnClose = fn
+	}
+}
+
+// ShowDialog shows content in a modal dialog above everything else in the
+// window and returns it.
+//
+// Content is drawn on a themed surface over a scrim. While the dialog is
+// open, nothing beneath it receives input, and Tab and Shift+Tab cycle focus
+// among its focusable widgets; the first one receives focus when the dialog
+// opens. Closing the dialog returns focus to where it was. Dialogs stack: a
+// dialog opened from another blocks it in turn, and popups such as menus
+// opened from a dialog appear above it.
+func ShowDialog(ctx *core.Context, content core.Widget, opts ...DialogOption) *Dialog {
+	d := &Dialog{ctx: ctx, frame: &dialogFrame{content: content}}
+	d.frame.SetChildren(content)
+	d.overlay = &core.Overlay{
+		Content:       d.frame,
+		Modal:         true,
+		Scrim:         theme.From(ctx).Colors.Scrim,
+		CloseOnEscape: true,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	ctx.ShowOverlay(d.overlay)
+	if !core.FocusNext(ctx, d.frame, false) {
+		ctx.RequestFocus(nil)
+	}
+	return d
+}
+
+// Close closes the dialog and any popups opened from it.
+func (d *Dialog) Close() {
+	d.ctx.CloseOverlay(d.overlay)
+}
+
+// IsOpen reports whether the dialog is shown.
+func (d *Dialog) IsOpen() bool {
+	return d.overlay.IsOpen()
+}
+
+// Content returns the widget shown in the dialog.
+func (d *Dialog) Content() core.Widget {
+	return d.frame.content
+}
+
+// Overlay returns the overlay backing the dialog.
+func (d *Dialog) Overlay() *core.Overlay {
+	return d.overlay
+}
+
+// dialogFrame draws the dialog surface around its content and keeps it
+// clear of the window edges.
+type dialogFrame struct {
+	core.WidgetBase
+	content core.Widget
+	pad     core.Insets
+}
+
+func (f *dialogFrame) Layout(ctx *core.LayoutContext) core.Size {
+	sp := theme.From(ctx.Context).Spacing
+	f.pad = core.UniformInsets(sp.L)
+	c := ctx.Constraints.Loosen().Deflate(core.UniformInsets(sp.XL)).Deflate(f.pad)
+	size := ctx.Measure(f.content, c)
+	return core.Sz(size.Width+f.pad.Horizontal(), size.Height+f.pad.Vertical())
+}
+
+func (f *dialogFrame) SetBounds(r core.Rect) {
+	f.WidgetBase.SetBounds(r)
+	f.content.SetBounds(r.Inset(f.pad))
+}
+
+func (f *dialogFrame) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	r := f.Bounds()
+	core.DrawShadow(ctx.Canvas, r, th.Radii.Large, th.Elevation(theme.ElevationDialog)...)
+	ctx.Canvas.DrawRoundedRect(r, th.Radii.Large, core.RectStyle{
+		Fill:        th.Colors.Surface,
+		Stroke:      th.Colors.Outline.WithAlpha(0.4),
+		StrokeWidth: 1,
+	})
+	ctx.Canvas.Save()
+	core.ClipRoundedRect(ctx.Canvas, r, th.Radii.Large)
+	f.content.Paint(ctx)
+	ctx.Canvas.Restore()
+}
@@ -0,0 +1,205 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/theme"
+)
+
+// field is a focusable pane.
+type field struct {
+	pane
+	core.FocusState
+}
+
+// fills records the colors of the rectangles drawn over the whole window.
+type fills struct {
+	core.Recording
+	window core.Rect
+	colors []core.Color
+}
+
+func (f *fills) DrawRect(r core.Rect, st core.RectStyle) {
+	if r == f.window {
+		f.colors = append(f.colors, st.Fill)
+	}
+	f.Recording.DrawRect(r, st)
+}
+
+// dialogWindow returns a window of 400 by 300 whose tree holds a focused
+// field, and the field.
+func dialogWindow() (*Window, *field) {
+	f := &field{}
+	w := NewWindow(newPane(f))
+	w.Resize(core.Sz(400, 300))
+	w.Layout()
+	w.Context().RequestFocus(f)
+	return w, f
+}
+
+func TestDialogFocus(t *testing.T) {
+	w, before := dialogWindow()
+	a, b := &field{}, &field{}
+	d := ShowDialog(w.Context(), newPane(a, b))
+	w.Layout()
+	if !a.IsFocused() {
+		t.Fatal("the first field of the dialog is not focused")
+	}
+	tab := event.KeyEvent{Type: event.KeyPress, Key: event.KeyTab}
+	var got []*field
+	for range 3 {
+		w.HandleEvent(tab)
+		got = append(got, w.Context().Focused().(*field))
+	}
+	if want := []*field{b, a, b}; !slices.Equal(got, want) {
+		t.Error("Tab left the dialog")
+	}
+	w.HandleEvent(event.KeyEvent{Type: event.KeyPress, Key: event.KeyA})
+	if len(before.got) != 0 {
+		t.Errorf("the tree beneath got %v", before.got)
+	}
+	d.Close()
+	if d.IsOpen() || !before.IsFocused() {
+		t.Error("closing the dialog did not return the focus")
+	}
+}
+
+func TestDialogDismiss(t *testing.T) {
+	outside := event.MouseEvent{Type: event.MouseDown, Position: core.Pt(2, 2), Button: event.ButtonLeft}
+	escape := event.KeyEvent{Type: event.KeyPress, Key: event.KeyEscape}
+	tests := []struct {
+		name string
+		opts []DialogOption
+		ev   core.Event
+		open bool
+	}{
+		{"escape", nil, escape, false},
+		{"escape disabled", []DialogOption{DialogEscape(false)}, escape, true},
+		{"press outside", nil, outside, true},
+		{"press outside when dismissible", []DialogOption{DialogDismissible(true)}, outside, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, before := dialogWindow()
+			closed := 0
+			content := &field{}
+			opts := append(tt.opts, DialogOnClose(func() { closed++ }))
+			d := ShowDialog(w.Context(), content, opts...)
+			w.Layout()
+			w.HandleEvent(tt.ev)
+			if d.IsOpen() != tt.open {
+				t.Errorf("IsOpen() = %v, want %v", d.IsOpen(), tt.open)
+			}
+			if (closed == 1) == tt.open || closed > 1 {
+				t.Errorf("OnClose called %d times", closed)
+			}
+			if len(before.got) != 0 {
+				t.Errorf("the tree beneath got %v", before.got)
+			}
+		})
+	}
+}
+
+func TestDialogPlacement(t *testing.T) {
+	w, _ := dialogWindow()
+	d := ShowDialog(w.Context(), newPane())
+	w.Layout()
+	sp := theme.From(w.Context()).Spacing
+	if got, want := d.Overlay().Bounds(), core.R(0, 0, 400, 300).Inset(core.UniformInsets(sp.XL)); got != want {
+		t.Errorf("dialog at %v, want %v, centered clear of the edges", got, want)
+	}
+	if got := d.Content().Bounds(); got != d.Overlay().Bounds().Inset(core.UniformInsets(sp.L)) {
+		t.Errorf("content at %v inside %v", got, d.Overlay().Bounds())
+	}
+	d.Close()
+	d = ShowDialog(w.Context(), newPane(), DialogPlacement(func(core.Size, core.Rect) core.Point { return core.Pt(5, 6) }))
+	w.Layout()
+	if got := d.Overlay().Bounds().Origin(); got != core.Pt(5, 6) {
+		t.Errorf("dialog at %v, want (5, 6)", got)
+	}
+}
+
+func TestDialogScrim(t *testing.T) {
+	red := core.RGB(255, 0, 0)
+	tests := []struct {
+		name  string
+		opts  []DialogOption
+		scrim []core.Color
+	}{
+		{"theme", nil, []core.Color{theme.Light().Colors.Scrim}},
+		{"custom", []DialogOption{DialogScrim(red)}, []core.Color{red}},
+		{"none", []DialogOption{DialogScrim(core.Transparent)}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, _ := dialogWindow()
+			beneath := &fills{window: core.R(0, 0, 400, 300)}
+			w.Frame(beneath)
+			ShowDialog(w.Context(), newPane(), tt.opts...)
+			cv := &fills{window: beneath.window}
+			w.Frame(cv)
+			if got := cv.colors[len(beneath.colors):]; !slices.Equal(got, tt.scrim) {
+				t.Errorf("scrim %v, want %v", got, tt.scrim)
+			}
+		})
+	}
+}
+
+// frostedFills is fills with backdrop filters, recording them among the
+// fills as the color black.
+type frostedFills struct {
+	fills
+}
+
+func (f *frostedFills) DrawBackdrop(r core.Rect, _ float32, _ core.Filter) {
+	if r == f.window {
+		f.colors = append(f.colors, core.Black)
+	}
+}
+
+func TestDialogScrimFilter(t *testing.T) {
+	w, _ := dialogWindow()
+	ShowDialog(w.Context(), newPane(), DialogScrim(core.White), DialogScrimFilter(core.Blur(8)))
+	window := core.R(0, 0, 400, 300)
+
+	// The scrim is painted over the window filtered beneath it.
+	cv := &frostedFills{fills{window: window}}
+	w.Frame(cv)
+	// The tree beneath fills the window twice, in its pane and field.
+	if got := cv.colors[2:]; !slices.Equal(got, []core.Color{core.Black, core.White}) {
+		t.Errorf("fills %v, want the backdrop and then the scrim", got)
+	}
+	plain := &fills{window: window}
+	w.RepaintAll()
+	w.Frame(plain)
+	if got := plain.colors[2:]; !slices.Equal(got, []core.Color{core.White}) {
+		t.Errorf("fills %v without filters, want the scrim alone", got)
+	}
+}
+
+func TestDialogStacks(t *testing.T) {
+	w, _ := dialogWindow()
+	first := &field{}
+	ShowDialog(w.Context(), first)
+	w.Layout()
+	r := first.Bounds()
+	second := ShowDialog(w.Context(), newPane(), DialogPlacement(func(core.Size, core.Rect) core.Point { return core.Pt(0, 0) }),
+		DialogScrim(core.Transparent))
+	w.Layout()
+	p := core.Pt(r.Right()-2, r.Bottom()-2)
+	if second.Overlay().Bounds().Contains(p) {
+		t.Fatal("the dialogs overlap at the probe")
+	}
+	w.HandleEvent(event.MouseEvent{Type: event.MouseDown, Position: p, Button: event.ButtonLeft})
+	if len(first.got) != 0 {
+		t.Errorf("the dialog beneath got %v", first.got)
+	}
+	second.Close()
+	w.HandleEvent(event.MouseEvent{Type: event.MouseDown, Position: p, Button: event.ButtonLeft})
+	if len(first.got) == 0 {
+		t.Error("the dialog got nothing once the one above closed")
+	}
+}
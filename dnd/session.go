@@ -0,0 +1,329 @@
+// Package dnd drags data from one widget of an application to another.
+//
+// A [DragSource] lifts what it holds when dragged, and a preview of it
+// follows the pointer above everything else until it is dropped. A
+// [DropTarget] takes part in a drag if it accepts the payload's type:
+// while the pointer is over it, it is highlighted and told where the
+// pointer is, and on release it receives the payload:
+//
+//	card := dnd.NewDragSource(view, func() Card { return c })
+//	column := dnd.NewDropTarget(list, func(c Card, p core.Point) {
+//		board.Move(c, col)
+//	})
+//
+// The target a drag is over is the deepest one under the pointer that
+// accepts the payload, so targets nest, as the columns of a board hold
+// cards that are targets too. Held near the edge of something scrolling
+// under the pointer, such as a ScrollView or a VirtualList, a drag scrolls
+// it, faster the closer to the edge. Escape cancels a drag.
+//
+// Drags are recognized with package gesture, so they compete with the
+// other gestures of their pointer, and payloads are typed: a target only
+// sees drags of the type it was made for.
+//
+// Drags from other applications, of files, text or images, carry an
+// *event.DragData, which a DropTarget[*event.DragData] accepts:
+//
+//	dnd.NewDropTarget(view, func(d *event.DragData, p core.Point) {
+//		for _, path := range d.Files {
+//			open(path)
+//		}
+//	}).Accept(func(d *event.DragData) bool { return d.Has(event.FormatFiles) })
+//
+// With a platform [Host], drags of a source that exports its payload go
+// on to other applications when the pointer leaves the window.
+package dnd
+
+import (
+	"time"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/layout"
+	"github.com/gogpu/ui/theme"
+)
+
+const (
+	scrollEdge  float32 = 32
+	scrollSpeed float32 = 600 // Pixels per second at the very edge.
+	liftedAlpha float32 = 0.4 // Of a source while its payload is dragged.
+)
+
+// target is what a session needs of a DropTarget, whatever its type.
+type target interface {
+	core.Widget
+	accepts(data any) bool
+	enter(ctx *core.Context, data any, p core.Point)
+	over(data any, p core.Point)
+	leave(ctx *core.Context)
+	drop(ctx *core.Context, data any, p core.Point)
+}
+
+// Host is implemented by platform integrations that can carry drags out
+// of the window, to other applications.
+type Host interface {
+	// StartDrag hands the drag of the pointer held down over to the
+	// platform, carrying data, and calls done, from any goroutine, with
+	// whether it was dropped.
+	StartDrag(data *event.DragData, done func(dropped bool))
+}
+
+type hostKey struct{}
+
+// SetHost installs the platform's drags for the window of ctx. Without
+// one, drags end at the edge of the window.
+func SetHost(ctx *core.Context, h Host) {
+	ctx.SetValue(hostKey{}, h)
+}
+
+func hostFrom(ctx *core.Context) Host {
+	h, _ := ctx.Value(hostKey{}).(Host)
+	return h
+}
+
+// session is a drag in progress.
+type session struct {
+	ctx     *core.Context
+	data    any
+	export  func() *event.DragData
+	onEnd   func(dropped bool)
+	pos     core.Point
+	overlay *core.Overlay
+	target  target
+	done    bool
+
+	scrolling bool // Set while its ticker scrolls.
+	scrollAt  time.Time
+}
+
+// start begins dragging data from source, pressed at p. The preview
+// shows content, or else the source itself where it was lifted from.
+func start(ctx *core.Context, data any, export func() *event.DragData, source core.Widget, content core.Widget, p core.Point, onEnd func(dropped bool)) *session {
+	s := &session{ctx: ctx, data: data, export: export, onEnd: onEnd, pos: p}
+	pv := &preview{source: source, content: content}
+	if content != nil {
+		pv.SetChildren(content)
+	}
+	grab := p.Sub(core.Pt(source.Bounds().X, source.Bounds().Y))
+	s.overlay = &core.Overlay{
+		Content: pv,
+		Placement: func(size core.Size, area core.Rect) core.Point {
+			if content != nil {
+				return s.pos.Sub(core.Pt(size.Width/2, size.Height/2))
+			}
+			return s.pos.Sub(grab)
+		},
+		CloseOnEscape: true,
+		OnClose: func() {
+			if !s.done {
+				s.finish(false)
+			}
+		},
+	}
+	ctx.ShowOverlay(s.overlay)
+	s.retarget(ctx.PathAt(p))
+	return s
+}
+
+// move follows the pointer to p.
+func (s *session) move(p core.Point) {
+	if s.done {
+		return
+	}
+	s.pos = p
+	// The window moves the preview and paints where it was and is.
+	s.ctx.Repaint(s.overlay.Content)
+	path := s.ctx.PathAt(p)
+	if h := hostFrom(s.ctx); path == nil && h != nil && s.export != nil {
+		// Left the window, for the platform to carry it on.
+		s.handOff(h)
+		return
+	}
+	s.retarget(path)
+}
+
+// handOff gives the drag to the platform.
+func (s *session) handOff(h Host) {
+	s.done = true
+	if s.target != nil {
+		s.target.leave(s.ctx)
+		s.target = nil
+	}
+	s.ctx.CloseOverlay(s.overlay)
+	h.StartDrag(s.export(), func(dropped bool) {
+		s.ctx.Post(func() {
+			if s.onEnd != nil {
+				s.onEnd(dropped)
+			}
+		})
+	})
+}
+
+// release drops the payload at p, on the target under it if there is one.
+func (s *session) release(p core.Point) {
+	if s.done {
+		return
+	}
+	s.move(p)
+	s.finish(true)
+}
+
+// finish ends the drag, dropping the payload on the target if drop is set.
+func (s *session) finish(drop bool) {
+	s.done = true
+	t := s.target
+	s.target = nil
+	if t != nil {
+		t.leave(s.ctx)
+	}
+	s.ctx.CloseOverlay(s.overlay)
+	dropped := drop && t != nil
+	if dropped {
+		t.drop(s.ctx, s.data, s.pos)
+	}
+	if s.onEnd != nil {
+		s.onEnd(dropped)
+	}
+}
+
+// retarget finds the target in path, that under the pointer, and whether
+// to scroll.
+func (s *session) retarget(path []core.Widget) {
+	var t target
+	for i := len(path) - 1; i >= 0; i-- {
+		if c, ok := path[i].(target); ok && c.accepts(s.data) {
+			t = c
+			break
+		}
+	}
+	switch {
+	case t == s.target:
+		if t != nil {
+			t.over(s.data, s.pos)
+		}
+	default:
+		if s.target != nil {
+			s.target.leave(s.ctx)
+		}
+		s.target = t
+		if t != nil {
+			t.enter(s.ctx, s.data, s.pos)
+		}
+	}
+	if !s.scrolling {
+		if _, _, v := scrollerAt(path, s.pos); v != (core.Point{}) {
+			s.scrolling, s.scrollAt = true, time.Time{}
+			s.ctx.AddTicker(s.tick)
+		}
+	}
+}
+
+// tick scrolls what the pointer is held near the edge of.
+func (s *session) tick(now time.Time) bool {
+	if s.done {
+		s.scrolling = false
+		return false
+	}
+	w, scroll, v := scrollerAt(s.ctx.PathAt(s.pos), s.pos)
+	if v == (core.Point{}) {
+		s.scrolling = false
+		return false
+	}
+	if !s.scrollAt.IsZero() {
+		scroll(v.Scale(float32(now.Sub(s.scrollAt).Seconds())))
+		s.ctx.MarkNeedsLayout(w)
+		// What is under the pointer moved.
+		s.ctx.AfterFrame(func() {
+			if !s.done {
+				s.retarget(s.ctx.PathAt(s.pos))
+			}
+		})
+	}
+	s.scrollAt = now
+	return true
+}
+
+// scrollerAt returns the deepest widget of path that p is held near the
+// edge of, how to scroll it and how fast.
+func scrollerAt(path []core.Widget, p core.Point) (w core.Widget, scroll func(d core.Point), v core.Point) {
+	for i := len(path) - 1; i >= 0; i-- {
+		b := path[i].Bounds()
+		switch s := path[i].(type) {
+		case *layout.ScrollView:
+			off, limit := s.Offset(), s.MaxOffset()
+			v = core.Pt(edgeSpeed(p.X, b.X, b.Right(), off.X, limit.X), edgeSpeed(p.Y, b.Y, b.Bottom(), off.Y, limit.Y))
+			if v != (core.Point{}) {
+				return s, func(d core.Point) { s.JumpTo(s.Offset().Add(d)) }, v
+			}
+		case interface {
+			ScrollOffset() float32
+			ScrollBy(dy float32) bool
+		}:
+			// Lists and grids, which only know their offset to be at the
+			// start.
+			if dy := edgeSpeed(p.Y, b.Y, b.Bottom(), s.ScrollOffset(), -1); dy != 0 {
+				return path[i], func(d core.Point) { s.ScrollBy(d.Y) }, core.Pt(0, dy)
+			}
+		}
+	}
+	return nil, nil, core.Point{}
+}
+
+// edgeSpeed returns how fast to scroll along an axis for the pointer at p
+// in the span from lo to hi, with the view scrolled to off of limit, or
+// of no known limit when limit is negative.
+func edgeSpeed(p, lo, hi, off, limit float32) float32 {
+	switch {
+	case p < lo+scrollEdge && off > 0:
+		return -scrollSpeed * min(1, (lo+scrollEdge-p)/scrollEdge)
+	case p > hi-scrollEdge && (limit < 0 || off < limit):
+		return scrollSpeed * min(1, (p-hi+scrollEdge)/scrollEdge)
+	}
+	return 0
+}
+
+// preview follows the pointer in a drag's overlay, passing it through to
+// what lies beneath.
+type preview struct {
+	core.WidgetBase
+	source  core.Widget
+	content core.Widget
+}
+
+// Layout implements core.Widget.
+func (pv *preview) Layout(ctx *core.LayoutContext) core.Size {
+	if pv.content != nil {
+		return ctx.Measure(pv.content, ctx.Constraints.Loosen())
+	}
+	return pv.source.Bounds().Size()
+}
+
+// SetBounds implements core.Widget.
+func (pv *preview) SetBounds(r core.Rect) {
+	pv.WidgetBase.SetBounds(r)
+	if pv.content != nil {
+		pv.content.SetBounds(r)
+	}
+}
+
+// HitTest implements core.HitTester.
+func (pv *preview) HitTest(core.Point) bool {
+	return false
+}
+
+// Paint implements core.Widget.
+func (pv *preview) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	b := pv.Bounds()
+	cv := ctx.Canvas
+	core.DrawShadow(cv, b, th.Radii.Small, th.Elevation(theme.ElevationMax)...)
+	if pv.content != nil {
+		pv.content.Paint(ctx)
+		return
+	}
+	src := pv.source.Bounds()
+	cv.Save()
+	cv.Translate(b.X-src.X, b.Y-src.Y)
+	pv.source.Paint(ctx)
+	cv.Restore()
+}
@@ -0,0 +1,239 @@
+package dnd
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/layout"
+)
+
+// block is a leaf of a fixed size.
+type block struct {
+	core.WidgetBase
+}
+
+func (b *block) Layout(ctx *core.LayoutContext) core.Size {
+	return ctx.Constraints.Constrain(core.Sz(100, 100))
+}
+
+func (b *block) Paint(*core.PaintContext) {}
+
+// window lays out root in a 300x100 window and delivers pointer events
+// to it as the window runtime does.
+type window struct {
+	ctx  *core.Context
+	root core.Widget
+}
+
+func newWindow(root core.Widget) *window {
+	w := &window{ctx: core.NewContext(), root: root}
+	bounds := core.R(0, 0, 300, 100)
+	w.ctx.SetPathAt(func(p core.Point) []core.Widget {
+		if !bounds.Contains(p) {
+			return nil
+		}
+		return core.HitTest(root, p)
+	})
+	w.ctx.LayoutRoot(root, bounds)
+	return w
+}
+
+func (w *window) mouse(typ event.MouseEventType, x, y float32) {
+	ev := event.MouseEvent{Type: typ, Button: event.ButtonLeft, Position: core.Pt(x, y)}
+	if typ == event.MouseMove {
+		ev.Button = event.ButtonNone
+	}
+	core.Dispatch(w.ctx, core.HitTest(w.root, ev.Position), ev)
+	w.ctx.RoutePointer(ev)
+}
+
+// drag presses the pointer at the first point, moves it through the
+// others and releases it at the last, unless it is cancelled.
+func (w *window) drag(points ...core.Point) {
+	w.mouse(event.MouseDown, points[0].X, points[0].Y)
+	for _, p := range points[1:] {
+		w.mouse(event.MouseMove, p.X, p.Y)
+	}
+	last := points[len(points)-1]
+	w.mouse(event.MouseUp, last.X, last.Y)
+}
+
+// recorder logs what happens to sources and targets.
+type recorder struct {
+	log []string
+}
+
+func (r *recorder) add(format string, args ...any) {
+	r.log = append(r.log, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) source(payload string) *DragSource[string] {
+	return NewDragSource(&block{}, func() string { return payload }).
+		OnDragStart(func() { r.add("start") }).
+		OnDragEnd(func(dropped bool) { r.add("end %v", dropped) })
+}
+
+func dropOn[T any](r *recorder, name string, child core.Widget) *DropTarget[T] {
+	return NewDropTarget(child, func(data T, p core.Point) { r.add("%s drop %v at %v,%v", name, data, p.X, p.Y) }).
+		OnEnter(func(data T) { r.add("%s enter", name) }).
+		OnLeave(func() { r.add("%s leave", name) })
+}
+
+func TestDrag(t *testing.T) {
+	tests := []struct {
+		name   string
+		right  func(r *recorder) core.Widget // The widget right of the source.
+		points []core.Point
+		want   []string
+	}{
+		{
+			"dropped",
+			func(r *recorder) core.Widget { return dropOn[string](r, "t", &block{}) },
+			[]core.Point{core.Pt(10, 10), core.Pt(50, 10), core.Pt(150, 20)},
+			[]string{"start", "t enter", "t leave", "t drop card at 150,20", "end true"},
+		},
+		{
+			"dropped outside a target",
+			func(r *recorder) core.Widget { return &block{} },
+			[]core.Point{core.Pt(10, 10), core.Pt(50, 10), core.Pt(150, 20)},
+			[]string{"start", "end false"},
+		},
+		{
+			"dragged over and off",
+			func(r *recorder) core.Widget { return dropOn[string](r, "t", &block{}) },
+			[]core.Point{core.Pt(10, 10), core.Pt(150, 10), core.Pt(50, 20)},
+			[]string{"start", "t enter", "t leave", "end false"},
+		},
+		{
+			"refused",
+			func(r *recorder) core.Widget {
+				return dropOn[string](r, "t", &block{}).Accept(func(s string) bool { return s != "card" })
+			},
+			[]core.Point{core.Pt(10, 10), core.Pt(50, 10), core.Pt(150, 20)},
+			[]string{"start", "end false"},
+		},
+		{
+			"of another type",
+			func(r *recorder) core.Widget { return dropOn[int](r, "t", &block{}) },
+			[]core.Point{core.Pt(10, 10), core.Pt(50, 10), core.Pt(150, 20)},
+			[]string{"start", "end false"},
+		},
+		{
+			"deepest target",
+			func(r *recorder) core.Widget { return dropOn[string](r, "outer", dropOn[string](r, "inner", &block{})) },
+			[]core.Point{core.Pt(10, 10), core.Pt(50, 10), core.Pt(150, 20)},
+			[]string{"start", "inner enter", "inner leave", "inner drop card at 150,20", "end true"},
+		},
+		{
+			"refused by the deepest",
+			func(r *recorder) core.Widget {
+				return dropOn[string](r, "outer", dropOn[string](r, "inner", &block{}).Accept(func(string) bool { return false }))
+			},
+			[]core.Point{core.Pt(10, 10), core.Pt(50, 10), core.Pt(150, 20)},
+			[]string{"start", "outer enter", "outer leave", "outer drop card at 150,20", "end true"},
+		},
+		{
+			"within the slop",
+			func(r *recorder) core.Widget { return dropOn[string](r, "t", &block{}) },
+			[]core.Point{core.Pt(10, 10), core.Pt(14, 10)},
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{}
+			src := r.source("card")
+			w := newWindow(layout.NewHStack(src, tt.right(r)))
+			w.drag(tt.points...)
+			if !slices.Equal(r.log, tt.want) {
+				t.Errorf("got %q, want %q", r.log, tt.want)
+			}
+			if src.Dragging() || len(w.ctx.Overlays()) != 0 {
+				t.Errorf("dragging %v with %d overlays after the drag", src.Dragging(), len(w.ctx.Overlays()))
+			}
+		})
+	}
+}
+
+func TestDragState(t *testing.T) {
+	r := &recorder{}
+	src := r.source("card")
+	tgt := dropOn[string](r, "t", &block{})
+	w := newWindow(layout.NewHStack(src, tgt))
+	w.mouse(event.MouseDown, 10, 10)
+	w.mouse(event.MouseMove, 50, 10)
+	if !src.Dragging() || len(w.ctx.Overlays()) != 1 {
+		t.Fatalf("dragging %v with %d overlays, want the preview shown", src.Dragging(), len(w.ctx.Overlays()))
+	}
+	w.mouse(event.MouseMove, 150, 10)
+	if !tgt.Hovered() {
+		t.Error("the target under the drag is not hovered")
+	}
+
+	// Escape closes the preview, cancelling the drag.
+	w.ctx.CloseOverlay(w.ctx.Overlays()[0])
+	if src.Dragging() || tgt.Hovered() {
+		t.Errorf("cancelled: dragging %v, hovered %v", src.Dragging(), tgt.Hovered())
+	}
+	w.mouse(event.MouseUp, 150, 10)
+	want := []string{"start", "t enter", "t leave", "end false"}
+	if !slices.Equal(r.log, want) {
+		t.Errorf("got %q, want %q", r.log, want)
+	}
+}
+
+// host records the drags handed to it.
+type host struct {
+	data *event.DragData
+	done func(dropped bool)
+}
+
+func (h *host) StartDrag(data *event.DragData, done func(dropped bool)) {
+	h.data, h.done = data, done
+}
+
+func TestDragHandOff(t *testing.T) {
+	tests := []struct {
+		name   string
+		export bool
+		host   bool
+		want   []string
+	}{
+		{"exported", true, true, []string{"start", "t enter", "t leave", "end true"}},
+		{"without a host", true, false, []string{"start", "t enter", "t leave", "end false"}},
+		{"not exported", false, true, []string{"start", "t enter", "t leave", "end false"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{}
+			src := r.source("card")
+			if tt.export {
+				src.Export(func() *event.DragData { return &event.DragData{Formats: []string{event.FormatText}, Text: "card"} })
+			}
+			w := newWindow(layout.NewHStack(src, dropOn[string](r, "t", &block{})))
+			h := &host{}
+			if tt.host {
+				SetHost(w.ctx, h)
+			}
+			// Out of the window, past the target.
+			w.drag(core.Pt(10, 10), core.Pt(150, 10), core.Pt(350, 10))
+			if handed := h.data != nil; handed != (tt.host && tt.export) {
+				t.Fatalf("handed to the host: %v", handed)
+			}
+			if h.data != nil {
+				if h.data.Text != "card" || len(w.ctx.Overlays()) != 0 {
+					t.Errorf("handed %+v with %d overlays", h.data, len(w.ctx.Overlays()))
+				}
+				// The platform reports the drop from its own goroutine.
+				h.done(true)
+				w.ctx.RunPosted()
+			}
+			if !slices.Equal(r.log, tt.want) {
+				t.Errorf("got %q, want %q", r.log, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package dnd
+
+import (
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/gesture"
+)
+
+// DragSource lets its child be dragged, carrying a payload of type T to
+// the drop targets accepting it. The child is shown faded in its place
+// while dragged, and unless given a Preview, a copy of it follows the
+// pointer.
+//
+// A drag starts once the pointer moves past the slop, or with LongPress
+// once it has been held still.
+type DragSource[T any] struct {
+	core.WidgetBase
+
+	child     core.Widget
+	det       *gesture.Detector
+	data      func() T
+	preview   core.Widget
+	export    func() *event.DragData
+	longPress bool
+	onStart   func()
+	onEnd     func(dropped bool)
+
+	ctx  *core.Context
+	drag *session
+	last core.Point
+}
+
+// NewDragSource returns child as a drag source, whose drags carry what
+// data returns when they start.
+func NewDragSource[T any](child core.Widget, data func() T) *DragSource[T] {
+	s := &DragSource[T]{child: child, data: data}
+	s.detect()
+	return s
+}
+
+// Preview sets the widget that follows the pointer in a drag, centered
+// on it, instead of a copy of the child.
+func (s *DragSource[T]) Preview(w core.Widget) *DragSource[T] {
+	s.preview = w
+	return s
+}
+
+// Export lets drags of the source leave the window for other
+// applications, carrying what fn returns, where the platform has a Host.
+// An item becomes a file where dropped, such as on the desktop, from the
+// path of one in Files, or from its Name and Source.
+func (s *DragSource[T]) Export(fn func() *event.DragData) *DragSource[T] {
+	s.export = fn
+	return s
+}
+
+// LongPress sets whether drags start when the child is pressed and held,
+// rather than as soon as the pointer moves. In a list scrolled by
+// dragging, this leaves quick drags to the scrolling.
+func (s *DragSource[T]) LongPress(on bool) *DragSource[T] {
+	s.longPress = on
+	s.detect()
+	return s
+}
+
+// OnDragStart sets fn to be called when a drag starts.
+func (s *DragSource[T]) OnDragStart(fn func()) *DragSource[T] {
+	s.onStart = fn
+	return s
+}
+
+// OnDragEnd sets fn to be called when a drag ends, reporting whether its
+// payload was dropped on a target.
+func (s *DragSource[T]) OnDragEnd(fn func(dropped bool)) *DragSource[T] {
+	s.onEnd = fn
+	return s
+}
+
+// Dragging reports whether the payload of the source is being dragged.
+func (s *DragSource[T]) Dragging() bool {
+	return s.drag != nil
+}
+
+// detect makes the detector recognizing drags.
+func (s *DragSource[T]) detect() {
+	var rec gesture.Recognizer
+	if s.longPress {
+		rec = gesture.NewLongPress().
+			OnLongPress(s.start).
+			OnMove(s.move).
+			OnEnd(s.release)
+	} else {
+		rec = gesture.NewPan().
+			OnStart(s.start).
+			OnUpdate(func(_, p core.Point) { s.move(p) }).
+			OnEnd(func(core.Point) { s.release(s.last) })
+	}
+	s.det = gesture.NewDetector(s.child, rec)
+	s.SetChildren(s.det)
+}
+
+func (s *DragSource[T]) start(p core.Point) {
+	if s.ctx == nil || s.drag != nil {
+		return
+	}
+	s.last = p
+	s.drag = start(s.ctx, s.data(), s.export, s.child, s.preview, p, func(dropped bool) {
+		s.drag = nil
+		s.ctx.Repaint(s)
+		if s.onEnd != nil {
+			s.onEnd(dropped)
+		}
+	})
+	s.ctx.Repaint(s)
+	if s.onStart != nil {
+		s.onStart()
+	}
+}
+
+func (s *DragSource[T]) move(p core.Point) {
+	s.last = p
+	if s.drag != nil {
+		s.drag.move(p)
+	}
+}
+
+func (s *DragSource[T]) release(p core.Point) {
+	if s.drag != nil {
+		s.drag.release(p)
+	}
+}
+
+// Layout implements core.Widget.
+func (s *DragSource[T]) Layout(ctx *core.LayoutContext) core.Size {
+	s.ctx = ctx.Context
+	return ctx.Measure(s.det, ctx.Constraints)
+}
+
+// IntrinsicWidth implements core.IntrinsicSizer.
+func (s *DragSource[T]) IntrinsicWidth(ctx *core.LayoutContext, height float32) (minWidth, maxWidth float32) {
+	return ctx.IntrinsicWidth(s.det, height)
+}
+
+// IntrinsicHeight implements core.IntrinsicSizer.
+func (s *DragSource[T]) IntrinsicHeight(ctx *core.LayoutContext, width float32) (minHeight, maxHeight float32) {
+	return ctx.IntrinsicHeight(s.det, width)
+}
+
+// Baseline implements core.Baseliner with the child's baseline.
+func (s *DragSource[T]) Baseline() (float32, bool) {
+	return core.BaselineOf(s.det)
+}
+
+// SetBounds implements core.Widget.
+func (s *DragSource[T]) SetBounds(r core.Rect) {
+	s.WidgetBase.SetBounds(r)
+	s.det.SetBounds(r)
+}
+
+// Paint implements core.Widget.
+func (s *DragSource[T]) Paint(ctx *core.PaintContext) {
+	if s.drag == nil {
+		s.det.Paint(ctx)
+		return
+	}
+	pc := *ctx
+	pc.Canvas = core.Fade(ctx.Canvas, liftedAlpha)
+	s.det.Paint(&pc)
+}
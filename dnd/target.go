@@ -0,0 +1,178 @@
+package dnd
+
+import (
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/theme"
+)
+
+// DropTarget receives the payloads of type T dropped on its child. While
+// a drag it accepts is over it, it is highlighted with a tint and an
+// outline in the theme's primary color; targets that show where a drop
+// would land themselves, from OnOver, can turn that off with Highlight.
+//
+// Drags from other applications carry an *event.DragData, so a
+// DropTarget[*event.DragData] takes the files, text and images dragged
+// onto it from outside. Their content is only there once dropped, while
+// their formats are known from the start.
+type DropTarget[T any] struct {
+	core.WidgetBase
+
+	child     core.Widget
+	onDrop    func(data T, p core.Point)
+	accept    func(data T) bool
+	onEnter   func(data T)
+	onOver    func(data T, p core.Point)
+	onLeave   func()
+	highlight bool
+
+	hovered bool
+}
+
+// NewDropTarget returns child as a drop target, calling onDrop with the
+// payload and the position of the pointer, in window coordinates, when
+// one is dropped on it.
+func NewDropTarget[T any](child core.Widget, onDrop func(data T, p core.Point)) *DropTarget[T] {
+	t := &DropTarget[T]{child: child, onDrop: onDrop, highlight: true}
+	t.SetChildren(child)
+	return t
+}
+
+// Accept sets fn to decide which payloads of type T the target accepts,
+// all of them by default. Drags it refuses go to the targets around it.
+func (t *DropTarget[T]) Accept(fn func(data T) bool) *DropTarget[T] {
+	t.accept = fn
+	return t
+}
+
+// OnEnter sets fn to be called when a drag it accepts comes over the
+// target.
+func (t *DropTarget[T]) OnEnter(fn func(data T)) *DropTarget[T] {
+	t.onEnter = fn
+	return t
+}
+
+// OnOver sets fn to be called with the position of the pointer as a drag
+// it accepts moves over the target, such as for showing where in a list
+// the payload would land.
+func (t *DropTarget[T]) OnOver(fn func(data T, p core.Point)) *DropTarget[T] {
+	t.onOver = fn
+	return t
+}
+
+// OnLeave sets fn to be called when a drag leaves the target, or is
+// dropped or canceled over it.
+func (t *DropTarget[T]) OnLeave(fn func()) *DropTarget[T] {
+	t.onLeave = fn
+	return t
+}
+
+// Highlight sets whether the target is highlighted while a drag it
+// accepts is over it, which it is by default.
+func (t *DropTarget[T]) Highlight(on bool) *DropTarget[T] {
+	t.highlight = on
+	return t
+}
+
+// Hovered reports whether a drag it accepts is over the target.
+func (t *DropTarget[T]) Hovered() bool {
+	return t.hovered
+}
+
+func (t *DropTarget[T]) accepts(data any) bool {
+	v, ok := data.(T)
+	return ok && (t.accept == nil || t.accept(v))
+}
+
+func (t *DropTarget[T]) enter(ctx *core.Context, data any, p core.Point) {
+	t.hovered = true
+	ctx.Repaint(t)
+	if t.onEnter != nil {
+		t.onEnter(data.(T))
+	}
+	t.over(data, p)
+}
+
+func (t *DropTarget[T]) over(data any, p core.Point) {
+	if t.onOver != nil {
+		t.onOver(data.(T), p)
+	}
+}
+
+func (t *DropTarget[T]) leave(ctx *core.Context) {
+	t.hovered = false
+	ctx.Repaint(t)
+	if t.onLeave != nil {
+		t.onLeave()
+	}
+}
+
+func (t *DropTarget[T]) drop(ctx *core.Context, data any, p core.Point) {
+	if t.onDrop != nil {
+		t.onDrop(data.(T), p)
+	}
+}
+
+// HandleEvent implements core.Widget. It takes the drags from other
+// applications of a DropTarget[*event.DragData].
+func (t *DropTarget[T]) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	e, ok := ev.(event.DragEvent)
+	if !ok || e.Data == nil {
+		return core.Ignored
+	}
+	switch e.Type {
+	case event.DragEnter:
+		if !t.accepts(e.Data) {
+			return core.Ignored
+		}
+		t.enter(ctx, e.Data, e.Position)
+	case event.DragOver:
+		t.over(e.Data, e.Position)
+	case event.DragLeave:
+		t.leave(ctx)
+	case event.Drop:
+		t.leave(ctx)
+		t.drop(ctx, e.Data, e.Position)
+	}
+	return core.Handled
+}
+
+// Layout implements core.Widget.
+func (t *DropTarget[T]) Layout(ctx *core.LayoutContext) core.Size {
+	return ctx.Measure(t.child, ctx.Constraints)
+}
+
+// IntrinsicWidth implements core.IntrinsicSizer.
+func (t *DropTarget[T]) IntrinsicWidth(ctx *core.LayoutContext, height float32) (minWidth, maxWidth float32) {
+	return ctx.IntrinsicWidth(t.child, height)
+}
+
+// IntrinsicHeight implements core.IntrinsicSizer.
+func (t *DropTarget[T]) IntrinsicHeight(ctx *core.LayoutContext, width float32) (minHeight, maxHeight float32) {
+	return ctx.IntrinsicHeight(t.child, width)
+}
+
+// Baseline implements core.Baseliner with the child's baseline.
+func (t *DropTarget[T]) Baseline() (float32, bool) {
+	return core.BaselineOf(t.child)
+}
+
+// SetBounds implements core.Widget.
+func (t *DropTarget[T]) SetBounds(r core.Rect) {
+	t.WidgetBase.SetBounds(r)
+	t.child.SetBounds(r)
+}
+
+// Paint implements core.Widget.
+func (t *DropTarget[T]) Paint(ctx *core.PaintContext) {
+	t.child.Paint(ctx)
+	if !t.hovered || !t.highlight {
+		return
+	}
+	th := theme.From(ctx.Context)
+	ctx.Canvas.DrawRoundedRect(t.Bounds().Inset(core.UniformInsets(1)), th.Radii.Small, core.RectStyle{
+		Fill:        th.Colors.Primary.WithAlpha(0.08),
+		Stroke:      th.Colors.Primary,
+		StrokeWidth: 2,
+	})
+}
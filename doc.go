@@ -43,17 +43,34 @@
 //   - layout: VStack, HStack, Grid, Flexbox
 //   - widgets: Button, TextField, Dropdown, etc.
 //   - theme: Material 3, Fluent, Cupertino
-//   - state: Signals integration (coregx/signals)
+//   - state: Signals, observable values widgets bind to
+//   - anim: Animation controllers, tweens and curves
+//   - gesture: Tap, pan, pinch and other recognizers, and their arena
+//   - dnd: Drag and drop of typed payloads between widgets
+//   - shortcuts: Key chords bound to commands in scopes, windows and the app
+//
+// # Multiple Windows
+//
+// An App opens several top-level windows through the platform, each with
+// a root widget of its own, such as tool palettes owned by a main window:
+//
+//	app := ui.NewApp(host)
+//	main := app.Open(editor, ui.WindowConfig{Title: "Editor"})
+//	app.Open(palette, ui.WindowConfig{Title: "Tools", Kind: ui.WindowTool, Owner: main})
+//
+// App.Focus moves keyboard focus to a widget in any of the windows.
 //
 // # State Management
 //
-// Use signals for reactive state:
+// Use signals for reactive state. Widgets bound to one follow its value
+// and write the user's edits back:
 //
-//	count := signals.New(0)
+//	volume := state.New(0.5)
+//	slider := widgets.NewSlider(0, 1).Bind(volume)
+//	volume.Set(0.8) // moves the slider
 //
-//	widgets.Text(signals.Computed(func() string {
-//	    return fmt.Sprintf("Count: %d", count.Get())
-//	}))
+// See docs/ARCHITECTURE.md for why package state provides its own
+// signals rather than depending on coregx/signals.
 //
 // # Platform Support
 //
@@ -66,7 +83,9 @@
 // gogpu/ui depends on:
 //   - github.com/gogpu/gg - 2D graphics
 //   - github.com/gogpu/gogpu - Windowing
-//   - github.com/coregx/signals - State management
+//
+// State management uses package state, which needs only the standard
+// library.
 //
 // # Status
 //
@@ -0,0 +1,22 @@
+// Package event defines the input events delivered to widgets.
+//
+// Platform integrations translate native input into these types and feed
+// them to the window runtime, which routes pointer events by hit testing
+// and keyboard events to the focused widget. All positions are logical
+// pixels in window coordinates.
+//
+// Widgets receive events through core.Widget.HandleEvent and switch on the
+// concrete type:
+//
+//	func (w *MyWidget) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+//	    switch e := ev.(type) {
+//	    case event.MouseEvent:
+//	        if e.Type == event.MouseDown {
+//	            ...
+//	        }
+//	    case event.KeyEvent:
+//	        ...
+//	    }
+//	    return core.Ignored
+//	}
+package event
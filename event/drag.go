@@ -0,0 +1,113 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/gogpu/ui/core"
+)
+
+// Data formats with a meaning of their own.
+const (
+	FormatText  = "text/plain"
+	FormatFiles = "text/uri-list"
+)
+
+// ErrNoFormat is returned by DragData.Open for formats the data is not
+// offered in.
+var ErrNoFormat = errors.New("event: data not offered in format")
+
+// DragData is what a drag between applications carries, in each of the
+// formats its source offers it in.
+type DragData struct {
+	// Formats lists the MIME types the data is offered in, such as
+	// FormatText and "image/png", with FormatFiles for files.
+	Formats []string
+
+	// Files holds the paths of the files dragged, and Text the text. Of
+	// drags from other applications, they are only set for Drop.
+	Files []string
+	Text  string
+
+	// Name is a file name for the data, for targets that make a file of
+	// it, such as a desktop.
+	Name string
+
+	// Source returns a stream of the data in one of Formats, such as the
+	// bytes of an image. It may be called from any goroutine, for drags
+	// from other applications once the data is dropped.
+	Source func(format string) (io.ReadCloser, error)
+}
+
+// Has reports whether the data is offered in format.
+func (d *DragData) Has(format string) bool {
+	return slices.Contains(d.Formats, format)
+}
+
+// Open returns a stream of the data in format.
+func (d *DragData) Open(format string) (io.ReadCloser, error) {
+	if !d.Has(format) || d.Source == nil {
+		return nil, ErrNoFormat
+	}
+	return d.Source(format)
+}
+
+// DragEventType identifies the kind of drag event.
+type DragEventType uint8
+
+// Drag event types.
+const (
+	DragEnter DragEventType = iota
+	DragOver
+	DragLeave
+	Drop
+)
+
+var dragEventNames = [...]string{
+	DragEnter: "DragEnter",
+	DragOver:  "DragOver",
+	DragLeave: "DragLeave",
+	Drop:      "Drop",
+}
+
+// String returns the event type name.
+func (t DragEventType) String() string {
+	if int(t) < len(dragEventNames) {
+		return dragEventNames[t]
+	}
+	return fmt.Sprintf("DragEventType(%d)", t)
+}
+
+// DragEvent is a drag from another application, such as of files from a
+// file manager, entering the window, moving over it, leaving it or being
+// dropped on it.
+//
+// The window runtime offers DragEnter to the widgets under the pointer,
+// from the deepest up, and the one handling it becomes the drag's target:
+// it is sent DragOver as the pointer moves, and then DragLeave, or Drop
+// if the data is dropped while it is the target. The target changes as
+// the pointer moves onto widgets that handle DragEnter themselves, or off
+// the target, which is sent DragLeave once the new one has DragEnter.
+// Widgets refusing a drag are not offered it again.
+type DragEvent struct {
+	Type      DragEventType
+	Position  core.Point
+	Modifiers Modifiers
+	Data      *DragData
+}
+
+// String implements core.Event.
+func (e DragEvent) String() string {
+	var formats []string
+	if e.Data != nil {
+		formats = e.Data.Formats
+	}
+	return fmt.Sprintf("%s(%.1f,%.1f %v)", e.Type, e.Position.X, e.Position.Y, formats)
+}
+
+// PointerPosition implements core.PointerEvent.
+func (e DragEvent) PointerPosition() core.Point {
+	return e.Position
+}
@@ -0,0 +1,84 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+)
+
+func TestModifiers(t *testing.T) {
+	tests := []struct {
+		m    Modifiers
+		has  Modifiers
+		want bool
+		str  string
+	}{
+		{ModCtrl | ModShift, ModCtrl, true, "Ctrl+Shift"},
+		{ModCtrl | ModShift, ModCtrl | ModShift, true, "Ctrl+Shift"},
+		{ModCtrl, ModCtrl | ModAlt, false, "Ctrl"},
+		{ModSuper | ModAlt, ModAlt, true, "Alt+Super"},
+		{0, 0, true, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.m.Has(tt.has); got != tt.want {
+			t.Errorf("%v.Has(%v) = %v, want %v", tt.m, tt.has, got, tt.want)
+		}
+		if got := tt.m.String(); got != tt.str {
+			t.Errorf("String() = %q, want %q", got, tt.str)
+		}
+	}
+}
+
+func TestKeyString(t *testing.T) {
+	tests := []struct {
+		k    Key
+		want string
+	}{
+		{KeyA, "A"},
+		{KeyZ, "Z"},
+		{Key0, "0"},
+		{Key9, "9"},
+		{KeyF1, "F1"},
+		{KeyF12, "F12"},
+		{KeyPageUp, "PageUp"},
+		{KeyComma, ","},
+		{KeyControl, "Ctrl"},
+		{Key(60000), "Key(60000)"},
+	}
+	for _, tt := range tests {
+		if got := tt.k.String(); got != tt.want {
+			t.Errorf("Key(%d).String() = %q, want %q", tt.k, got, tt.want)
+		}
+	}
+}
+
+func TestEventStrings(t *testing.T) {
+	tests := []struct {
+		ev   core.Event
+		want string
+	}{
+		{KeyEvent{Key: KeyS, Modifiers: ModCtrl}, "KeyPress(Ctrl+S)"},
+		{KeyEvent{Type: KeyRelease, Key: KeyEnter}, "KeyRelease(Enter)"},
+		{MouseEvent{Type: MouseDown, Position: core.Pt(1, 2), Button: ButtonLeft}, "MouseDown(1.0,2.0 button=1)"},
+		{ScrollEvent{Position: core.Pt(3, 4), Delta: core.Pt(0, 10)}, "Scroll(3.0,4.0 delta=0.0,10.0)"},
+	}
+	for _, tt := range tests {
+		if got := tt.ev.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+	if got := MouseEventType(99).String(); got != "MouseEventType(99)" {
+		t.Errorf("unknown type = %q", got)
+	}
+}
+
+func TestPointerPosition(t *testing.T) {
+	var _ core.PointerEvent = MouseEvent{}
+	var _ core.PointerEvent = ScrollEvent{}
+	if got := (MouseEvent{Position: core.Pt(5, 6)}).PointerPosition(); got != core.Pt(5, 6) {
+		t.Errorf("MouseEvent position = %v", got)
+	}
+	if got := (ScrollEvent{Position: core.Pt(7, 8)}).PointerPosition(); got != core.Pt(7, 8) {
+		t.Errorf("ScrollEvent position = %v", got)
+	}
+}
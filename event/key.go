@@ -0,0 +1,240 @@
+package event
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Modifiers is a bit set of keyboard modifier keys.
+type Modifiers uint8
+
+// Modifier keys. ModSuper is the Windows key or Command on macOS.
+const (
+	ModShift Modifiers = 1 << iota
+	ModCtrl
+	ModAlt
+	ModSuper
+)
+
+// Has reports whether all modifiers in m2 are set in m.
+func (m Modifiers) Has(m2 Modifiers) bool {
+	return m&m2 == m2
+}
+
+// String returns the modifiers joined with "+", e.g. "Ctrl+Shift".
+func (m Modifiers) String() string {
+	var parts []string
+	if m.Has(ModCtrl) {
+		parts = append(parts, "Ctrl")
+	}
+	if m.Has(ModAlt) {
+		parts = append(parts, "Alt")
+	}
+	if m.Has(ModShift) {
+		parts = append(parts, "Shift")
+	}
+	if m.Has(ModSuper) {
+		parts = append(parts, "Super")
+	}
+	return strings.Join(parts, "+")
+}
+
+// Key identifies a physical key independent of layout-specific text.
+type Key uint16
+
+// Keys.
+const (
+	KeyUnknown Key = iota
+
+	KeyA
+	KeyB
+	KeyC
+	KeyD
+	KeyE
+	KeyF
+	KeyG
+	KeyH
+	KeyI
+	KeyJ
+	KeyK
+	KeyL
+	KeyM
+	KeyN
+	KeyO
+	KeyP
+	KeyQ
+	KeyR
+	KeyS
+	KeyT
+	KeyU
+	KeyV
+	KeyW
+	KeyX
+	KeyY
+	KeyZ
+
+	Key0
+	Key1
+	Key2
+	Key3
+	Key4
+	Key5
+	Key6
+	Key7
+	Key8
+	Key9
+
+	KeyEnter
+	KeyEscape
+	KeyTab
+	KeyBackspace
+	KeyDelete
+	KeyInsert
+	KeySpace
+
+	KeyLeft
+	KeyRight
+	KeyUp
+	KeyDown
+	KeyHome
+	KeyEnd
+	KeyPageUp
+	KeyPageDown
+
+	KeyF1
+	KeyF2
+	KeyF3
+	KeyF4
+	KeyF5
+	KeyF6
+	KeyF7
+	KeyF8
+	KeyF9
+	KeyF10
+	KeyF11
+	KeyF12
+
+	KeyMinus
+	KeyEqual
+	KeyComma
+	KeyPeriod
+	KeySlash
+	KeyBackslash
+	KeySemicolon
+	KeyApostrophe
+	KeyGrave
+	KeyLeftBracket
+	KeyRightBracket
+
+	KeyShift
+	KeyControl
+	KeyAlt
+	KeySuper
+	KeyMenu
+)
+
+var keyNames = map[Key]string{
+	KeyEnter: "Enter", KeyEscape: "Escape", KeyTab: "Tab", KeyBackspace: "Backspace",
+	KeyDelete: "Delete", KeyInsert: "Insert", KeySpace: "Space",
+	KeyLeft: "Left", KeyRight: "Right", KeyUp: "Up", KeyDown: "Down",
+	KeyHome: "Home", KeyEnd: "End", KeyPageUp: "PageUp", KeyPageDown: "PageDown",
+	KeyMinus: "-", KeyEqual: "=", KeyComma: ",", KeyPeriod: ".", KeySlash: "/",
+	KeyBackslash: "\\", KeySemicolon: ";", KeyApostrophe: "'", KeyGrave: "`",
+	KeyLeftBracket: "[", KeyRightBracket: "]",
+	KeyShift: "Shift", KeyControl: "Ctrl", KeyAlt: "Alt", KeySuper: "Super", KeyMenu: "Menu",
+}
+
+// String returns a human-readable key name such as "A", "F5" or "PageUp".
+func (k Key) String() string {
+	switch {
+	case k >= KeyA && k <= KeyZ:
+		return string(rune('A' + k - KeyA))
+	case k >= Key0 && k <= Key9:
+		return string(rune('0' + k - Key0))
+	case k >= KeyF1 && k <= KeyF12:
+		return fmt.Sprintf("F%d", k-KeyF1+1)
+	}
+	if name, ok := keyNames[k]; ok {
+		return name
+	}
+	return fmt.Sprintf("Key(%d)", k)
+}
+
+// KeyEventType identifies the kind of key event.
+type KeyEventType uint8
+
+// Key event types.
+const (
+	KeyPress KeyEventType = iota
+	KeyRelease
+)
+
+// KeyEvent is a key press or release. Keys held down produce repeated
+// KeyPress events with Repeat set.
+type KeyEvent struct {
+	Type      KeyEventType
+	Key       Key
+	Modifiers Modifiers
+	Repeat    bool
+}
+
+// String implements core.Event.
+func (e KeyEvent) String() string {
+	kind := "KeyPress"
+	if e.Type == KeyRelease {
+		kind = "KeyRelease"
+	}
+	if e.Modifiers != 0 {
+		return fmt.Sprintf("%s(%s+%s)", kind, e.Modifiers, e.Key)
+	}
+	return fmt.Sprintf("%s(%s)", kind, e.Key)
+}
+
+// TextEvent delivers committed text input, already translated by the
+// keyboard layout. It follows the KeyPress that produced it.
+type TextEvent struct {
+	Text string
+}
+
+// String implements core.Event.
+func (e TextEvent) String() string {
+	return fmt.Sprintf("Text(%q)", e.Text)
+}
+
+// CompositionEventType identifies the kind of composition event.
+type CompositionEventType uint8
+
+// Composition event types.
+const (
+	CompositionStart CompositionEventType = iota
+	CompositionUpdate
+	CompositionEnd
+)
+
+// CompositionEvent reports text being composed with an input method, as
+// when Chinese, Japanese or Korean is typed as several keystrokes that
+// the input method turns into characters. Text inputs show the composed
+// text at the caret until it is committed by a TextEvent following
+// CompositionEnd; a CompositionEnd without one cancels it. Key events the
+// input method consumes are not delivered.
+type CompositionEvent struct {
+	Type CompositionEventType
+
+	// Text is the text composed so far. It is empty for CompositionEnd.
+	Text string
+
+	// Cursor is the position of the input method's cursor in Text, in
+	// runes.
+	Cursor int
+}
+
+// String implements core.Event.
+func (e CompositionEvent) String() string {
+	switch e.Type {
+	case CompositionStart:
+		return fmt.Sprintf("CompositionStart(%q)", e.Text)
+	case CompositionEnd:
+		return "CompositionEnd"
+	}
+	return fmt.Sprintf("CompositionUpdate(%q, %d)", e.Text, e.Cursor)
+}
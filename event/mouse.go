@@ -0,0 +1,95 @@
+package event
+
+import (
+	"fmt"
+
+	"github.com/gogpu/ui/core"
+)
+
+// MouseEventType identifies the kind of mouse event.
+type MouseEventType uint8
+
+// Mouse event types.
+const (
+	MouseMove MouseEventType = iota
+	MouseDown
+	MouseUp
+	MouseEnter
+	MouseLeave
+)
+
+var mouseEventNames = [...]string{
+	MouseMove:  "MouseMove",
+	MouseDown:  "MouseDown",
+	MouseUp:    "MouseUp",
+	MouseEnter: "MouseEnter",
+	MouseLeave: "MouseLeave",
+}
+
+// String returns the event type name.
+func (t MouseEventType) String() string {
+	if int(t) < len(mouseEventNames) {
+		return mouseEventNames[t]
+	}
+	return fmt.Sprintf("MouseEventType(%d)", t)
+}
+
+// MouseButton identifies a mouse button.
+type MouseButton uint8
+
+// Mouse buttons.
+const (
+	ButtonNone MouseButton = iota
+	ButtonLeft
+	ButtonRight
+	ButtonMiddle
+	ButtonBack
+	ButtonForward
+)
+
+// MouseEvent is a pointer motion or button event.
+type MouseEvent struct {
+	Type      MouseEventType
+	Position  core.Point
+	Button    MouseButton
+	Modifiers Modifiers
+
+	// ClickCount is 1 for a single click, 2 for a double click and so on.
+	// It is only meaningful for MouseDown and MouseUp.
+	ClickCount int
+}
+
+// String implements core.Event.
+func (e MouseEvent) String() string {
+	return fmt.Sprintf("%s(%.1f,%.1f button=%d)", e.Type, e.Position.X, e.Position.Y, e.Button)
+}
+
+// PointerPosition implements core.PointerEvent.
+func (e MouseEvent) PointerPosition() core.Point {
+	return e.Position
+}
+
+// ScrollEvent is a mouse wheel or touchpad scroll.
+type ScrollEvent struct {
+	Position core.Point
+
+	// Delta is the scroll distance in logical pixels. Positive Y scrolls
+	// content up (the viewport moves down), as with a wheel rolled toward
+	// the user.
+	Delta     core.Point
+	Modifiers Modifiers
+
+	// Precise is true for touchpads and other high-resolution devices that
+	// report pixel deltas rather than wheel notches.
+	Precise bool
+}
+
+// String implements core.Event.
+func (e ScrollEvent) String() string {
+	return fmt.Sprintf("Scroll(%.1f,%.1f delta=%.1f,%.1f)", e.Position.X, e.Position.Y, e.Delta.X, e.Delta.Y)
+}
+
+// PointerPosition implements core.PointerEvent.
+func (e ScrollEvent) PointerPosition() core.Point {
+	return e.Position
+}
@@ -0,0 +1,58 @@
+package event
+
+import (
+	"fmt"
+
+	"github.com/gogpu/ui/core"
+)
+
+// TouchEventType identifies the kind of touch event.
+type TouchEventType uint8
+
+// Touch event types.
+const (
+	TouchDown TouchEventType = iota
+	TouchMove
+	TouchUp
+
+	// TouchCancel ends a touch the platform took over, such as for a
+	// system gesture, rather than one that was lifted.
+	TouchCancel
+)
+
+var touchEventNames = [...]string{
+	TouchDown:   "TouchDown",
+	TouchMove:   "TouchMove",
+	TouchUp:     "TouchUp",
+	TouchCancel: "TouchCancel",
+}
+
+// String returns the event type name.
+func (t TouchEventType) String() string {
+	if int(t) < len(touchEventNames) {
+		return touchEventNames[t]
+	}
+	return fmt.Sprintf("TouchEventType(%d)", t)
+}
+
+// TouchEvent is a finger, or stylus, touching the screen, moving on it or
+// leaving it. Several touches may be down at once, told apart by ID.
+type TouchEvent struct {
+	Type     TouchEventType
+	Position core.Point
+
+	// ID identifies the touch from its TouchDown to its TouchUp or
+	// TouchCancel. IDs may be reused by later touches.
+	ID        int
+	Modifiers Modifiers
+}
+
+// String implements core.Event.
+func (e TouchEvent) String() string {
+	return fmt.Sprintf("%s(%.1f,%.1f id=%d)", e.Type, e.Position.X, e.Position.Y, e.ID)
+}
+
+// PointerPosition implements core.PointerEvent.
+func (e TouchEvent) PointerPosition() core.Point {
+	return e.Position
+}
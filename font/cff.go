@@ -0,0 +1,502 @@
+package font
+
+// Bounds on Type 2 charstrings, against corrupt fonts.
+const (
+	maxCFFStack    = 48
+	maxSubrDepth   = 10
+	maxCharOps     = 1 << 16 // Operators run for one glyph.
+	maxCFFHintMask = 12      // Bytes of a hint mask, for 96 stems.
+)
+
+// cff is the CFF table of an OpenType font: the charstrings of the glyphs
+// and the subroutines they call.
+type cff struct {
+	charStrings index
+	gsubrs      index
+	subrs       index // Of a font that is not CID-keyed.
+
+	// CID-keyed fonts have a local subroutine index for each font dict,
+	// which FDSelect assigns to glyphs.
+	fdSubrs  []index
+	fdSelect data
+}
+
+// index is a CFF INDEX: a count of items of data at offsets.
+type index struct {
+	d       data
+	count   int
+	offSize int
+	base    int // Of the data, from which offsets count from 1.
+}
+
+// readIndex reads the INDEX at off in d and returns it with the offset
+// past its end.
+func readIndex(d data, off int) (index, int, bool) {
+	n := int(d.u16(off))
+	if n == 0 {
+		return index{}, off + 2, off+2 <= len(d)
+	}
+	x := index{d: d, count: n, offSize: int(d.u8(off + 2))}
+	if x.offSize < 1 || x.offSize > 4 {
+		return index{}, 0, false
+	}
+	x.base = off + 3 + (n+1)*x.offSize - 1
+	end := x.base + x.offset(n)
+	if end > len(d) || end < x.base {
+		return index{}, 0, false
+	}
+	return x, end, true
+}
+
+func (x index) offset(i int) int {
+	at := x.base + 1 - (x.count+1)*x.offSize + i*x.offSize
+	v := 0
+	for k := range x.offSize {
+		v = v<<8 | int(x.d.u8(at+k))
+	}
+	return v
+}
+
+// item returns item i, or nil if it is out of range or its offsets are
+// corrupt.
+func (x index) item(i int) data {
+	if i < 0 || i >= x.count {
+		return nil
+	}
+	start, end := x.base+x.offset(i), x.base+x.offset(i+1)
+	if start < x.base+1 || start > end || end > len(x.d) {
+		return nil
+	}
+	return x.d[start:end]
+}
+
+// bias is added to the numbers subroutines are called by.
+func (x index) bias() int {
+	switch {
+	case x.count < 1240:
+		return 107
+	case x.count < 33900:
+		return 1131
+	}
+	return 32768
+}
+
+// DICT operators.
+const (
+	opPrivate     = 18
+	opSubrs       = 19
+	opCharStrings = 17
+	opFDArray     = 0x0C00 | 36
+	opFDSelect    = 0x0C00 | 37
+)
+
+// dict reads the operators of a CFF DICT with their last operand, which
+// is all those read here take, or the two of Private.
+func dict(d data) map[int][2]int {
+	out := make(map[int][2]int)
+	var operands []int
+	for i := 0; i < len(d); {
+		b := int(d[i])
+		switch {
+		case b == 28:
+			operands = append(operands, int(d.i16(i+1)))
+			i += 3
+		case b == 29:
+			operands = append(operands, int(int32(d.u32(i+1))))
+			i += 5
+		case b == 30:
+			// Reals are read past; no operator read here takes one.
+			i++
+			for i < len(d) && d[i]&0x0F != 0x0F && d[i]&0xF0 != 0xF0 {
+				i++
+			}
+			i++
+			operands = append(operands, 0)
+		case b >= 32 && b <= 246:
+			operands = append(operands, b-139)
+			i++
+		case b >= 247 && b <= 250:
+			operands = append(operands, (b-247)*256+int(d.u8(i+1))+108)
+			i += 2
+		case b >= 251 && b <= 254:
+			operands = append(operands, -(b-251)*256-int(d.u8(i+1))-108)
+			i += 2
+		default:
+			op := b
+			i++
+			if b == 12 {
+				op = 0x0C00 | int(d.u8(i))
+				i++
+			}
+			var v [2]int
+			if n := len(operands); n >= 2 {
+				v = [2]int{operands[n-2], operands[n-1]}
+			} else if n == 1 {
+				v = [2]int{0, operands[0]}
+			}
+			out[op] = v
+			operands = operands[:0]
+		}
+	}
+	return out
+}
+
+// parseCFF reads the charstrings and subroutines of the first font of a
+// CFF table, or returns nil if it cannot.
+func parseCFF(d data) *cff {
+	if d.u8(0) != 1 {
+		return nil
+	}
+	_, off, ok := readIndex(d, int(d.u8(2))) // Names.
+	if !ok {
+		return nil
+	}
+	tops, off, ok := readIndex(d, off)
+	if !ok || tops.count == 0 {
+		return nil
+	}
+	if _, off, ok = readIndex(d, off); !ok { // Strings.
+		return nil
+	}
+	c := &cff{}
+	if c.gsubrs, _, ok = readIndex(d, off); !ok {
+		return nil
+	}
+	top := dict(tops.item(0))
+	cs, ok := top[opCharStrings]
+	if !ok {
+		return nil
+	}
+	if c.charStrings, _, ok = readIndex(d, cs[1]); !ok {
+		return nil
+	}
+	if fds, ok := top[opFDArray]; ok {
+		fdArray, _, ok := readIndex(d, fds[1])
+		if !ok {
+			return nil
+		}
+		for i := range fdArray.count {
+			c.fdSubrs = append(c.fdSubrs, privateSubrs(d, dict(fdArray.item(i))))
+		}
+		c.fdSelect = d.sub(top[opFDSelect][1])
+		return c
+	}
+	c.subrs = privateSubrs(d, top)
+	return c
+}
+
+// privateSubrs returns the local subroutines of the Private DICT of a
+// top or font DICT, whose offset is from the start of the Private DICT.
+func privateSubrs(d data, top map[int][2]int) index {
+	p, ok := top[opPrivate]
+	if !ok || p[0] < 0 || p[1] < 0 || p[1]+p[0] > len(d) {
+		return index{}
+	}
+	priv := dict(d[p[1] : p[1]+p[0]])
+	s, ok := priv[opSubrs]
+	if !ok {
+		return index{}
+	}
+	subrs, _, _ := readIndex(d, p[1]+s[1])
+	return subrs
+}
+
+// fd returns the font dict of glyph g of a CID-keyed font.
+func (c *cff) fd(g uint16) int {
+	s := c.fdSelect
+	switch s.u8(0) {
+	case 0:
+		return int(s.u8(1 + int(g)))
+	case 3:
+		n := int(s.u16(1))
+		for i := range n {
+			rec := 3 + 3*i
+			if g >= s.u16(rec) && g < s.u16(rec+3) {
+				return int(s.u8(rec + 2))
+			}
+		}
+	}
+	return -1
+}
+
+// outline runs the charstring of g, adding its contours to o.
+func (c *cff) outline(o *outliner, g uint16) bool {
+	cs := c.charStrings.item(int(g))
+	if cs == nil {
+		return false
+	}
+	subrs := c.subrs
+	if c.fdSubrs != nil {
+		fd := c.fd(g)
+		if fd < 0 || fd >= len(c.fdSubrs) {
+			return false
+		}
+		subrs = c.fdSubrs[fd]
+	}
+	r := charRunner{o: o, gsubrs: c.gsubrs, subrs: subrs}
+	if !r.run(cs, 0) {
+		return false
+	}
+	o.close()
+	return true
+}
+
+// charRunner interprets Type 2 charstrings.
+type charRunner struct {
+	o             *outliner
+	gsubrs, subrs index
+
+	stack []float32
+	x, y  float32
+	stems int
+	width bool // The optional width before the first operator is read.
+	ops   int
+	ended bool
+}
+
+// run runs cs, a charstring or a subroutine called at depth, and reports
+// whether it ran without error.
+func (r *charRunner) run(cs data, depth int) bool {
+	if depth > maxSubrDepth {
+		return false
+	}
+	for i := 0; i < len(cs) && !r.ended; {
+		b := int(cs[i])
+		if b >= 32 || b == 28 {
+			var v float32
+			switch {
+			case b == 28:
+				v = float32(cs.i16(i + 1))
+				i += 3
+			case b <= 246:
+				v = float32(b - 139)
+				i++
+			case b <= 250:
+				v = float32((b-247)*256 + int(cs.u8(i+1)) + 108)
+				i += 2
+			case b <= 254:
+				v = float32(-(b-251)*256 - int(cs.u8(i+1)) - 108)
+				i += 2
+			default:
+				v = float32(int32(cs.u32(i+1))) / 65536
+				i += 5
+			}
+			if len(r.stack) >= maxCFFStack {
+				return false
+			}
+			r.stack = append(r.stack, v)
+			continue
+		}
+		if r.ops++; r.ops > maxCharOps {
+			return false
+		}
+		i++
+		switch b {
+		case 1, 3, 18, 23: // hstem, vstem, hstemhm, vstemhm.
+			r.takeWidth(len(r.stack)%2 == 1)
+			r.stems += len(r.stack) / 2
+		case 19, 20: // hintmask, cntrmask.
+			r.takeWidth(len(r.stack)%2 == 1)
+			r.stems += len(r.stack) / 2
+			n := (r.stems + 7) / 8
+			if n > maxCFFHintMask {
+				return false
+			}
+			i += n
+		case 21: // rmoveto.
+			r.takeWidth(len(r.stack) > 2)
+			r.moveTo(r.arg(0), r.arg(1))
+		case 22: // hmoveto.
+			r.takeWidth(len(r.stack) > 1)
+			r.moveTo(r.arg(0), 0)
+		case 4: // vmoveto.
+			r.takeWidth(len(r.stack) > 1)
+			r.moveTo(0, r.arg(0))
+		case 5: // rlineto.
+			for k := 0; k+1 < len(r.stack); k += 2 {
+				r.lineTo(r.stack[k], r.stack[k+1])
+			}
+		case 6, 7: // hlineto, vlineto.
+			horizontal := b == 6
+			for _, v := range r.stack {
+				if horizontal {
+					r.lineTo(v, 0)
+				} else {
+					r.lineTo(0, v)
+				}
+				horizontal = !horizontal
+			}
+		case 8: // rrcurveto.
+			for k := 0; k+5 < len(r.stack); k += 6 {
+				r.curve(r.stack[k:])
+			}
+		case 24: // rcurveline.
+			k := 0
+			for ; k+7 < len(r.stack); k += 6 {
+				r.curve(r.stack[k:])
+			}
+			if k+1 < len(r.stack) {
+				r.lineTo(r.stack[k], r.stack[k+1])
+			}
+		case 25: // rlinecurve.
+			k := 0
+			for ; k+7 < len(r.stack); k += 2 {
+				r.lineTo(r.stack[k], r.stack[k+1])
+			}
+			if k+5 < len(r.stack) {
+				r.curve(r.stack[k:])
+			}
+		case 26, 27: // vvcurveto, hhcurveto.
+			s := r.stack
+			var d1 float32
+			if len(s)%2 == 1 {
+				d1, s = s[0], s[1:]
+			}
+			for k := 0; k+3 < len(s); k += 4 {
+				if b == 26 {
+					r.curve([]float32{d1, s[k], s[k+1], s[k+2], 0, s[k+3]})
+				} else {
+					r.curve([]float32{s[k], d1, s[k+1], s[k+2], s[k+3], 0})
+				}
+				d1 = 0
+			}
+		case 30, 31: // vhcurveto, hvcurveto.
+			horizontal := b == 31
+			s := r.stack
+			for k := 0; k+3 < len(s); k += 4 {
+				var last float32
+				if len(s)-k == 5 {
+					last = s[k+4]
+				}
+				if horizontal {
+					r.curve([]float32{s[k], 0, s[k+1], s[k+2], last, s[k+3]})
+				} else {
+					r.curve([]float32{0, s[k], s[k+1], s[k+2], s[k+3], last})
+				}
+				horizontal = !horizontal
+			}
+		case 10, 29: // callsubr, callgsubr.
+			if len(r.stack) == 0 {
+				return false
+			}
+			subrs := r.subrs
+			if b == 29 {
+				subrs = r.gsubrs
+			}
+			n := int(r.stack[len(r.stack)-1]) + subrs.bias()
+			r.stack = r.stack[:len(r.stack)-1]
+			sub := subrs.item(n)
+			if sub == nil || !r.run(sub, depth+1) {
+				return false
+			}
+			continue
+		case 11: // return.
+			return true
+		case 14: // endchar.
+			r.takeWidth(len(r.stack) == 1 || len(r.stack) == 5)
+			r.ended = true
+		case 12:
+			if !r.flex(cs.u8(i)) {
+				return false
+			}
+			i++
+		default:
+			return false
+		}
+		r.stack = r.stack[:0]
+	}
+	return true
+}
+
+// takeWidth drops the width before the first stack-clearing operator,
+// where extra says the operator has one more operand than it takes.
+func (r *charRunner) takeWidth(extra bool) {
+	if !r.width {
+		r.width = true
+		if extra && len(r.stack) > 0 {
+			r.stack = r.stack[1:]
+		}
+	}
+}
+
+func (r *charRunner) arg(i int) float32 {
+	if i < len(r.stack) {
+		return r.stack[i]
+	}
+	return 0
+}
+
+func (r *charRunner) moveTo(dx, dy float32) {
+	r.x += dx
+	r.y += dy
+	r.o.moveTo(r.x, r.y)
+}
+
+func (r *charRunner) lineTo(dx, dy float32) {
+	r.x += dx
+	r.y += dy
+	r.o.lineTo(r.x, r.y)
+}
+
+// curve adds a cubic curve of the six relative coordinates of s.
+func (r *charRunner) curve(s []float32) {
+	x1, y1 := r.x+s[0], r.y+s[1]
+	x2, y2 := x1+s[2], y1+s[3]
+	r.x, r.y = x2+s[4], y2+s[5]
+	r.o.cubeTo(x1, y1, x2, y2, r.x, r.y)
+}
+
+// flex runs the flex operators, which draw two curves, and reports
+// whether op is one of them.
+func (r *charRunner) flex(op byte) bool {
+	s := r.stack
+	y0 := r.y
+	switch op {
+	case 35: // flex.
+		if len(s) < 12 {
+			return false
+		}
+		r.curve(s[0:6])
+		r.curve(s[6:12])
+	case 34: // hflex.
+		if len(s) < 7 {
+			return false
+		}
+		r.curve([]float32{s[0], 0, s[1], s[2], s[3], 0})
+		r.curve([]float32{s[4], 0, s[5], y0 - r.y, s[6], 0})
+	case 36: // hflex1.
+		if len(s) < 9 {
+			return false
+		}
+		r.curve([]float32{s[0], s[1], s[2], s[3], s[4], 0})
+		r.curve([]float32{s[5], 0, s[6], s[7], s[8], y0 - r.y - s[7]})
+	case 37: // flex1.
+		if len(s) < 11 {
+			return false
+		}
+		x0 := r.x
+		var dx, dy float32
+		for k := 0; k < 10; k += 2 {
+			dx += s[k]
+			dy += s[k+1]
+		}
+		r.curve(s[0:6])
+		x1, y1 := r.x+s[6], r.y+s[7]
+		x2, y2 := x1+s[8], y1+s[9]
+		if dx < 0 {
+			dx = -dx
+		}
+		if dy < 0 {
+			dy = -dy
+		}
+		if dx > dy {
+			r.x, r.y = x2+s[10], y0
+		} else {
+			r.x, r.y = x0, y2+s[10]
+		}
+		r.o.cubeTo(x1, y1, x2, y2, r.x, r.y)
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,364 @@
+package font
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"image/png"
+
+	"github.com/gogpu/ui/core"
+)
+
+// maxPaintDepth bounds the nesting of COLR paint tables, against fonts
+// whose paint graph loops.
+const maxPaintDepth = 16
+
+// Layer is one layer of a color glyph: the outline of Glyph filled with
+// Color. Layers are drawn in order, the first at the bottom.
+type Layer struct {
+	Glyph uint16
+	Color core.Color
+
+	// Foreground is set for a layer drawn in the color of the text, which
+	// Color then does not give.
+	Foreground bool
+}
+
+// Bitmap is a color glyph stored as an image at PPEM pixels per em, as
+// in bitmap emoji fonts. Bearing is the offset from the pen position on
+// the baseline to the top left corner of the image, in pixels of the
+// image with y down; text of size pixels per em draws the image scaled
+// by size/PPEM.
+type Bitmap struct {
+	Image   image.Image
+	PPEM    int
+	Bearing core.Point
+}
+
+// HasColor reports whether the font has color glyphs: COLR layers, or
+// CBDT or sbix bitmaps.
+func (f *Font) HasColor() bool {
+	return f.colr != nil || f.cbdt != nil || f.sbix != nil
+}
+
+// Palettes returns the number of color palettes of the font, from which
+// Layers takes its colors. Palette 0 is the default.
+func (f *Font) Palettes() int {
+	return int(f.cpal.u16(4))
+}
+
+// Layers returns the layers of color glyph g with colors from palette,
+// and false if g is not a color glyph. Glyphs of COLR version 1 are
+// reduced to layers when their paint is a stack of filled outlines, with
+// a gradient fill taking the average of its stops; glyphs transformed or
+// composited in the paint graph report false, leaving the backend to use
+// a bitmap or the plain outline.
+func (f *Font) Layers(g uint16, palette int) ([]Layer, bool) {
+	c := f.colr
+	if c == nil {
+		return nil, false
+	}
+	if c.u16(0) >= 1 {
+		if paint := f.basePaint(g); paint != nil {
+			var layers []Layer
+			if f.paintLayers(paint, palette, 0, &layers) && len(layers) > 0 {
+				return layers, true
+			}
+			return nil, false
+		}
+	}
+	n := int(c.u16(2))
+	base, recs := c.sub(int(c.u32(4))), c.sub(int(c.u32(8)))
+	lo, hi := 0, n
+	for lo < hi {
+		m := (lo + hi) / 2
+		switch id := base.u16(6 * m); {
+		case id < g:
+			lo = m + 1
+		case id > g:
+			hi = m
+		default:
+			first, count := int(base.u16(6*m+2)), int(base.u16(6*m+4))
+			layers := make([]Layer, 0, count)
+			for i := first; i < first+count; i++ {
+				layer := Layer{Glyph: recs.u16(4 * i)}
+				layer.Color, layer.Foreground = f.color(palette, recs.u16(4*i+2), 1)
+				layers = append(layers, layer)
+			}
+			return layers, count > 0
+		}
+	}
+	return nil, false
+}
+
+// basePaint returns the root paint of g in the base glyph list of COLR
+// version 1, or nil.
+func (f *Font) basePaint(g uint16) data {
+	list := f.colr.sub(int(f.colr.u32(14)))
+	lo, hi := 0, int(list.u32(0))
+	for lo < hi {
+		m := (lo + hi) / 2
+		switch id := list.u16(4 + 6*m); {
+		case id < g:
+			lo = m + 1
+		case id > g:
+			hi = m
+		default:
+			return list.sub(int(list.u32(4 + 6*m + 2)))
+		}
+	}
+	return nil
+}
+
+// paintLayers flattens the paint p into layers, reporting false for paint
+// that layers cannot express.
+func (f *Font) paintLayers(p data, palette, depth int, out *[]Layer) bool {
+	if p == nil || depth > maxPaintDepth {
+		return false
+	}
+	switch p.u8(0) {
+	case 1: // PaintColrLayers.
+		list := f.colr.sub(int(f.colr.u32(18)))
+		first, n := int(p.u32(2)), int(p.u8(1))
+		for i := first; i < first+n; i++ {
+			if !f.paintLayers(list.sub(int(list.u32(4+4*i))), palette, depth+1, out) {
+				return false
+			}
+		}
+		return true
+	case 10: // PaintGlyph.
+		layer := Layer{Glyph: p.u16(4)}
+		var ok bool
+		if layer.Color, layer.Foreground, ok = f.fill(p.sub(int(p.u24(1))), palette); !ok {
+			return false
+		}
+		*out = append(*out, layer)
+		return true
+	case 11: // PaintColrGlyph.
+		return f.paintLayers(f.basePaint(p.u16(1)), palette, depth+1, out)
+	}
+	return false
+}
+
+// fill returns the color of a fill paint.
+func (f *Font) fill(p data, palette int) (c core.Color, foreground, ok bool) {
+	switch format := p.u8(0); format {
+	case 2, 3: // PaintSolid, PaintVarSolid.
+		c, foreground = f.color(palette, p.u16(1), f2dot14(p.i16(3)))
+		return c, foreground, true
+	case 4, 5, 6, 7, 8, 9: // Linear, radial and sweep gradients.
+		line := p.sub(int(p.u24(1)))
+		n, size := int(line.u16(1)), 6
+		if format%2 == 1 {
+			size = 10 // VarColorStop.
+		}
+		if n == 0 {
+			return c, false, false
+		}
+		for i := range n {
+			stop := 3 + size*i
+			sc, fg := f.color(palette, line.u16(stop+2), f2dot14(line.i16(stop+4)))
+			if fg {
+				return c, true, true
+			}
+			c.R, c.G, c.B, c.A = c.R+sc.R, c.G+sc.G, c.B+sc.B, c.A+sc.A
+		}
+		k := 1 / float32(n)
+		return core.Color{R: c.R * k, G: c.G * k, B: c.B * k, A: c.A * k}, false, true
+	}
+	return c, false, false
+}
+
+func f2dot14(v int16) float32 {
+	return float32(v) / (1 << 14)
+}
+
+// color returns entry i of palette with its alpha scaled by alpha, and
+// whether i is the text color.
+func (f *Font) color(palette int, i uint16, alpha float32) (core.Color, bool) {
+	if i == 0xFFFF {
+		return core.Color{}, true
+	}
+	p := f.cpal
+	if palette < 0 || palette >= int(p.u16(4)) {
+		palette = 0
+	}
+	if int(i) >= int(p.u16(2)) {
+		return core.Black, false
+	}
+	rec := int(p.u32(8)) + 4*(int(p.u16(12+2*palette))+int(i))
+	c := core.RGBA(p.u8(rec+2), p.u8(rec+1), p.u8(rec), p.u8(rec+3))
+	c.A *= min(max(alpha, 0), 1)
+	return c, false
+}
+
+// Bitmap returns the color bitmap of glyph g from the strike that best
+// serves text of size pixels per em, the smallest at least that large or
+// else the largest, and false if the font has no bitmap for g. PNG and
+// JPEG images are decoded; other formats report false.
+func (f *Font) Bitmap(g uint16, size float32) (Bitmap, bool) {
+	if b, ok := f.cbdtBitmap(g, size); ok {
+		return b, true
+	}
+	return f.sbixBitmap(g, size, 0)
+}
+
+// pickStrike returns the index of the best of the strikes of ppems for
+// size pixels per em, ignoring those of ppem 0, or -1.
+func pickStrike(ppems []int, size float32) int {
+	best := -1
+	for i, p := range ppems {
+		switch {
+		case p == 0:
+		case best < 0:
+			best = i
+		case float32(ppems[best]) < size && p > ppems[best]:
+			best = i
+		case float32(p) >= size && p < ppems[best]:
+			best = i
+		}
+	}
+	return best
+}
+
+func (f *Font) cbdtBitmap(g uint16, size float32) (Bitmap, bool) {
+	c := f.cblc
+	if c == nil || f.cbdt == nil {
+		return Bitmap{}, false
+	}
+	n := int(c.u32(4))
+	ppems := make([]int, n)
+	for i := range n {
+		rec := 8 + 48*i
+		if g >= c.u16(rec+40) && g <= c.u16(rec+42) {
+			ppems[i] = int(c.u8(rec + 45))
+		}
+	}
+	i := pickStrike(ppems, size)
+	if i < 0 {
+		return Bitmap{}, false
+	}
+	rec := 8 + 48*i
+	array := c.sub(int(c.u32(rec)))
+	for k := range int(c.u32(rec + 8)) {
+		first, last := array.u16(8*k), array.u16(8*k+2)
+		if g < first || g > last {
+			continue
+		}
+		off, length, format, metrics, ok := indexEntry(array.sub(int(array.u32(8*k+4))), g, first)
+		if !ok {
+			return Bitmap{}, false
+		}
+		glyph := f.cbdt.sub(off)
+		if length > 0 && length < len(glyph) {
+			glyph = glyph[:length]
+		}
+		return cbdtImage(glyph, format, metrics, ppems[i])
+	}
+	return Bitmap{}, false
+}
+
+// indexEntry finds glyph g in an index subtable of CBLC, whose first
+// glyph is first. It returns the offset and length of the glyph's data in
+// CBDT, the image format, and the glyph metrics for formats that keep
+// them in the index.
+func indexEntry(st data, g, first uint16) (off, length int, format uint16, metrics data, ok bool) {
+	format = st.u16(2)
+	base := int(st.u32(4))
+	i := int(g - first)
+	switch st.u16(0) {
+	case 1:
+		a, b := int(st.u32(8+4*i)), int(st.u32(8+4*i+4))
+		return base + a, b - a, format, nil, b > a
+	case 2:
+		size := int(st.u32(8))
+		return base + size*i, size, format, st.sub(12), true
+	case 3:
+		a, b := int(st.u16(8+2*i)), int(st.u16(8+2*i+2))
+		return base + a, b - a, format, nil, b > a
+	case 4:
+		for k := range int(st.u32(8)) {
+			if st.u16(12+4*k) == g {
+				a, b := int(st.u16(12+4*k+2)), int(st.u16(12+4*k+6))
+				return base + a, b - a, format, nil, b > a
+			}
+		}
+	case 5:
+		size := int(st.u32(8))
+		for k := range int(st.u32(20)) {
+			if st.u16(24+2*k) == g {
+				return base + size*k, size, format, st.sub(12), true
+			}
+		}
+	}
+	return 0, 0, 0, nil, false
+}
+
+// cbdtImage decodes the glyph data of CBDT image format 17, 18 or 19.
+func cbdtImage(glyph data, format uint16, metrics data, ppem int) (Bitmap, bool) {
+	var img data
+	switch format {
+	case 17: // Small metrics, then the PNG.
+		metrics, img = glyph, glyph.sub(9)
+		img = img[:min(len(img), int(glyph.u32(5)))]
+	case 18: // Big metrics, then the PNG.
+		metrics, img = glyph, glyph.sub(12)
+		img = img[:min(len(img), int(glyph.u32(8)))]
+	case 19: // The PNG alone, with big metrics in the index.
+		img = glyph.sub(4)
+		img = img[:min(len(img), int(glyph.u32(0)))]
+	default:
+		return Bitmap{}, false
+	}
+	im, err := png.Decode(bytes.NewReader(img))
+	if err != nil {
+		return Bitmap{}, false
+	}
+	bearing := core.Pt(float32(int8(metrics.u8(2))), -float32(int8(metrics.u8(3))))
+	return Bitmap{Image: im, PPEM: ppem, Bearing: bearing}, true
+}
+
+func (f *Font) sbixBitmap(g uint16, size float32, depth int) (Bitmap, bool) {
+	s := f.sbix
+	if s == nil || int(g) >= f.numGlyphs || depth > 1 {
+		return Bitmap{}, false
+	}
+	n := int(s.u32(4))
+	ppems := make([]int, n)
+	for i := range n {
+		st := s.sub(int(s.u32(8 + 4*i)))
+		if st.u32(4+4*int(g)+4) > st.u32(4+4*int(g)) {
+			ppems[i] = int(st.u16(0))
+		}
+	}
+	i := pickStrike(ppems, size)
+	if i < 0 {
+		return Bitmap{}, false
+	}
+	st := s.sub(int(s.u32(8 + 4*i)))
+	a, b := int(st.u32(4+4*int(g))), int(st.u32(4+4*int(g)+4))
+	glyph := st.sub(a)
+	if b-a < 8 || len(glyph) < b-a {
+		return Bitmap{}, false
+	}
+	glyph = glyph[:b-a]
+	var im image.Image
+	var err error
+	switch glyph.tag(4) {
+	case "png ":
+		im, err = png.Decode(bytes.NewReader(glyph[8:]))
+	case "jpg ":
+		im, err = jpeg.Decode(bytes.NewReader(glyph[8:]))
+	case "dupe":
+		return f.sbixBitmap(glyph.u16(8), size, depth+1)
+	default:
+		return Bitmap{}, false
+	}
+	if err != nil {
+		return Bitmap{}, false
+	}
+	// The origin offsets place the bottom left corner of the image, y up.
+	h := float32(im.Bounds().Dy())
+	bearing := core.Pt(float32(glyph.i16(0)), -float32(glyph.i16(2))-h)
+	return Bitmap{Image: im, PPEM: ppems[i], Bearing: bearing}, true
+}
@@ -0,0 +1,229 @@
+package font
+
+// Contextual lookups (GSUB 5 and 6, GPOS 7 and 8) share their formats:
+// they match a sequence of glyphs, with optional backtrack and lookahead
+// context, by glyph, by class or by coverage, then apply nested lookups
+// at positions of the matched input.
+
+// matcher tests whether a glyph matches the value at index i of a rule's
+// sequence.
+type matcher func(g uint16, i int) bool
+
+// context applies a contextual or chained contextual subtable st at
+// buffer position i. chained selects the chained format. It returns
+// whether a rule matched and its lookups were applied.
+func (s *shaper) context(st data, i int, lk *lookup, chained bool) bool {
+	g := s.buf[i].id
+	switch st.u16(0) {
+	case 1:
+		ci := st.sub(int(st.u16(2))).coverage(g)
+		if ci < 0 || ci >= int(st.u16(4)) {
+			return false
+		}
+		set := st.sub(int(st.u16(6 + 2*ci)))
+		for r := range int(set.u16(0)) {
+			rule := set.sub(int(set.u16(2 + 2*r)))
+			byGlyph := func(seq int) matcher {
+				return func(g uint16, k int) bool { return rule.u16(seq+2*k) == g }
+			}
+			if s.applyRule(rule, i, lk, chained, byGlyph, byGlyph, byGlyph) {
+				return true
+			}
+		}
+	case 2:
+		if st.sub(int(st.u16(2))).coverage(g) < 0 {
+			return false
+		}
+		var back, input, ahead data
+		sets := 4
+		if chained {
+			back, input, ahead = st.sub(int(st.u16(4))), st.sub(int(st.u16(6))), st.sub(int(st.u16(8)))
+			sets = 10
+		} else {
+			input = st.sub(int(st.u16(4)))
+			sets = 6
+		}
+		c := int(input.class(g))
+		if c >= int(st.u16(sets)) {
+			return false
+		}
+		set := st.sub(int(st.u16(sets + 2 + 2*c)))
+		for r := range int(set.u16(0)) {
+			rule := set.sub(int(set.u16(2 + 2*r)))
+			byClass := func(cd data) func(seq int) matcher {
+				return func(seq int) matcher {
+					return func(g uint16, k int) bool { return rule.u16(seq+2*k) == cd.class(g) }
+				}
+			}
+			if s.applyRule(rule, i, lk, chained, byClass(back), byClass(input), byClass(ahead)) {
+				return true
+			}
+		}
+	case 3:
+		return s.applyCoverageRule(st, i, lk, chained)
+	}
+	return false
+}
+
+// applyRule matches a format 1 or 2 rule at i, given the matchers for
+// its backtrack, input and lookahead sequences, which start at the
+// offsets passed to them, and applies its lookups.
+func (s *shaper) applyRule(rule data, i int, lk *lookup, chained bool, back, input, ahead func(int) matcher) bool {
+	off := 0
+	var nBack, nIn, nAhead int
+	var backSeq, inSeq, aheadSeq int
+	if chained {
+		nBack = int(rule.u16(0))
+		backSeq = 2
+		off = 2 + 2*nBack
+	}
+	nIn = int(rule.u16(off))
+	off += 2
+	records := 0
+	if !chained {
+		records = int(rule.u16(off))
+		off += 2
+	}
+	inSeq = off
+	off += 2 * max(0, nIn-1)
+	if chained {
+		nAhead = int(rule.u16(off))
+		aheadSeq = off + 2
+		off += 2 + 2*nAhead
+		records = int(rule.u16(off))
+		off += 2
+	}
+	if nIn == 0 {
+		return false
+	}
+	// The first input glyph is matched by the coverage or class already;
+	// the sequence holds the rest.
+	in := input(inSeq)
+	pos, ok := s.matchInput(i, nIn, lk, func(g uint16, k int) bool { return in(g, k-1) })
+	if !ok {
+		return false
+	}
+	if nBack > 0 && !s.matchBack(i, nBack, lk, back(backSeq)) {
+		return false
+	}
+	if nAhead > 0 && !s.matchAhead(pos[len(pos)-1], nAhead, lk, ahead(aheadSeq)) {
+		return false
+	}
+	s.applyRecords(rule[off:], records, pos)
+	return true
+}
+
+// applyCoverageRule applies a format 3 subtable, whose sequences are
+// coverage tables.
+func (s *shaper) applyCoverageRule(st data, i int, lk *lookup, chained bool) bool {
+	cov := func(base int) matcher {
+		return func(g uint16, k int) bool { return st.sub(int(st.u16(base+2*k))).coverage(g) >= 0 }
+	}
+	off := 2
+	var nBack, backSeq int
+	if chained {
+		nBack = int(st.u16(off))
+		backSeq = off + 2
+		off += 2 + 2*nBack
+	}
+	nIn := int(st.u16(off))
+	records := 0
+	if chained {
+		off += 2
+	} else {
+		records = int(st.u16(off + 2))
+		off += 4
+	}
+	inSeq := off
+	off += 2 * nIn
+	var nAhead, aheadSeq int
+	if chained {
+		nAhead = int(st.u16(off))
+		aheadSeq = off + 2
+		off += 2 + 2*nAhead
+		records = int(st.u16(off))
+		off += 2
+	}
+	if nIn == 0 || !cov(inSeq)(s.buf[i].id, 0) {
+		return false
+	}
+	pos, ok := s.matchInput(i, nIn, lk, cov(inSeq))
+	if !ok {
+		return false
+	}
+	if nBack > 0 && !s.matchBack(i, nBack, lk, cov(backSeq)) {
+		return false
+	}
+	if nAhead > 0 && !s.matchAhead(pos[len(pos)-1], nAhead, lk, cov(aheadSeq)) {
+		return false
+	}
+	s.applyRecords(st[off:], records, pos)
+	return true
+}
+
+// matchInput matches n input glyphs from i, skipping the glyphs lk
+// ignores, and returns their positions. The glyph at i is not tested.
+func (s *shaper) matchInput(i, n int, lk *lookup, m matcher) ([]int, bool) {
+	pos := make([]int, 0, n)
+	pos = append(pos, i)
+	j := i
+	for k := 1; k < n; k++ {
+		j = s.next(j, lk)
+		if j < 0 || !m(s.buf[j].id, k) {
+			return nil, false
+		}
+		pos = append(pos, j)
+	}
+	return pos, true
+}
+
+// matchBack matches n backtrack glyphs before i, nearest first.
+func (s *shaper) matchBack(i, n int, lk *lookup, m matcher) bool {
+	j := i
+	for k := range n {
+		j = s.prev(j, lk)
+		if j < 0 || !m(s.buf[j].id, k) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchAhead matches n lookahead glyphs after i.
+func (s *shaper) matchAhead(i, n int, lk *lookup, m matcher) bool {
+	j := i
+	for k := range n {
+		j = s.next(j, lk)
+		if j < 0 || !m(s.buf[j].id, k) {
+			return false
+		}
+	}
+	return true
+}
+
+// applyRecords applies the n nested lookup records in rec at the matched
+// positions, following the changes in length that substitutions make.
+func (s *shaper) applyRecords(rec data, n int, pos []int) {
+	if s.depth >= maxNesting {
+		return
+	}
+	s.depth++
+	defer func() { s.depth-- }()
+	for r := range n {
+		seq, li := int(rec.u16(4*r)), rec.u16(4*r+2)
+		if seq >= len(pos) {
+			continue
+		}
+		at := pos[seq]
+		before := len(s.buf)
+		nested := s.table.lookup(li)
+		s.applyAt(&nested, at)
+		if delta := len(s.buf) - before; delta != 0 {
+			for k := range pos {
+				if pos[k] > at {
+					pos[k] += delta
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package font
+
+import (
+	"io"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/gogpu/ui/core"
+)
+
+// Face is one face of a font file: a family in one weight and style, or
+// in the range of weights, widths and other axes of a variable font. The
+// rendering backend loads the face from Path, or Data for fonts added from
+// memory, and Index within it for collections.
+type Face struct {
+	Family string
+	Style  string
+	Weight core.FontWeight
+	Italic bool
+
+	// Axes and Instances are the axes and named instances of a variable
+	// font, for design tools to offer as sliders and presets.
+	Axes      []Axis
+	Instances []Instance
+
+	Path  string
+	Data  []byte
+	Index int
+
+	src    io.ReaderAt
+	tables map[uint32]table
+
+	once   sync.Once
+	ranges []runeRange
+}
+
+// Covers reports whether the face has a glyph for r. The character map is
+// read on first use.
+func (f *Face) Covers(r rune) bool {
+	f.once.Do(f.loadCmap)
+	i := sort.Search(len(f.ranges), func(i int) bool { return f.ranges[i].hi >= r })
+	return i < len(f.ranges) && f.ranges[i].lo <= r
+}
+
+func (f *Face) loadCmap() {
+	t, ok := f.tables[tagCmap]
+	if !ok {
+		return
+	}
+	src := f.src
+	if src == nil {
+		file, err := os.Open(f.Path)
+		if err != nil {
+			return
+		}
+		defer file.Close()
+		src = file
+	}
+	rd := &reader{r: src}
+	b, err := rd.read(int64(t.offset), int(t.length))
+	if err != nil {
+		return
+	}
+	f.ranges = parseCmap(b)
+	f.tables = nil
+}
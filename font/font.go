@@ -0,0 +1,247 @@
+package font
+
+import (
+	"os"
+	"sort"
+)
+
+// Font is a face loaded for shaping and drawing: its character map, the
+// advances and outlines of its glyphs, kerning and OpenType layout
+// tables, the color glyphs of emoji fonts, and the axes of variable
+// fonts, at the values set with Vary.
+type Font struct {
+	upem      float32
+	numGlyphs int
+
+	cmap     data
+	hmtx     data
+	hmetrics int
+	kern     data
+
+	glyf, loca data
+	longLoca   bool
+	cff        *cff
+
+	glyphClass data
+	markClass  data
+	markSets   data
+
+	gsub *layout
+	gpos *layout
+
+	colr, cpal data
+	cblc, cbdt data
+	sbix       data
+
+	axes       []Axis
+	instances  []Instance
+	avar, hvar data
+	values     []float32 // The value of each axis, nil for the defaults.
+	coords     []float32 // The normalized values, nil for the default instance.
+}
+
+// Load reads the face's font file, or its data, and prepares it for
+// shaping.
+func (f *Face) Load() (*Font, error) {
+	b := f.Data
+	if b == nil {
+		var err error
+		if b, err = os.ReadFile(f.Path); err != nil {
+			return nil, err
+		}
+	}
+	return ParseFont(b, f.Index)
+}
+
+// ParseFont prepares the face at index of a TrueType or OpenType font or
+// collection for shaping. Index is 0 for single fonts.
+func ParseFont(b []byte, index int) (*Font, error) {
+	d := data(b)
+	off := 0
+	if d.u32(0) == tagCollection {
+		if index < 0 || index >= int(d.u32(8)) {
+			return nil, ErrFormat
+		}
+		off = int(d.u32(12 + 4*index))
+	}
+	switch d.u32(off) {
+	case tagTrueType, tagOpenType, tagTrue:
+	default:
+		return nil, ErrFormat
+	}
+	tables := make(map[string]data)
+	for i := range int(d.u16(off + 4)) {
+		rec := off + 12 + 16*i
+		start, length := int(d.u32(rec+8)), int(d.u32(rec+12))
+		if start < 0 || length < 0 || start+length > len(d) {
+			continue
+		}
+		tables[d.tag(rec)] = d[start : start+length]
+	}
+	head, hhea, maxp := tables["head"], tables["hhea"], tables["maxp"]
+	if head == nil || hhea == nil || maxp == nil {
+		return nil, ErrFormat
+	}
+	f := &Font{
+		upem:      float32(head.u16(18)),
+		numGlyphs: int(maxp.u16(4)),
+		hmtx:      tables["hmtx"],
+		hmetrics:  int(hhea.u16(34)),
+		cmap:      bestCmap(tables["cmap"]),
+	}
+	if f.upem == 0 {
+		f.upem = 1000
+	}
+	if tables["loca"] != nil {
+		f.glyf, f.loca = tables["glyf"], tables["loca"]
+		f.longLoca = head.i16(50) == 1
+	} else if t := tables["CFF "]; t != nil {
+		f.cff = parseCFF(t)
+	}
+	if k := tables["kern"]; k.u16(0) == 0 && k.u16(2) > 0 {
+		// The first subtable, if it is a horizontal format 0 one.
+		if k.u16(8)>>8 == 0 && k.u16(8)&1 != 0 {
+			f.kern = k.sub(4)
+		}
+	}
+	if gdef := tables["GDEF"]; gdef != nil {
+		f.glyphClass = gdef.sub(int(gdef.u16(4)))
+		f.markClass = gdef.sub(int(gdef.u16(10)))
+		if gdef.u32(0) >= 0x00010002 {
+			f.markSets = gdef.sub(int(gdef.u16(12)))
+		}
+	}
+	if t := tables["GSUB"]; t != nil {
+		f.gsub = parseLayout(t, false)
+	}
+	if t := tables["GPOS"]; t != nil {
+		f.gpos = parseLayout(t, true)
+	}
+	if tables["CPAL"] != nil {
+		f.colr, f.cpal = tables["COLR"], tables["CPAL"]
+	}
+	if tables["CBLC"] != nil {
+		f.cblc, f.cbdt = tables["CBLC"], tables["CBDT"]
+	}
+	f.sbix = tables["sbix"]
+	if t := tables["fvar"]; t != nil {
+		f.axes, f.instances = parseFvar(t, tables["name"])
+		f.avar, f.hvar = tables["avar"], tables["HVAR"]
+	}
+	return f, nil
+}
+
+// bestCmap returns the Unicode subtable of a cmap table that covers the
+// most characters.
+func bestCmap(d data) data {
+	var best data
+	bestRank := 0
+	for i := range int(d.u16(2)) {
+		rec := 4 + 8*i
+		platform, enc := d.u16(rec), d.u16(rec+2)
+		st := d.sub(int(d.u32(rec + 4)))
+		format := st.u16(0)
+		if format != 4 && format != 12 {
+			continue
+		}
+		r := 1
+		switch {
+		case platform == 3 && enc == 10, platform == 0 && enc >= 4:
+			r = 3
+		case platform == 3 && enc == 1, platform == 0:
+			r = 2
+		}
+		if r > bestRank {
+			best, bestRank = st, r
+		}
+	}
+	return best
+}
+
+// UnitsPerEm returns the size of the em square in font units.
+func (f *Font) UnitsPerEm() float32 {
+	return f.upem
+}
+
+// GlyphIndex returns the glyph for r, 0 (the missing glyph) if the font
+// has none.
+func (f *Font) GlyphIndex(r rune) uint16 {
+	c := f.cmap
+	switch c.u16(0) {
+	case 4:
+		if r > 0xFFFF {
+			return 0
+		}
+		segs := int(c.u16(6)) / 2
+		ends := 14
+		starts := 16 + 2*segs
+		deltas := starts + 2*segs
+		ranges := deltas + 2*segs
+		i := sort.Search(segs, func(i int) bool { return rune(c.u16(ends+2*i)) >= r })
+		if i == segs || rune(c.u16(starts+2*i)) > r {
+			return 0
+		}
+		delta := c.u16(deltas + 2*i)
+		ro := int(c.u16(ranges + 2*i))
+		if ro == 0 {
+			return uint16(r) + delta
+		}
+		g := c.u16(ranges + 2*i + ro + 2*int(r-rune(c.u16(starts+2*i))))
+		if g == 0 {
+			return 0
+		}
+		return g + delta
+	case 12:
+		n := int(c.u32(12))
+		i := sort.Search(n, func(i int) bool { return rune(c.u32(16+12*i+4)) >= r })
+		if i == n {
+			return 0
+		}
+		rec := 16 + 12*i
+		start := rune(c.u32(rec))
+		if start > r {
+			return 0
+		}
+		return uint16(c.u32(rec+8) + uint32(r-start))
+	}
+	return 0
+}
+
+// Advance returns the horizontal advance of glyph g in font units, at the
+// font's variation.
+func (f *Font) Advance(g uint16) float32 {
+	i := int(g)
+	if i >= f.hmetrics {
+		i = f.hmetrics - 1
+	}
+	return float32(f.hmtx.u16(4*i)) + f.advanceDelta(g)
+}
+
+// classOf returns the GDEF class of g: 1 base, 2 ligature, 3 mark, 4
+// component, or 0 if the font does not say.
+func (f *Font) classOf(g uint16) uint16 {
+	return f.glyphClass.class(g)
+}
+
+// inMarkSet reports whether g is in mark filtering set i of GDEF.
+func (f *Font) inMarkSet(i int, g uint16) bool {
+	if i < 0 || i >= int(f.markSets.u16(2)) {
+		return false
+	}
+	return f.markSets.sub(int(f.markSets.u32(4+4*i))).coverage(g) >= 0
+}
+
+// legacyKern returns the kerning of the pair l, r from the kern table.
+func (f *Font) legacyKern(l, r uint16) float32 {
+	k := f.kern
+	if k == nil {
+		return 0
+	}
+	n := int(k.u16(6))
+	key := uint32(l)<<16 | uint32(r)
+	i := sort.Search(n, func(i int) bool { return k.u32(14+6*i) >= key })
+	if i < n && k.u32(14+6*i) == key {
+		return float32(k.i16(14 + 6*i + 4))
+	}
+	return 0
+}
@@ -0,0 +1,390 @@
+package font
+
+import (
+	"encoding/binary"
+	"errors"
+	"slices"
+	"sort"
+	"testing"
+	"unicode/utf16"
+)
+
+// Glyphs of the test font.
+const (
+	gidNotdef uint16 = iota
+	gidF
+	gidI
+	gidFI // The fi ligature.
+	gidA
+	gidV
+	gidSmile // U+1F600, past the last horizontal metric.
+	numTestGlyphs
+)
+
+var testAdvances = []uint16{500, 300, 250, 500, 600, 600} // Glyphs 0 to 5.
+
+// fontBuilder writes the big-endian fields of a font table.
+type fontBuilder []byte
+
+func (b *fontBuilder) u16(vs ...uint16) *fontBuilder {
+	for _, v := range vs {
+		*b = binary.BigEndian.AppendUint16(*b, v)
+	}
+	return b
+}
+
+func (b *fontBuilder) u32(vs ...uint32) *fontBuilder {
+	for _, v := range vs {
+		*b = binary.BigEndian.AppendUint32(*b, v)
+	}
+	return b
+}
+
+func (b *fontBuilder) bytes(p []byte) *fontBuilder {
+	*b = append(*b, p...)
+	return b
+}
+
+// testFont returns the tables of a small TrueType font: 'f', 'i', 'A' and
+// 'V' and U+1F600 with the outlines of testGlyf, an fi ligature in GSUB,
+// and kerning of AV in a kern table. With bmpOnly the character map is a format 4 subtable only,
+// without U+1F600; otherwise it is a format 12 one.
+func testFont(bmpOnly bool) map[string][]byte {
+	tables := map[string][]byte{}
+
+	var head fontBuilder
+	head.u32(0x00010000, 0, 0, 0x5F0F3CF5).u16(0, 1000)
+	head = append(head, make([]byte, 54-len(head))...)
+	tables["head"] = head
+
+	hhea := make(fontBuilder, 34)
+	hhea.u16(uint16(len(testAdvances)))
+	tables["hhea"] = hhea
+
+	var maxp fontBuilder
+	maxp.u32(0x00005000).u16(uint16(numTestGlyphs))
+	tables["maxp"] = maxp
+
+	var hmtx fontBuilder
+	for _, adv := range testAdvances {
+		hmtx.u16(adv, 0)
+	}
+	hmtx.u16(0) // Left side bearing of the last glyph.
+	tables["hmtx"] = hmtx
+
+	chars := map[rune]uint16{'A': gidA, 'V': gidV, 'f': gidF, 'i': gidI}
+	var cmap fontBuilder
+	if bmpOnly {
+		codes := make([]rune, 0, len(chars))
+		for r := range chars {
+			codes = append(codes, r)
+		}
+		slices.Sort(codes)
+		codes = append(codes, 0xFFFF)
+		var sub fontBuilder
+		segs := uint16(len(codes))
+		sub.u16(4, 0, 0, 2*segs, 0, 0, 0)
+		for _, r := range codes {
+			sub.u16(uint16(r)) // End codes.
+		}
+		sub.u16(0)
+		for _, r := range codes {
+			sub.u16(uint16(r)) // Start codes.
+		}
+		for _, r := range codes {
+			delta := uint16(1)
+			if r != 0xFFFF {
+				delta = chars[r] - uint16(r)
+			}
+			sub.u16(delta)
+		}
+		for range codes {
+			sub.u16(0)
+		}
+		binary.BigEndian.PutUint16(sub[2:], uint16(len(sub)))
+		cmap.u16(0, 1).u16(3, 1).u32(12).bytes(sub)
+	} else {
+		chars[0x1F600] = gidSmile
+		codes := make([]rune, 0, len(chars))
+		for r := range chars {
+			codes = append(codes, r)
+		}
+		slices.Sort(codes)
+		var sub fontBuilder
+		sub.u16(12, 0).u32(uint32(16+12*len(codes)), 0, uint32(len(codes)))
+		for _, r := range codes {
+			sub.u32(uint32(r), uint32(r), uint32(chars[r]))
+		}
+		cmap.u16(0, 1).u16(3, 10).u32(12).bytes(sub)
+	}
+	tables["cmap"] = cmap
+
+	var kern fontBuilder
+	kern.u16(0, 1)               // Version, one subtable.
+	kern.u16(0, 6+8+6, 0x0001)   // Horizontal format 0.
+	kern.u16(1, 0, 0, 0)         // One pair.
+	kern.u16(gidA, gidV, 0xFFB0) // A V: -80.
+	tables["kern"] = kern
+
+	// GSUB with one liga lookup forming fi from f and i for every script.
+	var gsub fontBuilder
+	gsub.u32(0x00010000).u16(10, 30, 44)
+	// Script list at 10: DFLT, default language system with feature 0.
+	gsub.u16(1).bytes([]byte("DFLT")).u16(8)
+	gsub.u16(4, 0)
+	gsub.u16(0, 0xFFFF, 1, 0)
+	// Feature list at 30: liga with lookup 0.
+	gsub.u16(1).bytes([]byte("liga")).u16(8)
+	gsub.u16(0, 1, 0)
+	// Lookup list at 44: a ligature lookup.
+	gsub.u16(1, 4)
+	gsub.u16(4, 0, 1, 8)
+	// Ligature substitution at 56: coverage of f, one ligature set.
+	gsub.u16(1, 8, 1, 14)
+	gsub.u16(1, 1, gidF)     // Coverage, at 64.
+	gsub.u16(1, 4)           // Ligature set, at 70.
+	gsub.u16(gidFI, 2, gidI) // Ligature, at 74.
+	tables["GSUB"] = gsub
+
+	tables["glyf"], tables["loca"] = testGlyf()
+
+	name := func(id uint16, s string) (uint16, []byte) {
+		var b fontBuilder
+		b.u16(utf16.Encode([]rune(s))...)
+		return id, b
+	}
+	var names fontBuilder
+	id1, family := name(1, "Test Sans")
+	id2, style := name(2, "Regular")
+	names.u16(0, 2, 6+2*12)
+	names.u16(3, 1, 0x409, id1, uint16(len(family)), 0)
+	names.u16(3, 1, 0x409, id2, uint16(len(style)), uint16(len(family)))
+	names.bytes(family).bytes(style)
+	tables["name"] = names
+	return tables
+}
+
+// testGlyf returns glyf and loca tables, in the short format, with the
+// outlines of the test font:
+//
+//   - f, a rectangle from (100, 0) to (400, 700) of points on the curve;
+//   - i, empty, like a space;
+//   - A, a curve from (0, 0) through (100, 200) off it to (200, 0), with
+//     short coordinates;
+//   - V, a square from (0, 0) to (100, 100) of points all off the curve;
+//   - fi, f moved by (500, 0) and A halved and moved by (10, -10).
+func testGlyf() (glyf, loca []byte) {
+	outlines := map[uint16]fontBuilder{}
+
+	var f fontBuilder
+	f.u16(1, 100, 0, 400, 700) // One contour and its bounds.
+	f.u16(3, 0)                // The last point; no instructions.
+	f.bytes([]byte{1, 1, 1, 1})
+	f.u16(100, 300, 0, 0xFED4) // x: 100, 400, 400, 100.
+	f.u16(0, 0, 700, 0)        // y: 0, 0, 700, 700.
+	outlines[gidF] = f
+
+	var a fontBuilder
+	a.u16(1, 0, 0, 200, 200).u16(2, 0)
+	// On, x short 0, y the same; off, x +100 and y +200 short; on, x +100
+	// short, y -200 short.
+	a.bytes([]byte{0x33, 0x36, 0x17})
+	a.bytes([]byte{0, 100, 100, 200, 200})
+	outlines[gidA] = a
+
+	var v fontBuilder
+	v.u16(1, 0, 0, 100, 100).u16(3, 0)
+	v.bytes([]byte{0, 0, 0, 0})
+	v.u16(0, 100, 0, 0xFF9C).u16(0, 0, 100, 0)
+	outlines[gidV] = v
+
+	var fi fontBuilder
+	fi.u16(0xFFFF, 0, 0, 0, 0)
+	fi.u16(0x0023, gidF, 500, 0)                 // Words, x and y, more.
+	fi.u16(0x000A, gidA).bytes([]byte{10, 0xF6}) // Bytes, x and y, scale.
+	fi.u16(0x2000)                               // 0.5.
+	outlines[gidFI] = fi
+
+	for g := range numTestGlyphs + 1 {
+		(*fontBuilder)(&loca).u16(uint16(len(glyf) / 2))
+		if g < numTestGlyphs {
+			glyf = append(glyf, outlines[g]...)
+			if len(glyf)%2 == 1 {
+				glyf = append(glyf, 0)
+			}
+		}
+	}
+	return glyf, loca
+}
+
+// sfnt lays tables out into a font file.
+func sfnt(tables map[string][]byte) []byte {
+	tags := make([]string, 0, len(tables))
+	for tag := range tables {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	var b fontBuilder
+	b.u32(tagTrueType).u16(uint16(len(tags)), 0, 0, 0)
+	off := 12 + 16*len(tags)
+	var body fontBuilder
+	for _, tag := range tags {
+		t := tables[tag]
+		b.bytes([]byte(tag)).u32(0, uint32(off+len(body)), uint32(len(t)))
+		body.bytes(t)
+		for len(body)%4 != 0 {
+			body = append(body, 0)
+		}
+	}
+	return append(b, body...)
+}
+
+func TestParseFont(t *testing.T) {
+	tests := []struct {
+		name    string
+		bmpOnly bool
+		glyphs  map[rune]uint16
+	}{
+		{"format 4", true, map[rune]uint16{'A': gidA, 'V': gidV, 'f': gidF, 'i': gidI, 'B': 0, 0x1F600: 0}},
+		{"format 12", false, map[rune]uint16{'A': gidA, 'V': gidV, 'f': gidF, 'i': gidI, 'B': 0, 0x1F600: gidSmile, 0x1F601: 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := ParseFont(sfnt(testFont(tt.bmpOnly)), 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := f.UnitsPerEm(); got != 1000 {
+				t.Errorf("UnitsPerEm() = %v, want 1000", got)
+			}
+			for r, want := range tt.glyphs {
+				if got := f.GlyphIndex(r); got != want {
+					t.Errorf("GlyphIndex(%U) = %d, want %d", r, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestAdvance(t *testing.T) {
+	f, err := ParseFont(sfnt(testFont(false)), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for g, want := range testAdvances {
+		if got := f.Advance(uint16(g)); got != float32(want) {
+			t.Errorf("Advance(%d) = %v, want %v", g, got, want)
+		}
+	}
+	// Glyphs past the horizontal metrics take the last advance.
+	if got := f.Advance(gidSmile); got != 600 {
+		t.Errorf("Advance(%d) = %v, want 600", gidSmile, got)
+	}
+}
+
+func TestParseFontCollection(t *testing.T) {
+	font := sfnt(testFont(false))
+	var b fontBuilder
+	b.bytes([]byte("ttcf")).u32(0x00010000, 1, 16)
+	// Offsets in the table directory are from the start of the file.
+	for i := range int(binary.BigEndian.Uint16(font[4:])) {
+		rec := font[12+16*i:]
+		binary.BigEndian.PutUint32(rec[8:], binary.BigEndian.Uint32(rec[8:])+16)
+	}
+	ttc := append(b, font...)
+	f, err := ParseFont(ttc, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := f.GlyphIndex('A'); got != gidA {
+		t.Errorf("GlyphIndex('A') = %d, want %d", got, gidA)
+	}
+	if _, err := ParseFont(ttc, 1); !errors.Is(err, ErrFormat) {
+		t.Errorf("ParseFont of face 1 of 1: err = %v, want ErrFormat", err)
+	}
+}
+
+func TestParseFontErrors(t *testing.T) {
+	font := sfnt(testFont(false))
+	without := func(tag string) []byte {
+		tables := testFont(false)
+		delete(tables, tag)
+		return sfnt(tables)
+	}
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"not a font", []byte("%PDF-1.7 and more bytes")},
+		{"header only", font[:12]},
+		{"truncated directory", font[:40]},
+		{"no head", without("head")},
+		{"no maxp", without("maxp")},
+	}
+	for _, tt := range tests {
+		if _, err := ParseFont(tt.data, 0); !errors.Is(err, ErrFormat) {
+			t.Errorf("%s: err = %v, want ErrFormat", tt.name, err)
+		}
+	}
+}
+
+// TestCorruptFont checks that truncated and corrupt fonts parse without
+// panicking and shape, if they parse, without panicking either.
+func TestCorruptFont(t *testing.T) {
+	font := sfnt(testFont(false))
+	use := func(b []byte) {
+		f, err := ParseFont(b, 0)
+		if err != nil {
+			return
+		}
+		f.Shape("fi AV \U0001F600", 16)
+		for g := range numTestGlyphs + 1 {
+			f.Advance(g)
+			f.Outline(g, 16)
+		}
+	}
+	for n := range len(font) {
+		use(font[:n])
+	}
+	for i := range font {
+		for _, v := range []byte{0x00, 0x7F, 0xFF} {
+			b := slices.Clone(font)
+			b[i] = v
+			use(b)
+		}
+	}
+}
+
+func TestManagerAddData(t *testing.T) {
+	m := NewManager()
+	if err := m.AddData(sfnt(testFont(false))); err != nil {
+		t.Fatal(err)
+	}
+	faces := m.Faces("Test Sans")
+	if len(faces) != 1 {
+		t.Fatalf("Faces(\"Test Sans\") = %v", faces)
+	}
+	f := faces[0]
+	if f.Style != "Regular" {
+		t.Errorf("Style = %q, want Regular", f.Style)
+	}
+	for _, tt := range []struct {
+		r    rune
+		want bool
+	}{{'A', true}, {'i', true}, {0x1F600, true}, {'B', false}} {
+		if got := f.Covers(tt.r); got != tt.want {
+			t.Errorf("Covers(%U) = %v, want %v", tt.r, got, tt.want)
+		}
+	}
+	if _, err := f.Load(); err != nil {
+		t.Errorf("Load() = %v", err)
+	}
+
+	font := sfnt(testFont(false))
+	for _, n := range []int{0, 11, 40} {
+		if err := NewManager().AddData(font[:n]); err == nil {
+			t.Errorf("AddData of %d bytes succeeded", n)
+		}
+	}
+}
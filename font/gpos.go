@@ -0,0 +1,196 @@
+package font
+
+// valueSize returns the size in bytes of a ValueRecord of format f.
+func valueSize(f uint16) int {
+	n := 0
+	for ; f != 0; f &= f - 1 {
+		n++
+	}
+	return 2 * n
+}
+
+// addValue adds the ValueRecord of format f at off in d to glyph g.
+func addValue(g *glyphInfo, d data, off int, f uint16) {
+	if f&1 != 0 {
+		g.dx += float32(d.i16(off))
+		off += 2
+	}
+	if f&2 != 0 {
+		g.dy += float32(d.i16(off))
+		off += 2
+	}
+	if f&4 != 0 {
+		g.adv += float32(d.i16(off))
+	}
+}
+
+// anchor returns the coordinates of an Anchor table.
+func anchor(d data) (x, y float32) {
+	return float32(d.i16(2)), float32(d.i16(4))
+}
+
+// position applies GPOS lookup subtables at position i and returns
+// whether one of them applied.
+func (s *shaper) position(lk *lookup, i int) bool {
+	for _, st := range lk.subtables {
+		if s.positionOne(lk, st, i) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *shaper) positionOne(lk *lookup, st data, i int) bool {
+	g := s.buf[i].id
+	switch lk.kind {
+	case 1: // Single adjustment.
+		ci := st.sub(int(st.u16(2))).coverage(g)
+		if ci < 0 {
+			return false
+		}
+		f := st.u16(4)
+		if st.u16(0) == 1 {
+			addValue(&s.buf[i], st, 6, f)
+		} else {
+			addValue(&s.buf[i], st, 8+ci*valueSize(f), f)
+		}
+		return true
+	case 2: // Pair adjustment.
+		ci := st.sub(int(st.u16(2))).coverage(g)
+		if ci < 0 {
+			return false
+		}
+		j := s.next(i, lk)
+		if j < 0 {
+			return false
+		}
+		f1, f2 := st.u16(4), st.u16(6)
+		s1, s2 := valueSize(f1), valueSize(f2)
+		second := s.buf[j].id
+		switch st.u16(0) {
+		case 1:
+			if ci >= int(st.u16(8)) {
+				return false
+			}
+			set := st.sub(int(st.u16(10 + 2*ci)))
+			rec := 2 + s1 + s2
+			n := int(set.u16(0))
+			lo, hi := 0, n
+			for lo < hi {
+				m := (lo + hi) / 2
+				v := set.u16(2 + m*rec)
+				if v < second {
+					lo = m + 1
+				} else {
+					hi = m
+				}
+			}
+			if lo == n || set.u16(2+lo*rec) != second {
+				return false
+			}
+			off := 2 + lo*rec + 2
+			addValue(&s.buf[i], set, off, f1)
+			addValue(&s.buf[j], set, off+s1, f2)
+		case 2:
+			c1 := int(st.sub(int(st.u16(8))).class(g))
+			c2 := int(st.sub(int(st.u16(10))).class(second))
+			n1, n2 := int(st.u16(12)), int(st.u16(14))
+			if c1 >= n1 || c2 >= n2 {
+				return false
+			}
+			off := 16 + (c1*n2+c2)*(s1+s2)
+			addValue(&s.buf[i], st, off, f1)
+			addValue(&s.buf[j], st, off+s1, f2)
+		default:
+			return false
+		}
+		return true
+	case 4, 5, 6: // Mark to base, to ligature and to mark.
+		if s.buf[i].class != classMark {
+			return false
+		}
+		mi := st.sub(int(st.u16(2))).coverage(g)
+		if mi < 0 {
+			return false
+		}
+		// The base is the previous glyph that is not a mark, or for mark
+		// to mark the previous mark.
+		j := i - 1
+		for ; j >= 0; j-- {
+			c := s.buf[j].class
+			if lk.kind == 6 {
+				if c == classMark && !s.skip(lk, j) {
+					break
+				}
+				if c != classMark {
+					return false
+				}
+				continue
+			}
+			if c != classMark {
+				break
+			}
+		}
+		if j < 0 {
+			return false
+		}
+		bi := st.sub(int(st.u16(4))).coverage(s.buf[j].id)
+		if bi < 0 {
+			return false
+		}
+		classes := int(st.u16(6))
+		marks := st.sub(int(st.u16(8)))
+		bases := st.sub(int(st.u16(10)))
+		if mi >= int(marks.u16(0)) || bi >= int(bases.u16(0)) {
+			return false
+		}
+		mc := int(marks.u16(2 + 4*mi))
+		if mc >= classes {
+			return false
+		}
+		mx, my := anchor(marks.sub(int(marks.u16(2 + 4*mi + 2))))
+		var ba data
+		if lk.kind == 5 {
+			// The anchor of the last component of the ligature.
+			lig := bases.sub(int(bases.u16(2 + 2*bi)))
+			n := int(lig.u16(0))
+			if n == 0 {
+				return false
+			}
+			ba = lig.sub(int(lig.u16(2 + 2*((n-1)*classes+mc))))
+		} else {
+			ba = bases.sub(int(bases.u16(2 + 2*(bi*classes+mc))))
+		}
+		if ba == nil {
+			return false
+		}
+		bx, by := anchor(ba)
+		s.attach(i, j, bx-mx, by-my)
+		return true
+	case 7:
+		return s.context(st, i, lk, false)
+	case 8:
+		return s.context(st, i, lk, true)
+	}
+	return false
+}
+
+// attach positions mark i on base j, with the mark anchor dx, dy from
+// the base anchor.
+func (s *shaper) attach(i, j int, dx, dy float32) {
+	m := &s.buf[i]
+	m.adv = 0
+	m.dx = s.buf[j].dx + dx
+	m.dy = s.buf[j].dy + dy
+	// In logical order the pen has moved past the base and the glyphs
+	// between; laid out right to left they are drawn before it instead.
+	var between float32
+	for k := j; k < i; k++ {
+		between += s.buf[k].adv
+	}
+	if s.rtl {
+		m.dx += between - s.buf[j].adv
+	} else {
+		m.dx -= between
+	}
+}
@@ -0,0 +1,90 @@
+package font
+
+// substitute applies GSUB lookup subtables at position i and returns
+// whether one of them applied.
+func (s *shaper) substitute(lk *lookup, i int) bool {
+	for _, st := range lk.subtables {
+		if s.substituteOne(lk, st, i) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *shaper) substituteOne(lk *lookup, st data, i int) bool {
+	g := s.buf[i].id
+	switch lk.kind {
+	case 1: // Single.
+		ci := st.sub(int(st.u16(2))).coverage(g)
+		if ci < 0 {
+			return false
+		}
+		if st.u16(0) == 1 {
+			s.buf[i].id = g + st.u16(4)
+		} else {
+			if ci >= int(st.u16(4)) {
+				return false
+			}
+			s.buf[i].id = st.u16(6 + 2*ci)
+		}
+		s.buf[i].class = s.font.classOf(s.buf[i].id)
+		return true
+	case 2: // Multiple.
+		ci := st.sub(int(st.u16(2))).coverage(g)
+		if ci < 0 || ci >= int(st.u16(4)) {
+			return false
+		}
+		seq := st.sub(int(st.u16(6 + 2*ci)))
+		n := int(seq.u16(0))
+		out := make([]glyphInfo, n)
+		for k := range out {
+			out[k] = s.buf[i]
+			out[k].id = seq.u16(2 + 2*k)
+			out[k].class = s.font.classOf(out[k].id)
+		}
+		s.replace(i, i+1, out)
+		return true
+	case 3: // Alternate: the first alternate.
+		ci := st.sub(int(st.u16(2))).coverage(g)
+		if ci < 0 || ci >= int(st.u16(4)) {
+			return false
+		}
+		set := st.sub(int(st.u16(6 + 2*ci)))
+		if set.u16(0) == 0 {
+			return false
+		}
+		s.buf[i].id = set.u16(2)
+		s.buf[i].class = s.font.classOf(s.buf[i].id)
+		return true
+	case 4: // Ligature.
+		ci := st.sub(int(st.u16(2))).coverage(g)
+		if ci < 0 || ci >= int(st.u16(4)) {
+			return false
+		}
+		set := st.sub(int(st.u16(6 + 2*ci)))
+		for l := range int(set.u16(0)) {
+			lig := set.sub(int(set.u16(2 + 2*l)))
+			n := int(lig.u16(2))
+			pos, ok := s.matchInput(i, n, lk, func(g uint16, k int) bool { return lig.u16(4+2*(k-1)) == g })
+			if !ok {
+				continue
+			}
+			s.buf[i].id = lig.u16(0)
+			s.buf[i].class = s.font.classOf(s.buf[i].id)
+			if s.buf[i].class == 0 {
+				s.buf[i].class = classLigature
+			}
+			// Remove the other components; skipped marks between them stay
+			// after the ligature.
+			for k := len(pos) - 1; k >= 1; k-- {
+				s.replace(pos[k], pos[k]+1, nil)
+			}
+			return true
+		}
+	case 5:
+		return s.context(st, i, lk, false)
+	case 6:
+		return s.context(st, i, lk, true)
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package font
+
+import (
+	"slices"
+	"unicode"
+)
+
+// indicScript describes a script of the Devanagari family for syllable
+// reordering. The scripts share the layout of their Unicode blocks.
+type indicScript struct {
+	block   rune   // First code point of the block.
+	reph    bool   // Whether an initial ra and virama form a reph.
+	preBase []rune // Vowel signs drawn before the consonant they follow.
+}
+
+var indicScripts = map[string]indicScript{
+	"Devanagari": {block: 0x0900, reph: true, preBase: []rune{0x093F}},
+	"Bengali":    {block: 0x0980, reph: true, preBase: []rune{0x09BF, 0x09C7, 0x09C8}},
+	"Gurmukhi":   {block: 0x0A00, preBase: []rune{0x0A3F}},
+	"Gujarati":   {block: 0x0A80, reph: true, preBase: []rune{0x0ABF}},
+}
+
+// Offsets of characters in the blocks.
+const (
+	indicRa     = 0x30
+	indicVirama = 0x4D
+)
+
+func (sc indicScript) isConsonant(r rune) bool {
+	off := r - sc.block
+	return off >= 0x15 && off <= 0x39 || off >= 0x58 && off <= 0x5F
+}
+
+func (sc indicScript) isVirama(r rune) bool {
+	return r == sc.block+indicVirama
+}
+
+// isModifier reports whether r is a candrabindu, anusvara or visarga,
+// which stay at the end of a syllable.
+func (sc indicScript) isModifier(r rune) bool {
+	off := r - sc.block
+	return off >= 0x01 && off <= 0x03
+}
+
+// reorderIndic splits the buffer into syllables and puts their characters
+// in the order their glyphs are drawn in: a reph, from an initial ra and
+// virama, moves to the end of the syllable, and a pre-base vowel sign to
+// its start. Consonants followed by a virama and another consonant are
+// marked for their half forms. The glyphs of a syllable share the cluster
+// of its first character.
+func reorderIndic(s *shaper) {
+	sc, ok := indicScripts[scriptOf(s.firstLetter())]
+	if !ok {
+		return
+	}
+	for start := 0; start < len(s.buf); {
+		end := start + 1
+		for end < len(s.buf) {
+			r := s.buf[end].r
+			joins := unicode.In(r, unicode.Mn, unicode.Mc) || r == 0x200C || r == 0x200D ||
+				sc.isVirama(s.buf[end-1].r) && sc.isConsonant(r)
+			if !joins {
+				break
+			}
+			end++
+		}
+		s.reorderSyllable(sc, start, end)
+		start = end
+	}
+}
+
+func (s *shaper) firstLetter() rune {
+	for _, g := range s.buf {
+		if unicode.IsLetter(g.r) {
+			return g.r
+		}
+	}
+	return 0
+}
+
+func (s *shaper) reorderSyllable(sc indicScript, start, end int) {
+	syl := s.buf[start:end]
+	cluster := syl[0].cluster
+	for i := range syl {
+		syl[i].cluster = cluster
+	}
+	if sc.reph && len(syl) >= 3 && syl[0].r == sc.block+indicRa && sc.isVirama(syl[1].r) && sc.isConsonant(syl[2].r) {
+		reph := []glyphInfo{syl[0], syl[1]}
+		reph[0].mask |= maskRphf
+		reph[1].mask |= maskRphf
+		at := len(syl)
+		for at > 2 && sc.isModifier(syl[at-1].r) {
+			at--
+		}
+		copy(syl, syl[2:at])
+		copy(syl[at-2:], reph)
+	}
+	for i := 0; i+2 < len(syl); i++ {
+		if sc.isConsonant(syl[i].r) && sc.isVirama(syl[i+1].r) && sc.isConsonant(syl[i+2].r) && syl[i].mask&maskRphf == 0 {
+			syl[i].mask |= maskHalf
+			syl[i+1].mask |= maskHalf
+		}
+	}
+	for i := 1; i < len(syl); i++ {
+		if slices.Contains(sc.preBase, syl[i].r) {
+			m := syl[i]
+			copy(syl[1:i+1], syl[:i])
+			syl[0] = m
+		}
+	}
+}
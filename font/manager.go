@@ -0,0 +1,539 @@
+package font
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/grapheme"
+)
+
+// Generic family names, resolved to installed families through the lists
+// set with Manager.SetGeneric.
+const (
+	SansSerif = "sans-serif"
+	Serif     = "serif"
+	Monospace = "monospace"
+	SystemUI  = "system-ui"
+	Emoji     = "emoji"
+)
+
+// Common is the fallback chain for characters shared by every script,
+// such as punctuation, symbols and emoji.
+const Common = "Common"
+
+var defaultGenerics = map[string][]string{
+	SansSerif: {"Segoe UI", "Helvetica Neue", "Helvetica", "Arial", "Noto Sans", "DejaVu Sans", "Liberation Sans", "Roboto"},
+	Serif:     {"Times New Roman", "Times", "Georgia", "Noto Serif", "DejaVu Serif", "Liberation Serif"},
+	Monospace: {"Cascadia Mono", "Consolas", "SF Mono", "Menlo", "Noto Sans Mono", "DejaVu Sans Mono", "Liberation Mono", "Courier New"},
+	SystemUI:  {"Segoe UI", "SF Pro", "SF Pro Text", ".AppleSystemUIFont", "Helvetica Neue", "Cantarell", "Ubuntu", "Noto Sans", "DejaVu Sans", "Roboto"},
+	Emoji:     {"Segoe UI Emoji", "Apple Color Emoji", "Noto Color Emoji"},
+}
+
+var (
+	cjkFallback  = []string{"Microsoft YaHei", "PingFang SC", "Noto Sans CJK SC", "Source Han Sans SC", "Noto Sans SC", "WenQuanYi Micro Hei", "Hiragino Sans", "Yu Gothic", "Noto Sans CJK JP", "Malgun Gothic", "Noto Sans CJK KR"}
+	kanaFallback = []string{"Yu Gothic", "Meiryo", "Hiragino Sans", "Hiragino Kaku Gothic ProN", "Noto Sans CJK JP", "Source Han Sans JP", "Noto Sans JP"}
+)
+
+var defaultFallbacks = map[string][]string{
+	"Latin":      {"Segoe UI", "Helvetica Neue", "Arial", "Noto Sans", "DejaVu Sans"},
+	"Cyrillic":   {"Segoe UI", "Helvetica Neue", "Arial", "Noto Sans", "DejaVu Sans", "Liberation Sans"},
+	"Greek":      {"Segoe UI", "Helvetica Neue", "Arial", "Noto Sans", "DejaVu Sans", "Liberation Sans"},
+	"Han":        cjkFallback,
+	"Bopomofo":   cjkFallback,
+	"Hiragana":   kanaFallback,
+	"Katakana":   kanaFallback,
+	"Hangul":     {"Malgun Gothic", "Apple SD Gothic Neo", "Noto Sans CJK KR", "Source Han Sans KR", "Noto Sans KR", "NanumGothic"},
+	"Arabic":     {"Segoe UI", "Geeza Pro", "Noto Sans Arabic", "Noto Naskh Arabic", "DejaVu Sans"},
+	"Hebrew":     {"Segoe UI", "Arial Hebrew", "Noto Sans Hebrew", "DejaVu Sans"},
+	"Thai":       {"Leelawadee UI", "Thonburi", "Noto Sans Thai", "Noto Sans Thai UI"},
+	"Devanagari": {"Nirmala UI", "Kohinoor Devanagari", "Noto Sans Devanagari"},
+	"Bengali":    {"Nirmala UI", "Kohinoor Bangla", "Noto Sans Bengali"},
+	"Tamil":      {"Nirmala UI", "Tamil Sangam MN", "Noto Sans Tamil"},
+	"Armenian":   {"Segoe UI", "Noto Sans Armenian", "DejaVu Sans"},
+	"Georgian":   {"Segoe UI", "Noto Sans Georgian", "DejaVu Sans"},
+	"Ethiopic":   {"Ebrima", "Kefa", "Noto Sans Ethiopic"},
+	Common:       {"Segoe UI Symbol", "Segoe UI Emoji", "Apple Symbols", "Apple Color Emoji", "Noto Sans Symbols", "Noto Sans Symbols 2", "Noto Color Emoji", "Noto Sans Math", "DejaVu Sans"},
+}
+
+// Manager resolves font requests to installed faces: it matches a family,
+// weight and style like CSS, and finds a fallback face for characters the
+// matched face lacks, through a chain of families per script, so CJK,
+// Cyrillic and symbols render instead of missing-glyph boxes:
+//
+//	fonts := font.NewManager()
+//	fonts.LoadSystem()
+//	for _, run := range fonts.Runs("Price: 42 € — 价格", style) {
+//		// shape and draw run.Text with run.Face
+//	}
+//
+// A Manager is safe for concurrent use, so system fonts can be loaded in
+// the background.
+type Manager struct {
+	mu        sync.RWMutex
+	families  map[string][]*Face // By lower-case family name.
+	generics  map[string][]string
+	fallbacks map[string][]string
+
+	missMu  sync.Mutex
+	missing map[rune]bool // Characters no face covers.
+}
+
+// NewManager returns a manager with no fonts and the default generic
+// families and fallback chains.
+func NewManager() *Manager {
+	m := &Manager{
+		families:  make(map[string][]*Face),
+		generics:  make(map[string][]string),
+		fallbacks: make(map[string][]string),
+		missing:   make(map[rune]bool),
+	}
+	for k, v := range defaultGenerics {
+		m.generics[k] = v
+	}
+	for k, v := range defaultFallbacks {
+		m.fallbacks[k] = v
+	}
+	return m
+}
+
+// LoadSystem adds the fonts installed on the system, from SystemDirs, and
+// returns the number of faces found.
+func (m *Manager) LoadSystem() int {
+	n := 0
+	for _, dir := range SystemDirs() {
+		n += m.AddDir(dir)
+	}
+	return n
+}
+
+// AddDir adds the font files in dir and its subdirectories and returns
+// the number of faces found. Files that cannot be read are skipped.
+func (m *Manager) AddDir(dir string) int {
+	n := 0
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !isFontFile(path) {
+			return nil
+		}
+		if faces, err := loadFile(path); err == nil {
+			m.add(faces)
+			n += len(faces)
+		}
+		return nil
+	})
+	return n
+}
+
+// AddFile adds the faces of a font file or collection.
+func (m *Manager) AddFile(path string) error {
+	faces, err := loadFile(path)
+	if err != nil {
+		return err
+	}
+	m.add(faces)
+	return nil
+}
+
+// AddData adds the faces of a font held in memory, such as one embedded in
+// the application. The faces have no Path and keep data in Data.
+func (m *Manager) AddData(data []byte) error {
+	faces, err := parseFaces(bytes.NewReader(data), "")
+	if err != nil {
+		return err
+	}
+	for _, f := range faces {
+		f.Data = data
+	}
+	m.add(faces)
+	return nil
+}
+
+func isFontFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ttf", ".otf", ".ttc", ".otc":
+		return true
+	}
+	return false
+}
+
+func loadFile(path string) ([]*Face, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseFaces(f, path)
+}
+
+func (m *Manager) add(faces []*Face) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, f := range faces {
+		key := strings.ToLower(f.Family)
+		m.families[key] = append(m.families[key], f)
+	}
+	m.forgetMissing()
+}
+
+func (m *Manager) forgetMissing() {
+	m.missMu.Lock()
+	clear(m.missing)
+	m.missMu.Unlock()
+}
+
+// Families returns the names of the installed families, sorted.
+func (m *Manager) Families() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	names := make([]string, 0, len(m.families))
+	for _, faces := range m.families {
+		names = append(names, faces[0].Family)
+	}
+	slices.SortFunc(names, func(a, b string) int { return strings.Compare(strings.ToLower(a), strings.ToLower(b)) })
+	return names
+}
+
+// Faces returns the faces of family.
+func (m *Manager) Faces(family string) []*Face {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.families[strings.ToLower(family)])
+}
+
+// SetGeneric sets the families, in order of preference, that a generic
+// family name such as SansSerif resolves to. Other names can be added,
+// for example "code" for an application's editor font.
+func (m *Manager) SetGeneric(name string, families ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.generics[strings.ToLower(name)] = families
+	m.forgetMissing()
+}
+
+// SetFallback sets the families, in order of preference, tried for
+// characters of script, named as in the unicode package's Scripts table
+// ("Han", "Cyrillic", …), or Common for symbols and punctuation.
+func (m *Manager) SetFallback(script string, families ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.fallbacks[script] = families
+	m.forgetMissing()
+}
+
+// Match returns the face of family closest to weight and italic, by the
+// CSS font matching rules, or nil if no family is installed. Family may
+// be a comma-separated list and may name generic families; the first
+// installed one is used. An empty family is SystemUI. A variable face
+// matches every weight of its wght axis exactly, so a weight animated
+// across it stays in the one face, drawn at the weight with Font.Vary.
+func (m *Manager) Match(family string, weight core.FontWeight, italic bool) *Face {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if c := m.candidates(family); len(c) > 0 {
+		return bestFace(c[0], weight, italic)
+	}
+	return nil
+}
+
+// Face returns the face for style, as Match does, or the closest face of
+// any installed family.
+func (m *Manager) Face(style core.TextStyle) *Face {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.face(style)
+}
+
+func (m *Manager) face(style core.TextStyle) *Face {
+	weight := resolveWeight(style.Weight)
+	if c := m.candidates(style.Family + "," + SystemUI + "," + SansSerif); len(c) > 0 {
+		return bestFace(c[0], weight, style.Italic)
+	}
+	if names := m.sortedFamilies(); len(names) > 0 {
+		return bestFace(m.families[names[0]], weight, style.Italic)
+	}
+	return nil
+}
+
+// Fallback returns the face to draw r with in style: the face of the
+// requested family if it covers r, else a face from the fallback chain of
+// the script of r, else any face that covers it. It returns nil if no
+// installed face covers r.
+func (m *Manager) Fallback(r rune, style core.TextStyle) *Face {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.fallback(r, style)
+}
+
+func (m *Manager) fallback(r rune, style core.TextStyle) *Face {
+	weight := resolveWeight(style.Weight)
+	var chain []string
+	chain = append(chain, style.Family)
+	chain = append(chain, m.fallbacks[scriptOf(r)]...)
+	chain = append(chain, m.fallbacks[Common]...)
+	chain = append(chain, SystemUI, SansSerif)
+	seen := make(map[string]bool)
+	for _, name := range chain {
+		for _, faces := range m.candidates(name) {
+			key := strings.ToLower(faces[0].Family)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			if f := coveringFace(faces, r, weight, style.Italic); f != nil {
+				return f
+			}
+		}
+	}
+	m.missMu.Lock()
+	missing := m.missing[r]
+	m.missMu.Unlock()
+	if missing {
+		return nil
+	}
+	for _, name := range m.sortedFamilies() {
+		if !seen[name] {
+			if f := coveringFace(m.families[name], r, weight, style.Italic); f != nil {
+				return f
+			}
+		}
+	}
+	// Remember characters nothing covers, which would otherwise scan every
+	// face on each call.
+	m.missMu.Lock()
+	m.missing[r] = true
+	m.missMu.Unlock()
+	return nil
+}
+
+// Run is a piece of text drawn with one face.
+type Run struct {
+	Text string
+
+	// Face is nil for characters no installed face covers.
+	Face *Face
+}
+
+// Runs splits text into runs that can each be drawn with a single face,
+// choosing a face for each grapheme cluster with Fallback, so combining
+// marks, joiners and variation selectors stay with the character they
+// modify. Clusters that the current face covers stay in its run. Clusters
+// shown as emoji, such as emoji with skin tones and characters followed
+// by the emoji presentation selector, are drawn with an emoji font when
+// one is installed, and text around them is not.
+func (m *Manager) Runs(text string, style core.TextStyle) []Run {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	rs := []rune(text)
+	var runs []Run
+	start, pos := 0, 0
+	var cur *Face
+	curEmoji := false
+	for i := 0; i < len(rs); {
+		j := grapheme.Next(rs, i)
+		cluster := rs[i:j]
+		emoji := isEmoji(cluster)
+		if pos == 0 || emoji != curEmoji || cur == nil || !cur.Covers(cluster[0]) {
+			var f *Face
+			if emoji {
+				f = m.emojiFace(cluster[0], style)
+			}
+			if f == nil {
+				f = m.fallback(cluster[0], style)
+			}
+			if pos > 0 && (f != cur || emoji != curEmoji) {
+				runs = append(runs, Run{Text: text[start:pos], Face: cur})
+				start = pos
+			}
+			cur, curEmoji = f, emoji
+		}
+		for _, r := range cluster {
+			pos += utf8.RuneLen(r)
+		}
+		i = j
+	}
+	if start < len(text) {
+		runs = append(runs, Run{Text: text[start:], Face: cur})
+	}
+	return runs
+}
+
+// emojiFace returns a face of the emoji families that covers r, or nil.
+func (m *Manager) emojiFace(r rune, style core.TextStyle) *Face {
+	weight := resolveWeight(style.Weight)
+	for _, faces := range m.candidates(Emoji) {
+		if f := coveringFace(faces, r, weight, style.Italic); f != nil {
+			return f
+		}
+	}
+	return nil
+}
+
+// isEmoji reports whether a grapheme cluster displays as an emoji: its
+// character has emoji presentation by default, or it asks for it with
+// the presentation selector U+FE0F, a keycap or a skin tone. Flags have
+// emoji presentation.
+func isEmoji(cluster []rune) bool {
+	for _, r := range cluster[1:] {
+		switch {
+		case r == 0xFE0E:
+			return false
+		case r == 0xFE0F, r == 0x20E3, r >= 0x1F3FB && r <= 0x1F3FF:
+			return true
+		}
+	}
+	r := cluster[0]
+	i := sort.Search(len(emojiPresentation), func(i int) bool { return emojiPresentation[i].hi >= r })
+	return i < len(emojiPresentation) && emojiPresentation[i].lo <= r
+}
+
+// candidates returns the installed families named by the comma-separated
+// list names, with generic names expanded, in order of preference.
+func (m *Manager) candidates(names string) [][]*Face {
+	if strings.TrimSpace(names) == "" {
+		names = SystemUI
+	}
+	var out [][]*Face
+	for _, name := range strings.Split(names, ",") {
+		key := strings.ToLower(strings.Trim(strings.TrimSpace(name), `"'`))
+		if key == "" {
+			continue
+		}
+		if faces := m.families[key]; len(faces) > 0 {
+			out = append(out, faces)
+		}
+		for _, alt := range m.generics[key] {
+			if faces := m.families[strings.ToLower(alt)]; len(faces) > 0 {
+				out = append(out, faces)
+			}
+		}
+	}
+	return out
+}
+
+func (m *Manager) sortedFamilies() []string {
+	names := make([]string, 0, len(m.families))
+	for name := range m.families {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func resolveWeight(w core.FontWeight) core.FontWeight {
+	if w == 0 {
+		return core.WeightRegular
+	}
+	return w
+}
+
+// bestFace returns the face of faces that matches weight and italic best.
+func bestFace(faces []*Face, weight core.FontWeight, italic bool) *Face {
+	var best *Face
+	for _, f := range faces {
+		if best == nil || faceRank(f, weight, italic) < faceRank(best, weight, italic) {
+			best = f
+		}
+	}
+	return best
+}
+
+// coveringFace returns the best matching face of faces that covers r.
+func coveringFace(faces []*Face, r rune, weight core.FontWeight, italic bool) *Face {
+	var best *Face
+	for _, f := range faces {
+		if f.Covers(r) && (best == nil || faceRank(f, weight, italic) < faceRank(best, weight, italic)) {
+			best = f
+		}
+	}
+	return best
+}
+
+// faceRank orders faces for a request, lower first: a face in the right
+// style before any other, then by weightRank. A variable face has every
+// weight of its wght axis, and is italic if it can be made so.
+func faceRank(f *Face, weight core.FontWeight, italic bool) int {
+	have := f.Weight
+	if a, ok := f.Axis("wght"); ok {
+		have = core.FontWeight(a.Clamp(float32(weight)))
+	}
+	r := weightRank(weight, have)
+	if f.Italic != italic && !(italic && isVariableItalic(f.Axes)) {
+		r += 10000
+	}
+	return r
+}
+
+// weightRank orders the weights of a family for a requested weight as CSS
+// does: for 400 to 500, heavier weights up to 500, then lighter ones, then
+// heavier ones; below 400 lighter weights first; above 500 heavier
+// weights first.
+func weightRank(want, have core.FontWeight) int {
+	d := int(have) - int(want)
+	switch {
+	case d == 0:
+		return 0
+	case want >= 400 && want <= 500:
+		if d > 0 && have <= 500 {
+			return d
+		}
+		if d < 0 {
+			return 1000 - d
+		}
+		return 2000 + d
+	case want < 400:
+		if d < 0 {
+			return -d
+		}
+		return 1000 + d
+	default:
+		if d > 0 {
+			return d
+		}
+		return 1000 - d
+	}
+}
+
+// scripts are the tables scriptOf tries first, for the most common
+// scripts, before the rest of unicode.Scripts.
+var scripts = []string{"Latin", "Cyrillic", "Greek", "Han", "Hiragana", "Katakana", "Hangul", "Arabic", "Hebrew", "Thai", "Devanagari"}
+
+var otherScripts = sync.OnceValue(func() []string {
+	var names []string
+	for name := range unicode.Scripts {
+		if name != "Common" && name != "Inherited" && !slices.Contains(scripts, name) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+})
+
+// scriptOf returns the name of the script of r in unicode.Scripts, or
+// Common.
+func scriptOf(r rune) string {
+	if r < 0x80 {
+		if unicode.IsLetter(r) {
+			return "Latin"
+		}
+		return Common
+	}
+	for _, name := range scripts {
+		if unicode.Is(unicode.Scripts[name], r) {
+			return name
+		}
+	}
+	for _, name := range otherScripts() {
+		if unicode.Is(unicode.Scripts[name], r) {
+			return name
+		}
+	}
+	return Common
+}
@@ -0,0 +1,242 @@
+package font
+
+import "encoding/binary"
+
+// data is a bounds-checked view of font table bytes. Reads past the end
+// return zero, so a corrupt table shapes badly instead of panicking.
+type data []byte
+
+func (d data) u8(off int) uint8 {
+	if off < 0 || off >= len(d) {
+		return 0
+	}
+	return d[off]
+}
+
+func (d data) u16(off int) uint16 {
+	if off < 0 || off+2 > len(d) {
+		return 0
+	}
+	return binary.BigEndian.Uint16(d[off:])
+}
+
+func (d data) i16(off int) int16 {
+	return int16(d.u16(off))
+}
+
+// u24 reads the 24-bit offsets of COLR paint tables.
+func (d data) u24(off int) uint32 {
+	if off < 0 || off+3 > len(d) {
+		return 0
+	}
+	return uint32(d[off])<<16 | uint32(d[off+1])<<8 | uint32(d[off+2])
+}
+
+func (d data) u32(off int) uint32 {
+	if off < 0 || off+4 > len(d) {
+		return 0
+	}
+	return binary.BigEndian.Uint32(d[off:])
+}
+
+func (d data) tag(off int) string {
+	if off < 0 || off+4 > len(d) {
+		return ""
+	}
+	return string(d[off : off+4])
+}
+
+// sub returns the data from off, or nil if off is zero or out of range,
+// as for a null offset.
+func (d data) sub(off int) data {
+	if off <= 0 || off >= len(d) {
+		return nil
+	}
+	return d[off:]
+}
+
+// coverage returns the coverage index of glyph g in a Coverage table, or
+// -1 if g is not covered.
+func (d data) coverage(g uint16) int {
+	switch d.u16(0) {
+	case 1:
+		n := int(d.u16(2))
+		lo, hi := 0, n
+		for lo < hi {
+			m := (lo + hi) / 2
+			v := d.u16(4 + 2*m)
+			switch {
+			case v == g:
+				return m
+			case v < g:
+				lo = m + 1
+			default:
+				hi = m
+			}
+		}
+	case 2:
+		n := int(d.u16(2))
+		lo, hi := 0, n
+		for lo < hi {
+			m := (lo + hi) / 2
+			rec := 4 + 6*m
+			start, end := d.u16(rec), d.u16(rec+2)
+			switch {
+			case g < start:
+				hi = m
+			case g > end:
+				lo = m + 1
+			default:
+				return int(d.u16(rec+4)) + int(g-start)
+			}
+		}
+	}
+	return -1
+}
+
+// class returns the class of glyph g in a ClassDef table, 0 if it has
+// none.
+func (d data) class(g uint16) uint16 {
+	switch d.u16(0) {
+	case 1:
+		start := d.u16(2)
+		n := d.u16(4)
+		if g >= start && g-start < n {
+			return d.u16(6 + 2*int(g-start))
+		}
+	case 2:
+		n := int(d.u16(2))
+		lo, hi := 0, n
+		for lo < hi {
+			m := (lo + hi) / 2
+			rec := 4 + 6*m
+			start, end := d.u16(rec), d.u16(rec+2)
+			switch {
+			case g < start:
+				hi = m
+			case g > end:
+				lo = m + 1
+			default:
+				return d.u16(rec + 4)
+			}
+		}
+	}
+	return 0
+}
+
+// layout is a parsed GSUB or GPOS table.
+type layout struct {
+	d        data
+	scripts  data
+	features data
+	lookups  data
+	gpos     bool
+}
+
+func parseLayout(d data, gpos bool) *layout {
+	if len(d) < 10 {
+		return nil
+	}
+	return &layout{
+		d:        d,
+		scripts:  d.sub(int(d.u16(4))),
+		features: d.sub(int(d.u16(6))),
+		lookups:  d.sub(int(d.u16(8))),
+		gpos:     gpos,
+	}
+}
+
+// langSys returns the default language system of the first of the script
+// tags the table has, and the tag found.
+func (l *layout) langSys(tags []string) (data, string) {
+	n := int(l.scripts.u16(0))
+	for _, want := range tags {
+		for i := range n {
+			rec := 2 + 6*i
+			if l.scripts.tag(rec) != want {
+				continue
+			}
+			script := l.scripts.sub(int(l.scripts.u16(rec + 4)))
+			if ls := script.sub(int(script.u16(0))); ls != nil {
+				return ls, want
+			}
+			// No default: use the first language system.
+			if script.u16(2) > 0 {
+				return script.sub(int(script.u16(6))), want
+			}
+		}
+	}
+	return nil, ""
+}
+
+// lookupsFor returns the lookup indices of the features of langSys with
+// tag, in the table's order.
+func (l *layout) lookupsFor(ls data, tag string) []uint16 {
+	if ls == nil {
+		return nil
+	}
+	var out []uint16
+	collect := func(fi uint16) {
+		rec := 2 + 6*int(fi)
+		if fi >= l.features.u16(0) || l.features.tag(rec) != tag {
+			return
+		}
+		f := l.features.sub(int(l.features.u16(rec + 4)))
+		for j := range int(f.u16(2)) {
+			out = append(out, f.u16(4+2*j))
+		}
+	}
+	if req := ls.u16(2); req != 0xFFFF {
+		collect(req)
+	}
+	for i := range int(ls.u16(4)) {
+		collect(ls.u16(6 + 2*i))
+	}
+	return out
+}
+
+// lookup is a lookup of a GSUB or GPOS table.
+type lookup struct {
+	kind      uint16
+	flag      uint16
+	filter    int // Mark filtering set, or -1.
+	subtables []data
+}
+
+// Lookup flags.
+const (
+	ignoreBase      = 0x0002
+	ignoreLigatures = 0x0004
+	ignoreMarks     = 0x0008
+	useMarkFilter   = 0x0010
+	markAttachType  = 0xFF00
+)
+
+// lookup returns lookup i with extension subtables resolved.
+func (l *layout) lookup(i uint16) lookup {
+	if i >= l.lookups.u16(0) {
+		return lookup{}
+	}
+	lk := l.lookups.sub(int(l.lookups.u16(2 + 2*int(i))))
+	out := lookup{kind: lk.u16(0), flag: lk.u16(2), filter: -1}
+	n := int(lk.u16(4))
+	if out.flag&useMarkFilter != 0 {
+		out.filter = int(lk.u16(6 + 2*n))
+	}
+	ext := uint16(7)
+	if l.gpos {
+		ext = 9
+	}
+	isExt := out.kind == ext
+	for j := range n {
+		st := lk.sub(int(lk.u16(6 + 2*j)))
+		if isExt {
+			out.kind = st.u16(2)
+			st = st.sub(int(st.u32(4)))
+		}
+		if st != nil {
+			out.subtables = append(out.subtables, st)
+		}
+	}
+	return out
+}
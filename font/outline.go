@@ -0,0 +1,299 @@
+package font
+
+import (
+	"github.com/gogpu/ui/core"
+)
+
+// maxComponentDepth bounds the nesting of composite glyphs, against fonts
+// whose components refer to each other.
+const maxComponentDepth = 8
+
+// Outline returns the outline of glyph g at size pixels per em, with y
+// growing down from the pen position on the baseline, to be filled with
+// the nonzero rule, and false if the font has no outline for g, as bitmap
+// emoji fonts have none. Outlines are read from glyf or CFF tables,
+// unhinted and at the default instance of variable fonts. A glyph with
+// nothing to draw, such as a space, has an empty outline.
+func (f *Font) Outline(g uint16, size float32) (*core.Path, bool) {
+	if int(g) >= f.numGlyphs {
+		return nil, false
+	}
+	o := outliner{path: core.NewPath(), scale: size / f.upem}
+	switch {
+	case f.loca != nil && f.glyf != nil:
+		if !f.glyphOutline(&o, g, identity, 0) {
+			return nil, false
+		}
+	case f.cff != nil:
+		if !f.cff.outline(&o, g) {
+			return nil, false
+		}
+	default:
+		return nil, false
+	}
+	return o.path, true
+}
+
+// affine is a transform of the points of a composite glyph's component,
+// x' = a*x + c*y + e and y' = b*x + d*y + f, in font units.
+type affine struct {
+	a, b, c, d, e, f float32
+}
+
+var identity = affine{a: 1, d: 1}
+
+func (t affine) apply(x, y float32) (float32, float32) {
+	return t.a*x + t.c*y + t.e, t.b*x + t.d*y + t.f
+}
+
+// then returns the transform applying t and then u.
+func (t affine) then(u affine) affine {
+	return affine{
+		a: u.a*t.a + u.c*t.b,
+		b: u.b*t.a + u.d*t.b,
+		c: u.a*t.c + u.c*t.d,
+		d: u.b*t.c + u.d*t.d,
+		e: u.a*t.e + u.c*t.f + u.e,
+		f: u.b*t.e + u.d*t.f + u.f,
+	}
+}
+
+// outliner builds a path from points in font units, y up.
+type outliner struct {
+	path  *core.Path
+	scale float32
+	open  bool // A contour was started and not closed.
+}
+
+func (o *outliner) pt(x, y float32) core.Point {
+	return core.Pt(x*o.scale, -y*o.scale)
+}
+
+func (o *outliner) moveTo(x, y float32) {
+	o.close()
+	o.path.MoveTo(o.pt(x, y))
+	o.open = true
+}
+
+func (o *outliner) lineTo(x, y float32) {
+	o.path.LineTo(o.pt(x, y))
+}
+
+func (o *outliner) quadTo(cx, cy, x, y float32) {
+	o.path.QuadTo(o.pt(cx, cy), o.pt(x, y))
+}
+
+func (o *outliner) cubeTo(c1x, c1y, c2x, c2y, x, y float32) {
+	o.path.CubicTo(o.pt(c1x, c1y), o.pt(c2x, c2y), o.pt(x, y))
+}
+
+func (o *outliner) close() {
+	if o.open {
+		o.path.Close()
+		o.open = false
+	}
+}
+
+// glyphData returns the glyf entry of g, empty for a glyph without
+// contours, and false if loca points outside glyf.
+func (f *Font) glyphData(g uint16) (data, bool) {
+	var start, end int
+	if f.longLoca {
+		start, end = int(f.loca.u32(4*int(g))), int(f.loca.u32(4*int(g)+4))
+	} else {
+		start, end = 2*int(f.loca.u16(2*int(g))), 2*int(f.loca.u16(2*int(g)+2))
+	}
+	if start > end || end > len(f.glyf) {
+		return nil, false
+	}
+	return f.glyf[start:end], true
+}
+
+// glyphOutline adds the contours of g from the glyf table to o, through
+// t, following the components of composite glyphs.
+func (f *Font) glyphOutline(o *outliner, g uint16, t affine, depth int) bool {
+	d, ok := f.glyphData(g)
+	if !ok {
+		return false
+	}
+	if len(d) == 0 {
+		return true
+	}
+	n := int(d.i16(0))
+	if n >= 0 {
+		return simpleOutline(o, d, n, t)
+	}
+	if depth >= maxComponentDepth {
+		return false
+	}
+	// Component flags.
+	const (
+		argWords    = 0x0001
+		argsXY      = 0x0002
+		scaleOne    = 0x0008
+		moreComps   = 0x0020
+		scaleXY     = 0x0040
+		scaleMatrix = 0x0080
+	)
+	for off := 10; ; {
+		flags, comp := d.u16(off), d.u16(off+2)
+		off += 4
+		var dx, dy float32
+		if flags&argWords != 0 {
+			dx, dy = float32(d.i16(off)), float32(d.i16(off+2))
+			off += 4
+		} else {
+			dx, dy = float32(int8(d.u8(off))), float32(int8(d.u8(off+1)))
+			off += 2
+		}
+		if flags&argsXY == 0 {
+			// Components placed by matching points are left unmoved.
+			dx, dy = 0, 0
+		}
+		c := affine{a: 1, d: 1, e: dx, f: dy}
+		switch {
+		case flags&scaleOne != 0:
+			c.a = f2dot14(d.i16(off))
+			c.d = c.a
+			off += 2
+		case flags&scaleXY != 0:
+			c.a, c.d = f2dot14(d.i16(off)), f2dot14(d.i16(off+2))
+			off += 4
+		case flags&scaleMatrix != 0:
+			c.a, c.b = f2dot14(d.i16(off)), f2dot14(d.i16(off+2))
+			c.c, c.d = f2dot14(d.i16(off+4)), f2dot14(d.i16(off+6))
+			off += 8
+		}
+		if off > len(d) || !f.glyphOutline(o, comp, c.then(t), depth+1) {
+			return false
+		}
+		if flags&moreComps == 0 {
+			return true
+		}
+	}
+}
+
+// simpleOutline adds the n contours of the simple glyph d to o.
+func simpleOutline(o *outliner, d data, n int, t affine) bool {
+	// Point flags.
+	const (
+		onCurve = 0x01
+		xShort  = 0x02
+		yShort  = 0x04
+		repeat  = 0x08
+		xSame   = 0x10 // Or positive, for a short x.
+		ySame   = 0x20
+	)
+	ends := make([]int, n)
+	for i := range ends {
+		ends[i] = int(d.u16(10 + 2*i))
+		if i > 0 && ends[i] < ends[i-1] {
+			return false
+		}
+	}
+	if n == 0 {
+		return true
+	}
+	points := ends[n-1] + 1
+	off := 10 + 2*n
+	off += 2 + int(d.u16(off)) // Instructions.
+	flags := make([]byte, 0, points)
+	for len(flags) < points {
+		if off >= len(d) {
+			return false
+		}
+		fl := d[off]
+		off++
+		flags = append(flags, fl)
+		if fl&repeat != 0 {
+			for k := d.u8(off); k > 0 && len(flags) < points; k-- {
+				flags = append(flags, fl)
+			}
+			off++
+		}
+	}
+	coords := func(short, same byte) ([]float32, bool) {
+		out := make([]float32, points)
+		var v float32
+		for i, fl := range flags {
+			switch {
+			case fl&short != 0:
+				dv := float32(d.u8(off))
+				off++
+				if fl&same == 0 {
+					dv = -dv
+				}
+				v += dv
+			case fl&same == 0:
+				v += float32(d.i16(off))
+				off += 2
+			}
+			out[i] = v
+		}
+		return out, off <= len(d)
+	}
+	xs, ok := coords(xShort, xSame)
+	if !ok {
+		return false
+	}
+	ys, ok := coords(yShort, ySame)
+	if !ok {
+		return false
+	}
+	start := 0
+	for _, end := range ends {
+		contour(o, xs[start:end+1], ys[start:end+1], flags[start:end+1], t)
+		start = end + 1
+	}
+	return true
+}
+
+// contour adds a closed contour of quadratic curves to o: between two
+// points off the curve lies one on it, halfway.
+func contour(o *outliner, xs, ys []float32, flags []byte, t affine) {
+	n := len(xs)
+	if n == 0 {
+		return
+	}
+	on := func(i int) bool { return flags[i%n]&1 != 0 }
+	at := func(i int) (float32, float32) { return t.apply(xs[i%n], ys[i%n]) }
+	mid := func(i, j int) (float32, float32) {
+		x0, y0 := at(i)
+		x1, y1 := at(j)
+		return (x0 + x1) / 2, (y0 + y1) / 2
+	}
+	// Start on the curve: at the first point on it, or between the first
+	// two if none is.
+	first := 0
+	for first < n && !on(first) {
+		first++
+	}
+	var sx, sy float32
+	if first == n {
+		first = 0
+		sx, sy = mid(0, 1)
+	} else {
+		sx, sy = at(first)
+	}
+	o.moveTo(sx, sy)
+	for k := 1; k <= n; k++ {
+		i := first + k
+		if on(i) {
+			o.lineTo(at(i))
+			continue
+		}
+		cx, cy := at(i)
+		var x, y float32
+		switch {
+		case k == n:
+			x, y = sx, sy
+		case on(i + 1):
+			x, y = at(i + 1)
+			k++
+		default:
+			x, y = mid(i, i+1)
+		}
+		o.quadTo(cx, cy, x, y)
+	}
+	o.close()
+}
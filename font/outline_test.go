@@ -0,0 +1,263 @@
+package font
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+)
+
+// describe writes p as SVG-like commands, for comparing outlines.
+func describe(p *core.Path) string {
+	var b strings.Builder
+	for _, s := range p.Segments {
+		b.WriteString([]string{"M", "L", "Q", "C", "Z"}[s.Verb])
+		for _, pt := range s.Points[:[]int{1, 1, 2, 3, 0}[s.Verb]] {
+			// Adding zero turns -0 into 0.
+			fmt.Fprintf(&b, " %g,%g", pt.X+0, pt.Y+0)
+		}
+		b.WriteString(" ")
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// cs encodes the numbers and operators of a Type 2 charstring: ints are
+// numbers and strings operators.
+func cs(items ...any) []byte {
+	ops := map[string][]byte{
+		"hstem": {1}, "rlineto": {5}, "hlineto": {6}, "callsubr": {10}, "return": {11},
+		"endchar": {14}, "hintmask": {19}, "rmoveto": {21}, "hmoveto": {22}, "hvcurveto": {31},
+	}
+	var b fontBuilder
+	for _, it := range items {
+		switch v := it.(type) {
+		case int:
+			if v >= -107 && v <= 107 {
+				b = append(b, byte(v+139))
+			} else {
+				b = append(b, 28)
+				b.u16(uint16(int16(v)))
+			}
+		case string:
+			op, ok := ops[v]
+			if !ok {
+				// A hint mask's bytes.
+				op = []byte(v)
+			}
+			b = append(b, op...)
+		}
+	}
+	return b
+}
+
+// cffIndex lays items out as a CFF INDEX with one-byte offsets.
+func cffIndex(items ...[]byte) []byte {
+	var b fontBuilder
+	b.u16(uint16(len(items)))
+	if len(items) == 0 {
+		return b
+	}
+	b = append(b, 1)
+	off := 1
+	b = append(b, byte(off))
+	for _, it := range items {
+		off += len(it)
+		b = append(b, byte(off))
+	}
+	for _, it := range items {
+		b.bytes(it)
+	}
+	return b
+}
+
+// testCFF returns a CFF table with the outlines of the test font, f as
+// in testGlyf and A and V drawn with other operators, and whether it is
+// CID-keyed, with the glyphs in font dicts of their own.
+func testCFF(cid bool) []byte {
+	glyphs := make([][]byte, numTestGlyphs)
+	for g := range glyphs {
+		glyphs[g] = cs("endchar")
+	}
+	// A width before the first operator, then the rectangle of f.
+	glyphs[gidF] = cs(500, 100, 0, "rmoveto", 300, 0, 0, 700, -300, 0, "rlineto", "endchar")
+	// A curve in local subroutine 0, the first, numbered by the bias.
+	glyphs[gidA] = cs(0, 0, "rmoveto", -107, "callsubr", "endchar")
+	// Stems and a hint mask with stems of its own, then lines alternating
+	// from horizontal.
+	glyphs[gidV] = cs(10, 20, "hstem", 30, 40, "hintmask", "\xC0", 0, "hmoveto", 100, 100, -100, "hlineto", "endchar")
+	subrs := cffIndex(cs(50, 100, 50, -100, "hvcurveto", "return"))
+	charStrings := cffIndex(glyphs...)
+
+	// The DICTs take offsets as 32-bit numbers, so they are as long
+	// whatever the offsets.
+	num := func(v int) []byte {
+		var b fontBuilder
+		return append([]byte{29}, *b.u32(uint32(v))...)
+	}
+	var private fontBuilder
+	private.bytes(num(len(num(0)) + 1)).bytes([]byte{opSubrs}) // Subrs just after it.
+
+	header := []byte{1, 0, 4, 1}
+	names := cffIndex([]byte("T"))
+	topLen := 6 + 11 // CharStrings and Private.
+	if cid {
+		topLen = 6 + 5 + 7 + 7 // CharStrings, a ROS of zeros, FDArray and FDSelect.
+	}
+	strs, gsubrs := cffIndex(), cffIndex()
+	start := len(header) + len(names) + 2 + 1 + 2 + topLen + len(strs) + len(gsubrs)
+	charAt := start
+	privAt := charAt + len(charStrings)
+	fdArrayAt := privAt + len(private) + len(subrs)
+
+	var top fontBuilder
+	if cid {
+		top.bytes([]byte{139, 139, 139, 12, 30}) // ROS.
+	}
+	top.bytes(num(charAt)).bytes([]byte{opCharStrings})
+	var fdArray, fdSelect []byte
+	if cid {
+		// Font dict 1 has the subroutines; 0 has none, and holds f only.
+		var fd0, fd1 fontBuilder
+		fd0.bytes(num(0)).bytes(num(privAt)).bytes([]byte{opPrivate})
+		fd1.bytes(num(len(private))).bytes(num(privAt)).bytes([]byte{opPrivate})
+		fdArray = cffIndex(fd0, fd1)
+		// Font dict 1 for glyph 0, 0 for f, and 1 from i on.
+		var sel fontBuilder
+		sel = append(sel, 3)
+		sel.u16(3, 0)
+		sel = append(sel, 1)
+		sel.u16(gidF)
+		sel = append(sel, 0)
+		sel.u16(gidI)
+		sel = append(sel, 1)
+		sel.u16(uint16(numTestGlyphs))
+		fdSelect = sel
+		top.bytes(num(fdArrayAt)).bytes([]byte{12, 36})
+		top.bytes(num(fdArrayAt + len(fdArray))).bytes([]byte{12, 37})
+	} else {
+		top.bytes(num(len(private))).bytes(num(privAt)).bytes([]byte{opPrivate})
+	}
+	if len(top) != topLen {
+		panic(fmt.Sprintf("top DICT of %d bytes, want %d", len(top), topLen))
+	}
+
+	var b fontBuilder
+	b.bytes(header).bytes(names).bytes(cffIndex(top)).bytes(strs).bytes(gsubrs)
+	b.bytes(charStrings).bytes(private).bytes(subrs).bytes(fdArray).bytes(fdSelect)
+	return b
+}
+
+// cffFont returns the tables of the test font with CFF outlines.
+func cffFont(cid bool) map[string][]byte {
+	tables := testFont(false)
+	delete(tables, "glyf")
+	delete(tables, "loca")
+	tables["CFF "] = testCFF(cid)
+	return tables
+}
+
+func TestOutline(t *testing.T) {
+	glyf := map[uint16]string{
+		gidF: "M 100,0 L 400,0 L 400,-700 L 100,-700 L 100,0 Z",
+		gidI: "",
+		gidA: "M 0,0 Q 100,-200 200,0 L 0,0 Z",
+		gidV: "M 50,0 Q 100,0 100,-50 Q 100,-100 50,-100 Q 0,-100 0,-50 Q 0,0 50,0 Z",
+		gidFI: "M 600,0 L 900,0 L 900,-700 L 600,-700 L 600,0 Z " +
+			"M 10,10 Q 60,-90 110,10 L 10,10 Z",
+	}
+	cff := map[uint16]string{
+		gidF: "M 100,0 L 400,0 L 400,-700 L 100,-700 Z",
+		gidI: "",
+		gidA: "M 0,0 C 50,0 150,-50 150,50 Z",
+		gidV: "M 0,0 L 100,0 L 100,-100 L 0,-100 Z",
+	}
+	longLoca := testFont(false)
+	glyfData, short := testGlyf()
+	var long fontBuilder
+	for i := 0; i < len(short); i += 2 {
+		long.u32(2 * uint32(data(short).u16(i)))
+	}
+	longLoca["loca"] = long
+	longLoca["glyf"] = glyfData
+	head := longLoca["head"]
+	head[51] = 1
+
+	tests := []struct {
+		name   string
+		tables map[string][]byte
+		want   map[uint16]string
+	}{
+		{"glyf", testFont(false), glyf},
+		{"glyf with long offsets", longLoca, glyf},
+		{"CFF", cffFont(false), cff},
+		{"CID-keyed CFF", cffFont(true), cff},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := ParseFont(sfnt(tt.tables), 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for g, want := range tt.want {
+				// At 1000 pixels per em a font unit is a pixel.
+				p, ok := f.Outline(g, 1000)
+				if !ok {
+					t.Errorf("Outline(%d) failed", g)
+					continue
+				}
+				if got := describe(p); got != want {
+					t.Errorf("Outline(%d) = %s, want %s", g, got, want)
+				}
+			}
+			if p, ok := f.Outline(gidF, 500); !ok || p.Bounds() != core.R(50, -350, 150, 350) {
+				t.Errorf("Outline at 500 pixels per em: bounds %v, want halved", p.Bounds())
+			}
+			if _, ok := f.Outline(numTestGlyphs, 1000); ok {
+				t.Error("Outline of a glyph past the last succeeded")
+			}
+		})
+	}
+}
+
+func TestOutlineWithout(t *testing.T) {
+	tables := testFont(false)
+	delete(tables, "glyf")
+	delete(tables, "loca")
+	f, err := ParseFont(sfnt(tables), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := f.Outline(gidF, 16); ok {
+		t.Error("Outline of a font without outlines succeeded")
+	}
+}
+
+// TestCorruptCFF checks that truncated and corrupt CFF tables parse and
+// draw without panicking.
+func TestCorruptCFF(t *testing.T) {
+	for _, cid := range []bool{false, true} {
+		tables := cffFont(cid)
+		table := tables["CFF "]
+		use := func(b []byte) {
+			tables["CFF "] = b
+			f, err := ParseFont(sfnt(tables), 0)
+			if err != nil {
+				return
+			}
+			for g := range numTestGlyphs + 1 {
+				f.Outline(g, 16)
+			}
+		}
+		for n := range len(table) {
+			use(table[:n])
+		}
+		for i := range table {
+			for _, v := range []byte{0x00, 0x0A, 0x1D, 0x7F, 0xFF} {
+				b := append([]byte(nil), table...)
+				b[i] = v
+				use(b)
+			}
+		}
+	}
+}
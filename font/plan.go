@@ -0,0 +1,117 @@
+package font
+
+import "unicode"
+
+// plan is how a script is shaped: the preparation of the buffer before
+// substitution, and the GSUB features applied in stages.
+type plan struct {
+	prepare func(s *shaper)
+	stages  [][]string
+}
+
+var defaultPlan = plan{
+	prepare: func(*shaper) {},
+	stages:  [][]string{{"ccmp", "locl"}, {"rlig"}, {"calt", "clig", "liga", "rclt"}},
+}
+
+var arabicPlan = plan{
+	prepare: joinArabic,
+	stages: [][]string{
+		{"ccmp", "locl"},
+		{"isol"}, {"fina"}, {"medi"}, {"init"},
+		{"rlig"}, {"calt"}, {"clig", "liga", "mset"},
+	},
+}
+
+var indicPlan = plan{
+	prepare: reorderIndic,
+	stages: [][]string{
+		{"locl", "ccmp"},
+		{"nukt"}, {"akhn"}, {"rphf"}, {"rkrf"}, {"pref"}, {"blwf"}, {"abvf"}, {"half"}, {"pstf"}, {"vatu"}, {"cjct"},
+		{"init", "pres", "abvs", "blws", "psts", "haln"},
+		{"calt", "clig", "liga", "rclt"},
+	},
+}
+
+func shaperFor(script string) plan {
+	switch script {
+	case "Arabic", "Syriac", "Nko":
+		return arabicPlan
+	}
+	if _, ok := indicScripts[script]; ok {
+		return indicPlan
+	}
+	return defaultPlan
+}
+
+// Arabic joining types.
+const (
+	joinNone  = iota // U: joins with neither neighbor.
+	joinRight        // R: joins with the character before it only.
+	joinDual         // D: joins on both sides.
+	joinCause        // C: makes its neighbors join, like tatweel.
+	joinTrans        // T: transparent, like marks.
+)
+
+// rightJoining lists the Arabic letters that only join to the letter
+// before them; the other letters of the block are dual joining.
+var rightJoining = []rune{
+	0x0622, 0x0623, 0x0624, 0x0625, 0x0627, 0x0629, 0x062F, 0x0630, 0x0631,
+	0x0632, 0x0648, 0x0671, 0x0672, 0x0673, 0x0675, 0x0676, 0x0677, 0x06C0,
+	0x06C3, 0x06C4, 0x06C5, 0x06C6, 0x06C7, 0x06C8, 0x06C9, 0x06CA, 0x06CB,
+	0x06CD, 0x06CF, 0x06D2, 0x06D3, 0x06D5, 0x06EE, 0x06EF,
+}
+
+func joiningType(r rune) int {
+	switch {
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) && r != 0x200D && r != 0x200C:
+		return joinTrans
+	case r == 0x0640 || r == 0x200D:
+		return joinCause
+	case r >= 0x0688 && r <= 0x0699:
+		return joinRight
+	case r >= 0x0620 && r <= 0x06FF || r >= 0x0750 && r <= 0x077F || r >= 0x08A0 && r <= 0x08FF:
+		for _, rj := range rightJoining {
+			if r == rj {
+				return joinRight
+			}
+		}
+		if r == 0x0621 || r == 0x0674 || r == 0x06DD || !unicode.IsLetter(r) {
+			return joinNone
+		}
+		return joinDual
+	}
+	return joinNone
+}
+
+// joinArabic marks each letter with the joining form it takes between
+// its neighbors: isolated, initial, medial or final.
+func joinArabic(s *shaper) {
+	prev := -1 // The last character that is not transparent.
+	types := make([]int, len(s.buf))
+	forms := make([]uint32, len(s.buf))
+	for i := range s.buf {
+		t := joiningType(s.buf[i].r)
+		types[i] = t
+		if t == joinTrans {
+			continue
+		}
+		forms[i] = maskIsol
+		if prev >= 0 && (types[prev] == joinDual || types[prev] == joinCause) && (t == joinRight || t == joinDual || t == joinCause) {
+			// prev joins forward to i.
+			if forms[prev] == maskFina || forms[prev] == maskMedi {
+				forms[prev] = maskMedi
+			} else {
+				forms[prev] = maskInit
+			}
+			forms[i] = maskFina
+		}
+		prev = i
+	}
+	for i := range s.buf {
+		if types[i] == joinNone || types[i] == joinTrans || types[i] == joinCause {
+			continue
+		}
+		s.buf[i].mask |= forms[i]
+	}
+}
@@ -0,0 +1,316 @@
+package font
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/gogpu/ui/core"
+)
+
+// ErrFormat is returned for data that is not a TrueType or OpenType font
+// or collection.
+var ErrFormat = errors.New("font: not a TrueType or OpenType font")
+
+const (
+	tagCollection = 0x74746366 // "ttcf"
+	tagOpenType   = 0x4F54544F // "OTTO"
+	tagTrue       = 0x74727565 // "true"
+	tagTrueType   = 0x00010000
+
+	tagName = 0x6E616D65 // "name"
+	tagOS2  = 0x4F532F32 // "OS/2"
+	tagCmap = 0x636D6170 // "cmap"
+	tagFvar = 0x66766172 // "fvar"
+
+	maxTables = 256 // Bound on the table directory, against corrupt files.
+)
+
+type table struct {
+	offset, length uint32
+}
+
+type reader struct {
+	r   io.ReaderAt
+	buf []byte
+}
+
+func (r *reader) read(off int64, n int) ([]byte, error) {
+	if n < 0 || n > 1<<26 {
+		return nil, ErrFormat
+	}
+	if cap(r.buf) < n {
+		r.buf = make([]byte, n)
+	}
+	b := r.buf[:n]
+	if _, err := r.r.ReadAt(b, off); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// parseFaces reads the faces of the font or collection in r, taken from
+// path.
+func parseFaces(r io.ReaderAt, path string) ([]*Face, error) {
+	rd := &reader{r: r}
+	hdr, err := rd.read(0, 12)
+	if err != nil {
+		return nil, ErrFormat
+	}
+	offsets := []uint32{0}
+	if binary.BigEndian.Uint32(hdr) == tagCollection {
+		n := binary.BigEndian.Uint32(hdr[8:])
+		if n == 0 || n > maxTables {
+			return nil, ErrFormat
+		}
+		b, err := rd.read(12, int(4*n))
+		if err != nil {
+			return nil, ErrFormat
+		}
+		offsets = make([]uint32, n)
+		for i := range offsets {
+			offsets[i] = binary.BigEndian.Uint32(b[4*i:])
+		}
+	}
+	var faces []*Face
+	for i, off := range offsets {
+		f, err := parseFace(rd, int64(off))
+		if err != nil {
+			continue
+		}
+		f.Path, f.Index = path, i
+		if path == "" {
+			f.src = r
+		}
+		faces = append(faces, f)
+	}
+	if len(faces) == 0 {
+		return nil, ErrFormat
+	}
+	return faces, nil
+}
+
+func parseFace(rd *reader, off int64) (*Face, error) {
+	tables, err := readDirectory(rd, off)
+	if err != nil {
+		return nil, err
+	}
+	f := &Face{Weight: core.WeightRegular, tables: tables}
+	var names []byte
+	if t, ok := tables[tagName]; ok {
+		b, err := rd.read(int64(t.offset), int(t.length))
+		if err == nil {
+			names = slices.Clone(b)
+			f.Family, f.Style = parseNames(names)
+		}
+	}
+	if f.Family == "" {
+		return nil, ErrFormat
+	}
+	if t, ok := tables[tagFvar]; ok {
+		b, err := rd.read(int64(t.offset), int(t.length))
+		if err == nil {
+			f.Axes, f.Instances = parseFvar(data(b), names)
+		}
+	}
+	style := strings.ToLower(f.Style)
+	f.Italic = strings.Contains(style, "italic") || strings.Contains(style, "oblique")
+	if strings.Contains(style, "bold") {
+		f.Weight = core.WeightBold
+	}
+	if t, ok := tables[tagOS2]; ok && t.length >= 64 {
+		b, err := rd.read(int64(t.offset), 64)
+		if err == nil {
+			w := binary.BigEndian.Uint16(b[4:])
+			if w > 0 && w < 10 {
+				// Some old fonts give the weight in hundreds.
+				w *= 100
+			}
+			if w >= 1 && w <= 1000 {
+				f.Weight = core.FontWeight(w)
+			}
+			sel := binary.BigEndian.Uint16(b[62:])
+			f.Italic = sel&(1<<0|1<<9) != 0
+		}
+	}
+	return f, nil
+}
+
+func readDirectory(rd *reader, off int64) (map[uint32]table, error) {
+	b, err := rd.read(off, 12)
+	if err != nil {
+		return nil, ErrFormat
+	}
+	switch binary.BigEndian.Uint32(b) {
+	case tagTrueType, tagOpenType, tagTrue:
+	default:
+		return nil, ErrFormat
+	}
+	n := int(binary.BigEndian.Uint16(b[4:]))
+	if n > maxTables {
+		return nil, ErrFormat
+	}
+	b, err = rd.read(off+12, 16*n)
+	if err != nil {
+		return nil, ErrFormat
+	}
+	tables := make(map[uint32]table, n)
+	for i := range n {
+		rec := b[16*i:]
+		tables[binary.BigEndian.Uint32(rec)] = table{
+			offset: binary.BigEndian.Uint32(rec[8:]),
+			length: binary.BigEndian.Uint32(rec[12:]),
+		}
+	}
+	return tables, nil
+}
+
+// parseNames returns the family and subfamily from a name table,
+// preferring the typographic names.
+func parseNames(b []byte) (family, style string) {
+	names := parseNameTable(b, func(id uint16) bool { return id == 1 || id == 2 || id == 16 || id == 17 })
+	family, style = names[16], names[17]
+	if family == "" {
+		family = names[1]
+	}
+	if style == "" {
+		style = names[2]
+	}
+	return family, style
+}
+
+// parseNameTable returns the names of a name table with the IDs want
+// accepts, preferring English Windows records.
+func parseNameTable(b []byte, want func(id uint16) bool) map[uint16]string {
+	names := make(map[uint16]string)
+	if len(b) < 6 {
+		return names
+	}
+	n := int(binary.BigEndian.Uint16(b[2:]))
+	strs := int(binary.BigEndian.Uint16(b[4:]))
+	rank := make(map[uint16]int)
+	for i := range n {
+		rec := 6 + 12*i
+		if rec+12 > len(b) {
+			break
+		}
+		platform := binary.BigEndian.Uint16(b[rec:])
+		lang := binary.BigEndian.Uint16(b[rec+4:])
+		id := binary.BigEndian.Uint16(b[rec+6:])
+		length := int(binary.BigEndian.Uint16(b[rec+8:]))
+		offset := int(binary.BigEndian.Uint16(b[rec+10:]))
+		if !want(id) {
+			continue
+		}
+		start := strs + offset
+		if start+length > len(b) {
+			continue
+		}
+		raw := b[start : start+length]
+		var s string
+		r := 0
+		switch {
+		case platform == 3 && lang == 0x409:
+			s, r = decodeUTF16(raw), 3
+		case platform == 3 || platform == 0:
+			s, r = decodeUTF16(raw), 2
+		case platform == 1 && lang == 0:
+			s, r = string(raw), 1
+		default:
+			continue
+		}
+		if s != "" && r > rank[id] {
+			names[id], rank[id] = s, r
+		}
+	}
+	return names
+}
+
+func decodeUTF16(b []byte) string {
+	u := make([]uint16, len(b)/2)
+	for i := range u {
+		u[i] = binary.BigEndian.Uint16(b[2*i:])
+	}
+	return string(utf16.Decode(u))
+}
+
+// runeRange is an inclusive range of code points.
+type runeRange struct {
+	lo, hi rune
+}
+
+// parseCmap returns the code points mapped by the best Unicode subtable of
+// a cmap table, as sorted ranges.
+func parseCmap(b []byte) []runeRange {
+	if len(b) < 4 {
+		return nil
+	}
+	n := int(binary.BigEndian.Uint16(b[2:]))
+	best, bestRank := -1, 0
+	for i := range n {
+		rec := 4 + 8*i
+		if rec+8 > len(b) {
+			break
+		}
+		platform := binary.BigEndian.Uint16(b[rec:])
+		enc := binary.BigEndian.Uint16(b[rec+2:])
+		off := int(binary.BigEndian.Uint32(b[rec+4:]))
+		r := 0
+		switch {
+		case platform == 3 && enc == 10, platform == 0 && enc >= 4:
+			r = 3
+		case platform == 3 && enc == 1, platform == 0:
+			r = 2
+		case platform == 3 && enc == 0:
+			r = 1
+		}
+		if r > bestRank && off+4 <= len(b) {
+			best, bestRank = off, r
+		}
+	}
+	if best < 0 {
+		return nil
+	}
+	sub := b[best:]
+	var ranges []runeRange
+	switch binary.BigEndian.Uint16(sub) {
+	case 4:
+		if len(sub) < 14 {
+			return nil
+		}
+		segs := int(binary.BigEndian.Uint16(sub[6:])) / 2
+		if 16+8*segs > len(sub) {
+			return nil
+		}
+		ends, starts := sub[14:], sub[16+2*segs:]
+		for i := range segs {
+			lo, hi := rune(binary.BigEndian.Uint16(starts[2*i:])), rune(binary.BigEndian.Uint16(ends[2*i:]))
+			if hi == 0xFFFF {
+				hi--
+			}
+			if lo <= hi {
+				ranges = append(ranges, runeRange{lo, hi})
+			}
+		}
+	case 12:
+		if len(sub) < 16 {
+			return nil
+		}
+		groups := int(binary.BigEndian.Uint32(sub[12:]))
+		if groups < 0 || 16+12*groups > len(sub) {
+			return nil
+		}
+		for i := range groups {
+			g := sub[16+12*i:]
+			lo, hi := rune(binary.BigEndian.Uint32(g)), rune(binary.BigEndian.Uint32(g[4:]))
+			if lo <= hi {
+				ranges = append(ranges, runeRange{lo, hi})
+			}
+		}
+	}
+	slices.SortFunc(ranges, func(a, b runeRange) int { return int(a.lo - b.lo) })
+	return ranges
+}
@@ -0,0 +1,398 @@
+package font
+
+import (
+	"slices"
+	"unicode"
+
+	"github.com/gogpu/ui/bidi"
+	"github.com/gogpu/ui/core"
+)
+
+// Glyph is a glyph of shaped text, positioned relative to the pen.
+type Glyph struct {
+	// ID is the index of the glyph in the font.
+	ID uint16
+
+	// Cluster is the byte offset in the text of the first character the
+	// glyph was formed from. The glyphs of a ligature, or of a syllable
+	// whose characters were reordered, share it.
+	Cluster int
+
+	// Advance is how far the pen moves after the glyph, in pixels.
+	Advance float32
+
+	// Offset moves the glyph from the pen position, with y growing down,
+	// as when a mark is attached above its base.
+	Offset core.Point
+}
+
+// Feature turns an OpenType feature on or off, such as Feature{"liga",
+// false} to disable ligatures or Feature{"tnum", true} for tabular
+// figures.
+type Feature struct {
+	Tag string
+	On  bool
+}
+
+// GDEF glyph classes.
+const (
+	classBase     = 1
+	classLigature = 2
+	classMark     = 3
+)
+
+// maxNesting bounds the nesting of contextual lookups, against fonts that
+// loop.
+const maxNesting = 8
+
+// Feature masks. A glyph is processed by a feature if its mask has the
+// feature's bit; every glyph has maskGlobal.
+const (
+	maskGlobal uint32 = 1 << iota
+	maskIsol
+	maskFina
+	maskMedi
+	maskInit
+	maskRphf
+	maskHalf
+)
+
+var localFeatures = map[string]uint32{
+	"isol": maskIsol,
+	"fina": maskFina,
+	"medi": maskMedi,
+	"init": maskInit,
+	"rphf": maskRphf,
+	"half": maskHalf,
+}
+
+type glyphInfo struct {
+	id      uint16
+	r       rune
+	cluster int
+	mask    uint32
+	class   uint16
+
+	adv, dx, dy float32 // In font units, y up.
+}
+
+type shaper struct {
+	font  *Font
+	table *layout
+	buf   []glyphInfo
+	rtl   bool
+	gpos  bool
+	depth int
+}
+
+// Shape converts text into positioned glyphs for drawing at size pixels
+// per em, applying the font's ligatures, contextual forms, kerning and
+// mark positioning, with the features given turned on or off.
+//
+// The text is split into runs of one script, each shaped by the rules of
+// its script: Arabic letters take their joining forms, Devanagari and
+// Bengali syllables are reordered with reph and pre-base vowel signs
+// moved, and marks are attached to their bases. The glyphs of a right to
+// left run are returned in visual order, with brackets and other paired
+// characters mirrored; the runs themselves stay in logical order, and
+// bidirectional reordering of mixed text is left to the caller, which
+// can use package bidi. Invisible characters the font has no glyph for,
+// such as zero-width joiners and variation selectors left over from emoji
+// sequences, produce no glyph rather than a missing-glyph box.
+func (f *Font) Shape(text string, size float32, features ...Feature) []Glyph {
+	var out []Glyph
+	scale := size / f.upem
+	for _, run := range scriptRuns(text) {
+		s := &shaper{font: f, rtl: isRTLScript(run.script)}
+		s.load(text, run.start, run.end)
+		plan := shaperFor(run.script)
+		plan.prepare(s)
+		s.substituteAll(run.script, plan.stages, features)
+		for i := range s.buf {
+			s.buf[i].adv = f.Advance(s.buf[i].id)
+		}
+		s.positionAll(run.script, features)
+		if s.rtl {
+			slices.Reverse(s.buf)
+		}
+		for _, g := range s.buf {
+			if g.id == 0 && isDefaultIgnorable(g.r) {
+				continue
+			}
+			out = append(out, Glyph{
+				ID:      g.id,
+				Cluster: g.cluster,
+				Advance: g.adv * scale,
+				Offset:  core.Pt(g.dx*scale, -g.dy*scale),
+			})
+		}
+	}
+	return out
+}
+
+// isDefaultIgnorable reports whether r is Default_Ignorable_Code_Point,
+// which is not displayed unless the font has a glyph for it.
+func isDefaultIgnorable(r rune) bool {
+	switch {
+	case r == 0x00AD, r == 0x034F, r == 0x061C, r == 0x3164, r == 0xFEFF, r == 0xFFA0:
+	case r >= 0x115F && r <= 0x1160, r >= 0x17B4 && r <= 0x17B5, r >= 0x180B && r <= 0x180F:
+	case r >= 0x200B && r <= 0x200F, r >= 0x202A && r <= 0x202E, r >= 0x2060 && r <= 0x206F:
+	case r >= 0xFE00 && r <= 0xFE0F, r >= 0xFFF0 && r <= 0xFFF8, r >= 0x1BCA0 && r <= 0x1BCA3:
+	case r >= 0x1D173 && r <= 0x1D17A, r >= 0xE0000 && r <= 0xE0FFF:
+	default:
+		return false
+	}
+	return true
+}
+
+type scriptRun struct {
+	script     string
+	start, end int
+}
+
+// scriptRuns splits text into runs of one script. Characters common to
+// every script, such as spaces and digits, join the run they are in, or
+// the run after them at the start of the text.
+func scriptRuns(text string) []scriptRun {
+	var runs []scriptRun
+	for i, r := range text {
+		sc := scriptOf(r)
+		if sc == Common || unicode.In(r, unicode.Mn, unicode.Me) {
+			if len(runs) > 0 {
+				continue
+			}
+			sc = Common
+		}
+		if n := len(runs); n > 0 {
+			if runs[n-1].script == sc {
+				continue
+			}
+			if runs[n-1].script == Common {
+				runs[n-1].script = sc
+				continue
+			}
+			runs[n-1].end = i
+		}
+		runs = append(runs, scriptRun{script: sc, start: i})
+	}
+	if n := len(runs); n > 0 {
+		runs[n-1].end = len(text)
+	}
+	return runs
+}
+
+var rtlScripts = map[string]bool{
+	"Arabic": true, "Hebrew": true, "Syriac": true, "Thaana": true, "Nko": true,
+	"Samaritan": true, "Mandaic": true, "Adlam": true, "Hanifi_Rohingya": true,
+}
+
+func isRTLScript(script string) bool {
+	return rtlScripts[script]
+}
+
+// load fills the buffer with the characters of text[start:end].
+func (s *shaper) load(text string, start, end int) {
+	for i, r := range text[start:end] {
+		if m, ok := bidi.Mirror(r); ok && s.rtl {
+			r = m
+		}
+		id := s.font.GlyphIndex(r)
+		s.buf = append(s.buf, glyphInfo{
+			id:      id,
+			r:       r,
+			cluster: start + i,
+			mask:    maskGlobal,
+			class:   s.font.classOf(id),
+		})
+		if s.buf[len(s.buf)-1].class == 0 && unicode.In(r, unicode.Mn, unicode.Me) {
+			s.buf[len(s.buf)-1].class = classMark
+		}
+	}
+}
+
+// skip reports whether lk ignores the glyph at i.
+func (s *shaper) skip(lk *lookup, i int) bool {
+	g := &s.buf[i]
+	switch g.class {
+	case classBase:
+		return lk.flag&ignoreBase != 0
+	case classLigature:
+		return lk.flag&ignoreLigatures != 0
+	case classMark:
+		if lk.flag&ignoreMarks != 0 {
+			return true
+		}
+		if t := lk.flag & markAttachType >> 8; t != 0 && s.font.markClass.class(g.id) != t {
+			return true
+		}
+		if lk.filter >= 0 && !s.font.inMarkSet(lk.filter, g.id) {
+			return true
+		}
+	}
+	return false
+}
+
+// next returns the position of the first glyph after i that lk does not
+// ignore, or -1.
+func (s *shaper) next(i int, lk *lookup) int {
+	for j := i + 1; j < len(s.buf); j++ {
+		if !s.skip(lk, j) {
+			return j
+		}
+	}
+	return -1
+}
+
+// prev returns the position of the last glyph before i that lk does not
+// ignore, or -1.
+func (s *shaper) prev(i int, lk *lookup) int {
+	for j := i - 1; j >= 0; j-- {
+		if !s.skip(lk, j) {
+			return j
+		}
+	}
+	return -1
+}
+
+// replace replaces the glyphs from i to j with out.
+func (s *shaper) replace(i, j int, out []glyphInfo) {
+	s.buf = slices.Replace(s.buf, i, j, out...)
+}
+
+// applyAt applies lk at position i only, as a nested lookup.
+func (s *shaper) applyAt(lk *lookup, i int) bool {
+	if i >= len(s.buf) || s.skip(lk, i) {
+		return false
+	}
+	if s.gpos {
+		return s.position(lk, i)
+	}
+	return s.substitute(lk, i)
+}
+
+// apply applies lk to every glyph whose mask has a bit of mask.
+func (s *shaper) apply(lk *lookup, mask uint32) {
+	for i := 0; i < len(s.buf); i++ {
+		if s.buf[i].mask&mask == 0 || s.skip(lk, i) {
+			continue
+		}
+		n := len(s.buf)
+		if s.applyAt(lk, i) && len(s.buf) > n {
+			// Step over the glyphs a multiple substitution produced.
+			i += len(s.buf) - n
+		}
+	}
+}
+
+// featureSet returns the features of stages with the caller's features
+// applied: ones turned off are removed and others added to the last
+// stage.
+func featureSet(stages [][]string, features []Feature) [][]string {
+	out := make([][]string, len(stages))
+	for i, st := range stages {
+		out[i] = slices.DeleteFunc(slices.Clone(st), func(tag string) bool {
+			return slices.Contains(features, Feature{tag, false})
+		})
+	}
+	for _, f := range features {
+		if !f.On {
+			continue
+		}
+		found := false
+		for _, st := range out {
+			found = found || slices.Contains(st, f.Tag)
+		}
+		if !found && len(out) > 0 {
+			out[len(out)-1] = append(out[len(out)-1], f.Tag)
+		}
+	}
+	return out
+}
+
+// runStages applies the lookups of each stage of features in the order
+// of the lookup list, each to the glyphs its feature applies to.
+func (s *shaper) runStages(t *layout, script string, stages [][]string) {
+	if t == nil {
+		return
+	}
+	ls, _ := t.langSys(scriptTags(script))
+	s.table = t
+	for _, stage := range stages {
+		masks := make(map[uint16]uint32)
+		var order []uint16
+		for _, tag := range stage {
+			mask, ok := localFeatures[tag]
+			if !ok {
+				mask = maskGlobal
+			}
+			for _, li := range t.lookupsFor(ls, tag) {
+				if _, seen := masks[li]; !seen {
+					order = append(order, li)
+				}
+				masks[li] |= mask
+			}
+		}
+		slices.Sort(order)
+		for _, li := range order {
+			lk := t.lookup(li)
+			s.apply(&lk, masks[li])
+		}
+	}
+}
+
+func (s *shaper) substituteAll(script string, stages [][]string, features []Feature) {
+	s.runStages(s.font.gsub, script, featureSet(stages, features))
+}
+
+var positionStages = [][]string{{"kern", "mark", "mkmk", "dist", "abvm", "blwm"}}
+
+func (s *shaper) positionAll(script string, features []Feature) {
+	stages := featureSet(positionStages, features)
+	kern := slices.Contains(stages[0], "kern")
+	if s.font.gpos != nil {
+		s.gpos = true
+		s.runStages(s.font.gpos, script, stages)
+		s.gpos = false
+		if ls, _ := s.font.gpos.langSys(scriptTags(script)); len(s.font.gpos.lookupsFor(ls, "kern")) > 0 {
+			kern = false
+		}
+	}
+	if kern && s.font.kern != nil {
+		for i := 0; i+1 < len(s.buf); i++ {
+			s.buf[i].adv += s.font.legacyKern(s.buf[i].id, s.buf[i+1].id)
+		}
+	}
+}
+
+var scriptTagTable = map[string][]string{
+	"Latin":      {"latn"},
+	"Cyrillic":   {"cyrl"},
+	"Greek":      {"grek"},
+	"Arabic":     {"arab"},
+	"Hebrew":     {"hebr"},
+	"Syriac":     {"syrc"},
+	"Thaana":     {"thaa"},
+	"Devanagari": {"dev2", "deva"},
+	"Bengali":    {"bng2", "beng"},
+	"Gurmukhi":   {"gur2", "guru"},
+	"Gujarati":   {"gjr2", "gujr"},
+	"Tamil":      {"tml2", "taml"},
+	"Telugu":     {"tel2", "telu"},
+	"Kannada":    {"knd2", "knda"},
+	"Malayalam":  {"mlm2", "mlym"},
+	"Han":        {"hani"},
+	"Hiragana":   {"kana"},
+	"Katakana":   {"kana"},
+	"Hangul":     {"hang"},
+	"Thai":       {"thai"},
+	"Armenian":   {"armn"},
+	"Georgian":   {"geor"},
+	"Ethiopic":   {"ethi"},
+}
+
+// scriptTags returns the OpenType script tags to look for for script, in
+// order of preference, ending with the default script.
+func scriptTags(script string) []string {
+	tags := slices.Clone(scriptTagTable[script])
+	return append(tags, "DFLT", "dflt", "latn")
+}
@@ -0,0 +1,52 @@
+package font
+
+import (
+	"testing"
+)
+
+type shaped struct {
+	id      uint16
+	cluster int
+	advance float32
+}
+
+func TestShape(t *testing.T) {
+	f, err := ParseFont(sfnt(testFont(false)), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// At 100 pixels per em, a font unit is a tenth of a pixel.
+	tests := []struct {
+		name     string
+		text     string
+		features []Feature
+		want     []shaped
+	}{
+		{"ligature", "fi", nil, []shaped{{gidFI, 0, 50}}},
+		{"ligature off", "fi", []Feature{{"liga", false}}, []shaped{{gidF, 0, 30}, {gidI, 1, 25}}},
+		{"kerning", "AV", nil, []shaped{{gidA, 0, 52}, {gidV, 1, 60}}},
+		{"kerning off", "AV", []Feature{{"kern", false}}, []shaped{{gidA, 0, 60}, {gidV, 1, 60}}},
+		{"clusters", "fiAfi", nil, []shaped{{gidFI, 0, 50}, {gidA, 2, 60}, {gidFI, 3, 50}}},
+		{"missing glyph", "AB", nil, []shaped{{gidA, 0, 60}, {gidNotdef, 1, 50}}},
+		{"ignorable dropped", "A\u200DV", nil, []shaped{{gidA, 0, 60}, {gidV, 4, 60}}},
+		{"supplementary plane", "\U0001F600f", nil, []shaped{{gidSmile, 0, 60}, {gidF, 4, 30}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			glyphs := f.Shape(tt.text, 100, tt.features...)
+			var got []shaped
+			for _, g := range glyphs {
+				got = append(got, shaped{g.ID, g.Cluster, g.Advance})
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Shape(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Shape(%q) = %v, want %v", tt.text, got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
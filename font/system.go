@@ -0,0 +1,101 @@
+package font
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// SystemDirs returns the directories the platform's font system reads
+// installed fonts from: the Windows and per-user font folders that
+// DirectWrite enumerates, the CoreText font folders on macOS, and on
+// other systems the directories listed in the fontconfig configuration,
+// or the usual XDG locations if there is none.
+func SystemDirs() []string {
+	home, _ := os.UserHomeDir()
+	switch runtime.GOOS {
+	case "windows":
+		win := os.Getenv("WINDIR")
+		if win == "" {
+			win = `C:\Windows`
+		}
+		dirs := []string{filepath.Join(win, "Fonts")}
+		if local := os.Getenv("LOCALAPPDATA"); local != "" {
+			dirs = append(dirs, filepath.Join(local, "Microsoft", "Windows", "Fonts"))
+		}
+		return dirs
+	case "darwin", "ios":
+		dirs := []string{"/System/Library/Fonts", "/Library/Fonts", "/Network/Library/Fonts"}
+		if home != "" {
+			dirs = append(dirs, filepath.Join(home, "Library", "Fonts"))
+		}
+		return dirs
+	case "android":
+		return []string{"/system/fonts", "/product/fonts"}
+	}
+	if dirs := fontconfigDirs(home); len(dirs) > 0 {
+		return dirs
+	}
+	data := os.Getenv("XDG_DATA_HOME")
+	if data == "" && home != "" {
+		data = filepath.Join(home, ".local", "share")
+	}
+	dirs := []string{"/usr/share/fonts", "/usr/local/share/fonts"}
+	if data != "" {
+		dirs = append(dirs, filepath.Join(data, "fonts"))
+	}
+	if home != "" {
+		dirs = append(dirs, filepath.Join(home, ".fonts"))
+	}
+	return dirs
+}
+
+// fontconfigDirs returns the <dir> entries of the fontconfig
+// configuration and the files it includes from conf.d.
+func fontconfigDirs(home string) []string {
+	conf := os.Getenv("FONTCONFIG_FILE")
+	if conf == "" {
+		conf = "/etc/fonts/fonts.conf"
+	}
+	files := []string{conf}
+	more, _ := filepath.Glob(filepath.Join(filepath.Dir(conf), "conf.d", "*.conf"))
+	files = append(files, more...)
+
+	var dirs []string
+	seen := make(map[string]bool)
+	for _, name := range files {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			continue
+		}
+		var cfg struct {
+			Dirs []struct {
+				Prefix string `xml:"prefix,attr"`
+				Path   string `xml:",chardata"`
+			} `xml:"dir"`
+		}
+		if xml.Unmarshal(data, &cfg) != nil {
+			continue
+		}
+		for _, d := range cfg.Dirs {
+			p := strings.TrimSpace(d.Path)
+			switch {
+			case d.Prefix == "xdg":
+				base := os.Getenv("XDG_DATA_HOME")
+				if base == "" && home != "" {
+					base = filepath.Join(home, ".local", "share")
+				}
+				p = filepath.Join(base, p)
+			case strings.HasPrefix(p, "~/") && home != "":
+				p = filepath.Join(home, p[2:])
+			}
+			if p != "" && !seen[p] {
+				seen[p] = true
+				dirs = append(dirs, p)
+			}
+		}
+	}
+	return dirs
+}
@@ -0,0 +1,290 @@
+package font
+
+import (
+	"slices"
+
+	"github.com/gogpu/ui/core"
+)
+
+// Axis is an axis of variation of a variable font, such as weight (wght)
+// from 100 to 900 with 400 the default.
+type Axis struct {
+	// Tag is the four-letter OpenType tag of the axis: wght, wdth, slnt,
+	// ital or opsz for the registered axes, upper-case for the font's own.
+	Tag string
+
+	// Name is the name the font gives the axis, such as "Weight" or
+	// "Grade", for axis sliders.
+	Name string
+
+	Min, Default, Max float32
+
+	// Hidden is set for axes the font asks not to be offered to users.
+	Hidden bool
+}
+
+// Clamp returns v limited to the range of the axis.
+func (a Axis) Clamp(v float32) float32 {
+	return min(max(v, a.Min), a.Max)
+}
+
+// Instance is a named instance of a variable font, a point in its design
+// space the font names as if it were a separate face, such as "Semibold
+// Condensed".
+type Instance struct {
+	Name   string
+	Values []core.Variation
+}
+
+// Axis returns the axis of the face with tag, if the face is variable and
+// has one.
+func (f *Face) Axis(tag string) (Axis, bool) {
+	return findAxis(f.Axes, tag)
+}
+
+func findAxis(axes []Axis, tag string) (Axis, bool) {
+	for _, a := range axes {
+		if a.Tag == tag {
+			return a, true
+		}
+	}
+	return Axis{}, false
+}
+
+// fixed converts a 16.16 fixed point number.
+func fixed(v uint32) float32 {
+	return float32(int32(v)) / 65536
+}
+
+// parseFvar returns the axes and named instances of an fvar table, with
+// their names from the name table names.
+func parseFvar(d data, names []byte) ([]Axis, []Instance) {
+	if d.u16(0) != 1 {
+		return nil, nil
+	}
+	off, count, size := int(d.u16(4)), int(d.u16(8)), int(d.u16(10))
+	instances, isize := int(d.u16(12)), int(d.u16(14))
+	if size < 20 || isize < 4+4*count {
+		return nil, nil
+	}
+	ids := make(map[uint16]bool)
+	for i := range count {
+		ids[d.u16(off+size*i+18)] = true
+	}
+	ioff := off + size*count
+	for i := range instances {
+		ids[d.u16(ioff+isize*i)] = true
+	}
+	text := parseNameTable(names, func(id uint16) bool { return ids[id] })
+	axes := make([]Axis, count)
+	for i := range axes {
+		rec := off + size*i
+		axes[i] = Axis{
+			Tag:     d.tag(rec),
+			Min:     fixed(d.u32(rec + 4)),
+			Default: fixed(d.u32(rec + 8)),
+			Max:     fixed(d.u32(rec + 12)),
+			Hidden:  d.u16(rec+16)&1 != 0,
+			Name:    text[d.u16(rec+18)],
+		}
+	}
+	var out []Instance
+	for i := range instances {
+		rec := ioff + isize*i
+		in := Instance{Name: text[d.u16(rec)]}
+		for k, a := range axes {
+			in.Values = append(in.Values, core.Variation{Tag: a.Tag, Value: fixed(d.u32(rec + 4 + 4*k))})
+		}
+		out = append(out, in)
+	}
+	return axes, out
+}
+
+// Axes returns the axes of variation of the font, none if it is not a
+// variable font.
+func (f *Font) Axes() []Axis {
+	return f.axes
+}
+
+// Instances returns the named instances of a variable font.
+func (f *Font) Instances() []Instance {
+	return f.instances
+}
+
+// Variations returns the value of each axis of the font, the defaults
+// unless set with Vary.
+func (f *Font) Variations() []core.Variation {
+	out := make([]core.Variation, len(f.axes))
+	for i, a := range f.axes {
+		out[i] = core.Variation{Tag: a.Tag, Value: a.Default}
+		if f.values != nil {
+			out[i].Value = f.values[i]
+		}
+	}
+	return out
+}
+
+// Vary returns the font at the axis values of vs, such as those of
+// TextStyle.Axes, with the other axes at the values of f. Values are
+// clamped to the range of their axis, and axes the font does not have
+// are ignored, so the same style can be given to any font. Advances
+// follow the variation where the font has an HVAR table; kerning and
+// mark positions are those of the default instance.
+func (f *Font) Vary(vs ...core.Variation) *Font {
+	if len(f.axes) == 0 {
+		return f
+	}
+	g := *f
+	g.values = make([]float32, len(f.axes))
+	for i, v := range f.Variations() {
+		g.values[i] = v.Value
+	}
+	for _, v := range vs {
+		for i, a := range f.axes {
+			if a.Tag == v.Tag {
+				g.values[i] = a.Clamp(v.Value)
+			}
+		}
+	}
+	g.coords = make([]float32, len(f.axes))
+	zero := true
+	for i, a := range f.axes {
+		g.coords[i] = f.normalize(i, a, g.values[i])
+		zero = zero && g.coords[i] == 0
+	}
+	if zero {
+		g.coords = nil
+	}
+	return &g
+}
+
+// normalize maps value v of axis i, a, to the range -1 to 1 with the
+// default at 0, through the axis's segment map in avar if the font has
+// one.
+func (f *Font) normalize(i int, a Axis, v float32) float32 {
+	var n float32
+	switch {
+	case v < a.Default && a.Default > a.Min:
+		n = (v - a.Default) / (a.Default - a.Min)
+	case v > a.Default && a.Max > a.Default:
+		n = (v - a.Default) / (a.Max - a.Default)
+	}
+	if f.avar == nil || int(f.avar.u16(6)) != len(f.axes) {
+		return n
+	}
+	off := 8
+	for range i {
+		off += 2 + 4*int(f.avar.u16(off))
+	}
+	maps := int(f.avar.u16(off))
+	off += 2
+	for k := 0; k < maps; k++ {
+		from := f2dot14(f.avar.i16(off + 4*k))
+		if n > from {
+			continue
+		}
+		to := f2dot14(f.avar.i16(off + 4*k + 2))
+		if k == 0 || n == from {
+			return to
+		}
+		pf, pt := f2dot14(f.avar.i16(off+4*k-4)), f2dot14(f.avar.i16(off+4*k-2))
+		return pt + (to-pt)*(n-pf)/(from-pf)
+	}
+	return n
+}
+
+// advanceDelta returns the change of the advance of glyph g at the
+// font's variation, from HVAR, in font units.
+func (f *Font) advanceDelta(g uint16) float32 {
+	if f.coords == nil || f.hvar == nil {
+		return 0
+	}
+	outer, inner := 0, int(g)
+	if off := int(f.hvar.u32(8)); off != 0 {
+		m := f.hvar.sub(off)
+		format, entry := m.u8(0), m.u8(1)
+		count, base := int(m.u16(2)), 4
+		if format == 1 {
+			count, base = int(m.u32(2)), 6
+		}
+		if count == 0 {
+			return 0
+		}
+		size, bits := int(entry>>4&3)+1, int(entry&0xF)+1
+		at := base + size*min(int(g), count-1)
+		var v uint32
+		for k := range size {
+			v = v<<8 | uint32(m.u8(at+k))
+		}
+		outer, inner = int(v>>bits), int(v&(1<<bits-1))
+	}
+	return f.delta(f.hvar.sub(int(f.hvar.u32(4))), outer, inner)
+}
+
+// delta returns the delta of item outer, inner of an item variation store
+// at the font's variation.
+func (f *Font) delta(store data, outer, inner int) float32 {
+	if outer >= int(store.u16(6)) {
+		return 0
+	}
+	regions := store.sub(int(store.u32(2)))
+	axes := int(regions.u16(0))
+	item := store.sub(int(store.u32(8 + 4*outer)))
+	items, words, refs := int(item.u16(0)), int(item.u16(2)), int(item.u16(4))
+	if inner >= items {
+		return 0
+	}
+	long := words&0x8000 != 0
+	words &= 0x7FFF
+	wide, narrow := 2, 1
+	if long {
+		wide, narrow = 4, 2
+	}
+	row := 6 + 2*refs + inner*(words*wide+(refs-words)*narrow)
+	var sum float32
+	for k := range refs {
+		var d float32
+		switch at := row + min(k, words)*wide + max(k-words, 0)*narrow; {
+		case k < words && long:
+			d = float32(int32(item.u32(at)))
+		case k < words, long:
+			d = float32(item.i16(at))
+		default:
+			d = float32(int8(item.u8(at)))
+		}
+		if d != 0 {
+			sum += d * f.scalar(regions, axes, int(item.u16(6+2*k)))
+		}
+	}
+	return sum
+}
+
+// scalar returns how much region r of a variation region list applies
+// at the font's variation, from 0 outside it to 1 at its peak.
+func (f *Font) scalar(regions data, axes, r int) float32 {
+	s := float32(1)
+	rec := 4 + 6*axes*r
+	for i := range min(axes, len(f.coords)) {
+		start := f2dot14(regions.i16(rec + 6*i))
+		peak := f2dot14(regions.i16(rec + 6*i + 2))
+		end := f2dot14(regions.i16(rec + 6*i + 4))
+		v := f.coords[i]
+		switch {
+		case peak == 0 || start > peak || peak > end || start < 0 && end > 0 || v == peak:
+		case v <= start || v >= end:
+			return 0
+		case v < peak:
+			s *= (v - start) / (peak - start)
+		default:
+			s *= (end - v) / (end - peak)
+		}
+	}
+	return s
+}
+
+// isVariableItalic reports whether the axes of a face can make it italic.
+func isVariableItalic(axes []Axis) bool {
+	return slices.ContainsFunc(axes, func(a Axis) bool {
+		return a.Tag == "ital" && a.Max >= 1 || a.Tag == "slnt" && a.Min < 0
+	})
+}
@@ -0,0 +1,286 @@
+package gesture
+
+import (
+	"slices"
+	"time"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+)
+
+const (
+	mouseSlop = 8
+	touchSlop = 18
+)
+
+// pointerID tells pointers apart: the mouse, and each touch.
+type pointerID struct {
+	touch bool
+	n     int
+}
+
+type phase uint8
+
+const (
+	phaseDown phase = iota
+	phaseMove
+	phaseUp
+	phaseCancel
+)
+
+// pointer is an event of a pointer.
+type pointer struct {
+	id    pointerID
+	phase phase
+	pos   core.Point
+	time  time.Time
+}
+
+// slop returns how far the pointer may stray before it is moving rather
+// than pressed, which is further for fingers.
+func (p pointer) slop() float32 {
+	if p.id.touch {
+		return touchSlop
+	}
+	return mouseSlop
+}
+
+// pointerOf returns the pointer event in ev, if it is one of a touch or
+// of the left mouse button.
+func pointerOf(ev core.Event) (pointer, bool) {
+	p := pointer{time: time.Now()}
+	switch e := ev.(type) {
+	case event.MouseEvent:
+		switch {
+		case e.Type == event.MouseDown && e.Button == event.ButtonLeft:
+			p.phase = phaseDown
+		case e.Type == event.MouseMove:
+			p.phase = phaseMove
+		case e.Type == event.MouseUp && e.Button == event.ButtonLeft:
+			p.phase = phaseUp
+		default:
+			return p, false
+		}
+		p.pos = e.Position
+	case event.TouchEvent:
+		p.id = pointerID{touch: true, n: e.ID}
+		p.phase = phase(e.Type) // The phases follow the touch event types.
+		p.pos = e.Position
+	default:
+		return p, false
+	}
+	return p, true
+}
+
+// Recognizer is a gesture a Detector recognizes. The recognizers of this
+// package implement it.
+type Recognizer interface {
+	// add offers the recognizer a pointer pressed on its detector, which
+	// it joins the arena of to follow.
+	add(r *Router, p pointer)
+
+	// handle delivers the later events of a pointer it follows.
+	handle(r *Router, p pointer)
+
+	// accept and reject tell it that it won or lost the press of an arena.
+	accept(r *Router, a *arena)
+	reject(r *Router, a *arena)
+}
+
+// arena is where the recognizers following a press of a pointer compete
+// for it. It outlives the press while held.
+type arena struct {
+	id      pointerID
+	members []Recognizer
+	follow  []Recognizer // Those given the events of the press.
+	winner  Recognizer
+	open    bool       // Set until the press has reached every detector.
+	held    bool       // Set while a recognizer keeps it from being swept.
+	swept   bool       // Set when it was swept while held.
+	eager   Recognizer // The first to accept while it was open.
+	done    bool       // Set once settled.
+}
+
+// Router follows the pointers of a window for the recognizers of its
+// detectors, and holds the arenas of their presses.
+type Router struct {
+	ctx      *core.Context
+	pointers map[pointerID]*arena // Of the pointers down.
+	routing  bool                 // Set while its pointer route is added.
+}
+
+type routerKey struct{}
+
+// RouterOf returns the router of the window of ctx. The window runtime
+// makes it with the context, so that every detector finds the same one.
+func RouterOf(ctx *core.Context) *Router {
+	if r, ok := ctx.Value(routerKey{}).(*Router); ok {
+		return r
+	}
+	r := &Router{ctx: ctx, pointers: make(map[pointerID]*arena)}
+	ctx.SetValue(routerKey{}, r)
+	return r
+}
+
+// pressing reports whether a press of id is being offered to detectors,
+// rather than being one of a pointer already down.
+func (r *Router) pressing(id pointerID) bool {
+	a := r.pointers[id]
+	return a == nil || a.open
+}
+
+// join makes rec follow the pointer pressed in p, in the arena of the
+// press, which it returns.
+func (r *Router) join(rec Recognizer, p pointer) *arena {
+	a := r.pointers[p.id]
+	if a == nil {
+		a = &arena{id: p.id, open: true}
+		r.pointers[p.id] = a
+		if !r.routing {
+			r.routing = true
+			r.ctx.AddPointerRoute(r.route)
+		}
+	}
+	a.members = append(a.members, rec)
+	a.follow = append(a.follow, rec)
+	return a
+}
+
+// route follows the pointer events of the window, once the tree has seen
+// them.
+func (r *Router) route(ev core.PointerEvent) bool {
+	p, ok := pointerOf(ev)
+	a := r.pointers[p.id]
+	switch {
+	case !ok || a == nil:
+	case p.phase == phaseDown:
+		// The press has reached each detector it is going to.
+		r.close(a)
+	default:
+		cancel := p.phase == phaseCancel && !a.done
+		if cancel {
+			// Nobody wins a press the platform took over, not even one
+			// left alone by others giving it up on the way.
+			a.done = true
+		}
+		for _, rec := range slices.Clone(a.follow) {
+			if slices.Contains(a.follow, rec) {
+				rec.handle(r, p)
+			}
+		}
+		switch p.phase {
+		case phaseUp:
+			delete(r.pointers, p.id)
+			r.sweep(a)
+		case phaseCancel:
+			delete(r.pointers, p.id)
+			if cancel {
+				for _, m := range a.members {
+					r.lose(a, m)
+				}
+			}
+		}
+	}
+	r.routing = len(r.pointers) > 0
+	return r.routing
+}
+
+// stop makes rec stop following the press of a, as when it no longer
+// cares which gesture the press makes.
+func (r *Router) stop(a *arena, rec Recognizer) {
+	a.follow = slices.DeleteFunc(a.follow, func(m Recognizer) bool { return m == rec })
+}
+
+// close ends joining a, which a lone member wins at once.
+func (r *Router) close(a *arena) {
+	if !a.open {
+		return
+	}
+	a.open = false
+	r.settle(a)
+}
+
+func (r *Router) settle(a *arena) {
+	switch {
+	case len(a.members) == 0:
+		a.done = true
+	case len(a.members) == 1:
+		r.win(a, a.members[0])
+	case a.eager != nil:
+		r.win(a, a.eager)
+	}
+}
+
+// sweep settles the arena of a lifted pointer, giving it to its first
+// member, unless it is held.
+func (r *Router) sweep(a *arena) {
+	switch {
+	case a.done:
+	case a.held:
+		a.swept = true
+	case len(a.members) == 0:
+		a.done = true
+	default:
+		r.win(a, a.members[0])
+	}
+}
+
+// hold keeps a from being swept until release.
+func (r *Router) hold(a *arena) {
+	a.held = true
+}
+
+// release lets a be swept, sweeping it if it was meanwhile.
+func (r *Router) release(a *arena) {
+	a.held = false
+	if a.swept {
+		r.sweep(a)
+	}
+}
+
+// resolve has rec claim the press of a, or give it up. A claim while a is
+// open wins when it closes. Once a is settled, resolving does nothing.
+func (r *Router) resolve(a *arena, rec Recognizer, accepted bool) {
+	if a == nil || a.done || !slices.Contains(a.members, rec) {
+		return
+	}
+	if accepted {
+		if a.open {
+			if a.eager == nil {
+				a.eager = rec
+			}
+			return
+		}
+		r.win(a, rec)
+		return
+	}
+	a.members = slices.DeleteFunc(a.members, func(m Recognizer) bool { return m == rec })
+	if a.eager == rec {
+		a.eager = nil
+	}
+	r.lose(a, rec)
+	if !a.open {
+		r.settle(a)
+	}
+}
+
+func (r *Router) win(a *arena, rec Recognizer) {
+	a.done, a.winner = true, rec
+	for _, m := range a.members {
+		if m != rec {
+			r.lose(a, m)
+		}
+	}
+	rec.accept(r, a)
+}
+
+func (r *Router) lose(a *arena, rec Recognizer) {
+	r.stop(a, rec)
+	rec.reject(r, a)
+}
+
+// won reports whether one of recs won the press of pointer id.
+func (r *Router) won(id pointerID, recs []Recognizer) bool {
+	a := r.pointers[id]
+	return a != nil && a.winner != nil && slices.Contains(recs, a.winner)
+}
@@ -0,0 +1,251 @@
+package gesture
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/layout"
+)
+
+// leaf is a widget filling its constraints, which ignores events.
+type leaf struct {
+	core.WidgetBase
+}
+
+func (l *leaf) Layout(ctx *core.LayoutContext) core.Size {
+	return ctx.Constraints.Constrain(core.Sz(ctx.Constraints.MaxWidth, ctx.Constraints.MaxHeight))
+}
+
+func (l *leaf) Paint(*core.PaintContext) {}
+
+// window delivers pointer events as the window runtime does: bubbling
+// from the deepest of nested detectors until one handles them, then along
+// the pointer routes.
+type window struct {
+	t     *testing.T
+	ctx   *core.Context
+	chain []*Detector // From the deepest out.
+	woken chan struct{}
+}
+
+// newWindow lays out detectors, each given the recognizers of one entry
+// of recs, nested from the first out.
+func newWindow(t *testing.T, recs ...[]Recognizer) *window {
+	w := &window{t: t, ctx: core.NewContext(), woken: make(chan struct{}, 16)}
+	w.ctx.SetWakeup(func() { w.woken <- struct{}{} })
+	var child core.Widget = &leaf{}
+	for _, rs := range recs {
+		d := NewDetector(child, rs...)
+		w.chain = append(w.chain, d)
+		child = d
+	}
+	w.ctx.LayoutRoot(child, core.R(0, 0, 200, 200))
+	return w
+}
+
+func (w *window) send(ev core.PointerEvent) {
+	for _, d := range w.chain {
+		if d.HandleEvent(w.ctx, ev) == core.Handled {
+			break
+		}
+	}
+	w.ctx.RoutePointer(ev)
+}
+
+// wait runs what the timers of the recognizers post once they fire.
+func (w *window) wait() {
+	select {
+	case <-w.woken:
+		w.ctx.RunPosted()
+	case <-time.After(2 * time.Second):
+		w.t.Fatal("no timer fired")
+	}
+}
+
+// step is an input of a gesture test: a pointer event at (x, y), or a
+// wait for a timer.
+type step struct {
+	what string // down, move, up, cancel, touch down, touch move, touch up, touch cancel or wait.
+	x, y float32
+}
+
+func (w *window) run(steps []step) {
+	for _, s := range steps {
+		p := core.Pt(s.x, s.y)
+		switch s.what {
+		case "down":
+			w.send(event.MouseEvent{Type: event.MouseDown, Button: event.ButtonLeft, Position: p})
+		case "move":
+			w.send(event.MouseEvent{Type: event.MouseMove, Position: p})
+		case "up":
+			w.send(event.MouseEvent{Type: event.MouseUp, Button: event.ButtonLeft, Position: p})
+		case "touch down":
+			w.send(event.TouchEvent{Type: event.TouchDown, Position: p})
+		case "touch move":
+			w.send(event.TouchEvent{Type: event.TouchMove, Position: p})
+		case "touch up":
+			w.send(event.TouchEvent{Type: event.TouchUp, Position: p})
+		case "touch cancel":
+			w.send(event.TouchEvent{Type: event.TouchCancel, Position: p})
+		case "wait":
+			w.wait()
+		}
+	}
+}
+
+// recorder makes recognizers that log what they recognize.
+type recorder struct {
+	log []string
+}
+
+func (r *recorder) add(format string, args ...any) {
+	r.log = append(r.log, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) tap(name string) *Tap {
+	return NewTap().
+		OnTap(func(p core.Point) { r.add("%s tap %v,%v", name, p.X, p.Y) }).
+		OnCancel(func() { r.add("%s cancel", name) })
+}
+
+func (r *recorder) pan(name string) *Pan {
+	return NewPan().
+		OnStart(func(p core.Point) { r.add("%s start %v,%v", name, p.X, p.Y) }).
+		OnUpdate(func(d, p core.Point) { r.add("%s by %v,%v", name, d.X, d.Y) }).
+		OnEnd(func(core.Point) { r.add("%s end", name) })
+}
+
+func (r *recorder) longPress(name string) *LongPress {
+	return NewLongPress().Delay(time.Millisecond).
+		OnLongPress(func(p core.Point) { r.add("%s press %v,%v", name, p.X, p.Y) }).
+		OnMove(func(p core.Point) { r.add("%s move %v,%v", name, p.X, p.Y) }).
+		OnEnd(func(p core.Point) { r.add("%s end %v,%v", name, p.X, p.Y) })
+}
+
+func (r *recorder) doubleTap(name string) *DoubleTap {
+	return NewDoubleTap().OnDoubleTap(func(p core.Point) { r.add("%s double tap %v,%v", name, p.X, p.Y) })
+}
+
+func TestArena(t *testing.T) {
+	tests := []struct {
+		name  string
+		recs  func(r *recorder) [][]Recognizer
+		steps []step
+		want  []string
+	}{
+		{
+			"tap alone",
+			func(r *recorder) [][]Recognizer { return [][]Recognizer{{r.tap("tap")}} },
+			[]step{{"down", 10, 10}, {"up", 12, 10}},
+			[]string{"tap tap 12,10"},
+		},
+		{
+			"tap within the slop",
+			func(r *recorder) [][]Recognizer { return [][]Recognizer{{r.tap("tap"), r.pan("pan")}} },
+			[]step{{"down", 10, 10}, {"move", 15, 10}, {"up", 15, 10}},
+			[]string{"tap tap 15,10"},
+		},
+		{
+			"drag past the slop",
+			func(r *recorder) [][]Recognizer { return [][]Recognizer{{r.tap("tap"), r.pan("pan")}} },
+			[]step{{"down", 10, 10}, {"move", 30, 10}, {"move", 40, 10}, {"up", 40, 10}},
+			[]string{"tap cancel", "pan start 10,10", "pan by 20,0", "pan by 10,0", "pan end"},
+		},
+		{
+			"touch slop is wider",
+			func(r *recorder) [][]Recognizer { return [][]Recognizer{{r.tap("tap"), r.pan("pan")}} },
+			[]step{{"touch down", 10, 10}, {"touch move", 25, 10}, {"touch up", 25, 10}},
+			[]string{"tap tap 25,10"},
+		},
+		{
+			"deepest tap wins",
+			func(r *recorder) [][]Recognizer { return [][]Recognizer{{r.tap("inner")}, {r.tap("outer")}} },
+			[]step{{"down", 10, 10}, {"up", 10, 10}},
+			[]string{"outer cancel", "inner tap 10,10"},
+		},
+		{
+			"drag along the outer axis",
+			func(r *recorder) [][]Recognizer {
+				return [][]Recognizer{{r.pan("row").Axis(layout.Horizontal)}, {r.pan("list").Axis(layout.Vertical)}}
+			},
+			[]step{{"down", 10, 10}, {"move", 12, 40}, {"up", 12, 40}},
+			[]string{"list start 10,10", "list by 0,30", "list end"},
+		},
+		{
+			"drag along the inner axis",
+			func(r *recorder) [][]Recognizer {
+				return [][]Recognizer{{r.pan("row").Axis(layout.Horizontal)}, {r.pan("list").Axis(layout.Vertical)}}
+			},
+			[]step{{"down", 10, 10}, {"move", 40, 12}, {"up", 40, 12}},
+			[]string{"row start 10,10", "row by 30,0", "row end"},
+		},
+		{
+			"long press held",
+			func(r *recorder) [][]Recognizer { return [][]Recognizer{{r.tap("tap"), r.longPress("long")}} },
+			[]step{{"down", 10, 10}, {"wait", 0, 0}, {"move", 50, 50}, {"up", 50, 50}},
+			[]string{"tap cancel", "long press 10,10", "long move 50,50", "long end 50,50"},
+		},
+		{
+			"long press lifted early",
+			func(r *recorder) [][]Recognizer { return [][]Recognizer{{r.tap("tap"), r.longPress("long")}} },
+			[]step{{"down", 10, 10}, {"up", 10, 10}, {"wait", 0, 0}},
+			[]string{"tap tap 10,10"},
+		},
+		{
+			"long press dragged early",
+			func(r *recorder) [][]Recognizer { return [][]Recognizer{{r.longPress("long"), r.pan("pan")}} },
+			[]step{{"down", 10, 10}, {"move", 10, 40}, {"wait", 0, 0}, {"up", 10, 40}},
+			[]string{"pan start 10,10", "pan by 0,30", "pan end"},
+		},
+		{
+			"touch cancelled",
+			func(r *recorder) [][]Recognizer { return [][]Recognizer{{r.tap("tap"), r.pan("pan")}} },
+			[]step{{"touch down", 10, 10}, {"touch cancel", 10, 10}, {"touch down", 10, 10}, {"touch up", 10, 10}},
+			[]string{"tap cancel", "tap tap 10,10"},
+		},
+		{
+			"double tap",
+			func(r *recorder) [][]Recognizer { return [][]Recognizer{{r.tap("tap"), r.doubleTap("two")}} },
+			[]step{{"down", 10, 10}, {"up", 10, 10}, {"down", 12, 12}, {"up", 12, 12}},
+			// The tap still waits on the first press at the second, which
+			// it does not join.
+			[]string{"tap cancel", "two double tap 10,10"},
+		},
+		{
+			"single tap after the wait",
+			func(r *recorder) [][]Recognizer { return [][]Recognizer{{r.tap("tap"), r.doubleTap("two")}} },
+			[]step{{"down", 10, 10}, {"up", 10, 10}, {"wait", 0, 0}},
+			[]string{"tap tap 10,10"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{}
+			w := newWindow(t, tt.recs(r)...)
+			w.run(tt.steps)
+			if !slices.Equal(r.log, tt.want) {
+				t.Errorf("recognized %q, want %q", r.log, tt.want)
+			}
+		})
+	}
+}
+
+// TestDetectorKeepsWon checks that the events of a pointer a detector
+// won stop at it.
+func TestDetectorKeepsWon(t *testing.T) {
+	r := &recorder{}
+	w := newWindow(t, []Recognizer{r.pan("pan")}, []Recognizer{r.tap("outer")})
+	inner, outer := w.chain[0], w.chain[1]
+	w.run([]step{{"down", 10, 10}, {"move", 40, 10}})
+	move := event.MouseEvent{Type: event.MouseMove, Position: core.Pt(50, 10)}
+	if got := inner.HandleEvent(w.ctx, move); got != core.Handled {
+		t.Errorf("the winning detector returned %v, want Handled", got)
+	}
+	if got := outer.HandleEvent(w.ctx, move); got != core.Ignored {
+		t.Errorf("the losing detector returned %v, want Ignored", got)
+	}
+}
@@ -0,0 +1,77 @@
+package gesture
+
+import "github.com/gogpu/ui/core"
+
+// Detector recognizes gestures made on its child. It lays the child out
+// in its own place and lets its events through: a press goes on to the
+// child's ancestors, so that detectors around it also see it, and the
+// later events of a pointer are only kept from them once a recognizer of
+// this detector has won it.
+type Detector struct {
+	core.WidgetBase
+	child core.Widget
+	recs  []Recognizer
+	r     *Router
+}
+
+// NewDetector returns a detector recognizing the gestures of recs on
+// child. The recognizers compete in the order given.
+func NewDetector(child core.Widget, recs ...Recognizer) *Detector {
+	d := &Detector{child: child, recs: recs}
+	d.SetChildren(child)
+	return d
+}
+
+// Layout implements core.Widget.
+func (d *Detector) Layout(ctx *core.LayoutContext) core.Size {
+	if d.r == nil {
+		d.r = RouterOf(ctx.Context)
+	}
+	return ctx.Measure(d.child, ctx.Constraints)
+}
+
+// IntrinsicWidth implements core.IntrinsicSizer.
+func (d *Detector) IntrinsicWidth(ctx *core.LayoutContext, height float32) (minWidth, maxWidth float32) {
+	return ctx.IntrinsicWidth(d.child, height)
+}
+
+// IntrinsicHeight implements core.IntrinsicSizer.
+func (d *Detector) IntrinsicHeight(ctx *core.LayoutContext, width float32) (minHeight, maxHeight float32) {
+	return ctx.IntrinsicHeight(d.child, width)
+}
+
+// Baseline implements core.Baseliner with the child's baseline.
+func (d *Detector) Baseline() (float32, bool) {
+	return core.BaselineOf(d.child)
+}
+
+// SetBounds implements core.Widget.
+func (d *Detector) SetBounds(r core.Rect) {
+	d.WidgetBase.SetBounds(r)
+	d.child.SetBounds(r)
+}
+
+// Paint implements core.Widget.
+func (d *Detector) Paint(ctx *core.PaintContext) {
+	d.child.Paint(ctx)
+}
+
+// HandleEvent implements core.Widget.
+func (d *Detector) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	p, ok := pointerOf(ev)
+	if !ok || d.r == nil {
+		return core.Ignored
+	}
+	if p.phase != phaseDown {
+		if d.r.won(p.id, d.recs) {
+			return core.Handled
+		}
+		return core.Ignored
+	}
+	if d.r.pressing(p.id) {
+		for _, rec := range d.recs {
+			rec.add(d.r, p)
+		}
+	}
+	return core.Ignored
+}
@@ -0,0 +1,32 @@
+// Package gesture recognizes taps, double taps, long presses, pans,
+// pinches and rotations in mouse and touch input, and settles which of
+// the gestures a pointer could be making it is.
+//
+// A [Detector] wraps a widget with recognizers, which follow the pointers
+// pressed on it:
+//
+//	row := gesture.NewDetector(content,
+//		gesture.NewTap().OnTap(func(p core.Point) { open(item) }),
+//		gesture.NewLongPress().OnLongPress(func(p core.Point) { pick(item) }),
+//	)
+//
+// Every recognizer of the detectors under a pointer when it is pressed,
+// from the deepest detector out, enters the arena of that pointer. Each
+// watches the pointer and, once it is sure, claims it or gives up: a pan
+// claims it when it moves past the slop, a tap gives up. The first to
+// claim a pointer wins it and the others lose it, and a recognizer left
+// alone wins at once. If the pointer is lifted with the arena undecided,
+// the first recognizer still in it wins, which is the deepest. So a list
+// panning vertically around rows that pan horizontally scrolls when a
+// drag goes up or down and moves the row when it goes sideways, and a tap
+// on a row goes to the row's tap rather than the list's.
+//
+// A double tap holds the arena of the first tap until the second comes,
+// or the time for it has passed and the single tap wins. Pinches and
+// rotations follow two touches, and enter the arena of each.
+//
+// The mouse takes part with its left button. A widget that handles a
+// press itself, such as a button, keeps the detectors around it from
+// seeing it, and positions given to callbacks are in window coordinates.
+// Recognizers belong to the UI goroutine and to one detector.
+package gesture
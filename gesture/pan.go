@@ -0,0 +1,139 @@
+package gesture
+
+import (
+	"github.com/gogpu/ui/anim"
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/layout"
+)
+
+// Pan recognizes a pointer dragged, in any direction or along one axis.
+// It claims the press once the pointer has moved past the slop, so of two
+// pans along different axes, the one the drag goes along wins.
+type Pan struct {
+	onStart  func(p core.Point)
+	onUpdate func(delta, p core.Point)
+	onEnd    func(velocity core.Point)
+
+	axis   layout.Axis
+	locked bool // Set when it follows the axis alone.
+
+	a       *arena // Of the press followed, or nil.
+	down    core.Point
+	last    core.Point
+	won     bool
+	started bool
+	tracker anim.VelocityTracker
+}
+
+// NewPan returns a pan recognizer following drags in any direction.
+func NewPan() *Pan {
+	return &Pan{}
+}
+
+// Axis limits the pan to drags along axis; movement across it is left
+// out of the deltas.
+func (pn *Pan) Axis(axis layout.Axis) *Pan {
+	pn.axis, pn.locked = axis, true
+	return pn
+}
+
+// OnStart sets fn to be called with the position where a pan was
+// pressed, once it is recognized.
+func (pn *Pan) OnStart(fn func(p core.Point)) *Pan {
+	pn.onStart = fn
+	return pn
+}
+
+// OnUpdate sets fn to be called as the pointer moves in a pan, with how
+// far it moved and where it is.
+func (pn *Pan) OnUpdate(fn func(delta, p core.Point)) *Pan {
+	pn.onUpdate = fn
+	return pn
+}
+
+// OnEnd sets fn to be called with the velocity of the pointer, in logical
+// pixels a second, when a pan is lifted, for handing off to momentum.
+// Canceled touches end with no velocity.
+func (pn *Pan) OnEnd(fn func(velocity core.Point)) *Pan {
+	pn.onEnd = fn
+	return pn
+}
+
+// along returns d without movement across the axis of the pan.
+func (pn *Pan) along(d core.Point) core.Point {
+	switch {
+	case !pn.locked:
+	case pn.axis == layout.Horizontal:
+		d.Y = 0
+	default:
+		d.X = 0
+	}
+	return d
+}
+
+func (pn *Pan) add(r *Router, p pointer) {
+	if pn.a != nil {
+		return
+	}
+	pn.a, pn.down, pn.last = r.join(pn, p), p.pos, p.pos
+	pn.won, pn.started = false, false
+	pn.tracker.Reset()
+	pn.tracker.Add(p.time, p.pos)
+}
+
+func (pn *Pan) handle(r *Router, p pointer) {
+	switch p.phase {
+	case phaseMove:
+		pn.tracker.Add(p.time, p.pos)
+		if pn.started {
+			pn.update(p.pos)
+			return
+		}
+		d := pn.along(p.pos.Sub(pn.down))
+		if distance(d, core.Point{}) <= p.slop() {
+			return
+		}
+		if !pn.won {
+			r.resolve(pn.a, pn, true)
+		}
+		if pn.won {
+			pn.started = true
+			if pn.onStart != nil {
+				pn.onStart(pn.down)
+			}
+			pn.update(p.pos)
+		}
+	case phaseUp, phaseCancel:
+		a, started := pn.a, pn.started
+		pn.a, pn.started = nil, false
+		if !started {
+			r.resolve(a, pn, false)
+			return
+		}
+		var v core.Point
+		if p.phase == phaseUp {
+			v = pn.along(pn.tracker.VelocityAt(p.time))
+		}
+		if pn.onEnd != nil {
+			pn.onEnd(v)
+		}
+	}
+}
+
+func (pn *Pan) update(p core.Point) {
+	d := pn.along(p.Sub(pn.last))
+	pn.last = p
+	if pn.onUpdate != nil && d != (core.Point{}) {
+		pn.onUpdate(d, p)
+	}
+}
+
+func (pn *Pan) accept(r *Router, a *arena) {
+	pn.won = true
+}
+
+func (pn *Pan) reject(r *Router, a *arena) {
+	if pn.a == a {
+		pn.a, pn.started = nil, false
+	}
+}
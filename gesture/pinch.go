@@ -0,0 +1,264 @@
+package gesture
+
+import (
+	"math"
+
+	"github.com/gogpu/ui/core"
+)
+
+// pair follows the two touches of a pinch or a rotation.
+type pair struct {
+	a       [2]*arena // Of the touches followed, the first n set.
+	pos     [2]core.Point
+	won     [2]bool
+	n       int
+	span    float32 // Between the touches when the second went down.
+	angle   float32 // Of the line from the first to the second then.
+	started bool
+}
+
+func (pr *pair) add(r *Router, rec Recognizer, p pointer) {
+	if !p.id.touch || pr.n == 2 {
+		return
+	}
+	i := pr.n
+	pr.a[i], pr.pos[i], pr.won[i] = r.join(rec, p), p.pos, false
+	pr.n++
+	if pr.n == 2 {
+		pr.span, pr.angle = pr.spanNow(), pr.angleNow()
+	}
+}
+
+// at returns which of the touches p is of, or -1.
+func (pr *pair) at(p pointer) int {
+	for i := range pr.n {
+		if pr.a[i].id == p.id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (pr *pair) spanNow() float32 {
+	return distance(pr.pos[0], pr.pos[1])
+}
+
+func (pr *pair) angleNow() float32 {
+	d := pr.pos[1].Sub(pr.pos[0])
+	return float32(math.Atan2(float64(d.Y), float64(d.X)))
+}
+
+// focal returns the point between the touches.
+func (pr *pair) focal() core.Point {
+	return pr.pos[0].Add(pr.pos[1]).Scale(0.5)
+}
+
+// claim claims both touches for rec, and reports whether it won them.
+func (pr *pair) claim(r *Router, rec Recognizer) bool {
+	a := pr.a
+	r.resolve(a[0], rec, true)
+	r.resolve(a[1], rec, true)
+	return pr.n == 2 && pr.won[0] && pr.won[1]
+}
+
+func (pr *pair) accept(a *arena) {
+	for i := range pr.n {
+		if pr.a[i] == a {
+			pr.won[i] = true
+		}
+	}
+}
+
+// has reports whether a is the arena of one of the touches.
+func (pr *pair) has(a *arena) bool {
+	for i := range pr.n {
+		if pr.a[i] == a {
+			return true
+		}
+	}
+	return false
+}
+
+// reset gives up the touches.
+func (pr *pair) reset(r *Router, rec Recognizer) {
+	a, n := pr.a, pr.n
+	*pr = pair{}
+	for _, a := range a[:n] {
+		r.resolve(a, rec, false)
+		r.stop(a, rec)
+	}
+}
+
+// Pinch recognizes two touches moving apart or together. It claims both
+// once the distance between them has changed by more than the slop.
+type Pinch struct {
+	onStart  func(focal core.Point)
+	onUpdate func(scale float32, focal core.Point)
+	onEnd    func()
+
+	pair
+}
+
+// NewPinch returns a pinch recognizer.
+func NewPinch() *Pinch {
+	return &Pinch{}
+}
+
+// OnStart sets fn to be called with the point between the touches when a
+// pinch is recognized.
+func (pc *Pinch) OnStart(fn func(focal core.Point)) *Pinch {
+	pc.onStart = fn
+	return pc
+}
+
+// OnUpdate sets fn to be called as the touches of a pinch move, with the
+// scale from where they were when the second went down, and the point
+// between them.
+func (pc *Pinch) OnUpdate(fn func(scale float32, focal core.Point)) *Pinch {
+	pc.onUpdate = fn
+	return pc
+}
+
+// OnEnd sets fn to be called when either touch of a pinch is lifted.
+func (pc *Pinch) OnEnd(fn func()) *Pinch {
+	pc.onEnd = fn
+	return pc
+}
+
+func (pc *Pinch) add(r *Router, p pointer) {
+	pc.pair.add(r, pc, p)
+}
+
+func (pc *Pinch) handle(r *Router, p pointer) {
+	i := pc.at(p)
+	if i < 0 {
+		return
+	}
+	if p.phase != phaseMove {
+		pc.end(r)
+		return
+	}
+	pc.pos[i] = p.pos
+	if pc.n < 2 {
+		return
+	}
+	span := pc.spanNow()
+	if !pc.started {
+		if math.Abs(float64(span-pc.span)) <= float64(p.slop()) || !pc.claim(r, pc) {
+			return
+		}
+		pc.started = true
+		if pc.onStart != nil {
+			pc.onStart(pc.focal())
+		}
+	}
+	if pc.onUpdate != nil {
+		pc.onUpdate(span/max(pc.span, 1), pc.focal())
+	}
+}
+
+func (pc *Pinch) end(r *Router) {
+	started := pc.started
+	pc.reset(r, pc)
+	if started && pc.onEnd != nil {
+		pc.onEnd()
+	}
+}
+
+func (pc *Pinch) accept(r *Router, a *arena) {
+	pc.pair.accept(a)
+}
+
+func (pc *Pinch) reject(r *Router, a *arena) {
+	if pc.has(a) {
+		pc.end(r)
+	}
+}
+
+// Rotation recognizes two touches turning about each other. It claims
+// both once they have turned by more than the slop along the circle
+// between them.
+type Rotation struct {
+	onStart  func(focal core.Point)
+	onUpdate func(radians float32, focal core.Point)
+	onEnd    func()
+
+	pair
+}
+
+// NewRotation returns a rotation recognizer.
+func NewRotation() *Rotation {
+	return &Rotation{}
+}
+
+// OnStart sets fn to be called with the point between the touches when a
+// rotation is recognized.
+func (rt *Rotation) OnStart(fn func(focal core.Point)) *Rotation {
+	rt.onStart = fn
+	return rt
+}
+
+// OnUpdate sets fn to be called as the touches of a rotation move, with
+// the angle they turned by since the second went down, in radians
+// clockwise, and the point between them.
+func (rt *Rotation) OnUpdate(fn func(radians float32, focal core.Point)) *Rotation {
+	rt.onUpdate = fn
+	return rt
+}
+
+// OnEnd sets fn to be called when either touch of a rotation is lifted.
+func (rt *Rotation) OnEnd(fn func()) *Rotation {
+	rt.onEnd = fn
+	return rt
+}
+
+func (rt *Rotation) add(r *Router, p pointer) {
+	rt.pair.add(r, rt, p)
+}
+
+func (rt *Rotation) handle(r *Router, p pointer) {
+	i := rt.at(p)
+	if i < 0 {
+		return
+	}
+	if p.phase != phaseMove {
+		rt.end(r)
+		return
+	}
+	rt.pos[i] = p.pos
+	if rt.n < 2 {
+		return
+	}
+	// The turn is kept within half a circle either way.
+	turn := math.Remainder(float64(rt.angleNow()-rt.angle), 2*math.Pi)
+	if !rt.started {
+		if math.Abs(turn)*float64(rt.spanNow())/2 <= float64(p.slop()) || !rt.claim(r, rt) {
+			return
+		}
+		rt.started = true
+		if rt.onStart != nil {
+			rt.onStart(rt.focal())
+		}
+	}
+	if rt.onUpdate != nil {
+		rt.onUpdate(float32(turn), rt.focal())
+	}
+}
+
+func (rt *Rotation) end(r *Router) {
+	started := rt.started
+	rt.reset(r, rt)
+	if started && rt.onEnd != nil {
+		rt.onEnd()
+	}
+}
+
+func (rt *Rotation) accept(r *Router, a *arena) {
+	rt.pair.accept(a)
+}
+
+func (rt *Rotation) reject(r *Router, a *arena) {
+	if rt.has(a) {
+		rt.end(r)
+	}
+}
@@ -0,0 +1,340 @@
+package gesture
+
+import (
+	"math"
+	"time"
+
+	"github.com/gogpu/ui/core"
+)
+
+const (
+	doubleTapDelay = 300 * time.Millisecond
+	doubleTapSlop  = 100 // How far apart the taps of a double tap may be.
+	longPressDelay = 500 * time.Millisecond
+)
+
+// distance returns the distance from p to q.
+func distance(p, q core.Point) float32 {
+	return float32(math.Hypot(float64(p.X-q.X), float64(p.Y-q.Y)))
+}
+
+// Tap recognizes a pointer pressed and lifted without moving far, as a
+// click or a tap. As it makes no claim, it wins a press when the others
+// give it up, or when the press is lifted with the tap first in its
+// arena.
+type Tap struct {
+	onDown   func(p core.Point)
+	onTap    func(p core.Point)
+	onCancel func()
+
+	a      *arena // Of the press followed, or nil.
+	down   core.Point
+	up     core.Point
+	lifted bool
+	won    bool
+}
+
+// NewTap returns a tap recognizer.
+func NewTap() *Tap {
+	return &Tap{}
+}
+
+// OnDown sets fn to be called with the position of a press that may be a
+// tap, such as for showing it pressed.
+func (t *Tap) OnDown(fn func(p core.Point)) *Tap {
+	t.onDown = fn
+	return t
+}
+
+// OnTap sets fn to be called with the position where a tap was lifted.
+func (t *Tap) OnTap(fn func(p core.Point)) *Tap {
+	t.onTap = fn
+	return t
+}
+
+// OnCancel sets fn to be called when a press reported to OnDown turns out
+// not to be a tap.
+func (t *Tap) OnCancel(fn func()) *Tap {
+	t.onCancel = fn
+	return t
+}
+
+func (t *Tap) add(r *Router, p pointer) {
+	if t.a != nil {
+		return
+	}
+	t.a, t.down, t.lifted, t.won = r.join(t, p), p.pos, false, false
+	if t.onDown != nil {
+		t.onDown(p.pos)
+	}
+}
+
+func (t *Tap) handle(r *Router, p pointer) {
+	switch p.phase {
+	case phaseMove:
+		if distance(p.pos, t.down) > p.slop() {
+			t.cancel(r)
+		}
+	case phaseUp:
+		t.lifted, t.up = true, p.pos
+		if t.won {
+			t.fire()
+		}
+	}
+}
+
+func (t *Tap) accept(r *Router, a *arena) {
+	t.won = true
+	if t.lifted {
+		t.fire()
+	}
+}
+
+func (t *Tap) reject(r *Router, a *arena) {
+	if t.a != a {
+		return
+	}
+	t.a = nil
+	if t.onCancel != nil {
+		t.onCancel()
+	}
+}
+
+// cancel gives up the press, even if it was won.
+func (t *Tap) cancel(r *Router) {
+	a := t.a
+	r.resolve(a, t, false)
+	if t.a == a {
+		t.reject(r, a)
+	}
+	r.stop(a, t)
+}
+
+func (t *Tap) fire() {
+	t.a = nil
+	if t.onTap != nil {
+		t.onTap(t.up)
+	}
+}
+
+// DoubleTap recognizes two taps in quick succession near each other. It
+// holds the arena of the first tap while it waits for the second, so that
+// a single tap competing with it only wins once the time for the second
+// has passed.
+type DoubleTap struct {
+	onDoubleTap func(p core.Point)
+
+	taps    [2]*arena // Of the taps followed.
+	down    [2]core.Point
+	waiting bool // Set between the first tap and the second.
+	gen     int
+}
+
+// NewDoubleTap returns a double tap recognizer.
+func NewDoubleTap() *DoubleTap {
+	return &DoubleTap{}
+}
+
+// OnDoubleTap sets fn to be called with the position of the first tap of
+// a double tap, once the second is lifted.
+func (dt *DoubleTap) OnDoubleTap(fn func(p core.Point)) *DoubleTap {
+	dt.onDoubleTap = fn
+	return dt
+}
+
+func (dt *DoubleTap) add(r *Router, p pointer) {
+	switch {
+	case dt.taps[0] == nil:
+	case dt.waiting && distance(p.pos, dt.down[0]) <= doubleTapSlop:
+		dt.waiting = false
+		dt.gen++
+		dt.taps[1], dt.down[1] = r.join(dt, p), p.pos
+		return
+	case dt.waiting:
+		// Too far from the first to be its second; it may be a first.
+		dt.reset(r)
+	default:
+		return
+	}
+	dt.taps[0], dt.down[0] = r.join(dt, p), p.pos
+}
+
+func (dt *DoubleTap) handle(r *Router, p pointer) {
+	i := 0
+	if dt.taps[1] != nil {
+		i = 1
+	}
+	switch p.phase {
+	case phaseMove:
+		if distance(p.pos, dt.down[i]) > p.slop() {
+			dt.reset(r)
+		}
+	case phaseUp:
+		if i == 0 {
+			r.hold(dt.taps[0])
+			dt.waiting = true
+			dt.gen++
+			gen := dt.gen
+			time.AfterFunc(doubleTapDelay, func() {
+				r.ctx.Post(func() {
+					if dt.waiting && dt.gen == gen {
+						dt.reset(r)
+					}
+				})
+			})
+			return
+		}
+		first, second, pos := dt.taps[0], dt.taps[1], dt.down[0]
+		dt.taps = [2]*arena{}
+		r.resolve(first, dt, true)
+		r.resolve(second, dt, true)
+		r.release(first)
+		if dt.onDoubleTap != nil {
+			dt.onDoubleTap(pos)
+		}
+	}
+}
+
+func (dt *DoubleTap) accept(r *Router, a *arena) {}
+
+func (dt *DoubleTap) reject(r *Router, a *arena) {
+	if a == dt.taps[0] || a == dt.taps[1] {
+		dt.reset(r)
+	}
+}
+
+// reset gives up the taps followed.
+func (dt *DoubleTap) reset(r *Router) {
+	taps := dt.taps
+	if taps[0] == nil {
+		return
+	}
+	dt.taps, dt.waiting = [2]*arena{}, false
+	dt.gen++
+	for _, a := range taps {
+		if a != nil {
+			r.resolve(a, dt, false)
+			r.stop(a, dt)
+		}
+	}
+	r.release(taps[0])
+}
+
+// LongPress recognizes a pointer held still for a while, half a second by
+// default. It claims the press then, and follows it until it is lifted.
+type LongPress struct {
+	onPress func(p core.Point)
+	onMove  func(p core.Point)
+	onEnd   func(p core.Point)
+	delay   time.Duration
+
+	a       *arena // Of the press followed, or nil.
+	down    core.Point
+	pressed bool // Set once held for the delay.
+	won     bool
+	gen     int
+}
+
+// NewLongPress returns a long press recognizer.
+func NewLongPress() *LongPress {
+	return &LongPress{delay: longPressDelay}
+}
+
+// Delay sets how long the pointer must be held, 500 ms by default.
+func (lp *LongPress) Delay(d time.Duration) *LongPress {
+	lp.delay = d
+	return lp
+}
+
+// OnLongPress sets fn to be called with the position of a long press once
+// it has been held for the delay.
+func (lp *LongPress) OnLongPress(fn func(p core.Point)) *LongPress {
+	lp.onPress = fn
+	return lp
+}
+
+// OnMove sets fn to be called with the position of the pointer as it
+// moves after a long press, such as for dragging what it picked up.
+func (lp *LongPress) OnMove(fn func(p core.Point)) *LongPress {
+	lp.onMove = fn
+	return lp
+}
+
+// OnEnd sets fn to be called with the position where a long press was
+// lifted.
+func (lp *LongPress) OnEnd(fn func(p core.Point)) *LongPress {
+	lp.onEnd = fn
+	return lp
+}
+
+func (lp *LongPress) add(r *Router, p pointer) {
+	if lp.a != nil {
+		return
+	}
+	lp.a, lp.down, lp.pressed, lp.won = r.join(lp, p), p.pos, false, false
+	lp.gen++
+	gen := lp.gen
+	time.AfterFunc(lp.delay, func() {
+		r.ctx.Post(func() {
+			if lp.a == nil || lp.gen != gen {
+				return
+			}
+			lp.pressed = true
+			if lp.won {
+				lp.press()
+			} else {
+				r.resolve(lp.a, lp, true)
+			}
+		})
+	})
+}
+
+func (lp *LongPress) handle(r *Router, p pointer) {
+	switch p.phase {
+	case phaseMove:
+		switch {
+		case lp.pressed && lp.won:
+			if lp.onMove != nil {
+				lp.onMove(p.pos)
+			}
+		case distance(p.pos, lp.down) > p.slop():
+			lp.giveUp(r)
+		}
+	case phaseUp:
+		if lp.pressed && lp.won {
+			lp.a = nil
+			if lp.onEnd != nil {
+				lp.onEnd(p.pos)
+			}
+			return
+		}
+		lp.giveUp(r)
+	}
+}
+
+// giveUp stops following the press, before it was held long enough.
+func (lp *LongPress) giveUp(r *Router) {
+	a := lp.a
+	lp.a = nil
+	r.resolve(a, lp, false)
+	r.stop(a, lp)
+}
+
+func (lp *LongPress) accept(r *Router, a *arena) {
+	lp.won = true
+	if lp.pressed {
+		lp.press()
+	}
+}
+
+func (lp *LongPress) reject(r *Router, a *arena) {
+	if lp.a == a {
+		lp.a = nil
+	}
+}
+
+func (lp *LongPress) press() {
+	if lp.onPress != nil {
+		lp.onPress(lp.down)
+	}
+}
@@ -0,0 +1,203 @@
+// Package grapheme finds the boundaries of extended grapheme clusters
+// (UAX #29), the units a user thinks of as characters. An emoji with a
+// skin tone, a family joined with zero-width joiners, a flag made of two
+// regional indicators and a letter with combining accents are each one
+// cluster of several code points, which a caret must step over and a
+// deletion must remove whole:
+//
+//	text := []rune("👍🏽 ok")
+//	grapheme.Next(text, 0) // 2: the thumb and its skin tone
+//
+// Positions are rune indices.
+package grapheme
+
+// prop is the Grapheme_Cluster_Break property of a character, with
+// Extended_Pictographic folded in.
+type prop uint8
+
+const (
+	other prop = iota
+	cr
+	lf
+	control
+	extend
+	mark // Extend, of a nonzero combining class.
+	zwj
+	regionalIndicator
+	prepend
+	spacingMark
+	l
+	v
+	t
+	lv
+	lvt
+	extPict
+)
+
+type propRange struct {
+	lo, hi rune
+	prop   prop
+}
+
+const (
+	hangulBase  = 0xAC00
+	hangulLast  = 0xD7A3
+	hangulTails = 28 // Syllables per leading and vowel jamo pair.
+)
+
+func propOf(r rune) prop {
+	if r >= hangulBase && r <= hangulLast {
+		if (r-hangulBase)%hangulTails == 0 {
+			return lv
+		}
+		return lvt
+	}
+	lo, hi := 0, len(props)
+	for lo < hi {
+		m := (lo + hi) / 2
+		switch {
+		case r < props[m].lo:
+			hi = m
+		case r > props[m].hi:
+			lo = m + 1
+		default:
+			return props[m].prop
+		}
+	}
+	return other
+}
+
+// IsBoundary reports whether a cluster boundary falls before text[i]. The
+// start and end of the text are boundaries.
+func IsBoundary(text []rune, i int) bool {
+	if i <= 0 || i >= len(text) {
+		return true
+	}
+	before, after := propOf(text[i-1]), propOf(text[i])
+	switch {
+	case before == cr && after == lf: // GB3.
+		return false
+	case before == cr || before == lf || before == control: // GB4.
+		return true
+	case after == cr || after == lf || after == control: // GB5.
+		return true
+	case before == l && (after == l || after == v || after == lv || after == lvt): // GB6.
+		return false
+	case (before == lv || before == v) && (after == v || after == t): // GB7.
+		return false
+	case (before == lvt || before == t) && after == t: // GB8.
+		return false
+	case after == extend || after == mark || after == zwj || after == spacingMark: // GB9, GB9a.
+		return false
+	case before == prepend: // GB9b.
+		return false
+	case isConsonant(text[i]) && conjunct(text, i): // GB9c.
+		return false
+	case before == zwj && after == extPict: // GB11.
+		j := i - 2
+		for j >= 0 && (propOf(text[j]) == extend || propOf(text[j]) == mark) {
+			j--
+		}
+		return j < 0 || propOf(text[j]) != extPict
+	case before == regionalIndicator && after == regionalIndicator: // GB12, GB13.
+		n := 0
+		for j := i - 1; j >= 0 && propOf(text[j]) == regionalIndicator; j-- {
+			n++
+		}
+		return n%2 == 0
+	}
+	return true // GB999.
+}
+
+// conjunct reports whether text[:i] ends in a consonant followed by a
+// virama, with combining marks or joiners around it, which joins the
+// consonant at i into an Indic conjunct.
+func conjunct(text []rune, i int) bool {
+	linked := false
+	for j := i - 1; j >= 0; j-- {
+		switch r := text[j]; {
+		case isLinker(r):
+			linked = true
+		case propOf(r) == mark || propOf(r) == zwj:
+		default:
+			return linked && isConsonant(r)
+		}
+	}
+	return false
+}
+
+// consonants are the characters of Indic_Conjunct_Break=Consonant.
+var consonants = [][2]rune{
+	{0x0915, 0x0939}, {0x0958, 0x095F}, {0x0978, 0x097F}, {0x0995, 0x09A8}, {0x09AA, 0x09B0},
+	{0x09B2, 0x09B2}, {0x09B6, 0x09B9}, {0x09DC, 0x09DD}, {0x09DF, 0x09DF}, {0x09F0, 0x09F1},
+	{0x0A95, 0x0AA8}, {0x0AAA, 0x0AB0}, {0x0AB2, 0x0AB3}, {0x0AB5, 0x0AB9}, {0x0AF9, 0x0AF9},
+	{0x0B15, 0x0B28}, {0x0B2A, 0x0B30}, {0x0B32, 0x0B33}, {0x0B35, 0x0B39}, {0x0B5C, 0x0B5D},
+	{0x0B5F, 0x0B5F}, {0x0B71, 0x0B71}, {0x0C15, 0x0C28}, {0x0C2A, 0x0C39}, {0x0C58, 0x0C5A},
+	{0x0D15, 0x0D3A},
+}
+
+func isConsonant(r rune) bool {
+	if r < 0x0915 || r > 0x0D3A {
+		return false
+	}
+	for _, c := range consonants {
+		if r >= c[0] && r <= c[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// isLinker reports whether r is a virama of Indic_Conjunct_Break=Linker.
+func isLinker(r rune) bool {
+	switch r {
+	case 0x094D, 0x09CD, 0x0ACD, 0x0B4D, 0x0C4D, 0x0D4D:
+		return true
+	}
+	return false
+}
+
+// Next returns the end of the cluster that starts at i, or len(text) if i
+// is at or past the end.
+func Next(text []rune, i int) int {
+	if i >= len(text) {
+		return len(text)
+	}
+	i = max(i, 0) + 1
+	for !IsBoundary(text, i) {
+		i++
+	}
+	return i
+}
+
+// Prev returns the start of the cluster that ends at i, or 0 if i is at
+// or before the start.
+func Prev(text []rune, i int) int {
+	if i <= 0 {
+		return 0
+	}
+	i = min(i, len(text)) - 1
+	for !IsBoundary(text, i) {
+		i--
+	}
+	return i
+}
+
+// Snap returns the start of the cluster that contains position i, so a
+// position set from outside never splits a cluster.
+func Snap(text []rune, i int) int {
+	i = min(max(i, 0), len(text))
+	for !IsBoundary(text, i) {
+		i--
+	}
+	return i
+}
+
+// Count returns the number of clusters in text.
+func Count(text []rune) int {
+	n := 0
+	for i := 0; i < len(text); i = Next(text, i) {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,138 @@
+package grapheme
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// breakTests are in the form of GraphemeBreakTest.txt: code points in
+// hex, with ÷ where a boundary falls and × where none does.
+var breakTests = []string{
+	// Line breaks and controls (GB3 to GB5).
+	"÷ 000D × 000A ÷ 0061 ÷",
+	"÷ 000A ÷ 000D ÷",
+	"÷ 000A ÷ 0308 ÷",
+	"÷ 0061 ÷ 0009 ÷ 0308 ÷",
+	"÷ 0061 ÷ 000D ÷",
+
+	// Combining marks and spacing marks (GB9, GB9a).
+	"÷ 0061 × 0308 ÷ 0062 ÷",
+	"÷ 0065 × 0301 × 0323 ÷ 0078 ÷",
+	"÷ 0020 × 0308 ÷",
+	"÷ 0915 × 093F ÷ 0916 ÷",
+
+	// Prepend (GB9b).
+	"÷ 0600 × 0661 ÷",
+
+	// Hangul syllables from jamo and precomposed (GB6 to GB8).
+	"÷ 1100 × 1161 × 11A8 ÷ 1100 ÷",
+	"÷ 1100 × AC00 ÷",
+	"÷ AC00 × 1161 ÷ AC00 ÷",
+	"÷ AC00 × 11A8 ÷ AC01 × 11A8 × 11A8 ÷",
+	"÷ AC01 ÷ 1161 ÷",
+	"÷ 11A8 ÷ 1100 ÷",
+
+	// Indic conjuncts (GB9c).
+	"÷ 0915 × 094D × 0937 ÷",
+	"÷ 0915 × 094D × 200D × 0937 ÷",
+	"÷ 0915 × 094D ÷ 0061 ÷",
+
+	// Emoji: modifiers, variation selectors and ZWJ sequences (GB11).
+	"÷ 1F44D × 1F3FD ÷ 0020 ÷",
+	"÷ 1F468 × 200D × 1F469 × 200D × 1F467 ÷",
+	"÷ 2764 × FE0F × 200D × 1F525 ÷",
+	"÷ 1F469 × 1F3FD × 200D × 1F4BB ÷",
+	"÷ 0061 × 200D ÷ 1F469 ÷",
+	"÷ 200D ÷ 1F469 ÷",
+
+	// Regional indicators pair up into flags (GB12, GB13).
+	"÷ 1F1FA × 1F1F8 ÷ 1F1EB × 1F1F7 ÷ 1F1E9 ÷",
+	"÷ 0061 ÷ 1F1FA × 1F1F8 ÷ 1F1EB ÷",
+	"÷ 1F1FA × 1F1F8 × 0308 ÷ 1F1EB ÷",
+}
+
+// parseBreakTest returns the text of a test and whether a boundary falls
+// before each position, including the end.
+func parseBreakTest(t *testing.T, line string) ([]rune, []bool) {
+	var text []rune
+	var breaks []bool
+	for _, f := range strings.Fields(line) {
+		switch f {
+		case "÷":
+			breaks = append(breaks, true)
+		case "×":
+			breaks = append(breaks, false)
+		default:
+			r, err := strconv.ParseUint(f, 16, 32)
+			if err != nil {
+				t.Fatalf("%q: %v", line, err)
+			}
+			text = append(text, rune(r))
+		}
+	}
+	if len(breaks) != len(text)+1 {
+		t.Fatalf("%q: %d marks for %d code points", line, len(breaks), len(text))
+	}
+	return text, breaks
+}
+
+func TestIsBoundary(t *testing.T) {
+	for _, line := range breakTests {
+		text, want := parseBreakTest(t, line)
+		for i, w := range want {
+			if got := IsBoundary(text, i); got != w {
+				t.Errorf("%s: IsBoundary(%d) = %v, want %v", line, i, got, w)
+			}
+		}
+	}
+}
+
+func TestNextPrev(t *testing.T) {
+	for _, line := range breakTests {
+		text, breaks := parseBreakTest(t, line)
+		var bounds []int
+		for i, b := range breaks {
+			if b {
+				bounds = append(bounds, i)
+			}
+		}
+		for k := 0; k+1 < len(bounds); k++ {
+			if got := Next(text, bounds[k]); got != bounds[k+1] {
+				t.Errorf("%s: Next(%d) = %d, want %d", line, bounds[k], got, bounds[k+1])
+			}
+			if got := Prev(text, bounds[k+1]); got != bounds[k] {
+				t.Errorf("%s: Prev(%d) = %d, want %d", line, bounds[k+1], got, bounds[k])
+			}
+			for i := bounds[k]; i < bounds[k+1]; i++ {
+				if got := Snap(text, i); got != bounds[k] {
+					t.Errorf("%s: Snap(%d) = %d, want %d", line, i, got, bounds[k])
+				}
+			}
+		}
+		if got := Count(text); got != len(bounds)-1 {
+			t.Errorf("%s: Count() = %d, want %d", line, got, len(bounds)-1)
+		}
+	}
+}
+
+func TestOutOfRange(t *testing.T) {
+	text := []rune("ab")
+	tests := []struct {
+		name      string
+		got, want int
+	}{
+		{"Next past the end", Next(text, 5), 2},
+		{"Next before the start", Next(text, -3), 1},
+		{"Prev before the start", Prev(text, -1), 0},
+		{"Prev past the end", Prev(text, 9), 1},
+		{"Snap past the end", Snap(text, 9), 2},
+		{"Snap before the start", Snap(text, -1), 0},
+		{"Count of nothing", Count(nil), 0},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %d, want %d", tt.name, tt.got, tt.want)
+		}
+	}
+}
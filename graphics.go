@@ -0,0 +1,211 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// GraphicsAPI is a graphics API a platform integration renders with.
+type GraphicsAPI uint8
+
+const (
+	GraphicsNone GraphicsAPI = iota // None opened yet.
+	GraphicsDX12
+	GraphicsVulkan
+	GraphicsMetal
+	GraphicsGL
+	GraphicsCPU // Package raster, without a GPU.
+)
+
+var graphicsNames = [...]string{"none", "dx12", "vulkan", "metal", "gl", "cpu"}
+
+// String returns the lower-case name of the API, as the GOGPU_UI_BACKEND
+// environment variable names it.
+func (a GraphicsAPI) String() string {
+	if int(a) < len(graphicsNames) {
+		return graphicsNames[a]
+	}
+	return fmt.Sprintf("GraphicsAPI(%d)", a)
+}
+
+// FallbackOrder is the order OpenGraphics tries graphics APIs in by
+// default. The CPU comes last and always opens.
+var FallbackOrder = []GraphicsAPI{GraphicsDX12, GraphicsVulkan, GraphicsMetal, GraphicsGL, GraphicsCPU}
+
+// ErrGraphicsUnsupported is returned by GraphicsOpeners for APIs the
+// platform does not have, such as Metal on Windows.
+var ErrGraphicsUnsupported = errors.New("ui: graphics API not supported on this platform")
+
+// AdapterType is the kind of device an Adapter is.
+type AdapterType uint8
+
+const (
+	AdapterUnknown AdapterType = iota
+	AdapterDiscrete
+	AdapterIntegrated
+	AdapterVirtual
+	AdapterSoftware
+)
+
+var adapterTypes = [...]string{"unknown", "discrete", "integrated", "virtual", "software"}
+
+// String returns the lower-case name of the type.
+func (t AdapterType) String() string {
+	if int(t) < len(adapterTypes) {
+		return adapterTypes[t]
+	}
+	return fmt.Sprintf("AdapterType(%d)", t)
+}
+
+// Adapter describes the device a graphics API opened.
+type Adapter struct {
+	Name   string
+	Vendor string
+	Driver string // Name and version of the driver.
+	Type   AdapterType
+
+	Features GraphicsFeatures
+}
+
+// GraphicsFeatures are the capabilities of an Adapter that rendering
+// depends on.
+type GraphicsFeatures struct {
+	MaxTextureSize int // In texels on a side.
+	MaxSamples     int // The most MSAA samples of a pixel.
+	Compute        bool
+	Float16        bool // Half-precision floats in shaders.
+	Timestamps     bool // GPU timestamp queries, for profiling frames.
+}
+
+// GraphicsOpener is implemented by platform integrations, which open the
+// device of a graphics API for OpenGraphics and describe its adapter, or
+// return an error, ErrGraphicsUnsupported for APIs the platform lacks.
+// OpenGraphics opens the CPU itself.
+type GraphicsOpener interface {
+	OpenGraphics(api GraphicsAPI) (Adapter, error)
+}
+
+// GraphicsAttempt is a graphics API OpenGraphics tried, and the error it
+// failed with, or nil for the one it opened.
+type GraphicsAttempt struct {
+	API GraphicsAPI
+	Err error
+}
+
+// Diagnostics is a report of the graphics in use, for logs, bug reports
+// and about dialogs.
+type Diagnostics struct {
+	API      GraphicsAPI
+	Adapter  Adapter
+	Attempts []GraphicsAttempt
+	OS, Arch string
+}
+
+// String formats the report over several lines, leaving out the APIs the
+// platform lacks.
+func (d Diagnostics) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "backend: %s\n", d.API)
+	if d.Adapter.Name != "" {
+		fmt.Fprintf(&b, "adapter: %s (%s, %s)\n", d.Adapter.Name, d.Adapter.Vendor, d.Adapter.Type)
+	}
+	if d.Adapter.Driver != "" {
+		fmt.Fprintf(&b, "driver: %s\n", d.Adapter.Driver)
+	}
+	f := d.Adapter.Features
+	fmt.Fprintf(&b, "features: max texture %d, max samples %d, compute %t, f16 %t, timestamps %t\n",
+		f.MaxTextureSize, f.MaxSamples, f.Compute, f.Float16, f.Timestamps)
+	for _, a := range d.Attempts {
+		if a.Err != nil && !errors.Is(a.Err, ErrGraphicsUnsupported) {
+			fmt.Fprintf(&b, "failed: %s: %v\n", a.API, a.Err)
+		}
+	}
+	fmt.Fprintf(&b, "platform: %s/%s", d.OS, d.Arch)
+	return b.String()
+}
+
+var graphics struct {
+	mu   sync.Mutex
+	diag Diagnostics
+}
+
+// cpuAdapter is the Adapter of GraphicsCPU.
+var cpuAdapter = Adapter{
+	Name:     "gogpu/ui raster",
+	Vendor:   "software",
+	Type:     AdapterSoftware,
+	Features: GraphicsFeatures{MaxTextureSize: 1 << 14, MaxSamples: 8},
+}
+
+// OpenGraphics opens the first graphics API of order, FallbackOrder if it
+// is empty, that o opens, trying the next when one fails or panics, such
+// as when a driver crashes creating its device, and returns it. An API
+// named by the GOGPU_UI_BACKEND environment variable is tried first. The
+// CPU always opens, so it fails only for an order without it. What was
+// tried is kept for GraphicsDiagnostics, and windows of BackendAuto
+// render on the CPU once that is what opened.
+func OpenGraphics(o GraphicsOpener, order ...GraphicsAPI) (GraphicsAPI, error) {
+	if len(order) == 0 {
+		order = FallbackOrder
+	}
+	if env := strings.ToLower(os.Getenv(backendEnv)); env != "" {
+		if i := slices.Index(graphicsNames[:], env); i > 0 {
+			api := GraphicsAPI(i)
+			order = append([]GraphicsAPI{api}, slices.DeleteFunc(slices.Clone(order), func(a GraphicsAPI) bool { return a == api })...)
+		}
+	}
+	diag := Diagnostics{OS: runtime.GOOS, Arch: runtime.GOARCH}
+	defer func() {
+		graphics.mu.Lock()
+		graphics.diag = diag
+		graphics.mu.Unlock()
+	}()
+	var errs []error
+	for _, api := range order {
+		var (
+			ad  Adapter
+			err error
+		)
+		if api == GraphicsCPU {
+			ad = cpuAdapter
+		} else {
+			ad, err = openGraphics(o, api)
+		}
+		diag.Attempts = append(diag.Attempts, GraphicsAttempt{API: api, Err: err})
+		if err == nil {
+			diag.API, diag.Adapter = api, ad
+			return api, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", api, err))
+	}
+	return GraphicsNone, fmt.Errorf("ui: no graphics API opened: %w", errors.Join(errs...))
+}
+
+// openGraphics opens api with o, turning a panic of the opener, such as
+// a driver crashing in its bindings, into an error.
+func openGraphics(o GraphicsOpener, api GraphicsAPI) (ad Adapter, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			ad, err = Adapter{}, fmt.Errorf("panic opening the device: %v", r)
+		}
+	}()
+	return o.OpenGraphics(api)
+}
+
+// GraphicsDiagnostics returns the report of the last OpenGraphics. It is
+// safe to call from any goroutine.
+func GraphicsDiagnostics() Diagnostics {
+	graphics.mu.Lock()
+	defer graphics.mu.Unlock()
+	d := graphics.diag
+	d.Attempts = slices.Clone(d.Attempts)
+	if d.OS == "" {
+		d.OS, d.Arch = runtime.GOOS, runtime.GOARCH
+	}
+	return d
+}
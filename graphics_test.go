@@ -0,0 +1,108 @@
+package ui
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// opener opens the APIs it has adapters for, fails with the errors it has
+// and panics with the values it has for the others.
+type opener struct {
+	adapters map[GraphicsAPI]Adapter
+	errs     map[GraphicsAPI]error
+	panics   map[GraphicsAPI]any
+	tried    []GraphicsAPI
+}
+
+func (o *opener) OpenGraphics(api GraphicsAPI) (Adapter, error) {
+	o.tried = append(o.tried, api)
+	if v, ok := o.panics[api]; ok {
+		panic(v)
+	}
+	if ad, ok := o.adapters[api]; ok {
+		return ad, nil
+	}
+	if err, ok := o.errs[api]; ok {
+		return Adapter{}, err
+	}
+	return Adapter{}, ErrGraphicsUnsupported
+}
+
+func TestOpenGraphics(t *testing.T) {
+	gpu := Adapter{Name: "gpu", Type: AdapterDiscrete}
+	tests := []struct {
+		name    string
+		opener  *opener
+		order   []GraphicsAPI
+		env     string
+		want    GraphicsAPI
+		tried   []GraphicsAPI
+		failed  string // In the diagnostics.
+		wantErr bool
+	}{
+		{
+			name:   "first that opens",
+			opener: &opener{adapters: map[GraphicsAPI]Adapter{GraphicsGL: gpu}},
+			want:   GraphicsGL,
+			tried:  []GraphicsAPI{GraphicsDX12, GraphicsVulkan, GraphicsMetal, GraphicsGL},
+		},
+		{
+			name:   "falls back to the CPU",
+			opener: &opener{errs: map[GraphicsAPI]error{GraphicsVulkan: errors.New("no device")}},
+			want:   GraphicsCPU,
+			tried:  []GraphicsAPI{GraphicsDX12, GraphicsVulkan, GraphicsMetal, GraphicsGL},
+			failed: "failed: vulkan: no device",
+		},
+		{
+			name: "falls back after a panic",
+			opener: &opener{
+				panics:   map[GraphicsAPI]any{GraphicsVulkan: "driver crashed"},
+				adapters: map[GraphicsAPI]Adapter{GraphicsGL: gpu},
+			},
+			order:  []GraphicsAPI{GraphicsVulkan, GraphicsGL},
+			want:   GraphicsGL,
+			tried:  []GraphicsAPI{GraphicsVulkan, GraphicsGL},
+			failed: "failed: vulkan: panic opening the device: driver crashed",
+		},
+		{
+			name:   "environment first",
+			opener: &opener{adapters: map[GraphicsAPI]Adapter{GraphicsGL: gpu, GraphicsVulkan: gpu}},
+			env:    "GL",
+			want:   GraphicsGL,
+			tried:  []GraphicsAPI{GraphicsGL},
+		},
+		{
+			name:    "none without the CPU",
+			opener:  &opener{panics: map[GraphicsAPI]any{GraphicsGL: errors.New("boom")}},
+			order:   []GraphicsAPI{GraphicsMetal, GraphicsGL},
+			want:    GraphicsNone,
+			tried:   []GraphicsAPI{GraphicsMetal, GraphicsGL},
+			failed:  "failed: gl: panic opening the device: boom",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(backendEnv, tt.env)
+			got, err := OpenGraphics(tt.opener, tt.order...)
+			if got != tt.want || (err != nil) != tt.wantErr {
+				t.Fatalf("OpenGraphics() = %v, %v, want %v, error %t", got, err, tt.want, tt.wantErr)
+			}
+			if !slices.Equal(tt.opener.tried, tt.tried) {
+				t.Errorf("tried %v, want %v", tt.opener.tried, tt.tried)
+			}
+			d := GraphicsDiagnostics()
+			if d.API != tt.want {
+				t.Errorf("diagnostics API = %v, want %v", d.API, tt.want)
+			}
+			if tt.failed != "" && !strings.Contains(d.String(), tt.failed) {
+				t.Errorf("diagnostics do not report %q:\n%s", tt.failed, d)
+			}
+			if strings.Contains(d.String(), ErrGraphicsUnsupported.Error()) {
+				t.Errorf("diagnostics report unsupported APIs:\n%s", d)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+// Package hyphen finds the points where words may be broken with a hyphen,
+// by Liang's algorithm with the hyphenation patterns of TeX. Patterns for
+// American English are built in; those of other languages can be loaded
+// with New and registered for their locale:
+//
+//	h := hyphen.For("en-US")
+//	h.Points("hyphenation") // [2 6 7]: hy-phen-a-tion
+//
+// Points are byte offsets in the word.
+package hyphen
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Hyphenator hyphenates the words of one language.
+type Hyphenator struct {
+	// LeftMin and RightMin are the fewest characters left before the
+	// first hyphen and after the last, 2 and 3 unless changed.
+	LeftMin, RightMin int
+
+	patterns   map[string][]uint8
+	maxLen     int
+	exceptions map[string][]int
+}
+
+// New returns a Hyphenator for patterns and exceptions in the format of
+// TeX's \patterns and \hyphenation, separated by white space: patterns
+// such as "hy3ph" and ".ach4", in which the digits between letters rank
+// the points between them and dots stand for the ends of a word, and
+// words hyphenated in full such as "ta-ble", which override them.
+func New(patterns, exceptions string) *Hyphenator {
+	h := &Hyphenator{
+		LeftMin:    2,
+		RightMin:   3,
+		patterns:   make(map[string][]uint8),
+		exceptions: make(map[string][]int),
+	}
+	for _, p := range strings.Fields(patterns) {
+		var letters []rune
+		values := []uint8{0}
+		for _, r := range p {
+			if r >= '0' && r <= '9' {
+				values[len(values)-1] = uint8(r - '0')
+				continue
+			}
+			letters = append(letters, unicode.ToLower(r))
+			values = append(values, 0)
+		}
+		h.patterns[string(letters)] = values
+		h.maxLen = max(h.maxLen, len(letters))
+	}
+	for _, e := range strings.Fields(exceptions) {
+		var points []int
+		n := 0
+		for _, r := range e {
+			if r == '-' {
+				points = append(points, n)
+				continue
+			}
+			n++
+		}
+		h.exceptions[strings.ToLower(strings.ReplaceAll(e, "-", ""))] = points
+	}
+	return h
+}
+
+// Points returns the byte offsets in word before which it may be broken
+// with a hyphen, in increasing order. Words with characters other than
+// letters are not hyphenated.
+func (h *Hyphenator) Points(word string) []int {
+	if h == nil {
+		return nil
+	}
+	runes := []rune(strings.ToLower(word))
+	for _, r := range runes {
+		if !unicode.IsLetter(r) {
+			return nil
+		}
+	}
+	if len(runes) < h.LeftMin+h.RightMin {
+		return nil
+	}
+	var at []int // Rune positions.
+	if points, ok := h.exceptions[string(runes)]; ok {
+		at = points
+	} else {
+		at = h.ranked(runes)
+	}
+	// Patterns are matched on lowercase letters, which in a few scripts
+	// take a different number of bytes than their uppercase forms, so
+	// the points are mapped back onto the original word.
+	var out []int
+	i, n := 0, 0
+	for _, p := range at {
+		if p < h.LeftMin || len(runes)-p < h.RightMin {
+			continue
+		}
+		for ; n < p; n++ {
+			_, size := utf8.DecodeRuneInString(word[i:])
+			i += size
+		}
+		out = append(out, i)
+	}
+	return out
+}
+
+// Hyphenate returns word with sep inserted at each of its points, as in
+// Hyphenate("hyphenation", "\u00ad") for text to be broken by layout.
+func (h *Hyphenator) Hyphenate(word, sep string) string {
+	points := h.Points(word)
+	if len(points) == 0 {
+		return word
+	}
+	var b strings.Builder
+	last := 0
+	for _, p := range points {
+		b.WriteString(word[last:p])
+		b.WriteString(sep)
+		last = p
+	}
+	b.WriteString(word[last:])
+	return b.String()
+}
+
+// ranked returns the rune positions of word that the patterns give odd
+// values, which are the points a hyphen may be put.
+func (h *Hyphenator) ranked(word []rune) []int {
+	padded := make([]rune, 0, len(word)+2)
+	padded = append(append(append(padded, '.'), word...), '.')
+	values := make([]uint8, len(padded)+1)
+	for i := range padded {
+		for n := 1; n <= h.maxLen && i+n <= len(padded); n++ {
+			p, ok := h.patterns[string(padded[i:i+n])]
+			if !ok {
+				continue
+			}
+			for k, v := range p {
+				values[i+k] = max(values[i+k], v)
+			}
+		}
+	}
+	var at []int
+	for p := 1; p < len(word); p++ {
+		// The point before letter p of the word follows padded[p].
+		if values[p+1]%2 == 1 {
+			at = append(at, p)
+		}
+	}
+	return at
+}
+
+var (
+	mu       sync.RWMutex
+	registry = map[string]*Hyphenator{}
+	enUS     = sync.OnceValue(func() *Hyphenator {
+		return New(enUSPatterns, enUSExceptions)
+	})
+)
+
+// Register makes h the Hyphenator For returns for locale, a BCP 47 tag
+// such as "de" or "pt-BR".
+func Register(locale string, h *Hyphenator) {
+	mu.Lock()
+	registry[strings.ToLower(locale)] = h
+	mu.Unlock()
+}
+
+// For returns the Hyphenator registered for locale, or for its language
+// if none is registered for the whole tag, so "de-AT" falls back to "de".
+// English is hyphenated with the built-in American English patterns
+// unless others are registered. It returns nil for languages without
+// patterns, which a nil Hyphenator never hyphenates.
+func For(locale string) *Hyphenator {
+	tag := strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+	mu.RLock()
+	defer mu.RUnlock()
+	for t := tag; ; {
+		if h, ok := registry[t]; ok {
+			return h
+		}
+		i := strings.LastIndexByte(t, '-')
+		if i < 0 {
+			break
+		}
+		t = t[:i]
+	}
+	if tag == "en" || strings.HasPrefix(tag, "en-") {
+		return enUS()
+	}
+	return nil
+}
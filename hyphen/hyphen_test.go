@@ -0,0 +1,101 @@
+package hyphen
+
+import (
+	"slices"
+	"testing"
+)
+
+// liang are the patterns of Liang's thesis that hyphenate "hyphenation"
+// as hy-phen-ation.
+const liang = "hy3ph he2n hena4 hen5at 1na n2at 1tio 2io o2n"
+
+func TestPoints(t *testing.T) {
+	tests := []struct {
+		name              string
+		patterns, excepts string
+		leftMin, rightMin int
+		word              string
+		want              []int
+	}{
+		{"patterns", liang, "", 2, 3, "hyphenation", []int{2, 6}},
+		{"capitals", liang, "", 2, 3, "Hyphenation", []int{2, 6}},
+		{"minimum fragments", "1b", "", 2, 3, "abababa", []int{3}},
+		{"no minimum", "1b", "", 1, 1, "abababa", []int{1, 3, 5}},
+		{"too short", "1b", "", 2, 3, "abab", nil},
+		{"word ends", ".a2b 1b", "", 1, 1, "abab", []int{3}},
+		{"exception", "1b", "aba-baba", 2, 3, "abababa", []int{3}},
+		{"exception in capitals", "1b", "aba-baba", 2, 3, "ABABABA", []int{3}},
+		{"exception within minimums", "1b", "a-babab-a", 2, 3, "abababa", nil},
+		{"not letters", "1b", "", 2, 3, "abab1bab", nil},
+		{"byte offsets", "1b", "", 2, 3, "ébébéb", []int{5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := New(tt.patterns, tt.excepts)
+			h.LeftMin, h.RightMin = tt.leftMin, tt.rightMin
+			if got := h.Points(tt.word); !slices.Equal(got, tt.want) {
+				t.Errorf("Points(%q) = %v, want %v", tt.word, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHyphenate(t *testing.T) {
+	h := New(liang, "")
+	if got, want := h.Hyphenate("hyphenation", "-"), "hy-phen-ation"; got != want {
+		t.Errorf("Hyphenate() = %q, want %q", got, want)
+	}
+	if got, want := h.Hyphenate("phone", "-"), "phone"; got != want {
+		t.Errorf("Hyphenate() of a word without points = %q, want %q", got, want)
+	}
+	var none *Hyphenator
+	if got := none.Points("hyphenation"); got != nil {
+		t.Errorf("nil Hyphenator: Points() = %v", got)
+	}
+}
+
+func TestBuiltIn(t *testing.T) {
+	h := For("en-US")
+	if h == nil {
+		t.Fatal(`For("en-US") = nil`)
+	}
+	for _, tt := range []struct {
+		word string
+		want []int
+	}{
+		{"hyphenation", []int{2, 6, 7}},
+		{"table", []int{2}},
+		{"cat", nil},
+	} {
+		if got := h.Points(tt.word); !slices.Equal(got, tt.want) {
+			t.Errorf("Points(%q) = %v, want %v", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestFor(t *testing.T) {
+	de := New("1b", "")
+	Register("DE", de)
+	t.Cleanup(func() {
+		mu.Lock()
+		delete(registry, "de")
+		mu.Unlock()
+	})
+	tests := []struct {
+		locale string
+		want   *Hyphenator
+	}{
+		{"de", de},
+		{"de-AT", de},
+		{"de_CH", de},
+		{"en", enUS()},
+		{"en-GB", enUS()},
+		{"fr", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := For(tt.locale); got != tt.want {
+			t.Errorf("For(%q) = %p, want %p", tt.locale, got, tt.want)
+		}
+	}
+}
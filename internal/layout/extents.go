@@ -0,0 +1,147 @@
+// Package layout contains layout algorithms shared by public containers.
+package layout
+
+import "math/bits"
+
+// Extents tracks the sizes of a long sequence of items along one axis and
+// answers offset queries in O(log n). Items whose size has not been measured
+// yet use the estimate.
+//
+// It is the backbone of every virtualized container: offsets of items far
+// outside the viewport are derived from estimates until they are realized
+// and measured.
+type Extents struct {
+	estimate float64
+	sizes    []float64 // size per item, each estimate until measured
+	measured []bool
+	tree     []float64 // Fenwick tree over sizes, 1-based
+}
+
+// NewExtents returns Extents for n items with the given estimated size.
+func NewExtents(n int, estimate float32) *Extents {
+	e := &Extents{estimate: float64(estimate)}
+	e.Reset(n)
+	return e
+}
+
+// Reset discards all measurements and resizes to n items.
+func (e *Extents) Reset(n int) {
+	e.sizes = make([]float64, n)
+	e.measured = make([]bool, n)
+	for i := range e.sizes {
+		e.sizes[i] = e.estimate
+	}
+	e.rebuild()
+}
+
+// Resize changes the item count, keeping measurements of surviving items.
+func (e *Extents) Resize(n int) {
+	old := len(e.sizes)
+	if n == old {
+		return
+	}
+	if n < old {
+		e.sizes = e.sizes[:n]
+		e.measured = e.measured[:n]
+	} else {
+		for range n - old {
+			e.sizes = append(e.sizes, e.estimate)
+			e.measured = append(e.measured, false)
+		}
+	}
+	e.rebuild()
+}
+
+// SetEstimate changes the size used for unmeasured items.
+func (e *Extents) SetEstimate(estimate float32) {
+	e.estimate = float64(estimate)
+	for i, m := range e.measured {
+		if !m {
+			e.sizes[i] = e.estimate
+		}
+	}
+	e.rebuild()
+}
+
+func (e *Extents) rebuild() {
+	n := len(e.sizes)
+	e.tree = make([]float64, n+1)
+	for i, s := range e.sizes {
+		e.tree[i+1] += s
+		if j := i + 1 + (i+1)&-(i+1); j <= n {
+			e.tree[j] += e.tree[i+1]
+		}
+	}
+}
+
+// Len returns the item count.
+func (e *Extents) Len() int {
+	return len(e.sizes)
+}
+
+// Size returns the current size of item i.
+func (e *Extents) Size(i int) float32 {
+	return float32(e.sizes[i])
+}
+
+// IsMeasured reports whether item i has a measured size.
+func (e *Extents) IsMeasured(i int) bool {
+	return e.measured[i]
+}
+
+// Set records the measured size of item i and reports whether it changed.
+func (e *Extents) Set(i int, size float32) bool {
+	s := float64(size)
+	e.measured[i] = true
+	d := s - e.sizes[i]
+	if d == 0 {
+		return false
+	}
+	e.sizes[i] = s
+	for j := i + 1; j < len(e.tree); j += j & -j {
+		e.tree[j] += d
+	}
+	return true
+}
+
+// Forget marks item i as unmeasured so that it reverts to the estimate.
+func (e *Extents) Forget(i int) {
+	e.Set(i, float32(e.estimate))
+	e.measured[i] = false
+}
+
+// Offset returns the sum of the sizes of items [0, i).
+func (e *Extents) Offset(i int) float32 {
+	var sum float64
+	for j := min(i, len(e.sizes)); j > 0; j -= j & -j {
+		sum += e.tree[j]
+	}
+	return float32(sum)
+}
+
+// Total returns the sum of all sizes.
+func (e *Extents) Total() float32 {
+	return e.Offset(len(e.sizes))
+}
+
+// IndexAt returns the index of the item covering offset. Offsets before the
+// first item return 0 and offsets past the end return Len()-1. It returns
+// -1 if there are no items.
+func (e *Extents) IndexAt(offset float32) int {
+	n := len(e.sizes)
+	if n == 0 {
+		return -1
+	}
+	if offset <= 0 {
+		return 0
+	}
+	target := float64(offset)
+	pos := 0
+	for step := 1 << (bits.Len(uint(n)) - 1); step > 0; step >>= 1 {
+		if next := pos + step; next <= n && e.tree[next] <= target {
+			pos = next
+			target -= e.tree[next]
+		}
+	}
+	return min(pos, n-1)
+}
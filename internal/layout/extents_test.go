@@ -0,0 +1,80 @@
+package layout
+
+import "testing"
+
+func TestExtentsOffsets(t *testing.T) {
+	e := NewExtents(5, 10)
+	e.Set(1, 30)
+	e.Set(3, 5)
+	tests := []struct {
+		i    int
+		want float32
+	}{
+		{0, 0}, {1, 10}, {2, 40}, {3, 50}, {4, 55}, {5, 65}, {9, 65},
+	}
+	for _, tt := range tests {
+		if got := e.Offset(tt.i); got != tt.want {
+			t.Errorf("Offset(%d) = %v, want %v", tt.i, got, tt.want)
+		}
+	}
+	if got := e.Total(); got != 65 {
+		t.Errorf("Total = %v, want 65", got)
+	}
+}
+
+func TestExtentsIndexAt(t *testing.T) {
+	e := NewExtents(4, 10) // 0, 10, 20, 30, end 40
+	e.Set(2, 20)           // 0, 10, 20, 40, end 50
+	tests := []struct {
+		offset float32
+		want   int
+	}{
+		{-5, 0}, {0, 0}, {9.9, 0}, {10, 1}, {25, 2}, {39.9, 2}, {40, 3}, {100, 3},
+	}
+	for _, tt := range tests {
+		if got := e.IndexAt(tt.offset); got != tt.want {
+			t.Errorf("IndexAt(%v) = %d, want %d", tt.offset, got, tt.want)
+		}
+	}
+	if got := NewExtents(0, 10).IndexAt(5); got != -1 {
+		t.Errorf("IndexAt on no items = %d, want -1", got)
+	}
+}
+
+func TestExtentsMeasurements(t *testing.T) {
+	e := NewExtents(3, 10)
+	if e.IsMeasured(1) {
+		t.Fatal("measured before Set")
+	}
+	if !e.Set(1, 25) || e.Set(1, 25) {
+		t.Error("Set should report only changes")
+	}
+	if !e.IsMeasured(1) || e.Size(1) != 25 {
+		t.Errorf("after Set: measured %v, size %v", e.IsMeasured(1), e.Size(1))
+	}
+
+	e.SetEstimate(20)
+	if e.Size(0) != 20 || e.Size(1) != 25 {
+		t.Errorf("SetEstimate changed sizes to %v, %v; want 20, 25", e.Size(0), e.Size(1))
+	}
+
+	e.Resize(5)
+	if e.Len() != 5 || e.Size(4) != 20 || e.Size(1) != 25 || e.Total() != 105 {
+		t.Errorf("after growing: len %d, sizes %v, %v, total %v", e.Len(), e.Size(4), e.Size(1), e.Total())
+	}
+	e.Resize(2)
+	if e.Len() != 2 || e.Total() != 45 {
+		t.Errorf("after shrinking: len %d, total %v", e.Len(), e.Total())
+	}
+
+	e.Forget(1)
+	if e.IsMeasured(1) || e.Size(1) != 20 {
+		t.Errorf("after Forget: measured %v, size %v", e.IsMeasured(1), e.Size(1))
+	}
+
+	e.Set(0, 1)
+	e.Reset(3)
+	if e.IsMeasured(0) || e.Total() != 60 {
+		t.Errorf("after Reset: measured %v, total %v", e.IsMeasured(0), e.Total())
+	}
+}
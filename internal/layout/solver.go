@@ -0,0 +1,655 @@
+package layout
+
+import (
+	"cmp"
+	"errors"
+	"math"
+	"slices"
+)
+
+// Strengths of constraints. A required constraint must hold; the others
+// are satisfied as well as possible, a stronger one always winning over any
+// number of weaker ones.
+const (
+	Required = 1001001000.0
+	Strong   = 1000000.0
+	Medium   = 1000.0
+	Weak     = 1.0
+)
+
+// Errors returned by Solver.
+var (
+	ErrDuplicateConstraint = errors.New("duplicate constraint")
+	ErrUnknownConstraint   = errors.New("unknown constraint")
+	ErrUnsatisfiable       = errors.New("unsatisfiable required constraint")
+	ErrDuplicateEdit       = errors.New("duplicate edit variable")
+	ErrUnknownEdit         = errors.New("unknown edit variable")
+	ErrRequiredEdit        = errors.New("edit variables cannot be required")
+	errUnbounded           = errors.New("objective is unbounded")
+)
+
+// Variable is an unknown of a constraint system. Value holds the solution
+// after Solver.UpdateVariables.
+type Variable struct {
+	Value float64
+}
+
+// Term is a variable multiplied by a coefficient.
+type Term struct {
+	Var   *Variable
+	Coeff float64
+}
+
+// Expression is a linear expression: the sum of its terms and a constant.
+type Expression struct {
+	Terms    []Term
+	Constant float64
+}
+
+// Relation is the operator of a constraint, which compares an expression
+// with zero.
+type Relation uint8
+
+// Relations.
+const (
+	LE Relation = iota // Expression <= 0
+	GE                 // Expression >= 0
+	EQ                 // Expression == 0
+)
+
+// Constraint is a relation between an expression and zero.
+type Constraint struct {
+	Expr     Expression
+	Op       Relation
+	Strength float64
+}
+
+// NewConstraint returns the constraint expr op 0 with the given strength,
+// clamped to Required.
+func NewConstraint(expr Expression, op Relation, strength float64) *Constraint {
+	return &Constraint{Expr: expr, Op: op, Strength: min(max(strength, 0), Required)}
+}
+
+// Solver is an incremental Cassowary solver for systems of linear
+// equalities and inequalities, after Badros, Borning and Stuckey, "The
+// Cassowary Linear Arithmetic Constraint Solving Algorithm" (2001), in the
+// formulation of the Kiwi library.
+//
+// Constraints can be added and removed at any time. Edit variables take
+// suggested values, such as a window size during a resize, which are
+// applied by re-optimizing the current solution rather than solving from
+// scratch.
+type Solver struct {
+	cns        map[*Constraint]tag
+	added      []*Constraint // In the order they were added.
+	rows       map[symbol]*row
+	vars       map[*Variable]symbol
+	edits      map[*Variable]*edit
+	infeasible []symbol
+	objective  *row
+	artificial *row
+	nextID     uint64
+}
+
+type symbolKind uint8
+
+const (
+	invalidSymbol symbolKind = iota
+	externalSymbol
+	slackSymbol
+	errorSymbol
+	dummySymbol
+)
+
+// symbol is a variable of the tableau. Its id orders symbols so that the
+// solver's choices do not depend on map iteration order.
+type symbol struct {
+	id   uint64
+	kind symbolKind
+}
+
+// tag records the symbols a constraint added to the tableau.
+type tag struct {
+	marker, other symbol
+}
+
+type edit struct {
+	tag        tag
+	constraint *Constraint
+	constant   float64
+}
+
+// NewSolver returns an empty solver.
+func NewSolver() *Solver {
+	return &Solver{
+		cns:       make(map[*Constraint]tag),
+		rows:      make(map[symbol]*row),
+		vars:      make(map[*Variable]symbol),
+		edits:     make(map[*Variable]*edit),
+		objective: newRow(0),
+	}
+}
+
+// AddConstraint adds c. It returns ErrUnsatisfiable if c is required and
+// conflicts with the required constraints already added.
+func (s *Solver) AddConstraint(c *Constraint) error {
+	if _, ok := s.cns[c]; ok {
+		return ErrDuplicateConstraint
+	}
+	r, t := s.createRow(c)
+	subject := chooseSubject(r, t)
+	if subject.kind == invalidSymbol && r.allDummies() {
+		if !nearZero(r.constant) {
+			return ErrUnsatisfiable
+		}
+		subject = t.marker
+	}
+	if subject.kind == invalidSymbol {
+		ok, err := s.addWithArtificialVariable(r)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			// The pivots made while trying left rows of c in the
+			// tableau.
+			s.rebuild()
+			return ErrUnsatisfiable
+		}
+	} else {
+		r.solveFor(subject)
+		s.substitute(subject, r)
+		s.rows[subject] = r
+	}
+	s.cns[c] = t
+	s.added = append(s.added, c)
+	return s.optimize(s.objective)
+}
+
+// rebuild solves the constraints added so far from scratch and suggests
+// the current edit values again.
+func (s *Solver) rebuild() {
+	added, edits := s.added, s.edits
+	*s = *NewSolver()
+	for _, c := range added {
+		s.AddConstraint(c)
+	}
+	for v, e := range edits {
+		s.edits[v] = &edit{tag: s.cns[e.constraint], constraint: e.constraint}
+		s.SuggestValue(v, e.constant)
+	}
+}
+
+// RemoveConstraint removes c.
+func (s *Solver) RemoveConstraint(c *Constraint) error {
+	t, ok := s.cns[c]
+	if !ok {
+		return ErrUnknownConstraint
+	}
+	delete(s.cns, c)
+	s.added = slices.DeleteFunc(s.added, func(x *Constraint) bool { return x == c })
+	s.removeMarkerEffects(t.marker, c.Strength)
+	s.removeMarkerEffects(t.other, c.Strength)
+	if _, ok := s.rows[t.marker]; ok {
+		delete(s.rows, t.marker)
+	} else {
+		leaving, ok := s.markerLeavingRow(t.marker)
+		if !ok {
+			return errUnbounded
+		}
+		r := s.rows[leaving]
+		delete(s.rows, leaving)
+		r.solveForPair(leaving, t.marker)
+		s.substitute(t.marker, r)
+	}
+	return s.optimize(s.objective)
+}
+
+// HasConstraint reports whether c was added.
+func (s *Solver) HasConstraint(c *Constraint) bool {
+	_, ok := s.cns[c]
+	return ok
+}
+
+// AddEditVariable makes v an edit variable, whose value is suggested with
+// SuggestValue and held with the given strength, which must be less than
+// Required.
+func (s *Solver) AddEditVariable(v *Variable, strength float64) error {
+	if _, ok := s.edits[v]; ok {
+		return ErrDuplicateEdit
+	}
+	strength = min(max(strength, 0), Required)
+	if strength == Required {
+		return ErrRequiredEdit
+	}
+	c := NewConstraint(Expression{Terms: []Term{{v, 1}}}, EQ, strength)
+	if err := s.AddConstraint(c); err != nil {
+		return err
+	}
+	s.edits[v] = &edit{tag: s.cns[c], constraint: c}
+	return nil
+}
+
+// RemoveEditVariable stops v from being an edit variable.
+func (s *Solver) RemoveEditVariable(v *Variable) error {
+	e, ok := s.edits[v]
+	if !ok {
+		return ErrUnknownEdit
+	}
+	delete(s.edits, v)
+	return s.RemoveConstraint(e.constraint)
+}
+
+// HasEditVariable reports whether v is an edit variable.
+func (s *Solver) HasEditVariable(v *Variable) bool {
+	_, ok := s.edits[v]
+	return ok
+}
+
+// SuggestValue suggests value for the edit variable v.
+func (s *Solver) SuggestValue(v *Variable, value float64) error {
+	e, ok := s.edits[v]
+	if !ok {
+		return ErrUnknownEdit
+	}
+	delta := value - e.constant
+	e.constant = value
+	if r, ok := s.rows[e.tag.marker]; ok {
+		if r.add(-delta) < 0 {
+			s.infeasible = append(s.infeasible, e.tag.marker)
+		}
+		return s.dualOptimize()
+	}
+	if r, ok := s.rows[e.tag.other]; ok {
+		if r.add(delta) < 0 {
+			s.infeasible = append(s.infeasible, e.tag.other)
+		}
+		return s.dualOptimize()
+	}
+	for _, sym := range s.sortedRows() {
+		r := s.rows[sym]
+		c := r.cells[e.tag.marker]
+		if c != 0 && r.add(delta*c) < 0 && sym.kind != externalSymbol {
+			s.infeasible = append(s.infeasible, sym)
+		}
+	}
+	return s.dualOptimize()
+}
+
+// UpdateVariables stores the current solution in the Value of every
+// variable used by a constraint.
+func (s *Solver) UpdateVariables() {
+	for v, sym := range s.vars {
+		if r, ok := s.rows[sym]; ok {
+			v.Value = r.constant
+		} else {
+			v.Value = 0
+		}
+	}
+}
+
+func (s *Solver) newSymbol(kind symbolKind) symbol {
+	s.nextID++
+	return symbol{id: s.nextID, kind: kind}
+}
+
+func (s *Solver) varSymbol(v *Variable) symbol {
+	if sym, ok := s.vars[v]; ok {
+		return sym
+	}
+	sym := s.newSymbol(externalSymbol)
+	s.vars[v] = sym
+	return sym
+}
+
+// createRow returns the tableau row for c, with the slack, error and dummy
+// symbols it needs, and the tag recording them.
+func (s *Solver) createRow(c *Constraint) (*row, tag) {
+	r := newRow(c.Expr.Constant)
+	for _, t := range c.Expr.Terms {
+		if nearZero(t.Coeff) {
+			continue
+		}
+		sym := s.varSymbol(t.Var)
+		if basic, ok := s.rows[sym]; ok {
+			r.insertRow(basic, t.Coeff)
+		} else {
+			r.insertSymbol(sym, t.Coeff)
+		}
+	}
+	var t tag
+	switch c.Op {
+	case LE, GE:
+		coeff := 1.0
+		if c.Op == GE {
+			coeff = -1
+		}
+		t.marker = s.newSymbol(slackSymbol)
+		r.insertSymbol(t.marker, coeff)
+		if c.Strength < Required {
+			t.other = s.newSymbol(errorSymbol)
+			r.insertSymbol(t.other, -coeff)
+			s.objective.insertSymbol(t.other, c.Strength)
+		}
+	case EQ:
+		if c.Strength < Required {
+			t.marker = s.newSymbol(errorSymbol)
+			t.other = s.newSymbol(errorSymbol)
+			r.insertSymbol(t.marker, -1)
+			r.insertSymbol(t.other, 1)
+			s.objective.insertSymbol(t.marker, c.Strength)
+			s.objective.insertSymbol(t.other, c.Strength)
+		} else {
+			t.marker = s.newSymbol(dummySymbol)
+			r.insertSymbol(t.marker, 1)
+		}
+	}
+	if r.constant < 0 {
+		r.reverseSign()
+	}
+	return r, t
+}
+
+// chooseSubject returns the symbol to solve a new row for: an external
+// symbol if there is one, otherwise a new slack or error symbol with a
+// negative coefficient.
+func chooseSubject(r *row, t tag) symbol {
+	var subject symbol
+	for sym := range r.cells {
+		if sym.kind == externalSymbol && (subject.kind == invalidSymbol || sym.id < subject.id) {
+			subject = sym
+		}
+	}
+	if subject.kind != invalidSymbol {
+		return subject
+	}
+	for _, sym := range []symbol{t.marker, t.other} {
+		if (sym.kind == slackSymbol || sym.kind == errorSymbol) && r.cells[sym] < 0 {
+			return sym
+		}
+	}
+	return symbol{}
+}
+
+// addWithArtificialVariable adds r to the tableau by minimizing an
+// artificial variable, reporting whether r could be satisfied.
+func (s *Solver) addWithArtificialVariable(r *row) (bool, error) {
+	art := s.newSymbol(slackSymbol)
+	s.rows[art] = r.clone()
+	s.artificial = r.clone()
+	if err := s.optimize(s.artificial); err != nil {
+		return false, err
+	}
+	success := nearZero(s.artificial.constant)
+	s.artificial = nil
+	if ar, ok := s.rows[art]; ok {
+		delete(s.rows, art)
+		if len(ar.cells) == 0 {
+			return success, nil
+		}
+		entering := ar.anyPivotableSymbol()
+		if entering.kind == invalidSymbol {
+			return false, nil
+		}
+		ar.solveForPair(art, entering)
+		s.substitute(entering, ar)
+		s.rows[entering] = ar
+	}
+	for _, r := range s.rows {
+		r.remove(art)
+	}
+	s.objective.remove(art)
+	return success, nil
+}
+
+// substitute replaces sym by r in every row and the objectives.
+func (s *Solver) substitute(sym symbol, r *row) {
+	for _, other := range s.sortedRows() {
+		or := s.rows[other]
+		or.substitute(sym, r)
+		if other.kind != externalSymbol && or.constant < 0 {
+			s.infeasible = append(s.infeasible, other)
+		}
+	}
+	s.objective.substitute(sym, r)
+	if s.artificial != nil {
+		s.artificial.substitute(sym, r)
+	}
+}
+
+// optimize runs the primal simplex method on objective.
+func (s *Solver) optimize(objective *row) error {
+	for {
+		entering := objective.enteringSymbol()
+		if entering.kind == invalidSymbol {
+			return nil
+		}
+		leaving, ok := s.leavingRow(entering)
+		if !ok {
+			return errUnbounded
+		}
+		r := s.rows[leaving]
+		delete(s.rows, leaving)
+		r.solveForPair(leaving, entering)
+		s.substitute(entering, r)
+		s.rows[entering] = r
+	}
+}
+
+// dualOptimize restores feasibility after edit values changed, with the
+// dual simplex method.
+func (s *Solver) dualOptimize() error {
+	for len(s.infeasible) > 0 {
+		leaving := s.infeasible[len(s.infeasible)-1]
+		s.infeasible = s.infeasible[:len(s.infeasible)-1]
+		r, ok := s.rows[leaving]
+		if !ok || r.constant >= 0 {
+			continue
+		}
+		entering := s.dualEnteringSymbol(r)
+		if entering.kind == invalidSymbol {
+			return errUnbounded
+		}
+		delete(s.rows, leaving)
+		r.solveForPair(leaving, entering)
+		s.substitute(entering, r)
+		s.rows[entering] = r
+	}
+	return nil
+}
+
+// leavingRow returns the basic symbol whose row limits entering the most.
+func (s *Solver) leavingRow(entering symbol) (symbol, bool) {
+	ratio := math.MaxFloat64
+	var leaving symbol
+	for sym, r := range s.rows {
+		if sym.kind == externalSymbol {
+			continue
+		}
+		c := r.cells[entering]
+		if c >= 0 {
+			continue
+		}
+		q := -r.constant / c
+		if q < ratio || q == ratio && sym.id < leaving.id {
+			ratio, leaving = q, sym
+		}
+	}
+	return leaving, leaving.kind != invalidSymbol
+}
+
+func (s *Solver) dualEnteringSymbol(r *row) symbol {
+	ratio := math.MaxFloat64
+	var entering symbol
+	for sym, c := range r.cells {
+		if c <= 0 || sym.kind == dummySymbol {
+			continue
+		}
+		q := s.objective.cells[sym] / c
+		if q < ratio || q == ratio && sym.id < entering.id {
+			ratio, entering = q, sym
+		}
+	}
+	return entering
+}
+
+// markerLeavingRow returns the row to pivot out when removing a constraint
+// whose marker is not basic.
+func (s *Solver) markerLeavingRow(marker symbol) (symbol, bool) {
+	r1, r2 := math.MaxFloat64, math.MaxFloat64
+	var first, second, third symbol
+	for _, sym := range s.sortedRows() {
+		r := s.rows[sym]
+		c := r.cells[marker]
+		switch {
+		case c == 0:
+		case sym.kind == externalSymbol:
+			third = sym
+		case c < 0:
+			if q := -r.constant / c; q < r1 {
+				r1, first = q, sym
+			}
+		default:
+			if q := r.constant / c; q < r2 {
+				r2, second = q, sym
+			}
+		}
+	}
+	for _, sym := range []symbol{first, second, third} {
+		if sym.kind != invalidSymbol {
+			return sym, true
+		}
+	}
+	return symbol{}, false
+}
+
+func (s *Solver) removeMarkerEffects(marker symbol, strength float64) {
+	if marker.kind != errorSymbol {
+		return
+	}
+	if r, ok := s.rows[marker]; ok {
+		s.objective.insertRow(r, -strength)
+	} else {
+		s.objective.insertSymbol(marker, -strength)
+	}
+}
+
+// sortedRows returns the basic symbols by id, for deterministic updates.
+func (s *Solver) sortedRows() []symbol {
+	syms := make([]symbol, 0, len(s.rows))
+	for sym := range s.rows {
+		syms = append(syms, sym)
+	}
+	slices.SortFunc(syms, func(a, b symbol) int { return cmp.Compare(a.id, b.id) })
+	return syms
+}
+
+// row is a row of the tableau: a basic symbol equals constant plus the sum
+// of cells times their symbols.
+type row struct {
+	constant float64
+	cells    map[symbol]float64
+}
+
+func newRow(constant float64) *row {
+	return &row{constant: constant, cells: make(map[symbol]float64)}
+}
+
+func (r *row) clone() *row {
+	c := newRow(r.constant)
+	for sym, v := range r.cells {
+		c.cells[sym] = v
+	}
+	return c
+}
+
+func (r *row) add(v float64) float64 {
+	r.constant += v
+	return r.constant
+}
+
+func (r *row) insertSymbol(sym symbol, coeff float64) {
+	v := r.cells[sym] + coeff
+	if nearZero(v) {
+		delete(r.cells, sym)
+	} else {
+		r.cells[sym] = v
+	}
+}
+
+func (r *row) insertRow(other *row, coeff float64) {
+	r.constant += other.constant * coeff
+	for sym, v := range other.cells {
+		r.insertSymbol(sym, v*coeff)
+	}
+}
+
+func (r *row) remove(sym symbol) {
+	delete(r.cells, sym)
+}
+
+func (r *row) reverseSign() {
+	r.constant = -r.constant
+	for sym, v := range r.cells {
+		r.cells[sym] = -v
+	}
+}
+
+// solveFor rewrites the row, an expression equal to zero, as sym equal to
+// the rest.
+func (r *row) solveFor(sym symbol) {
+	coeff := -1 / r.cells[sym]
+	delete(r.cells, sym)
+	r.constant *= coeff
+	for s, v := range r.cells {
+		r.cells[s] = v * coeff
+	}
+}
+
+// solveForPair rewrites the row, lhs equal to the rest, as rhs equal to the
+// rest.
+func (r *row) solveForPair(lhs, rhs symbol) {
+	r.insertSymbol(lhs, -1)
+	r.solveFor(rhs)
+}
+
+func (r *row) substitute(sym symbol, other *row) {
+	if c, ok := r.cells[sym]; ok {
+		delete(r.cells, sym)
+		r.insertRow(other, c)
+	}
+}
+
+func (r *row) allDummies() bool {
+	for sym := range r.cells {
+		if sym.kind != dummySymbol {
+			return false
+		}
+	}
+	return true
+}
+
+// enteringSymbol returns the objective symbol with the lowest id whose
+// coefficient is negative, so that increasing it improves the objective.
+func (r *row) enteringSymbol() symbol {
+	var entering symbol
+	for sym, c := range r.cells {
+		if sym.kind != dummySymbol && c < 0 && (entering.kind == invalidSymbol || sym.id < entering.id) {
+			entering = sym
+		}
+	}
+	return entering
+}
+
+func (r *row) anyPivotableSymbol() symbol {
+	var pivot symbol
+	for sym := range r.cells {
+		if (sym.kind == slackSymbol || sym.kind == errorSymbol) && (pivot.kind == invalidSymbol || sym.id < pivot.id) {
+			pivot = sym
+		}
+	}
+	return pivot
+}
+
+func nearZero(v float64) bool {
+	return math.Abs(v) < 1e-8
+}
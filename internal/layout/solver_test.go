@@ -0,0 +1,308 @@
+package layout
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// expr returns the expression constant plus the sum of terms.
+func expr(constant float64, terms ...Term) Expression {
+	return Expression{Terms: terms, Constant: constant}
+}
+
+// near reports whether a and b are equal up to rounding.
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func TestSolverSolves(t *testing.T) {
+	tests := []struct {
+		name string
+		// rules returns the constraints over x and y.
+		rules func(x, y *Variable) []*Constraint
+		x, y  float64
+	}{
+		{"equalities", func(x, y *Variable) []*Constraint {
+			return []*Constraint{
+				NewConstraint(expr(-10, Term{x, 1}), EQ, Required),
+				NewConstraint(expr(-5, Term{y, 1}, Term{x, -1}), EQ, Required),
+			}
+		}, 10, 15},
+		{"required bound", func(x, y *Variable) []*Constraint {
+			return []*Constraint{
+				NewConstraint(expr(-100, Term{x, 1}), GE, Required),
+				NewConstraint(expr(-50, Term{x, 1}), EQ, Weak),
+				NewConstraint(expr(-300, Term{y, 1}), LE, Required),
+				NewConstraint(expr(-400, Term{y, 1}), EQ, Strong),
+			}
+		}, 100, 300},
+		{"stronger wins", func(x, y *Variable) []*Constraint {
+			return []*Constraint{
+				NewConstraint(expr(-20, Term{x, 1}), EQ, Weak),
+				NewConstraint(expr(-20, Term{x, 1}), GE, Weak),
+				NewConstraint(expr(-20, Term{x, 1}), GE, Weak),
+				NewConstraint(expr(-10, Term{x, 1}), EQ, Medium),
+				NewConstraint(expr(0, Term{y, 1}, Term{x, -2}), EQ, Required),
+			}
+		}, 10, 20},
+		{"inequality holds", func(x, y *Variable) []*Constraint {
+			// x + y == 100, x >= 2y, y as large as possible.
+			return []*Constraint{
+				NewConstraint(expr(-100, Term{x, 1}, Term{y, 1}), EQ, Required),
+				NewConstraint(expr(0, Term{x, 1}, Term{y, -2}), GE, Required),
+				NewConstraint(expr(-100, Term{y, 1}), EQ, Weak),
+			}
+		}, 200.0 / 3, 100.0 / 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var x, y Variable
+			s := NewSolver()
+			for i, c := range tt.rules(&x, &y) {
+				if err := s.AddConstraint(c); err != nil {
+					t.Fatalf("constraint %d: %v", i, err)
+				}
+			}
+			s.UpdateVariables()
+			if !near(x.Value, tt.x) || !near(y.Value, tt.y) {
+				t.Errorf("x, y = %v, %v, want %v, %v", x.Value, y.Value, tt.x, tt.y)
+			}
+		})
+	}
+}
+
+func TestSolverUnsatisfiable(t *testing.T) {
+	tests := []struct {
+		name  string
+		added func(x *Variable) []*Constraint
+		bad   func(x *Variable) *Constraint
+	}{
+		{"conflicting equality", func(x *Variable) []*Constraint {
+			return []*Constraint{NewConstraint(expr(-10, Term{x, 1}), EQ, Required)}
+		}, func(x *Variable) *Constraint {
+			return NewConstraint(expr(-20, Term{x, 1}), EQ, Required)
+		}},
+		{"empty range", func(x *Variable) []*Constraint {
+			return []*Constraint{
+				NewConstraint(expr(-10, Term{x, 1}), GE, Required),
+				NewConstraint(expr(-10, Term{x, 1}), EQ, Weak),
+			}
+		}, func(x *Variable) *Constraint {
+			return NewConstraint(expr(-5, Term{x, 1}), LE, Required)
+		}},
+		{"false constant", func(*Variable) []*Constraint { return nil }, func(*Variable) *Constraint {
+			return NewConstraint(expr(5), EQ, Required)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var x Variable
+			s := NewSolver()
+			for _, c := range tt.added(&x) {
+				s.AddConstraint(c)
+			}
+			s.UpdateVariables()
+			want := x.Value
+			bad := tt.bad(&x)
+			if err := s.AddConstraint(bad); !errors.Is(err, ErrUnsatisfiable) {
+				t.Fatalf("AddConstraint = %v, want ErrUnsatisfiable", err)
+			}
+			if s.HasConstraint(bad) {
+				t.Error("the unsatisfiable constraint was added")
+			}
+			// The solver still holds the other constraints.
+			s.UpdateVariables()
+			if !near(x.Value, want) {
+				t.Errorf("x = %v, want %v", x.Value, want)
+			}
+			c := NewConstraint(expr(-10, Term{&x, 0}), GE, Required)
+			if err := s.AddConstraint(c); !errors.Is(err, ErrUnsatisfiable) {
+				t.Errorf("0x >= 10 added with %v", err)
+			}
+		})
+	}
+}
+
+func TestSolverConstraints(t *testing.T) {
+	var x Variable
+	s := NewSolver()
+	strong := NewConstraint(expr(-10, Term{&x, 1}), EQ, Strong)
+	weak := NewConstraint(expr(-20, Term{&x, 1}), EQ, Weak)
+	trivial := NewConstraint(expr(0), EQ, Required)
+	for _, c := range []*Constraint{strong, weak, trivial} {
+		if err := s.AddConstraint(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.AddConstraint(strong); !errors.Is(err, ErrDuplicateConstraint) {
+		t.Errorf("adding twice = %v", err)
+	}
+	s.UpdateVariables()
+	if x.Value != 10 {
+		t.Errorf("x = %v, want 10", x.Value)
+	}
+
+	tests := []struct {
+		name   string
+		remove *Constraint
+		err    error
+		x      float64
+	}{
+		{"strong", strong, nil, 20},
+		{"again", strong, ErrUnknownConstraint, 20},
+		{"trivial", trivial, nil, 20},
+		{"weak", weak, nil, 0},
+	}
+	for _, tt := range tests {
+		if err := s.RemoveConstraint(tt.remove); !errors.Is(err, tt.err) {
+			t.Errorf("%s: RemoveConstraint = %v, want %v", tt.name, err, tt.err)
+		}
+		s.UpdateVariables()
+		if x.Value != tt.x || s.HasConstraint(tt.remove) {
+			t.Errorf("%s: x = %v, want %v", tt.name, x.Value, tt.x)
+		}
+	}
+
+	if got := NewConstraint(expr(0), EQ, 2*Required).Strength; got != Required {
+		t.Errorf("the strength is clamped to %v", got)
+	}
+	if got := NewConstraint(expr(0), EQ, -1).Strength; got != 0 {
+		t.Errorf("a negative strength is clamped to %v", got)
+	}
+}
+
+func TestSolverEdits(t *testing.T) {
+	// A box from left to right of width w, with left >= 0 and right <= 100.
+	var left, width, right Variable
+	s := NewSolver()
+	for _, c := range []*Constraint{
+		NewConstraint(expr(0, Term{&right, 1}, Term{&left, -1}, Term{&width, -1}), EQ, Required),
+		NewConstraint(expr(0, Term{&left, 1}), GE, Required),
+		NewConstraint(expr(-100, Term{&right, 1}), LE, Required),
+		NewConstraint(expr(0, Term{&left, 1}), EQ, Weak),
+	} {
+		if err := s.AddConstraint(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.AddEditVariable(&width, Strong); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddEditVariable(&left, Medium); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name                string
+		width, left         float64
+		wantLeft, wantWidth float64
+	}{
+		{"fits", 40, 10, 10, 40},
+		{"pushed left", 80, 50, 20, 80},
+		{"too wide", 150, 0, 0, 100},
+		{"negative left", 30, -20, 0, 30},
+		{"back", 40, 10, 10, 40},
+	}
+	for _, tt := range tests {
+		if err := s.SuggestValue(&width, tt.width); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if err := s.SuggestValue(&left, tt.left); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		s.UpdateVariables()
+		if !near(left.Value, tt.wantLeft) || !near(width.Value, tt.wantWidth) || !near(right.Value, tt.wantLeft+tt.wantWidth) {
+			t.Errorf("%s: left %v width %v right %v, want %v and %v", tt.name, left.Value, width.Value, right.Value, tt.wantLeft, tt.wantWidth)
+		}
+	}
+
+	errs := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"duplicate", s.AddEditVariable(&width, Weak), ErrDuplicateEdit},
+		{"required", s.AddEditVariable(&right, Required), ErrRequiredEdit},
+		{"suggest unknown", s.SuggestValue(&right, 1), ErrUnknownEdit},
+		{"remove unknown", s.RemoveEditVariable(&right), ErrUnknownEdit},
+		{"remove", s.RemoveEditVariable(&left), nil},
+		{"suggest removed", s.SuggestValue(&left, 1), ErrUnknownEdit},
+	}
+	for _, tt := range errs {
+		if !errors.Is(tt.err, tt.want) {
+			t.Errorf("%s: %v, want %v", tt.name, tt.err, tt.want)
+		}
+	}
+	if !s.HasEditVariable(&width) || s.HasEditVariable(&left) || s.HasEditVariable(&right) {
+		t.Error("HasEditVariable does not match the edit variables")
+	}
+	s.UpdateVariables()
+	if left.Value != 0 {
+		t.Errorf("without its edit left = %v, want the weak 0", left.Value)
+	}
+}
+
+func TestSolverIncremental(t *testing.T) {
+	// Suggesting values one after another gives the solution of a solver
+	// that only saw the last ones.
+	build := func() (*Solver, []*Variable) {
+		vars := make([]*Variable, 4)
+		for i := range vars {
+			vars[i] = &Variable{}
+		}
+		s := NewSolver()
+		for i := 1; i < len(vars); i++ {
+			// Each variable is at least 10 past the previous one.
+			s.AddConstraint(NewConstraint(expr(-10, Term{vars[i], 1}, Term{vars[i-1], -1}), GE, Required))
+			s.AddConstraint(NewConstraint(expr(0, Term{vars[i], 1}), EQ, Weak))
+		}
+		s.AddEditVariable(vars[0], Strong)
+		s.AddEditVariable(vars[3], Medium)
+		return s, vars
+	}
+	s, vars := build()
+	for _, v := range []float64{5, 100, -30, 60, 0, 25} {
+		s.SuggestValue(vars[0], v)
+		s.SuggestValue(vars[3], 200-v)
+	}
+	fresh, want := build()
+	fresh.SuggestValue(want[0], 25)
+	fresh.SuggestValue(want[3], 175)
+	s.UpdateVariables()
+	fresh.UpdateVariables()
+	for i := range vars {
+		if !near(vars[i].Value, want[i].Value) {
+			t.Errorf("variable %d = %v, want %v", i, vars[i].Value, want[i].Value)
+		}
+	}
+	if vars[0].Value != 25 || vars[3].Value != 175 {
+		t.Errorf("the edits hold %v and %v", vars[0].Value, vars[3].Value)
+	}
+}
+
+func TestSolverUnsatisfiableKeepsEdits(t *testing.T) {
+	var x, y Variable
+	s := NewSolver()
+	s.AddConstraint(NewConstraint(expr(0, Term{&y, 1}, Term{&x, -1}), GE, Required))
+	s.AddEditVariable(&x, Strong)
+	s.SuggestValue(&x, 30)
+	if err := s.AddConstraint(NewConstraint(expr(-10, Term{&y, 1}, Term{&x, -1}), LE, Required)); err != nil {
+		t.Fatal(err)
+	}
+	bad := NewConstraint(expr(-20, Term{&y, 1}, Term{&x, -1}), GE, Required)
+	if err := s.AddConstraint(bad); !errors.Is(err, ErrUnsatisfiable) {
+		t.Fatalf("AddConstraint = %v, want ErrUnsatisfiable", err)
+	}
+	s.UpdateVariables()
+	if x.Value != 30 {
+		t.Errorf("x = %v, want the suggested 30", x.Value)
+	}
+	if err := s.SuggestValue(&x, 50); err != nil {
+		t.Fatal(err)
+	}
+	s.UpdateVariables()
+	if x.Value != 50 || y.Value < 50 || y.Value > 60 {
+		t.Errorf("x, y = %v, %v, want 50 and y within 10 past it", x.Value, y.Value)
+	}
+}
@@ -0,0 +1,270 @@
+// Package lottie parses Lottie animations, the Bodymovin JSON exported
+// from After Effects, and renders their frames into filled and stroked
+// paths.
+//
+// It covers the vector subset: shape, solid, null and precomposition
+// layers, with parenting, transforms and opacity; groups, paths,
+// rectangles, ellipses and stars; solid and gradient fills and strokes;
+// and trim paths, with linear, eased, hold and motion path keyframes.
+// Masks, mattes, effects, expressions, skew, time remapping, images and
+// text are ignored, and the opacity of a layer or group applies to each
+// of its shapes rather than to them together.
+package lottie
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gogpu/ui/core"
+)
+
+// Shape is a path with the style it is drawn with.
+type Shape struct {
+	Path  *core.Path
+	Style core.PathStyle
+}
+
+// Marker is a named segment of an animation, in frames.
+type Marker struct {
+	Name            string
+	Start, Duration float32
+}
+
+// Animation is a parsed Lottie animation. Frames are numbered from In to
+// Out, which it ends before, at FrameRate frames a second.
+type Animation struct {
+	Width, Height float32
+	FrameRate     float32
+	In, Out       float32
+	Markers       []Marker
+
+	layers []*layer
+	assets map[string][]*layer
+}
+
+// Parse parses a Lottie animation.
+func Parse(data []byte) (*Animation, error) {
+	var doc struct {
+		FR     float32  `json:"fr"`
+		IP     float32  `json:"ip"`
+		OP     float32  `json:"op"`
+		W      float32  `json:"w"`
+		H      float32  `json:"h"`
+		Layers []*layer `json:"layers"`
+		Assets []struct {
+			ID     string   `json:"id"`
+			Layers []*layer `json:"layers"`
+		} `json:"assets"`
+		Markers []struct {
+			CM string  `json:"cm"`
+			TM float32 `json:"tm"`
+			DR float32 `json:"dr"`
+		} `json:"markers"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc.W <= 0 || doc.H <= 0 || doc.FR <= 0 || doc.OP <= doc.IP {
+		return nil, errors.New("lottie: not an animation")
+	}
+	a := &Animation{
+		Width: doc.W, Height: doc.H,
+		FrameRate: doc.FR,
+		In:        doc.IP, Out: doc.OP,
+		layers: compact(doc.Layers),
+		assets: make(map[string][]*layer),
+	}
+	for _, as := range doc.Assets {
+		if as.Layers != nil {
+			a.assets[as.ID] = compact(as.Layers)
+		}
+	}
+	for _, m := range doc.Markers {
+		a.Markers = append(a.Markers, Marker{Name: m.CM, Start: m.TM, Duration: m.DR})
+	}
+	return a, nil
+}
+
+// Render returns the shapes of frame f, mapped from the animation's
+// size onto a rectangle of the given size at the origin, stretching them
+// if the aspect differs. They are in the order they are drawn in.
+func (a *Animation) Render(f float32, size core.Size) []Shape {
+	r := &renderer{assets: a.assets}
+	r.layers(a.layers, f, scale(size.Width/a.Width, size.Height/a.Height), 1, 0)
+	return r.out
+}
+
+// layer is a layer of the animation or of a precomposition.
+type layer struct {
+	Type    int       `json:"ty"`
+	Index   *int      `json:"ind"`
+	Parent  *int      `json:"parent"`
+	In      float32   `json:"ip"`
+	Out     float32   `json:"op"`
+	Start   float32   `json:"st"`
+	Stretch float32   `json:"sr"`
+	Hidden  bool      `json:"hd"`
+	Matte   int       `json:"td"` // Set on layers that are the matte of the next.
+	Ref     string    `json:"refId"`
+	Xform   transform `json:"ks"`
+	Shapes  []*item   `json:"shapes"`
+
+	Solid  string  `json:"sc"`
+	SolidW float32 `json:"sw"`
+	SolidH float32 `json:"sh"`
+}
+
+// compact drops the null layers of ls and the null items of their
+// shapes, which a malformed file may list.
+func compact(ls []*layer) []*layer {
+	ls = slices.DeleteFunc(ls, func(l *layer) bool { return l == nil })
+	for _, l := range ls {
+		l.Shapes = compactItems(l.Shapes)
+	}
+	return ls
+}
+
+// Layer types.
+const (
+	layerPrecomp = 0
+	layerSolid   = 1
+	layerNull    = 3
+	layerShape   = 4
+)
+
+// local returns the frame of the layer's own time at frame f of the
+// composition it is in.
+func (l *layer) local(f float32) float32 {
+	if l.Stretch > 0 {
+		return (f - l.Start) / l.Stretch
+	}
+	return f - l.Start
+}
+
+type transform struct {
+	Anchor   prop `json:"a"`
+	Position prop `json:"p"`
+	Scale    prop `json:"s"`
+	Rotation prop `json:"r"`
+	Opacity  prop `json:"o"`
+}
+
+// matrix returns the transform at frame f: scaled and rotated about the
+// anchor, which is then moved to the position.
+func (t *transform) matrix(f float32) matrix {
+	a := t.Anchor.point(f, [2]float32{})
+	p := t.Position.point(f, [2]float32{})
+	s := t.Scale.point(f, [2]float32{100, 100})
+	sin, cos := math.Sincos(radians(t.Rotation.scalar(f, 0)))
+	rot := matrix{float32(cos), float32(sin), float32(-sin), float32(cos), 0, 0}
+	return translate(p[0], p[1]).mul(rot).mul(scale(s[0]/100, s[1]/100)).mul(translate(-a[0], -a[1]))
+}
+
+func (t *transform) opacity(f float32) float32 {
+	return min(max(t.Opacity.scalar(f, 100)/100, 0), 1)
+}
+
+// maxDepth bounds the nesting of precompositions, which a malformed file
+// could make cyclic.
+const maxDepth = 16
+
+type renderer struct {
+	assets map[string][]*layer
+	out    []Shape
+}
+
+// layers renders the layers of a composition at its frame f, which are
+// listed from the top down.
+func (r *renderer) layers(ls []*layer, f float32, m matrix, opacity float32, depth int) {
+	for i, l := range slices.Backward(ls) {
+		// The layer under a matte is drawn without it, but the matte is
+		// not drawn.
+		if l.Hidden || l.Matte != 0 || f < l.In || f >= l.Out {
+			continue
+		}
+		local := l.local(f)
+		lm := m.mul(r.world(ls, i, f, 0))
+		o := opacity * l.Xform.opacity(local)
+		if o <= 0 {
+			continue
+		}
+		switch l.Type {
+		case layerShape:
+			g := r.group(l.Shapes, local, lm, o)
+			for _, s := range slices.Backward(g.shapes) {
+				r.out = append(r.out, s)
+			}
+		case layerSolid:
+			c := hexColor(l.Solid)
+			p := core.NewPath().AddRect(core.R(0, 0, l.SolidW, l.SolidH)).Transform(lm.apply)
+			r.out = append(r.out, Shape{Path: p, Style: core.PathStyle{Fill: c.WithAlpha(c.A * o)}})
+		case layerPrecomp:
+			if sub := r.assets[l.Ref]; sub != nil && depth < maxDepth {
+				r.layers(sub, local, lm, o, depth+1)
+			}
+		}
+	}
+}
+
+// world returns the transform of the i-th layer of ls at frame f of their
+// composition, through those of its parents.
+func (r *renderer) world(ls []*layer, i int, f float32, depth int) matrix {
+	l := ls[i]
+	m := l.Xform.matrix(l.local(f))
+	if l.Parent == nil || depth >= maxDepth {
+		return m
+	}
+	for j, p := range ls {
+		if p.Index != nil && *p.Index == *l.Parent {
+			return r.world(ls, j, f, depth+1).mul(m)
+		}
+	}
+	return m
+}
+
+// hexColor parses a color written as #rrggbb.
+func hexColor(s string) core.Color {
+	v, err := strconv.ParseUint(strings.TrimPrefix(s, "#"), 16, 32)
+	if err != nil {
+		return core.Black
+	}
+	return core.RGB(uint8(v>>16), uint8(v>>8), uint8(v))
+}
+
+// matrix is an affine transform [a b c d e f], mapping (x, y) to
+// (ax + cy + e, bx + dy + f).
+type matrix [6]float32
+
+func translate(x, y float32) matrix {
+	return matrix{1, 0, 0, 1, x, y}
+}
+
+func scale(x, y float32) matrix {
+	return matrix{x, 0, 0, y, 0, 0}
+}
+
+// mul returns the transform applying n, then m.
+func (m matrix) mul(n matrix) matrix {
+	return matrix{
+		m[0]*n[0] + m[2]*n[1],
+		m[1]*n[0] + m[3]*n[1],
+		m[0]*n[2] + m[2]*n[3],
+		m[1]*n[2] + m[3]*n[3],
+		m[0]*n[4] + m[2]*n[5] + m[4],
+		m[1]*n[4] + m[3]*n[5] + m[5],
+	}
+}
+
+func (m matrix) apply(p core.Point) core.Point {
+	return core.Pt(m[0]*p.X+m[2]*p.Y+m[4], m[1]*p.X+m[3]*p.Y+m[5])
+}
+
+// scale returns the factor by which m scales lengths on average, used for
+// stroke widths.
+func (m matrix) scale() float32 {
+	return float32(math.Sqrt(math.Abs(float64(m[0]*m[3] - m[1]*m[2]))))
+}
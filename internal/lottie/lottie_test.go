@@ -0,0 +1,145 @@
+package lottie
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+)
+
+// rect is a shape layer filling a red 20x40 rectangle centred at (50, 50).
+const rect = `{"ty":4,"ip":0,"op":60,"ks":{},"shapes":[` +
+	`{"ty":"rc","p":{"k":[50,50]},"s":{"k":[20,40]}},` +
+	`{"ty":"fl","c":{"k":[1,0,0,1]},"o":{"k":100}}]}`
+
+// doc returns a 100x100 animation of 60 frames at 30 a second with the
+// given layers, and more top-level fields if any.
+func doc(layers string, more ...string) []byte {
+	s := `{"fr":30,"ip":0,"op":60,"w":100,"h":100,"layers":[` + layers + `]`
+	for _, m := range more {
+		s += "," + m
+	}
+	return []byte(s + "}")
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   []byte
+		err    bool
+		shapes int
+	}{
+		{"not JSON", []byte("{"), true, 0},
+		{"no size", []byte(`{"fr":30,"ip":0,"op":60,"layers":[]}`), true, 0},
+		{"no frames", []byte(`{"fr":30,"ip":10,"op":10,"w":100,"h":100}`), true, 0},
+		{"no frame rate", []byte(`{"ip":0,"op":60,"w":100,"h":100}`), true, 0},
+		{"empty", doc(""), false, 0},
+		{"a layer", doc(rect), false, 1},
+		{"null layers", doc("null," + rect + ",null"), false, 1},
+		{"null shapes", doc(`{"ty":4,"ip":0,"op":60,"shapes":[null,` +
+			`{"ty":"rc","s":{"k":[10,10]}},null,{"ty":"fl","c":{"k":[0,0,0,1]}},null]}`), false, 1},
+		{"null group items", doc(`{"ty":4,"ip":0,"op":60,"shapes":[{"ty":"gr","it":[null,` +
+			`{"ty":"el","s":{"k":[10,10]}},null,{"ty":"st","c":{"k":[0,0,0,1]}},null]}]}`), false, 1},
+		{"null asset layers", doc(`{"ty":0,"ip":0,"op":60,"refId":"a"}`,
+			`"assets":[{"id":"a","layers":[null,`+rect+`,null]}]`), false, 1},
+		{"negative gradient stops", doc(`{"ty":4,"ip":0,"op":60,"shapes":[` +
+			`{"ty":"rc","s":{"k":[10,10]}},{"ty":"gf","g":{"p":-1,"k":{"k":[0,1,0,0]}}}]}`), false, 1},
+		{"more gradient stops than values", doc(`{"ty":4,"ip":0,"op":60,"shapes":[` +
+			`{"ty":"rc","s":{"k":[10,10]}},{"ty":"gs","g":{"p":5,"k":{"k":[0,1,0,0]}}}]}`), false, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := Parse(tt.data)
+			if (err != nil) != tt.err {
+				t.Fatalf("Parse() error = %v, want error %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if got := a.Render(0, core.Sz(100, 100)); len(got) != tt.shapes {
+				t.Errorf("%d shapes, want %d", len(got), tt.shapes)
+			}
+		})
+	}
+}
+
+func TestParseMarkers(t *testing.T) {
+	a, err := Parse(doc("", `"markers":[{"cm":"intro","tm":0,"dr":20},{"cm":"loop","tm":20,"dr":40}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Marker{{"intro", 0, 20}, {"loop", 20, 40}}
+	if len(a.Markers) != len(want) || a.Markers[0] != want[0] || a.Markers[1] != want[1] {
+		t.Errorf("markers %v, want %v", a.Markers, want)
+	}
+	if a.Width != 100 || a.Height != 100 || a.FrameRate != 30 || a.In != 0 || a.Out != 60 {
+		t.Errorf("animation %vx%v, %v fps from %v to %v", a.Width, a.Height, a.FrameRate, a.In, a.Out)
+	}
+}
+
+func TestRender(t *testing.T) {
+	red := core.Color{R: 1, A: 1}
+	tests := []struct {
+		name   string
+		layers string
+		frame  float32
+		size   core.Size
+		bounds []core.Rect
+		fill   core.Color
+	}{
+		{"shape", rect, 0, core.Sz(100, 100), []core.Rect{core.R(40, 30, 20, 40)}, red},
+		{"stretched", rect, 0, core.Sz(200, 100), []core.Rect{core.R(80, 30, 40, 40)}, red},
+		{"before the layer", strings.Replace(rect, `"ip":0`, `"ip":10`, 1), 5, core.Sz(100, 100), nil, red},
+		{"after the layer", strings.Replace(rect, `"op":60`, `"op":10`, 1), 10, core.Sz(100, 100), nil, red},
+		{"hidden", strings.Replace(rect, `"ty":4`, `"ty":4,"hd":true`, 1), 0, core.Sz(100, 100), nil, red},
+		{"matte", strings.Replace(rect, `"ty":4`, `"ty":4,"td":1`, 1), 0, core.Sz(100, 100), nil, red},
+		{"layer opacity", strings.Replace(rect, `"ks":{}`, `"ks":{"o":{"k":50}}`, 1), 0, core.Sz(100, 100),
+			[]core.Rect{core.R(40, 30, 20, 40)}, red.WithAlpha(0.5)},
+		{"moved", strings.Replace(rect, `"ks":{}`, `"ks":{"p":{"k":[10,0]}}`, 1), 0, core.Sz(100, 100),
+			[]core.Rect{core.R(50, 30, 20, 40)}, red},
+		{"parent", `{"ty":3,"ind":1,"ip":0,"op":60,"ks":{"p":{"k":[0,10]}}},` +
+			strings.Replace(rect, `"ks":{}`, `"parent":1,"ks":{}`, 1), 0, core.Sz(100, 100),
+			[]core.Rect{core.R(40, 40, 20, 40)}, red},
+		{"solid", `{"ty":1,"ip":0,"op":60,"sc":"#ff0000","sw":30,"sh":20}`, 0, core.Sz(100, 100),
+			[]core.Rect{core.R(0, 0, 30, 20)}, red},
+		{"from the top down", strings.Replace(rect, "1,0,0,1", "0,0,1,1", 1) + "," + rect, 0, core.Sz(100, 100),
+			[]core.Rect{core.R(40, 30, 20, 40), core.R(40, 30, 20, 40)}, red},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := Parse(doc(tt.layers))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := a.Render(tt.frame, tt.size)
+			if len(got) != len(tt.bounds) {
+				t.Fatalf("%d shapes, want %d", len(got), len(tt.bounds))
+			}
+			for i, s := range got {
+				if b := s.Path.Bounds(); b != tt.bounds[i] {
+					t.Errorf("shape %d bounds %v, want %v", i, b, tt.bounds[i])
+				}
+			}
+			// The bottom layer is drawn first.
+			if len(got) > 0 && got[0].Style.Fill != tt.fill {
+				t.Errorf("fill %v, want %v", got[0].Style.Fill, tt.fill)
+			}
+		})
+	}
+}
+
+func TestRenderGradient(t *testing.T) {
+	a, err := Parse(doc(`{"ty":4,"ip":0,"op":60,"shapes":[{"ty":"rc","p":{"k":[50,50]},"s":{"k":[100,100]}},` +
+		`{"ty":"gf","t":1,"s":{"k":[0,0]},"e":{"k":[100,0]},"g":{"p":2,"k":{"k":[0,1,0,0,1,0,0,1,0,1,1,0]}}}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	shapes := a.Render(0, core.Sz(100, 100))
+	if len(shapes) != 1 || shapes[0].Style.FillGradient == nil {
+		t.Fatalf("shapes %v, want one filled with a gradient", shapes)
+	}
+	g := shapes[0].Style.FillGradient
+	if len(g.Stops) != 2 || g.Stops[0].Color != (core.Color{R: 1, A: 1}) || g.Stops[1].Color != (core.Color{B: 1}) {
+		t.Errorf("stops %v, want opaque red to transparent blue", g.Stops)
+	}
+}
@@ -0,0 +1,247 @@
+package lottie
+
+import (
+	"math"
+	"slices"
+
+	"github.com/gogpu/ui/core"
+)
+
+// item is an element of a shape layer or group. The properties share
+// keys across types, with meanings differing by type.
+type item struct {
+	Type   string  `json:"ty"`
+	Hidden bool    `json:"hd"`
+	Items  []*item `json:"it"` // Of groups.
+
+	Path  prop `json:"ks"` // Of paths.
+	A     prop `json:"a"`  // The anchor of transforms.
+	P     prop `json:"p"`  // The position of transforms and shapes.
+	S     prop `json:"s"`  // The size of shapes, scale of transforms, start of gradients and trims.
+	E     prop `json:"e"`  // The end of gradients and trims.
+	R     prop `json:"r"`  // The roundness of rectangles, rotation of transforms and stars, fill rule of fills.
+	O     prop `json:"o"`  // The opacity of paints and transforms, offset of trims.
+	C     prop `json:"c"`  // The color of paints.
+	W     prop `json:"w"`  // The width of strokes.
+	Cap   int  `json:"lc"` // 1 butt, 2 round, 3 square.
+	Join  int  `json:"lj"` // 1 miter, 2 round, 3 bevel.
+	Kind  int  `json:"t"`  // Of gradients: 1 linear, 2 radial.
+	Stops struct {
+		Count int  `json:"p"`
+		K     prop `json:"k"`
+	} `json:"g"`
+
+	Star        int  `json:"sy"` // 1 a star, 2 a polygon.
+	Points      prop `json:"pt"`
+	InnerRadius prop `json:"ir"`
+	OuterRadius prop `json:"or"`
+
+	Mode int `json:"m"` // Of trims: 1 trimming each path, 2 all as one.
+}
+
+// compactItems drops the null items of items and of their groups.
+func compactItems(items []*item) []*item {
+	items = slices.DeleteFunc(items, func(it *item) bool { return it == nil })
+	for _, it := range items {
+		it.Items = compactItems(it.Items)
+	}
+	return items
+}
+
+// group is what rendering a group made: the paths of its shapes, which
+// paints after it in its parent also draw, and the shapes it drew, from
+// the top down.
+type group struct {
+	paths  []*core.Path
+	shapes []Shape
+}
+
+// group renders a group's items at frame f. Paints draw the paths of the
+// shapes before them, and of the groups before them, and are drawn under
+// the shapes of those groups.
+func (r *renderer) group(items []*item, f float32, m matrix, opacity float32) group {
+	for _, it := range items {
+		if it.Type == "tr" {
+			t := transform{Anchor: it.A, Position: it.P, Scale: it.S, Rotation: it.R, Opacity: it.O}
+			m = m.mul(t.matrix(f))
+			opacity *= t.opacity(f)
+			break
+		}
+	}
+	var g group
+	for _, it := range items {
+		if it.Hidden {
+			continue
+		}
+		switch it.Type {
+		case "gr":
+			sub := r.group(it.Items, f, m, opacity)
+			g.paths = append(g.paths, sub.paths...)
+			g.shapes = append(g.shapes, sub.shapes...)
+		case "sh", "rc", "el", "sr":
+			if p := it.geometry(f); p != nil {
+				g.paths = append(g.paths, p.Transform(m.apply))
+			}
+		case "fl", "st", "gf", "gs":
+			if len(g.paths) > 0 && opacity > 0 {
+				g.shapes = append(g.shapes, Shape{Path: merge(g.paths), Style: it.paint(f, m, opacity)})
+			}
+		case "tm":
+			g.paths = it.trim(f, g.paths)
+		}
+	}
+	return g
+}
+
+// geometry returns the path of a shape at frame f.
+func (it *item) geometry(f float32) *core.Path {
+	p := core.NewPath()
+	switch it.Type {
+	case "sh":
+		v := it.Path.at(f)
+		n := len(v) / 6
+		if n == 0 {
+			return nil
+		}
+		// The tangents are relative to their vertex.
+		vertex := func(i int) core.Point {
+			return core.Pt(v[6*i], v[6*i+1])
+		}
+		in := func(i int) core.Point {
+			return core.Pt(v[6*i]+v[6*i+2], v[6*i+1]+v[6*i+3])
+		}
+		out := func(i int) core.Point {
+			return core.Pt(v[6*i]+v[6*i+4], v[6*i+1]+v[6*i+5])
+		}
+		p.MoveTo(vertex(0))
+		for i := 1; i < n; i++ {
+			p.CubicTo(out(i-1), in(i), vertex(i))
+		}
+		if it.Path.closed {
+			p.CubicTo(out(n-1), in(0), vertex(0)).Close()
+		}
+	case "rc", "el":
+		c, s := it.P.point(f, [2]float32{}), it.S.point(f, [2]float32{})
+		rect := core.R(c[0]-s[0]/2, c[1]-s[1]/2, s[0], s[1])
+		if it.Type == "el" {
+			p.AddEllipse(rect)
+		} else if rad := min(it.R.scalar(f, 0), s[0]/2, s[1]/2); rad > 0 {
+			p.AddRoundedRect(rect, rad)
+		} else {
+			p.AddRect(rect)
+		}
+	case "sr":
+		c := it.P.point(f, [2]float32{})
+		n := int(math.Round(float64(it.Points.scalar(f, 5))))
+		if n < 2 {
+			return nil
+		}
+		outer, inner := it.OuterRadius.scalar(f, 0), it.InnerRadius.scalar(f, 0)
+		verts, step := n, 2*math.Pi/float64(n)
+		if it.Star != 2 {
+			verts, step = 2*n, math.Pi/float64(n)
+		}
+		start := radians(it.R.scalar(f, 0)) - math.Pi/2
+		for i := range verts {
+			rad := outer
+			if it.Star != 2 && i%2 == 1 {
+				rad = inner
+			}
+			sin, cos := math.Sincos(start + float64(i)*step)
+			pt := core.Pt(c[0]+rad*float32(cos), c[1]+rad*float32(sin))
+			if i == 0 {
+				p.MoveTo(pt)
+			} else {
+				p.LineTo(pt)
+			}
+		}
+		p.Close()
+	}
+	return p
+}
+
+// paint returns the style a paint draws with at frame f.
+func (it *item) paint(f float32, m matrix, opacity float32) core.PathStyle {
+	alpha := opacity * min(max(it.O.scalar(f, 100)/100, 0), 1)
+	var st core.PathStyle
+	var color core.Color
+	var grad *core.Gradient
+	if it.Type == "fl" || it.Type == "st" {
+		c := it.C.at(f)
+		color = core.Color{R: at(c, 0), G: at(c, 1), B: at(c, 2), A: alpha}
+		if len(c) > 3 {
+			color.A *= c[3]
+		}
+	} else {
+		grad = it.gradient(f, m, alpha)
+		color = grad.Average()
+	}
+	if it.Type == "fl" || it.Type == "gf" {
+		st.Fill, st.FillGradient = color, grad
+		if it.R.scalar(f, 1) == 2 {
+			st.FillRule = core.FillEvenOdd
+		}
+		return st
+	}
+	st.Stroke, st.StrokeGradient = color, grad
+	st.StrokeWidth = it.W.scalar(f, 1) * m.scale()
+	st.LineCap = core.LineCap(min(max(it.Cap-1, 0), 2))
+	st.LineJoin = core.LineJoin(min(max(it.Join-1, 0), 2))
+	return st
+}
+
+// gradient returns the gradient of a gradient paint at frame f. Its
+// stops are offsets followed by colors, then optionally offsets followed
+// by alphas.
+func (it *item) gradient(f float32, m matrix, alpha float32) *core.Gradient {
+	v := it.Stops.K.at(f)
+	n := max(min(it.Stops.Count, len(v)/4), 0)
+	alphas := v[4*n:]
+	stops := make([]core.GradientStop, n)
+	for i := range n {
+		o := v[4*i]
+		c := core.Color{R: v[4*i+1], G: v[4*i+2], B: v[4*i+3], A: alpha * stopAlpha(alphas, o)}
+		stops[i] = core.Stop(o, c)
+	}
+	s, e := it.S.point(f, [2]float32{}), it.E.point(f, [2]float32{})
+	start, end := m.apply(core.Pt(s[0], s[1])), m.apply(core.Pt(e[0], e[1]))
+	if it.Kind == 2 {
+		return core.RadialGradient(start, float32(math.Hypot(float64(end.X-start.X), float64(end.Y-start.Y))), stops...)
+	}
+	return core.LinearGradient(start, end, stops...)
+}
+
+// stopAlpha returns the alpha at offset o of a gradient's alpha stops,
+// pairs of an offset and an alpha, or 1 if it has none.
+func stopAlpha(v []float32, o float32) float32 {
+	n := len(v) / 2
+	if n == 0 {
+		return 1
+	}
+	if o <= v[0] {
+		return v[1]
+	}
+	for i := 1; i < n; i++ {
+		if o <= v[2*i] {
+			a, b := v[2*i-2], v[2*i]
+			if b <= a {
+				return v[2*i+1]
+			}
+			t := (o - a) / (b - a)
+			return v[2*i-1] + (v[2*i+1]-v[2*i-1])*t
+		}
+	}
+	return v[2*n-1]
+}
+
+// merge returns the paths as one, filled together.
+func merge(paths []*core.Path) *core.Path {
+	if len(paths) == 1 {
+		return paths[0]
+	}
+	p := core.NewPath()
+	for _, q := range paths {
+		p.Segments = append(p.Segments, q.Segments...)
+	}
+	return p
+}
@@ -0,0 +1,195 @@
+package lottie
+
+import (
+	"math"
+
+	"github.com/gogpu/ui/core"
+)
+
+// trim returns the parts of paths a trim keeps at frame f: the stretch
+// from its start to its end percentage of their length, moved along by
+// its offset in degrees of a full length. The paths are flattened into
+// lines when trimmed.
+func (it *item) trim(f float32, paths []*core.Path) []*core.Path {
+	s := min(max(it.S.scalar(f, 0)/100, 0), 1)
+	e := min(max(it.E.scalar(f, 100)/100, 0), 1)
+	if s > e {
+		s, e = e, s
+	}
+	if e-s >= 1 {
+		return paths
+	}
+	out := make([]*core.Path, 0, len(paths))
+	if e-s <= 0 {
+		return out
+	}
+	length := e - s
+	s += it.O.scalar(f, 0) / 360
+	s -= float32(math.Floor(float64(s)))
+	e = s + length
+	ranges := [][2]float32{{s, min(e, 1)}}
+	if e > 1 {
+		ranges = append(ranges, [2]float32{0, e - 1})
+	}
+	lines := make([][]polyline, len(paths))
+	lengths := make([]float32, len(paths))
+	var total float32
+	for i, p := range paths {
+		lines[i] = flatten(p)
+		for _, pl := range lines[i] {
+			lengths[i] += pl.length()
+		}
+		total += lengths[i]
+	}
+	if it.Mode == 2 {
+		// The paths are trimmed as one, each keeping the part of the
+		// ranges that falls on it.
+		var at float32
+		for i := range paths {
+			p := core.NewPath()
+			for _, r := range ranges {
+				cut(p, lines[i], r[0]*total-at, r[1]*total-at)
+			}
+			at += lengths[i]
+			out = append(out, p)
+		}
+		return out
+	}
+	for i := range paths {
+		p := core.NewPath()
+		for _, r := range ranges {
+			cut(p, lines[i], r[0]*lengths[i], r[1]*lengths[i])
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+type polyline struct {
+	points []core.Point
+	closed bool
+}
+
+func (pl polyline) length() float32 {
+	var n float32
+	for i := 1; i < len(pl.points); i++ {
+		n += dist(pl.points[i-1], pl.points[i])
+	}
+	return n
+}
+
+func dist(a, b core.Point) float32 {
+	return float32(math.Hypot(float64(b.X-a.X), float64(b.Y-a.Y)))
+}
+
+// flatten returns the contours of p as lines, with curves divided into
+// lines of about 2 units.
+func flatten(p *core.Path) []polyline {
+	var out []polyline
+	var cur polyline
+	end := func() {
+		if len(cur.points) > 1 {
+			out = append(out, cur)
+		}
+		cur = polyline{}
+	}
+	last := func() core.Point {
+		if len(cur.points) == 0 {
+			return core.Point{}
+		}
+		return cur.points[len(cur.points)-1]
+	}
+	curve := func(pts []core.Point, at func(t float32) core.Point) {
+		var net float32
+		for i := 1; i < len(pts); i++ {
+			net += dist(pts[i-1], pts[i])
+		}
+		n := min(max(int(net/2), 1), 64)
+		for i := 1; i <= n; i++ {
+			cur.points = append(cur.points, at(float32(i)/float32(n)))
+		}
+	}
+	for _, s := range p.Segments {
+		pts := s.Points
+		switch s.Verb {
+		case core.MoveTo:
+			end()
+			cur.points = append(cur.points, pts[0])
+		case core.LineTo:
+			cur.points = append(cur.points, pts[0])
+		case core.QuadTo:
+			p0 := last()
+			curve([]core.Point{p0, pts[0], pts[1]}, func(t float32) core.Point {
+				q := 1 - t
+				return core.Pt(q*q*p0.X+2*q*t*pts[0].X+t*t*pts[1].X, q*q*p0.Y+2*q*t*pts[0].Y+t*t*pts[1].Y)
+			})
+		case core.CubicTo:
+			p0 := last()
+			curve([]core.Point{p0, pts[0], pts[1], pts[2]}, func(t float32) core.Point {
+				q := 1 - t
+				a, b, c, d := q*q*q, 3*q*q*t, 3*q*t*t, t*t*t
+				return core.Pt(a*p0.X+b*pts[0].X+c*pts[1].X+d*pts[2].X, a*p0.Y+b*pts[0].Y+c*pts[1].Y+d*pts[2].Y)
+			})
+		case core.Close:
+			if len(cur.points) > 0 {
+				first := cur.points[0]
+				if last() != first {
+					cur.points = append(cur.points, first)
+				}
+				cur.closed = true
+				start := first
+				end()
+				cur.points = append(cur.points, start)
+			}
+		}
+	}
+	end()
+	return out
+}
+
+// cut adds to p the stretch of the polylines from distance a to b along
+// them.
+func cut(p *core.Path, lines []polyline, a, b float32) {
+	if b <= a {
+		return
+	}
+	var at float32
+	for _, pl := range lines {
+		n := pl.length()
+		if at+n <= a || at >= b {
+			at += n
+			continue
+		}
+		if a <= at && b >= at+n {
+			// The whole contour, which stays closed.
+			p.MoveTo(pl.points[0])
+			for _, pt := range pl.points[1:] {
+				p.LineTo(pt)
+			}
+			if pl.closed {
+				p.Close()
+			}
+			at += n
+			continue
+		}
+		started := false
+		for i := 1; i < len(pl.points); i++ {
+			p0, p1 := pl.points[i-1], pl.points[i]
+			d := dist(p0, p1)
+			s, e := at, at+d
+			at = e
+			if e <= a || s >= b || d == 0 {
+				continue
+			}
+			lerp := func(x float32) core.Point {
+				t := (x - s) / d
+				return core.Pt(p0.X+(p1.X-p0.X)*t, p0.Y+(p1.Y-p0.Y)*t)
+			}
+			if !started {
+				p.MoveTo(lerp(max(a, s)))
+				started = true
+			}
+			p.LineTo(lerp(min(b, e)))
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package lottie
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+
+	"github.com/gogpu/ui/anim"
+)
+
+// prop is an animatable property: a static value, or keyframes between
+// which it is interpolated. Values are vectors of numbers; a path is its
+// vertices and their tangents flattened to (vx, vy, ix, iy, ox, oy) per
+// vertex, so paths with as many vertices are interpolated alike.
+type prop struct {
+	static []float32
+	keys   []keyframe
+	closed bool // Of paths.
+
+	// x and y are the dimensions of a position animated apart.
+	x, y *prop
+}
+
+type keyframe struct {
+	t      float32
+	s, e   []float32 // e is only set by files of before version 5.
+	hold   bool
+	curves []anim.Curve // Easing each dimension, or all with one.
+
+	// to and ti are the tangents of a motion path from s and into the
+	// end, relative to them.
+	to, ti []float32
+}
+
+func (p *prop) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != '{' {
+		// Some properties, such as fill rules, are bare numbers.
+		p.static, p.closed = value(data)
+		return nil
+	}
+	var raw struct {
+		K     json.RawMessage `json:"k"`
+		Split bool            `json:"s"`
+		X     *prop           `json:"x"`
+		Y     *prop           `json:"y"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw.Split && raw.X != nil && raw.Y != nil {
+		p.x, p.y = raw.X, raw.Y
+		return nil
+	}
+	k := bytes.TrimSpace(raw.K)
+	if len(k) > 1 && k[0] == '[' && bytes.TrimSpace(k[1:])[0] == '{' {
+		var keys []struct {
+			T  float32         `json:"t"`
+			S  json.RawMessage `json:"s"`
+			E  json.RawMessage `json:"e"`
+			H  json.RawMessage `json:"h"`
+			O  *tangent        `json:"o"`
+			I  *tangent        `json:"i"`
+			To []float32       `json:"to"`
+			Ti []float32       `json:"ti"`
+		}
+		if err := json.Unmarshal(k, &keys); err == nil && (len(keys) == 0 || keys[0].S != nil || keys[0].T != 0) {
+			p.keys = make([]keyframe, len(keys))
+			for i, raw := range keys {
+				kf := keyframe{t: raw.T, to: raw.To, ti: raw.Ti}
+				kf.s, p.closed = value(raw.S)
+				kf.e, _ = value(raw.E)
+				kf.hold = string(raw.H) == "1" || string(raw.H) == "true"
+				if raw.O != nil && raw.I != nil {
+					n := max(len(raw.O.X), len(raw.I.X), 1)
+					for j := range n {
+						kf.curves = append(kf.curves, anim.CubicBezier(
+							at(raw.O.X, j), at(raw.O.Y, j), at(raw.I.X, j), at(raw.I.Y, j)))
+					}
+				}
+				p.keys[i] = kf
+			}
+			return nil
+		}
+	}
+	p.static, p.closed = value(k)
+	return nil
+}
+
+// tangent is the easing of a keyframe, each coordinate a number or one
+// per dimension.
+type tangent struct {
+	X, Y numbers
+}
+
+type numbers []float32
+
+func (n *numbers) UnmarshalJSON(data []byte) error {
+	*n, _ = value(data)
+	return nil
+}
+
+func at(v []float32, i int) float32 {
+	if len(v) == 0 {
+		return 0
+	}
+	return v[min(i, len(v)-1)]
+}
+
+// value decodes a number, an array of them or a path, which may be
+// wrapped in an array, as the values of keyframes are.
+func value(data []byte) ([]float32, bool) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, false
+	}
+	switch data[0] {
+	case '{':
+		var path struct {
+			I, O, V [][]float32
+			C       bool
+		}
+		if json.Unmarshal(data, &path) != nil {
+			return nil, false
+		}
+		out := make([]float32, 0, 6*len(path.V))
+		for i, v := range path.V {
+			var in, o []float32
+			if i < len(path.I) {
+				in = path.I[i]
+			}
+			if i < len(path.O) {
+				o = path.O[i]
+			}
+			out = append(out, at(v, 0), at(v, 1), at(in, 0), at(in, 1), at(o, 0), at(o, 1))
+		}
+		return out, path.C
+	case '[':
+		var vs []float32
+		if json.Unmarshal(data, &vs) == nil {
+			return vs, false
+		}
+		var wrapped []json.RawMessage
+		if json.Unmarshal(data, &wrapped) == nil && len(wrapped) > 0 {
+			return value(wrapped[0])
+		}
+		return nil, false
+	}
+	var v float32
+	if json.Unmarshal(data, &v) != nil {
+		return nil, false
+	}
+	return []float32{v}, false
+}
+
+// at returns the value at frame f.
+func (p *prop) at(f float32) []float32 {
+	if p.x != nil {
+		return []float32{p.x.scalar(f, 0), p.y.scalar(f, 0)}
+	}
+	if len(p.keys) == 0 {
+		return p.static
+	}
+	first, last := &p.keys[0], &p.keys[len(p.keys)-1]
+	if f <= first.t {
+		return first.s
+	}
+	if f >= last.t {
+		if last.s == nil && len(p.keys) > 1 {
+			// Old files end with a keyframe of only a time.
+			return p.keys[len(p.keys)-2].e
+		}
+		return last.s
+	}
+	i := 1
+	for p.keys[i].t <= f {
+		i++
+	}
+	k, next := &p.keys[i-1], &p.keys[i]
+	end := k.e
+	if end == nil {
+		end = next.s
+	}
+	if k.hold || len(end) != len(k.s) || next.t <= k.t {
+		return k.s
+	}
+	t := (f - k.t) / (next.t - k.t)
+	out := make([]float32, len(k.s))
+	if len(k.to) >= 2 && len(k.ti) >= 2 && len(k.s) >= 2 && (k.to[0] != 0 || k.to[1] != 0 || k.ti[0] != 0 || k.ti[1] != 0) {
+		// The position moves along a curve.
+		e := t
+		if len(k.curves) > 0 {
+			e = k.curves[0](t)
+		}
+		q := 1 - e
+		for j := range 2 {
+			c1, c2 := k.s[j]+k.to[j], end[j]+k.ti[j]
+			out[j] = q*q*q*k.s[j] + 3*q*q*e*c1 + 3*q*e*e*c2 + e*e*e*end[j]
+		}
+		copy(out[2:], k.s[2:])
+		return out
+	}
+	for j := range out {
+		e := t
+		if len(k.curves) > 0 {
+			e = k.curves[min(j, len(k.curves)-1)](t)
+		}
+		out[j] = k.s[j] + (end[j]-k.s[j])*e
+	}
+	return out
+}
+
+// scalar returns the first number of the value at f, or def if it has
+// none.
+func (p *prop) scalar(f float32, def float32) float32 {
+	v := p.at(f)
+	if len(v) == 0 {
+		return def
+	}
+	return at(v, 0)
+}
+
+// point returns the first two numbers of the value at f, or def.
+func (p *prop) point(f float32, def [2]float32) [2]float32 {
+	v := p.at(f)
+	switch len(v) {
+	case 0:
+		return def
+	case 1:
+		return [2]float32{v[0], v[0]}
+	}
+	return [2]float32{v[0], v[1]}
+}
+
+func radians(deg float32) float64 {
+	return float64(deg) * math.Pi / 180
+}
@@ -0,0 +1,403 @@
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gogpu/ui/richtext"
+)
+
+// node is an element of a paragraph's inline content while it is parsed:
+// literal text, or a run of emphasis delimiters or a link opener that may
+// still turn out to be literal.
+type node struct {
+	text  string
+	style richtext.Style
+	link  string
+	image *richtext.Image
+
+	delim       byte // '*' or '_' for emphasis, '[' or '!' for link openers
+	n, orig     int  // delimiters left, and in the original run
+	open, close bool // whether the delimiters can open and close emphasis
+	src         int  // for link openers, where the link text starts
+}
+
+var (
+	autolinkRE = regexp.MustCompile(`^<([A-Za-z][A-Za-z0-9+.-]{1,31}:[^\s<>]*)>`)
+	emailRE    = regexp.MustCompile(`^<([A-Za-z0-9.!#$%&'*+/=?^_{|}~-]+@[A-Za-z0-9](?:[A-Za-z0-9-]{0,61}[A-Za-z0-9])?(?:\.[A-Za-z0-9](?:[A-Za-z0-9-]{0,61}[A-Za-z0-9])?)*)>`)
+	entityRE   = regexp.MustCompile(`^&(?:#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6}|[A-Za-z][A-Za-z0-9]{1,31});`)
+)
+
+func isPunct(r rune) bool {
+	return unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
+
+// unescape resolves backslash escapes and entities in s.
+func unescape(s string) string {
+	if !strings.ContainsAny(s, `\&`) {
+		return s
+	}
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && isPunct(rune(s[i+1])) && s[i+1] < utf8.RuneSelf {
+			i++
+		}
+		sb.WriteByte(s[i])
+	}
+	return html.UnescapeString(sb.String())
+}
+
+// inline parses inline source into spans.
+func (p *parser) inline(src string) []richtext.Span {
+	var nodes []node
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			nodes = append(nodes, node{text: text.String()})
+			text.Reset()
+		}
+	}
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\\' && i+1 < len(src) && src[i+1] == '\n':
+			flush()
+			nodes = append(nodes, node{text: "\n"})
+			i += 2
+		case c == '\\' && i+1 < len(src) && src[i+1] < utf8.RuneSelf && isPunct(rune(src[i+1])):
+			text.WriteByte(src[i+1])
+			i += 2
+		case c == '`':
+			n := runLen(src, i, '`')
+			end := closingBackticks(src, i+n, n)
+			if end < 0 {
+				text.WriteString(src[i : i+n])
+				i += n
+				break
+			}
+			flush()
+			code := strings.ReplaceAll(src[i+n:end], "\n", " ")
+			if len(code) >= 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.TrimSpace(code) != "" {
+				code = code[1 : len(code)-1]
+			}
+			nodes = append(nodes, node{text: code, style: richtext.Code})
+			i = end + n
+		case c == '*' || c == '_':
+			flush()
+			n := runLen(src, i, c)
+			prev, _ := utf8.DecodeLastRuneInString(src[:i])
+			next, _ := utf8.DecodeRuneInString(src[i+n:])
+			if i == 0 {
+				prev = ' '
+			}
+			if i+n == len(src) {
+				next = ' '
+			}
+			left := !unicode.IsSpace(next) && (!isPunct(next) || unicode.IsSpace(prev) || isPunct(prev))
+			right := !unicode.IsSpace(prev) && (!isPunct(prev) || unicode.IsSpace(next) || isPunct(next))
+			d := node{delim: c, n: n, orig: n, open: left, close: right}
+			if c == '_' {
+				d.open = left && (!right || isPunct(prev))
+				d.close = right && (!left || isPunct(next))
+			}
+			nodes = append(nodes, d)
+			i += n
+		case c == '!' && i+1 < len(src) && src[i+1] == '[':
+			flush()
+			nodes = append(nodes, node{delim: '!', src: i + 2, open: true})
+			i += 2
+		case c == '[':
+			flush()
+			nodes = append(nodes, node{delim: '[', src: i + 1, open: true})
+			i++
+		case c == ']':
+			flush()
+			i = p.closeBracket(&nodes, src, i)
+		case c == '<' && autolinkRE.MatchString(src[i:]):
+			flush()
+			sub := autolinkRE.FindStringSubmatch(src[i:])
+			nodes = append(nodes, node{text: sub[1], link: sub[1]})
+			i += len(sub[0])
+		case c == '<' && emailRE.MatchString(src[i:]):
+			flush()
+			sub := emailRE.FindStringSubmatch(src[i:])
+			nodes = append(nodes, node{text: sub[1], link: "mailto:" + sub[1]})
+			i += len(sub[0])
+		case c == '&' && entityRE.MatchString(src[i:]):
+			e := entityRE.FindString(src[i:])
+			text.WriteString(html.UnescapeString(e))
+			i += len(e)
+		case c == '\n':
+			// A line break is hard after two spaces, and otherwise soft.
+			s := text.String()
+			trimmed := strings.TrimRight(s, " ")
+			text.Reset()
+			text.WriteString(trimmed)
+			if len(s)-len(trimmed) >= 2 {
+				flush()
+				nodes = append(nodes, node{text: "\n"})
+			} else {
+				text.WriteByte(' ')
+			}
+			i++
+			for i < len(src) && src[i] == ' ' {
+				i++
+			}
+		default:
+			text.WriteByte(c)
+			i++
+		}
+	}
+	flush()
+	emphasis(nodes, -1)
+	return spans(nodes)
+}
+
+// runLen returns the length of the run of c starting at i.
+func runLen(s string, i int, c byte) int {
+	n := 0
+	for i+n < len(s) && s[i+n] == c {
+		n++
+	}
+	return n
+}
+
+// closingBackticks returns where a run of exactly n backticks starts at
+// or after i, or -1.
+func closingBackticks(s string, i, n int) int {
+	for i < len(s) {
+		j := strings.IndexByte(s[i:], '`')
+		if j < 0 {
+			return -1
+		}
+		i += j
+		m := runLen(s, i, '`')
+		if m == n {
+			return i
+		}
+		i += m
+	}
+	return -1
+}
+
+// closeBracket handles the ] at src[i], turning the text since the last
+// link opener into a link or image if a destination follows. It returns
+// where parsing continues.
+func (p *parser) closeBracket(nodes *[]node, src string, i int) int {
+	ns := *nodes
+	o := len(ns) - 1
+	for o >= 0 && ns[o].delim != '[' && ns[o].delim != '!' {
+		o--
+	}
+	if o < 0 {
+		*nodes = append(ns, node{text: "]"})
+		return i + 1
+	}
+	opener := ns[o]
+	url, end, ok := p.destination(src, i+1, src[opener.src:i])
+	if !opener.open || !ok {
+		// Without a destination, or inside another link, the brackets
+		// are text.
+		ns[o] = node{text: opener.literal()}
+		*nodes = append(ns, node{text: "]"})
+		return i + 1
+	}
+	emphasis(ns, o)
+	inner := ns[o+1:]
+	for k := range inner {
+		inner[k].open, inner[k].close = false, false
+	}
+	if opener.delim == '!' {
+		var alt strings.Builder
+		for _, n := range inner {
+			alt.WriteString(n.literal())
+		}
+		ns = append(ns[:o], node{image: &richtext.Image{Src: url, Alt: alt.String()}})
+	} else {
+		for k := range inner {
+			if inner[k].link == "" {
+				inner[k].link = url
+			}
+		}
+		ns = append(ns[:o], inner...)
+		// Links may not contain other links.
+		for k := range ns[:o] {
+			if ns[k].delim == '[' {
+				ns[k].open = false
+			}
+		}
+	}
+	*nodes = ns
+	return end
+}
+
+// destination parses what follows the ] of a link at src[i]: an inline
+// destination and title in parentheses, or a reference to a definition.
+// label is the link text, which is also the label of collapsed and
+// shortcut references.
+func (p *parser) destination(src string, i int, label string) (url string, end int, ok bool) {
+	if i < len(src) && src[i] == '(' {
+		if url, end, ok := inlineDestination(src, i+1); ok {
+			return url, end, true
+		}
+	}
+	end = i
+	if i < len(src) && src[i] == '[' {
+		if j := strings.IndexByte(src[i:], ']'); j > 0 {
+			if l := src[i+1 : i+j]; l != "" {
+				label = l
+			}
+			end = i + j + 1
+		}
+	}
+	url, ok = p.refs[normalizeLabel(label)]
+	if !ok {
+		return "", 0, false
+	}
+	return url, end, true
+}
+
+// inlineDestination parses `dest "title")` starting at src[i]. Titles are
+// not displayed, and are skipped.
+func inlineDestination(src string, i int) (url string, end int, ok bool) {
+	skip := func() {
+		for i < len(src) && (src[i] == ' ' || src[i] == '\t' || src[i] == '\n') {
+			i++
+		}
+	}
+	skip()
+	start := i
+	if i < len(src) && src[i] == '<' {
+		j := strings.IndexAny(src[i+1:], ">\n")
+		if j < 0 || src[i+1+j] != '>' {
+			return "", 0, false
+		}
+		url = src[i+1 : i+1+j]
+		i += j + 2
+	} else {
+		depth := 0
+		for i < len(src) && src[i] > ' ' {
+			if src[i] == '\\' && i+1 < len(src) {
+				i += 2
+				continue
+			}
+			if src[i] == '(' {
+				depth++
+			} else if src[i] == ')' {
+				if depth == 0 {
+					break
+				}
+				depth--
+			}
+			i++
+		}
+		if depth != 0 {
+			return "", 0, false
+		}
+		url = src[start:i]
+	}
+	skip()
+	if i < len(src) && i > start && strings.IndexByte(`"'(`, src[i]) >= 0 {
+		closer := map[byte]byte{'"': '"', '\'': '\'', '(': ')'}[src[i]]
+		j := i + 1
+		for j < len(src) && src[j] != closer {
+			if src[j] == '\\' {
+				j++
+			}
+			j++
+		}
+		if j >= len(src) {
+			return "", 0, false
+		}
+		i = j + 1
+		skip()
+	}
+	if i >= len(src) || src[i] != ')' {
+		return "", 0, false
+	}
+	return unescape(url), i + 1, true
+}
+
+// literal returns the text n stands for if it is not markup.
+func (n *node) literal() string {
+	switch n.delim {
+	case '*', '_':
+		return strings.Repeat(string(n.delim), n.n)
+	case '[':
+		return "["
+	case '!':
+		return "!["
+	}
+	if n.image != nil {
+		return n.image.Alt
+	}
+	return n.text
+}
+
+// emphasis matches the emphasis delimiters after nodes[bottom], making the
+// nodes between matched delimiters italic or bold.
+func emphasis(nodes []node, bottom int) {
+	for c := bottom + 1; c < len(nodes); c++ {
+		closer := &nodes[c]
+		if closer.delim != '*' && closer.delim != '_' || !closer.close || closer.n == 0 {
+			continue
+		}
+		o := c - 1
+		for ; o > bottom; o-- {
+			op := &nodes[o]
+			if op.delim != closer.delim || !op.open || op.n == 0 {
+				continue
+			}
+			// A run that can both open and close matches only runs that
+			// keep the total a non-multiple of three.
+			if (op.close || closer.open) && (op.orig+closer.orig)%3 == 0 && !(op.orig%3 == 0 && closer.orig%3 == 0) {
+				continue
+			}
+			break
+		}
+		if o <= bottom {
+			continue
+		}
+		use, style := 1, richtext.Italic
+		if nodes[o].n >= 2 && closer.n >= 2 {
+			use, style = 2, richtext.Bold
+		}
+		for k := o + 1; k < c; k++ {
+			nodes[k].style |= style
+			if d := nodes[k].delim; d == '*' || d == '_' {
+				// Delimiters inside the emphasis can no longer match
+				// delimiters outside it.
+				nodes[k].open, nodes[k].close = false, false
+			}
+		}
+		nodes[o].n -= use
+		closer.n -= use
+		if closer.n > 0 {
+			c--
+		}
+	}
+}
+
+// spans merges parsed nodes into spans.
+func spans(nodes []node) []richtext.Span {
+	var out []richtext.Span
+	for _, n := range nodes {
+		if n.image != nil {
+			out = append(out, richtext.Span{Style: n.style, Link: n.link, Image: n.image})
+			continue
+		}
+		text := n.literal()
+		if text == "" {
+			continue
+		}
+		if k := len(out) - 1; k >= 0 && out[k].Image == nil && out[k].Style == n.style && out[k].Link == n.link {
+			out[k].Text += text
+			continue
+		}
+		out = append(out, richtext.Span{Text: text, Style: n.style, Link: n.link})
+	}
+	return out
+}
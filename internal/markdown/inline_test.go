@@ -0,0 +1,98 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gogpu/ui/richtext"
+)
+
+// spanString returns a compact form of spans: each span's formats as
+// letters before a colon, its text, and @ and the URL of a link. Images
+// are written as !alt(src).
+func spanString(spans []richtext.Span) string {
+	parts := make([]string, len(spans))
+	for i, s := range spans {
+		text := s.Text
+		if s.Image != nil {
+			text = "!" + s.Image.Alt + "(" + s.Image.Src + ")"
+		}
+		var flags string
+		for _, f := range []struct {
+			s richtext.Style
+			c string
+		}{{richtext.Bold, "b"}, {richtext.Italic, "i"}, {richtext.Code, "c"}} {
+			if s.Style.Has(f.s) {
+				flags += f.c
+			}
+		}
+		if flags != "" {
+			text = flags + ":" + text
+		}
+		if s.Link != "" {
+			text += "@" + s.Link
+		}
+		parts[i] = text
+	}
+	return strings.Join(parts, "|")
+}
+
+func TestInline(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"a *b* c", "a |i:b| c"},
+		{"**a** __b__", "b:a| |b:b"},
+		{"***x***", "bi:x"},
+		{"snake_case_name", "snake_case_name"},
+		{"_a_b", "_a_b"},
+		{"*a **b** c*", "i:a |bi:b|i: c"},
+		{"*foo**bar**baz*", "i:foo|bi:bar|i:baz"},
+		{"**a*", "*|i:a"},
+		{"* a *", "* a *"},
+		{"`a*b*`", "c:a*b*"},
+		{"`` a`b ``", "c:a`b"},
+		{"` `", "c: "},
+		{"```x", "```x"},
+		{"*a `*` b*", "i:a |ic:*|i: b"},
+		{`\*not\*`, "*not*"},
+		{"a\\\nb", "a\nb"},
+		{"a  \nb", "a\nb"},
+		{"a \n  b", "a b"},
+		{`[go](http://x "title")`, "go@http://x"},
+		{"[*go*](</a b>)", "i:go@/a b"},
+		{"[a](b(c)d)", "a@b(c)d"},
+		{"![cat *x*](c.png)", "!cat x(c.png)"},
+		{"[![i](s)](u)", "!i(s)@u"},
+		{"[a [b](u)](v)", "[a |b@u|](v)"},
+		{"[x]", "[x]"},
+		{"a ]", "a ]"},
+		{"[a](b", "[a](b"},
+		{"[a](<b)", "[a](<b)"},
+		{`[a](b "t)`, `[a](b "t)`},
+		{"<https://a.b/c>", "https://a.b/c@https://a.b/c"},
+		{"<me@x.org>", "me@x.org@mailto:me@x.org"},
+		{"<b>raw</b>", "<b>raw</b>"},
+		{"&amp; &copy; &#65; &bogus;", "& © A &bogus;"},
+	}
+	for _, tt := range tests {
+		p := &parser{refs: map[string]string{}}
+		if got := spanString(p.inline(tt.src)); got != tt.want {
+			t.Errorf("inline(%q)\n got %q\nwant %q", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestUnescape(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"plain", "plain"},
+		{`a\.b \q`, `a.b \q`},
+		{"&lt;&#x41;", "<A"},
+	}
+	for _, tt := range tests {
+		if got := unescape(tt.in); got != tt.want {
+			t.Errorf("unescape(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
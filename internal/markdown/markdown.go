@@ -0,0 +1,461 @@
+// Package markdown parses CommonMark, with GitHub's tables, into the block
+// tree displayed by widgets.Markdown. Inline content is parsed into
+// richtext spans.
+//
+// The parser covers the constructs documents use in practice: ATX and
+// setext headings, paragraphs, block quotes, nested bullet and ordered
+// lists, fenced and indented code blocks, thematic breaks, tables, and
+// inline emphasis, code spans, links, images, autolinks, entities and
+// hard line breaks. Raw HTML is shown as text.
+package markdown
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gogpu/ui/richtext"
+)
+
+// Kind is the kind of a block.
+type Kind uint8
+
+// Block kinds.
+const (
+	Paragraph Kind = iota
+	Heading
+	Quote
+	List
+	Item
+	Code
+	Rule
+	Table
+)
+
+// Align is the alignment of a table column.
+type Align uint8
+
+// Column alignments.
+const (
+	AlignNone Align = iota
+	AlignLeft
+	AlignCenter
+	AlignRight
+)
+
+// Block is a node of a parsed document.
+type Block struct {
+	Kind Kind
+	// Level is the level of a heading, from 1 to 6.
+	Level int
+	// Spans is the inline content of a paragraph or heading.
+	Spans []richtext.Span
+	// Children holds the blocks of a quote or list item, and the items
+	// of a list.
+	Children []*Block
+	// Ordered lists are numbered from Start. Items of a tight list are
+	// not separated by blank lines.
+	Ordered bool
+	Start   int
+	Tight   bool
+	// Lang and Text are the language and content of a code block.
+	Lang, Text string
+	// Rows holds the cells of a table, header row first, and Align the
+	// alignment of each column.
+	Rows  [][][]richtext.Span
+	Align []Align
+
+	raw   string     // inline source, until inlines are parsed
+	cells [][]string // inline source of table cells
+}
+
+type parser struct {
+	// refs maps the normalized labels of link reference definitions to
+	// their destinations.
+	refs map[string]string
+}
+
+// Parse parses CommonMark source into blocks.
+func Parse(src string) []*Block {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	src = strings.ReplaceAll(src, "\r", "\n")
+	lines := strings.Split(src, "\n")
+	for i, l := range lines {
+		lines[i] = expandIndent(l)
+	}
+	p := &parser{refs: map[string]string{}}
+	blocks := p.blocks(lines)
+	p.inlines(blocks)
+	return blocks
+}
+
+// expandIndent replaces the tabs in the indentation of l with spaces, to
+// tab stops of 4.
+func expandIndent(l string) string {
+	var sb strings.Builder
+	col := 0
+	for i := 0; i < len(l); i++ {
+		switch l[i] {
+		case ' ':
+			sb.WriteByte(' ')
+			col++
+		case '\t':
+			n := 4 - col%4
+			sb.WriteString(strings.Repeat(" ", n))
+			col += n
+		default:
+			if i == 0 {
+				return l
+			}
+			return sb.String() + l[i:]
+		}
+	}
+	return sb.String()
+}
+
+func blank(l string) bool {
+	return strings.TrimSpace(l) == ""
+}
+
+func indent(l string) int {
+	return len(l) - len(strings.TrimLeft(l, " "))
+}
+
+// dedent removes up to n columns of indentation from l.
+func dedent(l string, n int) string {
+	return l[min(n, indent(l)):]
+}
+
+var (
+	ruleRE    = regexp.MustCompile(`^ {0,3}((\*[ \t]*){3,}|(-[ \t]*){3,}|(_[ \t]*){3,})$`)
+	headingRE = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	fenceRE   = regexp.MustCompile("^( {0,3})(`{3,}|~{3,})[ \t]*(.*)$")
+	setextRE  = regexp.MustCompile(`^ {0,3}(=+|-+)[ \t]*$`)
+	delimRE   = regexp.MustCompile(`^ {0,3}\|?[ \t]*:?-+:?[ \t]*(\|[ \t]*:?-+:?[ \t]*)*\|?[ \t]*$`)
+	refRE     = regexp.MustCompile(`^ {0,3}\[((?:[^\]\\]|\\.)+)\]:[ \t]*(<[^>]*>|\S+)(?:[ \t]+("(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'|\((?:[^)\\]|\\.)*\)))?[ \t]*$`)
+)
+
+// marker is a list item marker.
+type marker struct {
+	ordered bool
+	delim   byte // the bullet character, or the . or ) after the number
+	start   int
+	width   int // the column of the item's content
+	empty   bool
+}
+
+func listMarker(l string) (marker, bool) {
+	var m marker
+	ind := indent(l)
+	if ind > 3 || ruleRE.MatchString(l) {
+		return m, false
+	}
+	rest := l[ind:]
+	n := 0 // the length of the marker
+	if rest != "" && strings.IndexByte("-+*", rest[0]) >= 0 {
+		m.delim, n = rest[0], 1
+	} else {
+		for n < len(rest) && n < 10 && rest[n] >= '0' && rest[n] <= '9' {
+			n++
+		}
+		if n == 0 || n > 9 || n == len(rest) || rest[n] != '.' && rest[n] != ')' {
+			return m, false
+		}
+		m.ordered = true
+		m.start, _ = strconv.Atoi(rest[:n])
+		m.delim = rest[n]
+		n++
+	}
+	after := rest[n:]
+	end := ind + n
+	switch {
+	case blank(after):
+		m.empty = true
+		m.width = end + 1
+	case after[0] == '\t':
+		m.width = end + 1
+	case after[0] != ' ':
+		return m, false
+	case indent(after) >= 5:
+		// The content is an indented code block, one space after the
+		// marker.
+		m.width = end + 1
+	default:
+		m.width = end + indent(after)
+	}
+	return m, true
+}
+
+// startsBlock reports whether l starts a block that interrupts a
+// paragraph.
+func startsBlock(l string) bool {
+	if blank(l) || indent(l) >= 4 {
+		return blank(l)
+	}
+	if ruleRE.MatchString(l) || headingRE.MatchString(l) || fenceRE.MatchString(l) || strings.HasPrefix(strings.TrimLeft(l, " "), ">") {
+		return true
+	}
+	if m, ok := listMarker(l); ok && !m.empty && (!m.ordered || m.start == 1) {
+		return true
+	}
+	return false
+}
+
+// blocks parses lines into blocks.
+func (p *parser) blocks(lines []string) []*Block {
+	var out []*Block
+	for i := 0; i < len(lines); {
+		l := lines[i]
+		switch {
+		case blank(l):
+			i++
+		case indent(l) >= 4:
+			var code []string
+			for i < len(lines) && (blank(lines[i]) || indent(lines[i]) >= 4) {
+				code = append(code, dedent(lines[i], 4))
+				i++
+			}
+			for len(code) > 0 && blank(code[len(code)-1]) {
+				code = code[:len(code)-1]
+			}
+			out = append(out, &Block{Kind: Code, Text: strings.Join(code, "\n")})
+		case fenceRE.MatchString(l):
+			sub := fenceRE.FindStringSubmatch(l)
+			fence, info := sub[2], strings.TrimSpace(sub[3])
+			if fence[0] == '`' && strings.Contains(info, "`") {
+				i = p.paragraph(lines, i, &out)
+				break
+			}
+			var code []string
+			for i++; i < len(lines); i++ {
+				t := strings.TrimSpace(lines[i])
+				if indent(lines[i]) < 4 && strings.HasPrefix(t, fence) && strings.Trim(t, fence[:1]) == "" {
+					i++
+					break
+				}
+				code = append(code, dedent(lines[i], len(sub[1])))
+			}
+			lang, _, _ := strings.Cut(info, " ")
+			out = append(out, &Block{Kind: Code, Lang: unescape(lang), Text: strings.Join(code, "\n")})
+		case ruleRE.MatchString(l):
+			out = append(out, &Block{Kind: Rule})
+			i++
+		case headingRE.MatchString(l):
+			sub := headingRE.FindStringSubmatch(l)
+			out = append(out, &Block{Kind: Heading, Level: len(sub[1]), raw: sub[2]})
+			i++
+		case strings.HasPrefix(strings.TrimLeft(l, " "), ">"):
+			var quoted []string
+			for i < len(lines) {
+				t := strings.TrimLeft(lines[i], " ")
+				if indent(lines[i]) < 4 && strings.HasPrefix(t, ">") {
+					quoted = append(quoted, strings.TrimPrefix(t[1:], " "))
+				} else if len(quoted) > 0 && !blank(quoted[len(quoted)-1]) && !startsBlock(lines[i]) {
+					// A lazy continuation of a quoted paragraph.
+					quoted = append(quoted, lines[i])
+				} else {
+					break
+				}
+				i++
+			}
+			out = append(out, &Block{Kind: Quote, Children: p.blocks(quoted)})
+		default:
+			if _, ok := listMarker(l); ok {
+				i = p.list(lines, i, &out)
+			} else if i+1 < len(lines) && strings.Contains(l, "|") && delimRE.MatchString(lines[i+1]) {
+				i = p.table(lines, i, &out)
+			} else {
+				i = p.paragraph(lines, i, &out)
+			}
+		}
+	}
+	return out
+}
+
+// paragraph parses the paragraph, or setext heading, starting at line i,
+// and returns the line after it.
+func (p *parser) paragraph(lines []string, i int, out *[]*Block) int {
+	var text []string
+	level := 0
+	for ; i < len(lines); i++ {
+		l := lines[i]
+		if len(text) > 0 {
+			if sub := setextRE.FindStringSubmatch(l); sub != nil {
+				level = 2
+				if sub[1][0] == '=' {
+					level = 1
+				}
+				i++
+				break
+			}
+			if startsBlock(l) {
+				break
+			}
+		}
+		text = append(text, strings.TrimLeft(l, " "))
+	}
+	// Link reference definitions at the start of the paragraph are not
+	// displayed.
+	for len(text) > 0 {
+		sub := refRE.FindStringSubmatch(text[0])
+		if sub == nil {
+			break
+		}
+		label := normalizeLabel(sub[1])
+		if _, ok := p.refs[label]; !ok && label != "" {
+			url := strings.TrimSuffix(strings.TrimPrefix(sub[2], "<"), ">")
+			p.refs[label] = unescape(url)
+		}
+		text = text[1:]
+	}
+	if len(text) == 0 {
+		return i
+	}
+	raw := strings.TrimRight(strings.Join(text, "\n"), " \t")
+	if level > 0 {
+		*out = append(*out, &Block{Kind: Heading, Level: level, raw: raw})
+	} else {
+		*out = append(*out, &Block{Kind: Paragraph, raw: raw})
+	}
+	return i
+}
+
+// list parses the list starting at line i and returns the line after it.
+func (p *parser) list(lines []string, i int, out *[]*Block) int {
+	first, _ := listMarker(lines[i])
+	list := &Block{Kind: List, Ordered: first.ordered, Start: first.start, Tight: true}
+	for i < len(lines) {
+		m, ok := listMarker(lines[i])
+		if !ok || m.ordered != first.ordered || m.delim != first.delim {
+			break
+		}
+		item := []string{lines[i][min(m.width, len(lines[i])):]}
+		for i++; i < len(lines); i++ {
+			l := lines[i]
+			if blank(l) {
+				item = append(item, "")
+				continue
+			}
+			if indent(l) >= m.width {
+				item = append(item, l[m.width:])
+				continue
+			}
+			if _, next := listMarker(l); next || blank(item[len(item)-1]) || startsBlock(l) {
+				break
+			}
+			// A lazy continuation of the item's paragraph.
+			item = append(item, strings.TrimLeft(l, " "))
+		}
+		trailing := 0
+		for len(item) > 0 && blank(item[len(item)-1]) {
+			item = item[:len(item)-1]
+			trailing++
+		}
+		children := p.blocks(item)
+		if len(children) > 1 && slices.ContainsFunc(item, blank) {
+			list.Tight = false
+		}
+		list.Children = append(list.Children, &Block{Kind: Item, Children: children})
+		if trailing > 0 {
+			if next, ok := listMarker(lineAt(lines, i)); ok && next.ordered == first.ordered && next.delim == first.delim {
+				list.Tight = false
+			}
+		}
+	}
+	*out = append(*out, list)
+	return i
+}
+
+func lineAt(lines []string, i int) string {
+	if i < len(lines) {
+		return lines[i]
+	}
+	return ""
+}
+
+// table parses the table starting at line i and returns the line after
+// it. Lines that do not form a table are parsed as a paragraph.
+func (p *parser) table(lines []string, i int, out *[]*Block) int {
+	header := splitRow(lines[i])
+	delims := splitRow(lines[i+1])
+	if len(header) != len(delims) {
+		return p.paragraph(lines, i, out)
+	}
+	t := &Block{Kind: Table, cells: [][]string{header}}
+	for _, d := range delims {
+		d = strings.TrimSpace(d)
+		left, right := strings.HasPrefix(d, ":"), strings.HasSuffix(d, ":")
+		switch {
+		case left && right:
+			t.Align = append(t.Align, AlignCenter)
+		case left:
+			t.Align = append(t.Align, AlignLeft)
+		case right:
+			t.Align = append(t.Align, AlignRight)
+		default:
+			t.Align = append(t.Align, AlignNone)
+		}
+	}
+	for i += 2; i < len(lines) && !startsBlock(lines[i]); i++ {
+		row := splitRow(lines[i])
+		for len(row) < len(header) {
+			row = append(row, "")
+		}
+		t.cells = append(t.cells, row[:len(header)])
+	}
+	*out = append(*out, t)
+	return i
+}
+
+// splitRow splits a table row into the source of its cells.
+func splitRow(l string) []string {
+	l = strings.TrimSpace(l)
+	l = strings.TrimPrefix(l, "|")
+	if strings.HasSuffix(l, "|") && !strings.HasSuffix(l, `\|`) {
+		l = l[:len(l)-1]
+	}
+	var cells []string
+	var sb strings.Builder
+	for i := 0; i < len(l); i++ {
+		switch {
+		case l[i] == '\\' && i+1 < len(l) && l[i+1] == '|':
+			sb.WriteByte('|')
+			i++
+		case l[i] == '|':
+			cells = append(cells, strings.TrimSpace(sb.String()))
+			sb.Reset()
+		default:
+			sb.WriteByte(l[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(sb.String()))
+}
+
+// inlines parses the inline content of blocks, once every reference
+// definition is known.
+func (p *parser) inlines(blocks []*Block) {
+	for _, b := range blocks {
+		switch b.Kind {
+		case Paragraph, Heading:
+			b.Spans = p.inline(b.raw)
+			b.raw = ""
+		case Table:
+			for _, row := range b.cells {
+				spans := make([][]richtext.Span, len(row))
+				for i, c := range row {
+					spans[i] = p.inline(c)
+				}
+				b.Rows = append(b.Rows, spans)
+			}
+			b.cells = nil
+		}
+		p.inlines(b.Children)
+	}
+}
+
+// normalizeLabel returns the form of a reference label used to match
+// references with definitions.
+func normalizeLabel(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
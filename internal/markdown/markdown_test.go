@@ -0,0 +1,166 @@
+package markdown
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gogpu/ui/richtext"
+)
+
+// dump returns a compact form of blocks for comparing parse trees.
+func dump(blocks []*Block) string {
+	parts := make([]string, len(blocks))
+	for i, b := range blocks {
+		parts[i] = dumpBlock(b)
+	}
+	return strings.Join(parts, " ")
+}
+
+func dumpBlock(b *Block) string {
+	switch b.Kind {
+	case Paragraph:
+		return "p:" + plain(b.Spans)
+	case Heading:
+		return fmt.Sprintf("h%d:%s", b.Level, plain(b.Spans))
+	case Quote:
+		return "quote(" + dump(b.Children) + ")"
+	case List:
+		kind := "ul"
+		if b.Ordered {
+			kind = fmt.Sprintf("ol%d", b.Start)
+		}
+		if !b.Tight {
+			kind += "*"
+		}
+		return kind + "(" + dump(b.Children) + ")"
+	case Item:
+		return "li(" + dump(b.Children) + ")"
+	case Code:
+		return fmt.Sprintf("code[%s]:%q", b.Lang, b.Text)
+	case Rule:
+		return "hr"
+	case Table:
+		var rows []string
+		for _, r := range b.Rows {
+			var cells []string
+			for _, c := range r {
+				cells = append(cells, plain(c))
+			}
+			rows = append(rows, strings.Join(cells, "|"))
+		}
+		return fmt.Sprintf("table%v[%s]", b.Align, strings.Join(rows, "/"))
+	}
+	return "?"
+}
+
+// plain returns the text of spans, with images as their alt text.
+func plain(spans []richtext.Span) string {
+	b := richtext.Block{Spans: spans}
+	return b.Text()
+}
+
+func TestParseBlocks(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"paragraphs", "one\ntwo\n\nthree", "p:one two p:three"},
+		{"atx headings", "# One\n###### Six ##\n####### seven", "h1:One h6:Six p:####### seven"},
+		{"empty heading", "#", "h1:"},
+		{"setext headings", "One\n===\nTwo\n---", "h1:One h2:Two"},
+		{"rules", "***\n- - -\n___", "hr hr hr"},
+		{"a rule is not a list", "* * *", "hr"},
+		{"fenced code", "```go\nx := 1\n\n  y\n```", `code[go]:"x := 1\n\n  y"`},
+		{"tilde fence", "~~~\na\n~~~~\nb", `code[]:"a" p:b`},
+		{"unclosed fence", "```\na", `code[]:"a"`},
+		{"indented fence", "  ```\n  a\n b\n  ```", `code[]:"a\nb"`},
+		{"backticks in the info string", "```a`b\n", "p:```a`b"},
+		{"indented code", "    a\n\n    b\n\n\nc", `code[]:"a\n\nb" p:c`},
+		{"tab indented code", "\ta", `code[]:"a"`},
+		{"quote", "> a\nb\n> # c", "quote(p:a b h1:c)"},
+		{"nested quote", "> a\n>> b", "quote(p:a quote(p:b))"},
+		{"quote ends at a blank line", "> a\n\nb", "quote(p:a) p:b"},
+		{"tight list", "- a\n- b", "ul(li(p:a) li(p:b))"},
+		{"loose list", "- a\n\n- b", "ul*(li(p:a) li(p:b))"},
+		{"loose item", "- a\n\n  b\n- c", "ul*(li(p:a p:b) li(p:c))"},
+		{"ordered list", "3. a\n4. b", "ol3(li(p:a) li(p:b))"},
+		{"another delimiter starts a list", "- a\n+ b\n1) c", "ul(li(p:a)) ul(li(p:b)) ol1(li(p:c))"},
+		{"nested list", "- a\n  1. b\n  2. c\n- d", "ul(li(p:a ol1(li(p:b) li(p:c))) li(p:d))"},
+		{"lazy continuation", "- a\nb", "ul(li(p:a b))"},
+		{"a list interrupts a paragraph", "a\n- b", "p:a ul(li(p:b))"},
+		{"only 1 interrupts a paragraph", "a\n2. b", "p:a 2. b"},
+		{"code in an item", "-      code", `ul(li(code[]:" code"))`},
+		{"empty item", "-\n  a", "ul(li(p:a))"},
+		{"table", "| a | b | c |\n|:--|:-:|--:|\n| 1 | 2 |\nx | y | z | w", "table[1 2 3][a|b|c/1|2|/x|y|z]"},
+		{"table without pipes at the ends", "a | b\n--- | ---\n1 | 2\n\np", "table[0 0][a|b/1|2] p:p"},
+		{"escaped pipe", "a | b\n-|-\n`x\\|y` | 2", "table[0 0][a|b/x|y|2]"},
+		{"mismatched table", "a | b\n--- | --- | ---", "p:a | b --- | --- | ---"},
+		{"crlf", "a\r\nb\rc", "p:a b c"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dump(Parse(tt.src)); got != tt.want {
+				t.Errorf("Parse(%q)\n got %s\nwant %s", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseReferences(t *testing.T) {
+	src := "[Go]: https://go.dev \"Go\"\n[b]: </a b>\n\nSee [Go], [the docs][go], [x][] and [b][].\n\n[x]: /x\n[Go]: /second"
+	blocks := Parse(src)
+	if len(blocks) != 1 {
+		t.Fatalf("%d blocks, want the definitions hidden: %s", len(blocks), dump(blocks))
+	}
+	var links []string
+	for _, s := range blocks[0].Spans {
+		if s.Link != "" {
+			links = append(links, s.Text+"="+s.Link)
+		}
+	}
+	want := "Go=https://go.dev the docs=https://go.dev x=/x b=/a b"
+	if got := strings.Join(links, " "); got != want {
+		t.Errorf("links %s, want %s", got, want)
+	}
+}
+
+func TestListMarker(t *testing.T) {
+	tests := []struct {
+		line string
+		ok   bool
+		want marker
+	}{
+		{"- a", true, marker{delim: '-', width: 2}},
+		{"  *   a", true, marker{delim: '*', width: 6}},
+		{"12. a", true, marker{ordered: true, delim: '.', start: 12, width: 4}},
+		{"1) a", true, marker{ordered: true, delim: ')', start: 1, width: 3}},
+		{"-", true, marker{delim: '-', width: 2, empty: true}},
+		{"-a", false, marker{}},
+		{"1234567890. a", false, marker{}},
+		{"    - a", false, marker{}},
+		{"1.", true, marker{ordered: true, delim: '.', start: 1, width: 3, empty: true}},
+		{"12", false, marker{}},
+	}
+	for _, tt := range tests {
+		m, ok := listMarker(tt.line)
+		if ok != tt.ok || ok && m != tt.want {
+			t.Errorf("listMarker(%q) = %+v, %v, want %+v, %v", tt.line, m, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestExpandIndent(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"\ta", "    a"},
+		{"  \t a", "     a"},
+		{"a\tb", "a\tb"},
+		{" \t", "    "},
+	}
+	for _, tt := range tests {
+		if got := expandIndent(tt.in); got != tt.want {
+			t.Errorf("expandIndent(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+// Package scroll implements the scrollbar used by scrolling containers.
+package scroll
+
+import (
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/theme"
+)
+
+// Thickness is the width of a vertical bar (height of a horizontal one).
+const Thickness float32 = 10
+
+const minThumb float32 = 24
+
+// Bar is a scrollbar attached to a scrolling widget. The owner sets Track,
+// Viewport and Content every arrange pass and forwards pointer events to
+// HandleEvent.
+type Bar struct {
+	// Horizontal selects a horizontal bar; the default is vertical.
+	Horizontal bool
+
+	// Track is the rectangle the bar occupies in window coordinates.
+	Track core.Rect
+
+	// Viewport is the visible extent and Content the total scrollable
+	// extent along the bar's axis.
+	Viewport, Content float32
+
+	// Faded is how far the bar has faded out, from 0, fully shown, to 1,
+	// for containers that hide their bars while the content is idle.
+	Faded float32
+
+	dragging   bool
+	grabOffset float32
+	hover      bool
+}
+
+// EndTrack splits r into the track of a vertical bar along its end edge,
+// which is the left edge in right-to-left layouts, and the rest of r.
+func EndTrack(r core.Rect, dir core.Direction) (track, rest core.Rect) {
+	t := min(Thickness, r.Width)
+	track = core.R(r.Right()-t, r.Y, t, r.Height)
+	rest = core.R(r.X, r.Y, r.Width-t, r.Height)
+	if dir.IsRTL() {
+		track.X, rest.X = r.X, r.X+t
+	}
+	return track, rest
+}
+
+// Visible reports whether the content overflows the viewport.
+func (b *Bar) Visible() bool {
+	return b.Content > b.Viewport+0.5
+}
+
+// MaxOffset returns the largest valid scroll offset.
+func (b *Bar) MaxOffset() float32 {
+	return max(0, b.Content-b.Viewport)
+}
+
+// Hovered reports whether the pointer is over the track.
+func (b *Bar) Hovered() bool {
+	return b.hover
+}
+
+// Dragging reports whether the thumb is being dragged.
+func (b *Bar) Dragging() bool {
+	return b.dragging
+}
+
+func (b *Bar) trackLength() float32 {
+	if b.Horizontal {
+		return b.Track.Width
+	}
+	return b.Track.Height
+}
+
+func (b *Bar) thumbLength() float32 {
+	l := b.trackLength()
+	if b.Content <= 0 {
+		return l
+	}
+	return core.Clamp(l*b.Viewport/b.Content, min(minThumb, l), l)
+}
+
+// Thumb returns the thumb rectangle for the given scroll offset.
+func (b *Bar) Thumb(offset float32) core.Rect {
+	l, tl := b.trackLength(), b.thumbLength()
+	var pos float32
+	if m := b.MaxOffset(); m > 0 {
+		pos = core.Clamp(offset/m, 0, 1) * (l - tl)
+	}
+	if b.Horizontal {
+		return core.R(b.Track.X+pos, b.Track.Y, tl, b.Track.Height)
+	}
+	return core.R(b.Track.X, b.Track.Y+pos, b.Track.Width, tl)
+}
+
+// Paint draws the thumb if the content overflows.
+func (b *Bar) Paint(ctx *core.PaintContext, offset float32) {
+	if !b.Visible() {
+		return
+	}
+	th := theme.From(ctx.Context)
+	alpha := float32(0.35)
+	if b.hover || b.dragging {
+		alpha = 0.6
+	}
+	alpha *= 1 - core.Clamp(b.Faded, 0, 1)
+	if alpha <= 0 {
+		return
+	}
+	thumb := b.Thumb(offset).Inset(core.UniformInsets(2))
+	r := min(thumb.Width, thumb.Height) / 2
+	ctx.Canvas.DrawRoundedRect(thumb, r, core.Filled(th.Colors.OnSurfaceVariant.WithAlpha(alpha)))
+}
+
+func (b *Bar) axis(p core.Point) float32 {
+	if b.Horizontal {
+		return p.X - b.Track.X
+	}
+	return p.Y - b.Track.Y
+}
+
+// HandleEvent processes pointer events aimed at the bar and returns the new
+// scroll offset. handled is false for events the bar does not consume.
+// The bar repaints its track when its look changes; the owner repaints or
+// lays itself out again for a new offset.
+func (b *Bar) HandleEvent(ctx *core.Context, owner core.Widget, ev core.Event, offset float32) (newOffset float32, handled bool) {
+	me, ok := ev.(event.MouseEvent)
+	if !ok || !b.Visible() {
+		return offset, false
+	}
+	switch me.Type {
+	case event.MouseDown:
+		if me.Button != event.ButtonLeft || !b.Track.Contains(me.Position) {
+			return offset, false
+		}
+		thumb := b.Thumb(offset)
+		if !thumb.Contains(me.Position) {
+			// Clicking the track pages toward the click.
+			if b.axis(me.Position) < b.axis(thumb.Origin()) {
+				offset -= b.Viewport
+			} else {
+				offset += b.Viewport
+			}
+			return core.Clamp(offset, 0, b.MaxOffset()), true
+		}
+		b.dragging = true
+		b.grabOffset = b.axis(me.Position) - b.axis(thumb.Origin())
+		ctx.CapturePointer(owner)
+		ctx.InvalidateRect(b.Track)
+		return offset, true
+	case event.MouseMove:
+		hover := b.Track.Contains(me.Position)
+		if hover != b.hover {
+			b.hover = hover
+			ctx.InvalidateRect(b.Track)
+		}
+		if !b.dragging {
+			return offset, false
+		}
+		free := b.trackLength() - b.thumbLength()
+		if free <= 0 {
+			return offset, true
+		}
+		pos := b.axis(me.Position) - b.grabOffset
+		return core.Clamp(pos/free, 0, 1) * b.MaxOffset(), true
+	case event.MouseUp:
+		if !b.dragging {
+			return offset, false
+		}
+		b.dragging = false
+		ctx.ReleasePointer()
+		ctx.InvalidateRect(b.Track)
+		return offset, true
+	case event.MouseLeave:
+		if b.hover {
+			b.hover = false
+			ctx.InvalidateRect(b.Track)
+		}
+	}
+	return offset, false
+}
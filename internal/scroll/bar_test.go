@@ -0,0 +1,134 @@
+package scroll
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+)
+
+func newBar() *Bar {
+	return &Bar{Track: core.R(90, 0, 10, 100), Viewport: 100, Content: 400}
+}
+
+func TestBarGeometry(t *testing.T) {
+	tests := []struct {
+		name    string
+		bar     Bar
+		offset  float32
+		visible bool
+		maxOff  float32
+		thumb   core.Rect
+	}{
+		{"top", *newBar(), 0, true, 300, core.R(90, 0, 10, 25)},
+		{"end", *newBar(), 300, true, 300, core.R(90, 75, 10, 25)},
+		{"past the end", *newBar(), 900, true, 300, core.R(90, 75, 10, 25)},
+		{"minimum thumb", Bar{Track: core.R(0, 0, 10, 100), Viewport: 100, Content: 10000}, 0, true, 9900, core.R(0, 0, 10, 24)},
+		{"fits", Bar{Track: core.R(0, 0, 10, 100), Viewport: 100, Content: 100}, 0, false, 0, core.R(0, 0, 10, 100)},
+		{"horizontal", Bar{Horizontal: true, Track: core.R(0, 90, 200, 10), Viewport: 200, Content: 400}, 200, true, 200, core.R(100, 90, 100, 10)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.bar.Visible(); got != tt.visible {
+				t.Errorf("Visible = %v, want %v", got, tt.visible)
+			}
+			if got := tt.bar.MaxOffset(); got != tt.maxOff {
+				t.Errorf("MaxOffset = %v, want %v", got, tt.maxOff)
+			}
+			if got := tt.bar.Thumb(tt.offset); got != tt.thumb {
+				t.Errorf("Thumb(%v) = %v, want %v", tt.offset, got, tt.thumb)
+			}
+		})
+	}
+}
+
+// owner is the widget scrolled by a bar in the tests.
+type owner struct{ core.WidgetBase }
+
+func (*owner) Layout(*core.LayoutContext) core.Size { return core.Size{} }
+func (*owner) Paint(*core.PaintContext)             {}
+
+func mouse(typ event.MouseEventType, x, y float32) event.MouseEvent {
+	return event.MouseEvent{Type: typ, Position: core.Pt(x, y), Button: event.ButtonLeft}
+}
+
+func TestBarTrackClickPages(t *testing.T) {
+	tests := []struct {
+		name    string
+		offset  float32
+		at      float32
+		want    float32
+		handled bool
+	}{
+		{"below the thumb", 0, 80, 100, true},
+		{"above the thumb", 200, 5, 100, true},
+		{"clamped", 250, 95, 300, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newBar()
+			got, handled := b.HandleEvent(core.NewContext(), &owner{}, mouse(event.MouseDown, 95, tt.at), tt.offset)
+			if got != tt.want || handled != tt.handled {
+				t.Errorf("HandleEvent = %v, %v, want %v, %v", got, handled, tt.want, tt.handled)
+			}
+		})
+	}
+}
+
+func TestBarDrag(t *testing.T) {
+	ctx := core.NewContext()
+	b, w := newBar(), &owner{}
+	// Grab the thumb 5px below its top and drag it down by half the free
+	// track.
+	if _, ok := b.HandleEvent(ctx, w, mouse(event.MouseDown, 95, 5), 0); !ok || !b.Dragging() {
+		t.Fatal("pressing the thumb did not start a drag")
+	}
+	if ctx.PointerCapture() != w {
+		t.Error("the owner did not capture the pointer")
+	}
+	off, ok := b.HandleEvent(ctx, w, mouse(event.MouseMove, 95, 5+37.5), 0)
+	if !ok || off != 150 {
+		t.Errorf("drag = %v, %v, want 150, true", off, ok)
+	}
+	if _, ok := b.HandleEvent(ctx, w, mouse(event.MouseUp, 95, 50), off); !ok || b.Dragging() {
+		t.Error("release did not end the drag")
+	}
+	if ctx.PointerCapture() != nil {
+		t.Error("the pointer is still captured")
+	}
+}
+
+func TestBarIgnores(t *testing.T) {
+	ctx := core.NewContext()
+	tests := []struct {
+		name string
+		bar  *Bar
+		ev   core.Event
+	}{
+		{"outside the track", newBar(), mouse(event.MouseDown, 10, 10)},
+		{"right button", newBar(), event.MouseEvent{Type: event.MouseDown, Position: core.Pt(95, 5), Button: event.ButtonRight}},
+		{"content fits", &Bar{Track: core.R(90, 0, 10, 100), Viewport: 100, Content: 50}, mouse(event.MouseDown, 95, 5)},
+		{"not a mouse event", newBar(), event.ScrollEvent{Position: core.Pt(95, 5)}},
+		{"move without a drag", newBar(), mouse(event.MouseMove, 95, 5)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if off, ok := tt.bar.HandleEvent(ctx, &owner{}, tt.ev, 10); ok || off != 10 {
+				t.Errorf("HandleEvent = %v, %v, want 10, false", off, ok)
+			}
+		})
+	}
+}
+
+func TestBarHover(t *testing.T) {
+	ctx := core.NewContext()
+	b := newBar()
+	b.HandleEvent(ctx, &owner{}, mouse(event.MouseMove, 95, 50), 0)
+	if !b.Hovered() {
+		t.Error("not hovered over the track")
+	}
+	b.HandleEvent(ctx, &owner{}, event.MouseEvent{Type: event.MouseLeave}, 0)
+	if b.Hovered() {
+		t.Error("hovered after the pointer left")
+	}
+}
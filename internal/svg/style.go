@@ -0,0 +1,393 @@
+package svg
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/gogpu/ui/core"
+)
+
+// paint is a fill or stroke: none, a color, the current color or a
+// reference to a gradient.
+type paint struct {
+	none    bool
+	current bool
+	color   core.Color
+	ref     string
+}
+
+// style is the inherited state of the presentation properties.
+type style struct {
+	fill, stroke  paint
+	color         paint
+	strokeWidth   float32
+	fillOpacity   float32
+	strokeOpacity float32
+	opacity       float32
+	fillRule      core.FillRule
+	cap           core.LineCap
+	join          core.LineJoin
+	hidden        bool
+
+	stopColor   paint
+	stopOpacity float32
+}
+
+func defaultStyle() style {
+	return style{
+		fill:          paint{color: core.Black},
+		stroke:        paint{none: true},
+		color:         paint{current: true}, // the color the document is drawn with
+		strokeWidth:   1,
+		fillOpacity:   1,
+		strokeOpacity: 1,
+		opacity:       1,
+		stopColor:     paint{color: core.Black},
+		stopOpacity:   1,
+	}
+}
+
+// apply returns st with the presentation attributes and the inline style
+// of an element applied; the style attribute takes precedence.
+func (st style) apply(attrs map[string]string) style {
+	// The stop properties are not inherited.
+	st.stopColor, st.stopOpacity = paint{color: core.Black}, 1
+	for _, name := range properties {
+		if v, ok := attrs[name]; ok {
+			st.set(name, v)
+		}
+	}
+	for decl := range strings.SplitSeq(attrs["style"], ";") {
+		name, v, ok := strings.Cut(decl, ":")
+		if ok {
+			st.set(strings.TrimSpace(name), v)
+		}
+	}
+	return st
+}
+
+// properties are the presentation attributes understood, in the order they
+// are applied so that color is known before currentColor is resolved.
+var properties = []string{
+	"color", "fill", "stroke", "stroke-width", "opacity", "fill-opacity", "stroke-opacity",
+	"fill-rule", "stroke-linecap", "stroke-linejoin", "visibility", "display",
+	"stop-color", "stop-opacity",
+}
+
+func (st *style) set(name, v string) {
+	v = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(v), "!important"))
+	if v == "inherit" || v == "" {
+		return
+	}
+	switch name {
+	case "color":
+		if p, ok := parsePaint(v); ok && !p.current && p.ref == "" {
+			st.color = p
+		}
+	case "fill":
+		if p, ok := parsePaint(v); ok {
+			st.fill = p
+		}
+	case "stroke":
+		if p, ok := parsePaint(v); ok {
+			st.stroke = p
+		}
+	case "stop-color":
+		if p, ok := parsePaint(v); ok {
+			if p.current {
+				p = st.color
+			}
+			st.stopColor = p
+		}
+	case "stroke-width":
+		if w, ok := length(v, 0); ok && w >= 0 {
+			st.strokeWidth = w
+		}
+	case "opacity":
+		// Opacity applies to the element as a whole, so it compounds down
+		// the tree rather than being inherited.
+		st.opacity *= opacity(v)
+	case "fill-opacity":
+		st.fillOpacity = opacity(v)
+	case "stroke-opacity":
+		st.strokeOpacity = opacity(v)
+	case "stop-opacity":
+		st.stopOpacity = opacity(v)
+	case "fill-rule":
+		st.fillRule = core.FillNonZero
+		if v == "evenodd" {
+			st.fillRule = core.FillEvenOdd
+		}
+	case "stroke-linecap":
+		switch v {
+		case "round":
+			st.cap = core.CapRound
+		case "square":
+			st.cap = core.CapSquare
+		default:
+			st.cap = core.CapButt
+		}
+	case "stroke-linejoin":
+		switch v {
+		case "round":
+			st.join = core.JoinRound
+		case "bevel":
+			st.join = core.JoinBevel
+		default:
+			st.join = core.JoinMiter
+		}
+	case "display":
+		st.hidden = st.hidden || v == "none"
+	case "visibility":
+		st.hidden = v == "hidden" || v == "collapse"
+	}
+}
+
+func opacity(v string) float32 {
+	pct := strings.HasSuffix(v, "%")
+	f, err := strconv.ParseFloat(strings.TrimSuffix(v, "%"), 32)
+	if err != nil {
+		return 1
+	}
+	if pct {
+		f /= 100
+	}
+	return float32(math.Max(0, math.Min(1, f)))
+}
+
+func parsePaint(v string) (paint, bool) {
+	switch {
+	case v == "none" || v == "transparent":
+		return paint{none: true}, true
+	case v == "currentColor" || v == "currentcolor":
+		return paint{current: true}, true
+	case strings.HasPrefix(v, "url("):
+		ref, _, _ := strings.Cut(v[4:], ")")
+		ref = strings.Trim(strings.TrimSpace(ref), `'"`)
+		return paint{ref: strings.TrimPrefix(ref, "#")}, true
+	}
+	c, ok := parseColor(v)
+	return paint{color: c}, ok
+}
+
+// parseColor parses a hex, rgb() or rgba() color or a color keyword.
+func parseColor(v string) (core.Color, bool) {
+	v = strings.ToLower(v)
+	if strings.HasPrefix(v, "#") {
+		h := v[1:]
+		n, err := strconv.ParseUint(h, 16, 32)
+		if err != nil {
+			return core.Color{}, false
+		}
+		nib := func(shift uint) uint8 { return uint8(n>>shift&0xf) * 0x11 }
+		switch len(h) {
+		case 3:
+			return core.RGB(nib(8), nib(4), nib(0)), true
+		case 4:
+			return core.RGBA(nib(12), nib(8), nib(4), nib(0)), true
+		case 6:
+			return core.Hex(uint32(n)), true
+		case 8:
+			return core.RGBA(uint8(n>>24), uint8(n>>16), uint8(n>>8), uint8(n)), true
+		}
+		return core.Color{}, false
+	}
+	if args, ok := strings.CutPrefix(v, "rgb"); ok {
+		args = strings.TrimPrefix(args, "a")
+		if !strings.HasPrefix(args, "(") || !strings.HasSuffix(args, ")") {
+			return core.Color{}, false
+		}
+		fields := strings.FieldsFunc(args[1:len(args)-1], func(r rune) bool {
+			return r == ',' || r == ' ' || r == '/'
+		})
+		if len(fields) < 3 {
+			return core.Color{}, false
+		}
+		var c [3]float32
+		for i := range c {
+			f := fields[i]
+			x, err := strconv.ParseFloat(strings.TrimSuffix(f, "%"), 32)
+			if err != nil {
+				return core.Color{}, false
+			}
+			if strings.HasSuffix(f, "%") {
+				x = x * 255 / 100
+			}
+			c[i] = float32(math.Max(0, math.Min(255, x))) / 255
+		}
+		a := float32(1)
+		if len(fields) > 3 {
+			a = opacity(fields[3])
+		}
+		return core.Color{R: c[0], G: c[1], B: c[2], A: a}, true
+	}
+	if hex, ok := colorNames[v]; ok {
+		return core.Hex(hex), true
+	}
+	return core.Color{}, false
+}
+
+// colorNames are the common CSS color keywords.
+var colorNames = map[string]uint32{
+	"black": 0x000000, "white": 0xffffff, "red": 0xff0000, "green": 0x008000,
+	"blue": 0x0000ff, "yellow": 0xffff00, "cyan": 0x00ffff, "aqua": 0x00ffff,
+	"magenta": 0xff00ff, "fuchsia": 0xff00ff, "gray": 0x808080, "grey": 0x808080,
+	"silver": 0xc0c0c0, "maroon": 0x800000, "olive": 0x808000, "lime": 0x00ff00,
+	"teal": 0x008080, "navy": 0x000080, "purple": 0x800080, "orange": 0xffa500,
+	"pink": 0xffc0cb, "brown": 0xa52a2a, "gold": 0xffd700, "indigo": 0x4b0082,
+	"violet": 0xee82ee, "coral": 0xff7f50, "salmon": 0xfa8072, "tomato": 0xff6347,
+	"crimson": 0xdc143c, "orchid": 0xda70d6, "khaki": 0xf0e68c, "beige": 0xf5f5dc,
+	"tan": 0xd2b48c, "chocolate": 0xd2691e, "firebrick": 0xb22222, "darkred": 0x8b0000,
+	"darkgreen": 0x006400, "darkblue": 0x00008b, "darkgray": 0xa9a9a9, "darkgrey": 0xa9a9a9,
+	"lightgray": 0xd3d3d3, "lightgrey": 0xd3d3d3, "dimgray": 0x696969, "dimgrey": 0x696969,
+	"lightblue": 0xadd8e6, "skyblue": 0x87ceeb, "steelblue": 0x4682b4, "royalblue": 0x4169e1,
+	"dodgerblue": 0x1e90ff, "deepskyblue": 0x00bfff, "turquoise": 0x40e0d0, "seagreen": 0x2e8b57,
+	"forestgreen": 0x228b22, "limegreen": 0x32cd32, "lightgreen": 0x90ee90, "darkorange": 0xff8c00,
+	"orangered": 0xff4500, "hotpink": 0xff69b4, "deeppink": 0xff1493, "slategray": 0x708090,
+	"slategrey": 0x708090, "whitesmoke": 0xf5f5f5, "gainsboro": 0xdcdcdc, "ivory": 0xfffff0,
+}
+
+// length parses a length in user units. Percentages are of ref.
+func length(v string, ref float32) (float32, bool) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0, false
+	}
+	unit := float32(1)
+	for _, u := range units {
+		if s, ok := strings.CutSuffix(v, u.suffix); ok {
+			v, unit = s, u.scale
+			if u.suffix == "%" {
+				unit = ref / 100
+			}
+			break
+		}
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(v), 32)
+	if err != nil {
+		return 0, false
+	}
+	return float32(f) * unit, true
+}
+
+var units = []struct {
+	suffix string
+	scale  float32
+}{
+	{"px", 1}, {"pt", 4.0 / 3}, {"pc", 16}, {"mm", 96 / 25.4}, {"cm", 96 / 2.54},
+	{"in", 96}, {"em", 16}, {"%", 0},
+}
+
+// numbers parses a list of numbers separated by whitespace or commas.
+func numbers(v string) []float32 {
+	var out []float32
+	for f := range strings.FieldsFuncSeq(v, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+	}) {
+		x, err := strconv.ParseFloat(f, 32)
+		if err != nil {
+			break
+		}
+		out = append(out, float32(x))
+	}
+	return out
+}
+
+// matrix is an affine transform [a b c d e f], mapping (x, y) to
+// (ax + cy + e, bx + dy + f).
+type matrix [6]float32
+
+var identity = matrix{1, 0, 0, 1, 0, 0}
+
+func translate(x, y float32) matrix {
+	return matrix{1, 0, 0, 1, x, y}
+}
+
+// mul returns the transform applying n, then m.
+func (m matrix) mul(n matrix) matrix {
+	return matrix{
+		m[0]*n[0] + m[2]*n[1],
+		m[1]*n[0] + m[3]*n[1],
+		m[0]*n[2] + m[2]*n[3],
+		m[1]*n[2] + m[3]*n[3],
+		m[0]*n[4] + m[2]*n[5] + m[4],
+		m[1]*n[4] + m[3]*n[5] + m[5],
+	}
+}
+
+func (m matrix) apply(p core.Point) core.Point {
+	return core.Pt(m[0]*p.X+m[2]*p.Y+m[4], m[1]*p.X+m[3]*p.Y+m[5])
+}
+
+// scale returns the factor by which m scales lengths on average, used for
+// stroke widths.
+func (m matrix) scale() float32 {
+	return float32(math.Sqrt(math.Abs(float64(m[0]*m[3] - m[1]*m[2]))))
+}
+
+// parseTransform parses a transform list. It stops at the first malformed
+// function, as renderers do.
+func parseTransform(v string) matrix {
+	m := identity
+	for {
+		v = strings.TrimLeft(v, " \t\r\n,")
+		name, rest, ok := strings.Cut(v, "(")
+		if !ok {
+			return m
+		}
+		args, after, ok := strings.Cut(rest, ")")
+		if !ok {
+			return m
+		}
+		v = after
+		a := numbers(args)
+		var t matrix
+		switch strings.TrimSpace(name) {
+		case "matrix":
+			if len(a) != 6 {
+				return m
+			}
+			t = matrix(a)
+		case "translate":
+			if len(a) == 0 {
+				return m
+			}
+			t = translate(a[0], at(a, 1, 0))
+		case "scale":
+			if len(a) == 0 {
+				return m
+			}
+			t = matrix{a[0], 0, 0, at(a, 1, a[0]), 0, 0}
+		case "rotate":
+			if len(a) == 0 {
+				return m
+			}
+			r := float64(a[0]) * math.Pi / 180
+			cos, sin := float32(math.Cos(r)), float32(math.Sin(r))
+			cx, cy := at(a, 1, 0), at(a, 2, 0)
+			t = translate(cx, cy).mul(matrix{cos, sin, -sin, cos, 0, 0}).mul(translate(-cx, -cy))
+		case "skewX":
+			if len(a) == 0 {
+				return m
+			}
+			t = matrix{1, 0, float32(math.Tan(float64(a[0]) * math.Pi / 180)), 1, 0, 0}
+		case "skewY":
+			if len(a) == 0 {
+				return m
+			}
+			t = matrix{1, float32(math.Tan(float64(a[0]) * math.Pi / 180)), 0, 1, 0, 0}
+		default:
+			return m
+		}
+		m = m.mul(t)
+	}
+}
+
+// at returns a[i], or def if a is shorter.
+func at(a []float32, i int, def float32) float32 {
+	if i < len(a) {
+		return a[i]
+	}
+	return def
+}
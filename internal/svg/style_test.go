@@ -0,0 +1,184 @@
+package svg
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+)
+
+func nearly(a, b float32) bool {
+	return math.Abs(float64(a-b)) < 1e-4
+}
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		v    string
+		want core.Color
+		ok   bool
+	}{
+		{"#f80", core.RGB(0xff, 0x88, 0x00), true},
+		{"#F808", core.RGBA(0xff, 0x88, 0x00, 0x88), true},
+		{"#12ab34", core.Hex(0x12ab34), true},
+		{"#12ab3480", core.RGBA(0x12, 0xab, 0x34, 0x80), true},
+		{"#12ab3", core.Color{}, false},
+		{"#xyz", core.Color{}, false},
+		{"rgb(255, 0, 51)", core.RGB(255, 0, 51), true},
+		{"rgb(100% 0% 20%)", core.RGB(255, 0, 51), true},
+		{"rgba(255,0,51,0.5)", core.RGB(255, 0, 51).WithAlpha(0.5), true},
+		{"rgb(255 0 51 / 50%)", core.RGB(255, 0, 51).WithAlpha(0.5), true},
+		{"rgb(300, -5, 0)", core.RGB(255, 0, 0), true},
+		{"rgb(1, 2)", core.Color{}, false},
+		{"rgb(a, b, c)", core.Color{}, false},
+		{"rgb 1 2 3", core.Color{}, false},
+		{"Navy", core.Hex(0x000080), true},
+		{"grey", core.Hex(0x808080), true},
+		{"bluish", core.Color{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseColor(tt.v)
+		if ok != tt.ok || ok && !nearly(got.R, tt.want.R) || !nearly(got.G, tt.want.G) || !nearly(got.B, tt.want.B) || !nearly(got.A, tt.want.A) {
+			t.Errorf("parseColor(%q) = %v, %v, want %v, %v", tt.v, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParsePaint(t *testing.T) {
+	tests := []struct {
+		v    string
+		want paint
+		ok   bool
+	}{
+		{"none", paint{none: true}, true},
+		{"transparent", paint{none: true}, true},
+		{"currentColor", paint{current: true}, true},
+		{"url(#g)", paint{ref: "g"}, true},
+		{"url( '#g' ) red", paint{ref: "g"}, true},
+		{"red", paint{color: core.Hex(0xff0000)}, true},
+		{"nope", paint{}, false},
+	}
+	for _, tt := range tests {
+		if got, ok := parsePaint(tt.v); got != tt.want || ok != tt.ok {
+			t.Errorf("parsePaint(%q) = %+v, %v, want %+v, %v", tt.v, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestLength(t *testing.T) {
+	tests := []struct {
+		v    string
+		ref  float32
+		want float32
+		ok   bool
+	}{
+		{"12", 0, 12, true},
+		{" 12px ", 0, 12, true},
+		{"3pt", 0, 4, true},
+		{"1in", 0, 96, true},
+		{"2.54cm", 0, 96, true},
+		{"25.4mm", 0, 96, true},
+		{"1pc", 0, 16, true},
+		{"2em", 0, 32, true},
+		{"50%", 200, 100, true},
+		{"", 0, 0, false},
+		{"wide", 0, 0, false},
+	}
+	for _, tt := range tests {
+		if got, ok := length(tt.v, tt.ref); !nearly(got, tt.want) || ok != tt.ok {
+			t.Errorf("length(%q, %v) = %v, %v, want %v, %v", tt.v, tt.ref, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestNumbersAndOpacity(t *testing.T) {
+	if got := numbers("1, 2 3\n-4.5,x 6"); len(got) != 4 || got[3] != -4.5 {
+		t.Errorf("numbers = %v, want the four before the bad one", got)
+	}
+	for v, want := range map[string]float32{"0.5": 0.5, "50%": 0.5, "2": 1, "-1": 0, "half": 1} {
+		if got := opacity(v); got != want {
+			t.Errorf("opacity(%q) = %v, want %v", v, got, want)
+		}
+	}
+}
+
+func TestParseTransform(t *testing.T) {
+	tests := []struct {
+		v    string
+		p    core.Point
+		want core.Point
+	}{
+		{"translate(10 20)", core.Pt(1, 1), core.Pt(11, 21)},
+		{"translate(10)", core.Pt(1, 1), core.Pt(11, 1)},
+		{"scale(2)", core.Pt(1, 3), core.Pt(2, 6)},
+		{"scale(2, 3)", core.Pt(1, 1), core.Pt(2, 3)},
+		{"rotate(90)", core.Pt(1, 0), core.Pt(0, 1)},
+		{"rotate(180 5 5)", core.Pt(0, 0), core.Pt(10, 10)},
+		{"skewX(45)", core.Pt(0, 1), core.Pt(1, 1)},
+		{"skewY(45)", core.Pt(1, 0), core.Pt(1, 1)},
+		{"matrix(1 0 0 1 5 6)", core.Pt(0, 0), core.Pt(5, 6)},
+		// The functions apply right to left.
+		{"translate(10, 0) scale(2)", core.Pt(1, 1), core.Pt(12, 2)},
+		{"scale(2),translate(10, 0)", core.Pt(1, 1), core.Pt(22, 2)},
+		// Parsing stops at a bad function.
+		{"translate(1) bogus(2) translate(5)", core.Pt(0, 0), core.Pt(1, 0)},
+		{"translate(1) matrix(1 2)", core.Pt(0, 0), core.Pt(1, 0)},
+		{"translate(1) scale(", core.Pt(0, 0), core.Pt(1, 0)},
+		{"rotate()", core.Pt(3, 4), core.Pt(3, 4)},
+	}
+	for _, tt := range tests {
+		got := parseTransform(tt.v).apply(tt.p)
+		if !nearly(got.X, tt.want.X) || !nearly(got.Y, tt.want.Y) {
+			t.Errorf("parseTransform(%q) takes %v to %v, want %v", tt.v, tt.p, got, tt.want)
+		}
+	}
+	if s := parseTransform("scale(2, 8)").scale(); s != 4 {
+		t.Errorf("the stroke scale of scale(2, 8) = %v, want 4", s)
+	}
+}
+
+func TestStyleApply(t *testing.T) {
+	st := defaultStyle().apply(map[string]string{
+		"fill":            "red",
+		"stroke":          "blue",
+		"stroke-width":    "3",
+		"opacity":         "0.5",
+		"fill-rule":       "evenodd",
+		"stroke-linecap":  "round",
+		"stroke-linejoin": "bevel",
+		"style":           "fill: green !important; stroke-width: inherit; bogus",
+	})
+	if st.fill.color != core.Hex(0x008000) || st.stroke.color != core.Hex(0x0000ff) || st.strokeWidth != 3 {
+		t.Errorf("fill %v, stroke %v %v", st.fill.color, st.stroke.color, st.strokeWidth)
+	}
+	if st.fillRule != core.FillEvenOdd || st.cap != core.CapRound || st.join != core.JoinBevel {
+		t.Errorf("rule %v, cap %v, join %v", st.fillRule, st.cap, st.join)
+	}
+	child := st.apply(map[string]string{"opacity": "0.5", "stroke-width": "-1", "stroke-linecap": "square", "stroke-linejoin": "miter"})
+	if child.opacity != 0.25 || child.strokeWidth != 3 || child.cap != core.CapSquare || child.join != core.JoinMiter {
+		t.Errorf("child opacity %v, stroke width %v, cap %v, join %v", child.opacity, child.strokeWidth, child.cap, child.join)
+	}
+
+	tests := []struct {
+		attrs  map[string]string
+		hidden bool
+	}{
+		{map[string]string{"display": "none"}, true},
+		{map[string]string{"visibility": "hidden"}, true},
+		{map[string]string{"visibility": "collapse"}, true},
+		{map[string]string{"style": "display:inline"}, false},
+	}
+	for _, tt := range tests {
+		if got := defaultStyle().apply(tt.attrs).hidden; got != tt.hidden {
+			t.Errorf("%v hidden %v, want %v", tt.attrs, got, tt.hidden)
+		}
+	}
+
+	// currentColor is never the color itself, and stops take it.
+	st = defaultStyle().apply(map[string]string{"color": "red"}).apply(map[string]string{"color": "currentColor", "stop-color": "currentColor"})
+	if st.color.color != core.Hex(0xff0000) || st.stopColor.color != core.Hex(0xff0000) {
+		t.Errorf("color %v, stop color %v", st.color, st.stopColor)
+	}
+	if st.apply(nil).stopColor.color != core.Black {
+		t.Error("the stop color is inherited")
+	}
+}
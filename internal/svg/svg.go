@@ -0,0 +1,347 @@
+// Package svg parses SVG documents into filled and stroked paths.
+//
+// It covers the static subset icons and illustrations use: the basic
+// shapes and paths, groups, transforms, <use> references, presentation
+// attributes and inline styles, opacity and currentColor. Gradients are
+// drawn in the color of their first stop; text, filters, masks, clipping
+// and style sheets are ignored.
+package svg
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/gogpu/ui/core"
+)
+
+// Shape is a path with the paint it is drawn with. A paint that is the
+// current color draws in the color the document is drawn with, its alpha
+// multiplied by the alpha of Fill or Stroke.
+type Shape struct {
+	Path          *core.Path
+	Fill, Stroke  core.Color
+	FillCurrent   bool
+	StrokeCurrent bool
+	StrokeWidth   float32
+	FillRule      core.FillRule
+	Cap           core.LineCap
+	Join          core.LineJoin
+}
+
+// Document is a parsed SVG image: shapes in the coordinates of the view
+// box, and the size the document asks to be drawn at.
+type Document struct {
+	ViewBox       core.Rect
+	Width, Height float32
+	Shapes        []Shape
+}
+
+// Parse parses an SVG document.
+func Parse(data []byte) (*Document, error) {
+	root, err := parseTree(data)
+	if err != nil {
+		return nil, err
+	}
+	if root.name != "svg" {
+		return nil, errors.New("svg: the root element is not <svg>")
+	}
+	d := &Document{}
+	vb := numbers(root.attrs["viewBox"])
+	w, wok := length(root.attrs["width"], 0)
+	h, hok := length(root.attrs["height"], 0)
+	switch {
+	case len(vb) == 4 && vb[2] > 0 && vb[3] > 0:
+		d.ViewBox = core.R(vb[0], vb[1], vb[2], vb[3])
+	case wok && hok:
+		d.ViewBox = core.R(0, 0, w, h)
+	default:
+		d.ViewBox = core.R(0, 0, 300, 150) // the default size of an SVG
+	}
+	// A missing or relative dimension follows the view box's aspect.
+	switch {
+	case wok && hok:
+	case wok:
+		h = w * d.ViewBox.Height / d.ViewBox.Width
+	case hok:
+		w = h * d.ViewBox.Width / d.ViewBox.Height
+	default:
+		w, h = d.ViewBox.Width, d.ViewBox.Height
+	}
+	d.Width, d.Height = w, h
+
+	r := &renderer{doc: d, ids: make(map[string]*node)}
+	r.index(root)
+	r.children(root, identity, defaultStyle(), 0)
+	return d, nil
+}
+
+// Scaled returns the shapes mapped from the view box onto a rectangle of
+// the given size at the origin, stretching them if the aspect differs.
+func (d *Document) Scaled(size core.Size) []Shape {
+	sx, sy := size.Width/d.ViewBox.Width, size.Height/d.ViewBox.Height
+	scale := float32(math.Sqrt(float64(sx * sy)))
+	out := make([]Shape, len(d.Shapes))
+	for i, s := range d.Shapes {
+		s.Path = s.Path.Transform(func(p core.Point) core.Point {
+			return core.Pt((p.X-d.ViewBox.X)*sx, (p.Y-d.ViewBox.Y)*sy)
+		})
+		s.StrokeWidth *= scale
+		out[i] = s
+	}
+	return out
+}
+
+// node is an element of the parsed XML tree.
+type node struct {
+	name     string
+	attrs    map[string]string
+	children []*node
+}
+
+func parseTree(data []byte) (*node, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.Strict = false
+	dec.Entity = xml.HTMLEntity
+	var stack []*node
+	var root *node
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := &node{name: t.Name.Local, attrs: make(map[string]string, len(t.Attr))}
+			for _, a := range t.Attr {
+				// xlink:href and href are the same reference.
+				n.attrs[a.Name.Local] = a.Value
+			}
+			if len(stack) > 0 {
+				p := stack[len(stack)-1]
+				p.children = append(p.children, n)
+			} else if root == nil {
+				root = n
+			}
+			stack = append(stack, n)
+		case xml.EndElement:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+	if root == nil {
+		return nil, errors.New("svg: no elements")
+	}
+	return root, nil
+}
+
+type renderer struct {
+	doc *Document
+	ids map[string]*node
+}
+
+// index records the elements with an id, for <use> and paint references.
+func (r *renderer) index(n *node) {
+	if id := n.attrs["id"]; id != "" {
+		r.ids[id] = n
+	}
+	for _, c := range n.children {
+		r.index(c)
+	}
+}
+
+// maxUseDepth bounds nested <use> references, which may be cyclic.
+const maxUseDepth = 16
+
+func (r *renderer) children(n *node, m matrix, st style, depth int) {
+	for _, c := range n.children {
+		r.element(c, m, st, depth)
+	}
+}
+
+func (r *renderer) element(n *node, m matrix, st style, depth int) {
+	switch n.name {
+	case "defs", "symbol", "clipPath", "mask", "linearGradient", "radialGradient", "pattern",
+		"marker", "style", "title", "desc", "metadata", "text", "filter", "script":
+		return
+	}
+	st = st.apply(n.attrs)
+	if st.hidden {
+		return
+	}
+	if t, ok := n.attrs["transform"]; ok {
+		m = m.mul(parseTransform(t))
+	}
+	switch n.name {
+	case "g", "a", "switch":
+		r.children(n, m, st, depth)
+	case "svg":
+		x, _ := length(n.attrs["x"], 0)
+		y, _ := length(n.attrs["y"], 0)
+		r.children(n, m.mul(translate(x, y)), st, depth)
+	case "use":
+		href := strings.TrimPrefix(n.attrs["href"], "#")
+		target, ok := r.ids[href]
+		if !ok || depth >= maxUseDepth {
+			return
+		}
+		x, _ := length(n.attrs["x"], 0)
+		y, _ := length(n.attrs["y"], 0)
+		m = m.mul(translate(x, y))
+		if target.name == "symbol" {
+			r.children(target, m, st, depth+1)
+		} else {
+			r.element(target, m, st, depth+1)
+		}
+	default:
+		if path := r.shapePath(n); !path.IsEmpty() {
+			r.add(path, m, st, n.name == "line" || n.name == "polyline")
+		}
+	}
+}
+
+// shapePath returns the outline of a basic shape or path.
+func (r *renderer) shapePath(n *node) *core.Path {
+	num := func(name string) float32 {
+		v, _ := length(n.attrs[name], r.doc.ViewBox.Width)
+		return v
+	}
+	switch n.name {
+	case "path":
+		p, _ := core.ParseSVGPath(n.attrs["d"])
+		return p
+	case "rect":
+		w, h := num("width"), num("height")
+		if w <= 0 || h <= 0 {
+			return nil
+		}
+		rx, rxok := length(n.attrs["rx"], w)
+		ry, ryok := length(n.attrs["ry"], h)
+		switch {
+		case rxok && !ryok:
+			ry = rx
+		case ryok && !rxok:
+			rx = ry
+		}
+		return roundedRect(core.R(num("x"), num("y"), w, h), min(rx, w/2), min(ry, h/2))
+	case "circle":
+		cr := num("r")
+		if cr <= 0 {
+			return nil
+		}
+		return core.NewPath().AddEllipse(core.R(num("cx")-cr, num("cy")-cr, 2*cr, 2*cr))
+	case "ellipse":
+		rx, ry := num("rx"), num("ry")
+		if rx <= 0 || ry <= 0 {
+			return nil
+		}
+		return core.NewPath().AddEllipse(core.R(num("cx")-rx, num("cy")-ry, 2*rx, 2*ry))
+	case "line":
+		return core.NewPath().MoveTo(core.Pt(num("x1"), num("y1"))).LineTo(core.Pt(num("x2"), num("y2")))
+	case "polyline", "polygon":
+		v := numbers(n.attrs["points"])
+		if len(v) < 4 {
+			return nil
+		}
+		p := core.NewPath().MoveTo(core.Pt(v[0], v[1]))
+		for i := 2; i+1 < len(v); i += 2 {
+			p.LineTo(core.Pt(v[i], v[i+1]))
+		}
+		if n.name == "polygon" {
+			p.Close()
+		}
+		return p
+	}
+	return nil
+}
+
+// roundedRect returns a rectangle with elliptical corners.
+func roundedRect(r core.Rect, rx, ry float32) *core.Path {
+	if rx <= 0 || ry <= 0 {
+		return core.NewPath().AddRect(r)
+	}
+	const kappa = 0.5522848
+	kx, ky := rx*kappa, ry*kappa
+	x0, y0, x1, y1 := r.X, r.Y, r.Right(), r.Bottom()
+	return core.NewPath().MoveTo(core.Pt(x0+rx, y0)).
+		LineTo(core.Pt(x1-rx, y0)).
+		CubicTo(core.Pt(x1-rx+kx, y0), core.Pt(x1, y0+ry-ky), core.Pt(x1, y0+ry)).
+		LineTo(core.Pt(x1, y1-ry)).
+		CubicTo(core.Pt(x1, y1-ry+ky), core.Pt(x1-rx+kx, y1), core.Pt(x1-rx, y1)).
+		LineTo(core.Pt(x0+rx, y1)).
+		CubicTo(core.Pt(x0+rx-kx, y1), core.Pt(x0, y1-ry+ky), core.Pt(x0, y1-ry)).
+		LineTo(core.Pt(x0, y0+ry)).
+		CubicTo(core.Pt(x0, y0+ry-ky), core.Pt(x0+rx-kx, y0), core.Pt(x0+rx, y0)).
+		Close()
+}
+
+// add appends a shape drawn with st. Open shapes such as lines are never
+// filled.
+func (r *renderer) add(path *core.Path, m matrix, st style, open bool) {
+	s := Shape{
+		Path:     path.Transform(m.apply),
+		FillRule: st.fillRule,
+		Cap:      st.cap,
+		Join:     st.join,
+	}
+	alpha := st.opacity
+	if !open {
+		s.Fill, s.FillCurrent = r.paint(st.fill, st.color, alpha*st.fillOpacity)
+	}
+	s.Stroke, s.StrokeCurrent = r.paint(st.stroke, st.color, alpha*st.strokeOpacity)
+	if s.Stroke.A > 0 || s.StrokeCurrent {
+		s.StrokeWidth = st.strokeWidth * m.scale()
+	}
+	if s.Fill.A == 0 && !s.FillCurrent && (s.StrokeWidth <= 0 || s.Stroke.A == 0 && !s.StrokeCurrent) {
+		return
+	}
+	r.doc.Shapes = append(r.doc.Shapes, s)
+}
+
+// paint resolves p to a color, or to the current color with alpha a.
+func (r *renderer) paint(p paint, color paint, a float32) (core.Color, bool) {
+	if p.ref != "" {
+		p = r.gradientPaint(p.ref)
+	}
+	if p.current {
+		p = color
+	}
+	switch {
+	case p.none:
+		return core.Color{}, false
+	case p.current:
+		return core.Color{A: a}, true
+	}
+	c := p.color
+	c.A *= a
+	return c, false
+}
+
+// gradientPaint returns the color of the first stop of the gradient with
+// the given id, following href chains to the gradient defining the stops.
+func (r *renderer) gradientPaint(id string) paint {
+	for range maxUseDepth {
+		g, ok := r.ids[id]
+		if !ok {
+			break
+		}
+		for _, c := range g.children {
+			if c.name != "stop" {
+				continue
+			}
+			st := defaultStyle().apply(c.attrs)
+			color := st.stopColor
+			color.color.A *= st.stopOpacity
+			return color
+		}
+		id = strings.TrimPrefix(g.attrs["href"], "#")
+	}
+	return paint{none: true}
+}
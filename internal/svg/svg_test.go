@@ -0,0 +1,208 @@
+package svg
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+)
+
+func parse(t *testing.T, doc string) *Document {
+	t.Helper()
+	d, err := Parse([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+// shapes parses the elements of body in a 100 by 100 document.
+func shapes(t *testing.T, body string) []Shape {
+	t.Helper()
+	return parse(t, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 100 100">`+body+`</svg>`).Shapes
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		attrs   string
+		viewBox core.Rect
+		w, h    float32
+	}{
+		{`viewBox="0 0 24 12"`, core.R(0, 0, 24, 12), 24, 12},
+		{`viewBox="-5,-5,10,10" width="40" height="30"`, core.R(-5, -5, 10, 10), 40, 30},
+		{`viewBox="0 0 24 12" width="48"`, core.R(0, 0, 24, 12), 48, 24},
+		{`viewBox="0 0 24 12" height="6"`, core.R(0, 0, 24, 12), 12, 6},
+		{`width="1in" height="48px"`, core.R(0, 0, 96, 48), 96, 48},
+		{`viewBox="0 0 0 10"`, core.R(0, 0, 300, 150), 300, 150},
+		{``, core.R(0, 0, 300, 150), 300, 150},
+	}
+	for _, tt := range tests {
+		d := parse(t, "<svg "+tt.attrs+"/>")
+		if d.ViewBox != tt.viewBox || d.Width != tt.w || d.Height != tt.h {
+			t.Errorf("<svg %s> views %v at %v by %v, want %v at %v by %v", tt.attrs, d.ViewBox, d.Width, d.Height, tt.viewBox, tt.w, tt.h)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, doc := range []string{"", "just text", "<html></html>", "<svg><g></svg"} {
+		if _, err := Parse([]byte(doc)); err == nil {
+			t.Errorf("Parse(%q) succeeded", doc)
+		}
+	}
+	// HTML entities and unclosed elements do not stop a document.
+	if s := shapes(t, `<title>a&nbsp;b</title><g><rect width="1" height="1"/>`); len(s) != 1 {
+		t.Errorf("%d shapes in a sloppy document", len(s))
+	}
+}
+
+func TestShapes(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		bounds core.Rect
+		verbs  int
+	}{
+		{"rect", `<rect x="10" y="20" width="30" height="40"/>`, core.R(10, 20, 30, 40), 5},
+		{"rounded rect", `<rect width="30" height="40" rx="5"/>`, core.R(0, 0, 30, 40), 10},
+		{"rounded rect by ry", `<rect width="30" height="40" ry="50"/>`, core.R(0, 0, 30, 40), 10},
+		{"percent rect", `<rect width="50%" height="10"/>`, core.R(0, 0, 50, 10), 5},
+		{"circle", `<circle cx="50" cy="50" r="10"/>`, core.R(40, 40, 20, 20), 6},
+		{"ellipse", `<ellipse cx="50" cy="50" rx="20" ry="10"/>`, core.R(30, 40, 40, 20), 6},
+		{"polygon", `<polygon points="0,0 10,0 10,10"/>`, core.R(0, 0, 10, 10), 4},
+		{"path", `<path d="M0 0 H10 V10 Z"/>`, core.R(0, 0, 10, 10), 4},
+		{"broken path", `<path d="M0 0 H10 V10 Q"/>`, core.R(0, 0, 10, 10), 3},
+	}
+	for _, tt := range tests {
+		s := shapes(t, tt.body)
+		if len(s) != 1 {
+			t.Errorf("%s: %d shapes", tt.name, len(s))
+			continue
+		}
+		if got := s[0].Path.Bounds(); got != tt.bounds || len(s[0].Path.Segments) != tt.verbs {
+			t.Errorf("%s: %v in %d segments, want %v in %d", tt.name, got, len(s[0].Path.Segments), tt.bounds, tt.verbs)
+		}
+		if s[0].Fill != core.Black || s[0].StrokeWidth != 0 {
+			t.Errorf("%s: drawn %v, %v wide, want filled black", tt.name, s[0].Fill, s[0].StrokeWidth)
+		}
+	}
+
+	for _, body := range []string{
+		`<rect width="0" height="10"/>`,
+		`<circle r="0"/>`,
+		`<ellipse rx="1"/>`,
+		`<polygon points="1 2"/>`,
+		`<path d=""/>`,
+		`<text>label</text>`,
+		`<line x1="0" y1="0" x2="10" y2="10"/>`, // open and not stroked
+		`<rect width="1" height="1" fill="none"/>`,
+		`<defs><rect id="r" width="1" height="1"/></defs>`,
+		`<g display="none"><rect width="1" height="1"/></g>`,
+		`<rect width="1" height="1" fill="none" stroke="red" stroke-width="0"/>`,
+	} {
+		if s := shapes(t, body); len(s) != 0 {
+			t.Errorf("%s drew %d shapes", body, len(s))
+		}
+	}
+
+	s := shapes(t, `<polyline points="0 0 10 0 10 10" fill="red" stroke="blue" stroke-width="2"/>`)
+	if len(s) != 1 || s[0].Fill.A != 0 || s[0].Stroke != core.Hex(0x0000ff) || s[0].StrokeWidth != 2 {
+		t.Errorf("a polyline drawn %+v, want only stroked", s)
+	}
+	if v := s[0].Path.Segments; v[len(v)-1].Verb == core.Close {
+		t.Error("the polyline was closed")
+	}
+}
+
+func TestStructure(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		bounds []core.Rect
+	}{
+		{"group transform", `<g transform="translate(10 20)"><rect width="5" height="5"/><g transform="scale(2)"><rect width="5" height="5"/></g></g>`,
+			[]core.Rect{core.R(10, 20, 5, 5), core.R(10, 20, 10, 10)}},
+		{"nested svg", `<svg x="30" y="40"><rect width="5" height="5"/></svg>`, []core.Rect{core.R(30, 40, 5, 5)}},
+		{"use", `<defs><rect id="r" width="5" height="5"/></defs><use href="#r" x="10"/><use xlink:href="#r" y="10"/>`,
+			[]core.Rect{core.R(10, 0, 5, 5), core.R(0, 10, 5, 5)}},
+		{"use of a symbol", `<symbol id="s"><rect width="5" height="5"/><rect x="10" width="5" height="5"/></symbol><use href="#s" transform="translate(1 1)"/>`,
+			[]core.Rect{core.R(1, 1, 5, 5), core.R(11, 1, 5, 5)}},
+		{"missing use", `<use href="#nothing"/>`, nil},
+		{"anchor and switch", `<a><switch><rect width="5" height="5"/></switch></a>`, []core.Rect{core.R(0, 0, 5, 5)}},
+	}
+	for _, tt := range tests {
+		s := shapes(t, tt.body)
+		if len(s) != len(tt.bounds) {
+			t.Errorf("%s: %d shapes, want %d", tt.name, len(s), len(tt.bounds))
+			continue
+		}
+		for i, sh := range s {
+			if got := sh.Path.Bounds(); got != tt.bounds[i] {
+				t.Errorf("%s: shape %d at %v, want %v", tt.name, i, got, tt.bounds[i])
+			}
+		}
+	}
+
+	// A use that refers to itself stops at the depth limit.
+	s := shapes(t, `<g id="g"><rect width="1" height="1"/><use href="#g"/></g>`)
+	if len(s) != maxUseDepth+1 {
+		t.Errorf("a cyclic use drew %d shapes, want %d", len(s), maxUseDepth+1)
+	}
+}
+
+func TestPaints(t *testing.T) {
+	const grad = `<defs>
+		<linearGradient id="base"><stop offset="0" stop-color="#f00" stop-opacity="0.5"/><stop offset="1" stop-color="blue"/></linearGradient>
+		<linearGradient id="alias" href="#base"/>
+		<radialGradient id="loop" href="#loop"/>
+	</defs>`
+	tests := []struct {
+		name          string
+		body          string
+		fill, stroke  core.Color
+		fillCurrent   bool
+		strokeCurrent bool
+		width         float32
+	}{
+		{"default", `<rect width="1" height="1"/>`, core.Black, core.Color{}, false, false, 0},
+		{"stroke", `<rect width="1" height="1" fill="none" stroke="red"/>`, core.Color{}, core.Hex(0xff0000), false, false, 1},
+		{"opacity", `<g opacity="0.5"><rect width="1" height="1" fill-opacity="0.5" fill="red"/></g>`, core.Hex(0xff0000).WithAlpha(0.25), core.Color{}, false, false, 0},
+		{"current color", `<rect width="1" height="1" fill="currentColor" fill-opacity="0.5" stroke="currentColor"/>`, core.Color{A: 0.5}, core.Color{A: 1}, true, true, 1},
+		{"set color", `<g color="red"><rect width="1" height="1" fill="currentColor"/></g>`, core.Hex(0xff0000), core.Color{}, false, false, 0},
+		{"gradient", grad + `<rect width="1" height="1" fill="url(#base)"/>`, core.Hex(0xff0000).WithAlpha(0.5), core.Color{}, false, false, 0},
+		{"gradient by href", grad + `<rect width="1" height="1" fill="url(#alias)"/>`, core.Hex(0xff0000).WithAlpha(0.5), core.Color{}, false, false, 0},
+		{"scaled stroke", `<g transform="scale(3)"><path d="M0 0 L1 1" fill="none" stroke="red" stroke-width="2"/></g>`, core.Color{}, core.Hex(0xff0000), false, false, 6},
+	}
+	for _, tt := range tests {
+		s := shapes(t, tt.body)
+		if len(s) != 1 {
+			t.Errorf("%s: %d shapes", tt.name, len(s))
+			continue
+		}
+		got := s[0]
+		if got.Fill != tt.fill || got.Stroke != tt.stroke || got.FillCurrent != tt.fillCurrent || got.StrokeCurrent != tt.strokeCurrent || got.StrokeWidth != tt.width {
+			t.Errorf("%s: fill %v %v, stroke %v %v %v wide", tt.name, got.Fill, got.FillCurrent, got.Stroke, got.StrokeCurrent, got.StrokeWidth)
+		}
+	}
+
+	// A gradient without stops, or a cycle of references, paints nothing.
+	for _, ref := range []string{"loop", "missing"} {
+		if s := shapes(t, grad+`<rect width="1" height="1" fill="url(#`+ref+`)"/>`); len(s) != 0 {
+			t.Errorf("a fill of %q drew %v", ref, s[0].Fill)
+		}
+	}
+}
+
+func TestScaled(t *testing.T) {
+	d := parse(t, `<svg viewBox="10 10 20 10"><rect x="10" y="10" width="20" height="10" stroke="red" stroke-width="2"/></svg>`)
+	s := d.Scaled(core.Sz(40, 20))
+	if got := s[0].Path.Bounds(); got != core.R(0, 0, 40, 20) || s[0].StrokeWidth != 4 {
+		t.Errorf("scaled to %v, %v wide", got, s[0].StrokeWidth)
+	}
+	s = d.Scaled(core.Sz(20, 40))
+	if got := s[0].Path.Bounds(); got != core.R(0, 0, 20, 40) || s[0].StrokeWidth != 4 {
+		t.Errorf("stretched to %v, %v wide", got, s[0].StrokeWidth)
+	}
+	if got := d.Shapes[0].Path.Bounds(); got != core.R(10, 10, 20, 10) {
+		t.Errorf("Scaled changed the document: %v", got)
+	}
+}
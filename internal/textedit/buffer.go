@@ -0,0 +1,709 @@
+package textedit
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/gogpu/ui/grapheme"
+)
+
+// Pos is a position in a Buffer: a line and a rune column.
+type Pos struct {
+	Line, Col int
+}
+
+// Less reports whether p comes before q.
+func (p Pos) Less(q Pos) bool {
+	return p.Line < q.Line || p.Line == q.Line && p.Col < q.Col
+}
+
+// Cursor is a caret with the anchor of its selection.
+type Cursor struct {
+	Caret, Anchor Pos
+	// goal is the visual column kept while moving up and down, or -1.
+	goal int
+}
+
+// Range returns the selected range, start first.
+func (c Cursor) Range() (from, to Pos) {
+	if c.Caret.Less(c.Anchor) {
+		return c.Caret, c.Anchor
+	}
+	return c.Anchor, c.Caret
+}
+
+// HasSelection reports whether the cursor selects a non-empty range.
+func (c Cursor) HasSelection() bool {
+	return c.Caret != c.Anchor
+}
+
+func at(p Pos) Cursor {
+	return Cursor{Caret: p, Anchor: p, goal: -1}
+}
+
+// Buffer is a multi-line text buffer edited through any number of
+// cursors. Edits apply at every cursor at once; cursors that come to
+// overlap are merged. The first cursor added is the primary one, which
+// survives CollapseCursors.
+type Buffer struct {
+	lines   [][]rune
+	cursors []Cursor
+	primary int
+}
+
+// SetText replaces the buffer and puts a single caret at the start.
+func (b *Buffer) SetText(s string) {
+	parts := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	b.lines = make([][]rune, len(parts))
+	for i, p := range parts {
+		b.lines[i] = []rune(p)
+	}
+	b.cursors = []Cursor{at(Pos{})}
+	b.primary = 0
+}
+
+func (b *Buffer) init() {
+	if b.lines == nil {
+		b.SetText("")
+	}
+}
+
+// Text returns the buffer contents.
+func (b *Buffer) Text() string {
+	b.init()
+	parts := make([]string, len(b.lines))
+	for i, l := range b.lines {
+		parts[i] = string(l)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// LineCount returns the number of lines.
+func (b *Buffer) LineCount() int {
+	b.init()
+	return len(b.lines)
+}
+
+// Line returns line i. The slice must not be modified.
+func (b *Buffer) Line(i int) []rune {
+	b.init()
+	return b.lines[i]
+}
+
+// Cursors returns the cursors in document order.
+func (b *Buffer) Cursors() []Cursor {
+	b.init()
+	return b.cursors
+}
+
+// Primary returns the primary cursor.
+func (b *Buffer) Primary() Cursor {
+	b.init()
+	return b.cursors[b.primary]
+}
+
+// Clamp returns the valid position nearest to p.
+func (b *Buffer) Clamp(p Pos) Pos {
+	b.init()
+	p.Line = max(0, min(p.Line, len(b.lines)-1))
+	p.Col = max(0, min(p.Col, len(b.lines[p.Line])))
+	return p
+}
+
+// End returns the position after the last character.
+func (b *Buffer) End() Pos {
+	b.init()
+	last := len(b.lines) - 1
+	return Pos{last, len(b.lines[last])}
+}
+
+// SetCursor replaces every cursor with one selecting from anchor to caret.
+func (b *Buffer) SetCursor(anchor, caret Pos) {
+	b.init()
+	b.cursors = []Cursor{{Caret: b.Clamp(caret), Anchor: b.Clamp(anchor), goal: -1}}
+	b.primary = 0
+}
+
+// AddCursor adds a caret at p, or removes the cursor already there if it
+// is not the only one.
+func (b *Buffer) AddCursor(p Pos) {
+	b.init()
+	p = b.Clamp(p)
+	for i, c := range b.cursors {
+		if c.Caret == p && len(b.cursors) > 1 {
+			b.removeCursor(i)
+			return
+		}
+	}
+	b.cursors = append(b.cursors, at(p))
+	b.normalize()
+}
+
+// SetPrimary moves the caret of the primary cursor, keeping the others.
+func (b *Buffer) SetPrimary(anchor, caret Pos) {
+	b.init()
+	b.cursors[b.primary] = Cursor{Caret: b.Clamp(caret), Anchor: b.Clamp(anchor), goal: -1}
+	b.normalize()
+}
+
+func (b *Buffer) removeCursor(i int) {
+	b.cursors = slices.Delete(b.cursors, i, i+1)
+	if b.primary >= i && b.primary > 0 {
+		b.primary--
+	}
+}
+
+// CollapseCursors removes every cursor but the primary one. It reports
+// whether there were others.
+func (b *Buffer) CollapseCursors() bool {
+	b.init()
+	if len(b.cursors) == 1 {
+		return false
+	}
+	b.cursors = []Cursor{b.cursors[b.primary]}
+	b.primary = 0
+	return true
+}
+
+// normalize sorts the cursors and merges overlapping ones, keeping track
+// of the primary one.
+func (b *Buffer) normalize() {
+	type entry struct {
+		c       Cursor
+		primary bool
+	}
+	es := make([]entry, len(b.cursors))
+	for i, c := range b.cursors {
+		es[i] = entry{c: c, primary: i == b.primary}
+	}
+	slices.SortStableFunc(es, func(x, y entry) int {
+		xf, _ := x.c.Range()
+		yf, _ := y.c.Range()
+		switch {
+		case xf.Less(yf):
+			return -1
+		case yf.Less(xf):
+			return 1
+		}
+		return 0
+	})
+	out := es[:0]
+	for _, e := range es {
+		if n := len(out); n > 0 {
+			prev := &out[n-1]
+			_, pt := prev.c.Range()
+			f, t := e.c.Range()
+			if f.Less(pt) || f == pt && (!prev.c.HasSelection() || !e.c.HasSelection()) {
+				// Overlapping or touching at a caret: merge into prev.
+				pf, _ := prev.c.Range()
+				if pt.Less(t) {
+					pt = t
+				}
+				if prev.c.Caret.Less(prev.c.Anchor) {
+					prev.c.Caret, prev.c.Anchor = pf, pt
+				} else {
+					prev.c.Anchor, prev.c.Caret = pf, pt
+				}
+				prev.primary = prev.primary || e.primary
+				continue
+			}
+		}
+		out = append(out, e)
+	}
+	b.cursors = b.cursors[:0]
+	b.primary = 0
+	for i, e := range out {
+		b.cursors = append(b.cursors, e.c)
+		if e.primary {
+			b.primary = i
+		}
+	}
+}
+
+// Move moves every caret to fn of it, extending the selections if extend
+// is set.
+func (b *Buffer) Move(fn func(c Cursor) Pos, extend bool) {
+	b.init()
+	for i := range b.cursors {
+		c := &b.cursors[i]
+		c.Caret = b.Clamp(fn(*c))
+		if !extend {
+			c.Anchor = c.Caret
+		}
+		c.goal = -1
+	}
+	b.normalize()
+}
+
+// Step returns the position one character, or one word, before or after
+// p, continuing on the neighboring line at either end. A character is a
+// grapheme cluster.
+func (b *Buffer) Step(p Pos, forward, word bool) Pos {
+	b.init()
+	line := b.lines[p.Line]
+	switch {
+	case forward && p.Col >= len(line):
+		if p.Line+1 < len(b.lines) {
+			return Pos{p.Line + 1, 0}
+		}
+		return p
+	case !forward && p.Col == 0:
+		if p.Line > 0 {
+			return Pos{p.Line - 1, len(b.lines[p.Line-1])}
+		}
+		return p
+	case !word && forward:
+		return Pos{p.Line, grapheme.Next(line, p.Col)}
+	case !word:
+		return Pos{p.Line, grapheme.Prev(line, p.Col)}
+	case forward:
+		i := p.Col
+		for i < len(line) && !isWord(line[i]) {
+			i++
+		}
+		for i < len(line) && isWord(line[i]) {
+			i++
+		}
+		return Pos{p.Line, i}
+	}
+	i := p.Col
+	for i > 0 && !isWord(line[i-1]) {
+		i--
+	}
+	for i > 0 && isWord(line[i-1]) {
+		i--
+	}
+	return Pos{p.Line, i}
+}
+
+// LineStart returns the first position of p's line after its indentation,
+// or the very start of the line if p is already there.
+func (b *Buffer) LineStart(p Pos) Pos {
+	b.init()
+	line := b.lines[p.Line]
+	i := 0
+	for i < len(line) && (line[i] == ' ' || line[i] == '\t') {
+		i++
+	}
+	if p.Col == i {
+		i = 0
+	}
+	return Pos{p.Line, i}
+}
+
+// VisualCol returns the column of p on screen, expanding tabs to tab
+// stops every tab columns.
+func (b *Buffer) VisualCol(p Pos, tab int) int {
+	b.init()
+	v := 0
+	for _, r := range b.lines[p.Line][:min(p.Col, len(b.lines[p.Line]))] {
+		if r == '\t' {
+			v += tab - v%tab
+		} else {
+			v++
+		}
+	}
+	return v
+}
+
+// ColAt returns the column of line nearest to the visual column v.
+func (b *Buffer) ColAt(line, v, tab int) int {
+	b.init()
+	x := 0
+	for i, r := range b.lines[line] {
+		w := 1
+		if r == '\t' {
+			w = tab - x%tab
+		}
+		if v < x+(w+1)/2 {
+			return grapheme.Snap(b.lines[line], i)
+		}
+		x += w
+	}
+	return len(b.lines[line])
+}
+
+// MoveLines moves every caret delta lines up or down, keeping its visual
+// column across shorter lines. Moving past the first or last line goes to
+// its start or end.
+func (b *Buffer) MoveLines(delta, tab int, extend bool) {
+	b.init()
+	for i := range b.cursors {
+		c := &b.cursors[i]
+		if c.goal < 0 {
+			c.goal = b.VisualCol(c.Caret, tab)
+		}
+		line := c.Caret.Line + delta
+		switch {
+		case line < 0:
+			c.Caret = Pos{}
+		case line >= len(b.lines):
+			c.Caret = b.End()
+		default:
+			c.Caret = Pos{line, b.ColAt(line, c.goal, tab)}
+		}
+		if !extend {
+			c.Anchor = c.Caret
+		}
+	}
+	goals := make(map[Pos]int, len(b.cursors))
+	for _, c := range b.cursors {
+		goals[c.Caret] = c.goal
+	}
+	b.normalize()
+	for i := range b.cursors {
+		if g, ok := goals[b.cursors[i].Caret]; ok {
+			b.cursors[i].goal = g
+		}
+	}
+}
+
+// AddCursorLine adds a caret on the line above (delta -1) or below (delta
+// 1) the outermost cursor in that direction, at the same visual column.
+func (b *Buffer) AddCursorLine(delta, tab int) {
+	b.init()
+	c := b.cursors[len(b.cursors)-1]
+	if delta < 0 {
+		c = b.cursors[0]
+	}
+	line := c.Caret.Line + delta
+	if line < 0 || line >= len(b.lines) {
+		return
+	}
+	goal := c.goal
+	if goal < 0 {
+		goal = b.VisualCol(c.Caret, tab)
+	}
+	n := at(Pos{line, b.ColAt(line, goal, tab)})
+	n.goal = goal
+	b.cursors = append(b.cursors, n)
+	b.normalize()
+}
+
+// SelectAll selects the whole buffer with a single cursor.
+func (b *Buffer) SelectAll() {
+	b.SetCursor(Pos{}, b.End())
+}
+
+// Slice returns the text between from and to.
+func (b *Buffer) Slice(from, to Pos) string {
+	b.init()
+	from, to = b.Clamp(from), b.Clamp(to)
+	if to.Less(from) {
+		from, to = to, from
+	}
+	if from.Line == to.Line {
+		return string(b.lines[from.Line][from.Col:to.Col])
+	}
+	var sb strings.Builder
+	sb.WriteString(string(b.lines[from.Line][from.Col:]))
+	for i := from.Line + 1; i < to.Line; i++ {
+		sb.WriteByte('\n')
+		sb.WriteString(string(b.lines[i]))
+	}
+	sb.WriteByte('\n')
+	sb.WriteString(string(b.lines[to.Line][:to.Col]))
+	return sb.String()
+}
+
+// SelectNext adds a cursor selecting the next occurrence of the primary
+// selection, wrapping around at the end. With nothing selected it selects
+// the word around the primary caret instead. It reports whether the
+// selection changed.
+func (b *Buffer) SelectNext() bool {
+	b.init()
+	p := b.cursors[b.primary]
+	if !p.HasSelection() {
+		line := b.lines[p.Caret.Line]
+		s, e := p.Caret.Col, p.Caret.Col
+		for s > 0 && isWord(line[s-1]) {
+			s--
+		}
+		for e < len(line) && isWord(line[e]) {
+			e++
+		}
+		if s == e {
+			return false
+		}
+		b.cursors[b.primary] = Cursor{Anchor: Pos{p.Caret.Line, s}, Caret: Pos{p.Caret.Line, e}, goal: -1}
+		return true
+	}
+	from, to := p.Range()
+	if from.Line != to.Line {
+		return false
+	}
+	needle := b.lines[from.Line][from.Col:to.Col]
+	last := b.cursors[len(b.cursors)-1]
+	_, start := last.Range()
+	for n := 0; n <= len(b.lines); n++ {
+		l := (start.Line + n) % len(b.lines)
+		line := b.lines[l]
+		col := 0
+		if n == 0 {
+			col = start.Col
+		}
+		for ; col+len(needle) <= len(line); col++ {
+			if !slices.Equal(line[col:col+len(needle)], needle) {
+				continue
+			}
+			c := Pos{l, col}
+			for _, x := range b.cursors {
+				if f, _ := x.Range(); f == c {
+					return false // Every occurrence is selected.
+				}
+			}
+			b.cursors = append(b.cursors, Cursor{Anchor: c, Caret: Pos{l, col + len(needle)}, goal: -1})
+			b.normalize()
+			return true
+		}
+	}
+	return false
+}
+
+// replace replaces the text between from and to with text and returns the
+// position after the inserted text.
+func (b *Buffer) replace(from, to Pos, text []rune) Pos {
+	head := b.lines[from.Line][:from.Col]
+	tail := b.lines[to.Line][to.Col:]
+	var parts [][]rune
+	start := 0
+	for i, r := range text {
+		if r == '\n' {
+			parts = append(parts, text[start:i])
+			start = i + 1
+		}
+	}
+	parts = append(parts, text[start:])
+	end := Pos{from.Line + len(parts) - 1, len(parts[len(parts)-1])}
+	if len(parts) == 1 {
+		end.Col += len(head)
+	}
+	repl := make([][]rune, len(parts))
+	for i, p := range parts {
+		var l []rune
+		if i == 0 {
+			l = append(l, head...)
+		}
+		l = append(l, p...)
+		if i == len(parts)-1 {
+			l = append(l, tail...)
+		}
+		repl[i] = l
+	}
+	b.lines = slices.Replace(b.lines, from.Line, to.Line+1, repl...)
+	return end
+}
+
+// shift returns where p, which lies at or after to, moves when the text
+// between from and to is replaced by text ending at end.
+func shift(p, to, end Pos) Pos {
+	if p.Line == to.Line {
+		return Pos{end.Line, end.Col + p.Col - to.Col}
+	}
+	return Pos{p.Line + end.Line - to.Line, p.Col}
+}
+
+// Edit replaces, for every cursor in document order, the range fn returns
+// with its text, and collapses the cursor after it. It returns the first
+// line changed, or -1 if fn changed nothing.
+func (b *Buffer) Edit(fn func(c Cursor) (from, to Pos, text string)) int {
+	b.init()
+	first := -1
+	for i := range b.cursors {
+		from, to, text := fn(b.cursors[i])
+		from, to = b.Clamp(from), b.Clamp(to)
+		if to.Less(from) {
+			from, to = to, from
+		}
+		if from == to && text == "" {
+			continue
+		}
+		end := b.replace(from, to, []rune(text))
+		if first < 0 || from.Line < first {
+			first = from.Line
+		}
+		b.cursors[i] = at(end)
+		for j := i + 1; j < len(b.cursors); j++ {
+			c := &b.cursors[j]
+			c.Caret, c.Anchor = shift(c.Caret, to, end), shift(c.Anchor, to, end)
+		}
+	}
+	b.normalize()
+	return first
+}
+
+// Insert replaces every selection with s. It returns the first line
+// changed.
+func (b *Buffer) Insert(s string) int {
+	return b.Edit(func(c Cursor) (Pos, Pos, string) {
+		from, to := c.Range()
+		return from, to, s
+	})
+}
+
+// Delete removes every selection, or else the character, or word if word
+// is set, before each caret (or after it if forward is set). It returns
+// the first line changed, or -1.
+func (b *Buffer) Delete(forward, word bool) int {
+	return b.Edit(func(c Cursor) (Pos, Pos, string) {
+		if c.HasSelection() {
+			from, to := c.Range()
+			return from, to, ""
+		}
+		return c.Caret, b.Step(c.Caret, forward, word), ""
+	})
+}
+
+// Indent returns the leading spaces and tabs of line i.
+func (b *Buffer) Indent(i int) string {
+	b.init()
+	line := b.lines[i]
+	n := 0
+	for n < len(line) && (line[n] == ' ' || line[n] == '\t') {
+		n++
+	}
+	return string(line[:n])
+}
+
+// NewLine breaks the line at every caret, indenting the new line like the
+// one it was split from, and by unit more after an opening bracket. It
+// returns the first line changed.
+func (b *Buffer) NewLine(unit string) int {
+	return b.Edit(func(c Cursor) (Pos, Pos, string) {
+		from, to := c.Range()
+		indent := []rune(b.Indent(from.Line))
+		text := "\n" + string(indent[:min(len(indent), from.Col)])
+		before := strings.TrimRight(string(b.lines[from.Line][:from.Col]), " \t")
+		if strings.HasSuffix(before, "(") || strings.HasSuffix(before, "[") || strings.HasSuffix(before, "{") {
+			text += unit
+		}
+		return from, to, text
+	})
+}
+
+// selectedLines returns the lines touched by each cursor, without
+// repeating lines shared by several cursors. A selection ending at the
+// start of a line does not touch it.
+func (b *Buffer) selectedLines() []int {
+	var lines []int
+	for _, c := range b.cursors {
+		from, to := c.Range()
+		last := to.Line
+		if to.Col == 0 && to.Line > from.Line {
+			last--
+		}
+		for l := from.Line; l <= last; l++ {
+			if len(lines) == 0 || lines[len(lines)-1] < l {
+				lines = append(lines, l)
+			}
+		}
+	}
+	return lines
+}
+
+// IndentLines adds unit at the start of every line touched by a selection,
+// or removes one level of indentation from them if outdent is set. It
+// returns the first line changed, or -1.
+func (b *Buffer) IndentLines(unit string, tab int, outdent bool) int {
+	b.init()
+	first := -1
+	for _, l := range b.selectedLines() {
+		line := b.lines[l]
+		n := 0 // runes removed (negative) or added at the start
+		if outdent {
+			w := 0
+			for n < len(line) && w < tab && (line[n] == ' ' || line[n] == '\t') {
+				if line[n] == '\t' {
+					w = tab
+				} else {
+					w++
+				}
+				n++
+			}
+			if n == 0 {
+				continue
+			}
+			b.lines[l] = slices.Clone(line[n:])
+			n = -n
+		} else {
+			if len(line) == 0 {
+				continue
+			}
+			b.lines[l] = append([]rune(unit), line...)
+			n = len([]rune(unit))
+		}
+		if first < 0 {
+			first = l
+		}
+		for i := range b.cursors {
+			c := &b.cursors[i]
+			if c.Caret.Line == l {
+				c.Caret.Col = max(0, c.Caret.Col+n)
+			}
+			if c.Anchor.Line == l {
+				c.Anchor.Col = max(0, c.Anchor.Col+n)
+			}
+		}
+	}
+	return first
+}
+
+// MatchSkip reports, for the rune at p, whether bracket matching should
+// skip it, as inside a string or comment.
+type MatchSkip func(p Pos) bool
+
+// bracketPairs maps each bracket to its partner.
+var bracketPairs = map[rune]rune{'(': ')', '[': ']', '{': '}', ')': '(', ']': '[', '}': '{'}
+
+// MatchBracket finds the bracket matching the one just before or after p,
+// looking at most limit lines away. It returns the positions of both
+// brackets.
+func (b *Buffer) MatchBracket(p Pos, limit int, skip MatchSkip) (at, match Pos, ok bool) {
+	b.init()
+	line := b.lines[p.Line]
+	for _, col := range []int{p.Col, p.Col - 1} {
+		if col < 0 || col >= len(line) {
+			continue
+		}
+		r := line[col]
+		partner, isBracket := bracketPairs[r]
+		if !isBracket || skip != nil && skip(Pos{p.Line, col}) {
+			continue
+		}
+		forward := strings.ContainsRune("([{", r)
+		depth := 0
+		q := Pos{p.Line, col}
+		for {
+			if forward {
+				q.Col++
+				for q.Col >= len(b.lines[q.Line]) {
+					if q.Line+1 >= len(b.lines) || q.Line-p.Line >= limit {
+						return Pos{}, Pos{}, false
+					}
+					q = Pos{q.Line + 1, 0}
+				}
+			} else {
+				q.Col--
+				for q.Col < 0 {
+					if q.Line == 0 || p.Line-q.Line >= limit {
+						return Pos{}, Pos{}, false
+					}
+					q.Line--
+					q.Col = len(b.lines[q.Line]) - 1
+				}
+			}
+			c := b.lines[q.Line][q.Col]
+			if c != r && c != partner || skip != nil && skip(q) {
+				continue
+			}
+			if c == r {
+				depth++
+			} else if depth == 0 {
+				return Pos{p.Line, col}, q, true
+			} else {
+				depth--
+			}
+		}
+	}
+	return Pos{}, Pos{}, false
+}
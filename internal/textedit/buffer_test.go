@@ -0,0 +1,324 @@
+package textedit
+
+import (
+	"testing"
+)
+
+// newBuffer returns a buffer holding text with a caret at each of carets.
+func newBuffer(text string, carets ...Pos) *Buffer {
+	b := &Buffer{}
+	b.SetText(text)
+	if len(carets) > 0 {
+		b.SetCursor(carets[0], carets[0])
+		for _, p := range carets[1:] {
+			b.AddCursor(p)
+		}
+	}
+	return b
+}
+
+// carets returns the caret of every cursor of b.
+func carets(b *Buffer) []Pos {
+	var ps []Pos
+	for _, c := range b.Cursors() {
+		ps = append(ps, c.Caret)
+	}
+	return ps
+}
+
+func equalPos(a, b []Pos) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestBufferText(t *testing.T) {
+	var b Buffer
+	if b.Text() != "" || b.LineCount() != 1 || b.End() != (Pos{}) {
+		t.Errorf("a zero Buffer holds %q in %d lines", b.Text(), b.LineCount())
+	}
+	b.SetText("ab\r\ncd\n")
+	if b.Text() != "ab\ncd\n" || b.LineCount() != 3 || b.End() != (Pos{2, 0}) {
+		t.Errorf("SetText gave %q in %d lines", b.Text(), b.LineCount())
+	}
+	if got := b.Clamp(Pos{-1, 9}); got != (Pos{0, 2}) {
+		t.Errorf("Clamp = %v", got)
+	}
+	if got := b.Slice(Pos{0, 1}, Pos{2, 0}); got != "b\ncd\n" {
+		t.Errorf("Slice = %q", got)
+	}
+	if got := b.Slice(Pos{1, 1}, Pos{0, 1}); got != "b\nc" {
+		t.Errorf("a reversed Slice = %q, want the same text as in order", got)
+	}
+}
+
+func TestBufferEdit(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		carets []Pos
+		edit   func(b *Buffer) int
+		want   string
+		after  []Pos
+		first  int
+	}{
+		{"insert at every caret", "ab\ncd", []Pos{{0, 1}, {1, 1}},
+			func(b *Buffer) int { return b.Insert("X") }, "aXb\ncXd", []Pos{{0, 2}, {1, 2}}, 0},
+		{"newlines on one line", "abcd", []Pos{{0, 1}, {0, 3}},
+			func(b *Buffer) int { return b.Insert("\n") }, "a\nbc\nd", []Pos{{1, 0}, {2, 0}}, 0},
+		{"carets that meet merge", "abc", []Pos{{0, 1}, {0, 2}},
+			func(b *Buffer) int { return b.Delete(false, false) }, "c", []Pos{{0, 0}}, 0},
+		{"delete forward joins lines", "ab\ncd", []Pos{{0, 2}},
+			func(b *Buffer) int { return b.Delete(true, false) }, "abcd", []Pos{{0, 2}}, 0},
+		{"delete a word", "foo bar", []Pos{{0, 7}},
+			func(b *Buffer) int { return b.Delete(false, true) }, "foo ", []Pos{{0, 4}}, 0},
+		{"nothing to delete", "ab", []Pos{{0, 0}},
+			func(b *Buffer) int { return b.Delete(false, false) }, "ab", []Pos{{0, 0}}, -1},
+		{"first line changed", "a\nb\nc", []Pos{{2, 1}, {1, 0}},
+			func(b *Buffer) int { return b.Insert("!") }, "a\n!b\nc!", []Pos{{1, 1}, {2, 2}}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newBuffer(tt.text, tt.carets...)
+			first := tt.edit(b)
+			if b.Text() != tt.want || first != tt.first {
+				t.Errorf("text %q from line %d, want %q from %d", b.Text(), first, tt.want, tt.first)
+			}
+			if got := carets(b); !equalPos(got, tt.after) {
+				t.Errorf("carets %v, want %v", got, tt.after)
+			}
+		})
+	}
+
+	b := newBuffer("one two")
+	b.SetCursor(Pos{0, 0}, Pos{0, 3})
+	b.Insert("1")
+	if b.Text() != "1 two" || b.Primary().HasSelection() {
+		t.Errorf("typing over a selection gave %q", b.Text())
+	}
+}
+
+func TestBufferCursors(t *testing.T) {
+	b := newBuffer("abc\ndef", Pos{1, 0})
+	b.AddCursor(Pos{0, 0})
+	if got := carets(b); !equalPos(got, []Pos{{0, 0}, {1, 0}}) || b.Primary().Caret != (Pos{1, 0}) {
+		t.Fatalf("carets %v with %v primary", got, b.Primary().Caret)
+	}
+	b.AddCursor(Pos{0, 0})
+	if len(b.Cursors()) != 1 {
+		t.Errorf("adding a caret where one is does not remove it")
+	}
+	b.AddCursor(Pos{1, 0})
+	if len(b.Cursors()) != 1 {
+		t.Errorf("the only caret was removed")
+	}
+
+	b.AddCursor(Pos{0, 2})
+	b.SetPrimary(Pos{1, 0}, Pos{1, 3})
+	b.Move(func(c Cursor) Pos { return Pos{c.Caret.Line, 0} }, true)
+	if got := b.Cursors(); len(got) != 2 || got[1].Anchor != (Pos{1, 0}) || got[1].Caret != (Pos{1, 0}) {
+		t.Errorf("cursors %+v", got)
+	}
+	if !b.CollapseCursors() || b.Primary().Caret != (Pos{1, 0}) || b.CollapseCursors() {
+		t.Errorf("CollapseCursors kept %v", carets(b))
+	}
+
+	// Overlapping selections merge into one.
+	b = newBuffer("abcdef")
+	b.SetCursor(Pos{0, 0}, Pos{0, 3})
+	b.AddCursor(Pos{0, 5})
+	b.Move(func(c Cursor) Pos { return Pos{0, 6 - c.Caret.Col} }, true)
+	if got := b.Cursors(); len(got) != 1 || got[0].Anchor != (Pos{}) || got[0].Caret != (Pos{0, 5}) {
+		t.Errorf("overlapping selections stayed apart: %+v", got)
+	}
+	b.SelectAll()
+	if from, to := b.Primary().Range(); from != (Pos{}) || to != (Pos{0, 6}) {
+		t.Errorf("SelectAll selected %v to %v", from, to)
+	}
+}
+
+func TestBufferMoveLines(t *testing.T) {
+	b := newBuffer("abcdef\nab\nabcdef", Pos{0, 5})
+	b.MoveLines(1, 4, false)
+	b.MoveLines(1, 4, false)
+	if got := b.Primary().Caret; got != (Pos{2, 5}) {
+		t.Errorf("moving down twice went to %v, want the column kept", got)
+	}
+	b.MoveLines(1, 4, true)
+	if c := b.Primary(); c.Caret != (Pos{2, 6}) || c.Anchor != (Pos{2, 5}) {
+		t.Errorf("moving past the end selected %v to %v", c.Anchor, c.Caret)
+	}
+	b.MoveLines(-10, 4, false)
+	if got := b.Primary().Caret; got != (Pos{}) {
+		t.Errorf("moving past the start went to %v", got)
+	}
+
+	b = newBuffer("abcdef\nabcdef\nab", Pos{1, 2})
+	b.AddCursorLine(1, 4)
+	b.AddCursorLine(-1, 4)
+	b.AddCursorLine(-1, 4)
+	if got := carets(b); !equalPos(got, []Pos{{0, 2}, {1, 2}, {2, 2}}) {
+		t.Errorf("carets %v, want one on each line", got)
+	}
+}
+
+func TestBufferTabs(t *testing.T) {
+	b := newBuffer("\tx\na\tb")
+	tests := []struct {
+		p    Pos
+		want int
+	}{
+		{Pos{0, 1}, 4},
+		{Pos{0, 2}, 5},
+		{Pos{1, 2}, 4},
+		{Pos{1, 9}, 5},
+	}
+	for _, tt := range tests {
+		if got := b.VisualCol(tt.p, 4); got != tt.want {
+			t.Errorf("VisualCol(%v) = %d, want %d", tt.p, got, tt.want)
+		}
+	}
+	for v, want := range map[int]int{0: 0, 1: 0, 2: 1, 4: 1, 5: 2, 9: 2} {
+		if got := b.ColAt(0, v, 4); got != want {
+			t.Errorf("ColAt(%d) = %d, want %d", v, got, want)
+		}
+	}
+}
+
+func TestBufferLineStart(t *testing.T) {
+	b := newBuffer("  \tx")
+	if got := b.LineStart(Pos{0, 4}); got != (Pos{0, 3}) {
+		t.Errorf("LineStart from the end = %v, want after the indentation", got)
+	}
+	if got := b.LineStart(Pos{0, 3}); got != (Pos{0, 0}) {
+		t.Errorf("LineStart from the indentation = %v, want the start", got)
+	}
+	if got := b.Indent(0); got != "  \t" {
+		t.Errorf("Indent = %q", got)
+	}
+}
+
+func TestBufferSelectNext(t *testing.T) {
+	b := newBuffer("foo bar foo\nfoo", Pos{0, 1})
+	want := [][2]Pos{{{0, 0}, {0, 3}}, {{0, 8}, {0, 11}}, {{1, 0}, {1, 3}}}
+	for i := range want {
+		if !b.SelectNext() {
+			t.Fatalf("SelectNext %d found nothing", i+1)
+		}
+	}
+	for i, c := range b.Cursors() {
+		if c.Anchor != want[i][0] || c.Caret != want[i][1] {
+			t.Errorf("cursor %d selects %v to %v, want %v", i, c.Anchor, c.Caret, want[i])
+		}
+	}
+	if b.SelectNext() {
+		t.Error("SelectNext went on after every occurrence was selected")
+	}
+	if newBuffer("a  b", Pos{0, 2}).SelectNext() {
+		t.Error("SelectNext selected a word between spaces")
+	}
+}
+
+func TestBufferNewLine(t *testing.T) {
+	tests := []struct {
+		text  string
+		at    Pos
+		want  string
+		caret Pos
+	}{
+		{"\tx", Pos{0, 2}, "\tx\n\t", Pos{1, 1}},
+		{"\tif x {", Pos{0, 7}, "\tif x {\n\t  ", Pos{1, 3}},
+		{"f(  ", Pos{0, 4}, "f(  \n  ", Pos{1, 2}},
+		{"    x", Pos{0, 2}, "  \n    x", Pos{1, 2}},
+	}
+	for _, tt := range tests {
+		b := newBuffer(tt.text, tt.at)
+		b.NewLine("  ")
+		if b.Text() != tt.want || b.Primary().Caret != tt.caret {
+			t.Errorf("NewLine in %q gave %q with the caret at %v, want %q at %v", tt.text, b.Text(), b.Primary().Caret, tt.want, tt.caret)
+		}
+	}
+}
+
+func TestBufferIndentLines(t *testing.T) {
+	tests := []struct {
+		name         string
+		text         string
+		from, to     Pos
+		outdent      bool
+		want         string
+		first        int
+		anchor, care Pos
+	}{
+		{"indent", "a\n\nb", Pos{0, 1}, Pos{2, 1}, false, "  a\n\n  b", 0, Pos{0, 3}, Pos{2, 3}},
+		{"the line after the selection", "a\nb", Pos{0, 0}, Pos{1, 0}, false, "  a\nb", 0, Pos{0, 2}, Pos{1, 0}},
+		{"outdent", "\t\ta\n     b", Pos{0, 3}, Pos{1, 6}, true, "\ta\n b", 0, Pos{0, 2}, Pos{1, 2}},
+		{"nothing to outdent", "a\n  b", Pos{0, 0}, Pos{1, 3}, true, "a\nb", 1, Pos{0, 0}, Pos{1, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newBuffer(tt.text)
+			b.SetCursor(tt.from, tt.to)
+			first := b.IndentLines("  ", 4, tt.outdent)
+			if b.Text() != tt.want || first != tt.first {
+				t.Errorf("text %q from line %d, want %q from %d", b.Text(), first, tt.want, tt.first)
+			}
+			if c := b.Primary(); c.Anchor != tt.anchor || c.Caret != tt.care {
+				t.Errorf("selection %v to %v, want %v to %v", c.Anchor, c.Caret, tt.anchor, tt.care)
+			}
+		})
+	}
+}
+
+func TestBufferMatchBracket(t *testing.T) {
+	quoted := func(b *Buffer) MatchSkip {
+		return func(p Pos) bool {
+			in := false
+			for _, r := range b.Line(p.Line)[:p.Col] {
+				if r == '"' {
+					in = !in
+				}
+			}
+			return in
+		}
+	}
+	tests := []struct {
+		name      string
+		text      string
+		p         Pos
+		limit     int
+		skip      bool
+		at, match Pos
+		ok        bool
+	}{
+		{"after an opening bracket", "f(a[1], {b})", Pos{0, 2}, 10, false, Pos{0, 1}, Pos{0, 11}, true},
+		{"before an opening bracket", "f(a[1], {b})", Pos{0, 3}, 10, false, Pos{0, 3}, Pos{0, 5}, true},
+		{"after a closing bracket", "f(a[1], {b})", Pos{0, 12}, 10, false, Pos{0, 11}, Pos{0, 1}, true},
+		{"across lines", "{\n x\n}", Pos{0, 0}, 10, false, Pos{0, 0}, Pos{2, 0}, true},
+		{"backward across lines", "{\n x\n}", Pos{2, 1}, 10, false, Pos{2, 0}, Pos{0, 0}, true},
+		{"past the limit", "{\n x\n}", Pos{0, 0}, 1, false, Pos{}, Pos{}, false},
+		{"unmatched", "(a", Pos{0, 0}, 10, false, Pos{}, Pos{}, false},
+		{"not a bracket", "a b", Pos{0, 1}, 10, false, Pos{}, Pos{}, false},
+		{"skipping strings", `(")")`, Pos{0, 0}, 10, true, Pos{0, 0}, Pos{0, 4}, true},
+		{"not skipping strings", `(")")`, Pos{0, 0}, 10, false, Pos{0, 0}, Pos{0, 2}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newBuffer(tt.text)
+			var skip MatchSkip
+			if tt.skip {
+				skip = quoted(b)
+			}
+			at, match, ok := b.MatchBracket(tt.p, tt.limit, skip)
+			if at != tt.at || match != tt.match || ok != tt.ok {
+				t.Errorf("MatchBracket = %v, %v, %v, want %v, %v, %v", at, match, ok, tt.at, tt.match, tt.ok)
+			}
+		})
+	}
+}
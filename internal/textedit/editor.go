@@ -0,0 +1,245 @@
+// Package textedit implements the editing model shared by text inputs: a
+// buffer with a caret and selection, the standard keyboard commands, a
+// single-line view that paints and scrolls it, and a multi-line Buffer
+// edited through several cursors at once for code editing.
+package textedit
+
+import (
+	"unicode"
+
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/grapheme"
+)
+
+// Editor is a text buffer with a caret and a selection. Positions are rune
+// indices; the selection runs between the anchor and the caret. The arrow
+// keys and deletion step over whole grapheme clusters, so the caret never
+// lands inside an emoji sequence.
+type Editor struct {
+	text   []rune
+	caret  int
+	anchor int
+}
+
+// Text returns the buffer contents.
+func (e *Editor) Text() string {
+	return string(e.text)
+}
+
+// Len returns the length of the buffer in runes.
+func (e *Editor) Len() int {
+	return len(e.text)
+}
+
+// SetText replaces the buffer and puts the caret at the end.
+func (e *Editor) SetText(s string) {
+	e.text = []rune(s)
+	e.caret = len(e.text)
+	e.anchor = e.caret
+}
+
+// Replace replaces the buffer with s and puts the caret at caret.
+func (e *Editor) Replace(s string, caret int) {
+	e.text = []rune(s)
+	e.SetCaret(caret, false)
+}
+
+// Caret returns the caret position.
+func (e *Editor) Caret() int {
+	return e.caret
+}
+
+// Selection returns the selected range, start first. It is empty when
+// nothing is selected.
+func (e *Editor) Selection() (start, end int) {
+	return min(e.caret, e.anchor), max(e.caret, e.anchor)
+}
+
+// HasSelection reports whether a non-empty range is selected.
+func (e *Editor) HasSelection() bool {
+	return e.caret != e.anchor
+}
+
+// SelectedText returns the selected text.
+func (e *Editor) SelectedText() string {
+	s, t := e.Selection()
+	return string(e.text[s:t])
+}
+
+// SetCaret moves the caret to i, extending the selection if extend is set
+// and collapsing it otherwise.
+func (e *Editor) SetCaret(i int, extend bool) {
+	e.caret = max(0, min(i, len(e.text)))
+	if !extend {
+		e.anchor = e.caret
+	}
+}
+
+// Select selects the range from start to end with the caret at end.
+func (e *Editor) Select(start, end int) {
+	e.anchor = max(0, min(start, len(e.text)))
+	e.SetCaret(end, true)
+}
+
+// SelectAll selects the whole buffer.
+func (e *Editor) SelectAll() {
+	e.Select(0, len(e.text))
+}
+
+// Insert replaces the selection with s.
+func (e *Editor) Insert(s string) {
+	start, end := e.Selection()
+	r := []rune(s)
+	text := make([]rune, 0, len(e.text)-(end-start)+len(r))
+	text = append(text, e.text[:start]...)
+	text = append(text, r...)
+	text = append(text, e.text[end:]...)
+	e.text = text
+	e.SetCaret(start+len(r), false)
+}
+
+// Delete removes the selection, or else the character, or word if word is
+// set, before the caret (or after it if forward is set). A character is a
+// grapheme cluster, so an emoji sequence or a letter with its accents goes
+// at once. It reports whether the text changed.
+func (e *Editor) Delete(forward, word bool) bool {
+	if !e.HasSelection() {
+		var to int
+		switch {
+		case forward && word:
+			to = e.wordEnd(e.caret)
+		case forward:
+			to = grapheme.Next(e.text, e.caret)
+		case word:
+			to = e.wordStart(e.caret)
+		default:
+			to = grapheme.Prev(e.text, e.caret)
+		}
+		e.SetCaret(to, true)
+	}
+	if !e.HasSelection() {
+		return false
+	}
+	e.Insert("")
+	return true
+}
+
+// WordAt returns the bounds of the word around i.
+func (e *Editor) WordAt(i int) (start, end int) {
+	i = max(0, min(i, len(e.text)))
+	start, end = i, i
+	for start > 0 && isWord(e.text[start-1]) {
+		start--
+	}
+	for end < len(e.text) && isWord(e.text[end]) {
+		end++
+	}
+	return start, end
+}
+
+func isWord(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r) || r == '_'
+}
+
+// wordStart returns the start of the word before i, skipping spaces and
+// punctuation first.
+func (e *Editor) wordStart(i int) int {
+	for i > 0 && !isWord(e.text[i-1]) {
+		i--
+	}
+	for i > 0 && isWord(e.text[i-1]) {
+		i--
+	}
+	return i
+}
+
+// wordEnd returns the end of the word after i.
+func (e *Editor) wordEnd(i int) int {
+	for i < len(e.text) && !isWord(e.text[i]) {
+		i++
+	}
+	for i < len(e.text) && isWord(e.text[i]) {
+		i++
+	}
+	return i
+}
+
+// Result describes the effect of an event on the editor.
+type Result uint8
+
+const (
+	// Ignored means the event is not an editing command.
+	Ignored Result = iota
+	// Moved means the caret or selection changed.
+	Moved
+	// Changed means the text changed.
+	Changed
+)
+
+// HandleKey applies the editing command for a key press: the arrow, Home
+// and End keys move the caret, extending the selection with Shift and
+// moving by word with Ctrl (Alt on macOS keyboards); Backspace and Delete
+// remove text; Ctrl+A selects everything.
+func (e *Editor) HandleKey(ev event.KeyEvent) Result {
+	if ev.Type != event.KeyPress {
+		return Ignored
+	}
+	extend := ev.Modifiers.Has(event.ModShift)
+	word := ev.Modifiers.Has(event.ModCtrl) || ev.Modifiers.Has(event.ModAlt)
+	caret := e.caret
+	switch ev.Key {
+	case event.KeyLeft:
+		switch {
+		case e.HasSelection() && !extend && !word:
+			caret, _ = e.Selection()
+		case word:
+			caret = e.wordStart(caret)
+		default:
+			caret = grapheme.Prev(e.text, caret)
+		}
+	case event.KeyRight:
+		switch {
+		case e.HasSelection() && !extend && !word:
+			_, caret = e.Selection()
+		case word:
+			caret = e.wordEnd(caret)
+		default:
+			caret = grapheme.Next(e.text, caret)
+		}
+	case event.KeyHome:
+		caret = 0
+	case event.KeyEnd:
+		caret = len(e.text)
+	case event.KeyBackspace, event.KeyDelete:
+		if e.Delete(ev.Key == event.KeyDelete, word) {
+			return Changed
+		}
+		return Moved
+	case event.KeyA:
+		if ev.Modifiers != event.ModCtrl && ev.Modifiers != event.ModSuper {
+			return Ignored
+		}
+		e.SelectAll()
+		return Moved
+	default:
+		return Ignored
+	}
+	e.SetCaret(caret, extend)
+	return Moved
+}
+
+// HandleText inserts committed text, dropping control characters.
+func (e *Editor) HandleText(ev event.TextEvent) Result {
+	r := []rune(ev.Text)
+	kept := r[:0]
+	for _, c := range r {
+		if !unicode.IsControl(c) {
+			kept = append(kept, c)
+		}
+	}
+	if len(kept) == 0 {
+		return Ignored
+	}
+	e.Insert(string(kept))
+	return Changed
+}
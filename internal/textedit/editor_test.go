@@ -0,0 +1,101 @@
+package textedit
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/event"
+)
+
+// key returns a key press of k with mods.
+func key(k event.Key, mods event.Modifiers) event.KeyEvent {
+	return event.KeyEvent{Type: event.KeyPress, Key: k, Modifiers: mods}
+}
+
+func TestEditorKeys(t *testing.T) {
+	tests := []struct {
+		name       string
+		keys       []event.KeyEvent
+		text       string
+		start, end int
+		result     Result
+	}{
+		{"left", []event.KeyEvent{key(event.KeyLeft, 0)}, "hello world", 10, 10, Moved},
+		{"home", []event.KeyEvent{key(event.KeyHome, 0)}, "hello world", 0, 0, Moved},
+		{"word left", []event.KeyEvent{key(event.KeyLeft, event.ModCtrl)}, "hello world", 6, 6, Moved},
+		{"word left twice", []event.KeyEvent{key(event.KeyLeft, event.ModCtrl), key(event.KeyLeft, event.ModCtrl)}, "hello world", 0, 0, Moved},
+		{"word right", []event.KeyEvent{key(event.KeyHome, 0), key(event.KeyRight, event.ModAlt)}, "hello world", 5, 5, Moved},
+		{"select left", []event.KeyEvent{key(event.KeyLeft, event.ModShift), key(event.KeyLeft, event.ModShift)}, "hello world", 9, 11, Moved},
+		{"left collapses the selection", []event.KeyEvent{key(event.KeyHome, event.ModShift), key(event.KeyRight, 0)}, "hello world", 11, 11, Moved},
+		{"select all", []event.KeyEvent{key(event.KeyA, event.ModCtrl)}, "hello world", 0, 11, Moved},
+		{"plain A is not a command", []event.KeyEvent{key(event.KeyA, 0)}, "hello world", 11, 11, Ignored},
+		{"backspace", []event.KeyEvent{key(event.KeyBackspace, 0)}, "hello worl", 10, 10, Changed},
+		{"backspace word", []event.KeyEvent{key(event.KeyBackspace, event.ModCtrl)}, "hello ", 6, 6, Changed},
+		{"delete at the end", []event.KeyEvent{key(event.KeyDelete, 0)}, "hello world", 11, 11, Moved},
+		{"delete word", []event.KeyEvent{key(event.KeyHome, 0), key(event.KeyDelete, event.ModCtrl)}, " world", 0, 0, Changed},
+		{"delete the selection", []event.KeyEvent{key(event.KeyLeft, event.ModCtrl|event.ModShift), key(event.KeyDelete, 0)}, "hello ", 6, 6, Changed},
+		{"release", []event.KeyEvent{{Type: event.KeyRelease, Key: event.KeyHome}}, "hello world", 11, 11, Ignored},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var e Editor
+			e.SetText("hello world")
+			var r Result
+			for _, k := range tt.keys {
+				r = e.HandleKey(k)
+			}
+			if e.Text() != tt.text || r != tt.result {
+				t.Errorf("text %q, result %d, want %q, %d", e.Text(), r, tt.text, tt.result)
+			}
+			if s, end := e.Selection(); s != tt.start || end != tt.end {
+				t.Errorf("selection %d-%d, want %d-%d", s, end, tt.start, tt.end)
+			}
+		})
+	}
+}
+
+func TestEditorInsert(t *testing.T) {
+	var e Editor
+	e.SetText("héllo")
+	e.Select(1, 4)
+	if got := e.SelectedText(); got != "éll" {
+		t.Errorf("SelectedText() = %q", got)
+	}
+	e.Insert("EL")
+	if e.Text() != "hELo" || e.Caret() != 3 || e.HasSelection() {
+		t.Errorf("text %q, caret %d after Insert", e.Text(), e.Caret())
+	}
+	if r := e.HandleText(event.TextEvent{Text: "\x08\t"}); r != Ignored || e.Text() != "hELo" {
+		t.Errorf("control characters inserted: %q", e.Text())
+	}
+	if r := e.HandleText(event.TextEvent{Text: "a\nb"}); r != Changed || e.Text() != "hELabo" {
+		t.Errorf("text %q after typing", e.Text())
+	}
+	e.SetCaret(99, false)
+	if e.Caret() != e.Len() {
+		t.Errorf("caret %d past the end", e.Caret())
+	}
+	e.Replace("xy", 1)
+	if e.Text() != "xy" || e.Caret() != 1 {
+		t.Errorf("Replace: %q with the caret at %d", e.Text(), e.Caret())
+	}
+}
+
+func TestEditorWordAt(t *testing.T) {
+	var e Editor
+	e.SetText("foo_bar, baz")
+	tests := []struct {
+		at, start, end int
+	}{
+		{0, 0, 7},
+		{4, 0, 7},
+		{7, 0, 7},
+		{8, 8, 8},
+		{10, 9, 12},
+		{99, 9, 12},
+	}
+	for _, tt := range tests {
+		if s, end := e.WordAt(tt.at); s != tt.start || end != tt.end {
+			t.Errorf("WordAt(%d) = %d, %d, want %d, %d", tt.at, s, end, tt.start, tt.end)
+		}
+	}
+}
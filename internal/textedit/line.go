@@ -0,0 +1,281 @@
+package textedit
+
+import (
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/grapheme"
+	"github.com/gogpu/ui/theme"
+)
+
+const caretWidth = 1.5
+
+// Line is a single-line view of an Editor. The owner sets Rect and Style
+// every arrange pass, forwards input to HandleEvent and paints it with
+// Paint. Text wider than Rect scrolls horizontally to keep the caret in
+// view. Text mixing left-to-right and right-to-left scripts is displayed
+// in bidirectional order, with the caret, selection and arrow keys
+// following it on screen. Text being composed with an input method is
+// shown underlined at the caret until it is committed.
+type Line struct {
+	Editor
+
+	// Rect is the text area in window coordinates.
+	Rect core.Rect
+
+	// Style is the text style. A transparent color uses OnSurface.
+	Style core.TextStyle
+
+	// Placeholder is shown in a muted color while the buffer is empty.
+	Placeholder string
+
+	// Filter, if set, rewrites typed text before it is inserted; returning
+	// an empty string rejects it.
+	Filter func(s string) string
+
+	// Format, if set, rewrites the whole text after every edit, for
+	// example to insert separators. It receives the caret position and
+	// returns where the caret belongs in the new text.
+	Format func(text string, caret int) (string, int)
+
+	offset   float32
+	dragging bool
+	dir      core.Direction
+	vis      visual
+
+	preedit       []rune // The text being composed, shown at the caret.
+	preeditCursor int
+}
+
+// Height returns the height of a line in Style.
+func (l *Line) Height() float32 {
+	return l.Style.LineHeight()
+}
+
+func (l *Line) style(ctx *core.Context) core.TextStyle {
+	s := l.Style
+	if s.Color.IsTransparent() {
+		s.Color = theme.From(ctx).Colors.OnSurface
+	}
+	return s
+}
+
+// PositionAt returns the caret position nearest to p.
+func (l *Line) PositionAt(ctx *core.Context, p core.Point) int {
+	target := p.X - l.Rect.X + l.offset
+	best, dist := 0, float32(core.Infinity)
+	for i := 0; ; i = grapheme.Next(l.text, i) {
+		if d := abs32(l.x(ctx, i) - target); d < dist {
+			best, dist = i, d
+		}
+		if i == len(l.text) {
+			return best
+		}
+	}
+}
+
+// CaretRect returns the caret rectangle in window coordinates.
+func (l *Line) CaretRect(ctx *core.Context) core.Rect {
+	x := l.Rect.X + l.x(ctx, l.shownCaret()) - l.offset
+	h := l.Height()
+	return core.R(x, l.Rect.Y+(l.Rect.Height-h)/2, caretWidth, h)
+}
+
+// ScrollToCaret adjusts the horizontal offset so the caret is visible.
+func (l *Line) ScrollToCaret(ctx *core.Context) {
+	x := l.x(ctx, l.shownCaret())
+	total := l.layout(ctx).width
+	w := l.Rect.Width - caretWidth
+	switch {
+	case x-l.offset > w:
+		l.offset = x - w
+	case x < l.offset:
+		l.offset = x
+	}
+	l.offset = core.Clamp(l.offset, 0, max(0, total-w))
+}
+
+// Paint draws the text, the selection and, if focused in an active
+// window, the caret.
+func (l *Line) Paint(ctx *core.PaintContext, focused bool) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	style := l.style(ctx.Context)
+	l.dir = ctx.Direction()
+	v := l.layout(ctx.Context)
+	y := l.Rect.Y + (l.Rect.Height-l.Height())/2
+	cv.Save()
+	cv.Clip(l.Rect)
+	if len(l.text) == 0 && !l.Composing() && l.Placeholder != "" {
+		cv.DrawText(l.Placeholder, core.Pt(l.Rect.X, y), theme.TextStyle(style, th.Colors.OnSurfaceVariant.WithAlpha(0.6)))
+	}
+	if focused && l.HasSelection() {
+		// A selection crossing runs of both directions covers several
+		// pieces of the line.
+		s, e := l.Selection()
+		for k := range v.runs {
+			r := &v.runs[k]
+			a, b := max(s, r.start), min(e, r.end)
+			if a >= b {
+				continue
+			}
+			x0, x1 := l.edge(ctx.Context, r, a), l.edge(ctx.Context, r, b)
+			x0, x1 = min(x0, x1), max(x0, x1)
+			cv.DrawRect(core.R(l.Rect.X+x0-l.offset, y, x1-x0, l.Height()), core.Filled(th.Colors.Selection))
+		}
+	}
+	for _, r := range v.runs {
+		cv.DrawText(r.text, core.Pt(l.Rect.X+r.x-l.offset, y), style)
+	}
+	if l.Composing() {
+		s, e := l.caret, l.caret+len(l.preedit)
+		for k := range v.runs {
+			r := &v.runs[k]
+			a, b := max(s, r.start), min(e, r.end)
+			if a >= b {
+				continue
+			}
+			x0, x1 := l.edge(ctx.Context, r, a), l.edge(ctx.Context, r, b)
+			x0, x1 = min(x0, x1), max(x0, x1)
+			cv.DrawRect(core.R(l.Rect.X+x0-l.offset, y+l.Height()-1, x1-x0, 1), core.Filled(style.Color))
+		}
+	}
+	if focused && ctx.WindowActive() {
+		cv.DrawRect(l.CaretRect(ctx.Context), core.Filled(th.Colors.Primary))
+	}
+	cv.Restore()
+}
+
+// HandleEvent applies editing keys, typed text and pointer selection:
+// click to place the caret, drag or Shift+click to select, double-click to
+// select a word and triple-click to select everything. owner receives
+// pointer capture while dragging. Keys are ignored while text is being
+// composed, as they belong to the input method.
+func (l *Line) HandleEvent(ctx *core.Context, owner core.Widget, ev core.Event) Result {
+	var r Result
+	switch e := ev.(type) {
+	case event.CompositionEvent:
+		r = l.compose(e)
+	case event.KeyEvent:
+		if l.Composing() {
+			return Ignored
+		}
+		if r = l.moveVisually(ctx, e); r != Ignored {
+			break
+		}
+		before, caret, length := l.Text(), l.caret, len(l.text)
+		r = l.HandleKey(e)
+		if r == Changed && l.Format != nil {
+			l.format()
+			// Deleting a separator the formatter puts straight back would
+			// do nothing, so widen the deletion until a character that
+			// sticks is gone.
+			for n := 2; l.Text() == before && n <= length; n++ {
+				l.Replace(before, caret)
+				if e.Key == event.KeyDelete {
+					l.Select(caret, caret+n)
+				} else {
+					l.Select(caret-n, caret)
+				}
+				if !l.Delete(false, false) {
+					l.Replace(before, caret)
+					break
+				}
+				l.format()
+			}
+		}
+	case event.TextEvent:
+		l.preedit = nil
+		if l.Filter != nil {
+			e.Text = l.Filter(e.Text)
+		}
+		before := l.Text()
+		r = l.HandleText(e)
+		if r == Changed && l.Format != nil {
+			l.format()
+			if l.Text() == before {
+				// The formatter dropped what was typed.
+				r = Moved
+			}
+		}
+	case event.MouseEvent:
+		r = l.handleMouse(ctx, owner, e)
+	}
+	if r != Ignored {
+		l.ScrollToCaret(ctx)
+		ctx.Repaint(owner)
+	}
+	return r
+}
+
+// Composing reports whether text is being composed with an input method.
+func (l *Line) Composing() bool {
+	return len(l.preedit) > 0
+}
+
+// compose shows the text being composed at the caret, replacing the
+// selection when composition starts. The text only enters the buffer
+// when a TextEvent commits it.
+func (l *Line) compose(e event.CompositionEvent) Result {
+	r := Moved
+	if e.Type == event.CompositionEnd {
+		l.preedit = nil
+		return r
+	}
+	if !l.Composing() && l.HasSelection() {
+		l.Insert("")
+		r = Changed
+	}
+	l.preedit = []rune(e.Text)
+	l.preeditCursor = min(max(e.Cursor, 0), len(l.preedit))
+	return r
+}
+
+// shownCaret returns the position of the caret in the text shown, which
+// is at the input method's cursor while text is being composed.
+func (l *Line) shownCaret() int {
+	if l.Composing() {
+		return l.caret + l.preeditCursor
+	}
+	return l.caret
+}
+
+// format applies Format to the text.
+func (l *Line) format() {
+	text, caret := l.Format(l.Text(), l.caret)
+	l.Replace(text, caret)
+}
+
+func (l *Line) handleMouse(ctx *core.Context, owner core.Widget, e event.MouseEvent) Result {
+	switch e.Type {
+	case event.MouseDown:
+		if e.Button != event.ButtonLeft || !l.Rect.Contains(e.Position) {
+			return Ignored
+		}
+		i := l.PositionAt(ctx, e.Position)
+		switch e.ClickCount {
+		case 0, 1:
+			l.SetCaret(i, e.Modifiers.Has(event.ModShift))
+			l.dragging = true
+			ctx.CapturePointer(owner)
+		case 2:
+			l.Select(l.WordAt(i))
+		default:
+			l.SelectAll()
+		}
+		return Moved
+	case event.MouseMove:
+		if !l.dragging {
+			return Ignored
+		}
+		l.SetCaret(l.PositionAt(ctx, e.Position), true)
+		return Moved
+	case event.MouseUp:
+		if !l.dragging {
+			return Ignored
+		}
+		l.dragging = false
+		ctx.ReleasePointer()
+		return Moved
+	}
+	return Ignored
+}
@@ -0,0 +1,201 @@
+package textedit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+)
+
+// testWidget is the owner of a Line in tests.
+type testWidget struct {
+	core.WidgetBase
+}
+
+func (*testWidget) Layout(ctx *core.LayoutContext) core.Size { return ctx.Constraints.Min() }
+func (*testWidget) Paint(*core.PaintContext)                 {}
+
+// newLine returns a line showing text in a wide rectangle.
+func newLine(text string) (*Line, *core.Context, core.Widget) {
+	l := &Line{Rect: core.R(10, 0, 400, 30), Style: core.TextStyle{Size: 14}}
+	l.SetText(text)
+	return l, core.NewContext(), &testWidget{}
+}
+
+func mouse(typ event.MouseEventType, x float32, clicks int) event.MouseEvent {
+	return event.MouseEvent{Type: typ, Position: core.Pt(x, 15), Button: event.ButtonLeft, ClickCount: clicks}
+}
+
+func TestLineMouse(t *testing.T) {
+	l, ctx, owner := newLine("hello world")
+	end := l.CaretRect(ctx).X
+	tests := []struct {
+		name       string
+		events     []event.MouseEvent
+		start, end int
+	}{
+		{"click at the start", []event.MouseEvent{mouse(event.MouseDown, 10, 1)}, 0, 0},
+		{"click past the end", []event.MouseEvent{mouse(event.MouseDown, 390, 1)}, 11, 11},
+		{"drag", []event.MouseEvent{mouse(event.MouseDown, 10, 1), mouse(event.MouseMove, end, 0), mouse(event.MouseUp, end, 0)}, 0, 11},
+		{"double click", []event.MouseEvent{mouse(event.MouseDown, 12, 2)}, 0, 5},
+		{"triple click", []event.MouseEvent{mouse(event.MouseDown, 12, 3)}, 0, 11},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l.SetText("hello world")
+			for _, e := range tt.events {
+				if r := l.HandleEvent(ctx, owner, e); r != Moved {
+					t.Errorf("%v = %d, want Moved", e.Type, r)
+				}
+			}
+			if s, e := l.Selection(); s != tt.start || e != tt.end {
+				t.Errorf("selection %d-%d, want %d-%d", s, e, tt.start, tt.end)
+			}
+			if ctx.PointerCapture() != nil && tt.name == "drag" {
+				t.Error("the pointer stayed captured")
+			}
+		})
+	}
+	if r := l.HandleEvent(ctx, owner, mouse(event.MouseDown, 500, 1)); r != Ignored {
+		t.Errorf("a press outside the line = %d, want Ignored", r)
+	}
+}
+
+func TestLineFilterAndFormat(t *testing.T) {
+	l, ctx, owner := newLine("")
+	l.Filter = func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if r < '0' || r > '9' {
+				return -1
+			}
+			return r
+		}, s)
+	}
+	// Format groups the digits in pairs separated by colons.
+	l.Format = func(text string, caret int) (string, int) {
+		digits := strings.ReplaceAll(text, ":", "")
+		n := strings.Count(text[:caret], ":")
+		caret -= n
+		var b strings.Builder
+		at := 0
+		for i, r := range digits {
+			if i > 0 && i%2 == 0 {
+				b.WriteByte(':')
+				if i < caret {
+					at++
+				}
+			}
+			b.WriteRune(r)
+		}
+		return b.String(), caret + at
+	}
+	for _, s := range []string{"1", "2", "x", "3", "4a"} {
+		l.HandleEvent(ctx, owner, event.TextEvent{Text: s})
+	}
+	if l.Text() != "12:34" || l.Caret() != 5 {
+		t.Fatalf("text %q, caret %d, want 12:34 at the end", l.Text(), l.Caret())
+	}
+	// Backspace after the colon deletes the digit before it.
+	l.SetCaret(3, false)
+	if r := l.HandleEvent(ctx, owner, key(event.KeyBackspace, 0)); r != Changed || l.Text() != "13:4" {
+		t.Errorf("text %q after Backspace over the separator, want 13:4", l.Text())
+	}
+}
+
+func TestLineScrollsToCaret(t *testing.T) {
+	l, ctx, owner := newLine(strings.Repeat("wide ", 40))
+	l.Rect.Width = 60
+	l.HandleEvent(ctx, owner, key(event.KeyEnd, 0))
+	if c := l.CaretRect(ctx); c.X < l.Rect.X || c.Right() > l.Rect.Right()+caretWidth {
+		t.Errorf("caret at %v outside %v", c, l.Rect)
+	}
+	l.HandleEvent(ctx, owner, key(event.KeyHome, 0))
+	if c := l.CaretRect(ctx); c.X != l.Rect.X {
+		t.Errorf("caret at %v, want at the left edge %v", c.X, l.Rect.X)
+	}
+}
+
+func TestLineFormatRejects(t *testing.T) {
+	l, ctx, owner := newLine("")
+	// Format keeps only digits and puts a dash after the second.
+	l.Format = func(text string, caret int) (string, int) {
+		digits := strings.Map(func(r rune) rune {
+			if r < '0' || r > '9' {
+				return -1
+			}
+			return r
+		}, text)
+		if len(digits) > 2 {
+			digits = digits[:2] + "-" + digits[2:]
+		}
+		return digits, min(caret, len(digits))
+	}
+	for _, s := range []string{"1", "2", "3"} {
+		l.HandleEvent(ctx, owner, event.TextEvent{Text: s})
+	}
+	if r := l.HandleEvent(ctx, owner, event.TextEvent{Text: "x"}); r != Moved || l.Text() != "12-3" {
+		t.Errorf("typing a rejected letter = %d with %q, want Moved", r, l.Text())
+	}
+	// Delete before the dash removes the digit after it.
+	l.SetCaret(2, false)
+	if r := l.HandleEvent(ctx, owner, key(event.KeyDelete, 0)); r != Changed || l.Text() != "12" {
+		t.Errorf("text %q after Delete over the separator, want 12", l.Text())
+	}
+}
+
+func TestLineCompose(t *testing.T) {
+	l, ctx, owner := newLine("ab")
+	l.SetCaret(1, false)
+	caret := l.CaretRect(ctx).X
+	compose := func(typ event.CompositionEventType, text string, cursor int) Result {
+		return l.HandleEvent(ctx, owner, event.CompositionEvent{Type: typ, Text: text, Cursor: cursor})
+	}
+
+	// The text composed is shown at the caret, with the caret at the input
+	// method's cursor, and keys go to the input method.
+	if r := compose(event.CompositionStart, "に", 1); r != Moved || !l.Composing() {
+		t.Fatalf("composition start = %d, composing %v", r, l.Composing())
+	}
+	if l.Text() != "ab" || string(l.layout(ctx).text) != "aにb" {
+		t.Errorf("text %q shown as %q, want the composition shown alone", l.Text(), string(l.vis.text))
+	}
+	after := l.CaretRect(ctx).X
+	if after <= caret {
+		t.Errorf("caret at %v composing, want after the composition from %v", after, caret)
+	}
+	if r := l.HandleEvent(ctx, owner, key(event.KeyLeft, 0)); r != Ignored || l.Caret() != 1 {
+		t.Errorf("a key while composing = %d, caret %d", r, l.Caret())
+	}
+	compose(event.CompositionUpdate, "日本", 0)
+	if got := l.CaretRect(ctx).X; got != caret {
+		t.Errorf("caret at %v with the cursor at the start of the composition, want %v", got, caret)
+	}
+	compose(event.CompositionUpdate, "日本", 9)
+	if l.preeditCursor != 2 {
+		t.Errorf("cursor %d past the composition, want at its end", l.preeditCursor)
+	}
+
+	// Committed, the text enters the buffer.
+	compose(event.CompositionEnd, "", 0)
+	if r := l.HandleEvent(ctx, owner, event.TextEvent{Text: "日本"}); r != Changed || l.Text() != "a日本b" || l.Caret() != 3 {
+		t.Errorf("commit = %d with %q, caret %d", r, l.Text(), l.Caret())
+	}
+	if l.Composing() || string(l.layout(ctx).text) != "a日本b" {
+		t.Errorf("shown as %q after the commit", string(l.vis.text))
+	}
+
+	// Started over a selection, composition replaces it; ended without a
+	// commit, it leaves nothing.
+	l.SelectAll()
+	if r := compose(event.CompositionStart, "한", 1); r != Changed || l.Text() != "" {
+		t.Errorf("composition over a selection = %d with %q", r, l.Text())
+	}
+	if r := compose(event.CompositionEnd, "", 0); r != Moved || l.Composing() || l.Text() != "" {
+		t.Errorf("cancel = %d with %q, composing %v", r, l.Text(), l.Composing())
+	}
+	l.SetText("xy")
+	if r := l.HandleEvent(ctx, owner, key(event.KeyHome, 0)); r != Moved || l.Caret() != 0 {
+		t.Errorf("Home after the composition ended = %d, caret %d", r, l.Caret())
+	}
+}
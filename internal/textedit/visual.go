@@ -0,0 +1,176 @@
+package textedit
+
+import (
+	"slices"
+
+	"github.com/gogpu/ui/bidi"
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/grapheme"
+)
+
+// caretSlop is how far apart two caret positions must be, in pixels, for
+// visual caret movement to treat them as different places.
+const caretSlop = 0.5
+
+// visualRun is a run of characters from start up to end laid out in one
+// direction, x pixels from the left edge of the text.
+type visualRun struct {
+	start, end int
+	rtl        bool
+	text       string
+	x, width   float32
+}
+
+// visual is the bidirectional layout of a Line's text: its runs in visual
+// order, left to right. It is rebuilt when the text, style or direction
+// changes.
+type visual struct {
+	src     []rune
+	preedit []rune
+	caret   int
+	style   core.TextStyle
+	dir     core.Direction
+	valid   bool
+
+	text []rune // The text shown: src with preedit at caret.
+
+	rtl   bool // Whether the paragraph is right to left.
+	runs  []visualRun
+	width float32
+}
+
+// isMixed reports whether the text displays in any order but logical
+// order left to right, which is when the arrow keys move visually.
+func (v *visual) isMixed() bool {
+	return v.rtl || len(v.runs) > 1 || len(v.runs) == 1 && v.runs[0].rtl
+}
+
+// layout returns the visual layout of the text, with any text being
+// composed shown at the caret. The paragraph direction
+// is that of its first strong character, so text typed in Hebrew reads
+// right to left even in a left-to-right interface, and that of the
+// interface when there is none.
+func (l *Line) layout(ctx *core.Context) *visual {
+	v := &l.vis
+	if v.valid && v.style == l.Style && v.dir == l.dir && slices.Equal(v.src, l.text) &&
+		slices.Equal(v.preedit, l.preedit) && (len(l.preedit) == 0 || v.caret == l.caret) {
+		return v
+	}
+	v.src = append(v.src[:0], l.text...)
+	v.preedit = append(v.preedit[:0], l.preedit...)
+	v.caret = l.caret
+	v.text = append(append(append(v.text[:0], l.text[:l.caret]...), l.preedit...), l.text[l.caret:]...)
+	v.style, v.dir, v.valid = l.Style, l.dir, true
+	v.runs, v.width = v.runs[:0], 0
+	base := bidi.LeftToRight
+	if l.dir.IsRTL() {
+		base = bidi.RightToLeft
+	}
+	if first, ok := bidi.FirstStrong(v.text); ok {
+		base = first
+	}
+	p := bidi.Resolve(v.text, base)
+	v.rtl = p.BaseLevel().IsRTL()
+	for _, r := range p.Runs(0, len(v.text)) {
+		s := string(v.text[r.Start:r.End])
+		w := ctx.MeasureText(s, l.Style).Width
+		v.runs = append(v.runs, visualRun{start: r.Start, end: r.End, rtl: r.IsRTL(), text: s, x: v.width, width: w})
+		v.width += w
+	}
+	return v
+}
+
+// edge returns the offset of the boundary before character k of run r,
+// or after its last character for k == r.end.
+func (l *Line) edge(ctx *core.Context, r *visualRun, k int) float32 {
+	var w float32
+	if k > r.start {
+		w = ctx.MeasureText(string(l.vis.text[r.start:k]), l.Style).Width
+	}
+	if r.rtl {
+		return r.x + r.width - w
+	}
+	return r.x + w
+}
+
+// x returns the horizontal offset of caret position i of the text shown
+// from the left edge of the text. The caret sits on the trailing edge of
+// the character before it, which is its left edge in a right-to-left
+// run, or on the leading edge of the first character.
+func (l *Line) x(ctx *core.Context, i int) float32 {
+	v := l.layout(ctx)
+	i = min(max(i, 0), len(v.text))
+	c := max(i-1, 0)
+	for k := range v.runs {
+		if r := &v.runs[k]; c >= r.start && c < r.end {
+			return l.edge(ctx, r, i)
+		}
+	}
+	return 0
+}
+
+// moveVisually handles the Left and Right keys in text that does not read
+// plainly left to right. Without a modifier they move the caret to the
+// nearest position on screen in their direction; moving by word follows
+// the paragraph, so Left moves to the next word in right-to-left text.
+func (l *Line) moveVisually(ctx *core.Context, e event.KeyEvent) Result {
+	if e.Type != event.KeyPress || e.Key != event.KeyLeft && e.Key != event.KeyRight {
+		return Ignored
+	}
+	v := l.layout(ctx)
+	if !v.isMixed() {
+		return Ignored
+	}
+	right := e.Key == event.KeyRight
+	if e.Modifiers.Has(event.ModCtrl) || e.Modifiers.Has(event.ModAlt) {
+		if v.rtl {
+			e.Key = event.KeyLeft
+			if !right {
+				e.Key = event.KeyRight
+			}
+		}
+		return l.HandleKey(e)
+	}
+	extend := e.Modifiers.Has(event.ModShift)
+	if l.HasSelection() && !extend {
+		s, end := l.Selection()
+		if (l.x(ctx, end) > l.x(ctx, s)) != right {
+			end = s
+		}
+		l.SetCaret(end, false)
+		return Moved
+	}
+	cur := l.x(ctx, l.caret)
+	best, dist := -1, float32(0)
+	for i := 0; i <= len(l.text); i = grapheme.Next(l.text, i) {
+		d := l.x(ctx, i) - cur
+		if !right {
+			d = -d
+		}
+		if d >= caretSlop && (best < 0 || d < dist || d == dist && abs(i-l.caret) < abs(best-l.caret)) {
+			best, dist = i, d
+		}
+		if i == len(l.text) {
+			break
+		}
+	}
+	if best >= 0 {
+		l.SetCaret(best, extend)
+	}
+	return Moved
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func abs32(f float32) float32 {
+	if f < 0 {
+		return -f
+	}
+	return f
+}
@@ -0,0 +1,590 @@
+package vt
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// Parser states.
+const (
+	stateGround = iota
+	stateEscape
+	stateCSI
+	stateOSC
+	stateString // a DCS, SOS, PM or APC string, which is ignored
+)
+
+// maxParams and maxOSC bound what a malformed sequence can accumulate.
+const (
+	maxParams = 32
+	maxOSC    = 4096
+)
+
+// parser holds a partially received character or control sequence.
+type parser struct {
+	state int
+	utf8  []byte
+	// params holds the parameters of a control sequence; each is a list
+	// of its colon-separated parts.
+	params  [][]int
+	private byte // the ? > = or < before the parameters
+	inter   []byte
+	osc     []byte
+	esc     bool // an ESC was seen in a string, which may start ST
+}
+
+// Write interprets output of the program running in the terminal. It
+// always consumes all of p.
+func (t *Terminal) Write(p []byte) (int, error) {
+	for _, b := range p {
+		t.feed(b)
+	}
+	return len(p), nil
+}
+
+func (t *Terminal) feed(b byte) {
+	ps := &t.p
+	switch ps.state {
+	case stateOSC, stateString:
+		switch {
+		case b == 0x07 && ps.state == stateOSC, ps.esc && b == '\\':
+			if ps.state == stateOSC {
+				t.osc(string(ps.osc))
+			}
+			ps.state, ps.esc = stateGround, false
+		case b == 0x1b:
+			ps.esc = true
+		case b == 0x18 || b == 0x1a:
+			ps.state, ps.esc = stateGround, false
+		default:
+			if ps.esc {
+				// ESC followed by anything but \ ends the string and
+				// starts a new sequence.
+				ps.state, ps.esc = stateEscape, false
+				ps.inter = ps.inter[:0]
+				t.feed(b)
+				return
+			}
+			if ps.state == stateOSC && len(ps.osc) < maxOSC {
+				ps.osc = append(ps.osc, b)
+			}
+		}
+		return
+	}
+	if b < 0x20 || b == 0x7f {
+		t.control(b)
+		return
+	}
+	switch ps.state {
+	case stateGround:
+		t.printByte(b)
+	case stateEscape:
+		t.escape(b)
+	case stateCSI:
+		t.csiByte(b)
+	}
+}
+
+// printByte decodes UTF-8 and prints complete characters.
+func (t *Terminal) printByte(b byte) {
+	ps := &t.p
+	if b < utf8.RuneSelf && len(ps.utf8) == 0 {
+		t.print(rune(b))
+		return
+	}
+	if b < 0x80 || b >= 0xc0 && len(ps.utf8) > 0 {
+		// The sequence was cut short.
+		ps.utf8 = ps.utf8[:0]
+		t.print(utf8.RuneError)
+		if b < 0x80 {
+			t.print(rune(b))
+			return
+		}
+	}
+	ps.utf8 = append(ps.utf8, b)
+	if utf8.FullRune(ps.utf8) {
+		r, _ := utf8.DecodeRune(ps.utf8)
+		ps.utf8 = ps.utf8[:0]
+		t.print(r)
+	}
+}
+
+// control executes a C0 control character, which may arrive in the
+// middle of a sequence.
+func (t *Terminal) control(b byte) {
+	ps := &t.p
+	switch b {
+	case 0x1b:
+		ps.state = stateEscape
+		ps.inter = ps.inter[:0]
+	case 0x18, 0x1a: // CAN, SUB
+		ps.state = stateGround
+	case 0x07:
+		if t.Bell != nil {
+			t.Bell()
+		}
+	case 0x08:
+		if t.cur.x > 0 {
+			t.cur.x--
+		}
+		t.cur.wrap = false
+	case 0x09:
+		t.tab(1)
+	case 0x0a, 0x0b, 0x0c:
+		t.lineFeed()
+		if t.newline {
+			t.cur.x = 0
+		}
+	case 0x0d:
+		t.cur.x, t.cur.wrap = 0, false
+	case 0x0e: // SO
+		t.shift = 1
+	case 0x0f: // SI
+		t.shift = 0
+	}
+}
+
+// escape handles the byte after ESC, or after its intermediate bytes.
+func (t *Terminal) escape(b byte) {
+	ps := &t.p
+	if b >= 0x20 && b <= 0x2f {
+		ps.inter = append(ps.inter, b)
+		return
+	}
+	ps.state = stateGround
+	if len(ps.inter) > 0 {
+		switch ps.inter[0] {
+		case '(', ')':
+			t.charsets[ps.inter[0]-'('] = b
+		case '#':
+			if b == '8' { // DECALN: fill the screen with E
+				for y := range t.lines {
+					for x := range t.lines[y].Cells {
+						t.lines[y].Cells[x] = Cell{Rune: 'E'}
+					}
+				}
+			}
+		}
+		return
+	}
+	switch b {
+	case '[':
+		ps.state = stateCSI
+		ps.params, ps.private = ps.params[:0], 0
+	case ']':
+		ps.state, ps.osc = stateOSC, ps.osc[:0]
+	case 'P', 'X', '^', '_':
+		ps.state = stateString
+	case '7':
+		t.saveCursor()
+	case '8':
+		t.restoreCursor()
+	case 'D':
+		t.lineFeed()
+	case 'E':
+		t.lineFeed()
+		t.cur.x = 0
+	case 'H':
+		t.tabs[t.cur.x] = true
+	case 'M':
+		t.reverseIndex()
+	case 'c':
+		t.reset()
+	case '=':
+		t.appKeypad = true
+	case '>':
+		t.appKeypad = false
+	}
+}
+
+// csiByte accumulates a control sequence and dispatches it on its final
+// byte.
+func (t *Terminal) csiByte(b byte) {
+	ps := &t.p
+	switch {
+	case b >= '0' && b <= '9':
+		if len(ps.params) == 0 {
+			ps.params = append(ps.params, []int{0})
+		}
+		last := ps.params[len(ps.params)-1]
+		if v := &last[len(last)-1]; *v < 1<<16 {
+			*v = *v*10 + int(b-'0')
+		}
+	case b == ';':
+		if len(ps.params) == 0 {
+			ps.params = append(ps.params, []int{0})
+		}
+		if len(ps.params) < maxParams {
+			ps.params = append(ps.params, []int{0})
+		}
+	case b == ':':
+		if len(ps.params) == 0 {
+			ps.params = append(ps.params, []int{0})
+		}
+		last := &ps.params[len(ps.params)-1]
+		if len(*last) < maxParams {
+			*last = append(*last, 0)
+		}
+	case b >= '<' && b <= '?':
+		ps.private = b
+	case b >= 0x20 && b <= 0x2f:
+		ps.inter = append(ps.inter, b)
+	case b >= 0x40 && b <= 0x7e:
+		ps.state = stateGround
+		t.csi(b)
+		ps.inter = ps.inter[:0]
+	}
+}
+
+// param returns parameter i, or def if it is missing or zero.
+func (t *Terminal) param(i, def int) int {
+	if i < len(t.p.params) && t.p.params[i][0] != 0 {
+		return t.p.params[i][0]
+	}
+	return def
+}
+
+// csi executes a control sequence.
+func (t *Terminal) csi(final byte) {
+	ps := &t.p
+	n := t.param(0, 1)
+	if len(ps.inter) > 0 {
+		switch {
+		case ps.inter[0] == ' ' && final == 'q': // DECSCUSR
+			switch t.param(0, 1) {
+			case 3, 4:
+				t.shape = CursorUnderline
+			case 5, 6:
+				t.shape = CursorBar
+			default:
+				t.shape = CursorBlock
+			}
+		case ps.inter[0] == '!' && final == 'p': // DECSTR
+			t.autowrap, t.origin, t.insert = true, false, false
+			t.cursorHidden, t.appCursor, t.appKeypad = false, false, false
+			t.cur.style, t.cur.wrap = Style{}, false
+			t.top, t.bottom = 0, t.rows-1
+			t.charsets, t.shift = [2]byte{'B', 'B'}, 0
+			t.saved = [2]savedCursor{}
+		}
+		return
+	}
+	if ps.private != 0 && ps.private != '?' {
+		if ps.private == '>' && final == 'c' {
+			t.reply("\x1b[>1;10;0c")
+		}
+		return
+	}
+	if ps.private == '?' {
+		switch final {
+		case 'h', 'l':
+			for i := range ps.params {
+				t.setPrivateMode(ps.params[i][0], final == 'h')
+			}
+		}
+		return
+	}
+	switch final {
+	case '@':
+		t.insertChars(n)
+	case 'A':
+		t.moveRows(-n)
+	case 'B', 'e':
+		t.moveRows(n)
+	case 'C', 'a':
+		t.cur.x, t.cur.wrap = min(t.cur.x+n, t.cols-1), false
+	case 'D':
+		t.cur.x, t.cur.wrap = max(t.cur.x-n, 0), false
+	case 'E':
+		t.moveRows(n)
+		t.cur.x = 0
+	case 'F':
+		t.moveRows(-n)
+		t.cur.x = 0
+	case 'G', '`':
+		t.cur.x, t.cur.wrap = min(n-1, t.cols-1), false
+	case 'H', 'f':
+		t.moveTo(t.param(1, 1)-1, n-1)
+	case 'I':
+		t.tab(n)
+	case 'Z':
+		t.tab(-n)
+	case 'J':
+		t.eraseDisplay(t.param(0, 0))
+	case 'K':
+		switch t.param(0, 0) {
+		case 0:
+			t.erase(t.cur.y, t.cur.x, t.cols)
+		case 1:
+			t.erase(t.cur.y, 0, t.cur.x+1)
+		case 2:
+			t.erase(t.cur.y, 0, t.cols)
+		}
+	case 'L':
+		t.insertLines(n)
+	case 'M':
+		t.deleteLines(n)
+	case 'P':
+		t.deleteChars(n)
+	case 'S':
+		t.scrollUp(n)
+	case 'T':
+		t.scrollDown(n)
+	case 'X':
+		t.erase(t.cur.y, t.cur.x, t.cur.x+n)
+	case 'b':
+		if t.last != 0 {
+			for range min(n, t.cols*t.rows) {
+				t.print(t.last)
+			}
+		}
+	case 'c':
+		if t.param(0, 0) == 0 {
+			t.reply("\x1b[?62;22c")
+		}
+	case 'd':
+		x := t.cur.x
+		t.moveTo(x, n-1)
+	case 'g':
+		switch t.param(0, 0) {
+		case 0:
+			t.tabs[t.cur.x] = false
+		case 3:
+			clear(t.tabs)
+		}
+	case 'h', 'l':
+		for i := range ps.params {
+			switch ps.params[i][0] {
+			case 4:
+				t.insert = final == 'h'
+			case 20:
+				t.newline = final == 'h'
+			}
+		}
+	case 'm':
+		t.sgr()
+	case 'n':
+		switch t.param(0, 0) {
+		case 5:
+			t.reply("\x1b[0n")
+		case 6:
+			y := t.cur.y
+			if t.origin {
+				y -= t.top
+			}
+			t.reply(fmt.Sprintf("\x1b[%d;%dR", y+1, t.cur.x+1))
+		}
+	case 'r':
+		top, bottom := t.param(0, 1)-1, t.param(1, t.rows)-1
+		if top < bottom && bottom < t.rows {
+			t.top, t.bottom = top, bottom
+			t.moveTo(0, 0)
+		}
+	case 's':
+		t.saveCursor()
+	case 'u':
+		t.restoreCursor()
+	}
+}
+
+// eraseDisplay executes ED.
+func (t *Terminal) eraseDisplay(mode int) {
+	switch mode {
+	case 0:
+		t.erase(t.cur.y, t.cur.x, t.cols)
+		for y := t.cur.y + 1; y < t.rows; y++ {
+			t.erase(y, 0, t.cols)
+		}
+	case 1:
+		for y := 0; y < t.cur.y; y++ {
+			t.erase(y, 0, t.cols)
+		}
+		t.erase(t.cur.y, 0, t.cur.x+1)
+	case 2:
+		for y := 0; y < t.rows; y++ {
+			t.erase(y, 0, t.cols)
+		}
+	case 3:
+		t.ClearScrollback()
+	}
+}
+
+// setPrivateMode sets or resets a DEC private mode.
+func (t *Terminal) setPrivateMode(mode int, on bool) {
+	switch mode {
+	case 1:
+		t.appCursor = on
+	case 6:
+		t.origin = on
+		t.moveTo(0, 0)
+	case 7:
+		t.autowrap = on
+	case 25:
+		t.cursorHidden = !on
+	case 47, 1047:
+		t.setAlt(on, mode == 1047)
+	case 1048:
+		if on {
+			t.saveCursor()
+		} else {
+			t.restoreCursor()
+		}
+	case 1049:
+		if on {
+			t.saveCursor()
+			t.setAlt(true, true)
+		} else {
+			t.setAlt(false, false)
+			t.restoreCursor()
+		}
+	case 1000:
+		t.setMouse(MouseClicks, on)
+	case 1002:
+		t.setMouse(MouseDrags, on)
+	case 1003:
+		t.setMouse(MouseMotion, on)
+	case 1006:
+		t.sgrMouse = on
+	case 2004:
+		t.bracketedPaste = on
+	}
+}
+
+func (t *Terminal) setMouse(m MouseMode, on bool) {
+	switch {
+	case on:
+		t.mouse = m
+	case t.mouse == m:
+		t.mouse = MouseOff
+	}
+}
+
+// sgr executes Select Graphic Rendition.
+func (t *Terminal) sgr() {
+	ps := &t.p
+	st := &t.cur.style
+	if len(ps.params) == 0 {
+		*st = Style{}
+		return
+	}
+	for i := 0; i < len(ps.params); i++ {
+		p := ps.params[i]
+		switch v := p[0]; {
+		case v == 0:
+			*st = Style{}
+		case v == 1:
+			st.Attr |= Bold
+		case v == 2:
+			st.Attr |= Faint
+		case v == 3:
+			st.Attr |= Italic
+		case v == 4:
+			if len(p) > 1 && p[1] == 0 {
+				st.Attr &^= Underline
+			} else {
+				st.Attr |= Underline
+			}
+		case v == 5 || v == 6:
+			st.Attr |= Blink
+		case v == 7:
+			st.Attr |= Inverse
+		case v == 8:
+			st.Attr |= Hidden
+		case v == 9:
+			st.Attr |= Strike
+		case v == 21:
+			st.Attr |= Underline
+		case v == 22:
+			st.Attr &^= Bold | Faint
+		case v == 23:
+			st.Attr &^= Italic
+		case v == 24:
+			st.Attr &^= Underline
+		case v == 25:
+			st.Attr &^= Blink
+		case v == 27:
+			st.Attr &^= Inverse
+		case v == 28:
+			st.Attr &^= Hidden
+		case v == 29:
+			st.Attr &^= Strike
+		case v >= 30 && v <= 37:
+			st.FG = Indexed(uint8(v - 30))
+		case v == 38:
+			st.FG, i = t.extendedColor(i)
+		case v == 39:
+			st.FG = DefaultColor
+		case v >= 40 && v <= 47:
+			st.BG = Indexed(uint8(v - 40))
+		case v == 48:
+			st.BG, i = t.extendedColor(i)
+		case v == 49:
+			st.BG = DefaultColor
+		case v >= 90 && v <= 97:
+			st.FG = Indexed(uint8(v - 90 + 8))
+		case v >= 100 && v <= 107:
+			st.BG = Indexed(uint8(v - 100 + 8))
+		}
+	}
+}
+
+// extendedColor parses the 256-color or RGB color of SGR 38 or 48 at
+// parameter i, in either the colon form, 38:5:n and 38:2::r:g:b, or the
+// semicolon form, 38;5;n and 38;2;r;g;b. It returns the color and the
+// last parameter it used.
+func (t *Terminal) extendedColor(i int) (Color, int) {
+	ps := &t.p
+	args := ps.params[i][1:]
+	next := i
+	if len(args) == 0 {
+		// The semicolon form: the arguments are the next parameters.
+		for _, p := range ps.params[i+1:] {
+			args = append(args, p[0])
+		}
+	}
+	if len(args) == 0 {
+		return DefaultColor, i
+	}
+	switch args[0] {
+	case 5:
+		if len(args) < 2 {
+			return DefaultColor, i
+		}
+		if len(ps.params[i]) == 1 {
+			next = i + 2
+		}
+		return Indexed(uint8(args[1])), next
+	case 2:
+		rgb := args[1:]
+		if len(ps.params[i]) > 1 && len(rgb) >= 4 {
+			rgb = rgb[1:] // skip the color space
+		}
+		if len(rgb) < 3 {
+			return DefaultColor, len(ps.params)
+		}
+		if len(ps.params[i]) == 1 {
+			next = i + 4
+		}
+		return RGB(uint8(rgb[0]), uint8(rgb[1]), uint8(rgb[2])), next
+	}
+	return DefaultColor, i
+}
+
+// osc executes an operating system command.
+func (t *Terminal) osc(s string) {
+	for i := 0; i < len(s); i++ {
+		if s[i] == ';' {
+			switch s[:i] {
+			case "0", "2":
+				t.title = s[i+1:]
+			}
+			return
+		}
+	}
+}
+
+func (t *Terminal) reply(s string) {
+	if t.Reply != nil {
+		t.Reply([]byte(s))
+	}
+}
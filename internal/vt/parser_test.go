@@ -0,0 +1,305 @@
+package vt
+
+import (
+	"strings"
+	"testing"
+)
+
+// rows returns the text of the screen, one string per row with trailing
+// blanks trimmed, and blank rows at the bottom dropped.
+func rows(t *Terminal) []string {
+	var out []string
+	for y := range t.rows {
+		out = append(out, lineText(t.Line(t.Scrollback()+y)))
+	}
+	for len(out) > 0 && out[len(out)-1] == "" {
+		out = out[:len(out)-1]
+	}
+	return out
+}
+
+func lineText(l *Line) string {
+	var sb strings.Builder
+	for _, c := range l.Cells {
+		switch {
+		case c.Tail:
+		case c.Rune == 0:
+			sb.WriteByte(' ')
+		default:
+			sb.WriteRune(c.Rune)
+		}
+	}
+	return strings.TrimRight(sb.String(), " ")
+}
+
+func equalRows(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// write feeds s to a terminal of cols by rows cells.
+func write(cols, rows int, s string) *Terminal {
+	t := New(cols, rows)
+	t.Write([]byte(s))
+	return t
+}
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []string
+		x, y int
+	}{
+		{"text", "hello", []string{"hello"}, 5, 0},
+		{"newline", "ab\r\ncd", []string{"ab", "cd"}, 2, 1},
+		{"line feed keeps the column", "ab\ncd", []string{"ab", "  cd"}, 4, 1},
+		{"backspace", "abc\bX", []string{"abX"}, 3, 0},
+		{"backspace at the margin", "\bX", []string{"X"}, 1, 0},
+		{"tab", "a\tb", []string{"a       b"}, 9, 0},
+		{"cursor position", "\x1b[2;3HX", []string{"", "  X"}, 3, 1},
+		{"cursor position defaults", "abc\x1b[HX", []string{"Xbc"}, 1, 0},
+		{"cursor position clamped", "\x1b[99;99HX", []string{"", "", "", "         X"}, 9, 3},
+		{"cursor moves", "\x1b[3B\x1b[4CX\x1b[2A\x1b[2DY", []string{"", "   Y", "", "    X"}, 4, 1},
+		{"next and previous line", "ab\x1b[2EX\x1b[FY", []string{"ab", "Y", "X"}, 1, 1},
+		{"column and row", "\x1b[5GX\x1b[3dY", []string{"    X", "", "     Y"}, 6, 2},
+		{"erase to the end of the line", "abcdef\x1b[1;3H\x1b[K", []string{"ab"}, 2, 0},
+		{"erase to the cursor", "abcdef\x1b[1;3H\x1b[1K", []string{"   def"}, 2, 0},
+		{"erase the line", "abcdef\x1b[2K", nil, 6, 0},
+		{"erase characters", "abcdef\x1b[1;2H\x1b[2X", []string{"a  def"}, 1, 0},
+		{"delete characters", "abcdef\x1b[1;2H\x1b[2P", []string{"adef"}, 1, 0},
+		{"insert characters", "abc\x1b[1;2H\x1b[2@", []string{"a  bc"}, 1, 0},
+		{"insert mode", "abc\x1b[4h\x1b[1;1HX", []string{"Xabc"}, 1, 0},
+		{"newline mode", "\x1b[20hab\ncd", []string{"ab", "cd"}, 2, 1},
+		{"repeat", "a\x1b[3b", []string{"aaaa"}, 4, 0},
+		{"repeat without a character", "\x1b[3b", nil, 0, 0},
+		{"line drawing", "\x1b(0qx\x1b(Bq", []string{"─│q"}, 3, 0},
+		{"shift to G1", "\x1b)0a\x0eq\x0fq", []string{"a─q"}, 3, 0},
+		{"utf-8", "héllo 世界", []string{"héllo 世界"}, 9, 0},
+		{"invalid utf-8", "\xffa", []string{"�a"}, 2, 0},
+		{"cut utf-8", "\xc3a", []string{"�a"}, 2, 0},
+		{"combining marks take no cell", "éx", []string{"ex"}, 2, 0},
+		{"wrap", "abcdefghijklm", []string{"abcdefghij", "klm"}, 3, 1},
+		{"no wrap", "\x1b[?7labcdefghijklm", []string{"abcdefghim"}, 9, 0},
+		{"wide character at the margin", "abcdefghi世", []string{"abcdefghi", "世"}, 2, 1},
+		{"carriage return cancels the wrap", "abcdefghij\rX", []string{"Xbcdefghij"}, 1, 0},
+		{"title is not printed", "\x1b]0;title\x07X", []string{"X"}, 1, 0},
+		{"device control string is ignored", "\x1bPqabc\x1b\\X", []string{"X"}, 1, 0},
+		{"string ended by another escape", "\x1b_abc\x1b[2CX", []string{"  X"}, 3, 0},
+		{"cancel", "\x1b[2\x18X", []string{"X"}, 1, 0},
+		{"control inside a sequence", "ab\x1b[\r2CX", []string{"abX"}, 3, 0},
+		{"unknown sequences", "\x1b[99zA\x1b%GB\x1b[>5mC", []string{"ABC"}, 3, 0},
+		{"screen alignment", "\x1b#8", []string{"EEEEEEEEEE", "EEEEEEEEEE", "EEEEEEEEEE", "EEEEEEEEEE"}, 0, 0},
+		{"reset", "abc\x1b[?25l\x1bcX", []string{"X"}, 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			term := write(10, 4, tt.in)
+			if got := rows(term); !equalRows(got, tt.want) {
+				t.Errorf("rows %q, want %q", got, tt.want)
+			}
+			if x, y, _ := term.Cursor(); x != tt.x || y != tt.y {
+				t.Errorf("cursor at %d, %d, want %d, %d", x, y, tt.x, tt.y)
+			}
+		})
+	}
+}
+
+func TestWriteInPieces(t *testing.T) {
+	const in = "\x1b[31mhé\x1b]2;title\x1b\\\x1b[2;3H世"
+	whole := write(10, 4, in)
+	pieces := New(10, 4)
+	for i := range len(in) {
+		pieces.Write([]byte{in[i]})
+	}
+	if got, want := rows(pieces), rows(whole); !equalRows(got, want) {
+		t.Errorf("byte by byte gave %q, want %q", got, want)
+	}
+	if pieces.Title() != "title" || pieces.Line(0).Cells[0].Style.FG != Indexed(1) {
+		t.Errorf("byte by byte gave title %q and style %v", pieces.Title(), pieces.Line(0).Cells[0].Style)
+	}
+}
+
+func TestMalformedSequences(t *testing.T) {
+	tests := []string{
+		"\x1b[99999999999999999999A",
+		"\x1b[" + strings.Repeat("1;", 1000) + "m",
+		"\x1b[38:" + strings.Repeat("2:", 1000) + "m",
+		"\x1b[38;2m\x1b[48;5m\x1b[38:2:1m",
+		"\x1b[99999999999b",
+		"\x1b[5;2r\x1b[50;60r\x1b[0;0r",
+	}
+	for _, in := range tests {
+		term := write(10, 4, in+"X")
+		if x, y, _ := term.Cursor(); x < 0 || x >= 10 || y < 0 || y >= 4 {
+			t.Errorf("%q left the cursor at %d, %d", in, x, y)
+		}
+	}
+
+	term := write(10, 4, "\x1b]2;"+strings.Repeat("a", 2*maxOSC)+"\x07")
+	if n := len(term.Title()); n > maxOSC {
+		t.Errorf("a title of %d bytes was kept", n)
+	}
+}
+
+func TestTitle(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"\x1b]0;one\x07", "one"},
+		{"\x1b]2;two\x1b\\", "two"},
+		{"\x1b]1;icon\x07", ""},
+		{"\x1b]2;a;b\x07", "a;b"},
+		{"\x1b]2;first\x07\x1b]2;\x07", ""},
+		{"\x1b]2;cut\x18", ""},
+	}
+	for _, tt := range tests {
+		if got := write(10, 4, tt.in).Title(); got != tt.want {
+			t.Errorf("%q: Title() = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSGR(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Style
+	}{
+		{"", Style{}},
+		{"\x1b[1;31m", Style{FG: Indexed(1), Attr: Bold}},
+		{"\x1b[2;3;4;5;7;8;9m", Style{Attr: Faint | Italic | Underline | Blink | Inverse | Hidden | Strike}},
+		{"\x1b[1;2;3;4;5;7;8;9m\x1b[22;23;24;25;27;28;29m", Style{}},
+		{"\x1b[4m\x1b[4:0m", Style{}},
+		{"\x1b[4:3m", Style{Attr: Underline}},
+		{"\x1b[21m", Style{Attr: Underline}},
+		{"\x1b[31;42m\x1b[39;49m", Style{}},
+		{"\x1b[1;31m\x1b[m", Style{}},
+		{"\x1b[1;31m\x1b[0m", Style{}},
+		{"\x1b[97;104m", Style{FG: Indexed(15), BG: Indexed(12)}},
+		{"\x1b[38;5;200m", Style{FG: Indexed(200)}},
+		{"\x1b[48;5;17;1m", Style{BG: Indexed(17), Attr: Bold}},
+		{"\x1b[38:5:9m", Style{FG: Indexed(9)}},
+		{"\x1b[38;2;1;2;3;4m", Style{FG: RGB(1, 2, 3), Attr: Underline}},
+		{"\x1b[48:2::1:2:3m", Style{BG: RGB(1, 2, 3)}},
+		{"\x1b[48:2:1:2:3m", Style{BG: RGB(1, 2, 3)}},
+		{"\x1b[31m\x1b[38;2;1m", Style{}},
+	}
+	for _, tt := range tests {
+		term := write(10, 4, tt.in+"X")
+		if got := term.Line(0).Cells[0].Style; got != tt.want {
+			t.Errorf("%q: style %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestColor(t *testing.T) {
+	if i, ok := Indexed(200).Index(); i != 200 || !ok {
+		t.Errorf("Indexed(200).Index() = %v, %v", i, ok)
+	}
+	if _, _, _, ok := Indexed(200).RGB(); ok {
+		t.Error("an indexed color has RGB components")
+	}
+	if r, g, b, ok := RGB(1, 2, 3).RGB(); r != 1 || g != 2 || b != 3 || !ok {
+		t.Errorf("RGB(1, 2, 3).RGB() = %v, %v, %v, %v", r, g, b, ok)
+	}
+	for _, c := range []Color{DefaultColor, RGB(0, 0, 0)} {
+		if _, ok := c.Index(); ok {
+			t.Errorf("%#x is indexed", c)
+		}
+	}
+	if Indexed(0) == DefaultColor || RGB(0, 0, 0) == DefaultColor {
+		t.Error("black is the default color")
+	}
+}
+
+func TestReplies(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"\x1b[5n", "\x1b[0n"},
+		{"\x1b[3;4H\x1b[6n", "\x1b[3;4R"},
+		{"\x1b[2;4r\x1b[?6h\x1b[2;2H\x1b[6n", "\x1b[2;2R"},
+		{"\x1b[c", "\x1b[?62;22c"},
+		{"\x1b[0c", "\x1b[?62;22c"},
+		{"\x1b[>c", "\x1b[>1;10;0c"},
+		{"\x1b[1c\x1b[7n", ""},
+	}
+	for _, tt := range tests {
+		term := New(10, 4)
+		var got []byte
+		term.Reply = func(b []byte) { got = append(got, b...) }
+		term.Write([]byte(tt.in))
+		if string(got) != tt.want {
+			t.Errorf("%q replied %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	// Without Reply the requests are dropped.
+	write(10, 4, "\x1b[6n")
+}
+
+func TestBell(t *testing.T) {
+	term := New(10, 4)
+	rings := 0
+	term.Bell = func() { rings++ }
+	term.Write([]byte("a\ab\x1b]0;title\x07"))
+	if rings != 1 {
+		t.Errorf("rang %d times, want once", rings)
+	}
+	write(10, 4, "\a")
+}
+
+func TestModes(t *testing.T) {
+	tests := []struct {
+		in        string
+		appCursor bool
+		hidden    bool
+		paste     bool
+		mouse     MouseMode
+		sgr       bool
+		shape     CursorShape
+	}{
+		{"", false, false, false, MouseOff, false, CursorBlock},
+		{"\x1b[?1h", true, false, false, MouseOff, false, CursorBlock},
+		{"\x1b[?1h\x1b[?1l", false, false, false, MouseOff, false, CursorBlock},
+		{"\x1b[?25l", false, true, false, MouseOff, false, CursorBlock},
+		{"\x1b[?2004h", false, false, true, MouseOff, false, CursorBlock},
+		{"\x1b[?1000h", false, false, false, MouseClicks, false, CursorBlock},
+		{"\x1b[?1002;1006h", false, false, false, MouseDrags, true, CursorBlock},
+		{"\x1b[?1003h\x1b[?1003l", false, false, false, MouseOff, false, CursorBlock},
+		{"\x1b[?1000h\x1b[?1002l", false, false, false, MouseClicks, false, CursorBlock},
+		{"\x1b[4 q", false, false, false, MouseOff, false, CursorUnderline},
+		{"\x1b[5 q", false, false, false, MouseOff, false, CursorBar},
+		{"\x1b[5 q\x1b[ q", false, false, false, MouseOff, false, CursorBlock},
+		{"\x1b[?1;25;2004;1000l\x1b[?25l\x1b[!p", false, false, false, MouseOff, false, CursorBlock},
+		{"\x1b[?1h\x1b[?2004h\x1b[5 q\x1bc", false, false, false, MouseOff, false, CursorBlock},
+	}
+	for _, tt := range tests {
+		term := write(10, 4, tt.in)
+		_, _, visible := term.Cursor()
+		mouse, sgr := term.Mouse()
+		if term.AppCursor() != tt.appCursor || visible == tt.hidden || term.BracketedPaste() != tt.paste ||
+			mouse != tt.mouse || sgr != tt.sgr || term.CursorShape() != tt.shape {
+			t.Errorf("%q: app cursor %v, visible %v, paste %v, mouse %v, sgr %v, shape %v",
+				tt.in, term.AppCursor(), visible, term.BracketedPaste(), mouse, sgr, term.CursorShape())
+		}
+	}
+}
+
+func TestSoftReset(t *testing.T) {
+	term := write(10, 4, "\x1b[2;3r\x1b[?6h\x1b[?7l\x1b[4h\x1b[1m\x1b(0\x1b[!pabcdefghijklq")
+	// The cursor stays where the scroll region put it.
+	if got, want := rows(term), []string{"", "abcdefghij", "klq"}; !equalRows(got, want) {
+		t.Errorf("rows %q, want %q", got, want)
+	}
+	if term.Line(0).Cells[0].Style != (Style{}) {
+		t.Errorf("the style survived: %+v", term.Line(0).Cells[0].Style)
+	}
+}
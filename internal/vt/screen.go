@@ -0,0 +1,638 @@
+// Package vt emulates the screen of a VT100/xterm-compatible terminal: it
+// interprets the output of programs, including control sequences, and
+// maintains the resulting grid of cells, the cursor and the scrollback.
+// widgets.Terminal draws the grid and turns input into the sequences
+// programs expect.
+//
+// The emulation covers what shells and full-screen programs commonly use:
+// cursor movement, erasing, insertion and deletion, scroll regions, tab
+// stops, 16, 256 and 24-bit colors, the alternate screen, DEC line drawing,
+// bracketed paste, mouse reporting, window titles and status reports.
+package vt
+
+import "unicode"
+
+// Color is the color of a cell: the default color, an index into the
+// 256-color palette, or a 24-bit RGB value.
+type Color uint32
+
+// DefaultColor is the terminal's default foreground or background.
+const DefaultColor Color = 0
+
+const (
+	colorIndexed Color = 1 << 24
+	colorRGB     Color = 2 << 24
+)
+
+// Indexed returns palette color i. Colors 0 to 15 are the ANSI colors.
+func Indexed(i uint8) Color {
+	return colorIndexed | Color(i)
+}
+
+// RGB returns a 24-bit color.
+func RGB(r, g, b uint8) Color {
+	return colorRGB | Color(r)<<16 | Color(g)<<8 | Color(b)
+}
+
+// Index returns the palette index of an indexed color.
+func (c Color) Index() (i uint8, ok bool) {
+	return uint8(c), c&^0xFFFFFF == colorIndexed
+}
+
+// RGB returns the components of a 24-bit color.
+func (c Color) RGB() (r, g, b uint8, ok bool) {
+	return uint8(c >> 16), uint8(c >> 8), uint8(c), c&^0xFFFFFF == colorRGB
+}
+
+// Attr is a set of character attributes.
+type Attr uint8
+
+// Character attributes.
+const (
+	Bold Attr = 1 << iota
+	Faint
+	Italic
+	Underline
+	Blink
+	Inverse
+	Hidden
+	Strike
+)
+
+// Style is how a cell is drawn.
+type Style struct {
+	FG, BG Color
+	Attr   Attr
+}
+
+// Cell is one position of the grid. An empty cell has Rune 0. A wide
+// character occupies two cells: the first is Wide and holds the rune, the
+// second is its Tail.
+type Cell struct {
+	Rune  rune
+	Style Style
+	Wide  bool
+	Tail  bool
+}
+
+// Line is a row of cells. Wrapped is set when the text continues on the
+// next line because it reached the right margin.
+type Line struct {
+	Cells   []Cell
+	Wrapped bool
+}
+
+// CursorShape is the shape a program asked the cursor to be drawn in.
+type CursorShape uint8
+
+// Cursor shapes.
+const (
+	CursorBlock CursorShape = iota
+	CursorUnderline
+	CursorBar
+)
+
+// MouseMode is the kind of mouse events a program asked to be reported.
+type MouseMode uint8
+
+// Mouse modes.
+const (
+	MouseOff    MouseMode = iota
+	MouseClicks           // presses and releases
+	MouseDrags            // and motion with a button held
+	MouseMotion           // and all motion
+)
+
+// DefaultScrollback is the number of lines kept above the screen.
+const DefaultScrollback = 10000
+
+type cursor struct {
+	x, y  int
+	style Style
+	// wrap is set after a character is printed in the last column: the
+	// next one goes on a new line.
+	wrap bool
+}
+
+type savedCursor struct {
+	cursor
+	origin   bool
+	charsets [2]byte
+	shift    int
+}
+
+// Terminal is the state of an emulated terminal. It is not safe for
+// concurrent use.
+type Terminal struct {
+	// Reply, if set, receives the bytes the terminal sends back to the
+	// program, such as answers to status requests.
+	Reply func(b []byte)
+	// Bell, if set, is called for the BEL character.
+	Bell func()
+	// MaxScrollback limits the lines kept above the screen.
+	MaxScrollback int
+
+	cols, rows int
+	lines      []Line // of the active screen
+	other      []Line // of the inactive screen
+	alt        bool
+	scrollback []Line
+	pushed     int // lines ever pushed into the scrollback
+
+	cur         cursor
+	saved       [2]savedCursor // for the main and alternate screens
+	top, bottom int            // the scroll region, inclusive
+	tabs        []bool
+	charsets    [2]byte // G0 and G1: 'B' for ASCII, '0' for line drawing
+	shift       int     // the charset in use
+	last        rune    // the last printed character, for REP
+
+	autowrap, origin, insert, newline  bool
+	cursorHidden, appCursor, appKeypad bool
+	bracketedPaste, sgrMouse           bool
+	mouse                              MouseMode
+	shape                              CursorShape
+	title                              string
+
+	p parser
+}
+
+// New returns a terminal cols wide and rows high.
+func New(cols, rows int) *Terminal {
+	t := &Terminal{MaxScrollback: DefaultScrollback}
+	t.cols, t.rows = max(1, cols), max(1, rows)
+	t.reset()
+	return t
+}
+
+// reset returns the terminal to its initial state, keeping the
+// scrollback.
+func (t *Terminal) reset() {
+	t.lines = t.blankLines(t.rows)
+	t.other = t.blankLines(t.rows)
+	t.alt = false
+	t.cur = cursor{}
+	t.saved = [2]savedCursor{}
+	t.top, t.bottom = 0, t.rows-1
+	t.resetTabs()
+	t.charsets = [2]byte{'B', 'B'}
+	t.shift = 0
+	t.autowrap, t.origin, t.insert, t.newline = true, false, false, false
+	t.cursorHidden, t.appCursor, t.appKeypad = false, false, false
+	t.bracketedPaste, t.sgrMouse, t.mouse = false, false, MouseOff
+	t.shape = CursorBlock
+	t.title = ""
+	t.p = parser{}
+}
+
+func (t *Terminal) resetTabs() {
+	t.tabs = make([]bool, t.cols)
+	for i := 8; i < t.cols; i += 8 {
+		t.tabs[i] = true
+	}
+}
+
+func (t *Terminal) blankLine() Line {
+	cells := make([]Cell, t.cols)
+	if bg := t.cur.style.BG; bg != DefaultColor {
+		for i := range cells {
+			cells[i].Style.BG = bg
+		}
+	}
+	return Line{Cells: cells}
+}
+
+func (t *Terminal) blankLines(n int) []Line {
+	lines := make([]Line, n)
+	for i := range lines {
+		lines[i] = Line{Cells: make([]Cell, t.cols)}
+	}
+	return lines
+}
+
+// blank returns an erased cell, which keeps the current background.
+func (t *Terminal) blank() Cell {
+	return Cell{Style: Style{BG: t.cur.style.BG}}
+}
+
+// Size returns the size of the screen in cells.
+func (t *Terminal) Size() (cols, rows int) {
+	return t.cols, t.rows
+}
+
+// Len returns the number of lines of scrollback and screen together.
+func (t *Terminal) Len() int {
+	return len(t.scrollback) + t.rows
+}
+
+// Scrollback returns the number of lines above the screen.
+func (t *Terminal) Scrollback() int {
+	return len(t.scrollback)
+}
+
+// Pushed returns the number of lines ever scrolled into the scrollback,
+// which tells a view how far to move to stay on the same text.
+func (t *Terminal) Pushed() int {
+	return t.pushed
+}
+
+// Line returns line i of the scrollback and screen together; the screen
+// starts at Scrollback. The line must not be modified.
+func (t *Terminal) Line(i int) *Line {
+	if i < len(t.scrollback) {
+		return &t.scrollback[i]
+	}
+	return &t.lines[i-len(t.scrollback)]
+}
+
+// ClearScrollback discards the lines above the screen.
+func (t *Terminal) ClearScrollback() {
+	t.scrollback = nil
+}
+
+// Cursor returns the cursor position on the screen and whether it is
+// shown.
+func (t *Terminal) Cursor() (x, y int, visible bool) {
+	return t.cur.x, t.cur.y, !t.cursorHidden
+}
+
+// CursorShape returns the shape of the cursor.
+func (t *Terminal) CursorShape() CursorShape {
+	return t.shape
+}
+
+// Title returns the window title set by the program.
+func (t *Terminal) Title() string {
+	return t.title
+}
+
+// AltScreen reports whether the alternate screen, used by full-screen
+// programs, is active.
+func (t *Terminal) AltScreen() bool {
+	return t.alt
+}
+
+// AppCursor reports whether the cursor keys send application sequences.
+func (t *Terminal) AppCursor() bool {
+	return t.appCursor
+}
+
+// BracketedPaste reports whether pasted text is to be bracketed.
+func (t *Terminal) BracketedPaste() bool {
+	return t.bracketedPaste
+}
+
+// Mouse returns the mouse events the program wants reported, and whether
+// they are to be reported in the SGR encoding.
+func (t *Terminal) Mouse() (mode MouseMode, sgr bool) {
+	return t.mouse, t.sgrMouse
+}
+
+// Resize changes the size of the screen. Lines are cut or extended, not
+// rewrapped. Shrinking the main screen moves the lines above the cursor
+// into the scrollback and growing it brings them back.
+func (t *Terminal) Resize(cols, rows int) {
+	cols, rows = max(1, cols), max(1, rows)
+	if cols == t.cols && rows == t.rows {
+		return
+	}
+	// Only the active main screen trades lines with the scrollback.
+	resize := func(lines []Line, main bool, y *int) []Line {
+		for i := range lines {
+			lines[i] = fitLine(lines[i], cols)
+		}
+		if n := min(*y+1-rows, len(lines)); n > 0 {
+			if main {
+				t.push(lines[:n])
+			}
+			lines = lines[n:]
+			*y -= n
+		}
+		switch {
+		case len(lines) > rows:
+			lines = lines[:rows]
+		case len(lines) < rows && main && len(t.scrollback) > 0:
+			n := min(rows-len(lines), len(t.scrollback))
+			back := append([]Line(nil), t.scrollback[len(t.scrollback)-n:]...)
+			t.scrollback = t.scrollback[:len(t.scrollback)-n]
+			for i := range back {
+				back[i] = fitLine(back[i], cols)
+			}
+			lines = append(back, lines...)
+			*y += n
+		}
+		for len(lines) < rows {
+			lines = append(lines, Line{Cells: make([]Cell, cols)})
+		}
+		return lines
+	}
+	var other int
+	t.lines = resize(t.lines, !t.alt, &t.cur.y)
+	t.other = resize(t.other, false, &other)
+	t.cols, t.rows = cols, rows
+	t.top, t.bottom = 0, rows-1
+	t.resetTabs()
+	t.cur.x = min(t.cur.x, cols-1)
+	t.cur.y = min(max(t.cur.y, 0), rows-1)
+	t.cur.wrap = false
+}
+
+// fitLine cuts or extends l to cols cells.
+func fitLine(l Line, cols int) Line {
+	switch {
+	case len(l.Cells) > cols:
+		l.Cells = l.Cells[:cols]
+		if l.Cells[cols-1].Wide {
+			l.Cells[cols-1] = Cell{}
+		}
+		l.Wrapped = false
+	case len(l.Cells) < cols:
+		l.Cells = append(l.Cells, make([]Cell, cols-len(l.Cells))...)
+	}
+	return l
+}
+
+// push moves lines, which the caller removes from the screen, into the
+// scrollback.
+func (t *Terminal) push(lines []Line) {
+	t.scrollback = append(t.scrollback, lines...)
+	t.pushed += len(lines)
+	if n := len(t.scrollback) - max(0, t.MaxScrollback); n > 0 {
+		// Appending copies the kept lines to a new array from time to
+		// time, which releases the dropped ones.
+		t.scrollback = t.scrollback[n:]
+	}
+}
+
+// scrollUp scrolls the scroll region up n lines. Lines scrolled off the
+// top of the whole main screen go into the scrollback.
+func (t *Terminal) scrollUp(n int) {
+	n = min(n, t.bottom-t.top+1)
+	if n <= 0 {
+		return
+	}
+	if !t.alt && t.top == 0 {
+		t.push(t.lines[:n])
+	}
+	copy(t.lines[t.top:], t.lines[t.top+n:t.bottom+1])
+	for i := t.bottom - n + 1; i <= t.bottom; i++ {
+		t.lines[i] = t.blankLine()
+	}
+}
+
+// scrollDown scrolls the scroll region down n lines.
+func (t *Terminal) scrollDown(n int) {
+	n = min(n, t.bottom-t.top+1)
+	if n <= 0 {
+		return
+	}
+	copy(t.lines[t.top+n:], t.lines[t.top:t.bottom+1-n])
+	for i := t.top; i < t.top+n; i++ {
+		t.lines[i] = t.blankLine()
+	}
+}
+
+// lineFeed moves the cursor down, scrolling at the bottom of the region.
+func (t *Terminal) lineFeed() {
+	t.cur.wrap = false
+	switch {
+	case t.cur.y == t.bottom:
+		t.scrollUp(1)
+	case t.cur.y < t.rows-1:
+		t.cur.y++
+	}
+}
+
+// reverseIndex moves the cursor up, scrolling at the top of the region.
+func (t *Terminal) reverseIndex() {
+	t.cur.wrap = false
+	switch {
+	case t.cur.y == t.top:
+		t.scrollDown(1)
+	case t.cur.y > 0:
+		t.cur.y--
+	}
+}
+
+// moveTo moves the cursor to column x of row y, clamped to the screen,
+// or in origin mode to the scroll region, which y is then relative to.
+func (t *Terminal) moveTo(x, y int) {
+	lo, hi := 0, t.rows-1
+	if t.origin {
+		lo, hi = t.top, t.bottom
+		y += t.top
+	}
+	t.cur.x = min(max(x, 0), t.cols-1)
+	t.cur.y = min(max(y, lo), hi)
+	t.cur.wrap = false
+}
+
+// moveRows moves the cursor n rows down, or up if n is negative, stopping
+// at the margins of the scroll region if it starts inside it.
+func (t *Terminal) moveRows(n int) {
+	lo, hi := 0, t.rows-1
+	if t.cur.y >= t.top && t.cur.y <= t.bottom {
+		lo, hi = t.top, t.bottom
+	}
+	t.cur.y = min(max(t.cur.y+n, lo), hi)
+	t.cur.wrap = false
+}
+
+// print writes r at the cursor and advances it.
+func (t *Terminal) print(r rune) {
+	if t.charsets[t.shift] == '0' && r >= 0x5f && r <= 0x7e {
+		r = lineDrawing[r-0x5f]
+	}
+	w := runeWidth(r)
+	if w == 0 {
+		return
+	}
+	t.last = r
+	if t.autowrap && (t.cur.wrap || t.cur.x+w > t.cols) {
+		t.lines[t.cur.y].Wrapped = true
+		t.cur.x = 0
+		t.lineFeed()
+	}
+	t.cur.wrap = false
+	if t.cur.x+w > t.cols {
+		t.cur.x = t.cols - w
+	}
+	cells := t.lines[t.cur.y].Cells
+	if t.insert {
+		copy(cells[t.cur.x+w:], cells[t.cur.x:])
+	}
+	t.clearWide(t.cur.y, t.cur.x)
+	if w == 2 {
+		t.clearWide(t.cur.y, t.cur.x+1)
+	}
+	cells[t.cur.x] = Cell{Rune: r, Style: t.cur.style, Wide: w == 2}
+	if w == 2 {
+		cells[t.cur.x+1] = Cell{Style: t.cur.style, Tail: true}
+	}
+	if t.cur.x+w >= t.cols {
+		t.cur.x = t.cols - 1
+		t.cur.wrap = t.autowrap
+	} else {
+		t.cur.x += w
+	}
+}
+
+// clearWide erases the other half of a wide character at column x of row
+// y, which is about to be overwritten.
+func (t *Terminal) clearWide(y, x int) {
+	cells := t.lines[y].Cells
+	switch {
+	case cells[x].Wide && x+1 < t.cols:
+		cells[x+1] = t.blank()
+	case cells[x].Tail && x > 0:
+		cells[x-1] = t.blank()
+	}
+}
+
+// erase blanks columns [from, to) of row y.
+func (t *Terminal) erase(y, from, to int) {
+	from, to = max(from, 0), min(to, t.cols)
+	if from >= to {
+		return
+	}
+	t.clearWide(y, from)
+	t.clearWide(y, to-1)
+	cells := t.lines[y].Cells
+	for x := from; x < to; x++ {
+		cells[x] = t.blank()
+	}
+	if to == t.cols {
+		t.lines[y].Wrapped = false
+	}
+}
+
+// insertLines inserts n blank lines at the cursor, in the scroll region.
+func (t *Terminal) insertLines(n int) {
+	if t.cur.y < t.top || t.cur.y > t.bottom {
+		return
+	}
+	top := t.top
+	t.top = t.cur.y
+	t.scrollDown(n)
+	t.top = top
+	t.cur.x, t.cur.wrap = 0, false
+}
+
+// deleteLines deletes n lines at the cursor, in the scroll region.
+func (t *Terminal) deleteLines(n int) {
+	if t.cur.y < t.top || t.cur.y > t.bottom {
+		return
+	}
+	top := t.top
+	t.top = t.cur.y
+	// Deleted lines never go into the scrollback.
+	alt := t.alt
+	t.alt = true
+	t.scrollUp(n)
+	t.alt = alt
+	t.top = top
+	t.cur.x, t.cur.wrap = 0, false
+}
+
+// insertChars inserts n blanks at the cursor, shifting the rest of the
+// line right.
+func (t *Terminal) insertChars(n int) {
+	cells := t.lines[t.cur.y].Cells
+	n = min(n, t.cols-t.cur.x)
+	t.clearWide(t.cur.y, t.cur.x)
+	copy(cells[t.cur.x+n:], cells[t.cur.x:])
+	for x := t.cur.x; x < t.cur.x+n; x++ {
+		cells[x] = t.blank()
+	}
+	t.cur.wrap = false
+}
+
+// deleteChars deletes n characters at the cursor, shifting the rest of
+// the line left.
+func (t *Terminal) deleteChars(n int) {
+	cells := t.lines[t.cur.y].Cells
+	n = min(n, t.cols-t.cur.x)
+	t.clearWide(t.cur.y, t.cur.x)
+	copy(cells[t.cur.x:], cells[t.cur.x+n:])
+	for x := t.cols - n; x < t.cols; x++ {
+		cells[x] = t.blank()
+	}
+	t.cur.wrap = false
+}
+
+// tab moves the cursor n tab stops forward, or back if n is negative.
+func (t *Terminal) tab(n int) {
+	for ; n > 0 && t.cur.x < t.cols-1; n-- {
+		for t.cur.x++; t.cur.x < t.cols-1 && !t.tabs[t.cur.x]; t.cur.x++ {
+		}
+	}
+	for ; n < 0 && t.cur.x > 0; n++ {
+		for t.cur.x--; t.cur.x > 0 && !t.tabs[t.cur.x]; t.cur.x-- {
+		}
+	}
+	t.cur.wrap = false
+}
+
+// saveCursor saves the cursor of the active screen.
+func (t *Terminal) saveCursor() {
+	t.saved[t.screen()] = savedCursor{cursor: t.cur, origin: t.origin, charsets: t.charsets, shift: t.shift}
+}
+
+// restoreCursor restores the cursor saved by saveCursor.
+func (t *Terminal) restoreCursor() {
+	s := t.saved[t.screen()]
+	t.cur, t.origin, t.charsets, t.shift = s.cursor, s.origin, s.charsets, s.shift
+	t.cur.x = min(t.cur.x, t.cols-1)
+	t.cur.y = min(t.cur.y, t.rows-1)
+}
+
+func (t *Terminal) screen() int {
+	if t.alt {
+		return 1
+	}
+	return 0
+}
+
+// setAlt switches between the main and alternate screens, clearing the
+// alternate screen on entry if clear is set.
+func (t *Terminal) setAlt(alt, clear bool) {
+	if alt == t.alt {
+		return
+	}
+	t.lines, t.other = t.other, t.lines
+	t.alt = alt
+	if alt && clear {
+		for y := range t.lines {
+			t.lines[y] = t.blankLine()
+		}
+	}
+}
+
+// lineDrawing maps 0x5f to 0x7e in the DEC special graphics character set.
+var lineDrawing = [...]rune{
+	' ', '◆', '▒', '␉', '␌', '␍', '␊', '°', '±', '␤', '␋', '┘', '┐', '┌', '└', '┼',
+	'⎺', '⎻', '─', '⎼', '⎽', '├', '┤', '┴', '┬', '│', '≤', '≥', 'π', '≠', '£', '·',
+}
+
+// runeWidth returns the number of cells r occupies.
+func runeWidth(r rune) int {
+	switch {
+	case r < 0x20 || r >= 0x7f && r < 0xa0:
+		return 0
+	case r < 0x300:
+		return 1
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) || r == 0x200b:
+		return 0
+	case r >= 0x1100 && (r <= 0x115F ||
+		r >= 0x2E80 && r <= 0xA4CF && r != 0x303F ||
+		r >= 0xAC00 && r <= 0xD7A3 ||
+		r >= 0xF900 && r <= 0xFAFF ||
+		r >= 0xFE30 && r <= 0xFE4F ||
+		r >= 0xFF00 && r <= 0xFF60 ||
+		r >= 0xFFE0 && r <= 0xFFE6 ||
+		r >= 0x1F300 && r <= 0x1FAFF ||
+		r >= 0x20000 && r <= 0x3FFFD):
+		return 2
+	}
+	return 1
+}
@@ -0,0 +1,308 @@
+package vt
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// numbered writes lines 1 to n to a fresh terminal, one per line.
+func numbered(cols, rows, n int) *Terminal {
+	var sb strings.Builder
+	for i := 1; i <= n; i++ {
+		if i > 1 {
+			sb.WriteString("\r\n")
+		}
+		fmt.Fprint(&sb, i)
+	}
+	return write(cols, rows, sb.String())
+}
+
+func TestScrollback(t *testing.T) {
+	term := numbered(5, 3, 5)
+	if got, want := rows(term), []string{"3", "4", "5"}; !equalRows(got, want) {
+		t.Errorf("rows %q, want %q", got, want)
+	}
+	if term.Scrollback() != 2 || term.Pushed() != 2 || term.Len() != 5 {
+		t.Errorf("Scrollback() = %d, Pushed() = %d, Len() = %d", term.Scrollback(), term.Pushed(), term.Len())
+	}
+	for i, want := range []string{"1", "2", "3"} {
+		if got := lineText(term.Line(i)); got != want {
+			t.Errorf("Line(%d) = %q, want %q", i, got, want)
+		}
+	}
+
+	term.MaxScrollback = 1
+	term.Write([]byte("\r\n6"))
+	if term.Scrollback() != 1 || term.Pushed() != 3 || lineText(term.Line(0)) != "3" {
+		t.Errorf("limited to one line, the scrollback holds %d lines from %q", term.Scrollback(), lineText(term.Line(0)))
+	}
+	term.Write([]byte("\x1b[3J"))
+	if term.Scrollback() != 0 || term.Pushed() != 3 {
+		t.Errorf("ED 3 left %d lines", term.Scrollback())
+	}
+
+	term = numbered(5, 3, 3)
+	term.ClearScrollback()
+	term.Write([]byte("\x1bc"))
+	if term.Scrollback() != 0 || len(rows(term)) != 0 {
+		t.Errorf("reset left rows %q", rows(term))
+	}
+	term = numbered(5, 3, 5)
+	term.Write([]byte("\x1bc"))
+	if term.Scrollback() != 2 {
+		t.Error("reset dropped the scrollback")
+	}
+}
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []string
+		back int
+	}{
+		{"scroll region", "\x1b[2;4r\x1b[4;1H\n", []string{"1", "3", "4", "", "5"}, 0},
+		{"reverse index in the region", "\x1b[2;4r\x1b[2;1H\x1bM", []string{"1", "", "2", "3", "5"}, 0},
+		{"reverse index at the top", "\x1b[H\x1bM", []string{"", "1", "2", "3", "4"}, 0},
+		{"index and next line", "\x1b[5;3H\x1bD\x1bEX", []string{"3", "4", "5", "", "X"}, 2},
+		{"scroll up", "\x1b[2S", []string{"3", "4", "5"}, 2},
+		{"scroll down", "\x1b[2T", []string{"", "", "1", "2", "3"}, 0},
+		{"scroll up in the region", "\x1b[2;4r\x1b[S", []string{"1", "3", "4", "", "5"}, 0},
+		{"insert lines", "\x1b[2;1H\x1b[L", []string{"1", "", "2", "3", "4"}, 0},
+		{"insert lines in the region", "\x1b[1;3r\x1b[2;1H\x1b[5L", []string{"1", "", "", "4", "5"}, 0},
+		{"insert lines below the region", "\x1b[1;3r\x1b[5;1H\x1b[L", []string{"1", "2", "3", "4", "5"}, 0},
+		{"delete lines", "\x1b[2;1H\x1b[2M", []string{"1", "4", "5"}, 0},
+		{"delete the top line", "\x1b[H\x1b[M", []string{"2", "3", "4", "5"}, 0},
+		{"erase below", "\x1b[3;2H\x1b[J", []string{"1", "2", "3"}, 0},
+		{"erase above", "\x1b[3;1H\x1b[1J", []string{"", "", "", "4", "5"}, 0},
+		{"erase all", "\x1b[2J", nil, 0},
+		{"cursor stops at the region", "\x1b[2;4r\x1b[3;1H\x1b[9AX\x1b[9BY", []string{"1", "X", "3", "4Y", "5"}, 0},
+		{"cursor leaves from outside the region", "\x1b[2;3r\x1b[5;1H\x1b[9AX", []string{"X", "2", "3", "4", "5"}, 0},
+		{"origin mode", "\x1b[2;4r\x1b[?6h\x1b[9;1HX", []string{"1", "2", "3", "X", "5"}, 0},
+		{"bad region is ignored", "\x1b[4;2r\x1b[5;1H\n", []string{"2", "3", "4", "5"}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			term := numbered(5, 5, 5)
+			term.Write([]byte(tt.in))
+			if got := rows(term); !equalRows(got, tt.want) {
+				t.Errorf("rows %q, want %q", got, tt.want)
+			}
+			if term.Scrollback() != tt.back {
+				t.Errorf("%d lines in the scrollback, want %d", term.Scrollback(), tt.back)
+			}
+		})
+	}
+}
+
+func TestWrapped(t *testing.T) {
+	term := write(5, 3, "abcdefg\r\nxy")
+	if !term.Line(0).Wrapped || term.Line(1).Wrapped {
+		t.Errorf("Wrapped is %v, %v, want true, false", term.Line(0).Wrapped, term.Line(1).Wrapped)
+	}
+	term.Write([]byte("\x1b[1;3H\x1b[K"))
+	if term.Line(0).Wrapped {
+		t.Error("erasing to the end of the line kept Wrapped")
+	}
+}
+
+func TestWideCharacters(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{"overwrite the tail", "世\x1b[1;2HX", []string{" X"}},
+		{"overwrite the head", "世\x1b[1;1HX", []string{"X"}},
+		{"erase the tail", "ab世\x1b[1;4H\x1b[K", []string{"ab"}},
+		{"delete the head", "a世b\x1b[1;2H\x1b[P", []string{"a b"}},
+		{"wide over wide", "a世\x1b[1;3H界", []string{"a 界"}},
+	}
+	for _, tt := range tests {
+		term := write(10, 2, tt.in)
+		if got := rows(term); !equalRows(got, tt.want) {
+			t.Errorf("%s: rows %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	term := write(10, 2, "世")
+	if c := term.Line(0).Cells; !c[0].Wide || c[0].Rune != '世' || !c[1].Tail {
+		t.Errorf("cells %+v, %+v", c[0], c[1])
+	}
+}
+
+func TestTabStops(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		x    int
+	}{
+		{"default", "\t", 8},
+		{"two", "\t\t", 16},
+		{"last column", "\t\t\t\t\t", 19},
+		{"set", "\x1b[1;4H\x1bH\r\t", 3},
+		{"clear one", "\x1b[1;9H\x1b[g\r\t", 16},
+		{"clear all", "\x1b[3g\r\t", 19},
+		{"forward", "\x1b[2I", 16},
+		{"back", "\x1b[1;18H\x1b[Z", 16},
+		{"back twice", "\x1b[1;18H\x1b[2Z", 8},
+		{"back to the margin", "\x1b[1;5H\x1b[5Z", 0},
+	}
+	for _, tt := range tests {
+		term := write(20, 2, tt.in)
+		if x, _, _ := term.Cursor(); x != tt.x {
+			t.Errorf("%s: cursor at column %d, want %d", tt.name, x, tt.x)
+		}
+	}
+}
+
+func TestAltScreen(t *testing.T) {
+	tests := []struct {
+		enter, leave string
+		keepsCursor  bool
+		clears       bool
+	}{
+		{"\x1b[?1049h", "\x1b[?1049l", true, true},
+		{"\x1b[?1047h", "\x1b[?1047l", false, true},
+		{"\x1b[?47h", "\x1b[?47l", false, false},
+	}
+	for _, tt := range tests {
+		term := write(5, 3, "main")
+		term.Write([]byte(tt.enter))
+		if !term.AltScreen() {
+			t.Fatalf("%q did not enter the alternate screen", tt.enter)
+		}
+		term.Write([]byte("\x1b[Halt\r\n1\r\n2\r\n3\r\n4"))
+		if term.Scrollback() != 0 {
+			t.Errorf("%q: the alternate screen pushed %d lines", tt.enter, term.Scrollback())
+		}
+		term.Write([]byte(tt.leave))
+		if got := rows(term); term.AltScreen() || !equalRows(got, []string{"main"}) {
+			t.Errorf("%q: back on the main screen with rows %q", tt.leave, got)
+		}
+		if x, y, _ := term.Cursor(); (x == 4 && y == 0) != tt.keepsCursor {
+			t.Errorf("%q: cursor at %d, %d after leaving", tt.leave, x, y)
+		}
+		term.Write([]byte(tt.enter))
+		if got := rows(term); (len(got) == 0) != tt.clears {
+			t.Errorf("%q: entering again shows %q", tt.enter, got)
+		}
+	}
+
+	// Each screen has its own saved cursor.
+	term := write(5, 3, "\x1b[2;2H\x1b7\x1b[?1049h\x1b[3;3H\x1b7\x1b[?1049l\x1b[H\x1b8")
+	if x, y, _ := term.Cursor(); x != 1 || y != 1 {
+		t.Errorf("the main screen restored %d, %d", x, y)
+	}
+}
+
+func TestSaveCursor(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		x, y int
+	}{
+		{"DECSC", "\x1b[2;3H\x1b7\x1b[H\x1b8", 2, 1},
+		{"SCOSC", "\x1b[2;3H\x1b[s\x1b[H\x1b[u", 2, 1},
+		{"1048", "\x1b[2;3H\x1b[?1048h\x1b[H\x1b[?1048l", 2, 1},
+		{"nothing saved", "\x1b[2;3H\x1b8", 0, 0},
+	}
+	for _, tt := range tests {
+		term := write(10, 4, tt.in)
+		if x, y, _ := term.Cursor(); x != tt.x || y != tt.y {
+			t.Errorf("%s: cursor at %d, %d, want %d, %d", tt.name, x, y, tt.x, tt.y)
+		}
+	}
+
+	// The style and character set are saved with the position.
+	term := write(10, 4, "\x1b[1m\x1b(0\x1b7\x1b[m\x1b(B\x1b8q")
+	if c := term.Line(0).Cells[0]; c.Rune != '─' || c.Style.Attr != Bold {
+		t.Errorf("restored cell %+v", c)
+	}
+}
+
+func TestEraseKeepsBackground(t *testing.T) {
+	// The erased line scrolls down and a blank line scrolls in above it.
+	term := write(5, 2, "abc\x1b[44m\x1b[2K\x1b[T")
+	for y := range 2 {
+		for _, c := range term.Line(y).Cells {
+			if c.Rune != 0 || c.Style.BG != Indexed(4) {
+				t.Errorf("row %d: cell %+v, want blank on blue", y, c)
+			}
+		}
+	}
+}
+
+func TestResize(t *testing.T) {
+	term := numbered(10, 3, 3)
+	term.Resize(10, 2)
+	if got := rows(term); !equalRows(got, []string{"2", "3"}) || term.Scrollback() != 1 {
+		t.Errorf("shrunk to rows %q with %d lines above", got, term.Scrollback())
+	}
+	if _, y, _ := term.Cursor(); y != 1 {
+		t.Errorf("cursor on row %d, want it to stay on 3", y)
+	}
+	term.Resize(10, 4)
+	if got := rows(term); !equalRows(got, []string{"1", "2", "3"}) || term.Scrollback() != 0 {
+		t.Errorf("grown to rows %q with %d lines above", got, term.Scrollback())
+	}
+	if _, y, _ := term.Cursor(); y != 2 {
+		t.Errorf("cursor on row %d, want 2", y)
+	}
+	if cols, rows := term.Size(); cols != 10 || rows != 4 {
+		t.Errorf("Size() = %d, %d", cols, rows)
+	}
+
+	// Lines are cut, not rewrapped, and a cut wide character is dropped.
+	term = write(10, 2, "abcd世")
+	term.Resize(5, 2)
+	if got := rows(term); !equalRows(got, []string{"abcd"}) {
+		t.Errorf("narrowed to rows %q", got)
+	}
+	if x, _, _ := term.Cursor(); x != 4 {
+		t.Errorf("cursor at column %d, want 4", x)
+	}
+	term.Resize(0, 0)
+	if cols, rows := term.Size(); cols != 1 || rows != 1 {
+		t.Errorf("Size() = %d, %d after Resize(0, 0)", cols, rows)
+	}
+
+	// The alternate screen does not trade lines with the scrollback, and
+	// the main screen is resized with it.
+	term = numbered(10, 3, 3)
+	term.Write([]byte("\x1b[?1049h\x1b[3;1Halt"))
+	term.Resize(10, 2)
+	if term.Scrollback() != 0 || !equalRows(rows(term), []string{"", "alt"}) {
+		t.Errorf("the alternate screen shows %q with %d lines above", rows(term), term.Scrollback())
+	}
+	term.Write([]byte("\x1b[?1049l"))
+	if got := rows(term); !equalRows(got, []string{"1", "2"}) {
+		t.Errorf("the main screen shows %q", got)
+	}
+}
+
+func TestRuneWidth(t *testing.T) {
+	tests := []struct {
+		r    rune
+		want int
+	}{
+		{'a', 1},
+		{0x07, 0},
+		{0x85, 0},
+		{'é', 1},
+		{0x301, 0},
+		{0x200b, 0},
+		{'世', 2},
+		{'한', 2},
+		{'Ａ', 2},
+		{'😀', 2},
+		{'→', 1},
+		{0x303f, 1},
+	}
+	for _, tt := range tests {
+		if got := runeWidth(tt.r); got != tt.want {
+			t.Errorf("runeWidth(%U) = %d, want %d", tt.r, got, tt.want)
+		}
+	}
+}
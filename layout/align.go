@@ -0,0 +1,72 @@
+package layout
+
+// Justify distributes the free space along a container's main axis.
+type Justify uint8
+
+const (
+	// JustifyStart packs children at the start.
+	JustifyStart Justify = iota
+	// JustifyCenter centers children.
+	JustifyCenter
+	// JustifyEnd packs children at the end.
+	JustifyEnd
+	// JustifySpaceBetween puts the free space between children, none at
+	// the ends.
+	JustifySpaceBetween
+	// JustifySpaceAround puts equal space around each child, so the ends
+	// get half the space found between children.
+	JustifySpaceAround
+)
+
+// offsets returns where the first child starts and the extra space added
+// after each child, for n children given free space.
+func (j Justify) offsets(free float32, n int) (lead, between float32) {
+	if free <= 0 || n == 0 {
+		return 0, 0
+	}
+	switch j {
+	case JustifyCenter:
+		return free / 2, 0
+	case JustifyEnd:
+		return free, 0
+	case JustifySpaceBetween:
+		if n == 1 {
+			return 0, 0
+		}
+		return 0, free / float32(n-1)
+	case JustifySpaceAround:
+		gap := free / float32(n)
+		return gap / 2, gap
+	}
+	return 0, 0
+}
+
+// Align positions a child across a container's main axis.
+type Align uint8
+
+const (
+	// AlignStart puts the child at the start of the cross axis.
+	AlignStart Align = iota
+	// AlignCenter centers the child.
+	AlignCenter
+	// AlignEnd puts the child at the end.
+	AlignEnd
+	// AlignStretch makes the child fill the cross axis.
+	AlignStretch
+	// AlignBaseline lines the children of a row up on the baseline of
+	// their first line of text; children without text sit on it with
+	// their bottom edge. It applies to horizontal Stacks and Wraps and to
+	// Grid rows; elsewhere it is AlignStart.
+	AlignBaseline
+)
+
+// offset returns the position of a child of extent size in space.
+func (a Align) offset(space, size float32) float32 {
+	switch a {
+	case AlignCenter:
+		return (space - size) / 2
+	case AlignEnd:
+		return space - size
+	}
+	return 0
+}
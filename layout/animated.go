@@ -0,0 +1,139 @@
+package layout
+
+import (
+	"time"
+
+	"github.com/gogpu/ui/anim"
+	"github.com/gogpu/ui/core"
+)
+
+const (
+	animatedRestDistance = 0.5 // Distance from the target at which motion stops.
+	animatedRestVelocity = 5   // Speed below which motion stops, in px/s.
+)
+
+// Spring describes the motion of an animated layout change; see
+// anim.Spring.
+type Spring = anim.Spring
+
+// AnimatedLayout animates the position and size of its child whenever the
+// container it is in moves or resizes it, so insertions, removals,
+// reorders and resizes glide instead of jumping. Wrap each item of a list
+// or each panel in a layout to animate them:
+//
+//	for _, item := range items {
+//		column.Add(layout.Animated(item, layout.Spring{}))
+//	}
+//
+// The container lays out the final geometry; the child follows it on a
+// spring and is laid out at its current size on the way. The first
+// placement and, with reduced motion, every placement are immediate.
+type AnimatedLayout struct {
+	core.WidgetBase
+	child  core.Widget
+	spring Spring
+
+	ctx      *core.Context
+	placed   bool
+	target   core.Rect
+	pos      [4]float32 // X, Y, Width, Height.
+	vel      [4]float32
+	lastTick time.Time
+}
+
+// Animated returns child with its layout changes animated by spring.
+func Animated(child core.Widget, spring Spring) *AnimatedLayout {
+	a := &AnimatedLayout{child: child, spring: spring}
+	a.SetChildren(child)
+	return a
+}
+
+// Child returns the animated widget.
+func (a *AnimatedLayout) Child() core.Widget {
+	return a.child
+}
+
+// IsAnimating reports whether the child is still moving toward the
+// geometry it was given.
+func (a *AnimatedLayout) IsAnimating() bool {
+	return a.placed && a.current() != a.target
+}
+
+func (a *AnimatedLayout) current() core.Rect {
+	return core.R(a.pos[0], a.pos[1], a.pos[2], a.pos[3])
+}
+
+func (a *AnimatedLayout) snap() {
+	r := a.target
+	a.pos = [4]float32{r.X, r.Y, r.Width, r.Height}
+	a.vel = [4]float32{}
+}
+
+// step advances the spring to the frame time.
+func (a *AnimatedLayout) step(ctx *core.LayoutContext) {
+	now := ctx.Now()
+	dt := time.Duration(0)
+	if !a.lastTick.IsZero() {
+		dt = now.Sub(a.lastTick)
+	}
+	a.lastTick = now
+	if !a.IsAnimating() {
+		return
+	}
+	if ctx.ReducedMotion() {
+		a.snap()
+		return
+	}
+	t := a.target
+	goal := [4]float32{t.X, t.Y, t.Width, t.Height}
+	for i := range a.pos {
+		a.pos[i], a.vel[i] = a.spring.Step(a.pos[i], a.vel[i], goal[i], dt)
+	}
+	rest := true
+	for i := range a.pos {
+		if abs32(a.pos[i]-goal[i]) > animatedRestDistance || abs32(a.vel[i]) > animatedRestVelocity {
+			rest = false
+		}
+	}
+	if rest {
+		a.snap()
+		return
+	}
+	ctx.MarkNeedsLayout(a)
+}
+
+// Layout implements core.Widget. It reports the size the child takes at
+// rest.
+func (a *AnimatedLayout) Layout(ctx *core.LayoutContext) core.Size {
+	a.ctx = ctx.Context
+	size := ctx.Measure(a.child, ctx.Constraints)
+	a.step(ctx)
+	if a.IsAnimating() {
+		cur := a.current().Size()
+		ctx.Measure(a.child, core.Tight(core.Sz(max(0, cur.Width), max(0, cur.Height))))
+	}
+	return size
+}
+
+// SetBounds implements core.Widget. A new geometry becomes the target the
+// child moves toward; Bounds reports where the child currently is.
+func (a *AnimatedLayout) SetBounds(r core.Rect) {
+	if r != a.target || !a.placed {
+		a.target = r
+		if !a.placed || a.ctx == nil || a.ctx.ReducedMotion() {
+			a.placed = true
+			a.snap()
+		} else {
+			a.ctx.MarkNeedsLayout(a)
+		}
+	}
+	cur := a.current()
+	cur.Width, cur.Height = max(0, cur.Width), max(0, cur.Height)
+	a.WidgetBase.SetBounds(cur)
+	a.child.SetBounds(cur)
+}
+
+// Paint implements core.Widget.
+func (a *AnimatedLayout) Paint(ctx *core.PaintContext) {
+	a.child.Paint(ctx)
+}
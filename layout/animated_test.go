@@ -0,0 +1,121 @@
+package layout
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogpu/ui/core"
+)
+
+// column is a column of a 50 high box, an animated 20 high box and
+// another 10 high.
+type column struct {
+	s                *Stack
+	top, item, below *box
+	a                *AnimatedLayout
+	ctx              *core.Context
+	l                *loose
+}
+
+// animatedColumn returns a column laid out at time 0.
+func animatedColumn() *column {
+	c := &column{top: newBox(100, 50), item: newBox(100, 20), below: newBox(100, 10), ctx: core.NewContext()}
+	c.a = Animated(c.item, Spring{})
+	c.s = NewVStack(c.top, c.a, c.below)
+	c.l = &loose{child: c.s}
+	c.l.SetChildren(c.s)
+	c.frame(0)
+	return c
+}
+
+// frame lays the column out again at ms.
+func (c *column) frame(ms int) {
+	c.ctx.SetNow(time.Unix(0, 0).Add(time.Duration(ms) * time.Millisecond))
+	c.ctx.LayoutRoot(c.l, core.R(0, 0, 100, 300))
+}
+
+func TestAnimatedLayout(t *testing.T) {
+	c := animatedColumn()
+	if got := c.a.Bounds(); got != core.R(0, 50, 100, 20) || c.a.IsAnimating() {
+		t.Fatalf("first placed at %v, want at once where the column puts it", got)
+	}
+
+	// Removed from above, the box glides up without overshooting.
+	c.s.Remove(c.top)
+	c.ctx.MarkNeedsLayout(c.s)
+	c.frame(16)
+	if got := c.a.Bounds(); got != core.R(0, 50, 100, 20) || !c.a.IsAnimating() {
+		t.Errorf("at %v when moved, want where it was", got)
+	}
+	last := c.a.Bounds().Y
+	ms := 16
+	for ; c.a.IsAnimating() && ms < 2000; ms += 16 {
+		c.frame(ms + 16)
+		b := c.a.Bounds()
+		if b.Y > last || b.Y < 0 || b.Width != 100 || b.Height != 20 {
+			t.Fatalf("at %v after %d ms, from y %v", b, ms+16, last)
+		}
+		if c.item.Bounds() != b {
+			t.Fatalf("child at %v, want with its layout at %v", c.item.Bounds(), b)
+		}
+		last = b.Y
+	}
+	if got := c.a.Bounds(); got != core.R(0, 0, 100, 20) {
+		t.Errorf("at rest at %v, want where the column put it", got)
+	}
+	if ms < 100 || ms > 1000 {
+		t.Errorf("moved for %d ms, want about a third of a second", ms)
+	}
+}
+
+func TestAnimatedLayoutResize(t *testing.T) {
+	c := animatedColumn()
+	// The container lays out the size at rest, and the child is laid out
+	// at its size on the way.
+	c.item.size.Height = 60
+	c.ctx.MarkNeedsLayout(c.item)
+	c.ctx.MarkNeedsLayout(c.s)
+	c.frame(16)
+	c.frame(32)
+	c.frame(48)
+	if got := c.below.Bounds(); got != core.R(0, 110, 100, 10) {
+		t.Errorf("the box below at %v, want below the size at rest", got)
+	}
+	if h := c.item.Bounds().Height; h <= 20 || h >= 60 || c.a.Bounds() != c.item.Bounds() {
+		t.Errorf("the child %v high on the way, want between 20 and 60", h)
+	}
+	for ms := 64; c.a.IsAnimating() && ms < 2000; ms += 16 {
+		c.frame(ms)
+	}
+	if got := c.item.Bounds(); got != core.R(0, 50, 100, 60) {
+		t.Errorf("child at rest at %v", got)
+	}
+}
+
+func TestAnimatedLayoutReducedMotion(t *testing.T) {
+	c := animatedColumn()
+	c.ctx.SetReducedMotion(true)
+	c.s.Remove(c.top)
+	c.ctx.MarkNeedsLayout(c.s)
+	c.frame(16)
+	if got := c.a.Bounds(); got != core.R(0, 0, 100, 20) || c.a.IsAnimating() {
+		t.Errorf("at %v with reduced motion, want moved at once", got)
+	}
+
+	// Turned on during a move, it ends the move at the next frame.
+	c.ctx.SetReducedMotion(false)
+	c.s.Add(newBox(100, 30))
+	c.s.Remove(c.a)
+	c.s.Add(c.a)
+	c.ctx.MarkNeedsLayout(c.s)
+	c.frame(32)
+	c.frame(48)
+	if !c.a.IsAnimating() {
+		t.Fatal("not moving")
+	}
+	c.ctx.SetReducedMotion(true)
+	c.frame(64)
+	if got := c.a.Bounds(); got != core.R(0, 40, 100, 20) || c.a.IsAnimating() {
+		t.Errorf("at %v, want at rest once motion is reduced", got)
+	}
+}
@@ -0,0 +1,19 @@
+package layout
+
+// Axis is the direction along which a container arranges its children.
+type Axis uint8
+
+const (
+	// Horizontal arranges children side by side, left to right.
+	Horizontal Axis = iota
+	// Vertical arranges children top to bottom.
+	Vertical
+)
+
+// String returns "horizontal" or "vertical".
+func (a Axis) String() string {
+	if a == Vertical {
+		return "vertical"
+	}
+	return "horizontal"
+}
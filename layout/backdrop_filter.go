@@ -0,0 +1,106 @@
+package layout
+
+import "github.com/gogpu/ui/core"
+
+// BackdropFilter filters what lies behind its child, such as to blur it
+// for a frosted glass panel, and paints a tint over it before the child:
+//
+//	glass := layout.NewBackdropFilter(toolbar, core.Blur(20).WithSaturation(1.6)).
+//		Radius(12).
+//		Tint(core.White.WithAlpha(0.4))
+//
+// The filter applies on canvases that implement core.BackdropCanvas. On
+// others the fallback color is painted in place of the filtered backdrop
+// and tint, so that content over a busy background stays legible.
+type BackdropFilter struct {
+	core.WidgetBase
+	child    core.Widget
+	filter   core.Filter
+	radius   float32
+	tint     core.Color
+	fallback core.Color
+	custom   bool // Set when the fallback was replaced.
+}
+
+// NewBackdropFilter returns a container filtering what lies behind child
+// by f.
+func NewBackdropFilter(child core.Widget, f core.Filter) *BackdropFilter {
+	b := &BackdropFilter{child: child, filter: f}
+	b.SetChildren(child)
+	return b
+}
+
+// Filter sets the filter.
+func (b *BackdropFilter) Filter(f core.Filter) *BackdropFilter {
+	b.filter = f
+	return b
+}
+
+// Radius rounds the corners of the filtered region.
+func (b *BackdropFilter) Radius(r float32) *BackdropFilter {
+	b.radius = max(r, 0)
+	return b
+}
+
+// Tint sets the color painted over the filtered backdrop, usually a
+// translucent surface color. It is also the fallback unless that is set.
+func (b *BackdropFilter) Tint(c core.Color) *BackdropFilter {
+	b.tint = c
+	if !b.custom {
+		b.fallback = c
+	}
+	return b
+}
+
+// Fallback sets the color painted instead of the filtered backdrop and
+// tint on canvases without backdrop filters, usually a more opaque
+// version of the tint.
+func (b *BackdropFilter) Fallback(c core.Color) *BackdropFilter {
+	b.fallback, b.custom = c, true
+	return b
+}
+
+// Layout implements core.Widget.
+func (b *BackdropFilter) Layout(ctx *core.LayoutContext) core.Size {
+	return ctx.Measure(b.child, ctx.Constraints)
+}
+
+// IntrinsicWidth implements core.IntrinsicSizer.
+func (b *BackdropFilter) IntrinsicWidth(ctx *core.LayoutContext, height float32) (minWidth, maxWidth float32) {
+	return ctx.IntrinsicWidth(b.child, height)
+}
+
+// IntrinsicHeight implements core.IntrinsicSizer.
+func (b *BackdropFilter) IntrinsicHeight(ctx *core.LayoutContext, width float32) (minHeight, maxHeight float32) {
+	return ctx.IntrinsicHeight(b.child, width)
+}
+
+// Baseline implements core.Baseliner with the child's baseline.
+func (b *BackdropFilter) Baseline() (float32, bool) {
+	return core.BaselineOf(b.child)
+}
+
+// SetBounds implements core.Widget.
+func (b *BackdropFilter) SetBounds(r core.Rect) {
+	b.WidgetBase.SetBounds(r)
+	b.child.SetBounds(r)
+}
+
+// Paint implements core.Widget.
+func (b *BackdropFilter) Paint(ctx *core.PaintContext) {
+	r := b.Bounds()
+	tint := b.fallback
+	if bc, ok := ctx.Canvas.(core.BackdropCanvas); ok {
+		tint = b.tint
+		if !b.filter.IsZero() {
+			// What the filter draws depends on all it reads, so a
+			// frame painting any of that paints all of it.
+			ctx.AddBackdrop(r.Inset(core.UniformInsets(-b.filter.Reach())))
+			bc.DrawBackdrop(r, b.radius, b.filter)
+		}
+	}
+	if !tint.IsTransparent() {
+		ctx.Canvas.DrawRoundedRect(r, b.radius, core.Filled(tint))
+	}
+	b.child.Paint(ctx)
+}
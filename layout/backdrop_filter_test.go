@@ -0,0 +1,82 @@
+package layout
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+)
+
+// frosted is a canvas with backdrop filters, recording the regions it
+// filters.
+type frosted struct {
+	core.Recording
+	filtered []core.Rect
+}
+
+func (c *frosted) DrawBackdrop(r core.Rect, _ float32, _ core.Filter) {
+	c.filtered = append(c.filtered, r)
+}
+
+func TestBackdropFilter(t *testing.T) {
+	tint, opaque := core.White.WithAlpha(0.4), core.White.WithAlpha(0.9)
+	r := core.R(10, 10, 100, 40)
+	tests := []struct {
+		name     string
+		b        func(child core.Widget) *BackdropFilter
+		filtered []core.Rect
+		drop     []core.Rect // Recorded by AddBackdrop.
+		fill     core.Color  // Painted beneath the child, if any.
+		fallback core.Color  // Painted beneath the child without filters.
+	}{
+		{"tinted", func(c core.Widget) *BackdropFilter {
+			return NewBackdropFilter(c, core.Blur(4)).Tint(tint)
+		}, []core.Rect{r}, []core.Rect{core.R(-2, -2, 124, 64)}, tint, tint},
+		{"with a fallback", func(c core.Widget) *BackdropFilter {
+			return NewBackdropFilter(c, core.Blur(4)).Fallback(opaque).Tint(tint)
+		}, []core.Rect{r}, []core.Rect{core.R(-2, -2, 124, 64)}, tint, opaque},
+		{"untinted", func(c core.Widget) *BackdropFilter {
+			return NewBackdropFilter(c, core.Blur(0).WithSaturation(2))
+		}, []core.Rect{r}, []core.Rect{r}, core.Transparent, core.Transparent},
+		{"no filter", func(c core.Widget) *BackdropFilter {
+			return NewBackdropFilter(c, core.Filter{}).Tint(tint)
+		}, nil, nil, tint, tint},
+	}
+	for _, tt := range tests {
+		child := &swatch{color: core.Black}
+		b := tt.b(child)
+		ctx := core.NewContext()
+		ctx.LayoutRoot(b, r)
+		if child.Bounds() != r {
+			t.Errorf("%s: child at %v, want over the container", tt.name, child.Bounds())
+		}
+		cv := &frosted{}
+		b.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+		if !slices.Equal(cv.filtered, tt.filtered) {
+			t.Errorf("%s: filtered %v, want %v", tt.name, cv.filtered, tt.filtered)
+		}
+		ctx.InvalidateRect(core.R(9, 9, 2, 2))
+		ctx.LayoutRoot(b, r)
+		if rects, _ := ctx.Damage(); !slices.Equal(rects[1:], tt.drop) {
+			t.Errorf("%s: damage %v, want the region read with the one painted", tt.name, rects)
+		}
+
+		var want core.Recording
+		if !tt.fill.IsTransparent() {
+			want.DrawRoundedRect(r, 0, core.Filled(tt.fill))
+		}
+		want.DrawRect(r, core.Filled(core.Black))
+		if !cv.Recording.Equal(&want) {
+			t.Errorf("%s: painted %d ops, want the child over a fill of %v", tt.name, cv.Recording.Len(), tt.fill)
+		}
+		var plain, fallback core.Recording
+		b.Paint(&core.PaintContext{Context: ctx, Canvas: &plain})
+		if !tt.fallback.IsTransparent() {
+			fallback.DrawRoundedRect(r, 0, core.Filled(tt.fallback))
+		}
+		fallback.DrawRect(r, core.Filled(core.Black))
+		if !plain.Equal(&fallback) {
+			t.Errorf("%s: painted beneath the child without filters, want %v", tt.name, tt.fallback)
+		}
+	}
+}
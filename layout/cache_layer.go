@@ -0,0 +1,128 @@
+package layout
+
+import "github.com/gogpu/ui/core"
+
+// CacheLayer draws its child into an offscreen layer and draws the layer
+// in later frames, for panels with expensive content that rarely
+// changes, such as minimaps and thumbnails. The layer is a texture on
+// canvases that implement core.LayerCanvas.
+//
+// Each frame the child is painted into a core.Recording, which costs no
+// rasterization, and the layer is drawn again only when the recording
+// differs from the one it was drawn from, or when the size changes or
+// Invalidate is called. Retained content is not painted at all until
+// then. What the child draws outside its bounds, such as shadows, is cut
+// off at the edges of the layer.
+type CacheLayer struct {
+	core.WidgetBase
+	child core.Widget
+
+	retained bool
+	dirty    bool
+	layer    core.Layer
+	scale    float32        // Of the canvas the layer was made for.
+	rec      core.Recording // What the layer was drawn from.
+	at       core.Point     // Where rec was recorded.
+	next     core.Recording
+}
+
+// NewCacheLayer returns a container caching what child draws.
+func NewCacheLayer(child core.Widget) *CacheLayer {
+	c := &CacheLayer{child: child, dirty: true}
+	c.SetChildren(child)
+	return c
+}
+
+// Retained makes the child be painted only when its size changes or
+// Invalidate is called, for content that changes only in ways its owner
+// knows of, where painting it only to compare costs too much.
+func (c *CacheLayer) Retained(on bool) *CacheLayer {
+	c.retained = on
+	return c
+}
+
+// Invalidate makes the layer be drawn again in the next frame.
+func (c *CacheLayer) Invalidate() {
+	c.dirty = true
+}
+
+// Release frees the layer, for when the container leaves the tree. It is
+// made again if the container is painted.
+func (c *CacheLayer) Release() {
+	if c.layer != nil {
+		c.layer.Release()
+		c.layer = nil
+	}
+	c.rec.Reset()
+	c.dirty = true
+}
+
+// Layout implements core.Widget.
+func (c *CacheLayer) Layout(ctx *core.LayoutContext) core.Size {
+	return ctx.Measure(c.child, ctx.Constraints)
+}
+
+// IntrinsicWidth implements core.IntrinsicSizer.
+func (c *CacheLayer) IntrinsicWidth(ctx *core.LayoutContext, height float32) (minWidth, maxWidth float32) {
+	return ctx.IntrinsicWidth(c.child, height)
+}
+
+// IntrinsicHeight implements core.IntrinsicSizer.
+func (c *CacheLayer) IntrinsicHeight(ctx *core.LayoutContext, width float32) (minHeight, maxHeight float32) {
+	return ctx.IntrinsicHeight(c.child, width)
+}
+
+// Baseline implements core.Baseliner with the child's baseline.
+func (c *CacheLayer) Baseline() (float32, bool) {
+	return core.BaselineOf(c.child)
+}
+
+// SetBounds implements core.Widget.
+func (c *CacheLayer) SetBounds(r core.Rect) {
+	c.WidgetBase.SetBounds(r)
+	c.child.SetBounds(r)
+}
+
+// Paint implements core.Widget.
+func (c *CacheLayer) Paint(ctx *core.PaintContext) {
+	b := c.Bounds()
+	if b.IsEmpty() {
+		return
+	}
+	if ctx.Capture {
+		c.child.Paint(ctx)
+		return
+	}
+	// Moving to a monitor of another scale makes the layer again, at the
+	// resolution of the new one.
+	if c.layer == nil || c.layer.Size() != b.Size() || c.scale != ctx.ScaleFactor() {
+		c.Release()
+		c.layer, c.scale = core.NewLayer(ctx.Canvas, b.Size()), ctx.ScaleFactor()
+	}
+	// The child paints in window coordinates and the layer from its top
+	// left, so the layer can move without being drawn again.
+	if !c.retained {
+		c.next.Reset()
+		c.child.Paint(&core.PaintContext{Context: ctx.Context, Canvas: &c.next})
+		if c.dirty || !c.next.EqualMoved(&c.rec, b.Origin().Sub(c.at)) {
+			c.rec, c.next, c.at = c.next, c.rec, b.Origin()
+			c.redraw(func(cv core.Canvas) {
+				cv.Translate(-b.X, -b.Y)
+				c.rec.Replay(cv)
+			})
+		}
+	} else if c.dirty {
+		c.redraw(func(cv core.Canvas) {
+			cv.Translate(-b.X, -b.Y)
+			c.child.Paint(&core.PaintContext{Context: ctx.Context, Canvas: cv})
+		})
+	}
+	core.DrawLayer(ctx.Canvas, c.layer, b.Origin())
+}
+
+// redraw draws the layer with paint.
+func (c *CacheLayer) redraw(paint func(cv core.Canvas)) {
+	c.dirty = false
+	paint(c.layer.Begin())
+	c.layer.End()
+}
@@ -0,0 +1,170 @@
+package layout
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+)
+
+// textures is a canvas with offscreen layers, recording where they are
+// drawn.
+type textures struct {
+	core.Recording
+	made  []*texture
+	drawn []core.Point
+}
+
+func (c *textures) NewLayer(size core.Size) core.Layer {
+	t := &texture{size: size}
+	c.made = append(c.made, t)
+	return t
+}
+
+func (c *textures) DrawLayer(_ core.Layer, pos core.Point) {
+	c.drawn = append(c.drawn, pos)
+}
+
+// texture is a layer of textures, counting how often it is drawn into.
+type texture struct {
+	size     core.Size
+	rec      core.Recording
+	drawn    int
+	released bool
+}
+
+func (t *texture) Size() core.Size { return t.size }
+func (t *texture) End()            {}
+func (t *texture) Release()        { t.released = true }
+
+func (t *texture) Begin() core.Canvas {
+	t.drawn++
+	t.rec.Reset()
+	return &t.rec
+}
+
+// swatch fills its bounds with color, counting its paints.
+type swatch struct {
+	core.WidgetBase
+	color  core.Color
+	paints int
+}
+
+func (s *swatch) Layout(ctx *core.LayoutContext) core.Size {
+	return ctx.Constraints.Constrain(core.Sz(50, 30))
+}
+
+func (s *swatch) Paint(ctx *core.PaintContext) {
+	s.paints++
+	ctx.Canvas.DrawRect(s.Bounds(), core.Filled(s.color))
+}
+
+func TestCacheLayer(t *testing.T) {
+	child := &swatch{color: core.White}
+	c := NewCacheLayer(child)
+	c.SetBounds(core.R(10, 20, 50, 30))
+	ctx := core.NewContext()
+	cv := &textures{}
+	paint := func() { c.Paint(&core.PaintContext{Context: ctx, Canvas: cv}) }
+
+	tests := []struct {
+		name   string
+		change func()
+		layers int // Made so far.
+		drawn  int // Into the latest layer.
+		at     core.Point
+	}{
+		{"first", func() {}, 1, 1, core.Pt(10, 20)},
+		{"unchanged", func() {}, 1, 1, core.Pt(10, 20)},
+		{"repainted differently", func() { child.color = core.Black }, 1, 2, core.Pt(10, 20)},
+		{"moved", func() { c.SetBounds(core.R(70, 5, 50, 30)) }, 1, 2, core.Pt(70, 5)},
+		{"invalidated", c.Invalidate, 1, 3, core.Pt(70, 5)},
+		{"resized", func() { c.SetBounds(core.R(70, 5, 60, 30)) }, 2, 1, core.Pt(70, 5)},
+		{"scaled", func() { ctx.SetScaleFactor(2) }, 3, 1, core.Pt(70, 5)},
+		{"released", c.Release, 4, 1, core.Pt(70, 5)},
+	}
+	for _, tt := range tests {
+		tt.change()
+		paints := child.paints
+		paint()
+		if len(cv.made) != tt.layers {
+			t.Fatalf("%s: %d layers made, want %d", tt.name, len(cv.made), tt.layers)
+		}
+		l := cv.made[len(cv.made)-1]
+		if l.drawn != tt.drawn || l.size != c.Bounds().Size() {
+			t.Errorf("%s: layer of %v drawn %d times, want %d", tt.name, l.size, l.drawn, tt.drawn)
+		}
+		if got := cv.drawn[len(cv.drawn)-1]; got != tt.at {
+			t.Errorf("%s: layer drawn at %v, want %v", tt.name, got, tt.at)
+		}
+		if child.paints != paints+1 {
+			t.Errorf("%s: child painted %d times, want once to compare", tt.name, child.paints-paints)
+		}
+		for _, old := range cv.made[:len(cv.made)-1] {
+			if !old.released {
+				t.Errorf("%s: a layer replaced was not released", tt.name)
+			}
+		}
+	}
+
+	// The layer holds what the child drew, moved to its top left.
+	var want core.Recording
+	want.Translate(-70, -5)
+	want.DrawRect(core.R(70, 5, 60, 30), core.Filled(core.Black))
+	if !cv.made[len(cv.made)-1].rec.Equal(&want) {
+		t.Error("the layer does not hold the child at its top left")
+	}
+
+	// A capture paints the child itself, for its pixels to be read.
+	drawn := len(cv.drawn)
+	c.Paint(&core.PaintContext{Context: ctx, Canvas: cv, Capture: true})
+	if len(cv.drawn) != drawn || len(cv.made) != 4 {
+		t.Error("a capture drew the layer")
+	}
+}
+
+func TestCacheLayerRetained(t *testing.T) {
+	child := &swatch{color: core.White}
+	c := NewCacheLayer(child).Retained(true)
+	c.SetBounds(core.R(0, 0, 50, 30))
+	ctx := core.NewContext()
+	cv := &textures{}
+	paint := func() { c.Paint(&core.PaintContext{Context: ctx, Canvas: cv}) }
+
+	// Retained, the child is painted only when asked to.
+	paint()
+	child.color = core.Black
+	paint()
+	paint()
+	if child.paints != 1 || cv.made[0].drawn != 1 || len(cv.drawn) != 3 {
+		t.Errorf("child painted %d times, the layer %d, want once", child.paints, cv.made[0].drawn)
+	}
+	c.Invalidate()
+	paint()
+	if child.paints != 2 || cv.made[0].drawn != 2 {
+		t.Errorf("child painted %d times after Invalidate, want again", child.paints)
+	}
+	c.SetBounds(core.R(0, 0, 50, 40))
+	paint()
+	if child.paints != 3 || len(cv.made) != 2 {
+		t.Errorf("child painted %d times after a resize, want again", child.paints)
+	}
+}
+
+func TestCacheLayerRecorded(t *testing.T) {
+	// Without offscreen textures, the layer is a recording replayed at the
+	// container's position.
+	child := &swatch{color: core.White}
+	c := NewCacheLayer(child)
+	c.SetBounds(core.R(10, 20, 50, 30))
+	ctx := core.NewContext()
+	var cv core.Recording
+	c.Paint(&core.PaintContext{Context: ctx, Canvas: &cv})
+	if cv.Len() == 0 {
+		t.Fatal("nothing drawn")
+	}
+	var again core.Recording
+	c.Paint(&core.PaintContext{Context: ctx, Canvas: &again})
+	if !again.Equal(&cv) || child.paints != 2 {
+		t.Error("the layer drawn again differs")
+	}
+}
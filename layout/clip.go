@@ -0,0 +1,107 @@
+package layout
+
+import "github.com/gogpu/ui/core"
+
+// Clip cuts off what its child draws outside a shape: its bounds, with
+// rounded corners, the ellipse inscribed in them or any path. Clips
+// nested in one another intersect. Pointer events outside the shape pass
+// through to what lies beneath.
+//
+// The edges are antialiased on canvases implementing
+// core.PathClipCanvas; others clip to the bounds of the shape.
+type Clip struct {
+	core.WidgetBase
+	child core.Widget
+
+	radius  float32
+	ellipse bool
+	path    func(bounds core.Rect) *core.Path
+	rule    core.FillRule
+}
+
+// NewClip returns a container clipping child to its bounds.
+func NewClip(child core.Widget) *Clip {
+	c := &Clip{child: child}
+	c.SetChildren(child)
+	return c
+}
+
+// Rounded rounds the corners of the clip by radius.
+func (c *Clip) Rounded(radius float32) *Clip {
+	c.radius, c.ellipse, c.path = max(radius, 0), false, nil
+	return c
+}
+
+// Ellipse clips to the ellipse inscribed in the bounds, a circle for a
+// square child.
+func (c *Clip) Ellipse() *Clip {
+	c.radius, c.ellipse, c.path = 0, true, nil
+	return c
+}
+
+// Path clips to the inside of the path fn returns for the bounds of the
+// container, by rule.
+func (c *Clip) Path(fn func(bounds core.Rect) *core.Path, rule core.FillRule) *Clip {
+	c.radius, c.ellipse, c.path, c.rule = 0, false, fn, rule
+	return c
+}
+
+// shape returns the path clipped to, or nil for the bounds.
+func (c *Clip) shape() *core.Path {
+	b := c.Bounds()
+	switch {
+	case c.path != nil:
+		return c.path(b)
+	case c.ellipse:
+		return core.NewPath().AddEllipse(b)
+	case c.radius > 0:
+		return core.NewPath().AddRoundedRect(b, c.radius)
+	}
+	return nil
+}
+
+// Layout implements core.Widget.
+func (c *Clip) Layout(ctx *core.LayoutContext) core.Size {
+	return ctx.Measure(c.child, ctx.Constraints)
+}
+
+// IntrinsicWidth implements core.IntrinsicSizer.
+func (c *Clip) IntrinsicWidth(ctx *core.LayoutContext, height float32) (minWidth, maxWidth float32) {
+	return ctx.IntrinsicWidth(c.child, height)
+}
+
+// IntrinsicHeight implements core.IntrinsicSizer.
+func (c *Clip) IntrinsicHeight(ctx *core.LayoutContext, width float32) (minHeight, maxHeight float32) {
+	return ctx.IntrinsicHeight(c.child, width)
+}
+
+// Baseline implements core.Baseliner with the child's baseline.
+func (c *Clip) Baseline() (float32, bool) {
+	return core.BaselineOf(c.child)
+}
+
+// SetBounds implements core.Widget.
+func (c *Clip) SetBounds(r core.Rect) {
+	c.WidgetBase.SetBounds(r)
+	c.child.SetBounds(r)
+}
+
+// HitTest implements core.HitTester, hitting only inside the shape.
+func (c *Clip) HitTest(p core.Point) bool {
+	if s := c.shape(); s != nil {
+		return s.Contains(p, c.rule)
+	}
+	return true
+}
+
+// Paint implements core.Widget.
+func (c *Clip) Paint(ctx *core.PaintContext) {
+	cv := ctx.Canvas
+	cv.Save()
+	cv.Clip(c.Bounds())
+	if s := c.shape(); s != nil {
+		core.ClipPath(cv, s, c.rule)
+	}
+	c.child.Paint(ctx)
+	cv.Restore()
+}
@@ -0,0 +1,65 @@
+package layout
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+)
+
+func TestClip(t *testing.T) {
+	r := core.R(10, 10, 40, 40)
+	// framed is the bounds with a hole by the even-odd rule.
+	framed := func(b core.Rect) *core.Path {
+		return core.NewPath().AddRect(b).AddRect(b.Inset(core.UniformInsets(10)))
+	}
+	tests := []struct {
+		name string
+		c    func(child core.Widget) *Clip
+		path *core.Path // Clipped to within the bounds, if any.
+		rule core.FillRule
+		hits []core.Point
+		miss []core.Point
+	}{
+		{"bounds", NewClip, nil, 0, []core.Point{core.Pt(11, 11), core.Pt(49, 49)}, nil},
+		{"rounded", func(c core.Widget) *Clip { return NewClip(c).Rounded(10) },
+			core.NewPath().AddRoundedRect(r, 10), core.FillNonZero, []core.Point{core.Pt(30, 11), core.Pt(15, 15)}, []core.Point{core.Pt(11, 11)}},
+		{"ellipse", func(c core.Widget) *Clip { return NewClip(c).Rounded(10).Ellipse() },
+			core.NewPath().AddEllipse(r), core.FillNonZero, []core.Point{core.Pt(30, 30), core.Pt(11, 30)}, []core.Point{core.Pt(15, 15)}},
+		{"path", func(c core.Widget) *Clip { return NewClip(c).Ellipse().Path(framed, core.FillEvenOdd) },
+			framed(r), core.FillEvenOdd, []core.Point{core.Pt(15, 15)}, []core.Point{core.Pt(30, 30)}},
+		{"rounded again", func(c core.Widget) *Clip { return NewClip(c).Path(framed, core.FillEvenOdd).Rounded(0) },
+			nil, 0, []core.Point{core.Pt(30, 30), core.Pt(11, 11)}, nil},
+	}
+	for _, tt := range tests {
+		child := &swatch{color: core.Black}
+		c := tt.c(child)
+		ctx := core.NewContext()
+		ctx.LayoutRoot(c, r)
+		if child.Bounds() != r {
+			t.Errorf("%s: child at %v, want over the clip", tt.name, child.Bounds())
+		}
+		for _, p := range tt.hits {
+			if !c.HitTest(p) {
+				t.Errorf("%s: %v missed", tt.name, p)
+			}
+		}
+		for _, p := range tt.miss {
+			if c.HitTest(p) {
+				t.Errorf("%s: %v hit outside the shape", tt.name, p)
+			}
+		}
+
+		var got, want core.Recording
+		c.Paint(&core.PaintContext{Context: ctx, Canvas: &got})
+		want.Save()
+		want.Clip(r)
+		if tt.path != nil {
+			want.ClipPath(tt.path, tt.rule)
+		}
+		want.DrawRect(r, core.Filled(core.Black))
+		want.Restore()
+		if !got.Equal(&want) {
+			t.Errorf("%s: painted %d ops unlike the %d wanted", tt.name, got.Len(), want.Len())
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package layout
+
+import (
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/state"
+)
+
+// Composite paints its child as a group, into an offscreen layer that is
+// then faded and blended as a whole, so that overlapping parts of a
+// fading subtree do not show through one another:
+//
+//	opacity := state.New[float32](1)
+//	page := layout.NewComposite(content).Bind(opacity)
+//	shade := layout.NewComposite(texture).Blend(core.BlendMultiply)
+//
+// Fully opaque groups in the normal mode are painted as they are. On
+// canvases that do not implement core.CompositeCanvas the child is
+// painted faded shape by shape, with core.Fade, and without the blend
+// mode.
+type Composite struct {
+	core.WidgetBase
+	child core.Widget
+
+	opacity float32
+	mode    core.BlendMode
+	sig     *state.Signal[float32]
+	cancel  func()
+	layer   core.Layer
+	scale   float32 // Of the canvas the layer was made for.
+}
+
+// NewComposite returns an opaque group of child in the normal mode.
+func NewComposite(child core.Widget) *Composite {
+	c := &Composite{child: child, opacity: 1}
+	c.SetChildren(child)
+	return c
+}
+
+// Opacity sets the opacity of the group, from 0 to 1. The group stops
+// following a signal bound to it.
+func (c *Composite) Opacity(a float32) *Composite {
+	c.unbind()
+	c.opacity = core.Clamp(a, 0, 1)
+	return c
+}
+
+// Bind keeps the opacity of the group at the value of sig, painting the
+// container again whenever it changes, such as to animate a fade.
+func (c *Composite) Bind(sig *state.Signal[float32]) *Composite {
+	c.unbind()
+	c.sig = sig
+	return c
+}
+
+func (c *Composite) unbind() {
+	if c.cancel != nil {
+		c.cancel()
+		c.cancel = nil
+	}
+	c.sig = nil
+}
+
+// Blend sets how the group blends with what lies beneath it.
+func (c *Composite) Blend(mode core.BlendMode) *Composite {
+	c.mode = mode
+	return c
+}
+
+// Release frees the layer, for when the container leaves the tree. It is
+// made again if the container is painted.
+func (c *Composite) Release() {
+	if c.layer != nil {
+		c.layer.Release()
+		c.layer = nil
+	}
+}
+
+// Layout implements core.Widget.
+func (c *Composite) Layout(ctx *core.LayoutContext) core.Size {
+	if c.sig != nil && c.cancel == nil {
+		cx := ctx.Context
+		c.cancel = c.sig.Subscribe(func(float32) { cx.Post(func() { cx.Repaint(c) }) })
+	}
+	return ctx.Measure(c.child, ctx.Constraints)
+}
+
+// IntrinsicWidth implements core.IntrinsicSizer.
+func (c *Composite) IntrinsicWidth(ctx *core.LayoutContext, height float32) (minWidth, maxWidth float32) {
+	return ctx.IntrinsicWidth(c.child, height)
+}
+
+// IntrinsicHeight implements core.IntrinsicSizer.
+func (c *Composite) IntrinsicHeight(ctx *core.LayoutContext, width float32) (minHeight, maxHeight float32) {
+	return ctx.IntrinsicHeight(c.child, width)
+}
+
+// Baseline implements core.Baseliner with the child's baseline.
+func (c *Composite) Baseline() (float32, bool) {
+	return core.BaselineOf(c.child)
+}
+
+// SetBounds implements core.Widget.
+func (c *Composite) SetBounds(r core.Rect) {
+	c.WidgetBase.SetBounds(r)
+	c.child.SetBounds(r)
+}
+
+// Paint implements core.Widget.
+func (c *Composite) Paint(ctx *core.PaintContext) {
+	b := c.Bounds()
+	opacity := c.opacity
+	if c.sig != nil {
+		opacity = core.Clamp(c.sig.Get(), 0, 1)
+	}
+	if b.IsEmpty() || opacity == 0 {
+		return
+	}
+	if opacity == 1 && c.mode == core.BlendNormal {
+		c.child.Paint(ctx)
+		return
+	}
+	cc, ok := ctx.Canvas.(core.CompositeCanvas)
+	if !ok {
+		pc := *ctx
+		pc.Canvas = core.Fade(ctx.Canvas, opacity)
+		c.child.Paint(&pc)
+		return
+	}
+	// Captures draw into canvases of their own, which the layer of the
+	// frames does not belong to.
+	l := c.layer
+	if ctx.Capture {
+		l = cc.NewLayer(b.Size())
+		defer l.Release()
+	} else if l == nil || l.Size() != b.Size() || c.scale != ctx.ScaleFactor() {
+		c.Release()
+		c.layer, c.scale = cc.NewLayer(b.Size()), ctx.ScaleFactor()
+		l = c.layer
+	}
+	pc := *ctx
+	pc.Canvas = l.Begin()
+	pc.Canvas.Translate(-b.X, -b.Y)
+	c.child.Paint(&pc)
+	l.End()
+	cc.DrawLayerComposite(l, b.Origin(), opacity, c.mode)
+}
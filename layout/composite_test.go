@@ -0,0 +1,137 @@
+package layout
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/state"
+)
+
+// composited is textures drawing layers faded and blended, recording how.
+type composited struct {
+	textures
+	opacity []float32
+	modes   []core.BlendMode
+}
+
+func (c *composited) DrawLayerComposite(l core.Layer, pos core.Point, opacity float32, mode core.BlendMode) {
+	c.DrawLayer(l, pos)
+	c.opacity, c.modes = append(c.opacity, opacity), append(c.modes, mode)
+}
+
+func TestComposite(t *testing.T) {
+	r := core.R(10, 20, 50, 30)
+	tests := []struct {
+		name    string
+		c       func(child core.Widget) *Composite
+		layered bool       // Whether the child is drawn into a layer.
+		faded   core.Color // The child's color without layers, if drawn.
+	}{
+		{"opaque", NewComposite, false, core.Black},
+		{"faded", func(c core.Widget) *Composite { return NewComposite(c).Opacity(0.5) }, true, core.Black.WithAlpha(0.5)},
+		{"blended", func(c core.Widget) *Composite { return NewComposite(c).Blend(core.BlendMultiply) }, true, core.Black},
+		{"invisible", func(c core.Widget) *Composite { return NewComposite(c).Opacity(-1).Blend(core.BlendScreen) }, false, core.Transparent},
+		{"too opaque", func(c core.Widget) *Composite { return NewComposite(c).Opacity(2) }, false, core.Black},
+	}
+	for _, tt := range tests {
+		child := &swatch{color: core.Black}
+		c := tt.c(child)
+		ctx := core.NewContext()
+		ctx.LayoutRoot(c, r)
+		if child.Bounds() != r {
+			t.Errorf("%s: child at %v, want over the container", tt.name, child.Bounds())
+		}
+
+		cv := &composited{}
+		c.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+		if got := len(cv.made) == 1; got != tt.layered || len(cv.opacity) != len(cv.made) {
+			t.Fatalf("%s: %d layers made and %d drawn, want layered %v", tt.name, len(cv.made), len(cv.opacity), tt.layered)
+		}
+		if tt.layered {
+			if cv.opacity[0] != c.opacity || cv.modes[0] != c.mode || cv.drawn[0] != r.Origin() {
+				t.Errorf("%s: layer drawn at %v with %v, mode %v", tt.name, cv.drawn[0], cv.opacity[0], cv.modes[0])
+			}
+			// The layer holds the child from its top left.
+			var want core.Recording
+			want.Translate(-r.X, -r.Y)
+			want.DrawRect(r, core.Filled(core.Black))
+			if !cv.made[0].rec.Equal(&want) || cv.Len() != 0 {
+				t.Errorf("%s: the layer does not hold the child alone", tt.name)
+			}
+		}
+
+		// Without layers, the child is faded shape by shape.
+		var got, want core.Recording
+		c.Paint(&core.PaintContext{Context: ctx, Canvas: &got})
+		if !tt.faded.IsTransparent() {
+			want.DrawRect(r, core.Filled(tt.faded))
+		}
+		if !got.Equal(&want) {
+			t.Errorf("%s: painted %d ops without layers, want the child in %v", tt.name, got.Len(), tt.faded)
+		}
+	}
+}
+
+func TestCompositeLayer(t *testing.T) {
+	child := &swatch{color: core.Black}
+	c := NewComposite(child).Opacity(0.5)
+	ctx := core.NewContext()
+	ctx.LayoutRoot(c, core.R(0, 0, 50, 30))
+	cv := &composited{}
+	paint := func(capture bool) { c.Paint(&core.PaintContext{Context: ctx, Canvas: cv, Capture: capture}) }
+
+	// The layer is kept across frames, and made again at another size or
+	// scale; captures draw into layers of their own.
+	paint(false)
+	paint(false)
+	if len(cv.made) != 1 || cv.made[0].drawn != 2 {
+		t.Errorf("%d layers made, want one drawn into twice", len(cv.made))
+	}
+	paint(true)
+	if len(cv.made) != 2 || !cv.made[1].released || cv.made[0].released {
+		t.Error("a capture drew into the layer of the frames")
+	}
+	ctx.SetScaleFactor(2)
+	paint(false)
+	ctx.LayoutRoot(c, core.R(0, 0, 60, 30))
+	paint(false)
+	if len(cv.made) != 4 || !cv.made[0].released || !cv.made[2].released || cv.made[3].size != core.Sz(60, 30) {
+		t.Errorf("%d layers made, want one again at the new scale and size", len(cv.made))
+	}
+	c.Release()
+	if !cv.made[3].released {
+		t.Error("Release kept the layer")
+	}
+}
+
+func TestCompositeBind(t *testing.T) {
+	opacity := state.New[float32](1)
+	c := NewComposite(&swatch{color: core.Black}).Opacity(0.3).Bind(opacity)
+	ctx := core.NewContext()
+	ctx.LayoutRoot(c, core.R(0, 0, 50, 30))
+	cv := &composited{}
+	c.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+	if len(cv.made) != 0 {
+		t.Error("a group bound to an opacity of 1 drew into a layer")
+	}
+
+	// A change of the signal repaints the container at the new opacity.
+	ctx.ClearRedraw()
+	opacity.Set(0.25)
+	ctx.RunPosted()
+	if !ctx.NeedsRedraw() {
+		t.Error("a change of opacity asked for no frame")
+	}
+	c.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+	if len(cv.opacity) != 1 || cv.opacity[0] != 0.25 {
+		t.Errorf("drawn with %v, want the signal's opacity", cv.opacity)
+	}
+
+	// Set again, the opacity stops following the signal.
+	c.Opacity(0.75)
+	opacity.Set(0.5)
+	c.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+	if cv.opacity[1] != 0.75 {
+		t.Errorf("drawn with %v after Opacity, want 0.75", cv.opacity[1])
+	}
+}
@@ -0,0 +1,344 @@
+package layout
+
+import (
+	"fmt"
+
+	"github.com/gogpu/ui/core"
+	ilayout "github.com/gogpu/ui/internal/layout"
+)
+
+// Strength is the priority of a Rule. When rules conflict, the stronger
+// one wins over any number of weaker ones.
+type Strength float64
+
+// Strengths of rules.
+const (
+	// Required rules always hold; adding one that conflicts with the
+	// required rules already added fails.
+	Required Strength = ilayout.Required
+	// Strong rules win over the size the container is given.
+	Strong Strength = ilayout.Strong
+	// Medium rules are as strong as the natural size of the children.
+	Medium Strength = ilayout.Medium
+	// Weak rules give way to everything else.
+	Weak Strength = ilayout.Weak
+)
+
+// Strengths of the implicit rules of a Constraints container.
+const (
+	containerStrength = ilayout.Strong   // Fill the space given.
+	naturalStrength   = ilayout.Medium   // Keep the natural size of children.
+	shrinkStrength    = ilayout.Weak / 2 // Shrink-wrap on unbounded axes.
+)
+
+// Expr is a linear expression over the edges of the widgets of a
+// Constraints container, such as a.Right().Add(8).
+type Expr struct {
+	e ilayout.Expression
+}
+
+// Const returns an expression with the constant value px.
+func Const(px float32) Expr {
+	return Expr{ilayout.Expression{Constant: float64(px)}}
+}
+
+func varExpr(v *ilayout.Variable, coeff float64) Expr {
+	return Expr{ilayout.Expression{Terms: []ilayout.Term{{Var: v, Coeff: coeff}}}}
+}
+
+// Plus returns e + o.
+func (e Expr) Plus(o Expr) Expr {
+	terms := append(append([]ilayout.Term(nil), e.e.Terms...), o.e.Terms...)
+	return Expr{ilayout.Expression{Terms: terms, Constant: e.e.Constant + o.e.Constant}}
+}
+
+// Minus returns e - o.
+func (e Expr) Minus(o Expr) Expr {
+	return e.Plus(o.Times(-1))
+}
+
+// Add returns e + px.
+func (e Expr) Add(px float32) Expr {
+	return e.Plus(Const(px))
+}
+
+// Times returns e scaled by k.
+func (e Expr) Times(k float32) Expr {
+	terms := make([]ilayout.Term, len(e.e.Terms))
+	for i, t := range e.e.Terms {
+		terms[i] = ilayout.Term{Var: t.Var, Coeff: t.Coeff * float64(k)}
+	}
+	return Expr{ilayout.Expression{Terms: terms, Constant: e.e.Constant * float64(k)}}
+}
+
+// Eq returns the rule e == o.
+func (e Expr) Eq(o Expr) *Rule {
+	return newRule(e.Minus(o), ilayout.EQ)
+}
+
+// Le returns the rule e <= o.
+func (e Expr) Le(o Expr) *Rule {
+	return newRule(e.Minus(o), ilayout.LE)
+}
+
+// Ge returns the rule e >= o.
+func (e Expr) Ge(o Expr) *Rule {
+	return newRule(e.Minus(o), ilayout.GE)
+}
+
+// Rule is a relation between two expressions for a Constraints container
+// to satisfy, made with Expr.Eq, Expr.Le or Expr.Ge.
+type Rule struct {
+	expr     ilayout.Expression
+	op       ilayout.Relation
+	strength Strength
+	c        *ilayout.Constraint
+}
+
+func newRule(e Expr, op ilayout.Relation) *Rule {
+	return &Rule{expr: e.e, op: op, strength: Required}
+}
+
+// Strength sets the priority of the rule, Required by default. It has no
+// effect once the rule was added to a container.
+func (r *Rule) Strength(s Strength) *Rule {
+	r.strength = s
+	return r
+}
+
+// Anchors are the edges of a widget in a Constraints container, relative
+// to the container's top left corner.
+type Anchors struct {
+	left, top, width, height ilayout.Variable
+
+	widget  core.Widget
+	natural core.Size
+	rules   []*ilayout.Constraint // Implicit rules.
+}
+
+// Left returns the position of the left edge.
+func (a *Anchors) Left() Expr { return varExpr(&a.left, 1) }
+
+// Top returns the position of the top edge.
+func (a *Anchors) Top() Expr { return varExpr(&a.top, 1) }
+
+// Width returns the width.
+func (a *Anchors) Width() Expr { return varExpr(&a.width, 1) }
+
+// Height returns the height.
+func (a *Anchors) Height() Expr { return varExpr(&a.height, 1) }
+
+// Right returns the position of the right edge.
+func (a *Anchors) Right() Expr { return a.Left().Plus(a.Width()) }
+
+// Bottom returns the position of the bottom edge.
+func (a *Anchors) Bottom() Expr { return a.Top().Plus(a.Height()) }
+
+// CenterX returns the position of the horizontal center.
+func (a *Anchors) CenterX() Expr { return a.Left().Plus(a.Width().Times(0.5)) }
+
+// CenterY returns the position of the vertical center.
+func (a *Anchors) CenterY() Expr { return a.Top().Plus(a.Height().Times(0.5)) }
+
+func (a *Anchors) rect() core.Rect {
+	return core.R(float32(a.left.Value), float32(a.top.Value), max(0, float32(a.width.Value)), max(0, float32(a.height.Value)))
+}
+
+// Constraints positions its children by rules relating their edges to
+// each other and to the container, as in
+//
+//	c := layout.NewConstraints()
+//	list, detail := c.Add(listView), c.Add(detailView)
+//	p := c.Parent()
+//	err := c.Require(
+//		list.Left().Eq(p.Left()),
+//		list.Width().Ge(layout.Const(120)),
+//		detail.Left().Eq(list.Right().Add(8)),
+//		detail.Right().Eq(p.Right()),
+//	)
+//
+// The rules are solved with the Cassowary algorithm. Rules that are not
+// required hold as well as possible by their strength. Each child
+// prefers its natural size with Medium strength and the container fills
+// the space it is given with Strong strength; on an unbounded axis it
+// shrinks to fit its children, which it weakly prefers to contain.
+//
+// The rules are solved incrementally: a resize only updates the solution.
+type Constraints struct {
+	core.WidgetBase
+
+	solver   *ilayout.Solver
+	parent   *Anchors
+	items    []*Anchors
+	bounded  [2]bool // Whether the parent size was suggested with containerStrength, per axis.
+	parentSz core.Size
+}
+
+// NewConstraints returns an empty constraint container.
+func NewConstraints() *Constraints {
+	c := &Constraints{solver: ilayout.NewSolver(), parent: &Anchors{}}
+	p := c.parent
+	c.require(p.Left().Eq(Const(0)))
+	c.require(p.Top().Eq(Const(0)))
+	c.solver.AddEditVariable(&p.width, shrinkStrength)
+	c.solver.AddEditVariable(&p.height, shrinkStrength)
+	return c
+}
+
+func (c *Constraints) require(r *Rule) *ilayout.Constraint {
+	k := ilayout.NewConstraint(r.expr, r.op, float64(r.strength))
+	c.solver.AddConstraint(k)
+	return k
+}
+
+// Parent returns the edges of the container itself. Its left and top are
+// zero.
+func (c *Constraints) Parent() *Anchors {
+	return c.parent
+}
+
+// Add adds child and returns its edges. Adding a child twice returns the
+// same edges.
+func (c *Constraints) Add(child core.Widget) *Anchors {
+	if a := c.Anchors(child); a != nil {
+		return a
+	}
+	a := &Anchors{widget: child}
+	a.rules = []*ilayout.Constraint{
+		c.require(a.Width().Ge(Const(0))),
+		c.require(a.Height().Ge(Const(0))),
+		c.require(a.Left().Ge(c.parent.Left()).Strength(Weak)),
+		c.require(a.Top().Ge(c.parent.Top()).Strength(Weak)),
+		c.require(c.parent.Right().Ge(a.Right()).Strength(Weak)),
+		c.require(c.parent.Bottom().Ge(a.Bottom()).Strength(Weak)),
+	}
+	c.solver.AddEditVariable(&a.width, naturalStrength)
+	c.solver.AddEditVariable(&a.height, naturalStrength)
+	c.items = append(c.items, a)
+	c.AppendChild(child)
+	return a
+}
+
+// Anchors returns the edges of child, or nil if it was not added.
+func (c *Constraints) Anchors(child core.Widget) *Anchors {
+	for _, a := range c.items {
+		if a.widget == child {
+			return a
+		}
+	}
+	return nil
+}
+
+// Remove removes child. The rules that mention its edges stay in effect
+// until they are removed.
+func (c *Constraints) Remove(child core.Widget) {
+	for i, a := range c.items {
+		if a.widget != child {
+			continue
+		}
+		c.solver.RemoveEditVariable(&a.width)
+		c.solver.RemoveEditVariable(&a.height)
+		for _, k := range a.rules {
+			c.solver.RemoveConstraint(k)
+		}
+		c.items = append(c.items[:i], c.items[i+1:]...)
+		children := make([]core.Widget, len(c.items))
+		for k, a := range c.items {
+			children[k] = a.widget
+		}
+		c.SetChildren(children...)
+		return
+	}
+}
+
+// Require adds rules. It stops at the first required rule that conflicts
+// with the required rules already added and returns an error naming it;
+// the rules before it stay added.
+func (c *Constraints) Require(rules ...*Rule) error {
+	for i, r := range rules {
+		if r.c != nil {
+			continue
+		}
+		k := ilayout.NewConstraint(r.expr, r.op, float64(r.strength))
+		if err := c.solver.AddConstraint(k); err != nil {
+			return fmt.Errorf("layout: rule %d: %w", i, err)
+		}
+		r.c = k
+	}
+	return nil
+}
+
+// Release removes rules added with Require.
+func (c *Constraints) Release(rules ...*Rule) {
+	for _, r := range rules {
+		if r.c != nil {
+			c.solver.RemoveConstraint(r.c)
+			r.c = nil
+		}
+	}
+}
+
+// suggestParent offers the solver the size given to the container, on
+// bounded axes with containerStrength and on unbounded ones as zero with
+// shrinkStrength.
+func (c *Constraints) suggestParent(size core.Size, bounded [2]bool) {
+	p := c.parent
+	for axis, v := range []*ilayout.Variable{&p.width, &p.height} {
+		if bounded[axis] != c.bounded[axis] {
+			c.solver.RemoveEditVariable(v)
+			strength := float64(shrinkStrength)
+			if bounded[axis] {
+				strength = containerStrength
+			}
+			c.solver.AddEditVariable(v, strength)
+			c.bounded[axis] = bounded[axis]
+		}
+	}
+	w, h := float64(size.Width), float64(size.Height)
+	if !bounded[0] {
+		w = 0
+	}
+	if !bounded[1] {
+		h = 0
+	}
+	c.solver.SuggestValue(&p.width, w)
+	c.solver.SuggestValue(&p.height, h)
+	c.solver.UpdateVariables()
+}
+
+// Layout implements core.Widget.
+func (c *Constraints) Layout(ctx *core.LayoutContext) core.Size {
+	cs := ctx.Constraints
+	for _, a := range c.items {
+		a.natural = ctx.Measure(a.widget, cs.Loosen())
+		c.solver.SuggestValue(&a.width, float64(a.natural.Width))
+		c.solver.SuggestValue(&a.height, float64(a.natural.Height))
+	}
+	c.suggestParent(core.Sz(cs.MaxWidth, cs.MaxHeight), [2]bool{cs.HasBoundedWidth(), cs.HasBoundedHeight()})
+	for _, a := range c.items {
+		r := a.rect()
+		if r.Width != a.natural.Width || r.Height != a.natural.Height {
+			ctx.Measure(a.widget, core.Tight(r.Size()))
+		}
+	}
+	c.parentSz = cs.Constrain(c.parent.rect().Size())
+	return c.parentSz
+}
+
+// SetBounds implements core.Widget.
+func (c *Constraints) SetBounds(r core.Rect) {
+	c.WidgetBase.SetBounds(r)
+	if r.Size() != c.parentSz {
+		c.suggestParent(r.Size(), [2]bool{true, true})
+		c.parentSz = r.Size()
+	}
+	for _, a := range c.items {
+		ar := a.rect()
+		a.widget.SetBounds(core.R(r.X+ar.X, r.Y+ar.Y, ar.Width, ar.Height))
+	}
+}
+
+// Paint implements core.Widget.
+func (c *Constraints) Paint(ctx *core.PaintContext) {
+	core.PaintChildren(ctx, c.Children())
+}
@@ -0,0 +1,204 @@
+package layout
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	ilayout "github.com/gogpu/ui/internal/layout"
+)
+
+// masterDetail returns a list beside a detail view that fills the rest of
+// the container, as in the example of Constraints.
+func masterDetail() (c *Constraints, list, detail *box) {
+	c = NewConstraints()
+	list, detail = newBox(100, 20), newBox(50, 30)
+	l, d, p := c.Add(list), c.Add(detail), c.Parent()
+	err := c.Require(
+		l.Left().Eq(p.Left()),
+		l.Top().Eq(p.Top()),
+		l.Width().Ge(Const(120)),
+		d.Left().Eq(l.Right().Add(8)),
+		d.Right().Eq(p.Right()),
+		d.Top().Eq(p.Top()),
+	)
+	if err != nil {
+		panic(err)
+	}
+	return c, list, detail
+}
+
+func TestConstraintsPlacesChildren(t *testing.T) {
+	tests := []struct {
+		name   string
+		bounds core.Rect
+		list   core.Rect
+		detail core.Rect
+	}{
+		{"wide", core.R(0, 0, 300, 100), core.R(0, 0, 120, 20), core.R(128, 0, 172, 30)},
+		{"narrow", core.R(10, 20, 200, 100), core.R(10, 20, 120, 20), core.R(138, 20, 72, 30)},
+		{"too narrow", core.R(0, 0, 100, 100), core.R(0, 0, 120, 20), core.R(128, 0, 0, 30)},
+	}
+	c, list, detail := masterDetail()
+	ctx := core.NewContext()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The same container is resized from one case to the next.
+			ctx.LayoutRoot(c, tt.bounds)
+			if got := list.Bounds(); got != tt.list {
+				t.Errorf("list bounds = %v, want %v", got, tt.list)
+			}
+			if got := detail.Bounds(); got != tt.detail {
+				t.Errorf("detail bounds = %v, want %v", got, tt.detail)
+			}
+		})
+	}
+}
+
+// origin returns the rules placing a at the top left corner of p.
+func origin(a, p *Anchors) []*Rule {
+	return []*Rule{a.Left().Eq(p.Left()), a.Top().Eq(p.Top())}
+}
+
+func TestConstraintsExpressions(t *testing.T) {
+	tests := []struct {
+		name string
+		rule func(a, p *Anchors) []*Rule
+		want core.Rect
+	}{
+		{"natural size", func(a, p *Anchors) []*Rule { return origin(a, p) }, core.R(0, 0, 40, 10)},
+		{"centered", func(a, p *Anchors) []*Rule {
+			return []*Rule{a.CenterX().Eq(p.CenterX()), a.CenterY().Eq(p.CenterY())}
+		}, core.R(80, 45, 40, 10)},
+		{"bottom right", func(a, p *Anchors) []*Rule {
+			return []*Rule{a.Right().Eq(p.Right().Add(-8)), a.Bottom().Eq(p.Bottom().Minus(Const(8)))}
+		}, core.R(152, 82, 40, 10)},
+		{"half the width", func(a, p *Anchors) []*Rule {
+			return append(origin(a, p), a.Width().Eq(p.Width().Times(0.5)), a.Height().Le(Const(4)))
+		}, core.R(0, 0, 100, 4)},
+		{"aspect", func(a, p *Anchors) []*Rule {
+			return []*Rule{
+				a.Height().Eq(a.Width().Times(2)),
+				a.Width().Eq(Const(30)).Strength(Strong),
+				a.Left().Plus(a.Width()).Eq(Const(50)),
+				a.Top().Eq(p.Top()),
+			}
+		}, core.R(20, 0, 30, 60)},
+		{"weak rule", func(a, p *Anchors) []*Rule {
+			return append(origin(a, p), a.Width().Eq(Const(10)).Strength(Weak))
+		}, core.R(0, 0, 40, 10)},
+		{"strong rule", func(a, p *Anchors) []*Rule {
+			return append(origin(a, p), a.Width().Eq(Const(10)).Strength(Strong))
+		}, core.R(0, 0, 10, 10)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewConstraints()
+			b := newBox(40, 10)
+			a := c.Add(b)
+			if err := c.Require(tt.rule(a, c.Parent())...); err != nil {
+				t.Fatal(err)
+			}
+			ctx := core.NewContext()
+			ctx.LayoutRoot(c, core.R(0, 0, 200, 100))
+			if got := b.Bounds(); got != tt.want {
+				t.Errorf("bounds = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConstraintsShrinkWrap(t *testing.T) {
+	c := NewConstraints()
+	a, b := newBox(40, 10), newBox(30, 20)
+	aa, ba := c.Add(a), c.Add(b)
+	c.Require(ba.Left().Eq(aa.Right().Add(8)), ba.Top().Eq(Const(4)))
+	lc := &core.LayoutContext{Context: core.NewContext()}
+	tests := []struct {
+		name string
+		cs   core.Constraints
+		want core.Size
+	}{
+		{"unbounded", core.Unbounded(), core.Sz(78, 24)},
+		{"bounded width", core.Loose(core.Sz(200, core.Infinity)), core.Sz(200, 24)},
+		{"unbounded again", core.Unbounded(), core.Sz(78, 24)},
+	}
+	for _, tt := range tests {
+		if got := lc.Measure(c, tt.cs); got != tt.want {
+			t.Errorf("%s: size = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestConstraintsRules(t *testing.T) {
+	c := NewConstraints()
+	b := newBox(40, 10)
+	a := c.Add(b)
+	if c.Add(b) != a || c.Anchors(b) != a || c.Anchors(newBox(1, 1)) != nil {
+		t.Fatal("the anchors of the child are not the same")
+	}
+	fixed := a.Width().Eq(Const(50))
+	err := c.Require(a.Left().Eq(Const(5)), a.Top().Eq(Const(7)), fixed, a.Width().Eq(Const(60)), a.Height().Eq(Const(1)))
+	if !errors.Is(err, ilayout.ErrUnsatisfiable) || !strings.Contains(err.Error(), "rule 3") {
+		t.Fatalf("Require = %v, want rule 3 unsatisfiable", err)
+	}
+	ctx := core.NewContext()
+	bounds := core.R(0, 0, 200, 100)
+	ctx.LayoutRoot(c, bounds)
+	if got := b.Bounds(); got != core.R(5, 7, 50, 10) {
+		t.Errorf("bounds = %v, want the rules before the conflict", got)
+	}
+	if err := c.Require(fixed); err != nil {
+		t.Errorf("requiring an added rule again = %v", err)
+	}
+
+	c.Release(fixed, fixed)
+	ctx.LayoutRoot(c, bounds)
+	if got := b.Bounds(); got != core.R(5, 7, 40, 10) {
+		t.Errorf("after Release bounds = %v", got)
+	}
+
+	// A released rule can be added again, with another strength. Its
+	// strength is fixed once added.
+	if err := c.Require(fixed.Strength(Strong)); err != nil {
+		t.Fatal(err)
+	}
+	fixed.Strength(Weak)
+	ctx.LayoutRoot(c, core.R(0, 0, 201, 100))
+	if got := b.Bounds(); got != core.R(5, 7, 50, 10) {
+		t.Errorf("bounds = %v, want the width of the strong rule", got)
+	}
+}
+
+func TestConstraintsRemove(t *testing.T) {
+	c, list, detail := masterDetail()
+	c.Remove(newBox(1, 1))
+	c.Remove(list)
+	if got := c.Children(); len(got) != 1 || got[0] != detail || c.Anchors(list) != nil {
+		t.Fatalf("children = %v, want [detail]", got)
+	}
+	// The rules about the list still hold, but without its natural size
+	// it widens for the detail to keep its own.
+	ctx := core.NewContext()
+	ctx.LayoutRoot(c, core.R(0, 0, 300, 100))
+	if got := detail.Bounds(); got != core.R(250, 0, 50, 30) {
+		t.Errorf("detail bounds = %v, want %v", got, core.R(250, 0, 50, 30))
+	}
+}
+
+func TestConstraintsSetBounds(t *testing.T) {
+	// Bounds other than the measured size solve the rules again.
+	c, list, detail := masterDetail()
+	lc := &core.LayoutContext{Context: core.NewContext()}
+	if got := lc.Measure(c, core.Loose(core.Sz(300, 100))); got != core.Sz(300, 100) {
+		t.Errorf("size = %v, want all of the space", got)
+	}
+	c.SetBounds(core.R(0, 0, 250, 60))
+	if got := detail.Bounds(); got != core.R(128, 0, 122, 30) {
+		t.Errorf("detail bounds = %v, want %v", got, core.R(128, 0, 122, 30))
+	}
+	if got := list.Bounds(); got != core.R(0, 0, 120, 20) {
+		t.Errorf("list bounds = %v, want %v", got, core.R(0, 0, 120, 20))
+	}
+}
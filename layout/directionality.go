@@ -0,0 +1,57 @@
+package layout
+
+import "github.com/gogpu/ui/core"
+
+// Directionality sets the reading direction of its subtree, for content
+// that keeps its own direction in a mirrored UI, such as a phone number
+// or a code snippet in a right-to-left window.
+type Directionality struct {
+	core.WidgetBase
+	dir   core.Direction
+	child core.Widget
+}
+
+// NewDirectionality returns child laid out and painted in direction dir.
+func NewDirectionality(dir core.Direction, child core.Widget) *Directionality {
+	d := &Directionality{dir: dir, child: child}
+	d.SetChildren(child)
+	return d
+}
+
+// Direction returns the direction of the subtree.
+func (d *Directionality) Direction() core.Direction {
+	return d.dir
+}
+
+// SetDirection changes the direction of the subtree. It takes effect at
+// the next layout.
+func (d *Directionality) SetDirection(dir core.Direction) {
+	d.dir = dir
+}
+
+// Layout implements core.Widget.
+func (d *Directionality) Layout(ctx *core.LayoutContext) core.Size {
+	prev := ctx.Direction()
+	ctx.SetDirection(d.dir)
+	defer ctx.SetDirection(prev)
+	return ctx.Measure(d.child, ctx.Constraints)
+}
+
+// Baseline implements core.Baseliner with the child's baseline.
+func (d *Directionality) Baseline() (float32, bool) {
+	return core.BaselineOf(d.child)
+}
+
+// SetBounds implements core.Widget.
+func (d *Directionality) SetBounds(r core.Rect) {
+	d.WidgetBase.SetBounds(r)
+	d.child.SetBounds(r)
+}
+
+// Paint implements core.Widget.
+func (d *Directionality) Paint(ctx *core.PaintContext) {
+	prev := ctx.Direction()
+	ctx.SetDirection(d.dir)
+	defer ctx.SetDirection(prev)
+	d.child.Paint(ctx)
+}
@@ -0,0 +1,141 @@
+package layout
+
+import "github.com/gogpu/ui/core"
+
+// DisplayList records what its child paints into a core.Recording and
+// replays the recording in later frames, painting the child again only
+// when it repaints: when a region it covers is damaged, its size changes
+// or Invalidate is called. Frames that repaint other parts of the window
+// cost the child nothing, however expensive its paint is.
+//
+// The recorded content can be panned and zoomed without being painted
+// again, for pan and zoom of diagrams and previews:
+//
+//	view := layout.NewDisplayList(diagram)
+//	view.Pan(core.Pt(-120, -40)).Zoom(2)
+//	ctx.Repaint(view)
+//
+// Input still reaches the child where it was laid out, so transformed
+// content is for looking at, or its owner maps the input itself. Shadows
+// and effects the child draws through optional canvas capabilities are
+// not recorded.
+type DisplayList struct {
+	core.WidgetBase
+	child core.Widget
+
+	pan  core.Point
+	zoom float32
+
+	list     core.Recording
+	recorded core.Size
+	frame    uint64 // Of the context, when the list was last checked.
+	dirty    bool
+}
+
+// NewDisplayList returns a container recording what child paints.
+func NewDisplayList(child core.Widget) *DisplayList {
+	d := &DisplayList{child: child, zoom: 1, dirty: true}
+	d.SetChildren(child)
+	return d
+}
+
+// Pan moves the recorded content by offset, in logical pixels, clipped to
+// the bounds of the container. Repaint the container after changing it.
+func (d *DisplayList) Pan(offset core.Point) *DisplayList {
+	d.pan = offset
+	return d
+}
+
+// Zoom scales the recorded content by z about the top left of the
+// container, clipped to its bounds. Text is drawn again at the scaled
+// size rather than stretched. Repaint the container after changing it.
+func (d *DisplayList) Zoom(z float32) *DisplayList {
+	if z > 0 {
+		d.zoom = z
+	}
+	return d
+}
+
+// Transform returns the pan offset and zoom.
+func (d *DisplayList) Transform() (pan core.Point, zoom float32) {
+	return d.pan, d.zoom
+}
+
+// Invalidate makes the child be painted again in the next frame, for
+// changes it does not repaint itself for.
+func (d *DisplayList) Invalidate() {
+	d.dirty = true
+}
+
+// Release frees what was recorded, for when the container leaves the
+// tree.
+func (d *DisplayList) Release() {
+	d.list.Reset()
+	d.dirty = true
+}
+
+// Layout implements core.Widget.
+func (d *DisplayList) Layout(ctx *core.LayoutContext) core.Size {
+	return ctx.Measure(d.child, ctx.Constraints)
+}
+
+// IntrinsicWidth implements core.IntrinsicSizer.
+func (d *DisplayList) IntrinsicWidth(ctx *core.LayoutContext, height float32) (minWidth, maxWidth float32) {
+	return ctx.IntrinsicWidth(d.child, height)
+}
+
+// IntrinsicHeight implements core.IntrinsicSizer.
+func (d *DisplayList) IntrinsicHeight(ctx *core.LayoutContext, width float32) (minHeight, maxHeight float32) {
+	return ctx.IntrinsicHeight(d.child, width)
+}
+
+// Baseline implements core.Baseliner with the child's baseline.
+func (d *DisplayList) Baseline() (float32, bool) {
+	return core.BaselineOf(d.child)
+}
+
+// SetBounds implements core.Widget.
+func (d *DisplayList) SetBounds(r core.Rect) {
+	d.WidgetBase.SetBounds(r)
+	d.child.SetBounds(r)
+}
+
+// Paint implements core.Widget.
+func (d *DisplayList) Paint(ctx *core.PaintContext) {
+	b := d.Bounds()
+	if b.IsEmpty() {
+		return
+	}
+	// The window paints once a frame, clipped to the region bounding the
+	// damage, but a capture can paint the list again in the same frame,
+	// so it is checked once a frame. Damage the list was not painted for,
+	// in a frame it was left out of, makes it stale as well.
+	if frame := ctx.FrameNumber(); d.frame != frame {
+		stale := d.dirty || d.recorded != b.Size() || d.frame+1 != frame || ctx.Damaged(b)
+		d.frame = frame
+		if stale {
+			d.dirty, d.recorded = false, b.Size()
+			d.list.Reset()
+			d.list.Translate(-b.X, -b.Y)
+			d.child.Paint(&core.PaintContext{Context: ctx.Context, Canvas: &d.list})
+		}
+	}
+	cv := ctx.Canvas
+	if d.pan == (core.Point{}) && d.zoom == 1 {
+		cv.Save()
+		cv.Translate(b.X, b.Y)
+		d.list.Replay(cv)
+		cv.Restore()
+		return
+	}
+	// What the child repaints shows elsewhere in the bounds once moved,
+	// so damage to any of them paints all of them.
+	if !ctx.Capture {
+		ctx.AddBackdrop(b)
+	}
+	cv.Save()
+	cv.Clip(b)
+	cv.Translate(b.X+d.pan.X, b.Y+d.pan.Y)
+	d.list.Replay(core.Zoom(cv, d.zoom))
+	cv.Restore()
+}
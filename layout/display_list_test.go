@@ -0,0 +1,138 @@
+package layout
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+)
+
+// filler fills its bounds and counts its paints.
+type filler struct {
+	box
+	paints int
+}
+
+func (p *filler) Paint(ctx *core.PaintContext) {
+	p.paints++
+	ctx.Canvas.DrawRect(p.Bounds(), core.Filled(core.RGB(255, 0, 0)))
+}
+
+func TestDisplayListRepaints(t *testing.T) {
+	bounds := core.R(10, 20, 100, 50)
+	tests := []struct {
+		name    string
+		change  func(ctx *core.Context, d *DisplayList) core.Rect // Returns the bounds of the frame.
+		repaint bool
+	}{
+		{"damage elsewhere", func(ctx *core.Context, _ *DisplayList) core.Rect {
+			ctx.InvalidateRect(core.R(200, 200, 10, 10))
+			return bounds
+		}, false},
+		{"damage over it", func(ctx *core.Context, _ *DisplayList) core.Rect {
+			ctx.InvalidateRect(core.R(100, 60, 20, 20))
+			return bounds
+		}, true},
+		{"invalidated", func(ctx *core.Context, d *DisplayList) core.Rect {
+			d.Invalidate()
+			ctx.InvalidateRect(core.R(200, 200, 10, 10))
+			return bounds
+		}, true},
+		{"resized", func(ctx *core.Context, _ *DisplayList) core.Rect {
+			ctx.InvalidateRect(core.R(200, 200, 10, 10))
+			return core.R(10, 20, 120, 50)
+		}, true},
+		{"frame left out", func(ctx *core.Context, _ *DisplayList) core.Rect {
+			// A frame damaging the list that did not paint it.
+			ctx.InvalidateRect(bounds)
+			ctx.LayoutRoot(newBox(400, 400), core.R(0, 0, 400, 400))
+			ctx.InvalidateRect(core.R(200, 200, 10, 10))
+			return bounds
+		}, true},
+		{"released", func(ctx *core.Context, d *DisplayList) core.Rect {
+			d.Release()
+			ctx.InvalidateRect(core.R(200, 200, 10, 10))
+			return bounds
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			child := &filler{box: box{size: core.Sz(100, 50), base: -1}}
+			d := NewDisplayList(child)
+			ctx := core.NewContext()
+			frame := func(r core.Rect) *core.Recording {
+				ctx.LayoutRoot(d, r)
+				var cv core.Recording
+				d.Paint(&core.PaintContext{Context: ctx, Canvas: &cv})
+				return &cv
+			}
+			first := frame(bounds)
+			r := tt.change(ctx, d)
+			got := frame(r)
+			if want := map[bool]int{false: 1, true: 2}[tt.repaint]; child.paints != want {
+				t.Errorf("the child painted %d times, want %d", child.paints, want)
+			}
+
+			// What is replayed is what the child painted, where it is.
+			if r == bounds && !first.Equal(replayed(bounds, bounds)) {
+				t.Error("the first frame replayed something else")
+			}
+			if tt.repaint && !got.Equal(replayed(r, r)) {
+				t.Error("the second frame replayed something else")
+			}
+		})
+	}
+}
+
+// replayed returns what a display list at r replays of a filler painted
+// at painted.
+func replayed(r, painted core.Rect) *core.Recording {
+	var want core.Recording
+	want.Save()
+	want.Translate(r.X, r.Y)
+	want.Translate(-painted.X, -painted.Y)
+	want.DrawRect(painted, core.Filled(core.RGB(255, 0, 0)))
+	want.Restore()
+	return &want
+}
+
+func TestDisplayListTransform(t *testing.T) {
+	bounds := core.R(10, 20, 100, 50)
+	child := &filler{box: box{size: core.Sz(100, 50), base: -1}}
+	d := NewDisplayList(child).Pan(core.Pt(-5, 3)).Zoom(2)
+	if pan, zoom := d.Transform(); pan != core.Pt(-5, 3) || zoom != 2 {
+		t.Errorf("Transform() = %v, %v", pan, zoom)
+	}
+	if d.Zoom(0); d.zoom != 2 {
+		t.Errorf("Zoom(0) set the zoom to %v", d.zoom)
+	}
+	ctx := core.NewContext()
+	ctx.LayoutRoot(d, bounds)
+	var cv core.Recording
+	d.Paint(&core.PaintContext{Context: ctx, Canvas: &cv})
+
+	var want core.Recording
+	want.Save()
+	want.Clip(bounds)
+	want.Translate(5, 23)
+	zoomed := core.Zoom(&want, 2)
+	zoomed.Translate(-10, -20)
+	zoomed.DrawRect(bounds, core.Filled(core.RGB(255, 0, 0)))
+	want.Restore()
+	if !cv.Equal(&want) {
+		t.Error("the transformed list replayed something else")
+	}
+
+	// Moved content shows what the child repaints anywhere in the bounds,
+	// so damage next to it damages all of it.
+	ctx.InvalidateRect(core.R(0, 0, 12, 22))
+	ctx.LayoutRoot(d, bounds)
+	d.Paint(&core.PaintContext{Context: ctx, Canvas: &cv})
+	ctx.InvalidateRect(core.R(100, 60, 20, 20))
+	ctx.LayoutRoot(d, bounds)
+	if rects, _ := ctx.Damage(); len(rects) != 2 || rects[1] != bounds {
+		t.Errorf("Damage() = %v, want the bounds added", rects)
+	}
+	if child.paints != 2 {
+		t.Errorf("the child painted %d times, want 2", child.paints)
+	}
+}
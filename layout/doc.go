@@ -0,0 +1,7 @@
+// Package layout provides container widgets that size and position their
+// children.
+//
+// Containers follow the core widget protocol: they measure children during
+// Layout, position them in SetBounds and report them through Children so
+// that hit testing and event routing reach them.
+package layout
@@ -0,0 +1,629 @@
+package layout
+
+import (
+	"slices"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/theme"
+)
+
+// Panel is a dockable piece of UI managed by a DockSpace.
+type Panel struct {
+	// ID identifies the panel in saved layouts. It must be unique within a
+	// DockSpace and stable across runs.
+	ID string
+
+	// Title is shown on the panel's tab.
+	Title string
+
+	// Content is the panel's widget.
+	Content core.Widget
+
+	// Closable shows a close button on the panel's tab.
+	Closable bool
+}
+
+type floatingPanel struct {
+	panel  *Panel
+	bounds core.Rect
+}
+
+const (
+	defaultDockStripHeight float32 = 28
+	defaultDividerSize     float32 = 4
+	defaultMinPaneSize     float32 = 60
+	dockTabPadding         float32 = 10
+	dockCloseSize          float32 = 14
+	dockDragThreshold      float32 = 6
+	dockRootEdge           float32 = 24
+)
+
+// DockSpace is an IDE-style docking area. Panels live in tab groups that
+// are arranged by nested horizontal and vertical splits.
+//
+// Users resize groups by dragging the dividers between them, switch and
+// close tabs, and rearrange panels by dragging a tab: dropping it on a tab
+// strip or the middle of a group adds it to that group, dropping it near a
+// group's edge splits the group, dropping it near the edge of the whole
+// space docks it along that edge, and dropping it outside the space floats
+// it. Floating panels are handed to the OnFloat handler, which typically
+// shows them in a separate window.
+//
+// The arrangement can be saved to JSON with Save and restored with Restore.
+type DockSpace struct {
+	core.WidgetBase
+
+	root        *dockNode
+	floating    []floatingPanel
+	known       map[string]*Panel
+	stripHeight float32
+	dividerSize float32
+	minPaneSize float32
+	dividers    []dockDivider
+
+	hoverTab  *Panel
+	divDrag   int
+	press     *Panel
+	pressPos  core.Point
+	dragging  bool
+	dragPos   core.Point
+	dropGroup *dockNode
+	dropEdge  DockEdge
+	dropRoot  bool
+	dropFloat bool
+
+	onFloat  func(p *Panel, bounds core.Rect)
+	onClose  func(p *Panel) bool
+	onChange func()
+	measurer func(p *Panel) float32
+}
+
+// NewDockSpace returns an empty dock space.
+func NewDockSpace() *DockSpace {
+	return &DockSpace{
+		known:       make(map[string]*Panel),
+		stripHeight: defaultDockStripHeight,
+		dividerSize: defaultDividerSize,
+		minPaneSize: defaultMinPaneSize,
+		divDrag:     -1,
+	}
+}
+
+// StripHeight sets the height of each group's tab strip.
+func (d *DockSpace) StripHeight(h float32) *DockSpace {
+	d.stripHeight = max(0, h)
+	return d
+}
+
+// DividerSize sets the thickness of the dividers between groups.
+func (d *DockSpace) DividerSize(px float32) *DockSpace {
+	d.dividerSize = max(1, px)
+	return d
+}
+
+// MinPaneSize sets the smallest size a divider drag may shrink a group to.
+func (d *DockSpace) MinPaneSize(px float32) *DockSpace {
+	d.minPaneSize = max(0, px)
+	return d
+}
+
+// OnFloat registers fn to be called when a panel is floated, either by the
+// user dragging its tab outside the space or by FloatPanel and Restore.
+// bounds is the requested window rectangle in this window's coordinates.
+// Without a handler, tabs dropped outside the space stay where they were.
+func (d *DockSpace) OnFloat(fn func(p *Panel, bounds core.Rect)) *DockSpace {
+	d.onFloat = fn
+	return d
+}
+
+// OnClose registers fn to be called before the user closes a panel.
+// Returning false keeps it open.
+func (d *DockSpace) OnClose(fn func(p *Panel) bool) *DockSpace {
+	d.onClose = fn
+	return d
+}
+
+// OnLayoutChange registers fn to be called after the user changes the
+// arrangement, for example to persist it with Save.
+func (d *DockSpace) OnLayoutChange(fn func()) *DockSpace {
+	d.onChange = fn
+	return d
+}
+
+func (d *DockSpace) changed() {
+	if d.onChange != nil {
+		d.onChange()
+	}
+}
+
+func (d *DockSpace) register(p *Panel) {
+	if p.ID != "" {
+		d.known[p.ID] = p
+	}
+}
+
+// AddPanel docks p along edge of the whole space. DockCenter adds it as a
+// tab of the first group. A panel that is already docked or floating is
+// moved.
+func (d *DockSpace) AddPanel(p *Panel, edge DockEdge) *DockSpace {
+	d.remove(p)
+	d.register(p)
+	d.dockRoot(newGroup(p), edge)
+	return d
+}
+
+// DockPanel docks p relative to the group containing target. It does
+// nothing if target is not docked.
+func (d *DockSpace) DockPanel(p, target *Panel, edge DockEdge) {
+	if p == target {
+		return
+	}
+	d.remove(p)
+	d.register(p)
+	g, _ := d.groupOf(target)
+	if g == nil {
+		d.dockRoot(newGroup(p), edge)
+		return
+	}
+	d.dockInto(g, p, edge)
+}
+
+func (d *DockSpace) dockInto(g *dockNode, p *Panel, edge DockEdge) {
+	if edge == DockCenter {
+		d.addToGroup(g, p)
+		return
+	}
+	d.insertBeside(g, newGroup(p), edge, 0.5)
+}
+
+// remove takes p out of the tree or the floating list.
+func (d *DockSpace) remove(p *Panel) {
+	d.detach(p)
+	d.floating = slices.DeleteFunc(d.floating, func(f floatingPanel) bool { return f.panel == p })
+}
+
+// ClosePanel removes p from the space. The panel stays known by ID so that
+// a later Restore can bring it back.
+func (d *DockSpace) ClosePanel(p *Panel) {
+	d.remove(p)
+}
+
+// FloatPanel undocks p and hands it to the OnFloat handler with bounds.
+func (d *DockSpace) FloatPanel(p *Panel, bounds core.Rect) {
+	d.remove(p)
+	d.register(p)
+	d.floating = append(d.floating, floatingPanel{panel: p, bounds: bounds})
+	if d.onFloat != nil {
+		d.onFloat(p, bounds)
+	}
+}
+
+// IsFloating reports whether p is floating.
+func (d *DockSpace) IsFloating(p *Panel) bool {
+	return slices.ContainsFunc(d.floating, func(f floatingPanel) bool { return f.panel == p })
+}
+
+// SetFloatingBounds records the current rectangle of a floating panel's
+// window so that Save captures it.
+func (d *DockSpace) SetFloatingBounds(p *Panel, bounds core.Rect) {
+	for i := range d.floating {
+		if d.floating[i].panel == p {
+			d.floating[i].bounds = bounds
+		}
+	}
+}
+
+// Activate brings p to the front of its tab group.
+func (d *DockSpace) Activate(p *Panel) {
+	if g, i := d.groupOf(p); g != nil {
+		g.active = i
+	}
+}
+
+// Panel returns the known panel with the given ID, or nil.
+func (d *DockSpace) Panel(id string) *Panel {
+	return d.known[id]
+}
+
+// Panels returns the docked panels in layout order.
+func (d *DockSpace) Panels() []*Panel {
+	var out []*Panel
+	walkGroups(d.root, func(g *dockNode) {
+		out = append(out, g.panels...)
+	})
+	return out
+}
+
+// Floating returns the floating panels.
+func (d *DockSpace) Floating() []*Panel {
+	out := make([]*Panel, len(d.floating))
+	for i, f := range d.floating {
+		out[i] = f.panel
+	}
+	return out
+}
+
+func (d *DockSpace) tabWidth(p *Panel) float32 {
+	w := d.measurer(p) + 2*dockTabPadding
+	if p.Closable {
+		w += dockCloseSize + 4
+	}
+	return min(w, 200)
+}
+
+func (d *DockSpace) layoutTree(ctx *core.Context, r core.Rect) {
+	style := theme.From(ctx).Typography.Label
+	d.measurer = func(p *Panel) float32 { return ctx.MeasureText(p.Title, style).Width }
+	d.dividers = d.dividers[:0]
+	if d.root != nil {
+		d.arrange(d.root, r)
+	}
+}
+
+// Layout implements core.Widget.
+func (d *DockSpace) Layout(ctx *core.LayoutContext) core.Size {
+	c := ctx.Constraints
+	size := core.Size{Width: 800, Height: 600}
+	if c.HasBoundedWidth() {
+		size.Width = c.MaxWidth
+	}
+	if c.HasBoundedHeight() {
+		size.Height = c.MaxHeight
+	}
+	size = c.Constrain(size)
+	d.layoutTree(ctx.Context, core.Rect{Width: size.Width, Height: size.Height})
+	var children []core.Widget
+	walkGroups(d.root, func(g *dockNode) {
+		if p := g.activePanel(); p != nil && p.Content != nil {
+			ctx.Measure(p.Content, core.Tight(g.content.Size()))
+			children = append(children, p.Content)
+		}
+	})
+	d.SetChildren(children...)
+	return size
+}
+
+// SetBounds implements core.Widget.
+func (d *DockSpace) SetBounds(r core.Rect) {
+	d.WidgetBase.SetBounds(r)
+	d.dividers = d.dividers[:0]
+	if d.root == nil || d.measurer == nil {
+		return
+	}
+	d.arrange(d.root, r)
+	walkGroups(d.root, func(g *dockNode) {
+		if p := g.activePanel(); p != nil && p.Content != nil {
+			p.Content.SetBounds(g.content)
+		}
+	})
+}
+
+// Paint implements core.Widget.
+func (d *DockSpace) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	cv.Save()
+	cv.Clip(d.Bounds())
+	cv.DrawRect(d.Bounds(), core.Filled(th.Colors.Background))
+	walkGroups(d.root, func(g *dockNode) {
+		d.paintGroup(ctx, g)
+	})
+	for i, div := range d.dividers {
+		c := th.Colors.Outline.WithAlpha(0.4)
+		if i == d.divDrag {
+			c = th.Colors.Primary
+		}
+		cv.DrawRect(div.rect, core.Filled(c))
+	}
+	if d.dragging {
+		if r, ok := d.dropHighlight(); ok {
+			cv.DrawRect(r, core.RectStyle{Fill: th.Colors.Primary.WithAlpha(0.2), Stroke: th.Colors.Primary, StrokeWidth: 1})
+		}
+		ghost := core.R(d.dragPos.X-40, d.dragPos.Y-d.stripHeight/2, 80, d.stripHeight)
+		cv.DrawRoundedRect(ghost, th.Radii.Small, core.Filled(th.Colors.Surface.WithAlpha(0.9)))
+		label := theme.TextStyle(th.Typography.Label, th.Colors.OnSurface)
+		size := ctx.MeasureText(d.press.Title, label)
+		cv.DrawText(d.press.Title, core.Pt(ghost.X+dockTabPadding, ghost.Y+(ghost.Height-size.Height)/2), label)
+	}
+	cv.Restore()
+}
+
+func (d *DockSpace) paintGroup(ctx *core.PaintContext, g *dockNode) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	cv.DrawRect(g.bounds, core.Filled(th.Colors.Surface))
+	cv.DrawRect(g.strip, core.Filled(th.Colors.SurfaceVariant))
+	cv.Save()
+	cv.Clip(g.strip)
+	for i, p := range g.panels {
+		r := g.tabs[i]
+		color := th.Colors.OnSurfaceVariant
+		if i == g.active {
+			cv.DrawRect(r, core.Filled(th.Colors.Surface))
+			cv.DrawRect(core.R(r.X, r.Bottom()-2, r.Width, 2), core.Filled(th.Colors.Primary))
+			color = th.Colors.OnSurface
+		} else if p == d.hoverTab {
+			cv.DrawRect(r, core.Filled(th.Colors.OnSurface.WithAlpha(0.05)))
+		}
+		style := theme.TextStyle(th.Typography.Label, color)
+		size := ctx.MeasureText(p.Title, style)
+		cv.DrawText(p.Title, core.Pt(r.X+dockTabPadding, r.Y+(r.Height-size.Height)/2), style)
+		if p.Closable {
+			c := dockCloseRect(r).Center()
+			const s = 3
+			x := core.NewPath().
+				MoveTo(core.Pt(c.X-s, c.Y-s)).LineTo(core.Pt(c.X+s, c.Y+s)).
+				MoveTo(core.Pt(c.X+s, c.Y-s)).LineTo(core.Pt(c.X-s, c.Y+s))
+			cv.DrawPath(x, core.PathStyle{Stroke: color, StrokeWidth: 1.25, LineCap: core.CapRound})
+		}
+	}
+	cv.Restore()
+	if p := g.activePanel(); p != nil && p.Content != nil {
+		cv.Save()
+		cv.Clip(g.content)
+		p.Content.Paint(ctx)
+		cv.Restore()
+	}
+}
+
+func dockCloseRect(tab core.Rect) core.Rect {
+	return core.R(tab.Right()-dockTabPadding/2-dockCloseSize, tab.Y+(tab.Height-dockCloseSize)/2, dockCloseSize, dockCloseSize)
+}
+
+// tabAt returns the group and tab index under p, with index -1 for a point
+// on a strip but not on a tab.
+func (d *DockSpace) tabAt(p core.Point) (*dockNode, int) {
+	var group *dockNode
+	idx := -1
+	walkGroups(d.root, func(g *dockNode) {
+		if group != nil || !g.strip.Contains(p) {
+			return
+		}
+		group = g
+		for i, r := range g.tabs {
+			if r.Contains(p) {
+				idx = i
+			}
+		}
+	})
+	return group, idx
+}
+
+func (d *DockSpace) groupAt(p core.Point) *dockNode {
+	var found *dockNode
+	walkGroups(d.root, func(g *dockNode) {
+		if found == nil && g.bounds.Contains(p) {
+			found = g
+		}
+	})
+	return found
+}
+
+func (d *DockSpace) dividerAt(p core.Point) int {
+	for i, div := range d.dividers {
+		// Dividers are thin; accept a slightly larger grab area.
+		if div.rect.Inset(core.UniformInsets(-2)).Contains(p) {
+			return i
+		}
+	}
+	return -1
+}
+
+// updateDrop works out where the dragged tab would land at p.
+func (d *DockSpace) updateDrop(p core.Point) {
+	d.dropGroup, d.dropRoot, d.dropFloat = nil, false, false
+	b := d.Bounds()
+	if !b.Contains(p) {
+		d.dropFloat = d.onFloat != nil
+		return
+	}
+	if edge, ok := nearEdge(b, p, dockRootEdge); ok && d.root != nil && !d.root.isGroup() {
+		d.dropRoot, d.dropEdge = true, edge
+		return
+	}
+	g := d.groupAt(p)
+	if g == nil {
+		d.dropRoot, d.dropEdge = true, DockCenter
+		return
+	}
+	d.dropGroup, d.dropEdge = g, DockCenter
+	if g.strip.Contains(p) {
+		return
+	}
+	r := g.content
+	if edge, ok := nearEdge(r, p, min(r.Width, r.Height)/4); ok {
+		d.dropEdge = edge
+	}
+}
+
+// nearEdge returns the edge of r closest to p if it is within margin.
+func nearEdge(r core.Rect, p core.Point, margin float32) (DockEdge, bool) {
+	dists := [...]float32{
+		DockLeft:   p.X - r.X,
+		DockRight:  r.Right() - p.X,
+		DockTop:    p.Y - r.Y,
+		DockBottom: r.Bottom() - p.Y,
+	}
+	best, bestDist := DockCenter, margin
+	for e := DockLeft; e <= DockBottom; e++ {
+		if dists[e] < bestDist {
+			best, bestDist = e, dists[e]
+		}
+	}
+	return best, best != DockCenter
+}
+
+func edgeRect(r core.Rect, edge DockEdge, fraction float32) core.Rect {
+	switch edge {
+	case DockLeft:
+		r.Width *= fraction
+	case DockRight:
+		r.X += r.Width * (1 - fraction)
+		r.Width *= fraction
+	case DockTop:
+		r.Height *= fraction
+	case DockBottom:
+		r.Y += r.Height * (1 - fraction)
+		r.Height *= fraction
+	}
+	return r
+}
+
+func (d *DockSpace) dropHighlight() (core.Rect, bool) {
+	switch {
+	case d.dropRoot:
+		return edgeRect(d.Bounds(), d.dropEdge, 0.25), true
+	case d.dropGroup != nil && d.dropEdge == DockCenter:
+		return d.dropGroup.bounds, true
+	case d.dropGroup != nil:
+		return edgeRect(d.dropGroup.bounds, d.dropEdge, 0.5), true
+	}
+	return core.Rect{}, false
+}
+
+// drop applies the pending tab drop.
+func (d *DockSpace) drop() bool {
+	p := d.press
+	src, _ := d.groupOf(p)
+	switch {
+	case d.dropFloat:
+		size := core.Sz(400, 300)
+		if src != nil {
+			size = src.bounds.Size()
+		}
+		d.FloatPanel(p, core.R(d.dragPos.X-40, d.dragPos.Y-d.stripHeight/2, size.Width, size.Height))
+	case d.dropRoot:
+		d.AddPanel(p, d.dropEdge)
+	case d.dropGroup != nil:
+		g := d.dropGroup
+		if g == src && (d.dropEdge == DockCenter || len(g.panels) == 1) {
+			return false
+		}
+		d.detach(p)
+		d.dockInto(g, p, d.dropEdge)
+	default:
+		return false
+	}
+	return true
+}
+
+// HandleEvent implements core.Widget.
+func (d *DockSpace) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	e, ok := ev.(event.MouseEvent)
+	if !ok {
+		return core.Ignored
+	}
+	switch e.Type {
+	case event.MouseMove:
+		return d.mouseMove(ctx, e)
+	case event.MouseLeave:
+		if d.hoverTab != nil {
+			d.hoverTab = nil
+			ctx.Repaint(d)
+		}
+	case event.MouseDown:
+		return d.mouseDown(ctx, e)
+	case event.MouseUp:
+		return d.mouseUp(ctx, e)
+	}
+	return core.Ignored
+}
+
+func (d *DockSpace) mouseMove(ctx *core.Context, e event.MouseEvent) core.EventResult {
+	switch {
+	case d.divDrag >= 0:
+		div := d.dividers[d.divDrag]
+		pos := e.Position.X
+		if div.split.axis == Vertical {
+			pos = e.Position.Y
+		}
+		d.moveDivider(div, pos-d.dividerSize/2)
+		ctx.MarkNeedsLayout(d)
+		return core.Handled
+	case d.press != nil:
+		if !d.dragging && abs32(e.Position.X-d.pressPos.X) < dockDragThreshold && abs32(e.Position.Y-d.pressPos.Y) < dockDragThreshold {
+			return core.Handled
+		}
+		d.dragging, d.dragPos = true, e.Position
+		d.updateDrop(e.Position)
+		ctx.Repaint(d)
+		return core.Handled
+	}
+	var hover *Panel
+	if g, i := d.tabAt(e.Position); g != nil && i >= 0 {
+		hover = g.panels[i]
+	}
+	if hover != d.hoverTab {
+		d.hoverTab = hover
+		ctx.Repaint(d)
+	}
+	return core.Ignored
+}
+
+func (d *DockSpace) mouseDown(ctx *core.Context, e event.MouseEvent) core.EventResult {
+	if e.Button != event.ButtonLeft && e.Button != event.ButtonMiddle {
+		return core.Ignored
+	}
+	if i := d.dividerAt(e.Position); i >= 0 && e.Button == event.ButtonLeft {
+		d.divDrag = i
+		ctx.CapturePointer(d)
+		ctx.Repaint(d)
+		return core.Handled
+	}
+	g, i := d.tabAt(e.Position)
+	if g == nil {
+		return core.Ignored
+	}
+	if i < 0 {
+		return core.Handled
+	}
+	p := g.panels[i]
+	if p.Closable && (e.Button == event.ButtonMiddle || dockCloseRect(g.tabs[i]).Contains(e.Position)) {
+		if d.onClose == nil || d.onClose(p) {
+			d.ClosePanel(p)
+			d.hoverTab = nil
+			d.changed()
+			ctx.MarkNeedsLayout(d)
+		}
+		return core.Handled
+	}
+	if e.Button != event.ButtonLeft {
+		return core.Handled
+	}
+	g.active = i
+	d.press, d.pressPos = p, e.Position
+	ctx.CapturePointer(d)
+	ctx.MarkNeedsLayout(d)
+	return core.Handled
+}
+
+func (d *DockSpace) mouseUp(ctx *core.Context, e event.MouseEvent) core.EventResult {
+	switch {
+	case d.divDrag >= 0:
+		d.divDrag = -1
+		d.changed()
+	case d.press != nil:
+		if d.dragging {
+			d.dragPos = e.Position
+			d.updateDrop(e.Position)
+			if d.drop() {
+				d.changed()
+			}
+		}
+		d.press, d.dragging = nil, false
+		d.dropGroup, d.dropRoot, d.dropFloat = nil, false, false
+	default:
+		return core.Ignored
+	}
+	ctx.ReleasePointer()
+	ctx.MarkNeedsLayout(d)
+	return core.Handled
+}
+
+func abs32(v float32) float32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
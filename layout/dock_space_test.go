@@ -0,0 +1,234 @@
+package layout
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+)
+
+// shape describes the tree under n: groups as their panel IDs in
+// brackets, splits as H or V and their children.
+func shape(n *dockNode) string {
+	if n == nil {
+		return ""
+	}
+	if n.isGroup() {
+		var ids []string
+		for _, p := range n.panels {
+			ids = append(ids, p.ID)
+		}
+		return "[" + strings.Join(ids, " ") + "]"
+	}
+	var kids []string
+	for _, c := range n.children {
+		kids = append(kids, shape(c))
+	}
+	return n.axis.String()[:1] + "(" + strings.Join(kids, ",") + ")"
+}
+
+// panels returns panels with the IDs a, b, c and so on.
+func panels(n int) []*Panel {
+	out := make([]*Panel, n)
+	for i := range out {
+		id := string(rune('a' + i))
+		out[i] = &Panel{ID: id, Title: id, Content: newBox(10, 10), Closable: true}
+	}
+	return out
+}
+
+// layoutAt lays d out to fill bounds.
+func layoutAt(d *DockSpace, bounds core.Rect) *core.Context {
+	ctx := core.NewContext()
+	ctx.LayoutRoot(d, bounds)
+	return ctx
+}
+
+func TestDockSpaceDocking(t *testing.T) {
+	tests := []struct {
+		name string
+		dock func(d *DockSpace, p []*Panel)
+		want string
+	}{
+		{"first panel", func(d *DockSpace, p []*Panel) { d.AddPanel(p[0], DockLeft) }, "[a]"},
+		{"tabs", func(d *DockSpace, p []*Panel) {
+			d.AddPanel(p[0], DockCenter).AddPanel(p[1], DockCenter)
+		}, "[a b]"},
+		{"outer edges", func(d *DockSpace, p []*Panel) {
+			d.AddPanel(p[0], DockCenter).AddPanel(p[1], DockLeft).AddPanel(p[2], DockBottom)
+		}, "v(h([b],[a]),[c])"},
+		{"same axis appends", func(d *DockSpace, p []*Panel) {
+			d.AddPanel(p[0], DockCenter).AddPanel(p[1], DockRight).AddPanel(p[2], DockLeft)
+		}, "h([c],[a],[b])"},
+		{"beside a panel", func(d *DockSpace, p []*Panel) {
+			d.AddPanel(p[0], DockCenter).AddPanel(p[1], DockRight)
+			d.DockPanel(p[2], p[0], DockBottom)
+		}, "h(v([a],[c]),[b])"},
+		{"moved", func(d *DockSpace, p []*Panel) {
+			d.AddPanel(p[0], DockCenter).AddPanel(p[1], DockRight).AddPanel(p[2], DockBottom)
+			d.DockPanel(p[2], p[1], DockCenter)
+		}, "h([a],[b c])"},
+		{"closed", func(d *DockSpace, p []*Panel) {
+			d.AddPanel(p[0], DockCenter).AddPanel(p[1], DockRight)
+			d.DockPanel(p[2], p[1], DockBottom)
+			d.ClosePanel(p[1])
+		}, "h([a],[c])"},
+		{"floated", func(d *DockSpace, p []*Panel) {
+			d.AddPanel(p[0], DockCenter).AddPanel(p[1], DockRight)
+			d.FloatPanel(p[0], core.R(0, 0, 100, 100))
+		}, "[b]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDockSpace()
+			tt.dock(d, panels(3))
+			if got := shape(d.root); got != tt.want {
+				t.Errorf("tree = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDockSpaceArrange(t *testing.T) {
+	p := panels(3)
+	d := NewDockSpace().DividerSize(4).StripHeight(20).MinPaneSize(50)
+	d.AddPanel(p[0], DockCenter)
+	d.DockPanel(p[1], p[0], DockRight)
+	ctx := layoutAt(d, core.R(0, 0, 404, 300))
+	if got, want := p[1].Content.Bounds(), core.R(204, 20, 200, 280); got != want {
+		t.Errorf("right content = %v, want %v", got, want)
+	}
+
+	// Dragging the divider resizes the panes, keeping them MinPaneSize.
+	drag := func(x float32) {
+		at := d.dividers[0].rect.Center()
+		d.HandleEvent(ctx, event.MouseEvent{Type: event.MouseDown, Position: at, Button: event.ButtonLeft})
+		d.HandleEvent(ctx, event.MouseEvent{Type: event.MouseMove, Position: core.Pt(x, 100)})
+		d.HandleEvent(ctx, event.MouseEvent{Type: event.MouseUp, Position: core.Pt(x, 100)})
+		ctx.LayoutRoot(d, core.R(0, 0, 404, 300))
+	}
+	drag(102)
+	if got := p[0].Content.Bounds().Width; got != 100 {
+		t.Errorf("left width = %v after the drag, want 100", got)
+	}
+	drag(0)
+	if got := p[0].Content.Bounds().Width; got != 50 {
+		t.Errorf("left width = %v, want the minimum 50", got)
+	}
+}
+
+func TestDockSpaceTabDrag(t *testing.T) {
+	tests := []struct {
+		name    string
+		to      core.Point
+		want    string
+		floated bool
+	}{
+		{"onto the strip of another group", core.Pt(250, 30), "h([a],[b c])", false},
+		{"to the bottom of another group", core.Pt(300, 260), "h([a],v([b],[c]))", false},
+		{"to the top edge of the space", core.Pt(100, 10), "v([c],h([a],[b]))", false},
+		{"outside the space", core.Pt(500, 100), "h([a],[b])", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := panels(3)
+			d := NewDockSpace().DividerSize(4).StripHeight(40)
+			var floated []*Panel
+			d.OnFloat(func(p *Panel, _ core.Rect) { floated = append(floated, p) })
+			changes := 0
+			d.OnLayoutChange(func() { changes++ })
+			d.AddPanel(p[0], DockCenter).AddPanel(p[2], DockCenter)
+			d.DockPanel(p[1], p[0], DockRight)
+			ctx := layoutAt(d, core.R(0, 0, 404, 300))
+
+			// Drag the tab of c, the second of the left group.
+			from := d.root.children[0].tabs[1].Center()
+			d.HandleEvent(ctx, event.MouseEvent{Type: event.MouseDown, Position: from, Button: event.ButtonLeft})
+			d.HandleEvent(ctx, event.MouseEvent{Type: event.MouseMove, Position: tt.to})
+			d.HandleEvent(ctx, event.MouseEvent{Type: event.MouseUp, Position: tt.to})
+			if got := shape(d.root); got != tt.want {
+				t.Errorf("tree = %s, want %s", got, tt.want)
+			}
+			if got := len(floated) == 1 && d.IsFloating(p[2]); got != tt.floated {
+				t.Errorf("floated = %v, want %v", got, tt.floated)
+			}
+			if changes != 1 {
+				t.Errorf("%d layout changes, want 1", changes)
+			}
+		})
+	}
+}
+
+func TestDockSpaceCloseTab(t *testing.T) {
+	p := panels(2)
+	d := NewDockSpace()
+	d.OnClose(func(p *Panel) bool { return p.ID != "a" })
+	d.AddPanel(p[0], DockCenter).AddPanel(p[1], DockCenter)
+	ctx := layoutAt(d, core.R(0, 0, 400, 300))
+	for _, tab := range slices.Clone(d.root.tabs) {
+		d.HandleEvent(ctx, event.MouseEvent{Type: event.MouseDown, Position: tab.Center(), Button: event.ButtonMiddle})
+		ctx.LayoutRoot(d, core.R(0, 0, 400, 300))
+	}
+	if got := shape(d.root); got != "[a]" {
+		t.Errorf("tree = %s, want [a]", got)
+	}
+	if d.Panel("b") != p[1] {
+		t.Error("a closed panel is no longer known by its ID")
+	}
+}
+
+func TestDockSpaceSaveRestore(t *testing.T) {
+	p := panels(4)
+	d := NewDockSpace()
+	d.AddPanel(p[0], DockCenter).AddPanel(p[1], DockCenter).AddPanel(p[2], DockBottom)
+	d.Activate(p[0])
+	d.FloatPanel(p[3], core.R(10, 20, 300, 200))
+	data, err := d.Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Restored into a space that knows none of the panels, with d lost.
+	fresh := panels(4)
+	r := NewDockSpace()
+	var floated []core.Rect
+	r.OnFloat(func(_ *Panel, b core.Rect) { floated = append(floated, b) })
+	err = r.Restore(data, func(id string) *Panel {
+		if id == "b" {
+			return nil
+		}
+		return fresh[id[0]-'a']
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := shape(r.root), "v([a],[c])"; got != want {
+		t.Errorf("tree = %s, want %s", got, want)
+	}
+	if len(floated) != 1 || floated[0] != core.R(10, 20, 300, 200) || !r.IsFloating(fresh[3]) {
+		t.Errorf("floated %v", floated)
+	}
+}
+
+func TestDockSpaceRestoreErrors(t *testing.T) {
+	tests := []struct {
+		name, data string
+	}{
+		{"not JSON", "{"},
+		{"unknown split", `{"root":{"split":"diagonal","children":[{"panels":["a"]}],"ratios":[1]}}`},
+		{"ratios", `{"root":{"split":"horizontal","children":[{"panels":["a"]},{"panels":["b"]}],"ratios":[1]}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDockSpace().AddPanel(panels(1)[0], DockCenter)
+			if err := d.Restore([]byte(tt.data), nil); err == nil {
+				t.Error("Restore() = nil")
+			}
+			if got := shape(d.root); got != "[a]" {
+				t.Errorf("a failed Restore changed the tree to %s", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package layout
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gogpu/ui/core"
+)
+
+// dockState is the JSON form of a DockSpace arrangement.
+type dockState struct {
+	Root     *dockNodeState      `json:"root,omitempty"`
+	Floating []dockFloatingState `json:"floating,omitempty"`
+}
+
+type dockNodeState struct {
+	// Split is "horizontal" or "vertical" for split nodes and empty for
+	// tab groups.
+	Split    string           `json:"split,omitempty"`
+	Ratios   []float32        `json:"ratios,omitempty"`
+	Children []*dockNodeState `json:"children,omitempty"`
+	Panels   []string         `json:"panels,omitempty"`
+	Active   int              `json:"active,omitempty"`
+}
+
+type dockFloatingState struct {
+	Panel  string     `json:"panel"`
+	Bounds [4]float32 `json:"bounds"`
+}
+
+// Save returns the arrangement as JSON. Panels are recorded by ID; panels
+// without an ID are omitted.
+func (d *DockSpace) Save() ([]byte, error) {
+	st := dockState{Root: saveNode(d.root)}
+	for _, f := range d.floating {
+		if f.panel.ID == "" {
+			continue
+		}
+		b := f.bounds
+		st.Floating = append(st.Floating, dockFloatingState{Panel: f.panel.ID, Bounds: [4]float32{b.X, b.Y, b.Width, b.Height}})
+	}
+	return json.Marshal(st)
+}
+
+func saveNode(n *dockNode) *dockNodeState {
+	if n == nil {
+		return nil
+	}
+	if n.isGroup() {
+		s := &dockNodeState{Active: n.active}
+		for _, p := range n.panels {
+			if p.ID != "" {
+				s.Panels = append(s.Panels, p.ID)
+			}
+		}
+		return s
+	}
+	s := &dockNodeState{Split: n.axis.String(), Ratios: append([]float32(nil), n.ratios...)}
+	for _, c := range n.children {
+		s.Children = append(s.Children, saveNode(c))
+	}
+	return s
+}
+
+// Restore replaces the arrangement with one produced by Save. Panel IDs are
+// resolved against panels previously added to the space and then, for
+// unknown IDs, against resolve, which may be nil. IDs that resolve to no
+// panel are dropped. Floating panels are handed to the OnFloat handler.
+func (d *DockSpace) Restore(data []byte, resolve func(id string) *Panel) error {
+	var st dockState
+	if err := json.Unmarshal(data, &st); err != nil {
+		return fmt.Errorf("layout: restore dock layout: %w", err)
+	}
+	lookup := func(id string) *Panel {
+		if p := d.known[id]; p != nil {
+			return p
+		}
+		if resolve == nil {
+			return nil
+		}
+		p := resolve(id)
+		if p != nil {
+			d.register(p)
+		}
+		return p
+	}
+	root, err := restoreNode(st.Root, lookup, make(map[string]bool))
+	if err != nil {
+		return err
+	}
+	d.root = root
+	if root != nil {
+		root.parent = nil
+	}
+	d.floating = nil
+	d.dividers = d.dividers[:0]
+	for _, f := range st.Floating {
+		if p := lookup(f.Panel); p != nil {
+			if g, _ := d.groupOf(p); g == nil {
+				d.FloatPanel(p, core.R(f.Bounds[0], f.Bounds[1], f.Bounds[2], f.Bounds[3]))
+			}
+		}
+	}
+	return nil
+}
+
+func restoreNode(s *dockNodeState, lookup func(string) *Panel, seen map[string]bool) (*dockNode, error) {
+	if s == nil {
+		return nil, nil
+	}
+	switch s.Split {
+	case "":
+		g := newGroup()
+		for _, id := range s.Panels {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			if p := lookup(id); p != nil {
+				g.panels = append(g.panels, p)
+			}
+		}
+		if len(g.panels) == 0 {
+			return nil, nil
+		}
+		g.active = min(max(s.Active, 0), len(g.panels)-1)
+		return g, nil
+	case "horizontal", "vertical":
+	default:
+		return nil, fmt.Errorf("layout: restore dock layout: unknown split %q", s.Split)
+	}
+	if len(s.Ratios) != len(s.Children) {
+		return nil, fmt.Errorf("layout: restore dock layout: %d ratios for %d children", len(s.Ratios), len(s.Children))
+	}
+	n := &dockNode{axis: Horizontal}
+	if s.Split == "vertical" {
+		n.axis = Vertical
+	}
+	var total float32
+	for i, cs := range s.Children {
+		c, err := restoreNode(cs, lookup, seen)
+		if err != nil {
+			return nil, err
+		}
+		if c == nil {
+			continue // Every panel of the pane is gone.
+		}
+		r := max(s.Ratios[i], 0)
+		c.parent = n
+		n.children = append(n.children, c)
+		n.ratios = append(n.ratios, r)
+		total += r
+	}
+	switch len(n.children) {
+	case 0:
+		return nil, nil
+	case 1:
+		return n.children[0], nil
+	}
+	for i := range n.ratios {
+		if total > 0 {
+			n.ratios[i] /= total
+		} else {
+			n.ratios[i] = 1 / float32(len(n.ratios))
+		}
+	}
+	return n, nil
+}
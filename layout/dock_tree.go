@@ -0,0 +1,304 @@
+package layout
+
+import (
+	"slices"
+
+	"github.com/gogpu/ui/core"
+)
+
+// DockEdge is where a panel is docked relative to a target.
+type DockEdge uint8
+
+const (
+	// DockCenter adds the panel as a tab of the target group.
+	DockCenter DockEdge = iota
+	// DockLeft splits the target and places the panel on its left.
+	DockLeft
+	// DockRight splits the target and places the panel on its right.
+	DockRight
+	// DockTop splits the target and places the panel above it.
+	DockTop
+	// DockBottom splits the target and places the panel below it.
+	DockBottom
+)
+
+func (e DockEdge) axis() Axis {
+	if e == DockTop || e == DockBottom {
+		return Vertical
+	}
+	return Horizontal
+}
+
+func (e DockEdge) before() bool {
+	return e == DockLeft || e == DockTop
+}
+
+// dockNode is a node of the dock layout tree. A node with children is a
+// split; a node without children is a tab group holding panels.
+type dockNode struct {
+	parent *dockNode
+
+	axis     Axis
+	children []*dockNode
+	ratios   []float32
+
+	panels []*Panel
+	active int
+
+	bounds  core.Rect
+	strip   core.Rect
+	content core.Rect
+	tabs    []core.Rect
+}
+
+func newGroup(panels ...*Panel) *dockNode {
+	return &dockNode{panels: panels}
+}
+
+func (n *dockNode) isGroup() bool {
+	return len(n.children) == 0
+}
+
+func (n *dockNode) activePanel() *Panel {
+	if n.active >= 0 && n.active < len(n.panels) {
+		return n.panels[n.active]
+	}
+	return nil
+}
+
+// walkGroups calls fn for every tab group under n in depth-first order.
+func walkGroups(n *dockNode, fn func(g *dockNode)) {
+	if n == nil {
+		return
+	}
+	if n.isGroup() {
+		fn(n)
+		return
+	}
+	for _, c := range n.children {
+		walkGroups(c, fn)
+	}
+}
+
+// replaceNode puts repl where old is in the tree, updating root if needed.
+func (d *DockSpace) replaceNode(old, repl *dockNode) {
+	p := old.parent
+	if repl != nil {
+		repl.parent = p
+	}
+	if p == nil {
+		d.root = repl
+		return
+	}
+	i := slices.Index(p.children, old)
+	p.children[i] = repl
+}
+
+// insertBeside docks g next to target on edge, giving it fraction of the
+// target's space.
+func (d *DockSpace) insertBeside(target, g *dockNode, edge DockEdge, fraction float32) {
+	axis := edge.axis()
+	if p := target.parent; p != nil && p.axis == axis {
+		// Share the target's slot in an existing split along the same axis.
+		i := slices.Index(p.children, target)
+		share := p.ratios[i] * fraction
+		p.ratios[i] -= share
+		at := i
+		if !edge.before() {
+			at = i + 1
+		}
+		p.children = slices.Insert(p.children, at, g)
+		p.ratios = slices.Insert(p.ratios, at, share)
+		g.parent = p
+		return
+	}
+	split := &dockNode{axis: axis}
+	d.replaceNode(target, split)
+	if edge.before() {
+		split.children = []*dockNode{g, target}
+		split.ratios = []float32{fraction, 1 - fraction}
+	} else {
+		split.children = []*dockNode{target, g}
+		split.ratios = []float32{1 - fraction, fraction}
+	}
+	target.parent, g.parent = split, split
+}
+
+// dockRoot docks g along an outer edge of the whole space.
+func (d *DockSpace) dockRoot(g *dockNode, edge DockEdge) {
+	switch {
+	case d.root == nil:
+		d.root = g
+		g.parent = nil
+	case edge == DockCenter:
+		d.addToGroup(d.firstGroup(), g.panels...)
+	case !d.root.isGroup() && d.root.axis == edge.axis():
+		// Append to the root split, scaling the other panes down.
+		const share = 0.25
+		for i := range d.root.ratios {
+			d.root.ratios[i] *= 1 - share
+		}
+		if edge.before() {
+			d.root.children = slices.Insert(d.root.children, 0, g)
+			d.root.ratios = slices.Insert(d.root.ratios, 0, share)
+		} else {
+			d.root.children = append(d.root.children, g)
+			d.root.ratios = append(d.root.ratios, share)
+		}
+		g.parent = d.root
+	default:
+		d.insertBeside(d.root, g, edge, 0.25)
+	}
+}
+
+func (d *DockSpace) firstGroup() *dockNode {
+	var first *dockNode
+	walkGroups(d.root, func(g *dockNode) {
+		if first == nil {
+			first = g
+		}
+	})
+	return first
+}
+
+func (d *DockSpace) addToGroup(g *dockNode, panels ...*Panel) {
+	g.panels = append(g.panels, panels...)
+	g.active = len(g.panels) - 1
+}
+
+// groupOf returns the group holding p and its index there.
+func (d *DockSpace) groupOf(p *Panel) (*dockNode, int) {
+	var found *dockNode
+	idx := -1
+	walkGroups(d.root, func(g *dockNode) {
+		if i := slices.Index(g.panels, p); i >= 0 && found == nil {
+			found, idx = g, i
+		}
+	})
+	return found, idx
+}
+
+// detach removes p from the tree, collapsing empty groups and splits left
+// with a single child.
+func (d *DockSpace) detach(p *Panel) {
+	g, i := d.groupOf(p)
+	if g == nil {
+		return
+	}
+	g.panels = slices.Delete(g.panels, i, i+1)
+	if g.active >= len(g.panels) || g.active > i {
+		g.active = max(0, g.active-1)
+	}
+	if len(g.panels) == 0 {
+		d.prune(g)
+	}
+}
+
+func (d *DockSpace) prune(g *dockNode) {
+	p := g.parent
+	if p == nil {
+		d.root = nil
+		return
+	}
+	i := slices.Index(p.children, g)
+	removed := p.ratios[i]
+	p.children = slices.Delete(p.children, i, i+1)
+	p.ratios = slices.Delete(p.ratios, i, i+1)
+	// Give the freed space to the remaining panes proportionally.
+	if rest := 1 - removed; rest > 0 {
+		for j := range p.ratios {
+			p.ratios[j] /= rest
+		}
+	}
+	if len(p.children) == 1 {
+		only := p.children[0]
+		d.replaceNode(p, only)
+		// A split that ends up inside a split along the same axis is
+		// flattened into it.
+		if pp := only.parent; pp != nil && !only.isGroup() && pp.axis == only.axis {
+			j := slices.Index(pp.children, only)
+			share := pp.ratios[j]
+			kids := only.children
+			ratios := make([]float32, len(only.ratios))
+			for k, r := range only.ratios {
+				ratios[k] = r * share
+			}
+			for _, k := range kids {
+				k.parent = pp
+			}
+			pp.children = slices.Replace(pp.children, j, j+1, kids...)
+			pp.ratios = slices.Replace(pp.ratios, j, j+1, ratios...)
+		}
+	}
+}
+
+type dockDivider struct {
+	split *dockNode
+	index int // Divider between children index and index+1.
+	rect  core.Rect
+}
+
+// arrange computes the geometry of n and its descendants within r.
+func (d *DockSpace) arrange(n *dockNode, r core.Rect) {
+	n.bounds = r
+	if n.isGroup() {
+		n.strip = core.R(r.X, r.Y, r.Width, min(d.stripHeight, r.Height))
+		n.content = core.R(r.X, n.strip.Bottom(), r.Width, max(0, r.Height-n.strip.Height))
+		n.tabs = n.tabs[:0]
+		x := r.X
+		for _, p := range n.panels {
+			w := d.tabWidth(p)
+			n.tabs = append(n.tabs, core.R(x, r.Y, w, n.strip.Height))
+			x += w
+		}
+		return
+	}
+	extent := r.Width
+	if n.axis == Vertical {
+		extent = r.Height
+	}
+	avail := max(0, extent-float32(len(n.children)-1)*d.dividerSize)
+	pos := float32(0)
+	for i, c := range n.children {
+		size := avail * n.ratios[i]
+		if i == len(n.children)-1 {
+			size = max(0, extent-pos) // Absorb rounding in the last pane.
+		}
+		var cr, div core.Rect
+		if n.axis == Horizontal {
+			cr = core.R(r.X+pos, r.Y, size, r.Height)
+			div = core.R(cr.Right(), r.Y, d.dividerSize, r.Height)
+		} else {
+			cr = core.R(r.X, r.Y+pos, r.Width, size)
+			div = core.R(r.X, cr.Bottom(), r.Width, d.dividerSize)
+		}
+		d.arrange(c, cr)
+		if i < len(n.children)-1 {
+			d.dividers = append(d.dividers, dockDivider{split: n, index: i, rect: div})
+		}
+		pos += size + d.dividerSize
+	}
+}
+
+// moveDivider places the divider at pos along the split's axis, keeping
+// both neighbouring panes at least minPaneSize.
+func (d *DockSpace) moveDivider(div dockDivider, pos float32) {
+	n := div.split
+	r := n.bounds
+	start, extent := r.X, r.Width
+	if n.axis == Vertical {
+		start, extent = r.Y, r.Height
+	}
+	avail := max(1, extent-float32(len(n.children)-1)*d.dividerSize)
+	i := div.index
+	var before float32
+	for j := range i {
+		before += n.ratios[j]*avail + d.dividerSize
+	}
+	pair := (n.ratios[i] + n.ratios[i+1]) * avail
+	minSize := min(d.minPaneSize, pair/2)
+	first := core.Clamp(pos-start-before, minSize, pair-minSize)
+	sum := n.ratios[i] + n.ratios[i+1]
+	n.ratios[i] = first / avail
+	n.ratios[i+1] = sum - n.ratios[i]
+}
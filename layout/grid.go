@@ -0,0 +1,520 @@
+package layout
+
+import (
+	"slices"
+
+	"github.com/gogpu/ui/core"
+)
+
+type trackKind uint8
+
+const (
+	trackAuto trackKind = iota
+	trackPx
+	trackFr
+	trackMinContent
+	trackMaxContent
+)
+
+// Track sizes a row or column of a Grid.
+type Track struct {
+	kind  trackKind
+	value float32
+}
+
+// Px returns a track of fixed size v.
+func Px(v float32) Track {
+	return Track{kind: trackPx, value: max(0, v)}
+}
+
+// Fr returns a flexible track taking share v of the space left by the
+// other tracks. On an unbounded axis flexible tracks fit their content,
+// keeping their proportions.
+func Fr(v float32) Track {
+	return Track{kind: trackFr, value: max(0, v)}
+}
+
+// MinContent returns a track as small as its content allows, for text the
+// longest word.
+func MinContent() Track {
+	return Track{kind: trackMinContent}
+}
+
+// MaxContent returns a track as large as its content wants, for text the
+// longest line.
+func MaxContent() Track {
+	return Track{kind: trackMaxContent}
+}
+
+// Auto returns a track that fits its content, shrinking down to the
+// minimum content size when space is short. It is the default for the
+// implicit rows auto-placement adds.
+func Auto() Track {
+	return Track{}
+}
+
+func (t Track) intrinsic() bool {
+	return t.kind == trackAuto || t.kind == trackMinContent || t.kind == trackMaxContent
+}
+
+// Cell is the area of a Grid a child occupies: a position and a number of
+// rows and columns spanned. The zero Cell is auto-placed and spans one
+// row and one column.
+type Cell struct {
+	row, col         int
+	rowSpan, colSpan int
+	fixed            bool
+}
+
+// CellAt returns the cell at row and col, counted from zero.
+func CellAt(row, col int) Cell {
+	return Cell{row: max(0, row), col: max(0, col), fixed: true}
+}
+
+// Span returns the cell extended to rows rows and cols columns.
+func (c Cell) Span(rows, cols int) Cell {
+	c.rowSpan, c.colSpan = rows, cols
+	return c
+}
+
+// Spans returns an auto-placed cell spanning rows rows and cols columns.
+func Spans(rows, cols int) Cell {
+	return Cell{}.Span(rows, cols)
+}
+
+type gridItem struct {
+	content core.Widget
+	cell    Cell
+	area    Cell // Resolved position and spans.
+
+	size     core.Size
+	baseline float32 // With AlignBaseline; -1 without text.
+}
+
+// Grid arranges children in rows and columns, each sized by a Track:
+// fixed pixels, a fraction of the free space, or fitted to its content.
+// Children occupy one cell or span several, and children without a cell
+// position are auto-placed into the next free cells, row after row:
+//
+//	g := layout.NewGrid().
+//		Columns(layout.MaxContent(), layout.Fr(1), layout.Fr(2)).
+//		Gap(8, 12)
+//	g.Place(header, layout.CellAt(0, 0).Span(1, 3))
+//	g.Add(nameLabel, nameField, notes)
+//
+// Rows beyond the ones declared are added as needed and sized by
+// AutoRows. Children fill their cell unless RowAlign aligns them in
+// their row. In right-to-left layouts the first column is on the right.
+type Grid struct {
+	core.WidgetBase
+
+	columns  []Track
+	rows     []Track
+	autoRow  Track
+	rowAlign Align
+	dense    bool
+	rowGap   float32
+	colGap   float32
+	items    []gridItem
+	dir      core.Direction
+	colSizes []float32
+	rowSizes []float32
+	rowBases []float32 // With AlignBaseline, from the top of each row.
+}
+
+// NewGrid returns a grid of one auto column.
+func NewGrid() *Grid {
+	return &Grid{rowAlign: AlignStretch}
+}
+
+// Columns sets the column tracks.
+func (g *Grid) Columns(tracks ...Track) *Grid {
+	g.columns = tracks
+	return g
+}
+
+// Rows sets the tracks of the first rows.
+func (g *Grid) Rows(tracks ...Track) *Grid {
+	g.rows = tracks
+	return g
+}
+
+// AutoRows sets the track of the rows added beyond the ones set with Rows,
+// Auto by default.
+func (g *Grid) AutoRows(t Track) *Grid {
+	g.autoRow = t
+	return g
+}
+
+// RowAlign sets how children keeping their height are placed in their
+// row, AlignStretch by default, which makes them fill it. With
+// AlignBaseline the children of a row line up on the baseline of their
+// first line of text, as labels next to fields. Children spanning rows
+// fill their cells.
+func (g *Grid) RowAlign(a Align) *Grid {
+	g.rowAlign = a
+	return g
+}
+
+// Dense sets whether auto-placed children take the first free cells of
+// the grid rather than those after the last child placed, filling the
+// holes children spanning more columns than were left leave behind. The
+// children then no longer appear in the order they were added.
+func (g *Grid) Dense(on bool) *Grid {
+	g.dense = on
+	return g
+}
+
+// Gap sets the space between rows and between columns.
+func (g *Grid) Gap(row, col float32) *Grid {
+	g.rowGap, g.colGap = max(0, row), max(0, col)
+	return g
+}
+
+// Add auto-places children in one cell each.
+func (g *Grid) Add(children ...core.Widget) *Grid {
+	for _, c := range children {
+		g.Place(c, Cell{})
+	}
+	return g
+}
+
+// Place puts child in cell. Placing a child again moves it.
+func (g *Grid) Place(child core.Widget, cell Cell) *Grid {
+	if i := g.index(child); i >= 0 {
+		g.items[i].cell = cell
+		return g
+	}
+	g.items = append(g.items, gridItem{content: child, cell: cell})
+	g.AppendChild(child)
+	return g
+}
+
+// Remove removes child.
+func (g *Grid) Remove(child core.Widget) {
+	if i := g.index(child); i >= 0 {
+		g.items = slices.Delete(g.items, i, i+1)
+		children := make([]core.Widget, len(g.items))
+		for k, it := range g.items {
+			children[k] = it.content
+		}
+		g.SetChildren(children...)
+	}
+}
+
+func (g *Grid) index(child core.Widget) int {
+	return slices.IndexFunc(g.items, func(it gridItem) bool { return it.content == child })
+}
+
+func (g *Grid) columnTracks() []Track {
+	if len(g.columns) == 0 {
+		return []Track{Auto()}
+	}
+	return g.columns
+}
+
+func (g *Grid) rowTrack(i int) Track {
+	if i < len(g.rows) {
+		return g.rows[i]
+	}
+	return g.autoRow
+}
+
+// place resolves the area of every item and returns the number of rows.
+// Children with a position are placed first; the others fill the free
+// cells after the last auto-placed one, as in CSS grid, or the first free
+// cells when dense.
+func (g *Grid) place(cols int) int {
+	var used [][]bool
+	free := func(r, c, rs, cs int) bool {
+		for i := r; i < r+rs && i < len(used); i++ {
+			for k := c; k < c+cs; k++ {
+				if used[i][k] {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	mark := func(a Cell) {
+		for len(used) < a.row+a.rowSpan {
+			used = append(used, make([]bool, cols))
+		}
+		for i := a.row; i < a.row+a.rowSpan; i++ {
+			for k := a.col; k < a.col+a.colSpan; k++ {
+				used[i][k] = true
+			}
+		}
+	}
+	resolve := func(c Cell) Cell {
+		c.rowSpan, c.colSpan = max(c.rowSpan, 1), min(max(c.colSpan, 1), cols)
+		c.col = min(c.col, cols-c.colSpan)
+		return c
+	}
+	for i := range g.items {
+		if it := &g.items[i]; it.cell.fixed {
+			it.area = resolve(it.cell)
+			mark(it.area)
+		}
+	}
+	row, col := 0, 0
+	for i := range g.items {
+		it := &g.items[i]
+		if it.cell.fixed {
+			continue
+		}
+		a := resolve(it.cell)
+		if g.dense {
+			row, col = 0, 0
+		}
+		for {
+			if col+a.colSpan > cols {
+				row, col = row+1, 0
+				continue
+			}
+			if free(row, col, a.rowSpan, a.colSpan) {
+				break
+			}
+			col++
+		}
+		a.row, a.col = row, col
+		it.area = a
+		mark(a)
+		col += a.colSpan
+	}
+	return max(len(used), len(g.rows))
+}
+
+// sizeTracks sizes tracks to avail, or to their content if avail is
+// unbounded. contrib returns the minimum and maximum content size of an
+// item along the axis, start and span its position.
+func sizeTracks(tracks []Track, gap, avail float32, items []gridItem, pos func(Cell) (start, span int), contrib func(it gridItem) (lo, hi float32)) []float32 {
+	n := len(tracks)
+	lo := make([]float32, n) // Minimum content sizes.
+	hi := make([]float32, n) // Maximum content sizes.
+	for i, t := range tracks {
+		if t.kind == trackPx {
+			lo[i], hi[i] = t.value, t.value
+		}
+	}
+	var flexUnit float32
+	// Single-span items first, then spanning ones distribute what the
+	// tracks they span lack over the intrinsic ones.
+	for pass := 0; pass < 2; pass++ {
+		for _, it := range items {
+			start, span := pos(it.area)
+			if (span == 1) != (pass == 0) {
+				continue
+			}
+			clo, chi := contrib(it)
+			if span == 1 {
+				switch t := tracks[start]; {
+				case t.kind == trackFr:
+					if t.value > 0 {
+						flexUnit = max(flexUnit, chi/t.value)
+					}
+				case t.intrinsic():
+					lo[start], hi[start] = max(lo[start], clo), max(hi[start], chi)
+				}
+				continue
+			}
+			var intrinsic []int
+			haveLo, haveHi := gap*float32(span-1), gap*float32(span-1)
+			for k := start; k < start+span; k++ {
+				haveLo += lo[k]
+				haveHi += hi[k]
+				if tracks[k].intrinsic() {
+					intrinsic = append(intrinsic, k)
+				}
+			}
+			for _, k := range intrinsic {
+				lo[k] += max(0, clo-haveLo) / float32(len(intrinsic))
+				hi[k] += max(0, chi-haveHi) / float32(len(intrinsic))
+			}
+		}
+	}
+
+	sizes := make([]float32, n)
+	var fixed, frTotal, shrinkable float32
+	for i, t := range tracks {
+		switch t.kind {
+		case trackFr:
+			frTotal += t.value
+			continue
+		case trackMinContent:
+			sizes[i] = lo[i]
+		case trackAuto:
+			sizes[i] = max(lo[i], hi[i])
+			shrinkable += sizes[i] - lo[i]
+		default:
+			sizes[i] = max(lo[i], hi[i])
+		}
+		fixed += sizes[i]
+	}
+	gaps := gap * float32(max(n-1, 0))
+	if avail >= core.Infinity {
+		for i, t := range tracks {
+			if t.kind == trackFr {
+				sizes[i] = flexUnit * t.value
+			}
+		}
+		return sizes
+	}
+	free := avail - gaps - fixed
+	if free < 0 && shrinkable > 0 {
+		// Shrink auto tracks toward their minimum content size.
+		k := min(-free, shrinkable) / shrinkable
+		for i, t := range tracks {
+			if t.kind == trackAuto {
+				sizes[i] -= (sizes[i] - lo[i]) * k
+			}
+		}
+		free += min(-free, shrinkable)
+	}
+	if frTotal > 0 {
+		for i, t := range tracks {
+			if t.kind == trackFr {
+				sizes[i] = max(0, free) * t.value / frTotal
+			}
+		}
+	}
+	return sizes
+}
+
+// extent returns the size covered by tracks [start, start+span).
+func extent(sizes []float32, gap float32, start, span int) float32 {
+	if span <= 0 {
+		return 0
+	}
+	var s float32
+	for k := start; k < start+span; k++ {
+		s += sizes[k]
+	}
+	return s + gap*float32(span-1)
+}
+
+// Layout implements core.Widget.
+func (g *Grid) Layout(ctx *core.LayoutContext) core.Size {
+	g.dir = ctx.Direction()
+	c := ctx.Constraints
+	cols := g.columnTracks()
+	nrows := g.place(len(cols))
+	rows := make([]Track, nrows)
+	for i := range rows {
+		rows[i] = g.rowTrack(i)
+	}
+
+	availW, availH := core.Infinity, core.Infinity
+	if c.HasBoundedWidth() {
+		availW = c.MaxWidth
+	}
+	if c.HasBoundedHeight() {
+		availH = c.MaxHeight
+	}
+	g.colSizes = sizeTracks(cols, g.colGap, availW, g.items,
+		func(a Cell) (int, int) { return a.col, a.colSpan },
+		func(it gridItem) (float32, float32) { return ctx.IntrinsicWidth(it.content, core.Infinity) })
+	g.rowBases = slices.Grow(g.rowBases[:0], nrows)[:nrows]
+	descents := make([]float32, nrows)
+	for i := range g.items {
+		it := &g.items[i]
+		w := extent(g.colSizes, g.colGap, it.area.col, it.area.colSpan)
+		it.size = ctx.Measure(it.content, core.Constraints{MinWidth: w, MaxWidth: w, MaxHeight: core.Infinity})
+		if !g.baselined(*it) {
+			continue
+		}
+		b, ok := core.BaselineOf(it.content)
+		it.baseline = b
+		if !ok {
+			it.baseline, b = -1, it.size.Height
+		}
+		g.rowBases[it.area.row] = max(g.rowBases[it.area.row], b)
+		descents[it.area.row] = max(descents[it.area.row], it.size.Height-b)
+	}
+	g.rowSizes = sizeTracks(rows, g.rowGap, availH, g.items,
+		func(a Cell) (int, int) { return a.row, a.rowSpan },
+		func(it gridItem) (float32, float32) {
+			if g.baselined(it) {
+				h := g.rowBases[it.area.row] + descents[it.area.row]
+				return h, h
+			}
+			return it.size.Height, it.size.Height
+		})
+	for i := range g.items {
+		it := &g.items[i]
+		a := it.area
+		h := extent(g.rowSizes, g.rowGap, a.row, a.rowSpan)
+		if g.aligned(*it) {
+			h = min(h, it.size.Height)
+		}
+		it.size = ctx.Measure(it.content, core.Tight(core.Sz(
+			extent(g.colSizes, g.colGap, a.col, a.colSpan), h)))
+	}
+	size := core.Sz(extent(g.colSizes, g.colGap, 0, len(cols)), 0)
+	if nrows > 0 {
+		size.Height = extent(g.rowSizes, g.rowGap, 0, nrows)
+	}
+	return c.Constrain(size)
+}
+
+// SetBounds implements core.Widget.
+func (g *Grid) SetBounds(r core.Rect) {
+	g.WidgetBase.SetBounds(r)
+	for _, it := range g.items {
+		a := it.area
+		x := r.X + extent(g.colSizes, g.colGap, 0, a.col)
+		y := r.Y + extent(g.rowSizes, g.rowGap, 0, a.row)
+		if a.col > 0 {
+			x += g.colGap
+		}
+		if a.row > 0 {
+			y += g.rowGap
+		}
+		cr := core.R(x, y+g.offset(it), it.size.Width, it.size.Height)
+		it.content.SetBounds(g.dir.Mirror(cr, r))
+	}
+}
+
+// aligned reports whether it keeps its height in its row rather than
+// filling its cells.
+func (g *Grid) aligned(it gridItem) bool {
+	return g.rowAlign != AlignStretch && it.area.rowSpan == 1
+}
+
+func (g *Grid) baselined(it gridItem) bool {
+	return g.rowAlign == AlignBaseline && it.area.rowSpan == 1
+}
+
+// offset returns the position of it from the top of its row.
+func (g *Grid) offset(it gridItem) float32 {
+	switch {
+	case !g.aligned(it):
+		return 0
+	case g.baselined(it):
+		b := it.baseline
+		if b < 0 {
+			b = it.size.Height
+		}
+		return g.rowBases[it.area.row] - b
+	}
+	return g.rowAlign.offset(g.rowSizes[it.area.row], it.size.Height)
+}
+
+// Baseline implements core.Baseliner with the baseline of the first child
+// with text in the first row.
+func (g *Grid) Baseline() (float32, bool) {
+	for _, it := range g.items {
+		if it.area.row != 0 {
+			continue
+		}
+		if b, ok := core.BaselineOf(it.content); ok {
+			return g.offset(it) + b, true
+		}
+	}
+	return 0, false
+}
+
+// Paint implements core.Widget.
+func (g *Grid) Paint(ctx *core.PaintContext) {
+	core.PaintChildren(ctx, g.Children())
+}
@@ -0,0 +1,214 @@
+package layout
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+)
+
+func TestGridRowAlign(t *testing.T) {
+	tests := []struct {
+		name  string
+		align Align
+		want  []core.Rect // Label, field, icon.
+	}{
+		{"stretch", AlignStretch, []core.Rect{core.R(0, 0, 50, 30), core.R(50, 0, 60, 30), core.R(110, 0, 10, 30)}},
+		{"start", AlignStart, []core.Rect{core.R(0, 0, 50, 12), core.R(50, 0, 60, 30), core.R(110, 0, 10, 10)}},
+		{"center", AlignCenter, []core.Rect{core.R(0, 9, 50, 12), core.R(50, 0, 60, 30), core.R(110, 10, 10, 10)}},
+		{"end", AlignEnd, []core.Rect{core.R(0, 18, 50, 12), core.R(50, 0, 60, 30), core.R(110, 20, 10, 10)}},
+		{"baseline", AlignBaseline, []core.Rect{core.R(0, 11, 50, 12), core.R(50, 0, 60, 30), core.R(110, 10, 10, 10)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kids := []*box{text(50, 12, 9), text(60, 30, 20), newBox(10, 10)}
+			g := NewGrid().Columns(Px(50), Px(60), Px(10)).RowAlign(tt.align)
+			g.Add(kids[0], kids[1], kids[2])
+			layoutIn(g, core.R(0, 0, 200, 200), core.LeftToRight)
+			for i, k := range kids {
+				if got := k.Bounds(); got != tt.want[i] {
+					t.Errorf("child %d: bounds = %v, want %v", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGridBaselineRowHeight(t *testing.T) {
+	// Each row is as tall as its deepest ascent and descent together,
+	// and the grid's baseline is that of its first row.
+	g := NewGrid().Columns(Auto(), Auto()).Gap(4, 0).RowAlign(AlignBaseline)
+	a, b := text(10, 20, 18), text(10, 20, 6)
+	c, d := text(10, 10, 8), newBox(10, 16)
+	g.Add(a, b, c, d)
+	layoutIn(g, core.R(0, 0, 100, 100), core.LeftToRight)
+	want := map[*box]core.Rect{
+		a: core.R(0, 0, 10, 20),
+		b: core.R(10, 12, 10, 20),
+		c: core.R(0, 44, 10, 10),
+		d: core.R(10, 36, 10, 16),
+	}
+	for k, r := range want {
+		if got := k.Bounds(); got != r {
+			t.Errorf("bounds = %v, want %v", got, r)
+		}
+	}
+	if got := g.Bounds().Height; got != 32+4+18 {
+		t.Errorf("height = %v, want %v", got, 32+4+18)
+	}
+	if bl, ok := g.Baseline(); !ok || bl != 18 {
+		t.Errorf("Baseline() = %v, %v, want 18, true", bl, ok)
+	}
+}
+
+func TestGridSpanFillsRows(t *testing.T) {
+	// A child spanning rows fills them whatever the row alignment.
+	g := NewGrid().Columns(Px(20), Px(20)).RowAlign(AlignCenter)
+	tall, a, b := newBox(20, 5), newBox(20, 10), newBox(20, 10)
+	g.Place(tall, CellAt(0, 0).Span(2, 1))
+	g.Add(a, b)
+	layoutIn(g, core.R(0, 0, 100, 100), core.LeftToRight)
+	if got, want := tall.Bounds(), core.R(0, 0, 20, 20); got != want {
+		t.Errorf("spanning child bounds = %v, want %v", got, want)
+	}
+	if got, want := b.Bounds(), core.R(20, 10, 20, 10); got != want {
+		t.Errorf("second row bounds = %v, want %v", got, want)
+	}
+}
+
+func TestGridTracks(t *testing.T) {
+	tests := []struct {
+		name     string
+		columns  []Track
+		children []core.Widget
+		want     []float32
+	}{
+		{"fixed and flexible", []Track{Px(50), Fr(1), Fr(3)},
+			[]core.Widget{newBox(10, 10), newBox(10, 10), newBox(10, 10)}, []float32{50, 32.5, 97.5}},
+		{"auto", []Track{Auto(), Fr(1)},
+			[]core.Widget{newBox(40, 10), newBox(10, 10)}, []float32{40, 150}},
+		{"auto shrinking", []Track{Auto(), Auto()},
+			[]core.Widget{&area{min: 30, max: 150, area: 300}, &area{min: 30, max: 150, area: 300}}, []float32{95, 95}},
+		{"min-content", []Track{MinContent(), Fr(1)},
+			[]core.Widget{&area{min: 30, max: 150, area: 300}, newBox(10, 10)}, []float32{30, 160}},
+		{"max-content", []Track{MaxContent(), Fr(1)},
+			[]core.Widget{&area{min: 30, max: 150, area: 300}, newBox(10, 10)}, []float32{150, 40}},
+		{"max-content overflowing", []Track{MaxContent(), MaxContent()},
+			[]core.Widget{&area{min: 30, max: 150, area: 300}, &area{min: 30, max: 150, area: 300}}, []float32{150, 150}},
+		{"fixed ignoring content", []Track{Px(20), Fr(1)},
+			[]core.Widget{newBox(80, 10), newBox(10, 10)}, []float32{20, 170}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGrid().Columns(tt.columns...).Gap(0, 10)
+			g.Add(tt.children...)
+			layoutIn(g, core.R(0, 0, 200, 200), core.LeftToRight)
+			if !slices.Equal(g.colSizes, tt.want) {
+				t.Fatalf("columns %v, want %v", g.colSizes, tt.want)
+			}
+			var x float32
+			for i, c := range tt.children {
+				if got := c.Bounds(); got.X != x || got.Width != tt.want[i] {
+					t.Errorf("child %d at %v, want at %v, %v wide", i, got, x, tt.want[i])
+				}
+				x += tt.want[i] + 10
+			}
+		})
+	}
+}
+
+func TestGridTracksUnbounded(t *testing.T) {
+	// Without a width to share, flexible columns fit their content in
+	// proportion.
+	g := NewGrid().Columns(Fr(1), Fr(2), Px(15))
+	g.Add(newBox(30, 10), newBox(30, 10), newBox(5, 10))
+	lc := &core.LayoutContext{Context: core.NewContext()}
+	if got := lc.Measure(g, unbounded(100)); got != core.Sz(105, 10) {
+		t.Errorf("size = %v, want the columns' content", got)
+	}
+	if want := []float32{30, 60, 15}; !slices.Equal(g.colSizes, want) {
+		t.Errorf("columns %v, want %v", g.colSizes, want)
+	}
+
+	// Rows past the ones set are sized by AutoRows.
+	g = NewGrid().Columns(Px(10), Px(10)).Rows(Px(20), Fr(1)).AutoRows(Px(15)).Gap(5, 0)
+	for range 6 {
+		g.Add(newBox(10, 10))
+	}
+	layoutIn(g, core.R(0, 0, 200, 200), core.LeftToRight)
+	if want := []float32{20, 155, 15}; !slices.Equal(g.rowSizes, want) {
+		t.Errorf("rows %v, want %v", g.rowSizes, want)
+	}
+}
+
+func TestGridSpanTracks(t *testing.T) {
+	// What a spanning child lacks is shared by the intrinsic tracks it
+	// spans, after children in one track are fitted.
+	tests := []struct {
+		name    string
+		columns []Track
+		first   float32 // Width of a child in the first column, if any.
+		want    []float32
+	}{
+		{"alone", []Track{Auto(), Px(20), Auto()}, 0, []float32{40, 20, 40}},
+		{"after a child", []Track{Auto(), Px(20), Auto()}, 50, []float32{65, 20, 15}},
+		{"wide enough", []Track{Auto(), Px(20), Auto()}, 90, []float32{90, 20, 0}},
+		{"over flexible tracks", []Track{Px(20), Fr(1), Fr(1)}, 0, []float32{20, 90, 90}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGrid().Columns(tt.columns...)
+			wide := newBox(100, 10)
+			g.Place(wide, CellAt(1, 0).Span(1, 3))
+			if tt.first > 0 {
+				g.Place(newBox(tt.first, 10), CellAt(0, 0))
+			}
+			layoutIn(g, core.R(0, 0, 200, 200), core.LeftToRight)
+			if !slices.Equal(g.colSizes, tt.want) {
+				t.Errorf("columns %v, want %v", g.colSizes, tt.want)
+			}
+			if got := wide.Bounds().Width; got != extent(tt.want, 0, 0, 3) {
+				t.Errorf("spanning child %v wide, want across its columns", got)
+			}
+		})
+	}
+}
+
+func TestGridPlacement(t *testing.T) {
+	type placed struct {
+		cell Cell // The zero Cell to add the child.
+		at   [2]int
+	}
+	tests := []struct {
+		name     string
+		dense    bool
+		children []placed
+		rows     int
+	}{
+		{"in order", false, []placed{{Cell{}, [2]int{0, 0}}, {Cell{}, [2]int{0, 1}}, {Cell{}, [2]int{0, 2}}, {Cell{}, [2]int{1, 0}}}, 2},
+		{"around a fixed cell", false, []placed{{Cell{}, [2]int{0, 0}}, {CellAt(0, 1), [2]int{0, 1}}, {Cell{}, [2]int{0, 2}}, {Cell{}, [2]int{1, 0}}}, 2},
+		{"around spanned rows", false, []placed{{Spans(2, 1), [2]int{0, 0}}, {Cell{}, [2]int{0, 1}}, {Cell{}, [2]int{0, 2}}, {Cell{}, [2]int{1, 1}}}, 2},
+		{"a span wrapping", false, []placed{{Cell{}, [2]int{0, 0}}, {Spans(1, 3), [2]int{1, 0}}, {Cell{}, [2]int{2, 0}}}, 3},
+		{"a span too wide", false, []placed{{Cell{}, [2]int{0, 0}}, {Spans(1, 5), [2]int{1, 0}}}, 2},
+		{"a fixed cell past the end", false, []placed{{CellAt(1, 5).Span(1, 2), [2]int{1, 1}}}, 2},
+		{"overlapping fixed cells", false, []placed{{CellAt(0, 0), [2]int{0, 0}}, {CellAt(0, 0), [2]int{0, 0}}}, 1},
+		{"dense", true, []placed{{Cell{}, [2]int{0, 0}}, {Spans(1, 3), [2]int{1, 0}}, {Cell{}, [2]int{0, 1}}, {Cell{}, [2]int{0, 2}}, {Cell{}, [2]int{2, 0}}}, 3},
+		{"dense around a fixed cell", true, []placed{{CellAt(0, 0), [2]int{0, 0}}, {Spans(1, 2), [2]int{0, 1}}, {Cell{}, [2]int{1, 0}}, {CellAt(1, 2), [2]int{1, 2}}, {Cell{}, [2]int{1, 1}}}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGrid().Columns(Px(10), Px(10), Px(10)).Dense(tt.dense)
+			for _, p := range tt.children {
+				g.Place(newBox(10, 10), p.cell)
+			}
+			if rows := g.place(3); rows != tt.rows {
+				t.Errorf("%d rows, want %d", rows, tt.rows)
+			}
+			for i, p := range tt.children {
+				if a := g.items[i].area; a.row != p.at[0] || a.col != p.at[1] {
+					t.Errorf("child %d at %d, %d, want %v", i, a.row, a.col, p.at)
+				}
+			}
+		})
+	}
+}
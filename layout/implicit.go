@@ -0,0 +1,307 @@
+package layout
+
+import (
+	"time"
+
+	"github.com/gogpu/ui/anim"
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/state"
+)
+
+const implicitDuration = 250 * time.Millisecond
+
+// implicit animates a value from where it is to each new value of a
+// signal.
+type implicit[T comparable] struct {
+	sig   *state.Signal[T]
+	tween anim.Tween[T] // From where the value was to the signal's.
+	dur   time.Duration
+	curve anim.Curve
+
+	cancel func() // Ends the subscription to the signal.
+	ctl    *anim.Controller
+	value  T
+}
+
+func newImplicit[T comparable](sig *state.Signal[T], tween anim.Tween[T]) implicit[T] {
+	return implicit[T]{sig: sig, tween: tween, dur: implicitDuration, curve: anim.Standard}
+}
+
+// bind starts following the signal, at its value without animating, and
+// calls changed at every step of the animations to its later values.
+func (i *implicit[T]) bind(ctx *core.Context, changed func()) {
+	if i.cancel != nil {
+		return
+	}
+	i.tween.To = i.sig.Get()
+	i.value = i.tween.To
+	i.cancel = i.sig.Subscribe(func(T) { ctx.Post(func() { i.retarget(ctx, changed) }) })
+}
+
+// release stops following the signal and ends the animation, until the
+// next bind.
+func (i *implicit[T]) release() {
+	if i.cancel == nil {
+		return
+	}
+	i.cancel()
+	i.cancel = nil
+	if i.ctl != nil {
+		i.ctl.Stop()
+	}
+}
+
+func (i *implicit[T]) retarget(ctx *core.Context, changed func()) {
+	target := i.sig.Get()
+	if i.cancel == nil || target == i.tween.To {
+		return
+	}
+	if i.ctl == nil {
+		i.ctl = anim.NewController(anim.ClockOf(ctx), i.dur)
+		i.ctl.Listen(func(v float32) {
+			i.value = i.tween.At(v)
+			changed()
+		})
+	}
+	i.tween.From, i.tween.To = i.value, target
+	i.ctl.Duration(i.dur).Curve(i.curve).SetProgress(0)
+	i.ctl.Forward()
+}
+
+// AnimatedOpacity fades its child to each new value of a signal, from 0
+// to 1, without a controller to manage:
+//
+//	visible := state.New[float32](1)
+//	hint := layout.NewAnimatedOpacity(label, visible)
+//	visible.Set(0) // fades out
+//
+// The child is painted as a group, as by Composite.
+type AnimatedOpacity struct {
+	core.WidgetBase
+	group *Composite
+	shown *state.Signal[float32]
+	anim  implicit[float32]
+}
+
+// NewAnimatedOpacity returns child faded to the values of opacity.
+func NewAnimatedOpacity(child core.Widget, opacity *state.Signal[float32]) *AnimatedOpacity {
+	o := &AnimatedOpacity{shown: state.New(opacity.Get()), anim: newImplicit(opacity, anim.Float(0, 0))}
+	o.group = NewComposite(child).Bind(o.shown)
+	o.SetChildren(o.group)
+	return o
+}
+
+// Duration sets how long a fade takes, 250 ms by default.
+func (o *AnimatedOpacity) Duration(d time.Duration) *AnimatedOpacity {
+	o.anim.dur = d
+	return o
+}
+
+// Curve sets the easing of the fades, anim.Standard by default.
+func (o *AnimatedOpacity) Curve(c anim.Curve) *AnimatedOpacity {
+	o.anim.curve = c
+	return o
+}
+
+// Release stops following the signal and frees the layer of the group,
+// for when the container leaves the tree. Laid out again, the container
+// follows the signal from its value then.
+func (o *AnimatedOpacity) Release() {
+	o.anim.release()
+	o.group.Release()
+}
+
+// Layout implements core.Widget.
+func (o *AnimatedOpacity) Layout(ctx *core.LayoutContext) core.Size {
+	o.anim.bind(ctx.Context, func() { o.shown.Set(o.anim.value) })
+	return ctx.Measure(o.group, ctx.Constraints)
+}
+
+// IntrinsicWidth implements core.IntrinsicSizer.
+func (o *AnimatedOpacity) IntrinsicWidth(ctx *core.LayoutContext, height float32) (minWidth, maxWidth float32) {
+	return ctx.IntrinsicWidth(o.group, height)
+}
+
+// IntrinsicHeight implements core.IntrinsicSizer.
+func (o *AnimatedOpacity) IntrinsicHeight(ctx *core.LayoutContext, width float32) (minHeight, maxHeight float32) {
+	return ctx.IntrinsicHeight(o.group, width)
+}
+
+// Baseline implements core.Baseliner with the child's baseline.
+func (o *AnimatedOpacity) Baseline() (float32, bool) {
+	return core.BaselineOf(o.group)
+}
+
+// SetBounds implements core.Widget.
+func (o *AnimatedOpacity) SetBounds(r core.Rect) {
+	o.WidgetBase.SetBounds(r)
+	o.group.SetBounds(r)
+}
+
+// Paint implements core.Widget.
+func (o *AnimatedOpacity) Paint(ctx *core.PaintContext) {
+	o.group.Paint(ctx)
+}
+
+// AnimatedOffset slides its child by each new offset of a signal, in
+// logical pixels, without a controller to manage, such as to slide a
+// panel in from the side. The container keeps the place of the child
+// unmoved; the child and its input move with the offset.
+type AnimatedOffset struct {
+	core.WidgetBase
+	child core.Widget
+	anim  implicit[core.Point]
+	ctx   *core.Context
+}
+
+// NewAnimatedOffset returns child moved by the values of offset.
+func NewAnimatedOffset(child core.Widget, offset *state.Signal[core.Point]) *AnimatedOffset {
+	o := &AnimatedOffset{child: child, anim: newImplicit(offset, anim.Point(core.Point{}, core.Point{}))}
+	o.SetChildren(child)
+	return o
+}
+
+// Duration sets how long a slide takes, 250 ms by default.
+func (o *AnimatedOffset) Duration(d time.Duration) *AnimatedOffset {
+	o.anim.dur = d
+	return o
+}
+
+// Curve sets the easing of the slides, anim.Standard by default.
+func (o *AnimatedOffset) Curve(c anim.Curve) *AnimatedOffset {
+	o.anim.curve = c
+	return o
+}
+
+// Release stops following the signal, for when the container leaves the
+// tree. Laid out again, the container follows the signal from its value
+// then.
+func (o *AnimatedOffset) Release() {
+	o.anim.release()
+}
+
+// Layout implements core.Widget.
+func (o *AnimatedOffset) Layout(ctx *core.LayoutContext) core.Size {
+	o.ctx = ctx.Context
+	o.anim.bind(ctx.Context, o.moved)
+	return ctx.Measure(o.child, ctx.Constraints)
+}
+
+// moved arranges the child at the offset of the step, painting where it
+// was and where it is.
+func (o *AnimatedOffset) moved() {
+	old := o.child.Bounds()
+	o.ctx.MarkNeedsLayout(o)
+	o.ctx.InvalidateRect(old.Union(o.Bounds().Translate(o.anim.value)))
+}
+
+// IntrinsicWidth implements core.IntrinsicSizer.
+func (o *AnimatedOffset) IntrinsicWidth(ctx *core.LayoutContext, height float32) (minWidth, maxWidth float32) {
+	return ctx.IntrinsicWidth(o.child, height)
+}
+
+// IntrinsicHeight implements core.IntrinsicSizer.
+func (o *AnimatedOffset) IntrinsicHeight(ctx *core.LayoutContext, width float32) (minHeight, maxHeight float32) {
+	return ctx.IntrinsicHeight(o.child, width)
+}
+
+// Baseline implements core.Baseliner with the child's baseline.
+func (o *AnimatedOffset) Baseline() (float32, bool) {
+	return core.BaselineOf(o.child)
+}
+
+// SetBounds implements core.Widget.
+func (o *AnimatedOffset) SetBounds(r core.Rect) {
+	o.WidgetBase.SetBounds(r)
+	o.child.SetBounds(r.Translate(o.anim.value))
+}
+
+// HitTest implements core.HitTester, taking the points of the moved
+// child.
+func (o *AnimatedOffset) HitTest(p core.Point) bool {
+	return o.child.Bounds().Contains(p)
+}
+
+// Paint implements core.Widget.
+func (o *AnimatedOffset) Paint(ctx *core.PaintContext) {
+	o.child.Paint(ctx)
+}
+
+// AnimatedBackground fills its bounds behind its child with each new
+// color of a signal, blending to it without a controller to manage, such
+// as for rows that highlight when selected.
+type AnimatedBackground struct {
+	core.WidgetBase
+	child  core.Widget
+	radius float32
+	anim   implicit[core.Color]
+}
+
+// NewAnimatedBackground returns child over the colors of background.
+func NewAnimatedBackground(child core.Widget, background *state.Signal[core.Color]) *AnimatedBackground {
+	b := &AnimatedBackground{child: child, anim: newImplicit(background, anim.Color(core.Color{}, core.Color{}))}
+	b.SetChildren(child)
+	return b
+}
+
+// Radius sets the corner radius of the background.
+func (b *AnimatedBackground) Radius(r float32) *AnimatedBackground {
+	b.radius = r
+	return b
+}
+
+// Duration sets how long a change of color takes, 250 ms by default.
+func (b *AnimatedBackground) Duration(d time.Duration) *AnimatedBackground {
+	b.anim.dur = d
+	return b
+}
+
+// Curve sets the easing of the changes of color, anim.Standard by
+// default.
+func (b *AnimatedBackground) Curve(c anim.Curve) *AnimatedBackground {
+	b.anim.curve = c
+	return b
+}
+
+// Release stops following the signal, for when the container leaves the
+// tree. Laid out again, the container follows the signal from its value
+// then.
+func (b *AnimatedBackground) Release() {
+	b.anim.release()
+}
+
+// Layout implements core.Widget.
+func (b *AnimatedBackground) Layout(ctx *core.LayoutContext) core.Size {
+	cx := ctx.Context
+	b.anim.bind(cx, func() { cx.Repaint(b) })
+	return ctx.Measure(b.child, ctx.Constraints)
+}
+
+// IntrinsicWidth implements core.IntrinsicSizer.
+func (b *AnimatedBackground) IntrinsicWidth(ctx *core.LayoutContext, height float32) (minWidth, maxWidth float32) {
+	return ctx.IntrinsicWidth(b.child, height)
+}
+
+// IntrinsicHeight implements core.IntrinsicSizer.
+func (b *AnimatedBackground) IntrinsicHeight(ctx *core.LayoutContext, width float32) (minHeight, maxHeight float32) {
+	return ctx.IntrinsicHeight(b.child, width)
+}
+
+// Baseline implements core.Baseliner with the child's baseline.
+func (b *AnimatedBackground) Baseline() (float32, bool) {
+	return core.BaselineOf(b.child)
+}
+
+// SetBounds implements core.Widget.
+func (b *AnimatedBackground) SetBounds(r core.Rect) {
+	b.WidgetBase.SetBounds(r)
+	b.child.SetBounds(r)
+}
+
+// Paint implements core.Widget.
+func (b *AnimatedBackground) Paint(ctx *core.PaintContext) {
+	if c := b.anim.value; !c.IsTransparent() {
+		ctx.Canvas.DrawRoundedRect(b.Bounds(), b.radius, core.Filled(c))
+	}
+	b.child.Paint(ctx)
+}
@@ -0,0 +1,109 @@
+package layout
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogpu/ui/anim"
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/state"
+)
+
+func TestImplicitRelease(t *testing.T) {
+	red, blue := core.RGB(255, 0, 0), core.RGB(0, 0, 255)
+	tests := []struct {
+		name    string
+		release func(b *AnimatedBackground)
+		follows bool
+	}{
+		{"bound", func(*AnimatedBackground) {}, true},
+		{"released", (*AnimatedBackground).Release, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sig := state.New(red)
+			b := NewAnimatedBackground(newBox(10, 10), sig).Duration(100 * time.Millisecond).Curve(anim.Linear)
+			t0 := time.Unix(0, 0)
+			ctx := core.NewContext()
+			ctx.SetNow(t0)
+			ctx.LayoutRoot(b, core.R(0, 0, 10, 10))
+			if b.anim.value != red {
+				t.Fatalf("value = %v, want the signal's %v", b.anim.value, red)
+			}
+
+			tt.release(b)
+			sig.Set(blue)
+			if got := ctx.HasPosted(); got != tt.follows {
+				t.Fatalf("HasPosted() = %v after a change, want %v", got, tt.follows)
+			}
+			ctx.RunPosted()
+			clock := anim.ClockOf(ctx)
+			clock.Tick(t0)
+			clock.Tick(t0.Add(100 * time.Millisecond))
+			want := red
+			if tt.follows {
+				want = blue
+			}
+			if b.anim.value != want {
+				t.Errorf("value = %v, want %v", b.anim.value, want)
+			}
+		})
+	}
+}
+
+func TestImplicitRebind(t *testing.T) {
+	// Laid out again after Release, the container follows the signal from
+	// its value then, without animating to it.
+	sig := state.New(core.Pt(0, 0))
+	o := NewAnimatedOffset(newBox(10, 10), sig)
+	ctx := core.NewContext()
+	ctx.LayoutRoot(o, core.R(0, 0, 10, 10))
+	o.Release()
+	sig.Set(core.Pt(5, 0))
+	ctx.Invalidate()
+	ctx.LayoutRoot(o, core.R(0, 0, 10, 10))
+	if got, want := o.child.Bounds(), core.R(5, 0, 10, 10); got != want {
+		t.Errorf("child bounds = %v, want %v", got, want)
+	}
+	sig.Set(core.Pt(8, 0))
+	if !ctx.HasPosted() {
+		t.Error("the container does not follow the signal again")
+	}
+}
+
+func TestImplicitAnimates(t *testing.T) {
+	red, blue := core.RGB(255, 0, 0), core.RGB(0, 0, 255)
+	opacity := state.New[float32](0)
+	offset := state.New(core.Pt(0, 0))
+	background := state.New(red)
+	o := NewAnimatedOpacity(newBox(10, 10), opacity).Duration(100 * time.Millisecond).Curve(anim.Linear)
+	f := NewAnimatedOffset(newBox(10, 10), offset).Duration(100 * time.Millisecond).Curve(anim.Linear)
+	b := NewAnimatedBackground(newBox(10, 10), background).Duration(100 * time.Millisecond).Curve(anim.Linear)
+	tests := []struct {
+		name   string
+		w      core.Widget
+		change func()
+		check  func() (got, want any)
+	}{
+		{"opacity", o, func() { opacity.Set(1) }, func() (any, any) { return o.shown.Get(), float32(0.5) }},
+		{"offset", f, func() { offset.Set(core.Pt(10, 0)) }, func() (any, any) { return f.child.Bounds(), core.R(5, 0, 10, 10) }},
+		{"background", b, func() { background.Set(blue) }, func() (any, any) { return b.anim.value, anim.Color(red, blue).At(0.5) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t0 := time.Unix(0, 0)
+			ctx := core.NewContext()
+			ctx.SetNow(t0)
+			ctx.LayoutRoot(tt.w, core.R(0, 0, 10, 10))
+			tt.change()
+			ctx.RunPosted()
+			clock := anim.ClockOf(ctx)
+			clock.Tick(t0)
+			clock.Tick(t0.Add(50 * time.Millisecond))
+			ctx.LayoutRoot(tt.w, core.R(0, 0, 10, 10))
+			if got, want := tt.check(); got != want {
+				t.Errorf("halfway at %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package layout
+
+import "github.com/gogpu/ui/core"
+
+// IntrinsicWidth sizes its child to the child's maximum intrinsic width,
+// within the constraints it is given, instead of the widest size allowed.
+// It lets a column of widgets, such as the items of a menu, take the width
+// of the widest one. It asks the child for its intrinsic sizes, which
+// costs a layout of the child for widgets that do not implement
+// core.IntrinsicSizer.
+type IntrinsicWidth struct {
+	core.WidgetBase
+	child core.Widget
+}
+
+// NewIntrinsicWidth returns a container sizing child to its intrinsic
+// width.
+func NewIntrinsicWidth(child core.Widget) *IntrinsicWidth {
+	w := &IntrinsicWidth{child: child}
+	w.SetChildren(child)
+	return w
+}
+
+// Layout implements core.Widget.
+func (w *IntrinsicWidth) Layout(ctx *core.LayoutContext) core.Size {
+	c := ctx.Constraints
+	if !c.IsTight() {
+		_, width := ctx.IntrinsicWidth(w.child, c.MaxHeight)
+		width = core.Clamp(width, c.MinWidth, c.MaxWidth)
+		c.MinWidth, c.MaxWidth = width, width
+	}
+	return ctx.Measure(w.child, c)
+}
+
+// IntrinsicWidth implements core.IntrinsicSizer.
+func (w *IntrinsicWidth) IntrinsicWidth(ctx *core.LayoutContext, height float32) (minWidth, maxWidth float32) {
+	_, width := ctx.IntrinsicWidth(w.child, height)
+	return width, width
+}
+
+// IntrinsicHeight implements core.IntrinsicSizer.
+func (w *IntrinsicWidth) IntrinsicHeight(ctx *core.LayoutContext, width float32) (minHeight, maxHeight float32) {
+	return ctx.IntrinsicHeight(w.child, width)
+}
+
+// Baseline implements core.Baseliner with the child's baseline.
+func (w *IntrinsicWidth) Baseline() (float32, bool) {
+	return core.BaselineOf(w.child)
+}
+
+// SetBounds implements core.Widget.
+func (w *IntrinsicWidth) SetBounds(r core.Rect) {
+	w.WidgetBase.SetBounds(r)
+	w.child.SetBounds(r)
+}
+
+// Paint implements core.Widget.
+func (w *IntrinsicWidth) Paint(ctx *core.PaintContext) {
+	w.child.Paint(ctx)
+}
+
+// IntrinsicHeight sizes its child to the child's maximum intrinsic height
+// at the width it is given, so that siblings in a row can share the height
+// of the tallest one. Like IntrinsicWidth, it lays out the child an extra
+// time for widgets that do not implement core.IntrinsicSizer.
+type IntrinsicHeight struct {
+	core.WidgetBase
+	child core.Widget
+}
+
+// NewIntrinsicHeight returns a container sizing child to its intrinsic
+// height.
+func NewIntrinsicHeight(child core.Widget) *IntrinsicHeight {
+	h := &IntrinsicHeight{child: child}
+	h.SetChildren(child)
+	return h
+}
+
+// Layout implements core.Widget.
+func (h *IntrinsicHeight) Layout(ctx *core.LayoutContext) core.Size {
+	c := ctx.Constraints
+	if !c.IsTight() {
+		_, height := ctx.IntrinsicHeight(h.child, c.MaxWidth)
+		height = core.Clamp(height, c.MinHeight, c.MaxHeight)
+		c.MinHeight, c.MaxHeight = height, height
+	}
+	return ctx.Measure(h.child, c)
+}
+
+// IntrinsicWidth implements core.IntrinsicSizer.
+func (h *IntrinsicHeight) IntrinsicWidth(ctx *core.LayoutContext, height float32) (minWidth, maxWidth float32) {
+	return ctx.IntrinsicWidth(h.child, height)
+}
+
+// IntrinsicHeight implements core.IntrinsicSizer.
+func (h *IntrinsicHeight) IntrinsicHeight(ctx *core.LayoutContext, width float32) (minHeight, maxHeight float32) {
+	_, height := ctx.IntrinsicHeight(h.child, width)
+	return height, height
+}
+
+// Baseline implements core.Baseliner with the child's baseline.
+func (h *IntrinsicHeight) Baseline() (float32, bool) {
+	return core.BaselineOf(h.child)
+}
+
+// SetBounds implements core.Widget.
+func (h *IntrinsicHeight) SetBounds(r core.Rect) {
+	h.WidgetBase.SetBounds(r)
+	h.child.SetBounds(r)
+}
+
+// Paint implements core.Widget.
+func (h *IntrinsicHeight) Paint(ctx *core.PaintContext) {
+	h.child.Paint(ctx)
+}
+
+// AspectRatio sizes its child to a width-to-height ratio, as large as the
+// constraints allow: the full width if the matching height fits, else the
+// full height. With both axes unbounded it uses the child's intrinsic
+// width.
+type AspectRatio struct {
+	core.WidgetBase
+	ratio float32
+	child core.Widget
+}
+
+// NewAspectRatio returns a container keeping child at ratio, the width
+// divided by the height, such as 16.0/9.
+func NewAspectRatio(ratio float32, child core.Widget) *AspectRatio {
+	a := &AspectRatio{child: child}
+	a.SetRatio(ratio)
+	a.SetChildren(child)
+	return a
+}
+
+// Ratio returns the width-to-height ratio.
+func (a *AspectRatio) Ratio() float32 {
+	return a.ratio
+}
+
+// SetRatio changes the width-to-height ratio. Ratios that are not
+// positive are replaced by 1.
+func (a *AspectRatio) SetRatio(ratio float32) {
+	if !(ratio > 0) {
+		ratio = 1
+	}
+	a.ratio = ratio
+}
+
+// Layout implements core.Widget.
+func (a *AspectRatio) Layout(ctx *core.LayoutContext) core.Size {
+	c := ctx.Constraints
+	var w float32
+	switch {
+	case c.HasBoundedWidth():
+		w = c.MaxWidth
+	case c.HasBoundedHeight():
+		w = c.MaxHeight * a.ratio
+	default:
+		_, w = ctx.IntrinsicWidth(a.child, core.Infinity)
+	}
+	h := w / a.ratio
+	if h > c.MaxHeight {
+		h = c.MaxHeight
+		w = h * a.ratio
+	}
+	// Grow to the minimums, keeping the ratio where the maximums allow.
+	if w < c.MinWidth {
+		w = c.MinWidth
+		h = min(max(w/a.ratio, c.MinHeight), c.MaxHeight)
+	}
+	if h < c.MinHeight {
+		h = c.MinHeight
+		w = min(max(h*a.ratio, c.MinWidth), c.MaxWidth)
+	}
+	size := c.Constrain(core.Sz(w, h))
+	ctx.Measure(a.child, core.Tight(size))
+	return size
+}
+
+// IntrinsicWidth implements core.IntrinsicSizer.
+func (a *AspectRatio) IntrinsicWidth(ctx *core.LayoutContext, height float32) (minWidth, maxWidth float32) {
+	if height < core.Infinity {
+		return height * a.ratio, height * a.ratio
+	}
+	return ctx.IntrinsicWidth(a.child, height)
+}
+
+// IntrinsicHeight implements core.IntrinsicSizer.
+func (a *AspectRatio) IntrinsicHeight(ctx *core.LayoutContext, width float32) (minHeight, maxHeight float32) {
+	if width < core.Infinity {
+		return width / a.ratio, width / a.ratio
+	}
+	return ctx.IntrinsicHeight(a.child, width)
+}
+
+// SetBounds implements core.Widget.
+func (a *AspectRatio) SetBounds(r core.Rect) {
+	a.WidgetBase.SetBounds(r)
+	a.child.SetBounds(r)
+}
+
+// Paint implements core.Widget.
+func (a *AspectRatio) Paint(ctx *core.PaintContext) {
+	a.child.Paint(ctx)
+}
@@ -0,0 +1,198 @@
+package layout
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+)
+
+// area is a leaf of a fixed area, like a paragraph: between its minimum
+// and maximum widths it gets taller as it gets narrower.
+type area struct {
+	core.WidgetBase
+	min, max, area float32
+}
+
+func (a *area) height(width float32) float32 {
+	return a.area / core.Clamp(width, a.min, a.max)
+}
+
+func (a *area) Layout(ctx *core.LayoutContext) core.Size {
+	c := ctx.Constraints
+	w := core.Clamp(a.max, c.MinWidth, c.MaxWidth)
+	return c.Constrain(core.Sz(w, a.height(w)))
+}
+
+func (a *area) IntrinsicWidth(*core.LayoutContext, float32) (float32, float32) {
+	return a.min, a.max
+}
+
+func (a *area) IntrinsicHeight(_ *core.LayoutContext, width float32) (float32, float32) {
+	return a.height(width), a.height(width)
+}
+
+func (a *area) Paint(*core.PaintContext) {}
+
+// unbounded returns constraints bounded only by the given maximum height.
+func unbounded(maxHeight float32) core.Constraints {
+	return core.Constraints{MaxWidth: core.Infinity, MaxHeight: maxHeight}
+}
+
+func TestIntrinsicWidth(t *testing.T) {
+	tests := []struct {
+		name  string
+		child core.Widget
+		cs    core.Constraints
+		want  core.Size
+	}{
+		{"sizer", &area{min: 20, max: 100, area: 1000}, core.Loose(core.Sz(300, 300)), core.Sz(100, 10)},
+		{"narrow", &area{min: 20, max: 100, area: 1000}, core.Loose(core.Sz(50, 300)), core.Sz(50, 20)},
+		{"minimum", &area{min: 20, max: 100, area: 1000}, core.Constraints{MinWidth: 150, MaxWidth: 300, MaxHeight: 300}, core.Sz(150, 1000.0/100)},
+		{"tight", &area{min: 20, max: 100, area: 1000}, core.Tight(core.Sz(200, 40)), core.Sz(200, 40)},
+		{"measured", newBox(40, 10), core.Loose(core.Sz(300, 300)), core.Sz(40, 10)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lc := &core.LayoutContext{Context: core.NewContext()}
+			w := NewIntrinsicWidth(tt.child)
+			if got := lc.Measure(w, tt.cs); got != tt.want {
+				t.Errorf("size = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIntrinsicWidthColumn(t *testing.T) {
+	// The items of a menu take the width of the widest one.
+	a, b := newBox(40, 10), newBox(90, 10)
+	col := NewVStack(a, b).Align(AlignStretch)
+	layoutIn(NewIntrinsicWidth(col), core.R(0, 0, 300, 300), core.LeftToRight)
+	if got := a.Bounds(); got != core.R(0, 0, 90, 10) {
+		t.Errorf("bounds = %v, want the width of the widest item", got)
+	}
+}
+
+func TestIntrinsicHeight(t *testing.T) {
+	tests := []struct {
+		name  string
+		child core.Widget
+		cs    core.Constraints
+		want  core.Size
+	}{
+		{"sizer", &area{min: 20, max: 100, area: 1000}, core.Loose(core.Sz(50, 300)), core.Sz(50, 20)},
+		{"short", &area{min: 20, max: 100, area: 1000}, core.Loose(core.Sz(50, 15)), core.Sz(50, 15)},
+		{"unbounded width", &area{min: 20, max: 100, area: 1000}, unbounded(300), core.Sz(100, 10)},
+		{"tight", &area{min: 20, max: 100, area: 1000}, core.Tight(core.Sz(200, 40)), core.Sz(200, 40)},
+		{"measured", newBox(40, 10), core.Loose(core.Sz(300, 300)), core.Sz(40, 10)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lc := &core.LayoutContext{Context: core.NewContext()}
+			h := NewIntrinsicHeight(tt.child)
+			if got := lc.Measure(h, tt.cs); got != tt.want {
+				t.Errorf("size = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIntrinsicHeightRow(t *testing.T) {
+	// Siblings in a row share the height of the tallest.
+	a, b := newBox(10, 10), newBox(10, 30)
+	row := NewHStack(a, b).Align(AlignStretch)
+	layoutIn(NewIntrinsicHeight(row), core.R(0, 0, 300, 300), core.LeftToRight)
+	if got := a.Bounds(); got != core.R(0, 0, 10, 30) {
+		t.Errorf("bounds = %v, want the height of the tallest sibling", got)
+	}
+}
+
+func TestIntrinsicContainersReport(t *testing.T) {
+	lc := &core.LayoutContext{Context: core.NewContext()}
+	child := &area{min: 20, max: 100, area: 1000}
+	w, h := NewIntrinsicWidth(child), NewIntrinsicHeight(child)
+	tests := []struct {
+		name   string
+		got    func() (float32, float32)
+		lo, hi float32
+	}{
+		{"width of IntrinsicWidth", func() (float32, float32) { return w.IntrinsicWidth(lc, core.Infinity) }, 100, 100},
+		{"height of IntrinsicWidth", func() (float32, float32) { return w.IntrinsicHeight(lc, 50) }, 20, 20},
+		{"width of IntrinsicHeight", func() (float32, float32) { return h.IntrinsicWidth(lc, core.Infinity) }, 20, 100},
+		{"height of IntrinsicHeight", func() (float32, float32) { return h.IntrinsicHeight(lc, 50) }, 20, 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if lo, hi := tt.got(); lo != tt.lo || hi != tt.hi {
+				t.Errorf("= %v, %v, want %v, %v", lo, hi, tt.lo, tt.hi)
+			}
+		})
+	}
+
+	label := text(40, 20, 15)
+	for _, b := range []core.Baseliner{NewIntrinsicWidth(label), NewIntrinsicHeight(label)} {
+		if got, ok := b.Baseline(); !ok || got != 15 {
+			t.Errorf("Baseline() = %v, %v, want the child's 15", got, ok)
+		}
+	}
+}
+
+func TestAspectRatio(t *testing.T) {
+	tests := []struct {
+		name  string
+		ratio float32
+		cs    core.Constraints
+		want  core.Size
+	}{
+		{"full width", 2, core.Loose(core.Sz(300, 300)), core.Sz(300, 150)},
+		{"full height", 2, core.Loose(core.Sz(300, 100)), core.Sz(200, 100)},
+		{"unbounded width", 2, unbounded(50), core.Sz(100, 50)},
+		{"unbounded", 2, core.Unbounded(), core.Sz(40, 20)},
+		{"minimum width", 2, core.Constraints{MinWidth: 100, MaxWidth: core.Infinity, MaxHeight: core.Infinity}, core.Sz(100, 50)},
+		{"minimum height", 2, core.Constraints{MinHeight: 80, MaxWidth: 100, MaxHeight: 200}, core.Sz(100, 80)},
+		{"tight", 2, core.Tight(core.Sz(300, 100)), core.Sz(300, 100)},
+		{"tall", 0.5, core.Loose(core.Sz(300, 300)), core.Sz(150, 300)},
+		{"zero ratio", 0, core.Loose(core.Sz(300, 200)), core.Sz(200, 200)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lc := &core.LayoutContext{Context: core.NewContext()}
+			child := newBox(40, 10)
+			a := NewAspectRatio(tt.ratio, child)
+			got := lc.Measure(a, tt.cs)
+			if got != tt.want {
+				t.Errorf("size = %v, want %v", got, tt.want)
+			}
+			a.SetBounds(core.R(5, 5, got.Width, got.Height))
+			if child.Bounds() != a.Bounds() {
+				t.Errorf("child bounds = %v, want %v", child.Bounds(), a.Bounds())
+			}
+		})
+	}
+}
+
+func TestAspectRatioIntrinsic(t *testing.T) {
+	lc := &core.LayoutContext{Context: core.NewContext()}
+	nan := float32(0)
+	a := NewAspectRatio(0/nan, newBox(40, 10))
+	if a.Ratio() != 1 {
+		t.Errorf("Ratio() = %v for NaN, want 1", a.Ratio())
+	}
+	a.SetRatio(4)
+	tests := []struct {
+		name   string
+		got    func() (float32, float32)
+		lo, hi float32
+	}{
+		{"width at a height", func() (float32, float32) { return a.IntrinsicWidth(lc, 10) }, 40, 40},
+		{"width", func() (float32, float32) { return a.IntrinsicWidth(lc, core.Infinity) }, 40, 40},
+		{"height at a width", func() (float32, float32) { return a.IntrinsicHeight(lc, 100) }, 25, 25},
+		{"height", func() (float32, float32) { return a.IntrinsicHeight(lc, core.Infinity) }, 10, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if lo, hi := tt.got(); lo != tt.lo || hi != tt.hi {
+				t.Errorf("= %v, %v, want %v, %v", lo, hi, tt.lo, tt.hi)
+			}
+		})
+	}
+}
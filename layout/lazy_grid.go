@@ -0,0 +1,395 @@
+package layout
+
+import (
+	"math"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/internal/scroll"
+)
+
+const (
+	defaultGridExtent   float32 = 120
+	defaultGridOverscan float32 = 120
+)
+
+// LazyGrid is a scrolling grid of count items that builds only the cells
+// intersecting the viewport plus an overscan margin, so it can show tens of
+// thousands of items:
+//
+//	grid := layout.NewLazyGrid(len(assets), func(i int) core.Widget {
+//	    return newThumbnail(assets[i])
+//	}).MaxCellExtent(160).Spacing(8)
+//
+// Cells are laid out row by row, all the same size. With MaxCellExtent the
+// grid fits as many columns as it can with cells no wider than the extent
+// and stretches them to fill the width; with Columns the column count is
+// fixed. Cells are square unless CellHeight or CellAspectRatio is set.
+// In right-to-left layouts rows fill from the right and the scrollbar is
+// on the left.
+//
+// The grid should be given a bounded height; under unbounded constraints it
+// sizes itself to its content and builds every cell.
+type LazyGrid struct {
+	core.WidgetBase
+	core.FocusState
+
+	count     int
+	build     func(index int) core.Widget
+	columns   int
+	maxExtent float32
+	height    float32
+	aspect    float32
+	spacing   float32
+	overscan  float32
+	onRange   func(first, last int)
+
+	dir       core.Direction
+	cols      int
+	cell      core.Size
+	offset    float32
+	viewport  core.Size
+	cells     map[int]core.Widget
+	first     int
+	last      int
+	pending   int // Index to reveal at the next layout, or -1.
+	bar       scroll.Bar
+	lastFirst int
+	lastLast  int
+}
+
+// NewLazyGrid returns a grid of count items built on demand by build.
+func NewLazyGrid(count int, build func(index int) core.Widget) *LazyGrid {
+	return &LazyGrid{
+		count:     max(0, count),
+		build:     build,
+		maxExtent: defaultGridExtent,
+		aspect:    1,
+		overscan:  defaultGridOverscan,
+		cells:     make(map[int]core.Widget),
+		pending:   -1,
+		lastFirst: -1,
+		lastLast:  -1,
+	}
+}
+
+// Columns fixes the number of columns. Zero restores MaxCellExtent sizing.
+func (g *LazyGrid) Columns(n int) *LazyGrid {
+	g.columns = max(0, n)
+	return g
+}
+
+// MaxCellExtent sets the largest cell width, 120 by default. The grid uses
+// the fewest columns that keep cells within it.
+func (g *LazyGrid) MaxCellExtent(px float32) *LazyGrid {
+	if px > 0 {
+		g.maxExtent = px
+	}
+	g.columns = 0
+	return g
+}
+
+// CellHeight fixes the height of the cells. Zero derives it from the cell
+// width and the aspect ratio.
+func (g *LazyGrid) CellHeight(px float32) *LazyGrid {
+	g.height = max(0, px)
+	return g
+}
+
+// CellAspectRatio sets the width to height ratio of the cells, 1 by
+// default. It has no effect while CellHeight is set.
+func (g *LazyGrid) CellAspectRatio(ratio float32) *LazyGrid {
+	if ratio > 0 {
+		g.aspect = ratio
+	}
+	return g
+}
+
+// Spacing sets the gap between rows and between columns.
+func (g *LazyGrid) Spacing(px float32) *LazyGrid {
+	g.spacing = max(0, px)
+	return g
+}
+
+// Overscan sets how far beyond the viewport, above and below, cells are
+// built ahead of scrolling.
+func (g *LazyGrid) Overscan(px float32) *LazyGrid {
+	g.overscan = max(0, px)
+	return g
+}
+
+// OnVisibleRangeChange registers fn to be called after a layout that
+// changed the range of items intersecting the viewport. last is exclusive.
+func (g *LazyGrid) OnVisibleRangeChange(fn func(first, last int)) *LazyGrid {
+	g.onRange = fn
+	return g
+}
+
+// Count returns the number of items.
+func (g *LazyGrid) Count() int {
+	return g.count
+}
+
+// SetCount changes the number of items. Cells past the new count are
+// released; the others are kept.
+func (g *LazyGrid) SetCount(n int) {
+	g.count = max(0, n)
+	for i := range g.cells {
+		if i >= g.count {
+			delete(g.cells, i)
+		}
+	}
+}
+
+// InvalidateItem discards the cell of the item at index so that it is
+// built again.
+func (g *LazyGrid) InvalidateItem(index int) {
+	delete(g.cells, index)
+}
+
+// Refresh discards every cell.
+func (g *LazyGrid) Refresh() {
+	clear(g.cells)
+}
+
+// ScrollOffset returns the distance in pixels from the top of the content
+// to the top of the viewport.
+func (g *LazyGrid) ScrollOffset() float32 {
+	return g.offset
+}
+
+// SetScrollOffset scrolls to the given content offset.
+func (g *LazyGrid) SetScrollOffset(y float32) {
+	g.pending = -1
+	g.offset = g.clampOffset(y)
+}
+
+// ScrollBy scrolls by dy pixels and reports whether the offset changed.
+func (g *LazyGrid) ScrollBy(dy float32) bool {
+	old := g.offset
+	g.SetScrollOffset(g.offset + dy)
+	return g.offset != old
+}
+
+// ScrollToIndex scrolls the minimum distance that makes the item at index
+// fully visible. The request is resolved during the next layout, once the
+// column count is known.
+func (g *LazyGrid) ScrollToIndex(index int) {
+	if g.count > 0 {
+		g.pending = min(max(index, 0), g.count-1)
+	}
+}
+
+// VisibleRange returns the indexes of the items in the rows intersecting
+// the viewport after the last layout. last is exclusive.
+func (g *LazyGrid) VisibleRange() (first, last int) {
+	if g.count == 0 || g.cols == 0 {
+		return 0, 0
+	}
+	top := g.rowAt(g.offset)
+	bottom := g.rowAt(g.offset+g.viewport.Height-0.5) + 1
+	return min(top*g.cols, g.count), min(bottom*g.cols, g.count)
+}
+
+func (g *LazyGrid) stride() float32 {
+	return g.cell.Height + g.spacing
+}
+
+func (g *LazyGrid) rows() int {
+	if g.cols == 0 {
+		return 0
+	}
+	return (g.count + g.cols - 1) / g.cols
+}
+
+// rowAt returns the row at content offset y, clamped to the rows.
+func (g *LazyGrid) rowAt(y float32) int {
+	if g.stride() <= 0 {
+		return 0
+	}
+	return min(max(int(y/g.stride()), 0), max(g.rows()-1, 0))
+}
+
+func (g *LazyGrid) total() float32 {
+	n := g.rows()
+	if n == 0 {
+		return 0
+	}
+	return float32(n)*g.stride() - g.spacing
+}
+
+func (g *LazyGrid) clampOffset(y float32) float32 {
+	return core.Clamp(y, 0, max(0, g.total()-g.viewport.Height))
+}
+
+// fitColumns returns the number and width of the columns that fill width:
+// columns if it is positive, otherwise the fewest no wider than maxExtent.
+func fitColumns(width float32, columns int, maxExtent, spacing float32) (int, float32) {
+	n := columns
+	if n <= 0 {
+		n = int(math.Ceil(float64((width + spacing) / (maxExtent + spacing))))
+	}
+	n = max(n, 1)
+	return n, max(0, (width-spacing*float32(n-1))/float32(n))
+}
+
+// arrange derives the column count and cell size from the width available
+// to the cells. When the column count changes, the item at the top of the
+// viewport stays at the top.
+func (g *LazyGrid) arrange(width float32) {
+	cols, cellW := fitColumns(width, g.columns, g.maxExtent, g.spacing)
+	cellH := g.height
+	if cellH == 0 {
+		cellH = cellW / g.aspect
+	}
+	if g.cols != 0 && cols != g.cols {
+		top := g.rowAt(g.offset) * g.cols
+		delta := g.offset - float32(g.rowAt(g.offset))*g.stride()
+		g.cols, g.cell = cols, core.Sz(cellW, cellH)
+		g.offset = float32(top/cols)*g.stride() + min(delta, cellH)
+		return
+	}
+	g.cols, g.cell = cols, core.Sz(cellW, cellH)
+}
+
+// Layout implements core.Widget.
+func (g *LazyGrid) Layout(ctx *core.LayoutContext) core.Size {
+	g.dir = ctx.Direction()
+	c := ctx.Constraints
+	width := c.MinWidth
+	switch {
+	case c.HasBoundedWidth():
+		width = c.MaxWidth
+	case g.columns > 0:
+		width = max(width, float32(g.columns)*g.maxExtent+scroll.Thickness)
+	default:
+		width = max(width, g.maxExtent+scroll.Thickness)
+	}
+	g.arrange(max(0, width-scroll.Thickness))
+	height := g.total()
+	if c.HasBoundedHeight() {
+		height = c.MaxHeight
+	}
+	g.viewport = c.Constrain(core.Sz(width, height))
+
+	if g.pending >= 0 {
+		top := float32(g.pending/g.cols) * g.stride()
+		if top < g.offset {
+			g.offset = top
+		} else if top+g.cell.Height > g.offset+g.viewport.Height {
+			g.offset = top + g.cell.Height - g.viewport.Height
+		}
+		g.pending = -1
+	}
+	g.offset = g.clampOffset(g.offset)
+	g.realize(ctx)
+
+	if g.onRange != nil {
+		if first, last := g.VisibleRange(); first != g.lastFirst || last != g.lastLast {
+			g.lastFirst, g.lastLast = first, last
+			g.onRange(first, last)
+		}
+	}
+	return g.viewport
+}
+
+// realize builds and measures the cells of the rows covering the viewport
+// and overscan, releasing cells that scrolled out.
+func (g *LazyGrid) realize(ctx *core.LayoutContext) {
+	if g.count == 0 {
+		g.first, g.last = 0, 0
+		g.SetChildren()
+		clear(g.cells)
+		return
+	}
+	g.first = g.rowAt(g.offset-g.overscan) * g.cols
+	g.last = min((g.rowAt(g.offset+g.viewport.Height+g.overscan)+1)*g.cols, g.count)
+	live := make(map[int]core.Widget, g.last-g.first)
+	children := make([]core.Widget, 0, g.last-g.first)
+	tight := core.Tight(g.cell)
+	for i := g.first; i < g.last; i++ {
+		w, ok := g.cells[i]
+		if !ok {
+			w = g.build(i)
+		}
+		live[i] = w
+		ctx.Measure(w, tight)
+		children = append(children, w)
+	}
+	g.cells = live
+	g.SetChildren(children...)
+}
+
+// SetBounds implements core.Widget and positions the built cells.
+func (g *LazyGrid) SetBounds(r core.Rect) {
+	g.WidgetBase.SetBounds(r)
+	track, area := scroll.EndTrack(r, g.dir)
+	stride := g.stride()
+	for i := g.first; i < g.last; i++ {
+		row, col := i/g.cols, i%g.cols
+		x := area.X + float32(col)*(g.cell.Width+g.spacing)
+		y := r.Y + float32(row)*stride - g.offset
+		g.cells[i].SetBounds(g.dir.Mirror(core.R(x, y, g.cell.Width, g.cell.Height), area))
+	}
+	g.bar.Track = track
+	g.bar.Viewport = r.Height
+	g.bar.Content = g.total()
+}
+
+// Paint implements core.Widget.
+func (g *LazyGrid) Paint(ctx *core.PaintContext) {
+	ctx.Canvas.Save()
+	ctx.Canvas.Clip(g.Bounds())
+	core.PaintChildren(ctx, g.Children())
+	g.bar.Paint(ctx, g.offset)
+	ctx.Canvas.Restore()
+}
+
+// HandleEvent implements core.Widget. It scrolls on wheel input, on
+// scrollbar drags and, while focused, on arrow, page, Home and End keys.
+func (g *LazyGrid) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	if off, ok := g.bar.HandleEvent(ctx, g, ev, g.offset); ok {
+		if off != g.offset {
+			g.SetScrollOffset(off)
+			ctx.MarkNeedsLayout(g)
+		}
+		return core.Handled
+	}
+	switch e := ev.(type) {
+	case event.ScrollEvent:
+		if g.ScrollBy(e.Delta.Y) {
+			ctx.MarkNeedsLayout(g)
+			return core.Handled
+		}
+	case event.MouseEvent:
+		if e.Type == event.MouseDown {
+			ctx.RequestFocus(g)
+		}
+	case event.KeyEvent:
+		if e.Type != event.KeyPress || !g.IsFocused() {
+			return core.Ignored
+		}
+		var changed bool
+		switch e.Key {
+		case event.KeyUp:
+			changed = g.ScrollBy(-g.stride())
+		case event.KeyDown:
+			changed = g.ScrollBy(g.stride())
+		case event.KeyPageUp:
+			changed = g.ScrollBy(-g.viewport.Height)
+		case event.KeyPageDown:
+			changed = g.ScrollBy(g.viewport.Height)
+		case event.KeyHome:
+			changed = g.ScrollBy(-g.offset)
+		case event.KeyEnd:
+			changed = g.ScrollBy(g.total())
+		default:
+			return core.Ignored
+		}
+		if changed {
+			ctx.MarkNeedsLayout(g)
+		}
+		return core.Handled
+	}
+	return core.Ignored
+}
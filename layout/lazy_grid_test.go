@@ -0,0 +1,142 @@
+package layout
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+)
+
+// builds counts the cells built for each index.
+type builds map[int]int
+
+func (b builds) cell(w, h float32) func(int) core.Widget {
+	return func(i int) core.Widget {
+		b[i]++
+		return newBox(w, h)
+	}
+}
+
+func TestLazyGridBuildsViewport(t *testing.T) {
+	tests := []struct {
+		name              string
+		offset, overscan  float32
+		first, last       int // Built.
+		visFirst, visLast int
+	}{
+		{"top", 0, 0, 0, 8, 0, 8},
+		{"scrolled", 230, 0, 8, 16, 8, 16},
+		{"overscan", 230, 100, 4, 20, 8, 16},
+		{"end", 5000, 0, 92, 100, 92, 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := builds{}
+			// Four columns of 100 by 100 cells beside the scrollbar, and
+			// rows 0 to 1.5 in view.
+			g := NewLazyGrid(100, b.cell(0, 0)).Columns(4).Overscan(tt.overscan)
+			ctx := core.NewContext()
+			bounds := core.R(0, 0, 400+10, 150)
+			ctx.LayoutRoot(g, bounds)
+			g.SetScrollOffset(tt.offset)
+			ctx.Invalidate()
+			ctx.LayoutRoot(g, bounds)
+
+			if got := len(g.Children()); got != tt.last-tt.first {
+				t.Errorf("%d cells built, want %d", got, tt.last-tt.first)
+			}
+			for i := tt.first; i < tt.last; i++ {
+				if _, ok := g.cells[i]; !ok {
+					t.Errorf("cell %d not built", i)
+				}
+			}
+			if first, last := g.VisibleRange(); first != tt.visFirst || last != tt.visLast {
+				t.Errorf("VisibleRange() = %d, %d, want %d, %d", first, last, tt.visFirst, tt.visLast)
+			}
+		})
+	}
+}
+
+func TestLazyGridColumns(t *testing.T) {
+	tests := []struct {
+		name string
+		dir  core.Direction
+		x    []float32 // Of the cells of the first row.
+	}{
+		{"ltr", core.LeftToRight, []float32{0, 110, 220}},
+		{"rtl", core.RightToLeft, []float32{230, 120, 10}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 320 pixels beside the scrollbar fit three columns of at most
+			// 120 with a spacing of 10, each then 100 wide.
+			g := NewLazyGrid(10, builds{}.cell(0, 0)).MaxCellExtent(120).Spacing(10)
+			ctx := core.NewContext()
+			ctx.SetDirection(tt.dir)
+			ctx.LayoutRoot(g, core.R(0, 0, 330, 400))
+			for i, x := range tt.x {
+				if got, want := g.cells[i].Bounds(), core.R(x, 0, 100, 100); got != want {
+					t.Errorf("cell %d at %v, want %v", i, got, want)
+				}
+			}
+			if got, want := g.cells[3].Bounds().Y, float32(110); got != want {
+				t.Errorf("second row at y %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestLazyGridReusesCells(t *testing.T) {
+	b := builds{}
+	g := NewLazyGrid(100, b.cell(0, 0)).Columns(4).Overscan(100)
+	ctx := core.NewContext()
+	bounds := core.R(0, 0, 410, 150)
+	relayout := func() {
+		ctx.Invalidate()
+		ctx.LayoutRoot(g, bounds)
+	}
+	relayout()
+	kept := g.cells[4]
+
+	// Scrolling a little builds the row coming into the overscan and keeps
+	// the others.
+	g.ScrollBy(60)
+	relayout()
+	if g.cells[4] != kept {
+		t.Error("a cell still in the overscan was built again")
+	}
+	for i := range 16 {
+		if b[i] != 1 {
+			t.Errorf("cell %d built %d times, want once", i, b[i])
+		}
+	}
+
+	// Cells scrolled away are released and built again when back.
+	g.SetScrollOffset(1000)
+	relayout()
+	if _, ok := g.cells[4]; ok {
+		t.Error("a cell far out of view was kept")
+	}
+	g.SetScrollOffset(0)
+	relayout()
+	if b[4] != 2 {
+		t.Errorf("cell 4 built %d times after scrolling back, want 2", b[4])
+	}
+}
+
+func TestLazyGridVisibleRangeChange(t *testing.T) {
+	var ranges [][2]int
+	g := NewLazyGrid(100, builds{}.cell(0, 0)).Columns(4).
+		OnVisibleRangeChange(func(first, last int) { ranges = append(ranges, [2]int{first, last}) })
+	ctx := core.NewContext()
+	bounds := core.R(0, 0, 410, 150)
+	ctx.LayoutRoot(g, bounds)
+	ctx.Invalidate()
+	ctx.LayoutRoot(g, bounds)
+	g.ScrollBy(230)
+	ctx.Invalidate()
+	ctx.LayoutRoot(g, bounds)
+	want := [][2]int{{0, 8}, {8, 16}}
+	if len(ranges) != len(want) || ranges[0] != want[0] || ranges[1] != want[1] {
+		t.Errorf("ranges %v, want %v", ranges, want)
+	}
+}
@@ -0,0 +1,542 @@
+package layout
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/internal/scroll"
+)
+
+const (
+	defaultMasonryEstimate float32 = 200
+	masonryLineStep        float32 = 40
+)
+
+// masonryPacker places items of varying height into columns of equal
+// width, each into the column that is currently shortest.
+type masonryPacker struct {
+	columns   int
+	maxExtent float32
+	spacing   float32
+
+	cols    int
+	colW    float32
+	heights []float32 // Bottom of each column, including the trailing spacing.
+}
+
+// reset starts packing into the columns that fit width.
+func (p *masonryPacker) reset(width float32) {
+	p.cols, p.colW = fitColumns(width, p.columns, p.maxExtent, p.spacing)
+	p.heights = append(p.heights[:0], make([]float32, p.cols)...)
+}
+
+// place returns the column and position of the next item, of height h.
+func (p *masonryPacker) place(h float32) (int, core.Rect) {
+	col := 0
+	for i, y := range p.heights {
+		if y < p.heights[col] {
+			col = i
+		}
+	}
+	r := core.R(float32(col)*(p.colW+p.spacing), p.heights[col], p.colW, h)
+	p.heights[col] += h + p.spacing
+	return col, r
+}
+
+// shortest returns the height of the shortest column.
+func (p *masonryPacker) shortest() float32 {
+	h := p.heights[0]
+	for _, y := range p.heights[1:] {
+		h = min(h, y)
+	}
+	return h
+}
+
+// extent returns the height of the tallest column.
+func (p *masonryPacker) extent() float32 {
+	var h float32
+	for _, y := range p.heights {
+		h = max(h, y-p.spacing)
+	}
+	return h
+}
+
+// Masonry packs children of varying height into columns of equal width,
+// Pinterest style: each child goes into the column that is shortest so far,
+// which keeps the column heights close. With MaxColumnExtent the container
+// fits as many columns as it can no wider than the extent; with Columns the
+// column count is fixed. In right-to-left layouts the first column is on
+// the right.
+//
+// Masonry lays out every child; put it in a ScrollView for short feeds and
+// use LazyMasonry for long ones.
+type Masonry struct {
+	core.WidgetBase
+	pack  masonryPacker
+	rects []core.Rect
+	dir   core.Direction
+}
+
+// NewMasonry returns a masonry container of children.
+func NewMasonry(children ...core.Widget) *Masonry {
+	m := &Masonry{pack: masonryPacker{maxExtent: defaultGridExtent}}
+	m.SetChildren(children...)
+	return m
+}
+
+// Add appends children.
+func (m *Masonry) Add(children ...core.Widget) *Masonry {
+	for _, c := range children {
+		m.AppendChild(c)
+	}
+	return m
+}
+
+// Columns fixes the number of columns. Zero restores MaxColumnExtent
+// sizing.
+func (m *Masonry) Columns(n int) *Masonry {
+	m.pack.columns = max(0, n)
+	return m
+}
+
+// MaxColumnExtent sets the largest column width, 120 by default.
+func (m *Masonry) MaxColumnExtent(px float32) *Masonry {
+	if px > 0 {
+		m.pack.maxExtent = px
+	}
+	m.pack.columns = 0
+	return m
+}
+
+// Spacing sets the gap between columns and between the children of a
+// column.
+func (m *Masonry) Spacing(px float32) *Masonry {
+	m.pack.spacing = max(0, px)
+	return m
+}
+
+// masonryWidth returns the width a masonry container takes under c.
+func masonryWidth(c core.Constraints, p *masonryPacker) float32 {
+	if c.HasBoundedWidth() {
+		return c.MaxWidth
+	}
+	n := max(p.columns, 1)
+	return max(c.MinWidth, float32(n)*p.maxExtent+float32(n-1)*p.spacing)
+}
+
+// Layout implements core.Widget.
+func (m *Masonry) Layout(ctx *core.LayoutContext) core.Size {
+	m.dir = ctx.Direction()
+	c := ctx.Constraints
+	width := masonryWidth(c, &m.pack)
+	m.pack.reset(width)
+	colC := core.Constraints{MinWidth: m.pack.colW, MaxWidth: m.pack.colW, MaxHeight: core.Infinity}
+	m.rects = m.rects[:0]
+	for _, child := range m.Children() {
+		_, r := m.pack.place(ctx.Measure(child, colC).Height)
+		m.rects = append(m.rects, r)
+	}
+	return c.Constrain(core.Sz(width, m.pack.extent()))
+}
+
+// SetBounds implements core.Widget.
+func (m *Masonry) SetBounds(r core.Rect) {
+	m.WidgetBase.SetBounds(r)
+	for i, child := range m.Children() {
+		cr := m.rects[i]
+		child.SetBounds(m.dir.Mirror(core.R(r.X+cr.X, r.Y+cr.Y, cr.Width, cr.Height), r))
+	}
+}
+
+// Paint implements core.Widget.
+func (m *Masonry) Paint(ctx *core.PaintContext) {
+	core.PaintChildren(ctx, m.Children())
+}
+
+// LazyMasonry is a scrolling masonry layout of count items that builds only
+// the items intersecting the viewport plus an overscan margin, for long
+// feeds:
+//
+//	feed := layout.NewLazyMasonry(len(posts), func(i int) core.Widget {
+//	    return newCard(posts[i])
+//	}).MaxColumnExtent(240).Spacing(12)
+//
+// Since the position of an item depends on the heights of all the items
+// before it, items are measured in order as the user scrolls down; the
+// heights are remembered, so scrolling back up does not measure again. The
+// content below the packed items is assumed to hold items of the estimated
+// height, so the scroll range converges as the user scrolls.
+//
+// The layout should be given a bounded height; under unbounded constraints
+// it builds every item.
+type LazyMasonry struct {
+	core.WidgetBase
+	core.FocusState
+
+	count    int
+	build    func(index int) core.Widget
+	pack     masonryPacker
+	estimate float32
+	overscan float32
+
+	dir      core.Direction
+	rects    []core.Rect // Positions of the packed items, in order.
+	columns  [][]int     // Packed items of each column, top to bottom.
+	offset   float32
+	viewport core.Size
+	cells    map[int]core.Widget
+	live     []int   // Built items, in order.
+	anchor   int     // Item to keep at the top when repacking, or -1.
+	delta    float32 // Distance from the anchor's top to the viewport top.
+	reveal   int     // Item to reveal at the next layout, or -1.
+	bar      scroll.Bar
+}
+
+// NewLazyMasonry returns a masonry layout of count items built on demand by
+// build.
+func NewLazyMasonry(count int, build func(index int) core.Widget) *LazyMasonry {
+	return &LazyMasonry{
+		count:    max(0, count),
+		build:    build,
+		pack:     masonryPacker{maxExtent: defaultGridExtent},
+		estimate: defaultMasonryEstimate,
+		overscan: defaultGridOverscan,
+		cells:    make(map[int]core.Widget),
+		anchor:   -1,
+		reveal:   -1,
+	}
+}
+
+// Columns fixes the number of columns. Zero restores MaxColumnExtent
+// sizing.
+func (m *LazyMasonry) Columns(n int) *LazyMasonry {
+	m.pack.columns = max(0, n)
+	return m
+}
+
+// MaxColumnExtent sets the largest column width, 120 by default.
+func (m *LazyMasonry) MaxColumnExtent(px float32) *LazyMasonry {
+	if px > 0 {
+		m.pack.maxExtent = px
+	}
+	m.pack.columns = 0
+	return m
+}
+
+// Spacing sets the gap between columns and between the items of a column.
+func (m *LazyMasonry) Spacing(px float32) *LazyMasonry {
+	m.pack.spacing = max(0, px)
+	return m
+}
+
+// EstimatedItemHeight sets the height assumed for the items not measured
+// yet, 200 by default.
+func (m *LazyMasonry) EstimatedItemHeight(h float32) *LazyMasonry {
+	if h > 0 {
+		m.estimate = h
+	}
+	return m
+}
+
+// Overscan sets how far beyond the viewport, above and below, items are
+// built ahead of scrolling.
+func (m *LazyMasonry) Overscan(px float32) *LazyMasonry {
+	m.overscan = max(0, px)
+	return m
+}
+
+// Count returns the number of items.
+func (m *LazyMasonry) Count() int {
+	return m.count
+}
+
+// SetCount changes the number of items. Appending items keeps the packing
+// of the existing ones, as a feed loading more entries needs.
+func (m *LazyMasonry) SetCount(n int) {
+	n = max(0, n)
+	if n < len(m.rects) {
+		m.unpack(n)
+	}
+	for i := range m.cells {
+		if i >= n {
+			delete(m.cells, i)
+		}
+	}
+	m.count = n
+}
+
+// InvalidateItem discards the item at index so that it is built and
+// measured again. The items after it are packed again.
+func (m *LazyMasonry) InvalidateItem(index int) {
+	if index < 0 || index >= m.count {
+		return
+	}
+	delete(m.cells, index)
+	if index < len(m.rects) {
+		m.keepTop()
+		m.unpack(index)
+	}
+}
+
+// Refresh discards every item and measurement.
+func (m *LazyMasonry) Refresh() {
+	clear(m.cells)
+	m.keepTop()
+	m.unpack(0)
+}
+
+// ScrollOffset returns the distance in pixels from the top of the content
+// to the top of the viewport.
+func (m *LazyMasonry) ScrollOffset() float32 {
+	return m.offset
+}
+
+// SetScrollOffset scrolls to the given content offset.
+func (m *LazyMasonry) SetScrollOffset(y float32) {
+	m.reveal, m.anchor = -1, -1
+	m.offset = m.clampOffset(y)
+}
+
+// ScrollBy scrolls by dy pixels and reports whether the offset changed.
+func (m *LazyMasonry) ScrollBy(dy float32) bool {
+	old := m.offset
+	m.SetScrollOffset(m.offset + dy)
+	return m.offset != old
+}
+
+// ScrollToIndex scrolls the minimum distance that makes the item at index
+// fully visible. The request is resolved during the next layout, once the
+// items up to it are packed.
+func (m *LazyMasonry) ScrollToIndex(index int) {
+	if m.count > 0 {
+		m.reveal = min(max(index, 0), m.count-1)
+	}
+}
+
+// VisibleRange returns the lowest and one past the highest index of the
+// items intersecting the viewport after the last layout.
+func (m *LazyMasonry) VisibleRange() (first, last int) {
+	first, last = m.count, 0
+	for _, i := range m.live {
+		r := m.rects[i]
+		if r.Y < m.offset+m.viewport.Height && r.Bottom() > m.offset {
+			first, last = min(first, i), max(last, i+1)
+		}
+	}
+	if last == 0 {
+		return 0, 0
+	}
+	return first, last
+}
+
+// total returns the height of the content, estimating the part below the
+// packed items.
+func (m *LazyMasonry) total() float32 {
+	if len(m.rects) == 0 && m.count == 0 {
+		return 0
+	}
+	total := m.pack.extent()
+	if rest := m.count - len(m.rects); rest > 0 && m.pack.cols > 0 {
+		rows := float32(rest+m.pack.cols-1) / float32(m.pack.cols)
+		total = max(total, m.pack.shortest()+rows*(m.estimate+m.pack.spacing)-m.pack.spacing)
+	}
+	return total
+}
+
+func (m *LazyMasonry) clampOffset(y float32) float32 {
+	return core.Clamp(y, 0, max(0, m.total()-m.viewport.Height))
+}
+
+// keepTop remembers the item at the top of the viewport so that it stays
+// there once the items are packed again.
+func (m *LazyMasonry) keepTop() {
+	if m.anchor >= 0 {
+		return
+	}
+	for _, i := range m.live {
+		if r := m.rects[i]; r.Bottom() > m.offset {
+			m.anchor, m.delta = i, m.offset-r.Y
+			return
+		}
+	}
+}
+
+// unpack forgets the positions of the items from index n on.
+func (m *LazyMasonry) unpack(n int) {
+	m.rects = m.rects[:n]
+	m.live = slices.DeleteFunc(m.live, func(i int) bool { return i >= n })
+	for c, col := range m.columns {
+		col = col[:sort.SearchInts(col, n)]
+		m.columns[c] = col
+		m.pack.heights[c] = 0
+		if len(col) > 0 {
+			m.pack.heights[c] = m.rects[col[len(col)-1]].Bottom() + m.pack.spacing
+		}
+	}
+}
+
+// packTo measures and packs items in order until every column reaches
+// bottom or the item at index need is packed.
+func (m *LazyMasonry) packTo(ctx *core.LayoutContext, bottom float32, need int) {
+	colC := core.Constraints{MinWidth: m.pack.colW, MaxWidth: m.pack.colW, MaxHeight: core.Infinity}
+	for i := len(m.rects); i < m.count && (m.pack.shortest() < bottom || i <= need); i++ {
+		w, ok := m.cells[i]
+		if !ok {
+			w = m.build(i)
+			m.cells[i] = w
+		}
+		col, r := m.pack.place(ctx.Measure(w, colC).Height)
+		m.rects = append(m.rects, r)
+		m.columns[col] = append(m.columns[col], i)
+	}
+}
+
+// Layout implements core.Widget.
+func (m *LazyMasonry) Layout(ctx *core.LayoutContext) core.Size {
+	m.dir = ctx.Direction()
+	c := ctx.Constraints
+	width := masonryWidth(c, &m.pack) + scroll.Thickness
+	if c.HasBoundedWidth() {
+		width = c.MaxWidth
+	}
+	inner := max(0, width-scroll.Thickness)
+	if cols, colW := fitColumns(inner, m.pack.columns, m.pack.maxExtent, m.pack.spacing); cols != m.pack.cols || colW != m.pack.colW {
+		// Wrapped content changes height with width; pack from scratch.
+		m.keepTop()
+		m.rects, m.live = m.rects[:0], m.live[:0]
+		m.pack.reset(inner)
+		m.columns = make([][]int, m.pack.cols)
+	}
+	height := m.total()
+	if c.HasBoundedHeight() {
+		height = c.MaxHeight
+	}
+	m.viewport = c.Constrain(core.Sz(width, height))
+	if !c.HasBoundedHeight() {
+		m.packTo(ctx, core.Infinity, m.count-1)
+		m.viewport.Height = c.Constrain(core.Sz(width, m.total())).Height
+	}
+
+	if m.anchor >= 0 {
+		m.packTo(ctx, 0, m.anchor)
+		if m.anchor < len(m.rects) {
+			m.offset = m.rects[m.anchor].Y + min(m.delta, m.rects[m.anchor].Height)
+		}
+		m.anchor = -1
+	}
+	if m.reveal >= 0 && m.reveal < m.count {
+		m.packTo(ctx, 0, m.reveal)
+		r := m.rects[m.reveal]
+		if r.Y < m.offset {
+			m.offset = r.Y
+		} else if r.Bottom() > m.offset+m.viewport.Height {
+			m.offset = r.Bottom() - m.viewport.Height
+		}
+		m.reveal = -1
+	}
+	m.packTo(ctx, m.offset+m.viewport.Height+m.overscan, -1)
+	m.offset = m.clampOffset(m.offset)
+	m.realize(ctx)
+	return m.viewport
+}
+
+// realize builds the packed items intersecting the viewport and overscan,
+// releasing the others.
+func (m *LazyMasonry) realize(ctx *core.LayoutContext) {
+	top, bottom := m.offset-m.overscan, m.offset+m.viewport.Height+m.overscan
+	m.live = m.live[:0]
+	for _, col := range m.columns {
+		k := sort.Search(len(col), func(k int) bool { return m.rects[col[k]].Bottom() > top })
+		for ; k < len(col) && m.rects[col[k]].Y < bottom; k++ {
+			m.live = append(m.live, col[k])
+		}
+	}
+	sort.Ints(m.live)
+	built := make(map[int]core.Widget, len(m.live))
+	children := make([]core.Widget, len(m.live))
+	for k, i := range m.live {
+		w, ok := m.cells[i]
+		if !ok {
+			w = m.build(i)
+		}
+		built[i] = w
+		ctx.Measure(w, core.Tight(m.rects[i].Size()))
+		children[k] = w
+	}
+	m.cells = built
+	m.SetChildren(children...)
+}
+
+// SetBounds implements core.Widget and positions the built items.
+func (m *LazyMasonry) SetBounds(r core.Rect) {
+	m.WidgetBase.SetBounds(r)
+	track, area := scroll.EndTrack(r, m.dir)
+	for _, i := range m.live {
+		ir := m.rects[i]
+		m.cells[i].SetBounds(m.dir.Mirror(core.R(area.X+ir.X, r.Y+ir.Y-m.offset, ir.Width, ir.Height), area))
+	}
+	m.bar.Track = track
+	m.bar.Viewport = r.Height
+	m.bar.Content = m.total()
+}
+
+// Paint implements core.Widget.
+func (m *LazyMasonry) Paint(ctx *core.PaintContext) {
+	ctx.Canvas.Save()
+	ctx.Canvas.Clip(m.Bounds())
+	core.PaintChildren(ctx, m.Children())
+	m.bar.Paint(ctx, m.offset)
+	ctx.Canvas.Restore()
+}
+
+// HandleEvent implements core.Widget. It scrolls on wheel input, on
+// scrollbar drags and, while focused, on arrow, page, Home and End keys.
+func (m *LazyMasonry) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	if off, ok := m.bar.HandleEvent(ctx, m, ev, m.offset); ok {
+		if off != m.offset {
+			m.SetScrollOffset(off)
+			ctx.MarkNeedsLayout(m)
+		}
+		return core.Handled
+	}
+	switch e := ev.(type) {
+	case event.ScrollEvent:
+		if m.ScrollBy(e.Delta.Y) {
+			ctx.MarkNeedsLayout(m)
+			return core.Handled
+		}
+	case event.MouseEvent:
+		if e.Type == event.MouseDown {
+			ctx.RequestFocus(m)
+		}
+	case event.KeyEvent:
+		if e.Type != event.KeyPress || !m.IsFocused() {
+			return core.Ignored
+		}
+		var changed bool
+		switch e.Key {
+		case event.KeyUp:
+			changed = m.ScrollBy(-masonryLineStep)
+		case event.KeyDown:
+			changed = m.ScrollBy(masonryLineStep)
+		case event.KeyPageUp:
+			changed = m.ScrollBy(-m.viewport.Height)
+		case event.KeyPageDown:
+			changed = m.ScrollBy(m.viewport.Height)
+		case event.KeyHome:
+			changed = m.ScrollBy(-m.offset)
+		case event.KeyEnd:
+			m.ScrollToIndex(m.count - 1)
+			changed = true
+		default:
+			return core.Ignored
+		}
+		if changed {
+			ctx.MarkNeedsLayout(m)
+		}
+		return core.Handled
+	}
+	return core.Ignored
+}
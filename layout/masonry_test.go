@@ -0,0 +1,142 @@
+package layout
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+)
+
+func TestMasonryPlacesChildren(t *testing.T) {
+	tests := []struct {
+		name  string
+		dir   core.Direction
+		rects []core.Rect
+	}{
+		// Each child goes into the column shortest so far: the fourth into
+		// the second column and the fifth into the third.
+		{"ltr", core.LeftToRight, []core.Rect{
+			core.R(0, 0, 100, 100), core.R(110, 0, 100, 50), core.R(220, 0, 100, 80),
+			core.R(110, 60, 100, 30), core.R(220, 90, 100, 60),
+		}},
+		{"rtl", core.RightToLeft, []core.Rect{
+			core.R(220, 0, 100, 100), core.R(110, 0, 100, 50), core.R(0, 0, 100, 80),
+			core.R(110, 60, 100, 30), core.R(0, 90, 100, 60),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var kids []core.Widget
+			for _, h := range []float32{100, 50, 80, 30, 60} {
+				kids = append(kids, newBox(100, h))
+			}
+			m := NewMasonry(kids...).Columns(3).Spacing(10)
+			layoutIn(m, core.R(0, 0, 320, 400), tt.dir)
+			for i, want := range tt.rects {
+				if got := kids[i].Bounds(); got != want {
+					t.Errorf("child %d at %v, want %v", i, got, want)
+				}
+			}
+			if got, want := m.Bounds().Size(), core.Sz(320, 150); got != want {
+				t.Errorf("size = %v, want the tallest column %v", got, want)
+			}
+		})
+	}
+}
+
+func TestMasonryMaxColumnExtent(t *testing.T) {
+	kids := []core.Widget{newBox(0, 10), newBox(0, 10), newBox(0, 10)}
+	m := NewMasonry(kids...).MaxColumnExtent(120).Spacing(10)
+	layoutIn(m, core.R(0, 0, 320, 400), core.LeftToRight)
+	for i, x := range []float32{0, 110, 220} {
+		if got, want := kids[i].Bounds(), core.R(x, 0, 100, 10); got != want {
+			t.Errorf("child %d at %v, want %v", i, got, want)
+		}
+	}
+}
+
+// masonryItem returns a builder of items 50, 80 and 110 high in turn.
+func (b builds) masonryItem() func(int) core.Widget {
+	return func(i int) core.Widget {
+		b[i]++
+		return newBox(0, 50+30*float32(i%3))
+	}
+}
+
+func TestLazyMasonryBuildsViewport(t *testing.T) {
+	b := builds{}
+	m := NewLazyMasonry(100, b.masonryItem()).Columns(2).Overscan(0)
+	ctx := core.NewContext()
+	bounds := core.R(0, 0, 200+10, 200)
+	relayout := func() {
+		ctx.Invalidate()
+		ctx.LayoutRoot(m, bounds)
+	}
+	relayout()
+
+	// Items are packed until both columns reach the bottom of the view.
+	want := []core.Rect{
+		core.R(0, 0, 100, 50), core.R(100, 0, 100, 80), core.R(0, 50, 100, 110),
+		core.R(100, 80, 100, 50), core.R(100, 130, 100, 80), core.R(0, 160, 100, 110),
+	}
+	if got := len(m.rects); got != len(want) {
+		t.Fatalf("%d items packed, want %d", got, len(want))
+	}
+	for i, r := range want {
+		if got := m.cells[i].Bounds(); got != r {
+			t.Errorf("item %d at %v, want %v", i, got, r)
+		}
+	}
+	if first, last := m.VisibleRange(); first != 0 || last != 6 {
+		t.Errorf("VisibleRange() = %d, %d, want 0, 6", first, last)
+	}
+
+	// Scrolling down packs more and releases the items out of view.
+	m.SetScrollOffset(1000)
+	relayout()
+	packed := len(m.rects)
+	first, last := m.VisibleRange()
+	if first == 0 || last <= first {
+		t.Fatalf("VisibleRange() = %d, %d after scrolling", first, last)
+	}
+	if _, ok := m.cells[0]; ok {
+		t.Error("an item out of view was kept")
+	}
+	for i := range m.cells {
+		if r := m.rects[i]; r.Bottom() <= 1000 || r.Y >= 1200 {
+			t.Errorf("item %d at %v is built out of view", i, r)
+		}
+	}
+
+	// Scrolling back keeps the packing, building the items again.
+	m.SetScrollOffset(0)
+	relayout()
+	if got := len(m.rects); got != packed {
+		t.Errorf("%d items packed after scrolling back, want %d", got, packed)
+	}
+	if got := m.cells[5].Bounds(); got != want[5] {
+		t.Errorf("item 5 at %v after scrolling back, want %v", got, want[5])
+	}
+	if b[0] != 2 {
+		t.Errorf("item 0 built %d times, want 2", b[0])
+	}
+}
+
+func TestLazyMasonryInvalidateItem(t *testing.T) {
+	b := builds{}
+	m := NewLazyMasonry(20, b.masonryItem()).Columns(2).Overscan(0)
+	ctx := core.NewContext()
+	bounds := core.R(0, 0, 210, 200)
+	ctx.LayoutRoot(m, bounds)
+	m.InvalidateItem(2)
+	if got := len(m.rects); got != 2 {
+		t.Fatalf("%d items packed after invalidating item 2, want 2", got)
+	}
+	ctx.Invalidate()
+	ctx.LayoutRoot(m, bounds)
+	if b[2] != 2 || b[1] != 1 {
+		t.Errorf("items 1 and 2 built %d and %d times, want 1 and 2", b[1], b[2])
+	}
+	if got, want := m.cells[2].Bounds(), core.R(0, 50, 100, 110); got != want {
+		t.Errorf("item 2 at %v, want %v", got, want)
+	}
+}
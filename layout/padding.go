@@ -0,0 +1,84 @@
+package layout
+
+import "github.com/gogpu/ui/core"
+
+// Padding surrounds its child with empty space. The space is given either
+// by side, or by reading direction with NewDirectionalPadding, whose start
+// space is on the left in left-to-right text and on the right in
+// right-to-left text.
+type Padding struct {
+	core.WidgetBase
+	child core.Widget
+
+	insets      core.Insets
+	directional *core.DirectionalInsets
+	dir         core.Direction
+}
+
+// NewPadding returns child surrounded by insets.
+func NewPadding(insets core.Insets, child core.Widget) *Padding {
+	p := &Padding{child: child, insets: insets}
+	p.SetChildren(child)
+	return p
+}
+
+// NewDirectionalPadding returns child surrounded by insets whose start and
+// end sides follow the reading direction.
+func NewDirectionalPadding(insets core.DirectionalInsets, child core.Widget) *Padding {
+	p := &Padding{child: child, directional: &insets}
+	p.SetChildren(child)
+	return p
+}
+
+// Insets returns the space around the child as of the last layout, with
+// directional insets resolved.
+func (p *Padding) Insets() core.Insets {
+	return p.resolve(p.dir)
+}
+
+// resolve returns the space around the child in direction dir.
+func (p *Padding) resolve(dir core.Direction) core.Insets {
+	if p.directional != nil {
+		return p.directional.Resolve(dir)
+	}
+	return p.insets
+}
+
+// Layout implements core.Widget.
+func (p *Padding) Layout(ctx *core.LayoutContext) core.Size {
+	p.dir = ctx.Direction()
+	in := p.resolve(p.dir)
+	size := ctx.Measure(p.child, ctx.Constraints.Deflate(in))
+	return ctx.Constraints.Constrain(core.Sz(size.Width+in.Horizontal(), size.Height+in.Vertical()))
+}
+
+// IntrinsicWidth implements core.IntrinsicSizer.
+func (p *Padding) IntrinsicWidth(ctx *core.LayoutContext, height float32) (minWidth, maxWidth float32) {
+	in := p.resolve(ctx.Direction())
+	lo, hi := ctx.IntrinsicWidth(p.child, max(0, height-in.Vertical()))
+	return lo + in.Horizontal(), hi + in.Horizontal()
+}
+
+// IntrinsicHeight implements core.IntrinsicSizer.
+func (p *Padding) IntrinsicHeight(ctx *core.LayoutContext, width float32) (minHeight, maxHeight float32) {
+	in := p.resolve(ctx.Direction())
+	lo, hi := ctx.IntrinsicHeight(p.child, max(0, width-in.Horizontal()))
+	return lo + in.Vertical(), hi + in.Vertical()
+}
+
+// Baseline implements core.Baseliner with the child's baseline.
+func (p *Padding) Baseline() (float32, bool) {
+	b, ok := core.BaselineOf(p.child)
+	return b + p.Insets().Top, ok
+}
+
+// SetBounds implements core.Widget.
+func (p *Padding) SetBounds(r core.Rect) {
+	p.WidgetBase.SetBounds(r)
+	p.child.SetBounds(r.Inset(p.Insets()))
+}
+
+// Paint implements core.Widget.
+func (p *Padding) Paint(ctx *core.PaintContext) {
+	p.child.Paint(ctx)
+}
@@ -0,0 +1,56 @@
+package layout
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+)
+
+func TestPadding(t *testing.T) {
+	tests := []struct {
+		name string
+		pad  func(child core.Widget) *Padding
+		dir  core.Direction
+		want core.Rect // The bounds of the child.
+	}{
+		{"by side", func(c core.Widget) *Padding { return NewPadding(core.Insets{Top: 1, Right: 2, Bottom: 3, Left: 4}, c) },
+			core.LeftToRight, core.R(14, 11, 20, 10)},
+		{"by side, right to left", func(c core.Widget) *Padding { return NewPadding(core.Insets{Top: 1, Right: 2, Bottom: 3, Left: 4}, c) },
+			core.RightToLeft, core.R(14, 11, 20, 10)},
+		{"start", func(c core.Widget) *Padding {
+			return NewDirectionalPadding(core.DirectionalInsets{Top: 1, End: 2, Bottom: 3, Start: 4}, c)
+		},
+			core.LeftToRight, core.R(14, 11, 20, 10)},
+		{"start, right to left", func(c core.Widget) *Padding {
+			return NewDirectionalPadding(core.DirectionalInsets{Top: 1, End: 2, Bottom: 3, Start: 4}, c)
+		},
+			core.RightToLeft, core.R(12, 11, 20, 10)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			child := text(20, 10, 8)
+			p := tt.pad(child)
+			layoutIn(p, core.R(10, 10, 100, 100), tt.dir)
+			if got := p.Bounds(); got != core.R(10, 10, 26, 14) {
+				t.Errorf("bounds = %v, want (10, 10, 26, 14)", got)
+			}
+			if got := child.Bounds(); got != tt.want {
+				t.Errorf("child bounds = %v, want %v", got, tt.want)
+			}
+			if b, ok := p.Baseline(); !ok || b != 9 {
+				t.Errorf("Baseline = %v, %v, want 9", b, ok)
+			}
+		})
+	}
+}
+
+func TestPaddingIntrinsic(t *testing.T) {
+	p := NewDirectionalPadding(core.DirectionalInsets{Top: 1, End: 2, Bottom: 3, Start: 4}, newBox(20, 10))
+	lc := &core.LayoutContext{Context: core.NewContext()}
+	if lo, hi := lc.IntrinsicWidth(p, core.Infinity); lo != 26 || hi != 26 {
+		t.Errorf("IntrinsicWidth = %v, %v, want 26", lo, hi)
+	}
+	if lo, hi := lc.IntrinsicHeight(p, core.Infinity); lo != 14 || hi != 14 {
+		t.Errorf("IntrinsicHeight = %v, %v, want 14", lo, hi)
+	}
+}
@@ -0,0 +1,208 @@
+package layout
+
+import (
+	"slices"
+
+	"github.com/gogpu/ui/core"
+)
+
+// Placement is where a Positioned container puts a child: the child's
+// pivot goes to the container's anchor, moved by offset. Anchor and
+// pivot are fractions of the container's and the child's size, so the
+// zero Placement puts the child's top left corner at the container's, and
+// an anchor and pivot of (0.5, 0.5) center it.
+type Placement struct {
+	Anchor core.Point
+	Pivot  core.Point
+	Offset core.Point
+
+	// Size fixes the size of the child on the axes where it is positive;
+	// elsewhere the child takes its natural size.
+	Size core.Size
+
+	// Z orders children front to back: higher values are painted above
+	// and hit first. Among equal values the child added last is on top.
+	Z int
+}
+
+// At returns a placement with the child's top left corner at x, y.
+func At(x, y float32) Placement {
+	return Placement{Offset: core.Pt(x, y)}
+}
+
+// Anchored returns a placement with the child's point at fractions px, py
+// of its size put at fractions ax, ay of the container's size. Anchored(1,
+// 1, 1, 1) docks a child in the bottom right corner.
+func Anchored(ax, ay, px, py float32) Placement {
+	return Placement{Anchor: core.Pt(ax, ay), Pivot: core.Pt(px, py)}
+}
+
+// Moved returns p with its offset moved by dx, dy.
+func (p Placement) Moved(dx, dy float32) Placement {
+	p.Offset = p.Offset.Add(core.Pt(dx, dy))
+	return p
+}
+
+// Sized returns p with a fixed size.
+func (p Placement) Sized(w, h float32) Placement {
+	p.Size = core.Sz(w, h)
+	return p
+}
+
+// Above returns p with stacking order z.
+func (p Placement) Above(z int) Placement {
+	p.Z = z
+	return p
+}
+
+type positionedItem struct {
+	content core.Widget
+	place   Placement
+	size    core.Size
+}
+
+// Positioned places children at absolute coordinates or at fractions of
+// its size, with an explicit stacking order, for node editors, canvas
+// applications and floating panels:
+//
+//	board := layout.NewPositioned().
+//		Add(node, layout.At(120, 80)).
+//		Add(minimap, layout.Anchored(1, 1, 1, 1).Moved(-16, -16))
+//
+// Positions are in pixels from the container's top left corner and are
+// not mirrored in right-to-left layouts. The container fills the space it
+// is given; on an unbounded axis it grows to contain the children placed
+// from its top left corner, so a board inside a ScrollView scrolls to show
+// every node.
+type Positioned struct {
+	core.WidgetBase
+
+	items []positionedItem
+}
+
+// NewPositioned returns an empty positioning container.
+func NewPositioned() *Positioned {
+	return &Positioned{}
+}
+
+// Add places child on top of the children with the same stacking order.
+// Adding a child again changes its placement.
+func (p *Positioned) Add(child core.Widget, place Placement) *Positioned {
+	if i := p.index(child); i >= 0 {
+		p.items = slices.Delete(p.items, i, i+1)
+	}
+	p.items = append(p.items, positionedItem{content: child, place: place})
+	p.sync()
+	return p
+}
+
+// Placement returns the placement of child, and false if it was not added.
+func (p *Positioned) Placement(child core.Widget) (Placement, bool) {
+	if i := p.index(child); i >= 0 {
+		return p.items[i].place, true
+	}
+	return Placement{}, false
+}
+
+// Place changes the placement of child, keeping its place among the
+// children with the same stacking order.
+func (p *Positioned) Place(child core.Widget, place Placement) {
+	if i := p.index(child); i >= 0 {
+		p.items[i].place = place
+		p.sync()
+	}
+}
+
+// Move changes the offset of child, as when dragging a node.
+func (p *Positioned) Move(child core.Widget, offset core.Point) {
+	if i := p.index(child); i >= 0 {
+		p.items[i].place.Offset = offset
+	}
+}
+
+// Raise brings child above every other child, as when a floating panel
+// is clicked.
+func (p *Positioned) Raise(child core.Widget) {
+	i := p.index(child)
+	if i < 0 {
+		return
+	}
+	it := p.items[i]
+	for _, o := range p.items {
+		if o.content != child {
+			it.place.Z = max(it.place.Z, o.place.Z)
+		}
+	}
+	p.Add(child, it.place)
+}
+
+// Remove removes child.
+func (p *Positioned) Remove(child core.Widget) {
+	if i := p.index(child); i >= 0 {
+		p.items = slices.Delete(p.items, i, i+1)
+		p.sync()
+	}
+}
+
+func (p *Positioned) index(child core.Widget) int {
+	return slices.IndexFunc(p.items, func(it positionedItem) bool { return it.content == child })
+}
+
+// sync sorts the items back to front and sets the children in that order,
+// which is the order core.HitTest walks in reverse.
+func (p *Positioned) sync() {
+	slices.SortStableFunc(p.items, func(a, b positionedItem) int { return a.place.Z - b.place.Z })
+	children := make([]core.Widget, len(p.items))
+	for i, it := range p.items {
+		children[i] = it.content
+	}
+	p.SetChildren(children...)
+}
+
+// Layout implements core.Widget.
+func (p *Positioned) Layout(ctx *core.LayoutContext) core.Size {
+	c := ctx.Constraints
+	var extent core.Size
+	for i := range p.items {
+		it := &p.items[i]
+		cc := core.Constraints{MaxWidth: core.Infinity, MaxHeight: core.Infinity}
+		if w := it.place.Size.Width; w > 0 {
+			cc.MinWidth, cc.MaxWidth = w, w
+		}
+		if h := it.place.Size.Height; h > 0 {
+			cc.MinHeight, cc.MaxHeight = h, h
+		}
+		it.size = ctx.Measure(it.content, cc)
+		pl := it.place
+		if pl.Anchor.X == 0 {
+			extent.Width = max(extent.Width, pl.Offset.X+it.size.Width*(1-pl.Pivot.X))
+		}
+		if pl.Anchor.Y == 0 {
+			extent.Height = max(extent.Height, pl.Offset.Y+it.size.Height*(1-pl.Pivot.Y))
+		}
+	}
+	size := c.Constrain(extent)
+	if c.HasBoundedWidth() {
+		size.Width = c.MaxWidth
+	}
+	if c.HasBoundedHeight() {
+		size.Height = c.MaxHeight
+	}
+	return size
+}
+
+// SetBounds implements core.Widget.
+func (p *Positioned) SetBounds(r core.Rect) {
+	p.WidgetBase.SetBounds(r)
+	for _, it := range p.items {
+		pl, sz := it.place, it.size
+		x := r.X + pl.Anchor.X*r.Width + pl.Offset.X - pl.Pivot.X*sz.Width
+		y := r.Y + pl.Anchor.Y*r.Height + pl.Offset.Y - pl.Pivot.Y*sz.Height
+		it.content.SetBounds(core.R(x, y, sz.Width, sz.Height))
+	}
+}
+
+// Paint implements core.Widget. Children are painted back to front.
+func (p *Positioned) Paint(ctx *core.PaintContext) {
+	core.PaintChildren(ctx, p.Children())
+}
@@ -0,0 +1,103 @@
+package layout
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+)
+
+func TestPositionedPlacement(t *testing.T) {
+	tests := []struct {
+		name  string
+		place Placement
+		want  core.Rect
+	}{
+		{"zero", Placement{}, core.R(0, 0, 20, 10)},
+		{"at", At(30, 40), core.R(30, 40, 20, 10)},
+		{"centered", Anchored(0.5, 0.5, 0.5, 0.5), core.R(90, 45, 20, 10)},
+		{"bottom right", Anchored(1, 1, 1, 1).Moved(-16, -16), core.R(164, 74, 20, 10)},
+		{"pivot alone", Placement{Pivot: core.Pt(1, 0)}.Moved(50, 0), core.R(30, 0, 20, 10)},
+		{"sized", At(5, 5).Sized(60, 30), core.R(5, 5, 60, 30)},
+		{"sized on one axis", At(5, 5).Sized(0, 30), core.R(5, 5, 20, 30)},
+		{"outside", At(190, 95), core.R(190, 95, 20, 10)},
+	}
+	for _, dir := range []core.Direction{core.LeftToRight, core.RightToLeft} {
+		for _, tt := range tests {
+			child := newBox(20, 10)
+			p := NewPositioned().Add(child, tt.place)
+			layoutIn(p, core.R(0, 0, 200, 100), dir)
+			if got := p.Bounds(); got != core.R(0, 0, 200, 100) {
+				t.Errorf("%s: container at %v, want filling its space", tt.name, got)
+			}
+			if got := child.Bounds(); got != tt.want {
+				t.Errorf("%s, %v: child at %v, want %v", tt.name, dir, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestPositionedUnbounded(t *testing.T) {
+	// On an unbounded axis the container grows to hold the children placed
+	// from its top left corner, and not those anchored elsewhere.
+	p := NewPositioned().
+		Add(newBox(20, 10), At(100, 40)).
+		Add(newBox(40, 40), Placement{Pivot: core.Pt(0.5, 0.5)}.Moved(150, 10)).
+		Add(newBox(500, 500), Anchored(1, 1, 1, 1))
+	lc := &core.LayoutContext{Context: core.NewContext()}
+	if got := lc.Measure(p, unbounded(300)); got != core.Sz(170, 300) {
+		t.Errorf("size = %v, want the width of the nodes from the corner", got)
+	}
+	lc.Constraints = core.Constraints{MaxWidth: core.Infinity, MaxHeight: core.Infinity}
+	if got := lc.Measure(p, lc.Constraints); got != core.Sz(170, 50) {
+		t.Errorf("size = %v unbounded, want the extent of the nodes from the corner", got)
+	}
+}
+
+func TestPositionedOrder(t *testing.T) {
+	a, b, c := newBox(50, 50), newBox(50, 50), newBox(50, 50)
+	p := NewPositioned().Add(a, At(0, 0).Above(1)).Add(b, At(10, 10)).Add(c, At(20, 20))
+	order := func(want ...core.Widget) {
+		t.Helper()
+		if got := p.Children(); !slices.Equal(got, want) {
+			t.Errorf("children %v, want %v back to front", got, want)
+		}
+	}
+	order(b, c, a)
+	ctx := layoutIn(p, core.R(0, 0, 200, 200), core.LeftToRight)
+	if path := core.HitTest(p, core.Pt(30, 30)); path[len(path)-1] != a {
+		t.Errorf("hit %v, want the child above", path[len(path)-1])
+	}
+
+	// Raised, a child goes above all the others; placed again, it keeps
+	// its place among those of its order.
+	p.Raise(b)
+	order(c, a, b)
+	if pl, ok := p.Placement(b); !ok || pl.Z != 1 {
+		t.Errorf("raised to %v, want the highest order", pl.Z)
+	}
+	p.Place(a, At(5, 5).Above(1))
+	order(c, a, b)
+	p.Place(c, At(20, 20).Above(2))
+	order(a, b, c)
+
+	// Moved, a child keeps its order and goes to its offset once laid out.
+	p.Move(b, core.Pt(100, 120))
+	ctx.Invalidate()
+	ctx.LayoutRoot(p, core.R(0, 0, 200, 200))
+	if got := b.Bounds(); got != core.R(100, 120, 50, 50) {
+		t.Errorf("moved to %v", got)
+	}
+	order(a, b, c)
+	if pl, _ := p.Placement(b); pl.Offset != core.Pt(100, 120) || pl.Z != 1 {
+		t.Errorf("placement %v after a move", pl)
+	}
+
+	p.Remove(a)
+	order(b, c)
+	if _, ok := p.Placement(a); ok {
+		t.Error("a removed child has a placement")
+	}
+	p.Raise(a)
+	order(b, c)
+}
@@ -0,0 +1,132 @@
+package layout
+
+import "github.com/gogpu/ui/core"
+
+// Responsive shows one of several layouts of the same content depending on
+// the width it is given: compact, medium or expanded.
+//
+// A layout missing for a size class falls back to the one for the next
+// smaller class, so NewResponsive(single).Expanded(split) switches only
+// between two. Only the shown layout is a child; the others keep their
+// state while hidden, so the same widgets can be shared between them.
+//
+// While its layout is laid out and painted, Responsive sets the size class
+// of the context, which descendants read with core.Context.SizeClass to
+// adapt in the same way.
+type Responsive struct {
+	core.WidgetBase
+
+	layouts  [3]core.Widget
+	medium   float32
+	expanded float32
+	class    core.SizeClass
+	active   core.Widget
+	onChange func(core.SizeClass)
+}
+
+// NewResponsive returns a container showing compact at every width until
+// layouts for wider classes are set.
+func NewResponsive(compact core.Widget) *Responsive {
+	r := &Responsive{medium: core.MediumBreakpoint, expanded: core.ExpandedBreakpoint}
+	r.layouts[core.SizeCompact] = compact
+	r.active = compact
+	if compact != nil {
+		r.SetChildren(compact)
+	}
+	return r
+}
+
+// Medium sets the layout shown from the medium breakpoint.
+func (r *Responsive) Medium(w core.Widget) *Responsive {
+	r.layouts[core.SizeMedium] = w
+	return r
+}
+
+// Expanded sets the layout shown from the expanded breakpoint.
+func (r *Responsive) Expanded(w core.Widget) *Responsive {
+	r.layouts[core.SizeExpanded] = w
+	return r
+}
+
+// Breakpoints sets the widths at which the medium and expanded classes
+// start, by default core.MediumBreakpoint and core.ExpandedBreakpoint.
+func (r *Responsive) Breakpoints(medium, expanded float32) *Responsive {
+	r.medium, r.expanded = medium, max(medium, expanded)
+	return r
+}
+
+// OnChange registers fn to be called when the size class changes.
+func (r *Responsive) OnChange(fn func(core.SizeClass)) *Responsive {
+	r.onChange = fn
+	return r
+}
+
+// Class returns the size class of the last layout.
+func (r *Responsive) Class() core.SizeClass {
+	return r.class
+}
+
+func (r *Responsive) classify(width float32) core.SizeClass {
+	switch {
+	case width >= r.expanded:
+		return core.SizeExpanded
+	case width >= r.medium:
+		return core.SizeMedium
+	}
+	return core.SizeCompact
+}
+
+// layoutFor returns the layout for class, falling back to smaller ones.
+func (r *Responsive) layoutFor(class core.SizeClass) core.Widget {
+	for c := int(class); c >= 0; c-- {
+		if r.layouts[c] != nil {
+			return r.layouts[c]
+		}
+	}
+	return nil
+}
+
+// Layout implements core.Widget.
+func (r *Responsive) Layout(ctx *core.LayoutContext) core.Size {
+	class := r.classify(ctx.Constraints.MaxWidth)
+	if class != r.class {
+		r.class = class
+		if r.onChange != nil {
+			r.onChange(class)
+		}
+	}
+	if w := r.layoutFor(class); w != r.active {
+		r.active = w
+		if w != nil {
+			r.SetChildren(w)
+		} else {
+			r.SetChildren()
+		}
+	}
+	if r.active == nil {
+		return ctx.Constraints.Constrain(core.Size{})
+	}
+	prev := ctx.SizeClass()
+	ctx.SetSizeClass(class)
+	defer ctx.SetSizeClass(prev)
+	return ctx.Measure(r.active, ctx.Constraints)
+}
+
+// SetBounds implements core.Widget.
+func (r *Responsive) SetBounds(b core.Rect) {
+	r.WidgetBase.SetBounds(b)
+	if r.active != nil {
+		r.active.SetBounds(b)
+	}
+}
+
+// Paint implements core.Widget.
+func (r *Responsive) Paint(ctx *core.PaintContext) {
+	if r.active == nil {
+		return
+	}
+	prev := ctx.SizeClass()
+	ctx.SetSizeClass(r.class)
+	defer ctx.SetSizeClass(prev)
+	r.active.Paint(ctx)
+}
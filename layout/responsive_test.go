@@ -0,0 +1,169 @@
+package layout
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+)
+
+// probe fills the space it is given and records the size class it sees
+// when laid out and painted.
+type probe struct {
+	core.WidgetBase
+	name    string
+	laidOut []core.SizeClass
+	painted []core.SizeClass
+}
+
+func (p *probe) Layout(ctx *core.LayoutContext) core.Size {
+	p.laidOut = append(p.laidOut, ctx.SizeClass())
+	c := ctx.Constraints
+	return c.Constrain(core.Sz(c.MaxWidth, c.MaxHeight))
+}
+
+func (p *probe) Paint(ctx *core.PaintContext) {
+	p.painted = append(p.painted, ctx.SizeClass())
+}
+
+func TestResponsiveShowsLayout(t *testing.T) {
+	tests := []struct {
+		name   string
+		layout func(compact, medium, expanded core.Widget) *Responsive
+		widths []float32
+		want   []string
+	}{
+		{
+			name: "three layouts",
+			layout: func(c, m, e core.Widget) *Responsive {
+				return NewResponsive(c).Medium(m).Expanded(e)
+			},
+			widths: []float32{300, 600, 839, 840, 1200, 599},
+			want:   []string{"compact", "medium", "medium", "expanded", "expanded", "compact"},
+		},
+		{
+			name:   "expanded only",
+			layout: func(c, m, e core.Widget) *Responsive { return NewResponsive(c).Expanded(e) },
+			widths: []float32{300, 700, 900},
+			want:   []string{"compact", "compact", "expanded"},
+		},
+		{
+			name:   "medium only",
+			layout: func(c, m, e core.Widget) *Responsive { return NewResponsive(c).Medium(m) },
+			widths: []float32{300, 700, 900},
+			want:   []string{"compact", "medium", "medium"},
+		},
+		{
+			name: "breakpoints",
+			layout: func(c, m, e core.Widget) *Responsive {
+				return NewResponsive(c).Medium(m).Expanded(e).Breakpoints(200, 400)
+			},
+			widths: []float32{199, 200, 400},
+			want:   []string{"compact", "medium", "expanded"},
+		},
+		{
+			name: "crossed breakpoints",
+			layout: func(c, m, e core.Widget) *Responsive {
+				return NewResponsive(c).Medium(m).Expanded(e).Breakpoints(500, 300)
+			},
+			widths: []float32{400, 500},
+			want:   []string{"compact", "expanded"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kids := []*probe{{name: "compact"}, {name: "medium"}, {name: "expanded"}}
+			r := tt.layout(kids[0], kids[1], kids[2])
+			ctx := core.NewContext()
+			for i, w := range tt.widths {
+				bounds := core.R(0, 0, w, 100)
+				ctx.LayoutRoot(r, bounds)
+				shown := r.Children()
+				if len(shown) != 1 || shown[0].(*probe).name != tt.want[i] {
+					t.Errorf("at %v: children = %v, want %s", w, shown, tt.want[i])
+					continue
+				}
+				if got := shown[0].Bounds(); got != bounds {
+					t.Errorf("at %v: bounds = %v, want %v", w, got, bounds)
+				}
+			}
+		})
+	}
+}
+
+func TestResponsiveSizeClass(t *testing.T) {
+	inner := &probe{}
+	r := NewResponsive(inner)
+	ctx := core.NewContext()
+	ctx.SetSizeClass(core.SizeMedium)
+	var changes []core.SizeClass
+	r.OnChange(func(c core.SizeClass) { changes = append(changes, c) })
+
+	tests := []struct {
+		width float32
+		want  core.SizeClass
+	}{
+		{300, core.SizeCompact},
+		{900, core.SizeExpanded},
+		{1000, core.SizeExpanded},
+		{700, core.SizeMedium},
+	}
+	for _, tt := range tests {
+		inner.laidOut, inner.painted = nil, nil
+		ctx.LayoutRoot(r, core.R(0, 0, tt.width, 100))
+		r.Paint(&core.PaintContext{Context: ctx, Canvas: &core.Recording{}})
+		if r.Class() != tt.want || !slices.Equal(inner.laidOut, []core.SizeClass{tt.want}) || !slices.Equal(inner.painted, []core.SizeClass{tt.want}) {
+			t.Errorf("at %v: class %v, laid out in %v, painted in %v, want %v", tt.width, r.Class(), inner.laidOut, inner.painted, tt.want)
+		}
+		if ctx.SizeClass() != core.SizeMedium {
+			t.Errorf("at %v: the context was left in %v", tt.width, ctx.SizeClass())
+		}
+	}
+	if want := []core.SizeClass{core.SizeExpanded, core.SizeMedium}; !slices.Equal(changes, want) {
+		t.Errorf("OnChange got %v, want %v", changes, want)
+	}
+
+	// Laying out only the child again still gives it the class of the
+	// container.
+	ctx.LayoutRoot(r, core.R(0, 0, 900, 100))
+	inner.laidOut = nil
+	ctx.MarkNeedsLayout(inner)
+	ctx.LayoutRoot(r, core.R(0, 0, 900, 100))
+	if !slices.Equal(inner.laidOut, []core.SizeClass{core.SizeExpanded}) {
+		t.Errorf("laid out again in %v, want expanded", inner.laidOut)
+	}
+}
+
+func TestResponsiveSharesWidgets(t *testing.T) {
+	// The same widgets move between the layouts of each class.
+	a, b := newBox(40, 10), newBox(40, 10)
+	r := NewResponsive(NewVStack(a, b)).Expanded(NewHStack(a, b))
+	ctx := core.NewContext()
+	tests := []struct {
+		width float32
+		b     core.Rect
+	}{
+		{400, core.R(0, 10, 40, 10)},
+		{900, core.R(40, 0, 40, 10)},
+		{400, core.R(0, 10, 40, 10)},
+	}
+	for _, tt := range tests {
+		ctx.LayoutRoot(&loose{child: r}, core.R(0, 0, tt.width, 100))
+		if got := b.Bounds(); got != tt.b {
+			t.Errorf("at %v: bounds = %v, want %v", tt.width, got, tt.b)
+		}
+	}
+}
+
+func TestResponsiveEmpty(t *testing.T) {
+	r := NewResponsive(nil).Expanded(newBox(40, 10))
+	lc := &core.LayoutContext{Context: core.NewContext()}
+	if got := lc.Measure(r, core.Loose(core.Sz(300, 100))); got != core.Sz(0, 0) || len(r.Children()) != 0 {
+		t.Errorf("without a compact layout size = %v, children %v", got, r.Children())
+	}
+	r.SetBounds(core.R(0, 0, 300, 100))
+	r.Paint(&core.PaintContext{Context: lc.Context, Canvas: &core.Recording{}})
+	if got := lc.Measure(r, core.Loose(core.Sz(900, 100))); got != core.Sz(40, 10) || len(r.Children()) != 1 {
+		t.Errorf("expanded size = %v, children %v", got, r.Children())
+	}
+}
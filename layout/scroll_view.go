@@ -0,0 +1,838 @@
+package layout
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/internal/scroll"
+	"github.com/gogpu/ui/theme"
+)
+
+const (
+	scrollSmooth      = 150 * time.Millisecond // Wheel notches and ScrollTo.
+	scrollLatch       = 300 * time.Millisecond // Pause that ends a scroll gesture's latch.
+	scrollGestureEnd  = 80 * time.Millisecond  // Pause after which touchpad input has stopped.
+	scrollSampling    = 100 * time.Millisecond // Window for the release velocity.
+	scrollBarHold     = 900 * time.Millisecond
+	scrollBarFade     = 250 * time.Millisecond
+	scrollGlowFade    = 400 * time.Millisecond
+	scrollMaxStep     = 50 * time.Millisecond // Longest physics step, after a stall.
+	scrollFriction    = 3.5                   // Momentum decay rate, per second.
+	scrollSpring      = 200.0                 // Bounce-back stiffness, per second squared.
+	scrollMinVelocity = 20                    // Fling speed below which momentum stops, in px/s.
+
+	scrollSlop float32 = 6
+	scrollGlow float32 = 60 // Depth of a full glow.
+)
+
+// Overscroll selects what a ScrollView shows when scrolled past an end.
+type Overscroll uint8
+
+const (
+	// OverscrollAuto bounces with the Cupertino design and glows with the
+	// others.
+	OverscrollAuto Overscroll = iota
+	// OverscrollBounce lets the content follow the gesture past the end
+	// with resistance and springs it back on release.
+	OverscrollBounce
+	// OverscrollGlow stops the content at the end and lights up the edge
+	// in proportion to the excess.
+	OverscrollGlow
+	// OverscrollNone stops the content at the end.
+	OverscrollNone
+)
+
+// scrollAxis is the scroll state along one axis.
+type scrollAxis struct {
+	enabled           bool
+	offset            float32 // Outside [0, limit] while bouncing.
+	content, viewport float32
+	velocity          float32 // Momentum, in px/s.
+
+	tweening bool
+	jump     bool // Go to to at the next layout, once the limit is known.
+	from, to float32
+	tweenAt  time.Time
+
+	glow [2]float32 // Intensity at the start and end edges, 0 to 1.
+	bar  scroll.Bar
+}
+
+func (a *scrollAxis) limit() float32 {
+	return max(0, a.content-a.viewport)
+}
+
+// over returns how far the offset is past an end, negative before the
+// start.
+func (a *scrollAxis) over() float32 {
+	switch {
+	case a.offset < 0:
+		return a.offset
+	case a.offset > a.limit():
+		return a.offset - a.limit()
+	}
+	return 0
+}
+
+// canScroll reports whether the axis can move by a delta of sign d.
+func (a *scrollAxis) canScroll(d float32) bool {
+	return a.enabled && (d < 0 && a.offset > 0.5 || d > 0 && a.offset < a.limit()-0.5)
+}
+
+type scrollSample struct {
+	at     time.Time
+	offset core.Point
+}
+
+// scrollLatchState is the scroll view that took the current scroll
+// gesture, shared by the scroll views of a window.
+type scrollLatchState struct {
+	view *ScrollView
+	at   time.Time
+}
+
+type scrollLatchKey struct{}
+
+// scrollParentKey holds the ScrollView whose content is being laid out.
+type scrollParentKey struct{}
+
+// ScrollView shows a part of content larger than itself and scrolls it
+// with the wheel, the touchpad, the scrollbars and, if enabled, by
+// dragging the content.
+//
+// Wheel notches and ScrollTo animate smoothly. Touchpad gestures and
+// drags move the content directly and, when released in motion, keep
+// going with momentum that decays by friction. Scrolling past an end
+// bounces or glows as selected by Overscroll. The scrollbars overlay the
+// content along its end edge, on the left in right-to-left layouts, and
+// fade out while it is idle. Section headers wrapped with
+// Sticky stay docked at the top while their section is in view.
+//
+// Scroll views can be nested. A scroll gesture goes to the innermost
+// scroll view under the pointer that can scroll in the gesture's
+// direction and stays with it, even past its end, until the gesture
+// pauses; when neither it nor any enclosing scroll view can move, the
+// innermost one shows the overscroll.
+type ScrollView struct {
+	core.WidgetBase
+
+	content    core.Widget
+	axes       [2]scrollAxis // By Axis.
+	handles    [2]*scrollHandle
+	overscroll Overscroll
+	bounce     bool // Resolved mode at the last layout.
+	glow       bool
+	momentum   bool
+	drag       bool
+	parent     *ScrollView
+	dir        core.Direction
+	stickies   []*StickyHeader // Registered by the content during layout.
+	pinned     []*StickyHeader
+	onScroll   func(core.Point)
+	reported   core.Point
+	stagger    *Stagger
+
+	lastTick time.Time
+	active   time.Time // Last scroll activity, for the bar fade.
+	hover    bool
+
+	gesture   bool // At least one touchpad event, and the gesture not yet ended.
+	lastEvent time.Time
+	samples   []scrollSample
+
+	pressed     bool
+	dragging    bool
+	pressPos    core.Point
+	lastPos     core.Point
+	barDragging bool
+}
+
+// NewScrollView returns a vertically scrolling view of content.
+func NewScrollView(content core.Widget) *ScrollView {
+	s := &ScrollView{content: content, momentum: true}
+	s.axes[Vertical].enabled = true
+	s.axes[Horizontal].bar.Horizontal = true
+	for i := range s.handles {
+		s.handles[i] = &scrollHandle{view: s, axis: Axis(i)}
+	}
+	s.SetChildren(content)
+	return s
+}
+
+// Content returns the scrolled widget.
+func (s *ScrollView) Content() core.Widget {
+	return s.content
+}
+
+// Horizontal enables or disables horizontal scrolling, off by default.
+// Content that does not scroll along an axis is sized to the view's width
+// or height.
+func (s *ScrollView) Horizontal(enabled bool) *ScrollView {
+	s.axes[Horizontal].enabled = enabled
+	return s
+}
+
+// Vertical enables or disables vertical scrolling, on by default.
+func (s *ScrollView) Vertical(enabled bool) *ScrollView {
+	s.axes[Vertical].enabled = enabled
+	return s
+}
+
+// Overscroll sets what is shown when scrolling past an end.
+func (s *ScrollView) Overscroll(o Overscroll) *ScrollView {
+	s.overscroll = o
+	return s
+}
+
+// Momentum enables or disables the momentum after touchpad gestures and
+// drags, on by default. Hosts whose platform sends momentum as scroll
+// events, such as macOS, should disable it for touchpads.
+func (s *ScrollView) Momentum(enabled bool) *ScrollView {
+	s.momentum = enabled
+	return s
+}
+
+// DragToScroll enables scrolling by dragging the content with the primary
+// button, for touch screens that report touches as mouse events. Drags
+// that start on a widget handling the press, such as a button, do not
+// scroll.
+func (s *ScrollView) DragToScroll(enabled bool) *ScrollView {
+	s.drag = enabled
+	return s
+}
+
+// OnScroll registers fn to be called with the offset after it changes.
+func (s *ScrollView) OnScroll(fn func(offset core.Point)) *ScrollView {
+	s.onScroll = fn
+	return s
+}
+
+// Stagger sets the stagger of the entrance of the content's items, which
+// scrolling cancels.
+func (s *ScrollView) Stagger(st *Stagger) *ScrollView {
+	s.stagger = st
+	return s
+}
+
+// Offset returns the scroll position: the point of the content shown at
+// the top left corner of the view.
+func (s *ScrollView) Offset() core.Point {
+	return core.Pt(s.axes[Horizontal].offset, s.axes[Vertical].offset)
+}
+
+// MaxOffset returns the largest offset on each axis.
+func (s *ScrollView) MaxOffset() core.Point {
+	return core.Pt(s.axes[Horizontal].limit(), s.axes[Vertical].limit())
+}
+
+// ScrollTo scrolls smoothly to offset, clamped to the content. With
+// reduced motion it jumps.
+func (s *ScrollView) ScrollTo(offset core.Point) {
+	for i, v := range []float32{offset.X, offset.Y} {
+		a := &s.axes[i]
+		if !a.enabled {
+			continue
+		}
+		a.velocity = 0
+		a.from, a.to = a.offset, v
+		a.tweening, a.tweenAt = true, time.Time{}
+	}
+	s.active = time.Time{}
+}
+
+// JumpTo scrolls to offset, clamped to the content, without animation.
+func (s *ScrollView) JumpTo(offset core.Point) {
+	for i, v := range []float32{offset.X, offset.Y} {
+		a := &s.axes[i]
+		if a.enabled {
+			a.velocity, a.tweening = 0, false
+			a.to, a.jump = v, true
+		}
+	}
+}
+
+// Reveal scrolls smoothly by the least amount that brings r, in window
+// coordinates, into view.
+func (s *ScrollView) Reveal(r core.Rect) {
+	b := s.Bounds()
+	off := s.Offset()
+	target := off
+	if r.X < b.X {
+		target.X += r.X - b.X
+	} else if r.Right() > b.Right() {
+		target.X += min(r.Right()-b.Right(), r.X-b.X)
+	}
+	if r.Y < b.Y {
+		target.Y += r.Y - b.Y
+	} else if r.Bottom() > b.Bottom() {
+		target.Y += min(r.Bottom()-b.Bottom(), r.Y-b.Y)
+	}
+	if target != off {
+		s.ScrollTo(target)
+	}
+}
+
+// canScroll reports whether the view can move in the direction of d.
+func (s *ScrollView) canScroll(d core.Point) bool {
+	return s.axes[Horizontal].canScroll(d.X) || s.axes[Vertical].canScroll(d.Y)
+}
+
+// scrollsAlong reports whether the view scrolls along an axis of d.
+func (s *ScrollView) scrollsAlong(d core.Point) bool {
+	return s.axes[Horizontal].enabled && d.X != 0 || s.axes[Vertical].enabled && d.Y != 0
+}
+
+func (s *ScrollView) encloses(v *ScrollView) bool {
+	for p := v.parent; p != nil; p = p.parent {
+		if p == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *ScrollView) held() bool {
+	return s.gesture || s.dragging || s.barDragging
+}
+
+// Layout implements core.Widget.
+func (s *ScrollView) Layout(ctx *core.LayoutContext) core.Size {
+	s.parent, _ = ctx.Value(scrollParentKey{}).(*ScrollView)
+	s.dir = ctx.Direction()
+	switch s.overscroll {
+	case OverscrollAuto:
+		s.bounce = theme.From(ctx.Context).Design == theme.DesignCupertino
+		s.glow = !s.bounce
+	default:
+		s.bounce, s.glow = s.overscroll == OverscrollBounce, s.overscroll == OverscrollGlow
+	}
+
+	c := ctx.Constraints
+	bounded := [2]bool{c.HasBoundedWidth(), c.HasBoundedHeight()}
+	maxes := [2]float32{c.MaxWidth, c.MaxHeight}
+	var lo, hi [2]float32
+	for i := range s.axes {
+		lo[i], hi[i] = 0, maxes[i]
+		if bounded[i] {
+			lo[i] = maxes[i]
+		}
+		if s.axes[i].enabled {
+			hi[i] = core.Infinity
+		}
+	}
+	ctx.SetValue(scrollParentKey{}, s)
+	cs := ctx.Measure(s.content, core.Constraints{MinWidth: lo[0], MaxWidth: hi[0], MinHeight: lo[1], MaxHeight: hi[1]})
+	ctx.SetValue(scrollParentKey{}, s.parent)
+
+	size := cs
+	if bounded[0] {
+		size.Width = c.MaxWidth
+	}
+	if bounded[1] {
+		size.Height = c.MaxHeight
+	}
+	size = c.Constrain(size)
+	s.axes[Horizontal].content, s.axes[Horizontal].viewport = cs.Width, size.Width
+	s.axes[Vertical].content, s.axes[Vertical].viewport = cs.Height, size.Height
+	s.step(ctx)
+	return size
+}
+
+// step advances the animations and physics to the frame time.
+func (s *ScrollView) step(ctx *core.LayoutContext) {
+	now := ctx.Now()
+	dt := float32(0)
+	if !s.lastTick.IsZero() {
+		dt = float32(min(max(now.Sub(s.lastTick), 0), scrollMaxStep).Seconds())
+	}
+	s.lastTick = now
+	if s.active.IsZero() {
+		s.active = now
+	}
+	if s.gesture && now.Sub(s.lastEvent) > scrollGestureEnd {
+		s.gesture = false
+		s.release(now)
+	}
+	moving := false
+	for i := range s.axes {
+		a := &s.axes[i]
+		if !a.enabled {
+			a.offset, a.velocity, a.tweening = 0, 0, false
+			continue
+		}
+		if s.stepAxis(ctx, a, dt) {
+			moving = true
+		}
+	}
+	if moving {
+		s.active = now
+	}
+	if s.fadeBars(now) || moving {
+		// The animations step as the view is laid out.
+		ctx.MarkNeedsLayout(s)
+	}
+	if off := s.Offset(); off != s.reported {
+		s.reported = off
+		if s.stagger != nil {
+			s.stagger.Cancel()
+		}
+		if s.onScroll != nil {
+			s.onScroll(off)
+		}
+	}
+}
+
+// stepAxis advances one axis by dt seconds and reports whether it is still
+// in motion.
+func (s *ScrollView) stepAxis(ctx *core.LayoutContext, a *scrollAxis, dt float32) bool {
+	for k := range a.glow {
+		if a.glow[k] > 0 && !s.held() {
+			a.glow[k] = max(0, a.glow[k]-dt/float32(scrollGlowFade.Seconds()))
+		}
+	}
+	glowing := a.glow[0] > 0 || a.glow[1] > 0
+	if a.jump {
+		a.offset, a.jump = core.Clamp(a.to, 0, a.limit()), false
+	}
+	if a.tweening {
+		if a.tweenAt.IsZero() {
+			a.tweenAt = ctx.Now()
+		}
+		a.to = core.Clamp(a.to, 0, a.limit())
+		t := float32(ctx.Now().Sub(a.tweenAt)) / float32(scrollSmooth)
+		if t >= 1 || ctx.ReducedMotion() {
+			a.offset, a.tweening = a.to, false
+			return glowing
+		}
+		a.offset = a.from + (a.to-a.from)*easeOut(t)
+		return true
+	}
+	if s.held() {
+		return glowing
+	}
+	if s.bounce && (a.over() != 0 || a.velocity != 0) {
+		// Momentum with friction inside the range and a critically damped
+		// spring pulling back to the end outside it, in small steps.
+		for rest := dt; rest > 0; rest -= 0.004 {
+			h := min(rest, 0.004)
+			if x := a.over(); x != 0 {
+				k := float32(scrollSpring)
+				a.velocity += (-k*x - 2*float32(math.Sqrt(scrollSpring))*a.velocity) * h
+			} else {
+				a.velocity *= float32(math.Exp(-scrollFriction * float64(h)))
+			}
+			a.offset += a.velocity * h
+		}
+		if x := a.over(); abs32(x) < 0.5 && abs32(a.velocity) < scrollMinVelocity {
+			a.offset = core.Clamp(a.offset, 0, a.limit())
+			a.velocity = 0
+			return glowing
+		}
+		if ctx.ReducedMotion() {
+			a.offset, a.velocity = core.Clamp(a.offset, 0, a.limit()), 0
+		}
+		return true
+	}
+	if a.velocity != 0 {
+		a.offset += a.velocity * dt
+		a.velocity *= float32(math.Exp(-scrollFriction * float64(dt)))
+		if x := a.over(); x != 0 {
+			if s.glow {
+				a.addGlow(x, abs32(a.velocity)/4000)
+			}
+			a.velocity = 0
+		}
+		if abs32(a.velocity) < scrollMinVelocity {
+			a.velocity = 0
+		}
+	}
+	a.offset = core.Clamp(a.offset, 0, a.limit())
+	return a.velocity != 0 || glowing
+}
+
+// addGlow lights the edge that over points past.
+func (a *scrollAxis) addGlow(over, amount float32) {
+	edge := 1
+	if over < 0 {
+		edge = 0
+	}
+	a.glow[edge] = min(1, a.glow[edge]+amount)
+}
+
+// push moves axis a by d for direct input, applying the overscroll mode
+// past the ends.
+func (s *ScrollView) push(a *scrollAxis, d float32) {
+	if !a.enabled || d == 0 {
+		return
+	}
+	a.tweening, a.velocity = false, 0
+	next := a.offset + d
+	lim := a.limit()
+	switch {
+	case s.bounce:
+		if x := a.over(); next < 0 && d < 0 || next > lim && d > 0 {
+			// Resist more the further the content is pulled.
+			d *= 0.5 * max(0, 1-abs32(x)/max(1, a.viewport/2))
+		}
+		a.offset += d
+	case s.glow:
+		a.offset = core.Clamp(next, 0, lim)
+		if x := next - a.offset; x != 0 {
+			a.addGlow(x, abs32(x)/200)
+		}
+	default:
+		a.offset = core.Clamp(next, 0, lim)
+	}
+}
+
+// wheel animates by d, for wheel notches.
+func (s *ScrollView) wheel(a *scrollAxis, d float32) {
+	if !a.enabled || d == 0 {
+		return
+	}
+	base := a.offset
+	if a.tweening {
+		base = a.to
+	}
+	a.velocity = 0
+	a.from, a.to = a.offset, core.Clamp(base+d, 0, a.limit())
+	a.tweening, a.tweenAt = true, time.Time{}
+}
+
+// sample records the offset for the release velocity.
+func (s *ScrollView) sample(now time.Time) {
+	s.samples = append(s.samples, scrollSample{now, s.Offset()})
+	i := 0
+	for i < len(s.samples)-1 && now.Sub(s.samples[i].at) > scrollSampling {
+		i++
+	}
+	s.samples = s.samples[i:]
+}
+
+// release ends direct input, starting momentum from the recent motion.
+func (s *ScrollView) release(now time.Time) {
+	defer func() { s.samples = s.samples[:0] }()
+	if !s.momentum || len(s.samples) < 2 || now.Sub(s.samples[len(s.samples)-1].at) > scrollSampling {
+		return
+	}
+	first, last := s.samples[0], s.samples[len(s.samples)-1]
+	dt := float32(last.at.Sub(first.at).Seconds())
+	if dt <= 0 {
+		return
+	}
+	v := last.offset.Sub(first.offset)
+	for i, d := range []float32{v.X, v.Y} {
+		a := &s.axes[i]
+		if a.enabled && a.over() == 0 && abs32(d/dt) >= scrollMinVelocity {
+			a.velocity = d / dt
+		}
+	}
+}
+
+// fadeBars updates the fade of the scrollbars and reports whether it is
+// still to change.
+func (s *ScrollView) fadeBars(now time.Time) bool {
+	show := s.hover || s.barDragging || s.held()
+	visible := false
+	for i := range s.axes {
+		b := &s.axes[i].bar
+		show = show || b.Hovered()
+		visible = visible || s.axes[i].enabled && b.Visible()
+	}
+	if show {
+		s.active = now
+	}
+	faded := float32(0)
+	if idle := now.Sub(s.active) - scrollBarHold; idle > 0 {
+		faded = min(1, float32(idle)/float32(scrollBarFade))
+	}
+	for i := range s.axes {
+		s.axes[i].bar.Faded = faded
+	}
+	return visible && faded < 1 && !show
+}
+
+// SetBounds implements core.Widget.
+func (s *ScrollView) SetBounds(r core.Rect) {
+	s.WidgetBase.SetBounds(r)
+	h, v := &s.axes[Horizontal], &s.axes[Vertical]
+	h.viewport, v.viewport = r.Width, r.Height
+	off := s.Offset()
+	s.content.SetBounds(core.R(r.X-off.X, r.Y-off.Y, max(h.content, r.Width), max(v.content, r.Height)))
+
+	h.bar.Viewport, h.bar.Content = h.viewport, h.content
+	v.bar.Viewport, v.bar.Content = v.viewport, v.content
+	hv := h.enabled && h.bar.Visible()
+	vv := v.enabled && v.bar.Visible()
+	var corner float32
+	if hv && vv {
+		corner = scroll.Thickness
+	}
+	v.bar.Track, _ = scroll.EndTrack(r, s.dir)
+	v.bar.Track.Height = max(0, r.Height-corner)
+	h.bar.Track = core.R(r.X, r.Bottom()-scroll.Thickness, max(0, r.Width-corner), scroll.Thickness)
+	if s.dir.IsRTL() {
+		h.bar.Track.X += corner
+	}
+	children := []core.Widget{s.content}
+	s.stickies = attached(s.stickies, s.content)
+	s.pinned = pinStickies(s.stickies, r.Y)
+	for _, h := range s.pinned {
+		children = append(children, h)
+	}
+	for i, visible := range []bool{hv, vv} {
+		if visible && s.axes[i].bar.Faded < 1 {
+			s.handles[i].SetBounds(s.axes[i].bar.Track)
+			children = append(children, s.handles[i])
+		}
+	}
+	s.SetChildren(children...)
+}
+
+// unpinDetached drops the pinned headers that a relayout inside the
+// content removed since the scroll view was arranged.
+func (s *ScrollView) unpinDetached() {
+	n := len(s.pinned)
+	if s.pinned = attached(s.pinned, s.content); len(s.pinned) == n {
+		return
+	}
+	s.SetChildren(slices.DeleteFunc(slices.Clone(s.Children()), func(w core.Widget) bool {
+		h, ok := w.(*StickyHeader)
+		return ok && !slices.Contains(s.pinned, h)
+	})...)
+}
+
+// Paint implements core.Widget.
+func (s *ScrollView) Paint(ctx *core.PaintContext) {
+	s.unpinDetached()
+	cv := ctx.Canvas
+	r := s.Bounds()
+	cv.Save()
+	cv.Clip(r)
+	s.content.Paint(ctx)
+	for _, h := range s.pinned {
+		h.child.Paint(ctx)
+	}
+	s.paintGlow(ctx)
+	for i := range s.axes {
+		a := &s.axes[i]
+		if a.enabled {
+			a.bar.Paint(ctx, core.Clamp(a.offset, 0, a.limit()))
+		}
+	}
+	cv.Restore()
+}
+
+func (s *ScrollView) paintGlow(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	r := s.Bounds()
+	for i := range s.axes {
+		for edge, g := range s.axes[i].glow {
+			if g <= 0 {
+				continue
+			}
+			depth := scrollGlow * g
+			var e core.Rect
+			switch {
+			case Axis(i) == Vertical && edge == 0:
+				e = core.R(r.X-r.Width/4, r.Y-depth, r.Width*1.5, 2*depth)
+			case Axis(i) == Vertical:
+				e = core.R(r.X-r.Width/4, r.Bottom()-depth, r.Width*1.5, 2*depth)
+			case edge == 0:
+				e = core.R(r.X-depth, r.Y-r.Height/4, 2*depth, r.Height*1.5)
+			default:
+				e = core.R(r.Right()-depth, r.Y-r.Height/4, 2*depth, r.Height*1.5)
+			}
+			ctx.Canvas.DrawPath(core.NewPath().AddEllipse(e), core.PathStyle{Fill: th.Colors.Primary.WithAlpha(0.16 * g)})
+		}
+	}
+}
+
+// takeGesture decides whether the view handles a scroll event of delta d,
+// following the nesting rules in the type's documentation.
+func (s *ScrollView) takeGesture(ctx *core.Context, d core.Point) bool {
+	now := time.Now() // The frame time is stale between frames.
+	latch, _ := ctx.Value(scrollLatchKey{}).(*scrollLatchState)
+	if latch == nil {
+		latch = &scrollLatchState{}
+		ctx.SetValue(scrollLatchKey{}, latch)
+	}
+	if latch.view != nil && now.Sub(latch.at) < scrollLatch {
+		if latch.view == s {
+			latch.at = now
+			return true
+		}
+		if latch.view.encloses(s) {
+			return false
+		}
+	}
+	if !s.scrollsAlong(d) {
+		return false
+	}
+	if !s.canScroll(d) {
+		for p := s.parent; p != nil; p = p.parent {
+			if p.canScroll(d) {
+				return false
+			}
+		}
+	}
+	latch.view, latch.at = s, now
+	return true
+}
+
+// HandleEvent implements core.Widget.
+func (s *ScrollView) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	switch e := ev.(type) {
+	case event.ScrollEvent:
+		d := e.Delta
+		if e.Modifiers&event.ModShift != 0 && d.X == 0 && s.axes[Horizontal].enabled && !s.axes[Vertical].enabled {
+			d = core.Pt(d.Y, 0)
+		}
+		if !s.takeGesture(ctx, d) {
+			return core.Ignored
+		}
+		now := time.Now()
+		s.active = now
+		if e.Precise {
+			if !s.gesture {
+				s.samples = s.samples[:0]
+			}
+			s.gesture, s.lastEvent = true, now
+			s.push(&s.axes[Horizontal], d.X)
+			s.push(&s.axes[Vertical], d.Y)
+			s.sample(now)
+		} else {
+			s.wheel(&s.axes[Horizontal], d.X)
+			s.wheel(&s.axes[Vertical], d.Y)
+		}
+		ctx.MarkNeedsLayout(s)
+		return core.Handled
+	case event.MouseEvent:
+		return s.handleMouse(ctx, e)
+	case event.KeyEvent:
+		v := &s.axes[Vertical]
+		if e.Type != event.KeyPress || e.Modifiers != 0 || !v.enabled {
+			return core.Ignored
+		}
+		switch e.Key {
+		case event.KeyPageUp:
+			s.wheel(v, -v.viewport*0.9)
+		case event.KeyPageDown:
+			s.wheel(v, v.viewport*0.9)
+		default:
+			return core.Ignored
+		}
+		s.active = time.Time{}
+		ctx.MarkNeedsLayout(s)
+		return core.Handled
+	}
+	return core.Ignored
+}
+
+func (s *ScrollView) handleMouse(ctx *core.Context, e event.MouseEvent) core.EventResult {
+	switch e.Type {
+	case event.MouseEnter, event.MouseMove:
+		if !s.hover {
+			s.hover = true
+			ctx.MarkNeedsLayout(s)
+		}
+	case event.MouseLeave:
+		s.hover = false
+		ctx.MarkNeedsLayout(s)
+	}
+	if !s.drag {
+		return core.Ignored
+	}
+	now := time.Now()
+	switch e.Type {
+	case event.MouseDown:
+		if e.Button != event.ButtonLeft {
+			return core.Ignored
+		}
+		for i := range s.axes {
+			s.axes[i].velocity, s.axes[i].tweening = 0, false
+		}
+		s.pressed, s.dragging = true, false
+		s.pressPos, s.lastPos = e.Position, e.Position
+		s.samples = s.samples[:0]
+		ctx.CapturePointer(s)
+		return core.Handled
+	case event.MouseMove:
+		if !s.pressed {
+			return core.Ignored
+		}
+		if !s.dragging {
+			d := e.Position.Sub(s.pressPos)
+			if max(abs32(d.X), abs32(d.Y)) < scrollSlop {
+				return core.Handled
+			}
+			s.dragging = true
+		}
+		d := s.lastPos.Sub(e.Position)
+		s.lastPos = e.Position
+		s.push(&s.axes[Horizontal], d.X)
+		s.push(&s.axes[Vertical], d.Y)
+		s.sample(now)
+		s.active = now
+		ctx.MarkNeedsLayout(s)
+		return core.Handled
+	case event.MouseUp:
+		if !s.pressed || e.Button != event.ButtonLeft {
+			return core.Ignored
+		}
+		s.pressed = false
+		ctx.ReleasePointer()
+		if s.dragging {
+			s.dragging = false
+			s.release(now)
+			ctx.MarkNeedsLayout(s)
+		}
+		return core.Handled
+	}
+	return core.Ignored
+}
+
+// barEvent forwards pointer events on a scrollbar track to its bar.
+func (s *ScrollView) barEvent(ctx *core.Context, axis Axis, ev core.Event) core.EventResult {
+	a := &s.axes[axis]
+	off, ok := a.bar.HandleEvent(ctx, s.handles[axis], ev, core.Clamp(a.offset, 0, a.limit()))
+	s.barDragging = a.bar.Dragging()
+	if !ok {
+		if _, wheel := ev.(event.ScrollEvent); wheel {
+			return s.HandleEvent(ctx, ev)
+		}
+		return core.Ignored
+	}
+	if off != a.offset {
+		a.offset, a.velocity, a.tweening = off, 0, false
+	}
+	s.active = ctx.Now()
+	ctx.MarkNeedsLayout(s)
+	return core.Handled
+}
+
+// scrollHandle is the hit area of a scrollbar, on top of the content so
+// that the bar gets the pointer before the widgets under it.
+type scrollHandle struct {
+	core.WidgetBase
+	view *ScrollView
+	axis Axis
+}
+
+func (h *scrollHandle) Layout(ctx *core.LayoutContext) core.Size {
+	return ctx.Constraints.Min()
+}
+
+func (h *scrollHandle) Paint(*core.PaintContext) {}
+
+func (h *scrollHandle) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	return h.view.barEvent(ctx, h.axis, ev)
+}
+
+func easeOut(p float32) float32 {
+	q := 1 - p
+	return 1 - q*q*q
+}
@@ -0,0 +1,164 @@
+package layout
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+)
+
+// scrollFrame lays s out in bounds as the frame at now.
+func scrollFrame(ctx *core.Context, s *ScrollView, bounds core.Rect, now time.Time) {
+	ctx.SetNow(now)
+	ctx.Invalidate()
+	ctx.LayoutRoot(s, bounds)
+}
+
+func TestScrollViewContentSize(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    core.Size
+		horizontal bool
+		bounds     core.Rect // Of the content.
+		max        core.Point
+	}{
+		{"taller", core.Sz(50, 500), false, core.R(0, 0, 100, 500), core.Pt(0, 300)},
+		{"shorter fills the view", core.Sz(50, 80), false, core.R(0, 0, 100, 200), core.Pt(0, 0)},
+		{"wider without horizontal scrolling", core.Sz(300, 500), false, core.R(0, 0, 100, 500), core.Pt(0, 300)},
+		{"wider with horizontal scrolling", core.Sz(300, 500), true, core.R(0, 0, 300, 500), core.Pt(200, 300)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := newBox(tt.content.Width, tt.content.Height)
+			s := NewScrollView(content).Horizontal(tt.horizontal)
+			scrollFrame(core.NewContext(), s, core.R(0, 0, 100, 200), time.Unix(0, 0))
+			if got := content.Bounds(); got != tt.bounds {
+				t.Errorf("content bounds = %v, want %v", got, tt.bounds)
+			}
+			if got := s.MaxOffset(); got != tt.max {
+				t.Errorf("MaxOffset() = %v, want %v", got, tt.max)
+			}
+		})
+	}
+}
+
+func TestScrollViewJumpToClamps(t *testing.T) {
+	bounds := core.R(0, 0, 100, 200)
+	tests := []struct {
+		to, want core.Point
+	}{
+		{core.Pt(0, 120), core.Pt(0, 120)},
+		{core.Pt(0, 1000), core.Pt(0, 300)},
+		{core.Pt(0, -50), core.Pt(0, 0)},
+		{core.Pt(40, 50), core.Pt(0, 50)}, // Not scrolling horizontally.
+	}
+	for _, tt := range tests {
+		content := newBox(100, 500)
+		s := NewScrollView(content)
+		ctx := core.NewContext()
+		t0 := time.Unix(0, 0)
+		scrollFrame(ctx, s, bounds, t0)
+		s.JumpTo(tt.to)
+		scrollFrame(ctx, s, bounds, t0)
+		if got := s.Offset(); got != tt.want {
+			t.Errorf("JumpTo(%v): Offset() = %v, want %v", tt.to, got, tt.want)
+		}
+		if got, want := content.Bounds().Y, -tt.want.Y; got != want {
+			t.Errorf("JumpTo(%v): content at y %v, want %v", tt.to, got, want)
+		}
+	}
+}
+
+func TestScrollViewScrollTo(t *testing.T) {
+	bounds := core.R(0, 0, 100, 200)
+	s := NewScrollView(newBox(100, 500))
+	ctx := core.NewContext()
+	t0 := time.Unix(0, 0)
+	scrollFrame(ctx, s, bounds, t0)
+
+	s.ScrollTo(core.Pt(0, 1000))
+	scrollFrame(ctx, s, bounds, t0)
+	if got := s.Offset().Y; got != 0 {
+		t.Errorf("offset %v as the scroll starts, want 0", got)
+	}
+	scrollFrame(ctx, s, bounds, t0.Add(scrollSmooth/2))
+	if got := s.Offset().Y; got <= 0 || got >= 300 {
+		t.Errorf("offset %v halfway, want between 0 and 300", got)
+	}
+	scrollFrame(ctx, s, bounds, t0.Add(scrollSmooth))
+	if got := s.Offset().Y; got != 300 {
+		t.Errorf("offset %v at the end, want the limit 300", got)
+	}
+
+	// With reduced motion it jumps.
+	ctx.SetReducedMotion(true)
+	s.ScrollTo(core.Pt(0, 100))
+	scrollFrame(ctx, s, bounds, t0.Add(2*scrollSmooth))
+	if got := s.Offset().Y; got != 100 {
+		t.Errorf("offset %v with reduced motion, want 100", got)
+	}
+}
+
+func TestScrollViewPreciseScrollClamps(t *testing.T) {
+	bounds := core.R(0, 0, 100, 200)
+	tests := []struct {
+		name       string
+		overscroll Overscroll
+		want       float32
+	}{
+		{"none", OverscrollNone, 300},
+		{"glow", OverscrollGlow, 300},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewScrollView(newBox(100, 500)).Overscroll(tt.overscroll)
+			ctx := core.NewContext()
+			scrollFrame(ctx, s, bounds, time.Unix(0, 0))
+			ev := event.ScrollEvent{Position: core.Pt(50, 50), Delta: core.Pt(0, 400), Precise: true}
+			if s.HandleEvent(ctx, ev) != core.Handled {
+				t.Fatal("the scroll event was not handled")
+			}
+			if got := s.Offset().Y; got != tt.want {
+				t.Errorf("offset %v, want %v", got, tt.want)
+			}
+			if glowing := s.axes[Vertical].glow[1] > 0; glowing != (tt.overscroll == OverscrollGlow) {
+				t.Errorf("end edge glowing = %v", glowing)
+			}
+		})
+	}
+}
+
+func TestScrollViewCancelsStagger(t *testing.T) {
+	bounds := core.R(0, 0, 100, 200)
+	st := NewStagger(50 * time.Millisecond)
+	var rows []core.Widget
+	for range 10 {
+		rows = append(rows, newBox(100, 50))
+	}
+	s := NewScrollView(NewVStack(rows...).Stagger(st)).Stagger(st)
+	ctx := core.NewContext()
+	t0 := time.Unix(0, 0)
+	scrollFrame(ctx, s, bounds, t0)
+	ctx.RunAfterFrame()
+	if !st.IsRunning() {
+		t.Fatal("the entrance did not start")
+	}
+
+	// Laying out again without scrolling leaves it running.
+	scrollFrame(ctx, s, bounds, t0.Add(10*time.Millisecond))
+	if !st.IsRunning() {
+		t.Fatal("a layout without scrolling cancelled the entrance")
+	}
+
+	s.JumpTo(core.Pt(0, 40))
+	scrollFrame(ctx, s, bounds, t0.Add(20*time.Millisecond))
+	if st.IsRunning() {
+		t.Error("scrolling did not cancel the entrance")
+	}
+	for i := range rows {
+		if got := st.progress(i); got != 1 {
+			t.Errorf("progress(%d) = %v after scrolling, want 1", i, got)
+		}
+	}
+}
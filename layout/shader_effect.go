@@ -0,0 +1,186 @@
+package layout
+
+import (
+	"time"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/state"
+)
+
+// ShaderEffect draws its child through a custom WGSL shader, a
+// core.Effect, for effects the renderer does not provide, such as
+// ripples and dissolves:
+//
+//	dissolve := core.MustEffect(src, core.Uniform{Name: "progress"})
+//	progress := state.New[float32](0)
+//	panel := layout.NewShaderEffect(content, dissolve).Bind("progress", progress)
+//
+// The child is painted into an offscreen layer each frame and the layer
+// drawn through the effect. Effects reading u.time are painted every
+// frame, with the clock starting when the container is first painted.
+// On canvases that do not implement core.EffectCanvas, which include
+// those of render/raster and render/batch, the child is painted as it
+// is, without the effect.
+type ShaderEffect struct {
+	core.WidgetBase
+	child  core.Widget
+	effect *core.Effect
+
+	values [][4]float32 // Of each uniform of the effect.
+	sigs   []*state.Signal[float32]
+	cancel []func()
+	layer  core.Layer
+	scale  float32 // Of the canvas the layer was made for.
+	start  time.Time
+}
+
+// NewShaderEffect returns a container drawing child through e. Its
+// uniforms start at zero.
+func NewShaderEffect(child core.Widget, e *core.Effect) *ShaderEffect {
+	n := len(e.Uniforms())
+	s := &ShaderEffect{
+		child:  child,
+		effect: e,
+		values: make([][4]float32, n),
+		sigs:   make([]*state.Signal[float32], n),
+		cancel: make([]func(), n),
+	}
+	s.SetChildren(child)
+	return s
+}
+
+// Effect returns the effect the child is drawn through.
+func (s *ShaderEffect) Effect() *core.Effect {
+	return s.effect
+}
+
+// uniform returns the index of the uniform name, or -1.
+func (s *ShaderEffect) uniform(name string) int {
+	for i, u := range s.effect.Uniforms() {
+		if u.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Uniform sets the uniform name to the components v, one for a float.
+// The uniform stops following a signal bound to it. Names the effect
+// does not declare are ignored.
+func (s *ShaderEffect) Uniform(name string, v ...float32) *ShaderEffect {
+	if i := s.uniform(name); i >= 0 {
+		s.unbind(i)
+		s.values[i] = [4]float32{}
+		copy(s.values[i][:], v)
+	}
+	return s
+}
+
+// Color sets the vector uniform name to c, premultiplied as the layer's
+// colors are.
+func (s *ShaderEffect) Color(name string, c core.Color) *ShaderEffect {
+	return s.Uniform(name, c.R*c.A, c.G*c.A, c.B*c.A, c.A)
+}
+
+// Bind keeps the float uniform name at the value of sig, painting the
+// container again whenever it changes, such as to animate a dissolve.
+func (s *ShaderEffect) Bind(name string, sig *state.Signal[float32]) *ShaderEffect {
+	if i := s.uniform(name); i >= 0 {
+		s.unbind(i)
+		s.sigs[i] = sig
+	}
+	return s
+}
+
+func (s *ShaderEffect) unbind(i int) {
+	if s.cancel[i] != nil {
+		s.cancel[i]()
+		s.cancel[i] = nil
+	}
+	s.sigs[i] = nil
+}
+
+// Release frees the layer, for when the container leaves the tree. It is
+// made again if the container is painted.
+func (s *ShaderEffect) Release() {
+	if s.layer != nil {
+		s.layer.Release()
+		s.layer = nil
+	}
+}
+
+// Layout implements core.Widget.
+func (s *ShaderEffect) Layout(ctx *core.LayoutContext) core.Size {
+	for i, sig := range s.sigs {
+		if sig != nil && s.cancel[i] == nil {
+			c := ctx.Context
+			s.cancel[i] = sig.Subscribe(func(float32) { c.Post(func() { c.Repaint(s) }) })
+		}
+	}
+	return ctx.Measure(s.child, ctx.Constraints)
+}
+
+// IntrinsicWidth implements core.IntrinsicSizer.
+func (s *ShaderEffect) IntrinsicWidth(ctx *core.LayoutContext, height float32) (minWidth, maxWidth float32) {
+	return ctx.IntrinsicWidth(s.child, height)
+}
+
+// IntrinsicHeight implements core.IntrinsicSizer.
+func (s *ShaderEffect) IntrinsicHeight(ctx *core.LayoutContext, width float32) (minHeight, maxHeight float32) {
+	return ctx.IntrinsicHeight(s.child, width)
+}
+
+// Baseline implements core.Baseliner with the child's baseline.
+func (s *ShaderEffect) Baseline() (float32, bool) {
+	return core.BaselineOf(s.child)
+}
+
+// SetBounds implements core.Widget.
+func (s *ShaderEffect) SetBounds(r core.Rect) {
+	s.WidgetBase.SetBounds(r)
+	s.child.SetBounds(r)
+}
+
+// Paint implements core.Widget.
+func (s *ShaderEffect) Paint(ctx *core.PaintContext) {
+	b := s.Bounds()
+	if b.IsEmpty() {
+		return
+	}
+	ec, ok := ctx.Canvas.(core.EffectCanvas)
+	if !ok {
+		s.child.Paint(ctx)
+		return
+	}
+	if s.start.IsZero() {
+		s.start = ctx.Now()
+	}
+	// Captures draw into canvases of their own, which the layer of the
+	// frames does not belong to.
+	l := s.layer
+	if ctx.Capture {
+		l = ec.NewLayer(b.Size())
+		defer l.Release()
+	} else if l == nil || l.Size() != b.Size() || s.scale != ctx.ScaleFactor() {
+		s.Release()
+		s.layer, s.scale = ec.NewLayer(b.Size()), ctx.ScaleFactor()
+		l = s.layer
+	}
+	pc := *ctx
+	pc.Canvas = l.Begin()
+	pc.Canvas.Translate(-b.X, -b.Y)
+	s.child.Paint(&pc)
+	l.End()
+
+	for i, sig := range s.sigs {
+		if sig != nil {
+			s.values[i] = [4]float32{sig.Get()}
+		}
+	}
+	scale := ctx.ScaleFactor()
+	t := float32(ctx.Now().Sub(s.start).Seconds())
+	ec.DrawLayerEffect(l, b.Origin(), s.effect, s.effect.Pack(core.Sz(b.Width*scale, b.Height*scale), t, s.values))
+	if s.effect.Animated() {
+		ctx.Repaint(s)
+	}
+}
@@ -0,0 +1,471 @@
+package layout
+
+import (
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/theme"
+)
+
+type splitPaneItem struct {
+	content     core.Widget
+	min, max    float32
+	collapsible bool
+	collapsed   bool
+	restore     float32 // Ratio to return to when expanded.
+	size        float32
+}
+
+// SplitPane arranges panes along an axis separated by draggable dividers.
+//
+// Each pane gets a share of the space given by its ratio, limited by its
+// minimum and maximum size. Panes marked collapsible snap shut when a
+// divider is dragged past half their minimum size, and double-clicking a
+// divider collapses or expands its collapsible neighbour.
+//
+// The ratios can be persisted with Ratios and OnRatiosChange and restored
+// with SetRatios. In right-to-left text horizontal panes run from the
+// right, the first pane on the right.
+type SplitPane struct {
+	core.WidgetBase
+
+	axis        Axis
+	panes       []splitPaneItem
+	ratios      []float32
+	dividerSize float32
+	dividers    []core.Rect
+	avail       float32
+	dir         core.Direction
+
+	hover    int
+	drag     int
+	grab     float32
+	onChange func(ratios []float32)
+}
+
+// NewSplitPane returns a split pane arranging panes along axis, sharing the
+// space equally.
+func NewSplitPane(axis Axis, panes ...core.Widget) *SplitPane {
+	s := &SplitPane{axis: axis, dividerSize: defaultDividerSize, hover: -1, drag: -1}
+	for _, p := range panes {
+		s.panes = append(s.panes, splitPaneItem{content: p, max: core.Infinity})
+		s.ratios = append(s.ratios, 1/float32(len(panes)))
+	}
+	s.SetChildren(panes...)
+	return s
+}
+
+// DividerSize sets the thickness of the dividers.
+func (s *SplitPane) DividerSize(px float32) *SplitPane {
+	s.dividerSize = max(1, px)
+	return s
+}
+
+// PaneLimits sets the minimum and maximum size of pane i along the axis.
+func (s *SplitPane) PaneLimits(i int, minSize, maxSize float32) *SplitPane {
+	if i >= 0 && i < len(s.panes) {
+		s.panes[i].min = max(0, minSize)
+		s.panes[i].max = max(s.panes[i].min, maxSize)
+	}
+	return s
+}
+
+// Collapsible allows pane i to be collapsed to the edge.
+func (s *SplitPane) Collapsible(i int, collapsible bool) *SplitPane {
+	if i >= 0 && i < len(s.panes) {
+		s.panes[i].collapsible = collapsible
+	}
+	return s
+}
+
+// OnRatiosChange registers fn to be called with the new ratios after the
+// user moves a divider or a pane is collapsed or expanded.
+func (s *SplitPane) OnRatiosChange(fn func(ratios []float32)) *SplitPane {
+	s.onChange = fn
+	return s
+}
+
+// Axis returns the direction in which panes are arranged.
+func (s *SplitPane) Axis() Axis {
+	return s.axis
+}
+
+// Ratios returns the share of the available space of each pane. Collapsed
+// panes have a ratio of zero.
+func (s *SplitPane) Ratios() []float32 {
+	out := make([]float32, len(s.ratios))
+	for i, r := range s.ratios {
+		if !s.panes[i].collapsed {
+			out[i] = r
+		}
+	}
+	return out
+}
+
+// SetRatios sets the share of each pane, for example from a saved layout.
+// The ratios are normalized; a zero ratio collapses a collapsible pane.
+// Calls with the wrong number of ratios are ignored.
+func (s *SplitPane) SetRatios(ratios ...float32) {
+	if len(ratios) != len(s.panes) {
+		return
+	}
+	var total float32
+	for _, r := range ratios {
+		total += max(0, r)
+	}
+	if total <= 0 {
+		return
+	}
+	for i, r := range ratios {
+		r = max(0, r) / total
+		p := &s.panes[i]
+		p.collapsed = r == 0 && p.collapsible
+		if p.collapsed {
+			p.restore = 1 / float32(len(s.panes))
+		}
+		s.ratios[i] = r
+	}
+}
+
+// IsCollapsed reports whether pane i is collapsed.
+func (s *SplitPane) IsCollapsed(i int) bool {
+	return i >= 0 && i < len(s.panes) && s.panes[i].collapsed
+}
+
+// Collapse collapses pane i if it is collapsible.
+func (s *SplitPane) Collapse(i int) {
+	if i < 0 || i >= len(s.panes) || !s.panes[i].collapsible || s.panes[i].collapsed {
+		return
+	}
+	p := &s.panes[i]
+	p.collapsed, p.restore = true, s.ratios[i]
+	s.giveTo(neighbour(i, len(s.panes)), s.ratios[i])
+	s.ratios[i] = 0
+	s.notify()
+}
+
+// Expand restores a collapsed pane to its previous size.
+func (s *SplitPane) Expand(i int) {
+	if !s.IsCollapsed(i) {
+		return
+	}
+	p := &s.panes[i]
+	p.collapsed = false
+	n := neighbour(i, len(s.panes))
+	share := min(p.restore, s.ratios[n])
+	s.ratios[n] -= share
+	s.ratios[i] = share
+	s.notify()
+}
+
+func neighbour(i, n int) int {
+	if i+1 < n {
+		return i + 1
+	}
+	return i - 1
+}
+
+func (s *SplitPane) giveTo(i int, r float32) {
+	if i >= 0 && i < len(s.ratios) {
+		s.ratios[i] += r
+	}
+}
+
+func (s *SplitPane) notify() {
+	if s.onChange != nil {
+		s.onChange(s.Ratios())
+	}
+}
+
+func (s *SplitPane) main(sz core.Size) float32 {
+	if s.axis == Vertical {
+		return sz.Height
+	}
+	return sz.Width
+}
+
+func (s *SplitPane) cross(sz core.Size) float32 {
+	if s.axis == Vertical {
+		return sz.Width
+	}
+	return sz.Height
+}
+
+func (s *SplitPane) size(main, cross float32) core.Size {
+	if s.axis == Vertical {
+		return core.Sz(cross, main)
+	}
+	return core.Sz(main, cross)
+}
+
+// resolve turns the ratios into pane sizes that respect the limits.
+func (s *SplitPane) resolve(avail float32) {
+	s.avail = avail
+	fixed := make([]bool, len(s.panes))
+	for i := range s.panes {
+		s.panes[i].size = s.ratios[i] * avail
+	}
+	// Clamp panes to their limits and spread what that frees or consumes
+	// over the panes that are still free to flex.
+	for range len(s.panes) {
+		var used, flexRatio float32
+		for i := range s.panes {
+			p := &s.panes[i]
+			switch {
+			case p.collapsed:
+				p.size, fixed[i] = 0, true
+			case p.size < p.min:
+				p.size, fixed[i] = p.min, true
+			case p.size > p.max:
+				p.size, fixed[i] = p.max, true
+			}
+			used += p.size
+			if !fixed[i] {
+				flexRatio += s.ratios[i]
+			}
+		}
+		diff := avail - used
+		if abs32(diff) < 0.5 || flexRatio == 0 {
+			break
+		}
+		for i := range s.panes {
+			if !fixed[i] {
+				s.panes[i].size += diff * s.ratios[i] / flexRatio
+			}
+		}
+	}
+}
+
+// Layout implements core.Widget.
+func (s *SplitPane) Layout(ctx *core.LayoutContext) core.Size {
+	s.dir = ctx.Direction()
+	c := ctx.Constraints
+	gaps := float32(max(0, len(s.panes)-1)) * s.dividerSize
+	maxMain := s.main(core.Sz(c.MaxWidth, c.MaxHeight))
+	maxCross := s.cross(core.Sz(c.MaxWidth, c.MaxHeight))
+	if maxMain == core.Infinity {
+		// Without a bounded extent there is nothing to share; use the
+		// panes' natural sizes.
+		var total, cross float32
+		for i := range s.panes {
+			p := &s.panes[i]
+			if p.collapsed {
+				p.size = 0
+				continue
+			}
+			sz := ctx.Measure(p.content, core.Loose(s.size(core.Infinity, maxCross)))
+			p.size = core.Clamp(s.main(sz), p.min, p.max)
+			total += p.size
+			cross = max(cross, s.cross(sz))
+		}
+		if maxCross != core.Infinity {
+			cross = maxCross
+		}
+		return c.Constrain(s.size(total+gaps, cross))
+	}
+	s.resolve(max(0, maxMain-gaps))
+	cross := maxCross
+	if cross == core.Infinity {
+		cross = 0
+		for i := range s.panes {
+			main := s.panes[i].size
+			pc := core.Constraints{MinWidth: main, MaxWidth: main, MaxHeight: core.Infinity}
+			if s.axis == Vertical {
+				pc = core.Constraints{MaxWidth: core.Infinity, MinHeight: main, MaxHeight: main}
+			}
+			sz := ctx.Measure(s.panes[i].content, pc)
+			cross = max(cross, s.cross(sz))
+		}
+	}
+	for i := range s.panes {
+		ctx.Measure(s.panes[i].content, core.Tight(s.size(s.panes[i].size, cross)))
+	}
+	return c.Constrain(s.size(maxMain, cross))
+}
+
+// SetBounds implements core.Widget.
+func (s *SplitPane) SetBounds(r core.Rect) {
+	s.WidgetBase.SetBounds(r)
+	gaps := float32(max(0, len(s.panes)-1)) * s.dividerSize
+	if avail := max(0, s.main(r.Size())-gaps); avail != s.avail {
+		s.resolve(avail)
+	}
+	s.dividers = s.dividers[:0]
+	pos := float32(0)
+	cross := s.cross(r.Size())
+	for i := range s.panes {
+		p := &s.panes[i]
+		var pr, dr core.Rect
+		if s.axis == Vertical {
+			pr = core.R(r.X, r.Y+pos, cross, p.size)
+			dr = core.R(r.X, pr.Bottom(), cross, s.dividerSize)
+		} else {
+			pr = core.R(r.X+pos, r.Y, p.size, cross)
+			dr = s.dir.Mirror(core.R(pr.Right(), r.Y, s.dividerSize, cross), r)
+			pr = s.dir.Mirror(pr, r)
+		}
+		p.content.SetBounds(pr)
+		if i < len(s.panes)-1 {
+			s.dividers = append(s.dividers, dr)
+		}
+		pos += p.size + s.dividerSize
+	}
+}
+
+// Paint implements core.Widget.
+func (s *SplitPane) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	for i := range s.panes {
+		p := &s.panes[i]
+		if p.collapsed || p.size <= 0 {
+			continue
+		}
+		cv.Save()
+		cv.Clip(p.content.Bounds())
+		p.content.Paint(ctx)
+		cv.Restore()
+	}
+	for i, d := range s.dividers {
+		c := th.Colors.Outline.WithAlpha(0.4)
+		if i == s.drag || i == s.hover {
+			c = th.Colors.Primary
+		}
+		cv.DrawRect(d, core.Filled(c))
+	}
+}
+
+func (s *SplitPane) dividerAt(p core.Point) int {
+	for i, d := range s.dividers {
+		if d.Inset(core.UniformInsets(-2)).Contains(p) {
+			return i
+		}
+	}
+	return -1
+}
+
+// along returns the distance of p along the axis from the start of the
+// pane: from the top, or from the start edge of horizontal panes.
+func (s *SplitPane) along(p core.Point) float32 {
+	r := s.Bounds()
+	switch {
+	case s.axis == Vertical:
+		return p.Y - r.Y
+	case s.dir.IsRTL():
+		return r.Right() - p.X
+	}
+	return p.X - r.X
+}
+
+// offset returns the distance of pane i along the axis from the start of
+// the split pane.
+func (s *SplitPane) offset(i int) float32 {
+	var pos float32
+	for _, p := range s.panes[:i] {
+		pos += p.size + s.dividerSize
+	}
+	return pos
+}
+
+// moveDivider places divider i so that it starts at pos along the axis.
+func (s *SplitPane) moveDivider(i int, pos float32) {
+	a, b := &s.panes[i], &s.panes[i+1]
+	start := s.offset(i)
+	pair := a.size + b.size
+	first := pos - start
+	switch {
+	case a.collapsible && first < a.min/2:
+		first = 0
+	case b.collapsible && pair-first < b.min/2:
+		first = pair
+	default:
+		lo := max(a.min, pair-b.max)
+		hi := min(a.max, pair-b.min)
+		first = core.Clamp(first, lo, max(lo, hi))
+	}
+	a.collapsed = first == 0 && a.collapsible
+	b.collapsed = first == pair && b.collapsible
+	a.size, b.size = first, pair-first
+	if s.avail > 0 {
+		s.ratios[i] = a.size / s.avail
+		s.ratios[i+1] = b.size / s.avail
+	}
+	// A pane collapsed by dragging expands back to its minimum size.
+	if a.collapsed {
+		a.restore = max(a.min, 1) / max(s.avail, 1)
+	}
+	if b.collapsed {
+		b.restore = max(b.min, 1) / max(s.avail, 1)
+	}
+}
+
+// HandleEvent implements core.Widget.
+func (s *SplitPane) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	e, ok := ev.(event.MouseEvent)
+	if !ok {
+		return core.Ignored
+	}
+	switch e.Type {
+	case event.MouseMove:
+		if s.drag >= 0 {
+			s.moveDivider(s.drag, s.along(e.Position)-s.grab)
+			s.SetBounds(s.Bounds())
+			ctx.MarkNeedsLayout(s)
+			return core.Handled
+		}
+		if h := s.dividerAt(e.Position); h != s.hover {
+			s.hover = h
+			ctx.Repaint(s)
+		}
+	case event.MouseLeave:
+		if s.hover >= 0 && s.drag < 0 {
+			s.hover = -1
+			ctx.Repaint(s)
+		}
+	case event.MouseDown:
+		i := s.dividerAt(e.Position)
+		if i < 0 || e.Button != event.ButtonLeft {
+			return core.Ignored
+		}
+		if e.ClickCount == 2 {
+			s.toggleAt(i)
+			ctx.MarkNeedsLayout(s)
+			return core.Handled
+		}
+		s.drag = i
+		s.grab = s.along(e.Position) - s.offset(i) - s.panes[i].size
+		ctx.CapturePointer(s)
+		ctx.Repaint(s)
+		return core.Handled
+	case event.MouseUp:
+		if s.drag < 0 {
+			return core.Ignored
+		}
+		s.drag = -1
+		ctx.ReleasePointer()
+		s.notify()
+		ctx.Repaint(s)
+		return core.Handled
+	}
+	return core.Ignored
+}
+
+// toggleAt collapses or expands the collapsible pane next to divider i,
+// preferring a pane at the outer edge.
+func (s *SplitPane) toggleAt(i int) {
+	for _, j := range []int{i, i + 1} {
+		if s.panes[j].collapsed {
+			s.Expand(j)
+			return
+		}
+	}
+	candidates := []int{i + 1, i}
+	if i == 0 {
+		candidates = []int{0, 1}
+	}
+	for _, j := range candidates {
+		if s.panes[j].collapsible {
+			s.Collapse(j)
+			return
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package layout
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+)
+
+// paneSizes returns the sizes of the panes of s along its axis.
+func paneSizes(s *SplitPane) []float32 {
+	var out []float32
+	for _, p := range s.panes {
+		out = append(out, s.main(p.content.Bounds().Size()))
+	}
+	return out
+}
+
+// newSplit returns a split of three boxes along axis, with 4px dividers.
+func newSplit(axis Axis) *SplitPane {
+	return NewSplitPane(axis, newBox(10, 10), newBox(10, 10), newBox(10, 10)).DividerSize(4)
+}
+
+func TestSplitPaneSizes(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(s *SplitPane)
+		want  []float32
+	}{
+		{"equal", func(*SplitPane) {}, []float32{100, 100, 100}},
+		{"ratios", func(s *SplitPane) { s.SetRatios(1, 2, 1) }, []float32{75, 150, 75}},
+		{"wrong count ignored", func(s *SplitPane) { s.SetRatios(1, 2) }, []float32{100, 100, 100}},
+		{"minimum", func(s *SplitPane) { s.PaneLimits(0, 60, core.Infinity).SetRatios(1, 4, 1) }, []float32{60, 192, 48}},
+		{"maximum", func(s *SplitPane) { s.PaneLimits(1, 0, 40) }, []float32{130, 40, 130}},
+		{"collapsed", func(s *SplitPane) { s.Collapsible(2, true).SetRatios(1, 1, 0) }, []float32{150, 150, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newSplit(Horizontal)
+			tt.setup(s)
+			layoutIn(s, core.R(0, 0, 308, 100), core.LeftToRight)
+			if got := paneSizes(s); !slices.Equal(got, tt.want) {
+				t.Errorf("sizes = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitPaneCollapse(t *testing.T) {
+	s := newSplit(Vertical).Collapsible(0, true)
+	var got [][]float32
+	s.OnRatiosChange(func(r []float32) { got = append(got, r) })
+	s.Collapse(1) // Not collapsible.
+	s.Collapse(0)
+	if !s.IsCollapsed(0) {
+		t.Fatal("IsCollapsed(0) = false after Collapse")
+	}
+	layoutIn(s, core.R(0, 0, 100, 308), core.LeftToRight)
+	if sizes := paneSizes(s); !slices.Equal(sizes, []float32{0, 200, 100}) {
+		t.Errorf("sizes = %v, want [0 200 100]", sizes)
+	}
+	s.Expand(0)
+	if s.IsCollapsed(0) {
+		t.Error("IsCollapsed(0) = true after Expand")
+	}
+	third := float32(1) / 3
+	want := [][]float32{{0, 2 * third, third}, {third, third, third}}
+	if len(got) != 2 || !slices.Equal(got[0], want[0]) || !slices.Equal(got[1], want[1]) {
+		t.Errorf("ratios = %v, want %v", got, want)
+	}
+}
+
+func TestSplitPaneDrag(t *testing.T) {
+	tests := []struct {
+		name        string
+		collapsible bool
+		to          float32 // Where divider 0 is dragged to.
+		want        []float32
+	}{
+		{"within limits", false, 150, []float32{150, 50, 100}},
+		{"kept above the minimum", false, 20, []float32{40, 160, 100}},
+		{"snapped shut", true, 10, []float32{0, 200, 100}},
+		{"open above half the minimum", true, 25, []float32{40, 160, 100}},
+		{"kept below the next minimum", false, 199, []float32{160, 40, 100}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newSplit(Horizontal).PaneLimits(0, 40, core.Infinity).PaneLimits(1, 40, core.Infinity).
+				Collapsible(0, tt.collapsible)
+			var changes int
+			s.OnRatiosChange(func([]float32) { changes++ })
+			ctx := layoutIn(s, core.R(0, 0, 308, 100), core.LeftToRight)
+			at := s.dividers[0].Center()
+			s.HandleEvent(ctx, event.MouseEvent{Type: event.MouseDown, Position: at, Button: event.ButtonLeft, ClickCount: 1})
+			s.HandleEvent(ctx, event.MouseEvent{Type: event.MouseMove, Position: core.Pt(tt.to+2, 50)})
+			s.HandleEvent(ctx, event.MouseEvent{Type: event.MouseUp, Position: core.Pt(tt.to+2, 50)})
+			if got := paneSizes(s); !slices.Equal(got, tt.want) {
+				t.Errorf("sizes = %v, want %v", got, tt.want)
+			}
+			if changes != 1 {
+				t.Errorf("%d ratio changes, want 1", changes)
+			}
+		})
+	}
+}
+
+func TestSplitPaneDoubleClick(t *testing.T) {
+	s := newSplit(Horizontal).Collapsible(2, true)
+	ctx := layoutIn(s, core.R(0, 0, 308, 100), core.LeftToRight)
+	click := func() {
+		at := s.dividers[1].Center()
+		s.HandleEvent(ctx, event.MouseEvent{Type: event.MouseDown, Position: at, Button: event.ButtonLeft, ClickCount: 2})
+	}
+	click()
+	if !s.IsCollapsed(2) {
+		t.Fatal("a double click did not collapse the pane")
+	}
+	click()
+	if s.IsCollapsed(2) {
+		t.Error("a second double click did not expand the pane")
+	}
+}
+
+func TestSplitPaneRightToLeft(t *testing.T) {
+	s := newSplit(Horizontal)
+	ctx := layoutIn(s, core.R(0, 0, 308, 100), core.RightToLeft)
+	var xs []float32
+	for _, p := range s.panes {
+		xs = append(xs, p.content.Bounds().X)
+	}
+	if want := []float32{208, 104, 0}; !slices.Equal(xs, want) {
+		t.Errorf("panes at %v, want %v", xs, want)
+	}
+	if got := s.dividers[0].X; got != 204 {
+		t.Errorf("divider 0 at %v, want 204", got)
+	}
+
+	// Dragged left, the first divider widens the first pane.
+	at := s.dividers[0].Center()
+	s.HandleEvent(ctx, event.MouseEvent{Type: event.MouseDown, Position: at, Button: event.ButtonLeft, ClickCount: 1})
+	s.HandleEvent(ctx, event.MouseEvent{Type: event.MouseMove, Position: core.Pt(at.X-50, 50)})
+	s.HandleEvent(ctx, event.MouseEvent{Type: event.MouseUp, Position: core.Pt(at.X-50, 50)})
+	if got, want := paneSizes(s), []float32{150, 50, 100}; !slices.Equal(got, want) {
+		t.Errorf("sizes = %v, want %v", got, want)
+	}
+}
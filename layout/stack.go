@@ -0,0 +1,323 @@
+package layout
+
+import (
+	"slices"
+
+	"github.com/gogpu/ui/core"
+)
+
+type stackItem struct {
+	child    core.Widget
+	view     core.Widget // The child, or its item in the stagger.
+	grow     float32
+	size     core.Size
+	baseline float32 // With AlignBaseline; -1 without text.
+}
+
+// Stack places children one after another along an axis: NewHStack in a
+// row, NewVStack in a column.
+//
+// Spacing separates the children, Justify distributes the free space
+// along the axis and Align positions the children across it. With
+// AlignBaseline the children of a row line up on the baseline of their
+// first line of text, so that labels of different sizes read as one line.
+// A child given a weight with Grow takes that share of the space the
+// others leave. In right-to-left layouts a row runs from the right edge
+// and the children of a column are aligned from it.
+type Stack struct {
+	core.WidgetBase
+
+	axis    Axis
+	spacing float32
+	justify Justify
+	align   Align
+
+	items    []stackItem
+	stagger  *Stagger
+	dir      core.Direction
+	main     float32 // Of the children and the spacing between them.
+	extent   float32 // Along the axis, as laid out.
+	cross    float32
+	baseline float32 // Of a row with AlignBaseline, from its top.
+}
+
+// NewHStack returns a row of children, from the start edge.
+func NewHStack(children ...core.Widget) *Stack {
+	return newStack(Horizontal, children)
+}
+
+// NewVStack returns a column of children, from the top.
+func NewVStack(children ...core.Widget) *Stack {
+	return newStack(Vertical, children)
+}
+
+func newStack(axis Axis, children []core.Widget) *Stack {
+	s := &Stack{axis: axis}
+	return s.Add(children...)
+}
+
+// Add appends children.
+func (s *Stack) Add(children ...core.Widget) *Stack {
+	for _, c := range children {
+		s.items = append(s.items, stackItem{child: c})
+	}
+	s.sync()
+	return s
+}
+
+// Remove removes child.
+func (s *Stack) Remove(child core.Widget) {
+	if i := s.index(child); i >= 0 {
+		s.items = slices.Delete(s.items, i, i+1)
+		s.sync()
+	}
+}
+
+// Stagger makes the children enter through st one after another, in
+// their order, or with nil at once. Children added later enter with them
+// until the entrance has started and appear at once after that.
+func (s *Stack) Stagger(st *Stagger) *Stack {
+	s.stagger = st
+	for i := range s.items {
+		s.items[i].view = nil
+	}
+	s.sync()
+	return s
+}
+
+// Grow makes child take share weight of the space along the axis that
+// the children without a weight leave, or gives it its own size again
+// with a weight of 0.
+func (s *Stack) Grow(child core.Widget, weight float32) *Stack {
+	if i := s.index(child); i >= 0 {
+		s.items[i].grow = max(0, weight)
+	}
+	return s
+}
+
+// Spacing sets the gap between children.
+func (s *Stack) Spacing(px float32) *Stack {
+	s.spacing = max(0, px)
+	return s
+}
+
+// Justify sets how the free space along the axis is distributed.
+func (s *Stack) Justify(j Justify) *Stack {
+	s.justify = j
+	return s
+}
+
+// Align sets how children narrower than the stack across its axis are
+// positioned.
+func (s *Stack) Align(a Align) *Stack {
+	s.align = a
+	return s
+}
+
+func (s *Stack) index(child core.Widget) int {
+	return slices.IndexFunc(s.items, func(it stackItem) bool { return it.child == child })
+}
+
+func (s *Stack) sync() {
+	children := make([]core.Widget, len(s.items))
+	for i := range s.items {
+		it := &s.items[i]
+		if it.view == nil {
+			it.view = it.child
+			if s.stagger != nil {
+				it.view = s.stagger.Item(i, it.child)
+			}
+		}
+		children[i] = it.view
+	}
+	s.SetChildren(children...)
+}
+
+func (s *Stack) mainOf(sz core.Size) float32 {
+	if s.axis == Vertical {
+		return sz.Height
+	}
+	return sz.Width
+}
+
+func (s *Stack) crossOf(sz core.Size) float32 {
+	if s.axis == Vertical {
+		return sz.Width
+	}
+	return sz.Height
+}
+
+func (s *Stack) size(main, cross float32) core.Size {
+	if s.axis == Vertical {
+		return core.Sz(cross, main)
+	}
+	return core.Sz(main, cross)
+}
+
+// constraints returns the constraints of a child between main and
+// maxMain along the axis and up to maxCross across it.
+func (s *Stack) constraints(main, maxMain, maxCross float32) core.Constraints {
+	if s.axis == Vertical {
+		return core.Constraints{MinHeight: main, MaxHeight: maxMain, MaxWidth: maxCross}
+	}
+	return core.Constraints{MinWidth: main, MaxWidth: maxMain, MaxHeight: maxCross}
+}
+
+func (s *Stack) baselined() bool {
+	return s.align == AlignBaseline && s.axis == Horizontal
+}
+
+// Layout implements core.Widget.
+func (s *Stack) Layout(ctx *core.LayoutContext) core.Size {
+	s.dir = ctx.Direction()
+	c := ctx.Constraints
+	maxMain := s.mainOf(core.Sz(c.MaxWidth, c.MaxHeight))
+	maxCross := s.crossOf(core.Sz(c.MaxWidth, c.MaxHeight))
+	s.main, s.cross, s.baseline = 0, 0, 0
+	var weights float32
+	for i := range s.items {
+		it := &s.items[i]
+		if i > 0 {
+			s.main += s.spacing
+		}
+		if it.grow > 0 && maxMain < core.Infinity {
+			weights += it.grow
+			continue
+		}
+		it.size = ctx.Measure(it.view, s.constraints(0, maxMain, maxCross))
+		s.main += s.mainOf(it.size)
+	}
+	if weights > 0 {
+		free := max(0, maxMain-s.main)
+		for i := range s.items {
+			it := &s.items[i]
+			if it.grow <= 0 {
+				continue
+			}
+			m := free * it.grow / weights
+			it.size = ctx.Measure(it.view, s.constraints(m, m, maxCross))
+			s.main += s.mainOf(it.size)
+		}
+	}
+	var descent float32
+	for i := range s.items {
+		it := &s.items[i]
+		s.cross = max(s.cross, s.crossOf(it.size))
+		if !s.baselined() {
+			continue
+		}
+		b, ok := core.BaselineOf(it.view)
+		it.baseline = b
+		if !ok {
+			it.baseline, b = -1, it.size.Height
+		}
+		s.baseline = max(s.baseline, b)
+		descent = max(descent, it.size.Height-b)
+	}
+	if s.baselined() {
+		s.cross = max(s.cross, s.baseline+descent)
+	}
+	main := s.main
+	if s.justify != JustifyStart && maxMain < core.Infinity {
+		main = maxMain
+	}
+	size := c.Constrain(s.size(main, s.cross))
+	s.extent = s.mainOf(size)
+	if s.align == AlignStretch {
+		cross := s.crossOf(size)
+		for i := range s.items {
+			it := &s.items[i]
+			if s.crossOf(it.size) < cross {
+				it.size = ctx.Measure(it.view, core.Tight(s.size(s.mainOf(it.size), cross)))
+			}
+		}
+	}
+	return size
+}
+
+// IntrinsicWidth implements core.IntrinsicSizer. A row is as wide as its
+// children and the spacing between them, and a column as its widest child.
+func (s *Stack) IntrinsicWidth(ctx *core.LayoutContext, height float32) (minWidth, maxWidth float32) {
+	return s.intrinsic(Horizontal, func(c core.Widget) (float32, float32) { return ctx.IntrinsicWidth(c, height) })
+}
+
+// IntrinsicHeight implements core.IntrinsicSizer. A column is as tall as
+// its children and the spacing between them, and a row as its tallest
+// child.
+func (s *Stack) IntrinsicHeight(ctx *core.LayoutContext, width float32) (minHeight, maxHeight float32) {
+	return s.intrinsic(Vertical, func(c core.Widget) (float32, float32) { return ctx.IntrinsicHeight(c, width) })
+}
+
+func (s *Stack) intrinsic(axis Axis, size func(core.Widget) (float32, float32)) (lo, hi float32) {
+	for i, it := range s.items {
+		cmin, cmax := size(it.view)
+		if axis != s.axis {
+			lo, hi = max(lo, cmin), max(hi, cmax)
+			continue
+		}
+		lo += cmin
+		hi += cmax
+		if i > 0 {
+			lo += s.spacing
+			hi += s.spacing
+		}
+	}
+	return lo, hi
+}
+
+// crossOffset returns the position of it across a stack of extent cross.
+func (s *Stack) crossOffset(it stackItem, cross float32) float32 {
+	if !s.baselined() {
+		return s.align.offset(cross, s.crossOf(it.size))
+	}
+	b := it.baseline
+	if b < 0 {
+		b = it.size.Height
+	}
+	return s.baseline - b
+}
+
+// Baseline implements core.Baseliner with the baseline of the first child
+// with text in a row, or of the first child of a column.
+func (s *Stack) Baseline() (float32, bool) {
+	if len(s.items) == 0 {
+		return 0, false
+	}
+	if s.baselined() {
+		return s.baseline, slices.ContainsFunc(s.items, func(it stackItem) bool { return it.baseline >= 0 })
+	}
+	if s.axis == Vertical {
+		lead, _ := s.justify.offsets(s.extent-s.main, len(s.items))
+		b, ok := core.BaselineOf(s.items[0].view)
+		return lead + b, ok
+	}
+	for _, it := range s.items {
+		if b, ok := core.BaselineOf(it.view); ok {
+			return s.crossOffset(it, s.cross) + b, true
+		}
+	}
+	return 0, false
+}
+
+// SetBounds implements core.Widget.
+func (s *Stack) SetBounds(r core.Rect) {
+	s.WidgetBase.SetBounds(r)
+	lead, between := s.justify.offsets(s.mainOf(r.Size())-s.main, len(s.items))
+	cross := s.crossOf(r.Size())
+	at := lead
+	for _, it := range s.items {
+		sz := it.size
+		off := s.crossOffset(it, cross)
+		cr := core.R(r.X+at, r.Y+off, sz.Width, sz.Height)
+		if s.axis == Vertical {
+			cr = core.R(r.X+off, r.Y+at, sz.Width, sz.Height)
+		}
+		it.view.SetBounds(s.dir.Mirror(cr, r))
+		at += s.mainOf(sz) + s.spacing + between
+	}
+}
+
+// Paint implements core.Widget.
+func (s *Stack) Paint(ctx *core.PaintContext) {
+	core.PaintChildren(ctx, s.Children())
+}
@@ -0,0 +1,247 @@
+package layout
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogpu/ui/anim"
+	"github.com/gogpu/ui/core"
+)
+
+// box is a leaf of a fixed size, with a baseline if base is not negative.
+type box struct {
+	core.WidgetBase
+	size core.Size
+	base float32
+}
+
+func newBox(w, h float32) *box {
+	return &box{size: core.Sz(w, h), base: -1}
+}
+
+// text returns a box with a baseline at base, as a line of text.
+func text(w, h, base float32) *box {
+	return &box{size: core.Sz(w, h), base: base}
+}
+
+func (b *box) Layout(ctx *core.LayoutContext) core.Size {
+	return ctx.Constraints.Constrain(b.size)
+}
+
+func (b *box) Baseline() (float32, bool) {
+	return b.base, b.base >= 0
+}
+
+func (b *box) Paint(*core.PaintContext) {}
+
+// loose lays its child out at its top left under loose constraints of
+// its own size.
+type loose struct {
+	core.WidgetBase
+	child core.Widget
+	size  core.Size
+}
+
+func (l *loose) Layout(ctx *core.LayoutContext) core.Size {
+	c := ctx.Constraints
+	l.size = ctx.Measure(l.child, core.Constraints{MaxWidth: c.MaxWidth, MaxHeight: c.MaxHeight})
+	return c.Constrain(core.Sz(c.MaxWidth, c.MaxHeight))
+}
+
+func (l *loose) SetBounds(r core.Rect) {
+	l.WidgetBase.SetBounds(r)
+	l.child.SetBounds(core.R(r.X, r.Y, l.size.Width, l.size.Height))
+}
+
+func (l *loose) Paint(*core.PaintContext) {}
+
+// layoutIn lays w out in a window of the size of bounds, under loose
+// constraints, with direction dir.
+func layoutIn(w core.Widget, bounds core.Rect, dir core.Direction) *core.Context {
+	ctx := core.NewContext()
+	ctx.SetDirection(dir)
+	l := &loose{child: w}
+	l.SetChildren(w)
+	ctx.LayoutRoot(l, bounds)
+	return ctx
+}
+
+func TestStackPlacesChildren(t *testing.T) {
+	tests := []struct {
+		name  string
+		stack func(a, b, c core.Widget) *Stack
+		dir   core.Direction
+		want  []core.Rect
+	}{
+		{
+			name:  "row",
+			stack: func(a, b, c core.Widget) *Stack { return NewHStack(a, b, c).Spacing(10) },
+			want:  []core.Rect{core.R(0, 0, 20, 10), core.R(30, 0, 30, 20), core.R(70, 0, 10, 5)},
+		},
+		{
+			name:  "column",
+			stack: func(a, b, c core.Widget) *Stack { return NewVStack(a, b, c).Spacing(10) },
+			want:  []core.Rect{core.R(0, 0, 20, 10), core.R(0, 20, 30, 20), core.R(0, 50, 10, 5)},
+		},
+		{
+			name:  "row right to left",
+			stack: func(a, b, c core.Widget) *Stack { return NewHStack(a, b, c).Spacing(10) },
+			dir:   core.RightToLeft,
+			want:  []core.Rect{core.R(60, 0, 20, 10), core.R(20, 0, 30, 20), core.R(0, 0, 10, 5)},
+		},
+		{
+			name:  "row centered",
+			stack: func(a, b, c core.Widget) *Stack { return NewHStack(a, b, c).Justify(JustifyCenter).Align(AlignCenter) },
+			want:  []core.Rect{core.R(70, 5, 20, 10), core.R(90, 0, 30, 20), core.R(120, 7.5, 10, 5)},
+		},
+		{
+			name: "row spaced between",
+			stack: func(a, b, c core.Widget) *Stack {
+				return NewHStack(a, b, c).Justify(JustifySpaceBetween).Align(AlignEnd)
+			},
+			want: []core.Rect{core.R(0, 10, 20, 10), core.R(90, 0, 30, 20), core.R(190, 15, 10, 5)},
+		},
+		{
+			name:  "row stretched",
+			stack: func(a, b, c core.Widget) *Stack { return NewHStack(a, b, c).Align(AlignStretch) },
+			want:  []core.Rect{core.R(0, 0, 20, 20), core.R(20, 0, 30, 20), core.R(50, 0, 10, 20)},
+		},
+		{
+			name: "row growing",
+			stack: func(a, b, c core.Widget) *Stack {
+				return NewHStack(a, b, c).Spacing(10).Grow(a, 1).Grow(c, 3)
+			},
+			want: []core.Rect{core.R(0, 0, 37.5, 10), core.R(47.5, 0, 30, 20), core.R(87.5, 0, 112.5, 5)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kids := []*box{newBox(20, 10), newBox(30, 20), newBox(10, 5)}
+			s := tt.stack(kids[0], kids[1], kids[2])
+			layoutIn(s, core.R(0, 0, 200, 100), tt.dir)
+			for i, k := range kids {
+				if got := k.Bounds(); got != tt.want[i] {
+					t.Errorf("child %d: bounds = %v, want %v", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestStackBaseline(t *testing.T) {
+	// A large and a small label and an icon line up on the large label's
+	// baseline, and the icon sits on it.
+	big, small, icon := text(40, 30, 24), text(20, 12, 9), newBox(10, 10)
+	s := NewHStack(big, small, icon).Align(AlignBaseline)
+	layoutIn(s, core.R(0, 0, 200, 100), core.LeftToRight)
+
+	want := map[*box]core.Rect{
+		big:   core.R(0, 0, 40, 30),
+		small: core.R(40, 15, 20, 12),
+		icon:  core.R(60, 14, 10, 10),
+	}
+	for b, r := range want {
+		if got := b.Bounds(); got != r {
+			t.Errorf("bounds = %v, want %v", got, r)
+		}
+	}
+	if got := s.Bounds().Height; got != 30 {
+		t.Errorf("height = %v, want 30", got)
+	}
+	if b, ok := s.Baseline(); !ok || b != 24 {
+		t.Errorf("Baseline() = %v, %v, want 24, true", b, ok)
+	}
+}
+
+func TestStackBaselineDescent(t *testing.T) {
+	// The row is as tall as the deepest ascent and descent together.
+	a, b := text(10, 20, 18), text(10, 20, 6)
+	s := NewHStack(a, b).Align(AlignBaseline)
+	layoutIn(s, core.R(0, 0, 100, 100), core.LeftToRight)
+	if got := s.Bounds().Height; got != 32 {
+		t.Errorf("height = %v, want 32", got)
+	}
+	if got := b.Bounds().Y; got != 12 {
+		t.Errorf("second child y = %v, want 12", got)
+	}
+}
+
+func TestStackRemove(t *testing.T) {
+	a, b := newBox(10, 10), newBox(20, 10)
+	s := NewHStack(a, b)
+	s.Remove(a)
+	s.Remove(a)
+	if got := s.Children(); len(got) != 1 || got[0] != b {
+		t.Fatalf("children = %v, want [b]", got)
+	}
+	layoutIn(s, core.R(0, 0, 100, 100), core.LeftToRight)
+	if got := b.Bounds().X; got != 0 {
+		t.Errorf("x = %v, want 0", got)
+	}
+}
+
+func TestStackIntrinsic(t *testing.T) {
+	row := NewHStack(newBox(10, 5), newBox(20, 15)).Spacing(4)
+	col := NewVStack(newBox(10, 5), newBox(20, 15)).Spacing(4)
+	ctx := &core.LayoutContext{Context: core.NewContext()}
+	tests := []struct {
+		name   string
+		got    func() (float32, float32)
+		lo, hi float32
+	}{
+		{"row width", func() (float32, float32) { return row.IntrinsicWidth(ctx, core.Infinity) }, 34, 34},
+		{"row height", func() (float32, float32) { return row.IntrinsicHeight(ctx, core.Infinity) }, 15, 15},
+		{"column width", func() (float32, float32) { return col.IntrinsicWidth(ctx, core.Infinity) }, 20, 20},
+		{"column height", func() (float32, float32) { return col.IntrinsicHeight(ctx, core.Infinity) }, 24, 24},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if lo, hi := tt.got(); lo != tt.lo || hi != tt.hi {
+				t.Errorf("= %v, %v, want %v, %v", lo, hi, tt.lo, tt.hi)
+			}
+		})
+	}
+}
+
+func TestStackStagger(t *testing.T) {
+	kids := []core.Widget{newBox(10, 10), newBox(10, 10), newBox(10, 10)}
+	st := NewStagger(100 * time.Millisecond).Duration(100 * time.Millisecond).Curve(anim.Linear)
+	s := NewVStack(kids...).Stagger(st)
+	for i, c := range s.Children() {
+		if c == kids[i] {
+			t.Fatalf("child %d is not an item of the stagger", i)
+		}
+	}
+
+	t0 := time.Unix(0, 0)
+	ctx := core.NewContext()
+	ctx.SetNow(t0)
+	l := &loose{child: s}
+	l.SetChildren(s)
+	ctx.LayoutRoot(l, core.R(0, 0, 100, 100))
+	ctx.RunAfterFrame()
+	if !st.IsRunning() {
+		t.Fatal("the entrance did not start")
+	}
+	clock := anim.ClockOf(ctx)
+	clock.Tick(t0)
+	clock.Tick(t0.Add(150 * time.Millisecond))
+	for i, want := range []float32{1, 0.5, 0} {
+		if got := st.progress(i); got != want {
+			t.Errorf("progress(%d) = %v, want %v", i, got, want)
+		}
+	}
+
+	// The children keep their place, and are still found by themselves.
+	if got, want := kids[2].Bounds(), core.R(0, 20, 10, 10); got != want {
+		t.Errorf("bounds = %v, want %v", got, want)
+	}
+	s.Remove(kids[1])
+	if got := len(s.Children()); got != 2 {
+		t.Errorf("%d children after Remove, want 2", got)
+	}
+	s.Stagger(nil)
+	if got := s.Children(); got[0] != kids[0] || got[1] != kids[2] {
+		t.Errorf("children = %v, want the children themselves", got)
+	}
+}
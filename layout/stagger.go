@@ -0,0 +1,260 @@
+package layout
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/gogpu/ui/anim"
+	"github.com/gogpu/ui/core"
+)
+
+const (
+	staggerDuration = 300 * time.Millisecond
+	staggerOffset   = 16
+)
+
+// Stagger runs the entrance of the items of a list one after another,
+// each fading in and sliding up a little a delay after the one before:
+//
+//	enter := layout.NewStagger(40 * time.Millisecond)
+//	list := layout.NewVStack(rows...).Stagger(enter)
+//
+// Stacks and virtual lists given a stagger make their children its items;
+// for other containers Item wraps each child.
+//
+// The items of the first layout enter, in the order of their indexes. As
+// the timing of each follows from its index and the one animation of the
+// stagger, an item built again, as virtual lists do, picks up where its
+// entrance is. Items first laid out after those, such as rows scrolled
+// into view, appear at once, and Cancel ends the entrances, as scroll
+// views and virtual lists given the stagger do when scrolled.
+type Stagger struct {
+	delay  time.Duration
+	dur    time.Duration
+	curve  anim.Curve
+	offset float32
+
+	ctx     *core.Context
+	ctl     *anim.Controller
+	slots   map[int]int // The order of entrance of each index, once started.
+	items   map[int]*staggerItem
+	total   time.Duration
+	started bool
+	done    bool
+}
+
+// NewStagger returns a stagger starting each entrance delay after the
+// one before.
+func NewStagger(delay time.Duration) *Stagger {
+	return &Stagger{
+		delay:  delay,
+		dur:    staggerDuration,
+		curve:  anim.Standard,
+		offset: staggerOffset,
+		slots:  make(map[int]int),
+		items:  make(map[int]*staggerItem),
+	}
+}
+
+// Duration sets how long the entrance of each item takes, 300 ms by
+// default.
+func (s *Stagger) Duration(d time.Duration) *Stagger {
+	s.dur = d
+	return s
+}
+
+// Curve sets the easing of the entrances, anim.Standard by default.
+func (s *Stagger) Curve(c anim.Curve) *Stagger {
+	if c == nil {
+		c = anim.Linear
+	}
+	s.curve = c
+	return s
+}
+
+// Offset sets how far below its place an item starts, 16 pixels by
+// default, or 0 for items only fading in.
+func (s *Stagger) Offset(px float32) *Stagger {
+	s.offset = px
+	return s
+}
+
+// Item returns child entering as the item at index.
+func (s *Stagger) Item(index int, child core.Widget) core.Widget {
+	it := &staggerItem{stagger: s, index: index, child: child}
+	it.SetChildren(child)
+	return it
+}
+
+// IsRunning reports whether entrances are waiting or running.
+func (s *Stagger) IsRunning() bool {
+	return len(s.slots) > 0 && !s.done
+}
+
+// Cancel ends the entrances, showing every item in its place.
+func (s *Stagger) Cancel() {
+	if s.done {
+		return
+	}
+	s.done = true
+	if s.ctl != nil {
+		s.ctl.Stop()
+	}
+	s.repaint()
+	clear(s.items)
+}
+
+// Replay runs the entrances again for the items of the next layout, such
+// as after the list is filled with new content.
+func (s *Stagger) Replay() {
+	if s.ctl != nil {
+		s.ctl.Stop()
+	}
+	clear(s.slots)
+	clear(s.items)
+	s.started, s.done = false, false
+}
+
+// enter gives the item at index the next place in the entrance, unless
+// the entrance has started.
+func (s *Stagger) enter(ctx *core.Context, it *staggerItem) {
+	if s.done {
+		return
+	}
+	if _, ok := s.slots[it.index]; ok {
+		// Built again while entering.
+		s.items[it.index] = it
+		return
+	}
+	if s.started {
+		return
+	}
+	s.slots[it.index] = len(s.slots)
+	s.items[it.index] = it
+	if len(s.slots) == 1 {
+		// The entrance starts once the frame has laid all of them out.
+		s.ctx = ctx
+		ctx.AfterFrame(s.start)
+	}
+}
+
+func (s *Stagger) start() {
+	if s.done || len(s.slots) == 0 {
+		return
+	}
+	s.started = true
+	// Items enter in the order of their indexes, whatever that of their
+	// layout.
+	for slot, index := range slices.Sorted(maps.Keys(s.slots)) {
+		s.slots[index] = slot
+	}
+	s.total = s.dur + time.Duration(len(s.slots)-1)*s.delay
+	if s.ctl == nil {
+		s.ctl = anim.NewController(anim.ClockOf(s.ctx), s.total)
+		s.ctl.Listen(func(float32) { s.repaint() })
+		s.ctl.OnEnd(func() {
+			s.done = true
+			clear(s.items)
+		})
+	}
+	s.ctl.Duration(s.total).SetProgress(0)
+	s.ctl.Forward()
+}
+
+// repaint repaints the items entering.
+func (s *Stagger) repaint() {
+	if s.ctx == nil {
+		return
+	}
+	for _, it := range s.items {
+		if !it.entered {
+			s.ctx.InvalidateRect(it.area())
+		}
+	}
+}
+
+// progress returns how far the entrance of the item at index is, eased.
+func (s *Stagger) progress(index int) float32 {
+	slot, ok := s.slots[index]
+	if s.done || !ok {
+		return 1
+	}
+	if !s.started {
+		return 0
+	}
+	// The time since the entrance of the item started.
+	elapsed := time.Duration(float64(s.ctl.Progress())*float64(s.total)) - time.Duration(slot)*s.delay
+	if s.dur <= 0 {
+		// Items without an entrance appear in their turn.
+		if elapsed >= 0 {
+			return 1
+		}
+		return 0
+	}
+	t := float32(elapsed) / float32(s.dur)
+	return s.curve(min(max(t, 0), 1))
+}
+
+// staggerItem is an item of a Stagger.
+type staggerItem struct {
+	core.WidgetBase
+	stagger *Stagger
+	index   int
+	child   core.Widget
+	entered bool // Set once it is shown in its place.
+}
+
+// area returns what the item may paint over while entering.
+func (it *staggerItem) area() core.Rect {
+	b := it.Bounds()
+	b.Height += it.stagger.offset
+	return b
+}
+
+// Layout implements core.Widget.
+func (it *staggerItem) Layout(ctx *core.LayoutContext) core.Size {
+	it.stagger.enter(ctx.Context, it)
+	return ctx.Measure(it.child, ctx.Constraints)
+}
+
+// IntrinsicWidth implements core.IntrinsicSizer.
+func (it *staggerItem) IntrinsicWidth(ctx *core.LayoutContext, height float32) (minWidth, maxWidth float32) {
+	return ctx.IntrinsicWidth(it.child, height)
+}
+
+// IntrinsicHeight implements core.IntrinsicSizer.
+func (it *staggerItem) IntrinsicHeight(ctx *core.LayoutContext, width float32) (minHeight, maxHeight float32) {
+	return ctx.IntrinsicHeight(it.child, width)
+}
+
+// Baseline implements core.Baseliner with the child's baseline.
+func (it *staggerItem) Baseline() (float32, bool) {
+	return core.BaselineOf(it.child)
+}
+
+// SetBounds implements core.Widget.
+func (it *staggerItem) SetBounds(r core.Rect) {
+	it.WidgetBase.SetBounds(r)
+	it.child.SetBounds(r)
+}
+
+// Paint implements core.Widget.
+func (it *staggerItem) Paint(ctx *core.PaintContext) {
+	p := it.stagger.progress(it.index)
+	if p >= 1 {
+		it.entered = true
+		it.child.Paint(ctx)
+		return
+	}
+	if p <= 0 {
+		return
+	}
+	cv := ctx.Canvas
+	cv.Save()
+	cv.Translate(0, it.stagger.offset*(1-p))
+	pc := *ctx
+	pc.Canvas = core.Fade(cv, p)
+	it.child.Paint(&pc)
+	cv.Restore()
+}
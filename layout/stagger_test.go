@@ -0,0 +1,94 @@
+package layout
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogpu/ui/anim"
+	"github.com/gogpu/ui/core"
+)
+
+// staggered lays out a stack of the items of st at the given indexes, in
+// that order, starts their entrance at t0 and returns the clock it runs
+// on.
+func staggered(t *testing.T, st *Stagger, t0 time.Time, indexes ...int) (*core.Context, *anim.Clock) {
+	t.Helper()
+	var items []core.Widget
+	for _, i := range indexes {
+		items = append(items, st.Item(i, newBox(10, 10)))
+	}
+	ctx := core.NewContext()
+	ctx.SetNow(t0)
+	ctx.LayoutRoot(NewVStack(items...), core.R(0, 0, 100, 100))
+	ctx.RunAfterFrame()
+	if !st.IsRunning() {
+		t.Fatal("the entrance did not start")
+	}
+	clock := anim.ClockOf(ctx)
+	clock.Tick(t0)
+	return ctx, clock
+}
+
+func TestStaggerProgress(t *testing.T) {
+	tests := []struct {
+		name    string
+		dur     time.Duration
+		indexes []int
+		at      time.Duration
+		want    []float32 // By index.
+	}{
+		{"first entering", 100 * time.Millisecond, []int{0, 1, 2}, 50 * time.Millisecond, []float32{0.5, 0, 0}},
+		{"second entering", 100 * time.Millisecond, []int{0, 1, 2}, 150 * time.Millisecond, []float32{1, 0.5, 0}},
+		{"all entered", 100 * time.Millisecond, []int{0, 1, 2}, 300 * time.Millisecond, []float32{1, 1, 1}},
+		{"in the order of indexes", 100 * time.Millisecond, []int{2, 0, 1}, 150 * time.Millisecond, []float32{1, 0.5, 0}},
+		{"no duration, first", 0, []int{0, 1, 2}, 0, []float32{1, 0, 0}},
+		{"no duration, second", 0, []int{0, 1, 2}, 150 * time.Millisecond, []float32{1, 1, 0}},
+		{"no duration, all", 0, []int{0, 1, 2}, 200 * time.Millisecond, []float32{1, 1, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := NewStagger(100 * time.Millisecond).Duration(tt.dur).Curve(anim.Linear)
+			t0 := time.Unix(0, 0)
+			_, clock := staggered(t, st, t0, tt.indexes...)
+			clock.Tick(t0.Add(tt.at))
+			for i, want := range tt.want {
+				if got := st.progress(i); got != want {
+					t.Errorf("progress(%d) = %v, want %v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestStaggerEnd(t *testing.T) {
+	st := NewStagger(100 * time.Millisecond).Duration(100 * time.Millisecond)
+	t0 := time.Unix(0, 0)
+	ctx, clock := staggered(t, st, t0, 0, 1)
+
+	// Items laid out once the entrance started appear at once.
+	ctx.LayoutRoot(st.Item(5, newBox(10, 10)), core.R(0, 0, 10, 10))
+	if got := st.progress(5); got != 1 {
+		t.Errorf("progress of a later item = %v, want 1", got)
+	}
+
+	clock.Tick(t0.Add(200 * time.Millisecond))
+	if st.IsRunning() {
+		t.Error("running after the last entrance")
+	}
+	// Replayed, the items of the next layout enter again.
+	st.Replay()
+	_, clock = staggered(t, st, t0, 0, 1)
+	if got := st.progress(1); got != 0 {
+		t.Errorf("replayed, progress(1) = %v, want 0", got)
+	}
+	clock.Tick(t0.Add(50 * time.Millisecond))
+	st.Cancel()
+	if st.IsRunning() {
+		t.Error("running after Cancel")
+	}
+	for i := range 2 {
+		if got := st.progress(i); got != 1 {
+			t.Errorf("cancelled, progress(%d) = %v, want 1", i, got)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package layout
+
+import (
+	"slices"
+
+	"github.com/gogpu/ui/core"
+)
+
+// StickyHeader is a section header that the enclosing ScrollView keeps
+// docked at the top of its viewport while the section is in view. The
+// header scrolls normally until it reaches the top, stays there as the
+// section scrolls under it and is pushed out by the next sticky header.
+//
+// Put each header directly above the rows of its section, for example as
+// the first child of a column, so that the next header starts where the
+// section ends. Sticky headers outside a ScrollView scroll normally.
+type StickyHeader struct {
+	core.WidgetBase
+	child  core.Widget
+	pinned bool
+}
+
+// Sticky returns child as a sticky header.
+func Sticky(child core.Widget) *StickyHeader {
+	h := &StickyHeader{child: child}
+	h.SetChildren(child)
+	return h
+}
+
+// IsPinned reports whether the header is docked away from its place in
+// the content.
+func (h *StickyHeader) IsPinned() bool {
+	return h.pinned
+}
+
+// Layout implements core.Widget.
+func (h *StickyHeader) Layout(ctx *core.LayoutContext) core.Size {
+	if sv, _ := ctx.Value(scrollParentKey{}).(*ScrollView); sv != nil {
+		// A header in a relayout boundary can be laid out again without
+		// the scroll view, and the scroll view without its content, so
+		// the scroll view keeps the list and drops the headers that left
+		// its content when it arranges it.
+		if !slices.Contains(sv.stickies, h) {
+			sv.stickies = append(sv.stickies, h)
+		}
+	}
+	return ctx.Measure(h.child, ctx.Constraints)
+}
+
+// SetBounds implements core.Widget.
+func (h *StickyHeader) SetBounds(r core.Rect) {
+	h.WidgetBase.SetBounds(r)
+	h.child.SetBounds(r)
+	h.pinned = false
+}
+
+// Paint implements core.Widget. A pinned header is painted by its scroll
+// view, above the rest of the content.
+func (h *StickyHeader) Paint(ctx *core.PaintContext) {
+	if !h.pinned {
+		h.child.Paint(ctx)
+	}
+}
+
+// attached returns the headers of stickies that are still in the tree
+// under root, in their order. Headers of scroll views nested in root are
+// not looked for, as they are registered with those.
+func attached(stickies []*StickyHeader, root core.Widget) []*StickyHeader {
+	if len(stickies) == 0 {
+		return stickies
+	}
+	found := make(map[*StickyHeader]bool, len(stickies))
+	var walk func(w core.Widget)
+	walk = func(w core.Widget) {
+		if h, ok := w.(*StickyHeader); ok {
+			found[h] = true
+		}
+		if _, nested := w.(*ScrollView); nested && w != root {
+			return
+		}
+		if p, ok := w.(core.Parent); ok {
+			for _, c := range p.Children() {
+				walk(c)
+			}
+		}
+	}
+	walk(root)
+	return slices.DeleteFunc(stickies, func(h *StickyHeader) bool { return !found[h] })
+}
+
+// pinStickies docks the sticky headers of the content at top, after the
+// content was arranged, and returns the pinned ones.
+func pinStickies(stickies []*StickyHeader, top float32) []*StickyHeader {
+	slices.SortStableFunc(stickies, func(a, b *StickyHeader) int {
+		ay, by := a.Bounds().Y, b.Bounds().Y
+		switch {
+		case ay < by:
+			return -1
+		case ay > by:
+			return 1
+		}
+		return 0
+	})
+	var pinned []*StickyHeader
+	for i, h := range stickies {
+		r := h.Bounds()
+		if r.Y >= top {
+			break
+		}
+		y := top
+		if i+1 < len(stickies) {
+			y = min(y, stickies[i+1].Bounds().Y-r.Height)
+		}
+		if y <= r.Y || y+r.Height <= top {
+			continue
+		}
+		r.Y = y
+		h.SetBounds(r)
+		h.pinned = true
+		pinned = append(pinned, h)
+	}
+	return pinned
+}
@@ -0,0 +1,102 @@
+package layout
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gogpu/ui/core"
+)
+
+// tight lays its child out under tight constraints of size, making it a
+// relayout boundary.
+type tight struct {
+	core.WidgetBase
+	child core.Widget
+	size  core.Size
+}
+
+func (b *tight) Layout(ctx *core.LayoutContext) core.Size {
+	ctx.Measure(b.child, core.Tight(b.size))
+	return ctx.Constraints.Constrain(b.size)
+}
+
+func (b *tight) SetBounds(r core.Rect) {
+	b.WidgetBase.SetBounds(r)
+	b.child.SetBounds(core.R(r.X, r.Y, b.size.Width, b.size.Height))
+}
+
+func (b *tight) Paint(ctx *core.PaintContext) { b.child.Paint(ctx) }
+
+// sections returns a scroll view 100 high over two sections of a 20 high
+// header and 300 of rows, the second in a relayout boundary.
+func sections() (s *ScrollView, first, second *StickyHeader, inner *Stack) {
+	first, second = Sticky(newBox(100, 20)), Sticky(newBox(100, 20))
+	inner = NewVStack(second, newBox(100, 300))
+	boundary := &tight{child: inner, size: core.Sz(100, 320)}
+	boundary.SetChildren(inner)
+	s = NewScrollView(NewVStack(first, newBox(100, 300), boundary, newBox(100, 1000)))
+	return s, first, second, inner
+}
+
+func TestStickyHeaderPins(t *testing.T) {
+	tests := []struct {
+		offset float32
+		pinned []float32 // The tops of the pinned headers.
+		first  float32   // The top of the first header.
+	}{
+		{0, nil, 0},
+		{100, []float32{0}, 0},
+		{310, []float32{-10}, -10}, // Pushed out by the second header.
+		{400, []float32{0}, -400},  // The second header.
+		{700, []float32{0}, -700},
+	}
+	for _, tt := range tests {
+		s, first, _, _ := sections()
+		ctx := core.NewContext()
+		bounds := core.R(0, 0, 100, 100)
+		scrollFrame(ctx, s, bounds, time.Unix(0, 0))
+		s.JumpTo(core.Pt(0, tt.offset))
+		scrollFrame(ctx, s, bounds, time.Unix(0, 0))
+		var tops []float32
+		for _, h := range s.pinned {
+			tops = append(tops, h.Bounds().Y)
+		}
+		if !slices.Equal(tops, tt.pinned) || first.Bounds().Y != tt.first {
+			t.Errorf("at %v: pinned at %v with the first header at %v, want %v and %v", tt.offset, tops, first.Bounds().Y, tt.pinned, tt.first)
+		}
+	}
+}
+
+func TestStickyHeaderIncremental(t *testing.T) {
+	s, first, second, inner := sections()
+	ctx := core.NewContext()
+	bounds := core.R(0, 0, 100, 100)
+	scrollFrame(ctx, s, bounds, time.Unix(0, 0))
+	// Later, with the bars faded, frames lay out only what was marked.
+	ctx.SetNow(time.Unix(60, 0))
+	scroll := func(y float32) {
+		s.JumpTo(core.Pt(0, y))
+		ctx.MarkNeedsLayout(s)
+		ctx.LayoutRoot(s, bounds)
+	}
+	scroll(400)
+	if !slices.Equal(s.pinned, []*StickyHeader{second}) || second.Bounds().Y != 0 {
+		t.Fatalf("pinned %v, want the second header at the top", s.pinned)
+	}
+
+	// A header removed inside a relayout boundary is no longer painted
+	// pinned, nor pinned when the view scrolls, and the one before stays
+	// up for the rest of the content.
+	inner.Remove(second)
+	ctx.MarkNeedsLayout(inner)
+	ctx.LayoutRoot(s, bounds)
+	s.Paint(&core.PaintContext{Context: ctx, Canvas: &core.Recording{}})
+	if len(s.pinned) != 0 || slices.Contains(s.Children(), core.Widget(second)) {
+		t.Errorf("painted %v pinned after the header was removed", s.pinned)
+	}
+	scroll(420)
+	if !slices.Equal(s.pinned, []*StickyHeader{first}) || slices.Contains(s.stickies, second) {
+		t.Errorf("pinned %v with %d headers registered after the header was removed, want the first", s.pinned, len(s.stickies))
+	}
+}
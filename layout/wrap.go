@@ -0,0 +1,274 @@
+package layout
+
+import "github.com/gogpu/ui/core"
+
+// wrapLine is a run of children placed on one line of a Wrap.
+type wrapLine struct {
+	start, end int // Children [start, end).
+	main       float32
+	cross      float32
+	baseline   float32 // With AlignBaseline, the baseline's distance from the line top.
+}
+
+// Wrap places children one after another along an axis and starts a new
+// line when the next child does not fit, as for tag clouds, chip lists and
+// toolbars that flow onto several rows.
+//
+// Spacing separates children on a line and LineSpacing separates lines.
+// Justify distributes the free space of each line and Align positions the
+// children within the height of their line. A child wider than the
+// available space gets a line of its own. In right-to-left layouts rows
+// run from the right edge and columns start at the right.
+type Wrap struct {
+	core.WidgetBase
+
+	axis        Axis
+	spacing     float32
+	lineSpacing float32
+	justify     Justify
+	align       Align
+
+	dir       core.Direction
+	sizes     []core.Size
+	baselines []float32 // With AlignBaseline, per child; -1 without text.
+	lines     []wrapLine
+}
+
+// NewWrap returns a wrap arranging children in rows, from the start edge.
+func NewWrap(children ...core.Widget) *Wrap {
+	w := &Wrap{}
+	w.SetChildren(children...)
+	return w
+}
+
+// Add appends children.
+func (w *Wrap) Add(children ...core.Widget) *Wrap {
+	for _, c := range children {
+		w.AppendChild(c)
+	}
+	return w
+}
+
+// Direction sets the axis along which lines run. With Vertical, children
+// flow top to bottom in columns that are added left to right.
+func (w *Wrap) Direction(axis Axis) *Wrap {
+	w.axis = axis
+	return w
+}
+
+// Spacing sets the gap between children on a line.
+func (w *Wrap) Spacing(px float32) *Wrap {
+	w.spacing = max(0, px)
+	return w
+}
+
+// LineSpacing sets the gap between lines.
+func (w *Wrap) LineSpacing(px float32) *Wrap {
+	w.lineSpacing = max(0, px)
+	return w
+}
+
+// Justify sets how the free space of each line is distributed.
+func (w *Wrap) Justify(j Justify) *Wrap {
+	w.justify = j
+	return w
+}
+
+// Align sets how children shorter than their line are positioned.
+func (w *Wrap) Align(a Align) *Wrap {
+	w.align = a
+	return w
+}
+
+func (w *Wrap) main(sz core.Size) float32 {
+	if w.axis == Vertical {
+		return sz.Height
+	}
+	return sz.Width
+}
+
+func (w *Wrap) cross(sz core.Size) float32 {
+	if w.axis == Vertical {
+		return sz.Width
+	}
+	return sz.Height
+}
+
+func (w *Wrap) size(main, cross float32) core.Size {
+	if w.axis == Vertical {
+		return core.Sz(cross, main)
+	}
+	return core.Sz(main, cross)
+}
+
+// Layout implements core.Widget.
+func (w *Wrap) Layout(ctx *core.LayoutContext) core.Size {
+	w.dir = ctx.Direction()
+	c := ctx.Constraints
+	maxMain := w.main(core.Sz(c.MaxWidth, c.MaxHeight))
+	maxCross := w.cross(core.Sz(c.MaxWidth, c.MaxHeight))
+	children := w.Children()
+	w.sizes = w.sizes[:0]
+	w.baselines = w.baselines[:0]
+	w.lines = w.lines[:0]
+	baselined := w.baselined()
+	var descent float32
+	line := wrapLine{}
+	for i, child := range children {
+		sz := ctx.Measure(child, core.Loose(w.size(maxMain, maxCross)))
+		w.sizes = append(w.sizes, sz)
+		m := w.main(sz)
+		if i > line.start && line.main+w.spacing+m > maxMain {
+			line.end = i
+			w.lines = append(w.lines, line)
+			line, descent = wrapLine{start: i}, 0
+		}
+		if i > line.start {
+			line.main += w.spacing
+		}
+		line.main += m
+		line.cross = max(line.cross, w.cross(sz))
+		if baselined {
+			b, ok := core.BaselineOf(child)
+			if !ok {
+				b = -1
+			}
+			w.baselines = append(w.baselines, b)
+			if !ok {
+				b = sz.Height
+			}
+			line.baseline = max(line.baseline, b)
+			descent = max(descent, sz.Height-b)
+			line.cross = max(line.cross, line.baseline+descent)
+		}
+	}
+	if len(children) > 0 {
+		line.end = len(children)
+		w.lines = append(w.lines, line)
+	}
+
+	var main, cross float32
+	for i, l := range w.lines {
+		main = max(main, l.main)
+		cross += l.cross
+		if i > 0 {
+			cross += w.lineSpacing
+		}
+		if w.align != AlignStretch {
+			continue
+		}
+		for k := l.start; k < l.end; k++ {
+			if w.cross(w.sizes[k]) < l.cross {
+				w.sizes[k] = ctx.Measure(children[k], core.Tight(w.size(w.main(w.sizes[k]), l.cross)))
+			}
+		}
+	}
+	if w.justify != JustifyStart && maxMain < core.Infinity {
+		main = maxMain
+	}
+	return c.Constrain(w.size(main, cross))
+}
+
+// IntrinsicWidth implements core.IntrinsicSizer. In rows, the minimum is
+// the widest child and the maximum all children on one line.
+func (w *Wrap) IntrinsicWidth(ctx *core.LayoutContext, height float32) (minWidth, maxWidth float32) {
+	if w.axis == Vertical {
+		sz := ctx.Measure(w, core.Loose(core.Sz(core.Infinity, height)))
+		return sz.Width, sz.Width
+	}
+	return w.intrinsicMain(ctx, func(c core.Widget) (float32, float32) { return ctx.IntrinsicWidth(c, core.Infinity) })
+}
+
+// IntrinsicHeight implements core.IntrinsicSizer. In columns, the minimum
+// is the tallest child and the maximum all children on one line.
+func (w *Wrap) IntrinsicHeight(ctx *core.LayoutContext, width float32) (minHeight, maxHeight float32) {
+	if w.axis == Horizontal {
+		sz := ctx.Measure(w, core.Loose(core.Sz(width, core.Infinity)))
+		return sz.Height, sz.Height
+	}
+	return w.intrinsicMain(ctx, func(c core.Widget) (float32, float32) { return ctx.IntrinsicHeight(c, core.Infinity) })
+}
+
+func (w *Wrap) intrinsicMain(ctx *core.LayoutContext, size func(core.Widget) (float32, float32)) (lo, hi float32) {
+	for i, c := range w.Children() {
+		cmin, cmax := size(c)
+		lo = max(lo, cmin)
+		hi += cmax
+		if i > 0 {
+			hi += w.spacing
+		}
+	}
+	return lo, hi
+}
+
+func (w *Wrap) baselined() bool {
+	return w.align == AlignBaseline && w.axis == Horizontal
+}
+
+// crossOffset returns the position of child k within the height of its
+// line l.
+func (w *Wrap) crossOffset(l wrapLine, k int) float32 {
+	if !w.baselined() {
+		return w.align.offset(l.cross, w.cross(w.sizes[k]))
+	}
+	b := w.baselines[k]
+	if b < 0 {
+		b = w.sizes[k].Height
+	}
+	return l.baseline - b
+}
+
+// Baseline implements core.Baseliner with the baseline of the first child
+// with text on the first row, or of the first child in columns.
+func (w *Wrap) Baseline() (float32, bool) {
+	if len(w.lines) == 0 {
+		return 0, false
+	}
+	l := w.lines[0]
+	if w.baselined() {
+		for k := l.start; k < l.end; k++ {
+			if w.baselines[k] >= 0 {
+				return l.baseline, true
+			}
+		}
+		return 0, false
+	}
+	children := w.Children()
+	if w.axis == Vertical {
+		return core.BaselineOf(children[l.start])
+	}
+	for k := l.start; k < l.end; k++ {
+		if b, ok := core.BaselineOf(children[k]); ok {
+			return w.crossOffset(l, k) + b, true
+		}
+	}
+	return 0, false
+}
+
+// SetBounds implements core.Widget.
+func (w *Wrap) SetBounds(r core.Rect) {
+	w.WidgetBase.SetBounds(r)
+	children := w.Children()
+	avail := w.main(r.Size())
+	pos := float32(0)
+	for _, l := range w.lines {
+		lead, between := w.justify.offsets(avail-l.main, l.end-l.start)
+		at := lead
+		for k := l.start; k < l.end; k++ {
+			sz := w.sizes[k]
+			off := w.crossOffset(l, k)
+			cr := core.R(r.X+at, r.Y+pos+off, sz.Width, sz.Height)
+			if w.axis == Vertical {
+				cr = core.R(r.X+pos+off, r.Y+at, sz.Width, sz.Height)
+			}
+			children[k].SetBounds(w.dir.Mirror(cr, r))
+			at += w.main(sz) + w.spacing + between
+		}
+		pos += l.cross + w.lineSpacing
+	}
+}
+
+// Paint implements core.Widget.
+func (w *Wrap) Paint(ctx *core.PaintContext) {
+	core.PaintChildren(ctx, w.Children())
+}
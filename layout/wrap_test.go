@@ -0,0 +1,213 @@
+package layout
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+)
+
+func TestWrapPlacesChildren(t *testing.T) {
+	tests := []struct {
+		name   string
+		wrap   func(kids ...core.Widget) *Wrap
+		bounds core.Rect
+		dir    core.Direction
+		want   []core.Rect
+	}{
+		{
+			name: "rows",
+			wrap: func(kids ...core.Widget) *Wrap { return NewWrap(kids...).Spacing(10).LineSpacing(5) },
+			want: []core.Rect{core.R(0, 0, 40, 10), core.R(50, 0, 30, 20), core.R(0, 25, 50, 10), core.R(60, 25, 20, 5)},
+		},
+		{
+			name: "rows right to left",
+			wrap: func(kids ...core.Widget) *Wrap { return NewWrap(kids...).Spacing(10) },
+			dir:  core.RightToLeft,
+			want: []core.Rect{core.R(40, 0, 40, 10), core.R(0, 0, 30, 20), core.R(30, 20, 50, 10), core.R(0, 20, 20, 5)},
+		},
+		{
+			name: "centered",
+			wrap: func(kids ...core.Widget) *Wrap {
+				return NewWrap(kids...).Spacing(10).Justify(JustifyCenter).Align(AlignCenter)
+			},
+			want: []core.Rect{core.R(10, 5, 40, 10), core.R(60, 0, 30, 20), core.R(10, 20, 50, 10), core.R(70, 22.5, 20, 5)},
+		},
+		{
+			name: "spaced between",
+			wrap: func(kids ...core.Widget) *Wrap {
+				return NewWrap(kids...).Spacing(10).Justify(JustifySpaceBetween).Align(AlignEnd)
+			},
+			want: []core.Rect{core.R(0, 10, 40, 10), core.R(70, 0, 30, 20), core.R(0, 20, 50, 10), core.R(80, 25, 20, 5)},
+		},
+		{
+			name: "spaced around",
+			wrap: func(kids ...core.Widget) *Wrap { return NewWrap(kids...).Spacing(10).Justify(JustifySpaceAround) },
+			want: []core.Rect{core.R(5, 0, 40, 10), core.R(65, 0, 30, 20), core.R(5, 20, 50, 10), core.R(75, 20, 20, 5)},
+		},
+		{
+			name: "stretched",
+			wrap: func(kids ...core.Widget) *Wrap { return NewWrap(kids...).Spacing(10).Align(AlignStretch) },
+			want: []core.Rect{core.R(0, 0, 40, 20), core.R(50, 0, 30, 20), core.R(0, 20, 50, 10), core.R(60, 20, 20, 10)},
+		},
+		{
+			name:   "columns",
+			wrap:   func(kids ...core.Widget) *Wrap { return NewWrap(kids...).Direction(Vertical).Spacing(10) },
+			bounds: core.R(0, 0, 200, 40),
+			want:   []core.Rect{core.R(0, 0, 40, 10), core.R(0, 20, 30, 20), core.R(40, 0, 50, 10), core.R(40, 20, 20, 5)},
+		},
+		{
+			name:   "columns right to left",
+			wrap:   func(kids ...core.Widget) *Wrap { return NewWrap(kids...).Direction(Vertical).Spacing(10) },
+			bounds: core.R(0, 0, 200, 40),
+			dir:    core.RightToLeft,
+			want:   []core.Rect{core.R(50, 0, 40, 10), core.R(60, 20, 30, 20), core.R(0, 0, 50, 10), core.R(30, 20, 20, 5)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kids := []*box{newBox(40, 10), newBox(30, 20), newBox(50, 10), newBox(20, 5)}
+			w := tt.wrap(kids[0], kids[1], kids[2], kids[3])
+			bounds := tt.bounds
+			if bounds.IsEmpty() {
+				bounds = core.R(0, 0, 100, 100)
+			}
+			layoutIn(w, bounds, tt.dir)
+			for i, k := range kids {
+				if got := k.Bounds(); got != tt.want[i] {
+					t.Errorf("child %d: bounds = %v, want %v", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWrapSize(t *testing.T) {
+	tests := []struct {
+		name string
+		wrap *Wrap
+		want core.Size
+	}{
+		{"empty", NewWrap(), core.Sz(0, 0)},
+		{"one line", NewWrap(newBox(40, 10), newBox(30, 20)).Spacing(10), core.Sz(80, 20)},
+		{"two lines", NewWrap(newBox(60, 10)).Add(newBox(50, 20)).LineSpacing(4), core.Sz(60, 34)},
+		{"justified", NewWrap(newBox(40, 10)).Justify(JustifyEnd), core.Sz(100, 10)},
+		{"oversized", NewWrap(newBox(150, 10), newBox(20, 5)).Spacing(-5), core.Sz(100, 15)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layoutIn(tt.wrap, core.R(0, 0, 100, 100), core.LeftToRight)
+			if got := tt.wrap.Bounds().Size(); got != tt.want {
+				t.Errorf("size = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapOversizedChild(t *testing.T) {
+	// A child wider than the wrap gets a line of its own.
+	a, big, c := newBox(40, 10), newBox(150, 10), newBox(20, 5)
+	w := NewWrap(a, big, c).Spacing(10)
+	layoutIn(w, core.R(0, 0, 100, 100), core.LeftToRight)
+	want := map[*box]core.Rect{
+		a:   core.R(0, 0, 40, 10),
+		big: core.R(0, 10, 100, 10),
+		c:   core.R(0, 20, 20, 5),
+	}
+	for b, r := range want {
+		if got := b.Bounds(); got != r {
+			t.Errorf("bounds = %v, want %v", got, r)
+		}
+	}
+}
+
+func TestWrapBaseline(t *testing.T) {
+	// Each line lines its children up on its own baseline.
+	big, small, icon, next := text(40, 30, 24), text(20, 12, 9), newBox(10, 10), text(50, 20, 15)
+	w := NewWrap(big, small, icon, next).Align(AlignBaseline)
+	layoutIn(w, core.R(0, 0, 100, 100), core.LeftToRight)
+
+	want := map[*box]core.Rect{
+		big:   core.R(0, 0, 40, 30),
+		small: core.R(40, 15, 20, 12),
+		icon:  core.R(60, 14, 10, 10),
+		next:  core.R(0, 30, 50, 20),
+	}
+	for b, r := range want {
+		if got := b.Bounds(); got != r {
+			t.Errorf("bounds = %v, want %v", got, r)
+		}
+	}
+	if got := w.Bounds().Height; got != 50 {
+		t.Errorf("height = %v, want 50", got)
+	}
+
+	tests := []struct {
+		name string
+		wrap *Wrap
+		want float32
+		ok   bool
+	}{
+		{"baseline aligned", w, 24, true},
+		{"centered", NewWrap(newBox(10, 20), text(20, 12, 9)).Align(AlignCenter), 13, true},
+		{"columns", NewWrap(text(20, 12, 9), newBox(10, 20)).Direction(Vertical), 9, true},
+		{"no text", NewWrap(newBox(10, 10)).Align(AlignBaseline), 0, false},
+		{"no text on the first line", NewWrap(newBox(80, 10), text(80, 12, 9)), 0, false},
+		{"empty", NewWrap(), 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layoutIn(tt.wrap, core.R(0, 0, 100, 100), core.LeftToRight)
+			if b, ok := tt.wrap.Baseline(); b != tt.want || ok != tt.ok {
+				t.Errorf("Baseline() = %v, %v, want %v, %v", b, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestWrapIntrinsic(t *testing.T) {
+	row := NewWrap(newBox(10, 5), newBox(20, 15)).Spacing(4)
+	col := NewWrap(newBox(10, 5), newBox(20, 15)).Spacing(4).Direction(Vertical)
+	ctx := &core.LayoutContext{Context: core.NewContext()}
+	tests := []struct {
+		name   string
+		got    func() (float32, float32)
+		lo, hi float32
+	}{
+		{"row width", func() (float32, float32) { return row.IntrinsicWidth(ctx, core.Infinity) }, 20, 34},
+		{"row height", func() (float32, float32) { return row.IntrinsicHeight(ctx, core.Infinity) }, 15, 15},
+		{"row height wrapped", func() (float32, float32) { return row.IntrinsicHeight(ctx, 25) }, 20, 20},
+		{"column width", func() (float32, float32) { return col.IntrinsicWidth(ctx, core.Infinity) }, 20, 20},
+		{"column width wrapped", func() (float32, float32) { return col.IntrinsicWidth(ctx, 18) }, 30, 30},
+		{"column height", func() (float32, float32) { return col.IntrinsicHeight(ctx, core.Infinity) }, 15, 24},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if lo, hi := tt.got(); lo != tt.lo || hi != tt.hi {
+				t.Errorf("= %v, %v, want %v, %v", lo, hi, tt.lo, tt.hi)
+			}
+		})
+	}
+}
+
+func TestJustifyOffsets(t *testing.T) {
+	tests := []struct {
+		justify       Justify
+		free          float32
+		n             int
+		lead, between float32
+	}{
+		{JustifyStart, 30, 3, 0, 0},
+		{JustifyCenter, 30, 3, 15, 0},
+		{JustifyEnd, 30, 3, 30, 0},
+		{JustifySpaceBetween, 30, 3, 0, 15},
+		{JustifySpaceBetween, 30, 1, 0, 0},
+		{JustifySpaceAround, 30, 3, 5, 10},
+		{JustifyCenter, -10, 3, 0, 0},
+		{JustifyEnd, 30, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		if lead, between := tt.justify.offsets(tt.free, tt.n); lead != tt.lead || between != tt.between {
+			t.Errorf("%d.offsets(%v, %d) = %v, %v, want %v, %v", tt.justify, tt.free, tt.n, lead, between, tt.lead, tt.between)
+		}
+	}
+}
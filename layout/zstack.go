@@ -0,0 +1,183 @@
+package layout
+
+import (
+	"slices"
+
+	"github.com/gogpu/ui/core"
+)
+
+type zStackItem struct {
+	content core.Widget
+	h, v    Align
+	offset  core.Point
+	order   int
+	size    core.Size
+}
+
+// ZStack places children on top of each other, later children over
+// earlier ones, as for a badge over an avatar or a floating button over
+// the content it belongs to.
+//
+// Each child is aligned horizontally and vertically within the stack and
+// then moved by its offset; AlignStretch makes it fill that axis. In
+// right-to-left layouts the horizontal alignment and offset are mirrored,
+// so AlignStart is the right edge. The stack is as large as its largest
+// child.
+//
+// Pointer events go to the topmost child under the pointer. HitOrder
+// changes that without changing what is painted on top, for example to
+// keep a decoration drawn over a list from intercepting its clicks.
+type ZStack struct {
+	core.WidgetBase
+
+	items []zStackItem
+	dir   core.Direction
+}
+
+// NewZStack returns a stack of children, aligned at the top start corner.
+func NewZStack(children ...core.Widget) *ZStack {
+	s := &ZStack{}
+	for _, c := range children {
+		s.items = append(s.items, zStackItem{content: c})
+	}
+	s.sync()
+	return s
+}
+
+// Alignment sets the alignment of every child added so far.
+func (s *ZStack) Alignment(h, v Align) *ZStack {
+	for i := range s.items {
+		s.items[i].h, s.items[i].v = h, v
+	}
+	return s
+}
+
+// Add puts child on top of the stack, aligned by h and v and moved by
+// offset.
+func (s *ZStack) Add(child core.Widget, h, v Align, offset core.Point) *ZStack {
+	s.items = append(s.items, zStackItem{content: child, h: h, v: v, offset: offset})
+	s.sync()
+	return s
+}
+
+// Place changes the alignment and offset of child.
+func (s *ZStack) Place(child core.Widget, h, v Align, offset core.Point) *ZStack {
+	if i := s.index(child); i >= 0 {
+		it := &s.items[i]
+		it.h, it.v, it.offset = h, v, offset
+	}
+	return s
+}
+
+// HitOrder sets the priority of child for pointer events. Children with a
+// higher order are tested first; among equal orders, the default of zero
+// included, the child painted on top wins.
+func (s *ZStack) HitOrder(child core.Widget, order int) *ZStack {
+	if i := s.index(child); i >= 0 {
+		s.items[i].order = order
+		s.sync()
+	}
+	return s
+}
+
+// Remove takes child off the stack.
+func (s *ZStack) Remove(child core.Widget) {
+	if i := s.index(child); i >= 0 {
+		s.items = slices.Delete(s.items, i, i+1)
+		s.sync()
+	}
+}
+
+func (s *ZStack) index(child core.Widget) int {
+	return slices.IndexFunc(s.items, func(it zStackItem) bool { return it.content == child })
+}
+
+// sync sets the children in hit test order, which core.HitTest walks from
+// last to first.
+func (s *ZStack) sync() {
+	order := make([]int, len(s.items))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int { return s.items[a].order - s.items[b].order })
+	children := make([]core.Widget, len(order))
+	for i, k := range order {
+		children[i] = s.items[k].content
+	}
+	s.SetChildren(children...)
+}
+
+// Layout implements core.Widget.
+func (s *ZStack) Layout(ctx *core.LayoutContext) core.Size {
+	s.dir = ctx.Direction()
+	c := ctx.Constraints
+	loose := c.Loosen()
+	var size core.Size
+	for i := range s.items {
+		it := &s.items[i]
+		it.size = ctx.Measure(it.content, loose)
+		size.Width = max(size.Width, it.size.Width)
+		size.Height = max(size.Height, it.size.Height)
+	}
+	size = c.Constrain(size)
+	for i := range s.items {
+		it := &s.items[i]
+		if it.h != AlignStretch && it.v != AlignStretch {
+			continue
+		}
+		sz := it.size
+		if it.h == AlignStretch {
+			sz.Width = size.Width
+		}
+		if it.v == AlignStretch {
+			sz.Height = size.Height
+		}
+		it.size = ctx.Measure(it.content, core.Tight(sz))
+	}
+	return size
+}
+
+// IntrinsicWidth implements core.IntrinsicSizer: the largest intrinsic
+// widths of the children.
+func (s *ZStack) IntrinsicWidth(ctx *core.LayoutContext, height float32) (minWidth, maxWidth float32) {
+	for _, it := range s.items {
+		lo, hi := ctx.IntrinsicWidth(it.content, height)
+		minWidth, maxWidth = max(minWidth, lo), max(maxWidth, hi)
+	}
+	return minWidth, maxWidth
+}
+
+// IntrinsicHeight implements core.IntrinsicSizer: the largest intrinsic
+// heights of the children.
+func (s *ZStack) IntrinsicHeight(ctx *core.LayoutContext, width float32) (minHeight, maxHeight float32) {
+	for _, it := range s.items {
+		lo, hi := ctx.IntrinsicHeight(it.content, width)
+		minHeight, maxHeight = max(minHeight, lo), max(maxHeight, hi)
+	}
+	return minHeight, maxHeight
+}
+
+// SetBounds implements core.Widget.
+func (s *ZStack) SetBounds(r core.Rect) {
+	s.WidgetBase.SetBounds(r)
+	for _, it := range s.items {
+		sz := it.size
+		if it.h == AlignStretch {
+			sz.Width = r.Width
+		}
+		if it.v == AlignStretch {
+			sz.Height = r.Height
+		}
+		x := r.X + it.h.offset(r.Width, sz.Width) + it.offset.X
+		y := r.Y + it.v.offset(r.Height, sz.Height) + it.offset.Y
+		it.content.SetBounds(s.dir.Mirror(core.R(x, y, sz.Width, sz.Height), r))
+	}
+}
+
+// Paint implements core.Widget. Children are painted in the order they
+// were added, regardless of their hit order.
+func (s *ZStack) Paint(ctx *core.PaintContext) {
+	for _, it := range s.items {
+		it.content.Paint(ctx)
+	}
+}
@@ -0,0 +1,156 @@
+package layout
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+)
+
+// painter is a box that records its name when painted.
+type painter struct {
+	box
+	name    string
+	painted *[]string
+}
+
+func (p *painter) Paint(*core.PaintContext) {
+	*p.painted = append(*p.painted, p.name)
+}
+
+func TestZStackPlacesChildren(t *testing.T) {
+	tests := []struct {
+		name  string
+		stack func(a, b, c core.Widget) *ZStack
+		dir   core.Direction
+		want  []core.Rect
+	}{
+		{
+			name:  "top start",
+			stack: func(a, b, c core.Widget) *ZStack { return NewZStack(a, b, c) },
+			want:  []core.Rect{core.R(0, 0, 40, 30), core.R(0, 0, 10, 10), core.R(0, 0, 20, 5)},
+		},
+		{
+			name:  "aligned",
+			stack: func(a, b, c core.Widget) *ZStack { return NewZStack(a, b, c).Alignment(AlignCenter, AlignEnd) },
+			want:  []core.Rect{core.R(0, 0, 40, 30), core.R(15, 20, 10, 10), core.R(10, 25, 20, 5)},
+		},
+		{
+			name: "badge",
+			stack: func(a, b, c core.Widget) *ZStack {
+				return NewZStack(a).Add(b, AlignEnd, AlignStart, core.Pt(4, -4)).Add(c, AlignCenter, AlignCenter, core.Point{})
+			},
+			want: []core.Rect{core.R(0, 0, 40, 30), core.R(34, -4, 10, 10), core.R(10, 12.5, 20, 5)},
+		},
+		{
+			name: "right to left",
+			stack: func(a, b, c core.Widget) *ZStack {
+				return NewZStack(a).Add(b, AlignStart, AlignStart, core.Pt(4, 0)).Add(c, AlignEnd, AlignEnd, core.Point{})
+			},
+			dir:  core.RightToLeft,
+			want: []core.Rect{core.R(0, 0, 40, 30), core.R(26, 0, 10, 10), core.R(0, 25, 20, 5)},
+		},
+		{
+			name: "stretched",
+			stack: func(a, b, c core.Widget) *ZStack {
+				return NewZStack(a).Add(b, AlignStretch, AlignStretch, core.Point{}).Add(c, AlignStretch, AlignCenter, core.Point{})
+			},
+			want: []core.Rect{core.R(0, 0, 40, 30), core.R(0, 0, 40, 30), core.R(0, 12.5, 40, 5)},
+		},
+		{
+			name: "placed",
+			stack: func(a, b, c core.Widget) *ZStack {
+				return NewZStack(a, b, c).Place(b, AlignEnd, AlignEnd, core.Pt(-1, -1)).Place(newBox(1, 1), AlignEnd, AlignEnd, core.Point{})
+			},
+			want: []core.Rect{core.R(0, 0, 40, 30), core.R(29, 19, 10, 10), core.R(0, 0, 20, 5)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kids := []*box{newBox(40, 30), newBox(10, 10), newBox(20, 5)}
+			s := tt.stack(kids[0], kids[1], kids[2])
+			layoutIn(s, core.R(0, 0, 100, 100), tt.dir)
+			if got := s.Bounds().Size(); got != core.Sz(40, 30) {
+				t.Errorf("size = %v, want the largest child", got)
+			}
+			for i, k := range kids {
+				if got := k.Bounds(); got != tt.want[i] {
+					t.Errorf("child %d: bounds = %v, want %v", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestZStackTight(t *testing.T) {
+	// Under tight constraints the children align within the whole stack.
+	fab := newBox(20, 20)
+	s := NewZStack(newBox(10, 10)).Add(fab, AlignEnd, AlignEnd, core.Pt(-8, -8))
+	ctx := core.NewContext()
+	ctx.LayoutRoot(s, core.R(0, 0, 200, 100))
+	if got := fab.Bounds(); got != core.R(172, 72, 20, 20) {
+		t.Errorf("bounds = %v, want %v", got, core.R(172, 72, 20, 20))
+	}
+}
+
+func TestZStackHitOrder(t *testing.T) {
+	var painted []string
+	under := &painter{box: *newBox(40, 30), name: "under", painted: &painted}
+	over := &painter{box: *newBox(40, 30), name: "over", painted: &painted}
+	s := NewZStack(under, over)
+	layoutIn(s, core.R(0, 0, 100, 100), core.LeftToRight)
+	top := func() core.Widget {
+		path := core.HitTest(s, core.Pt(5, 5))
+		return path[len(path)-1]
+	}
+
+	tests := []struct {
+		name  string
+		order func()
+		want  core.Widget
+	}{
+		{"painted on top", func() {}, over},
+		{"higher order", func() { s.HitOrder(under, 1) }, under},
+		{"equal orders", func() { s.HitOrder(over, 1) }, over},
+		{"lower order", func() { s.HitOrder(over, -1).HitOrder(under, 0) }, under},
+		{"not a child", func() { s.HitOrder(newBox(1, 1), 5) }, under},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.order()
+			if got := top(); got != tt.want {
+				t.Errorf("hit %v, want %v", got, tt.want)
+			}
+			painted = painted[:0]
+			s.Paint(&core.PaintContext{Context: core.NewContext(), Canvas: &core.Recording{}})
+			if !slices.Equal(painted, []string{"under", "over"}) {
+				t.Errorf("painted %q, want under then over", painted)
+			}
+		})
+	}
+}
+
+func TestZStackRemove(t *testing.T) {
+	a, b := newBox(40, 30), newBox(10, 10)
+	s := NewZStack(a, b)
+	s.Remove(a)
+	s.Remove(a)
+	if got := s.Children(); len(got) != 1 || got[0] != b {
+		t.Fatalf("children = %v, want [b]", got)
+	}
+	layoutIn(s, core.R(0, 0, 100, 100), core.LeftToRight)
+	if got := s.Bounds().Size(); got != core.Sz(10, 10) {
+		t.Errorf("size = %v, want 10x10", got)
+	}
+}
+
+func TestZStackIntrinsic(t *testing.T) {
+	s := NewZStack(newBox(40, 5), newBox(10, 30))
+	ctx := &core.LayoutContext{Context: core.NewContext()}
+	if lo, hi := s.IntrinsicWidth(ctx, core.Infinity); lo != 40 || hi != 40 {
+		t.Errorf("IntrinsicWidth = %v, %v, want 40, 40", lo, hi)
+	}
+	if lo, hi := s.IntrinsicHeight(ctx, core.Infinity); lo != 30 || hi != 30 {
+		t.Errorf("IntrinsicHeight = %v, %v, want 30, 30", lo, hi)
+	}
+}
@@ -0,0 +1,26 @@
+package ui
+
+import (
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/widgets"
+)
+
+// Notify queues a toast showing message in the window's notification area
+// and returns it. Actions and other settings may still be added before the
+// next frame, when the toast appears:
+//
+//	ui.Notify(ctx, "Message deleted").Action("Undo", restore)
+//
+// Use widgets.ShowToast to show a toast built beforehand.
+func Notify(ctx *core.Context, message string) *widgets.Toast {
+	t := widgets.NewToast(message)
+	widgets.ShowToast(ctx, t)
+	return t
+}
+
+// WithToastCorner selects the window corner toasts are stacked in.
+func WithToastCorner(corner widgets.ToastCorner) Option {
+	return func(w *Window) {
+		widgets.SetToastCorner(w.ctx, corner)
+	}
+}
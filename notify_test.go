@@ -0,0 +1,37 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/widgets"
+)
+
+func TestNotify(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []Option
+		corner func(toast, window core.Rect) bool
+	}{
+		{"bottom right", nil, func(r, win core.Rect) bool { return r.Right() > win.Width/2 && r.Bottom() > win.Height/2 }},
+		{"top left", []Option{WithToastCorner(widgets.ToastTopLeft)}, func(r, win core.Rect) bool { return r.X < win.Width/2 && r.Y < win.Height/2 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewWindow(newPane(), append(tt.opts, WithReducedMotion(true))...)
+			w.Resize(core.Sz(800, 600))
+			w.Frame(&core.Recording{})
+			toast := Notify(w.Context(), "Message deleted").Action("Undo", nil)
+			if toast.Message() != "Message deleted" {
+				t.Errorf("Message() = %q", toast.Message())
+			}
+			if !w.NeedsFrame() {
+				t.Error("NeedsFrame() = false with a toast to show")
+			}
+			w.Frame(&core.Recording{})
+			if r := toast.Bounds(); r.IsEmpty() || !tt.corner(r, core.R(0, 0, 800, 600)) {
+				t.Errorf("toast at %v", r)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package ui
+
+import "time"
+
+// RenderMode selects when a window produces frames.
+type RenderMode uint8
+
+const (
+	// RenderOnDemand produces frames only when something changed: input
+	// was handled, a signal or posted work repainted a widget, or an
+	// animation is running. An idle window draws nothing, so desktop
+	// utilities cost no GPU time while they wait.
+	RenderOnDemand RenderMode = iota
+
+	// RenderContinuous produces a frame at every display refresh, or at
+	// the frame rate, for visualizations and games that change every
+	// frame.
+	RenderContinuous
+)
+
+// VSync selects how the frames of a window are synchronized with the
+// display refresh. Platform integrations read it before presenting each
+// frame and reconfigure their surface when it changes.
+type VSync uint8
+
+const (
+	VSyncOn       VSync = iota // Frames wait for the refresh, without tearing.
+	VSyncAdaptive              // Frames wait for the refresh unless late, which tear instead of stalling.
+	VSyncOff                   // Frames are presented at once, with the lowest latency.
+)
+
+// WithRenderMode sets when the window produces frames, on demand by
+// default.
+func WithRenderMode(m RenderMode) Option {
+	return func(w *Window) {
+		w.mode = m
+	}
+}
+
+// WithFrameRate limits the window to fps frames a second; see
+// Window.SetFrameRate.
+func WithFrameRate(fps float64) Option {
+	return func(w *Window) {
+		w.SetFrameRate(fps)
+	}
+}
+
+// WithVSync sets how frames are synchronized with the display, VSyncOn
+// by default.
+func WithVSync(v VSync) Option {
+	return func(w *Window) {
+		w.vsync = v
+	}
+}
+
+// RenderMode returns when the window produces frames.
+func (w *Window) RenderMode() RenderMode {
+	return w.mode
+}
+
+// SetRenderMode sets when the window produces frames.
+func (w *Window) SetRenderMode(m RenderMode) {
+	w.mode = m
+}
+
+// FrameRate returns the most frames a second the window produces, or 0
+// for the display refresh rate.
+func (w *Window) FrameRate() float64 {
+	return w.fps
+}
+
+// SetFrameRate limits the window to fps frames a second, such as 30 to
+// save power while animations run, and 0 or less removes the limit so
+// that frames keep to the display refresh rate.
+func (w *Window) SetFrameRate(fps float64) {
+	w.fps = max(fps, 0)
+}
+
+// VSync returns how frames are synchronized with the display.
+func (w *Window) VSync() VSync {
+	return w.vsync
+}
+
+// SetVSync sets how frames are synchronized with the display.
+func (w *Window) SetVSync(v VSync) {
+	w.vsync = v
+}
+
+// NextFrame reports whether the window needs a frame and when it is due,
+// for platform integrations to schedule frames with: the event loop
+// sleeps until the time or the next event or wakeup of the Context, and
+// then calls Frame if it is due. The time is after the last frame by the
+// interval of the frame rate, or then for no limit, so that it may have
+// passed already.
+func (w *Window) NextFrame() (at time.Time, ok bool) {
+	if w.mode == RenderOnDemand && !w.NeedsFrame() {
+		return time.Time{}, false
+	}
+	if w.fps > 0 && !w.lastFrame.IsZero() {
+		return w.lastFrame.Add(time.Duration(float64(time.Second) / w.fps)), true
+	}
+	return w.lastFrame, true
+}
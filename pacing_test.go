@@ -0,0 +1,44 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogpu/ui/core"
+)
+
+func TestWindowNextFrame(t *testing.T) {
+	w := NewWindow(newPane(), WithFrameRate(-1), WithVSync(VSyncAdaptive))
+	w.Resize(core.Sz(100, 100))
+	if at, ok := w.NextFrame(); !ok || !at.IsZero() {
+		t.Errorf("first frame due at %v, %v, want at once", at, ok)
+	}
+	var rec core.Recording
+	w.Frame(&rec)
+	if _, ok := w.NextFrame(); ok {
+		t.Error("an idle window on demand asks for a frame")
+	}
+	if w.FrameRate() != 0 || w.VSync() != VSyncAdaptive || w.RenderMode() != RenderOnDemand {
+		t.Errorf("frame rate %v, vsync %v, mode %v", w.FrameRate(), w.VSync(), w.RenderMode())
+	}
+
+	// Changed, it asks for a frame at once, or at the interval of its
+	// frame rate after the last.
+	last := w.lastFrame
+	w.Context().Invalidate()
+	if at, ok := w.NextFrame(); !ok || at != last {
+		t.Errorf("frame due at %v, %v after a change, want at once", at, ok)
+	}
+	w.SetFrameRate(25)
+	if at, _ := w.NextFrame(); at != last.Add(40*time.Millisecond) {
+		t.Errorf("frame due %v after the last at 25 fps, want 40ms", at.Sub(last))
+	}
+
+	// Continuous, it asks for frames when idle too.
+	w.Frame(&rec)
+	w.SetRenderMode(RenderContinuous)
+	w.SetFrameRate(0)
+	if at, ok := w.NextFrame(); !ok || at != w.lastFrame {
+		t.Errorf("frame due at %v, %v rendering continuously", at, ok)
+	}
+}
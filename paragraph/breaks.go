@@ -0,0 +1,110 @@
+package paragraph
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gogpu/ui/grapheme"
+)
+
+// Characters with a part in line breaking.
+const (
+	softHyphen    = '\u00AD'
+	zeroWidthSp   = '\u200B'
+	wordJoiner    = '\u2060'
+	zeroWidthNBSP = '\uFEFF'
+	object        = '\uFFFC'
+)
+
+// opportunity is a place a line may end: before the byte at, after a
+// soft hyphen if soft is set.
+type opportunity struct {
+	at   int
+	soft bool
+}
+
+// isSpace reports whether r is a space lines may break after. The
+// no-break spaces, U+00A0, U+2007 and U+202F, are not.
+func isSpace(r rune) bool {
+	switch r {
+	case ' ', '\t', '\u1680', '\u205F', '\u3000':
+		return true
+	}
+	return r >= '\u2000' && r <= '\u200A' && r != '\u2007'
+}
+
+// isIdeographic reports whether r is written without spaces between
+// words, so that lines may break on either side of it: Chinese
+// characters and Japanese kana.
+func isIdeographic(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) ||
+		r >= '\u3000' && r <= '\u303F' || r >= '\uFF00' && r <= '\uFFEF'
+}
+
+// noBreakBefore and noBreakAfter are the closing and opening punctuation
+// of East Asian text, and the small kana and prolonged sound marks, which
+// must not start or end a line.
+func noBreakBefore(r rune) bool {
+	switch r {
+	case '、', '。', '，', '．', '：', '；', '！', '？', '）', '」', '』', '】', '〕', '〉', '》', '｝', '］',
+		'ー', 'ぁ', 'ぃ', 'ぅ', 'ぇ', 'ぉ', 'っ', 'ゃ', 'ゅ', 'ょ', 'ァ', 'ィ', 'ゥ', 'ェ', 'ォ', 'ッ', 'ャ', 'ュ', 'ョ',
+		'!', '?', ',', '.', ':', ';', ')', ']', '}':
+		return true
+	}
+	return false
+}
+
+func noBreakAfter(r rune) bool {
+	switch r {
+	case '（', '「', '『', '【', '〔', '〈', '《', '｛', '［', '(', '[', '{':
+		return true
+	}
+	return false
+}
+
+func isHyphen(r rune) bool {
+	return r == '-' || r == '‐'
+}
+
+// opportunities returns the places text may be broken, ending with its
+// end: after spaces, zero-width spaces, soft hyphens and hyphens between
+// letters, and around boxes and Chinese and Japanese characters, but
+// never inside a grapheme cluster or next to a word joiner.
+func opportunities(text string) []opportunity {
+	runes := []rune(text)
+	var out []opportunity
+	at := 0
+	for i, r := range runes {
+		if i > 0 && allowed(runes, i) {
+			out = append(out, opportunity{at: at, soft: runes[i-1] == softHyphen})
+		}
+		at += utf8.RuneLen(r)
+	}
+	return append(out, opportunity{at: len(text)})
+}
+
+// allowed reports whether a line may break before runes[i].
+func allowed(runes []rune, i int) bool {
+	before, after := runes[i-1], runes[i]
+	switch {
+	case !grapheme.IsBoundary(runes, i):
+		return false
+	case before == wordJoiner || after == wordJoiner || before == zeroWidthNBSP || after == zeroWidthNBSP:
+		return false
+	case isSpace(after):
+		return false
+	case isSpace(before), before == zeroWidthSp, before == softHyphen:
+		return true
+	case before == object || after == object:
+		return true
+	case isHyphen(before):
+		return i >= 2 && isLetterOrDigit(runes[i-2]) && unicode.IsLetter(after)
+	case isIdeographic(before) || isIdeographic(after):
+		return !noBreakBefore(after) && !noBreakAfter(before)
+	}
+	return false
+}
+
+func isLetterOrDigit(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,817 @@
+// Package paragraph lays out text in lines for document-style display. It
+// breaks lines where the line-breaking rules of the text allow, honouring
+// no-break spaces, word joiners, zero-width spaces and soft hyphens, and
+// hyphenates words that do not fit by the patterns of package hyphen. It
+// aligns the lines, justifying them if asked, reorders text mixing
+// directions by package bidi, and ends text past the lines allowed with
+// an ellipsis or a fade. Text may mix styles and decorations, and leave
+// room for boxes such as widgets shown inline.
+//
+// widgets.Text is built on it, and other widgets use it to draw text the
+// same way:
+//
+//	p := paragraph.New(ctx, text, style, paragraph.Options{
+//		Width:     300,
+//		Align:     paragraph.Justify,
+//		Hyphenate: true,
+//	})
+//	p.Paint(canvas, core.R(x, y, 300, p.Height))
+//
+// Offsets into the text are in bytes.
+package paragraph
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gogpu/ui/bidi"
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/grapheme"
+	"github.com/gogpu/ui/hyphen"
+)
+
+const (
+	hyphenText   = "-"
+	ellipsisText = "…"
+
+	// objectText stands for a box in the text of a paragraph.
+	objectText = "\uFFFC"
+
+	// fadeLength is the length of the fade at the end of text that runs
+	// out of room, in ems.
+	fadeLength = 2
+)
+
+// Align is the horizontal alignment of the lines of a paragraph.
+type Align uint8
+
+// Alignments. Start and End follow the direction of the text.
+const (
+	Start Align = iota
+	End
+	Left
+	Right
+	Center
+
+	// Justify stretches the spaces of each line to fill the width, apart
+	// from the last line of each paragraph, which is aligned at the start.
+	Justify
+)
+
+// Overflow is how text that does not fit ends.
+type Overflow uint8
+
+// Overflow modes.
+const (
+	// Clip leaves lines past MaxLines out, and lets lines that cannot be
+	// broken run past the width.
+	Clip Overflow = iota
+
+	// Ellipsis ends the last line with "…" where text was left out, and
+	// shortens a line that runs past the width to fit with one.
+	Ellipsis
+
+	// Fade fades out the end of the last line where text was left out,
+	// and of a line that runs past the width, at the width.
+	Fade
+)
+
+// Decoration is a set of lines drawn along text.
+type Decoration uint8
+
+// Decorations.
+const (
+	Underline Decoration = 1 << iota
+	Strikethrough
+)
+
+// Has reports whether d includes f.
+func (d Decoration) Has(f Decoration) bool {
+	return d&f != 0
+}
+
+// Run is a stretch of the text of a paragraph in one style.
+type Run struct {
+	Text       string
+	Style      core.TextStyle
+	Decoration Decoration
+
+	// Box makes the run a box of Size standing on the baseline, such as
+	// a widget shown inline, which lines are broken around like a
+	// character. Text is ignored; the box stands as U+FFFC OBJECT
+	// REPLACEMENT CHARACTER in the paragraph's text.
+	Box  bool
+	Size core.Size
+}
+
+// Options control the layout of a paragraph.
+type Options struct {
+	// Width is the width lines are broken at and fitted to by Overflow.
+	// Infinity puts each paragraph on one line.
+	Width float32
+
+	// NoWrap puts each paragraph on one line, which Overflow shortens if
+	// it is wider than Width.
+	NoWrap bool
+
+	Align Align
+
+	// Direction is the reading direction, which Start and End and the
+	// order of text mixing directions follow.
+	Direction core.Direction
+
+	// MaxLines limits the number of lines. Zero means unlimited.
+	MaxLines int
+
+	Overflow Overflow
+
+	// Hyphenate breaks words that do not fit at the hyphenation points
+	// of the language of Locale. Soft hyphens in the text are honoured
+	// either way.
+	Hyphenate bool
+
+	// Locale is the language of the text, as a BCP 47 tag such as
+	// "en-GB". Empty means American English.
+	Locale string
+}
+
+// Paragraph is text laid out in lines.
+type Paragraph struct {
+	Lines []Line
+
+	// Width is the width of the widest line, and Height the height of
+	// the lines.
+	Width, Height float32
+
+	// Truncated is set when text was left out, by MaxLines or by a line
+	// shortened to fit.
+	Truncated bool
+
+	content
+	align Align
+	rtl   bool
+}
+
+// Line is a line of a paragraph.
+type Line struct {
+	// Start and End are the text on the line, with the spaces it ends in
+	// but not the newline.
+	Start, End int
+
+	// Width is the width of what is drawn, without the spaces the line
+	// ends in.
+	Width float32
+
+	// Y is the top of the line, measured from the top of the paragraph,
+	// Height its height, and Ascent the distance from its top to the
+	// baseline, which the styles and boxes on the line share.
+	Y, Height, Ascent float32
+
+	// Segments are the pieces the line is drawn in, left to right.
+	Segments []Segment
+}
+
+// Segment is a piece of a line drawn as one string, in the style of one
+// run.
+type Segment struct {
+	Text string
+
+	// Run is the index of the run the segment is part of.
+	Run int
+
+	// Start and End are the text drawn. They are equal for a hyphen or
+	// ellipsis added by layout.
+	Start, End int
+
+	// X is the offset of the segment from the left of the line, and Width
+	// its width, which for justified spaces includes the space they are
+	// stretched by.
+	X, Width float32
+
+	// RTL is set for right-to-left text.
+	RTL bool
+
+	// Alpha scales the opacity of the text, below 1 where it fades out.
+	Alpha float32
+}
+
+// content is the text of a paragraph and the runs it is made of.
+type content struct {
+	text   string
+	runs   []Run
+	starts []int // The offset in text of each run, and the length of text.
+}
+
+func newContent(runs []Run) content {
+	c := content{runs: runs}
+	var b strings.Builder
+	for _, r := range runs {
+		c.starts = append(c.starts, b.Len())
+		if r.Box {
+			b.WriteString(objectText)
+		} else {
+			b.WriteString(r.Text)
+		}
+	}
+	c.starts = append(c.starts, b.Len())
+	c.text = b.String()
+	return c
+}
+
+// runAt returns the run the byte at offset i is in, or the last run for
+// the end of the text.
+func (c *content) runAt(i int) int {
+	k := sort.Search(len(c.runs), func(k int) bool { return c.starts[k+1] > i })
+	return min(k, len(c.runs)-1)
+}
+
+// advance returns the width of s, part of run k, as drawn.
+func (c *content) advance(m core.TextMeasurer, k int, s string) float32 {
+	if s == "" {
+		return 0
+	}
+	if r := &c.runs[k]; r.Box {
+		return r.Size.Width
+	}
+	return m.MeasureText(visible(s), c.runs[k].Style).Width
+}
+
+// measure returns the width of the text from start up to end, in the
+// styles of the runs it is in.
+func (c *content) measure(m core.TextMeasurer, start, end int) float32 {
+	var w float32
+	for start < end {
+		k := c.runAt(start)
+		z := min(end, c.starts[k+1])
+		w += c.advance(m, k, c.text[start:z])
+		start = z
+	}
+	return w
+}
+
+// Text returns the text of the paragraph, with U+FFFC for each box.
+func (p *Paragraph) Text() string {
+	return p.text
+}
+
+// New lays out text in lines in style. Newlines end paragraphs.
+func New(m core.TextMeasurer, text string, style core.TextStyle, opts Options) *Paragraph {
+	return NewRuns(m, []Run{{Text: text, Style: style}}, opts)
+}
+
+// NewRuns lays out text made of runs in several styles in lines. It
+// needs at least one run, whose style empty lines take.
+func NewRuns(m core.TextMeasurer, runs []Run, opts Options) *Paragraph {
+	p := &Paragraph{
+		content: newContent(runs),
+		align:   opts.Align,
+		rtl:     opts.Direction.IsRTL(),
+	}
+	text := p.text
+	width := opts.Width
+	b := &breaker{content: p.content, m: m, width: width, noWrap: opts.NoWrap || width >= core.Infinity}
+	if opts.Hyphenate {
+		locale := opts.Locale
+		if locale == "" {
+			locale = "en-US"
+		}
+		b.hyph = hyphen.For(locale)
+	}
+	var spans []span
+	for start := 0; ; {
+		end := len(text)
+		if i := strings.IndexByte(text[start:], '\n'); i >= 0 {
+			end = start + i
+		}
+		spans = append(spans, b.paragraph(start, end)...)
+		spans[len(spans)-1].last = true
+		if end == len(text) {
+			break
+		}
+		start = end + 1
+	}
+	if opts.MaxLines > 0 && len(spans) > opts.MaxLines {
+		spans = spans[:opts.MaxLines]
+		p.Truncated = true
+	}
+	for i, s := range spans {
+		justify := opts.Align == Justify && !s.last && width < core.Infinity
+		cut := p.Truncated && i == len(spans)-1
+		l := b.line(s, justify, p.rtl, "")
+		if opts.Overflow != Clip && width < core.Infinity && (cut || l.Width > width) {
+			p.Truncated = true
+			if opts.Overflow == Ellipsis {
+				l = b.ellipsize(s, p.rtl)
+			} else {
+				b.fade(&l, p.rtl)
+			}
+		}
+		b.metrics(&l)
+		l.Y = p.Height
+		p.Height += l.Height
+		p.Lines = append(p.Lines, l)
+		p.Width = max(p.Width, l.Width)
+	}
+	return p
+}
+
+// Offset returns the offset of line i from the left of a box width
+// wide, by the paragraph's alignment.
+func (p *Paragraph) Offset(i int, width float32) float32 {
+	free := width - p.Lines[i].Width
+	switch p.align {
+	case Left:
+		return 0
+	case Right:
+		return free
+	case Center:
+		return free / 2
+	case End:
+		if p.rtl {
+			return 0
+		}
+		return free
+	}
+	if p.rtl {
+		return free
+	}
+	return 0
+}
+
+// Paint draws the text of the paragraph and its decorations in box,
+// aligning its lines in the width of the box. Boxes are left for their
+// owners to draw, at Rects.
+func (p *Paragraph) Paint(cv core.Canvas, box core.Rect) {
+	for i := range p.Lines {
+		l := &p.Lines[i]
+		x := box.X + p.Offset(i, box.Width)
+		baseline := box.Y + l.Y + l.Ascent
+		for _, s := range l.Segments {
+			r := &p.runs[s.Run]
+			if r.Box {
+				continue
+			}
+			st := r.Style
+			if s.Alpha < 1 {
+				st.Color = st.Color.WithAlpha(st.Color.A * s.Alpha)
+			}
+			if !isSpaces(s.Text) {
+				cv.DrawText(s.Text, core.Pt(x+s.X, baseline-st.Ascent()), st)
+			}
+			thick := max(st.Size/16, 1)
+			if r.Decoration.Has(Underline) {
+				cv.DrawRect(core.R(x+s.X, baseline+st.Size*0.1, s.Width, thick), core.Filled(st.Color))
+			}
+			if r.Decoration.Has(Strikethrough) {
+				cv.DrawRect(core.R(x+s.X, baseline-st.Size*0.3-thick/2, s.Width, thick), core.Filled(st.Color))
+			}
+		}
+	}
+}
+
+// Rects returns where run k is drawn in the paragraph laid out in a box
+// width wide, relative to its top left: a rectangle for each segment of
+// it, the height of its line, or the box of a box run.
+func (p *Paragraph) Rects(k int, width float32) []core.Rect {
+	var out []core.Rect
+	for i := range p.Lines {
+		l := &p.Lines[i]
+		x := p.Offset(i, width)
+		for _, s := range l.Segments {
+			switch r := &p.runs[s.Run]; {
+			case s.Run != k:
+			case r.Box:
+				out = append(out, core.R(x+s.X, l.Y+l.Ascent-r.Size.Height, s.Width, r.Size.Height))
+			default:
+				out = append(out, core.R(x+s.X, l.Y, s.Width, l.Height))
+			}
+		}
+	}
+	return out
+}
+
+// LineAt returns the line at y, measured from the top of the paragraph,
+// or the first or last line above or below it.
+func (p *Paragraph) LineAt(y float32) int {
+	i := sort.Search(len(p.Lines), func(i int) bool { return p.Lines[i].Y+p.Lines[i].Height > y })
+	return max(min(i, len(p.Lines)-1), 0)
+}
+
+// X returns the offset from the left of line i of the caret before the
+// byte at offset, on the trailing edge of the character before it. The
+// caret after the last character drawn stands for the spaces the line
+// ends in too.
+func (p *Paragraph) X(m core.TextMeasurer, i, offset int) float32 {
+	l := &p.Lines[i]
+	for k := range l.Segments {
+		if s := &l.Segments[k]; s.Start < offset && offset <= s.End {
+			return p.edge(m, s, offset)
+		}
+	}
+	if offset <= l.Start {
+		for k := range l.Segments {
+			if s := &l.Segments[k]; s.Start == l.Start && s.End > s.Start {
+				return p.edge(m, s, s.Start)
+			}
+		}
+	}
+	end := l.Start
+	for _, s := range l.Segments {
+		end = max(end, s.End)
+	}
+	if offset > end && end > l.Start {
+		return p.X(m, i, end)
+	}
+	return 0
+}
+
+// edge returns the offset from the left of the line of the boundary
+// before the byte at offset in s.
+func (p *Paragraph) edge(m core.TextMeasurer, s *Segment, offset int) float32 {
+	var w float32
+	switch part := p.text[s.Start:offset]; {
+	case offset >= s.End:
+		w = s.Width
+	case isSpaces(p.text[s.Start:s.End]):
+		w = s.Width * float32(utf8.RuneCountInString(part)) / float32(utf8.RuneCountInString(p.text[s.Start:s.End]))
+	default:
+		w = p.advance(m, s.Run, part)
+	}
+	if s.RTL {
+		return s.X + s.Width - w
+	}
+	return s.X + w
+}
+
+// OffsetAt returns the cluster boundary of line i nearest x, measured
+// from the left of the line.
+func (p *Paragraph) OffsetAt(m core.TextMeasurer, i int, x float32) int {
+	l := &p.Lines[i]
+	end := l.Start
+	for _, s := range l.Segments {
+		end = max(end, s.End)
+	}
+	text := []rune(p.text[l.Start:end])
+	best, dist := l.Start, float32(core.Infinity)
+	at := l.Start
+	for k := 0; ; {
+		d := p.X(m, i, at) - x
+		if d < 0 {
+			d = -d
+		}
+		if d < dist {
+			best, dist = at, d
+		}
+		if k == len(text) {
+			return best
+		}
+		next := grapheme.Next(text, k)
+		for _, r := range text[k:next] {
+			at += utf8.RuneLen(r)
+		}
+		k = next
+	}
+}
+
+// Highlight returns the boxes covering the text from start up to end on
+// line i, relative to the top left of the line: one for each segment the
+// text is in, so text crossing runs of both directions is covered in
+// pieces.
+func (p *Paragraph) Highlight(m core.TextMeasurer, i, start, end int) []core.Rect {
+	var out []core.Rect
+	l := &p.Lines[i]
+	for k := range l.Segments {
+		s := &l.Segments[k]
+		a, z := max(start, s.Start), min(end, s.End)
+		if a >= z {
+			continue
+		}
+		x0, x1 := p.edge(m, s, a), p.edge(m, s, z)
+		out = append(out, core.R(min(x0, x1), 0, max(x0, x1)-min(x0, x1), l.Height))
+	}
+	return out
+}
+
+// span is a line of text before it is laid out: the text from start up
+// to end, broken at a soft hyphen or hyphenated if hyphen is set, and the
+// last line of its paragraph if last is.
+type span struct {
+	start, end int
+	hyphen     bool
+	last       bool
+}
+
+type breaker struct {
+	content
+	m      core.TextMeasurer
+	width  float32
+	noWrap bool
+	hyph   *hyphen.Hyphenator
+}
+
+// measure returns the width of the text from start up to end as drawn at
+// the end of a line: without the spaces it ends in, and with a hyphen if
+// hyphen is set.
+func (b *breaker) measure(start, end int, hyphen bool) float32 {
+	end = start + len(strings.TrimRightFunc(b.text[start:end], isSpace))
+	w := b.content.measure(b.m, start, end)
+	if hyphen {
+		w += b.advance(b.m, b.runAt(max(end-1, 0)), hyphenText)
+	}
+	return w
+}
+
+// paragraph breaks the text from start up to end into lines no wider than
+// the width, filling each line before starting the next. A word that
+// does not fit is hyphenated if it can be, and one too wide for a line of
+// its own runs past the width.
+func (b *breaker) paragraph(start, end int) []span {
+	if b.noWrap {
+		return []span{{start: start, end: end}}
+	}
+	opps := opportunities(b.text[start:end])
+	for k := range opps {
+		opps[k].at += start
+	}
+	var out []span
+	line, fit := start, -1 // fit is the last opportunity that fits on the line.
+	for k := 0; k < len(opps); {
+		o := opps[k]
+		if b.measure(line, o.at, o.soft) <= b.width {
+			fit = k
+			k++
+			continue
+		}
+		from := line
+		if fit >= 0 {
+			from = opps[fit].at
+		}
+		switch q, ok := b.hyphenate(line, from, o.at); {
+		case ok:
+			out = append(out, span{start: line, end: q, hyphen: true})
+			line = q
+		case fit >= 0:
+			out = append(out, span{start: line, end: from, hyphen: opps[fit].soft})
+			line = from
+		default:
+			out = append(out, span{start: line, end: o.at, hyphen: o.soft})
+			line = o.at
+			k++
+		}
+		fit = -1
+	}
+	if line < end || len(out) == 0 {
+		out = append(out, span{start: line, end: end})
+	}
+	return out
+}
+
+// hyphenate returns the last hyphenation point of the word starting at
+// from, the text up to to, at which the line starting at line fits.
+func (b *breaker) hyphenate(line, from, to int) (int, bool) {
+	if b.hyph == nil {
+		return 0, false
+	}
+	piece := b.text[from:to]
+	word := strings.TrimLeftFunc(piece, func(r rune) bool { return !unicode.IsLetter(r) })
+	from += len(piece) - len(word)
+	if i := strings.IndexFunc(word, func(r rune) bool { return !unicode.IsLetter(r) }); i >= 0 {
+		word = word[:i]
+	}
+	points := b.hyph.Points(word)
+	for i := len(points) - 1; i >= 0; i-- {
+		if at := from + points[i]; b.measure(line, at, true) <= b.width {
+			return at, true
+		}
+	}
+	return 0, false
+}
+
+// line lays out the text of s, hanging the spaces it ends in past its
+// end and ending it with suffix, or a hyphen if it was broken at one.
+// The directional runs of text mixing directions are placed left to
+// right, split where the style changes, and the spaces of a justified
+// line stretched to fill the width.
+func (b *breaker) line(s span, justify, rtl bool, suffix string) Line {
+	l := Line{Start: s.start, End: s.end}
+	end := s.start + len(strings.TrimRightFunc(b.text[s.start:s.end], isSpace))
+	if s.hyphen && suffix == "" {
+		suffix = hyphenText
+	}
+	runes := []rune(b.text[s.start:end])
+	at := make([]int, len(runes)+1) // The byte offset of each rune.
+	at[0] = s.start
+	for i, r := range runes {
+		at[i+1] = at[i] + utf8.RuneLen(r)
+	}
+	base := bidi.LeftToRight
+	if rtl {
+		base = bidi.RightToLeft
+	}
+	added := Segment{Text: suffix, Run: b.runAt(max(end-1, s.start)), Start: end, End: end, RTL: rtl, Alpha: 1}
+	if len(runes) == 0 && suffix != "" {
+		l.Segments = append(l.Segments, added)
+	}
+	var spaces int
+	for _, r := range bidi.Resolve(runes, base).Runs(0, len(runes)) {
+		var pieces []Segment
+		for i := r.Start; i < r.End; {
+			run := b.runAt(at[i])
+			j := i + 1
+			for j < r.End && b.runAt(at[j]) == run && (justify && stretches(runes[j]) == stretches(runes[i]) || !justify) {
+				j++
+			}
+			if justify && stretches(runes[i]) {
+				spaces += j - i
+			}
+			pieces = append(pieces, Segment{Run: run, Start: at[i], End: at[j], RTL: r.IsRTL(), Alpha: 1})
+			i = j
+		}
+		if r.IsRTL() {
+			slices.Reverse(pieces)
+		}
+		if suffix != "" && r.End == len(runes) {
+			added.RTL = r.IsRTL()
+			if r.IsRTL() {
+				pieces = append([]Segment{added}, pieces...)
+			} else {
+				pieces = append(pieces, added)
+			}
+		}
+		l.Segments = append(l.Segments, pieces...)
+	}
+	for k := range l.Segments {
+		g := &l.Segments[k]
+		if g.End > g.Start {
+			g.Text = visible(b.text[g.Start:g.End])
+		}
+		g.Width = b.advance(b.m, g.Run, g.Text)
+		l.Width += g.Width
+	}
+	if extra := b.width - l.Width; spaces > 0 && extra > 0 {
+		for k := range l.Segments {
+			if g := &l.Segments[k]; isSpaces(b.text[g.Start:g.End]) {
+				g.Width += extra * float32(utf8.RuneCountInString(g.Text)) / float32(spaces)
+			}
+		}
+		l.Width = b.width
+	}
+	var x float32
+	for k := range l.Segments {
+		l.Segments[k].X = x
+		x += l.Segments[k].Width
+	}
+	return l
+}
+
+// metrics sets the height of l and its baseline, so the tallest style or
+// box on it fits. An empty line takes the style of the text it is in.
+func (b *breaker) metrics(l *Line) {
+	var ascent, descent float32
+	measured := false
+	for _, s := range l.Segments {
+		r := &b.runs[s.Run]
+		if r.Box {
+			ascent = max(ascent, r.Size.Height)
+		} else {
+			ascent = max(ascent, r.Style.Ascent())
+			descent = max(descent, r.Style.LineHeight()-r.Style.Ascent())
+		}
+		measured = true
+	}
+	if !measured {
+		st := b.runs[b.runAt(l.Start)].Style
+		ascent, descent = st.Ascent(), st.LineHeight()-st.Ascent()
+	}
+	l.Ascent, l.Height = ascent, ascent+descent
+}
+
+// ellipsize lays out s cut at the last cluster boundary at which it fits
+// in the width with an ellipsis after it.
+func (b *breaker) ellipsize(s span, rtl bool) Line {
+	end := s.start + len(strings.TrimRightFunc(b.text[s.start:s.end], isSpace))
+	w := b.advance(b.m, b.runAt(max(end-1, s.start)), ellipsisText)
+	runes := []rune(b.text[s.start:end])
+	cut, at := s.start, s.start
+	for i := 0; i < len(runes); {
+		next := grapheme.Next(runes, i)
+		for _, r := range runes[i:next] {
+			at += utf8.RuneLen(r)
+		}
+		if b.measure(s.start, at, false)+w > b.width {
+			break
+		}
+		cut, i = at, next
+	}
+	return b.line(span{start: s.start, end: cut}, false, rtl, ellipsisText)
+}
+
+// fade fades out the end of l, in the reading direction, over the last
+// fadeLength ems of it or of the width, leaving out the text past the
+// width.
+func (b *breaker) fade(l *Line, rtl bool) {
+	v0, v1 := float32(0), l.Width
+	if l.Width > b.width {
+		if rtl {
+			v0 = l.Width - b.width
+		} else {
+			v1 = b.width
+		}
+	}
+	var size float32
+	for _, g := range l.Segments {
+		size = max(size, b.runs[g.Run].Style.Size)
+	}
+	length := fadeLength * size
+	alpha := func(x0, x1 float32) float32 {
+		mid := (x0 + x1) / 2
+		if rtl {
+			return min(max((mid-v0)/length, 0), 1)
+		}
+		return min(max((v1-mid)/length, 0), 1)
+	}
+	var out []Segment
+	for _, g := range l.Segments {
+		x0, x1 := g.X, g.X+g.Width
+		switch {
+		case x1 <= v0 || x0 >= v1:
+			continue
+		case x0 >= v0 && x1 <= v1 && alpha(x0, x0) == 1 && alpha(x1, x1) == 1:
+			out = append(out, g)
+			continue
+		}
+		for _, c := range b.clusters(g) {
+			if mid := c.X + c.Width/2; mid >= v0 && mid <= v1 {
+				c.Alpha = alpha(c.X, c.X+c.Width)
+				out = append(out, c)
+			}
+		}
+	}
+	for k := range out {
+		out[k].X -= v0
+	}
+	l.Segments = out
+	l.Width = v1 - v0
+}
+
+// clusters splits g into a segment for each of its clusters.
+func (b *breaker) clusters(g Segment) []Segment {
+	if g.End == g.Start {
+		return []Segment{g}
+	}
+	runes := []rune(b.text[g.Start:g.End])
+	var out []Segment
+	at := g.Start
+	var w float32 // The width of the clusters before.
+	for i := 0; i < len(runes); {
+		next := grapheme.Next(runes, i)
+		c := Segment{Text: visible(string(runes[i:next])), Run: g.Run, Start: at, RTL: g.RTL, Alpha: g.Alpha}
+		for _, r := range runes[i:next] {
+			at += utf8.RuneLen(r)
+		}
+		c.End = at
+		c.Width = b.advance(b.m, c.Run, c.Text)
+		if isSpaces(c.Text) && g.Width > 0 {
+			// Justified spaces keep their share of the stretched width.
+			c.Width = g.Width * float32(next-i) / float32(len(runes))
+		}
+		c.X = g.X + w
+		if g.RTL {
+			c.X = g.X + g.Width - w - c.Width
+		}
+		w += c.Width
+		if c.Text != "" {
+			out = append(out, c)
+		}
+		i = next
+	}
+	return out
+}
+
+// visible returns s without its soft hyphens, which are only drawn when
+// a line is broken at one.
+func visible(s string) string {
+	if !strings.ContainsRune(s, softHyphen) {
+		return s
+	}
+	return strings.ReplaceAll(s, string(softHyphen), "")
+}
+
+// isSpaces reports whether s is all spaces.
+func isSpaces(s string) bool {
+	for _, r := range s {
+		if !stretches(r) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// stretches reports whether r is a space that justification stretches,
+// including the no-break spaces.
+func stretches(r rune) bool {
+	return isSpace(r) || r == '\u00A0' || r == '\u202F'
+}
@@ -0,0 +1,77 @@
+package paragraph
+
+import (
+	"slices"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/hyphen"
+)
+
+// monospace measures every character 10 pixels wide.
+type monospace struct{}
+
+func (monospace) MeasureText(text string, style core.TextStyle) core.Size {
+	return core.Sz(10*float32(utf8.RuneCountInString(text)), style.LineHeight())
+}
+
+// lineTexts returns the text drawn on each line of p.
+func lineTexts(p *Paragraph) []string {
+	var out []string
+	for _, l := range p.Lines {
+		var s string
+		for _, g := range l.Segments {
+			s += g.Text
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+func TestHyphenation(t *testing.T) {
+	// Liang's patterns, which break "hyphenation" as hy-phen-ation.
+	hyphen.Register("x-liang", hyphen.New("hy3ph he2n hena4 hen5at 1na n2at 1tio 2io o2n", ""))
+	tests := []struct {
+		name      string
+		text      string
+		width     float32
+		hyphenate bool
+		want      []string
+	}{
+		{"last point that fits", "a hyphenation", 90, true, []string{"a hyphen-", "ation"}},
+		{"earlier points", "a hyphenation", 70, true, []string{"a hy-", "phen-", "ation"}},
+		{"off", "a hyphenation", 90, false, []string{"a", "hyphenation"}},
+		{"fits whole", "a hyphenation", 130, true, []string{"a hyphenation"}},
+		{"soft hyphen", "a hyphen\u00ADation", 90, false, []string{"a hyphen-", "ation"}},
+		{"soft hyphen that fits", "a hyphen\u00ADation", 130, false, []string{"a hyphenation"}},
+	}
+	style := core.TextStyle{Size: 10}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New(monospace{}, tt.text, style, Options{Width: tt.width, Hyphenate: tt.hyphenate, Locale: "x-liang"})
+			if got := lineTexts(p); !slices.Equal(got, tt.want) {
+				t.Errorf("lines %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHyphenSegment(t *testing.T) {
+	p := New(monospace{}, "a hyphenation", core.TextStyle{Size: 10}, Options{Width: 90, Hyphenate: true, Locale: "x-liang"})
+	if len(p.Lines) != 2 {
+		t.Fatalf("%d lines, want 2", len(p.Lines))
+	}
+	l := p.Lines[0]
+	if l.End != 8 || p.Lines[1].Start != 8 {
+		t.Errorf("lines break at %d and %d, want 8", l.End, p.Lines[1].Start)
+	}
+	// The hyphen is drawn after the text but stands for none of it.
+	h := l.Segments[len(l.Segments)-1]
+	if h.Text != "-" || h.Start != h.End || h.X != 80 || h.Width != 10 {
+		t.Errorf("hyphen %+v, want - at 80 standing for no text", h)
+	}
+	if l.Width != 90 {
+		t.Errorf("line width %v, want 90 with the hyphen", l.Width)
+	}
+}
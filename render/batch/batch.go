@@ -0,0 +1,494 @@
+package batch
+
+import (
+	"image"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/render/sdf"
+)
+
+// Pipeline is the kind of draw a Batch is.
+type Pipeline uint8
+
+const (
+	// PipelineRect draws RectInstances, with RectShader.
+	PipelineRect Pipeline = iota
+
+	// PipelineGlyph draws QuadInstances of glyphs from the atlas page
+	// Batch.Texture, an int, with sdf.Shader.
+	PipelineGlyph
+
+	// PipelineImage draws QuadInstances of the image Batch.Texture.
+	PipelineImage
+
+	// PipelinePath is a single path the backend draws itself, in
+	// Batch.Path.
+	PipelinePath
+
+	// PipelineText is a single line of text the backend draws itself, in
+	// Batch.Text, on canvases without a glyph atlas.
+	PipelineText
+
+	// PipelineShadow draws ShadowInstances, with ShadowShader.
+	PipelineShadow
+
+	// PipelineBackdrop is a single filter of what was drawn before, in
+	// Batch.Backdrop, which the backend applies as a compositor pass.
+	PipelineBackdrop
+)
+
+// RectInstance is a rectangle with rounded corners, filled and stroked,
+// in device pixels. Colors are premultiplied.
+type RectInstance struct {
+	Rect        [4]float32 // x, y, width, height
+	Clip        [4]float32 // left, top, right, bottom
+	Fill        [4]float32
+	Stroke      [4]float32
+	Radius      float32
+	StrokeWidth float32
+}
+
+// QuadInstance is a textured rectangle, a glyph or an image, in device
+// pixels. Color is premultiplied, that of the text for glyphs and white
+// for images.
+type QuadInstance struct {
+	Rect  [4]float32 // x, y, width, height
+	UV    [4]float32 // left, top, right, bottom, from 0 to 1
+	Clip  [4]float32 // left, top, right, bottom
+	Color [4]float32
+}
+
+// ShadowInstance is the blurred shadow of a rounded rectangle, in device
+// pixels: the rectangle moved by the offset of the shadow and grown by its
+// spread. Color is premultiplied.
+type ShadowInstance struct {
+	Rect   [4]float32 // x, y, width, height
+	Clip   [4]float32 // left, top, right, bottom
+	Color  [4]float32
+	Radius float32
+	Blur   float32 // The standard deviation of the blur.
+}
+
+// PathDraw is a path drawn outside the instances.
+type PathDraw struct {
+	Path      *core.Path
+	Style     core.PathStyle
+	Transform core.Point // Offset of the path, in logical pixels.
+	Clip      core.Rect  // In device pixels.
+}
+
+// TextDraw is a line of text drawn outside the instances.
+type TextDraw struct {
+	Text  string
+	Pos   core.Point // In logical pixels, with the offset applied.
+	Style core.TextStyle
+	Clip  core.Rect // In device pixels.
+}
+
+// BackdropDraw is a filter of what was drawn inside a rounded rectangle,
+// as core.BackdropCanvas describes, applied outside the instances.
+type BackdropDraw struct {
+	Rect   core.Rect // In logical pixels, with the offset applied.
+	Radius float32
+	Filter core.Filter
+	Clip   core.Rect // In device pixels.
+}
+
+// Batch is one draw of a Frame: Count instances from First of the Rects,
+// Quads or Shadows of the frame, by Pipeline, or a single Path, Text or
+// Backdrop.
+type Batch struct {
+	Pipeline Pipeline
+	Texture  any
+	First    int
+	Count    int
+	Path     *PathDraw
+	Text     *TextDraw
+	Backdrop *BackdropDraw
+}
+
+// Frame is the drawing of a frame as batches, in the order they must be
+// drawn.
+type Frame struct {
+	Rects   []RectInstance
+	Quads   []QuadInstance
+	Shadows []ShadowInstance
+	Batches []Batch
+}
+
+// DrawCalls returns the number of draws of the frame, one for each batch.
+func (f *Frame) DrawCalls() int {
+	return len(f.Batches)
+}
+
+// Triangles returns the number of triangles the instances of the frame
+// draw, two for each. The paths, text and backdrops passed through are
+// left for the backend to count as it draws them.
+func (f *Frame) Triangles() int {
+	return 2 * (len(f.Rects) + len(f.Quads) + len(f.Shadows))
+}
+
+// Shaper positions the glyphs of text for a Canvas, usually with package
+// font.
+type Shaper interface {
+	// Shape returns the glyphs of a line of text in style, positioned
+	// from the pen on the baseline at the start of the line, in logical
+	// pixels. Their keys leave Subpixel for the canvas to set.
+	Shape(text string, style core.TextStyle) []ShapedGlyph
+}
+
+// ShapedGlyph is a glyph positioned by a Shaper.
+type ShapedGlyph struct {
+	Key sdf.GlyphKey
+	Pos core.Point
+}
+
+// unbounded is the extent of the clip of a canvas nothing clipped, in
+// device pixels, beyond any surface.
+const unbounded = 1 << 24
+
+// lookback is how many batches back a primitive may join one of its
+// pipeline and texture, past batches it does not overlap.
+const lookback = 16
+
+// maxBoxes is the most boxes the bounds of a batch are kept as.
+const maxBoxes = 256
+
+// pending is a batch being collected, with the instances of its own.
+type pending struct {
+	pipeline Pipeline
+	texture  any
+	boxes    []core.Rect // Bounding what it draws, in device pixels.
+	rects    []RectInstance
+	quads    []QuadInstance
+	shadows  []ShadowInstance
+	path     *PathDraw
+	text     *TextDraw
+	backdrop *BackdropDraw
+}
+
+// Canvas is a core.Canvas collecting what is drawn into the batches of a
+// Frame.
+type Canvas struct {
+	scale  float32
+	atlas  *sdf.Atlas
+	shaper Shaper
+
+	tx, ty float32
+	clip   core.Rect // In device pixels.
+	stack  []state
+
+	batches []*pending
+	free    []*pending
+}
+
+type state struct {
+	tx, ty float32
+	clip   core.Rect
+}
+
+// New returns a canvas for a frame at scale device pixels to each logical
+// one. Without a glyph atlas text is passed through as PipelineText.
+func New(scale float32) *Canvas {
+	if scale <= 0 {
+		scale = 1
+	}
+	c := &Canvas{scale: scale}
+	c.Reset()
+	return c
+}
+
+// Glyphs draws text as glyph quads from atlas, positioned by shaper.
+func (c *Canvas) Glyphs(atlas *sdf.Atlas, shaper Shaper) *Canvas {
+	c.atlas, c.shaper = atlas, shaper
+	return c
+}
+
+// Reset starts a new frame, keeping the memory of the last.
+func (c *Canvas) Reset() {
+	for _, b := range c.batches {
+		*b = pending{boxes: b.boxes[:0], rects: b.rects[:0], quads: b.quads[:0], shadows: b.shadows[:0]}
+		c.free = append(c.free, b)
+	}
+	c.batches = c.batches[:0]
+	c.tx, c.ty, c.stack = 0, 0, c.stack[:0]
+	c.clip = core.Rect{X: -unbounded, Y: -unbounded, Width: 2 * unbounded, Height: 2 * unbounded}
+}
+
+// Finish returns the frame drawn since Reset, into f if it is not nil,
+// and resets the canvas.
+func (c *Canvas) Finish(f *Frame) *Frame {
+	if f == nil {
+		f = &Frame{}
+	}
+	f.Rects, f.Quads, f.Shadows, f.Batches = f.Rects[:0], f.Quads[:0], f.Shadows[:0], f.Batches[:0]
+	for _, p := range c.batches {
+		b := Batch{Pipeline: p.pipeline, Texture: p.texture, Path: p.path, Text: p.text, Backdrop: p.backdrop, Count: 1}
+		switch p.pipeline {
+		case PipelineRect:
+			b.First, b.Count = len(f.Rects), len(p.rects)
+			f.Rects = append(f.Rects, p.rects...)
+		case PipelineGlyph, PipelineImage:
+			b.First, b.Count = len(f.Quads), len(p.quads)
+			f.Quads = append(f.Quads, p.quads...)
+		case PipelineShadow:
+			b.First, b.Count = len(f.Shadows), len(p.shadows)
+			f.Shadows = append(f.Shadows, p.shadows...)
+		}
+		f.Batches = append(f.Batches, b)
+	}
+	c.Reset()
+	return f
+}
+
+// batch returns the batch of pipeline and texture a primitive covering
+// bounds joins: the latest such batch that nothing drawn after it
+// overlaps, within lookback, or a new one.
+func (c *Canvas) batch(pipeline Pipeline, texture any, bounds core.Rect) *pending {
+	if pipeline != PipelinePath && pipeline != PipelineText && pipeline != PipelineBackdrop {
+		for i := len(c.batches) - 1; i >= max(len(c.batches)-lookback, 0); i-- {
+			b := c.batches[i]
+			if b.pipeline == pipeline && b.texture == texture {
+				b.add(bounds)
+				return b
+			}
+			if b.overlaps(bounds) {
+				break
+			}
+		}
+	}
+	var b *pending
+	if n := len(c.free); n > 0 {
+		b, c.free = c.free[n-1], c.free[:n-1]
+	} else {
+		b = &pending{}
+	}
+	b.pipeline, b.texture = pipeline, texture
+	b.add(bounds)
+	c.batches = append(c.batches, b)
+	return b
+}
+
+// add adds r to the bounds of the batch. Boxes drawn next to one another,
+// such as the glyphs of a word, make one box, while separate ones, such
+// as the text of the cells of a grid, stay apart, so that what is drawn
+// between them can still pass the batch. Past maxBoxes, neighbouring
+// boxes are merged in pairs.
+func (p *pending) add(r core.Rect) {
+	area := func(r core.Rect) float32 { return r.Width * r.Height }
+	if n := len(p.boxes); n > 0 {
+		last := p.boxes[n-1]
+		if u := last.Union(r); area(u) <= 1.25*(area(last)+area(r)) {
+			p.boxes[n-1] = u
+			return
+		}
+	}
+	p.boxes = append(p.boxes, r)
+	if n := len(p.boxes); n > maxBoxes {
+		for i := range n / 2 {
+			p.boxes[i] = p.boxes[2*i].Union(p.boxes[2*i+1])
+		}
+		if n%2 == 1 {
+			p.boxes[n/2] = p.boxes[n-1]
+		}
+		p.boxes = p.boxes[:(n+1)/2]
+	}
+}
+
+// overlaps reports whether what the batch draws may overlap r.
+func (p *pending) overlaps(r core.Rect) bool {
+	for _, b := range p.boxes {
+		if !b.Intersect(r).IsEmpty() {
+			return true
+		}
+	}
+	return false
+}
+
+// device returns r in device pixels.
+func (c *Canvas) device(r core.Rect) core.Rect {
+	s := c.scale
+	return core.Rect{X: (r.X + c.tx) * s, Y: (r.Y + c.ty) * s, Width: r.Width * s, Height: r.Height * s}
+}
+
+func (c *Canvas) clipEdges() [4]float32 {
+	return [4]float32{c.clip.X, c.clip.Y, c.clip.Right(), c.clip.Bottom()}
+}
+
+func premul(col core.Color) [4]float32 {
+	a := core.Clamp(col.A, 0, 1)
+	return [4]float32{col.R * a, col.G * a, col.B * a, a}
+}
+
+// DrawRect implements core.Canvas.
+func (c *Canvas) DrawRect(r core.Rect, st core.RectStyle) {
+	c.DrawRoundedRect(r, 0, st)
+}
+
+// DrawRoundedRect implements core.Canvas, with an instance unless the
+// style has gradients.
+func (c *Canvas) DrawRoundedRect(r core.Rect, radius float32, st core.RectStyle) {
+	if st.FillGradient != nil || st.StrokeGradient != nil {
+		c.DrawPath(core.NewPath().AddRoundedRect(r, radius), core.PathStyle{
+			Fill: st.Fill, Stroke: st.Stroke, StrokeWidth: st.StrokeWidth, LineJoin: core.JoinMiter,
+			FillGradient: st.FillGradient, StrokeGradient: st.StrokeGradient,
+		})
+		return
+	}
+	if st.Fill.IsTransparent() && (st.Stroke.IsTransparent() || st.StrokeWidth <= 0) {
+		return
+	}
+	d := c.device(r)
+	w := st.StrokeWidth * c.scale
+	bounds := d.Inset(core.UniformInsets(-w / 2)).Intersect(c.clip)
+	if bounds.IsEmpty() {
+		return
+	}
+	b := c.batch(PipelineRect, nil, bounds)
+	b.rects = append(b.rects, RectInstance{
+		Rect:        [4]float32{d.X, d.Y, d.Width, d.Height},
+		Clip:        c.clipEdges(),
+		Fill:        premul(st.Fill),
+		Stroke:      premul(st.Stroke),
+		Radius:      min(radius*c.scale, d.Width/2, d.Height/2),
+		StrokeWidth: w,
+	})
+}
+
+// DrawText implements core.Canvas, with a glyph quad for each glyph when
+// the canvas has a glyph atlas.
+func (c *Canvas) DrawText(text string, pos core.Point, st core.TextStyle) {
+	if st.Color.IsTransparent() || text == "" {
+		return
+	}
+	if c.atlas == nil || c.shaper == nil {
+		b := c.batch(PipelineText, nil, c.clip)
+		b.text = &TextDraw{Text: text, Pos: pos.Add(core.Pt(c.tx, c.ty)), Style: st, Clip: c.clip}
+		return
+	}
+	s := c.scale
+	size := st.Size * s
+	baseline := core.SnapToPixel(pos.Y+c.ty+st.Ascent(), s) * s
+	color, clip := premul(st.Color), c.clipEdges()
+	for _, g := range c.shaper.Shape(text, st) {
+		x, phase := sdf.SubpixelPosition((pos.X + c.tx + g.Pos.X) * s)
+		key := g.Key
+		key.Subpixel = phase
+		ag, ok := c.atlas.Glyph(key)
+		if !ok || ag.Region.Empty() {
+			continue
+		}
+		q := ag.Quad(core.Pt(x, baseline+g.Pos.Y*s), size)
+		bounds := q.Intersect(c.clip)
+		if bounds.IsEmpty() {
+			continue
+		}
+		page := c.atlas.Image(ag.Page).Bounds()
+		pw, ph := float32(page.Dx()), float32(page.Dy())
+		b := c.batch(PipelineGlyph, ag.Page, bounds)
+		b.quads = append(b.quads, QuadInstance{
+			Rect:  [4]float32{q.X, q.Y, q.Width, q.Height},
+			UV:    [4]float32{float32(ag.Region.Min.X) / pw, float32(ag.Region.Min.Y) / ph, float32(ag.Region.Max.X) / pw, float32(ag.Region.Max.Y) / ph},
+			Clip:  clip,
+			Color: color,
+		})
+	}
+}
+
+// DrawImage implements core.Canvas, with a quad of the image. Images are
+// batched by identity, so img should be a pointer, as the images of the
+// standard library are.
+func (c *Canvas) DrawImage(img image.Image, r core.Rect) {
+	d := c.device(r)
+	bounds := d.Intersect(c.clip)
+	if bounds.IsEmpty() {
+		return
+	}
+	b := c.batch(PipelineImage, img, bounds)
+	b.quads = append(b.quads, QuadInstance{
+		Rect:  [4]float32{d.X, d.Y, d.Width, d.Height},
+		UV:    [4]float32{0, 0, 1, 1},
+		Clip:  c.clipEdges(),
+		Color: [4]float32{1, 1, 1, 1},
+	})
+}
+
+// DrawPath implements core.Canvas, passing the path through.
+func (c *Canvas) DrawPath(p *core.Path, st core.PathStyle) {
+	if p.IsEmpty() {
+		return
+	}
+	w := st.StrokeWidth * c.scale
+	bounds := c.device(p.Bounds()).Inset(core.UniformInsets(-w)).Intersect(c.clip)
+	if bounds.IsEmpty() {
+		return
+	}
+	b := c.batch(PipelinePath, nil, bounds)
+	b.path = &PathDraw{Path: p, Style: st, Transform: core.Pt(c.tx, c.ty), Clip: c.clip}
+}
+
+// DrawShadow implements core.ShadowCanvas, with an instance evaluating the
+// blurred rounded rectangle for each pixel. Shadows blurred by less than
+// half a device pixel are drawn as rounded rectangles.
+func (c *Canvas) DrawShadow(r core.Rect, radius float32, s core.Shadow) {
+	if s.Color.IsTransparent() {
+		return
+	}
+	r = r.Translate(s.Offset).Inset(core.UniformInsets(-s.Spread))
+	radius = max(radius+s.Spread, 0)
+	blur := s.Blur * c.scale
+	if blur < 0.5 {
+		c.DrawRoundedRect(r, radius, core.Filled(s.Color))
+		return
+	}
+	d := c.device(r)
+	bounds := d.Inset(core.UniformInsets(-3 * blur)).Intersect(c.clip)
+	if d.Width <= 0 || d.Height <= 0 || bounds.IsEmpty() {
+		return
+	}
+	b := c.batch(PipelineShadow, nil, bounds)
+	b.shadows = append(b.shadows, ShadowInstance{
+		Rect:   [4]float32{d.X, d.Y, d.Width, d.Height},
+		Clip:   c.clipEdges(),
+		Color:  premul(s.Color),
+		Radius: min(radius*c.scale, d.Width/2, d.Height/2),
+		Blur:   blur,
+	})
+}
+
+// DrawBackdrop implements core.BackdropCanvas, passing the filter through.
+// Its batch bounds what the filter reads as well as what it draws, so
+// that nothing drawn after it over either joins a batch before it.
+func (c *Canvas) DrawBackdrop(r core.Rect, radius float32, f core.Filter) {
+	if f.IsZero() || c.device(r).Intersect(c.clip).IsEmpty() {
+		return
+	}
+	reach := c.device(r).Inset(core.UniformInsets(-f.Reach() * c.scale))
+	b := c.batch(PipelineBackdrop, nil, reach)
+	b.backdrop = &BackdropDraw{Rect: r.Translate(core.Pt(c.tx, c.ty)), Radius: radius, Filter: f, Clip: c.clip}
+}
+
+// Save implements core.Canvas.
+func (c *Canvas) Save() {
+	c.stack = append(c.stack, state{c.tx, c.ty, c.clip})
+}
+
+// Restore implements core.Canvas.
+func (c *Canvas) Restore() {
+	if n := len(c.stack); n > 0 {
+		s := c.stack[n-1]
+		c.tx, c.ty, c.clip = s.tx, s.ty, s.clip
+		c.stack = c.stack[:n-1]
+	}
+}
+
+// Translate implements core.Canvas.
+func (c *Canvas) Translate(x, y float32) {
+	c.tx += x
+	c.ty += y
+}
+
+// Clip implements core.Canvas.
+func (c *Canvas) Clip(r core.Rect) {
+	c.clip = c.clip.Intersect(c.device(r))
+}
@@ -0,0 +1,254 @@
+package batch
+
+import (
+	"image"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+)
+
+// pipelines returns the pipeline and instance count of each batch of f.
+func pipelines(f *Frame) []Batch {
+	out := make([]Batch, len(f.Batches))
+	for i, b := range f.Batches {
+		out[i] = Batch{Pipeline: b.Pipeline, Count: b.Count}
+	}
+	return out
+}
+
+func TestCanvasBatches(t *testing.T) {
+	red := core.Filled(core.RGB(255, 0, 0))
+	img, other := image.NewRGBA(image.Rect(0, 0, 1, 1)), image.NewRGBA(image.Rect(0, 0, 1, 1))
+	// images draws n images of textures of their own in a row at y, none
+	// of them overlapping another.
+	images := func(cv *Canvas, n int, y float32) {
+		for i := range n {
+			cv.DrawImage(image.NewRGBA(image.Rect(0, 0, 1, 1)), core.R(float32(i)*20, y, 10, 10))
+		}
+	}
+	tests := []struct {
+		name string
+		draw func(cv *Canvas)
+		want []Batch
+	}{
+		{"apart", func(cv *Canvas) {
+			cv.DrawRect(core.R(0, 0, 10, 10), red)
+			cv.DrawImage(img, core.R(20, 0, 10, 10))
+			cv.DrawRect(core.R(40, 0, 10, 10), red)
+		}, []Batch{{Pipeline: PipelineRect, Count: 2}, {Pipeline: PipelineImage, Count: 1}}},
+		{"over an image", func(cv *Canvas) {
+			cv.DrawRect(core.R(0, 0, 10, 10), red)
+			cv.DrawImage(img, core.R(0, 0, 10, 10))
+			cv.DrawRect(core.R(5, 5, 10, 10), red)
+		}, []Batch{{Pipeline: PipelineRect, Count: 1}, {Pipeline: PipelineImage, Count: 1}, {Pipeline: PipelineRect, Count: 1}}},
+		{"textures apart", func(cv *Canvas) {
+			cv.DrawImage(img, core.R(0, 0, 10, 10))
+			cv.DrawImage(other, core.R(20, 0, 10, 10))
+			cv.DrawImage(img, core.R(40, 0, 10, 10))
+		}, []Batch{{Pipeline: PipelineImage, Count: 2}, {Pipeline: PipelineImage, Count: 1}}},
+		{"within the lookback", func(cv *Canvas) {
+			cv.DrawRect(core.R(0, 100, 10, 10), red)
+			images(cv, lookback-1, 0)
+			cv.DrawRect(core.R(20, 100, 10, 10), red)
+		}, append([]Batch{{Pipeline: PipelineRect, Count: 2}}, slices.Repeat([]Batch{{Pipeline: PipelineImage, Count: 1}}, lookback-1)...)},
+		{"past the lookback", func(cv *Canvas) {
+			cv.DrawRect(core.R(0, 100, 10, 10), red)
+			images(cv, lookback, 0)
+			cv.DrawRect(core.R(20, 100, 10, 10), red)
+		}, slices.Concat([]Batch{{Pipeline: PipelineRect, Count: 1}}, slices.Repeat([]Batch{{Pipeline: PipelineImage, Count: 1}}, lookback), []Batch{{Pipeline: PipelineRect, Count: 1}})},
+		{"between separate boxes", func(cv *Canvas) {
+			// The rects are kept as two boxes, so the image between them
+			// joins the image before them.
+			cv.DrawImage(img, core.R(50, 50, 10, 10))
+			cv.DrawRect(core.R(0, 0, 10, 10), red)
+			cv.DrawRect(core.R(100, 0, 10, 10), red)
+			cv.DrawImage(img, core.R(50, 0, 10, 10))
+		}, []Batch{{Pipeline: PipelineImage, Count: 2}, {Pipeline: PipelineRect, Count: 2}}},
+		{"paths", func(cv *Canvas) {
+			cv.DrawRect(core.R(0, 0, 10, 10), red)
+			cv.DrawPath(core.NewPath().AddRect(core.R(50, 0, 10, 10)), core.PathStyle{Fill: core.Black})
+			cv.DrawPath(core.NewPath().AddRect(core.R(70, 0, 10, 10)), core.PathStyle{Fill: core.Black})
+			cv.DrawRect(core.R(20, 0, 10, 10), red)
+		}, []Batch{{Pipeline: PipelineRect, Count: 2}, {Pipeline: PipelinePath, Count: 1}, {Pipeline: PipelinePath, Count: 1}}},
+		{"clipped out", func(cv *Canvas) {
+			cv.Clip(core.R(0, 0, 10, 10))
+			cv.DrawRect(core.R(20, 0, 10, 10), red)
+			cv.DrawImage(img, core.R(20, 0, 10, 10))
+		}, []Batch{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cv := New(1)
+			tt.draw(cv)
+			if got := pipelines(cv.Finish(nil)); !slices.Equal(got, tt.want) {
+				t.Errorf("batches %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPendingBoxes(t *testing.T) {
+	tests := []struct {
+		name  string
+		boxes []core.Rect
+		want  []core.Rect
+	}{
+		{"side by side", []core.Rect{core.R(0, 0, 10, 10), core.R(10, 0, 10, 10), core.R(20, 0, 10, 10)}, []core.Rect{core.R(0, 0, 30, 10)}},
+		{"a little apart", []core.Rect{core.R(0, 0, 10, 10), core.R(12, 0, 10, 10)}, []core.Rect{core.R(0, 0, 22, 10)}},
+		{"apart", []core.Rect{core.R(0, 0, 10, 10), core.R(100, 0, 10, 10)}, []core.Rect{core.R(0, 0, 10, 10), core.R(100, 0, 10, 10)}},
+		{"diagonal", []core.Rect{core.R(0, 0, 10, 10), core.R(10, 10, 10, 10)}, []core.Rect{core.R(0, 0, 10, 10), core.R(10, 10, 10, 10)}},
+	}
+	for _, tt := range tests {
+		var p pending
+		for _, r := range tt.boxes {
+			p.add(r)
+		}
+		if !slices.Equal(p.boxes, tt.want) {
+			t.Errorf("%s: boxes %v, want %v", tt.name, p.boxes, tt.want)
+		}
+	}
+
+	// Past maxBoxes, neighbours merge in pairs, still bounding them all.
+	var p pending
+	var added []core.Rect
+	for i := range maxBoxes + 1 {
+		r := core.R(float32(i%16)*100, float32(i/16)*100, 10, 10)
+		added = append(added, r)
+		p.add(r)
+	}
+	if n := len(p.boxes); n != (maxBoxes+2)/2 {
+		t.Errorf("%d boxes past the most, want %d", n, (maxBoxes+2)/2)
+	}
+	for _, r := range added {
+		if !p.overlaps(r) {
+			t.Errorf("the merged boxes leave out %v", r)
+		}
+	}
+}
+
+// TestCanvasPaintOrder draws overlapping primitives of several pipelines
+// and textures and checks that, at every pixel, the last one covering it
+// in the frame is the last one drawn there.
+func TestCanvasPaintOrder(t *testing.T) {
+	const size = 48
+	textures := []image.Image{image.NewRGBA(image.Rect(0, 0, 1, 1)), image.NewRGBA(image.Rect(0, 0, 1, 1))}
+	rng := rand.New(rand.NewPCG(1, 2))
+	for round := range 50 {
+		cv := New(1)
+		drawn := map[core.Rect]int{} // The rect of each primitive, to its index.
+		var want [size][size]int
+		for i := 1; i <= 40; i++ {
+			r := core.R(float32(rng.IntN(size)), float32(rng.IntN(size)), float32(1+rng.IntN(12)), float32(1+rng.IntN(12)))
+			if _, ok := drawn[r]; ok {
+				continue
+			}
+			drawn[r] = i
+			switch k := rng.IntN(4); k {
+			case 0, 1:
+				cv.DrawImage(textures[k], r)
+			default:
+				cv.DrawRect(r, core.Filled(core.Black))
+			}
+			for y := range size {
+				for x := range size {
+					if r.Contains(core.Pt(float32(x)+0.5, float32(y)+0.5)) {
+						want[y][x] = i
+					}
+				}
+			}
+		}
+
+		f := cv.Finish(nil)
+		var got [size][size]int
+		cover := func(rect [4]float32) {
+			r := core.R(rect[0], rect[1], rect[2], rect[3])
+			for y := range size {
+				for x := range size {
+					if r.Contains(core.Pt(float32(x)+0.5, float32(y)+0.5)) {
+						got[y][x] = drawn[r]
+					}
+				}
+			}
+		}
+		for _, b := range f.Batches {
+			switch b.Pipeline {
+			case PipelineRect:
+				for _, in := range f.Rects[b.First : b.First+b.Count] {
+					cover(in.Rect)
+				}
+			case PipelineImage:
+				for _, in := range f.Quads[b.First : b.First+b.Count] {
+					cover(in.Rect)
+				}
+			}
+		}
+		if got != want {
+			t.Fatalf("round %d: the frame paints in another order than the primitives were drawn", round)
+		}
+		if len(f.Batches) >= len(drawn) {
+			t.Errorf("round %d: %d batches of %d primitives", round, len(f.Batches), len(drawn))
+		}
+	}
+}
+
+func TestCanvasShadows(t *testing.T) {
+	cv := New(2)
+	cv.Translate(10, 0)
+	shadow := core.Shadow{Color: core.Black.WithAlpha(0.5), Offset: core.Pt(0, 2), Blur: 4, Spread: 1}
+	core.DrawShadow(cv, core.R(0, 0, 20, 10), 3, shadow, shadow)
+	cv.DrawShadow(core.R(0, 0, 20, 10), 3, core.Shadow{Color: core.Black, Blur: 0.2})
+	cv.DrawShadow(core.R(0, 0, 20, 10), 3, core.Shadow{Blur: 4})
+	f := cv.Finish(nil)
+	want := []Batch{{Pipeline: PipelineShadow, Count: 2}, {Pipeline: PipelineRect, Count: 1}}
+	if got := pipelines(f); !slices.Equal(got, want) {
+		t.Fatalf("batches %+v, want two shadows and a sharp one as a rect", got)
+	}
+	s := f.Shadows[0]
+	if s.Rect != [4]float32{18, 2, 44, 24} || s.Radius != 8 || s.Blur != 8 || s.Color != [4]float32{0, 0, 0, 0.5} {
+		t.Errorf("shadow %+v, want moved, spread and scaled", s)
+	}
+	if f.Triangles() != 6 || f.DrawCalls() != 2 {
+		t.Errorf("%d triangles in %d draws, want 2 for each instance and a draw for each batch", f.Triangles(), f.DrawCalls())
+	}
+}
+
+func TestCanvasBackdrop(t *testing.T) {
+	red := core.Filled(core.RGB(255, 0, 0))
+	filter := core.Blur(4) // Reaching 12 pixels.
+	tests := []struct {
+		name string
+		at   core.Rect // Of a rect drawn after the backdrop.
+		want []Batch
+	}{
+		{"far", core.R(100, 0, 10, 10), []Batch{{Pipeline: PipelineRect, Count: 2}, {Pipeline: PipelineBackdrop, Count: 1}}},
+		{"read", core.R(45, 0, 10, 10), []Batch{{Pipeline: PipelineRect, Count: 1}, {Pipeline: PipelineBackdrop, Count: 1}, {Pipeline: PipelineRect, Count: 1}}},
+	}
+	for _, tt := range tests {
+		cv := New(1)
+		cv.DrawRect(core.R(0, 0, 10, 10), red)
+		cv.Translate(5, 0)
+		cv.DrawBackdrop(core.R(0, 0, 30, 30), 6, filter)
+		cv.Translate(-5, 0)
+		cv.DrawRect(tt.at, red)
+		f := cv.Finish(nil)
+		if got := pipelines(f); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: batches %+v, want %+v", tt.name, got, tt.want)
+			continue
+		}
+		d := f.Batches[1].Backdrop
+		if tt.name == "far" && (d.Rect != core.R(5, 0, 30, 30) || d.Radius != 6 || d.Filter != filter) {
+			t.Errorf("backdrop %+v", d)
+		}
+	}
+
+	// A backdrop outside the clip or without a filter draws nothing.
+	cv := New(1)
+	cv.DrawBackdrop(core.R(0, 0, 30, 30), 0, core.Filter{})
+	cv.Clip(core.R(100, 100, 10, 10))
+	cv.DrawBackdrop(core.R(0, 0, 30, 30), 0, filter)
+	if f := cv.Finish(nil); len(f.Batches) != 0 {
+		t.Errorf("%d batches of backdrops drawing nothing", len(f.Batches))
+	}
+}
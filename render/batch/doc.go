@@ -0,0 +1,35 @@
+// Package batch turns the drawing of a frame into few instanced draws
+// for rendering backends that draw on the GPU.
+//
+// Issuing a draw for every rectangle and glyph costs more in state
+// changes and submission than drawing them, so a data grid of thousands
+// of cells misses its frames on the CPU. A [Canvas] instead collects the
+// primitives of a frame as instances: rectangles and rounded rectangles
+// of one pipeline, and glyph and image quads grouped by the texture they
+// sample. A primitive joins an earlier batch of the same pipeline and
+// texture when nothing drawn since overlaps it, so that text and
+// backgrounds alternating down a grid still make a batch of each:
+//
+//	cv := batch.New(scale).Glyphs(atlas, shaper)
+//	atlas.BeginFrame()
+//	window.Frame(cv)
+//	f := cv.Finish()
+//	upload(f.Rects, f.Quads)
+//	for _, b := range f.Batches {
+//		draw(b) // one instanced draw of b.Count instances from b.First
+//	}
+//
+// Clips are rectangles carried by each instance, for the shaders to
+// discard outside of, so clipping never splits a batch. Shadows are
+// instances too, drawn with [ShadowShader]. What the instances cannot
+// draw, such as paths, gradients and backdrop filters, is passed through
+// as batches of one draw for the backend to draw as it did before.
+//
+// A Canvas draws no textures of its own, so it takes the fallbacks of
+// package core for the rest: a path clip clips to the bounds of the path,
+// since a clip carried by each instance must stay a rectangle; layers
+// are recordings replayed into the canvas, whose primitives batch like
+// any others; and a layout.Composite fades its child shape by shape with
+// core.Fade, without its blend mode. Backends that need exact path clips
+// or blend modes draw those subtrees with a canvas of their own.
+package batch
@@ -0,0 +1,157 @@
+package batch
+
+// RectShader is a WGSL shader drawing RectInstances, one instance of a
+// four-vertex triangle strip each. The fragment stage evaluates the
+// signed distance to the rounded rectangle, so fill, stroke and corners
+// are antialiased one device pixel wide without tessellation.
+//
+// Bindings: group 0, binding 0 is a uniform buffer holding the size of
+// the target in device pixels. The instance buffer holds the fields of
+// RectInstance in order at locations 0 to 5.
+const RectShader = `
+struct Target {
+	size: vec2<f32>,
+};
+
+@group(0) @binding(0) var<uniform> target: Target;
+
+struct RectIn {
+	@location(0) rect: vec4<f32>,
+	@location(1) clip: vec4<f32>,
+	@location(2) fill: vec4<f32>,
+	@location(3) stroke: vec4<f32>,
+	@location(4) radius: f32,
+	@location(5) stroke_width: f32,
+};
+
+struct RectOut {
+	@builtin(position) position: vec4<f32>,
+	@location(0) local: vec2<f32>,
+	@location(1) half_size: vec2<f32>,
+	@location(2) clip: vec4<f32>,
+	@location(3) fill: vec4<f32>,
+	@location(4) stroke: vec4<f32>,
+	@location(5) radius: f32,
+	@location(6) stroke_width: f32,
+};
+
+@vertex
+fn vs_rect(@builtin(vertex_index) v: u32, in: RectIn) -> RectOut {
+	// The quad grows by half the stroke and a pixel for the edges.
+	let grow = in.stroke_width * 0.5 + 1.0;
+	let corner = vec2<f32>(f32(v & 1u), f32(v >> 1u));
+	let half_size = in.rect.zw * 0.5;
+	let local = (corner * 2.0 - 1.0) * (half_size + grow);
+	let p = in.rect.xy + half_size + local;
+	var out: RectOut;
+	out.position = vec4<f32>(p / target.size * vec2<f32>(2.0, -2.0) + vec2<f32>(-1.0, 1.0), 0.0, 1.0);
+	out.local = local;
+	out.half_size = half_size;
+	out.clip = in.clip;
+	out.fill = in.fill;
+	out.stroke = in.stroke;
+	out.radius = in.radius;
+	out.stroke_width = in.stroke_width;
+	return out;
+}
+
+fn rounded_box(p: vec2<f32>, half_size: vec2<f32>, r: f32) -> f32 {
+	let q = abs(p) - half_size + r;
+	return length(max(q, vec2<f32>(0.0))) + min(max(q.x, q.y), 0.0) - r;
+}
+
+@fragment
+fn fs_rect(in: RectOut) -> @location(0) vec4<f32> {
+	let p = in.position.xy;
+	if (p.x < in.clip.x || p.y < in.clip.y || p.x >= in.clip.z || p.y >= in.clip.w) {
+		discard;
+	}
+	let d = rounded_box(in.local, in.half_size, in.radius);
+	let fill = clamp(0.5 - d, 0.0, 1.0);
+	var color = in.fill * fill;
+	if (in.stroke_width > 0.0) {
+		let s = clamp(in.stroke_width * 0.5 + 0.5 - abs(d), 0.0, 1.0);
+		color = in.stroke * s + color * (1.0 - in.stroke.a * s);
+	}
+	return color;
+}
+`
+
+// ShadowShader is a WGSL shader drawing ShadowInstances, one instance of
+// a four-vertex triangle strip each. The fragment stage takes the signed
+// distance to the rounded rectangle and covers each pixel as a straight
+// edge blurred by the gaussian would, which matches the blur along the
+// sides and comes close to it around the corners.
+//
+// Bindings: group 0, binding 0 is a uniform buffer holding the size of
+// the target in device pixels. The instance buffer holds the fields of
+// ShadowInstance in order at locations 0 to 4.
+const ShadowShader = `
+struct Target {
+	size: vec2<f32>,
+};
+
+@group(0) @binding(0) var<uniform> target: Target;
+
+struct ShadowIn {
+	@location(0) rect: vec4<f32>,
+	@location(1) clip: vec4<f32>,
+	@location(2) color: vec4<f32>,
+	@location(3) radius: f32,
+	@location(4) blur: f32,
+};
+
+struct ShadowOut {
+	@builtin(position) position: vec4<f32>,
+	@location(0) local: vec2<f32>,
+	@location(1) half_size: vec2<f32>,
+	@location(2) clip: vec4<f32>,
+	@location(3) color: vec4<f32>,
+	@location(4) radius: f32,
+	@location(5) blur: f32,
+};
+
+@vertex
+fn vs_shadow(@builtin(vertex_index) v: u32, in: ShadowIn) -> ShadowOut {
+	// The quad grows by three deviations, past which the shadow is too
+	// faint to matter, and a pixel.
+	let grow = in.blur * 3.0 + 1.0;
+	let corner = vec2<f32>(f32(v & 1u), f32(v >> 1u));
+	let half_size = in.rect.zw * 0.5;
+	let local = (corner * 2.0 - 1.0) * (half_size + grow);
+	let p = in.rect.xy + half_size + local;
+	var out: ShadowOut;
+	out.position = vec4<f32>(p / target.size * vec2<f32>(2.0, -2.0) + vec2<f32>(-1.0, 1.0), 0.0, 1.0);
+	out.local = local;
+	out.half_size = half_size;
+	out.clip = in.clip;
+	out.color = in.color;
+	out.radius = in.radius;
+	out.blur = in.blur;
+	return out;
+}
+
+fn rounded_box(p: vec2<f32>, half_size: vec2<f32>, r: f32) -> f32 {
+	let q = abs(p) - half_size + r;
+	return length(max(q, vec2<f32>(0.0))) + min(max(q.x, q.y), 0.0) - r;
+}
+
+// erf approximates the error function within 5e-4, after Abramowitz and
+// Stegun 7.1.27.
+fn erf(x: f32) -> f32 {
+	let a = abs(x);
+	let t = 1.0 + (0.278393 + (0.230389 + (0.000972 + 0.078108 * a) * a) * a) * a;
+	let t2 = t * t;
+	return sign(x) * (1.0 - 1.0 / (t2 * t2));
+}
+
+@fragment
+fn fs_shadow(in: ShadowOut) -> @location(0) vec4<f32> {
+	let p = in.position.xy;
+	if (p.x < in.clip.x || p.y < in.clip.y || p.x >= in.clip.z || p.y >= in.clip.w) {
+		discard;
+	}
+	let d = rounded_box(in.local, in.half_size, in.radius);
+	return in.color * (0.5 - 0.5 * erf(d / (in.blur * 1.41421356)));
+}
+`
@@ -0,0 +1,286 @@
+package raster
+
+import (
+	"image"
+	"math"
+	"slices"
+
+	"github.com/gogpu/ui/core"
+)
+
+// vec is a point in device pixels.
+type vec struct{ x, y float64 }
+
+func (a vec) add(b vec) vec       { return vec{a.x + b.x, a.y + b.y} }
+func (a vec) sub(b vec) vec       { return vec{a.x - b.x, a.y - b.y} }
+func (a vec) scale(s float64) vec { return vec{a.x * s, a.y * s} }
+func (a vec) len() float64        { return math.Hypot(a.x, a.y) }
+
+// polygon is a closed contour.
+type polygon []vec
+
+// area returns the signed area of p, positive when it runs clockwise on
+// the screen, with y pointing down.
+func (p polygon) area() float64 {
+	var a float64
+	for i := range p {
+		q, r := p[i], p[(i+1)%len(p)]
+		a += q.x*r.y - r.x*q.y
+	}
+	return a / 2
+}
+
+type edge struct {
+	x0, y0, x1, y1 float64
+	dir            int
+}
+
+// fill returns the coverage of polygons under rule for each pixel of
+// clip, as a rectangle of it and a coverage per pixel of that rectangle,
+// row by row, sampling samples scanlines in each row of pixels. Across a
+// scanline, coverage is exact.
+func fill(polys []polygon, rule core.FillRule, clip image.Rectangle, samples int) (image.Rectangle, []float32) {
+	var edges []edge
+	box := image.Rectangle{}
+	minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for _, p := range polys {
+		for i := range p {
+			a, b := p[i], p[(i+1)%len(p)]
+			minX, maxX = min(minX, a.x), max(maxX, a.x)
+			minY, maxY = min(minY, a.y), max(maxY, a.y)
+			if a.y == b.y {
+				continue
+			}
+			e := edge{a.x, a.y, b.x, b.y, 1}
+			if a.y > b.y {
+				e = edge{b.x, b.y, a.x, a.y, -1}
+			}
+			edges = append(edges, e)
+		}
+	}
+	if len(edges) == 0 {
+		return box, nil
+	}
+	box = image.Rect(int(math.Floor(minX)), int(math.Floor(minY)), int(math.Ceil(maxX))+1, int(math.Ceil(maxY))).Intersect(clip)
+	if box.Empty() {
+		return box, nil
+	}
+	slices.SortFunc(edges, func(a, b edge) int { return cmpFloat(a.y0, b.y0) })
+	w := box.Dx()
+	cov := make([]float32, w*box.Dy())
+	type crossing struct {
+		x   float64
+		dir int
+	}
+	var xs []crossing
+	for y := box.Min.Y; y < box.Max.Y; y++ {
+		row := cov[(y-box.Min.Y)*w : (y-box.Min.Y+1)*w]
+		for s := range samples {
+			sy := float64(y) + (float64(s)+0.5)/float64(samples)
+			xs = xs[:0]
+			for _, e := range edges {
+				if e.y0 > sy {
+					break
+				}
+				if sy < e.y1 {
+					t := (sy - e.y0) / (e.y1 - e.y0)
+					xs = append(xs, crossing{e.x0 + (e.x1-e.x0)*t, e.dir})
+				}
+			}
+			slices.SortFunc(xs, func(a, b crossing) int { return cmpFloat(a.x, b.x) })
+			winding := 0
+			for i, c := range xs {
+				winding += c.dir
+				inside := winding != 0
+				if rule == core.FillEvenOdd {
+					inside = winding%2 != 0
+				}
+				if inside && i+1 < len(xs) {
+					span(row, c.x-float64(box.Min.X), xs[i+1].x-float64(box.Min.X), 1/float64(samples))
+				}
+			}
+		}
+	}
+	return box, cov
+}
+
+// span adds w times the coverage of each pixel of row by the span from a
+// to b.
+func span(row []float32, a, b, w float64) {
+	a, b = max(a, 0), min(b, float64(len(row)))
+	if b <= a {
+		return
+	}
+	ia, ib := int(a), int(b)
+	if ia == ib {
+		row[ia] += float32((b - a) * w)
+		return
+	}
+	row[ia] += float32((float64(ia+1) - a) * w)
+	for i := ia + 1; i < ib; i++ {
+		row[i] += float32(w)
+	}
+	if ib < len(row) {
+		row[ib] += float32((b - float64(ib)) * w)
+	}
+}
+
+func cmpFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// contour is a flattened subpath, closed if the path closed it.
+type contour struct {
+	pts    []vec
+	closed bool
+}
+
+// flatten returns the contours of p, mapped to device pixels by tf, with
+// curves approximated by lines no more than about a quarter of a pixel
+// off.
+func flatten(p *core.Path, tf func(core.Point) vec) []contour {
+	var out []contour
+	var cur contour
+	var last vec
+	end := func(closed bool) {
+		if len(cur.pts) > 1 {
+			cur.closed = closed
+			out = append(out, cur)
+		}
+		cur = contour{}
+	}
+	for _, s := range p.Segments {
+		switch s.Verb {
+		case core.MoveTo:
+			end(false)
+			last = tf(s.Points[0])
+			cur.pts = append(cur.pts, last)
+		case core.LineTo:
+			last = tf(s.Points[0])
+			cur.pts = append(cur.pts, last)
+		case core.QuadTo:
+			c, to := tf(s.Points[0]), tf(s.Points[1])
+			n := steps(last.sub(c).len() + c.sub(to).len())
+			for i := 1; i <= n; i++ {
+				t := float64(i) / float64(n)
+				u := 1 - t
+				cur.pts = append(cur.pts, last.scale(u*u).add(c.scale(2*u*t)).add(to.scale(t*t)))
+			}
+			last = to
+		case core.CubicTo:
+			c1, c2, to := tf(s.Points[0]), tf(s.Points[1]), tf(s.Points[2])
+			n := steps(last.sub(c1).len() + c1.sub(c2).len() + c2.sub(to).len())
+			for i := 1; i <= n; i++ {
+				t := float64(i) / float64(n)
+				u := 1 - t
+				cur.pts = append(cur.pts, last.scale(u*u*u).add(c1.scale(3*u*u*t)).add(c2.scale(3*u*t*t)).add(to.scale(t*t*t)))
+			}
+			last = to
+		case core.Close:
+			if len(cur.pts) > 0 {
+				last = cur.pts[0]
+			}
+			end(true)
+			cur.pts = append(cur.pts, last)
+		}
+	}
+	end(false)
+	return out
+}
+
+// steps returns how many lines to flatten a curve of about length px
+// into.
+func steps(length float64) int {
+	return min(max(int(math.Ceil(math.Sqrt(length*2))), 2), 100)
+}
+
+// stroke returns polygons covering the outline of the contours at width:
+// a quadrilateral for each line, joins and caps, all running the same way
+// so that they fill as one shape by the non-zero rule.
+func stroke(cs []contour, width float64, cap core.LineCap, join core.LineJoin) []polygon {
+	var out []polygon
+	r := width / 2
+	add := func(p polygon) {
+		if p.area() == 0 {
+			return
+		}
+		if p.area() < 0 {
+			slices.Reverse(p)
+		}
+		out = append(out, p)
+	}
+	for _, c := range cs {
+		pts := c.pts
+		if c.closed && len(pts) > 1 && pts[0] == pts[len(pts)-1] {
+			pts = pts[:len(pts)-1]
+		}
+		n := len(pts) - 1
+		if c.closed {
+			n = len(pts)
+		}
+		for i := range n {
+			a, b := pts[i], pts[(i+1)%len(pts)]
+			d := b.sub(a)
+			l := d.len()
+			if l == 0 {
+				continue
+			}
+			u := d.scale(1 / l)
+			if !c.closed && cap == core.CapSquare {
+				if i == 0 {
+					a = a.sub(u.scale(r))
+				}
+				if i == n-1 {
+					b = b.add(u.scale(r))
+				}
+			}
+			nv := vec{-u.y * r, u.x * r}
+			add(polygon{a.add(nv), b.add(nv), b.sub(nv), a.sub(nv)})
+		}
+		for i, p := range pts {
+			ends := !c.closed && (i == 0 || i == len(pts)-1)
+			switch {
+			case ends && cap == core.CapRound, !ends && join == core.JoinRound:
+				add(circle(p, r))
+			case !ends:
+				// Miter and bevel joins are both drawn bevelled.
+				prev, next := pts[(i+len(pts)-1)%len(pts)], pts[(i+1)%len(pts)]
+				for _, t := range bevel(prev, p, next, r) {
+					add(t)
+				}
+			}
+		}
+	}
+	return out
+}
+
+// circle returns a polygon approximating the circle around c of radius r.
+func circle(c vec, r float64) polygon {
+	n := min(max(int(math.Ceil(r*2)), 8), 64)
+	p := make(polygon, n)
+	for i := range p {
+		a := 2 * math.Pi * float64(i) / float64(n)
+		p[i] = vec{c.x + r*math.Cos(a), c.y + r*math.Sin(a)}
+	}
+	return p
+}
+
+// bevel returns the triangles filling the gap on the outside of the
+// corner at p between the lines from prev and to next, one on each side,
+// as the one on the inside is covered by the lines anyway.
+func bevel(prev, p, next vec, r float64) []polygon {
+	d0, d1 := p.sub(prev), next.sub(p)
+	l0, l1 := d0.len(), d1.len()
+	if l0 == 0 || l1 == 0 {
+		return nil
+	}
+	n0 := vec{-d0.y / l0 * r, d0.x / l0 * r}
+	n1 := vec{-d1.y / l1 * r, d1.x / l1 * r}
+	return []polygon{{p, p.add(n0), p.add(n1)}, {p, p.sub(n0), p.sub(n1)}}
+}
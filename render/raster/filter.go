@@ -0,0 +1,166 @@
+package raster
+
+import (
+	"image"
+	"math"
+
+	"github.com/gogpu/ui/core"
+)
+
+// DrawBackdrop implements core.BackdropCanvas, blurring with three box
+// blurs, which come within a few percent of a gaussian.
+func (c *Canvas) DrawBackdrop(r core.Rect, radius float32, f core.Filter) {
+	if f.IsZero() {
+		return
+	}
+	cs := flatten(core.NewPath().AddRoundedRect(r, radius), c.device)
+	polys := make([]polygon, len(cs))
+	for i, ct := range cs {
+		polys[i] = ct.pts
+	}
+	box, cov := c.fill(polys, core.FillNonZero, c.clip)
+	if cov == nil {
+		return
+	}
+	sigma := float64(f.Blur * c.scale)
+	reach := int(math.Ceil(3 * sigma))
+	src := image.Rect(box.Min.X-reach, box.Min.Y-reach, box.Max.X+reach, box.Max.Y+reach).Intersect(c.img.Bounds())
+	w, h := src.Dx(), src.Dy()
+	buf := make([]float32, 4*w*h)
+	for y := range h {
+		i := c.img.PixOffset(src.Min.X, src.Min.Y+y)
+		for x := range 4 * w {
+			buf[4*w*y+x] = float32(c.img.Pix[i+x])
+		}
+	}
+	if sigma > 0 {
+		blur(buf, 4, w, h, sigma)
+	}
+	bw := box.Dx()
+	for y := box.Min.Y; y < box.Max.Y; y++ {
+		for x := box.Min.X; x < box.Max.X; x++ {
+			k := min(cov[(y-box.Min.Y)*bw+x-box.Min.X], 1) * c.maskAt(x, y)
+			if k <= 0 {
+				continue
+			}
+			s := buf[4*(w*(y-src.Min.Y)+x-src.Min.X):][:4]
+			col := filterPremul(f, s[0]/255, s[1]/255, s[2]/255, s[3]/255)
+			p := c.img.Pix[c.img.PixOffset(x, y):][:4]
+			for n := range 4 {
+				p[n] = uint8(float32(p[n])*(1-k) + col[n]*255*k + 0.5)
+			}
+		}
+	}
+}
+
+// DrawShadow implements core.ShadowCanvas, blurring the coverage of the
+// shape as DrawBackdrop blurs.
+func (c *Canvas) DrawShadow(r core.Rect, radius float32, s core.Shadow) {
+	sigma := float64(s.Blur * c.scale)
+	reach := int(math.Ceil(3 * sigma))
+	// The shape is covered beyond the clip as far as the blur reads.
+	area := image.Rect(c.clip.Min.X-reach, c.clip.Min.Y-reach, c.clip.Max.X+reach, c.clip.Max.Y+reach)
+	shape := r.Translate(s.Offset).Inset(core.UniformInsets(-s.Spread))
+	cs := flatten(core.NewPath().AddRoundedRect(shape, max(radius+s.Spread, 0)), c.device)
+	polys := make([]polygon, len(cs))
+	for i, ct := range cs {
+		polys[i] = ct.pts
+	}
+	box, cov := c.fill(polys, core.FillNonZero, area)
+	if cov == nil {
+		return
+	}
+	// Grow the coverage by the reach of the blur, so it spreads out.
+	grown := image.Rect(box.Min.X-reach, box.Min.Y-reach, box.Max.X+reach, box.Max.Y+reach)
+	w, h := grown.Dx(), grown.Dy()
+	buf := make([]float32, w*h)
+	for y := range box.Dy() {
+		copy(buf[(y+reach)*w+reach:], cov[y*box.Dx():(y+1)*box.Dx()])
+	}
+	if sigma > 0 {
+		blur(buf, 1, w, h, sigma)
+	}
+	dst := grown.Intersect(c.clip)
+	for y := dst.Min.Y; y < dst.Max.Y; y++ {
+		for x := dst.Min.X; x < dst.Max.X; x++ {
+			if k := buf[(y-grown.Min.Y)*w+x-grown.Min.X]; k > 0 {
+				r, g, b, a := premultiply(s.Color, min(k, 1))
+				c.blend(x, y, r, g, b, a)
+			}
+		}
+	}
+}
+
+// filterPremul applies the saturation and brightness of f to the
+// premultiplied color r, g, b, a. Both scale the color linearly, and so
+// apply to it as premultiplied, clamped to its alpha.
+func filterPremul(f core.Filter, r, g, b, a float32) [4]float32 {
+	c := f.Apply(core.Color{R: r, G: g, B: b, A: 1})
+	return [4]float32{min(c.R, a), min(c.G, a), min(c.B, a), a}
+}
+
+// boxSizes returns the widths of three box blurs, each odd, that blur
+// like a gaussian of deviation sigma together.
+func boxSizes(sigma float64) [3]int {
+	const n = 3
+	wl := int(math.Floor(math.Sqrt(12*sigma*sigma/n + 1)))
+	if wl%2 == 0 {
+		wl--
+	}
+	m := int(math.Round((12*sigma*sigma - n*float64(wl*wl) - 4*n*float64(wl) - 3*n) / float64(-4*wl-4)))
+	var out [3]int
+	for i := range out {
+		out[i] = wl
+		if i >= m {
+			out[i] = wl + 2
+		}
+	}
+	return out
+}
+
+// blur blurs the w by h pixels of comps components in buf like a
+// gaussian of deviation sigma, repeating the pixels at the edges beyond
+// them.
+func blur(buf []float32, comps, w, h int, sigma float64) {
+	tmp := make([]float32, comps*max(w, h))
+	for _, d := range boxSizes(sigma) {
+		for y := range h {
+			boxBlur(buf[comps*w*y:], comps, comps, w, d/2, tmp)
+		}
+		for x := range w {
+			boxBlur(buf[comps*x:], comps, comps*w, h, d/2, tmp)
+		}
+	}
+}
+
+// boxBlur blurs the n pixels of comps components starting at buf, stride
+// floats apart, by the mean over r pixels on each side, repeating the
+// pixels at the ends beyond them. tmp holds at least comps*n floats.
+func boxBlur(buf []float32, comps, stride, n, r int, tmp []float32) {
+	if r <= 0 || n == 0 {
+		return
+	}
+	at := func(i int) []float32 {
+		i = min(max(i, 0), n-1)
+		return buf[i*stride : i*stride+comps]
+	}
+	var sum [4]float32
+	for i := -r; i <= r; i++ {
+		for k, v := range at(i) {
+			sum[k] += v
+		}
+	}
+	norm := 1 / float32(2*r+1)
+	for i := range n {
+		for k := range comps {
+			tmp[comps*i+k] = sum[k] * norm
+		}
+		out, in := at(i-r), at(i+r+1)
+		for k := range comps {
+			sum[k] += in[k] - out[k]
+		}
+	}
+	for i := range n {
+		copy(buf[i*stride:i*stride+comps], tmp[comps*i:comps*i+comps])
+	}
+}
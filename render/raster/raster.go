@@ -0,0 +1,503 @@
+// Package raster draws widgets into images on the CPU, for captures of
+// windows and widgets where no GPU backend is at hand, such as golden
+// tests and generated documentation:
+//
+//	cv := raster.NewImage(size, 2)
+//	cv.Clear(core.White)
+//	widget.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+//	png.Encode(f, cv.Image())
+//
+// Shapes are antialiased. Text is drawn with the glyphs of fonts set with
+// Canvas.Fonts, or else as a box for each character, the width the text
+// measurer gives it, like the Ahem font of browser tests, so that images
+// do not depend on the fonts installed and show where text is laid out,
+// not what it reads. Miter joins are drawn bevelled.
+package raster
+
+import (
+	"image"
+	"image/draw"
+	"math"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/font"
+)
+
+// Canvas is a core.Canvas drawing into an image. It implements
+// core.LayerCanvas, with layers whose pixels can be read back,
+// core.CompositeCanvas, core.PathClipCanvas, core.BackdropCanvas,
+// core.ShadowCanvas and core.AntialiasCanvas.
+type Canvas struct {
+	img      *image.RGBA
+	scale    float32
+	measurer core.TextMeasurer
+	fonts    *font.Manager
+	aa       core.Antialiasing
+
+	tx, ty float32
+	clip   image.Rectangle
+	mask   []float32 // The coverage of each pixel by clip paths, or nil.
+	stack  []state
+}
+
+type state struct {
+	tx, ty float32
+	clip   image.Rectangle
+	mask   []float32
+}
+
+// New returns a canvas drawing into img, scale device pixels to each
+// logical pixel.
+func New(img *image.RGBA, scale float32) *Canvas {
+	if scale <= 0 {
+		scale = 1
+	}
+	return &Canvas{img: img, scale: scale, measurer: core.ApproxTextMeasurer{}, clip: img.Bounds()}
+}
+
+// NewImage returns a canvas drawing into a new transparent image of size
+// logical pixels, scale device pixels to each.
+func NewImage(size core.Size, scale float32) *Canvas {
+	if scale <= 0 {
+		scale = 1
+	}
+	w := int(math.Ceil(float64(size.Width * scale)))
+	h := int(math.Ceil(float64(size.Height * scale)))
+	return New(image.NewRGBA(image.Rect(0, 0, max(w, 0), max(h, 0))), scale)
+}
+
+// TextMeasurer sets the measurer giving the width of each character of
+// text drawn as boxes, which should be the one the text was laid out
+// with. The default is core.ApproxTextMeasurer, the measurer of a
+// core.Context without one installed.
+func (c *Canvas) TextMeasurer(m core.TextMeasurer) *Canvas {
+	c.measurer = m
+	return c
+}
+
+// Image returns the image drawn into.
+func (c *Canvas) Image() *image.RGBA {
+	return c.img
+}
+
+// Clear fills the whole image with col, ignoring the clip.
+func (c *Canvas) Clear(col core.Color) {
+	r, g, b, a := premultiply(col, 1)
+	for i := 0; i < len(c.img.Pix); i += 4 {
+		c.img.Pix[i], c.img.Pix[i+1], c.img.Pix[i+2], c.img.Pix[i+3] = r, g, b, a
+	}
+}
+
+// device maps p to device pixels.
+func (c *Canvas) device(p core.Point) vec {
+	return vec{float64((p.X + c.tx) * c.scale), float64((p.Y + c.ty) * c.scale)}
+}
+
+// SetAntialiasing implements core.AntialiasCanvas. Multisampling samples
+// as many scanlines in each row of pixels, with exact coverage along
+// them, and analytic antialiasing sixteen.
+func (c *Canvas) SetAntialiasing(a core.Antialiasing) {
+	c.aa = a
+}
+
+// analyticSamples is the number of scanlines sampled in each row of
+// pixels by analytic antialiasing. Edges close to horizontal step through
+// as many levels of coverage down each pixel.
+const analyticSamples = 16
+
+// fill returns the coverage of polys under rule within clip, as fill
+// does, sampled for the antialiasing of the canvas.
+func (c *Canvas) fill(polys []polygon, rule core.FillRule, clip image.Rectangle) (image.Rectangle, []float32) {
+	switch c.aa.Mode {
+	case core.AntialiasMSAA:
+		return fill(polys, rule, clip, c.aa.SampleCount())
+	case core.AntialiasNone:
+		// Pixels are in where their center is.
+		box, cov := fill(polys, rule, clip, 1)
+		for i, k := range cov {
+			cov[i] = 0
+			if k >= 0.5 {
+				cov[i] = 1
+			}
+		}
+		return box, cov
+	}
+	return fill(polys, rule, clip, analyticSamples)
+}
+
+// DrawRect implements core.Canvas, with its edges on device pixels.
+func (c *Canvas) DrawRect(r core.Rect, st core.RectStyle) {
+	if st.StrokeWidth > 0 {
+		st.StrokeWidth = c.snapWidth(st.StrokeWidth)
+	}
+	if hasFill(st) {
+		c.drawShape(core.NewPath().AddRect(c.snap(r, 0)), core.RectStyle{Fill: st.Fill, FillGradient: st.FillGradient})
+	}
+	if hasStroke(st) {
+		c.drawShape(core.NewPath().AddRect(c.snap(r, st.StrokeWidth)), core.RectStyle{Stroke: st.Stroke, StrokeGradient: st.StrokeGradient, StrokeWidth: st.StrokeWidth})
+	}
+}
+
+// DrawRoundedRect implements core.Canvas, with its straight edges on
+// device pixels.
+func (c *Canvas) DrawRoundedRect(r core.Rect, radius float32, st core.RectStyle) {
+	if st.StrokeWidth > 0 {
+		st.StrokeWidth = c.snapWidth(st.StrokeWidth)
+	}
+	if hasFill(st) {
+		c.drawShape(core.NewPath().AddRoundedRect(c.snap(r, 0), radius), core.RectStyle{Fill: st.Fill, FillGradient: st.FillGradient})
+	}
+	if hasStroke(st) {
+		c.drawShape(core.NewPath().AddRoundedRect(c.snap(r, st.StrokeWidth), radius), core.RectStyle{Stroke: st.Stroke, StrokeGradient: st.StrokeGradient, StrokeWidth: st.StrokeWidth})
+	}
+}
+
+func hasFill(st core.RectStyle) bool {
+	return !st.Fill.IsTransparent() || st.FillGradient != nil
+}
+
+func hasStroke(st core.RectStyle) bool {
+	return (!st.Stroke.IsTransparent() || st.StrokeGradient != nil) && st.StrokeWidth > 0
+}
+
+// snapWidth returns the stroke width w rounded to whole device pixels, at
+// least one.
+func (c *Canvas) snapWidth(w float32) float32 {
+	return max(float32(math.Round(float64(w*c.scale))), 1) / c.scale
+}
+
+// snap returns r, in the coordinates of the transform, snapped to device
+// pixels by core.SnapRect. A stroke of width w is centered on its edges,
+// which for an odd number of device pixels are put on pixel centers
+// instead, so that the stroke covers whole pixels.
+func (c *Canvas) snap(r core.Rect, w float32) core.Rect {
+	off := core.Pt(c.tx, c.ty)
+	if d := math.Round(float64(w * c.scale)); int(d)%2 == 1 {
+		half := 0.5 / c.scale
+		off = off.Sub(core.Pt(half, half))
+	}
+	return core.SnapRect(r.Translate(off), c.scale).Translate(core.Pt(-off.X, -off.Y))
+}
+
+func (c *Canvas) drawShape(p *core.Path, st core.RectStyle) {
+	c.DrawPath(p, core.PathStyle{
+		Fill:           st.Fill,
+		Stroke:         st.Stroke,
+		StrokeWidth:    st.StrokeWidth,
+		LineJoin:       core.JoinMiter,
+		FillGradient:   st.FillGradient,
+		StrokeGradient: st.StrokeGradient,
+	})
+}
+
+// DrawPath implements core.Canvas.
+func (c *Canvas) DrawPath(p *core.Path, st core.PathStyle) {
+	if p.IsEmpty() {
+		return
+	}
+	cs := flatten(p, c.device)
+	box := p.Bounds()
+	if !st.Fill.IsTransparent() || st.FillGradient != nil {
+		polys := make([]polygon, len(cs))
+		for i, ct := range cs {
+			polys[i] = ct.pts
+		}
+		c.cover(polys, st.FillRule, brush{st.Fill, st.FillGradient, box})
+	}
+	if (!st.Stroke.IsTransparent() || st.StrokeGradient != nil) && st.StrokeWidth > 0 {
+		w, col := float64(st.StrokeWidth*c.scale), st.Stroke
+		if w < 1 {
+			// A line thinner than a pixel covers it partly however it is
+			// placed, and is drawn a pixel wide and as much fainter, so
+			// that it does not break up.
+			if c.aa.Mode != core.AntialiasNone {
+				col = col.WithAlpha(col.A * float32(w))
+			}
+			w = 1
+		}
+		c.cover(stroke(cs, w, st.LineCap, st.LineJoin), core.FillNonZero, brush{col, st.StrokeGradient, box})
+	}
+}
+
+// DrawText implements core.Canvas, with the glyphs of the fonts set with
+// Fonts, or else a box for each character.
+func (c *Canvas) DrawText(text string, pos core.Point, st core.TextStyle) {
+	if st.Color.IsTransparent() {
+		return
+	}
+	// The baseline is put on a device pixel, as GPU backends put it to
+	// keep glyphs sharp.
+	base := core.SnapToPixel(pos.Y+c.ty+st.Ascent(), c.scale) - c.ty
+	if c.fonts != nil {
+		c.drawGlyphs(text, pos.X, base, st)
+		return
+	}
+	c.drawBoxes(text, pos.X, base, st)
+}
+
+// drawBoxes draws a box for each character of text, as wide as the
+// measurer of the canvas gives it, standing on the baseline at base.
+func (c *Canvas) drawBoxes(text string, x, base float32, st core.TextStyle) {
+	top := base - 0.7*st.Size
+	var x0 float32
+	for i, r := range text {
+		next := i + utf8.RuneLen(r)
+		x1 := c.measurer.MeasureText(text[:next], st).Width
+		if w := x1 - x0; w > 0 && !unicode.IsSpace(r) {
+			c.DrawRect(core.R(x+x0+w*0.1, top, w*0.8, 0.7*st.Size), core.Filled(st.Color))
+		}
+		x0 = x1
+	}
+}
+
+// DrawImage implements core.Canvas, sampling img at the nearest texel.
+func (c *Canvas) DrawImage(img image.Image, r core.Rect) {
+	a, b := c.device(r.Origin()), c.device(core.Pt(r.Right(), r.Bottom()))
+	dst := image.Rect(int(math.Round(a.x)), int(math.Round(a.y)), int(math.Round(b.x)), int(math.Round(b.y)))
+	area := dst.Intersect(c.clip)
+	sb := img.Bounds()
+	if area.Empty() || sb.Empty() {
+		return
+	}
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		sy := sb.Min.Y + (y-dst.Min.Y)*sb.Dy()/dst.Dy()
+		for x := area.Min.X; x < area.Max.X; x++ {
+			sx := sb.Min.X + (x-dst.Min.X)*sb.Dx()/dst.Dx()
+			r, g, b, a := img.At(sx, sy).RGBA()
+			c.blend(x, y, uint8(r>>8), uint8(g>>8), uint8(b>>8), uint8(a>>8))
+		}
+	}
+}
+
+// Save implements core.Canvas.
+func (c *Canvas) Save() {
+	c.stack = append(c.stack, state{c.tx, c.ty, c.clip, c.mask})
+}
+
+// Restore implements core.Canvas.
+func (c *Canvas) Restore() {
+	if n := len(c.stack); n > 0 {
+		s := c.stack[n-1]
+		c.tx, c.ty, c.clip, c.mask = s.tx, s.ty, s.clip, s.mask
+		c.stack = c.stack[:n-1]
+	}
+}
+
+// Translate implements core.Canvas.
+func (c *Canvas) Translate(x, y float32) {
+	c.tx += x
+	c.ty += y
+}
+
+// Clip implements core.Canvas, to whole device pixels.
+func (c *Canvas) Clip(r core.Rect) {
+	a, b := c.device(r.Origin()), c.device(core.Pt(r.Right(), r.Bottom()))
+	c.clip = c.clip.Intersect(image.Rect(int(math.Round(a.x)), int(math.Round(a.y)), int(math.Round(b.x)), int(math.Round(b.y))))
+}
+
+// ClipPath implements core.PathClipCanvas, with antialiased edges.
+func (c *Canvas) ClipPath(p *core.Path, rule core.FillRule) {
+	cs := flatten(p, c.device)
+	polys := make([]polygon, len(cs))
+	for i, ct := range cs {
+		polys[i] = ct.pts
+	}
+	box, cov := c.fill(polys, rule, c.clip)
+	c.clip = c.clip.Intersect(box)
+	if cov == nil {
+		return
+	}
+	// The mask is replaced rather than changed, as the states saved
+	// before hold on to the one they had.
+	b := c.img.Bounds()
+	mask := make([]float32, b.Dx()*b.Dy())
+	for y := box.Min.Y; y < box.Max.Y; y++ {
+		for x := box.Min.X; x < box.Max.X; x++ {
+			mask[(y-b.Min.Y)*b.Dx()+x-b.Min.X] = min(cov[(y-box.Min.Y)*box.Dx()+x-box.Min.X], 1) * c.maskAt(x, y)
+		}
+	}
+	c.mask = mask
+}
+
+// maskAt returns the coverage of the pixel at x, y by the clip paths.
+func (c *Canvas) maskAt(x, y int) float32 {
+	if c.mask == nil {
+		return 1
+	}
+	b := c.img.Bounds()
+	return c.mask[(y-b.Min.Y)*b.Dx()+x-b.Min.X]
+}
+
+// NewLayer implements core.LayerCanvas.
+func (c *Canvas) NewLayer(size core.Size) core.Layer {
+	l := &layer{size: size, canvas: NewImage(size, c.scale).TextMeasurer(c.measurer)}
+	l.canvas.aa, l.canvas.fonts = c.aa, c.fonts
+	return l
+}
+
+// DrawLayer implements core.LayerCanvas.
+func (c *Canvas) DrawLayer(l core.Layer, pos core.Point) {
+	ly, ok := l.(*layer)
+	if !ok {
+		return
+	}
+	a := c.device(pos)
+	src := ly.canvas.img
+	dst := src.Bounds().Add(image.Pt(int(math.Round(a.x)), int(math.Round(a.y))))
+	area := dst.Intersect(c.clip)
+	if c.mask == nil {
+		draw.Draw(c.img, area, src, area.Min.Sub(dst.Min), draw.Over)
+		return
+	}
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		for x := area.Min.X; x < area.Max.X; x++ {
+			p := src.Pix[src.PixOffset(x-dst.Min.X, y-dst.Min.Y):][:4]
+			c.blend(x, y, p[0], p[1], p[2], p[3])
+		}
+	}
+}
+
+// DrawLayerComposite implements core.CompositeCanvas.
+func (c *Canvas) DrawLayerComposite(l core.Layer, pos core.Point, opacity float32, mode core.BlendMode) {
+	ly, ok := l.(*layer)
+	if !ok {
+		return
+	}
+	if opacity >= 1 && mode == core.BlendNormal {
+		c.DrawLayer(l, pos)
+		return
+	}
+	k := core.Clamp(opacity, 0, 1)
+	if k == 0 {
+		return
+	}
+	a := c.device(pos)
+	src := ly.canvas.img
+	dst := src.Bounds().Add(image.Pt(int(math.Round(a.x)), int(math.Round(a.y))))
+	area := dst.Intersect(c.clip)
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		for x := area.Min.X; x < area.Max.X; x++ {
+			p := src.Pix[src.PixOffset(x-dst.Min.X, y-dst.Min.Y):][:4]
+			if p[3] == 0 {
+				continue
+			}
+			if mode == core.BlendNormal {
+				scale := func(v uint8) uint8 { return uint8(float32(v)*k + 0.5) }
+				c.blend(x, y, scale(p[0]), scale(p[1]), scale(p[2]), scale(p[3]))
+				continue
+			}
+			c.blendMode(x, y, p, k*c.maskAt(x, y), mode)
+		}
+	}
+}
+
+// blendMode composites the premultiplied source pixel s at opacity k over
+// the pixel at x, y by mode: in premultiplied terms, the source and the
+// backdrop each where the other is not, and the blended color where both
+// are.
+func (c *Canvas) blendMode(x, y int, s []uint8, k float32, mode core.BlendMode) {
+	if k <= 0 {
+		return
+	}
+	d := c.img.Pix[c.img.PixOffset(x, y):][:4]
+	sa, da := float32(s[3])/255*k, float32(d[3])/255
+	out := sa + da - sa*da
+	for n := range 3 {
+		sc, dc := float32(s[n])/255*k, float32(d[n])/255
+		v := sc*(1-da) + dc*(1-sa)
+		if sa > 0 && da > 0 {
+			v += sa * da * mode.Blend(min(sc/sa, 1), min(dc/da, 1))
+		}
+		d[n] = uint8(core.Clamp(v, 0, out)*255 + 0.5)
+	}
+	d[3] = uint8(core.Clamp(out, 0, 1)*255 + 0.5)
+}
+
+// brush is what a shape is filled with: a color, or a gradient over the
+// bounds of the shape.
+type brush struct {
+	color core.Color
+	grad  *core.Gradient
+	box   core.Rect
+}
+
+// cover fills polys with br.
+func (c *Canvas) cover(polys []polygon, rule core.FillRule, br brush) {
+	box, cov := c.fill(polys, rule, c.clip)
+	if cov == nil {
+		return
+	}
+	w := box.Dx()
+	col := br.color
+	for y := box.Min.Y; y < box.Max.Y; y++ {
+		for x := box.Min.X; x < box.Max.X; x++ {
+			if k := cov[(y-box.Min.Y)*w+x-box.Min.X]; k > 0 {
+				if br.grad != nil {
+					// The gradient is sampled at the center of the pixel.
+					p := core.Pt((float32(x)+0.5)/c.scale-c.tx, (float32(y)+0.5)/c.scale-c.ty)
+					col = br.grad.At(p, br.box)
+				}
+				r, g, b, a := premultiply(col, min(k, 1))
+				c.blend(x, y, r, g, b, a)
+			}
+		}
+	}
+}
+
+// blend draws the premultiplied color r, g, b, a over the pixel at x, y,
+// within the clip paths.
+func (c *Canvas) blend(x, y int, r, g, b, a uint8) {
+	if m := c.maskAt(x, y); m < 1 {
+		scale := func(v uint8) uint8 { return uint8(float32(v)*m + 0.5) }
+		r, g, b, a = scale(r), scale(g), scale(b), scale(a)
+	}
+	if a == 0 {
+		return
+	}
+	i := c.img.PixOffset(x, y)
+	p := c.img.Pix[i : i+4 : i+4]
+	inv := 255 - uint32(a)
+	p[0] = r + uint8((uint32(p[0])*inv+127)/255)
+	p[1] = g + uint8((uint32(p[1])*inv+127)/255)
+	p[2] = b + uint8((uint32(p[2])*inv+127)/255)
+	p[3] = a + uint8((uint32(p[3])*inv+127)/255)
+}
+
+// premultiply returns col at coverage k as premultiplied 8-bit components.
+func premultiply(col core.Color, k float32) (r, g, b, a uint8) {
+	al := core.Clamp(col.A, 0, 1) * k
+	conv := func(v float32) uint8 {
+		return uint8(core.Clamp(v, 0, 1)*al*255 + 0.5)
+	}
+	return conv(col.R), conv(col.G), conv(col.B), uint8(al*255 + 0.5)
+}
+
+// layer is a layer of a Canvas, an image of its own.
+type layer struct {
+	size   core.Size
+	canvas *Canvas
+}
+
+func (l *layer) Size() core.Size {
+	return l.size
+}
+
+func (l *layer) Begin() core.Canvas {
+	clear(l.canvas.img.Pix)
+	l.canvas.tx, l.canvas.ty, l.canvas.mask, l.canvas.stack = 0, 0, nil, l.canvas.stack[:0]
+	l.canvas.clip = l.canvas.img.Bounds()
+	return l.canvas
+}
+
+func (l *layer) End() {}
+
+func (l *layer) Release() {
+	l.canvas.img = image.NewRGBA(image.Rectangle{})
+}
+
+// ReadPixels implements core.ReadableLayer.
+func (l *layer) ReadPixels() (*image.RGBA, error) {
+	return l.canvas.img, nil
+}
@@ -0,0 +1,308 @@
+package raster
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+)
+
+// alphas returns the alpha of the pixels of row y of c from x0 to x1.
+func alphas(c *Canvas, y, x0, x1 int) []uint8 {
+	var a []uint8
+	for x := x0; x < x1; x++ {
+		a = append(a, c.Image().RGBAAt(x, y).A)
+	}
+	return a
+}
+
+func TestDrawRectSnapped(t *testing.T) {
+	tests := []struct {
+		name  string
+		scale float32
+		r     core.Rect
+		st    core.RectStyle
+		runs  [][2]int // Inked on row 20.
+	}{
+		{"filled", 1, core.R(10.3, 10.3, 5.4, 20), core.Filled(core.Black), [][2]int{{10, 15}}},
+		{"filled at 1.25", 1.25, core.R(8.3, 8, 4, 20), core.Filled(core.Black), [][2]int{{10, 15}}},
+		{"odd stroke", 1, core.R(10.3, 0, 10, 40), core.Stroked(core.Black, 1), [][2]int{{10, 11}, {20, 21}}},
+		{"even stroke", 1, core.R(10.3, 0, 10, 40), core.Stroked(core.Black, 2), [][2]int{{9, 11}, {19, 21}}},
+		{"stroke at 1.5", 1.5, core.R(6.9, 0, 10, 40), core.Stroked(core.Black, 1), [][2]int{{9, 11}, {24, 26}}},
+	}
+	for _, tt := range tests {
+		c := NewImage(core.Sz(40, 40), tt.scale)
+		c.DrawRect(tt.r, tt.st)
+		runs := inked(c, 20)
+		if len(runs) != len(tt.runs) {
+			t.Errorf("%s: runs %v, want %v", tt.name, runs, tt.runs)
+			continue
+		}
+		for i, run := range runs {
+			if run != tt.runs[i] {
+				t.Errorf("%s: runs %v, want %v", tt.name, runs, tt.runs)
+			}
+			// The edges are on pixels, with none partly covered.
+			for _, a := range alphas(c, 20, run[0]-1, run[1]+1) {
+				if a != 0 && a != 0xff {
+					t.Errorf("%s: alphas %v around %v, want whole pixels", tt.name, alphas(c, 20, run[0]-1, run[1]+1), run)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestDrawRectSnappedTranslated(t *testing.T) {
+	// Edges are snapped where they fall on the image, not before the
+	// transform moves them.
+	c := NewImage(core.Sz(40, 40), 1)
+	c.Translate(0.5, 0)
+	c.DrawRect(core.R(10, 0, 5, 40), core.Filled(core.Black))
+	if runs := inked(c, 20); len(runs) != 1 || runs[0][1]-runs[0][0] != 5 {
+		t.Errorf("runs %v, want one 5 wide", runs)
+	}
+	for _, a := range alphas(c, 20, 8, 18) {
+		if a != 0 && a != 0xff {
+			t.Errorf("alphas %v, want whole pixels", alphas(c, 20, 8, 18))
+			break
+		}
+	}
+}
+
+func TestAntialiasing(t *testing.T) {
+	// A triangle with a slanted edge, and a line half a pixel wide.
+	tri := core.NewPath().MoveTo(core.Pt(0, 0)).LineTo(core.Pt(40, 0)).LineTo(core.Pt(0, 13)).Close()
+	line := core.NewPath().MoveTo(core.Pt(0, 30.5)).LineTo(core.Pt(40, 30.5))
+	tests := []struct {
+		name   string
+		aa     core.Antialiasing
+		smooth bool  // Whether the slanted edge has pixels partly covered.
+		line   uint8 // The alpha of the thin line.
+	}{
+		{"analytic", core.Antialiasing{}, true, 0x80},
+		{"msaa", core.Antialiasing{Mode: core.AntialiasMSAA}, true, 0x80},
+		{"none", core.Antialiasing{Mode: core.AntialiasNone}, false, 0xff},
+	}
+	for _, tt := range tests {
+		c := NewImage(core.Sz(40, 40), 1)
+		c.SetAntialiasing(tt.aa)
+		c.DrawPath(tri, core.PathStyle{Fill: core.Black})
+		c.DrawPath(line, core.PathStyle{Stroke: core.Black, StrokeWidth: 0.5})
+		partly := false
+		for y := range 13 {
+			for _, a := range alphas(c, y, 0, 40) {
+				partly = partly || (a != 0 && a != 0xff)
+			}
+		}
+		if partly != tt.smooth {
+			t.Errorf("%s: edge pixels partly covered %v, want %v", tt.name, partly, tt.smooth)
+		}
+		// The line is a pixel wide, fainter where edges are smoothed.
+		if a := int(c.Image().RGBAAt(20, 30).A); a < int(tt.line)-2 || a > int(tt.line)+1 {
+			t.Errorf("%s: line of alpha %#x, want %#x", tt.name, a, tt.line)
+		}
+		if a := c.Image().RGBAAt(20, 29).A + c.Image().RGBAAt(20, 31).A; a != 0 {
+			t.Errorf("%s: line wider than a pixel", tt.name)
+		}
+
+		// Layers draw as the canvas they are made from.
+		l := c.NewLayer(core.Sz(40, 40))
+		l.Begin().DrawPath(tri, core.PathStyle{Fill: core.Black})
+		l.End()
+		if got := l.(*layer).canvas.aa; got != tt.aa {
+			t.Errorf("%s: layer antialiased %+v", tt.name, got)
+		}
+		l.Release()
+	}
+}
+
+func TestDrawBackdrop(t *testing.T) {
+	// halves returns a canvas black on its left half and red on its right.
+	halves := func() *Canvas {
+		c := NewImage(core.Sz(40, 20), 1)
+		c.DrawRect(core.R(0, 0, 20, 20), core.Filled(core.Black))
+		c.DrawRect(core.R(20, 0, 20, 20), core.Filled(core.RGB(255, 0, 0)))
+		return c
+	}
+	red := func(c *Canvas, x int) uint8 { return c.Image().RGBAAt(x, 10).R }
+
+	// A blur mixes the halves inside the region, reading past it, and
+	// leaves alone what is outside.
+	c := halves()
+	c.DrawBackdrop(core.R(10, 0, 20, 20), 0, core.Blur(2))
+	if r := red(c, 19); r < 0x30 || r > 0x80 {
+		t.Errorf("red %#x at the edge of black, want blurred", r)
+	}
+	if red(c, 5) != 0 || red(c, 35) != 0xff || red(c, 10) != 0 || red(c, 29) != 0xff {
+		t.Errorf("reds %#x %#x %#x %#x, want those far from the edge as they were", red(c, 5), red(c, 10), red(c, 29), red(c, 35))
+	}
+	if a := c.Image().RGBAAt(19, 10).A; a != 0xff {
+		t.Errorf("alpha %#x, want opaque as beneath", a)
+	}
+
+	// Without a blur, saturation and brightness apply to each pixel, and
+	// only within the clip.
+	c = halves()
+	c.Save()
+	c.Clip(core.R(0, 0, 30, 20))
+	c.DrawBackdrop(core.R(10, 0, 30, 20), 0, core.Blur(0).WithSaturation(0))
+	c.Restore()
+	if p := c.Image().RGBAAt(25, 10); p.R != p.G || p.G != p.B || p.R < 0x30 || p.R > 0x40 {
+		t.Errorf("%v, want red turned gray", p)
+	}
+	if p := c.Image().RGBAAt(35, 10); p.R != 0xff || p.G != 0 {
+		t.Errorf("%v outside the clip, want red", p)
+	}
+	c.DrawBackdrop(core.R(0, 0, 40, 20), 0, core.Blur(0).WithBrightness(0))
+	if red(c, 35) != 0 {
+		t.Error("a brightness of 0 did not darken to black")
+	}
+}
+
+func TestDrawShadow(t *testing.T) {
+	c := NewImage(core.Sz(60, 60), 1)
+	c.Save()
+	c.Clip(core.R(0, 0, 60, 45))
+	c.DrawShadow(core.R(20, 20, 20, 20), 0, core.Shadow{Color: core.Black, Offset: core.Pt(0, 10), Blur: 2})
+	c.Restore()
+	alpha := func(x, y int) uint8 { return c.Image().RGBAAt(x, y).A }
+
+	// The shadow is opaque inside the shape moved by its offset, about
+	// half at its edge and fades out within three deviations.
+	if a := alpha(30, 35); a < 0xfc {
+		t.Errorf("alpha %#x inside", a)
+	}
+	if a := alpha(20, 35); a < 0x70 || a > 0xa0 {
+		t.Errorf("alpha %#x at the edge, want about half", a)
+	}
+	if a, b := alpha(16, 35), alpha(14, 35); a == 0 || a >= alpha(20, 35) || b >= a {
+		t.Errorf("alphas %#x, %#x outside, want fading out", a, b)
+	}
+	if alpha(13, 35) != 0 || alpha(30, 22) != 0 {
+		t.Error("shadow beyond three deviations of the blur")
+	}
+	// It is drawn within the clip alone.
+	if alpha(30, 45) != 0 || alpha(30, 44) == 0 {
+		t.Error("shadow drawn outside the clip")
+	}
+}
+
+func TestDrawGradient(t *testing.T) {
+	red, blue := core.RGB(255, 0, 0), core.RGB(0, 0, 255)
+	rel := core.LinearGradient(core.Pt(0, 0), core.Pt(1, 0), core.Stop(0, red), core.Stop(1, blue))
+	rel.Relative = true
+	abs := core.RadialGradient(core.Pt(20, 30), 20, core.Stop(0, red), core.Stop(1, blue))
+
+	// At twice the scale, a relative gradient spans the shape wherever
+	// the transform puts it, and others are placed in its coordinates.
+	c := NewImage(core.Sz(60, 40), 2)
+	c.Translate(10, 0)
+	c.DrawRect(core.R(0, 0, 40, 10), core.GradientFilled(rel))
+	c.DrawPath(core.NewPath().AddRect(core.R(0, 20, 40, 20)), core.PathStyle{Stroke: core.Black, StrokeWidth: 4, StrokeGradient: abs})
+	at := func(x, y float32) [4]uint8 {
+		p := c.Image().RGBAAt(int(x*2), int(y*2))
+		return [4]uint8{p.R, p.G, p.B, p.A}
+	}
+	tests := []struct {
+		name string
+		x, y float32
+		want [4]uint8
+	}{
+		{"start", 10.1, 5, [4]uint8{0xff, 0, 0, 0xff}},
+		{"middle", 30, 5, [4]uint8{0x80, 0, 0x7f, 0xff}},
+		{"end", 49.9, 5, [4]uint8{0, 0, 0xff, 0xff}},
+		{"stroke near the center", 30, 20, [4]uint8{0x83, 0, 0x7c, 0xff}},
+		{"stroke far from it", 9.5, 30, [4]uint8{0, 0, 0xff, 0xff}},
+		{"inside the stroke", 30, 30, [4]uint8{}},
+	}
+	for _, tt := range tests {
+		got := at(tt.x, tt.y)
+		for i := range got {
+			if d := int(got[i]) - int(tt.want[i]); d < -2 || d > 2 {
+				t.Errorf("%s: %v at (%v, %v), want %v", tt.name, got, tt.x, tt.y, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestClipPath(t *testing.T) {
+	c := NewImage(core.Sz(40, 40), 1)
+	c.Save()
+	c.ClipPath(core.NewPath().AddEllipse(core.R(0, 0, 40, 40)), core.FillNonZero)
+	c.Save()
+	c.ClipPath(core.NewPath().AddRect(core.R(20, 0, 20, 40)), core.FillNonZero)
+	c.Restore()
+	c.DrawRect(core.R(0, 0, 40, 20), core.Filled(core.Black))
+	// Layers are drawn through the clip as shapes are.
+	l := c.NewLayer(core.Sz(40, 20))
+	l.Begin().DrawRect(core.R(0, 0, 40, 20), core.Filled(core.Black))
+	l.End()
+	c.DrawLayer(l, core.Pt(0, 20))
+	c.Restore()
+	c.DrawRect(core.R(0, 39, 1, 1), core.Filled(core.Black))
+
+	alpha := func(x, y int) uint8 { return c.Image().RGBAAt(x, y).A }
+	tests := []struct {
+		name string
+		x, y int
+		want uint8
+	}{
+		{"inside", 20, 10, 0xff},
+		{"inside the layer", 20, 30, 0xff},
+		{"left of the clip restored", 5, 20, 0xff},
+		{"off the corner", 2, 2, 0},
+		{"off the corner of the layer", 37, 37, 0},
+		{"after the clip", 0, 39, 0xff},
+	}
+	for _, tt := range tests {
+		if got := alpha(tt.x, tt.y); got != tt.want {
+			t.Errorf("%s: alpha %#x at (%d, %d), want %#x", tt.name, got, tt.x, tt.y, tt.want)
+		}
+	}
+	// The edge is antialiased.
+	if a := alpha(0, 20); a == 0 || a == 0xff {
+		t.Errorf("alpha %#x on the edge, want partly covered", a)
+	}
+}
+
+func TestDrawLayerComposite(t *testing.T) {
+	gray := core.RGB(128, 128, 128)
+	tests := []struct {
+		name    string
+		opacity float32
+		mode    core.BlendMode
+		want    [4]uint8 // Of red over gray.
+	}{
+		{"normal", 1, core.BlendNormal, [4]uint8{0xff, 0, 0, 0xff}},
+		{"faded", 0.5, core.BlendNormal, [4]uint8{0xc0, 0x40, 0x40, 0xff}},
+		{"multiply", 1, core.BlendMultiply, [4]uint8{0x80, 0, 0, 0xff}},
+		{"screen", 1, core.BlendScreen, [4]uint8{0xff, 0x80, 0x80, 0xff}},
+		{"faded multiply", 0.5, core.BlendMultiply, [4]uint8{0x80, 0x40, 0x40, 0xff}},
+		{"invisible", 0, core.BlendMultiply, [4]uint8{0x80, 0x80, 0x80, 0xff}},
+	}
+	for _, tt := range tests {
+		c := NewImage(core.Sz(20, 10), 1)
+		c.DrawRect(core.R(0, 0, 20, 10), core.Filled(gray))
+		l := c.NewLayer(core.Sz(20, 10))
+		l.Begin().DrawRect(core.R(0, 0, 10, 10), core.Filled(core.RGB(255, 0, 0)))
+		l.End()
+		c.Save()
+		c.Clip(core.R(0, 0, 5, 10))
+		c.DrawLayerComposite(l, core.Pt(0, 0), tt.opacity, tt.mode)
+		c.Restore()
+		p := c.Image().RGBAAt(2, 5)
+		got := [4]uint8{p.R, p.G, p.B, p.A}
+		for i := range got {
+			if d := int(got[i]) - int(tt.want[i]); d < -1 || d > 1 {
+				t.Errorf("%s: %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+		// Outside the clip, and where the layer is transparent, the
+		// backdrop is left as it is.
+		if p, q := c.Image().RGBAAt(7, 5), c.Image().RGBAAt(15, 5); p.R != 0x80 || q.R != 0x80 || q.G != 0x80 {
+			t.Errorf("%s: %v outside the clip and %v beside the layer", tt.name, p, q)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package raster
+
+import (
+	"sync"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/font"
+)
+
+// loaded holds the fonts of the faces text was drawn or measured with,
+// nil for those that failed to load.
+var loaded sync.Map // *font.Face → *font.Font
+
+func load(face *font.Face) *font.Font {
+	if f, ok := loaded.Load(face); ok {
+		return f.(*font.Font)
+	}
+	f, err := face.Load()
+	if err != nil {
+		f = nil
+	}
+	v, _ := loaded.LoadOrStore(face, f)
+	return v.(*font.Font)
+}
+
+// shapeText shapes text in style with the faces fonts gives its
+// characters and calls fn, if not nil, for each run of one face with its
+// glyphs and its x from the start of the text; f is nil for runs no face
+// covers, which have no glyphs and are as wide as core.ApproxTextMeasurer
+// measures them. It returns the width of the text.
+func shapeText(fonts *font.Manager, text string, st core.TextStyle, fn func(f *font.Font, run string, glyphs []font.Glyph, x float32)) float32 {
+	var x float32
+	for _, run := range fonts.Runs(text, st) {
+		var f *font.Font
+		if run.Face != nil {
+			f = load(run.Face)
+		}
+		if f == nil {
+			if fn != nil {
+				fn(nil, run.Text, nil, x)
+			}
+			x += core.ApproxTextMeasurer{}.MeasureText(run.Text, st).Width
+			continue
+		}
+		glyphs := f.Shape(run.Text, st.Size)
+		if fn != nil {
+			fn(f, run.Text, glyphs, x)
+		}
+		for _, g := range glyphs {
+			x += g.Advance
+		}
+	}
+	return x
+}
+
+// Measurer is a core.TextMeasurer giving text the width of its glyphs in
+// fonts, shaped as a Canvas drawing with the same fonts draws them, so
+// that text is laid out as it is drawn.
+type Measurer struct {
+	fonts *font.Manager
+}
+
+// NewMeasurer returns a Measurer of text in the faces of fonts.
+func NewMeasurer(fonts *font.Manager) *Measurer {
+	return &Measurer{fonts: fonts}
+}
+
+// MeasureText implements core.TextMeasurer.
+func (m *Measurer) MeasureText(text string, st core.TextStyle) core.Size {
+	return core.Sz(shapeText(m.fonts, text, st, nil), st.LineHeight())
+}
+
+// Fonts makes the canvas draw text with the outlines of its glyphs in the
+// faces of fonts, shaped and with fallback faces for the characters the
+// style's face lacks as GPU backends draw it, color glyphs of emoji fonts
+// included. Characters no face has an outline for are still drawn as
+// boxes. Text should be laid out with a Measurer of the same fonts.
+func (c *Canvas) Fonts(fonts *font.Manager) *Canvas {
+	c.fonts = fonts
+	return c
+}
+
+// drawGlyphs draws text with its baseline at base, in the fonts of the
+// canvas.
+func (c *Canvas) drawGlyphs(text string, x0, base float32, st core.TextStyle) {
+	fill := core.PathStyle{Fill: st.Color}
+	shapeText(c.fonts, text, st, func(f *font.Font, run string, glyphs []font.Glyph, x float32) {
+		if f == nil {
+			c.drawBoxes(run, x0+x, base, st)
+			return
+		}
+		pen := x0 + x
+		for _, g := range glyphs {
+			at := core.Pt(pen+g.Offset.X, base+g.Offset.Y)
+			pen += g.Advance
+			if layers, ok := f.Layers(g.ID, 0); ok {
+				for _, l := range layers {
+					col := l.Color
+					if l.Foreground {
+						col = st.Color
+					}
+					c.drawOutline(f, l.Glyph, at, st.Size, core.PathStyle{Fill: col})
+				}
+				continue
+			}
+			if bm, ok := f.Bitmap(g.ID, st.Size); ok && bm.PPEM > 0 {
+				k := st.Size / float32(bm.PPEM)
+				b := bm.Image.Bounds()
+				c.DrawImage(bm.Image, core.R(at.X+bm.Bearing.X*k, at.Y+bm.Bearing.Y*k, float32(b.Dx())*k, float32(b.Dy())*k))
+				continue
+			}
+			if !c.drawOutline(f, g.ID, at, st.Size, fill) {
+				c.DrawRect(core.R(at.X+g.Advance*0.1, base-0.7*st.Size, g.Advance*0.8, 0.7*st.Size), core.Filled(st.Color))
+			}
+		}
+	})
+}
+
+// drawOutline fills the outline of glyph g at size with its origin at
+// at, and reports whether f has one.
+func (c *Canvas) drawOutline(f *font.Font, g uint16, at core.Point, size float32, st core.PathStyle) bool {
+	p, ok := f.Outline(g, size)
+	if !ok {
+		return false
+	}
+	c.DrawPath(p.Transform(func(q core.Point) core.Point { return q.Add(at) }), st)
+	return true
+}
@@ -0,0 +1,63 @@
+package raster
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/font"
+)
+
+// inked returns the runs of painted pixels on row y of c, as their
+// starts and ends.
+func inked(c *Canvas, y int) [][2]int {
+	var runs [][2]int
+	img := c.Image()
+	for x := img.Bounds().Min.X; x < img.Bounds().Max.X; x++ {
+		if img.RGBAAt(x, y).A < 0x80 {
+			continue
+		}
+		if n := len(runs); n > 0 && runs[n-1][1] == x {
+			runs[n-1][1]++
+		} else {
+			runs = append(runs, [2]int{x, x + 1})
+		}
+	}
+	return runs
+}
+
+func TestDrawTextBoxes(t *testing.T) {
+	c := NewImage(core.Sz(100, 50), 1)
+	st := core.TextStyle{Size: 20, Color: core.Black}
+	c.DrawText("H H", core.Pt(0, 0), st)
+	// A box for each H, none for the space, on the middle row of the
+	// boxes.
+	base := int(st.Ascent())
+	if runs := inked(c, base-int(0.35*st.Size)); len(runs) != 2 {
+		t.Errorf("runs %v, want two boxes", runs)
+	}
+}
+
+func TestDrawTextGlyphs(t *testing.T) {
+	fonts := font.NewManager()
+	if fonts.LoadSystem() == 0 {
+		t.Skip("no fonts installed")
+	}
+	st := core.TextStyle{Size: 40, Color: core.Black}
+	m := NewMeasurer(fonts)
+	w := m.MeasureText("H", st).Width
+	if w <= 0 || m.MeasureText("HH", st).Width != 2*w {
+		t.Fatalf("widths of H and HH %v and %v, want one twice the other", w, m.MeasureText("HH", st).Width)
+	}
+	c := NewImage(core.Sz(100, 60), 1).TextMeasurer(m).Fonts(fonts)
+	c.DrawText("H", core.Pt(0, 0), st)
+	// Above the bar of the H, its two stems are painted and not the
+	// counter between them, where a box would be.
+	base := int(st.Ascent())
+	runs := inked(c, base-int(0.5*st.Size))
+	if len(runs) != 2 {
+		t.Fatalf("runs %v, want the two stems of an H", runs)
+	}
+	if runs[1][1] > int(w)+1 {
+		t.Errorf("H inked to %d, past its width %v", runs[1][1], w)
+	}
+}
@@ -0,0 +1,366 @@
+package sdf
+
+import (
+	"image"
+	"image/draw"
+	"math"
+
+	"github.com/gogpu/ui/core"
+)
+
+const (
+	defaultBaseSize float32 = 48
+	defaultSpread           = 6
+	defaultMaxPages         = 4
+	atlasPadding            = 1 // Empty texels between glyphs, against bleeding when sampling.
+)
+
+// GlyphKey identifies a glyph of a font face. Glyph is the index of the
+// glyph in the face, as produced by shaping, so ligatures and contextual
+// forms are distinct keys. Variations holds the axis values of a variable
+// font, as formatted by core.FormatVariations, so each instance has its
+// own fields; it is empty for the default instance and for other fonts.
+//
+// Size and Subpixel are for glyphs that are not drawn from one field at
+// every size: Size, if not zero, rasterizes the glyph at that size rather
+// than the atlas's base size, such as for hinted small text or color
+// bitmaps, and Subpixel is the phase of the pen within a pixel, from
+// SubpixelPosition, for rasterizers that position outlines at fractions of
+// a pixel. Glyphs kept at different sizes and phases never share a region.
+type GlyphKey struct {
+	Family     string
+	Weight     core.FontWeight
+	Variations string
+	Glyph      uint32
+	Size       float32
+	Subpixel   uint8
+}
+
+// SubpixelSteps is the number of pen phases within a pixel that
+// SubpixelPosition distinguishes.
+const SubpixelSteps = 4
+
+// SubpixelPosition splits the pen position x into the whole pixel to draw
+// a glyph at and the phase within it for GlyphKey.Subpixel, rounded to
+// the nearest of SubpixelSteps.
+func SubpixelPosition(x float32) (float32, uint8) {
+	whole := float32(math.Floor(float64(x)))
+	phase := int(math.Round(float64(x-whole) * SubpixelSteps))
+	if phase == SubpixelSteps {
+		whole, phase = whole+1, 0
+	}
+	return whole, uint8(phase)
+}
+
+// Metrics place a rasterized glyph relative to the pen position, in
+// pixels at the size it was rasterized at.
+type Metrics struct {
+	// Bearing is the offset from the pen position on the baseline to the
+	// top left corner of the coverage bitmap.
+	Bearing core.Point
+
+	// Advance is the distance the pen moves after the glyph.
+	Advance float32
+}
+
+// Rasterizer is implemented by the backend's font engine. Rasterize
+// returns the coverage of the glyph at size pixels per em; an empty glyph
+// such as a space returns an empty bitmap.
+type Rasterizer interface {
+	Rasterize(key GlyphKey, size float32) (*image.Alpha, Metrics)
+}
+
+// Glyph is a glyph stored in an Atlas.
+type Glyph struct {
+	// Page is the atlas page the glyph is in, and Region its distance
+	// field in the page's image, empty for glyphs with nothing to draw.
+	Page   int
+	Region image.Rectangle
+
+	// Metrics are those of the size the glyph was rasterized at, with the
+	// bearing moved to the corner of the padded field.
+	Metrics Metrics
+
+	base float32
+}
+
+// Quad returns the rectangle to draw the glyph's region into for text of
+// size pixels per em with the pen at pen.
+func (g Glyph) Quad(pen core.Point, size float32) core.Rect {
+	s := size / g.base
+	return core.R(
+		pen.X+g.Metrics.Bearing.X*s,
+		pen.Y+g.Metrics.Bearing.Y*s,
+		float32(g.Region.Dx())*s,
+		float32(g.Region.Dy())*s,
+	)
+}
+
+// Advance returns how far the pen moves after the glyph at size pixels
+// per em.
+func (g Glyph) Advance(size float32) float32 {
+	return g.Metrics.Advance * size / g.base
+}
+
+type shelf struct {
+	y, height, x int
+}
+
+// page is one image of an atlas and the shelves packed into it.
+type page struct {
+	img     *image.Alpha
+	shelves []shelf
+	dirty   image.Rectangle
+	used    uint64 // The last frame a glyph on the page was used in.
+}
+
+func (p *page) clear() {
+	clear(p.img.Pix)
+	p.shelves = p.shelves[:0]
+	p.dirty = p.img.Bounds()
+}
+
+// Atlas packs glyph distance fields into single-channel images, its
+// pages, that the backend uploads as textures. Each glyph is rasterized
+// once, at the base size, and serves every size it is drawn at.
+//
+// The atlas holds up to MaxPages pages. When they are full, the page used
+// least recently is cleared for new glyphs, but never one holding a glyph
+// used in the current frame, whose region may already be in the frame's
+// vertices: the atlas grows by a page instead, and gives up the pages past
+// the limit at the next BeginFrame. Regions therefore stay valid until
+// the end of the frame they were returned in.
+type Atlas struct {
+	raster   Rasterizer
+	base     float32
+	spread   int
+	size     image.Point
+	maxPages int
+
+	pages  []*page
+	glyphs map[GlyphKey]Glyph
+	frame  uint64
+}
+
+// NewAtlas returns an empty atlas of pages of width×height texels, up to
+// four of them, that rasterizes glyphs with r at a base size of 48 pixels
+// with a spread of 6 texels.
+func NewAtlas(r Rasterizer, width, height int) *Atlas {
+	a := &Atlas{
+		raster:   r,
+		base:     defaultBaseSize,
+		spread:   defaultSpread,
+		size:     image.Pt(width, height),
+		maxPages: defaultMaxPages,
+		glyphs:   make(map[GlyphKey]Glyph),
+		frame:    1,
+	}
+	a.addPage()
+	return a
+}
+
+// BaseSize sets the size glyphs are rasterized at, 48 pixels by default.
+// Larger sizes keep sharper corners at large zoom factors at the cost of
+// atlas space. Changing it resets the atlas.
+func (a *Atlas) BaseSize(px float32) *Atlas {
+	if px > 0 && px != a.base {
+		a.base = px
+		a.Reset()
+	}
+	return a
+}
+
+// Spread sets how many texels the field extends beyond the outline, 6 by
+// default, which bounds how far outlines and shadows drawn from the field
+// can reach. Changing it resets the atlas.
+func (a *Atlas) Spread(texels int) *Atlas {
+	if texels > 0 && texels != a.spread {
+		a.spread = texels
+		a.Reset()
+	}
+	return a
+}
+
+// MaxPages sets how many pages the atlas keeps between frames, 4 by
+// default.
+func (a *Atlas) MaxPages(n int) *Atlas {
+	a.maxPages = max(1, n)
+	return a
+}
+
+// BeginFrame starts a frame, and is called by the backend before the
+// frame's first Glyph. Glyphs returned since the last call may be
+// evicted from now on, and pages the atlas grew by past MaxPages during
+// the last frame are given up, the least recently used first. Giving up a
+// page moves the last page into its place, which is then dirty as a
+// whole, so each page's texture stays at its index.
+func (a *Atlas) BeginFrame() {
+	a.frame++
+	for len(a.pages) > a.maxPages {
+		lru := a.leastRecent()
+		last := len(a.pages) - 1
+		a.evict(lru)
+		if lru != last {
+			a.pages[lru] = a.pages[last]
+			a.pages[lru].dirty = a.pages[lru].img.Bounds()
+			for key, g := range a.glyphs {
+				if g.Page == last {
+					g.Page = lru
+					a.glyphs[key] = g
+				}
+			}
+		}
+		a.pages = a.pages[:last]
+	}
+}
+
+// Glyph returns the glyph for key, rasterizing it and adding it to the
+// atlas on first use, and marks it used in the current frame. It returns
+// false only for a glyph whose field is larger than a page.
+func (a *Atlas) Glyph(key GlyphKey) (Glyph, bool) {
+	if g, ok := a.glyphs[key]; ok {
+		a.touch(g)
+		return g, true
+	}
+	size := a.base
+	if key.Size > 0 {
+		size = key.Size
+	}
+	cov, m := a.raster.Rasterize(key, size)
+	g := Glyph{Metrics: m, base: size}
+	if cov == nil || cov.Bounds().Empty() {
+		a.glyphs[key] = g
+		return g, true
+	}
+	field := Generate(cov, a.spread)
+	n, r, ok := a.allocate(field.Bounds().Dx(), field.Bounds().Dy())
+	if !ok {
+		return Glyph{}, false
+	}
+	p := a.pages[n]
+	draw.Draw(p.img, r, field, image.Point{}, draw.Src)
+	p.dirty = p.dirty.Union(r)
+	g.Page, g.Region = n, r
+	g.Metrics.Bearing = m.Bearing.Sub(core.Pt(float32(a.spread), float32(a.spread)))
+	a.glyphs[key] = g
+	a.touch(g)
+	return g, true
+}
+
+// touch marks the page of g used in the current frame.
+func (a *Atlas) touch(g Glyph) {
+	if !g.Region.Empty() {
+		a.pages[g.Page].used = a.frame
+	}
+}
+
+// allocate finds room for a w×h region: on a page with room for it, on a
+// new page while there are fewer than MaxPages or all of them are in use
+// this frame, or else on the least recently used page, cleared.
+func (a *Atlas) allocate(w, h int) (int, image.Rectangle, bool) {
+	if w+atlasPadding > a.size.X || h+atlasPadding > a.size.Y {
+		return 0, image.Rectangle{}, false
+	}
+	for i, p := range a.pages {
+		if r, ok := a.pack(p, w, h); ok {
+			return i, r, true
+		}
+	}
+	n := a.leastRecent()
+	if len(a.pages) < a.maxPages || a.pages[n].used == a.frame {
+		n = a.addPage()
+	} else {
+		a.evict(n)
+	}
+	r, ok := a.pack(a.pages[n], w, h)
+	return n, r, ok
+}
+
+// pack finds room for a w×h region on the shelf of p that fits it most
+// tightly, opening a new shelf below the last one if none does.
+func (a *Atlas) pack(p *page, w, h int) (image.Rectangle, bool) {
+	w, h = w+atlasPadding, h+atlasPadding
+	best := -1
+	for i, s := range p.shelves {
+		if s.height >= h && s.x+w <= a.size.X && (best < 0 || s.height < p.shelves[best].height) {
+			best = i
+		}
+	}
+	if best < 0 {
+		y := 0
+		if n := len(p.shelves); n > 0 {
+			y = p.shelves[n-1].y + p.shelves[n-1].height
+		}
+		if y+h > a.size.Y {
+			return image.Rectangle{}, false
+		}
+		p.shelves = append(p.shelves, shelf{y: y, height: h})
+		best = len(p.shelves) - 1
+	}
+	s := &p.shelves[best]
+	r := image.Rect(s.x, s.y, s.x+w-atlasPadding, s.y+h-atlasPadding)
+	s.x += w
+	return r, true
+}
+
+func (a *Atlas) addPage() int {
+	img := image.NewAlpha(image.Rectangle{Max: a.size})
+	a.pages = append(a.pages, &page{img: img, dirty: img.Bounds()})
+	return len(a.pages) - 1
+}
+
+// leastRecent returns the page used least recently.
+func (a *Atlas) leastRecent() int {
+	n := 0
+	for i, p := range a.pages {
+		if p.used < a.pages[n].used {
+			n = i
+		}
+	}
+	return n
+}
+
+// evict clears page n and removes the glyphs on it.
+func (a *Atlas) evict(n int) {
+	for key, g := range a.glyphs {
+		if g.Page == n && !g.Region.Empty() {
+			delete(a.glyphs, key)
+		}
+	}
+	a.pages[n].clear()
+	a.pages[n].used = 0
+}
+
+// Pages returns the number of pages.
+func (a *Atlas) Pages() int {
+	return len(a.pages)
+}
+
+// Image returns the image of page n. The glyph regions are distance
+// fields for Shader; the rest is zero.
+func (a *Atlas) Image(n int) *image.Alpha {
+	return a.pages[n].img
+}
+
+// TakeDirty returns the part of the image of page n changed since the
+// last call, to be uploaded to its texture, and clears it.
+func (a *Atlas) TakeDirty(n int) image.Rectangle {
+	p := a.pages[n]
+	r := p.dirty
+	p.dirty = image.Rectangle{}
+	return r
+}
+
+// Len returns the number of glyphs in the atlas.
+func (a *Atlas) Len() int {
+	return len(a.glyphs)
+}
+
+// Reset removes every glyph and every page but the first, which is
+// cleared and dirty as a whole.
+func (a *Atlas) Reset() {
+	clear(a.glyphs)
+	a.pages = a.pages[:1]
+	a.pages[0].clear()
+	a.pages[0].used = 0
+}
@@ -0,0 +1,209 @@
+package sdf
+
+import (
+	"bytes"
+	"image"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+)
+
+// boxes rasterizes every glyph as a filled box, 8 texels square unless
+// sizes says otherwise, counting its calls.
+type boxes struct {
+	sizes map[uint32]image.Point
+	calls int
+}
+
+func (b *boxes) Rasterize(key GlyphKey, size float32) (*image.Alpha, Metrics) {
+	b.calls++
+	s, ok := b.sizes[key.Glyph]
+	if !ok {
+		s = image.Pt(8, 8)
+	}
+	img := image.NewAlpha(image.Rectangle{Max: s})
+	for i := range img.Pix {
+		img.Pix[i] = 255
+	}
+	return img, Metrics{Bearing: core.Pt(1, -float32(s.Y)), Advance: 10}
+}
+
+// glyph returns the glyph of id from a, failing t if there is none.
+func glyph(t *testing.T, a *Atlas, id uint32) Glyph {
+	t.Helper()
+	g, ok := a.Glyph(GlyphKey{Family: "Box", Glyph: id})
+	if !ok {
+		t.Fatalf("no glyph %d", id)
+	}
+	return g
+}
+
+func TestAtlasPack(t *testing.T) {
+	r := &boxes{sizes: map[uint32]image.Point{3: {4, 4}, 4: {8, 14}, 5: {4, 4}, 6: {0, 0}}}
+	a := NewAtlas(r, 64, 64).Spread(1)
+	// With a spread of 1, a field is the box grown by a texel on every
+	// side, and a texel of padding follows it on its shelf.
+	tests := []struct {
+		id   uint32
+		want image.Rectangle
+	}{
+		{1, image.Rect(0, 0, 10, 10)},
+		{2, image.Rect(11, 0, 21, 10)},
+		{3, image.Rect(22, 0, 28, 6)},  // On the first shelf, tall enough.
+		{4, image.Rect(0, 11, 10, 27)}, // Too tall for it: a new shelf.
+		{5, image.Rect(29, 0, 35, 6)},  // On the tightest shelf.
+		{1, image.Rect(0, 0, 10, 10)},  // Kept.
+		{6, image.Rectangle{}},         // Nothing to draw.
+	}
+	for _, tt := range tests {
+		g := glyph(t, a, tt.id)
+		if g.Page != 0 || g.Region != tt.want {
+			t.Errorf("glyph %d on page %d at %v, want page 0 at %v", tt.id, g.Page, g.Region, tt.want)
+		}
+	}
+	if r.calls != 6 || a.Len() != 6 {
+		t.Errorf("%d rasterized, %d kept, want each glyph once", r.calls, a.Len())
+	}
+	if got := a.TakeDirty(0); got != image.Rect(0, 0, 64, 64) {
+		t.Errorf("dirty %v, want the new page", got)
+	}
+	if got := a.Image(0).AlphaAt(5, 5).A; got <= edgeValue {
+		t.Errorf("field %d in the middle of a glyph, want inside", got)
+	}
+
+	// The field is placed by its padded corner, and drawn at any size.
+	g := glyph(t, a, 7)
+	if got := a.TakeDirty(0); got != g.Region {
+		t.Errorf("dirty %v, want the new glyph's %v", got, g.Region)
+	}
+	if g.Metrics.Bearing != core.Pt(0, -9) {
+		t.Errorf("bearing %v, want moved by the spread", g.Metrics.Bearing)
+	}
+	if q := g.Quad(core.Pt(100, 50), 24); q != core.R(100, 45.5, 5, 5) {
+		t.Errorf("quad at half the base size %v", q)
+	}
+	if adv := g.Advance(24); adv != 5 {
+		t.Errorf("advance at half the base size %v", adv)
+	}
+
+	// A field larger than a page is not kept.
+	r.sizes[8] = image.Pt(70, 8)
+	if _, ok := a.Glyph(GlyphKey{Family: "Box", Glyph: 8}); ok {
+		t.Error("a glyph wider than a page was packed")
+	}
+}
+
+// fill adds the glyphs from id on, four of which fill a 32×32 page at a
+// spread of 1, and returns the id after them.
+func fill(t *testing.T, a *Atlas, id uint32, n int) uint32 {
+	for range n {
+		glyph(t, a, id)
+		id++
+	}
+	return id
+}
+
+func TestAtlasEvict(t *testing.T) {
+	r := &boxes{}
+	a := NewAtlas(r, 32, 32).Spread(1).MaxPages(2)
+	a.BeginFrame()
+	fill(t, a, 1, 4)
+	a.BeginFrame()
+	fill(t, a, 5, 4)
+	if a.Pages() != 2 || a.Len() != 8 {
+		t.Fatalf("%d pages of %d glyphs, want 2 of 8", a.Pages(), a.Len())
+	}
+
+	// With both pages full, the one used least recently is cleared.
+	a.BeginFrame()
+	glyph(t, a, 5)
+	a.TakeDirty(0)
+	if g := glyph(t, a, 9); g.Page != 0 || g.Region != image.Rect(0, 0, 10, 10) {
+		t.Errorf("glyph 9 on page %d at %v, want the first page cleared", g.Page, g.Region)
+	}
+	if a.Pages() != 2 || a.Len() != 5 {
+		t.Errorf("%d pages of %d glyphs, want the first page's glyphs gone", a.Pages(), a.Len())
+	}
+	if got := a.TakeDirty(0); got != image.Rect(0, 0, 32, 32) {
+		t.Errorf("dirty %v, want the cleared page", got)
+	}
+	if got := a.Image(0).AlphaAt(15, 5).A; got != 0 {
+		t.Errorf("field %d where an evicted glyph was", got)
+	}
+	calls := r.calls
+	if g := glyph(t, a, 1); g.Page != 0 || r.calls != calls+1 {
+		t.Errorf("an evicted glyph on page %d, rasterized %d more times, want again onto page 0", g.Page, r.calls-calls)
+	}
+}
+
+func TestAtlasGrow(t *testing.T) {
+	a := NewAtlas(&boxes{}, 32, 32).Spread(1).MaxPages(1)
+	a.BeginFrame()
+	fill(t, a, 1, 4)
+
+	// A page in use this frame is not cleared: the atlas grows instead.
+	next := fill(t, a, 5, 5)
+	if a.Pages() != 3 || a.Len() != 9 {
+		t.Fatalf("%d pages of %d glyphs, want three pages holding them all", a.Pages(), a.Len())
+	}
+	for id := uint32(1); id < next; id++ {
+		if g := glyph(t, a, id); g.Page != int(id-1)/4 {
+			t.Errorf("glyph %d on page %d", id, g.Page)
+		}
+	}
+}
+
+func TestAtlasBeginFrame(t *testing.T) {
+	a := NewAtlas(&boxes{}, 32, 32).Spread(1).MaxPages(3)
+	a.BeginFrame()
+	fill(t, a, 1, 4)
+	a.BeginFrame()
+	fill(t, a, 5, 4)
+	a.BeginFrame()
+	fill(t, a, 9, 4)
+	kept := glyph(t, a, 6)
+	a.TakeDirty(1)
+	pix := bytes.Clone(a.Image(1).Pix)
+
+	// Down to two pages, the atlas gives up the first, the least recently
+	// used, moving the last page into its place.
+	a.MaxPages(2)
+	a.BeginFrame()
+	if a.Pages() != 2 || a.Len() != 8 {
+		t.Fatalf("%d pages of %d glyphs, want 2 of 8", a.Pages(), a.Len())
+	}
+	for id := uint32(5); id < 13; id++ {
+		want := 0 // Moved from the last page.
+		if id < 9 {
+			want = 1
+		}
+		if g := glyph(t, a, id); g.Page != want {
+			t.Errorf("glyph %d on page %d, want %d", id, g.Page, want)
+		}
+	}
+	if got := a.TakeDirty(0); got != image.Rect(0, 0, 32, 32) {
+		t.Errorf("dirty %v, want the moved page as a whole", got)
+	}
+	if got := a.TakeDirty(1); !got.Empty() {
+		t.Errorf("dirty %v on the page left in place", got)
+	}
+	if g := glyph(t, a, 6); g.Region != kept.Region || !bytes.Equal(a.Image(1).Pix, pix) {
+		t.Errorf("glyph 6 at %v, want %v with its page unchanged", g.Region, kept.Region)
+	}
+
+	// Giving up the last page moves nothing.
+	a.MaxPages(1)
+	a.BeginFrame()
+	if a.Pages() != 1 || a.Len() != 4 {
+		t.Errorf("%d pages of %d glyphs, want the page of 9 to 12 alone", a.Pages(), a.Len())
+	}
+	if g := glyph(t, a, 9); g.Page != 0 {
+		t.Errorf("glyph 9 on page %d", g.Page)
+	}
+
+	// Reset keeps a cleared first page.
+	a.Reset()
+	if a.Pages() != 1 || a.Len() != 0 || a.TakeDirty(0) != image.Rect(0, 0, 32, 32) {
+		t.Errorf("after Reset %d pages of %d glyphs", a.Pages(), a.Len())
+	}
+}
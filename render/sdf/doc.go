@@ -0,0 +1,28 @@
+// Package sdf builds signed distance field glyph atlases for rendering
+// backends that draw text on the GPU.
+//
+// A bitmap glyph atlas holds each glyph once per font size, so text that
+// is zoomed or animated through many sizes fills and evicts the atlas
+// every frame, and glyphs scaled between sizes blur or alias. A distance
+// field instead stores, for each texel, the distance to the outline of the
+// glyph. Drawn through [Shader], one field rasterized at [Atlas] base size
+// stays sharp from small labels to large zoom factors, so the atlas holds
+// each glyph of a face exactly once:
+//
+//	atlas := sdf.NewAtlas(rasterizer, 1024, 1024)
+//	atlas.BeginFrame()
+//	g, ok := atlas.Glyph(sdf.GlyphKey{Family: "Inter", Weight: core.WeightRegular, Glyph: id})
+//	if ok {
+//		quad := g.Quad(pen, style.Size) // sampled from page g.Page
+//	}
+//	for i := range atlas.Pages() {
+//		upload(i, atlas.Image(i), atlas.TakeDirty(i))
+//	}
+//
+// Text in more glyphs than fit a page spills onto more pages, and pages
+// not drawn from recently are reused, so the regions of the glyphs of a
+// frame never change under it however much text it draws.
+//
+// The package does no font loading or shaping; the backend supplies
+// coverage bitmaps through a [Rasterizer].
+package sdf
@@ -0,0 +1,126 @@
+package sdf
+
+import (
+	"image"
+	"math"
+)
+
+// edgeValue is the field value on the outline of a glyph. Texels inside
+// the glyph are above it and texels outside below it.
+const edgeValue = 128
+
+// Generate returns the signed distance field of the coverage bitmap src,
+// padded by spread texels on every side so the field can fall off outside
+// the outline. Texels with at least half coverage are inside. Distances
+// are encoded linearly, edgeValue on the outline and 0 and 255 at spread
+// texels outside and inside it.
+func Generate(src *image.Alpha, spread int) *image.Alpha {
+	spread = max(1, spread)
+	b := src.Bounds()
+	w, h := b.Dx()+2*spread, b.Dy()+2*spread
+	inside := make([]float64, w*h)
+	outside := make([]float64, w*h)
+	for y := range h {
+		for x := range w {
+			sx, sy := b.Min.X+x-spread, b.Min.Y+y-spread
+			in := image.Pt(sx, sy).In(b) && src.AlphaAt(sx, sy).A >= 128
+			// Each field holds the squared distance to the nearest texel
+			// of the other kind.
+			if in {
+				inside[y*w+x], outside[y*w+x] = math.Inf(1), 0
+			} else {
+				inside[y*w+x], outside[y*w+x] = 0, math.Inf(1)
+			}
+		}
+	}
+	transform(inside, w, h)
+	transform(outside, w, h)
+
+	dst := image.NewAlpha(image.Rect(0, 0, w, h))
+	scale := float64(edgeValue-1) / float64(spread)
+	for i := range inside {
+		// The distances are between texel centers, half a texel more than
+		// to the edge between them.
+		var d float64
+		if inside[i] > 0 {
+			d = math.Sqrt(inside[i]) - 0.5
+		} else {
+			d = -(math.Sqrt(outside[i]) - 0.5)
+		}
+		dst.Pix[i] = uint8(min(255, max(0, math.Round(edgeValue+d*scale))))
+	}
+	return dst
+}
+
+// transform replaces each value of the w×h grid f, 0 at feature texels
+// and infinite elsewhere, with the squared distance to the nearest
+// feature, transforming the columns and then the rows.
+func transform(f []float64, w, h int) {
+	n := max(w, h)
+	line := make([]float64, n)
+	out := make([]float64, n)
+	v := make([]int, n)
+	z := make([]float64, n+1)
+	for x := range w {
+		for y := range h {
+			line[y] = f[y*w+x]
+		}
+		transform1D(line[:h], out[:h], v, z)
+		for y := range h {
+			f[y*w+x] = out[y]
+		}
+	}
+	for y := range h {
+		copy(line, f[y*w:(y+1)*w])
+		transform1D(line[:w], out[:w], v, z)
+		copy(f[y*w:(y+1)*w], out[:w])
+	}
+}
+
+// transform1D is the one-dimensional squared distance transform of
+// Felzenszwalb and Huttenlocher: the lower envelope of the parabolas
+// rooted at each sample of f.
+func transform1D(f, out []float64, v []int, z []float64) {
+	n := len(f)
+	k := -1
+	for q := range n {
+		if math.IsInf(f[q], 1) {
+			continue
+		}
+		for k >= 0 {
+			s := intersect(f, v[k], q)
+			if s > z[k] {
+				break
+			}
+			k--
+		}
+		k++
+		v[k] = q
+		if k == 0 {
+			z[k] = math.Inf(-1)
+		} else {
+			z[k] = intersect(f, v[k-1], q)
+		}
+		z[k+1] = math.Inf(1)
+	}
+	if k < 0 {
+		for q := range out {
+			out[q] = math.Inf(1)
+		}
+		return
+	}
+	j := 0
+	for q := range n {
+		for z[j+1] < float64(q) {
+			j++
+		}
+		d := float64(q - v[j])
+		out[q] = d*d + f[v[j]]
+	}
+}
+
+// intersect returns where the parabolas rooted at samples p and q of f
+// cross.
+func intersect(f []float64, p, q int) float64 {
+	return ((f[q] + float64(q*q)) - (f[p] + float64(p*p))) / float64(2*q-2*p)
+}
@@ -0,0 +1,147 @@
+package sdf
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"strings"
+	"testing"
+)
+
+// square returns a w×h coverage bitmap covering the texels of r.
+func square(w, h int, r image.Rectangle) *image.Alpha {
+	src := image.NewAlpha(image.Rect(0, 0, w, h))
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			src.Pix[src.PixOffset(x, y)] = 255
+		}
+	}
+	return src
+}
+
+func TestGenerate(t *testing.T) {
+	// A 4×4 square at texels 3 to 6, padded by 4 to texels 7 to 10.
+	field := Generate(square(10, 10, image.Rect(3, 3, 7, 7)), 4)
+	if got := field.Bounds(); got != image.Rect(0, 0, 18, 18) {
+		t.Fatalf("bounds %v, want padded by the spread", got)
+	}
+	tests := []struct {
+		name string
+		x, y int
+		want uint8
+	}{
+		{"middle", 8, 8, 176},          // 1.5 texels inside
+		{"inside the edge", 7, 8, 144}, // 0.5 inside
+		{"outside the edge", 6, 8, 112},
+		{"outside a corner", 6, 6, 99}, // 0.91 outside
+		{"spread away", 3, 8, 17},      // 3.5 outside
+		{"past the spread", 0, 0, 0},
+		{"other side", 11, 9, 112},
+	}
+	for _, tt := range tests {
+		if got := field.AlphaAt(tt.x, tt.y).A; got != tt.want {
+			t.Errorf("%s: field at (%d, %d) = %d, want %d", tt.name, tt.x, tt.y, got, tt.want)
+		}
+	}
+
+	// A bitmap without coverage is outside everywhere, and the spread is
+	// at least a texel.
+	empty := Generate(image.NewAlpha(image.Rect(0, 0, 3, 2)), 0)
+	if got := empty.Bounds(); got != image.Rect(0, 0, 5, 4) {
+		t.Errorf("bounds %v with no spread, want padded by 1", got)
+	}
+	for _, v := range empty.Pix {
+		if v != 0 {
+			t.Fatalf("an empty bitmap has field value %d", v)
+		}
+	}
+}
+
+// TestGenerateDistances compares the field of a disc with distances
+// between texel centers found by brute force.
+func TestGenerateDistances(t *testing.T) {
+	const size, spread = 24, 6
+	src := image.NewAlpha(image.Rect(0, 0, size, size))
+	for y := range size {
+		for x := range size {
+			if math.Hypot(float64(x)-11.5, float64(y)-11.5) < 7.3 {
+				src.Pix[src.PixOffset(x, y)] = 200
+			} else {
+				// Coverage under half is outside.
+				src.Pix[src.PixOffset(x, y)] = 100
+			}
+		}
+	}
+	field := Generate(src, spread)
+	w := size + 2*spread
+	inside := func(x, y int) bool {
+		sx, sy := x-spread, y-spread
+		return sx >= 0 && sy >= 0 && sx < size && sy < size && src.AlphaAt(sx, sy).A >= 128
+	}
+	for y := range w {
+		for x := range w {
+			in := inside(x, y)
+			nearest := math.Inf(1)
+			for v := range w {
+				for u := range w {
+					if inside(u, v) != in {
+						nearest = min(nearest, math.Hypot(float64(u-x), float64(v-y)))
+					}
+				}
+			}
+			d := nearest - 0.5
+			if !in {
+				d = -d
+			}
+			want := uint8(min(255, max(0, math.Round(edgeValue+d*(edgeValue-1)/spread))))
+			if got := field.AlphaAt(x, y).A; got != want {
+				t.Fatalf("field at (%d, %d) = %d, want %d", x, y, got, want)
+			}
+			if got := field.AlphaAt(x, y).A; in != (got > edgeValue) {
+				t.Fatalf("field at (%d, %d) = %d on the wrong side of the outline", x, y, got)
+			}
+		}
+	}
+}
+
+// TestShaderEdge evaluates the shader's coverage across the edge of the
+// field of a square drawn at one pixel to the texel: whole inside, none
+// outside and half on the outline.
+func TestShaderEdge(t *testing.T) {
+	if want := fmt.Sprintf("- %d.0 / 255.0", edgeValue); !strings.Contains(Shader, want) {
+		t.Fatalf("the shader does not take %q as the outline", want)
+	}
+	field := Generate(square(10, 10, image.Rect(3, 3, 7, 7)), 4)
+	// sample returns the field along row 8 at x in texels, filtered
+	// linearly between texel centers as by the sampler.
+	sample := func(x float64) float64 {
+		x -= 0.5
+		i := int(math.Floor(x))
+		f := x - float64(i)
+		a, b := float64(field.AlphaAt(i, 8).A), float64(field.AlphaAt(i+1, 8).A)
+		return (a + (b-a)*f) / 255
+	}
+	// coverage is fs_glyph at x, with the derivative across a pixel.
+	coverage := func(x float64) float64 {
+		d := sample(x) - edgeValue/255.0
+		fw := math.Abs(sample(x+0.5) - sample(x-0.5))
+		return min(1, max(0, d/max(fw, 1e-4)+0.5))
+	}
+	tests := []struct {
+		x    float64
+		want float64
+	}{
+		{3.5, 0},
+		{6.0, 0},
+		{7.0, 0.5}, // The left side of the square.
+		{8.0, 1},
+		{9.0, 1},
+		{11.0, 0.5}, // The right side.
+		{12.0, 0},
+	}
+	for _, tt := range tests {
+		if got := coverage(tt.x); math.Abs(got-tt.want) > 0.02 {
+			t.Errorf("coverage at %v = %.3f, want %v", tt.x, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package sdf
+
+// Shader is a WGSL fragment shader that draws glyph quads from an atlas.
+// The vertex stage passes the normalized atlas coordinates and the
+// premultiplied text color; the screen-space derivative of the field gives
+// an antialiased edge one pixel wide at every scale.
+//
+// Bindings: group 0, binding 0 is the atlas texture in r8unorm format and
+// binding 1 a linear filtering sampler.
+const Shader = `
+@group(0) @binding(0) var atlas: texture_2d<f32>;
+@group(0) @binding(1) var atlas_sampler: sampler;
+
+struct GlyphIn {
+	@location(0) uv: vec2<f32>,
+	@location(1) color: vec4<f32>,
+};
+
+@fragment
+fn fs_glyph(in: GlyphIn) -> @location(0) vec4<f32> {
+	let d = textureSample(atlas, atlas_sampler, in.uv).r - 128.0 / 255.0;
+	let alpha = clamp(d / max(fwidth(d), 1e-4) + 0.5, 0.0, 1.0);
+	return in.color * alpha;
+}
+`
@@ -0,0 +1,448 @@
+// Package richtext defines the document model of formatted text: a
+// sequence of blocks, such as paragraphs, headings and list items, each
+// holding a run of styled spans.
+//
+// The model is what widgets.RichTextEditor edits. Applications build,
+// inspect and store documents through it, and serialize them with
+// [Document.HTML] and [Document.Markdown]:
+//
+//	doc := richtext.New(
+//		richtext.Block{Kind: richtext.Heading, Level: 1, Spans: []richtext.Span{{Text: "Notes"}}},
+//		richtext.Block{Spans: []richtext.Span{{Text: "Ship it", Style: richtext.Bold}}},
+//	)
+//	editor := widgets.NewRichTextEditor()
+//	editor.SetDocument(doc)
+//
+// Positions in a document are counted in runes within a block; an inline
+// image counts as one position.
+package richtext
+
+import (
+	"image"
+	"slices"
+	"strings"
+)
+
+// Style is a set of character formats.
+type Style uint8
+
+// Character formats.
+const (
+	Bold Style = 1 << iota
+	Italic
+	Underline
+	Code // inline code, in a monospace font
+)
+
+// Has reports whether s includes every format in f.
+func (s Style) Has(f Style) bool {
+	return s&f == f
+}
+
+// Span is a run of text in one style, or an inline image.
+type Span struct {
+	Text  string
+	Style Style
+	// Link is the URL the span links to, or empty.
+	Link string
+	// Image, if set, makes the span an inline image occupying one
+	// position; Text is then ignored.
+	Image *Image
+}
+
+// Image is an inline image.
+type Image struct {
+	// Src is where the image comes from, written to serialized documents.
+	Src string
+	// Alt is the text describing the image.
+	Alt string
+	// Width and Height are the display size. Zero uses the size of Data.
+	Width, Height float32
+	// Data is the decoded image drawn by editors, if loaded.
+	Data image.Image
+}
+
+// Size returns the display size of the image, falling back to the size of
+// Data and then to a square the height of a line of text.
+func (img *Image) Size() (w, h float32) {
+	w, h = img.Width, img.Height
+	if img.Data != nil {
+		b := img.Data.Bounds()
+		switch {
+		case w == 0 && h == 0:
+			w, h = float32(b.Dx()), float32(b.Dy())
+		case w == 0 && b.Dy() > 0:
+			w = h * float32(b.Dx()) / float32(b.Dy())
+		case h == 0 && b.Dx() > 0:
+			h = w * float32(b.Dy()) / float32(b.Dx())
+		}
+	}
+	if w == 0 || h == 0 {
+		w, h = 20, 20
+	}
+	return w, h
+}
+
+// BlockKind is the kind of a block.
+type BlockKind uint8
+
+// Block kinds.
+const (
+	Paragraph BlockKind = iota
+	Heading
+	BulletItem
+	NumberedItem
+)
+
+// IsList reports whether k is a kind of list item.
+func (k BlockKind) IsList() bool {
+	return k == BulletItem || k == NumberedItem
+}
+
+// Block is one paragraph, heading or list item.
+type Block struct {
+	Kind BlockKind
+	// Level is the heading level, 1 to 3, or the nesting depth of a list
+	// item, starting at 0.
+	Level int
+	Spans []Span
+}
+
+// Len returns the number of positions in the block.
+func (b *Block) Len() int {
+	n := 0
+	for _, s := range b.Spans {
+		n += s.len()
+	}
+	return n
+}
+
+// Text returns the text of the block, with images as their alternative
+// text.
+func (b *Block) Text() string {
+	var sb strings.Builder
+	for _, s := range b.Spans {
+		if s.Image != nil {
+			sb.WriteString(s.Image.Alt)
+		} else {
+			sb.WriteString(s.Text)
+		}
+	}
+	return sb.String()
+}
+
+func (s Span) len() int {
+	if s.Image != nil {
+		return 1
+	}
+	return len([]rune(s.Text))
+}
+
+// Document is a sequence of blocks. A document always has at least one
+// block once created with New or edited.
+type Document struct {
+	Blocks []Block
+}
+
+// New returns a document of blocks, or of one empty paragraph.
+func New(blocks ...Block) *Document {
+	d := &Document{Blocks: blocks}
+	d.normalize()
+	return d
+}
+
+// FromText returns a document with a paragraph for each line of s.
+func FromText(s string) *Document {
+	d := &Document{}
+	for _, line := range strings.Split(s, "\n") {
+		d.Blocks = append(d.Blocks, Block{Spans: []Span{{Text: line}}})
+	}
+	d.normalize()
+	return d
+}
+
+// Clone returns a deep copy of d. Images are shared.
+func (d *Document) Clone() *Document {
+	c := &Document{Blocks: make([]Block, len(d.Blocks))}
+	for i, b := range d.Blocks {
+		b.Spans = slices.Clone(b.Spans)
+		c.Blocks[i] = b
+	}
+	return c
+}
+
+// PlainText returns the text of the document with a newline between
+// blocks.
+func (d *Document) PlainText() string {
+	lines := make([]string, len(d.Blocks))
+	for i := range d.Blocks {
+		lines[i] = d.Blocks[i].Text()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Pos is a position in a document: an offset within a block.
+type Pos struct {
+	Block, Offset int
+}
+
+// Less reports whether p comes before q.
+func (p Pos) Less(q Pos) bool {
+	return p.Block < q.Block || p.Block == q.Block && p.Offset < q.Offset
+}
+
+// Start returns the position before the first character.
+func (d *Document) Start() Pos {
+	return Pos{}
+}
+
+// End returns the position after the last character.
+func (d *Document) End() Pos {
+	last := len(d.Blocks) - 1
+	return Pos{last, d.Blocks[last].Len()}
+}
+
+// Clamp returns the valid position nearest to p.
+func (d *Document) Clamp(p Pos) Pos {
+	p.Block = max(0, min(p.Block, len(d.Blocks)-1))
+	p.Offset = max(0, min(p.Offset, d.Blocks[p.Block].Len()))
+	return p
+}
+
+// order returns from and to with the earlier first, clamped.
+func (d *Document) order(from, to Pos) (Pos, Pos) {
+	from, to = d.Clamp(from), d.Clamp(to)
+	if to.Less(from) {
+		return to, from
+	}
+	return from, to
+}
+
+// cell is one position of a block in the flat form used for editing.
+type cell struct {
+	r     rune
+	style Style
+	link  string
+	img   *Image
+}
+
+func (b *Block) cells() []cell {
+	var cs []cell
+	for _, s := range b.Spans {
+		if s.Image != nil {
+			cs = append(cs, cell{style: s.Style, link: s.Link, img: s.Image})
+			continue
+		}
+		for _, r := range s.Text {
+			cs = append(cs, cell{r: r, style: s.Style, link: s.Link})
+		}
+	}
+	return cs
+}
+
+// setCells replaces the spans of b with cs, merging runs of equal format.
+func (b *Block) setCells(cs []cell) {
+	var spans []Span
+	var sb strings.Builder
+	flush := func(c cell) {
+		if sb.Len() > 0 {
+			spans = append(spans, Span{Text: sb.String(), Style: c.style, Link: c.link})
+			sb.Reset()
+		}
+	}
+	for i, c := range cs {
+		if i > 0 && (c.img != nil || c.style != cs[i-1].style || c.link != cs[i-1].link) {
+			flush(cs[i-1])
+		}
+		if c.img != nil {
+			spans = append(spans, Span{Style: c.style, Link: c.link, Image: c.img})
+			continue
+		}
+		sb.WriteRune(c.r)
+	}
+	if len(cs) > 0 {
+		flush(cs[len(cs)-1])
+	}
+	b.Spans = spans
+}
+
+func (d *Document) normalize() {
+	if len(d.Blocks) == 0 {
+		d.Blocks = []Block{{}}
+	}
+	for i := range d.Blocks {
+		b := &d.Blocks[i]
+		b.setCells(b.cells())
+		if b.Kind == Heading {
+			b.Level = max(1, min(b.Level, 3))
+		} else if !b.Kind.IsList() {
+			b.Level = 0
+		}
+	}
+}
+
+// FormatAt returns the format of the character before p, which text typed
+// at p takes on. At the start of a block it is the format of the first
+// character.
+func (d *Document) FormatAt(p Pos) (style Style, link string) {
+	p = d.Clamp(p)
+	cs := d.Blocks[p.Block].cells()
+	switch {
+	case p.Offset > 0:
+		c := cs[p.Offset-1]
+		return c.style, c.link
+	case len(cs) > 0:
+		return cs[0].style, cs[0].link
+	}
+	return 0, ""
+}
+
+// Insert inserts text at p in the given format and returns the position
+// after it. Newlines in text start new blocks of the same kind.
+func (d *Document) Insert(p Pos, text string, style Style, link string) Pos {
+	for i, line := range strings.Split(text, "\n") {
+		if i > 0 {
+			p = d.Split(p)
+		}
+		var ins []cell
+		for _, r := range line {
+			ins = append(ins, cell{r: r, style: style, link: link})
+		}
+		p = d.insertCells(p, ins)
+	}
+	return p
+}
+
+// InsertImage inserts img at p and returns the position after it.
+func (d *Document) InsertImage(p Pos, img *Image, link string) Pos {
+	return d.insertCells(p, []cell{{img: img, link: link}})
+}
+
+func (d *Document) insertCells(p Pos, ins []cell) Pos {
+	p = d.Clamp(p)
+	b := &d.Blocks[p.Block]
+	b.setCells(slices.Insert(b.cells(), p.Offset, ins...))
+	return Pos{p.Block, p.Offset + len(ins)}
+}
+
+// Split breaks the block at p in two and returns the start of the second.
+// The new block continues a list; after a heading it is a paragraph.
+func (d *Document) Split(p Pos) Pos {
+	p = d.Clamp(p)
+	b := &d.Blocks[p.Block]
+	cs := b.cells()
+	next := Block{Kind: b.Kind, Level: b.Level}
+	if next.Kind == Heading {
+		next.Kind, next.Level = Paragraph, 0
+	}
+	next.setCells(slices.Clone(cs[p.Offset:]))
+	b.setCells(cs[:p.Offset])
+	d.Blocks = slices.Insert(d.Blocks, p.Block+1, next)
+	return Pos{p.Block + 1, 0}
+}
+
+// Delete removes the content between from and to, joining the blocks at
+// either end. It returns the position the range collapsed to.
+func (d *Document) Delete(from, to Pos) Pos {
+	from, to = d.order(from, to)
+	first := d.Blocks[from.Block].cells()
+	last := d.Blocks[to.Block].cells()
+	cs := append(slices.Clone(first[:from.Offset]), last[to.Offset:]...)
+	d.Blocks[from.Block].setCells(cs)
+	d.Blocks = slices.Delete(d.Blocks, from.Block+1, to.Block+1)
+	return from
+}
+
+// Slice returns a copy of the content between from and to.
+func (d *Document) Slice(from, to Pos) *Document {
+	from, to = d.order(from, to)
+	out := &Document{}
+	for i := from.Block; i <= to.Block; i++ {
+		b := d.Blocks[i]
+		cs := b.cells()
+		start, end := 0, len(cs)
+		if i == from.Block {
+			start = from.Offset
+		}
+		if i == to.Block {
+			end = to.Offset
+		}
+		b.setCells(slices.Clone(cs[start:end]))
+		out.Blocks = append(out.Blocks, b)
+	}
+	out.normalize()
+	return out
+}
+
+// format applies fn to the format of every position between from and to.
+func (d *Document) format(from, to Pos, fn func(*cell)) {
+	from, to = d.order(from, to)
+	for i := from.Block; i <= to.Block; i++ {
+		b := &d.Blocks[i]
+		cs := b.cells()
+		start, end := 0, len(cs)
+		if i == from.Block {
+			start = from.Offset
+		}
+		if i == to.Block {
+			end = to.Offset
+		}
+		for j := start; j < end; j++ {
+			fn(&cs[j])
+		}
+		b.setCells(cs)
+	}
+}
+
+// HasStyle reports whether every character between from and to has the
+// formats in s. An empty range has them if the character before it does.
+func (d *Document) HasStyle(from, to Pos, s Style) bool {
+	from, to = d.order(from, to)
+	if from == to {
+		st, _ := d.FormatAt(from)
+		return st.Has(s)
+	}
+	all := true
+	d.format(from, to, func(c *cell) { all = all && c.style.Has(s) })
+	return all
+}
+
+// ToggleStyle removes the formats in s from the range between from and to
+// if it has them throughout, and adds them otherwise.
+func (d *Document) ToggleStyle(from, to Pos, s Style) {
+	on := !d.HasStyle(from, to, s)
+	d.format(from, to, func(c *cell) {
+		if on {
+			c.style |= s
+		} else {
+			c.style &^= s
+		}
+	})
+}
+
+// SetLink links the range between from and to to url, or unlinks it if
+// url is empty.
+func (d *Document) SetLink(from, to Pos, url string) {
+	d.format(from, to, func(c *cell) { c.link = url })
+}
+
+// LinkAt returns the URL of the character at p, if it is linked.
+func (d *Document) LinkAt(p Pos) string {
+	p = d.Clamp(p)
+	cs := d.Blocks[p.Block].cells()
+	if p.Offset < len(cs) {
+		return cs[p.Offset].link
+	}
+	return ""
+}
+
+// SetKind changes the blocks from first to last, inclusive, to kind at
+// level.
+func (d *Document) SetKind(first, last int, kind BlockKind, level int) {
+	first = max(0, first)
+	last = min(last, len(d.Blocks)-1)
+	for i := first; i <= last; i++ {
+		d.Blocks[i].Kind, d.Blocks[i].Level = kind, level
+	}
+	d.normalize()
+}
@@ -0,0 +1,246 @@
+package richtext
+
+import (
+	"image"
+	"slices"
+	"testing"
+)
+
+func text(s string, st Style) Span { return Span{Text: s, Style: st} }
+
+func TestNew(t *testing.T) {
+	d := New(
+		Block{Kind: Heading, Level: 9, Spans: []Span{text("a", Bold), text("b", Bold), text("", Italic)}},
+		Block{Kind: Paragraph, Level: 2},
+	)
+	if h := d.Blocks[0]; h.Level != 3 || !slices.Equal(h.Spans, []Span{text("ab", Bold)}) {
+		t.Errorf("heading %+v, want level 3 with the spans merged", h)
+	}
+	if d.Blocks[1].Level != 0 {
+		t.Errorf("paragraph at level %d", d.Blocks[1].Level)
+	}
+	if n := len(New().Blocks); n != 1 {
+		t.Errorf("an empty document has %d blocks, want 1", n)
+	}
+}
+
+func TestFromText(t *testing.T) {
+	d := FromText("one\n\nthree")
+	if len(d.Blocks) != 3 || d.PlainText() != "one\n\nthree" {
+		t.Errorf("%d blocks reading %q", len(d.Blocks), d.PlainText())
+	}
+	if end := d.End(); end != (Pos{2, 5}) {
+		t.Errorf("End() = %v", end)
+	}
+}
+
+func TestClone(t *testing.T) {
+	d := FromText("abc")
+	c := d.Clone()
+	c.Insert(Pos{0, 1}, "x", Bold, "")
+	if d.PlainText() != "abc" || c.PlainText() != "axbc" {
+		t.Errorf("editing the clone changed the original: %q, %q", d.PlainText(), c.PlainText())
+	}
+}
+
+func TestInsert(t *testing.T) {
+	tests := []struct {
+		name  string
+		at    Pos
+		text  string
+		want  string
+		after Pos
+	}{
+		{"middle", Pos{0, 2}, "XY", "heXYllo\nworld", Pos{0, 4}},
+		{"newline", Pos{0, 2}, "1\n2", "he1\n2llo\nworld", Pos{1, 1}},
+		{"clamped", Pos{5, 99}, "!", "hello\nworld!", Pos{1, 6}},
+	}
+	for _, tt := range tests {
+		d := FromText("hello\nworld")
+		if p := d.Insert(tt.at, tt.text, 0, ""); p != tt.after || d.PlainText() != tt.want {
+			t.Errorf("%s: %q ending at %v, want %q at %v", tt.name, d.PlainText(), p, tt.want, tt.after)
+		}
+	}
+
+	d := FromText("ab")
+	d.Insert(Pos{0, 1}, "X", Bold, "http://x")
+	want := []Span{text("a", 0), {Text: "X", Style: Bold, Link: "http://x"}, text("b", 0)}
+	if !slices.Equal(d.Blocks[0].Spans, want) {
+		t.Errorf("spans %+v, want %+v", d.Blocks[0].Spans, want)
+	}
+	img := &Image{Alt: "cat"}
+	if p := d.InsertImage(Pos{0, 0}, img, ""); p != (Pos{0, 1}) || d.Blocks[0].Len() != 4 || d.PlainText() != "cataXb" {
+		t.Errorf("an image at %v holds %q", p, d.PlainText())
+	}
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		kind     BlockKind
+		level    int
+		wantKind BlockKind
+		wantLvl  int
+	}{
+		{Paragraph, 0, Paragraph, 0},
+		{Heading, 2, Paragraph, 0},
+		{BulletItem, 1, BulletItem, 1},
+		{NumberedItem, 0, NumberedItem, 0},
+	}
+	for _, tt := range tests {
+		d := New(Block{Kind: tt.kind, Level: tt.level, Spans: []Span{text("abcd", Italic)}})
+		if p := d.Split(Pos{0, 1}); p != (Pos{1, 0}) {
+			t.Errorf("Split returned %v", p)
+		}
+		next := d.Blocks[1]
+		if next.Kind != tt.wantKind || next.Level != tt.wantLvl || d.Blocks[0].Kind != tt.kind {
+			t.Errorf("splitting kind %v gave %v at level %d", tt.kind, next.Kind, next.Level)
+		}
+		if d.PlainText() != "a\nbcd" || !d.HasStyle(Pos{1, 0}, Pos{1, 3}, Italic) {
+			t.Errorf("split into %q", d.PlainText())
+		}
+	}
+}
+
+func TestDelete(t *testing.T) {
+	tests := []struct {
+		from, to Pos
+		want     string
+	}{
+		{Pos{0, 1}, Pos{0, 3}, "hlo\nxy\n123"},
+		{Pos{1, 1}, Pos{0, 3}, "hely\n123"},
+		{Pos{0, 5}, Pos{1, 0}, "helloxy\n123"},
+		{Pos{0, 2}, Pos{2, 1}, "he23"},
+		{Pos{1, 1}, Pos{1, 1}, "hello\nxy\n123"},
+	}
+	for _, tt := range tests {
+		d := FromText("hello\nxy\n123")
+		p := d.Delete(tt.from, tt.to)
+		if d.PlainText() != tt.want {
+			t.Errorf("Delete(%v, %v) left %q, want %q", tt.from, tt.to, d.PlainText(), tt.want)
+		}
+		want := tt.from
+		if tt.to.Less(want) {
+			want = tt.to
+		}
+		if p != want {
+			t.Errorf("Delete(%v, %v) = %v", tt.from, tt.to, p)
+		}
+	}
+}
+
+func TestSlice(t *testing.T) {
+	d := New(
+		Block{Kind: Heading, Level: 1, Spans: []Span{text("Title", Bold)}},
+		Block{Kind: BulletItem, Spans: []Span{text("item", 0)}},
+	)
+	s := d.Slice(Pos{1, 2}, Pos{0, 2})
+	if s.PlainText() != "tle\nit" || s.Blocks[0].Kind != Heading || s.Blocks[1].Kind != BulletItem {
+		t.Errorf("Slice gave %q as %+v", s.PlainText(), s.Blocks)
+	}
+	if !s.HasStyle(s.Start(), Pos{0, 3}, Bold) {
+		t.Error("the slice lost the formats")
+	}
+	if d.PlainText() != "Title\nitem" {
+		t.Errorf("Slice changed the document to %q", d.PlainText())
+	}
+}
+
+func TestToggleStyle(t *testing.T) {
+	d := New(Block{Spans: []Span{text("ab", Bold), text("cd", 0)}})
+	tests := []struct {
+		from, to Pos
+		style    Style
+		has      bool
+		spans    []Span
+	}{
+		{Pos{0, 0}, Pos{0, 4}, Bold, false, []Span{text("abcd", Bold)}},
+		{Pos{0, 0}, Pos{0, 4}, Bold, true, []Span{text("abcd", 0)}},
+		{Pos{0, 1}, Pos{0, 3}, Italic | Underline, false, []Span{text("a", 0), text("bc", Italic|Underline), text("d", 0)}},
+	}
+	for i, tt := range tests {
+		if has := d.HasStyle(tt.from, tt.to, tt.style); has != tt.has {
+			t.Errorf("%d: HasStyle = %v", i, has)
+		}
+		d.ToggleStyle(tt.from, tt.to, tt.style)
+		if !slices.Equal(d.Blocks[0].Spans, tt.spans) {
+			t.Errorf("%d: spans %+v, want %+v", i, d.Blocks[0].Spans, tt.spans)
+		}
+	}
+	// An empty range has the format of the character before it.
+	if !d.HasStyle(Pos{0, 2}, Pos{0, 2}, Italic) || d.HasStyle(Pos{0, 1}, Pos{0, 1}, Italic) {
+		t.Error("HasStyle of an empty range does not follow the character before it")
+	}
+}
+
+func TestFormatAt(t *testing.T) {
+	d := New(Block{Spans: []Span{{Text: "ab", Style: Bold, Link: "u"}, text("c", Italic)}}, Block{})
+	tests := []struct {
+		at    Pos
+		style Style
+		link  string
+	}{
+		{Pos{0, 0}, Bold, "u"},
+		{Pos{0, 2}, Bold, "u"},
+		{Pos{0, 3}, Italic, ""},
+		{Pos{1, 0}, 0, ""},
+	}
+	for _, tt := range tests {
+		if s, l := d.FormatAt(tt.at); s != tt.style || l != tt.link {
+			t.Errorf("FormatAt(%v) = %v, %q, want %v, %q", tt.at, s, l, tt.style, tt.link)
+		}
+	}
+}
+
+func TestLinks(t *testing.T) {
+	d := FromText("see here now")
+	d.SetLink(Pos{0, 4}, Pos{0, 8}, "http://x")
+	for off, want := range map[int]string{3: "", 4: "http://x", 7: "http://x", 8: "", 12: ""} {
+		if got := d.LinkAt(Pos{0, off}); got != want {
+			t.Errorf("LinkAt(%d) = %q, want %q", off, got, want)
+		}
+	}
+	d.SetLink(Pos{0, 0}, Pos{0, 12}, "")
+	if n := len(d.Blocks[0].Spans); n != 1 {
+		t.Errorf("%d spans after unlinking everything", n)
+	}
+}
+
+func TestSetKind(t *testing.T) {
+	d := FromText("a\nb\nc")
+	d.SetKind(-1, 1, Heading, 0)
+	d.SetKind(2, 9, BulletItem, 2)
+	want := []struct {
+		kind  BlockKind
+		level int
+	}{{Heading, 1}, {Heading, 1}, {BulletItem, 2}}
+	for i, w := range want {
+		if b := d.Blocks[i]; b.Kind != w.kind || b.Level != w.level {
+			t.Errorf("block %d: %v at level %d, want %v at %d", i, b.Kind, b.Level, w.kind, w.level)
+		}
+	}
+	d.SetKind(2, 2, Paragraph, 2)
+	if d.Blocks[2].Level != 0 {
+		t.Error("a paragraph kept a level")
+	}
+}
+
+func TestImageSize(t *testing.T) {
+	data := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	tests := []struct {
+		name string
+		img  Image
+		w, h float32
+	}{
+		{"set", Image{Width: 10, Height: 5, Data: data}, 10, 5},
+		{"from data", Image{Data: data}, 40, 20},
+		{"width from height", Image{Height: 10, Data: data}, 20, 10},
+		{"height from width", Image{Width: 10, Data: data}, 10, 5},
+		{"unknown", Image{}, 20, 20},
+		{"half set", Image{Width: 30}, 20, 20},
+	}
+	for _, tt := range tests {
+		if w, h := tt.img.Size(); w != tt.w || h != tt.h {
+			t.Errorf("%s: %v x %v, want %v x %v", tt.name, w, h, tt.w, tt.h)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package richtext
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// HTML returns the document as an HTML fragment. Headings become h1 to h3,
+// consecutive list items become nested ul and ol lists, and character
+// formats become strong, em, u and code elements.
+func (d *Document) HTML() string {
+	var sb strings.Builder
+	// lists holds the open lists, one per nesting level.
+	var lists []BlockKind
+	closeTo := func(depth int) {
+		for len(lists) > depth {
+			sb.WriteString("</li>")
+			sb.WriteString(listTag(lists[len(lists)-1], true))
+			lists = lists[:len(lists)-1]
+		}
+	}
+	for _, b := range d.Blocks {
+		if !b.Kind.IsList() {
+			closeTo(0)
+			switch b.Kind {
+			case Heading:
+				fmt.Fprintf(&sb, "<h%d>", b.Level)
+				writeHTMLSpans(&sb, b.Spans)
+				fmt.Fprintf(&sb, "</h%d>\n", b.Level)
+			default:
+				sb.WriteString("<p>")
+				writeHTMLSpans(&sb, b.Spans)
+				sb.WriteString("</p>\n")
+			}
+			continue
+		}
+		closeTo(b.Level + 1)
+		if len(lists) == b.Level+1 && lists[b.Level] != b.Kind {
+			closeTo(b.Level)
+		}
+		if len(lists) == b.Level+1 {
+			sb.WriteString("</li>")
+		}
+		for open := len(lists); len(lists) < b.Level+1; {
+			// A list nested more than one level deeper than the previous
+			// item opens empty items to hold it.
+			if len(lists) > open {
+				sb.WriteString("<li>")
+			}
+			kind := b.Kind
+			if len(lists) < b.Level {
+				kind = BulletItem
+			}
+			sb.WriteString(listTag(kind, false))
+			lists = append(lists, kind)
+		}
+		sb.WriteString("<li>")
+		writeHTMLSpans(&sb, b.Spans)
+	}
+	closeTo(0)
+	return sb.String()
+}
+
+func listTag(k BlockKind, end bool) string {
+	tag := "ul"
+	if k == NumberedItem {
+		tag = "ol"
+	}
+	if end {
+		return "</" + tag + ">\n"
+	}
+	return "<" + tag + ">"
+}
+
+func writeHTMLSpans(sb *strings.Builder, spans []Span) {
+	for _, s := range spans {
+		if s.Link != "" {
+			fmt.Fprintf(sb, `<a href="%s">`, html.EscapeString(s.Link))
+		}
+		open := [...]struct {
+			s   Style
+			tag string
+		}{{Bold, "strong"}, {Italic, "em"}, {Underline, "u"}, {Code, "code"}}
+		for _, o := range open {
+			if s.Style.Has(o.s) {
+				sb.WriteString("<" + o.tag + ">")
+			}
+		}
+		if s.Image != nil {
+			fmt.Fprintf(sb, `<img src="%s" alt="%s"`, html.EscapeString(s.Image.Src), html.EscapeString(s.Image.Alt))
+			if s.Image.Width > 0 && s.Image.Height > 0 {
+				fmt.Fprintf(sb, ` width="%g" height="%g"`, s.Image.Width, s.Image.Height)
+			}
+			sb.WriteString(">")
+		} else {
+			sb.WriteString(html.EscapeString(s.Text))
+		}
+		for i := len(open) - 1; i >= 0; i-- {
+			if s.Style.Has(open[i].s) {
+				sb.WriteString("</" + open[i].tag + ">")
+			}
+		}
+		if s.Link != "" {
+			sb.WriteString("</a>")
+		}
+	}
+}
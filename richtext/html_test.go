@@ -0,0 +1,59 @@
+package richtext
+
+import "testing"
+
+func TestHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  *Document
+		want string
+	}{
+		{
+			"blocks",
+			New(
+				Block{Kind: Heading, Level: 2, Spans: []Span{{Text: "Notes"}}},
+				Block{Spans: []Span{{Text: "a < b"}}},
+			),
+			"<h2>Notes</h2>\n<p>a &lt; b</p>\n",
+		},
+		{
+			"formats",
+			New(Block{Spans: []Span{
+				{Text: "x", Style: Bold | Italic | Underline | Code},
+				{Text: "go", Link: `http://x?a="1"`},
+			}}),
+			`<p><strong><em><u><code>x</code></u></em></strong><a href="http://x?a=&#34;1&#34;">go</a></p>` + "\n",
+		},
+		{
+			"image",
+			New(Block{Spans: []Span{
+				{Image: &Image{Src: "cat.png", Alt: "a cat", Width: 20, Height: 10}},
+				{Image: &Image{Src: "dog.png"}},
+			}}),
+			`<p><img src="cat.png" alt="a cat" width="20" height="10"><img src="dog.png" alt=""></p>` + "\n",
+		},
+		{
+			"nested lists",
+			New(
+				Block{Kind: BulletItem, Spans: []Span{{Text: "a"}}},
+				Block{Kind: BulletItem, Level: 1, Spans: []Span{{Text: "b"}}},
+				Block{Kind: NumberedItem, Spans: []Span{{Text: "c"}}},
+				Block{Spans: []Span{{Text: "d"}}},
+			),
+			"<ul><li>a<ul><li>b</li></ul>\n</li></ul>\n<ol><li>c</li></ol>\n<p>d</p>\n",
+		},
+		{
+			"skipped level",
+			New(
+				Block{Kind: BulletItem, Spans: []Span{{Text: "a"}}},
+				Block{Kind: NumberedItem, Level: 2, Spans: []Span{{Text: "b"}}},
+			),
+			"<ul><li>a<ul><li><ol><li>b</li></ol>\n</li></ul>\n</li></ul>\n",
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.doc.HTML(); got != tt.want {
+			t.Errorf("%s:\n got %q\nwant %q", tt.name, got, tt.want)
+		}
+	}
+}
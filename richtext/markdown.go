@@ -0,0 +1,176 @@
+package richtext
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Markdown returns the document as CommonMark. Bold and italic text become
+// ** and * emphasis; Markdown has no underline, so underlined text is
+// written as an inline u element, and code as a backtick code span.
+// Characters that Markdown would read as syntax are escaped.
+func (d *Document) Markdown() string {
+	var sb strings.Builder
+	// indents holds the content column of each open list level, and
+	// numbers the next number of each numbered level.
+	var indents []string
+	var numbers []int
+	prevList := false
+	for i, b := range d.Blocks {
+		if !b.Kind.IsList() {
+			indents, numbers = indents[:0], numbers[:0]
+			if i > 0 {
+				sb.WriteString("\n")
+			}
+			if b.Kind == Heading {
+				sb.WriteString(strings.Repeat("#", b.Level) + " ")
+			}
+			sb.WriteString(markdownSpans(b.Spans))
+			sb.WriteString("\n")
+			prevList = false
+			continue
+		}
+		if i > 0 && !prevList {
+			sb.WriteString("\n")
+		}
+		prevList = true
+		level := min(b.Level, len(indents))
+		for len(numbers) > level+1 {
+			indents, numbers = indents[:len(indents)-1], numbers[:len(numbers)-1]
+		}
+		if len(numbers) == level+1 && (numbers[level] < 0) != (b.Kind == BulletItem) {
+			// The kind of list changed at this level: start a new one.
+			indents, numbers = indents[:level], numbers[:level]
+		}
+		if len(numbers) == level {
+			n := 1
+			if b.Kind == BulletItem {
+				n = -1
+			}
+			numbers = append(numbers, n)
+			indents = append(indents, "")
+		}
+		prefix := strings.Join(indents[:level], "")
+		marker := "- "
+		if numbers[level] > 0 {
+			marker = strconv.Itoa(numbers[level]) + ". "
+			numbers[level]++
+		}
+		indents[level] = strings.Repeat(" ", len(marker))
+		sb.WriteString(prefix + marker)
+		sb.WriteString(markdownSpans(b.Spans))
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// markdownSpans returns the inline Markdown of a block.
+func markdownSpans(spans []Span) string {
+	var sb strings.Builder
+	for _, s := range spans {
+		if s.Image != nil {
+			text := "![" + escapeMarkdown(s.Image.Alt) + "](" + markdownURL(s.Image.Src) + ")"
+			if s.Link != "" {
+				text = "[" + text + "](" + markdownURL(s.Link) + ")"
+			}
+			sb.WriteString(text)
+			continue
+		}
+		// Emphasis markers must touch the text, so surrounding spaces go
+		// outside them.
+		text := s.Text
+		lead := text[:len(text)-len(strings.TrimLeftFunc(text, unicode.IsSpace))]
+		text = text[len(lead):]
+		body := strings.TrimRightFunc(text, unicode.IsSpace)
+		trail := text[len(body):]
+		if body == "" {
+			sb.WriteString(lead + trail)
+			continue
+		}
+		if s.Style.Has(Code) {
+			body = codeSpan(body)
+		} else {
+			body = escapeMarkdown(body)
+		}
+		if s.Style.Has(Underline) {
+			body = "<u>" + body + "</u>"
+		}
+		if s.Style.Has(Italic) {
+			body = "*" + body + "*"
+		}
+		if s.Style.Has(Bold) {
+			body = "**" + body + "**"
+		}
+		if s.Link != "" {
+			body = "[" + body + "](" + markdownURL(s.Link) + ")"
+		}
+		sb.WriteString(lead + body + trail)
+	}
+	line := sb.String()
+	if i := blockMarker(line); i >= 0 {
+		line = line[:i] + "\\" + line[i:]
+	}
+	return line
+}
+
+// escapeMarkdown escapes the characters of s that are Markdown syntax
+// inside a line.
+func escapeMarkdown(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune("\\`*_[]<>!", r) {
+			sb.WriteByte('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// codeSpan returns s as a code span, fenced by one backtick more than the
+// longest run of backticks inside it.
+func codeSpan(s string) string {
+	longest, run := 0, 0
+	for _, r := range s {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	fence := strings.Repeat("`", longest+1)
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		s = " " + s + " "
+	}
+	return fence + s + fence
+}
+
+// blockMarker returns where to escape text at the start of a line that
+// would read as a heading, quote or list item, or -1 if it would not.
+func blockMarker(line string) int {
+	if line == "" {
+		return -1
+	}
+	switch line[0] {
+	case '#', '>', '-', '+', '=':
+		return 0
+	}
+	i := 0
+	for i < len(line) && line[i] >= '0' && line[i] <= '9' {
+		i++
+	}
+	if i > 0 && i < len(line) && (line[i] == '.' || line[i] == ')') {
+		return i
+	}
+	return -1
+}
+
+// markdownURL writes url as a link destination, in angle brackets if it
+// contains spaces or parentheses.
+func markdownURL(url string) string {
+	if strings.ContainsAny(url, " ()<>") {
+		return "<" + strings.NewReplacer("<", "%3C", ">", "%3E").Replace(url) + ">"
+	}
+	return url
+}
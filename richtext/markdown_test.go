@@ -0,0 +1,53 @@
+package richtext
+
+import "testing"
+
+func TestMarkdown(t *testing.T) {
+	para := func(spans ...Span) *Document { return New(Block{Spans: spans}) }
+	tests := []struct {
+		name string
+		doc  *Document
+		want string
+	}{
+		{
+			"blocks",
+			New(
+				Block{Kind: Heading, Level: 1, Spans: []Span{{Text: "Notes"}}},
+				Block{Spans: []Span{{Text: "Ship it", Style: Bold}}},
+			),
+			"# Notes\n\n**Ship it**\n",
+		},
+		{"spaces outside emphasis", para(Span{Text: "a"}, Span{Text: " hi ", Style: Italic}, Span{Text: "b"}), "a *hi* b\n"},
+		{"underline", para(Span{Text: "u", Style: Underline | Bold}), "**<u>u</u>**\n"},
+		{"escaped", para(Span{Text: "a*b_[c]"}), `a\*b\_\[c\]` + "\n"},
+		{"heading marker", para(Span{Text: "# not a heading"}), `\# not a heading` + "\n"},
+		{"number marker", para(Span{Text: "1. not a list"}), `1\. not a list` + "\n"},
+		{"code", para(Span{Text: "a*b", Style: Code}), "`a*b`\n"},
+		{"code with backticks", para(Span{Text: "`x``", Style: Code}), "``` `x`` ```\n"},
+		{"link", para(Span{Text: "go", Link: "http://x"}), "[go](http://x)\n"},
+		{"link with spaces", para(Span{Text: "go", Link: "a b<c>"}), "[go](<a b%3Cc%3E>)\n"},
+		{"image", para(Span{Image: &Image{Src: "c.png", Alt: "cat!"}, Link: "http://x"}), `[![cat\!](c.png)](http://x)` + "\n"},
+		{
+			"lists",
+			New(
+				Block{Spans: []Span{{Text: "p"}}},
+				Block{Kind: BulletItem, Spans: []Span{{Text: "a"}}},
+				Block{Kind: BulletItem, Level: 1, Spans: []Span{{Text: "b"}}},
+				Block{Kind: NumberedItem, Level: 2, Spans: []Span{{Text: "c"}}},
+				Block{Kind: NumberedItem, Spans: []Span{{Text: "d"}}},
+				Block{Kind: NumberedItem, Spans: []Span{{Text: "e"}}},
+			),
+			"p\n\n- a\n  - b\n    1. c\n1. d\n2. e\n",
+		},
+		{
+			"list too deep",
+			New(Block{Kind: BulletItem, Level: 3, Spans: []Span{{Text: "a"}}}),
+			"- a\n",
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.doc.Markdown(); got != tt.want {
+			t.Errorf("%s:\n got %q\nwant %q", tt.name, got, tt.want)
+		}
+	}
+}
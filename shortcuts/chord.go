@@ -0,0 +1,149 @@
+package shortcuts
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/gogpu/ui/event"
+)
+
+// mac is set on the platforms whose primary modifier is Command.
+var mac = runtime.GOOS == "darwin" || runtime.GOOS == "ios"
+
+// Chord is a key pressed with a set of modifiers.
+type Chord struct {
+	Key       event.Key
+	Modifiers event.Modifiers
+}
+
+// Primary returns the primary modifier of the platform, which "Mod"
+// stands for: event.ModSuper (Command) on macOS and event.ModCtrl
+// elsewhere.
+func Primary() event.Modifiers {
+	if mac {
+		return event.ModSuper
+	}
+	return event.ModCtrl
+}
+
+// modifierNames maps the lower-cased modifier names Parse accepts to
+// their modifiers; "mod" is missing as it depends on the platform.
+var modifierNames = map[string]event.Modifiers{
+	"ctrl": event.ModCtrl, "control": event.ModCtrl, "⌃": event.ModCtrl,
+	"alt": event.ModAlt, "option": event.ModAlt, "opt": event.ModAlt, "⌥": event.ModAlt,
+	"shift": event.ModShift, "⇧": event.ModShift,
+	"super": event.ModSuper, "cmd": event.ModSuper, "command": event.ModSuper,
+	"meta": event.ModSuper, "win": event.ModSuper, "⌘": event.ModSuper,
+}
+
+// keyNames maps the lower-cased names of keys, as event.Key.String
+// returns them, and a few aliases to the keys.
+var keyNames = func() map[string]event.Key {
+	m := map[string]event.Key{
+		"esc": event.KeyEscape, "return": event.KeyEnter, "del": event.KeyDelete,
+		"ins": event.KeyInsert, "pgup": event.KeyPageUp, "pgdn": event.KeyPageDown,
+	}
+	for k := event.KeyA; k < event.KeyShift; k++ {
+		m[strings.ToLower(k.String())] = k
+	}
+	m["menu"] = event.KeyMenu
+	return m
+}()
+
+// Parse returns the chord written as the names of its modifiers and key
+// joined with "+", in any case and order: "Ctrl+Shift+P", "mod+s",
+// "Alt+F4" or "Cmd+,". "Mod" and "CmdOrCtrl" stand for the Primary
+// modifier.
+func Parse(s string) (Chord, error) {
+	var c Chord
+	parts := strings.Split(strings.TrimSpace(s), "+")
+	for i, part := range parts {
+		name := strings.ToLower(strings.TrimSpace(part))
+		if i < len(parts)-1 {
+			switch m, ok := modifierNames[name]; {
+			case ok:
+				c.Modifiers |= m
+			case name == "mod" || name == "cmdorctrl":
+				c.Modifiers |= Primary()
+			default:
+				return Chord{}, fmt.Errorf("shortcuts: unknown modifier %q in %q", part, s)
+			}
+			continue
+		}
+		k, ok := keyNames[name]
+		if !ok {
+			if _, mod := modifierNames[name]; mod || name == "mod" {
+				return Chord{}, fmt.Errorf("shortcuts: no key in %q", s)
+			}
+			return Chord{}, fmt.Errorf("shortcuts: unknown key %q in %q", part, s)
+		}
+		c.Key = k
+	}
+	return c, nil
+}
+
+// MustParse is like Parse but panics on error, for chords written in the
+// program.
+func MustParse(s string) Chord {
+	c, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// ChordOf returns the chord pressed by ev.
+func ChordOf(ev event.KeyEvent) Chord {
+	return Chord{Key: ev.Key, Modifiers: ev.Modifiers}
+}
+
+// Matches reports whether ev is a press of the chord.
+func (c Chord) Matches(ev event.KeyEvent) bool {
+	return ev.Type == event.KeyPress && ChordOf(ev) == c
+}
+
+// String returns the chord as Parse reads it, with the modifiers in the
+// order of event.Modifiers.String, such as "Ctrl+Shift+P".
+func (c Chord) String() string {
+	if c.Modifiers == 0 {
+		return c.Key.String()
+	}
+	return c.Modifiers.String() + "+" + c.Key.String()
+}
+
+// macKeys are the symbols macOS menus show for keys.
+var macKeys = map[event.Key]string{
+	event.KeyEnter: "↩", event.KeyEscape: "⎋", event.KeyTab: "⇥",
+	event.KeyBackspace: "⌫", event.KeyDelete: "⌦", event.KeySpace: "Space",
+	event.KeyLeft: "←", event.KeyRight: "→", event.KeyUp: "↑", event.KeyDown: "↓",
+	event.KeyHome: "↖", event.KeyEnd: "↘", event.KeyPageUp: "⇞", event.KeyPageDown: "⇟",
+}
+
+// Label returns the chord as the platform displays it: with the symbols
+// of the modifiers in the order of macOS menus on a Mac, such as "⇧⌘P",
+// and as String elsewhere, with Super shown as "Win" on Windows.
+func (c Chord) Label() string {
+	if !mac {
+		s := c.String()
+		if runtime.GOOS == "windows" && c.Modifiers.Has(event.ModSuper) {
+			s = strings.Replace(s, "Super", "Win", 1)
+		}
+		return s
+	}
+	var b strings.Builder
+	for _, m := range []struct {
+		mod    event.Modifiers
+		symbol string
+	}{{event.ModCtrl, "⌃"}, {event.ModAlt, "⌥"}, {event.ModShift, "⇧"}, {event.ModSuper, "⌘"}} {
+		if c.Modifiers.Has(m.mod) {
+			b.WriteString(m.symbol)
+		}
+	}
+	if s, ok := macKeys[c.Key]; ok {
+		b.WriteString(s)
+	} else {
+		b.WriteString(c.Key.String())
+	}
+	return b.String()
+}
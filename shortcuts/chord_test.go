@@ -0,0 +1,111 @@
+package shortcuts
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/gogpu/ui/event"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Chord
+		err  string // A part of the error, or empty for none.
+	}{
+		{in: "Ctrl+Shift+P", want: Chord{event.KeyP, event.ModCtrl | event.ModShift}},
+		{in: "shift+ctrl+p", want: Chord{event.KeyP, event.ModCtrl | event.ModShift}},
+		{in: "mod+s", want: Chord{event.KeyS, Primary()}},
+		{in: "CmdOrCtrl+S", want: Chord{event.KeyS, Primary()}},
+		{in: "Alt+F4", want: Chord{event.KeyF4, event.ModAlt}},
+		{in: "Cmd+,", want: Chord{event.KeyComma, event.ModSuper}},
+		{in: "⌥+Enter", want: Chord{event.KeyEnter, event.ModAlt}},
+		{in: "esc", want: Chord{Key: event.KeyEscape}},
+		{in: "  ctrl + return ", want: Chord{event.KeyEnter, event.ModCtrl}},
+		{in: "Hyper+A", err: "unknown modifier"},
+		{in: "Ctrl+Shift", err: "no key"},
+		{in: "Mod", err: "no key"},
+		{in: "Ctrl+Foo", err: "unknown key"},
+		{in: "", err: "unknown key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Parse(tt.in)
+			switch {
+			case tt.err != "":
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("Parse = %v, %v, want an error with %q", got, err, tt.err)
+				}
+			case err != nil:
+				t.Errorf("Parse: %v", err)
+			case got != tt.want:
+				t.Errorf("Parse = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChordString(t *testing.T) {
+	tests := []struct {
+		c    Chord
+		want string
+	}{
+		{Chord{Key: event.KeyEscape}, "Escape"},
+		{Chord{event.KeyP, event.ModShift | event.ModCtrl}, "Ctrl+Shift+P"},
+		{Chord{event.KeyComma, event.ModSuper | event.ModAlt}, "Alt+Super+,"},
+	}
+	for _, tt := range tests {
+		got := tt.c.String()
+		if got != tt.want {
+			t.Errorf("String = %q, want %q", got, tt.want)
+		}
+		// Parse reads back what String writes.
+		if c, err := Parse(got); err != nil || c != tt.c {
+			t.Errorf("Parse(%q) = %v, %v, want %v", got, c, err, tt.c)
+		}
+	}
+}
+
+func TestMustParse(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("MustParse of no key did not panic")
+		}
+	}()
+	MustParse("Ctrl+")
+}
+
+func TestMatches(t *testing.T) {
+	c := MustParse("Ctrl+S")
+	tests := []struct {
+		name string
+		ev   event.KeyEvent
+		want bool
+	}{
+		{"press", event.KeyEvent{Type: event.KeyPress, Key: event.KeyS, Modifiers: event.ModCtrl}, true},
+		{"release", event.KeyEvent{Type: event.KeyRelease, Key: event.KeyS, Modifiers: event.ModCtrl}, false},
+		{"more modifiers", event.KeyEvent{Type: event.KeyPress, Key: event.KeyS, Modifiers: event.ModCtrl | event.ModShift}, false},
+		{"no modifiers", event.KeyEvent{Type: event.KeyPress, Key: event.KeyS}, false},
+		{"other key", event.KeyEvent{Type: event.KeyPress, Key: event.KeyD, Modifiers: event.ModCtrl}, false},
+	}
+	for _, tt := range tests {
+		if got := c.Matches(tt.ev); got != tt.want {
+			t.Errorf("%s: Matches = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLabel(t *testing.T) {
+	c := Chord{event.KeyEnter, event.ModSuper | event.ModShift}
+	want := "Shift+Super+Enter"
+	switch {
+	case mac:
+		want = "⇧⌘↩"
+	case runtime.GOOS == "windows":
+		want = "Shift+Win+Enter"
+	}
+	if got := c.Label(); got != want {
+		t.Errorf("Label = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,31 @@
+// Package shortcuts binds key chords such as Ctrl+Shift+P to commands,
+// resolves them in the scope of the focused widget, the window and the
+// application, and provides the labels menus and tooltips show for them.
+//
+// A [Registry] maps chords to command IDs and IDs to what they run. Each
+// window has one, falling back to the one of its App, and a [Scope] gives
+// a part of the tree bindings and commands of its own:
+//
+//	app.Shortcuts().
+//		Bind("Mod+S", "file.save").Handle("file.save", save).
+//		Bind("Mod+=", "view.zoomIn").Handle("view.zoomIn", zoomIn)
+//	editor := shortcuts.NewScope(view, shortcuts.NewRegistry().
+//		Bind("Mod+D", "edit.duplicate").Handle("edit.duplicate", view.DuplicateLine).
+//		Handle("edit.copy", view.Copy))
+//
+// "Mod" is the primary modifier of the platform: Command on macOS and
+// Ctrl elsewhere, so that one binding reads Ctrl+S on Windows and ⌘S on
+// a Mac. Ctrl, Cmd, Alt (or Option) and Shift name their keys as written.
+//
+// A key press the focused widget does not handle, such as a text input
+// editing with Ctrl+A, is resolved from the deepest scope around the
+// focused widget out to the window and the application. The first of
+// these registries binding the chord to a command that one of them
+// handles, again from the deepest, runs it. So an application can bind
+// "edit.copy" once and each editor handle it in its own way. While a
+// modal overlay holds the focus, only the scopes inside it resolve keys.
+//
+// MenuItem and CommandPalette entries and tooltip areas given a command
+// ID show its binding in the window, as [Label] formats it; Conflicts
+// reports chords bound to more than one command.
+package shortcuts
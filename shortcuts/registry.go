@@ -0,0 +1,245 @@
+package shortcuts
+
+import (
+	"slices"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+)
+
+// Registry binds chords to command IDs and commands to what they run.
+// A registry falls back to its parent for the bindings and commands it
+// does not have itself, as the registry of a window does to that of its
+// App. Registries belong to the UI goroutine.
+type Registry struct {
+	parent   *Registry
+	bindings []binding
+	commands map[string]*command
+}
+
+// binding is a chord bound to a command.
+type binding struct {
+	chord Chord
+	id    string
+}
+
+// command is what a registry runs for a command ID.
+type command struct {
+	run     func()
+	enabled func() bool
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{commands: make(map[string]*command)}
+}
+
+type registryKey struct{}
+
+// Of returns the registry of the window of ctx. The window runtime makes
+// it with the context, falling back to the registry of its App.
+func Of(ctx *core.Context) *Registry {
+	if r, ok := ctx.Value(registryKey{}).(*Registry); ok {
+		return r
+	}
+	r := NewRegistry()
+	ctx.SetValue(registryKey{}, r)
+	return r
+}
+
+// Label returns the label of the first chord bound to command id in the
+// registry of the window of ctx, or the empty string if it has none.
+func Label(ctx *core.Context, id string) string {
+	return Of(ctx).Label(id)
+}
+
+// SetParent sets the registry r falls back to, or nil for none.
+func (r *Registry) SetParent(parent *Registry) *Registry {
+	r.parent = parent
+	return r
+}
+
+// Parent returns the registry r falls back to, or nil.
+func (r *Registry) Parent() *Registry {
+	return r.parent
+}
+
+// Bind binds the chord written as keys, as Parse reads it, to command id.
+// It panics if keys is not a chord, as bindings written in the program
+// are; use BindChord for chords read from settings. A chord may be bound
+// to several commands, which are tried in the order they were bound.
+func (r *Registry) Bind(keys, id string) *Registry {
+	return r.BindChord(MustParse(keys), id)
+}
+
+// BindChord binds c to command id.
+func (r *Registry) BindChord(c Chord, id string) *Registry {
+	b := binding{chord: c, id: id}
+	if !slices.Contains(r.bindings, b) {
+		r.bindings = append(r.bindings, b)
+	}
+	return r
+}
+
+// Unbind removes the bindings of command id in r, such as before binding
+// the chords the user chose for it. Those of the parent stay.
+func (r *Registry) Unbind(id string) *Registry {
+	r.bindings = slices.DeleteFunc(r.bindings, func(b binding) bool { return b.id == id })
+	return r
+}
+
+// Handle sets run to perform command id, replacing what r ran for it.
+func (r *Registry) Handle(id string, run func()) *Registry {
+	r.cmd(id).run = run
+	return r
+}
+
+// Enabled sets fn to report whether r handles command id at the moment.
+// While it reports false, the chords of the command are resolved as if
+// r did not handle it.
+func (r *Registry) Enabled(id string, fn func() bool) *Registry {
+	r.cmd(id).enabled = fn
+	return r
+}
+
+func (r *Registry) cmd(id string) *command {
+	c := r.commands[id]
+	if c == nil {
+		c = &command{}
+		r.commands[id] = c
+	}
+	return c
+}
+
+// Chords returns the chords bound to command id in r, or in its parent
+// if r binds none, in the order they were bound.
+func (r *Registry) Chords(id string) []Chord {
+	for ; r != nil; r = r.parent {
+		var chords []Chord
+		for _, b := range r.bindings {
+			if b.id == id {
+				chords = append(chords, b.chord)
+			}
+		}
+		if chords != nil {
+			return chords
+		}
+	}
+	return nil
+}
+
+// Label returns the label of the first chord bound to command id, as
+// Chord.Label formats it, or the empty string if it has none. It suits
+// CommandPalette.ShortcutLookup.
+func (r *Registry) Label(id string) string {
+	if chords := r.Chords(id); len(chords) > 0 {
+		return chords[0].Label()
+	}
+	return ""
+}
+
+// Commands returns the IDs of the commands c is bound to in r, in the
+// order they were bound.
+func (r *Registry) Commands(c Chord) []string {
+	var ids []string
+	for _, b := range r.bindings {
+		if b.chord == c {
+			ids = append(ids, b.id)
+		}
+	}
+	return ids
+}
+
+// Conflict is a chord bound to more than one command.
+type Conflict struct {
+	Chord Chord
+
+	// Commands are those the chord is bound to in the registry, in the
+	// order they were bound. Only the first its registries handle runs,
+	// so more than one is a conflict unless their Enabled functions
+	// exclude each other.
+	Commands []string
+
+	// Shadowed are the other commands the chord is bound to in the
+	// registries the registry falls back to, which it hides.
+	Shadowed []string
+}
+
+// Conflicts returns the chords bound to more than one command in r, or
+// to other commands in its parents than in r, in the order they were
+// first bound.
+func (r *Registry) Conflicts() []Conflict {
+	var conflicts []Conflict
+	var seen []Chord
+	for _, b := range r.bindings {
+		if slices.Contains(seen, b.chord) {
+			continue
+		}
+		seen = append(seen, b.chord)
+		c := Conflict{Chord: b.chord, Commands: r.Commands(b.chord)}
+		for p := r.parent; p != nil; p = p.parent {
+			for _, id := range p.Commands(b.chord) {
+				if !slices.Contains(c.Commands, id) && !slices.Contains(c.Shadowed, id) {
+					c.Shadowed = append(c.Shadowed, id)
+				}
+			}
+		}
+		if len(c.Commands) > 1 || len(c.Shadowed) > 0 {
+			conflicts = append(conflicts, c)
+		}
+	}
+	return conflicts
+}
+
+// Run performs command id with the first of r and its parents handling
+// it and enabled, and reports whether one did.
+func (r *Registry) Run(id string) bool {
+	return run([]*Registry{r}, id)
+}
+
+// Resolve runs the command ev presses the chord of in regs, ordered from
+// the deepest scope out, and reports whether it ran one. The registries
+// fall back to their parents after the last of regs. Each registry,
+// from the first, is asked for the commands it binds the chord to; the
+// first of them handled and enabled in a registry, again from the first,
+// runs. The window runtime resolves the keys the focused widget leaves.
+func Resolve(ev event.KeyEvent, regs ...*Registry) bool {
+	if ev.Type != event.KeyPress {
+		return false
+	}
+	chain := lineage(regs)
+	c := ChordOf(ev)
+	for _, r := range chain {
+		for _, id := range r.Commands(c) {
+			if run(chain, id) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// lineage returns regs each followed by its parents, each registry once.
+func lineage(regs []*Registry) []*Registry {
+	var chain []*Registry
+	for _, r := range regs {
+		for ; r != nil && !slices.Contains(chain, r); r = r.parent {
+			chain = append(chain, r)
+		}
+	}
+	return chain
+}
+
+// run performs command id with the first registry of chain, or of their
+// parents, handling it and enabled.
+func run(chain []*Registry, id string) bool {
+	for _, r := range lineage(chain) {
+		c := r.commands[id]
+		if c == nil || c.run == nil || c.enabled != nil && !c.enabled() {
+			continue
+		}
+		c.run()
+		return true
+	}
+	return false
+}
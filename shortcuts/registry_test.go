@@ -0,0 +1,245 @@
+package shortcuts
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+)
+
+// press returns a press of the chord written as keys.
+func press(keys string) event.KeyEvent {
+	c := MustParse(keys)
+	return event.KeyEvent{Type: event.KeyPress, Key: c.Key, Modifiers: c.Modifiers}
+}
+
+// log records the commands registries run, as "registry:command".
+type log []string
+
+// handle makes r run each of ids by recording it as run by name.
+func (l *log) handle(r *Registry, name string, ids ...string) *Registry {
+	for _, id := range ids {
+		r.Handle(id, func() { *l = append(*l, name+":"+id) })
+	}
+	return r
+}
+
+func TestBindings(t *testing.T) {
+	app := NewRegistry().Bind("Ctrl+S", "save").Bind("Ctrl+Q", "quit")
+	win := NewRegistry().SetParent(app).
+		Bind("Ctrl+S", "save").Bind("Ctrl+S", "save").Bind("Ctrl+Shift+S", "save")
+
+	if got := win.Chords("save"); !slices.Equal(got, []Chord{MustParse("Ctrl+S"), MustParse("Ctrl+Shift+S")}) {
+		t.Errorf("Chords(save) = %v, want each bound once in order", got)
+	}
+	if got := win.Chords("quit"); !slices.Equal(got, []Chord{MustParse("Ctrl+Q")}) {
+		t.Errorf("Chords(quit) = %v, want the parent's", got)
+	}
+	if got := win.Chords("none"); got != nil {
+		t.Errorf("Chords(none) = %v, want none", got)
+	}
+	if got, want := win.Label("quit"), MustParse("Ctrl+Q").Label(); got != want {
+		t.Errorf("Label(quit) = %q, want %q", got, want)
+	}
+	if got := win.Label("none"); got != "" {
+		t.Errorf("Label(none) = %q, want empty", got)
+	}
+
+	win.Unbind("save")
+	if got := win.Chords("save"); !slices.Equal(got, []Chord{MustParse("Ctrl+S")}) {
+		t.Errorf("Chords(save) after Unbind = %v, want the parent's", got)
+	}
+	if got := app.Commands(MustParse("Ctrl+S")); !slices.Equal(got, []string{"save"}) {
+		t.Errorf("parent Commands(Ctrl+S) = %v, want its binding kept", got)
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	app := NewRegistry().Bind("Ctrl+W", "close").Bind("Ctrl+P", "print")
+	win := NewRegistry().SetParent(app).
+		Bind("Ctrl+P", "palette").Bind("Ctrl+P", "print").
+		Bind("Ctrl+W", "close").
+		Bind("Ctrl+K", "clear").Bind("Ctrl+K", "kill")
+
+	want := []Conflict{
+		{Chord: MustParse("Ctrl+P"), Commands: []string{"palette", "print"}},
+		{Chord: MustParse("Ctrl+K"), Commands: []string{"clear", "kill"}},
+	}
+	got := win.Conflicts()
+	if !slices.EqualFunc(got, want, func(a, b Conflict) bool {
+		return a.Chord == b.Chord && slices.Equal(a.Commands, b.Commands) && slices.Equal(a.Shadowed, b.Shadowed)
+	}) {
+		t.Errorf("Conflicts = %+v, want %+v", got, want)
+	}
+
+	// A chord the window binds to another command than the app shadows it.
+	win.Bind("Ctrl+W", "closeTab").Unbind("close")
+	got = win.Conflicts()
+	if len(got) != 3 || got[2].Chord != MustParse("Ctrl+W") || !slices.Equal(got[2].Shadowed, []string{"close"}) {
+		t.Errorf("Conflicts = %+v, want Ctrl+W shadowing close", got)
+	}
+	if got := app.Conflicts(); got != nil {
+		t.Errorf("parent Conflicts = %+v, want none", got)
+	}
+}
+
+func TestRun(t *testing.T) {
+	var l log
+	enabled := true
+	app := l.handle(NewRegistry(), "app", "save", "quit")
+	win := l.handle(NewRegistry().SetParent(app), "win", "save")
+	win.Enabled("save", func() bool { return enabled })
+
+	for _, id := range []string{"save", "quit"} {
+		win.Run(id)
+	}
+	enabled = false
+	win.Run("save")
+	if win.Run("none") {
+		t.Error("Run of a command none handles reported true")
+	}
+	if want := []string{"win:save", "app:quit", "app:save"}; !slices.Equal(l, want) {
+		t.Errorf("ran %q, want %q", l, want)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(l *log, inner, outer, win, app *Registry)
+		ev    event.KeyEvent
+		want  []string
+	}{
+		{
+			name: "deepest scope first",
+			setup: func(l *log, inner, outer, win, app *Registry) {
+				l.handle(inner.Bind("Ctrl+D", "duplicate"), "inner", "duplicate")
+				l.handle(outer.Bind("Ctrl+D", "delete"), "outer", "delete")
+				l.handle(app.Bind("Ctrl+D", "bookmark"), "app", "bookmark")
+			},
+			ev:   press("Ctrl+D"),
+			want: []string{"inner:duplicate"},
+		},
+		{
+			name: "window before app",
+			setup: func(l *log, inner, outer, win, app *Registry) {
+				l.handle(win.Bind("Ctrl+N", "newTab"), "win", "newTab")
+				l.handle(app.Bind("Ctrl+N", "newWindow"), "app", "newWindow")
+			},
+			ev:   press("Ctrl+N"),
+			want: []string{"win:newTab"},
+		},
+		{
+			name: "app binding",
+			setup: func(l *log, inner, outer, win, app *Registry) {
+				l.handle(app.Bind("Ctrl+Q", "quit"), "app", "quit")
+			},
+			ev:   press("Ctrl+Q"),
+			want: []string{"app:quit"},
+		},
+		{
+			// The app binds Ctrl+C to copy, which the focused scope runs.
+			name: "outer binding, inner handler",
+			setup: func(l *log, inner, outer, win, app *Registry) {
+				l.handle(app.Bind("Ctrl+C", "copy"), "app", "copy")
+				l.handle(outer, "outer", "copy")
+				l.handle(inner, "inner", "copy")
+			},
+			ev:   press("Ctrl+C"),
+			want: []string{"inner:copy"},
+		},
+		{
+			name: "disabled falls through",
+			setup: func(l *log, inner, outer, win, app *Registry) {
+				l.handle(app.Bind("Ctrl+C", "copy"), "app", "copy")
+				l.handle(outer, "outer", "copy")
+				l.handle(inner, "inner", "copy").Enabled("copy", func() bool { return false })
+			},
+			ev:   press("Ctrl+C"),
+			want: []string{"outer:copy"},
+		},
+		{
+			name: "unhandled binding falls through",
+			setup: func(l *log, inner, outer, win, app *Registry) {
+				inner.Bind("Ctrl+D", "duplicate")
+				l.handle(outer.Bind("Ctrl+D", "delete"), "outer", "delete")
+			},
+			ev:   press("Ctrl+D"),
+			want: []string{"outer:delete"},
+		},
+		{
+			name: "first handled of a conflict",
+			setup: func(l *log, inner, outer, win, app *Registry) {
+				win.Bind("Ctrl+P", "palette").Bind("Ctrl+P", "print")
+				l.handle(win, "win", "print")
+			},
+			ev:   press("Ctrl+P"),
+			want: []string{"win:print"},
+		},
+		{
+			name: "release",
+			setup: func(l *log, inner, outer, win, app *Registry) {
+				l.handle(app.Bind("Ctrl+Q", "quit"), "app", "quit")
+			},
+			ev: event.KeyEvent{Type: event.KeyRelease, Key: event.KeyQ, Modifiers: event.ModCtrl},
+		},
+		{
+			name: "other modifiers",
+			setup: func(l *log, inner, outer, win, app *Registry) {
+				l.handle(app.Bind("Ctrl+Q", "quit"), "app", "quit")
+			},
+			ev: press("Ctrl+Shift+Q"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var l log
+			app := NewRegistry()
+			win := NewRegistry().SetParent(app)
+			inner, outer := NewRegistry(), NewRegistry()
+			tt.setup(&l, inner, outer, win, app)
+			ran := Resolve(tt.ev, inner, outer, win)
+			if ran != (tt.want != nil) || !slices.Equal(l, tt.want) {
+				t.Errorf("Resolve = %v, ran %q, want %q", ran, l, tt.want)
+			}
+		})
+	}
+}
+
+// leaf is a widget without children.
+type leaf struct {
+	core.WidgetBase
+}
+
+func (l *leaf) Layout(ctx *core.LayoutContext) core.Size { return core.Size{} }
+
+func (l *leaf) Paint(*core.PaintContext) {}
+
+func TestScopes(t *testing.T) {
+	field := &leaf{}
+	inner := NewScope(field, nil)
+	outer := NewScope(inner, NewRegistry())
+	path := []core.Widget{outer, inner, field}
+	if got := Scopes(path); !slices.Equal(got, []*Registry{inner.Registry(), outer.Registry()}) {
+		t.Errorf("Scopes = %v, want the inner registry, then the outer", got)
+	}
+	if inner.Registry() == nil || inner.Registry() == outer.Registry() {
+		t.Error("NewScope(nil) did not make a registry of its own")
+	}
+	if got := Scopes([]core.Widget{field}); got != nil {
+		t.Errorf("Scopes without scopes = %v, want none", got)
+	}
+}
+
+func TestOf(t *testing.T) {
+	ctx := core.NewContext()
+	r := Of(ctx)
+	if Of(ctx) != r {
+		t.Error("Of returned another registry the second time")
+	}
+	r.Bind("Ctrl+S", "save")
+	if got, want := Label(ctx, "save"), MustParse("Ctrl+S").Label(); got != want {
+		t.Errorf("Label = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,71 @@
+package shortcuts
+
+import "github.com/gogpu/ui/core"
+
+// Scope wraps a widget with a registry whose bindings and commands apply
+// while the focus is within it, before those of the scopes around it,
+// the window and the application.
+type Scope struct {
+	core.WidgetBase
+	child    core.Widget
+	registry *Registry
+}
+
+// NewScope returns child with the bindings and commands of r, or of a
+// new registry if r is nil.
+func NewScope(child core.Widget, r *Registry) *Scope {
+	if r == nil {
+		r = NewRegistry()
+	}
+	s := &Scope{child: child, registry: r}
+	s.SetChildren(child)
+	return s
+}
+
+// Registry returns the registry of the scope.
+func (s *Scope) Registry() *Registry {
+	return s.registry
+}
+
+// Layout implements core.Widget.
+func (s *Scope) Layout(ctx *core.LayoutContext) core.Size {
+	return ctx.Measure(s.child, ctx.Constraints)
+}
+
+// IntrinsicWidth implements core.IntrinsicSizer.
+func (s *Scope) IntrinsicWidth(ctx *core.LayoutContext, height float32) (minWidth, maxWidth float32) {
+	return ctx.IntrinsicWidth(s.child, height)
+}
+
+// IntrinsicHeight implements core.IntrinsicSizer.
+func (s *Scope) IntrinsicHeight(ctx *core.LayoutContext, width float32) (minHeight, maxHeight float32) {
+	return ctx.IntrinsicHeight(s.child, width)
+}
+
+// Baseline implements core.Baseliner with the child's baseline.
+func (s *Scope) Baseline() (float32, bool) {
+	return core.BaselineOf(s.child)
+}
+
+// SetBounds implements core.Widget.
+func (s *Scope) SetBounds(r core.Rect) {
+	s.WidgetBase.SetBounds(r)
+	s.child.SetBounds(r)
+}
+
+// Paint implements core.Widget.
+func (s *Scope) Paint(ctx *core.PaintContext) {
+	s.child.Paint(ctx)
+}
+
+// Scopes returns the registries of the scopes on path, a path from the
+// root down, from the deepest out.
+func Scopes(path []core.Widget) []*Registry {
+	var regs []*Registry
+	for i := len(path) - 1; i >= 0; i-- {
+		if s, ok := path[i].(*Scope); ok {
+			regs = append(regs, s.registry)
+		}
+	}
+	return regs
+}
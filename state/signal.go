@@ -0,0 +1,131 @@
+// Package state provides observable values for binding application state
+// to widgets.
+//
+// A [Signal] holds a value and notifies subscribers when it changes.
+// Widgets that accept a signal read it on every layout pass and write the
+// user's edits back, so the signal and the widget stay in sync in both
+// directions:
+//
+//	volume := state.New(0.5)
+//	slider := widgets.NewSlider(0, 1).Bind(volume)
+//	volume.Set(0.8) // moves the slider
+package state
+
+import (
+	"slices"
+	"sync"
+)
+
+// Signal is an observable value. It is safe for concurrent use.
+//
+// Subscribers are called in the order they subscribed, with one value at
+// a time and in the order the values were set. A Set made while
+// subscribers are running, whether by one of them or by another
+// goroutine, stores its value and returns; the goroutine already
+// notifying passes the value on once the current one has reached every
+// subscriber. Otherwise subscribers run synchronously on the goroutine
+// that called Set.
+type Signal[T any] struct {
+	mu        sync.Mutex
+	value     T
+	equal     func(a, b T) bool
+	subs      []subscriber[T]
+	next      int
+	pending   []T
+	notifying bool
+}
+
+type subscriber[T any] struct {
+	id int
+	fn func(T)
+}
+
+// New returns a signal holding v. Setting an equal value does not notify
+// subscribers.
+func New[T comparable](v T) *Signal[T] {
+	return &Signal[T]{value: v, equal: func(a, b T) bool { return a == b }}
+}
+
+// NewFunc returns a signal holding v for types that are not comparable,
+// using equal to detect changes. A nil equal notifies on every Set.
+func NewFunc[T any](v T, equal func(a, b T) bool) *Signal[T] {
+	return &Signal[T]{value: v, equal: equal}
+}
+
+// Get returns the current value.
+func (s *Signal[T]) Get() T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.value
+}
+
+// Set stores v and, if it differs from the current value, calls every
+// subscriber with it.
+func (s *Signal[T]) Set(v T) {
+	s.Update(func(T) T { return v })
+}
+
+// Update atomically replaces the value with fn applied to it and notifies
+// subscribers if it changed.
+func (s *Signal[T]) Update(fn func(T) T) {
+	s.mu.Lock()
+	v := fn(s.value)
+	if s.equal != nil && s.equal(s.value, v) {
+		s.mu.Unlock()
+		return
+	}
+	s.value = v
+	s.pending = append(s.pending, v)
+	if s.notifying {
+		s.mu.Unlock()
+		return
+	}
+	s.notifying = true
+	s.mu.Unlock()
+	s.notify()
+}
+
+// notify passes the pending values to the subscribers until none are
+// left. A subscriber that panics drops the values not yet passed on.
+func (s *Signal[T]) notify() {
+	done := false
+	defer func() {
+		if !done {
+			s.mu.Lock()
+			s.pending = nil
+			s.notifying = false
+			s.mu.Unlock()
+		}
+	}()
+	for {
+		s.mu.Lock()
+		if len(s.pending) == 0 {
+			s.notifying = false
+			s.mu.Unlock()
+			done = true
+			return
+		}
+		v := s.pending[0]
+		s.pending = s.pending[1:]
+		subs := slices.Clone(s.subs)
+		s.mu.Unlock()
+		for _, sub := range subs {
+			sub.fn(v)
+		}
+	}
+}
+
+// Subscribe calls fn with every new value until the returned function is
+// called.
+func (s *Signal[T]) Subscribe(fn func(T)) (cancel func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := s.next
+	s.next++
+	s.subs = append(s.subs, subscriber[T]{id, fn})
+	return func() {
+		s.mu.Lock()
+		s.subs = slices.DeleteFunc(s.subs, func(sub subscriber[T]) bool { return sub.id == id })
+		s.mu.Unlock()
+	}
+}
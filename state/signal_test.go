@@ -0,0 +1,146 @@
+package state
+
+import (
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestSignalSet(t *testing.T) {
+	tests := []struct {
+		name string
+		sig  *Signal[[]int]
+		sets [][]int
+		want int
+	}{
+		{"equal values are dropped", NewFunc([]int{1}, slices.Equal[[]int]), [][]int{{1}, {2}, {2}, {1}}, 2},
+		{"nil equal notifies every set", NewFunc[[]int](nil, nil), [][]int{nil, {1}, {1}}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			tt.sig.Subscribe(func([]int) { calls++ })
+			for _, v := range tt.sets {
+				tt.sig.Set(v)
+			}
+			if calls != tt.want {
+				t.Errorf("%d notifications, want %d", calls, tt.want)
+			}
+			if got := tt.sig.Get(); !slices.Equal(got, tt.sets[len(tt.sets)-1]) {
+				t.Errorf("Get() = %v", got)
+			}
+		})
+	}
+}
+
+func TestSignalSubscribe(t *testing.T) {
+	s := New(0)
+	var a, b []int
+	cancelA := s.Subscribe(func(v int) { a = append(a, v) })
+	s.Subscribe(func(v int) { b = append(b, v) })
+	s.Set(1)
+	s.Set(1)
+	cancelA()
+	cancelA() // Cancelling twice is harmless.
+	s.Update(func(v int) int { return v + 1 })
+	if !slices.Equal(a, []int{1}) || !slices.Equal(b, []int{1, 2}) {
+		t.Errorf("subscribers got %v and %v", a, b)
+	}
+}
+
+func TestSignalSubscriberMaySet(t *testing.T) {
+	s := New(0)
+	// Subscribers run outside the lock, so they may use the signal.
+	s.Subscribe(func(v int) {
+		if v < 3 {
+			s.Set(v + 1)
+		}
+	})
+	s.Set(1)
+	if got := s.Get(); got != 3 {
+		t.Errorf("Get() = %d, want 3", got)
+	}
+}
+
+func TestSignalConcurrentUpdate(t *testing.T) {
+	s := New(0)
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Go(func() { s.Update(func(v int) int { return v + 1 }) })
+	}
+	wg.Wait()
+	if got := s.Get(); got != 50 {
+		t.Errorf("Get() = %d after 50 updates", got)
+	}
+}
+
+func TestSignalNotifyOrder(t *testing.T) {
+	s := New(0)
+	var got []string
+	for _, name := range []string{"a", "b", "c", "d"} {
+		s.Subscribe(func(v int) { got = append(got, name+strconv.Itoa(v)) })
+	}
+	s.Set(1)
+	s.Set(2)
+	want := []string{"a1", "b1", "c1", "d1", "a2", "b2", "c2", "d2"}
+	if !slices.Equal(got, want) {
+		t.Errorf("notified %v, want %v", got, want)
+	}
+}
+
+func TestSignalNestedSetDeliveredInOrder(t *testing.T) {
+	s := New(0)
+	var first, second []int
+	s.Subscribe(func(v int) {
+		first = append(first, v)
+		if v == 1 {
+			s.Set(2) // Delivered once 1 has reached every subscriber.
+		}
+	})
+	s.Subscribe(func(v int) { second = append(second, v) })
+	s.Set(1)
+	if !slices.Equal(first, []int{1, 2}) || !slices.Equal(second, []int{1, 2}) {
+		t.Errorf("subscribers got %v and %v, want [1 2] for both", first, second)
+	}
+}
+
+func TestSignalConcurrentSetsNotifyInOrder(t *testing.T) {
+	s := New(0)
+	var got []int
+	s.Subscribe(func(v int) { got = append(got, v) })
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Go(func() { s.Update(func(v int) int { return v + 1 }) })
+	}
+	wg.Wait()
+	// Each update adds one, so the values are seen in the order written
+	// only if they arrive as 1, 2, ..., 50.
+	if len(got) != 50 {
+		t.Fatalf("%d notifications, want 50", len(got))
+	}
+	for i, v := range got {
+		if v != i+1 {
+			t.Fatalf("notification %d is %d, want %d", i, v, i+1)
+		}
+	}
+}
+
+func TestSignalSubscriberPanic(t *testing.T) {
+	s := New(0)
+	var got []int
+	s.Subscribe(func(v int) {
+		if v == 1 {
+			panic("boom")
+		}
+		got = append(got, v)
+	})
+	func() {
+		defer func() { recover() }()
+		s.Set(1)
+	}()
+	s.Set(2)
+	if !slices.Equal(got, []int{2}) {
+		t.Errorf("after a panic the subscriber got %v, want [2]", got)
+	}
+}
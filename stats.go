@@ -0,0 +1,105 @@
+package ui
+
+import "time"
+
+// statsHistory is how many frames back ReportRender may report, for
+// timestamp queries that resolve a few frames late.
+const statsHistory = 8
+
+// FrameStats measures a frame, for apps to surface performance
+// regressions in their own telemetry.
+type FrameStats struct {
+	Frame  uint64 // Numbered from 1, as FrameNumber returns it.
+	Start  time.Time
+	Layout time.Duration // Running posted work and the layout and arrange passes.
+	Paint  time.Duration // Painting the tree and overlays into the canvas.
+
+	// RenderStats are what the platform integration reported of drawing
+	// the frame, and zero without ReportRender.
+	RenderStats
+}
+
+// RenderStats are what drawing a frame cost the rendering backend.
+type RenderStats struct {
+	DrawCalls      int
+	Triangles      int
+	TextureUploads int
+	UploadBytes    int
+	GPU            time.Duration // From timestamp queries, or 0 without them.
+}
+
+// CPU returns the time the frame took on the UI goroutine.
+func (s FrameStats) CPU() time.Duration {
+	return s.Layout + s.Paint
+}
+
+// WithFrameStats calls fn with the stats of each frame; see
+// Window.OnFrameStats.
+func WithFrameStats(fn func(FrameStats)) Option {
+	return func(w *Window) {
+		w.onStats = fn
+	}
+}
+
+// OnFrameStats calls fn with the stats of each frame once they are known:
+// at the end of Frame, or once a platform integration has reported
+// drawing a frame with ReportRender, when the integration reports each
+// frame. Frames whose report never arrives are left out. A nil fn stops
+// the calls.
+func (w *Window) OnFrameStats(fn func(FrameStats)) {
+	w.onStats = fn
+}
+
+// FrameNumber returns the number of the last frame, counting from 1, or 0
+// before the first.
+func (w *Window) FrameNumber() uint64 {
+	return w.seq
+}
+
+// FrameStats returns the stats of the latest frame that OnFrameStats was
+// or would have been called with.
+func (w *Window) FrameStats() FrameStats {
+	return w.stats
+}
+
+// ReportRender reports what drawing frame cost, for platform integrations
+// to call on the UI goroutine once the frame was submitted, or once its
+// timestamp queries resolved. Frames more than a few back are no longer
+// kept and their reports are ignored.
+func (w *Window) ReportRender(frame uint64, r RenderStats) {
+	s := &w.frames[frame%statsHistory]
+	if frame == 0 || s.Frame != frame {
+		return
+	}
+	s.RenderStats = r
+	if w.reportFrom == 0 {
+		// The frames until now were delivered at the end of Frame.
+		w.reportFrom = w.seq + 1
+	}
+	if frame < w.reportFrom {
+		if w.stats.Frame == frame {
+			w.stats = *s
+		}
+		return
+	}
+	w.deliverStats(*s)
+}
+
+// recordFrame keeps the stats of the frame that started at start and was
+// laid out at laid, and delivers them unless the integration reports.
+func (w *Window) recordFrame(start, laid time.Time) {
+	s := FrameStats{Frame: w.seq, Start: start, Layout: laid.Sub(start), Paint: time.Since(laid)}
+	w.frames[w.seq%statsHistory] = s
+	if w.reportFrom == 0 {
+		w.deliverStats(s)
+	}
+}
+
+func (w *Window) deliverStats(s FrameStats) {
+	if s.Frame >= w.stats.Frame {
+		w.stats = s
+	}
+	if w.onStats != nil {
+		w.onStats(s)
+	}
+}
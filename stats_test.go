@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+)
+
+func TestWindowFrameStats(t *testing.T) {
+	var got []FrameStats
+	w := NewWindow(newPane(), WithFrameStats(func(s FrameStats) { got = append(got, s) }))
+	w.Resize(core.Sz(100, 100))
+	frames := func() []uint64 {
+		var n []uint64
+		for _, s := range got {
+			n = append(n, s.Frame)
+		}
+		return n
+	}
+	var rec core.Recording
+	if w.FrameNumber() != 0 {
+		t.Errorf("frame %d before the first", w.FrameNumber())
+	}
+
+	// Until the platform reports, the stats are delivered with each frame.
+	w.Frame(&rec)
+	w.Frame(&rec)
+	if !slices.Equal(frames(), []uint64{1, 2}) || w.FrameNumber() != 2 {
+		t.Fatalf("stats of frames %v", frames())
+	}
+	if s := got[1]; s.Start.IsZero() || s.CPU() != s.Layout+s.Paint || s.Layout < 0 || s.Paint < 0 {
+		t.Errorf("stats %+v", s)
+	}
+
+	// A report of a frame delivered already updates its stats, and later
+	// frames wait for theirs, which may come out of order.
+	w.ReportRender(2, RenderStats{DrawCalls: 3})
+	if len(got) != 2 || w.FrameStats().DrawCalls != 3 {
+		t.Errorf("stats of frames %v, latest %+v after reporting one delivered", frames(), w.FrameStats())
+	}
+	w.Frame(&rec)
+	w.Frame(&rec)
+	if len(got) != 2 {
+		t.Errorf("stats of frames %v before their reports", frames())
+	}
+	w.ReportRender(4, RenderStats{DrawCalls: 4, Triangles: 8})
+	w.ReportRender(3, RenderStats{DrawCalls: 5})
+	if !slices.Equal(frames(), []uint64{1, 2, 4, 3}) || got[2].Triangles != 8 {
+		t.Errorf("stats of frames %v", frames())
+	}
+	if s := w.FrameStats(); s.Frame != 4 || s.DrawCalls != 4 {
+		t.Errorf("latest stats %+v, want of the latest frame", s)
+	}
+
+	// Reports of frames not kept, or not yet drawn, are ignored.
+	for range statsHistory {
+		w.Frame(&rec)
+	}
+	w.ReportRender(w.FrameNumber()-statsHistory, RenderStats{})
+	w.ReportRender(w.FrameNumber()+1, RenderStats{})
+	w.ReportRender(0, RenderStats{})
+	if len(got) != 4 {
+		t.Errorf("stats of frames %v after reports to ignore", frames())
+	}
+	w.OnFrameStats(nil)
+	w.ReportRender(w.FrameNumber(), RenderStats{GPU: 1})
+	if len(got) != 4 || w.FrameStats().GPU != 1 {
+		t.Errorf("stats delivered after OnFrameStats(nil), latest %+v", w.FrameStats())
+	}
+}
@@ -0,0 +1,193 @@
+// Package text measures and lays out text exactly as widgets draw it, for
+// custom widgets that place text themselves, such as code editors and
+// charts. Layout breaks text in lines with package paragraph, as
+// widgets.Text does, and reports where each line and character landed:
+//
+//	b := text.Layout(ctx, label, style, 120)
+//	for _, l := range b.Lines {
+//		// l.Rect is the line box, l.Baseline its baseline.
+//	}
+//	caret := b.Caret(offset)
+//	offset = b.OffsetAt(click.Sub(origin))
+//	b.Paint(canvas, origin)
+//
+// The measurer is usually the core.Context, whose measurer the rendering
+// backend installs. Offsets into the text are in bytes, and positions
+// are relative to the top left of the laid out text.
+package text
+
+import (
+	"unicode/utf8"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/grapheme"
+	"github.com/gogpu/ui/paragraph"
+)
+
+// Block is text laid out in lines.
+type Block struct {
+	Lines []Line
+
+	// Width is the width of the widest line, and Height the height of
+	// the lines.
+	Width, Height float32
+
+	// Truncated is set when text was left out by the options' MaxLines
+	// or Overflow.
+	Truncated bool
+
+	para  *paragraph.Paragraph
+	m     core.TextMeasurer
+	width float32 // The width lines are aligned in.
+}
+
+// Line is a line of a Block.
+type Line struct {
+	// Start and End are the text on the line, with the spaces it ends in
+	// but not the newline.
+	Start, End int
+
+	// Rect is the line box: as wide as what is drawn, and as tall as the
+	// tallest style on the line.
+	Rect core.Rect
+
+	// Baseline is the y of the baseline of the line.
+	Baseline float32
+
+	// Glyphs are the characters drawn, left to right.
+	Glyphs []Glyph
+}
+
+// Glyph is a character drawn on a line: a grapheme cluster of the text,
+// or a hyphen or ellipsis added by layout.
+type Glyph struct {
+	// Start and End are the cluster in the text. They are equal for a
+	// hyphen or ellipsis added by layout.
+	Start, End int
+
+	Text string
+
+	// X is the left edge of the glyph and Width its advance, which for
+	// justified spaces includes the space they are stretched by.
+	X, Width float32
+
+	RTL bool
+}
+
+// Layout lays out s in style, breaking lines to fit maxWidth, which
+// Infinity leaves unbounded, so that only newlines end lines.
+func Layout(m core.TextMeasurer, s string, style core.TextStyle, maxWidth float32) *Block {
+	return LayoutOptions(m, s, style, paragraph.Options{Width: maxWidth})
+}
+
+// LayoutOptions lays out s in style with the alignment, direction, line
+// limit and other options of opts. Lines are aligned in opts.Width, or in
+// the width of the widest line if it is unbounded.
+func LayoutOptions(m core.TextMeasurer, s string, style core.TextStyle, opts paragraph.Options) *Block {
+	p := paragraph.New(m, s, style, opts)
+	b := &Block{
+		Width:     p.Width,
+		Height:    p.Height,
+		Truncated: p.Truncated,
+		para:      p,
+		m:         m,
+		width:     opts.Width,
+	}
+	if b.width >= core.Infinity {
+		b.width = p.Width
+	}
+	text := p.Text()
+	for i, pl := range p.Lines {
+		x := p.Offset(i, b.width)
+		l := Line{
+			Start:    pl.Start,
+			End:      pl.End,
+			Rect:     core.R(x, pl.Y, pl.Width, pl.Height),
+			Baseline: pl.Y + pl.Ascent,
+		}
+		for _, seg := range pl.Segments {
+			if seg.Start == seg.End {
+				l.Glyphs = append(l.Glyphs, Glyph{Start: seg.Start, End: seg.End, Text: seg.Text, X: x + seg.X, Width: seg.Width, RTL: seg.RTL})
+				continue
+			}
+			runes := []rune(text[seg.Start:seg.End])
+			at := seg.Start
+			for k := 0; k < len(runes); {
+				next := grapheme.Next(runes, k)
+				g := Glyph{Start: at, Text: string(runes[k:next]), RTL: seg.RTL}
+				for _, r := range runes[k:next] {
+					at += utf8.RuneLen(r)
+				}
+				g.End = at
+				if r := p.Highlight(m, i, g.Start, g.End); len(r) > 0 {
+					g.X, g.Width = x+r[0].X, r[0].Width
+				}
+				l.Glyphs = append(l.Glyphs, g)
+				k = next
+			}
+		}
+		b.Lines = append(b.Lines, l)
+	}
+	return b
+}
+
+// Size returns the size of the text.
+func (b *Block) Size() core.Size {
+	return core.Sz(b.Width, b.Height)
+}
+
+// LineOf returns the line the text at offset is on. An offset where a
+// line is broken is on the line after it.
+func (b *Block) LineOf(offset int) int {
+	n := 0
+	for n+1 < len(b.Lines) && b.Lines[n+1].Start <= offset {
+		n++
+	}
+	return n
+}
+
+// Caret returns the caret before the byte at offset: a rectangle of no
+// width as tall as its line. The caret after the last character of a
+// line stands for the spaces the line ends in too.
+func (b *Block) Caret(offset int) core.Rect {
+	if len(b.Lines) == 0 {
+		return core.Rect{}
+	}
+	n := b.LineOf(offset)
+	l := &b.Lines[n]
+	x := b.para.Offset(n, b.width) + b.para.X(b.m, n, offset)
+	return core.R(x, l.Rect.Y, 0, l.Rect.Height)
+}
+
+// OffsetAt returns the cluster boundary nearest p, on the line at p.Y or
+// the first or last line above or below the text.
+func (b *Block) OffsetAt(p core.Point) int {
+	if len(b.Lines) == 0 {
+		return 0
+	}
+	n := b.para.LineAt(p.Y)
+	return b.para.OffsetAt(b.m, n, p.X-b.para.Offset(n, b.width))
+}
+
+// Highlight returns the boxes covering the text from start up to end, as
+// tall as their lines, for drawing a selection: one for each line and
+// each run of one direction the text crosses.
+func (b *Block) Highlight(start, end int) []core.Rect {
+	var out []core.Rect
+	for n, l := range b.Lines {
+		if l.End < start || l.Start > end {
+			continue
+		}
+		d := core.Pt(b.para.Offset(n, b.width), l.Rect.Y)
+		for _, r := range b.para.Highlight(b.m, n, start, end) {
+			out = append(out, r.Translate(d))
+		}
+	}
+	return out
+}
+
+// Paint draws the text with its top left at pos, as widgets.Text draws
+// it.
+func (b *Block) Paint(cv core.Canvas, pos core.Point) {
+	b.para.Paint(cv, core.R(pos.X, pos.Y, b.width, b.Height))
+}
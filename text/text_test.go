@@ -0,0 +1,164 @@
+package text
+
+import (
+	"fmt"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/paragraph"
+)
+
+// monospace measures every character 10 pixels wide.
+type monospace struct{}
+
+func (monospace) MeasureText(text string, style core.TextStyle) core.Size {
+	return core.Sz(10*float32(utf8.RuneCountInString(text)), style.LineHeight())
+}
+
+// Characters for the tests, each two bytes long.
+const (
+	alef  = "א"
+	bet   = "ב"
+	gimel = "ג"
+)
+
+// style is 12.5 pixels a line, with the baseline 9.5 from its top.
+var style = core.TextStyle{Size: 10}
+
+func TestLayout(t *testing.T) {
+	b := Layout(monospace{}, "abc def\nxy", style, 40)
+	want := []Line{
+		{Start: 0, End: 4, Rect: core.R(0, 0, 30, 12.5), Baseline: 9.5},
+		{Start: 4, End: 7, Rect: core.R(0, 12.5, 30, 12.5), Baseline: 22},
+		{Start: 8, End: 10, Rect: core.R(0, 25, 20, 12.5), Baseline: 34.5},
+	}
+	if len(b.Lines) != len(want) {
+		t.Fatalf("%d lines, want %d", len(b.Lines), len(want))
+	}
+	for i, w := range want {
+		l := b.Lines[i]
+		if l.Start != w.Start || l.End != w.End || l.Rect != w.Rect || l.Baseline != w.Baseline {
+			t.Errorf("line %d = %v %v %v %v, want %v %v %v %v", i, l.Start, l.End, l.Rect, l.Baseline, w.Start, w.End, w.Rect, w.Baseline)
+		}
+	}
+	// The space the first line ends in hangs past it and is not drawn.
+	var glyphs string
+	for _, g := range b.Lines[0].Glyphs {
+		glyphs += fmt.Sprintf("%s@%v ", g.Text, g.X)
+	}
+	if want := "a@0 b@10 c@20 "; glyphs != want {
+		t.Errorf("glyphs %q, want %q", glyphs, want)
+	}
+	if got, want := b.Size(), core.Sz(30, 37.5); got != want {
+		t.Errorf("Size() = %v, want %v", got, want)
+	}
+	for offset, want := range map[int]int{0: 0, 3: 0, 4: 1, 7: 1, 8: 2, 10: 2} {
+		if got := b.LineOf(offset); got != want {
+			t.Errorf("LineOf(%d) = %d, want %d", offset, got, want)
+		}
+	}
+}
+
+func TestLayoutOptions(t *testing.T) {
+	b := LayoutOptions(monospace{}, "abc def ghi", style, paragraph.Options{Width: 100, Align: paragraph.Center, MaxLines: 1, Overflow: paragraph.Clip})
+	if len(b.Lines) != 1 || !b.Truncated {
+		t.Fatalf("%d lines, truncated %v, want 1 line truncated", len(b.Lines), b.Truncated)
+	}
+	// "ghi" is cut by MaxLines, and "abc def", 70 wide, is centred.
+	if got, want := b.Lines[0].Rect.X, float32(15); got != want {
+		t.Errorf("line at %v, want %v", got, want)
+	}
+	if got, want := b.Caret(0).X, float32(15); got != want {
+		t.Errorf("Caret(0) at %v, want %v following the alignment", got, want)
+	}
+}
+
+func TestCaret(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		opts    paragraph.Options
+		offsets map[int]core.Point
+	}{
+		{"multi-line", "abc def", paragraph.Options{Width: 40}, map[int]core.Point{
+			0: core.Pt(0, 0), 2: core.Pt(20, 0), 3: core.Pt(30, 0),
+			4: core.Pt(0, 12.5), 6: core.Pt(20, 12.5), 7: core.Pt(30, 12.5),
+		}},
+		{"right to left", alef + bet + gimel, paragraph.Options{Width: core.Infinity, Direction: core.RightToLeft}, map[int]core.Point{
+			0: core.Pt(30, 0), 2: core.Pt(20, 0), 4: core.Pt(10, 0), 6: core.Pt(0, 0),
+		}},
+		{"right aligned", "ab", paragraph.Options{Width: 100, Align: paragraph.Right}, map[int]core.Point{
+			0: core.Pt(80, 0), 1: core.Pt(90, 0), 2: core.Pt(100, 0),
+		}},
+		// In "ab אב cd" the Hebrew is drawn right to left between the spaces.
+		{"mixed", "ab " + alef + bet + " cd", paragraph.Options{Width: core.Infinity}, map[int]core.Point{
+			2: core.Pt(20, 0), 3: core.Pt(30, 0), 5: core.Pt(40, 0), 8: core.Pt(60, 0), 10: core.Pt(80, 0),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := LayoutOptions(monospace{}, tt.text, style, tt.opts)
+			for offset, want := range tt.offsets {
+				c := b.Caret(offset)
+				if c.Origin() != want || c.Width != 0 || c.Height != 12.5 {
+					t.Errorf("Caret(%d) = %v, want at %v", offset, c, want)
+				}
+				// Clicking on the caret puts it back where it was.
+				if got := b.OffsetAt(c.Origin().Add(core.Pt(0, 1))); got != offset {
+					t.Errorf("OffsetAt(Caret(%d)) = %d", offset, got)
+				}
+			}
+		})
+	}
+}
+
+func TestOffsetAt(t *testing.T) {
+	b := Layout(monospace{}, "abc def", style, 40)
+	tests := []struct {
+		p    core.Point
+		want int
+	}{
+		{core.Pt(14, 5), 1},   // Nearer the left of b.
+		{core.Pt(16, 5), 2},   // Nearer its right.
+		{core.Pt(-20, 5), 0},  // Left of the line.
+		{core.Pt(90, 5), 3},   // Right of the first line, before its space.
+		{core.Pt(12, -30), 1}, // Above the text, on the first line.
+		{core.Pt(12, 99), 5},  // Below it, on the last.
+		{core.Pt(90, 20), 7},
+	}
+	for _, tt := range tests {
+		if got := b.OffsetAt(tt.p); got != tt.want {
+			t.Errorf("OffsetAt(%v) = %d, want %d", tt.p, got, tt.want)
+		}
+	}
+	if got := Layout(monospace{}, "", style, 40).OffsetAt(core.Pt(5, 5)); got != 0 {
+		t.Errorf("OffsetAt of empty text = %d", got)
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name       string
+		text       string
+		width      float32
+		start, end int
+		want       []core.Rect
+	}{
+		{"one line", "abc def", 40, 1, 3, []core.Rect{core.R(10, 0, 20, 12.5)}},
+		{"across lines", "abc def", 40, 2, 5, []core.Rect{core.R(20, 0, 10, 12.5), core.R(0, 12.5, 10, 12.5)}},
+		// Selecting the space and the first Hebrew letter covers two
+		// boxes apart, as the letter is drawn on the right of the word.
+		{"mixed directions", "ab " + alef + bet + " cd", core.Infinity, 2, 5, []core.Rect{core.R(20, 0, 10, 12.5), core.R(40, 0, 10, 12.5)}},
+		{"empty", "abc def", 40, 2, 2, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := Layout(monospace{}, tt.text, style, tt.width)
+			got := b.Highlight(tt.start, tt.end)
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("Highlight(%d, %d) = %v, want %v", tt.start, tt.end, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,271 @@
+// Package theme defines the design tokens widgets use for colors,
+// typography and shape.
+//
+// The active theme is injected into the widget tree through core.Context:
+//
+//	theme.Set(ctx, theme.Light())
+//	t := theme.From(ctx)
+//
+// Widgets never hard-code colors; they read them from the theme so that
+// Material, Fluent and Cupertino presets can restyle the whole tree.
+package theme
+
+import "github.com/gogpu/ui/core"
+
+// Theme is a complete set of design tokens.
+type Theme struct {
+	Colors     ColorPalette
+	Typography Typography
+	Radii      RadiusScale
+	Spacing    SpacingScale
+	Syntax     SyntaxPalette
+	Chart      ChartPalette
+
+	// Design selects the platform conventions for the shape of controls
+	// such as checkboxes and switches.
+	Design Design
+}
+
+// Design identifies a family of platform conventions. The color tokens are
+// the same for every design; it only changes the geometry of controls.
+type Design uint8
+
+const (
+	// DesignMaterial follows Material Design 3. It is the default.
+	DesignMaterial Design = iota
+	// DesignFluent follows Microsoft's Fluent design.
+	DesignFluent
+	// DesignCupertino follows Apple's human interface guidelines.
+	DesignCupertino
+)
+
+// ColorPalette holds the semantic color roles.
+type ColorPalette struct {
+	Primary          core.Color
+	OnPrimary        core.Color
+	PrimaryContainer core.Color
+	Secondary        core.Color
+	Background       core.Color
+	Surface          core.Color
+	SurfaceVariant   core.Color
+	OnSurface        core.Color
+	OnSurfaceVariant core.Color
+	Error            core.Color
+	OnError          core.Color
+	Outline          core.Color
+	Selection        core.Color
+	Scrim            core.Color
+	Shadow           core.Color
+}
+
+// SyntaxPalette holds the colors of highlighted source code. Plain code
+// uses the OnSurface color.
+type SyntaxPalette struct {
+	Keyword  core.Color
+	Type     core.Color
+	Function core.Color
+	String   core.Color
+	Number   core.Color
+	Comment  core.Color
+	Operator core.Color
+}
+
+// ChartPalette holds the colors given in turn to the series of a chart.
+type ChartPalette [8]core.Color
+
+// Typography holds the text styles for each semantic role. Colors are left
+// transparent; widgets fill them from the palette. Styles may use any
+// weight from 1 to 1000 and set the width, slant and other axes of
+// variable fonts, which fonts without those axes ignore.
+type Typography struct {
+	Title   core.TextStyle
+	Body    core.TextStyle
+	Label   core.TextStyle
+	Caption core.TextStyle
+	Mono    core.TextStyle
+}
+
+// Lerp interpolates each style of t towards to, as TextStyle.Lerp does,
+// for animating between typographies such as a compact and a spacious
+// one.
+func (t Typography) Lerp(to Typography, f float32) Typography {
+	return Typography{
+		Title:   t.Title.Lerp(to.Title, f),
+		Body:    t.Body.Lerp(to.Body, f),
+		Label:   t.Label.Lerp(to.Label, f),
+		Caption: t.Caption.Lerp(to.Caption, f),
+		Mono:    t.Mono.Lerp(to.Mono, f),
+	}
+}
+
+// RadiusScale holds corner radii.
+type RadiusScale struct {
+	Small  float32
+	Medium float32
+	Large  float32
+}
+
+// SpacingScale holds spacing steps in logical pixels.
+type SpacingScale struct {
+	XS, S, M, L, XL float32
+}
+
+func baseTypography() Typography {
+	return Typography{
+		Title:   core.TextStyle{Size: 20, Weight: core.WeightSemiBold},
+		Body:    core.TextStyle{Size: 14},
+		Label:   core.TextStyle{Size: 13, Weight: core.WeightMedium},
+		Caption: core.TextStyle{Size: 12},
+		Mono:    core.TextStyle{Family: "monospace", Size: 13},
+	}
+}
+
+// Light returns the default light theme.
+func Light() *Theme {
+	return &Theme{
+		Colors: ColorPalette{
+			Primary:          core.Hex(0x6750A4),
+			OnPrimary:        core.White,
+			PrimaryContainer: core.Hex(0xEADDFF),
+			Secondary:        core.Hex(0x625B71),
+			Background:       core.Hex(0xFFFBFE),
+			Surface:          core.Hex(0xFFFBFE),
+			SurfaceVariant:   core.Hex(0xE7E0EC),
+			OnSurface:        core.Hex(0x1C1B1F),
+			OnSurfaceVariant: core.Hex(0x49454F),
+			Error:            core.Hex(0xB3261E),
+			OnError:          core.White,
+			Outline:          core.Hex(0x79747E),
+			Selection:        core.Hex(0x6750A4).WithAlpha(0.16),
+			Scrim:            core.Black.WithAlpha(0.32),
+			Shadow:           core.Black,
+		},
+		Typography: baseTypography(),
+		Radii:      RadiusScale{Small: 4, Medium: 8, Large: 16},
+		Spacing:    SpacingScale{XS: 2, S: 4, M: 8, L: 16, XL: 24},
+		Syntax: SyntaxPalette{
+			Keyword:  core.Hex(0x7B1FA2),
+			Type:     core.Hex(0x00695C),
+			Function: core.Hex(0x1565C0),
+			String:   core.Hex(0x2E7D32),
+			Number:   core.Hex(0xC62828),
+			Comment:  core.Hex(0x757575),
+			Operator: core.Hex(0x5D4037),
+		},
+		Chart: ChartPalette{
+			core.Hex(0x6750A4), core.Hex(0x0277BD), core.Hex(0x2E7D32), core.Hex(0xEF6C00),
+			core.Hex(0xC2185B), core.Hex(0x00838F), core.Hex(0x9E9D24), core.Hex(0x6D4C41),
+		},
+	}
+}
+
+// Dark returns the default dark theme.
+func Dark() *Theme {
+	t := Light()
+	t.Colors = ColorPalette{
+		Primary:          core.Hex(0xD0BCFF),
+		OnPrimary:        core.Hex(0x381E72),
+		PrimaryContainer: core.Hex(0x4F378B),
+		Secondary:        core.Hex(0xCCC2DC),
+		Background:       core.Hex(0x1C1B1F),
+		Surface:          core.Hex(0x1C1B1F),
+		SurfaceVariant:   core.Hex(0x49454F),
+		OnSurface:        core.Hex(0xE6E1E5),
+		OnSurfaceVariant: core.Hex(0xCAC4D0),
+		Error:            core.Hex(0xF2B8B5),
+		OnError:          core.Hex(0x601410),
+		Outline:          core.Hex(0x938F99),
+		Selection:        core.Hex(0xD0BCFF).WithAlpha(0.24),
+		Scrim:            core.Black.WithAlpha(0.5),
+		Shadow:           core.Black,
+	}
+	t.Syntax = SyntaxPalette{
+		Keyword:  core.Hex(0xCE93D8),
+		Type:     core.Hex(0x80CBC4),
+		Function: core.Hex(0x90CAF9),
+		String:   core.Hex(0xA5D6A7),
+		Number:   core.Hex(0xFFAB91),
+		Comment:  core.Hex(0x9E9E9E),
+		Operator: core.Hex(0xBCAAA4),
+	}
+	t.Chart = ChartPalette{
+		core.Hex(0xD0BCFF), core.Hex(0x81D4FA), core.Hex(0xA5D6A7), core.Hex(0xFFCC80),
+		core.Hex(0xF48FB1), core.Hex(0x80DEEA), core.Hex(0xE6EE9C), core.Hex(0xBCAAA4),
+	}
+	return t
+}
+
+// Elevation levels of surfaces, for Theme.Elevation.
+const (
+	ElevationFlat    = 0 // Surfaces level with the window, such as cards in a list.
+	ElevationRaised  = 1 // Cards and buttons lifted off their background.
+	ElevationTooltip = 2 // Tooltips and toolbars above content.
+	ElevationMenu    = 3 // Menus, drop-downs and toasts.
+	ElevationDialog  = 4 // Dialogs and palettes.
+	ElevationMax     = 5 // The highest surfaces, such as dragged items.
+)
+
+// Elevation returns the shadows of a surface raised to level, from
+// ElevationFlat, which has none, to ElevationMax, in the conventions of
+// t.Design: the key and ambient shadows of Material, the sharper pair of
+// Fluent and the single soft shadow of Cupertino, all in the Shadow
+// color. The shadows are drawn with core.DrawShadow.
+func (t *Theme) Elevation(level int) []core.Shadow {
+	level = min(max(level, 0), ElevationMax)
+	if level == 0 {
+		return nil
+	}
+	c := t.Colors.Shadow
+	l := float32(level)
+	switch t.Design {
+	case DesignFluent:
+		// Fluent 2 shadow2 to shadow28: an ambient shadow and a key
+		// shadow whose offset is half its blur.
+		blur := [...]float32{0, 2, 4, 8, 16, 28}[level]
+		ambient := float32(2)
+		if level == ElevationMax {
+			ambient = 8
+		}
+		return []core.Shadow{
+			{Color: c.WithAlpha(c.A * 0.12), Blur: ambient / 2},
+			{Color: c.WithAlpha(c.A * 0.14), Offset: core.Pt(0, blur/2), Blur: blur / 2},
+		}
+	case DesignCupertino:
+		return []core.Shadow{
+			{Color: c.WithAlpha(c.A * 0.04), Blur: 0.5, Spread: 0.5},
+			{Color: c.WithAlpha(c.A * (0.06 + 0.02*l)), Offset: core.Pt(0, 2*l), Blur: 4 * l},
+		}
+	}
+	// Material 3 levels 1 to 5, with blur radii as in CSS.
+	key := [...]struct{ y, blur float32 }{{}, {1, 2}, {1, 2}, {1, 3}, {2, 3}, {4, 4}}[level]
+	ambient := [...]struct{ y, blur, spread float32 }{{}, {1, 3, 1}, {2, 6, 2}, {4, 8, 3}, {6, 10, 4}, {8, 12, 6}}[level]
+	return []core.Shadow{
+		{Color: c.WithAlpha(c.A * 0.3), Offset: core.Pt(0, key.y), Blur: key.blur / 2},
+		{Color: c.WithAlpha(c.A * 0.15), Offset: core.Pt(0, ambient.y), Blur: ambient.blur / 2, Spread: ambient.spread},
+	}
+}
+
+type contextKey struct{}
+
+var defaultTheme = Light()
+
+// Set installs t as the theme for every widget using ctx.
+func Set(ctx *core.Context, t *Theme) {
+	ctx.SetValue(contextKey{}, t)
+}
+
+// From returns the theme installed in ctx, or the default light theme.
+func From(ctx *core.Context) *Theme {
+	if ctx != nil {
+		if t, ok := ctx.Value(contextKey{}).(*Theme); ok && t != nil {
+			return t
+		}
+	}
+	return defaultTheme
+}
+
+// TextStyle returns style with its color set to c.
+func TextStyle(style core.TextStyle, c core.Color) core.TextStyle {
+	style.Color = c
+	return style
+}
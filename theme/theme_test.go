@@ -0,0 +1,129 @@
+package theme
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+)
+
+func TestFrom(t *testing.T) {
+	dark := Dark()
+	tests := []struct {
+		name string
+		ctx  func() *core.Context
+		want *Theme
+	}{
+		{"nil context", func() *core.Context { return nil }, defaultTheme},
+		{"unset", core.NewContext, defaultTheme},
+		{"set", func() *core.Context {
+			ctx := core.NewContext()
+			Set(ctx, dark)
+			return ctx
+		}, dark},
+		{"set to nil", func() *core.Context {
+			ctx := core.NewContext()
+			Set(ctx, nil)
+			return ctx
+		}, defaultTheme},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := From(tt.ctx()); got != tt.want {
+				t.Errorf("From() = %p, want %p", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDarkKeepsTypography(t *testing.T) {
+	light, dark := Light(), Dark()
+	if dark.Typography != light.Typography || dark.Spacing != light.Spacing {
+		t.Error("Dark changes more than the colors")
+	}
+	if dark.Colors.Background == light.Colors.Background {
+		t.Error("Dark has the background of Light")
+	}
+	if got := TextStyle(light.Typography.Body, core.White).Color; got != core.White {
+		t.Errorf("TextStyle color = %v, want white", got)
+	}
+}
+
+func TestSyntaxPalettes(t *testing.T) {
+	for _, th := range []*Theme{Light(), Dark()} {
+		s := th.Syntax
+		colors := []core.Color{s.Keyword, s.Type, s.Function, s.String, s.Number, s.Comment, s.Operator}
+		for i, c := range colors {
+			if c == th.Colors.Surface || c == (core.Color{}) {
+				t.Errorf("syntax color %d is %v, which does not show on the surface", i, c)
+			}
+			for _, d := range colors[:i] {
+				if c == d {
+					t.Errorf("syntax color %d repeats %v", i, c)
+				}
+			}
+		}
+	}
+	if Light().Syntax == Dark().Syntax {
+		t.Error("Dark has the syntax colors of Light")
+	}
+}
+
+func TestChartPalettes(t *testing.T) {
+	for _, th := range []*Theme{Light(), Dark()} {
+		for i, c := range th.Chart {
+			if c == th.Colors.Surface || c.A != 1 {
+				t.Errorf("chart color %d is %v, which does not show on the surface", i, c)
+			}
+			for _, d := range th.Chart[:i] {
+				if c == d {
+					t.Errorf("chart color %d repeats %v", i, c)
+				}
+			}
+		}
+	}
+	if Light().Chart == Dark().Chart {
+		t.Error("Dark has the chart colors of Light")
+	}
+}
+
+func TestElevation(t *testing.T) {
+	for _, d := range []Design{DesignMaterial, DesignFluent, DesignCupertino} {
+		th := Light()
+		th.Design = d
+		if th.Elevation(ElevationFlat) != nil || th.Elevation(-1) != nil {
+			t.Errorf("design %v: flat surfaces have shadows", d)
+		}
+		// The wider shadow of each level is larger than that of the one
+		// below, or further down, and above the top level they stay as
+		// they are.
+		var last core.Shadow
+		for level := ElevationRaised; level <= ElevationMax+1; level++ {
+			s := th.Elevation(level)
+			if len(s) != 2 {
+				t.Fatalf("design %v, level %d: %d shadows, want 2", d, level, len(s))
+			}
+			wide := s[1]
+			if level <= ElevationMax && (wide.Blur < last.Blur || wide.Offset.Y < last.Offset.Y || wide.Blur+wide.Offset.Y == last.Blur+last.Offset.Y) {
+				t.Errorf("design %v, level %d: shadow %+v, not above %+v", d, level, wide, last)
+			}
+			if level > ElevationMax && wide != last {
+				t.Errorf("design %v, level %d: shadow %+v past the top level", d, level, wide)
+			}
+			for _, sh := range s {
+				if sh.Color.A <= 0 || sh.Color.A > 0.3 || sh.Blur < 0 {
+					t.Errorf("design %v, level %d: shadow %+v", d, level, sh)
+				}
+			}
+			last = wide
+		}
+	}
+
+	// Shadows are of the theme's shadow color.
+	th := Light()
+	th.Colors.Shadow = core.RGB(0, 0, 255).WithAlpha(0.5)
+	for _, s := range th.Elevation(ElevationMenu) {
+		if s.Color.B != 1 || s.Color.R != 0 || s.Color.A > 0.15 {
+			t.Errorf("shadow %+v, want of the shadow color at its alpha", s)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package widgets
+
+import (
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/theme"
+)
+
+// Accordion stacks Expanders vertically with dividers between them.
+//
+// By default at most one expander is open: expanding one collapses the
+// others. Multiple allows any number to be open at once. While a header is
+// focused, the Up and Down arrows move to the previous or next header, and
+// Home and End to the first or last.
+type Accordion struct {
+	core.WidgetBase
+
+	items    []*Expander
+	multiple bool
+	sizes    []core.Size
+}
+
+// NewAccordion returns an accordion of items, in single-open mode.
+func NewAccordion(items ...*Expander) *Accordion {
+	a := &Accordion{}
+	a.Add(items...)
+	return a
+}
+
+// Add appends items.
+func (a *Accordion) Add(items ...*Expander) *Accordion {
+	for _, it := range items {
+		it.group = a
+		a.items = append(a.items, it)
+		if it.expanded {
+			a.collapseOthers(it, false)
+		}
+	}
+	return a
+}
+
+// Items returns the expanders in order.
+func (a *Accordion) Items() []*Expander {
+	return a.items
+}
+
+// Multiple sets whether several expanders can be open at once. Turning it
+// off keeps only the first open expander open.
+func (a *Accordion) Multiple(multiple bool) *Accordion {
+	a.multiple = multiple
+	if !multiple {
+		for _, it := range a.items {
+			if it.expanded {
+				a.collapseOthers(it, false)
+				break
+			}
+		}
+	}
+	return a
+}
+
+// collapseOthers collapses every expander but keep in single-open mode,
+// animating and notifying them if the user expanded keep.
+func (a *Accordion) collapseOthers(keep *Expander, user bool) {
+	if a.multiple {
+		return
+	}
+	for _, it := range a.items {
+		if it == keep || !it.expanded {
+			continue
+		}
+		if user {
+			it.setExpanded(false)
+		} else {
+			it.Expanded(false)
+		}
+	}
+}
+
+// Layout implements core.Widget.
+func (a *Accordion) Layout(ctx *core.LayoutContext) core.Size {
+	c := core.Constraints{MaxWidth: ctx.Constraints.MaxWidth, MaxHeight: core.Infinity}
+	a.sizes = a.sizes[:0]
+	var size core.Size
+	for _, it := range a.items {
+		sz := ctx.Measure(it, c)
+		a.sizes = append(a.sizes, sz)
+		size.Width = max(size.Width, sz.Width)
+		size.Height += sz.Height
+	}
+	if ctx.Constraints.HasBoundedWidth() {
+		size.Width = ctx.Constraints.MaxWidth
+	}
+	children := make([]core.Widget, len(a.items))
+	for i, it := range a.items {
+		children[i] = it
+	}
+	a.SetChildren(children...)
+	return ctx.Constraints.Constrain(size)
+}
+
+// SetBounds implements core.Widget.
+func (a *Accordion) SetBounds(r core.Rect) {
+	a.WidgetBase.SetBounds(r)
+	y := r.Y
+	for i, it := range a.items {
+		h := a.sizes[i].Height
+		it.SetBounds(core.R(r.X, y, r.Width, h))
+		y += h
+	}
+}
+
+// Paint implements core.Widget.
+func (a *Accordion) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	cv.Save()
+	cv.Clip(a.Bounds())
+	line := core.Filled(th.Colors.Outline.WithAlpha(0.4))
+	for i, it := range a.items {
+		b := it.Bounds()
+		if i > 0 {
+			cv.DrawRect(core.R(b.X, b.Y, b.Width, 1), line)
+		}
+		it.Paint(ctx)
+	}
+	cv.Restore()
+}
+
+// HandleEvent implements core.Widget.
+func (a *Accordion) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	e, ok := ev.(event.KeyEvent)
+	if !ok || e.Type != event.KeyPress || e.Modifiers != 0 || len(a.items) == 0 {
+		return core.Ignored
+	}
+	cur := -1
+	for i, it := range a.items {
+		if it.IsFocused() {
+			cur = i
+		}
+	}
+	if cur < 0 {
+		return core.Ignored
+	}
+	next := cur
+	switch e.Key {
+	case event.KeyUp:
+		next = max(0, cur-1)
+	case event.KeyDown:
+		next = min(len(a.items)-1, cur+1)
+	case event.KeyHome:
+		next = 0
+	case event.KeyEnd:
+		next = len(a.items) - 1
+	default:
+		return core.Ignored
+	}
+	ctx.RequestFocus(a.items[next])
+	return core.Handled
+}
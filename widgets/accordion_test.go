@@ -0,0 +1,131 @@
+package widgets
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+)
+
+// expandedOf returns whether each of a's expanders is expanded.
+func expandedOf(a *Accordion) []bool {
+	var open []bool
+	for _, it := range a.Items() {
+		open = append(open, it.IsExpanded())
+	}
+	return open
+}
+
+func TestAccordionSingleOpen(t *testing.T) {
+	first, _ := section("General")
+	second, _ := section("Network")
+	third, _ := section("Privacy")
+	var toggled []string
+	for _, e := range []*Expander{first, second, third} {
+		e.OnToggle(func(expanded bool) {
+			if expanded {
+				toggled = append(toggled, "+"+e.Title())
+			} else {
+				toggled = append(toggled, "-"+e.Title())
+			}
+		})
+	}
+	a := NewAccordion(first.Expanded(true), second.Expanded(true), third)
+	if got := expandedOf(a); !slices.Equal(got, []bool{false, true, false}) {
+		t.Errorf("adding two expanded keeps %v, want the last one", got)
+	}
+
+	tests := []struct {
+		name    string
+		change  func()
+		open    []bool
+		toggled []string
+	}{
+		{"expand", third.Toggle, []bool{false, false, true}, []string{"-Network", "+Privacy"}},
+		{"collapse", third.Toggle, []bool{false, false, false}, []string{"-Privacy"}},
+		{"quietly", func() { first.Expanded(true) }, []bool{true, false, false}, nil},
+		{"multiple", func() { a.Multiple(true); second.Toggle() }, []bool{true, true, false}, []string{"+Network"}},
+		{"single again", func() { a.Multiple(false) }, []bool{true, false, false}, nil},
+	}
+	for _, tt := range tests {
+		toggled = nil
+		tt.change()
+		if got := expandedOf(a); !slices.Equal(got, tt.open) || !equalStrings(toggled, tt.toggled) {
+			t.Errorf("%s: open %v toggling %q, want %v and %q", tt.name, got, toggled, tt.open, tt.toggled)
+		}
+	}
+}
+
+func TestAccordionLayout(t *testing.T) {
+	first, _ := section("General")
+	second, _ := section("Network")
+	a := NewAccordion(first, second.Expanded(true))
+	ctx := core.NewContext()
+	ctx.SetReducedMotion(true)
+	ctx.LayoutRoot(a, core.R(0, 0, 300, 400))
+	header := first.header.Height
+	if f, s := first.Bounds(), second.Bounds(); f != core.R(0, 0, 300, header) || s != core.R(0, header, 300, header+100+expanderPadding) {
+		t.Errorf("the expanders at %v and %v", f, s)
+	}
+	lc := &core.LayoutContext{Context: ctx}
+	if got := lc.Measure(a, core.Loose(core.Sz(300, 400))); got != core.Sz(300, 2*header+100+expanderPadding) {
+		t.Errorf("size %v", got)
+	}
+
+	// A click on the first collapses the second in the same layout.
+	ctx.LayoutRoot(a, core.R(0, 0, 300, 400))
+	click(ctx, first, first.header.Center())
+	ctx.LayoutRoot(a, core.R(0, 0, 300, 400))
+	if f, s := first.Bounds(), second.Bounds(); f.Height != header+100+expanderPadding || s.Y != f.Bottom() || s.Height != header {
+		t.Errorf("after clicking the first the expanders at %v and %v", f, s)
+	}
+
+	cv := &textCanvas{}
+	a.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+	if !equalStrings(cv.texts, []string{"General", "Network"}) {
+		t.Errorf("drew %q", cv.texts)
+	}
+	ops := &opCanvas{}
+	a.Paint(&core.PaintContext{Context: ctx, Canvas: ops})
+	if divider := fmt.Sprintf("rect 0 %v 300 1", second.Bounds().Y); !slices.Contains(ops.ops, divider) {
+		t.Errorf("drew %q without the divider above the second expander", ops.ops)
+	}
+}
+
+func TestAccordionKeys(t *testing.T) {
+	items := make([]*Expander, 3)
+	for i := range items {
+		items[i], _ = section(string(rune('A' + i)))
+	}
+	a := NewAccordion(items...)
+	ctx := layoutAt(a, core.R(0, 0, 300, 400))
+	if r := a.HandleEvent(ctx, press(event.KeyDown, 0)); r != core.Ignored {
+		t.Error("an accordion without a focused header handled Down")
+	}
+	ctx.RequestFocus(items[0])
+	tests := []struct {
+		key   event.Key
+		focus int
+	}{
+		{event.KeyDown, 1},
+		{event.KeyDown, 2},
+		{event.KeyDown, 2},
+		{event.KeyUp, 1},
+		{event.KeyHome, 0},
+		{event.KeyUp, 0},
+		{event.KeyEnd, 2},
+	}
+	for _, tt := range tests {
+		if r := a.HandleEvent(ctx, press(tt.key, 0)); r != core.Handled || !ctx.IsFocused(items[tt.focus]) {
+			t.Errorf("after %v the focus is on %v, want %s", tt.key, ctx.Focused(), items[tt.focus].Title())
+		}
+	}
+	if r := a.HandleEvent(ctx, press(event.KeyDown, event.ModShift)); r != core.Ignored {
+		t.Error("Shift+Down was handled")
+	}
+	if r := a.HandleEvent(ctx, press(event.KeyA, 0)); r != core.Ignored {
+		t.Error("a letter was handled")
+	}
+}
@@ -0,0 +1,419 @@
+package widgets
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/gogpu/ui/anim"
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/state"
+	"github.com/gogpu/ui/theme"
+)
+
+// AnimatedIcon is a vector icon with named states whose paths morph into
+// each other when a signal changes to another state's name:
+//
+//	mode := state.New("menu")
+//	icon := widgets.MenuIcon(mode)
+//	mode.Set("back") // the bars turn into an arrow
+//
+// The paths of a state are in a square view box, 24 units on a side by
+// default, and are filled, or stroked with round ends given a Stroke.
+// Paths are paired in order between states, and their contours too, so
+// icons morph best when each state draws the same number of contours
+// with points in matching places; missing contours grow from and shrink
+// to the middle of their partner, and contours of fewer segments are
+// subdivided to match. A state may also be rotated, which animates with
+// the morph. A change during a morph turns it toward the new state from
+// where it is.
+type AnimatedIcon struct {
+	core.WidgetBase
+
+	sig    *state.Signal[string]
+	states map[string]iconState
+	box    float32
+	size   float32
+	color  core.Color
+	stroke float32
+	dur    time.Duration
+	curve  anim.Curve
+
+	cancel   func()
+	ctl      *anim.Controller
+	name     string
+	from, to iconState
+	shown    iconState
+	hasShown bool
+}
+
+const animatedIconDuration = 300 * time.Millisecond
+
+// NewAnimatedIcon returns an icon showing the state named by the value
+// of sig, with no states until State adds them.
+func NewAnimatedIcon(sig *state.Signal[string]) *AnimatedIcon {
+	return &AnimatedIcon{
+		sig:    sig,
+		states: make(map[string]iconState),
+		box:    24,
+		size:   24,
+		dur:    animatedIconDuration,
+		curve:  anim.Standard,
+	}
+}
+
+// MenuIcon returns an icon of three bars in the state "menu", which turn
+// into an arrow pointing back in "back" and a cross in "close", stroked
+// two units wide.
+func MenuIcon(sig *state.Signal[string]) *AnimatedIcon {
+	line := func(x0, y0, x1, y1 float32) *core.Path {
+		return core.NewPath().MoveTo(core.Pt(x0, y0)).LineTo(core.Pt(x1, y1))
+	}
+	// The arrow points forward, to be turned around by the rotation.
+	return NewAnimatedIcon(sig).Stroke(2).
+		State("menu", line(3, 6, 21, 6), line(3, 12, 21, 12), line(3, 18, 21, 18)).
+		State("back", line(13, 19, 20, 12), line(4, 12, 20, 12), line(13, 5, 20, 12)).
+		Rotate("back", 180).
+		State("close", line(5, 5, 19, 19), line(12, 12, 12, 12), line(5, 19, 19, 5))
+}
+
+// PlayPauseIcon returns a filled triangle in the state "play", which
+// splits into two bars in "pause".
+func PlayPauseIcon(sig *state.Signal[string]) *AnimatedIcon {
+	quad := func(pts ...float32) *core.Path {
+		p := core.NewPath().MoveTo(core.Pt(pts[0], pts[1]))
+		for i := 2; i < len(pts); i += 2 {
+			p.LineTo(core.Pt(pts[i], pts[i+1]))
+		}
+		return p.Close()
+	}
+	// The triangle is cut in two where the bars part, its tip a corner
+	// drawn twice.
+	return NewAnimatedIcon(sig).
+		State("play", quad(8, 5, 13.5, 8.5, 13.5, 15.5, 8, 19), quad(13.5, 8.5, 19, 12, 19, 12, 13.5, 15.5)).
+		State("pause", quad(6, 5, 10, 5, 10, 19, 6, 19), quad(14, 5, 18, 5, 18, 19, 14, 19))
+}
+
+// State adds the state of the given name, drawn with paths in the view
+// box, or replaces its paths.
+func (i *AnimatedIcon) State(name string, paths ...*core.Path) *AnimatedIcon {
+	st := iconState{rotation: i.states[name].rotation}
+	for _, p := range paths {
+		st.contours = append(st.contours, iconContours(p)...)
+	}
+	i.states[name] = st
+	if name == i.name {
+		i.hasShown = false
+	}
+	return i
+}
+
+// Rotate sets the rotation of a state's paths about the middle of the
+// view box, in degrees clockwise.
+func (i *AnimatedIcon) Rotate(name string, degrees float32) *AnimatedIcon {
+	st := i.states[name]
+	st.rotation = degrees
+	i.states[name] = st
+	return i
+}
+
+// ViewBox sets the side of the square the paths are in, 24 by default.
+func (i *AnimatedIcon) ViewBox(side float32) *AnimatedIcon {
+	i.box = max(side, 1)
+	return i
+}
+
+// Size sets the side of the icon, 24 by default.
+func (i *AnimatedIcon) Size(side float32) *AnimatedIcon {
+	i.size = side
+	return i
+}
+
+// Color sets the color of the icon, by default the theme's OnSurface.
+func (i *AnimatedIcon) Color(c core.Color) *AnimatedIcon {
+	i.color = c
+	return i
+}
+
+// Stroke makes the paths stroked at width in view box units instead of
+// filled.
+func (i *AnimatedIcon) Stroke(width float32) *AnimatedIcon {
+	i.stroke = width
+	return i
+}
+
+// Duration sets how long a morph takes, 300 ms by default.
+func (i *AnimatedIcon) Duration(d time.Duration) *AnimatedIcon {
+	i.dur = d
+	return i
+}
+
+// Curve sets the easing of the morphs, anim.Standard by default.
+func (i *AnimatedIcon) Curve(c anim.Curve) *AnimatedIcon {
+	i.curve = c
+	return i
+}
+
+// Release stops following the signal and ends a morph, for when the
+// icon leaves the tree. Laid out again, the icon shows the state the
+// signal names then and follows it from there.
+func (i *AnimatedIcon) Release() {
+	if i.cancel == nil {
+		return
+	}
+	i.cancel()
+	i.cancel = nil
+	if i.ctl != nil {
+		i.ctl.Stop()
+	}
+	i.hasShown = false
+}
+
+// Layout implements core.Widget.
+func (i *AnimatedIcon) Layout(ctx *core.LayoutContext) core.Size {
+	if i.cancel == nil {
+		cx := ctx.Context
+		i.cancel = i.sig.Subscribe(func(string) { cx.Post(func() { i.retarget(cx) }) })
+	}
+	if !i.hasShown {
+		if st, ok := i.states[i.sig.Get()]; ok {
+			i.name, i.shown, i.hasShown = i.sig.Get(), st, true
+		}
+	}
+	return ctx.Constraints.Constrain(core.Sz(i.size, i.size))
+}
+
+// retarget morphs from where the icon is to the state the signal names.
+func (i *AnimatedIcon) retarget(ctx *core.Context) {
+	name := i.sig.Get()
+	st, ok := i.states[name]
+	if i.cancel == nil || !ok || name == i.name {
+		return
+	}
+	i.name = name
+	if !i.hasShown {
+		i.shown, i.hasShown = st, true
+		ctx.Repaint(i)
+		return
+	}
+	if i.ctl == nil {
+		i.ctl = anim.NewController(anim.ClockOf(ctx), i.dur)
+		i.ctl.Listen(func(v float32) {
+			i.shown = i.from.lerp(i.to, v)
+			ctx.Repaint(i)
+		})
+	}
+	i.from, i.to = matchIconStates(i.shown, st)
+	i.ctl.Duration(i.dur).Curve(i.curve).SetProgress(0)
+	i.ctl.Forward()
+}
+
+// Paint implements core.Widget.
+func (i *AnimatedIcon) Paint(ctx *core.PaintContext) {
+	b := i.Bounds()
+	side := min(b.Width, b.Height)
+	if side <= 0 || !i.hasShown {
+		return
+	}
+	color := i.color
+	if color == (core.Color{}) {
+		color = theme.From(ctx.Context).Colors.OnSurface
+	}
+	k := side / i.box
+	origin := core.Pt(b.X+(b.Width-side)/2, b.Y+(b.Height-side)/2)
+	c := i.box / 2
+	sin, cos := math.Sincos(float64(i.shown.rotation) * math.Pi / 180)
+	s, co := float32(sin), float32(cos)
+	tf := func(p core.Point) core.Point {
+		x, y := p.X-c, p.Y-c
+		return core.Pt(origin.X+(c+x*co-y*s)*k, origin.Y+(c+x*s+y*co)*k)
+	}
+	style := core.PathStyle{Fill: color}
+	if i.stroke > 0 {
+		style = core.PathStyle{Stroke: color, StrokeWidth: i.stroke * k, LineCap: core.CapRound, LineJoin: core.JoinRound}
+	}
+	path := core.NewPath()
+	for _, ct := range i.shown.contours {
+		ct.addTo(path, tf)
+	}
+	ctx.Canvas.DrawPath(path, style)
+}
+
+// iconState is the contours of a state of an AnimatedIcon as cubic
+// curves, and its rotation.
+type iconState struct {
+	contours []iconContour
+	rotation float32
+}
+
+func (s iconState) lerp(to iconState, t float32) iconState {
+	out := iconState{contours: make([]iconContour, len(s.contours)), rotation: s.rotation + (to.rotation-s.rotation)*t}
+	for j, c := range s.contours {
+		out.contours[j] = c.lerp(to.contours[j], t)
+	}
+	return out
+}
+
+// iconContour is a contour of cubic segments: the start, then the two
+// control points and end of each segment.
+type iconContour struct {
+	points []core.Point
+	closed bool
+}
+
+func (c iconContour) segments() int {
+	return (len(c.points) - 1) / 3
+}
+
+func (c iconContour) lerp(to iconContour, t float32) iconContour {
+	out := iconContour{points: make([]core.Point, len(c.points)), closed: c.closed}
+	if t >= 0.5 {
+		out.closed = to.closed
+	}
+	for j, p := range c.points {
+		q := to.points[j]
+		out.points[j] = core.Pt(p.X+(q.X-p.X)*t, p.Y+(q.Y-p.Y)*t)
+	}
+	return out
+}
+
+// addTo adds the contour to p, mapped by tf, unless it has shrunk to a
+// point, which strokes would draw as a dot.
+func (c iconContour) addTo(p *core.Path, tf func(core.Point) core.Point) {
+	if !slices.ContainsFunc(c.points, func(q core.Point) bool { return q != c.points[0] }) {
+		return
+	}
+	p.MoveTo(tf(c.points[0]))
+	for j := 1; j+2 < len(c.points); j += 3 {
+		p.CubicTo(tf(c.points[j]), tf(c.points[j+1]), tf(c.points[j+2]))
+	}
+	if c.closed {
+		p.Close()
+	}
+}
+
+// center returns the middle of the contour's points.
+func (c iconContour) center() core.Point {
+	if len(c.points) == 0 {
+		return core.Point{}
+	}
+	lo, hi := c.points[0], c.points[0]
+	for _, p := range c.points {
+		lo = core.Pt(min(lo.X, p.X), min(lo.Y, p.Y))
+		hi = core.Pt(max(hi.X, p.X), max(hi.Y, p.Y))
+	}
+	return core.Pt((lo.X+hi.X)/2, (lo.Y+hi.Y)/2)
+}
+
+// subdivide splits the longest segments in halves until the contour has
+// n of them.
+func (c iconContour) subdivide(n int) iconContour {
+	pts := append([]core.Point(nil), c.points...)
+	if len(pts) == 1 {
+		// A point grows into segments at it.
+		for len(pts) < 1+3*n {
+			pts = append(pts, pts[0])
+		}
+		return iconContour{points: pts, closed: c.closed}
+	}
+	for (len(pts)-1)/3 < n {
+		longest, at := float32(-1), 0
+		for j := 0; j+3 < len(pts); j += 3 {
+			d := pts[j+3].Sub(pts[j])
+			if l := d.X*d.X + d.Y*d.Y; l > longest {
+				longest, at = l, j
+			}
+		}
+		p0, p1, p2, p3 := pts[at], pts[at+1], pts[at+2], pts[at+3]
+		mid := func(a, b core.Point) core.Point { return core.Pt((a.X+b.X)/2, (a.Y+b.Y)/2) }
+		a, b, cc := mid(p0, p1), mid(p1, p2), mid(p2, p3)
+		d, e := mid(a, b), mid(b, cc)
+		m := mid(d, e)
+		pts = append(pts[:at+1], append([]core.Point{a, d, m, e, cc, p3}, pts[at+4:]...)...)
+	}
+	return iconContour{points: pts, closed: c.closed}
+}
+
+// matchIconStates returns a and b with as many contours as each other,
+// and of as many segments as their partners, so they interpolate.
+func matchIconStates(a, b iconState) (iconState, iconState) {
+	n := max(len(a.contours), len(b.contours))
+	ma := iconState{contours: make([]iconContour, n), rotation: a.rotation}
+	mb := iconState{contours: make([]iconContour, n), rotation: b.rotation}
+	for j := range n {
+		var ca, cb iconContour
+		if j < len(a.contours) {
+			ca = a.contours[j]
+		}
+		if j < len(b.contours) {
+			cb = b.contours[j]
+		}
+		if len(ca.points) == 0 {
+			ca = iconContour{points: []core.Point{cb.center()}}
+		}
+		if len(cb.points) == 0 {
+			cb = iconContour{points: []core.Point{ca.center()}}
+		}
+		segs := max(ca.segments(), cb.segments())
+		ma.contours[j], mb.contours[j] = ca.subdivide(segs), cb.subdivide(segs)
+	}
+	return ma, mb
+}
+
+// iconContours returns the contours of p as cubic curves, with lines and
+// quadratic curves raised to cubics and closed contours ending at their
+// start.
+func iconContours(p *core.Path) []iconContour {
+	var out []iconContour
+	var cur iconContour
+	end := func() {
+		if len(cur.points) > 0 {
+			out = append(out, cur)
+		}
+		cur = iconContour{}
+	}
+	last := func() core.Point {
+		if len(cur.points) == 0 {
+			return core.Point{}
+		}
+		return cur.points[len(cur.points)-1]
+	}
+	line := func(to core.Point) {
+		from := last()
+		d := to.Sub(from)
+		cur.points = append(cur.points, from.Add(d.Scale(1.0/3)), from.Add(d.Scale(2.0/3)), to)
+	}
+	for _, s := range p.Segments {
+		pts := s.Points
+		if s.Verb != core.MoveTo && len(cur.points) == 0 {
+			cur.points = append(cur.points, core.Point{})
+		}
+		switch s.Verb {
+		case core.MoveTo:
+			end()
+			cur.points = append(cur.points, pts[0])
+		case core.LineTo:
+			line(pts[0])
+		case core.QuadTo:
+			from := last()
+			cur.points = append(cur.points,
+				from.Add(pts[0].Sub(from).Scale(2.0/3)),
+				pts[1].Add(pts[0].Sub(pts[1]).Scale(2.0/3)),
+				pts[1])
+		case core.CubicTo:
+			cur.points = append(cur.points, pts[0], pts[1], pts[2])
+		case core.Close:
+			if start := cur.points[0]; last() != start {
+				line(start)
+			}
+			cur.closed = true
+			start := cur.points[0]
+			end()
+			cur.points = append(cur.points, start)
+		}
+	}
+	if cur.segments() > 0 || len(out) == 0 {
+		end()
+	}
+	return out
+}
@@ -0,0 +1,120 @@
+package widgets
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogpu/ui/anim"
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/state"
+)
+
+// morphed returns an icon on sig morphing linearly over 100ms, laid out
+// at a time of its context's clock.
+func morphed(sig *state.Signal[string]) (*AnimatedIcon, *core.Context, *anim.Clock) {
+	i := MenuIcon(sig).Duration(100 * time.Millisecond).Curve(anim.Linear)
+	ctx := layoutAt(i, core.R(0, 0, 24, 24))
+	clock := anim.ClockOf(ctx)
+	clock.Tick(time.Unix(0, 0))
+	return i, ctx, clock
+}
+
+func TestAnimatedIconMorph(t *testing.T) {
+	sig := state.New("menu")
+	i, ctx, clock := morphed(sig)
+	tick := func(ms int) {
+		clock.Tick(time.Unix(0, 0).Add(time.Duration(ms) * time.Millisecond))
+	}
+	if i.name != "menu" || !i.hasShown || i.shown.rotation != 0 {
+		t.Fatalf("showing %q, rotated %v, want menu", i.name, i.shown.rotation)
+	}
+
+	sig.Set("back")
+	ctx.RunPosted()
+	tick(10)
+	tick(60)
+	if got := i.shown.rotation; got != 90 {
+		t.Errorf("halfway to back: rotated %v, want 90", got)
+	}
+	// The bars turn into an arrow: the middle bar keeps its place while
+	// the others move.
+	if got := i.shown.contours[1].points[0]; got != core.Pt(3.5, 12) {
+		t.Errorf("halfway: the middle bar starts at %v, want (3.5, 12)", got)
+	}
+
+	// Turned around halfway, the icon morphs back from where it is.
+	sig.Set("menu")
+	ctx.RunPosted()
+	tick(70)
+	tick(120)
+	if got := i.shown.rotation; got != 45 {
+		t.Errorf("halfway back: rotated %v, want 45", got)
+	}
+	tick(200)
+	if got := i.shown.rotation; got != 0 || i.name != "menu" {
+		t.Errorf("back at menu: %q rotated %v", i.name, got)
+	}
+
+	// States the icon does not have are ignored.
+	sig.Set("unknown")
+	ctx.RunPosted()
+	if i.name != "menu" || i.ctl.IsRunning() {
+		t.Errorf("showing %q, morphing %v after an unknown state", i.name, i.ctl.IsRunning())
+	}
+}
+
+func TestAnimatedIconRelease(t *testing.T) {
+	tests := []struct {
+		name    string
+		release func(i *AnimatedIcon)
+		follows bool
+	}{
+		{"bound", func(*AnimatedIcon) {}, true},
+		{"released", (*AnimatedIcon).Release, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sig := state.New("menu")
+			i, ctx, _ := morphed(sig)
+			tt.release(i)
+			sig.Set("close")
+			if got := ctx.HasPosted(); got != tt.follows {
+				t.Errorf("HasPosted() = %v after a change, want %v", got, tt.follows)
+			}
+			ctx.RunPosted()
+			if got := i.ctl != nil && i.ctl.IsRunning(); got != tt.follows {
+				t.Errorf("morphing %v, want %v", got, tt.follows)
+			}
+		})
+	}
+}
+
+func TestAnimatedIconRebind(t *testing.T) {
+	sig := state.New("menu")
+	i, ctx, clock := morphed(sig)
+	sig.Set("back")
+	ctx.RunPosted()
+	clock.Tick(time.Unix(0, 0).Add(10 * time.Millisecond))
+	clock.Tick(time.Unix(0, 0).Add(60 * time.Millisecond))
+
+	// Released mid-morph, the icon stops; laid out again, it shows the
+	// state of the signal then, without morphing, and follows it again.
+	i.Release()
+	if i.ctl.IsRunning() {
+		t.Error("the morph runs on after Release")
+	}
+	sig.Set("close")
+	ctx.Invalidate()
+	ctx.LayoutRoot(i, core.R(0, 0, 24, 24))
+	if i.name != "close" || i.shown.rotation != 0 || i.ctl.IsRunning() {
+		t.Errorf("showing %q rotated %v, morphing %v, want close at once", i.name, i.shown.rotation, i.ctl.IsRunning())
+	}
+	sig.Set("menu")
+	if !ctx.HasPosted() {
+		t.Error("the icon does not follow the signal again")
+	}
+	ctx.RunPosted()
+	if i.name != "menu" || !i.ctl.IsRunning() {
+		t.Errorf("showing %q, morphing %v, want a morph to menu", i.name, i.ctl.IsRunning())
+	}
+}
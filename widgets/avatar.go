@@ -0,0 +1,174 @@
+package widgets
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/theme"
+)
+
+const defaultAvatarSize float32 = 40
+
+// Avatar shows a person as a round picture. Without a picture, or while
+// it loads or if it fails, the initials of the name are shown on a tinted
+// circle, and without a name a generic silhouette.
+//
+// Pictures are drawn in a square and rounded off by painting the
+// background color over the corners, so Background must match what is
+// behind the avatar when it is not the theme's surface color.
+type Avatar struct {
+	core.WidgetBase
+
+	name     string
+	initials string
+	image    *Image
+	size     float32
+	square   bool
+	color    core.Color
+	bg       core.Color
+}
+
+// NewAvatar returns an avatar for the person called name, which may be
+// empty.
+func NewAvatar(name string) *Avatar {
+	a := &Avatar{size: defaultAvatarSize}
+	a.SetName(name)
+	return a
+}
+
+// Name returns the name.
+func (a *Avatar) Name() string {
+	return a.name
+}
+
+// SetName replaces the name and the initials derived from it: the first
+// letters of the first and last words.
+func (a *Avatar) SetName(name string) {
+	a.name = name
+	words := strings.FieldsFunc(name, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+	a.initials = ""
+	if len(words) > 0 {
+		a.initials = firstLetter(words[0])
+		if len(words) > 1 {
+			a.initials += firstLetter(words[len(words)-1])
+		}
+	}
+}
+
+func firstLetter(s string) string {
+	r, _ := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r))
+}
+
+// Image sets the picture, loaded like an Image.
+func (a *Avatar) Image(src ImageSource) *Avatar {
+	if a.image == nil {
+		a.image = NewImage(src).Fit(ImageCover)
+		a.SetChildren(a.image)
+	} else {
+		a.image.SetSource(src)
+	}
+	return a
+}
+
+// Size sets the diameter. The default is 40.
+func (a *Avatar) Size(d float32) *Avatar {
+	a.size = max(0, d)
+	return a
+}
+
+// Square sets whether the avatar is a rounded square rather than a
+// circle, as is usual for groups and organizations.
+func (a *Avatar) Square(on bool) *Avatar {
+	a.square = on
+	return a
+}
+
+// Color sets the tint behind the initials, which is otherwise the theme's
+// primary container color.
+func (a *Avatar) Color(c core.Color) *Avatar {
+	a.color = c
+	return a
+}
+
+// Background sets the color painted over the corners of a picture.
+func (a *Avatar) Background(c core.Color) *Avatar {
+	a.bg = c
+	return a
+}
+
+func (a *Avatar) radius() float32 {
+	if a.square {
+		return a.size / 4
+	}
+	return a.size / 2
+}
+
+// Layout implements core.Widget.
+func (a *Avatar) Layout(ctx *core.LayoutContext) core.Size {
+	sz := ctx.Constraints.Constrain(core.Sz(a.size, a.size))
+	if a.image != nil {
+		ctx.Measure(a.image, core.Tight(sz))
+	}
+	return sz
+}
+
+// SetBounds implements core.Widget.
+func (a *Avatar) SetBounds(r core.Rect) {
+	a.WidgetBase.SetBounds(r)
+	if a.image != nil {
+		a.image.SetBounds(r)
+	}
+}
+
+// Paint implements core.Widget.
+func (a *Avatar) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	b := a.Bounds()
+	d := min(b.Width, b.Height)
+	b = core.R(b.X+(b.Width-d)/2, b.Y+(b.Height-d)/2, d, d)
+	radius := min(a.radius(), d/2)
+
+	if a.image != nil && a.image.Image() != nil {
+		cv.Save()
+		exact := core.ClipRoundedRect(cv, b, radius)
+		a.image.Paint(ctx)
+		cv.Restore()
+		if !exact {
+			// Without path clips the corners are painted over instead.
+			bg := a.bg
+			if bg.IsTransparent() {
+				bg = th.Colors.Surface
+			}
+			corners := core.NewPath().AddRect(b).AddRoundedRect(b, radius)
+			cv.DrawPath(corners, core.PathStyle{Fill: bg, FillRule: core.FillEvenOdd})
+		}
+		return
+	}
+
+	tint := a.color
+	if tint.IsTransparent() {
+		tint = th.Colors.PrimaryContainer
+	}
+	if a.initials == "" {
+		cv.DrawRoundedRect(b, radius, core.Filled(th.Colors.SurfaceVariant))
+		// A head above the shoulders, clipped by the circle at the bottom.
+		c := th.Colors.OnSurfaceVariant
+		head := d * 0.36
+		cv.DrawRoundedRect(core.R(b.X+(d-head)/2, b.Y+d*0.18, head, head), head/2, core.Filled(c))
+		body := core.NewPath().AddEllipse(core.R(b.X+d*0.2, b.Y+d*0.6, d*0.6, d*0.5))
+		cv.Save()
+		cv.Clip(core.R(b.X, b.Y, d, d*0.92))
+		cv.DrawPath(body, core.PathStyle{Fill: c})
+		cv.Restore()
+		return
+	}
+	cv.DrawRoundedRect(b, radius, core.Filled(tint))
+	style := theme.TextStyle(th.Typography.Label, th.Colors.OnSurface)
+	style.Size = d * 0.4
+	sz := ctx.MeasureText(a.initials, style)
+	cv.DrawText(a.initials, core.Pt(b.X+(d-sz.Width)/2, b.Y+(d-style.LineHeight())/2), style)
+}
@@ -0,0 +1,106 @@
+package widgets
+
+import (
+	"image"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+)
+
+// flatCanvas hides the path clipping of the canvas it wraps.
+type flatCanvas struct {
+	core.Canvas
+}
+
+func TestAvatarInitials(t *testing.T) {
+	tests := []struct {
+		name     string
+		initials string
+	}{
+		{"Ada Lovelace", "AL"},
+		{"grace brewster murray hopper", "GH"},
+		{"Prince", "P"},
+		{"  o'neil, shaquille ", "OS"},
+		{"Émile Zola", "ÉZ"},
+		{"李 小龙", "李小"},
+		{"--", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		a := NewAvatar(tt.name)
+		if a.initials != tt.initials || a.Name() != tt.name {
+			t.Errorf("%q: initials %q, want %q", tt.name, a.initials, tt.initials)
+		}
+	}
+}
+
+func TestAvatarLayout(t *testing.T) {
+	lc := &core.LayoutContext{Context: core.NewContext()}
+	tests := []struct {
+		name   string
+		avatar *Avatar
+		c      core.Constraints
+		want   core.Size
+	}{
+		{"default", NewAvatar("A"), core.Unbounded(), core.Sz(defaultAvatarSize, defaultAvatarSize)},
+		{"sized", NewAvatar("A").Size(24), core.Unbounded(), core.Sz(24, 24)},
+		{"negative", NewAvatar("A").Size(-5), core.Unbounded(), core.Sz(0, 0)},
+		{"constrained", NewAvatar("A"), core.Loose(core.Sz(30, 100)), core.Sz(30, 40)},
+	}
+	for _, tt := range tests {
+		if got := lc.Measure(tt.avatar, tt.c); got != tt.want {
+			t.Errorf("%s: size %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	a := NewAvatar("A").Image(ImageSource{})
+	first := a.image
+	a.Image(ImageSource{Key: "other"})
+	if a.image != first || len(a.Children()) != 1 {
+		t.Error("a second picture replaced the Image widget")
+	}
+	layoutAt(a, core.R(10, 10, 40, 40))
+	if a.image.Bounds() != core.R(10, 10, 40, 40) {
+		t.Errorf("the picture at %v", a.image.Bounds())
+	}
+}
+
+func TestAvatarPaint(t *testing.T) {
+	paint := func(a *Avatar, bounds core.Rect) *roundCanvas {
+		ctx := layoutAt(a, bounds)
+		cv := &roundCanvas{}
+		a.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+		return cv
+	}
+	// The avatar is centered in wider bounds.
+	cv := paint(NewAvatar("Ada Lovelace"), core.R(0, 0, 60, 40))
+	if !equalStrings(cv.texts, []string{"AL"}) || len(cv.rounded) != 1 || cv.rounded[0] != core.R(10, 0, 40, 40) {
+		t.Errorf("drew %q on %v", cv.texts, cv.rounded)
+	}
+	cv = paint(NewAvatar("Ada").Color(core.Hex(0x336699)), core.R(0, 0, 40, 40))
+	if len(cv.fills) != 1 || cv.fills[0] != core.Hex(0x336699) {
+		t.Errorf("the tint is %v", cv.fills)
+	}
+	cv = paint(NewAvatar(""), core.R(0, 0, 40, 40))
+	if len(cv.texts) != 0 || len(cv.rounded) != 2 {
+		t.Errorf("the silhouette drew %q and %d rounded rectangles", cv.texts, len(cv.rounded))
+	}
+
+	// A loaded picture replaces the initials; canvases without path clips
+	// get the corners painted over.
+	a := NewAvatar("Ada Lovelace").Image(ImageSource{})
+	a.image.SetImage(image.NewRGBA(image.Rect(0, 0, 10, 10)))
+	ctx := layoutAt(a, core.R(0, 0, 40, 40))
+	exact := &roundCanvas{}
+	a.Paint(&core.PaintContext{Context: ctx, Canvas: exact})
+	flat := &pathCanvas{}
+	a.Background(core.White)
+	a.Paint(&core.PaintContext{Context: ctx, Canvas: flatCanvas{flat}})
+	if len(exact.texts) != 0 || flat.Len() != exact.Len()+1 || len(flat.styles) != 1 || flat.styles[0].Fill != core.White {
+		t.Errorf("with a picture drew %q; %d operations without path clips, %d with, paths %v", exact.texts, flat.Len(), exact.Len(), flat.styles)
+	}
+
+	if r := NewAvatar("A").Square(true).radius(); r != defaultAvatarSize/4 {
+		t.Errorf("a square avatar is rounded by %v", r)
+	}
+}
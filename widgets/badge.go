@@ -0,0 +1,145 @@
+package widgets
+
+import (
+	"strconv"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/state"
+	"github.com/gogpu/ui/theme"
+)
+
+const (
+	badgeHeight  float32 = 16
+	badgePadding float32 = 4
+	badgeDot     float32 = 6
+)
+
+// Badge overlays a small count, such as unread messages, on the top-right
+// corner of its child. It is hidden while the count is zero unless
+// ShowZero is set, and counts above the maximum show as "99+". A dot
+// badge shows a plain dot instead of the count.
+//
+// The badge is drawn overlapping the corner of the child and is not
+// included in the size, so adding one does not move the layout.
+type Badge struct {
+	core.WidgetBase
+
+	child    core.Widget
+	count    int
+	max      int
+	dot      bool
+	showZero bool
+
+	sig    *state.Signal[int]
+	cancel func()
+}
+
+// NewBadge returns a badge on child with a count of zero.
+func NewBadge(child core.Widget) *Badge {
+	b := &Badge{child: child, max: 99}
+	b.SetChildren(child)
+	return b
+}
+
+// Count returns the count.
+func (b *Badge) Count() int {
+	return b.count
+}
+
+// SetCount sets the count.
+func (b *Badge) SetCount(n int) {
+	b.count = n
+}
+
+// Bind makes the badge show the value of sig.
+func (b *Badge) Bind(sig *state.Signal[int]) *Badge {
+	b.Release()
+	b.sig = sig
+	return b
+}
+
+// Release stops following the bound signal, for when the badge leaves
+// the tree. Laid out again, the badge follows the signal from its value
+// then.
+func (b *Badge) Release() {
+	if b.cancel != nil {
+		b.cancel()
+		b.cancel = nil
+	}
+}
+
+// Max sets the largest count shown in full; larger counts show as the
+// maximum followed by a plus sign. The default is 99.
+func (b *Badge) Max(n int) *Badge {
+	b.max = max(1, n)
+	return b
+}
+
+// Dot sets whether a plain dot is shown instead of the count. The dot is
+// hidden at zero like the count.
+func (b *Badge) Dot(on bool) *Badge {
+	b.dot = on
+	return b
+}
+
+// ShowZero sets whether the badge stays visible with a count of zero.
+func (b *Badge) ShowZero(on bool) *Badge {
+	b.showZero = on
+	return b
+}
+
+// IsVisible reports whether the badge is drawn.
+func (b *Badge) IsVisible() bool {
+	return b.count > 0 || b.showZero
+}
+
+func (b *Badge) text() string {
+	if b.count > b.max {
+		return strconv.Itoa(b.max) + "+"
+	}
+	return strconv.Itoa(b.count)
+}
+
+// Layout implements core.Widget.
+func (b *Badge) Layout(ctx *core.LayoutContext) core.Size {
+	if b.sig != nil {
+		if b.cancel == nil {
+			c := ctx.Context
+			b.cancel = b.sig.Subscribe(func(int) { c.Post(func() { c.MarkNeedsLayout(b) }) })
+		}
+		b.count = b.sig.Get()
+	}
+	return ctx.Measure(b.child, ctx.Constraints)
+}
+
+// SetBounds implements core.Widget.
+func (b *Badge) SetBounds(r core.Rect) {
+	b.WidgetBase.SetBounds(r)
+	b.child.SetBounds(r)
+}
+
+// Paint implements core.Widget.
+func (b *Badge) Paint(ctx *core.PaintContext) {
+	b.child.Paint(ctx)
+	if !b.IsVisible() {
+		return
+	}
+	th := theme.From(ctx.Context)
+	r := b.Bounds()
+	if b.dot {
+		d := core.R(r.Right()-badgeDot, r.Y, badgeDot, badgeDot)
+		ctx.Canvas.DrawRoundedRect(d, badgeDot/2, core.Filled(th.Colors.Error))
+		return
+	}
+	style := theme.TextStyle(th.Typography.Caption, th.Colors.OnError)
+	style.Size = min(style.Size, 11)
+	s := b.text()
+	tw := ctx.MeasureText(s, style).Width
+	w := max(badgeHeight, tw+2*badgePadding)
+	// Single digits are centered on the corner; longer counts grow to the
+	// left.
+	x := min(r.Right()-badgeHeight/2, r.Right()+badgeHeight/2-w)
+	bubble := core.R(x, r.Y-badgeHeight/2+2, w, badgeHeight)
+	ctx.Canvas.DrawRoundedRect(bubble, badgeHeight/2, core.Filled(th.Colors.Error))
+	ctx.Canvas.DrawText(s, core.Pt(bubble.X+(w-tw)/2, bubble.Y+(badgeHeight-style.LineHeight())/2), style)
+}
@@ -0,0 +1,107 @@
+package widgets
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/state"
+)
+
+func TestBadgeText(t *testing.T) {
+	tests := []struct {
+		name    string
+		badge   *Badge
+		count   int
+		visible bool
+		text    string
+	}{
+		{"zero", NewBadge(icon()), 0, false, "0"},
+		{"zero shown", NewBadge(icon()).ShowZero(true), 0, true, "0"},
+		{"count", NewBadge(icon()), 7, true, "7"},
+		{"maximum", NewBadge(icon()), 99, true, "99"},
+		{"over", NewBadge(icon()), 100, true, "99+"},
+		{"custom maximum", NewBadge(icon()).Max(9), 10, true, "9+"},
+		{"maximum below one", NewBadge(icon()).Max(0), 2, true, "1+"},
+	}
+	for _, tt := range tests {
+		tt.badge.SetCount(tt.count)
+		if tt.badge.Count() != tt.count || tt.badge.IsVisible() != tt.visible || tt.badge.text() != tt.text {
+			t.Errorf("%s: visible %v reading %q, want %v and %q", tt.name, tt.badge.IsVisible(), tt.badge.text(), tt.visible, tt.text)
+		}
+	}
+}
+
+func TestBadgeLayout(t *testing.T) {
+	child := icon()
+	b := NewBadge(child)
+	lc := &core.LayoutContext{Context: core.NewContext()}
+	if got := lc.Measure(b, core.Loose(core.Sz(30, 30))); got != core.Sz(30, toolIconSize) {
+		t.Errorf("size %v, want the child's", got)
+	}
+	layoutAt(b, core.R(5, 5, 30, 30))
+	if child.Bounds() != core.R(5, 5, 30, 30) {
+		t.Errorf("the child at %v", child.Bounds())
+	}
+
+	sig := state.New(3)
+	b.Bind(sig)
+	ctx := layoutAt(b, core.R(0, 0, 30, 30))
+	if b.Count() != 3 {
+		t.Fatalf("count %d, want the signal's", b.Count())
+	}
+	sig.Set(5)
+	runPosted(t, ctx)
+	ctx.LayoutRoot(b, core.R(0, 0, 30, 30))
+	if b.Count() != 5 {
+		t.Errorf("count %d after the signal changed", b.Count())
+	}
+	b.Bind(nil)
+	sig.Set(6)
+	if ctx.HasPosted() {
+		t.Error("an unbound signal still notifies the badge")
+	}
+}
+
+// roundCanvas records the rounded rectangles drawn on it and their fills.
+type roundCanvas struct {
+	textCanvas
+	rounded []core.Rect
+	fills   []core.Color
+}
+
+func (c *roundCanvas) DrawRoundedRect(r core.Rect, radius float32, st core.RectStyle) {
+	c.rounded = append(c.rounded, r)
+	c.fills = append(c.fills, st.Fill)
+	c.Recording.DrawRoundedRect(r, radius, st)
+}
+
+func TestBadgePaint(t *testing.T) {
+	paint := func(b *Badge) *roundCanvas {
+		ctx := layoutAt(b, core.R(0, 0, 30, 30))
+		cv := &roundCanvas{}
+		b.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+		return cv
+	}
+	if cv := paint(NewBadge(icon())); cv.Len() != 0 {
+		t.Errorf("a badge of zero drew %d operations", cv.Len())
+	}
+	tests := []struct {
+		name   string
+		count  int
+		dot    bool
+		texts  []string
+		bubble func(core.Rect) bool
+	}{
+		{"digit", 3, false, []string{"3"}, func(r core.Rect) bool { return r.X == 30-badgeHeight/2 && r.Width == badgeHeight }},
+		{"number", 1234, false, []string{"99+"}, func(r core.Rect) bool { return r.Right() == 30+badgeHeight/2 && r.Width > badgeHeight }},
+		{"dot", 3, true, nil, func(r core.Rect) bool { return r == core.R(30-badgeDot, 0, badgeDot, badgeDot) }},
+	}
+	for _, tt := range tests {
+		b := NewBadge(icon()).Dot(tt.dot)
+		b.SetCount(tt.count)
+		cv := paint(b)
+		if !equalStrings(cv.texts, tt.texts) || len(cv.rounded) != 1 || !tt.bubble(cv.rounded[0]) {
+			t.Errorf("%s: drew %q on %v", tt.name, cv.texts, cv.rounded)
+		}
+	}
+}
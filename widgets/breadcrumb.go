@@ -0,0 +1,395 @@
+package widgets
+
+import (
+	"strings"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/theme"
+)
+
+const (
+	breadcrumbPadding   float32 = 6
+	breadcrumbSeparator float32 = 16
+	breadcrumbMoreWidth float32 = 28
+	breadcrumbEditSpace float32 = 24
+)
+
+// Breadcrumb shows a path, such as the folders leading to the current
+// directory, as a row of clickable segments.
+//
+// Clicking a segment navigates to it: the path is cut after the segment
+// and OnNavigate is called with the new path. When the path is too long,
+// the leading segments are replaced by a button listing them in a menu;
+// the last segment is always shown.
+//
+// An editable breadcrumb turns into a text field holding the whole path
+// when the empty space after the segments is clicked or Ctrl+L is
+// pressed. Enter navigates to the typed path if it parses, Escape and
+// leaving the field cancel. While focused, the arrow keys move between the
+// segments and Enter or Space navigates. In right-to-left text the path
+// runs from the right.
+type Breadcrumb struct {
+	core.WidgetBase
+	core.FocusState
+
+	path       []string
+	onNavigate func(path []string)
+	format     func(path []string) string
+	parse      func(text string) ([]string, error)
+	editing    bool
+	field      *lineField
+
+	widths []float32
+	total  float32
+	dir    core.Direction
+	input  toolInput
+	first  int // First segment shown after the overflow button.
+}
+
+// NewBreadcrumb returns a breadcrumb showing path.
+func NewBreadcrumb(path ...string) *Breadcrumb {
+	b := &Breadcrumb{path: path}
+	b.input.reset()
+	return b
+}
+
+// Path returns the segments shown.
+func (b *Breadcrumb) Path() []string {
+	return b.path
+}
+
+// SetPath replaces the segments shown.
+func (b *Breadcrumb) SetPath(path ...string) {
+	b.path = path
+	b.input.reset()
+}
+
+// OnNavigate sets the function called with the new path when the user
+// clicks a segment or enters a path.
+func (b *Breadcrumb) OnNavigate(fn func(path []string)) *Breadcrumb {
+	b.onNavigate = fn
+	return b
+}
+
+// Editable enables the edit mode. format renders the path as text,
+// joining the segments with slashes if nil; parse splits typed text into
+// segments or rejects it with an error.
+func (b *Breadcrumb) Editable(format func(path []string) string, parse func(text string) ([]string, error)) *Breadcrumb {
+	if format == nil {
+		format = func(path []string) string { return strings.Join(path, "/") }
+	}
+	b.format, b.parse = format, parse
+	return b
+}
+
+// IsEditing reports whether the path is being typed.
+func (b *Breadcrumb) IsEditing() bool {
+	return b.editing
+}
+
+// Edit switches to the edit mode, with the path selected.
+func (b *Breadcrumb) Edit(ctx *core.Context) {
+	if b.parse == nil || b.editing {
+		return
+	}
+	b.input.closeMenu(ctx)
+	if b.field == nil {
+		b.field = newLineField(0, b.commit)
+	}
+	b.field.dirty, b.field.invalid = false, false
+	b.field.setText(b.format(b.path))
+	b.field.line.SelectAll()
+	// Enter commits even an unchanged path.
+	b.field.dirty = true
+	b.editing = true
+	b.SetChildren(b.field)
+	ctx.RequestFocus(b.field)
+	ctx.MarkNeedsLayout(b)
+}
+
+func (b *Breadcrumb) commit(ctx *core.Context, text string) bool {
+	path, err := b.parse(text)
+	if err != nil {
+		return false
+	}
+	b.stopEditing(ctx)
+	b.navigate(path)
+	return true
+}
+
+func (b *Breadcrumb) stopEditing(ctx *core.Context) {
+	b.editing = false
+	b.SetChildren()
+	if b.field.IsFocused() {
+		ctx.RequestFocus(b)
+	}
+	ctx.MarkNeedsLayout(b)
+}
+
+func (b *Breadcrumb) navigate(path []string) {
+	b.SetPath(path...)
+	if b.onNavigate != nil {
+		b.onNavigate(path)
+	}
+}
+
+func (b *Breadcrumb) navigateTo(i int) {
+	b.navigate(b.path[: i+1 : i+1])
+}
+
+// Layout implements core.Widget.
+func (b *Breadcrumb) Layout(ctx *core.LayoutContext) core.Size {
+	if b.editing && !b.field.IsFocused() {
+		b.stopEditing(ctx.Context)
+	}
+	b.dir = ctx.Direction()
+	style := theme.From(ctx.Context).Typography.Body
+	b.widths = b.widths[:0]
+	b.total = 0
+	for i, s := range b.path {
+		w := ctx.MeasureText(s, style).Width + 2*breadcrumbPadding
+		if i > 0 {
+			w += breadcrumbSeparator
+		}
+		b.widths = append(b.widths, w)
+		b.total += w
+	}
+	size := core.Sz(b.total, fieldHeight)
+	if ctx.Constraints.HasBoundedWidth() {
+		size.Width = ctx.Constraints.MaxWidth
+	}
+	size = ctx.Constraints.Constrain(size)
+	if b.editing {
+		ctx.Measure(b.field, core.Tight(size))
+	}
+	return size
+}
+
+// SetBounds implements core.Widget.
+func (b *Breadcrumb) SetBounds(r core.Rect) {
+	b.WidgetBase.SetBounds(r)
+	if b.editing {
+		b.field.SetBounds(r)
+	}
+	b.input.targets = b.input.targets[:0]
+	if len(b.path) == 0 {
+		return
+	}
+	avail := r.Width
+	if b.parse != nil {
+		avail -= breadcrumbEditSpace
+	}
+	b.first = 0
+	if b.total > avail {
+		avail -= breadcrumbMoreWidth
+		w := b.widths[len(b.path)-1]
+		b.first = len(b.path) - 1
+		for b.first > 0 && w+b.widths[b.first-1] <= avail {
+			b.first--
+			w += b.widths[b.first]
+		}
+	}
+	x := r.X
+	if b.first > 0 {
+		b.input.targets = append(b.input.targets, toolTarget{rect: b.dir.Mirror(core.R(x, r.Y, breadcrumbMoreWidth, r.Height), r)})
+		x += breadcrumbMoreWidth
+	}
+	for i := b.first; i < len(b.path); i++ {
+		w := b.widths[i]
+		if i > 0 {
+			// The separator before the segment is not part of its button.
+			x += breadcrumbSeparator
+			w -= breadcrumbSeparator
+		}
+		w = min(w, max(0, r.Right()-x))
+		b.input.targets = append(b.input.targets, toolTarget{rect: b.dir.Mirror(core.R(x, r.Y, w, r.Height), r)})
+		x += w
+	}
+}
+
+// segment returns the index in the path of target i, or -1 for the
+// overflow button.
+func (b *Breadcrumb) segment(i int) int {
+	if b.first > 0 {
+		i--
+	}
+	if i < 0 {
+		return -1
+	}
+	return b.first + i
+}
+
+// Paint implements core.Widget.
+func (b *Breadcrumb) Paint(ctx *core.PaintContext) {
+	if b.editing {
+		b.field.Paint(ctx)
+		return
+	}
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	for i, tg := range b.input.targets {
+		b.input.paintState(ctx, i, b.IsFocused())
+		r := tg.rect
+		s := b.segment(i)
+		if s < 0 {
+			paintMoreDots(ctx, r, th.Colors.OnSurfaceVariant)
+			continue
+		}
+		if s > 0 {
+			sep := core.R(r.X-breadcrumbSeparator, r.Y, breadcrumbSeparator, r.Height)
+			if b.dir.IsRTL() {
+				sep.X = r.Right()
+			}
+			paintDisclosure(ctx, sep, false, th.Colors.OnSurfaceVariant)
+		}
+		color := th.Colors.OnSurfaceVariant
+		if s == len(b.path)-1 {
+			color = th.Colors.OnSurface
+		}
+		style := theme.TextStyle(th.Typography.Body, color)
+		cv.Save()
+		cv.Clip(r)
+		x := r.X + breadcrumbPadding
+		if b.dir.IsRTL() {
+			x = r.Right() - breadcrumbPadding - ctx.MeasureText(b.path[s], style).Width
+		}
+		cv.DrawText(b.path[s], core.Pt(x, r.Y+(r.Height-style.LineHeight())/2), style)
+		cv.Restore()
+	}
+}
+
+// pastSegments reports whether p lies in the empty space after the last
+// segment.
+func (b *Breadcrumb) pastSegments(p core.Point) bool {
+	n := len(b.input.targets)
+	if n == 0 {
+		return true
+	}
+	last := b.input.targets[n-1].rect
+	if b.dir.IsRTL() {
+		return p.X < last.X
+	}
+	return p.X >= last.Right()
+}
+
+// activate navigates to the segment of target i or opens the overflow
+// menu.
+func (b *Breadcrumb) activate(ctx *core.Context, i int, keyboard bool) {
+	s := b.segment(i)
+	if s >= 0 {
+		b.input.closeMenu(ctx)
+		b.navigateTo(s)
+		ctx.MarkNeedsLayout(b)
+		return
+	}
+	open := b.input.open
+	b.input.closeMenu(ctx)
+	if open == i {
+		return
+	}
+	// Nearest ancestors first, as file managers list them.
+	var items []*MenuItem
+	for j := b.first - 1; j >= 0; j-- {
+		items = append(items, NewMenuItem(strings.ReplaceAll(b.path[j], "&", "&&"), func() { b.navigateTo(j) }))
+	}
+	sess := openMenu(ctx, items, placeBelow(b.input.targets[i].rect, b.dir), b, keyboard)
+	sess.onClose = func() {
+		if b.input.session == sess {
+			b.input.session, b.input.open = nil, -1
+			ctx.Repaint(b)
+		}
+	}
+	b.input.session, b.input.open = sess, i
+	ctx.Repaint(b)
+}
+
+// HandleEvent implements core.Widget.
+func (b *Breadcrumb) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	if b.editing {
+		if e, ok := ev.(event.KeyEvent); ok && e.Type == event.KeyPress && e.Key == event.KeyEscape {
+			b.stopEditing(ctx)
+			return core.Handled
+		}
+		return core.Ignored
+	}
+	in := &b.input
+	switch e := ev.(type) {
+	case event.MouseEvent:
+		switch e.Type {
+		case event.MouseEnter, event.MouseMove:
+			if i := in.at(e.Position); i != in.hover {
+				in.hover = i
+				ctx.Repaint(b)
+			}
+		case event.MouseLeave:
+			if in.hover >= 0 {
+				in.hover = -1
+				ctx.Repaint(b)
+			}
+		case event.MouseDown:
+			if e.Button != event.ButtonLeft {
+				return core.Ignored
+			}
+			i := in.at(e.Position)
+			switch {
+			case i < 0:
+				in.closeMenu(ctx)
+				if b.pastSegments(e.Position) {
+					b.Edit(ctx)
+				}
+			case b.segment(i) < 0:
+				b.activate(ctx, i, false)
+			default:
+				in.closeMenu(ctx)
+				in.press = i
+				ctx.CapturePointer(b)
+				ctx.Repaint(b)
+			}
+			return core.Handled
+		case event.MouseUp:
+			if in.press < 0 || e.Button != event.ButtonLeft {
+				return core.Ignored
+			}
+			i := in.press
+			in.press = -1
+			ctx.ReleasePointer()
+			ctx.Repaint(b)
+			if i < len(in.targets) && in.targets[i].rect.Contains(e.Position) {
+				b.activate(ctx, i, false)
+			}
+			return core.Handled
+		}
+	case event.KeyEvent:
+		if !b.IsFocused() || e.Type != event.KeyPress {
+			return core.Ignored
+		}
+		switch {
+		case e.Key == event.KeyL && e.Modifiers == event.ModCtrl && b.parse != nil:
+			b.Edit(ctx)
+		case e.Modifiers != 0:
+			return core.Ignored
+		case mirrorKey(b.dir, e.Key) == event.KeyLeft:
+			in.focus = in.focusIndex()
+			in.moveFocus(-1)
+		case mirrorKey(b.dir, e.Key) == event.KeyRight:
+			in.focus = in.focusIndex()
+			in.moveFocus(1)
+		case e.Key == event.KeyHome:
+			in.focus = -1
+			in.moveFocus(1)
+		case e.Key == event.KeyEnd:
+			in.focus = len(in.targets)
+			in.moveFocus(-1)
+		case e.Key == event.KeyEnter || e.Key == event.KeySpace:
+			if i := in.focusIndex(); i >= 0 {
+				b.activate(ctx, i, true)
+			}
+		default:
+			return core.Ignored
+		}
+		ctx.Repaint(b)
+		return core.Handled
+	}
+	return core.Ignored
+}
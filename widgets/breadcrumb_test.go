@@ -0,0 +1,274 @@
+package widgets
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+)
+
+// crumbs returns a breadcrumb showing path, laid out width wide, and the
+// paths it navigated to.
+func crumbs(width float32, path ...string) (*Breadcrumb, *core.Context, *[]string) {
+	var navigated []string
+	b := NewBreadcrumb(path...).OnNavigate(func(p []string) { navigated = append(navigated, strings.Join(p, "/")) })
+	ctx := layoutAt(b, core.R(0, 0, width, fieldHeight))
+	return b, ctx, &navigated
+}
+
+// segmentsShown returns the segments b draws, with "…" for the overflow
+// button.
+func segmentsShown(b *Breadcrumb) []string {
+	var shown []string
+	for i := range b.input.targets {
+		if s := b.segment(i); s >= 0 {
+			shown = append(shown, b.path[s])
+		} else {
+			shown = append(shown, "…")
+		}
+	}
+	return shown
+}
+
+func TestBreadcrumbLayout(t *testing.T) {
+	path := []string{"home", "user", "docs", "src"}
+	lc := &core.LayoutContext{Context: core.NewContext()}
+	full := lc.Measure(NewBreadcrumb(path...), core.Unbounded())
+	tests := []struct {
+		name  string
+		width float32
+		shown []string
+	}{
+		{"fits", full.Width, path},
+		{"overflows", full.Width - 1, []string{"…", "user", "docs", "src"}},
+		{"narrow", 10, []string{"…", "src"}},
+	}
+	for _, tt := range tests {
+		b, _, _ := crumbs(tt.width, path...)
+		if got := segmentsShown(b); !equalStrings(got, tt.shown) {
+			t.Errorf("%s: shown %q, want %q", tt.name, got, tt.shown)
+		}
+		if last := b.input.targets[len(b.input.targets)-1].rect; last.Right() > max(tt.width, last.X) {
+			t.Errorf("%s: the last segment ends at %v, past %v", tt.name, last.Right(), tt.width)
+		}
+	}
+
+	b, _, _ := crumbs(full.Width, path...)
+	first, second := b.input.targets[0].rect, b.input.targets[1].rect
+	if second.X != first.Right()+breadcrumbSeparator {
+		t.Errorf("the second segment at %v, want after the separator at %v", second.X, first.Right())
+	}
+	b.Editable(nil, func(s string) ([]string, error) { return strings.Split(s, "/"), nil })
+	ctx := layoutAt(b, core.R(0, 0, full.Width, fieldHeight))
+	if got := segmentsShown(b); got[0] != "…" {
+		t.Errorf("an editable breadcrumb does not leave room to click: %q", got)
+	}
+
+	empty, ctx, _ := crumbs(100)
+	if len(empty.input.targets) != 0 {
+		t.Errorf("an empty path has %d targets", len(empty.input.targets))
+	}
+	cv := &textCanvas{}
+	empty.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+	if len(cv.texts) != 0 {
+		t.Errorf("an empty path drew %q", cv.texts)
+	}
+}
+
+func TestBreadcrumbNavigate(t *testing.T) {
+	b, ctx, navigated := crumbs(800, "home", "user", "docs")
+	tg := b.input.targets
+	b.HandleEvent(ctx, leftMouse(event.MouseDown, tg[1].rect.Center()))
+	if ctx.PointerCapture() != b || b.input.press != 1 {
+		t.Fatal("a press on a segment was not captured")
+	}
+	b.HandleEvent(ctx, leftMouse(event.MouseUp, tg[2].rect.Center()))
+	if len(*navigated) != 0 {
+		t.Errorf("releasing off the segment navigated to %q", *navigated)
+	}
+	b.HandleEvent(ctx, leftMouse(event.MouseDown, tg[1].rect.Center()))
+	b.HandleEvent(ctx, leftMouse(event.MouseUp, tg[1].rect.Center()))
+	if !equalStrings(*navigated, []string{"home/user"}) || len(b.Path()) != 2 {
+		t.Errorf("navigated to %q, path %q", *navigated, b.Path())
+	}
+	ctx.LayoutRoot(b, core.R(0, 0, 800, fieldHeight))
+	if got := segmentsShown(b); !equalStrings(got, []string{"home", "user"}) {
+		t.Errorf("after navigating shown %q", got)
+	}
+	if r := b.HandleEvent(ctx, event.MouseEvent{Type: event.MouseDown, Button: event.ButtonRight, Position: tg[0].rect.Center()}); r != core.Ignored {
+		t.Error("a right press was handled")
+	}
+
+	b.HandleEvent(ctx, event.MouseEvent{Type: event.MouseMove, Position: tg[0].rect.Center()})
+	if b.input.hover != 0 {
+		t.Errorf("hovering the first segment set %d", b.input.hover)
+	}
+	b.HandleEvent(ctx, event.MouseEvent{Type: event.MouseLeave})
+	if b.input.hover != -1 {
+		t.Errorf("after leaving the hover is %d", b.input.hover)
+	}
+
+	cv := &textCanvas{}
+	b.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+	if !equalStrings(cv.texts, []string{"home", "user"}) {
+		t.Errorf("drew %q", cv.texts)
+	}
+}
+
+func TestBreadcrumbOverflowMenu(t *testing.T) {
+	b, ctx, navigated := crumbs(10, "home", "user", "docs", "src")
+	b.HandleEvent(ctx, leftMouse(event.MouseDown, b.input.targets[0].rect.Center()))
+	popups := layoutOverlays(ctx)
+	if len(popups) != 1 {
+		t.Fatalf("%d menus open", len(popups))
+	}
+	var labels []string
+	for _, it := range popups[0].items {
+		labels = append(labels, it.Label)
+	}
+	if want := []string{"docs", "user", "home"}; !equalStrings(labels, want) {
+		t.Errorf("the menu lists %q, want the nearest first %q", labels, want)
+	}
+	popups[0].activate(ctx, 1)
+	if !equalStrings(*navigated, []string{"home/user"}) {
+		t.Errorf("the menu navigated to %q", *navigated)
+	}
+	ctx.LayoutRoot(b, core.R(0, 0, 10, fieldHeight))
+	if got := segmentsShown(b); !equalStrings(got, []string{"…", "user"}) {
+		t.Errorf("after navigating shown %q", got)
+	}
+
+	// A second press on the button closes the menu.
+	more := b.input.targets[0].rect.Center()
+	b.HandleEvent(ctx, leftMouse(event.MouseDown, more))
+	b.HandleEvent(ctx, leftMouse(event.MouseDown, more))
+	if len(ctx.Overlays()) != 0 {
+		t.Error("the second press left the menu open")
+	}
+}
+
+func TestBreadcrumbKeys(t *testing.T) {
+	b, ctx, navigated := crumbs(800, "home", "user", "docs")
+	if r := b.HandleEvent(ctx, press(event.KeyRight, 0)); r != core.Ignored {
+		t.Error("an unfocused breadcrumb handled a key")
+	}
+	ctx.RequestFocus(b)
+	tests := []struct {
+		key   event.Key
+		focus int
+	}{
+		{event.KeyRight, 1},
+		{event.KeyRight, 2},
+		{event.KeyRight, 0},
+		{event.KeyLeft, 2},
+		{event.KeyHome, 0},
+		{event.KeyEnd, 2},
+	}
+	for _, tt := range tests {
+		if r := b.HandleEvent(ctx, press(tt.key, 0)); r != core.Handled || b.input.focus != tt.focus {
+			t.Errorf("after %v the focus is on %d, want %d", tt.key, b.input.focus, tt.focus)
+		}
+	}
+	b.HandleEvent(ctx, press(event.KeyHome, 0))
+	b.HandleEvent(ctx, press(event.KeyEnter, 0))
+	if !equalStrings(*navigated, []string{"home"}) {
+		t.Errorf("Enter navigated to %q", *navigated)
+	}
+	if r := b.HandleEvent(ctx, press(event.KeyL, event.ModCtrl)); r != core.Ignored || b.IsEditing() {
+		t.Error("Ctrl+L edited a breadcrumb that is not editable")
+	}
+	if r := b.HandleEvent(ctx, press(event.KeyA, 0)); r != core.Ignored {
+		t.Error("a letter was handled")
+	}
+}
+
+func TestBreadcrumbRightToLeft(t *testing.T) {
+	b, _, navigated := crumbs(800, "home", "user", "docs")
+	b.Editable(nil, func(s string) ([]string, error) { return strings.Split(s, "/"), nil })
+	ctx := layoutRTL(b, core.R(0, 0, 800, fieldHeight))
+	first, second := b.input.targets[0].rect, b.input.targets[1].rect
+	if first.Right() != 800 || second.Right() != first.X-breadcrumbSeparator {
+		t.Errorf("segments at %v and %v, want from the right edge", first, second)
+	}
+
+	ctx.RequestFocus(b)
+	b.HandleEvent(ctx, press(event.KeyLeft, 0))
+	if b.input.focus != 1 {
+		t.Errorf("after Left the focus is on %d, want 1", b.input.focus)
+	}
+	b.HandleEvent(ctx, press(event.KeyEnter, 0))
+	if !equalStrings(*navigated, []string{"home/user"}) {
+		t.Errorf("Enter navigated to %q", *navigated)
+	}
+
+	// The empty space after the segments is on their left.
+	b.HandleEvent(ctx, leftMouse(event.MouseDown, core.Pt(790, 10)))
+	if b.IsEditing() {
+		t.Error("a press on the first segment edited the path")
+	}
+	b.HandleEvent(ctx, leftMouse(event.MouseDown, core.Pt(10, 10)))
+	if !b.IsEditing() {
+		t.Error("a press after the segments did not edit the path")
+	}
+}
+
+func TestBreadcrumbEdit(t *testing.T) {
+	bad := errors.New("bad path")
+	parse := func(s string) ([]string, error) {
+		if !strings.HasPrefix(s, "/") {
+			return nil, bad
+		}
+		return strings.Split(s[1:], "/"), nil
+	}
+	format := func(p []string) string { return "/" + strings.Join(p, "/") }
+	b, ctx, navigated := crumbs(800, "home", "user")
+	b.Editable(format, parse)
+	ctx.LayoutRoot(b, core.R(0, 0, 800, fieldHeight))
+
+	// A click after the segments edits the path.
+	b.HandleEvent(ctx, leftMouse(event.MouseDown, core.Pt(790, 10)))
+	if !b.IsEditing() || !ctx.IsFocused(b.field) || b.field.line.Text() != "/home/user" {
+		t.Fatalf("editing %v with %q", b.IsEditing(), b.field.line.Text())
+	}
+	ctx.LayoutRoot(b, core.R(0, 0, 800, fieldHeight))
+	if got := b.field.Bounds(); got != b.Bounds() {
+		t.Errorf("the field at %v, want over the breadcrumb at %v", got, b.Bounds())
+	}
+	if b.commit(ctx, "relative") || !b.IsEditing() {
+		t.Error("a path that does not parse was accepted")
+	}
+	if !b.commit(ctx, "/etc/x") || b.IsEditing() || !ctx.IsFocused(b) {
+		t.Error("a valid path did not end the editing")
+	}
+	if !equalStrings(*navigated, []string{"etc/x"}) {
+		t.Errorf("navigated to %q", *navigated)
+	}
+
+	// Escape and losing focus cancel.
+	ctx.RequestFocus(b)
+	b.HandleEvent(ctx, press(event.KeyL, event.ModCtrl))
+	if !b.IsEditing() {
+		t.Fatal("Ctrl+L did not edit the path")
+	}
+	b.HandleEvent(ctx, press(event.KeyEscape, 0))
+	if b.IsEditing() || !ctx.IsFocused(b) {
+		t.Error("Escape did not cancel the editing")
+	}
+	b.Edit(ctx)
+	ctx.RequestFocus(nil)
+	ctx.LayoutRoot(b, core.R(0, 0, 800, fieldHeight))
+	if b.IsEditing() {
+		t.Error("leaving the field did not cancel the editing")
+	}
+	if len(*navigated) != 1 {
+		t.Errorf("cancelling navigated to %q", (*navigated)[1:])
+	}
+
+	// A click on a segment does not edit.
+	b.HandleEvent(ctx, leftMouse(event.MouseDown, b.input.targets[0].rect.Center()))
+	if b.IsEditing() {
+		t.Error("a press on a segment edited the path")
+	}
+}
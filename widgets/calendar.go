@@ -0,0 +1,438 @@
+package widgets
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/theme"
+)
+
+const (
+	calendarCell    float32 = 32
+	calendarHeader  float32 = 36
+	calendarDayRow  float32 = 24
+	calendarPadding float32 = 8
+)
+
+// DateLocale holds the names and conventions used to display dates.
+type DateLocale struct {
+	// FirstWeekday is the day shown in the first calendar column.
+	FirstWeekday time.Weekday
+
+	// Months holds the month names, January first.
+	Months [12]string
+
+	// Weekdays holds short day names, Sunday first.
+	Weekdays [7]string
+
+	// Format is the time layout used to display and parse typed dates.
+	Format string
+}
+
+// DefaultDateLocale is English with weeks starting on Sunday and ISO 8601
+// date entry.
+var DefaultDateLocale = DateLocale{
+	FirstWeekday: time.Sunday,
+	Months: [12]string{"January", "February", "March", "April", "May", "June",
+		"July", "August", "September", "October", "November", "December"},
+	Weekdays: [7]string{"Su", "Mo", "Tu", "We", "Th", "Fr", "Sa"},
+	Format:   "2006-01-02",
+}
+
+// dateOf truncates t to midnight of its day.
+func dateOf(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// dayKey orders calendar days independently of time zones.
+func dayKey(t time.Time) int {
+	y, m, d := t.Date()
+	return y*10000 + int(m)*100 + d
+}
+
+func sameDay(a, b time.Time) bool {
+	return !a.IsZero() && !b.IsZero() && dayKey(a) == dayKey(b)
+}
+
+// Calendar is a month view for picking a date or a range of dates. It is
+// used by DatePicker and can also be embedded directly.
+//
+// The arrow keys move by day and week, Page Up and Page Down by month (by
+// year with Shift), Home and End to the start and end of the week, and
+// Enter or Space select the highlighted day. Days outside the limits are
+// shown disabled and cannot be selected.
+type Calendar struct {
+	core.WidgetBase
+	core.FocusState
+
+	locale    DateLocale
+	month     time.Time
+	cursor    time.Time
+	selected  time.Time
+	rangeMode bool
+	start     time.Time
+	end       time.Time
+	hover     time.Time
+	min, max  time.Time
+
+	onSelect func(time.Time)
+	onRange  func(start, end time.Time)
+
+	prev, next core.Rect
+	grid       core.Rect
+}
+
+// NewCalendar returns a Calendar showing the current month.
+func NewCalendar() *Calendar {
+	c := &Calendar{locale: DefaultDateLocale}
+	c.cursor = dateOf(time.Now())
+	c.ShowMonth(c.cursor)
+	return c
+}
+
+// Locale sets the month and day names and the first day of the week.
+func (c *Calendar) Locale(l DateLocale) *Calendar {
+	c.locale = l
+	return c
+}
+
+// Limits restricts selection to days from min to max inclusive. A zero
+// time leaves that end open.
+func (c *Calendar) Limits(min, max time.Time) *Calendar {
+	c.min, c.max = min, max
+	return c
+}
+
+// RangeMode switches between selecting a single day and a range: in range
+// mode the first click picks the start and the second the end.
+func (c *Calendar) RangeMode(on bool) *Calendar {
+	c.rangeMode = on
+	return c
+}
+
+// OnSelect registers fn to be called when a day is selected in single
+// mode.
+func (c *Calendar) OnSelect(fn func(time.Time)) *Calendar {
+	c.onSelect = fn
+	return c
+}
+
+// OnRangeSelect registers fn to be called when a range is completed in
+// range mode.
+func (c *Calendar) OnRangeSelect(fn func(start, end time.Time)) *Calendar {
+	c.onRange = fn
+	return c
+}
+
+// Selected returns the selected day, or the zero time.
+func (c *Calendar) Selected() time.Time {
+	return c.selected
+}
+
+// SetSelected selects t and shows its month. A zero t clears the selection.
+func (c *Calendar) SetSelected(t time.Time) {
+	c.selected = time.Time{}
+	if !t.IsZero() {
+		c.selected = dateOf(t)
+		c.cursor = c.selected
+		c.ShowMonth(t)
+	}
+}
+
+// Range returns the selected range. end is zero while only the start has
+// been picked.
+func (c *Calendar) Range() (start, end time.Time) {
+	return c.start, c.end
+}
+
+// SetRange selects the range from start to end and shows the start month.
+func (c *Calendar) SetRange(start, end time.Time) {
+	c.start, c.end = time.Time{}, time.Time{}
+	if !start.IsZero() {
+		c.start = dateOf(start)
+		c.cursor = c.start
+		c.ShowMonth(start)
+	}
+	if !end.IsZero() {
+		c.end = dateOf(end)
+	}
+	if !c.start.IsZero() && !c.end.IsZero() && dayKey(c.end) < dayKey(c.start) {
+		c.start, c.end = c.end, c.start
+	}
+}
+
+// Month returns the first day of the displayed month.
+func (c *Calendar) Month() time.Time {
+	return c.month
+}
+
+// ShowMonth displays the month containing t.
+func (c *Calendar) ShowMonth(t time.Time) {
+	y, m, _ := t.Date()
+	c.month = time.Date(y, m, 1, 0, 0, 0, 0, t.Location())
+}
+
+// Selectable reports whether t lies within the limits.
+func (c *Calendar) Selectable(t time.Time) bool {
+	k := dayKey(t)
+	return (c.min.IsZero() || k >= dayKey(c.min)) && (c.max.IsZero() || k <= dayKey(c.max))
+}
+
+// gridStart returns the day shown in the first cell.
+func (c *Calendar) gridStart() time.Time {
+	offset := (int(c.month.Weekday()) - int(c.locale.FirstWeekday) + 7) % 7
+	return c.month.AddDate(0, 0, -offset)
+}
+
+func (c *Calendar) cellRect(i int) core.Rect {
+	return core.R(c.grid.X+float32(i%7)*calendarCell, c.grid.Y+float32(i/7)*calendarCell, calendarCell, calendarCell)
+}
+
+func (c *Calendar) dayAt(p core.Point) (time.Time, bool) {
+	if !c.grid.Contains(p) {
+		return time.Time{}, false
+	}
+	col := int((p.X - c.grid.X) / calendarCell)
+	row := int((p.Y - c.grid.Y) / calendarCell)
+	return c.gridStart().AddDate(0, 0, row*7+col), true
+}
+
+// Layout implements core.Widget.
+func (c *Calendar) Layout(ctx *core.LayoutContext) core.Size {
+	w := 7*calendarCell + 2*calendarPadding
+	h := calendarHeader + calendarDayRow + 6*calendarCell + calendarPadding
+	return ctx.Constraints.Constrain(core.Sz(w, h))
+}
+
+// SetBounds implements core.Widget.
+func (c *Calendar) SetBounds(r core.Rect) {
+	c.WidgetBase.SetBounds(r)
+	x := r.X + calendarPadding
+	c.prev = core.R(x, r.Y+4, calendarCell, calendarHeader-8)
+	c.next = core.R(x+6*calendarCell, r.Y+4, calendarCell, calendarHeader-8)
+	c.grid = core.R(x, r.Y+calendarHeader+calendarDayRow, 7*calendarCell, 6*calendarCell)
+}
+
+// Paint implements core.Widget.
+func (c *Calendar) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	b := c.Bounds()
+
+	title := fmt.Sprintf("%s %d", c.locale.Months[c.month.Month()-1], c.month.Year())
+	ts := theme.TextStyle(th.Typography.Label, th.Colors.OnSurface)
+	tw := ctx.MeasureText(title, ts).Width
+	cv.DrawText(title, core.Pt(b.X+(b.Width-tw)/2, b.Y+(calendarHeader-ts.LineHeight())/2), ts)
+	c.paintArrow(ctx, c.prev, -1)
+	c.paintArrow(ctx, c.next, 1)
+
+	ds := theme.TextStyle(th.Typography.Caption, th.Colors.OnSurfaceVariant)
+	for i := range 7 {
+		name := c.locale.Weekdays[(int(c.locale.FirstWeekday)+i)%7]
+		w := ctx.MeasureText(name, ds).Width
+		cv.DrawText(name, core.Pt(c.grid.X+float32(i)*calendarCell+(calendarCell-w)/2, b.Y+calendarHeader+(calendarDayRow-ds.LineHeight())/2), ds)
+	}
+
+	lo, hi := c.shownRange()
+	today := time.Now()
+	day := c.gridStart()
+	for i := range 42 {
+		r := c.cellRect(i)
+		k := dayKey(day)
+		inMonth := day.Month() == c.month.Month()
+		selected := sameDay(day, c.selected) || sameDay(day, c.start) || sameDay(day, c.end)
+		if lo > 0 && k >= lo && k <= hi {
+			band := r.Inset(core.SymmetricInsets(0, 3))
+			if k == lo {
+				band = core.R(r.Center().X, band.Y, r.Width/2, band.Height)
+			}
+			if k == hi {
+				band.Width = r.Center().X - band.X
+			}
+			if lo != hi {
+				cv.DrawRect(band, core.Filled(th.Colors.Selection))
+			}
+		}
+		circle := r.Inset(core.UniformInsets(3))
+		color := th.Colors.OnSurface
+		switch {
+		case selected:
+			cv.DrawRoundedRect(circle, circle.Width/2, core.Filled(th.Colors.Primary))
+			color = th.Colors.OnPrimary
+		case sameDay(day, c.hover) && c.Selectable(day):
+			cv.DrawRoundedRect(circle, circle.Width/2, core.Filled(th.Colors.OnSurface.WithAlpha(0.08)))
+		}
+		if sameDay(day, today) && !selected {
+			cv.DrawRoundedRect(circle, circle.Width/2, core.Stroked(th.Colors.Primary, 1))
+		}
+		if c.IsFocused() && sameDay(day, c.cursor) {
+			cv.DrawRoundedRect(r.Inset(core.UniformInsets(1)), th.Radii.Small, core.Stroked(th.Colors.Primary, 2))
+		}
+		switch {
+		case !c.Selectable(day):
+			color = th.Colors.OnSurfaceVariant.WithAlpha(0.38)
+		case !inMonth && !selected:
+			color = th.Colors.OnSurfaceVariant.WithAlpha(0.7)
+		}
+		style := theme.TextStyle(th.Typography.Body, color)
+		label := fmt.Sprint(day.Day())
+		w := ctx.MeasureText(label, style).Width
+		cv.DrawText(label, core.Pt(r.X+(r.Width-w)/2, r.Y+(r.Height-style.LineHeight())/2), style)
+		day = day.AddDate(0, 0, 1)
+	}
+}
+
+// shownRange returns the day keys of the highlighted range, including the
+// hover preview while only the start is picked, or zeros.
+func (c *Calendar) shownRange() (lo, hi int) {
+	if !c.rangeMode || c.start.IsZero() {
+		return 0, 0
+	}
+	end := c.end
+	if end.IsZero() {
+		end = c.hover
+	}
+	if end.IsZero() {
+		return dayKey(c.start), dayKey(c.start)
+	}
+	lo, hi = dayKey(c.start), dayKey(end)
+	return min(lo, hi), max(lo, hi)
+}
+
+func (c *Calendar) paintArrow(ctx *core.PaintContext, r core.Rect, dir float32) {
+	th := theme.From(ctx.Context)
+	center := r.Center()
+	const s = 4
+	p := core.NewPath().
+		MoveTo(core.Pt(center.X-dir*s/2, center.Y-s)).
+		LineTo(core.Pt(center.X+dir*s/2, center.Y)).
+		LineTo(core.Pt(center.X-dir*s/2, center.Y+s))
+	ctx.Canvas.DrawPath(p, core.PathStyle{Stroke: th.Colors.OnSurfaceVariant, StrokeWidth: 1.5, LineCap: core.CapRound})
+}
+
+// pick selects t as the single day or the next end of the range.
+func (c *Calendar) pick(ctx *core.Context, t time.Time) {
+	if !c.Selectable(t) {
+		return
+	}
+	t = dateOf(t)
+	c.cursor = t
+	ctx.Repaint(c)
+	if !c.rangeMode {
+		c.selected = t
+		if c.onSelect != nil {
+			c.onSelect(t)
+		}
+		return
+	}
+	if c.start.IsZero() || !c.end.IsZero() {
+		c.start, c.end = t, time.Time{}
+		return
+	}
+	c.end = t
+	if dayKey(c.end) < dayKey(c.start) {
+		c.start, c.end = c.end, c.start
+	}
+	if c.onRange != nil {
+		c.onRange(c.start, c.end)
+	}
+}
+
+// moveCursor moves the keyboard cursor to t, clamped to the limits, and
+// shows its month.
+func (c *Calendar) moveCursor(ctx *core.Context, t time.Time) {
+	if !c.min.IsZero() && dayKey(t) < dayKey(c.min) {
+		t = dateOf(c.min)
+	}
+	if !c.max.IsZero() && dayKey(t) > dayKey(c.max) {
+		t = dateOf(c.max)
+	}
+	c.cursor = t
+	c.ShowMonth(t)
+	if c.rangeMode && !c.start.IsZero() && c.end.IsZero() {
+		c.hover = t
+	}
+	ctx.MarkNeedsLayout(c)
+}
+
+// HandleEvent implements core.Widget.
+func (c *Calendar) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	switch e := ev.(type) {
+	case event.MouseEvent:
+		switch e.Type {
+		case event.MouseMove:
+			d, _ := c.dayAt(e.Position)
+			if !sameDay(d, c.hover) {
+				c.hover = d
+				ctx.Repaint(c)
+			}
+		case event.MouseLeave:
+			c.hover = time.Time{}
+			ctx.Repaint(c)
+		case event.MouseDown:
+			if e.Button != event.ButtonLeft {
+				return core.Ignored
+			}
+			ctx.RequestFocus(c)
+			switch {
+			case c.prev.Contains(e.Position):
+				c.month = c.month.AddDate(0, -1, 0)
+				ctx.MarkNeedsLayout(c)
+			case c.next.Contains(e.Position):
+				c.month = c.month.AddDate(0, 1, 0)
+				ctx.MarkNeedsLayout(c)
+			default:
+				if d, ok := c.dayAt(e.Position); ok {
+					c.pick(ctx, d)
+				}
+			}
+			return core.Handled
+		}
+	case event.KeyEvent:
+		if e.Type != event.KeyPress || !c.IsFocused() {
+			return core.Ignored
+		}
+		cur := c.cursor
+		switch e.Key {
+		case event.KeyLeft:
+			c.moveCursor(ctx, cur.AddDate(0, 0, -1))
+		case event.KeyRight:
+			c.moveCursor(ctx, cur.AddDate(0, 0, 1))
+		case event.KeyUp:
+			c.moveCursor(ctx, cur.AddDate(0, 0, -7))
+		case event.KeyDown:
+			c.moveCursor(ctx, cur.AddDate(0, 0, 7))
+		case event.KeyPageUp, event.KeyPageDown:
+			months := 1
+			if e.Modifiers.Has(event.ModShift) {
+				months = 12
+			}
+			if e.Key == event.KeyPageUp {
+				months = -months
+			}
+			c.moveCursor(ctx, addMonthsClamped(cur, months))
+		case event.KeyHome:
+			c.moveCursor(ctx, cur.AddDate(0, 0, -((int(cur.Weekday())-int(c.locale.FirstWeekday)+7)%7)))
+		case event.KeyEnd:
+			c.moveCursor(ctx, cur.AddDate(0, 0, 6-((int(cur.Weekday())-int(c.locale.FirstWeekday)+7)%7)))
+		case event.KeyEnter, event.KeySpace:
+			c.pick(ctx, cur)
+		default:
+			return core.Ignored
+		}
+		return core.Handled
+	}
+	return core.Ignored
+}
+
+// addMonthsClamped adds months to t, keeping the day within the target
+// month so that January 31 plus one month is the last day of February.
+func addMonthsClamped(t time.Time, months int) time.Time {
+	y, m, d := t.Date()
+	first := time.Date(y, m+time.Month(months), 1, 0, 0, 0, 0, t.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(d, last)-1)
+}
@@ -0,0 +1,156 @@
+package widgets
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+)
+
+// day returns midnight of the given day in 2024.
+func day(m time.Month, d int) time.Time {
+	return time.Date(2024, m, d, 0, 0, 0, 0, time.Local)
+}
+
+// newCalendar returns a focused calendar showing March 2024 with the
+// cursor on March 15, a Friday.
+func newCalendar() (*Calendar, *core.Context) {
+	c := NewCalendar()
+	c.cursor = day(time.March, 15)
+	c.ShowMonth(c.cursor)
+	ctx := layoutAt(c, core.R(0, 0, 400, 400))
+	ctx.RequestFocus(c)
+	return c, ctx
+}
+
+// cellOf returns the center of the cell showing t.
+func cellOf(c *Calendar, t time.Time) core.Point {
+	i := int(t.Sub(c.gridStart()).Hours()+12) / 24
+	return c.cellRect(i).Center()
+}
+
+func TestCalendarGrid(t *testing.T) {
+	// March 1, 2024 is a Friday.
+	tests := []struct {
+		first time.Weekday
+		want  time.Time
+	}{
+		{time.Sunday, day(time.February, 25)},
+		{time.Monday, day(time.February, 26)},
+		{time.Friday, day(time.March, 1)},
+		{time.Saturday, day(time.February, 24)},
+	}
+	for _, tt := range tests {
+		l := DefaultDateLocale
+		l.FirstWeekday = tt.first
+		c := NewCalendar().Locale(l)
+		c.ShowMonth(day(time.March, 20))
+		if got := c.gridStart(); !sameDay(got, tt.want) {
+			t.Errorf("weeks from %v: the grid starts on %v, want %v", tt.first, got, tt.want)
+		}
+	}
+}
+
+func TestCalendarKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		key  event.KeyEvent
+		min  time.Time
+		want time.Time
+	}{
+		{"left", press(event.KeyLeft, 0), time.Time{}, day(time.March, 14)},
+		{"right", press(event.KeyRight, 0), time.Time{}, day(time.March, 16)},
+		{"up", press(event.KeyUp, 0), time.Time{}, day(time.March, 8)},
+		{"down", press(event.KeyDown, 0), time.Time{}, day(time.March, 22)},
+		{"page up", press(event.KeyPageUp, 0), time.Time{}, day(time.February, 15)},
+		{"page down by year", press(event.KeyPageDown, event.ModShift), time.Time{}, time.Date(2025, time.March, 15, 0, 0, 0, 0, time.Local)},
+		{"home", press(event.KeyHome, 0), time.Time{}, day(time.March, 10)},
+		{"end", press(event.KeyEnd, 0), time.Time{}, day(time.March, 16)},
+		{"clamped to the limits", press(event.KeyUp, 0), day(time.March, 12), day(time.March, 12)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, ctx := newCalendar()
+			c.Limits(tt.min, time.Time{})
+			if got := c.HandleEvent(ctx, tt.key); got != core.Handled {
+				t.Fatalf("HandleEvent() = %v", got)
+			}
+			if !sameDay(c.cursor, tt.want) {
+				t.Errorf("cursor on %v, want %v", c.cursor, tt.want)
+			}
+			if y, m, _ := tt.want.Date(); c.Month().Year() != y || c.Month().Month() != m {
+				t.Errorf("showing %v, want the month of the cursor", c.Month())
+			}
+		})
+	}
+}
+
+func TestCalendarSelect(t *testing.T) {
+	c, ctx := newCalendar()
+	c.Limits(time.Time{}, day(time.March, 20))
+	var picked []time.Time
+	c.OnSelect(func(t time.Time) { picked = append(picked, t) })
+	c.HandleEvent(ctx, press(event.KeyEnter, 0))
+	click := func(p core.Point) {
+		c.HandleEvent(ctx, event.MouseEvent{Type: event.MouseDown, Position: p, Button: event.ButtonLeft})
+	}
+	click(cellOf(c, day(time.March, 4)))
+	click(cellOf(c, day(time.March, 25))) // Past the limit.
+	if len(picked) != 2 || !sameDay(picked[0], day(time.March, 15)) || !sameDay(picked[1], day(time.March, 4)) {
+		t.Errorf("picked %v, want March 15 and 4", picked)
+	}
+	if !sameDay(c.Selected(), day(time.March, 4)) {
+		t.Errorf("Selected() = %v", c.Selected())
+	}
+	click(c.next.Center())
+	click(c.next.Center())
+	click(c.prev.Center())
+	if c.Month().Month() != time.April {
+		t.Errorf("showing %v after the arrows, want April", c.Month().Month())
+	}
+}
+
+func TestCalendarRange(t *testing.T) {
+	c, ctx := newCalendar()
+	c.RangeMode(true)
+	var ranges [][2]time.Time
+	c.OnRangeSelect(func(start, end time.Time) { ranges = append(ranges, [2]time.Time{start, end}) })
+	c.HandleEvent(ctx, press(event.KeyEnter, 0))
+	c.HandleEvent(ctx, press(event.KeyLeft, 0))
+	c.HandleEvent(ctx, press(event.KeyLeft, 0))
+	if lo, hi := c.shownRange(); lo != dayKey(day(time.March, 13)) || hi != dayKey(day(time.March, 15)) {
+		t.Errorf("preview %d-%d, want March 13 to 15", lo, hi)
+	}
+	c.HandleEvent(ctx, press(event.KeyEnter, 0))
+	if len(ranges) != 1 || !sameDay(ranges[0][0], day(time.March, 13)) || !sameDay(ranges[0][1], day(time.March, 15)) {
+		t.Errorf("ranges %v, want March 13 to 15", ranges)
+	}
+	c.HandleEvent(ctx, press(event.KeyEnter, 0)) // Starts a new range.
+	if start, end := c.Range(); !sameDay(start, day(time.March, 13)) || !end.IsZero() {
+		t.Errorf("Range() = %v, %v, want a new start only", start, end)
+	}
+	c.SetRange(day(time.May, 2), day(time.April, 30))
+	if start, end := c.Range(); !sameDay(start, day(time.April, 30)) || !sameDay(end, day(time.May, 2)) {
+		t.Errorf("SetRange kept the ends reversed: %v, %v", start, end)
+	}
+}
+
+func TestAddMonthsClamped(t *testing.T) {
+	tests := []struct {
+		from   time.Time
+		months int
+		want   time.Time
+	}{
+		{day(time.January, 31), 1, day(time.February, 29)},
+		{day(time.March, 31), -1, day(time.February, 29)},
+		{day(time.May, 31), 1, day(time.June, 30)},
+		{day(time.January, 15), 12, time.Date(2025, time.January, 15, 0, 0, 0, 0, time.Local)},
+		{day(time.February, 29), 12, time.Date(2025, time.February, 28, 0, 0, 0, 0, time.Local)},
+	}
+	for _, tt := range tests {
+		if got := addMonthsClamped(tt.from, tt.months); !sameDay(got, tt.want) {
+			t.Errorf("addMonthsClamped(%v, %d) = %v, want %v", tt.from, tt.months, got, tt.want)
+		}
+	}
+}
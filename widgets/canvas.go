@@ -0,0 +1,138 @@
+package widgets
+
+import (
+	"sync/atomic"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+)
+
+// Canvas default size.
+const (
+	canvasWidth  float32 = 200
+	canvasHeight float32 = 200
+)
+
+// Canvas is a widget drawn by a function, for custom visualizations that
+// do not need a widget type of their own.
+//
+// The paint function draws in local coordinates, with the origin at the
+// canvas's top-left corner, clipped to its size. It is given a core.Canvas
+// rather than a gg.Canvas: this module does not depend on gogpu/gg, and
+// the platform integrations drawing with gg supply it as a core.Canvas,
+// so that the same function also draws into the software rasterizer and
+// recordings. What it draws is recorded
+// and replayed in later frames, so it runs again only when the size
+// changes or MarkDirty is called, however often the window repaints for
+// other reasons.
+//
+// Input reaches the function set with OnEvent with pointer positions in
+// local coordinates. A Canvas is not a Tab stop; the function can call
+// ctx.RequestFocus to receive key events.
+type Canvas struct {
+	core.WidgetBase
+
+	paint   func(c core.Canvas, size core.Size)
+	hit     func(p core.Point) bool
+	onEvent func(ctx *core.Context, ev core.Event) core.EventResult
+	size    core.Size
+
+	ctx      atomic.Pointer[core.Context]
+	dirty    atomic.Bool
+	recorded core.Size
+	list     core.Recording
+}
+
+// NewCanvas returns a Canvas drawn by paint, which may be nil.
+func NewCanvas(paint func(c core.Canvas, size core.Size)) *Canvas {
+	c := &Canvas{paint: paint, size: core.Sz(canvasWidth, canvasHeight)}
+	c.dirty.Store(true)
+	return c
+}
+
+// OnPaint replaces the paint function.
+func (c *Canvas) OnPaint(fn func(c core.Canvas, size core.Size)) *Canvas {
+	c.paint = fn
+	c.MarkDirty()
+	return c
+}
+
+// OnEvent sets the function receiving input. It returns core.Handled to
+// stop the event from reaching the canvas's ancestors.
+func (c *Canvas) OnEvent(fn func(ctx *core.Context, ev core.Event) core.EventResult) *Canvas {
+	c.onEvent = fn
+	return c
+}
+
+// OnHitTest sets the function deciding which points, in local
+// coordinates, belong to the canvas, for shapes that do not fill it. By
+// default every point of its bounds does.
+func (c *Canvas) OnHitTest(fn func(p core.Point) bool) *Canvas {
+	c.hit = fn
+	return c
+}
+
+// Size sets the preferred size. The default is 200 by 200.
+func (c *Canvas) Size(w, h float32) *Canvas {
+	c.size = core.Sz(w, h)
+	return c
+}
+
+// MarkDirty makes the paint function run again in the next frame. It may
+// be called from any goroutine.
+func (c *Canvas) MarkDirty() {
+	c.dirty.Store(true)
+	if ctx := c.ctx.Load(); ctx != nil {
+		ctx.Post(func() { ctx.Repaint(c) })
+	}
+}
+
+// Layout implements core.Widget.
+func (c *Canvas) Layout(ctx *core.LayoutContext) core.Size {
+	c.ctx.Store(ctx.Context)
+	return ctx.Constraints.Constrain(c.size)
+}
+
+// HitTest implements core.HitTester.
+func (c *Canvas) HitTest(p core.Point) bool {
+	if c.hit == nil {
+		return true
+	}
+	return c.hit(p.Sub(c.Bounds().Origin()))
+}
+
+// Paint implements core.Widget.
+func (c *Canvas) Paint(ctx *core.PaintContext) {
+	b := c.Bounds()
+	if c.paint == nil {
+		return
+	}
+	if c.dirty.Swap(false) || b.Size() != c.recorded {
+		c.recorded = b.Size()
+		c.list.Reset()
+		c.paint(&c.list, c.recorded)
+	}
+	cv := ctx.Canvas
+	cv.Save()
+	cv.Clip(b)
+	cv.Translate(b.X, b.Y)
+	c.list.Replay(cv)
+	cv.Restore()
+}
+
+// HandleEvent implements core.Widget.
+func (c *Canvas) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	if c.onEvent == nil {
+		return core.Ignored
+	}
+	o := c.Bounds().Origin()
+	switch e := ev.(type) {
+	case event.MouseEvent:
+		e.Position = e.Position.Sub(o)
+		ev = e
+	case event.ScrollEvent:
+		e.Position = e.Position.Sub(o)
+		ev = e
+	}
+	return c.onEvent(ctx, ev)
+}
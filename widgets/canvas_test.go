@@ -0,0 +1,145 @@
+package widgets
+
+import (
+	"fmt"
+	"image"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+)
+
+// opCanvas records the transforms and clips applied to it along with the
+// drawing.
+type opCanvas struct {
+	core.Recording
+	ops []string
+}
+
+func (c *opCanvas) Translate(x, y float32) {
+	c.ops = append(c.ops, fmt.Sprintf("translate %v %v", x, y))
+	c.Recording.Translate(x, y)
+}
+
+func (c *opCanvas) Clip(r core.Rect) {
+	c.ops = append(c.ops, fmt.Sprintf("clip %v %v %v %v", r.X, r.Y, r.Width, r.Height))
+	c.Recording.Clip(r)
+}
+
+func (c *opCanvas) DrawRect(r core.Rect, st core.RectStyle) {
+	c.ops = append(c.ops, fmt.Sprintf("rect %v %v %v %v", r.X, r.Y, r.Width, r.Height))
+	c.Recording.DrawRect(r, st)
+}
+
+func TestCanvasSize(t *testing.T) {
+	tests := []struct {
+		name   string
+		canvas *Canvas
+		c      core.Constraints
+		want   core.Size
+	}{
+		{"default", NewCanvas(nil), core.Unbounded(), core.Sz(200, 200)},
+		{"sized", NewCanvas(nil).Size(50, 30), core.Unbounded(), core.Sz(50, 30)},
+		{"constrained", NewCanvas(nil), core.Loose(core.Sz(120, 300)), core.Sz(120, 200)},
+	}
+	for _, tt := range tests {
+		lc := &core.LayoutContext{Context: core.NewContext()}
+		if got := lc.Measure(tt.canvas, tt.c); got != tt.want {
+			t.Errorf("%s: size %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCanvasPaint(t *testing.T) {
+	var sizes []core.Size
+	c := NewCanvas(func(cv core.Canvas, size core.Size) {
+		sizes = append(sizes, size)
+		cv.DrawRect(core.R(0, 0, 10, 10), core.Filled(core.Black))
+	})
+	ctx := layoutAt(c, core.R(20, 30, 100, 60))
+	paint := func() []string {
+		cv := &opCanvas{}
+		c.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+		return cv.ops
+	}
+	want := []string{"clip 20 30 100 60", "translate 20 30", "rect 0 0 10 10"}
+	if got := paint(); !equalStrings(got, want) {
+		t.Errorf("painted %q, want %q", got, want)
+	}
+	if got := paint(); !equalStrings(got, want) || len(sizes) != 1 || sizes[0] != core.Sz(100, 60) {
+		t.Errorf("painting again ran the function for %v and drew %q", sizes, got)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		c.MarkDirty()
+		close(done)
+	}()
+	<-done
+	runPosted(t, ctx)
+	paint()
+	if len(sizes) != 2 {
+		t.Errorf("MarkDirty ran the function %d times", len(sizes)-1)
+	}
+
+	ctx.LayoutRoot(c, core.R(0, 0, 40, 40))
+	paint()
+	if len(sizes) != 3 || sizes[2] != core.Sz(40, 40) {
+		t.Errorf("a new size painted %v", sizes)
+	}
+
+	c.OnPaint(func(cv core.Canvas, size core.Size) {
+		cv.DrawRect(core.R(1, 2, 3, 4), core.Filled(core.Black))
+	})
+	if got := paint(); len(got) != 3 || got[2] != "rect 1 2 3 4" {
+		t.Errorf("the new function drew %q", got)
+	}
+	c.OnPaint(nil)
+	if got := paint(); len(got) != 0 {
+		t.Errorf("a canvas without a function drew %q", got)
+	}
+}
+
+func TestCanvasInput(t *testing.T) {
+	c := NewCanvas(nil)
+	ctx := layoutAt(c, core.R(20, 30, 100, 60))
+	if !c.HitTest(core.Pt(21, 31)) {
+		t.Error("the canvas does not take its bounds by default")
+	}
+	if r := c.HandleEvent(ctx, event.MouseEvent{Type: event.MouseDown}); r != core.Ignored {
+		t.Errorf("a canvas without OnEvent = %v", r)
+	}
+
+	c.OnHitTest(func(p core.Point) bool { return p.X < 10 })
+	if !c.HitTest(core.Pt(25, 50)) || c.HitTest(core.Pt(35, 50)) {
+		t.Error("OnHitTest was not given local points")
+	}
+
+	var got []core.Event
+	c.OnEvent(func(ctx *core.Context, ev core.Event) core.EventResult {
+		got = append(got, ev)
+		return core.Handled
+	})
+	key := event.KeyEvent{Type: event.KeyPress, Key: event.KeyA}
+	tests := []struct {
+		ev   core.Event
+		want core.Event
+	}{
+		{event.MouseEvent{Type: event.MouseDown, Position: core.Pt(25, 35)}, event.MouseEvent{Type: event.MouseDown, Position: core.Pt(5, 5)}},
+		{event.ScrollEvent{Position: core.Pt(120, 90), Delta: core.Pt(0, 3)}, event.ScrollEvent{Position: core.Pt(100, 60), Delta: core.Pt(0, 3)}},
+		{key, key},
+	}
+	for _, tt := range tests {
+		if r := c.HandleEvent(ctx, tt.ev); r != core.Handled {
+			t.Errorf("%T = %v, want the function's result", tt.ev, r)
+		}
+		if last := got[len(got)-1]; last != tt.want {
+			t.Errorf("the function got %v, want %v", last, tt.want)
+		}
+	}
+}
+
+func (c *opCanvas) DrawImage(img image.Image, r core.Rect) {
+	c.ops = append(c.ops, fmt.Sprintf("image %v %v %v %v", r.X, r.Y, r.Width, r.Height))
+	c.Recording.DrawImage(img, r)
+}
@@ -0,0 +1,215 @@
+package charts
+
+import (
+	"math"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/state"
+	"github.com/gogpu/ui/theme"
+)
+
+// barGap is the part of a category's slot left empty around its bars.
+const barGap = 0.25
+
+// BarChart plots a value per category for each series, as bars side by
+// side or stacked.
+//
+// The values of a series are taken from its Values, one per category.
+// Hovering a category highlights it and shows its values.
+type BarChart struct {
+	core.WidgetBase
+	plot
+
+	categories []string
+	series     []Series
+	bind       binding[[]Series]
+	stacked    bool
+
+	hover   int // category under the pointer, or -1
+	tooltip tooltip
+}
+
+// NewBarChart returns a BarChart of series over categories.
+func NewBarChart(categories []string, series ...Series) *BarChart {
+	return &BarChart{categories: categories, series: series, hover: -1}
+}
+
+// Categories returns the categories.
+func (c *BarChart) Categories() []string {
+	return c.categories
+}
+
+// SetCategories replaces the categories.
+func (c *BarChart) SetCategories(categories ...string) {
+	c.categories = categories
+	c.hover = -1
+}
+
+// Series returns the series shown.
+func (c *BarChart) Series() []Series {
+	return c.series
+}
+
+// SetSeries replaces the series shown.
+func (c *BarChart) SetSeries(series ...Series) {
+	c.series = series
+}
+
+// Bind makes the chart show the series held by sig.
+func (c *BarChart) Bind(sig *state.Signal[[]Series]) *BarChart {
+	c.bind.bind(sig)
+	return c
+}
+
+// Release stops following the bound signal, for when the chart leaves
+// the tree. Laid out again, the chart follows the signal from its value
+// then.
+func (c *BarChart) Release() {
+	c.bind.release()
+}
+
+// Stacked stacks the bars of a category instead of placing them side by
+// side. Negative values stack downward from zero.
+func (c *BarChart) Stacked(on bool) *BarChart {
+	c.stacked = on
+	return c
+}
+
+// YRange fixes the value axis to lo to hi.
+func (c *BarChart) YRange(lo, hi float64) *BarChart {
+	c.yLo, c.yHi, c.yFixed = lo, hi, hi > lo
+	return c
+}
+
+// YFormat sets how values are written.
+func (c *BarChart) YFormat(fn func(y float64) string) *BarChart {
+	c.yFormat = fn
+	return c
+}
+
+// Legend shows or hides the legend of named series. It is shown by
+// default.
+func (c *BarChart) Legend(show bool) *BarChart {
+	c.noLegend = !show
+	return c
+}
+
+// Layout implements core.Widget.
+func (c *BarChart) Layout(ctx *core.LayoutContext) core.Size {
+	c.bind.sync(ctx.Context, c, func(s []Series) { c.series = s })
+	return chartSize(ctx)
+}
+
+// value returns the value of series i in category k, 0 if it has none.
+func (c *BarChart) value(i, k int) float64 {
+	if v := c.series[i].Values; k < len(v) && !math.IsNaN(v[k]) {
+		return v[k]
+	}
+	return 0
+}
+
+// Paint implements core.Widget.
+func (c *BarChart) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	r := inner(c.Bounds())
+	var legend []legendEntry
+	if !c.noLegend {
+		legend = seriesLegend(th, c.series)
+	}
+	lo, hi := 0.0, 0.0
+	for k := range c.categories {
+		var up, down float64
+		for i := range c.series {
+			v := c.value(i, k)
+			switch {
+			case !c.stacked:
+				lo, hi = math.Min(lo, v), math.Max(hi, v)
+			case v > 0:
+				up += v
+			default:
+				down += v
+			}
+		}
+		lo, hi = math.Min(lo, down), math.Max(hi, up)
+	}
+	c.layout(ctx.Context, th, r, 0, 0, lo, hi, max(len(c.categories), 1), legend)
+	paintLegend(ctx, r, legend, theme.TextStyle(th.Typography.Caption, th.Colors.OnSurface))
+	if c.hover >= 0 {
+		cv.DrawRect(c.slot(c.hover), core.Filled(th.Colors.SurfaceVariant.WithAlpha(0.5)))
+	}
+	c.paintAxes(ctx, th, c.categories)
+
+	cv.Save()
+	cv.Clip(c.area)
+	base := c.baseline()
+	for k := range c.categories {
+		slot := c.slot(k)
+		inset := slot.Width * barGap / 2
+		x, w := slot.X+inset, slot.Width-2*inset
+		if !c.stacked && len(c.series) > 0 {
+			w /= float32(len(c.series))
+		}
+		up, down := base, base
+		for i := range c.series {
+			v := c.value(i, k)
+			y := c.screenY(v)
+			var bar core.Rect
+			switch {
+			case !c.stacked:
+				bar = core.R(x+float32(i)*w, min(y, base), w, float32(math.Abs(float64(y-base))))
+			case v > 0:
+				h := base - y
+				up -= h
+				bar = core.R(x, up, w, h)
+			default:
+				h := y - base
+				bar = core.R(x, down, w, h)
+				down += h
+			}
+			if bar.Height > 0 {
+				cv.DrawRect(bar, core.Filled(seriesColor(th, i, c.series[i].Color)))
+			}
+		}
+	}
+	cv.Restore()
+}
+
+// slot returns the column of category k.
+func (c *BarChart) slot(k int) core.Rect {
+	w := c.area.Width / float32(max(len(c.categories), 1))
+	return core.R(c.area.X+float32(k)*w, c.area.Y, w, c.area.Height)
+}
+
+// HandleEvent implements core.Widget.
+func (c *BarChart) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	e, ok := ev.(event.MouseEvent)
+	if !ok || e.Type != event.MouseMove && e.Type != event.MouseLeave {
+		return core.Ignored
+	}
+	hover := -1
+	if e.Type == event.MouseMove && c.area.Contains(e.Position) && len(c.categories) > 0 {
+		hover = min(int((e.Position.X-c.area.X)/c.area.Width*float32(len(c.categories))), len(c.categories)-1)
+	}
+	if hover != c.hover {
+		c.hover = hover
+		ctx.Repaint(c)
+	}
+	if hover < 0 {
+		c.tooltip.hide(ctx)
+		return core.Ignored
+	}
+	th := theme.From(ctx)
+	lines := []tipLine{{text: c.categories[hover]}}
+	for i := range c.series {
+		s := &c.series[i]
+		text := c.yValue(c.value(i, hover))
+		if s.Name != "" {
+			text = s.Name + ": " + text
+		}
+		lines = append(lines, tipLine{seriesColor(th, i, s.Color), text})
+	}
+	c.tooltip.show(ctx, e.Position, lines)
+	return core.Handled
+}
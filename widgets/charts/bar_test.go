@@ -0,0 +1,121 @@
+package charts
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/theme"
+)
+
+func newBarChart() *BarChart {
+	return NewBarChart([]string{"a", "b", "c"},
+		Series{Name: "A", Values: []float64{1, 2, 3}},
+		Series{Name: "B", Values: []float64{2, -1, 4}},
+	)
+}
+
+func TestBarChartRange(t *testing.T) {
+	tests := []struct {
+		name   string
+		chart  *BarChart
+		y0, y1 float64
+	}{
+		{"side by side", newBarChart(), -1, 4},
+		{"stacked", newBarChart().Stacked(true), -2, 8},
+		{"fixed", newBarChart().YRange(0, 10), 0, 10},
+		{"no series", NewBarChart([]string{"a"}), -1, 1},
+	}
+	for _, tt := range tests {
+		layoutChart(tt.chart)
+		if tt.chart.y0 != tt.y0 || tt.chart.y1 != tt.y1 || tt.chart.x0 != -0.5 {
+			t.Errorf("%s: shows %v to %v from x %v", tt.name, tt.chart.y0, tt.chart.y1, tt.chart.x0)
+		}
+	}
+}
+
+func TestBarChartBars(t *testing.T) {
+	c := newBarChart()
+	ctx, cv := layoutChart(c)
+	th := theme.From(ctx)
+	a, b := cv.filled(th.Chart[0]), cv.filled(th.Chart[1])
+	if len(a) != 3 || len(b) != 3 {
+		t.Fatalf("%d and %d bars", len(a), len(b))
+	}
+	base := c.baseline()
+	if a[0].Bottom() != base || a[0].Y != c.screenY(1) {
+		t.Errorf("bar a of A spans %v to %v, want %v to %v", a[0].Y, a[0].Bottom(), c.screenY(1), base)
+	}
+	if b[0].X != a[0].Right() || b[0].Width != a[0].Width {
+		t.Errorf("bars %v and %v, want them side by side", a[0], b[0])
+	}
+	if b[1].Y != base || b[1].Bottom() != c.screenY(-1) {
+		t.Errorf("the negative bar spans %v to %v, want down from %v", b[1].Y, b[1].Bottom(), base)
+	}
+	for k := range 3 {
+		slot := c.slot(k)
+		if a[k].X <= slot.X || b[k].Right() >= slot.Right() {
+			t.Errorf("the bars of category %d leave no gap in %v", k, slot)
+		}
+	}
+	for _, name := range []string{"a", "b", "c", "A", "B"} {
+		if !cv.hasText(name) {
+			t.Errorf("%q was not drawn", name)
+		}
+	}
+
+	c.Stacked(true)
+	cv = paint(ctx, c)
+	a, b = cv.filled(th.Chart[0]), cv.filled(th.Chart[1])
+	base = c.baseline()
+	if b[0].Bottom() != a[0].Y || b[0].X != a[0].X || a[0].Bottom() != base {
+		t.Errorf("stacked bars %v and %v, want B on A", a[0], b[0])
+	}
+	if b[1].Y != base {
+		t.Errorf("the stacked negative bar starts at %v, want %v", b[1].Y, base)
+	}
+
+	// Missing and NaN values draw no bar.
+	c = NewBarChart([]string{"a", "b"}, Series{Values: []float64{1}}).Legend(false)
+	ctx, cv = layoutChart(c)
+	if bars := cv.filled(theme.From(ctx).Chart[0]); len(bars) != 1 {
+		t.Errorf("%d bars for one value", len(bars))
+	}
+}
+
+func TestBarChartHover(t *testing.T) {
+	c := newBarChart().YFormat(func(y float64) string { return formatValue(y) + "ms" })
+	ctx, _ := layoutChart(c)
+	if r := move(ctx, c, c.slot(1).Center()); r != core.Handled || c.hover != 1 {
+		t.Fatalf("moving over category b = %v, hovering %d", r, c.hover)
+	}
+	if got, want := tipTexts(&c.tooltip), []string{"b", "A: 2ms", "B: -1ms"}; !equalStrings(got, want) {
+		t.Errorf("tooltip %q, want %q", got, want)
+	}
+	cv := paint(ctx, c)
+	if hl := cv.filled(theme.From(ctx).Colors.SurfaceVariant.WithAlpha(0.5)); len(hl) != 1 || hl[0] != c.slot(1) {
+		t.Errorf("highlight %v, want the slot of b", hl)
+	}
+	move(ctx, c, core.Pt(c.area.Right()-1, c.area.Y+1))
+	if c.hover != 2 {
+		t.Errorf("hovering %d at the right edge, want the last category", c.hover)
+	}
+
+	if r := move(ctx, c, core.Pt(c.area.X-1, c.area.Y)); r != core.Ignored || c.hover != -1 || len(ctx.Overlays()) != 0 {
+		t.Errorf("moving off the plot = %v, hovering %d", r, c.hover)
+	}
+	move(ctx, c, c.slot(0).Center())
+	c.HandleEvent(ctx, event.MouseEvent{Type: event.MouseLeave})
+	if c.hover != -1 || len(ctx.Overlays()) != 0 {
+		t.Error("leaving kept the hover")
+	}
+	move(ctx, c, c.slot(0).Center())
+	c.SetCategories("x")
+	if c.hover != -1 || len(c.Categories()) != 1 {
+		t.Errorf("SetCategories kept hovering %d", c.hover)
+	}
+	c.SetSeries()
+	if len(c.Series()) != 0 {
+		t.Errorf("Series() = %v", c.Series())
+	}
+}
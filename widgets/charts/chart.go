@@ -0,0 +1,201 @@
+// Package charts provides line, bar, pie and scatter charts.
+//
+// Charts are drawn with the canvas's paths and rectangles, so they render
+// on the GPU like every other widget. Line and scatter charts draw at most
+// a few vertices per pixel column, so series of hundreds of thousands of
+// samples stay cheap to draw.
+//
+// Each chart takes its data directly or from a signal, and redraws when the
+// signal changes, for dashboards and live profiling views:
+//
+//	load := state.NewFunc[[]charts.Series](nil, nil)
+//	chart := charts.NewLineChart().YRange(0, 100).Bind(load)
+//	load.Set([]charts.Series{{Name: "CPU", Values: samples}})
+//
+// Hovering a chart shows the values under the pointer in a tooltip.
+package charts
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/state"
+	"github.com/gogpu/ui/theme"
+)
+
+// Chart metrics.
+const (
+	chartWidth   float32 = 400
+	chartHeight  float32 = 240
+	chartPadding float32 = 8
+	chartTicks           = 6 // the most ticks on an axis, as a guide
+	swatchSize   float32 = 10
+	hoverRadius  float32 = 12 // how near the pointer must be to a point
+)
+
+// Point is a data point.
+type Point struct {
+	X, Y float64
+}
+
+// Series is a named sequence of values.
+//
+// Line and scatter charts plot Points. A line chart plots Values instead
+// when Points is nil, at x = 0, 1, 2 and so on, which suits samples taken
+// at a steady rate; the points of a line chart must be in increasing x. A
+// bar chart takes one value per category from Values.
+type Series struct {
+	Name   string
+	Color  core.Color // transparent picks a color of the theme's chart palette
+	Values []float64
+	Points []Point
+}
+
+// Len returns the number of points.
+func (s *Series) Len() int {
+	if s.Points != nil {
+		return len(s.Points)
+	}
+	return len(s.Values)
+}
+
+// At returns point i.
+func (s *Series) At(i int) Point {
+	if s.Points != nil {
+		return s.Points[i]
+	}
+	return Point{float64(i), s.Values[i]}
+}
+
+// seriesColor returns the color of the i-th series or slice.
+func seriesColor(th *theme.Theme, i int, c core.Color) core.Color {
+	if c != (core.Color{}) {
+		return c
+	}
+	return th.Chart[i%len(th.Chart)]
+}
+
+// binding keeps a chart in sync with a signal.
+type binding[T any] struct {
+	sig    *state.Signal[T]
+	cancel func()
+}
+
+func (b *binding[T]) bind(sig *state.Signal[T]) {
+	b.release()
+	b.sig = sig
+}
+
+// release ends the subscription to the signal. The next sync subscribes
+// again.
+func (b *binding[T]) release() {
+	if b.cancel != nil {
+		b.cancel()
+		b.cancel = nil
+	}
+}
+
+// sync subscribes to the signal on the first layout, laying w out again
+// when it changes, and passes its value to set.
+func (b *binding[T]) sync(ctx *core.Context, w core.Widget, set func(T)) {
+	if b.sig == nil {
+		return
+	}
+	if b.cancel == nil {
+		b.cancel = b.sig.Subscribe(func(T) { ctx.Post(func() { ctx.MarkNeedsLayout(w) }) })
+	}
+	set(b.sig.Get())
+}
+
+// niceTicks returns round tick values covering lo to hi, roughly count of
+// them, and the step between them.
+func niceTicks(lo, hi float64, count int) (ticks []float64, step float64) {
+	if !(hi > lo) {
+		lo, hi = lo-1, hi+1
+	}
+	raw := (hi - lo) / float64(max(count-1, 1))
+	mag := math.Pow(10, math.Floor(math.Log10(raw)))
+	switch f := raw / mag; {
+	case f <= 1:
+		step = mag
+	case f <= 2:
+		step = 2 * mag
+	case f <= 5:
+		step = 5 * mag
+	default:
+		step = 10 * mag
+	}
+	for v := math.Floor(lo/step) * step; v <= hi+step*1e-9; v += step {
+		if v >= lo-step*1e-9 {
+			ticks = append(ticks, v)
+		}
+	}
+	return ticks, step
+}
+
+// extend widens lo to hi outward to the ticks around them.
+func extend(lo, hi float64) (float64, float64) {
+	if !(hi > lo) {
+		lo, hi = lo-1, lo+1
+	}
+	_, step := niceTicks(lo, hi, chartTicks)
+	return math.Floor(lo/step) * step, math.Ceil(hi/step) * step
+}
+
+// formatNumber formats v with as many decimals as step needs.
+func formatNumber(v, step float64) string {
+	prec := 0
+	if step > 0 && step < 1 {
+		prec = int(math.Ceil(-math.Log10(step)))
+	}
+	if math.Abs(v) < step*1e-9 {
+		v = 0
+	}
+	return strconv.FormatFloat(v, 'f', prec, 64)
+}
+
+// formatValue formats a data value for a tooltip.
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', 6, 64)
+}
+
+// legendEntry is an item of a chart's legend.
+type legendEntry struct {
+	name  string
+	color core.Color
+}
+
+// legendHeight returns the height of a legend, or 0 without entries.
+func legendHeight(entries []legendEntry, style core.TextStyle) float32 {
+	if len(entries) == 0 {
+		return 0
+	}
+	return style.LineHeight() + chartPadding
+}
+
+// paintLegend draws entries in a row along the top of r.
+func paintLegend(ctx *core.PaintContext, r core.Rect, entries []legendEntry, style core.TextStyle) {
+	x := r.X
+	h := style.LineHeight()
+	for _, e := range entries {
+		ctx.Canvas.DrawRoundedRect(core.R(x, r.Y+(h-swatchSize)/2, swatchSize, swatchSize), 2, core.Filled(e.color))
+		x += swatchSize + 4
+		ctx.Canvas.DrawText(e.name, core.Pt(x, r.Y), style)
+		x += ctx.MeasureText(e.name, style).Width + 2*chartPadding
+		if x >= r.Right() {
+			break
+		}
+	}
+}
+
+// seriesLegend returns the legend of named series.
+func seriesLegend(th *theme.Theme, series []Series) []legendEntry {
+	var entries []legendEntry
+	for i, s := range series {
+		if s.Name != "" {
+			entries = append(entries, legendEntry{s.Name, seriesColor(th, i, s.Color)})
+		}
+	}
+	return entries
+}
@@ -0,0 +1,364 @@
+package charts
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/state"
+	"github.com/gogpu/ui/theme"
+)
+
+var chartBounds = core.R(0, 0, chartWidth, chartHeight)
+
+type drawnRect struct {
+	r  core.Rect
+	st core.RectStyle
+}
+
+type drawnPath struct {
+	p  *core.Path
+	st core.PathStyle
+}
+
+// canvas records the rectangles, paths and text drawn on it.
+type canvas struct {
+	core.Recording
+	rects []drawnRect
+	paths []drawnPath
+	texts []string
+}
+
+func (c *canvas) DrawRect(r core.Rect, st core.RectStyle) {
+	c.rects = append(c.rects, drawnRect{r, st})
+	c.Recording.DrawRect(r, st)
+}
+
+func (c *canvas) DrawPath(p *core.Path, st core.PathStyle) {
+	c.paths = append(c.paths, drawnPath{p, st})
+	c.Recording.DrawPath(p, st)
+}
+
+func (c *canvas) DrawText(text string, pos core.Point, st core.TextStyle) {
+	c.texts = append(c.texts, text)
+	c.Recording.DrawText(text, pos, st)
+}
+
+// hasText reports whether s was drawn.
+func (c *canvas) hasText(s string) bool {
+	for _, t := range c.texts {
+		if t == s {
+			return true
+		}
+	}
+	return false
+}
+
+// filled returns the rectangles filled with color.
+func (c *canvas) filled(color core.Color) []core.Rect {
+	var rs []core.Rect
+	for _, d := range c.rects {
+		if d.st.Fill == color {
+			rs = append(rs, d.r)
+		}
+	}
+	return rs
+}
+
+// contours returns the number of contours of p.
+func contours(p *core.Path) int {
+	n := 0
+	for _, s := range p.Segments {
+		if s.Verb == core.MoveTo {
+			n++
+		}
+	}
+	return n
+}
+
+// layoutChart lays w out in chartBounds and paints it, as the window does
+// before the pointer can reach it.
+func layoutChart(w core.Widget) (*core.Context, *canvas) {
+	ctx := core.NewContext()
+	ctx.LayoutRoot(w, chartBounds)
+	return ctx, paint(ctx, w)
+}
+
+func paint(ctx *core.Context, w core.Widget) *canvas {
+	cv := &canvas{}
+	w.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+	return cv
+}
+
+func move(ctx *core.Context, w core.Widget, p core.Point) core.EventResult {
+	return w.HandleEvent(ctx, event.MouseEvent{Type: event.MouseMove, Position: p})
+}
+
+// tipTexts returns the lines of a tooltip.
+func tipTexts(t *tooltip) []string {
+	var s []string
+	for _, l := range t.tip.lines {
+		s = append(s, l.text)
+	}
+	return s
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestNiceTicks(t *testing.T) {
+	tests := []struct {
+		lo, hi float64
+		count  int
+		want   []float64
+		step   float64
+	}{
+		{0, 10, 6, []float64{0, 2, 4, 6, 8, 10}, 2},
+		{0, 1, 6, []float64{0, 0.2, 0.4, 0.6, 0.8, 1}, 0.2},
+		{0, 100, 5, []float64{0, 50, 100}, 50},
+		{0, 70, 6, []float64{0, 20, 40, 60}, 20},
+		{-3, 7, 6, []float64{-2, 0, 2, 4, 6}, 2},
+		{5, 5, 6, []float64{4, 4.5, 5, 5.5, 6}, 0.5},
+		{0, 1000, 1, []float64{0, 1000}, 1000},
+	}
+	for _, tt := range tests {
+		ticks, step := niceTicks(tt.lo, tt.hi, tt.count)
+		ok := len(ticks) == len(tt.want) && near(step, tt.step)
+		for i := range ticks {
+			ok = ok && i < len(tt.want) && near(ticks[i], tt.want[i])
+		}
+		if !ok {
+			t.Errorf("niceTicks(%v, %v, %d) = %v, %v, want %v, %v", tt.lo, tt.hi, tt.count, ticks, step, tt.want, tt.step)
+		}
+	}
+}
+
+func TestExtend(t *testing.T) {
+	tests := []struct {
+		lo, hi         float64
+		wantLo, wantHi float64
+	}{
+		{3, 97, 0, 100},
+		{0, 10, 0, 10},
+		{-0.3, 0.7, -0.4, 0.8},
+		{5, 5, 4, 6},
+		{7, 2, 6, 8},
+	}
+	for _, tt := range tests {
+		if lo, hi := extend(tt.lo, tt.hi); !near(lo, tt.wantLo) || !near(hi, tt.wantHi) {
+			t.Errorf("extend(%v, %v) = %v, %v, want %v, %v", tt.lo, tt.hi, lo, hi, tt.wantLo, tt.wantHi)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		v, step float64
+		want    string
+	}{
+		{5, 1, "5"},
+		{1234, 200, "1234"},
+		{0.5, 0.5, "0.5"},
+		{0.25, 0.05, "0.25"},
+		{1e-17, 0.2, "0.0"},
+		{-1e-17, 0.2, "0.0"},
+		{-2, 0, "-2"},
+	}
+	for _, tt := range tests {
+		if got := formatNumber(tt.v, tt.step); got != tt.want {
+			t.Errorf("formatNumber(%v, %v) = %q, want %q", tt.v, tt.step, got, tt.want)
+		}
+	}
+	for v, want := range map[float64]string{1.0 / 3: "0.333333", 1e7: "1e+07", 42: "42", -0.5: "-0.5"} {
+		if got := formatValue(v); got != want {
+			t.Errorf("formatValue(%v) = %q, want %q", v, got, want)
+		}
+	}
+}
+
+func TestSeries(t *testing.T) {
+	values := Series{Values: []float64{3, 4}}
+	if values.Len() != 2 || values.At(1) != (Point{1, 4}) {
+		t.Errorf("values: Len() = %d, At(1) = %v", values.Len(), values.At(1))
+	}
+	points := Series{Values: []float64{3, 4}, Points: []Point{{5, 6}}}
+	if points.Len() != 1 || points.At(0) != (Point{5, 6}) {
+		t.Errorf("points: Len() = %d, At(0) = %v", points.Len(), points.At(0))
+	}
+
+	th := theme.Light()
+	red := core.Hex(0xFF0000)
+	if got := seriesColor(th, 1, red); got != red {
+		t.Errorf("a series color gave %v", got)
+	}
+	if got := seriesColor(th, 9, core.Color{}); got != th.Chart[1] {
+		t.Errorf("the 10th series is %v, want the palette to repeat", got)
+	}
+	legend := seriesLegend(th, []Series{{Name: "a"}, {}, {Name: "c", Color: red}})
+	if len(legend) != 2 || legend[0] != (legendEntry{"a", th.Chart[0]}) || legend[1] != (legendEntry{"c", red}) {
+		t.Errorf("legend %v, want the named series", legend)
+	}
+	if h := legendHeight(nil, th.Typography.Caption); h != 0 {
+		t.Errorf("an empty legend is %v high", h)
+	}
+}
+
+func TestChartsBind(t *testing.T) {
+	one := []Series{{Values: []float64{1}}}
+	two := []Series{{Values: []float64{1}}, {Values: []float64{2}}}
+	tests := []struct {
+		name   string
+		bind   func(sig *state.Signal[[]Series]) core.Widget
+		series func(w core.Widget) []Series
+	}{
+		{"line", func(sig *state.Signal[[]Series]) core.Widget { return NewLineChart().Bind(sig) },
+			func(w core.Widget) []Series { return w.(*LineChart).Series() }},
+		{"bar", func(sig *state.Signal[[]Series]) core.Widget { return NewBarChart([]string{"a"}).Bind(sig) },
+			func(w core.Widget) []Series { return w.(*BarChart).Series() }},
+		{"scatter", func(sig *state.Signal[[]Series]) core.Widget { return NewScatterChart().Bind(sig) },
+			func(w core.Widget) []Series { return w.(*ScatterChart).Series() }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sig := state.NewFunc(one, nil)
+			w := tt.bind(sig)
+			ctx := core.NewContext()
+			ctx.LayoutRoot(w, chartBounds)
+			if n := len(tt.series(w)); n != 1 {
+				t.Fatalf("%d series after the first layout, want the signal's 1", n)
+			}
+			sig.Set(two)
+			ctx.RunPosted()
+			ctx.LayoutRoot(w, chartBounds)
+			if n := len(tt.series(w)); n != 2 {
+				t.Errorf("%d series after the signal changed, want 2", n)
+			}
+
+			// Binding another signal drops the first one.
+			other := state.NewFunc(one, nil)
+			switch c := w.(type) {
+			case *LineChart:
+				c.Bind(other)
+			case *BarChart:
+				c.Bind(other)
+			case *ScatterChart:
+				c.Bind(other)
+			}
+			ctx.Invalidate()
+			ctx.LayoutRoot(w, chartBounds)
+			sig.Set(two)
+			if ctx.HasPosted() || len(tt.series(w)) != 1 {
+				t.Error("the old signal still drives the chart")
+			}
+		})
+	}
+}
+
+func TestChartsRelease(t *testing.T) {
+	type released interface {
+		core.Widget
+		Release()
+	}
+	// series returns a build of a chart bound to a new signal of series.
+	series := func(bind func(sig *state.Signal[[]Series]) released) func() (released, func()) {
+		return func() (released, func()) {
+			sig := state.NewFunc([]Series{{Values: []float64{1}}}, nil)
+			return bind(sig), func() { sig.Set(nil) }
+		}
+	}
+	tests := []struct {
+		name  string
+		build func() (released, func())
+	}{
+		{"line", series(func(sig *state.Signal[[]Series]) released { return NewLineChart().Bind(sig) })},
+		{"bar", series(func(sig *state.Signal[[]Series]) released { return NewBarChart([]string{"a"}).Bind(sig) })},
+		{"scatter", series(func(sig *state.Signal[[]Series]) released { return NewScatterChart().Bind(sig) })},
+		{"pie", func() (released, func()) {
+			sig := state.NewFunc([]Slice{{Value: 1}}, nil)
+			return NewPieChart().Bind(sig), func() { sig.Set(nil) }
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, change := tt.build()
+			ctx := core.NewContext()
+			ctx.LayoutRoot(w, chartBounds)
+			w.Release()
+			change()
+			if ctx.HasPosted() {
+				t.Error("released, the chart still follows the signal")
+			}
+			ctx.Invalidate()
+			ctx.LayoutRoot(w, chartBounds)
+			change()
+			if !ctx.HasPosted() {
+				t.Error("laid out again, the chart does not follow the signal")
+			}
+		})
+	}
+}
+
+func TestChartSize(t *testing.T) {
+	ctx := core.NewContext()
+	lc := &core.LayoutContext{Context: ctx}
+	for _, w := range []core.Widget{NewLineChart(), NewBarChart(nil), NewPieChart(), NewScatterChart()} {
+		if s := lc.Measure(w, core.Unbounded()); s != core.Sz(chartWidth, chartHeight) {
+			t.Errorf("%T: unbounded size %v", w, s)
+		}
+		if s := lc.Measure(w, core.Loose(core.Sz(600, 100))); s != core.Sz(600, 100) {
+			t.Errorf("%T: size %v in 600 by 100, want the width and the room there is", w, s)
+		}
+	}
+}
+
+func TestTip(t *testing.T) {
+	ctx := core.NewContext()
+	th := theme.From(ctx)
+	lc := &core.LayoutContext{Context: ctx}
+	plain := &tip{lines: []tipLine{{text: "abc"}, {text: "a"}}}
+	s := lc.Measure(plain, core.Unbounded())
+	style := theme.TextStyle(th.Typography.Caption, th.Colors.Surface)
+	if want := ctx.MeasureText("abc", style).Width + 2*tipPadding; s.Width != want {
+		t.Errorf("width %v, want the widest line %v", s.Width, want)
+	}
+	if want := 2*style.LineHeight() + 2*tipPadding; s.Height != want {
+		t.Errorf("height %v, want two lines %v", s.Height, want)
+	}
+	swatched := &tip{lines: []tipLine{{color: th.Chart[0], text: "abc"}}}
+	if w := lc.Measure(swatched, core.Unbounded()).Width; w != s.Width+swatchSize+4 {
+		t.Errorf("width %v with a swatch, want room for it", w)
+	}
+
+	var tt tooltip
+	tt.show(ctx, core.Pt(10, 10), []tipLine{{text: "one"}})
+	tt.show(ctx, core.Pt(20, 10), []tipLine{{text: "two"}})
+	if len(ctx.Overlays()) != 1 || !ctx.Overlays()[0].Passive {
+		t.Fatalf("%d overlays shown, want one passive tooltip", len(ctx.Overlays()))
+	}
+	tt.hide(ctx)
+	tt.hide(ctx)
+	if len(ctx.Overlays()) != 0 || tt.overlay != nil {
+		t.Error("the tooltip stayed open")
+	}
+
+	// The window may close the tooltip itself.
+	tt.show(ctx, core.Pt(10, 10), []tipLine{{text: "one"}})
+	ctx.CloseOverlay(ctx.Overlays()[0])
+	if tt.overlay != nil {
+		t.Error("the closed tooltip is still held")
+	}
+}
@@ -0,0 +1,250 @@
+package charts
+
+import (
+	"math"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/state"
+	"github.com/gogpu/ui/theme"
+)
+
+// LineChart plots series as lines over a numeric x axis.
+//
+// The axes fit the data unless XRange or YRange fix them, as a live view
+// that scrolls through time does. A NaN value leaves a gap in its line.
+// Hovering shows a guide at the pointer's x and the value of each series
+// there.
+type LineChart struct {
+	core.WidgetBase
+	plot
+
+	series  []Series
+	bind    binding[[]Series]
+	fill    bool
+	markers bool
+	width   float32
+
+	hovering bool
+	hoverX   float64
+	tooltip  tooltip
+}
+
+// NewLineChart returns a LineChart of series.
+func NewLineChart(series ...Series) *LineChart {
+	return &LineChart{series: series, width: 2}
+}
+
+// Series returns the series shown.
+func (c *LineChart) Series() []Series {
+	return c.series
+}
+
+// SetSeries replaces the series shown.
+func (c *LineChart) SetSeries(series ...Series) {
+	c.series = series
+}
+
+// Bind makes the chart show the series held by sig.
+func (c *LineChart) Bind(sig *state.Signal[[]Series]) *LineChart {
+	c.bind.bind(sig)
+	return c
+}
+
+// Release stops following the bound signal, for when the chart leaves
+// the tree. Laid out again, the chart follows the signal from its value
+// then.
+func (c *LineChart) Release() {
+	c.bind.release()
+}
+
+// Area fills the area between each line and zero with a light tint of its
+// color.
+func (c *LineChart) Area(on bool) *LineChart {
+	c.fill = on
+	return c
+}
+
+// Markers draws a dot at each point.
+func (c *LineChart) Markers(on bool) *LineChart {
+	c.markers = on
+	return c
+}
+
+// LineWidth sets the width of the lines. The default is 2.
+func (c *LineChart) LineWidth(w float32) *LineChart {
+	c.width = w
+	return c
+}
+
+// XRange fixes the x axis to lo to hi.
+func (c *LineChart) XRange(lo, hi float64) *LineChart {
+	c.xLo, c.xHi, c.xFixed = lo, hi, hi > lo
+	return c
+}
+
+// YRange fixes the y axis to lo to hi.
+func (c *LineChart) YRange(lo, hi float64) *LineChart {
+	c.yLo, c.yHi, c.yFixed = lo, hi, hi > lo
+	return c
+}
+
+// XFormat sets how x values are written, for example as times.
+func (c *LineChart) XFormat(fn func(x float64) string) *LineChart {
+	c.xFormat = fn
+	return c
+}
+
+// YFormat sets how y values are written.
+func (c *LineChart) YFormat(fn func(y float64) string) *LineChart {
+	c.yFormat = fn
+	return c
+}
+
+// Legend shows or hides the legend of named series. It is shown by
+// default.
+func (c *LineChart) Legend(show bool) *LineChart {
+	c.noLegend = !show
+	return c
+}
+
+// Layout implements core.Widget.
+func (c *LineChart) Layout(ctx *core.LayoutContext) core.Size {
+	c.bind.sync(ctx.Context, c, func(s []Series) { c.series = s })
+	return chartSize(ctx)
+}
+
+// bounds returns the range of the data.
+func (c *LineChart) bounds() (x0, x1, y0, y1 float64) {
+	x0, y0 = math.Inf(1), math.Inf(1)
+	x1, y1 = math.Inf(-1), math.Inf(-1)
+	for i := range c.series {
+		s := &c.series[i]
+		n := s.Len()
+		if n == 0 {
+			continue
+		}
+		x0, x1 = math.Min(x0, s.At(0).X), math.Max(x1, s.At(n-1).X)
+		for k := range n {
+			if y := s.At(k).Y; !math.IsNaN(y) {
+				y0, y1 = math.Min(y0, y), math.Max(y1, y)
+			}
+		}
+	}
+	if math.IsInf(x0, 1) {
+		x0, x1 = 0, 1
+	}
+	if math.IsInf(y0, 1) {
+		y0, y1 = 0, 1
+	}
+	return x0, x1, y0, y1
+}
+
+// Paint implements core.Widget.
+func (c *LineChart) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	r := inner(c.Bounds())
+	var legend []legendEntry
+	if !c.noLegend {
+		legend = seriesLegend(th, c.series)
+	}
+	x0, x1, y0, y1 := c.bounds()
+	c.layout(ctx.Context, th, r, x0, x1, y0, y1, 0, legend)
+	paintLegend(ctx, r, legend, theme.TextStyle(th.Typography.Caption, th.Colors.OnSurface))
+	c.paintAxes(ctx, th, nil)
+
+	cv.Save()
+	cv.Clip(c.area)
+	base := c.baseline()
+	for i := range c.series {
+		s := &c.series[i]
+		color := seriesColor(th, i, s.Color)
+		runs := c.polylines(s)
+		if c.fill {
+			for _, run := range runs {
+				path := core.NewPath().MoveTo(core.Pt(run[0].X, base))
+				for _, q := range run {
+					path.LineTo(q)
+				}
+				path.LineTo(core.Pt(run[len(run)-1].X, base)).Close()
+				cv.DrawPath(path, core.PathStyle{Fill: color.WithAlpha(0.15)})
+			}
+		}
+		path := core.NewPath()
+		for _, run := range runs {
+			path.MoveTo(run[0])
+			for _, q := range run[1:] {
+				path.LineTo(q)
+			}
+		}
+		cv.DrawPath(path, core.PathStyle{Stroke: color, StrokeWidth: c.width, LineCap: core.CapRound, LineJoin: core.JoinRound})
+		if c.markers {
+			dots := core.NewPath()
+			for _, run := range runs {
+				for _, q := range run {
+					dots.AddEllipse(core.R(q.X-c.width-1, q.Y-c.width-1, 2*c.width+2, 2*c.width+2))
+				}
+			}
+			cv.DrawPath(dots, core.PathStyle{Fill: color})
+		}
+	}
+	if c.hovering {
+		x := c.screenX(c.hoverX)
+		cv.DrawRect(core.R(x-0.5, c.area.Y, 1, c.area.Height), core.Filled(th.Colors.Outline.WithAlpha(0.6)))
+		for i := range c.series {
+			s := &c.series[i]
+			if k := nearest(s, c.hoverX); k >= 0 && !math.IsNaN(s.At(k).Y) {
+				q := c.screen(s.At(k))
+				dot := core.NewPath().AddEllipse(core.R(q.X-4, q.Y-4, 8, 8))
+				cv.DrawPath(dot, core.PathStyle{Fill: seriesColor(th, i, s.Color), Stroke: th.Colors.Surface, StrokeWidth: 2})
+			}
+		}
+	}
+	cv.Restore()
+}
+
+// HandleEvent implements core.Widget.
+func (c *LineChart) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	e, ok := ev.(event.MouseEvent)
+	if !ok {
+		return core.Ignored
+	}
+	if e.Type == event.MouseLeave || !c.area.Contains(e.Position) {
+		if c.hovering {
+			c.hovering = false
+			c.tooltip.hide(ctx)
+			ctx.Repaint(c)
+		}
+		return core.Ignored
+	}
+	if e.Type != event.MouseMove {
+		return core.Ignored
+	}
+	th := theme.From(ctx)
+	c.hovering = true
+	c.hoverX = c.dataX(e.Position.X)
+	// Snap to the nearest point of the first series, so the guide and the
+	// tooltip show actual samples.
+	for i := range c.series {
+		if k := nearest(&c.series[i], c.hoverX); k >= 0 {
+			c.hoverX = c.series[i].At(k).X
+			break
+		}
+	}
+	lines := []tipLine{{text: c.xValue(c.hoverX)}}
+	for i := range c.series {
+		s := &c.series[i]
+		k := nearest(s, c.hoverX)
+		if k < 0 || math.IsNaN(s.At(k).Y) {
+			continue
+		}
+		text := c.yValue(s.At(k).Y)
+		if s.Name != "" {
+			text = s.Name + ": " + text
+		}
+		lines = append(lines, tipLine{seriesColor(th, i, s.Color), text})
+	}
+	c.tooltip.show(ctx, e.Position, lines)
+	return core.Handled
+}
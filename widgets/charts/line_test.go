@@ -0,0 +1,144 @@
+package charts
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/theme"
+)
+
+func TestLineChartBounds(t *testing.T) {
+	tests := []struct {
+		name   string
+		series []Series
+		want   [4]float64
+	}{
+		{"empty", nil, [4]float64{0, 1, 0, 1}},
+		{"values", []Series{{Values: []float64{1, 5, math.NaN(), 3}}}, [4]float64{0, 3, 1, 5}},
+		{"points and values", []Series{{Values: []float64{1, 5}}, {Points: []Point{{-2, 0}, {3, 10}}}}, [4]float64{-2, 3, 0, 10}},
+		{"only NaN", []Series{{Values: []float64{math.NaN(), math.NaN()}}}, [4]float64{0, 1, 0, 1}},
+	}
+	for _, tt := range tests {
+		c := NewLineChart(tt.series...)
+		if x0, x1, y0, y1 := c.bounds(); [4]float64{x0, x1, y0, y1} != tt.want {
+			t.Errorf("%s: bounds %v, %v, %v, %v, want %v", tt.name, x0, x1, y0, y1, tt.want)
+		}
+	}
+}
+
+func TestLineChartPaint(t *testing.T) {
+	red := core.Hex(0xFF0000)
+	series := []Series{
+		{Name: "cpu", Values: []float64{1, 4, 2, 8}},
+		{Values: []float64{3, math.NaN(), 5, 6}, Color: red},
+	}
+	tests := []struct {
+		name    string
+		chart   *LineChart
+		strokes int
+		fills   int
+		dots    int
+		legend  bool
+	}{
+		{"lines", NewLineChart(series...), 2, 0, 0, true},
+		{"area", NewLineChart(series...).Area(true), 2, 3, 0, true},
+		{"markers", NewLineChart(series...).Markers(true).LineWidth(1), 2, 2, 7, true},
+		{"no legend", NewLineChart(series...).Legend(false), 2, 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cv := layoutChart(tt.chart)
+			th := theme.From(ctx)
+			var strokes, fills, dots int
+			for _, d := range cv.paths {
+				switch {
+				case d.st.Stroke != (core.Color{}):
+					strokes++
+					if d.st.StrokeWidth != tt.chart.width {
+						t.Errorf("a line %v wide, want %v", d.st.StrokeWidth, tt.chart.width)
+					}
+				case d.st.Fill.A < 1:
+					fills++
+				default:
+					fills++
+					dots += contours(d.p)
+				}
+			}
+			if strokes != tt.strokes || fills != tt.fills || dots != tt.dots {
+				t.Errorf("%d lines, %d fills with %d dots, want %d, %d, %d", strokes, fills, dots, tt.strokes, tt.fills, tt.dots)
+			}
+			if cv.paths[0].st.Stroke != th.Chart[0] && cv.paths[0].st.Fill.WithAlpha(1) != th.Chart[0] {
+				t.Errorf("the first series is drawn in %v, want the palette's first color", cv.paths[0].st)
+			}
+			if got := cv.paths[len(cv.paths)-1].st; got.Stroke != red && got.Fill != red {
+				t.Errorf("the second series is drawn in %v, want its own color", got)
+			}
+			if cv.hasText("cpu") != tt.legend {
+				t.Errorf("legend shown %v, want %v", cv.hasText("cpu"), tt.legend)
+			}
+		})
+	}
+}
+
+func TestLineChartHover(t *testing.T) {
+	c := NewLineChart(
+		Series{Name: "a", Values: []float64{10, 20, 30, 40}},
+		Series{Values: []float64{1, 2, math.NaN(), 4}},
+	)
+	ctx, _ := layoutChart(c)
+	if r := move(ctx, c, core.Pt(c.screenX(2.3), c.area.Center().Y)); r != core.Handled {
+		t.Fatalf("moving over the plot = %v", r)
+	}
+	if c.hoverX != 2 {
+		t.Errorf("hovering at x %v, want it snapped to the sample at 2", c.hoverX)
+	}
+	if got, want := tipTexts(&c.tooltip), []string{"2", "a: 30"}; !equalStrings(got, want) {
+		t.Errorf("tooltip %q, want %q", got, want)
+	}
+	if len(ctx.Overlays()) != 1 {
+		t.Errorf("%d overlays, want the tooltip", len(ctx.Overlays()))
+	}
+	cv := paint(ctx, c)
+	if guides := cv.filled(theme.From(ctx).Colors.Outline.WithAlpha(0.6)); len(guides) < 2 {
+		t.Error("no guide was drawn at the pointer")
+	}
+
+	c.XFormat(func(x float64) string { return "t" + formatValue(x) }).YFormat(func(y float64) string { return formatValue(y) + "%" })
+	move(ctx, c, core.Pt(c.screenX(0.9), c.area.Center().Y))
+	if got, want := tipTexts(&c.tooltip), []string{"t1", "a: 20%", "2%"}; !equalStrings(got, want) {
+		t.Errorf("formatted tooltip %q, want %q", got, want)
+	}
+
+	for _, ev := range []event.MouseEvent{
+		{Type: event.MouseMove, Position: core.Pt(c.area.X-1, c.area.Y)},
+		{Type: event.MouseLeave},
+	} {
+		move(ctx, c, c.area.Center())
+		if r := c.HandleEvent(ctx, ev); r != core.Ignored || c.hovering || len(ctx.Overlays()) != 0 {
+			t.Errorf("%v left the hover on with %d overlays", ev.Type, len(ctx.Overlays()))
+		}
+	}
+	if r := c.HandleEvent(ctx, event.MouseEvent{Type: event.MouseDown, Position: c.area.Center()}); r != core.Ignored {
+		t.Errorf("a press = %v", r)
+	}
+}
+
+func TestLineChartRanges(t *testing.T) {
+	c := NewLineChart(Series{Values: []float64{1, 2, 3}}).XRange(0, 100).YRange(-5, 5)
+	ctx, _ := layoutChart(c)
+	if c.x0 != 0 || c.x1 != 100 || c.y0 != -5 || c.y1 != 5 {
+		t.Errorf("shows %v to %v by %v to %v", c.x0, c.x1, c.y0, c.y1)
+	}
+	c.XRange(1, 1).YRange(3, 2)
+	paint(ctx, c)
+	if c.x0 != 0 || c.x1 != 2 || c.y0 != 1 || c.y1 != 3 {
+		t.Errorf("an empty range did not fit the data: %v to %v by %v to %v", c.x0, c.x1, c.y0, c.y1)
+	}
+
+	c.SetSeries(Series{Values: []float64{7}})
+	if len(c.Series()) != 1 || c.Series()[0].Values[0] != 7 {
+		t.Errorf("Series() = %v", c.Series())
+	}
+}
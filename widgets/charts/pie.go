@@ -0,0 +1,224 @@
+package charts
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/state"
+	"github.com/gogpu/ui/theme"
+)
+
+// pieLift is how far the hovered slice moves out of the pie.
+const pieLift float32 = 6
+
+// Slice is a part of a pie chart.
+type Slice struct {
+	Label string
+	Value float64
+	Color core.Color // transparent picks a color of the theme's chart palette
+}
+
+// PieChart shows how slices make up a whole, as a pie or, with Donut, a
+// ring. Slices run clockwise from the top; those whose value is not
+// positive are left out. Hovering a slice lifts it out and shows its value
+// and share.
+type PieChart struct {
+	core.WidgetBase
+
+	slices   []Slice
+	bind     binding[[]Slice]
+	hole     float32
+	noLegend bool
+
+	center  core.Point
+	radius  float32
+	hover   int // slice under the pointer, or -1
+	tooltip tooltip
+}
+
+// NewPieChart returns a PieChart of slices.
+func NewPieChart(slices ...Slice) *PieChart {
+	return &PieChart{slices: slices, hover: -1}
+}
+
+// Slices returns the slices shown.
+func (c *PieChart) Slices() []Slice {
+	return c.slices
+}
+
+// SetSlices replaces the slices shown.
+func (c *PieChart) SetSlices(slices ...Slice) {
+	c.slices = slices
+	c.hover = -1
+}
+
+// Bind makes the chart show the slices held by sig.
+func (c *PieChart) Bind(sig *state.Signal[[]Slice]) *PieChart {
+	c.bind.bind(sig)
+	return c
+}
+
+// Release stops following the bound signal, for when the chart leaves
+// the tree. Laid out again, the chart follows the signal from its value
+// then.
+func (c *PieChart) Release() {
+	c.bind.release()
+}
+
+// Donut cuts a hole out of the middle, hole being its radius as a part of
+// the pie's, from 0 to 0.9.
+func (c *PieChart) Donut(hole float32) *PieChart {
+	c.hole = core.Clamp(hole, 0, 0.9)
+	return c
+}
+
+// Legend shows or hides the legend of labeled slices. It is shown by
+// default.
+func (c *PieChart) Legend(show bool) *PieChart {
+	c.noLegend = !show
+	return c
+}
+
+// Layout implements core.Widget.
+func (c *PieChart) Layout(ctx *core.LayoutContext) core.Size {
+	c.bind.sync(ctx.Context, c, func(s []Slice) {
+		c.slices = s
+		if c.hover >= len(s) {
+			c.hover = -1
+		}
+	})
+	return chartSize(ctx)
+}
+
+// total returns the sum of the positive values.
+func (c *PieChart) total() float64 {
+	var sum float64
+	for _, s := range c.slices {
+		if s.Value > 0 {
+			sum += s.Value
+		}
+	}
+	return sum
+}
+
+// angles returns where slice i starts and how far it sweeps, in radians
+// clockwise from the right as AddArc measures them.
+func (c *PieChart) angles(i int, total float64) (start, sweep float64) {
+	start = -math.Pi / 2
+	for _, s := range c.slices[:i] {
+		if s.Value > 0 {
+			start += 2 * math.Pi * s.Value / total
+		}
+	}
+	return start, 2 * math.Pi * math.Max(c.slices[i].Value, 0) / total
+}
+
+// Paint implements core.Widget.
+func (c *PieChart) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	r := inner(c.Bounds())
+	style := theme.TextStyle(th.Typography.Caption, th.Colors.OnSurface)
+	var legend []legendEntry
+	if !c.noLegend {
+		for i, s := range c.slices {
+			if s.Label != "" && s.Value > 0 {
+				legend = append(legend, legendEntry{s.Label, seriesColor(th, i, s.Color)})
+			}
+		}
+	}
+	paintLegend(ctx, r, legend, style)
+	top := legendHeight(legend, style)
+	area := core.R(r.X, r.Y+top, r.Width, max(0, r.Height-top))
+	c.center = area.Center()
+	c.radius = max(0, min(area.Width, area.Height)/2-pieLift)
+
+	total := c.total()
+	if total <= 0 || c.radius <= 0 {
+		return
+	}
+	for i, s := range c.slices {
+		if s.Value <= 0 {
+			continue
+		}
+		start, sweep := c.angles(i, total)
+		center := c.center
+		if i == c.hover {
+			mid := start + sweep/2
+			center = center.Add(core.Pt(float32(math.Cos(mid))*pieLift, float32(math.Sin(mid))*pieLift))
+		}
+		outer := core.R(center.X-c.radius, center.Y-c.radius, 2*c.radius, 2*c.radius)
+		path := core.NewPath()
+		if c.hole > 0 {
+			hr := c.radius * c.hole
+			path.AddArc(outer, start, sweep)
+			path.AddArc(core.R(center.X-hr, center.Y-hr, 2*hr, 2*hr), start+sweep, -sweep)
+		} else {
+			path.MoveTo(center)
+			path.AddArc(outer, start, sweep)
+		}
+		path.Close()
+		st := core.PathStyle{Fill: seriesColor(th, i, s.Color)}
+		if len(c.slices) > 1 {
+			st.Stroke, st.StrokeWidth, st.LineJoin = th.Colors.Surface, 1.5, core.JoinRound
+		}
+		cv.DrawPath(path, st)
+	}
+}
+
+// sliceAt returns the slice under p, or -1.
+func (c *PieChart) sliceAt(p core.Point) int {
+	total := c.total()
+	d := p.Sub(c.center)
+	dist := float32(math.Hypot(float64(d.X), float64(d.Y)))
+	if total <= 0 || dist > c.radius || dist < c.radius*c.hole {
+		return -1
+	}
+	// The angle of p clockwise from the top.
+	a := math.Atan2(float64(d.Y), float64(d.X)) + math.Pi/2
+	if a < 0 {
+		a += 2 * math.Pi
+	}
+	var at float64
+	for i, s := range c.slices {
+		if s.Value <= 0 {
+			continue
+		}
+		at += 2 * math.Pi * s.Value / total
+		if a < at {
+			return i
+		}
+	}
+	return -1
+}
+
+// HandleEvent implements core.Widget.
+func (c *PieChart) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	e, ok := ev.(event.MouseEvent)
+	if !ok || e.Type != event.MouseMove && e.Type != event.MouseLeave {
+		return core.Ignored
+	}
+	hover := -1
+	if e.Type == event.MouseMove {
+		hover = c.sliceAt(e.Position)
+	}
+	if hover != c.hover {
+		c.hover = hover
+		ctx.Repaint(c)
+	}
+	if hover < 0 {
+		c.tooltip.hide(ctx)
+		return core.Ignored
+	}
+	th := theme.From(ctx)
+	s := c.slices[hover]
+	share := strconv.FormatFloat(100*s.Value/c.total(), 'f', 1, 64) + "%"
+	text := formatValue(s.Value) + " (" + share + ")"
+	if s.Label != "" {
+		text = s.Label + ": " + text
+	}
+	c.tooltip.show(ctx, e.Position, []tipLine{{seriesColor(th, hover, s.Color), text}})
+	return core.Handled
+}
@@ -0,0 +1,127 @@
+package charts
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/state"
+)
+
+func newPieChart() *PieChart {
+	return NewPieChart(Slice{Label: "A", Value: 1}, Slice{Label: "B", Value: 0}, Slice{Label: "C", Value: 3})
+}
+
+// pieAt returns the point of c at angle degrees clockwise from the top, a
+// part f of the radius from the center.
+func pieAt(c *PieChart, degrees float64, f float32) core.Point {
+	a := degrees * math.Pi / 180
+	return c.center.Add(core.Pt(float32(math.Sin(a))*c.radius*f, -float32(math.Cos(a))*c.radius*f))
+}
+
+func TestPieChartSliceAt(t *testing.T) {
+	tests := []struct {
+		name    string
+		hole    float32
+		degrees float64
+		f       float32
+		want    int
+	}{
+		{"first quarter", 0, 45, 0.5, 0},
+		{"rest", 0, 180, 0.5, 2},
+		{"just before the top", 0, 359, 0.9, 2},
+		{"outside", 0, 45, 1.1, -1},
+		{"in the hole", 0.5, 45, 0.4, -1},
+		{"on the ring", 0.5, 45, 0.6, 0},
+	}
+	for _, tt := range tests {
+		c := newPieChart().Donut(tt.hole)
+		layoutChart(c)
+		if got := c.sliceAt(pieAt(c, tt.degrees, tt.f)); got != tt.want {
+			t.Errorf("%s: sliceAt = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+
+	if c := NewPieChart().Donut(2); c.hole != 0.9 {
+		t.Errorf("Donut(2) gave a hole of %v", c.hole)
+	}
+	c := NewPieChart(Slice{Value: -1})
+	layoutChart(c)
+	if got := c.sliceAt(c.center); got != -1 {
+		t.Errorf("a pie with nothing to show has slice %d", got)
+	}
+}
+
+func TestPieChartPaint(t *testing.T) {
+	c := newPieChart()
+	ctx, cv := layoutChart(c)
+	if len(cv.paths) != 2 {
+		t.Fatalf("%d slices drawn, want the 2 positive ones", len(cv.paths))
+	}
+	if !cv.paths[0].p.Contains(pieAt(c, 45, 0.5), core.FillNonZero) || cv.paths[0].p.Contains(pieAt(c, 180, 0.5), core.FillNonZero) {
+		t.Error("the first slice does not cover the first quarter")
+	}
+	if cv.paths[0].st.StrokeWidth == 0 {
+		t.Error("the slices are not outlined")
+	}
+	if !cv.hasText("A") || !cv.hasText("C") || cv.hasText("B") {
+		t.Errorf("legend %q, want the positive slices", cv.texts)
+	}
+
+	still := cv.paths[1].p.Bounds()
+	move(ctx, c, pieAt(c, 180, 0.5))
+	cv = paint(ctx, c)
+	if lifted := cv.paths[1].p.Bounds(); lifted.Y <= still.Y || lifted.Height != still.Height {
+		t.Errorf("the hovered slice moved from %v to %v, want it lifted down and out", still, lifted)
+	}
+
+	c = NewPieChart(Slice{Value: 2}).Donut(0.5)
+	_, cv = layoutChart(c)
+	if len(cv.paths) != 1 || cv.paths[0].st.StrokeWidth != 0 || cv.paths[0].p.Contains(c.center, core.FillNonZero) {
+		t.Error("a single ring should be drawn whole, without an outline, around its hole")
+	}
+	if _, cv = layoutChart(NewPieChart(Slice{Value: -1})); len(cv.paths) != 0 {
+		t.Error("a pie with nothing to show drew slices")
+	}
+}
+
+func TestPieChartHover(t *testing.T) {
+	c := newPieChart()
+	ctx, _ := layoutChart(c)
+	if r := move(ctx, c, pieAt(c, 180, 0.5)); r != core.Handled || c.hover != 2 {
+		t.Fatalf("moving over C = %v, hovering %d", r, c.hover)
+	}
+	if got, want := tipTexts(&c.tooltip), []string{"C: 3 (75.0%)"}; !equalStrings(got, want) {
+		t.Errorf("tooltip %q, want %q", got, want)
+	}
+	c.SetSlices(Slice{Value: 1}, Slice{Value: 1})
+	if c.hover != -1 || len(c.Slices()) != 2 {
+		t.Error("SetSlices kept the hover")
+	}
+	paint(ctx, c)
+	move(ctx, c, pieAt(c, 90, 0.5))
+	if got, want := tipTexts(&c.tooltip), []string{"1 (50.0%)"}; !equalStrings(got, want) {
+		t.Errorf("tooltip %q, want %q", got, want)
+	}
+	c.HandleEvent(ctx, event.MouseEvent{Type: event.MouseLeave})
+	if c.hover != -1 || len(ctx.Overlays()) != 0 {
+		t.Error("leaving kept the hover")
+	}
+}
+
+func TestPieChartBind(t *testing.T) {
+	sig := state.NewFunc([]Slice{{Value: 1}, {Value: 1}, {Value: 1}}, nil)
+	c := NewPieChart().Bind(sig).Legend(false)
+	ctx, _ := layoutChart(c)
+	move(ctx, c, pieAt(c, 300, 0.5))
+	if c.hover != 2 {
+		t.Fatalf("hovering %d, want the third slice", c.hover)
+	}
+	sig.Set([]Slice{{Value: 1}})
+	ctx.RunPosted()
+	ctx.LayoutRoot(c, chartBounds)
+	if len(c.Slices()) != 1 || c.hover != -1 {
+		t.Errorf("%d slices after the signal changed, hovering %d", len(c.Slices()), c.hover)
+	}
+}
@@ -0,0 +1,249 @@
+package charts
+
+import (
+	"math"
+	"sort"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/theme"
+)
+
+// plot holds the options and layout of the axes of a cartesian chart.
+type plot struct {
+	xLo, xHi, yLo, yHi float64
+	xFixed, yFixed     bool
+	xFormat, yFormat   func(float64) string
+	noLegend           bool
+
+	// Set by layout: the plot area, the data range it shows and the
+	// ticks of both axes.
+	area           core.Rect
+	x0, x1, y0, y1 float64
+	xTicks, yTicks []float64
+	xStep, yStep   float64
+	style          core.TextStyle
+}
+
+// layout fits axes showing x0 to x1 and y0 to y1, unless the range of an
+// axis is fixed, into r below the legend. Category axes have a slot for
+// each of n categories, centered on x = 0, 1 and so on.
+func (p *plot) layout(ctx *core.Context, th *theme.Theme, r core.Rect, x0, x1, y0, y1 float64, categories int, legend []legendEntry) {
+	p.style = theme.TextStyle(th.Typography.Caption, th.Colors.OnSurfaceVariant)
+	if p.yFixed {
+		y0, y1 = p.yLo, p.yHi
+	} else {
+		y0, y1 = extend(y0, y1)
+	}
+	if categories > 0 {
+		x0, x1 = -0.5, float64(categories)-0.5
+	} else if p.xFixed {
+		x0, x1 = p.xLo, p.xHi
+	} else if !(x1 > x0) {
+		x0, x1 = x0-1, x0+1
+	}
+	p.x0, p.x1, p.y0, p.y1 = x0, x1, y0, y1
+
+	lh := p.style.LineHeight()
+	h := max(0, r.Height-legendHeight(legend, p.style)-lh-chartPadding)
+	p.yTicks, p.yStep = niceTicks(y0, y1, max(2, min(chartTicks, int(h/(2*lh)))))
+	var labelW float32
+	for _, t := range p.yTicks {
+		labelW = max(labelW, ctx.MeasureText(p.yLabel(t), p.style).Width)
+	}
+	top := r.Y + legendHeight(legend, p.style)
+	p.area = core.R(r.X+labelW+chartPadding, top, max(0, r.Width-labelW-2*chartPadding), h)
+	p.xTicks = nil
+	if categories == 0 {
+		p.xTicks, p.xStep = niceTicks(x0, x1, max(2, min(chartTicks+2, int(p.area.Width/80))))
+	}
+}
+
+func (p *plot) xLabel(v float64) string {
+	if p.xFormat != nil {
+		return p.xFormat(v)
+	}
+	return formatNumber(v, p.xStep)
+}
+
+func (p *plot) yLabel(v float64) string {
+	if p.yFormat != nil {
+		return p.yFormat(v)
+	}
+	return formatNumber(v, p.yStep)
+}
+
+// xValue formats an x value for a tooltip.
+func (p *plot) xValue(v float64) string {
+	if p.xFormat != nil {
+		return p.xFormat(v)
+	}
+	return formatValue(v)
+}
+
+// yValue formats a y value for a tooltip.
+func (p *plot) yValue(v float64) string {
+	if p.yFormat != nil {
+		return p.yFormat(v)
+	}
+	return formatValue(v)
+}
+
+// screenX returns where x is drawn.
+func (p *plot) screenX(x float64) float32 {
+	return p.area.X + float32((x-p.x0)/(p.x1-p.x0))*p.area.Width
+}
+
+// screenY returns where y is drawn.
+func (p *plot) screenY(y float64) float32 {
+	return p.area.Bottom() - float32((y-p.y0)/(p.y1-p.y0))*p.area.Height
+}
+
+func (p *plot) screen(pt Point) core.Point {
+	return core.Pt(p.screenX(pt.X), p.screenY(pt.Y))
+}
+
+// dataX returns the x value drawn at sx.
+func (p *plot) dataX(sx float32) float64 {
+	return p.x0 + float64((sx-p.area.X)/p.area.Width)*(p.x1-p.x0)
+}
+
+// baseline returns where bars and filled areas start: at zero, or at the
+// edge of the plot nearest to it.
+func (p *plot) baseline() float32 {
+	return p.screenY(math.Max(p.y0, math.Min(0, p.y1)))
+}
+
+// paintAxes draws the grid, the tick labels and, for category axes, the
+// category names.
+func (p *plot) paintAxes(ctx *core.PaintContext, th *theme.Theme, categories []string) {
+	cv := ctx.Canvas
+	a := p.area
+	lh := p.style.LineHeight()
+	grid := th.Colors.Outline.WithAlpha(0.25)
+	for _, t := range p.yTicks {
+		y := p.screenY(t)
+		cv.DrawRect(core.R(a.X, y-0.5, a.Width, 1), core.Filled(grid))
+		label := p.yLabel(t)
+		w := ctx.MeasureText(label, p.style).Width
+		cv.DrawText(label, core.Pt(a.X-chartPadding-w, y-lh/2), p.style)
+	}
+	cv.DrawRect(core.R(a.X, a.Bottom()-0.5, a.Width, 1), core.Filled(th.Colors.Outline.WithAlpha(0.6)))
+	label := func(text string, x float32, room float32) {
+		w := ctx.MeasureText(text, p.style).Width
+		if w > room {
+			return
+		}
+		x = core.Clamp(x-w/2, a.X-chartPadding, a.Right()+chartPadding-w)
+		cv.DrawText(text, core.Pt(x, a.Bottom()+chartPadding/2), p.style)
+	}
+	if categories != nil {
+		slot := a.Width / float32(max(len(categories), 1))
+		for i, c := range categories {
+			label(c, p.screenX(float64(i)), slot)
+		}
+		return
+	}
+	for _, t := range p.xTicks {
+		label(p.xLabel(t), p.screenX(t), a.Width)
+	}
+}
+
+// polylines returns the screen points of s, in runs broken where a value
+// is NaN. Of the points in each pixel column only the first, the lowest,
+// the highest and the last are kept, so the path stays small however
+// dense the series is.
+func (p *plot) polylines(s *Series) [][]core.Point {
+	n := s.Len()
+	from := max(0, sort.Search(n, func(i int) bool { return s.At(i).X >= p.x0 })-1)
+	to := min(n, sort.Search(n, func(i int) bool { return s.At(i).X > p.x1 })+1)
+	var (
+		runs                [][]core.Point
+		run                 []core.Point
+		col                 int
+		count               int
+		first, lo, hi, last core.Point
+	)
+	emit := func() {
+		if count == 0 {
+			return
+		}
+		run = append(run, first)
+		if count > 2 {
+			if lo.X <= hi.X {
+				run = append(run, lo, hi)
+			} else {
+				run = append(run, hi, lo)
+			}
+		}
+		if count > 1 {
+			run = append(run, last)
+		}
+		count = 0
+	}
+	for i := from; i < to; i++ {
+		pt := s.At(i)
+		if math.IsNaN(pt.Y) {
+			emit()
+			if len(run) > 0 {
+				runs = append(runs, run)
+				run = nil
+			}
+			continue
+		}
+		q := p.screen(pt)
+		c := int(math.Floor(float64(q.X)))
+		if count > 0 && c == col {
+			// lo and hi are the lowest and highest values, which are
+			// drawn the other way round on screen.
+			if q.Y > lo.Y {
+				lo = q
+			}
+			if q.Y < hi.Y {
+				hi = q
+			}
+			last = q
+			count++
+			continue
+		}
+		emit()
+		col, count = c, 1
+		first, lo, hi, last = q, q, q, q
+	}
+	emit()
+	if len(run) > 0 {
+		runs = append(runs, run)
+	}
+	return runs
+}
+
+// nearest returns the index of the point of s whose x is nearest to x, or
+// -1 if s is empty. The points must be in increasing x.
+func nearest(s *Series, x float64) int {
+	n := s.Len()
+	if n == 0 {
+		return -1
+	}
+	i := sort.Search(n, func(i int) bool { return s.At(i).X >= x })
+	switch {
+	case i == n:
+		return n - 1
+	case i > 0 && x-s.At(i-1).X < s.At(i).X-x:
+		return i - 1
+	}
+	return i
+}
+
+// chartSize returns the size a chart takes: the available width, and a
+// default height.
+func chartSize(ctx *core.LayoutContext) core.Size {
+	w, h := chartWidth, chartHeight
+	if ctx.Constraints.HasBoundedWidth() {
+		w = ctx.Constraints.MaxWidth
+	}
+	return ctx.Constraints.Constrain(core.Sz(w, h))
+}
+
+// inner returns the area of b inside the chart padding.
+func inner(b core.Rect) core.Rect {
+	return b.Inset(core.UniformInsets(chartPadding))
+}
@@ -0,0 +1,180 @@
+package charts
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/theme"
+)
+
+// testPlot returns a plot showing x0 to x1 and y0 to y1 in area.
+func testPlot(area core.Rect, x0, x1, y0, y1 float64) *plot {
+	return &plot{area: area, x0: x0, x1: x1, y0: y0, y1: y1}
+}
+
+func TestPlotLayout(t *testing.T) {
+	tests := []struct {
+		name           string
+		p              plot
+		x0, x1, y0, y1 float64
+		categories     int
+		want           [4]float64
+		xTicks         bool
+	}{
+		{"fits the data", plot{}, 0, 10, 3, 97, 0, [4]float64{0, 10, 0, 100}, true},
+		{"one x value", plot{}, 5, 5, 0, 1, 0, [4]float64{4, 6, 0, 1}, true},
+		{"fixed", plot{xLo: 2, xHi: 4, xFixed: true, yLo: -1, yHi: 1, yFixed: true}, 0, 10, 3, 97, 0, [4]float64{2, 4, -1, 1}, true},
+		{"categories", plot{xLo: 2, xHi: 4, xFixed: true}, 0, 0, 0, 10, 3, [4]float64{-0.5, 2.5, 0, 10}, false},
+	}
+	ctx := core.NewContext()
+	th := theme.From(ctx)
+	r := core.R(10, 20, 400, 200)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.p
+			p.layout(ctx, th, r, tt.x0, tt.x1, tt.y0, tt.y1, tt.categories, nil)
+			if got := [4]float64{p.x0, p.x1, p.y0, p.y1}; got != tt.want {
+				t.Errorf("shows %v, want %v", got, tt.want)
+			}
+			if (len(p.xTicks) > 0) != tt.xTicks || len(p.yTicks) < 2 {
+				t.Errorf("ticks %v and %v", p.xTicks, p.yTicks)
+			}
+			if p.area.Y != r.Y || p.area.X <= r.X || p.area.Right() > r.Right() || p.area.Bottom() >= r.Bottom() {
+				t.Errorf("plot area %v in %v, want room for the labels left and below", p.area, r)
+			}
+		})
+	}
+
+	var p plot
+	legend := []legendEntry{{"a", th.Chart[0]}}
+	p.layout(ctx, th, r, 0, 1, 0, 1, 0, legend)
+	if want := r.Y + legendHeight(legend, p.style); p.area.Y != want {
+		t.Errorf("plot area at %v with a legend, want %v", p.area.Y, want)
+	}
+
+	p = plot{yFormat: func(float64) string { return "a very long label" }}
+	p.layout(ctx, th, r, 0, 1, 0, 1, 0, nil)
+	if want := r.X + ctx.MeasureText("a very long label", p.style).Width + chartPadding; p.area.X != want {
+		t.Errorf("plot area at %v with long labels, want %v", p.area.X, want)
+	}
+}
+
+func TestPlotScreen(t *testing.T) {
+	p := testPlot(core.R(10, 20, 100, 50), -1, 1, 0, 10)
+	tests := []struct {
+		pt   Point
+		want core.Point
+	}{
+		{Point{-1, 0}, core.Pt(10, 70)},
+		{Point{1, 10}, core.Pt(110, 20)},
+		{Point{0, 5}, core.Pt(60, 45)},
+	}
+	for _, tt := range tests {
+		if got := p.screen(tt.pt); got != tt.want {
+			t.Errorf("screen(%v) = %v, want %v", tt.pt, got, tt.want)
+		}
+		if x := p.dataX(tt.want.X); !near(x, tt.pt.X) {
+			t.Errorf("dataX(%v) = %v, want %v", tt.want.X, x, tt.pt.X)
+		}
+	}
+
+	baselines := []struct {
+		y0, y1 float64
+		want   float32
+	}{
+		{2, 10, 70},
+		{-10, -2, 20},
+		{-5, 5, 45},
+	}
+	for _, tt := range baselines {
+		p.y0, p.y1 = tt.y0, tt.y1
+		if got := p.baseline(); got != tt.want {
+			t.Errorf("baseline of %v to %v at %v, want %v", tt.y0, tt.y1, got, tt.want)
+		}
+	}
+}
+
+func TestPlotLabels(t *testing.T) {
+	p := plot{xStep: 0.5, yStep: 10}
+	if p.xLabel(1.5) != "1.5" || p.yLabel(20) != "20" || p.xValue(1.25) != "1.25" || p.yValue(1.0/3) != "0.333333" {
+		t.Errorf("labels %q, %q, values %q, %q", p.xLabel(1.5), p.yLabel(20), p.xValue(1.25), p.yValue(1.0/3))
+	}
+	p.xFormat = func(x float64) string { return "x" }
+	p.yFormat = func(y float64) string { return "y" }
+	if p.xLabel(1) != "x" || p.yLabel(1) != "y" || p.xValue(1) != "x" || p.yValue(1) != "y" {
+		t.Error("the formats were not used")
+	}
+}
+
+func TestPolylines(t *testing.T) {
+	// 10000 samples alternating between -1 and 1 across 100 pixels keep
+	// four points a column, among them both extremes.
+	values := make([]float64, 10000)
+	for i := range values {
+		values[i] = float64(i%2*2 - 1)
+	}
+	p := testPlot(core.R(0, 0, 100, 100), 0, 9999, -1, 1)
+	runs := p.polylines(&Series{Values: values})
+	if len(runs) != 1 || len(runs[0]) > 4*101 || len(runs[0]) < 100 {
+		t.Fatalf("%d runs of %d points", len(runs), len(runs[0]))
+	}
+	var top, bottom bool
+	for _, q := range runs[0][:4] {
+		top = top || q.Y == 0
+		bottom = bottom || q.Y == 100
+	}
+	if !top || !bottom {
+		t.Errorf("the first column %v lost an extreme", runs[0][:4])
+	}
+	for i := 1; i < len(runs[0]); i++ {
+		if runs[0][i].X < runs[0][i-1].X {
+			t.Errorf("point %d at x %v goes back from %v", i, runs[0][i].X, runs[0][i-1].X)
+		}
+	}
+
+	// A NaN breaks the line.
+	p = testPlot(core.R(0, 0, 300, 100), 0, 5, 0, 10)
+	runs = p.polylines(&Series{Values: []float64{1, math.NaN(), 2, 3, math.NaN(), math.NaN(), 4}})
+	if len(runs) != 3 || len(runs[0]) != 1 || len(runs[1]) != 2 || len(runs[2]) != 1 {
+		t.Errorf("runs %v, want 1, 2 and 1 points", runs)
+	}
+
+	// Points outside the range are left out but for one on each side,
+	// which the line runs to.
+	points := make([]Point, 101)
+	for i := range points {
+		points[i] = Point{float64(i), 0}
+	}
+	p = testPlot(core.R(0, 0, 1000, 100), 40, 60, -1, 1)
+	runs = p.polylines(&Series{Points: points})
+	if len(runs) != 1 || len(runs[0]) != 23 || runs[0][0] != p.screen(points[39]) {
+		t.Errorf("drew %d points from %v", len(runs[0]), runs[0][0])
+	}
+	if runs := p.polylines(&Series{}); len(runs) != 0 {
+		t.Errorf("an empty series drew %v", runs)
+	}
+}
+
+func TestNearest(t *testing.T) {
+	s := &Series{Points: []Point{{0, 1}, {10, 2}, {20, 3}}}
+	tests := []struct {
+		x    float64
+		want int
+	}{
+		{-5, 0},
+		{4, 0},
+		{5, 1},
+		{10, 1},
+		{16, 2},
+		{100, 2},
+	}
+	for _, tt := range tests {
+		if got := nearest(s, tt.x); got != tt.want {
+			t.Errorf("nearest(%v) = %d, want %d", tt.x, got, tt.want)
+		}
+	}
+	if got := nearest(&Series{}, 1); got != -1 {
+		t.Errorf("nearest in an empty series = %d", got)
+	}
+}
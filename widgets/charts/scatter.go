@@ -0,0 +1,200 @@
+package charts
+
+import (
+	"math"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/state"
+	"github.com/gogpu/ui/theme"
+)
+
+// ScatterChart plots the points of series as dots.
+//
+// Of several points drawn on the same pixel only one dot is drawn.
+// Hovering near a point shows its values.
+type ScatterChart struct {
+	core.WidgetBase
+	plot
+
+	series []Series
+	bind   binding[[]Series]
+	radius float32
+
+	hover   [2]int // series and point under the pointer, or -1
+	tooltip tooltip
+}
+
+// NewScatterChart returns a ScatterChart of series.
+func NewScatterChart(series ...Series) *ScatterChart {
+	return &ScatterChart{series: series, radius: 3, hover: [2]int{-1, -1}}
+}
+
+// Series returns the series shown.
+func (c *ScatterChart) Series() []Series {
+	return c.series
+}
+
+// SetSeries replaces the series shown.
+func (c *ScatterChart) SetSeries(series ...Series) {
+	c.series = series
+	c.hover = [2]int{-1, -1}
+}
+
+// Bind makes the chart show the series held by sig.
+func (c *ScatterChart) Bind(sig *state.Signal[[]Series]) *ScatterChart {
+	c.bind.bind(sig)
+	return c
+}
+
+// Release stops following the bound signal, for when the chart leaves
+// the tree. Laid out again, the chart follows the signal from its value
+// then.
+func (c *ScatterChart) Release() {
+	c.bind.release()
+}
+
+// PointSize sets the radius of the dots. The default is 3.
+func (c *ScatterChart) PointSize(r float32) *ScatterChart {
+	c.radius = r
+	return c
+}
+
+// XRange fixes the x axis to lo to hi.
+func (c *ScatterChart) XRange(lo, hi float64) *ScatterChart {
+	c.xLo, c.xHi, c.xFixed = lo, hi, hi > lo
+	return c
+}
+
+// YRange fixes the y axis to lo to hi.
+func (c *ScatterChart) YRange(lo, hi float64) *ScatterChart {
+	c.yLo, c.yHi, c.yFixed = lo, hi, hi > lo
+	return c
+}
+
+// XFormat sets how x values are written.
+func (c *ScatterChart) XFormat(fn func(x float64) string) *ScatterChart {
+	c.xFormat = fn
+	return c
+}
+
+// YFormat sets how y values are written.
+func (c *ScatterChart) YFormat(fn func(y float64) string) *ScatterChart {
+	c.yFormat = fn
+	return c
+}
+
+// Legend shows or hides the legend of named series. It is shown by
+// default.
+func (c *ScatterChart) Legend(show bool) *ScatterChart {
+	c.noLegend = !show
+	return c
+}
+
+// Layout implements core.Widget.
+func (c *ScatterChart) Layout(ctx *core.LayoutContext) core.Size {
+	c.bind.sync(ctx.Context, c, func(s []Series) {
+		c.series = s
+		if h := c.hover[0]; h >= len(s) || h >= 0 && c.hover[1] >= s[h].Len() {
+			c.hover = [2]int{-1, -1}
+		}
+	})
+	return chartSize(ctx)
+}
+
+// Paint implements core.Widget.
+func (c *ScatterChart) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	r := inner(c.Bounds())
+	var legend []legendEntry
+	if !c.noLegend {
+		legend = seriesLegend(th, c.series)
+	}
+	x0, y0 := math.Inf(1), math.Inf(1)
+	x1, y1 := math.Inf(-1), math.Inf(-1)
+	for i := range c.series {
+		s := &c.series[i]
+		for k := range s.Len() {
+			p := s.At(k)
+			x0, x1 = math.Min(x0, p.X), math.Max(x1, p.X)
+			y0, y1 = math.Min(y0, p.Y), math.Max(y1, p.Y)
+		}
+	}
+	if math.IsInf(x0, 1) {
+		x0, x1, y0, y1 = 0, 1, 0, 1
+	}
+	// A margin keeps the dots at the extremes clear of the edges.
+	dx, dy := (x1-x0)*0.05, (y1-y0)*0.05
+	x0, x1 = extend(x0-dx, x1+dx)
+	y0, y1 = y0-dy, y1+dy
+	c.layout(ctx.Context, th, r, x0, x1, y0, y1, 0, legend)
+	paintLegend(ctx, r, legend, theme.TextStyle(th.Typography.Caption, th.Colors.OnSurface))
+	c.paintAxes(ctx, th, nil)
+
+	cv.Save()
+	cv.Clip(c.area)
+	w, h := int(c.area.Width)+1, int(c.area.Height)+1
+	drawn := make([]bool, w*h)
+	for i := range c.series {
+		s := &c.series[i]
+		dots := core.NewPath()
+		clear(drawn)
+		for k := range s.Len() {
+			q := c.screen(s.At(k))
+			px, py := int(q.X-c.area.X), int(q.Y-c.area.Y)
+			if px < 0 || py < 0 || px >= w || py >= h || drawn[py*w+px] {
+				continue
+			}
+			drawn[py*w+px] = true
+			dots.AddEllipse(core.R(q.X-c.radius, q.Y-c.radius, 2*c.radius, 2*c.radius))
+		}
+		cv.DrawPath(dots, core.PathStyle{Fill: seriesColor(th, i, s.Color).WithAlpha(0.8)})
+	}
+	if i, k := c.hover[0], c.hover[1]; i >= 0 {
+		q := c.screen(c.series[i].At(k))
+		rr := c.radius + 2
+		ring := core.NewPath().AddEllipse(core.R(q.X-rr, q.Y-rr, 2*rr, 2*rr))
+		cv.DrawPath(ring, core.PathStyle{Fill: seriesColor(th, i, c.series[i].Color), Stroke: th.Colors.Surface, StrokeWidth: 2})
+	}
+	cv.Restore()
+}
+
+// HandleEvent implements core.Widget.
+func (c *ScatterChart) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	e, ok := ev.(event.MouseEvent)
+	if !ok || e.Type != event.MouseMove && e.Type != event.MouseLeave {
+		return core.Ignored
+	}
+	hover := [2]int{-1, -1}
+	if e.Type == event.MouseMove && c.area.Contains(e.Position) {
+		best := hoverRadius * hoverRadius
+		for i := range c.series {
+			s := &c.series[i]
+			for k := range s.Len() {
+				d := c.screen(s.At(k)).Sub(e.Position)
+				if dd := d.X*d.X + d.Y*d.Y; dd <= best {
+					best, hover = dd, [2]int{i, k}
+				}
+			}
+		}
+	}
+	if hover != c.hover {
+		c.hover = hover
+		ctx.Repaint(c)
+	}
+	if hover[0] < 0 {
+		c.tooltip.hide(ctx)
+		return core.Ignored
+	}
+	th := theme.From(ctx)
+	s := &c.series[hover[0]]
+	p := s.At(hover[1])
+	var lines []tipLine
+	if s.Name != "" {
+		lines = append(lines, tipLine{seriesColor(th, hover[0], s.Color), s.Name})
+	}
+	lines = append(lines, tipLine{text: "x: " + c.xValue(p.X)}, tipLine{text: "y: " + c.yValue(p.Y)})
+	c.tooltip.show(ctx, e.Position, lines)
+	return core.Handled
+}
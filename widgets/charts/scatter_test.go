@@ -0,0 +1,95 @@
+package charts
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/state"
+	"github.com/gogpu/ui/theme"
+)
+
+func TestScatterChartPaint(t *testing.T) {
+	c := NewScatterChart(Series{Name: "s", Points: []Point{{0, 0}, {1, 1}, {1, 1}, {2, 4}}})
+	ctx, cv := layoutChart(c)
+	if len(cv.paths) != 1 || contours(cv.paths[0].p) != 3 {
+		t.Fatalf("drew %d paths, want one of 3 dots with the repeated point once", len(cv.paths))
+	}
+	// The points stay clear of the edges of the plot.
+	for _, pt := range c.series[0].Points {
+		if q := c.screen(pt); !c.area.Inset(core.UniformInsets(c.radius)).Contains(q) {
+			t.Errorf("point %v drawn at %v, at the edge of %v", pt, q, c.area)
+		}
+	}
+
+	c.PointSize(5).SetSeries(Series{Points: []Point{{1, 1}}})
+	cv = paint(ctx, c)
+	if b := cv.paths[0].p.Bounds(); b.Width != 10 {
+		t.Errorf("a dot %v wide, want 10", b.Width)
+	}
+	if _, cv := layoutChart(NewScatterChart()); len(cv.paths) != 0 {
+		t.Errorf("an empty chart drew %d paths", len(cv.paths))
+	}
+}
+
+func TestScatterChartHover(t *testing.T) {
+	c := NewScatterChart(
+		Series{Name: "s", Points: []Point{{0, 0}, {2, 4}}},
+		Series{Points: []Point{{1, 2}}},
+	).XFormat(func(x float64) string { return "@" + formatValue(x) })
+	ctx, _ := layoutChart(c)
+	th := theme.From(ctx)
+	if r := move(ctx, c, c.screen(Point{2, 4}).Add(core.Pt(3, 3))); r != core.Handled || c.hover != [2]int{0, 1} {
+		t.Fatalf("moving near (2, 4) = %v, hovering %v", r, c.hover)
+	}
+	if got, want := tipTexts(&c.tooltip), []string{"s", "x: @2", "y: 4"}; !equalStrings(got, want) {
+		t.Errorf("tooltip %q, want %q", got, want)
+	}
+	cv := paint(ctx, c)
+	if ring := cv.paths[len(cv.paths)-1]; ring.st.Stroke != th.Colors.Surface || ring.st.Fill != th.Chart[0] {
+		t.Errorf("the hovered point is drawn %v", ring.st)
+	}
+
+	c.YFormat(func(y float64) string { return formatValue(y) + "!" })
+	move(ctx, c, c.screen(Point{1, 2}))
+	if got, want := tipTexts(&c.tooltip), []string{"x: @1", "y: 2!"}; !equalStrings(got, want) {
+		t.Errorf("tooltip of an unnamed series %q, want %q", got, want)
+	}
+
+	far := c.screen(Point{0, 4})
+	if r := move(ctx, c, far); r != core.Ignored || c.hover[0] != -1 || len(ctx.Overlays()) != 0 {
+		t.Errorf("moving %v away from the points = %v, hovering %v", far, r, c.hover)
+	}
+	move(ctx, c, c.screen(Point{1, 2}))
+	c.HandleEvent(ctx, event.MouseEvent{Type: event.MouseLeave})
+	if c.hover[0] != -1 {
+		t.Error("leaving kept the hover")
+	}
+	move(ctx, c, c.screen(Point{1, 2}))
+	c.SetSeries(c.series...)
+	if c.hover[0] != -1 {
+		t.Error("SetSeries kept the hover")
+	}
+}
+
+func TestScatterChartRanges(t *testing.T) {
+	c := NewScatterChart(Series{Points: []Point{{3, 3}}}).XRange(0, 10).YRange(0, 5)
+	layoutChart(c)
+	if c.x0 != 0 || c.x1 != 10 || c.y0 != 0 || c.y1 != 5 {
+		t.Errorf("shows %v to %v by %v to %v", c.x0, c.x1, c.y0, c.y1)
+	}
+
+	sig := state.NewFunc([]Series{{Points: []Point{{0, 0}, {1, 1}}}}, nil)
+	c = NewScatterChart().Bind(sig)
+	ctx, _ := layoutChart(c)
+	move(ctx, c, c.screen(Point{1, 1}))
+	if c.hover != [2]int{0, 1} {
+		t.Fatalf("hovering %v", c.hover)
+	}
+	sig.Set([]Series{{Points: []Point{{0, 0}}}})
+	ctx.RunPosted()
+	ctx.LayoutRoot(c, chartBounds)
+	if c.hover[0] != -1 {
+		t.Errorf("hovering %v, a point that is gone", c.hover)
+	}
+}
@@ -0,0 +1,88 @@
+package charts
+
+import (
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/theme"
+)
+
+const (
+	tipPadding float32 = 6
+	tipOffset  float32 = 18
+)
+
+// tipLine is a line of a chart's tooltip, after a swatch of color unless
+// it is transparent.
+type tipLine struct {
+	color core.Color
+	text  string
+}
+
+// tip is the content of a chart's tooltip.
+type tip struct {
+	core.WidgetBase
+	lines []tipLine
+	style core.TextStyle
+}
+
+func (t *tip) Layout(ctx *core.LayoutContext) core.Size {
+	th := theme.From(ctx.Context)
+	t.style = theme.TextStyle(th.Typography.Caption, th.Colors.Surface)
+	var w float32
+	for _, l := range t.lines {
+		lw := ctx.MeasureText(l.text, t.style).Width
+		if l.color != (core.Color{}) {
+			lw += swatchSize + 4
+		}
+		w = max(w, lw)
+	}
+	h := float32(len(t.lines)) * t.style.LineHeight()
+	return core.Sz(w+2*tipPadding, h+2*tipPadding)
+}
+
+func (t *tip) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	b := t.Bounds()
+	ctx.Canvas.DrawRoundedRect(b, th.Radii.Small, core.Filled(th.Colors.OnSurface.WithAlpha(0.92)))
+	lh := t.style.LineHeight()
+	for i, l := range t.lines {
+		p := core.Pt(b.X+tipPadding, b.Y+tipPadding+float32(i)*lh)
+		if l.color != (core.Color{}) {
+			ctx.Canvas.DrawRoundedRect(core.R(p.X, p.Y+(lh-swatchSize)/2, swatchSize, swatchSize), 2, core.Filled(l.color))
+			p.X += swatchSize + 4
+		}
+		ctx.Canvas.DrawText(l.text, p, t.style)
+	}
+}
+
+// tooltip shows a chart's tooltip below the pointer in a passive overlay,
+// which the window closes when a button or key is pressed.
+type tooltip struct {
+	overlay *core.Overlay
+	tip     tip
+}
+
+func (t *tooltip) show(ctx *core.Context, p core.Point, lines []tipLine) {
+	t.tip.lines = lines
+	place := core.PlaceAnchored(core.R(p.X, p.Y, 1, tipOffset), core.SideBelow)
+	if t.overlay == nil {
+		o := &core.Overlay{Content: &t.tip, Placement: place, Passive: true, Popup: true}
+		o.OnClose = func() {
+			if t.overlay == o {
+				t.overlay = nil
+			}
+		}
+		t.overlay = o
+		ctx.ShowOverlay(o)
+		return
+	}
+	t.overlay.Placement = place
+	// The window lays the tip out again as it places it.
+	ctx.Repaint(&t.tip)
+}
+
+func (t *tooltip) hide(ctx *core.Context) {
+	if o := t.overlay; o != nil {
+		t.overlay = nil
+		ctx.CloseOverlay(o)
+	}
+}
@@ -0,0 +1,196 @@
+package widgets
+
+import (
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/state"
+	"github.com/gogpu/ui/theme"
+)
+
+// CheckState is the state of a Checkbox.
+type CheckState uint8
+
+const (
+	// Unchecked is the cleared state.
+	Unchecked CheckState = iota
+	// Checked is the set state.
+	Checked
+	// Indeterminate shows a dash, typically for a parent whose children
+	// are only partly checked.
+	Indeterminate
+)
+
+// Checkbox is a labeled box that is checked and cleared by clicking it or
+// pressing Space while it is focused.
+//
+// A checkbox can also be indeterminate. Clicking an indeterminate box
+// checks it; with TriState the user can cycle back through the
+// indeterminate state as well.
+type Checkbox struct {
+	core.WidgetBase
+	core.FocusState
+
+	row      toggleRow
+	press    togglePress
+	state    CheckState
+	triState bool
+	onChange func(CheckState)
+
+	sig    *state.Signal[bool]
+	cancel func()
+}
+
+// NewCheckbox returns an unchecked Checkbox with the given label, which
+// may be empty.
+func NewCheckbox(label string) *Checkbox {
+	return &Checkbox{row: toggleRow{label: label}}
+}
+
+// TriState lets clicks cycle from checked to indeterminate as well.
+func (c *Checkbox) TriState(on bool) *Checkbox {
+	c.triState = on
+	return c
+}
+
+// Bind keeps the checked state in sync with sig in both directions.
+// Setting the signal to false leaves an indeterminate box indeterminate.
+func (c *Checkbox) Bind(sig *state.Signal[bool]) *Checkbox {
+	c.Release()
+	c.sig = sig
+	return c
+}
+
+// Release stops following the bound signal, for when the checkbox leaves
+// the tree. Laid out again, the checkbox follows the signal from its value
+// then.
+func (c *Checkbox) Release() {
+	if c.cancel != nil {
+		c.cancel()
+		c.cancel = nil
+	}
+}
+
+// OnChange registers fn to be called when the user changes the state.
+func (c *Checkbox) OnChange(fn func(CheckState)) *Checkbox {
+	c.onChange = fn
+	return c
+}
+
+// Label returns the label.
+func (c *Checkbox) Label() string {
+	return c.row.label
+}
+
+// SetLabel replaces the label.
+func (c *Checkbox) SetLabel(s string) {
+	c.row.label = s
+}
+
+// Checked reports whether the box is checked.
+func (c *Checkbox) Checked() bool {
+	return c.state == Checked
+}
+
+// SetChecked checks or clears the box without calling OnChange.
+func (c *Checkbox) SetChecked(on bool) {
+	if on {
+		c.SetState(Checked)
+	} else {
+		c.SetState(Unchecked)
+	}
+}
+
+// State returns the state.
+func (c *Checkbox) State() CheckState {
+	return c.state
+}
+
+// SetState sets the state without calling OnChange.
+func (c *Checkbox) SetState(s CheckState) {
+	c.state = s
+	if c.sig != nil {
+		c.sig.Set(s == Checked)
+	}
+}
+
+func (c *Checkbox) toggle(ctx *core.Context) {
+	next := Checked
+	switch c.state {
+	case Checked:
+		next = Unchecked
+		if c.triState {
+			next = Indeterminate
+		}
+	case Indeterminate:
+		next = Checked
+		if c.triState {
+			next = Unchecked
+		}
+	}
+	c.SetState(next)
+	ctx.Repaint(c)
+	if c.onChange != nil {
+		c.onChange(next)
+	}
+}
+
+func checkboxMetrics(d theme.Design) (size, radius, stroke float32) {
+	switch d {
+	case theme.DesignFluent:
+		return 20, 4, 1
+	case theme.DesignCupertino:
+		return 18, 4, 1
+	}
+	return 18, 2, 2
+}
+
+// Layout implements core.Widget.
+func (c *Checkbox) Layout(ctx *core.LayoutContext) core.Size {
+	if c.sig != nil {
+		if c.cancel == nil {
+			cx := ctx.Context
+			c.cancel = c.sig.Subscribe(func(bool) { cx.Post(func() { cx.MarkNeedsLayout(c) }) })
+		}
+		if on := c.sig.Get(); on != c.Checked() && (on || c.state == Checked) {
+			c.SetChecked(on)
+		}
+	}
+	size, _, _ := checkboxMetrics(theme.From(ctx.Context).Design)
+	return ctx.Constraints.Constrain(c.row.measure(ctx, core.Sz(size, size)))
+}
+
+// SetBounds implements core.Widget.
+func (c *Checkbox) SetBounds(r core.Rect) {
+	c.WidgetBase.SetBounds(r)
+	c.row.arrange(r)
+}
+
+// Paint implements core.Widget.
+func (c *Checkbox) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	_, radius, stroke := checkboxMetrics(th.Design)
+	b := c.row.control
+	paintToggleState(ctx, b, radius, c.press.hover, c.IsFocused())
+	if c.state == Unchecked {
+		ctx.Canvas.DrawRoundedRect(b.Inset(core.UniformInsets(stroke/2)), radius, core.Stroked(th.Colors.OnSurfaceVariant, stroke))
+	} else {
+		ctx.Canvas.DrawRoundedRect(b, radius, core.Filled(th.Colors.Primary))
+		mark := core.PathStyle{Stroke: th.Colors.OnPrimary, StrokeWidth: 2, LineCap: core.CapRound, LineJoin: core.JoinRound}
+		w, h := b.Width, b.Height
+		if c.state == Checked {
+			check := core.NewPath().
+				MoveTo(core.Pt(b.X+0.24*w, b.Y+0.52*h)).
+				LineTo(core.Pt(b.X+0.42*w, b.Y+0.70*h)).
+				LineTo(core.Pt(b.X+0.76*w, b.Y+0.32*h))
+			ctx.Canvas.DrawPath(check, mark)
+		} else {
+			dash := core.NewPath().MoveTo(core.Pt(b.X+0.26*w, b.Y+h/2)).LineTo(core.Pt(b.X+0.74*w, b.Y+h/2))
+			ctx.Canvas.DrawPath(dash, mark)
+		}
+	}
+	c.row.paintLabel(ctx)
+}
+
+// HandleEvent implements core.Widget.
+func (c *Checkbox) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	return c.press.handle(ctx, c, c.IsFocused(), ev, func() { c.toggle(ctx) })
+}
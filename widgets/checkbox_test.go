@@ -0,0 +1,158 @@
+package widgets
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/state"
+	"github.com/gogpu/ui/theme"
+)
+
+// click presses and releases the left button at p on w.
+func click(ctx *core.Context, w core.Widget, p core.Point) {
+	w.HandleEvent(ctx, leftMouse(event.MouseDown, p))
+	w.HandleEvent(ctx, leftMouse(event.MouseUp, p))
+}
+
+func TestCheckboxToggle(t *testing.T) {
+	tests := []struct {
+		name     string
+		triState bool
+		from     CheckState
+		want     []CheckState
+	}{
+		{"two states", false, Unchecked, []CheckState{Checked, Unchecked, Checked}},
+		{"indeterminate checks", false, Indeterminate, []CheckState{Checked, Unchecked}},
+		{"tri-state", true, Unchecked, []CheckState{Checked, Indeterminate, Unchecked}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCheckbox("Wrap lines").TriState(tt.triState)
+			c.SetState(tt.from)
+			var got []CheckState
+			c.OnChange(func(s CheckState) { got = append(got, s) })
+			ctx := layoutAt(c, core.R(0, 0, 200, 40))
+			for range tt.want {
+				click(ctx, c, c.row.control.Center())
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("changes %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("changes %v, want %v", got, tt.want)
+				}
+			}
+			if c.State() != tt.want[len(tt.want)-1] {
+				t.Errorf("State() = %v", c.State())
+			}
+		})
+	}
+}
+
+func TestTogglePress(t *testing.T) {
+	tests := []struct {
+		name    string
+		events  []core.Event
+		toggled bool
+	}{
+		{"click on the label", []core.Event{
+			leftMouse(event.MouseDown, core.Pt(100, 20)), leftMouse(event.MouseUp, core.Pt(100, 20)),
+		}, true},
+		{"released outside", []core.Event{
+			leftMouse(event.MouseDown, core.Pt(10, 20)), leftMouse(event.MouseUp, core.Pt(300, 20)),
+		}, false},
+		{"right button", []core.Event{
+			event.MouseEvent{Type: event.MouseDown, Position: core.Pt(10, 20), Button: event.ButtonRight},
+			event.MouseEvent{Type: event.MouseUp, Position: core.Pt(10, 20), Button: event.ButtonRight},
+		}, false},
+		{"space", []core.Event{press(event.KeySpace, 0)}, true},
+		{"shift+space", []core.Event{press(event.KeySpace, event.ModShift)}, false},
+		{"enter", []core.Event{press(event.KeyEnter, 0)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCheckbox("Wrap lines")
+			ctx := layoutAt(c, core.R(0, 0, 200, 40))
+			ctx.RequestFocus(c)
+			for _, ev := range tt.events {
+				c.HandleEvent(ctx, ev)
+			}
+			if c.Checked() != tt.toggled {
+				t.Errorf("Checked() = %v, want %v", c.Checked(), tt.toggled)
+			}
+			if ctx.PointerCapture() != nil || c.press.pressed {
+				t.Error("the press did not end")
+			}
+		})
+	}
+
+	c := NewCheckbox("")
+	ctx := layoutAt(c, core.R(0, 0, 200, 40))
+	if c.HandleEvent(ctx, press(event.KeySpace, 0)); c.Checked() {
+		t.Error("Space toggled a checkbox without focus")
+	}
+	c.HandleEvent(ctx, event.MouseEvent{Type: event.MouseEnter})
+	if !c.press.hover {
+		t.Error("entering did not highlight the box")
+	}
+}
+
+func TestCheckboxBind(t *testing.T) {
+	sig := state.New(true)
+	c := NewCheckbox("Bold").Bind(sig)
+	ctx := layoutAt(c, core.R(0, 0, 200, 40))
+	if !c.Checked() {
+		t.Fatal("the box does not show the signal")
+	}
+	ctx.RequestFocus(c)
+	c.HandleEvent(ctx, press(event.KeySpace, 0))
+	if sig.Get() {
+		t.Error("clearing the box did not clear the signal")
+	}
+
+	// Clearing the signal leaves an indeterminate box as it is.
+	c.SetState(Indeterminate)
+	sig.Set(false)
+	ctx.RunPosted()
+	ctx.LayoutRoot(c, core.R(0, 0, 200, 40))
+	if c.State() != Indeterminate {
+		t.Errorf("State() = %v, want Indeterminate", c.State())
+	}
+	sig.Set(true)
+	ctx.RunPosted()
+	ctx.LayoutRoot(c, core.R(0, 0, 200, 40))
+	if !c.Checked() {
+		t.Errorf("State() = %v after setting the signal", c.State())
+	}
+}
+
+func TestToggleRow(t *testing.T) {
+	tests := []struct {
+		design theme.Design
+		box    float32
+	}{
+		{theme.DesignMaterial, 18},
+		{theme.DesignFluent, 20},
+		{theme.DesignCupertino, 18},
+	}
+	for _, tt := range tests {
+		th := *theme.Light()
+		th.Design = tt.design
+		ctx := core.NewContext()
+		theme.Set(ctx, &th)
+		c := NewCheckbox("Label")
+		ctx.LayoutRoot(c, core.R(0, 0, 200, 40))
+		r := c.row
+		if r.control.Width != tt.box || r.control.Center().Y != 20 {
+			t.Errorf("design %d: box at %v", tt.design, r.control)
+		}
+		if r.text.X != r.control.Right()+toggleGap {
+			t.Errorf("design %d: label at %v", tt.design, r.text)
+		}
+	}
+	if theme.Light().Design != theme.DesignMaterial {
+		t.Error("the default design is not Material")
+	}
+}
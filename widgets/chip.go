@@ -0,0 +1,303 @@
+package widgets
+
+import (
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/state"
+	"github.com/gogpu/ui/theme"
+)
+
+const (
+	chipButtonHeight  float32 = 32
+	chipButtonPadding float32 = 12
+	chipIconSize      float32 = 18
+	chipIconGap       float32 = 8
+)
+
+// Chip is a compact labeled control for filters, tags and quick actions.
+//
+// A plain chip calls OnClick when clicked. A selectable chip is toggled
+// instead and shows a checkmark while selected. Setting OnDelete adds a
+// remove button at the trailing edge, which Backspace and Delete also
+// press while the chip is focused. Space and Enter click a focused chip.
+type Chip struct {
+	core.WidgetBase
+	core.FocusState
+
+	label      string
+	icon       core.Widget
+	selectable bool
+	selected   bool
+	onClick    func()
+	onChange   func(bool)
+	onDelete   func()
+
+	sig    *state.Signal[bool]
+	cancel func()
+
+	hover       bool
+	hoverRemove bool
+	pressed     bool
+	pressRemove bool
+	iconSize    core.Size
+}
+
+// NewChip returns a plain chip with the given label.
+func NewChip(label string) *Chip {
+	return &Chip{label: label}
+}
+
+// Label returns the label.
+func (c *Chip) Label() string {
+	return c.label
+}
+
+// SetLabel replaces the label.
+func (c *Chip) SetLabel(s string) {
+	c.label = s
+}
+
+// Icon sets a widget shown before the label, such as an Svg. A selected
+// chip shows its checkmark in its place.
+func (c *Chip) Icon(w core.Widget) *Chip {
+	c.icon = w
+	if w != nil {
+		c.SetChildren(w)
+	} else {
+		c.SetChildren()
+	}
+	return c
+}
+
+// Selectable makes clicks toggle the chip's selection.
+func (c *Chip) Selectable(on bool) *Chip {
+	c.selectable = on
+	return c
+}
+
+// Selected reports whether the chip is selected.
+func (c *Chip) Selected() bool {
+	return c.selected
+}
+
+// SetSelected selects or clears the chip without calling OnChange.
+func (c *Chip) SetSelected(on bool) {
+	c.selected = on
+	if c.sig != nil {
+		c.sig.Set(on)
+	}
+}
+
+// Bind keeps the selection in sync with sig in both directions.
+func (c *Chip) Bind(sig *state.Signal[bool]) *Chip {
+	c.Release()
+	c.sig = sig
+	return c
+}
+
+// Release stops following the bound signal, for when the chip leaves
+// the tree. Laid out again, the chip follows the signal from its value
+// then.
+func (c *Chip) Release() {
+	if c.cancel != nil {
+		c.cancel()
+		c.cancel = nil
+	}
+}
+
+// OnClick registers fn to be called when the chip is clicked.
+func (c *Chip) OnClick(fn func()) *Chip {
+	c.onClick = fn
+	return c
+}
+
+// OnChange registers fn to be called when the user toggles a selectable
+// chip.
+func (c *Chip) OnChange(fn func(bool)) *Chip {
+	c.onChange = fn
+	return c
+}
+
+// OnDelete shows a remove button calling fn, which typically removes the
+// chip from its parent.
+func (c *Chip) OnDelete(fn func()) *Chip {
+	c.onDelete = fn
+	return c
+}
+
+func (c *Chip) activate(ctx *core.Context) {
+	if c.selectable {
+		c.SetSelected(!c.selected)
+		if c.onChange != nil {
+			c.onChange(c.selected)
+		}
+	}
+	if c.onClick != nil {
+		c.onClick()
+	}
+	ctx.MarkNeedsLayout(c)
+}
+
+func (c *Chip) remove(ctx *core.Context) {
+	if c.onDelete != nil {
+		c.onDelete()
+	}
+	ctx.Invalidate()
+}
+
+// leading reports whether the chip shows an icon or checkmark.
+func (c *Chip) leading() bool {
+	return (c.selectable && c.selected) || c.icon != nil
+}
+
+func (c *Chip) removeRect() core.Rect {
+	b := c.Bounds()
+	return core.R(b.Right()-chipRemove-chipIconGap, b.Y, chipRemove+chipIconGap, b.Height)
+}
+
+// Layout implements core.Widget.
+func (c *Chip) Layout(ctx *core.LayoutContext) core.Size {
+	if c.sig != nil {
+		if c.cancel == nil {
+			cx := ctx.Context
+			c.cancel = c.sig.Subscribe(func(bool) { cx.Post(func() { cx.MarkNeedsLayout(c) }) })
+		}
+		c.selected = c.sig.Get()
+	}
+	w := ctx.MeasureText(c.label, theme.From(ctx.Context).Typography.Label).Width + 2*chipButtonPadding
+	if c.icon != nil {
+		c.iconSize = ctx.Measure(c.icon, core.Loose(core.Sz(chipIconSize, chipIconSize)))
+	}
+	if c.leading() {
+		w += chipIconSize + chipIconGap - chipButtonPadding/2
+	}
+	if c.onDelete != nil {
+		w += chipRemove + chipIconGap - chipButtonPadding
+	}
+	return ctx.Constraints.Constrain(core.Sz(w, chipButtonHeight))
+}
+
+// SetBounds implements core.Widget.
+func (c *Chip) SetBounds(r core.Rect) {
+	c.WidgetBase.SetBounds(r)
+	if c.icon != nil {
+		x := r.X + chipButtonPadding/2 + (chipIconSize-c.iconSize.Width)/2
+		c.icon.SetBounds(core.R(x, r.Y+(r.Height-c.iconSize.Height)/2, c.iconSize.Width, c.iconSize.Height))
+	}
+}
+
+// Paint implements core.Widget.
+func (c *Chip) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	b := c.Bounds()
+	radius := b.Height / 2
+	if th.Design == theme.DesignMaterial {
+		radius = 8
+	}
+	style := core.RectStyle{Stroke: th.Colors.Outline, StrokeWidth: 1}
+	if c.selected {
+		style = core.Filled(th.Colors.PrimaryContainer)
+	}
+	cv.DrawRoundedRect(b, radius, style)
+	var a float32
+	switch {
+	case c.pressed && c.hover && !c.pressRemove:
+		a = 0.12
+	case c.hover && !c.hoverRemove:
+		a = 0.08
+	}
+	if a > 0 {
+		cv.DrawRoundedRect(b, radius, core.Filled(th.Colors.OnSurface.WithAlpha(a)))
+	}
+	if c.IsFocused() {
+		ring := b.Inset(core.UniformInsets(-toggleInset))
+		cv.DrawRoundedRect(ring, radius+toggleInset, core.Stroked(th.Colors.Primary, toggleRing))
+	}
+
+	x := b.X + chipButtonPadding
+	if c.leading() {
+		x = b.X + chipButtonPadding/2
+		if c.selectable && c.selected {
+			cx, cy := x+chipIconSize/2, b.Y+b.Height/2
+			mark := core.NewPath().MoveTo(core.Pt(cx-5, cy)).LineTo(core.Pt(cx-1.5, cy+3.5)).LineTo(core.Pt(cx+5, cy-4))
+			cv.DrawPath(mark, core.PathStyle{Stroke: th.Colors.OnSurface, StrokeWidth: 2, LineCap: core.CapRound, LineJoin: core.JoinRound})
+		} else {
+			c.icon.Paint(ctx)
+		}
+		x += chipIconSize + chipIconGap
+	}
+	right := b.Right() - chipButtonPadding
+	if c.onDelete != nil {
+		rr := c.removeRect()
+		right = rr.X
+		if c.hoverRemove {
+			d := rr.Height - 8
+			cv.DrawRoundedRect(core.R(rr.X+(rr.Width-d)/2-chipIconGap/2, rr.Y+4, d, d), d/2, core.Filled(th.Colors.OnSurface.WithAlpha(0.08)))
+		}
+		paintCross(ctx, rr.Inset(core.Insets{Right: chipIconGap}), th.Colors.OnSurfaceVariant)
+	}
+	ts := theme.TextStyle(th.Typography.Label, th.Colors.OnSurface)
+	cv.Save()
+	cv.Clip(core.R(x, b.Y, max(0, right-x), b.Height))
+	cv.DrawText(c.label, core.Pt(x, b.Y+(b.Height-ts.LineHeight())/2), ts)
+	cv.Restore()
+}
+
+// HandleEvent implements core.Widget.
+func (c *Chip) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	switch e := ev.(type) {
+	case event.MouseEvent:
+		switch e.Type {
+		case event.MouseEnter, event.MouseMove:
+			hr := c.onDelete != nil && c.removeRect().Contains(e.Position)
+			if !c.hover || hr != c.hoverRemove {
+				c.hover, c.hoverRemove = true, hr
+				ctx.Repaint(c)
+			}
+		case event.MouseLeave:
+			c.hover, c.hoverRemove = false, false
+			ctx.Repaint(c)
+		case event.MouseDown:
+			if e.Button != event.ButtonLeft {
+				return core.Ignored
+			}
+			c.pressed = true
+			c.pressRemove = c.onDelete != nil && c.removeRect().Contains(e.Position)
+			ctx.RequestFocus(c)
+			ctx.CapturePointer(c)
+			ctx.Repaint(c)
+			return core.Handled
+		case event.MouseUp:
+			if !c.pressed || e.Button != event.ButtonLeft {
+				return core.Ignored
+			}
+			c.pressed = false
+			ctx.ReleasePointer()
+			ctx.Repaint(c)
+			switch {
+			case c.pressRemove:
+				if c.removeRect().Contains(e.Position) {
+					c.remove(ctx)
+				}
+			case c.Bounds().Contains(e.Position):
+				c.activate(ctx)
+			}
+			return core.Handled
+		}
+	case event.KeyEvent:
+		if !c.IsFocused() || e.Type != event.KeyPress || e.Modifiers != 0 {
+			return core.Ignored
+		}
+		switch {
+		case e.Key == event.KeySpace || e.Key == event.KeyEnter:
+			c.activate(ctx)
+		case (e.Key == event.KeyBackspace || e.Key == event.KeyDelete) && c.onDelete != nil:
+			c.remove(ctx)
+		default:
+			return core.Ignored
+		}
+		return core.Handled
+	}
+	return core.Ignored
+}
@@ -0,0 +1,174 @@
+package widgets
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/state"
+	"github.com/gogpu/ui/theme"
+)
+
+func TestChipActivate(t *testing.T) {
+	tests := []struct {
+		name       string
+		selectable bool
+		input      func(ctx *core.Context, c *Chip)
+		clicks     int
+		selected   bool
+		changes    int
+		deletes    int
+	}{
+		{"click", false, func(ctx *core.Context, c *Chip) { click(ctx, c, c.Bounds().Center()) }, 1, false, 0, 0},
+		{"select", true, func(ctx *core.Context, c *Chip) { click(ctx, c, c.Bounds().Center()) }, 1, true, 1, 0},
+		{"select twice", true, func(ctx *core.Context, c *Chip) {
+			click(ctx, c, c.Bounds().Center())
+			click(ctx, c, c.Bounds().Center())
+		}, 2, false, 2, 0},
+		{"Space", true, func(ctx *core.Context, c *Chip) {
+			ctx.RequestFocus(c)
+			c.HandleEvent(ctx, press(event.KeySpace, 0))
+		}, 1, true, 1, 0},
+		{"Enter", false, func(ctx *core.Context, c *Chip) {
+			ctx.RequestFocus(c)
+			c.HandleEvent(ctx, press(event.KeyEnter, 0))
+		}, 1, false, 0, 0},
+		{"unfocused", false, func(ctx *core.Context, c *Chip) { c.HandleEvent(ctx, press(event.KeyEnter, 0)) }, 0, false, 0, 0},
+		{"Backspace", false, func(ctx *core.Context, c *Chip) {
+			ctx.RequestFocus(c)
+			c.HandleEvent(ctx, press(event.KeyBackspace, 0))
+			c.HandleEvent(ctx, press(event.KeyDelete, 0))
+		}, 0, false, 0, 2},
+		{"remove button", true, func(ctx *core.Context, c *Chip) { click(ctx, c, c.removeRect().Center()) }, 0, false, 0, 1},
+		{"released off", false, func(ctx *core.Context, c *Chip) {
+			c.HandleEvent(ctx, leftMouse(event.MouseDown, c.Bounds().Center()))
+			c.HandleEvent(ctx, leftMouse(event.MouseUp, core.Pt(-10, -10)))
+		}, 0, false, 0, 0},
+		{"remove released on the label", false, func(ctx *core.Context, c *Chip) {
+			c.HandleEvent(ctx, leftMouse(event.MouseDown, c.removeRect().Center()))
+			c.HandleEvent(ctx, leftMouse(event.MouseUp, core.Pt(c.Bounds().X+5, 10)))
+		}, 0, false, 0, 0},
+	}
+	for _, tt := range tests {
+		var clicks, changes, deletes int
+		c := NewChip("Tag").Selectable(tt.selectable).
+			OnClick(func() { clicks++ }).
+			OnChange(func(bool) { changes++ }).
+			OnDelete(func() { deletes++ })
+		ctx := layoutAt(c, core.R(0, 0, 120, chipButtonHeight))
+		tt.input(ctx, c)
+		if clicks != tt.clicks || c.Selected() != tt.selected || changes != tt.changes || deletes != tt.deletes {
+			t.Errorf("%s: %d clicks, selected %v, %d changes, %d deletes; want %d, %v, %d, %d",
+				tt.name, clicks, c.Selected(), changes, deletes, tt.clicks, tt.selected, tt.changes, tt.deletes)
+		}
+		if ctx.PointerCapture() != nil {
+			t.Errorf("%s: the pointer is still captured", tt.name)
+		}
+	}
+
+	c := NewChip("Tag")
+	ctx := layoutAt(c, core.R(0, 0, 120, chipButtonHeight))
+	ctx.RequestFocus(c)
+	if r := c.HandleEvent(ctx, press(event.KeyBackspace, 0)); r != core.Ignored {
+		t.Error("Backspace was handled by a chip that cannot be removed")
+	}
+	if r := c.HandleEvent(ctx, event.MouseEvent{Type: event.MouseDown, Button: event.ButtonRight}); r != core.Ignored {
+		t.Error("a right press was handled")
+	}
+}
+
+func TestChipLayout(t *testing.T) {
+	lc := &core.LayoutContext{Context: core.NewContext()}
+	text := lc.MeasureText("Tag", theme.From(lc.Context).Typography.Label).Width
+	plain := text + 2*chipButtonPadding
+	leading := chipIconSize + chipIconGap - chipButtonPadding/2
+	remove := chipRemove + chipIconGap - chipButtonPadding
+	tests := []struct {
+		name  string
+		chip  *Chip
+		width float32
+	}{
+		{"plain", NewChip("Tag"), plain},
+		{"selectable", NewChip("Tag").Selectable(true), plain},
+		{"selected", func() *Chip { c := NewChip("Tag").Selectable(true); c.SetSelected(true); return c }(), plain + leading},
+		{"icon", NewChip("Tag").Icon(icon()), plain + leading},
+		{"removable", NewChip("Tag").OnDelete(func() {}), plain + remove},
+	}
+	for _, tt := range tests {
+		if got := lc.Measure(tt.chip, core.Unbounded()); got != core.Sz(tt.width, chipButtonHeight) {
+			t.Errorf("%s: size %v, want %v wide", tt.name, got, tt.width)
+		}
+	}
+
+	c := NewChip("Tag").Icon(&fixed{height: 10})
+	layoutAt(c, core.R(0, 0, 100, chipButtonHeight))
+	if got, want := c.icon.Bounds(), core.R(chipButtonPadding/2, (chipButtonHeight-10)/2, chipIconSize, 10); got != want {
+		t.Errorf("the icon at %v, want %v", got, want)
+	}
+	c.Icon(nil)
+	if len(c.Children()) != 0 {
+		t.Error("removing the icon kept it as a child")
+	}
+	c.SetLabel("Other")
+	if c.Label() != "Other" {
+		t.Errorf("label %q", c.Label())
+	}
+}
+
+func TestChipBind(t *testing.T) {
+	sig := state.New(true)
+	c := NewChip("Tag").Selectable(true).Bind(sig)
+	ctx := layoutAt(c, core.R(0, 0, 120, chipButtonHeight))
+	if !c.Selected() {
+		t.Fatal("the chip does not show the signal's value")
+	}
+	click(ctx, c, c.Bounds().Center())
+	if sig.Get() {
+		t.Error("toggling did not update the signal")
+	}
+	sig.Set(true)
+	runPosted(t, ctx)
+	ctx.LayoutRoot(c, core.R(0, 0, 120, chipButtonHeight))
+	if !c.Selected() {
+		t.Error("the chip did not follow the signal")
+	}
+	c.Bind(nil)
+	sig.Set(false)
+	if ctx.HasPosted() {
+		t.Error("an unbound signal still notifies the chip")
+	}
+}
+
+func TestChipPaint(t *testing.T) {
+	c := NewChip("Tag").Selectable(true).OnDelete(func() {})
+	ctx := layoutAt(c, core.R(0, 0, 120, chipButtonHeight))
+	paint := func() (*textCanvas, int) {
+		cv := &textCanvas{}
+		c.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+		return cv, cv.Len()
+	}
+	cv, plain := paint()
+	if !equalStrings(cv.texts, []string{"Tag"}) {
+		t.Errorf("drew %q", cv.texts)
+	}
+	c.SetSelected(true)
+	ctx.LayoutRoot(c, core.R(0, 0, 150, chipButtonHeight))
+	if _, n := paint(); n != plain+1 {
+		t.Errorf("selected drew %d operations, want %d with the checkmark", n, plain+1)
+	}
+	c.HandleEvent(ctx, event.MouseEvent{Type: event.MouseMove, Position: c.removeRect().Center()})
+	if !c.hover || !c.hoverRemove {
+		t.Error("hovering the remove button did not highlight it")
+	}
+	if _, n := paint(); n != plain+2 {
+		t.Errorf("hovering the remove button drew %d operations, want %d", n, plain+2)
+	}
+	c.HandleEvent(ctx, event.MouseEvent{Type: event.MouseMove, Position: core.Pt(5, 5)})
+	if !c.hover || c.hoverRemove {
+		t.Error("hovering the label kept the remove button highlighted")
+	}
+	c.HandleEvent(ctx, event.MouseEvent{Type: event.MouseLeave})
+	if c.hover {
+		t.Error("the hover stayed after the pointer left")
+	}
+}
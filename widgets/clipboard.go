@@ -0,0 +1,53 @@
+package widgets
+
+import (
+	"sync"
+
+	"github.com/gogpu/ui/core"
+)
+
+// Clipboard is implemented by platform integrations that give widgets
+// access to the system clipboard. ReadText may answer later, and done may
+// be called from any goroutine, with ok false if the clipboard holds no
+// text.
+type Clipboard interface {
+	WriteText(text string)
+	ReadText(done func(text string, ok bool))
+}
+
+type clipboardKey struct{}
+
+// SetClipboard installs the clipboard used by every widget in the window.
+// Without one, copied text is only available within the window.
+func SetClipboard(ctx *core.Context, c Clipboard) {
+	ctx.SetValue(clipboardKey{}, c)
+}
+
+func clipboardFrom(ctx *core.Context) Clipboard {
+	if c, ok := ctx.Value(clipboardKey{}).(Clipboard); ok {
+		return c
+	}
+	c := &localClipboard{}
+	ctx.SetValue(clipboardKey{}, c)
+	return c
+}
+
+// localClipboard is the clipboard of a window without a platform one.
+type localClipboard struct {
+	mu   sync.Mutex
+	text string
+	set  bool
+}
+
+func (c *localClipboard) WriteText(text string) {
+	c.mu.Lock()
+	c.text, c.set = text, true
+	c.mu.Unlock()
+}
+
+func (c *localClipboard) ReadText(done func(text string, ok bool)) {
+	c.mu.Lock()
+	text, ok := c.text, c.set
+	c.mu.Unlock()
+	done(text, ok)
+}
@@ -0,0 +1,59 @@
+package widgets
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+)
+
+// fakeClipboard is a Clipboard that answers reads later, when answer is
+// called, as platform clipboards may.
+type fakeClipboard struct {
+	text    string
+	writes  int
+	pending []func(string, bool)
+}
+
+func (c *fakeClipboard) WriteText(text string) {
+	c.text = text
+	c.writes++
+}
+
+func (c *fakeClipboard) ReadText(done func(text string, ok bool)) {
+	c.pending = append(c.pending, done)
+}
+
+func (c *fakeClipboard) answer() {
+	for _, done := range c.pending {
+		done(c.text, c.text != "")
+	}
+	c.pending = nil
+}
+
+func TestClipboard(t *testing.T) {
+	ctx := core.NewContext()
+	local := clipboardFrom(ctx)
+	local.ReadText(func(text string, ok bool) {
+		if ok {
+			t.Errorf("an empty clipboard read %q", text)
+		}
+	})
+	local.WriteText("")
+	read := false
+	clipboardFrom(ctx).ReadText(func(text string, ok bool) {
+		read = ok && text == ""
+	})
+	if !read {
+		t.Error("the empty text written was not read back")
+	}
+	if clipboardFrom(ctx) != local {
+		t.Error("each call made a new window clipboard")
+	}
+
+	c := &fakeClipboard{}
+	SetClipboard(ctx, c)
+	clipboardFrom(ctx).WriteText("copied")
+	if c.text != "copied" || clipboardFrom(ctx) != Clipboard(c) {
+		t.Errorf("the installed clipboard holds %q", c.text)
+	}
+}
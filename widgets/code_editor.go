@@ -0,0 +1,620 @@
+package widgets
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/internal/scroll"
+	"github.com/gogpu/ui/internal/textedit"
+	"github.com/gogpu/ui/theme"
+)
+
+// CodeEditor metrics.
+const (
+	codeEditorWidth  float32 = 600
+	codeEditorHeight float32 = 400
+	codeMatchLimit           = 2000 // lines searched for a matching bracket
+)
+
+// CodeEditor edits source code in a monospaced font, with line numbers,
+// syntax highlighting from a pluggable Tokenizer, several carets at once
+// and highlighting of the bracket matching the one at the caret.
+//
+// Only the lines in view are tokenized and drawn, so files of any length
+// stay responsive; lines do not wrap and scroll horizontally instead.
+// Alt+click adds or removes a caret, Ctrl+Alt+Up and Down add one on the
+// line above or below, Ctrl+D selects the next occurrence of the selection
+// and Escape returns to a single caret. Tab and Shift+Tab indent and
+// outdent the selected lines.
+type CodeEditor struct {
+	core.WidgetBase
+	core.FocusState
+
+	buf       textedit.Buffer
+	tokenizer Tokenizer
+	tabWidth  int
+	hardTabs  bool
+	readOnly  bool
+	noGutter  bool
+	onChange  func()
+
+	// states holds the tokenizer state at the end of each line; the
+	// entries before valid are up to date.
+	states []int
+	valid  int
+
+	style            core.TextStyle
+	charW, lineH     float32
+	gutter, text     core.Rect
+	scrollX, scrollY float32
+	vbar, hbar       scroll.Bar
+	longest          int // visual columns of the longest line
+	measured         bool
+	reveal           bool
+	dragging         bool
+}
+
+// NewCodeEditor returns an empty CodeEditor without highlighting.
+func NewCodeEditor() *CodeEditor {
+	e := &CodeEditor{tabWidth: 4, hbar: scroll.Bar{Horizontal: true}}
+	e.buf.SetText("")
+	return e
+}
+
+// Tokenizer sets the tokenizer used for highlighting, such as GoSyntax. A
+// nil tokenizer draws all code plain.
+func (e *CodeEditor) Tokenizer(t Tokenizer) *CodeEditor {
+	e.tokenizer = t
+	e.valid = 0
+	return e
+}
+
+// TabWidth sets the number of columns between tab stops.
+func (e *CodeEditor) TabWidth(n int) *CodeEditor {
+	e.tabWidth = max(1, n)
+	e.measured = false
+	return e
+}
+
+// HardTabs makes Tab insert a tab character instead of spaces up to the
+// next tab stop.
+func (e *CodeEditor) HardTabs(on bool) *CodeEditor {
+	e.hardTabs = on
+	return e
+}
+
+// ReadOnly prevents editing while still allowing selection.
+func (e *CodeEditor) ReadOnly(on bool) *CodeEditor {
+	e.readOnly = on
+	return e
+}
+
+// LineNumbers shows or hides the line number gutter. It is shown by
+// default.
+func (e *CodeEditor) LineNumbers(on bool) *CodeEditor {
+	e.noGutter = !on
+	return e
+}
+
+// OnChange registers fn to be called after every edit.
+func (e *CodeEditor) OnChange(fn func()) *CodeEditor {
+	e.onChange = fn
+	return e
+}
+
+// Text returns the code.
+func (e *CodeEditor) Text() string {
+	return e.buf.Text()
+}
+
+// SetText replaces the code, without calling OnChange, and puts a single
+// caret at the start.
+func (e *CodeEditor) SetText(s string) {
+	e.buf.SetText(s)
+	e.valid = 0
+	e.measured = false
+	e.scrollX, e.scrollY = 0, 0
+}
+
+// LineCount returns the number of lines.
+func (e *CodeEditor) LineCount() int {
+	return e.buf.LineCount()
+}
+
+// Caret returns the line and column of the primary caret, counted from 0.
+func (e *CodeEditor) Caret() (line, col int) {
+	c := e.buf.Primary().Caret
+	return c.Line, c.Col
+}
+
+// SetCaret moves to a single caret at line and col and scrolls it into
+// view.
+func (e *CodeEditor) SetCaret(line, col int) {
+	p := textedit.Pos{Line: line, Col: col}
+	e.buf.SetCursor(p, p)
+	e.reveal = true
+}
+
+// edited records a change starting at line first.
+func (e *CodeEditor) edited(ctx *core.Context, first int) {
+	e.reveal = true
+	ctx.MarkNeedsLayout(e)
+	if first < 0 {
+		return
+	}
+	e.valid = min(e.valid, first)
+	e.measured = false
+	if e.onChange != nil {
+		e.onChange()
+	}
+}
+
+// state returns the tokenizer state at the start of line i.
+func (e *CodeEditor) state(i int) int {
+	if i == 0 || e.tokenizer == nil {
+		return 0
+	}
+	n := e.buf.LineCount()
+	if len(e.states) != n {
+		e.states = append(e.states[:min(len(e.states), n)], make([]int, max(0, n-len(e.states)))...)
+		e.valid = min(e.valid, n)
+	}
+	for ; e.valid < i; e.valid++ {
+		_, e.states[e.valid] = e.tokenizer.Tokenize(e.buf.Line(e.valid), e.state(e.valid))
+	}
+	return e.states[i-1]
+}
+
+// tokens returns the tokens of line i.
+func (e *CodeEditor) tokens(i int) []Token {
+	if e.tokenizer == nil {
+		return nil
+	}
+	toks, _ := e.tokenizer.Tokenize(e.buf.Line(i), e.state(i))
+	return toks
+}
+
+// visual returns the visual column of every position of line, expanding
+// tabs, and the line with its tabs expanded to spaces.
+func (e *CodeEditor) visual(line []rune) ([]int, string) {
+	cols := make([]int, len(line)+1)
+	var sb strings.Builder
+	v := 0
+	for i, r := range line {
+		cols[i] = v
+		if r == '\t' {
+			n := e.tabWidth - v%e.tabWidth
+			sb.WriteString(strings.Repeat(" ", n))
+			v += n
+		} else {
+			sb.WriteRune(r)
+			v++
+		}
+	}
+	cols[len(line)] = v
+	return cols, sb.String()
+}
+
+// Layout implements core.Widget.
+func (e *CodeEditor) Layout(ctx *core.LayoutContext) core.Size {
+	size := ctx.Constraints.Constrain(core.Sz(codeEditorWidth, codeEditorHeight))
+	th := theme.From(ctx.Context)
+	e.style = theme.TextStyle(th.Typography.Mono, th.Colors.OnSurface)
+	e.charW = ctx.MeasureText("0", e.style).Width
+	e.lineH = e.style.LineHeight()
+	if !e.measured {
+		e.measured = true
+		e.longest = 0
+		for i := range e.buf.LineCount() {
+			e.longest = max(e.longest, e.buf.VisualCol(textedit.Pos{Line: i, Col: len(e.buf.Line(i))}, e.tabWidth))
+		}
+	}
+	if e.reveal {
+		// The text area is only known after SetBounds; estimate it from the
+		// size so the caret is in view in the first frame after a change.
+		e.reveal = false
+		view := core.Sz(size.Width-e.gutterWidth()-scroll.Thickness-fieldPadding, size.Height-scroll.Thickness)
+		c := e.buf.Primary().Caret
+		x := float32(e.buf.VisualCol(c, e.tabWidth)) * e.charW
+		y := float32(c.Line) * e.lineH
+		switch {
+		case y < e.scrollY:
+			e.scrollY = y
+		case y+e.lineH > e.scrollY+view.Height:
+			e.scrollY = y + e.lineH - view.Height
+		}
+		switch {
+		case x < e.scrollX:
+			e.scrollX = max(0, x-4*e.charW)
+		case x+e.charW > e.scrollX+view.Width:
+			e.scrollX = x + 4*e.charW - view.Width
+		}
+	}
+	return size
+}
+
+// gutterWidth returns the width of the line numbers.
+func (e *CodeEditor) gutterWidth() float32 {
+	if e.noGutter {
+		return 0
+	}
+	digits := max(2, len(strconv.Itoa(e.buf.LineCount())))
+	return float32(digits)*e.charW + 2*fieldPadding
+}
+
+// SetBounds implements core.Widget.
+func (e *CodeEditor) SetBounds(r core.Rect) {
+	e.WidgetBase.SetBounds(r)
+	gw := e.gutterWidth()
+	e.gutter = core.R(r.X, r.Y, gw, r.Height)
+	e.text = core.R(r.X+gw+fieldPadding/2, r.Y, max(0, r.Width-gw-fieldPadding/2-scroll.Thickness), max(0, r.Height-scroll.Thickness))
+	e.vbar.Track = core.R(r.Right()-scroll.Thickness, r.Y, scroll.Thickness, e.text.Height)
+	e.vbar.Viewport = e.text.Height
+	e.vbar.Content = float32(e.buf.LineCount()) * e.lineH
+	e.hbar.Track = core.R(e.text.X, r.Bottom()-scroll.Thickness, e.text.Width, scroll.Thickness)
+	e.hbar.Viewport = e.text.Width
+	e.hbar.Content = float32(e.longest+1) * e.charW
+	e.scrollY = core.Clamp(e.scrollY, 0, e.vbar.MaxOffset())
+	e.scrollX = core.Clamp(e.scrollX, 0, e.hbar.MaxOffset())
+}
+
+// visibleLines returns the range of lines in view.
+func (e *CodeEditor) visibleLines() (first, last int) {
+	if e.lineH <= 0 {
+		return 0, -1
+	}
+	first = int(e.scrollY / e.lineH)
+	last = min(e.buf.LineCount()-1, int((e.scrollY+e.text.Height)/e.lineH))
+	return first, last
+}
+
+// posAt returns the position under p in window coordinates.
+func (e *CodeEditor) posAt(p core.Point) textedit.Pos {
+	line := int((p.Y - e.text.Y + e.scrollY) / e.lineH)
+	line = max(0, min(line, e.buf.LineCount()-1))
+	v := (p.X - e.text.X + e.scrollX) / e.charW
+	col := e.buf.ColAt(line, int(v+0.5), e.tabWidth)
+	return textedit.Pos{Line: line, Col: col}
+}
+
+// Paint implements core.Widget.
+func (e *CodeEditor) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	b := e.Bounds()
+	paintField(ctx, b, e.IsFocused(), false)
+	first, last := e.visibleLines()
+	primary := e.buf.Primary()
+	y0 := e.text.Y - e.scrollY
+
+	if !e.noGutter {
+		cv.Save()
+		cv.Clip(e.gutter)
+		cv.DrawRect(e.gutter.Inset(core.UniformInsets(1)), core.Filled(th.Colors.SurfaceVariant.WithAlpha(0.4)))
+		for i := first; i <= last; i++ {
+			n := strconv.Itoa(i + 1)
+			color := th.Colors.OnSurfaceVariant.WithAlpha(0.7)
+			if i == primary.Caret.Line {
+				color = th.Colors.OnSurface
+			}
+			w := ctx.MeasureText(n, e.style).Width
+			cv.DrawText(n, core.Pt(e.gutter.Right()-fieldPadding-w, y0+float32(i)*e.lineH), theme.TextStyle(e.style, color))
+		}
+		cv.Restore()
+	}
+
+	cv.Save()
+	cv.Clip(e.text)
+	x0 := e.text.X - e.scrollX
+	if !primary.HasSelection() {
+		cv.DrawRect(core.R(e.text.X, y0+float32(primary.Caret.Line)*e.lineH, e.text.Width, e.lineH), core.Filled(th.Colors.SurfaceVariant.WithAlpha(0.35)))
+	}
+	cursors := e.buf.Cursors()
+	for i := first; i <= last; i++ {
+		line := e.buf.Line(i)
+		cols, shown := e.visual(line)
+		y := y0 + float32(i)*e.lineH
+
+		// Selections.
+		for _, c := range cursors {
+			from, to := c.Range()
+			if !c.HasSelection() || i < from.Line || i > to.Line {
+				continue
+			}
+			s, t := 0, len(line)
+			if i == from.Line {
+				s = from.Col
+			}
+			x1 := float32(cols[t]) * e.charW
+			if i == to.Line {
+				t = to.Col
+				x1 = float32(cols[t]) * e.charW
+			} else {
+				x1 += e.charW / 2 // The selected line break.
+			}
+			xs := float32(cols[s]) * e.charW
+			cv.DrawRect(core.R(x0+xs, y, x1-xs, e.lineH), core.Filled(th.Colors.Selection))
+		}
+
+		// Text, plain between tokens.
+		vis := []rune(shown)
+		draw := func(s, t int, color core.Color) {
+			if s >= t {
+				return
+			}
+			vs, vt := cols[s], cols[t]
+			cv.DrawText(string(vis[vs:vt]), core.Pt(x0+float32(vs)*e.charW, y), theme.TextStyle(e.style, color))
+		}
+		at := 0
+		for _, tok := range e.tokens(i) {
+			s, t := max(at, min(tok.Start, len(line))), min(tok.End, len(line))
+			draw(at, s, th.Colors.OnSurface)
+			draw(s, t, tokenColor(th, tok.Kind))
+			at = max(at, t)
+		}
+		draw(at, len(line), th.Colors.OnSurface)
+	}
+
+	// The bracket at the caret and its partner.
+	if p, q, ok := e.buf.MatchBracket(primary.Caret, codeMatchLimit, e.skipBracket()); ok && !primary.HasSelection() {
+		for _, m := range []textedit.Pos{p, q} {
+			v := e.buf.VisualCol(m, e.tabWidth)
+			r := core.R(x0+float32(v)*e.charW, y0+float32(m.Line)*e.lineH, e.charW, e.lineH)
+			cv.DrawRoundedRect(r, 2, core.RectStyle{Fill: th.Colors.Primary.WithAlpha(0.12), Stroke: th.Colors.Primary.WithAlpha(0.6), StrokeWidth: 1})
+		}
+	}
+
+	if e.IsFocused() {
+		for _, c := range cursors {
+			if c.Caret.Line < first || c.Caret.Line > last {
+				continue
+			}
+			v := e.buf.VisualCol(c.Caret, e.tabWidth)
+			cv.DrawRect(core.R(x0+float32(v)*e.charW-1, y0+float32(c.Caret.Line)*e.lineH, 2, e.lineH), core.Filled(th.Colors.Primary))
+		}
+	}
+	cv.Restore()
+	e.vbar.Paint(ctx, e.scrollY)
+	e.hbar.Paint(ctx, e.scrollX)
+}
+
+// skipBracket returns a function reporting whether the bracket at a
+// position is inside a string or comment, where it does not pair with
+// brackets in code.
+func (e *CodeEditor) skipBracket() textedit.MatchSkip {
+	cache := map[int][]Token{}
+	return func(p textedit.Pos) bool {
+		toks, ok := cache[p.Line]
+		if !ok {
+			toks = e.tokens(p.Line)
+			cache[p.Line] = toks
+		}
+		for _, t := range toks {
+			if p.Col >= t.Start && p.Col < t.End {
+				return t.Kind == TokenString || t.Kind == TokenComment
+			}
+		}
+		return false
+	}
+}
+
+// HandleEvent implements core.Widget.
+func (e *CodeEditor) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	if off, ok := e.vbar.HandleEvent(ctx, e, ev, e.scrollY); ok {
+		if off != e.scrollY {
+			e.scrollY = off
+			ctx.Repaint(e)
+		}
+		return core.Handled
+	}
+	if off, ok := e.hbar.HandleEvent(ctx, e, ev, e.scrollX); ok {
+		if off != e.scrollX {
+			e.scrollX = off
+			ctx.Repaint(e)
+		}
+		return core.Handled
+	}
+	switch ev := ev.(type) {
+	case event.MouseEvent:
+		return e.handleMouse(ctx, ev)
+	case event.ScrollEvent:
+		e.scrollX = core.Clamp(e.scrollX+ev.Delta.X, 0, e.hbar.MaxOffset())
+		e.scrollY = core.Clamp(e.scrollY+ev.Delta.Y, 0, e.vbar.MaxOffset())
+		ctx.Repaint(e)
+		return core.Handled
+	case event.KeyEvent:
+		if !e.IsFocused() || ev.Type != event.KeyPress {
+			return core.Ignored
+		}
+		return e.handleKey(ctx, ev)
+	case event.TextEvent:
+		if !e.IsFocused() || e.readOnly {
+			return core.Ignored
+		}
+		text := strings.Map(func(r rune) rune {
+			if r < ' ' && r != '\t' {
+				return -1
+			}
+			return r
+		}, ev.Text)
+		if text != "" {
+			e.edited(ctx, e.buf.Insert(text))
+		}
+		return core.Handled
+	}
+	return core.Ignored
+}
+
+func (e *CodeEditor) handleMouse(ctx *core.Context, ev event.MouseEvent) core.EventResult {
+	switch ev.Type {
+	case event.MouseDown:
+		if ev.Button != event.ButtonLeft {
+			return core.Ignored
+		}
+		ctx.RequestFocus(e)
+		p := e.posAt(ev.Position)
+		primary := e.buf.Primary()
+		switch {
+		case ev.Modifiers.Has(event.ModAlt):
+			e.buf.AddCursor(p)
+		case ev.ClickCount == 2:
+			line := e.buf.Line(p.Line)
+			s, t := p.Col, p.Col
+			for s > 0 && isCodeWord(line[s-1]) {
+				s--
+			}
+			for t < len(line) && isCodeWord(line[t]) {
+				t++
+			}
+			e.buf.SetCursor(textedit.Pos{Line: p.Line, Col: s}, textedit.Pos{Line: p.Line, Col: t})
+		case ev.ClickCount == 3:
+			e.buf.SetCursor(textedit.Pos{Line: p.Line}, textedit.Pos{Line: p.Line + 1})
+		case ev.Modifiers.Has(event.ModShift):
+			e.buf.SetCursor(primary.Anchor, p)
+		default:
+			e.buf.SetCursor(p, p)
+		}
+		e.dragging = true
+		ctx.CapturePointer(e)
+		ctx.Repaint(e)
+		return core.Handled
+	case event.MouseMove:
+		if e.dragging {
+			e.buf.SetPrimary(e.buf.Primary().Anchor, e.posAt(ev.Position))
+			e.reveal = true
+			ctx.MarkNeedsLayout(e)
+			return core.Handled
+		}
+	case event.MouseUp:
+		if e.dragging {
+			e.dragging = false
+			ctx.ReleasePointer()
+			return core.Handled
+		}
+	}
+	return core.Ignored
+}
+
+func isCodeWord(r rune) bool {
+	return r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r > 0x7F
+}
+
+// indentUnit returns the text of one level of indentation.
+func (e *CodeEditor) indentUnit() string {
+	if e.hardTabs {
+		return "\t"
+	}
+	return strings.Repeat(" ", e.tabWidth)
+}
+
+func (e *CodeEditor) handleKey(ctx *core.Context, ev event.KeyEvent) core.EventResult {
+	m := ev.Modifiers
+	extend := m.Has(event.ModShift)
+	cmd := m.Has(event.ModCtrl) || m.Has(event.ModSuper)
+	word := m.Has(event.ModCtrl) || m.Has(event.ModAlt)
+	edit := func(first int) core.EventResult {
+		e.edited(ctx, first)
+		return core.Handled
+	}
+	switch ev.Key {
+	case event.KeyLeft, event.KeyRight:
+		forward := ev.Key == event.KeyRight
+		e.buf.Move(func(c textedit.Cursor) textedit.Pos {
+			if c.HasSelection() && !extend && !word {
+				from, to := c.Range()
+				if forward {
+					return to
+				}
+				return from
+			}
+			return e.buf.Step(c.Caret, forward, word)
+		}, extend)
+	case event.KeyUp, event.KeyDown:
+		delta := 1
+		if ev.Key == event.KeyUp {
+			delta = -1
+		}
+		if m.Has(event.ModCtrl) && m.Has(event.ModAlt) {
+			e.buf.AddCursorLine(delta, e.tabWidth)
+		} else {
+			e.buf.MoveLines(delta, e.tabWidth, extend)
+		}
+	case event.KeyPageUp, event.KeyPageDown:
+		page := max(1, int(e.text.Height/e.lineH)-1)
+		if ev.Key == event.KeyPageUp {
+			page = -page
+		}
+		e.buf.MoveLines(page, e.tabWidth, extend)
+		e.scrollY = core.Clamp(e.scrollY+float32(page)*e.lineH, 0, e.vbar.MaxOffset())
+	case event.KeyHome, event.KeyEnd:
+		home := ev.Key == event.KeyHome
+		if cmd {
+			p := e.buf.End()
+			if home {
+				p = textedit.Pos{}
+			}
+			anchor := p
+			if extend {
+				anchor = e.buf.Primary().Anchor
+			}
+			e.buf.SetCursor(anchor, p)
+			break
+		}
+		e.buf.Move(func(c textedit.Cursor) textedit.Pos {
+			if home {
+				return e.buf.LineStart(c.Caret)
+			}
+			return textedit.Pos{Line: c.Caret.Line, Col: len(e.buf.Line(c.Caret.Line))}
+		}, extend)
+	case event.KeyBackspace, event.KeyDelete:
+		if e.readOnly {
+			return core.Ignored
+		}
+		return edit(e.buf.Delete(ev.Key == event.KeyDelete, word))
+	case event.KeyEnter:
+		if e.readOnly {
+			return core.Ignored
+		}
+		return edit(e.buf.NewLine(e.indentUnit()))
+	case event.KeyTab:
+		if e.readOnly || cmd {
+			return core.Ignored
+		}
+		multiline := false
+		for _, c := range e.buf.Cursors() {
+			from, to := c.Range()
+			multiline = multiline || from.Line != to.Line
+		}
+		if extend || multiline {
+			return edit(e.buf.IndentLines(e.indentUnit(), e.tabWidth, extend))
+		}
+		return edit(e.buf.Edit(func(c textedit.Cursor) (textedit.Pos, textedit.Pos, string) {
+			from, to := c.Range()
+			if e.hardTabs {
+				return from, to, "\t"
+			}
+			v := e.buf.VisualCol(from, e.tabWidth)
+			return from, to, strings.Repeat(" ", e.tabWidth-v%e.tabWidth)
+		}))
+	case event.KeyEscape:
+		if !e.buf.CollapseCursors() {
+			return core.Ignored
+		}
+	case event.KeyA, event.KeyD:
+		if !cmd || extend || m.Has(event.ModAlt) {
+			return core.Ignored
+		}
+		if ev.Key == event.KeyA {
+			e.buf.SelectAll()
+		} else if !e.buf.SelectNext() {
+			return core.Handled
+		}
+	default:
+		return core.Ignored
+	}
+	e.reveal = true
+	ctx.MarkNeedsLayout(e)
+	return core.Handled
+}
@@ -0,0 +1,313 @@
+package widgets
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/internal/textedit"
+)
+
+var codeBounds = core.R(0, 0, codeEditorWidth, codeEditorHeight)
+
+// textCanvas records the strings drawn on it.
+type textCanvas struct {
+	core.Recording
+	texts []string
+}
+
+func (c *textCanvas) DrawText(text string, pos core.Point, st core.TextStyle) {
+	c.texts = append(c.texts, text)
+	c.Recording.DrawText(text, pos, st)
+}
+
+// newCodeEditor returns a focused CodeEditor holding text, laid out, and a
+// count of its changes.
+func newCodeEditor(text string) (*CodeEditor, *core.Context, *int) {
+	e := NewCodeEditor()
+	e.SetText(text)
+	changes := 0
+	e.OnChange(func() { changes++ })
+	ctx := layoutAt(e, codeBounds)
+	ctx.RequestFocus(e)
+	return e, ctx, &changes
+}
+
+// codeKey delivers a key press to e and lays it out again.
+func codeKey(ctx *core.Context, e *CodeEditor, k event.Key, mods event.Modifiers) core.EventResult {
+	r := e.HandleEvent(ctx, press(k, mods))
+	ctx.LayoutRoot(e, codeBounds)
+	return r
+}
+
+// codePoint returns the window point of line and col in a laid out editor.
+func codePoint(e *CodeEditor, line, col int) core.Point {
+	v := e.buf.VisualCol(textedit.Pos{Line: line, Col: col}, e.tabWidth)
+	return core.Pt(e.text.X+float32(v)*e.charW-e.scrollX, e.text.Y+(float32(line)+0.5)*e.lineH-e.scrollY)
+}
+
+func TestCodeEditorTyping(t *testing.T) {
+	e, ctx, changes := newCodeEditor("ab\ncd")
+	e.SetCaret(0, 1)
+	codeKey(ctx, e, event.KeyDown, event.ModCtrl|event.ModAlt)
+	e.HandleEvent(ctx, event.TextEvent{Text: "X\x01"})
+	if e.Text() != "aXb\ncXd" || *changes != 1 {
+		t.Errorf("typing at two carets gave %q after %d changes", e.Text(), *changes)
+	}
+	codeKey(ctx, e, event.KeyBackspace, 0)
+	codeKey(ctx, e, event.KeyEscape, 0)
+	if e.Text() != "ab\ncd" || len(e.buf.Cursors()) != 1 {
+		t.Errorf("text %q with %d carets", e.Text(), len(e.buf.Cursors()))
+	}
+	if r := codeKey(ctx, e, event.KeyEscape, 0); r != core.Ignored {
+		t.Errorf("Escape with one caret = %v, want Ignored", r)
+	}
+	codeKey(ctx, e, event.KeyRight, 0)
+	if *changes != 2 {
+		t.Errorf("%d changes; moving the caret is not one", *changes)
+	}
+	if line, col := e.Caret(); line != 0 || col != 2 {
+		t.Errorf("Caret() = %d, %d", line, col)
+	}
+}
+
+func TestCodeEditorKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		line     int
+		col      int
+		hardTabs bool
+		keys     []event.KeyEvent
+		want     string
+		caret    textedit.Pos
+	}{
+		{"tab to the next stop", "ab", 0, 2, false, []event.KeyEvent{press(event.KeyTab, 0)}, "ab  ", textedit.Pos{Col: 4}},
+		{"hard tab", "ab", 0, 2, true, []event.KeyEvent{press(event.KeyTab, 0)}, "ab\t", textedit.Pos{Col: 3}},
+		{"tab indents selected lines", "a\nb", 0, 0, false, []event.KeyEvent{press(event.KeyA, event.ModCtrl), press(event.KeyTab, 0)}, "    a\n    b", textedit.Pos{Line: 1, Col: 5}},
+		{"shift tab outdents", "\t\ta", 0, 3, true, []event.KeyEvent{press(event.KeyTab, event.ModShift)}, "\ta", textedit.Pos{Col: 2}},
+		{"enter keeps the indentation", "\tif x {", 0, 7, true, []event.KeyEvent{press(event.KeyEnter, 0)}, "\tif x {\n\t\t", textedit.Pos{Line: 1, Col: 2}},
+		{"home goes to the indentation", "  x", 0, 3, false, []event.KeyEvent{press(event.KeyHome, 0)}, "  x", textedit.Pos{Col: 2}},
+		{"home twice goes to the start", "  x", 0, 3, false, []event.KeyEvent{press(event.KeyHome, 0), press(event.KeyHome, 0)}, "  x", textedit.Pos{}},
+		{"end", "abc\nd", 0, 0, false, []event.KeyEvent{press(event.KeyEnd, 0)}, "abc\nd", textedit.Pos{Col: 3}},
+		{"document end", "abc\nd", 0, 0, false, []event.KeyEvent{press(event.KeyEnd, event.ModCtrl)}, "abc\nd", textedit.Pos{Line: 1, Col: 1}},
+		{"word right", "foo.bar", 0, 0, false, []event.KeyEvent{press(event.KeyRight, event.ModCtrl)}, "foo.bar", textedit.Pos{Col: 3}},
+		{"delete a word", "foo bar", 0, 7, false, []event.KeyEvent{press(event.KeyBackspace, event.ModCtrl)}, "foo ", textedit.Pos{Col: 4}},
+		{"right collapses a selection", "abc", 0, 0, false, []event.KeyEvent{press(event.KeyEnd, event.ModShift), press(event.KeyRight, 0)}, "abc", textedit.Pos{Col: 3}},
+		{"ctrl tab is not indenting", "ab", 0, 0, false, []event.KeyEvent{press(event.KeyTab, event.ModCtrl)}, "ab", textedit.Pos{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, ctx, _ := newCodeEditor(tt.text)
+			e.HardTabs(tt.hardTabs)
+			e.SetCaret(tt.line, tt.col)
+			for _, k := range tt.keys {
+				codeKey(ctx, e, k.Key, k.Modifiers)
+			}
+			if e.Text() != tt.want {
+				t.Errorf("text %q, want %q", e.Text(), tt.want)
+			}
+			if c := e.buf.Primary().Caret; c != tt.caret {
+				t.Errorf("caret at %v, want %v", c, tt.caret)
+			}
+		})
+	}
+}
+
+func TestCodeEditorSelectNext(t *testing.T) {
+	e, ctx, _ := newCodeEditor("x := x + 1\nprint(x)")
+	codeKey(ctx, e, event.KeyD, event.ModCtrl)
+	codeKey(ctx, e, event.KeyD, event.ModCtrl)
+	codeKey(ctx, e, event.KeyD, event.ModCtrl)
+	e.HandleEvent(ctx, event.TextEvent{Text: "y"})
+	if e.Text() != "y := y + 1\nprint(y)" {
+		t.Errorf("renaming gave %q", e.Text())
+	}
+}
+
+func TestCodeEditorReadOnly(t *testing.T) {
+	e, ctx, changes := newCodeEditor("abc")
+	e.ReadOnly(true)
+	results := []core.EventResult{
+		e.HandleEvent(ctx, event.TextEvent{Text: "x"}),
+		codeKey(ctx, e, event.KeyBackspace, 0),
+		codeKey(ctx, e, event.KeyEnter, 0),
+		codeKey(ctx, e, event.KeyTab, 0),
+	}
+	for i, r := range results {
+		if r != core.Ignored {
+			t.Errorf("edit %d = %v, want Ignored", i, r)
+		}
+	}
+	if r := codeKey(ctx, e, event.KeyA, event.ModCtrl); r != core.Handled || !e.buf.Primary().HasSelection() {
+		t.Error("a read-only editor does not select")
+	}
+	if e.Text() != "abc" || *changes != 0 {
+		t.Errorf("a read-only editor changed to %q", e.Text())
+	}
+}
+
+func TestCodeEditorMouse(t *testing.T) {
+	e, ctx, _ := newCodeEditor("\tfoo_bar(x)\nnext")
+	mouse := func(typ event.MouseEventType, p core.Point, mods event.Modifiers, clicks int) {
+		e.HandleEvent(ctx, event.MouseEvent{Type: typ, Position: p, Button: event.ButtonLeft, Modifiers: mods, ClickCount: clicks})
+		ctx.LayoutRoot(e, codeBounds)
+	}
+	sel := func() (textedit.Pos, textedit.Pos) { c := e.buf.Primary(); return c.Anchor, c.Caret }
+
+	mouse(event.MouseDown, codePoint(e, 0, 3), 0, 1)
+	if a, c := sel(); a != c || c != (textedit.Pos{Col: 3}) {
+		t.Errorf("clicking put the caret at %v", c)
+	}
+	mouse(event.MouseUp, codePoint(e, 0, 3), 0, 1)
+	mouse(event.MouseDown, codePoint(e, 0, 3), 0, 2)
+	if a, c := sel(); a != (textedit.Pos{Col: 1}) || c != (textedit.Pos{Col: 8}) {
+		t.Errorf("double click selected %v to %v, want the identifier", a, c)
+	}
+	mouse(event.MouseUp, codePoint(e, 0, 3), 0, 2)
+	mouse(event.MouseDown, codePoint(e, 0, 3), 0, 3)
+	if a, c := sel(); a != (textedit.Pos{}) || c != (textedit.Pos{Line: 1}) {
+		t.Errorf("triple click selected %v to %v, want the line", a, c)
+	}
+	mouse(event.MouseUp, codePoint(e, 0, 3), 0, 3)
+
+	mouse(event.MouseDown, codePoint(e, 0, 9), 0, 1)
+	mouse(event.MouseUp, codePoint(e, 0, 9), 0, 1)
+	mouse(event.MouseDown, codePoint(e, 1, 2), event.ModShift, 1)
+	if a, c := sel(); a != (textedit.Pos{Col: 9}) || c != (textedit.Pos{Line: 1, Col: 2}) {
+		t.Errorf("shift click selected %v to %v", a, c)
+	}
+	mouse(event.MouseMove, codePoint(e, 1, 4), 0, 1)
+	mouse(event.MouseUp, codePoint(e, 1, 4), 0, 1)
+	if a, c := sel(); a != (textedit.Pos{Col: 9}) || c != (textedit.Pos{Line: 1, Col: 4}) {
+		t.Errorf("dragging selected %v to %v", a, c)
+	}
+
+	// A caret added inside a selection merges into it.
+	mouse(event.MouseDown, codePoint(e, 1, 0), event.ModAlt, 1)
+	mouse(event.MouseUp, codePoint(e, 1, 0), event.ModAlt, 1)
+	if n := len(e.buf.Cursors()); n != 1 {
+		t.Errorf("%d cursors after Alt+click in the selection, want 1", n)
+	}
+	mouse(event.MouseDown, codePoint(e, 0, 0), 0, 1)
+	mouse(event.MouseUp, codePoint(e, 0, 0), 0, 1)
+	mouse(event.MouseDown, codePoint(e, 1, 0), event.ModAlt, 1)
+	mouse(event.MouseUp, codePoint(e, 1, 0), event.ModAlt, 1)
+	if n := len(e.buf.Cursors()); n != 2 {
+		t.Errorf("%d cursors after Alt+click, want 2", n)
+	}
+	mouse(event.MouseDown, codePoint(e, 1, 0), event.ModAlt, 1)
+	if n := len(e.buf.Cursors()); n != 1 {
+		t.Errorf("%d cursors after Alt+click on a caret, want 1", n)
+	}
+}
+
+func TestCodeEditorLargeFile(t *testing.T) {
+	lines := make([]string, 1000)
+	for i := range lines {
+		lines[i] = "x := " + strconv.Itoa(i)
+	}
+	lines[0] = "/* a comment"
+	lines[1] = "still */ y"
+	e, ctx, _ := newCodeEditor(strings.Join(lines, "\n"))
+	e.Tokenizer(GoSyntax)
+	e.SetCaret(500, 3)
+	ctx.Invalidate()
+	ctx.LayoutRoot(e, codeBounds)
+	first, last := e.visibleLines()
+	if first > 500 || last < 500 || last-first > int(codeEditorHeight/e.lineH)+1 {
+		t.Fatalf("lines %d to %d in view, want line 500 and a screenful", first, last)
+	}
+
+	cv := &textCanvas{}
+	e.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+	for _, s := range cv.texts {
+		if s == "1" || s == "1000" {
+			t.Errorf("painted %q, which is out of view", s)
+		}
+	}
+	if e.valid < 500 || e.valid > last+1 {
+		t.Errorf("tokenized %d lines to show line %d", e.valid, last)
+	}
+
+	// Tokenizing carries state between lines, and edits invalidate it.
+	if toks := e.tokens(1); len(toks) == 0 || toks[0] != (Token{0, 8, TokenComment}) {
+		t.Errorf("line 2 tokens %v, want the end of the comment", toks)
+	}
+	e.SetCaret(0, 0)
+	codeKey(ctx, e, event.KeyDelete, 0)
+	codeKey(ctx, e, event.KeyDelete, 0)
+	if e.valid != 0 {
+		t.Errorf("%d lines kept tokenized after an edit at the start", e.valid)
+	}
+	if toks := e.tokens(1); len(toks) > 0 && toks[0].Kind == TokenComment {
+		t.Errorf("line 2 is still a comment: %v", toks)
+	}
+}
+
+func TestCodeEditorGutter(t *testing.T) {
+	tests := []struct {
+		lines   int
+		numbers bool
+		digits  float32
+	}{
+		{5, true, 2},
+		{100, true, 3},
+		{10000, true, 5},
+		{100, false, 0},
+	}
+	for _, tt := range tests {
+		e, ctx, _ := newCodeEditor(strings.Repeat("\n", tt.lines-1))
+		e.LineNumbers(tt.numbers)
+		ctx.Invalidate()
+		ctx.LayoutRoot(e, codeBounds)
+		want := float32(0)
+		if tt.numbers {
+			want = tt.digits*e.charW + 2*fieldPadding
+		}
+		if e.gutter.Width != want {
+			t.Errorf("%d lines: gutter %v wide, want %v", tt.lines, e.gutter.Width, want)
+		}
+		if e.text.X != e.gutter.Right()+fieldPadding/2 {
+			t.Errorf("%d lines: text at %v past the gutter at %v", tt.lines, e.text.X, e.gutter.Right())
+		}
+	}
+}
+
+func TestCodeEditorScroll(t *testing.T) {
+	e, ctx, _ := newCodeEditor(strings.Repeat("a\n", 200) + strings.Repeat("w", 300))
+	codeKey(ctx, e, event.KeyEnd, event.ModCtrl)
+	if e.scrollY == 0 || e.scrollX == 0 {
+		t.Fatalf("scrolled to %v, %v, want the end in view", e.scrollX, e.scrollY)
+	}
+	caret := codePoint(e, 200, 300)
+	if !e.text.Contains(core.Pt(caret.X-1, caret.Y)) {
+		t.Errorf("the caret at %v is out of view %v", caret, e.text)
+	}
+	e.HandleEvent(ctx, event.ScrollEvent{Delta: core.Pt(0, -1e6)})
+	if e.scrollY != 0 {
+		t.Errorf("scrolled to %v past the start", e.scrollY)
+	}
+	codeKey(ctx, e, event.KeyHome, event.ModCtrl)
+	if e.scrollX != 0 || e.scrollY != 0 {
+		t.Errorf("scrolled to %v, %v at the start", e.scrollX, e.scrollY)
+	}
+	codeKey(ctx, e, event.KeyPageDown, 0)
+	if line, _ := e.Caret(); line == 0 || line > int(e.text.Height/e.lineH) {
+		t.Errorf("Page Down went to line %d", line)
+	}
+}
+
+func TestCodeEditorBrackets(t *testing.T) {
+	e, _, _ := newCodeEditor(`f(")", x)`)
+	e.Tokenizer(GoSyntax)
+	skip := e.skipBracket()
+	if !skip(textedit.Pos{Col: 3}) || skip(textedit.Pos{Col: 1}) {
+		t.Error("skipBracket does not follow the strings")
+	}
+	if _, q, ok := e.buf.MatchBracket(textedit.Pos{Col: 1}, codeMatchLimit, skip); !ok || q.Col != 8 {
+		t.Errorf("the bracket matches at %v, want after the string", q)
+	}
+}
@@ -0,0 +1,214 @@
+package widgets
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/theme"
+)
+
+// TokenKind classifies a token for highlighting.
+type TokenKind uint8
+
+// Token kinds.
+const (
+	TokenPlain TokenKind = iota
+	TokenKeyword
+	TokenType
+	TokenFunction
+	TokenString
+	TokenNumber
+	TokenComment
+	TokenOperator
+)
+
+// Token is a highlighted stretch of a line, in rune columns.
+type Token struct {
+	Start, End int
+	Kind       TokenKind
+}
+
+// Tokenizer splits lines of source code into tokens for a CodeEditor.
+// Lines are tokenized in order; state carries what is open at the end of
+// one line, such as a block comment, into the next. The first line starts
+// in state 0. Columns not covered by a token are plain.
+type Tokenizer interface {
+	Tokenize(line []rune, state int) (tokens []Token, end int)
+}
+
+// CLikeTokenizer is a Tokenizer for languages with C-like lexical syntax:
+// identifiers, decimal and hexadecimal numbers, quoted strings, and line
+// and block comments.
+type CLikeTokenizer struct {
+	Keywords []string
+	Types    []string
+	// LineComment starts a comment running to the end of the line.
+	LineComment string
+	// BlockComment holds the delimiters of comments that may span lines.
+	BlockComment [2]string
+	// Quotes are the characters that delimit strings on one line, and
+	// RawQuote, if set, delimits strings that may span lines.
+	Quotes   string
+	RawQuote rune
+}
+
+// GoSyntax tokenizes Go source code.
+var GoSyntax = &CLikeTokenizer{
+	Keywords: []string{
+		"break", "case", "chan", "const", "continue", "default", "defer", "else",
+		"fallthrough", "for", "func", "go", "goto", "if", "import", "interface",
+		"map", "package", "range", "return", "select", "struct", "switch", "type",
+		"var", "true", "false", "nil", "iota",
+	},
+	Types: []string{
+		"any", "bool", "byte", "comparable", "complex64", "complex128", "error",
+		"float32", "float64", "int", "int8", "int16", "int32", "int64", "rune",
+		"string", "uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+	},
+	LineComment:  "//",
+	BlockComment: [2]string{"/*", "*/"},
+	Quotes:       `"'`,
+	RawQuote:     '`',
+}
+
+// States of a CLikeTokenizer between lines.
+const (
+	clikeCode = iota
+	clikeBlockComment
+	clikeRawString
+)
+
+func hasPrefix(line []rune, i int, prefix string) bool {
+	if prefix == "" {
+		return false
+	}
+	p := []rune(prefix)
+	return i+len(p) <= len(line) && slices.Equal(line[i:i+len(p)], p)
+}
+
+// find returns the column just after the first occurrence of s in line at
+// or after i, or -1.
+func find(line []rune, i int, s string) int {
+	for ; i < len(line); i++ {
+		if hasPrefix(line, i, s) {
+			return i + len([]rune(s))
+		}
+	}
+	return -1
+}
+
+// Tokenize implements Tokenizer.
+func (t *CLikeTokenizer) Tokenize(line []rune, state int) ([]Token, int) {
+	var toks []Token
+	i := 0
+	switch state {
+	case clikeBlockComment:
+		end := find(line, 0, t.BlockComment[1])
+		if end < 0 {
+			return []Token{{0, len(line), TokenComment}}, state
+		}
+		toks = append(toks, Token{0, end, TokenComment})
+		i = end
+	case clikeRawString:
+		end := slices.Index(line, t.RawQuote)
+		if end < 0 {
+			return []Token{{0, len(line), TokenString}}, state
+		}
+		toks = append(toks, Token{0, end + 1, TokenString})
+		i = end + 1
+	}
+	for i < len(line) {
+		r := line[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case hasPrefix(line, i, t.LineComment):
+			return append(toks, Token{i, len(line), TokenComment}), clikeCode
+		case hasPrefix(line, i, t.BlockComment[0]):
+			end := find(line, i+len([]rune(t.BlockComment[0])), t.BlockComment[1])
+			if end < 0 {
+				return append(toks, Token{i, len(line), TokenComment}), clikeBlockComment
+			}
+			toks = append(toks, Token{i, end, TokenComment})
+			i = end
+		case t.RawQuote != 0 && r == t.RawQuote:
+			end := slices.Index(line[i+1:], t.RawQuote)
+			if end < 0 {
+				return append(toks, Token{i, len(line), TokenString}), clikeRawString
+			}
+			toks = append(toks, Token{i, i + end + 2, TokenString})
+			i += end + 2
+		case strings.ContainsRune(t.Quotes, r):
+			j := i + 1
+			for j < len(line) && line[j] != r {
+				if line[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			j = min(j+1, len(line))
+			toks = append(toks, Token{i, j, TokenString})
+			i = j
+		case unicode.IsDigit(r) || r == '.' && i+1 < len(line) && unicode.IsDigit(line[i+1]):
+			j := i + 1
+			for j < len(line) && (unicode.IsLetter(line[j]) || unicode.IsDigit(line[j]) || line[j] == '.' || line[j] == '_') {
+				j++
+			}
+			toks = append(toks, Token{i, j, TokenNumber})
+			i = j
+		case unicode.IsLetter(r) || r == '_':
+			j := i + 1
+			for j < len(line) && (unicode.IsLetter(line[j]) || unicode.IsDigit(line[j]) || line[j] == '_') {
+				j++
+			}
+			word := string(line[i:j])
+			k := j
+			for k < len(line) && line[k] == ' ' {
+				k++
+			}
+			switch {
+			case slices.Contains(t.Keywords, word):
+				toks = append(toks, Token{i, j, TokenKeyword})
+			case slices.Contains(t.Types, word):
+				toks = append(toks, Token{i, j, TokenType})
+			case k < len(line) && line[k] == '(':
+				toks = append(toks, Token{i, j, TokenFunction})
+			}
+			i = j
+		case strings.ContainsRune("+-*/%&|^!<>=:~?", r):
+			j := i + 1
+			for j < len(line) && strings.ContainsRune("+-*/%&|^!<>=:~?", line[j]) && !hasPrefix(line, j, t.LineComment) && !hasPrefix(line, j, t.BlockComment[0]) {
+				j++
+			}
+			toks = append(toks, Token{i, j, TokenOperator})
+			i = j
+		default:
+			i++
+		}
+	}
+	return toks, clikeCode
+}
+
+// tokenColor returns the color of code of kind k.
+func tokenColor(th *theme.Theme, k TokenKind) core.Color {
+	s := th.Syntax
+	switch k {
+	case TokenKeyword:
+		return s.Keyword
+	case TokenType:
+		return s.Type
+	case TokenFunction:
+		return s.Function
+	case TokenString:
+		return s.String
+	case TokenNumber:
+		return s.Number
+	case TokenComment:
+		return s.Comment
+	case TokenOperator:
+		return s.Operator
+	}
+	return th.Colors.OnSurface
+}
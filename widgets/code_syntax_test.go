@@ -0,0 +1,81 @@
+package widgets
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/gogpu/ui/theme"
+)
+
+func TestCLikeTokenizer(t *testing.T) {
+	tests := []struct {
+		name      string
+		line      string
+		state     int
+		want      []Token
+		wantState int
+	}{
+		{"keywords and calls", "func main() {", clikeCode,
+			[]Token{{0, 4, TokenKeyword}, {5, 9, TokenFunction}}, clikeCode},
+		{"strings and comments", `x := "a\"b" // c`, clikeCode,
+			[]Token{{2, 4, TokenOperator}, {5, 11, TokenString}, {12, 16, TokenComment}}, clikeCode},
+		{"types and numbers", "var n int = 0x1F", clikeCode,
+			[]Token{{0, 3, TokenKeyword}, {6, 9, TokenType}, {10, 11, TokenOperator}, {12, 16, TokenNumber}}, clikeCode},
+		{"a fraction", "x * .5", clikeCode,
+			[]Token{{2, 3, TokenOperator}, {4, 6, TokenNumber}}, clikeCode},
+		{"an operator before a comment", "a+//b", clikeCode,
+			[]Token{{1, 2, TokenOperator}, {2, 5, TokenComment}}, clikeCode},
+		{"an unterminated string", `"abc`, clikeCode,
+			[]Token{{0, 4, TokenString}}, clikeCode},
+		{"a block comment opens", "a /* b", clikeCode,
+			[]Token{{2, 6, TokenComment}}, clikeBlockComment},
+		{"inside a block comment", "still", clikeBlockComment,
+			[]Token{{0, 5, TokenComment}}, clikeBlockComment},
+		{"a block comment closes", " b */ 1.5", clikeBlockComment,
+			[]Token{{0, 5, TokenComment}, {6, 9, TokenNumber}}, clikeCode},
+		{"a raw string opens", "s := `raw", clikeCode,
+			[]Token{{2, 4, TokenOperator}, {5, 9, TokenString}}, clikeRawString},
+		{"a raw string closes", "x` + nil", clikeRawString,
+			[]Token{{0, 2, TokenString}, {3, 4, TokenOperator}, {5, 8, TokenKeyword}}, clikeCode},
+		{"a raw string on one line", "`a`b", clikeCode,
+			[]Token{{0, 3, TokenString}}, clikeCode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			toks, state := GoSyntax.Tokenize([]rune(tt.line), tt.state)
+			if !slices.Equal(toks, tt.want) || state != tt.wantState {
+				t.Errorf("Tokenize(%q) = %v, %d, want %v, %d", tt.line, toks, state, tt.want, tt.wantState)
+			}
+		})
+	}
+
+	// A language without block comments or raw strings.
+	sh := &CLikeTokenizer{Keywords: []string{"if"}, LineComment: "#", Quotes: `"`}
+	toks, state := sh.Tokenize([]rune("if /* `x` # y"), clikeCode)
+	want := []Token{{0, 2, TokenKeyword}, {3, 5, TokenOperator}, {10, 13, TokenComment}}
+	if !slices.Equal(toks, want) || state != clikeCode {
+		t.Errorf("Tokenize = %v, %d, want %v", toks, state, want)
+	}
+}
+
+func TestTokenColor(t *testing.T) {
+	th := theme.Light()
+	tests := []struct {
+		kind TokenKind
+		want any
+	}{
+		{TokenPlain, th.Colors.OnSurface},
+		{TokenKeyword, th.Syntax.Keyword},
+		{TokenType, th.Syntax.Type},
+		{TokenFunction, th.Syntax.Function},
+		{TokenString, th.Syntax.String},
+		{TokenNumber, th.Syntax.Number},
+		{TokenComment, th.Syntax.Comment},
+		{TokenOperator, th.Syntax.Operator},
+	}
+	for _, tt := range tests {
+		if got := tokenColor(th, tt.kind); got != tt.want {
+			t.Errorf("tokenColor(%d) = %v, want %v", tt.kind, got, tt.want)
+		}
+	}
+}
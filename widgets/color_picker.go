@@ -0,0 +1,567 @@
+package widgets
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/theme"
+)
+
+const (
+	colorPickerWidth  float32 = 240
+	colorSquareHeight float32 = 160
+	colorStripHeight  float32 = 12
+	colorGap          float32 = 8
+	colorSwatch       float32 = 20
+	colorFieldHeight  float32 = 28
+	colorRecentLimit          = 10
+)
+
+// EyeDropper is implemented by platform integrations that can sample a
+// color from anywhere on the screen. PickScreenColor starts an interactive
+// pick and later calls done, from any goroutine, with the color or with ok
+// false if the user cancelled.
+type EyeDropper interface {
+	PickScreenColor(done func(c core.Color, ok bool))
+}
+
+type eyeDropperKey struct{}
+
+// SetEyeDropper installs the eyedropper used by every ColorPicker in the
+// window. Without one the eyedropper button is hidden.
+func SetEyeDropper(ctx *core.Context, d EyeDropper) {
+	ctx.SetValue(eyeDropperKey{}, d)
+}
+
+type recentColorsKey struct{}
+
+// RecentColors returns the colors recently chosen in any ColorPicker of
+// the window, most recent first.
+func RecentColors(ctx *core.Context) []core.Color {
+	if r, ok := ctx.Value(recentColorsKey{}).(*[]core.Color); ok {
+		return *r
+	}
+	return nil
+}
+
+func addRecentColor(ctx *core.Context, c core.Color) {
+	r, ok := ctx.Value(recentColorsKey{}).(*[]core.Color)
+	if !ok {
+		r = new([]core.Color)
+		ctx.SetValue(recentColorsKey{}, r)
+	}
+	list := slices.DeleteFunc(*r, func(x core.Color) bool { return sameColor(x, c) })
+	*r = slices.Insert(list, 0, c)
+	if len(*r) > colorRecentLimit {
+		*r = (*r)[:colorRecentLimit]
+	}
+}
+
+func sameColor(a, b core.Color) bool {
+	ar, ag, ab, aa := a.RGBA8()
+	br, bg, bb, ba := b.RGBA8()
+	return ar == br && ag == bg && ab == bb && aa == ba
+}
+
+// ColorPicker edits a color with a saturation/value square, hue and alpha
+// strips, hex and RGBA fields, a row of recently used colors and, where the
+// platform provides one, an eyedropper.
+//
+// The color is tracked in HSV so hue survives passing through grays.
+// OnChange is called continuously while dragging; a color is added to the
+// recent colors when a drag ends or a field is committed.
+type ColorPicker struct {
+	core.WidgetBase
+
+	h, s, v, a float32
+	noAlpha    bool
+	onChange   func(core.Color)
+
+	hex    *lineField
+	fields [4]*lineField
+
+	square, hue, alpha core.Rect
+	preview, dropper   core.Rect
+	swatches           []core.Rect
+	recent             []core.Color
+	hasDropper         bool
+	drag               int // 0 none, 1 square, 2 hue, 3 alpha
+
+	squareImg     *image.RGBA
+	squareImgHue  float32
+	hueImg        *image.RGBA
+	alphaImg      *image.RGBA
+	alphaImgColor core.Color
+}
+
+// NewColorPicker returns a ColorPicker showing c.
+func NewColorPicker(c core.Color) *ColorPicker {
+	p := &ColorPicker{squareImgHue: -1}
+	p.hex = newLineField(80, p.commitHex)
+	for i := range p.fields {
+		p.fields[i] = newLineField(40, func(ctx *core.Context, text string) bool { return p.commitChannel(ctx, i, text) })
+		p.fields[i].step = func(ctx *core.Context, delta int) { p.stepChannel(ctx, i, delta) }
+	}
+	p.SetValue(c)
+	return p
+}
+
+// ShowAlpha shows or hides the alpha strip and field. Hiding them makes
+// every chosen color opaque.
+func (p *ColorPicker) ShowAlpha(show bool) *ColorPicker {
+	p.noAlpha = !show
+	if p.noAlpha {
+		p.a = 1
+	}
+	return p
+}
+
+// OnChange registers fn to be called whenever the user changes the color.
+func (p *ColorPicker) OnChange(fn func(core.Color)) *ColorPicker {
+	p.onChange = fn
+	return p
+}
+
+// Value returns the current color.
+func (p *ColorPicker) Value() core.Color {
+	r, g, b := hsvToRGB(p.h, p.s, p.v)
+	return core.Color{R: r, G: g, B: b, A: p.a}
+}
+
+// SetValue sets the color without calling OnChange.
+func (p *ColorPicker) SetValue(c core.Color) {
+	h, s, v := rgbToHSV(c.R, c.G, c.B)
+	if s > 0 && v > 0 {
+		p.h = h
+	}
+	if v > 0 {
+		p.s = s
+	}
+	p.v, p.a = v, c.A
+	if p.noAlpha {
+		p.a = 1
+	}
+}
+
+// Children implements core.Parent.
+func (p *ColorPicker) Children() []core.Widget {
+	n := 4
+	if p.noAlpha {
+		n = 3
+	}
+	children := []core.Widget{p.hex}
+	for _, f := range p.fields[:n] {
+		children = append(children, f)
+	}
+	return children
+}
+
+func (p *ColorPicker) changed(ctx *core.Context, commit bool) {
+	// The fields show the new color and the recent colors may gain a row
+	// in the next layout.
+	ctx.MarkNeedsLayout(p)
+	if commit {
+		addRecentColor(ctx, p.Value())
+	}
+	if p.onChange != nil {
+		p.onChange(p.Value())
+	}
+}
+
+func (p *ColorPicker) commitHex(ctx *core.Context, text string) bool {
+	c, ok := parseHexColor(text)
+	if !ok {
+		return false
+	}
+	if p.noAlpha {
+		c.A = 1
+	}
+	p.SetValue(c)
+	p.changed(ctx, true)
+	return true
+}
+
+func (p *ColorPicker) commitChannel(ctx *core.Context, i int, text string) bool {
+	n, err := strconv.Atoi(strings.TrimSpace(text))
+	if err != nil || n < 0 || n > 255 {
+		return false
+	}
+	p.setChannel(i, n)
+	p.changed(ctx, true)
+	return true
+}
+
+func (p *ColorPicker) stepChannel(ctx *core.Context, i, delta int) {
+	r, g, b, a := p.Value().RGBA8()
+	ch := [4]int{int(r), int(g), int(b), int(a)}
+	p.setChannel(i, max(0, min(255, ch[i]+delta)))
+	p.changed(ctx, true)
+}
+
+func (p *ColorPicker) setChannel(i, n int) {
+	c := p.Value()
+	v := float32(n) / 255
+	switch i {
+	case 0:
+		c.R = v
+	case 1:
+		c.G = v
+	case 2:
+		c.B = v
+	case 3:
+		c.A = v
+	}
+	p.SetValue(c)
+}
+
+// Layout implements core.Widget.
+func (p *ColorPicker) Layout(ctx *core.LayoutContext) core.Size {
+	c := p.Value()
+	text := formatHexColor(c, !p.noAlpha)
+	p.hex.setText(text)
+	r, g, b, a := c.RGBA8()
+	for i, v := range [4]uint8{r, g, b, a} {
+		p.fields[i].setText(strconv.Itoa(int(v)))
+	}
+	for _, w := range p.Children() {
+		f := w.(*lineField)
+		f.height = colorFieldHeight
+		ctx.Measure(f, core.Loose(core.Sz(f.width, colorFieldHeight)))
+	}
+	p.recent = RecentColors(ctx.Context)
+	_, p.hasDropper = ctx.Value(eyeDropperKey{}).(EyeDropper)
+
+	h := colorSquareHeight + colorGap + colorStripHeight
+	if !p.noAlpha {
+		h += colorGap + colorStripHeight
+	}
+	h += colorGap + colorFieldHeight
+	if len(p.recent) > 0 {
+		h += colorGap + colorSwatch
+	}
+	return ctx.Constraints.Constrain(core.Sz(colorPickerWidth, h))
+}
+
+// SetBounds implements core.Widget.
+func (p *ColorPicker) SetBounds(r core.Rect) {
+	p.WidgetBase.SetBounds(r)
+	y := r.Y
+	p.square = core.R(r.X, y, r.Width, colorSquareHeight)
+	y += colorSquareHeight + colorGap
+	strip := r.Width
+	if p.hasDropper {
+		strip -= colorFieldHeight + colorGap
+		p.dropper = core.R(r.Right()-colorFieldHeight, y, colorFieldHeight, colorFieldHeight)
+	}
+	p.hue = core.R(r.X, y, strip, colorStripHeight)
+	y += colorStripHeight + colorGap
+	if !p.noAlpha {
+		p.alpha = core.R(r.X, y, strip, colorStripHeight)
+		y += colorStripHeight + colorGap
+	}
+	p.preview = core.R(r.X, y, colorFieldHeight, colorFieldHeight)
+	x := p.preview.Right() + colorGap
+	p.hex.SetBounds(core.R(x, y, p.hex.width, colorFieldHeight))
+	x += p.hex.width + colorGap/2
+	n := len(p.Children()) - 1
+	w := (r.Right() - x - float32(n-1)*colorGap/2) / float32(n)
+	for _, f := range p.fields[:n] {
+		f.SetBounds(core.R(x, y, w, colorFieldHeight))
+		x += w + colorGap/2
+	}
+	y += colorFieldHeight + colorGap
+	p.swatches = p.swatches[:0]
+	for i := range p.recent {
+		sr := core.R(r.X+float32(i)*(colorSwatch+colorGap/2), y, colorSwatch, colorSwatch)
+		if sr.Right() > r.Right() {
+			break
+		}
+		p.swatches = append(p.swatches, sr)
+	}
+}
+
+// Paint implements core.Widget.
+func (p *ColorPicker) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	outline := core.Stroked(th.Colors.Outline.WithAlpha(0.5), 1)
+
+	cv.DrawImage(p.squareImage(), p.square)
+	cv.DrawRect(p.square, outline)
+	knob := core.Pt(p.square.X+p.s*p.square.Width, p.square.Y+(1-p.v)*p.square.Height)
+	cv.DrawRoundedRect(core.R(knob.X-6, knob.Y-6, 12, 12), 6, core.Stroked(core.White, 2))
+	cv.DrawRoundedRect(core.R(knob.X-7, knob.Y-7, 14, 14), 7, core.Stroked(core.Black.WithAlpha(0.4), 1))
+
+	cv.DrawImage(p.hueImage(), p.hue)
+	p.paintStripKnob(ctx, p.hue, p.h/360)
+	if !p.noAlpha {
+		cv.DrawImage(p.alphaImage(), p.alpha)
+		p.paintStripKnob(ctx, p.alpha, p.a)
+	}
+
+	paintChecker(ctx, p.preview)
+	cv.DrawRoundedRect(p.preview, th.Radii.Small, core.RectStyle{Fill: p.Value(), Stroke: th.Colors.Outline.WithAlpha(0.5), StrokeWidth: 1})
+	for _, f := range p.Children() {
+		f.Paint(ctx)
+	}
+	for i, sr := range p.swatches {
+		paintChecker(ctx, sr)
+		cv.DrawRoundedRect(sr, th.Radii.Small, core.RectStyle{Fill: p.recent[i], Stroke: th.Colors.Outline.WithAlpha(0.5), StrokeWidth: 1})
+	}
+	if p.hasDropper {
+		cv.DrawRoundedRect(p.dropper, th.Radii.Small, outline)
+		c := p.dropper.Center()
+		pipette := core.NewPath().MoveTo(core.Pt(c.X-6, c.Y+6)).LineTo(core.Pt(c.X+3, c.Y-3))
+		cv.DrawPath(pipette, core.PathStyle{Stroke: th.Colors.OnSurfaceVariant, StrokeWidth: 2, LineCap: core.CapRound})
+		cv.DrawRoundedRect(core.R(c.X+1, c.Y-7, 6, 6), 3, core.Filled(th.Colors.OnSurfaceVariant))
+	}
+}
+
+func (p *ColorPicker) paintStripKnob(ctx *core.PaintContext, strip core.Rect, t float32) {
+	x := strip.X + t*strip.Width
+	k := core.R(x-3, strip.Y-2, 6, strip.Height+4)
+	ctx.Canvas.DrawRoundedRect(k, 2, core.RectStyle{Fill: core.White, Stroke: core.Black.WithAlpha(0.4), StrokeWidth: 1})
+}
+
+// paintChecker draws the checkerboard shown behind translucent colors.
+func paintChecker(ctx *core.PaintContext, r core.Rect) {
+	const cell = 5
+	ctx.Canvas.DrawRect(r, core.Filled(core.White))
+	gray := core.Filled(core.Hex(0xCCCCCC))
+	for y := float32(0); y < r.Height; y += cell {
+		for x := float32(0); x < r.Width; x += cell {
+			if int(x/cell+y/cell)%2 == 1 {
+				ctx.Canvas.DrawRect(core.R(r.X+x, r.Y+y, min(cell, r.Width-x), min(cell, r.Height-y)), gray)
+			}
+		}
+	}
+}
+
+// squareImage returns the saturation/value gradient for the current hue.
+func (p *ColorPicker) squareImage() image.Image {
+	const n = 64
+	if p.squareImg != nil && p.squareImgHue == p.h {
+		return p.squareImg
+	}
+	if p.squareImg == nil {
+		p.squareImg = image.NewRGBA(image.Rect(0, 0, n, n))
+	}
+	for y := range n {
+		for x := range n {
+			r, g, b := hsvToRGB(p.h, float32(x)/(n-1), 1-float32(y)/(n-1))
+			p.squareImg.SetRGBA(x, y, rgba8(core.Color{R: r, G: g, B: b, A: 1}))
+		}
+	}
+	p.squareImgHue = p.h
+	return p.squareImg
+}
+
+func (p *ColorPicker) hueImage() image.Image {
+	if p.hueImg == nil {
+		p.hueImg = image.NewRGBA(image.Rect(0, 0, 360, 1))
+		for x := range 360 {
+			r, g, b := hsvToRGB(float32(x), 1, 1)
+			p.hueImg.SetRGBA(x, 0, rgba8(core.Color{R: r, G: g, B: b, A: 1}))
+		}
+	}
+	return p.hueImg
+}
+
+// alphaImage returns the strip fading the opaque color over a checkerboard.
+func (p *ColorPicker) alphaImage() image.Image {
+	c := p.Value()
+	c.A = 1
+	if p.alphaImg != nil && sameColor(c, p.alphaImgColor) {
+		return p.alphaImg
+	}
+	w, h := int(colorPickerWidth), int(colorStripHeight)
+	if p.alphaImg == nil {
+		p.alphaImg = image.NewRGBA(image.Rect(0, 0, w, h))
+	}
+	for y := range h {
+		for x := range w {
+			bg := float32(1)
+			if (x/4+y/4)%2 == 1 {
+				bg = 0.8
+			}
+			t := float32(x) / float32(w-1)
+			p.alphaImg.SetRGBA(x, y, rgba8(core.Color{
+				R: c.R*t + bg*(1-t), G: c.G*t + bg*(1-t), B: c.B*t + bg*(1-t), A: 1,
+			}))
+		}
+	}
+	p.alphaImgColor = c
+	return p.alphaImg
+}
+
+func rgba8(c core.Color) color.RGBA {
+	r, g, b, a := c.RGBA8()
+	return color.RGBA{R: r, G: g, B: b, A: a}
+}
+
+func (p *ColorPicker) dragTo(pos core.Point) {
+	switch p.drag {
+	case 1:
+		p.s = core.Clamp((pos.X-p.square.X)/p.square.Width, 0, 1)
+		p.v = 1 - core.Clamp((pos.Y-p.square.Y)/p.square.Height, 0, 1)
+	case 2:
+		p.h = core.Clamp((pos.X-p.hue.X)/p.hue.Width, 0, 1) * 360
+	case 3:
+		p.a = core.Clamp((pos.X-p.alpha.X)/p.alpha.Width, 0, 1)
+	}
+}
+
+// HandleEvent implements core.Widget.
+func (p *ColorPicker) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	e, ok := ev.(event.MouseEvent)
+	if !ok {
+		return core.Ignored
+	}
+	switch e.Type {
+	case event.MouseDown:
+		if e.Button != event.ButtonLeft {
+			return core.Ignored
+		}
+		switch {
+		case p.square.Contains(e.Position):
+			p.drag = 1
+		case p.hue.Inset(core.SymmetricInsets(0, -4)).Contains(e.Position):
+			p.drag = 2
+		case !p.noAlpha && p.alpha.Inset(core.SymmetricInsets(0, -4)).Contains(e.Position):
+			p.drag = 3
+		case p.hasDropper && p.dropper.Contains(e.Position):
+			p.pickFromScreen(ctx)
+			return core.Handled
+		default:
+			for i, sr := range p.swatches {
+				if sr.Contains(e.Position) {
+					p.SetValue(p.recent[i])
+					p.changed(ctx, true)
+					return core.Handled
+				}
+			}
+			return core.Ignored
+		}
+		ctx.RequestFocus(nil)
+		ctx.CapturePointer(p)
+		p.dragTo(e.Position)
+		p.changed(ctx, false)
+		return core.Handled
+	case event.MouseMove:
+		if p.drag == 0 {
+			return core.Ignored
+		}
+		p.dragTo(e.Position)
+		p.changed(ctx, false)
+		return core.Handled
+	case event.MouseUp:
+		if p.drag == 0 {
+			return core.Ignored
+		}
+		p.drag = 0
+		ctx.ReleasePointer()
+		addRecentColor(ctx, p.Value())
+		ctx.MarkNeedsLayout(p)
+		return core.Handled
+	}
+	return core.Ignored
+}
+
+func (p *ColorPicker) pickFromScreen(ctx *core.Context) {
+	d, ok := ctx.Value(eyeDropperKey{}).(EyeDropper)
+	if !ok {
+		return
+	}
+	d.PickScreenColor(func(c core.Color, ok bool) {
+		ctx.Post(func() {
+			if !ok {
+				return
+			}
+			if p.noAlpha {
+				c.A = 1
+			}
+			p.SetValue(c)
+			p.changed(ctx, true)
+		})
+	})
+}
+
+// hsvToRGB converts hue in degrees and saturation and value in [0, 1].
+func hsvToRGB(h, s, v float32) (r, g, b float32) {
+	h = float32(math.Mod(float64(h), 360)) / 60
+	i := int(h)
+	f := h - float32(i)
+	pv, qv, tv := v*(1-s), v*(1-s*f), v*(1-s*(1-f))
+	switch i {
+	case 0:
+		return v, tv, pv
+	case 1:
+		return qv, v, pv
+	case 2:
+		return pv, v, tv
+	case 3:
+		return pv, qv, v
+	case 4:
+		return tv, pv, v
+	}
+	return v, pv, qv
+}
+
+func rgbToHSV(r, g, b float32) (h, s, v float32) {
+	hi, lo := max(r, g, b), min(r, g, b)
+	v = hi
+	d := hi - lo
+	if hi > 0 {
+		s = d / hi
+	}
+	if d == 0 {
+		return 0, s, v
+	}
+	switch hi {
+	case r:
+		h = (g - b) / d
+	case g:
+		h = 2 + (b-r)/d
+	default:
+		h = 4 + (r-g)/d
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return h, s, v
+}
+
+// parseHexColor parses #RGB, #RRGGBB or #RRGGBBAA, with or without the #.
+func parseHexColor(s string) (core.Color, bool) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(s) == 3 {
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+	}
+	if len(s) == 6 {
+		s += "ff"
+	}
+	if len(s) != 8 {
+		return core.Color{}, false
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return core.Color{}, false
+	}
+	return core.RGBA(uint8(v>>24), uint8(v>>16), uint8(v>>8), uint8(v)), true
+}
+
+func formatHexColor(c core.Color, alpha bool) string {
+	r, g, b, a := c.RGBA8()
+	if alpha && a != 255 {
+		return fmt.Sprintf("#%02X%02X%02X%02X", r, g, b, a)
+	}
+	return fmt.Sprintf("#%02X%02X%02X", r, g, b)
+}
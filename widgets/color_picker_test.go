@@ -0,0 +1,295 @@
+package widgets
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+)
+
+// dropper is an EyeDropper that keeps the pending pick for the test to
+// finish.
+type dropper struct {
+	done func(c core.Color, ok bool)
+}
+
+func (d *dropper) PickScreenColor(done func(c core.Color, ok bool)) { d.done = done }
+
+// newColorPicker returns a laid out picker showing c.
+func newColorPicker(c core.Color) (*ColorPicker, *core.Context) {
+	p := NewColorPicker(c)
+	return p, layoutAt(p, core.R(0, 0, colorPickerWidth, 400))
+}
+
+func leftMouse(typ event.MouseEventType, p core.Point) event.MouseEvent {
+	return event.MouseEvent{Type: typ, Position: p, Button: event.ButtonLeft}
+}
+
+func TestParseHexColor(t *testing.T) {
+	tests := []struct {
+		in   string
+		want core.Color
+		ok   bool
+	}{
+		{"#FF8000", core.RGB(255, 128, 0), true},
+		{"ff8000", core.RGB(255, 128, 0), true},
+		{" #f80 ", core.RGB(255, 136, 0), true},
+		{"#11223344", core.RGBA(0x11, 0x22, 0x33, 0x44), true},
+		{"#1234", core.Color{}, false},
+		{"#GG0000", core.Color{}, false},
+		{"", core.Color{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseHexColor(tt.in)
+		if ok != tt.ok || !sameColor(got, tt.want) {
+			t.Errorf("parseHexColor(%q) = %v, %v, want %v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestFormatHexColor(t *testing.T) {
+	tests := []struct {
+		c     core.Color
+		alpha bool
+		want  string
+	}{
+		{core.RGB(255, 128, 0), true, "#FF8000"},
+		{core.RGBA(0x11, 0x22, 0x33, 0x44), true, "#11223344"},
+		{core.RGBA(0x11, 0x22, 0x33, 0x44), false, "#112233"},
+	}
+	for _, tt := range tests {
+		if got := formatHexColor(tt.c, tt.alpha); got != tt.want {
+			t.Errorf("formatHexColor(%v, %v) = %q, want %q", tt.c, tt.alpha, got, tt.want)
+		}
+	}
+}
+
+func TestHSVRoundTrip(t *testing.T) {
+	for _, c := range []core.Color{
+		core.RGB(255, 0, 0), core.RGB(0, 255, 0), core.RGB(0, 0, 255),
+		core.RGB(255, 255, 0), core.RGB(0, 255, 255), core.RGB(255, 0, 255),
+		core.RGB(18, 52, 86), core.RGB(128, 128, 128), core.RGB(0, 0, 0),
+	} {
+		h, s, v := rgbToHSV(c.R, c.G, c.B)
+		r, g, b := hsvToRGB(h, s, v)
+		if got := (core.Color{R: r, G: g, B: b, A: 1}); !sameColor(got, c) {
+			t.Errorf("%v went through HSV %v, %v, %v and came back as %v", c, h, s, v, got)
+		}
+	}
+}
+
+func TestColorPickerKeepsHue(t *testing.T) {
+	p, ctx := newColorPicker(core.RGB(0, 255, 0))
+	p.SetValue(core.RGB(128, 128, 128))
+	p.SetValue(core.RGB(0, 0, 0))
+	r := p.square
+	p.HandleEvent(ctx, leftMouse(event.MouseDown, r.Center()))
+	p.HandleEvent(ctx, leftMouse(event.MouseMove, core.Pt(r.Right()+10, r.Y-10)))
+	if got := p.Value(); !sameColor(got, core.RGB(0, 255, 0)) {
+		t.Errorf("Value() = %v after passing through grays, want green again", got)
+	}
+}
+
+func TestColorPickerDrag(t *testing.T) {
+	tests := []struct {
+		name  string
+		strip func(p *ColorPicker) core.Rect
+		check func(c core.Color) bool
+	}{
+		{"square", func(p *ColorPicker) core.Rect { return p.square }, func(c core.Color) bool {
+			return sameColor(c, core.RGB(0, 0, 0))
+		}},
+		{"hue", func(p *ColorPicker) core.Rect { return p.hue }, func(c core.Color) bool {
+			r, g, b, _ := c.RGBA8()
+			return r == 0 && g == 255 && b == 255
+		}},
+		{"alpha", func(p *ColorPicker) core.Rect { return p.alpha }, func(c core.Color) bool {
+			_, _, _, a := c.RGBA8()
+			return a == 128
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, ctx := newColorPicker(core.RGB(255, 0, 0))
+			var changes []core.Color
+			p.OnChange(func(c core.Color) { changes = append(changes, c) })
+			r := tt.strip(p)
+			start := core.Pt(r.X, r.Center().Y)
+			p.HandleEvent(ctx, leftMouse(event.MouseDown, start))
+			if ctx.PointerCapture() != p {
+				t.Fatal("the picker did not capture the pointer")
+			}
+			end := r.Center()
+			if tt.name == "square" {
+				end = core.Pt(r.X, r.Bottom()+50) // Clamped to the square.
+			}
+			p.HandleEvent(ctx, leftMouse(event.MouseMove, end))
+			if len(RecentColors(ctx)) != 0 {
+				t.Error("a color was remembered while dragging")
+			}
+			p.HandleEvent(ctx, leftMouse(event.MouseUp, end))
+			if !tt.check(p.Value()) {
+				t.Errorf("Value() = %v", p.Value())
+			}
+			if len(changes) != 2 || !sameColor(changes[1], p.Value()) {
+				t.Errorf("OnChange got %v", changes)
+			}
+			if got := RecentColors(ctx); len(got) != 1 || !sameColor(got[0], p.Value()) || ctx.PointerCapture() != nil {
+				t.Errorf("recent colors %v after the drag ended", got)
+			}
+			ctx.LayoutRoot(p, p.Bounds())
+			if want := formatHexColor(p.Value(), true); p.hex.line.Text() != want {
+				t.Errorf("hex field shows %q, want %q", p.hex.line.Text(), want)
+			}
+		})
+	}
+}
+
+func TestColorPickerFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   func(p *ColorPicker) *lineField
+		keys    func(ctx *core.Context, f *lineField)
+		want    core.Color
+		invalid bool
+	}{
+		{"hex", func(p *ColorPicker) *lineField { return p.hex }, func(ctx *core.Context, f *lineField) {
+			typeText(ctx, f, "#00ff00")
+		}, core.RGB(0, 255, 0), false},
+		{"bad hex", func(p *ColorPicker) *lineField { return p.hex }, func(ctx *core.Context, f *lineField) {
+			typeText(ctx, f, "green")
+		}, core.RGB(255, 0, 0), true},
+		{"blue", func(p *ColorPicker) *lineField { return p.fields[2] }, func(ctx *core.Context, f *lineField) {
+			typeText(ctx, f, "128")
+		}, core.RGB(255, 0, 128), false},
+		{"out of range", func(p *ColorPicker) *lineField { return p.fields[1] }, func(ctx *core.Context, f *lineField) {
+			typeText(ctx, f, "256")
+		}, core.RGB(255, 0, 0), true},
+		{"step", func(p *ColorPicker) *lineField { return p.fields[1] }, func(ctx *core.Context, f *lineField) {
+			f.HandleEvent(ctx, press(event.KeyUp, 0))
+			f.HandleEvent(ctx, press(event.KeyUp, 0))
+		}, core.RGB(255, 2, 0), false},
+		{"step clamped", func(p *ColorPicker) *lineField { return p.fields[0] }, func(ctx *core.Context, f *lineField) {
+			f.HandleEvent(ctx, press(event.KeyUp, 0))
+		}, core.RGB(255, 0, 0), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, ctx := newColorPicker(core.RGB(255, 0, 0))
+			f := tt.field(p)
+			f.HandleEvent(ctx, leftMouse(event.MouseDown, f.Bounds().Center()))
+			if !f.IsFocused() {
+				t.Fatal("pressing the field did not focus it")
+			}
+			tt.keys(ctx, f)
+			f.HandleEvent(ctx, press(event.KeyEnter, 0))
+			if !sameColor(p.Value(), tt.want) || f.invalid != tt.invalid {
+				t.Errorf("Value() = %v, invalid %v, want %v, %v", p.Value(), f.invalid, tt.want, tt.invalid)
+			}
+			if !tt.invalid && !sameColor(RecentColors(ctx)[0], tt.want) {
+				t.Errorf("recent colors %v", RecentColors(ctx))
+			}
+			if tt.invalid {
+				f.HandleEvent(ctx, press(event.KeyEscape, 0))
+				ctx.LayoutRoot(p, p.Bounds())
+				want := "0"
+				if f == p.hex {
+					want = "#FF0000"
+				}
+				if f.invalid || f.line.Text() != want {
+					t.Errorf("Escape left %q, want %q", f.line.Text(), want)
+				}
+			}
+		})
+	}
+}
+
+func TestColorPickerWithoutAlpha(t *testing.T) {
+	p := NewColorPicker(core.RGBA(255, 0, 0, 64)).ShowAlpha(false)
+	ctx := layoutAt(p, core.R(0, 0, colorPickerWidth, 400))
+	if _, _, _, a := p.Value().RGBA8(); a != 255 {
+		t.Errorf("alpha %d with the alpha strip hidden", a)
+	}
+	if n := len(p.Children()); n != 4 {
+		t.Errorf("%d fields, want hex and three channels", n)
+	}
+	if p.hex.line.Text() != "#FF0000" {
+		t.Errorf("hex field shows %q", p.hex.line.Text())
+	}
+	if !p.commitHex(ctx, "#00000080") {
+		t.Fatal("the hex field rejected a color with alpha")
+	}
+	if _, _, _, a := p.Value().RGBA8(); a != 255 {
+		t.Errorf("typed alpha %d was kept", a)
+	}
+}
+
+func TestColorPickerRecentColors(t *testing.T) {
+	ctx := core.NewContext()
+	for i := range colorRecentLimit + 2 {
+		addRecentColor(ctx, core.RGB(uint8(i), 0, 0))
+	}
+	addRecentColor(ctx, core.RGB(5, 0, 0))
+	got := slices.Clone(RecentColors(ctx))
+	if len(got) != colorRecentLimit {
+		t.Fatalf("%d recent colors, want %d", len(got), colorRecentLimit)
+	}
+	if !sameColor(got[0], core.RGB(5, 0, 0)) || !sameColor(got[1], core.RGB(11, 0, 0)) {
+		t.Errorf("recent colors start with %v, %v", got[0], got[1])
+	}
+	for _, c := range got[1:] {
+		if sameColor(c, got[0]) {
+			t.Error("a color is listed twice")
+		}
+	}
+
+	p := NewColorPicker(core.RGB(0, 0, 255))
+	picked := core.Color{}
+	p.OnChange(func(c core.Color) { picked = c })
+	ctx.LayoutRoot(p, core.R(0, 0, colorPickerWidth, 400))
+	if len(p.swatches) == 0 {
+		t.Fatal("no swatches shown")
+	}
+	p.HandleEvent(ctx, leftMouse(event.MouseDown, p.swatches[2].Center()))
+	if !sameColor(picked, got[2]) {
+		t.Errorf("picked %v from the swatch of %v", picked, got[2])
+	}
+}
+
+func TestColorPickerEyeDropper(t *testing.T) {
+	tests := []struct {
+		name string
+		ok   bool
+		want core.Color
+	}{
+		{"picked", true, core.RGB(10, 20, 30)},
+		{"cancelled", false, core.RGB(255, 0, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewColorPicker(core.RGB(255, 0, 0))
+			ctx := core.NewContext()
+			d := &dropper{}
+			SetEyeDropper(ctx, d)
+			ctx.LayoutRoot(p, core.R(0, 0, colorPickerWidth, 400))
+			if !p.hasDropper || p.hue.Right() >= p.dropper.X {
+				t.Fatalf("dropper at %v beside the hue strip %v", p.dropper, p.hue)
+			}
+			p.HandleEvent(ctx, leftMouse(event.MouseDown, p.dropper.Center()))
+			if d.done == nil {
+				t.Fatal("the eyedropper was not started")
+			}
+			go d.done(tt.want, tt.ok)
+			runPosted(t, ctx)
+			if !sameColor(p.Value(), tt.want) {
+				t.Errorf("Value() = %v, want %v", p.Value(), tt.want)
+			}
+		})
+	}
+
+	p, _ := newColorPicker(core.RGB(255, 0, 0))
+	if p.hasDropper {
+		t.Error("the eyedropper button is shown without an eyedropper")
+	}
+}
@@ -0,0 +1,429 @@
+package widgets
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/internal/textedit"
+	"github.com/gogpu/ui/state"
+	"github.com/gogpu/ui/theme"
+)
+
+// ComboBoxDebounce is how long a ComboBox waits after the last keystroke
+// before asking its SuggestionProvider, unless set with Debounce.
+const ComboBoxDebounce = 250 * time.Millisecond
+
+// SuggestionProvider looks up the suggestions for query and passes them to
+// done. It is called on the UI goroutine and may call done later from any
+// goroutine, for example after a network request. Results for a query the
+// user has since changed are discarded.
+type SuggestionProvider func(query string, done func(suggestions []string))
+
+// ComboBox is a text field with a drop-down list of suggestions that is
+// filtered as the user types.
+//
+// The suggestions come from a fixed list of options, matched by
+// case-insensitive substring with prefix matches first, or from an
+// asynchronous SuggestionProvider queried once typing pauses. Up and Down
+// move through the list, Enter picks the highlighted suggestion and Escape
+// closes the list. By default any text is accepted; a restricted ComboBox
+// only accepts one of its suggestions and otherwise reverts to its previous
+// value when the text is committed.
+type ComboBox struct {
+	core.WidgetBase
+	core.FocusState
+
+	line       textedit.Line
+	width      float32
+	options    []string
+	provider   SuggestionProvider
+	debounce   time.Duration
+	restricted bool
+	value      string
+	dirty      bool
+	onChange   func(string)
+
+	list    *optionList
+	popup   *core.Overlay
+	button  core.Rect
+	query   int // generation of the latest query
+	loading bool
+
+	sig    *state.Signal[string]
+	cancel func()
+}
+
+// NewComboBox returns an empty ComboBox suggesting from options.
+func NewComboBox(options ...string) *ComboBox {
+	c := &ComboBox{options: options, width: textFieldWidth, debounce: ComboBoxDebounce}
+	c.list = newOptionList(func(ctx *core.Context, i int) { c.accept(ctx, c.list.items[i]) })
+	return c
+}
+
+// Options replaces the fixed list of options.
+func (c *ComboBox) Options(options ...string) *ComboBox {
+	c.options = options
+	return c
+}
+
+// Provider makes the ComboBox ask p for suggestions instead of filtering
+// its options.
+func (c *ComboBox) Provider(p SuggestionProvider) *ComboBox {
+	c.provider = p
+	return c
+}
+
+// Debounce sets how long to wait after typing before querying the
+// provider.
+func (c *ComboBox) Debounce(d time.Duration) *ComboBox {
+	c.debounce = max(0, d)
+	return c
+}
+
+// Restricted only accepts values from the suggestions.
+func (c *ComboBox) Restricted(on bool) *ComboBox {
+	c.restricted = on
+	return c
+}
+
+// Placeholder sets the hint shown while the field is empty.
+func (c *ComboBox) Placeholder(s string) *ComboBox {
+	c.line.Placeholder = s
+	return c
+}
+
+// Width sets the field's width.
+func (c *ComboBox) Width(w float32) *ComboBox {
+	c.width = w
+	return c
+}
+
+// Bind keeps the value in sync with sig in both directions.
+func (c *ComboBox) Bind(sig *state.Signal[string]) *ComboBox {
+	c.Release()
+	c.sig = sig
+	return c
+}
+
+// Release stops following the bound signal, for when the combo box
+// leaves the tree. Laid out again, the combo box follows the signal from
+// its value then.
+func (c *ComboBox) Release() {
+	if c.cancel != nil {
+		c.cancel()
+		c.cancel = nil
+	}
+}
+
+// OnChange registers fn to be called when the user commits a new value.
+func (c *ComboBox) OnChange(fn func(string)) *ComboBox {
+	c.onChange = fn
+	return c
+}
+
+// Value returns the committed value.
+func (c *ComboBox) Value() string {
+	return c.value
+}
+
+// SetValue sets the value without calling OnChange.
+func (c *ComboBox) SetValue(s string) {
+	c.value = s
+	c.line.SetText(s)
+	c.dirty = false
+	if c.sig != nil {
+		c.sig.Set(s)
+	}
+}
+
+// filter returns the options matching query.
+func (c *ComboBox) filter(query string) []string {
+	q := strings.ToLower(strings.TrimSpace(query))
+	var prefix, contains []string
+	for _, o := range c.options {
+		lo := strings.ToLower(o)
+		switch {
+		case strings.HasPrefix(lo, q):
+			prefix = append(prefix, o)
+		case strings.Contains(lo, q):
+			contains = append(contains, o)
+		}
+	}
+	return append(prefix, contains...)
+}
+
+// suggest updates the suggestions for the current text, at once for fixed
+// options and after the debounce delay for a provider.
+func (c *ComboBox) suggest(ctx *core.Context) {
+	query := c.line.Text()
+	c.query++
+	gen := c.query
+	if c.provider == nil {
+		c.show(ctx, c.filter(query))
+		return
+	}
+	ask := func() {
+		if gen != c.query {
+			return
+		}
+		c.loading = true
+		c.list.message = "Loading…"
+		if !c.isOpen() {
+			c.show(ctx, nil)
+		}
+		c.provider(query, func(s []string) {
+			ctx.Post(func() {
+				if gen != c.query {
+					return
+				}
+				c.loading = false
+				c.show(ctx, s)
+			})
+		})
+	}
+	if c.debounce == 0 {
+		ask()
+		return
+	}
+	time.AfterFunc(c.debounce, func() { ctx.Post(ask) })
+}
+
+func (c *ComboBox) isOpen() bool {
+	return c.popup != nil && c.popup.IsOpen()
+}
+
+// show opens the list with items, or refreshes it if it is open.
+func (c *ComboBox) show(ctx *core.Context, items []string) {
+	c.list.setItems(items)
+	if !c.loading {
+		c.list.message = "No matches"
+	}
+	ctx.MarkNeedsLayout(c.list)
+	if c.isOpen() {
+		return
+	}
+	c.popup = &core.Overlay{
+		Content:      newPopupFrame(c.list),
+		Placement:    core.PlaceAnchored(c.Bounds(), core.SideBelow),
+		LightDismiss: true,
+		Popup:        true,
+		Owner:        c,
+	}
+	ctx.ShowOverlay(c.popup)
+}
+
+func (c *ComboBox) close(ctx *core.Context) {
+	c.query++ // Drop pending results.
+	c.loading = false
+	if c.isOpen() {
+		ctx.CloseOverlay(c.popup)
+	}
+}
+
+// commit accepts the typed text as the value.
+func (c *ComboBox) commit(ctx *core.Context) {
+	if !c.dirty {
+		return
+	}
+	text := c.line.Text()
+	if c.restricted {
+		i := slices.IndexFunc(c.known(), func(s string) bool { return strings.EqualFold(s, strings.TrimSpace(text)) })
+		if i < 0 {
+			c.line.SetText(c.value)
+			c.dirty = false
+			ctx.MarkNeedsLayout(c)
+			return
+		}
+		text = c.known()[i]
+	}
+	c.setValue(ctx, text)
+}
+
+// known returns the values a restricted ComboBox accepts.
+func (c *ComboBox) known() []string {
+	if c.provider != nil {
+		return c.list.items
+	}
+	return c.options
+}
+
+func (c *ComboBox) setValue(ctx *core.Context, s string) {
+	changed := s != c.value
+	c.SetValue(s)
+	ctx.MarkNeedsLayout(c)
+	if changed && c.onChange != nil {
+		c.onChange(s)
+	}
+}
+
+// Layout implements core.Widget.
+func (c *ComboBox) Layout(ctx *core.LayoutContext) core.Size {
+	if c.sig != nil {
+		if c.cancel == nil {
+			cc := ctx.Context
+			c.cancel = c.sig.Subscribe(func(string) { cc.Post(func() { cc.MarkNeedsLayout(c) }) })
+		}
+		if v := c.sig.Get(); v != c.value && !c.dirty {
+			c.SetValue(v)
+		}
+	}
+	if !c.IsFocused() {
+		if c.isOpen() {
+			ctx.Post(func() { c.close(ctx.Context) })
+		} else if c.dirty {
+			// Focus has left the field since the text was edited.
+			c.commit(ctx.Context)
+		}
+	}
+	c.line.Style = theme.From(ctx.Context).Typography.Body
+	return ctx.Constraints.Constrain(core.Sz(c.width, fieldHeight))
+}
+
+// SetBounds implements core.Widget.
+func (c *ComboBox) SetBounds(r core.Rect) {
+	c.WidgetBase.SetBounds(r)
+	c.button = core.R(r.Right()-fieldButton, r.Y, fieldButton, r.Height)
+	c.line.Rect = core.R(r.X+fieldPadding, r.Y, max(0, r.Width-2*fieldPadding-fieldButton), r.Height)
+	c.list.minWidth = r.Width - 2*popupPadding
+}
+
+// Paint implements core.Widget.
+func (c *ComboBox) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	paintField(ctx, c.Bounds(), c.IsFocused() || c.isOpen(), false)
+	c.line.Paint(ctx, c.IsFocused())
+	paintChevronDown(ctx, c.button, th.Colors.OnSurfaceVariant)
+}
+
+// HandleEvent implements core.Widget.
+func (c *ComboBox) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	switch e := ev.(type) {
+	case event.MouseEvent:
+		if e.Type == event.MouseDown && e.Button == event.ButtonLeft {
+			ctx.RequestFocus(c)
+			if c.button.Contains(e.Position) {
+				if c.isOpen() {
+					c.close(ctx)
+				} else {
+					c.openAll(ctx)
+				}
+				return core.Handled
+			}
+		}
+		if c.line.HandleEvent(ctx, c, ev) != textedit.Ignored {
+			return core.Handled
+		}
+	case event.KeyEvent:
+		if !c.IsFocused() || e.Type != event.KeyPress {
+			return core.Ignored
+		}
+		open := c.isOpen()
+		switch {
+		case e.Key == event.KeyDown && e.Modifiers == event.ModAlt, e.Key == event.KeyF4 && e.Modifiers == 0:
+			if open {
+				c.close(ctx)
+			} else {
+				c.openAll(ctx)
+			}
+			return core.Handled
+		case (e.Key == event.KeyDown || e.Key == event.KeyUp) && e.Modifiers == 0:
+			if !open {
+				c.openAll(ctx)
+				return core.Handled
+			}
+			if e.Key == event.KeyDown {
+				c.list.move(1)
+			} else {
+				c.list.move(-1)
+			}
+			ctx.Repaint(c.list)
+			return core.Handled
+		case (e.Key == event.KeyPageDown || e.Key == event.KeyPageUp) && open:
+			if e.Key == event.KeyPageDown {
+				c.list.move(optionRows)
+			} else {
+				c.list.move(-optionRows)
+			}
+			ctx.Repaint(c.list)
+			return core.Handled
+		case e.Key == event.KeyEnter:
+			if open && c.list.highlight >= 0 && c.list.highlight < len(c.list.items) {
+				c.accept(ctx, c.list.items[c.list.highlight])
+				return core.Handled
+			}
+			c.close(ctx)
+			c.commit(ctx)
+			return core.Handled
+		case e.Key == event.KeyEscape:
+			if open {
+				c.close(ctx)
+				return core.Handled
+			}
+			if c.dirty {
+				c.line.SetText(c.value)
+				c.dirty = false
+				ctx.MarkNeedsLayout(c)
+				return core.Handled
+			}
+			return core.Ignored
+		case e.Key == event.KeyTab:
+			c.close(ctx)
+			c.commit(ctx)
+			return core.Ignored
+		}
+		if r := c.line.HandleEvent(ctx, c, ev); r != textedit.Ignored {
+			if r == textedit.Changed {
+				c.dirty = true
+				c.suggest(ctx)
+			}
+			return core.Handled
+		}
+	case event.TextEvent:
+		if !c.IsFocused() {
+			return core.Ignored
+		}
+		if c.line.HandleEvent(ctx, c, ev) == textedit.Changed {
+			c.dirty = true
+			c.suggest(ctx)
+		}
+		return core.Handled
+	}
+	return core.Ignored
+}
+
+// openAll opens the list with every option, or the provider's suggestions
+// for the current text.
+func (c *ComboBox) openAll(ctx *core.Context) {
+	if c.provider != nil {
+		c.query++
+		gen := c.query
+		c.loading = true
+		c.list.message = "Loading…"
+		c.show(ctx, nil)
+		c.provider(c.line.Text(), func(s []string) {
+			ctx.Post(func() {
+				if gen == c.query {
+					c.loading = false
+					c.show(ctx, s)
+				}
+			})
+		})
+		return
+	}
+	c.show(ctx, c.options)
+	if i := slices.Index(c.options, c.value); i >= 0 {
+		c.list.highlight = i
+		c.list.scrollToHighlight()
+	}
+}
+
+// accept takes s from the list as the new value.
+func (c *ComboBox) accept(ctx *core.Context, s string) {
+	c.close(ctx)
+	c.line.SetText(s)
+	c.dirty = true
+	c.commit(ctx)
+}
@@ -0,0 +1,250 @@
+package widgets
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/state"
+)
+
+var fruits = []string{"Apple", "Banana", "Cherry", "Pineapple", "Grape"}
+
+// newComboBox returns a focused ComboBox laid out in a 200 by 32 box.
+func newComboBox(c *ComboBox) *core.Context {
+	ctx := layoutAt(c, core.R(0, 0, 200, 32))
+	ctx.RequestFocus(c)
+	return ctx
+}
+
+// typeInto types s into c one character at a time.
+func typeInto(ctx *core.Context, c *ComboBox, s string) {
+	for _, r := range s {
+		c.HandleEvent(ctx, event.TextEvent{Text: string(r)})
+	}
+}
+
+func TestComboBoxFilter(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", fruits},
+		{"ap", []string{"Apple", "Pineapple", "Grape"}},
+		{"APP", []string{"Apple", "Pineapple"}},
+		{" ch ", []string{"Cherry"}},
+		{"kiwi", nil},
+	}
+	c := NewComboBox(fruits...)
+	for _, tt := range tests {
+		if got := c.filter(tt.query); !slices.Equal(got, tt.want) {
+			t.Errorf("filter(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestComboBoxTyping(t *testing.T) {
+	c := NewComboBox(fruits...)
+	var changes []string
+	c.OnChange(func(s string) { changes = append(changes, s) })
+	ctx := newComboBox(c)
+	typeInto(ctx, c, "ap")
+	if !c.isOpen() || !slices.Equal(c.list.items, []string{"Apple", "Pineapple", "Grape"}) {
+		t.Fatalf("open %v with %v after typing", c.isOpen(), c.list.items)
+	}
+	c.HandleEvent(ctx, press(event.KeyDown, 0))
+	c.HandleEvent(ctx, press(event.KeyEnter, 0))
+	if c.Value() != "Pineapple" || c.line.Text() != "Pineapple" || c.isOpen() {
+		t.Errorf("Value() = %q, open %v after picking", c.Value(), c.isOpen())
+	}
+	typeInto(ctx, c, "x")
+	if c.list.message != "No matches" || len(c.list.items) != 0 {
+		t.Errorf("list shows %v, %q for no matches", c.list.items, c.list.message)
+	}
+	c.HandleEvent(ctx, press(event.KeyEscape, 0))
+	if c.isOpen() || c.line.Text() != "Pineapplex" {
+		t.Error("the first Escape should only close the list")
+	}
+	c.HandleEvent(ctx, press(event.KeyEscape, 0))
+	if c.line.Text() != "Pineapple" {
+		t.Errorf("the second Escape left %q", c.line.Text())
+	}
+	if r := c.HandleEvent(ctx, press(event.KeyEscape, 0)); r != core.Ignored {
+		t.Error("a third Escape was not passed on")
+	}
+	typeInto(ctx, c, "!")
+	c.HandleEvent(ctx, press(event.KeyTab, 0))
+	if c.Value() != "Pineapple!" || !slices.Equal(changes, []string{"Pineapple", "Pineapple!"}) {
+		t.Errorf("Value() = %q with changes %v after Tab", c.Value(), changes)
+	}
+}
+
+func TestComboBoxRestricted(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"cherry", "Cherry"},
+		{" grape ", "Grape"},
+		{"kiwi", "Apple"},
+	}
+	for _, tt := range tests {
+		c := NewComboBox(fruits...).Restricted(true)
+		c.SetValue("Apple")
+		ctx := newComboBox(c)
+		c.HandleEvent(ctx, press(event.KeyA, event.ModCtrl))
+		typeInto(ctx, c, tt.text)
+		c.HandleEvent(ctx, press(event.KeyEscape, 0)) // Close the list.
+		c.HandleEvent(ctx, press(event.KeyEnter, 0))
+		if c.Value() != tt.want || c.line.Text() != tt.want {
+			t.Errorf("typing %q gave %q showing %q, want %q", tt.text, c.Value(), c.line.Text(), tt.want)
+		}
+	}
+}
+
+func TestComboBoxOpenAll(t *testing.T) {
+	tests := []struct {
+		name string
+		open func(ctx *core.Context, c *ComboBox)
+	}{
+		{"button", func(ctx *core.Context, c *ComboBox) {
+			c.HandleEvent(ctx, leftMouse(event.MouseDown, c.button.Center()))
+		}},
+		{"F4", func(ctx *core.Context, c *ComboBox) { c.HandleEvent(ctx, press(event.KeyF4, 0)) }},
+		{"Alt+Down", func(ctx *core.Context, c *ComboBox) { c.HandleEvent(ctx, press(event.KeyDown, event.ModAlt)) }},
+		{"Down", func(ctx *core.Context, c *ComboBox) { c.HandleEvent(ctx, press(event.KeyDown, 0)) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewComboBox(fruits...)
+			c.SetValue("Cherry")
+			ctx := newComboBox(c)
+			tt.open(ctx, c)
+			if !c.isOpen() || len(c.list.items) != len(fruits) || c.list.highlight != 2 {
+				t.Fatalf("open %v with %d items, highlight %d", c.isOpen(), len(c.list.items), c.list.highlight)
+			}
+			layoutOverlays(ctx)
+			if c.list.Bounds().Y < c.Bounds().Bottom() {
+				t.Errorf("list at %v, want below the field", c.list.Bounds())
+			}
+			c.list.HandleEvent(ctx, leftMouse(event.MouseDown, core.Pt(c.list.Bounds().X+10, c.list.Bounds().Y+5)))
+			if c.Value() != "Apple" || c.isOpen() {
+				t.Errorf("clicking the first item gave %q", c.Value())
+			}
+		})
+	}
+
+	c := NewComboBox(fruits...)
+	ctx := newComboBox(c)
+	c.HandleEvent(ctx, press(event.KeyF4, 0))
+	c.HandleEvent(ctx, press(event.KeyF4, 0))
+	if c.isOpen() {
+		t.Error("a second F4 did not close the list")
+	}
+	c.HandleEvent(ctx, press(event.KeyF4, 0))
+	ctx.RequestFocus(nil)
+	ctx.Invalidate()
+	ctx.LayoutRoot(c, c.Bounds())
+	ctx.RunPosted()
+	if c.isOpen() {
+		t.Error("the list stayed open after focus left")
+	}
+}
+
+// provider answers queries when the test releases them.
+type provider struct {
+	mu      sync.Mutex
+	queries []string
+	pending []func()
+}
+
+func (p *provider) suggest(query string, done func([]string)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.queries = append(p.queries, query)
+	p.pending = append(p.pending, func() { done([]string{query + " 1", query + " 2"}) })
+}
+
+// answer answers query i from another goroutine.
+func (p *provider) answer(i int) {
+	p.mu.Lock()
+	fn := p.pending[i]
+	p.mu.Unlock()
+	go fn()
+}
+
+func TestComboBoxProvider(t *testing.T) {
+	p := &provider{}
+	c := NewComboBox().Provider(p.suggest).Debounce(0).Restricted(true)
+	ctx := newComboBox(c)
+	typeInto(ctx, c, "a")
+	if !c.isOpen() || c.list.message != "Loading…" {
+		t.Fatalf("open %v showing %q while loading", c.isOpen(), c.list.message)
+	}
+	typeInto(ctx, c, "b")
+	p.answer(0) // Stale.
+	runPosted(t, ctx)
+	if len(c.list.items) != 0 {
+		t.Fatalf("stale results shown: %v", c.list.items)
+	}
+	p.answer(1)
+	runPosted(t, ctx)
+	if !slices.Equal(c.list.items, []string{"ab 1", "ab 2"}) {
+		t.Fatalf("list shows %v", c.list.items)
+	}
+	c.HandleEvent(ctx, press(event.KeyDown, 0))
+	c.HandleEvent(ctx, press(event.KeyEnter, 0))
+	if c.Value() != "ab 2" {
+		t.Errorf("Value() = %q", c.Value())
+	}
+
+	// Closing the list drops a pending answer.
+	c.HandleEvent(ctx, press(event.KeyF4, 0))
+	c.HandleEvent(ctx, press(event.KeyEscape, 0))
+	p.answer(2)
+	runPosted(t, ctx)
+	if c.isOpen() {
+		t.Error("a late answer opened the list again")
+	}
+}
+
+func TestComboBoxDebounce(t *testing.T) {
+	p := &provider{}
+	c := NewComboBox().Provider(p.suggest).Debounce(20 * time.Millisecond)
+	ctx := newComboBox(c)
+	typeInto(ctx, c, "abc")
+	if len(p.queries) != 0 {
+		t.Fatal("the provider was asked before typing paused")
+	}
+	deadline := time.Now().Add(time.Second)
+	for len(p.queries) == 0 && time.Now().Before(deadline) {
+		runPosted(t, ctx)
+	}
+	if !slices.Equal(p.queries, []string{"abc"}) {
+		t.Errorf("queries %v, want only the last text", p.queries)
+	}
+}
+
+func TestComboBoxBind(t *testing.T) {
+	sig := state.New("Banana")
+	c := NewComboBox(fruits...).Bind(sig)
+	ctx := newComboBox(c)
+	if c.Value() != "Banana" || c.line.Text() != "Banana" {
+		t.Fatalf("Value() = %q, want the signal's", c.Value())
+	}
+	c.HandleEvent(ctx, press(event.KeyDown, 0))
+	c.HandleEvent(ctx, press(event.KeyDown, 0))
+	c.HandleEvent(ctx, press(event.KeyEnter, 0))
+	if sig.Get() != "Cherry" {
+		t.Errorf("signal holds %q after picking", sig.Get())
+	}
+	sig.Set("Grape")
+	ctx.RunPosted()
+	ctx.LayoutRoot(c, c.Bounds())
+	if c.line.Text() != "Grape" {
+		t.Errorf("field shows %q after the signal changed", c.line.Text())
+	}
+}
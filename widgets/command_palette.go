@@ -0,0 +1,530 @@
+package widgets
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/internal/textedit"
+	"github.com/gogpu/ui/shortcuts"
+	"github.com/gogpu/ui/theme"
+)
+
+const (
+	paletteWidth  float32 = 560
+	paletteTop    float32 = 56 // distance from the top of the window
+	paletteField  float32 = 44
+	paletteRow    float32 = 36
+	paletteRows           = 10 // rows shown before the list scrolls
+	paletteRecent         = 8  // commands remembered as recently used
+)
+
+// Command is an action offered by a CommandPalette.
+type Command struct {
+	// ID identifies the command, for example to look up its key binding.
+	ID string
+
+	// Title is the text shown and searched, such as "Toggle Word Wrap".
+	Title string
+
+	// Category, if set, is shown before the title and searched with it,
+	// as in "View: Toggle Word Wrap".
+	Category string
+
+	// Accelerator is the key binding displayed next to the command, such
+	// as "Alt+Z". If empty, the palette's ShortcutLookup is asked.
+	Accelerator string
+
+	// Run performs the command, after the palette closed. If nil, the
+	// command ID names in the shortcut registry of the window runs.
+	Run func()
+}
+
+// NewCommand returns a command.
+func NewCommand(id, title string, run func()) *Command {
+	return &Command{ID: id, Title: title, Run: run}
+}
+
+// InCategory sets the category.
+func (c *Command) InCategory(category string) *Command {
+	c.Category = category
+	return c
+}
+
+// Shortcut sets the displayed key binding.
+func (c *Command) Shortcut(accel string) *Command {
+	c.Accelerator = accel
+	return c
+}
+
+func (c *Command) label() string {
+	if c.Category == "" {
+		return c.Title
+	}
+	return c.Category + ": " + c.Title
+}
+
+// CommandPalette is a search box over the application's commands, shown
+// at the top of the window in the style of Ctrl+Shift+P in code editors.
+//
+// Typing filters the commands by fuzzy matching: the typed characters must
+// appear in order, and matches at word starts and in runs rank higher.
+// Commands run recently are listed first while nothing is typed and get a
+// boost when something is. Up and Down move the highlight, Enter runs the
+// highlighted command and Escape or a click outside closes the palette.
+//
+// The palette is not part of the widget tree. Open shows it; its
+// HandleShortcut method opens it on its trigger key, by default
+// Ctrl+Shift+P or Super+Shift+P, when a widget of the tree forwards
+// unhandled keys to it.
+type CommandPalette struct {
+	commands    []*Command
+	recent      []*Command // Most recent first.
+	lookup      func(id string) string
+	placeholder string
+	key         event.Key
+	mods        []event.Modifiers
+
+	view    *paletteView
+	overlay *core.Overlay
+}
+
+// NewCommandPalette returns a palette offering commands.
+func NewCommandPalette(commands ...*Command) *CommandPalette {
+	return &CommandPalette{
+		commands:    commands,
+		placeholder: "Type a command",
+		key:         event.KeyP,
+		mods:        []event.Modifiers{event.ModCtrl | event.ModShift, event.ModSuper | event.ModShift},
+	}
+}
+
+// Add registers more commands.
+func (p *CommandPalette) Add(commands ...*Command) *CommandPalette {
+	p.commands = append(p.commands, commands...)
+	return p
+}
+
+// Remove unregisters the command with the given ID.
+func (p *CommandPalette) Remove(id string) {
+	match := func(c *Command) bool { return c.ID == id }
+	p.commands = slices.DeleteFunc(p.commands, match)
+	p.recent = slices.DeleteFunc(p.recent, match)
+}
+
+// Commands returns the registered commands.
+func (p *CommandPalette) Commands() []*Command {
+	return p.commands
+}
+
+// Recent returns the commands run from the palette, most recent first.
+func (p *CommandPalette) Recent() []*Command {
+	return p.recent
+}
+
+// ShortcutLookup sets the function asked for the key binding of commands
+// without an Accelerator, which returns the empty string for unbound
+// commands. By default the shortcut registry of the window is asked, as
+// shortcuts.Label does.
+func (p *CommandPalette) ShortcutLookup(fn func(id string) string) *CommandPalette {
+	p.lookup = fn
+	return p
+}
+
+// Placeholder sets the hint shown in the empty search box.
+func (p *CommandPalette) Placeholder(s string) *CommandPalette {
+	p.placeholder = s
+	return p
+}
+
+// Trigger sets the key that opens the palette in HandleShortcut, with any
+// of the given modifier combinations.
+func (p *CommandPalette) Trigger(key event.Key, mods ...event.Modifiers) *CommandPalette {
+	p.key, p.mods = key, mods
+	return p
+}
+
+// IsOpen reports whether the palette is shown.
+func (p *CommandPalette) IsOpen() bool {
+	return p.overlay != nil && p.overlay.IsOpen()
+}
+
+// Open shows the palette with an empty search box.
+func (p *CommandPalette) Open(ctx *core.Context) {
+	if p.IsOpen() {
+		ctx.RequestFocus(p.view)
+		return
+	}
+	if p.view == nil {
+		p.view = &paletteView{palette: p}
+	}
+	v := p.view
+	v.line.SetText("")
+	v.line.Placeholder = p.placeholder
+	v.query = "\x00" // Filter on the first layout.
+	v.hover = -1
+	p.overlay = &core.Overlay{
+		Content: v,
+		Placement: func(s core.Size, area core.Rect) core.Point {
+			return core.Pt(area.X+(area.Width-s.Width)/2, area.Y+min(paletteTop, max(0, area.Height-s.Height)))
+		},
+		LightDismiss: true,
+	}
+	ctx.ShowOverlay(p.overlay)
+	ctx.RequestFocus(v)
+}
+
+// Close hides the palette.
+func (p *CommandPalette) Close(ctx *core.Context) {
+	if p.IsOpen() {
+		ctx.CloseOverlay(p.overlay)
+	}
+}
+
+// HandleShortcut implements core.ShortcutHandler, opening the palette on
+// its trigger key.
+func (p *CommandPalette) HandleShortcut(ctx *core.Context, ev core.Event) core.EventResult {
+	e, ok := ev.(event.KeyEvent)
+	if !ok || e.Type != event.KeyPress || e.Key != p.key || !slices.Contains(p.mods, e.Modifiers) {
+		return core.Ignored
+	}
+	p.Open(ctx)
+	return core.Handled
+}
+
+// run closes the palette, records c as recently used and runs it.
+func (p *CommandPalette) run(ctx *core.Context, c *Command) {
+	p.Close(ctx)
+	p.recent = slices.DeleteFunc(p.recent, func(x *Command) bool { return x == c })
+	p.recent = slices.Insert(p.recent, 0, c)
+	if len(p.recent) > paletteRecent {
+		p.recent = p.recent[:paletteRecent]
+	}
+	if c.Run != nil {
+		c.Run()
+	} else {
+		shortcuts.Of(ctx).Run(c.ID)
+	}
+}
+
+func (p *CommandPalette) accelerator(ctx *core.Context, c *Command) string {
+	switch {
+	case c.Accelerator != "":
+		return c.Accelerator
+	case p.lookup != nil:
+		return p.lookup(c.ID)
+	}
+	return shortcuts.Label(ctx, c.ID)
+}
+
+// paletteMatch is a command found by the search, with the rune indexes of
+// its label that matched.
+type paletteMatch struct {
+	cmd   *Command
+	score int
+	hits  []int
+}
+
+// filter returns the commands matching query, best first.
+func (p *CommandPalette) filter(query string) []paletteMatch {
+	var out []paletteMatch
+	if strings.TrimSpace(query) == "" {
+		for _, c := range p.recent {
+			out = append(out, paletteMatch{cmd: c})
+		}
+		for _, c := range p.commands {
+			if !slices.Contains(p.recent, c) {
+				out = append(out, paletteMatch{cmd: c})
+			}
+		}
+		return out
+	}
+	for _, c := range p.commands {
+		score, hits, ok := fuzzyMatch(query, c.label())
+		if !ok {
+			continue
+		}
+		if i := slices.Index(p.recent, c); i >= 0 {
+			score += 3 * (paletteRecent - i)
+		}
+		out = append(out, paletteMatch{cmd: c, score: score, hits: hits})
+	}
+	slices.SortStableFunc(out, func(a, b paletteMatch) int { return b.score - a.score })
+	return out
+}
+
+// fuzzyMatch reports whether the letters of query, ignoring case and
+// spaces, appear in order in label. The score favors a contiguous match,
+// matches at word starts and runs of adjacent matches. hits are the rune
+// indexes of label that matched.
+func fuzzyMatch(query, label string) (score int, hits []int, ok bool) {
+	var q []rune
+	for _, r := range strings.ToLower(query) {
+		if !unicode.IsSpace(r) {
+			q = append(q, r)
+		}
+	}
+	if len(q) == 0 {
+		return 0, nil, true
+	}
+	l := []rune(label)
+	lower := []rune(strings.ToLower(label))
+	if len(lower) != len(l) {
+		lower = l // Case mapping changed the length; match case-sensitively.
+	}
+	wordStart := func(i int) bool {
+		if i == 0 {
+			return true
+		}
+		prev := l[i-1]
+		return !unicode.IsLetter(prev) && !unicode.IsDigit(prev) || unicode.IsLower(prev) && unicode.IsUpper(l[i])
+	}
+
+	j, last := 0, -2
+	for i := 0; i < len(lower) && j < len(q); i++ {
+		if lower[i] != q[j] {
+			continue
+		}
+		score++
+		if wordStart(i) {
+			score += 8
+		}
+		if i == last+1 {
+			score += 5
+		} else if last >= 0 {
+			score -= min(i-last-1, 5)
+		}
+		hits = append(hits, i)
+		last = i
+		j++
+	}
+	if j < len(q) {
+		return 0, nil, false
+	}
+	if at := indexRunes(lower, q); at >= 0 {
+		s := 10 * len(q)
+		if wordStart(at) {
+			s += 15
+		}
+		if s > score {
+			score = s
+			hits = hits[:0]
+			for k := range q {
+				hits = append(hits, at+k)
+			}
+		}
+	}
+	return score, hits, true
+}
+
+func indexRunes(s, sub []rune) int {
+	for i := 0; i+len(sub) <= len(s); i++ {
+		if slices.Equal(s[i:i+len(sub)], sub) {
+			return i
+		}
+	}
+	return -1
+}
+
+// paletteView is the palette's overlay: the search box above the list of
+// matches. It keeps focus while open.
+type paletteView struct {
+	core.WidgetBase
+	core.FocusState
+
+	palette   *CommandPalette
+	line      textedit.Line
+	query     string
+	results   []paletteMatch
+	highlight int
+	hover     int
+	first     int // First row shown.
+	rows      int // Rows shown.
+	list      core.Rect
+}
+
+func (v *paletteView) refilter() {
+	v.query = v.line.Text()
+	v.results = v.palette.filter(v.query)
+	v.highlight, v.first = 0, 0
+	if len(v.results) == 0 {
+		v.highlight = -1
+	}
+}
+
+func (v *paletteView) move(delta int) {
+	if len(v.results) == 0 {
+		return
+	}
+	v.highlight = min(max(v.highlight+delta, 0), len(v.results)-1)
+	if v.highlight < v.first {
+		v.first = v.highlight
+	} else if v.highlight >= v.first+v.rows {
+		v.first = v.highlight - v.rows + 1
+	}
+}
+
+func (v *paletteView) Layout(ctx *core.LayoutContext) core.Size {
+	if v.line.Text() != v.query {
+		v.refilter()
+	}
+	v.line.Style = theme.From(ctx.Context).Typography.Body
+	v.rows = max(1, min(len(v.results), paletteRows))
+	w := paletteWidth
+	if ctx.Constraints.HasBoundedWidth() {
+		w = min(w, ctx.Constraints.MaxWidth-32)
+	}
+	return ctx.Constraints.Constrain(core.Sz(w, paletteField+float32(v.rows)*paletteRow+popupPadding))
+}
+
+func (v *paletteView) SetBounds(r core.Rect) {
+	v.WidgetBase.SetBounds(r)
+	v.line.Rect = core.R(r.X+2*fieldPadding, r.Y+popupPadding, max(0, r.Width-4*fieldPadding), paletteField-2*popupPadding)
+	v.list = core.R(r.X, r.Y+paletteField, r.Width, float32(v.rows)*paletteRow)
+	v.first = min(v.first, max(0, len(v.results)-v.rows))
+}
+
+func (v *paletteView) rowAt(p core.Point) int {
+	if !v.list.Contains(p) {
+		return -1
+	}
+	i := v.first + int((p.Y-v.list.Y)/paletteRow)
+	if i >= len(v.results) {
+		return -1
+	}
+	return i
+}
+
+func (v *paletteView) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	r := v.Bounds()
+	core.DrawShadow(cv, r, th.Radii.Large, th.Elevation(theme.ElevationDialog)...)
+	cv.DrawRoundedRect(r, th.Radii.Large, core.RectStyle{
+		Fill:        th.Colors.Surface,
+		Stroke:      th.Colors.Outline.WithAlpha(0.4),
+		StrokeWidth: 1,
+	})
+	field := core.R(r.X+fieldPadding, r.Y+popupPadding*2, r.Width-2*fieldPadding, paletteField-popupPadding*3)
+	cv.DrawRoundedRect(field, th.Radii.Small, core.Stroked(th.Colors.Primary, 1))
+	v.line.Paint(ctx, v.IsFocused())
+
+	body := theme.TextStyle(th.Typography.Body, th.Colors.OnSurface)
+	ty := (paletteRow - body.LineHeight()) / 2
+	if len(v.results) == 0 {
+		cv.DrawText("No matching commands", core.Pt(v.list.X+2*fieldPadding, v.list.Y+ty), theme.TextStyle(th.Typography.Body, th.Colors.OnSurfaceVariant))
+		return
+	}
+	match := theme.TextStyle(th.Typography.Body, th.Colors.Primary)
+	match.Weight = core.WeightBold
+	accelStyle := theme.TextStyle(th.Typography.Body, th.Colors.OnSurfaceVariant)
+	cv.Save()
+	cv.Clip(v.list)
+	for row := 0; row < v.rows && v.first+row < len(v.results); row++ {
+		i := v.first + row
+		m := v.results[i]
+		rr := core.R(v.list.X+popupPadding, v.list.Y+float32(row)*paletteRow, v.list.Width-2*popupPadding, paletteRow)
+		switch {
+		case i == v.highlight:
+			cv.DrawRoundedRect(rr, th.Radii.Small, core.Filled(th.Colors.Primary.WithAlpha(0.12)))
+		case i == v.hover:
+			cv.DrawRoundedRect(rr, th.Radii.Small, core.Filled(th.Colors.OnSurface.WithAlpha(0.06)))
+		}
+		right := rr.Right() - fieldPadding
+		if accel := v.palette.accelerator(ctx.Context, m.cmd); accel != "" {
+			aw := ctx.MeasureText(accel, accelStyle).Width
+			right -= aw
+			cv.DrawText(accel, core.Pt(right, rr.Y+ty), accelStyle)
+			right -= 2 * fieldPadding
+		}
+		cv.Save()
+		cv.Clip(core.R(rr.X, rr.Y, max(0, right-rr.X), rr.Height))
+		// Draw the label in runs of matched and unmatched characters.
+		label := []rune(m.cmd.label())
+		x := rr.X + fieldPadding + popupPadding
+		for k := 0; k < len(label); {
+			hit := slices.Contains(m.hits, k)
+			end := k + 1
+			for end < len(label) && slices.Contains(m.hits, end) == hit {
+				end++
+			}
+			s := string(label[k:end])
+			style := body
+			if hit {
+				style = match
+			}
+			cv.DrawText(s, core.Pt(x, rr.Y+ty), style)
+			x += ctx.MeasureText(s, style).Width
+			k = end
+		}
+		cv.Restore()
+	}
+	cv.Restore()
+}
+
+func (v *paletteView) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	switch e := ev.(type) {
+	case event.MouseEvent:
+		switch e.Type {
+		case event.MouseEnter, event.MouseMove:
+			if i := v.rowAt(e.Position); i != v.hover {
+				v.hover = i
+				ctx.Repaint(v)
+			}
+		case event.MouseLeave:
+			v.hover = -1
+			ctx.Repaint(v)
+		case event.MouseDown:
+			if e.Button != event.ButtonLeft {
+				return core.Handled
+			}
+			if i := v.rowAt(e.Position); i >= 0 {
+				v.palette.run(ctx, v.results[i].cmd)
+				return core.Handled
+			}
+		}
+		v.line.HandleEvent(ctx, v, ev)
+		return core.Handled
+	case event.ScrollEvent:
+		step := 1
+		if e.Delta.Y < 0 {
+			step = -1
+		}
+		v.first = min(max(v.first+step, 0), max(0, len(v.results)-v.rows))
+		ctx.Repaint(v)
+		return core.Handled
+	case event.KeyEvent:
+		if e.Type != event.KeyPress {
+			return core.Ignored
+		}
+		switch {
+		case e.Key == event.KeyEscape:
+			v.palette.Close(ctx)
+		case e.Key == event.KeyEnter:
+			if v.highlight >= 0 {
+				v.palette.run(ctx, v.results[v.highlight].cmd)
+			}
+		case e.Key == event.KeyUp && e.Modifiers == 0:
+			v.move(-1)
+		case e.Key == event.KeyDown && e.Modifiers == 0:
+			v.move(1)
+		case e.Key == event.KeyPageUp:
+			v.move(-v.rows)
+		case e.Key == event.KeyPageDown:
+			v.move(v.rows)
+		default:
+			if v.line.HandleEvent(ctx, v, ev) == textedit.Ignored {
+				return core.Ignored
+			}
+		}
+		ctx.MarkNeedsLayout(v)
+		return core.Handled
+	case event.TextEvent:
+		v.line.HandleEvent(ctx, v, ev)
+		ctx.MarkNeedsLayout(v)
+		return core.Handled
+	}
+	return core.Ignored
+}
@@ -0,0 +1,362 @@
+package widgets
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/shortcuts"
+)
+
+// openPalette opens p in a new context and lays it out as the window does.
+func openPalette(p *CommandPalette) *core.Context {
+	ctx := core.NewContext()
+	p.Open(ctx)
+	layoutOverlays(ctx)
+	return ctx
+}
+
+// listed returns the titles of the commands the palette lists.
+func listed(v *paletteView) []string {
+	var out []string
+	for _, m := range v.results {
+		out = append(out, m.cmd.Title)
+	}
+	return out
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		query, label string
+		ok           bool
+		hits         []int
+	}{
+		{"tww", "View: Toggle Word Wrap", true, []int{6, 13, 18}},
+		{"word", "Toggle Word Wrap", true, []int{7, 8, 9, 10}},
+		{"WORD", "Toggle Word Wrap", true, []int{7, 8, 9, 10}},
+		{"t w w", "Toggle Word Wrap", true, []int{0, 7, 12}},
+		{"fb", "fooBar", true, []int{0, 3}},
+		{"wt", "Toggle Word Wrap", false, nil},
+		{"xyz", "Open File", false, nil},
+		{"  ", "Open File", true, nil},
+	}
+	for _, tt := range tests {
+		_, hits, ok := fuzzyMatch(tt.query, tt.label)
+		if ok != tt.ok || !slices.Equal(hits, tt.hits) {
+			t.Errorf("%q in %q: %v at %v, want %v at %v", tt.query, tt.label, ok, hits, tt.ok, tt.hits)
+		}
+	}
+
+	better := []struct {
+		query, first, second string
+	}{
+		{"open", "Open File", "Reopen Closed Editor"},
+		{"tw", "Toggle Word Wrap", "Settings Window"},
+		{"save", "Save", "Select All Values"},
+	}
+	for _, tt := range better {
+		a, _, _ := fuzzyMatch(tt.query, tt.first)
+		b, _, _ := fuzzyMatch(tt.query, tt.second)
+		if a <= b {
+			t.Errorf("%q scores %d in %q, %d in %q", tt.query, a, tt.first, b, tt.second)
+		}
+	}
+}
+
+func TestPaletteFilter(t *testing.T) {
+	open := NewCommand("open", "Open File", nil)
+	reopen := NewCommand("reopen", "Reopen Closed Editor", nil)
+	wrap := NewCommand("wrap", "Toggle Word Wrap", nil).InCategory("View")
+	p := NewCommandPalette(open, reopen, wrap)
+	ctx := core.NewContext()
+
+	tests := []struct {
+		name  string
+		query string
+		run   *Command
+		want  []string
+	}{
+		{"all", "", nil, []string{"Open File", "Reopen Closed Editor", "Toggle Word Wrap"}},
+		{"ranked", "open", nil, []string{"Open File", "Reopen Closed Editor"}},
+		{"category", "view", nil, []string{"Toggle Word Wrap"}},
+		{"none", "qq", nil, nil},
+		{"recent first", " ", wrap, []string{"Toggle Word Wrap", "Open File", "Reopen Closed Editor"}},
+		{"recent boost", "open", reopen, []string{"Reopen Closed Editor", "Open File"}},
+	}
+	for _, tt := range tests {
+		if tt.run != nil {
+			p.run(ctx, tt.run)
+		}
+		var got []string
+		for _, m := range p.filter(tt.query) {
+			got = append(got, m.cmd.Title)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: %q lists %q, want %q", tt.name, tt.query, got, tt.want)
+		}
+	}
+	if !slices.Equal(p.Recent(), []*Command{reopen, wrap}) {
+		t.Errorf("recent %v", p.Recent())
+	}
+
+	// Running a command again moves it to the front; the list is capped.
+	for i := range paletteRecent + 2 {
+		c := NewCommand(fmt.Sprint(i), fmt.Sprint("Command ", i), nil)
+		p.Add(c)
+		p.run(ctx, c)
+	}
+	p.run(ctx, p.Commands()[4])
+	if r := p.Recent(); len(r) != paletteRecent || r[0].Title != "Command 1" || slices.Contains(r, wrap) {
+		t.Errorf("recent %d commands, the first %q", len(r), r[0].Title)
+	}
+	p.Remove("1")
+	if slices.ContainsFunc(p.Commands(), func(c *Command) bool { return c.ID == "1" }) || p.Recent()[0].Title != "Command 9" {
+		t.Error("Remove kept the command")
+	}
+}
+
+func TestCommandPaletteOpen(t *testing.T) {
+	p := NewCommandPalette(NewCommand("a", "Alpha", nil))
+	ctx := core.NewContext()
+	tests := []struct {
+		name string
+		ev   core.Event
+		res  core.EventResult
+	}{
+		{"Ctrl+P", event.KeyEvent{Type: event.KeyPress, Key: event.KeyP, Modifiers: event.ModCtrl}, core.Ignored},
+		{"release", event.KeyEvent{Type: event.KeyRelease, Key: event.KeyP, Modifiers: event.ModCtrl | event.ModShift}, core.Ignored},
+		{"text", event.TextEvent{Text: "p"}, core.Ignored},
+		{"Ctrl+Shift+P", event.KeyEvent{Type: event.KeyPress, Key: event.KeyP, Modifiers: event.ModCtrl | event.ModShift}, core.Handled},
+	}
+	for _, tt := range tests {
+		if got := p.HandleShortcut(ctx, tt.ev); got != tt.res || p.IsOpen() != (tt.res == core.Handled) {
+			t.Errorf("%s: %v, open %v", tt.name, got, p.IsOpen())
+		}
+	}
+	if len(ctx.Overlays()) != 1 || !ctx.IsFocused(p.view) {
+		t.Fatal("the palette is not shown with focus")
+	}
+	if p.HandleShortcut(ctx, tests[3].ev); len(ctx.Overlays()) != 1 {
+		t.Error("opening again showed a second palette")
+	}
+
+	p.Close(ctx)
+	p.Trigger(event.KeyK, event.ModCtrl)
+	if p.HandleShortcut(ctx, tests[3].ev) != core.Ignored || p.HandleShortcut(ctx, event.KeyEvent{Type: event.KeyPress, Key: event.KeyK, Modifiers: event.ModCtrl}) != core.Handled {
+		t.Error("the palette did not open on its new trigger")
+	}
+
+	// Opening again starts from an empty search.
+	layoutOverlays(ctx)
+	p.view.HandleEvent(ctx, event.TextEvent{Text: "zz"})
+	p.view.HandleEvent(ctx, event.KeyEvent{Type: event.KeyPress, Key: event.KeyEscape})
+	if p.IsOpen() {
+		t.Fatal("Escape did not close the palette")
+	}
+	p.Open(ctx)
+	layoutOverlays(ctx)
+	if p.view.line.Text() != "" || !slices.Equal(listed(p.view), []string{"Alpha"}) {
+		t.Errorf("reopened with %q listing %q", p.view.line.Text(), listed(p.view))
+	}
+}
+
+func TestCommandPaletteKeys(t *testing.T) {
+	var ran []string
+	var cmds []*Command
+	for i := range 12 {
+		title := fmt.Sprint("Item ", i)
+		cmds = append(cmds, NewCommand(title, title, func() { ran = append(ran, title) }))
+	}
+	p := NewCommandPalette(cmds...)
+	ctx := openPalette(p)
+	v := p.view
+	key := func(k event.Key) core.EventResult {
+		r := v.HandleEvent(ctx, event.KeyEvent{Type: event.KeyPress, Key: k})
+		layoutOverlays(ctx)
+		return r
+	}
+
+	tests := []struct {
+		key              event.Key
+		highlight, first int
+	}{
+		{event.KeyDown, 1, 0},
+		{event.KeyUp, 0, 0},
+		{event.KeyUp, 0, 0},
+		{event.KeyPageDown, 10, 1},
+		{event.KeyPageDown, 11, 2},
+		{event.KeyDown, 11, 2},
+		{event.KeyPageUp, 1, 1},
+		{event.KeyPageUp, 0, 0},
+	}
+	for _, tt := range tests {
+		if r := key(tt.key); r != core.Handled || v.highlight != tt.highlight || v.first != tt.first {
+			t.Errorf("%v: highlight %d from %d, want %d from %d", tt.key, v.highlight, v.first, tt.highlight, tt.first)
+		}
+	}
+
+	// Typing filters and resets the highlight.
+	key(event.KeyDown)
+	v.HandleEvent(ctx, event.TextEvent{Text: "1"})
+	layoutOverlays(ctx)
+	if got := listed(v); !slices.Equal(got, []string{"Item 1", "Item 10", "Item 11"}) || v.highlight != 0 {
+		t.Errorf("typing listed %q, highlighting %d", got, v.highlight)
+	}
+	if v.rows != 3 || v.Bounds().Height != paletteField+3*paletteRow+popupPadding {
+		t.Errorf("%d rows in %v", v.rows, v.Bounds())
+	}
+	key(event.KeyDown)
+	key(event.KeyEnter)
+	if !slices.Equal(ran, []string{"Item 10"}) || p.IsOpen() || p.Recent()[0] != cmds[10] {
+		t.Errorf("Enter ran %q, open %v", ran, p.IsOpen())
+	}
+
+	p.Open(ctx)
+	layoutOverlays(ctx)
+	v.HandleEvent(ctx, event.TextEvent{Text: "zz"})
+	layoutOverlays(ctx)
+	if key(event.KeyDown); v.highlight != -1 || key(event.KeyEnter) != core.Handled || len(ran) != 1 || !p.IsOpen() {
+		t.Error("Enter without matches ran a command or closed the palette")
+	}
+	if v.HandleEvent(ctx, event.KeyEvent{Type: event.KeyRelease, Key: event.KeyEnter}) != core.Ignored {
+		t.Error("a key release was handled")
+	}
+}
+
+func TestCommandPaletteMouse(t *testing.T) {
+	var ran []string
+	var cmds []*Command
+	for i := range 12 {
+		title := fmt.Sprint("Item ", i)
+		cmds = append(cmds, NewCommand(title, title, func() { ran = append(ran, title) }))
+	}
+	p := NewCommandPalette(cmds...)
+	ctx := openPalette(p)
+	v := p.view
+	if r := v.Bounds(); r.Width != paletteWidth || r.X != (800-paletteWidth)/2 || r.Y != paletteTop {
+		t.Errorf("the palette at %v", r)
+	}
+	row := func(i int) core.Point {
+		return core.Pt(v.list.X+20, v.list.Y+(float32(i)+0.5)*paletteRow)
+	}
+
+	v.HandleEvent(ctx, event.MouseEvent{Type: event.MouseMove, Position: row(2)})
+	if v.hover != 2 {
+		t.Errorf("hovering row 2 hovers %d", v.hover)
+	}
+	v.HandleEvent(ctx, event.MouseEvent{Type: event.MouseLeave})
+	if v.hover != -1 {
+		t.Error("the hover stayed after the pointer left")
+	}
+
+	tests := []struct {
+		name  string
+		delta float32
+		first int
+	}{
+		{"down", 1, 1},
+		{"past the end", 1, 2},
+		{"clamped", 1, 2},
+		{"up", -1, 1},
+	}
+	for _, tt := range tests {
+		if v.HandleEvent(ctx, event.ScrollEvent{Delta: core.Pt(0, tt.delta)}); v.first != tt.first {
+			t.Errorf("%s: first row %d, want %d", tt.name, v.first, tt.first)
+		}
+	}
+
+	v.HandleEvent(ctx, event.MouseEvent{Type: event.MouseDown, Button: event.ButtonRight, Position: row(0)})
+	v.HandleEvent(ctx, leftMouse(event.MouseDown, core.Pt(v.list.X+20, v.line.Rect.Y+5)))
+	if len(ran) != 0 || !p.IsOpen() {
+		t.Fatalf("clicking off the rows ran %q", ran)
+	}
+	v.HandleEvent(ctx, leftMouse(event.MouseDown, row(0)))
+	if !slices.Equal(ran, []string{"Item 1"}) || p.IsOpen() {
+		t.Errorf("clicking the first row shown ran %q", ran)
+	}
+
+	// A narrow window narrows the palette.
+	ctx = core.NewContext()
+	p.Open(ctx)
+	lc := &core.LayoutContext{Context: ctx}
+	if got := lc.Measure(p.view, core.Loose(core.Sz(300, 600))); got.Width != 300-32 {
+		t.Errorf("in a narrow window %v", got)
+	}
+}
+
+func TestCommandPaletteShortcuts(t *testing.T) {
+	ctx := core.NewContext()
+	var saved int
+	shortcuts.Of(ctx).Bind("Ctrl+S", "save").Handle("save", func() { saved++ })
+	save := NewCommand("save", "Save", nil)
+	own := NewCommand("own", "Own", nil).Shortcut("F5")
+	plain := NewCommand("plain", "Plain", nil)
+	p := NewCommandPalette(save, own, plain)
+
+	tests := []struct {
+		name   string
+		lookup func(string) string
+		want   []string
+	}{
+		{"registry", nil, []string{shortcuts.Label(ctx, "save"), "F5", ""}},
+		{"lookup", func(id string) string { return "Alt+" + id }, []string{"Alt+save", "F5", "Alt+plain"}},
+	}
+	for _, tt := range tests {
+		p.ShortcutLookup(tt.lookup)
+		var got []string
+		for _, c := range p.Commands() {
+			got = append(got, p.accelerator(ctx, c))
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: %q, want %q", tt.name, got, tt.want)
+		}
+	}
+	if tests[0].want[0] == "" {
+		t.Fatal("the registry has no label for the bound command")
+	}
+
+	// A command without Run runs through the registry.
+	p.run(ctx, save)
+	if saved != 1 {
+		t.Errorf("the registry ran save %d times", saved)
+	}
+}
+
+func TestCommandPalettePaint(t *testing.T) {
+	p := NewCommandPalette(
+		NewCommand("open", "Open File", nil).Shortcut("Ctrl+O"),
+		NewCommand("reopen", "Reopen Closed Editor", nil),
+	).Placeholder("Search")
+	ctx := openPalette(p)
+	v := p.view
+	if v.line.Placeholder != "Search" {
+		t.Errorf("the placeholder is %q", v.line.Placeholder)
+	}
+	paint := func() []string {
+		cv := &textCanvas{}
+		v.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+		return slices.DeleteFunc(cv.texts, func(s string) bool { return s == "Search" })
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"Ctrl+O", "Open File", "Reopen Closed Editor"}},
+		{"open", []string{"Ctrl+O", "Open", " File", "Re", "open", " Closed Editor"}},
+		{"zz", []string{"No matching commands"}},
+	}
+	for _, tt := range tests {
+		v.line.SetText(tt.query)
+		layoutOverlays(ctx)
+		got := paint()
+		if tt.query != "" {
+			got = got[1:] // The typed query.
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%q drew %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package widgets
+
+import (
+	"time"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+)
+
+const (
+	longPressDelay = 500 * time.Millisecond
+	longPressSlop  = 8
+)
+
+// ShowContextMenu opens a popup menu with its top-left corner at p, in
+// window coordinates. Near the edges of the window, or of the screen when
+// the platform provides popup surfaces, the menu flips to the other side of
+// p so it stays visible. Any widget can call it from its event handler.
+func ShowContextMenu(ctx *core.Context, p core.Point, items ...*MenuItem) {
+	if len(items) == 0 {
+		return
+	}
+	openMenu(ctx, items, core.PlaceAt(p), nil, false)
+}
+
+// ShowMenu opens a popup menu next to anchor, flipping to the opposite side
+// when there is not enough room, for example below a toolbar button.
+func ShowMenu(ctx *core.Context, anchor core.Rect, side core.Side, items ...*MenuItem) {
+	if len(items) == 0 {
+		return
+	}
+	openMenu(ctx, items, core.PlaceAnchored(anchor, side), nil, true)
+}
+
+// ContextMenuArea is the wrapper returned by ContextMenu.
+type ContextMenuArea struct {
+	core.WidgetBase
+
+	child   core.Widget
+	items   []*MenuItem
+	provide func(at core.Point) []*MenuItem
+	onOpen  func()
+
+	pressAt  core.Point
+	pressGen int
+	pressing bool
+}
+
+// ContextMenu wraps child so that a right-click, a long press, the Menu key
+// or Shift+F10 anywhere inside it opens a popup menu with items.
+//
+// Widgets inside child that handle right-clicks themselves, such as a list
+// selecting the clicked row, let the press bubble so the menu still opens.
+// A long press is only seen where child does not consume the left button
+// press itself.
+func ContextMenu(child core.Widget, items ...*MenuItem) *ContextMenuArea {
+	a := &ContextMenuArea{child: child, items: items}
+	a.SetChildren(child)
+	return a
+}
+
+// Items builds the menu when it opens, for menus that depend on what was
+// clicked. at is the pointer position in window coordinates. Returning no
+// items suppresses the menu.
+func (a *ContextMenuArea) Items(fn func(at core.Point) []*MenuItem) *ContextMenuArea {
+	a.provide = fn
+	return a
+}
+
+// OnOpen registers fn to be called when the menu opens.
+func (a *ContextMenuArea) OnOpen(fn func()) *ContextMenuArea {
+	a.onOpen = fn
+	return a
+}
+
+// Layout implements core.Widget.
+func (a *ContextMenuArea) Layout(ctx *core.LayoutContext) core.Size {
+	return ctx.Measure(a.child, ctx.Constraints)
+}
+
+// SetBounds implements core.Widget.
+func (a *ContextMenuArea) SetBounds(r core.Rect) {
+	a.WidgetBase.SetBounds(r)
+	a.child.SetBounds(r)
+}
+
+// Paint implements core.Widget.
+func (a *ContextMenuArea) Paint(ctx *core.PaintContext) {
+	a.child.Paint(ctx)
+}
+
+func (a *ContextMenuArea) open(ctx *core.Context, at core.Point) {
+	items := a.items
+	if a.provide != nil {
+		items = a.provide(at)
+	}
+	if len(items) == 0 {
+		return
+	}
+	if a.onOpen != nil {
+		a.onOpen()
+	}
+	ShowContextMenu(ctx, at, items...)
+}
+
+// HandleEvent implements core.Widget.
+func (a *ContextMenuArea) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	switch e := ev.(type) {
+	case event.MouseEvent:
+		switch e.Type {
+		case event.MouseDown:
+			if e.Button == event.ButtonRight {
+				a.pressing = false
+				a.open(ctx, e.Position)
+				return core.Handled
+			}
+			if e.Button == event.ButtonLeft {
+				a.startLongPress(ctx, e.Position)
+			}
+		case event.MouseMove:
+			if a.pressing && (abs32(e.Position.X-a.pressAt.X) > longPressSlop || abs32(e.Position.Y-a.pressAt.Y) > longPressSlop) {
+				a.pressing = false
+			}
+		case event.MouseUp:
+			a.pressing = false
+		}
+	case event.KeyEvent:
+		if e.Type != event.KeyPress {
+			return core.Ignored
+		}
+		if e.Key == event.KeyMenu || e.Key == event.KeyF10 && e.Modifiers == event.ModShift {
+			a.pressing = false
+			a.open(ctx, a.keyboardAnchor(ctx))
+			return core.Handled
+		}
+	}
+	return core.Ignored
+}
+
+// startLongPress opens the menu if the button is held still for
+// longPressDelay. The press itself keeps bubbling so that a long press does
+// not disturb ordinary clicks.
+func (a *ContextMenuArea) startLongPress(ctx *core.Context, p core.Point) {
+	a.pressing, a.pressAt = true, p
+	a.pressGen++
+	gen := a.pressGen
+	time.AfterFunc(longPressDelay, func() {
+		ctx.Post(func() {
+			if a.pressing && a.pressGen == gen {
+				a.pressing = false
+				ctx.ReleasePointer()
+				a.open(ctx, a.pressAt)
+			}
+		})
+	})
+}
+
+// keyboardAnchor places a keyboard-invoked menu at the focused widget, or
+// at the area's top-left corner.
+func (a *ContextMenuArea) keyboardAnchor(ctx *core.Context) core.Point {
+	r := a.Bounds()
+	if f := ctx.Focused(); f != nil && core.PathTo(a, f) != nil {
+		r = f.Bounds()
+	}
+	return core.Pt(r.X+8, r.Y+min(r.Height, 24))
+}
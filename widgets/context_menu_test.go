@@ -0,0 +1,166 @@
+package widgets
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+)
+
+// rightClick returns a right button press at p.
+func rightClick(p core.Point) event.MouseEvent {
+	return event.MouseEvent{Type: event.MouseDown, Position: p, Button: event.ButtonRight}
+}
+
+// dispatchAt delivers ev to the widgets of root under the pointer, as the
+// window does.
+func dispatchAt(ctx *core.Context, root core.Widget, ev event.MouseEvent) core.EventResult {
+	return core.Dispatch(ctx, core.HitTest(root, ev.Position), ev)
+}
+
+func TestShowContextMenu(t *testing.T) {
+	ctx := core.NewContext()
+	ShowContextMenu(ctx, core.Pt(10, 10))
+	if len(ctx.Overlays()) != 0 {
+		t.Error("a menu without items opened")
+	}
+	ShowContextMenu(ctx, core.Pt(790, 20), NewMenuItem("Cut", nil))
+	m := layoutOverlays(ctx)[0]
+	if got := m.Bounds(); got.X != 790-got.Width || got.Y != 20 {
+		t.Errorf("menu at %v, want flipped left of (790, 20)", got)
+	}
+	if hovered(m) != "" {
+		t.Errorf("a pointer menu opened hovering %q", hovered(m))
+	}
+	ShowMenu(ctx, core.R(100, 100, 40, 20), core.SideBelow, NewMenuItem("Paste", nil))
+	if m := layoutOverlays(ctx)[1]; m.Bounds().Origin() != core.Pt(100, 120) || hovered(m) != "Paste" {
+		t.Errorf("menu at %v hovering %q, want below its anchor hovering Paste", m.Bounds(), hovered(m))
+	}
+}
+
+func TestContextMenuTriggers(t *testing.T) {
+	tests := []struct {
+		name string
+		ev   core.Event
+		at   core.Point // Where the menu opens; zero if it does not.
+	}{
+		{"right click", rightClick(core.Pt(30, 40)), core.Pt(30, 40)},
+		{"menu key", press(event.KeyMenu, 0), core.Pt(8, 24)},
+		{"shift F10", press(event.KeyF10, event.ModShift), core.Pt(8, 24)},
+		{"F10", press(event.KeyF10, 0), core.Point{}},
+		{"left click", event.MouseEvent{Type: event.MouseDown, Position: core.Pt(30, 40), Button: event.ButtonLeft}, core.Point{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opened := 0
+			a := ContextMenu(&fixed{height: 100}, NewMenuItem("Copy", nil)).OnOpen(func() { opened++ })
+			ctx := layoutAt(a, core.R(0, 0, 200, 100))
+			a.HandleEvent(ctx, tt.ev)
+			popups := layoutOverlays(ctx)
+			if want := tt.at != (core.Point{}); (len(popups) == 1) != want || (opened == 1) != want {
+				t.Fatalf("%d menus open, OnOpen called %d times", len(popups), opened)
+			}
+			if len(popups) == 1 && popups[0].Bounds().Origin() != tt.at {
+				t.Errorf("menu at %v, want %v", popups[0].Bounds().Origin(), tt.at)
+			}
+		})
+	}
+}
+
+func TestContextMenuItems(t *testing.T) {
+	var at []core.Point
+	a := ContextMenu(&fixed{height: 100}).Items(func(p core.Point) []*MenuItem {
+		at = append(at, p)
+		if p.X > 100 {
+			return nil
+		}
+		return []*MenuItem{NewMenuItem("Here", nil)}
+	})
+	ctx := layoutAt(a, core.R(0, 0, 200, 100))
+	a.HandleEvent(ctx, rightClick(core.Pt(150, 10)))
+	if len(ctx.Overlays()) != 0 {
+		t.Error("a menu opened without items")
+	}
+	a.HandleEvent(ctx, rightClick(core.Pt(50, 10)))
+	if len(ctx.Overlays()) != 1 {
+		t.Error("no menu opened")
+	}
+	if len(at) != 2 || at[1] != core.Pt(50, 10) {
+		t.Errorf("Items got %v", at)
+	}
+}
+
+func TestContextMenuLongPress(t *testing.T) {
+	tests := []struct {
+		name  string
+		moves []core.Point
+		open  bool
+	}{
+		{"held still", []core.Point{core.Pt(52, 13)}, true},
+		{"moved away", []core.Point{core.Pt(52, 13), core.Pt(70, 10)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := ContextMenu(&fixed{height: 100}, NewMenuItem("Copy", nil))
+			ctx := layoutAt(a, core.R(0, 0, 200, 100))
+			a.HandleEvent(ctx, event.MouseEvent{Type: event.MouseDown, Position: core.Pt(50, 10), Button: event.ButtonLeft})
+			for _, p := range tt.moves {
+				a.HandleEvent(ctx, event.MouseEvent{Type: event.MouseMove, Position: p})
+			}
+			runPosted(t, ctx)
+			if open := len(ctx.Overlays()) == 1; open != tt.open {
+				t.Errorf("open = %v, want %v", open, tt.open)
+			}
+		})
+	}
+}
+
+func TestContextMenuSelectsFirst(t *testing.T) {
+	roots, value := sizes()
+	treeTable := NewTreeTable(roots, value, Column{Key: "name", Width: 100}).RowHeight(20)
+	treeView := NewTreeView(sampleTree()...).RowHeight(20)
+	grid := newPeopleGrid()
+	tabView := NewTabView(tabs("abcd")...)
+	tests := []struct {
+		name     string
+		w        core.Widget
+		at       func() core.Point
+		selected func() string
+		want     string
+	}{
+		{"data grid", grid, func() core.Point { return core.Pt(10, 30+45) }, func() string {
+			r, _ := grid.SelectedRange()
+			return people.Value(grid.SourceRow(r.Row0), "name").(string)
+		}, "dave"},
+		{"tree view", treeView, func() core.Point { return core.Pt(50, 30) }, func() string { return labels(treeView.Selected()) }, "e"},
+		{"tree table", treeTable, func() core.Point { return core.Pt(50, treeTable.body.Y+30) }, func() string { return labels(treeTable.Selected()) }, "util.go"},
+		{"tab view", tabView, func() core.Point { return core.Pt(2*minTabWidth+minTabWidth/2, 10) }, func() string {
+			_, current := titles(tabView)
+			return current
+		}, "c"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := ContextMenu(tt.w, NewMenuItem("Copy", nil))
+			ctx := layoutAt(a, core.R(0, 0, 300, 200))
+			dispatchAt(ctx, a, rightClick(tt.at()))
+			if got := tt.selected(); got != tt.want {
+				t.Errorf("selected %q, want %q", got, tt.want)
+			}
+			if len(ctx.Overlays()) != 1 {
+				t.Error("the press did not bubble to the context menu")
+			}
+		})
+	}
+}
+
+func TestContextMenuKeepsSelection(t *testing.T) {
+	g := newPeopleGrid()
+	a := ContextMenu(g, NewMenuItem("Copy", nil))
+	ctx := layoutAt(a, core.R(0, 0, 300, 200))
+	g.Select(0, 0, 2, 1)
+	dispatchAt(ctx, a, rightClick(core.Pt(120, 30+25)))
+	if r, _ := g.SelectedRange(); r != (CellRange{0, 0, 2, 1}) {
+		t.Errorf("SelectedRange() = %v, want the range clicked in kept", r)
+	}
+}
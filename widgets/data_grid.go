@@ -0,0 +1,702 @@
+package widgets
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/internal/scroll"
+	"github.com/gogpu/ui/theme"
+)
+
+// RowProvider supplies the rows displayed by a DataGrid. Rows are
+// addressed by their index in the provider ("source rows"); sorting and
+// filtering only change the order in which the grid displays them.
+type RowProvider interface {
+	// RowCount returns the number of source rows.
+	RowCount() int
+
+	// Value returns the value of the cell in row for the column with key.
+	Value(row int, key string) any
+}
+
+type rowsFunc struct {
+	count int
+	value func(row int, key string) any
+}
+
+func (r rowsFunc) RowCount() int                 { return r.count }
+func (r rowsFunc) Value(row int, key string) any { return r.value(row, key) }
+
+// RowsFunc returns a RowProvider with count rows whose values are returned
+// by value.
+func RowsFunc(count int, value func(row int, key string) any) RowProvider {
+	return rowsFunc{count: count, value: value}
+}
+
+// SelectionMode selects what a DataGrid selects with pointer and keyboard.
+type SelectionMode uint8
+
+// Selection modes.
+const (
+	// SelectCells selects rectangular cell ranges.
+	SelectCells SelectionMode = iota
+
+	// SelectRows selects whole rows.
+	SelectRows
+
+	// SelectNone disables selection.
+	SelectNone
+)
+
+// CellRange is an inclusive rectangular range of cells in display
+// coordinates: Row indexes the sorted and filtered view, Col the current
+// column order.
+type CellRange struct {
+	Row0, Col0 int
+	Row1, Col1 int
+}
+
+// Contains reports whether the cell at (row, col) is inside the range.
+func (r CellRange) Contains(row, col int) bool {
+	return row >= r.Row0 && row <= r.Row1 && col >= r.Col0 && col <= r.Col1
+}
+
+type cellPos struct{ row, col int }
+
+const defaultRowHeight float32 = 28
+
+// DataGrid displays tabular data from a RowProvider with sortable,
+// filterable, resizable, reorderable and frozen columns and cell or row
+// selection.
+//
+// In right-to-left text the columns run from the right, frozen columns
+// are pinned on the right and the vertical scrollbar is on the left.
+//
+// Only the rows and columns inside the viewport are painted, so grids with
+// hundreds of thousands of rows stay cheap. Sorting and filtering build an
+// index over the provider; call Refresh when the provider's data changes.
+// The selection follows the provider row of its active cell through a new
+// sort or filter, collapsing a range, whose rows need no longer adjoin, to
+// that cell; it is cleared if the row is no longer shown.
+//
+//	grid := widgets.NewDataGrid(rows,
+//	    widgets.Column{Key: "name", Title: "Name", Sortable: true, Frozen: true},
+//	    widgets.Column{Key: "size", Title: "Size", Sortable: true, Align: widgets.CellAlignEnd},
+//	)
+type DataGrid struct {
+	core.WidgetBase
+	core.FocusState
+
+	rows      RowProvider
+	columns   columnSet
+	filters   map[string]func(value any) bool
+	view      []int
+	viewDirty bool
+
+	rowHeight    float32
+	headerHeight float32
+	striped      bool
+	mode         SelectionMode
+
+	scrollX, scrollY float32
+	vbar, hbar       scroll.Bar
+	header, body     core.Rect
+
+	anchor, active cellPos
+	selecting      bool
+
+	onSelect  func(sel CellRange)
+	onSort    func(key string, dir SortDirection)
+	onColumns func(columns []Column)
+}
+
+// NewDataGrid returns a grid showing rows with the given columns.
+func NewDataGrid(rows RowProvider, columns ...Column) *DataGrid {
+	return &DataGrid{
+		rows:         rows,
+		columns:      newColumnSet(columns),
+		filters:      make(map[string]func(any) bool),
+		viewDirty:    true,
+		rowHeight:    defaultRowHeight,
+		headerHeight: defaultHeaderHeight,
+		striped:      true,
+		anchor:       cellPos{-1, -1},
+		active:       cellPos{-1, -1},
+	}
+}
+
+// RowHeight sets the height of every row.
+func (g *DataGrid) RowHeight(h float32) *DataGrid {
+	if h > 0 {
+		g.rowHeight = h
+	}
+	return g
+}
+
+// HeaderHeight sets the height of the header row.
+func (g *DataGrid) HeaderHeight(h float32) *DataGrid {
+	g.headerHeight = max(0, h)
+	return g
+}
+
+// Striped enables or disables alternating row backgrounds.
+func (g *DataGrid) Striped(striped bool) *DataGrid {
+	g.striped = striped
+	return g
+}
+
+// Selection sets the selection mode.
+func (g *DataGrid) Selection(mode SelectionMode) *DataGrid {
+	g.mode = mode
+	g.ClearSelection()
+	return g
+}
+
+// OnSelectionChange registers fn to be called when the selection changes.
+func (g *DataGrid) OnSelectionChange(fn func(sel CellRange)) *DataGrid {
+	g.onSelect = fn
+	return g
+}
+
+// OnSortChange registers fn to be called when the user changes the sort
+// column or direction.
+func (g *DataGrid) OnSortChange(fn func(key string, dir SortDirection)) *DataGrid {
+	g.onSort = fn
+	return g
+}
+
+// OnColumnsChange registers fn to be called after the user resizes or
+// reorders columns, for example to persist the layout.
+func (g *DataGrid) OnColumnsChange(fn func(columns []Column)) *DataGrid {
+	g.onColumns = fn
+	return g
+}
+
+// Columns returns a copy of the columns in display order with their
+// current widths.
+func (g *DataGrid) Columns() []Column {
+	out := make([]Column, len(g.columns.cols))
+	for i, c := range g.columns.cols {
+		out[i] = *c
+	}
+	return out
+}
+
+// SetColumns replaces the columns. Frozen columns are moved to the front.
+func (g *DataGrid) SetColumns(columns ...Column) {
+	g.columns.setColumns(columns)
+	g.ClearSelection()
+	g.viewDirty = true
+}
+
+// SetRows replaces the row provider.
+func (g *DataGrid) SetRows(rows RowProvider) {
+	g.rows = rows
+	g.Refresh()
+}
+
+// Refresh rebuilds the sorted and filtered view after the provider's data
+// changed.
+func (g *DataGrid) Refresh() {
+	g.viewDirty = true
+}
+
+// SortBy sorts by the column with key. SortNone restores source order.
+func (g *DataGrid) SortBy(key string, dir SortDirection) {
+	if dir == SortNone {
+		key = ""
+	}
+	g.columns.sortKey, g.columns.sortDir = key, dir
+	g.viewDirty = true
+}
+
+// SortState returns the current sort column and direction.
+func (g *DataGrid) SortState() (key string, dir SortDirection) {
+	return g.columns.sortKey, g.columns.sortDir
+}
+
+// SetFilter installs a predicate on the column with key; only rows whose
+// value satisfies every column filter are shown. A nil predicate removes
+// the filter.
+func (g *DataGrid) SetFilter(key string, pred func(value any) bool) {
+	if pred == nil {
+		delete(g.filters, key)
+	} else {
+		g.filters[key] = pred
+	}
+	g.viewDirty = true
+}
+
+// SetFilterText filters the column with key to rows whose formatted value
+// contains text, ignoring case. An empty text removes the filter.
+func (g *DataGrid) SetFilterText(key, text string) {
+	i := g.columns.index(key)
+	if text == "" || i < 0 {
+		g.SetFilter(key, nil)
+		return
+	}
+	col := g.columns.cols[i]
+	needle := strings.ToLower(text)
+	g.SetFilter(key, func(v any) bool {
+		return strings.Contains(strings.ToLower(col.format(v)), needle)
+	})
+}
+
+// ClearFilters removes every column filter.
+func (g *DataGrid) ClearFilters() {
+	clear(g.filters)
+	g.viewDirty = true
+}
+
+// ViewRowCount returns the number of rows that pass the filters.
+func (g *DataGrid) ViewRowCount() int {
+	g.ensureView()
+	return len(g.view)
+}
+
+// SourceRow maps a display row to the provider's row index, or -1.
+func (g *DataGrid) SourceRow(viewRow int) int {
+	g.ensureView()
+	if viewRow < 0 || viewRow >= len(g.view) {
+		return -1
+	}
+	return g.view[viewRow]
+}
+
+// SelectedRange returns the selected cells and whether there is a
+// selection.
+func (g *DataGrid) SelectedRange() (CellRange, bool) {
+	g.ensureView()
+	if g.anchor.row < 0 || g.mode == SelectNone {
+		return CellRange{}, false
+	}
+	r := CellRange{
+		Row0: min(g.anchor.row, g.active.row), Row1: max(g.anchor.row, g.active.row),
+		Col0: min(g.anchor.col, g.active.col), Col1: max(g.anchor.col, g.active.col),
+	}
+	if g.mode == SelectRows {
+		r.Col0, r.Col1 = 0, len(g.columns.cols)-1
+	}
+	return r, true
+}
+
+// SelectedSourceRows returns the provider indexes of the selected rows.
+func (g *DataGrid) SelectedSourceRows() []int {
+	r, ok := g.SelectedRange()
+	if !ok {
+		return nil
+	}
+	out := make([]int, 0, r.Row1-r.Row0+1)
+	for v := r.Row0; v <= r.Row1; v++ {
+		out = append(out, g.SourceRow(v))
+	}
+	return out
+}
+
+// ClearSelection removes the selection.
+func (g *DataGrid) ClearSelection() {
+	g.anchor, g.active = cellPos{-1, -1}, cellPos{-1, -1}
+}
+
+// Select selects the range from (row0, col0) to (row1, col1) in display
+// coordinates and scrolls the active corner (row1, col1) into view.
+func (g *DataGrid) Select(row0, col0, row1, col1 int) {
+	g.anchor = g.clampCell(cellPos{row0, col0})
+	g.active = g.clampCell(cellPos{row1, col1})
+	g.scrollToCell(g.active)
+	g.notifySelect()
+}
+
+func (g *DataGrid) isSelected(c cellPos) bool {
+	r, ok := g.SelectedRange()
+	return ok && r.Contains(c.row, c.col)
+}
+
+func (g *DataGrid) clampCell(p cellPos) cellPos {
+	n, m := g.ViewRowCount(), len(g.columns.cols)
+	if n == 0 || m == 0 {
+		return cellPos{-1, -1}
+	}
+	return cellPos{min(max(p.row, 0), n-1), min(max(p.col, 0), m-1)}
+}
+
+func (g *DataGrid) notifySelect() {
+	if g.onSelect == nil {
+		return
+	}
+	if r, ok := g.SelectedRange(); ok {
+		g.onSelect(r)
+	}
+}
+
+func (g *DataGrid) ensureView() {
+	if !g.viewDirty && g.view != nil {
+		return
+	}
+	g.viewDirty = false
+	selected := -1
+	if g.active.row >= 0 && g.active.row < len(g.view) {
+		selected = g.view[g.active.row]
+	}
+	n := 0
+	if g.rows != nil {
+		n = g.rows.RowCount()
+	}
+	g.view = g.view[:0]
+	if g.view == nil {
+		g.view = make([]int, 0, n)
+	}
+	for r := range n {
+		if g.passes(r) {
+			g.view = append(g.view, r)
+		}
+	}
+	if i := g.columns.index(g.columns.sortKey); i >= 0 && g.columns.sortDir != SortNone {
+		col := g.columns.cols[i]
+		desc := g.columns.sortDir == SortDescending
+		slices.SortStableFunc(g.view, func(a, b int) int {
+			c := col.compare(g.rows.Value(a, col.Key), g.rows.Value(b, col.Key))
+			if desc {
+				return -c
+			}
+			return c
+		})
+	}
+	g.reselect(selected)
+}
+
+// reselect moves the selection to the active cell in provider row src
+// after the view was rebuilt, without calling OnSelect, or clears it if
+// the row is not shown.
+func (g *DataGrid) reselect(src int) {
+	i := -1
+	if src >= 0 {
+		i = slices.Index(g.view, src)
+	}
+	if i < 0 || len(g.columns.cols) == 0 {
+		g.ClearSelection()
+		return
+	}
+	g.active = cellPos{i, min(max(g.active.col, 0), len(g.columns.cols)-1)}
+	g.anchor = g.active
+}
+
+func (g *DataGrid) passes(row int) bool {
+	for key, pred := range g.filters {
+		if !pred(g.rows.Value(row, key)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Layout implements core.Widget.
+func (g *DataGrid) Layout(ctx *core.LayoutContext) core.Size {
+	g.columns.dir = ctx.Direction()
+	g.ensureView()
+	content := core.Size{
+		Width:  g.columns.frozenWidth() + g.columns.scrollableWidth() + scroll.Thickness,
+		Height: g.headerHeight + float32(len(g.view))*g.rowHeight + scroll.Thickness,
+	}
+	c := ctx.Constraints
+	if c.HasBoundedWidth() {
+		content.Width = c.MaxWidth
+	}
+	if c.HasBoundedHeight() {
+		content.Height = c.MaxHeight
+	}
+	return c.Constrain(content)
+}
+
+// SetBounds implements core.Widget.
+func (g *DataGrid) SetBounds(r core.Rect) {
+	g.WidgetBase.SetBounds(r)
+	track, rest := scroll.EndTrack(r, g.columns.dir)
+	g.header = core.R(rest.X, r.Y, rest.Width, g.headerHeight)
+	g.body = core.R(rest.X, r.Y+g.headerHeight, rest.Width, max(0, r.Height-g.headerHeight-scroll.Thickness))
+
+	g.vbar.Track = core.R(track.X, g.body.Y, track.Width, g.body.Height)
+	g.vbar.Viewport = g.body.Height
+	g.vbar.Content = float32(len(g.view)) * g.rowHeight
+
+	g.hbar.Horizontal = true
+	area := g.columns.scrollArea(g.body)
+	g.hbar.Track = core.R(area.X, g.body.Bottom(), area.Width, scroll.Thickness)
+	g.hbar.Viewport = g.hbar.Track.Width
+	g.hbar.Content = g.columns.scrollableWidth()
+
+	g.scrollX = core.Clamp(g.scrollX, 0, g.hbar.MaxOffset())
+	g.scrollY = core.Clamp(g.scrollY, 0, g.vbar.MaxOffset())
+}
+
+// visibleRows returns the view rows intersecting the body; last is
+// exclusive.
+func (g *DataGrid) visibleRows() (first, last int) {
+	first = int(g.scrollY / g.rowHeight)
+	last = int((g.scrollY+g.body.Height)/g.rowHeight) + 1
+	return max(0, first), min(len(g.view), last)
+}
+
+// Paint implements core.Widget.
+func (g *DataGrid) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	b := g.Bounds()
+	cv.Save()
+	cv.Clip(b)
+	cv.DrawRect(b, core.Filled(th.Colors.Surface))
+
+	nf := g.columns.frozenCount()
+	frozenArea := g.columns.frozenArea(g.body)
+	g.paintCells(ctx, nf, len(g.columns.cols), g.columns.scrollArea(g.body))
+	g.paintCells(ctx, 0, nf, frozenArea)
+	if nf > 0 {
+		cv.DrawRect(g.columns.endLine(core.R(frozenArea.X, b.Y, frozenArea.Width, g.header.Height+g.body.Height)), core.Filled(th.Colors.Outline))
+	}
+
+	g.columns.paintHeader(ctx, g.header, g.scrollX)
+	g.vbar.Paint(ctx, g.scrollY)
+	g.hbar.Paint(ctx, g.columns.barOffset(&g.hbar, g.scrollX))
+	cv.Restore()
+}
+
+func (g *DataGrid) paintCells(ctx *core.PaintContext, from, to int, area core.Rect) {
+	if from >= to || area.IsEmpty() {
+		return
+	}
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	cv.Save()
+	cv.Clip(area)
+	style := theme.TextStyle(th.Typography.Body, th.Colors.OnSurface)
+	sel, hasSel := g.SelectedRange()
+	first, last := g.visibleRows()
+	for v := first; v < last; v++ {
+		y := g.body.Y + float32(v)*g.rowHeight - g.scrollY
+		rowRect := core.R(area.X, y, area.Width, g.rowHeight)
+		if g.striped && v%2 == 1 {
+			cv.DrawRect(rowRect, core.Filled(th.Colors.SurfaceVariant.WithAlpha(0.35)))
+		}
+		src := g.view[v]
+		for i := from; i < to; i++ {
+			col := g.columns.cols[i]
+			cell := core.R(g.columns.columnX(i, g.body, g.scrollX), y, col.Width, g.rowHeight)
+			if cell.Right() < area.X || cell.X > area.Right() {
+				continue
+			}
+			if hasSel && sel.Contains(v, i) {
+				cv.DrawRect(cell, core.Filled(th.Colors.Selection))
+			}
+			value := g.rows.Value(src, col.Key)
+			if col.Paint != nil {
+				col.Paint(ctx, cell, value)
+			} else {
+				drawCellText(ctx, cell, col.format(value), style, col.Align)
+			}
+			if g.mode == SelectCells && g.IsFocused() && g.active == (cellPos{v, i}) {
+				cv.DrawRect(cell.Inset(core.UniformInsets(1)), core.Stroked(th.Colors.Primary, 2))
+			}
+		}
+	}
+	cv.Restore()
+}
+
+// cellAt returns the display cell under p, clamped to the grid.
+func (g *DataGrid) cellAt(p core.Point) cellPos {
+	row := int((p.Y - g.body.Y + g.scrollY) / g.rowHeight)
+	col := g.columns.columnAt(p.X, g.body, g.scrollX)
+	if col < 0 {
+		if g.columns.underFrozen(p.X, g.body) {
+			col = 0
+		} else {
+			col = len(g.columns.cols) - 1
+		}
+	}
+	return g.clampCell(cellPos{row, col})
+}
+
+func (g *DataGrid) scrollToCell(p cellPos) {
+	if p.row < 0 {
+		return
+	}
+	top := float32(p.row) * g.rowHeight
+	if top < g.scrollY {
+		g.scrollY = top
+	} else if top+g.rowHeight > g.scrollY+g.body.Height {
+		g.scrollY = top + g.rowHeight - g.body.Height
+	}
+	if p.col >= g.columns.frozenCount() {
+		x := g.columns.offset(p.col, 0) - g.columns.frozenWidth()
+		w := g.columns.cols[p.col].Width
+		view := g.hbar.Viewport
+		if x < g.scrollX {
+			g.scrollX = x
+		} else if x+w > g.scrollX+view {
+			g.scrollX = x + w - view
+		}
+	}
+	g.scrollX = core.Clamp(g.scrollX, 0, g.hbar.MaxOffset())
+	g.scrollY = core.Clamp(g.scrollY, 0, g.vbar.MaxOffset())
+}
+
+// HandleEvent implements core.Widget.
+func (g *DataGrid) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	if off, ok := g.vbar.HandleEvent(ctx, g, ev, g.scrollY); ok {
+		if off != g.scrollY {
+			g.scrollY = off
+			ctx.Repaint(g)
+		}
+		return core.Handled
+	}
+	if off, ok := g.hbar.HandleEvent(ctx, g, ev, g.columns.barOffset(&g.hbar, g.scrollX)); ok {
+		if off = g.columns.barOffset(&g.hbar, off); off != g.scrollX {
+			g.scrollX = off
+			ctx.Repaint(g)
+		}
+		return core.Handled
+	}
+	switch e := ev.(type) {
+	case event.MouseEvent:
+		return g.handleMouse(ctx, e)
+	case event.ScrollEvent:
+		dx, dy := e.Delta.X, e.Delta.Y
+		if e.Modifiers.Has(event.ModShift) && dx == 0 {
+			dx, dy = dy, 0
+		}
+		oldX, oldY := g.scrollX, g.scrollY
+		g.scrollX = core.Clamp(g.scrollX+dx, 0, g.hbar.MaxOffset())
+		g.scrollY = core.Clamp(g.scrollY+dy, 0, g.vbar.MaxOffset())
+		if oldX != g.scrollX || oldY != g.scrollY {
+			ctx.Repaint(g)
+			return core.Handled
+		}
+	case event.KeyEvent:
+		if e.Type == event.KeyPress && g.IsFocused() {
+			return g.handleKey(ctx, e)
+		}
+	}
+	return core.Ignored
+}
+
+func (g *DataGrid) handleMouse(ctx *core.Context, e event.MouseEvent) core.EventResult {
+	switch g.columns.handleHeader(ctx, g, e, g.header, g.scrollX) {
+	case headerSorted:
+		g.viewDirty = true
+		g.ClearSelection()
+		if g.onSort != nil {
+			g.onSort(g.columns.sortKey, g.columns.sortDir)
+		}
+		return core.Handled
+	case headerResized, headerReordered:
+		if e.Type == event.MouseUp {
+			g.ClearSelection()
+			if g.onColumns != nil {
+				g.onColumns(g.Columns())
+			}
+		}
+		return core.Handled
+	case headerHandled:
+		return core.Handled
+	}
+
+	switch e.Type {
+	case event.MouseDown:
+		if !g.body.Contains(e.Position) {
+			return core.Ignored
+		}
+		ctx.RequestFocus(g)
+		if e.Button == event.ButtonRight {
+			// Select the clicked cell unless it is already selected, then
+			// let the press bubble to a context menu.
+			if cell := g.cellAt(e.Position); g.mode != SelectNone && !g.isSelected(cell) && cell.row >= 0 {
+				g.anchor, g.active = cell, cell
+				ctx.Repaint(g)
+				g.notifySelect()
+			}
+			return core.Ignored
+		}
+		if g.mode == SelectNone || e.Button != event.ButtonLeft {
+			return core.Handled
+		}
+		cell := g.cellAt(e.Position)
+		if !e.Modifiers.Has(event.ModShift) || g.anchor.row < 0 {
+			g.anchor = cell
+		}
+		g.active = cell
+		g.selecting = true
+		ctx.CapturePointer(g)
+		ctx.Repaint(g)
+		g.notifySelect()
+		return core.Handled
+	case event.MouseMove:
+		if g.selecting {
+			if cell := g.cellAt(e.Position); cell != g.active {
+				g.active = cell
+				g.scrollToCell(cell)
+				ctx.Repaint(g)
+				g.notifySelect()
+			}
+			return core.Handled
+		}
+	case event.MouseUp:
+		if g.selecting {
+			g.selecting = false
+			ctx.ReleasePointer()
+			return core.Handled
+		}
+	}
+	return core.Ignored
+}
+
+func (g *DataGrid) handleKey(ctx *core.Context, e event.KeyEvent) core.EventResult {
+	if g.mode == SelectNone || len(g.view) == 0 {
+		return core.Ignored
+	}
+	ctrl := e.Modifiers.Has(event.ModCtrl) || e.Modifiers.Has(event.ModSuper)
+	if ctrl && e.Key == event.KeyA {
+		g.Select(0, 0, len(g.view)-1, len(g.columns.cols)-1)
+		ctx.Repaint(g)
+		return core.Handled
+	}
+	next := g.active
+	if next.row < 0 {
+		next = cellPos{0, 0}
+	}
+	page := max(1, int(g.body.Height/g.rowHeight)-1)
+	switch mirrorKey(g.columns.dir, e.Key) {
+	case event.KeyUp:
+		next.row--
+	case event.KeyDown:
+		next.row++
+	case event.KeyLeft:
+		next.col--
+	case event.KeyRight:
+		next.col++
+	case event.KeyPageUp:
+		next.row -= page
+	case event.KeyPageDown:
+		next.row += page
+	case event.KeyHome:
+		if ctrl {
+			next.row = 0
+		}
+		next.col = 0
+	case event.KeyEnd:
+		if ctrl {
+			next.row = len(g.view) - 1
+		}
+		next.col = len(g.columns.cols) - 1
+	default:
+		return core.Ignored
+	}
+	next = g.clampCell(next)
+	if e.Modifiers.Has(event.ModShift) && g.anchor.row >= 0 {
+		g.Select(g.anchor.row, g.anchor.col, next.row, next.col)
+	} else {
+		g.Select(next.row, next.col, next.row, next.col)
+	}
+	ctx.Repaint(g)
+	return core.Handled
+}
@@ -0,0 +1,282 @@
+package widgets
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+)
+
+// people is a provider of names and ages.
+var people = RowsFunc(4, func(row int, key string) any {
+	names := []string{"carol", "Alice", "dave", "bob"}
+	ages := []int{30, 25, 30, 40}
+	if key == "name" {
+		return names[row]
+	}
+	return ages[row]
+})
+
+func newPeopleGrid() *DataGrid {
+	return NewDataGrid(people,
+		Column{Key: "name", Title: "Name", Width: 100, Sortable: true},
+		Column{Key: "age", Title: "Age", Width: 50, Sortable: true},
+	).RowHeight(20).HeaderHeight(30)
+}
+
+// viewRows returns the provider rows of the view of g, in order.
+func viewRows(g *DataGrid) []int {
+	var out []int
+	for i := range g.ViewRowCount() {
+		out = append(out, g.SourceRow(i))
+	}
+	return out
+}
+
+func TestDataGridSortAndFilter(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(g *DataGrid)
+		want  []int
+	}{
+		{"source order", func(*DataGrid) {}, []int{0, 1, 2, 3}},
+		{"by name ignoring case", func(g *DataGrid) { g.SortBy("name", SortAscending) }, []int{1, 3, 0, 2}},
+		{"by name descending", func(g *DataGrid) { g.SortBy("name", SortDescending) }, []int{2, 0, 3, 1}},
+		{"by age, stable", func(g *DataGrid) { g.SortBy("age", SortAscending) }, []int{1, 0, 2, 3}},
+		{"unsorted again", func(g *DataGrid) {
+			g.SortBy("age", SortAscending)
+			g.SortBy("age", SortNone)
+		}, []int{0, 1, 2, 3}},
+		{"filter text", func(g *DataGrid) { g.SetFilterText("name", "A") }, []int{0, 1, 2}},
+		{"filter predicate", func(g *DataGrid) { g.SetFilter("age", func(v any) bool { return v.(int) == 30 }) }, []int{0, 2}},
+		{"filters combined", func(g *DataGrid) {
+			g.SetFilterText("name", "a")
+			g.SetFilter("age", func(v any) bool { return v.(int) == 30 })
+		}, []int{0, 2}},
+		{"filters cleared", func(g *DataGrid) {
+			g.SetFilterText("name", "zz")
+			g.ClearFilters()
+		}, []int{0, 1, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newPeopleGrid()
+			tt.setup(g)
+			if got := viewRows(g); !slices.Equal(got, tt.want) {
+				t.Errorf("view = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDataGridSelectionFollowsRow(t *testing.T) {
+	g := newPeopleGrid()
+	g.Select(3, 1, 3, 1) // Bob.
+	g.SortBy("name", SortAscending)
+	if r, ok := g.SelectedRange(); !ok || r != (CellRange{1, 1, 1, 1}) {
+		t.Errorf("SelectedRange() = %v, %v after sorting, want row 1", r, ok)
+	}
+	g.SetFilterText("name", "a")
+	if _, ok := g.SelectedRange(); ok {
+		t.Error("the selection of a row filtered out remains")
+	}
+}
+
+func TestDataGridRowSelection(t *testing.T) {
+	g := newPeopleGrid().Selection(SelectRows)
+	var got []CellRange
+	g.OnSelectionChange(func(r CellRange) { got = append(got, r) })
+	g.Select(2, 0, 1, 0)
+	if want := []CellRange{{1, 0, 2, 1}}; !slices.Equal(got, want) {
+		t.Errorf("selections = %v, want %v", got, want)
+	}
+	if rows := g.SelectedSourceRows(); !slices.Equal(rows, []int{1, 2}) {
+		t.Errorf("SelectedSourceRows() = %v, want [1 2]", rows)
+	}
+	g.ClearSelection()
+	if rows := g.SelectedSourceRows(); rows != nil {
+		t.Errorf("SelectedSourceRows() = %v after ClearSelection", rows)
+	}
+}
+
+func TestDataGridKeys(t *testing.T) {
+	press := func(k event.Key, mods event.Modifiers) event.KeyEvent {
+		return event.KeyEvent{Type: event.KeyPress, Key: k, Modifiers: mods}
+	}
+	tests := []struct {
+		name string
+		keys []event.KeyEvent
+		want CellRange
+	}{
+		{"down", []event.KeyEvent{press(event.KeyDown, 0)}, CellRange{1, 0, 1, 0}},
+		{"clamped", []event.KeyEvent{press(event.KeyUp, 0), press(event.KeyLeft, 0)}, CellRange{0, 0, 0, 0}},
+		{"extended", []event.KeyEvent{press(event.KeyDown, 0), press(event.KeyRight, event.ModShift), press(event.KeyDown, event.ModShift)}, CellRange{1, 0, 2, 1}},
+		{"end", []event.KeyEvent{press(event.KeyEnd, event.ModCtrl)}, CellRange{3, 1, 3, 1}},
+		{"all", []event.KeyEvent{press(event.KeyA, event.ModCtrl)}, CellRange{0, 0, 3, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newPeopleGrid()
+			ctx := layoutAt(g, core.R(0, 0, 300, 200))
+			ctx.RequestFocus(g)
+			g.Select(0, 0, 0, 0)
+			for _, k := range tt.keys {
+				g.HandleEvent(ctx, k)
+			}
+			if r, _ := g.SelectedRange(); r != tt.want {
+				t.Errorf("SelectedRange() = %v, want %v", r, tt.want)
+			}
+		})
+	}
+}
+
+func TestDataGridHeader(t *testing.T) {
+	g := newPeopleGrid()
+	var sorts []SortDirection
+	g.OnSortChange(func(key string, dir SortDirection) { sorts = append(sorts, dir) })
+	var widths []float32
+	g.OnColumnsChange(func(cols []Column) { widths = append(widths, cols[0].Width) })
+	ctx := layoutAt(g, core.R(0, 0, 300, 200))
+	mouse := func(typ event.MouseEventType, x float32) {
+		g.HandleEvent(ctx, event.MouseEvent{Type: typ, Position: core.Pt(x, 15), Button: event.ButtonLeft})
+	}
+
+	// Clicking a title cycles through the sort directions.
+	for range 3 {
+		mouse(event.MouseDown, 20)
+		mouse(event.MouseUp, 20)
+	}
+	if want := []SortDirection{SortAscending, SortDescending, SortNone}; !slices.Equal(sorts, want) {
+		t.Errorf("sorts = %v, want %v", sorts, want)
+	}
+
+	// Dragging the edge of a column resizes it, within its bounds.
+	mouse(event.MouseDown, 100)
+	mouse(event.MouseMove, 130)
+	mouse(event.MouseUp, 130)
+	mouse(event.MouseDown, 130)
+	mouse(event.MouseMove, -100)
+	mouse(event.MouseUp, -100)
+	if want := []float32{130, defaultMinColumnWidth}; !slices.Equal(widths, want) {
+		t.Errorf("widths = %v, want %v", widths, want)
+	}
+}
+
+func TestDataGridClickSelects(t *testing.T) {
+	g := newPeopleGrid()
+	ctx := layoutAt(g, core.R(0, 0, 300, 200))
+	click := func(x, y float32, mods event.Modifiers) {
+		g.HandleEvent(ctx, event.MouseEvent{Type: event.MouseDown, Position: core.Pt(x, y), Button: event.ButtonLeft, Modifiers: mods})
+		g.HandleEvent(ctx, event.MouseEvent{Type: event.MouseUp, Position: core.Pt(x, y), Button: event.ButtonLeft, Modifiers: mods})
+	}
+	click(10, 30+25, 0)
+	click(120, 30+65, event.ModShift)
+	if r, ok := g.SelectedRange(); !ok || r != (CellRange{1, 0, 3, 1}) {
+		t.Errorf("SelectedRange() = %v, %v, want rows 1 to 3", r, ok)
+	}
+	if !g.IsFocused() {
+		t.Error("a click on a cell did not focus the grid")
+	}
+}
+
+func TestDataGridRightToLeft(t *testing.T) {
+	g := newPeopleGrid()
+	var sorts []SortDirection
+	g.OnSortChange(func(key string, dir SortDirection) { sorts = append(sorts, dir) })
+	var widths []float32
+	g.OnColumnsChange(func(cols []Column) { widths = append(widths, cols[0].Width) })
+	ctx := layoutRTL(g, core.R(0, 0, 300, 200))
+	mouse := func(typ event.MouseEventType, x, y float32, mods event.Modifiers) {
+		g.HandleEvent(ctx, event.MouseEvent{Type: typ, Position: core.Pt(x, y), Button: event.ButtonLeft, Modifiers: mods})
+	}
+	click := func(x, y float32, mods event.Modifiers) {
+		mouse(event.MouseDown, x, y, mods)
+		mouse(event.MouseUp, x, y, mods)
+	}
+
+	// The first column is at the right edge.
+	click(280, 15, 0)
+	if !slices.Equal(sorts, []SortDirection{SortAscending}) {
+		t.Errorf("sorts = %v after a click on the right, want the first column ascending", sorts)
+	}
+	click(290, 30+25, 0)
+	click(180, 30+65, event.ModShift)
+	if r, ok := g.SelectedRange(); !ok || r != (CellRange{1, 0, 3, 1}) {
+		t.Errorf("SelectedRange() = %v, %v, want rows 1 to 3", r, ok)
+	}
+	g.Select(0, 0, 0, 0)
+	g.HandleEvent(ctx, press(event.KeyLeft, 0))
+	if r, _ := g.SelectedRange(); r != (CellRange{0, 1, 0, 1}) {
+		t.Errorf("SelectedRange() = %v after Left, want the second column", r)
+	}
+
+	// The end edge of a column is on its left, and dragging it left widens
+	// the column.
+	mouse(event.MouseDown, 200, 15, 0)
+	mouse(event.MouseMove, 170, 15, 0)
+	mouse(event.MouseUp, 170, 15, 0)
+	if !slices.Equal(widths, []float32{130}) {
+		t.Errorf("widths = %v, want [130]", widths)
+	}
+}
+
+func TestCompareValues(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		a, b any
+		want int
+	}{
+		{1, 2, -1},
+		{int64(3), int64(3), 0},
+		{2.5, 1.0, 1},
+		{float32(1), float32(2), -1},
+		{uint64(9), uint64(1), 1},
+		{"Apple", "banana", -1},
+		{"B", "a", 1},
+		{now, now.Add(time.Second), -1},
+		{false, true, -1},
+		{true, true, 0},
+		{1, "1", 0}, // Mixed types compare as text.
+	}
+	for _, tt := range tests {
+		if got := compareValues(tt.a, tt.b, (&Column{}).format); got != tt.want {
+			t.Errorf("compareValues(%v, %v) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestColumnSetMove(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to int
+		want     string
+		moved    bool
+	}{
+		{"forward", 2, 4, "abdec", true},
+		{"backward", 4, 2, "abecd", true},
+		{"kept out of the frozen columns", 3, 0, "abdce", true},
+		{"frozen kept frozen", 0, 4, "bacde", true},
+		{"in place", 2, 2, "abcde", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cols []Column
+			for i, k := range "abcde" {
+				cols = append(cols, Column{Key: string(k), Frozen: i < 2})
+			}
+			cs := newColumnSet(cols)
+			if got := cs.move(tt.from, tt.to); got != tt.moved {
+				t.Errorf("move() = %v, want %v", got, tt.moved)
+			}
+			var order string
+			for _, c := range cs.cols {
+				order += c.Key
+			}
+			if order != tt.want {
+				t.Errorf("order = %q, want %q", order, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,377 @@
+package widgets
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/internal/textedit"
+	"github.com/gogpu/ui/theme"
+)
+
+// dateRangeSeparator joins the two dates of a range in a DatePicker.
+const dateRangeSeparator = " – "
+
+// DatePicker is a date entry field with a drop-down month calendar.
+//
+// Dates can be typed in the locale's format and are committed with Enter
+// or when focus leaves the field; text that does not parse, or a date
+// outside the limits, marks the field invalid and keeps the previous value.
+// Up and Down step the date by a day. Alt+Down, F4 or the calendar button
+// open the calendar, which takes keyboard focus. In range mode the field
+// holds two dates separated by a dash and the calendar picks a start and an
+// end.
+type DatePicker struct {
+	core.WidgetBase
+	core.FocusState
+
+	line      textedit.Line
+	locale    DateLocale
+	min, max  time.Time
+	rangeMode bool
+	value     time.Time
+	start     time.Time
+	end       time.Time
+	invalid   bool
+	dirty     bool
+
+	onChange func(time.Time)
+	onRange  func(start, end time.Time)
+
+	button core.Rect
+	popup  *core.Overlay
+}
+
+// NewDatePicker returns an empty DatePicker.
+func NewDatePicker() *DatePicker {
+	p := &DatePicker{locale: DefaultDateLocale}
+	p.syncText()
+	return p
+}
+
+// Locale sets the entry format, the calendar's names and the first day of
+// the week.
+func (p *DatePicker) Locale(l DateLocale) *DatePicker {
+	p.locale = l
+	p.syncText()
+	return p
+}
+
+// Limits restricts the date to the days from min to max inclusive. A zero
+// time leaves that end open.
+func (p *DatePicker) Limits(min, max time.Time) *DatePicker {
+	p.min, p.max = min, max
+	return p
+}
+
+// RangeMode makes the picker select a range of dates.
+func (p *DatePicker) RangeMode(on bool) *DatePicker {
+	p.rangeMode = on
+	p.syncText()
+	return p
+}
+
+// Placeholder sets the hint shown while the field is empty.
+func (p *DatePicker) Placeholder(s string) *DatePicker {
+	p.line.Placeholder = s
+	return p
+}
+
+// OnChange registers fn to be called when the user changes the date in
+// single mode. fn receives the zero time when the field is cleared.
+func (p *DatePicker) OnChange(fn func(time.Time)) *DatePicker {
+	p.onChange = fn
+	return p
+}
+
+// OnRangeChange registers fn to be called when the user changes the range
+// in range mode.
+func (p *DatePicker) OnRangeChange(fn func(start, end time.Time)) *DatePicker {
+	p.onRange = fn
+	return p
+}
+
+// Value returns the selected date, or the zero time.
+func (p *DatePicker) Value() time.Time {
+	return p.value
+}
+
+// SetValue sets the date without calling OnChange.
+func (p *DatePicker) SetValue(t time.Time) {
+	p.value = time.Time{}
+	if !t.IsZero() {
+		p.value = dateOf(t)
+	}
+	p.syncText()
+}
+
+// Range returns the selected range in range mode.
+func (p *DatePicker) Range() (start, end time.Time) {
+	return p.start, p.end
+}
+
+// SetRange sets the range without calling OnRangeChange.
+func (p *DatePicker) SetRange(start, end time.Time) {
+	p.start, p.end = time.Time{}, time.Time{}
+	if !start.IsZero() && !end.IsZero() {
+		p.start, p.end = dateOf(start), dateOf(end)
+		if dayKey(p.end) < dayKey(p.start) {
+			p.start, p.end = p.end, p.start
+		}
+	}
+	p.syncText()
+}
+
+// IsInvalid reports whether the typed text was rejected.
+func (p *DatePicker) IsInvalid() bool {
+	return p.invalid
+}
+
+func (p *DatePicker) format(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(p.locale.Format)
+}
+
+// syncText shows the current value in the field.
+func (p *DatePicker) syncText() {
+	if p.rangeMode {
+		text := ""
+		if !p.start.IsZero() {
+			text = p.format(p.start) + dateRangeSeparator + p.format(p.end)
+		}
+		p.line.SetText(text)
+	} else {
+		p.line.SetText(p.format(p.value))
+	}
+	p.invalid, p.dirty = false, false
+}
+
+func (p *DatePicker) parse(s string) (time.Time, bool) {
+	t, err := time.ParseInLocation(p.locale.Format, strings.TrimSpace(s), time.Local)
+	if err != nil || !p.selectable(t) {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+func (p *DatePicker) selectable(t time.Time) bool {
+	k := dayKey(t)
+	return (p.min.IsZero() || k >= dayKey(p.min)) && (p.max.IsZero() || k <= dayKey(p.max))
+}
+
+// commit parses the typed text and applies it.
+func (p *DatePicker) commit(ctx *core.Context) {
+	if !p.dirty {
+		return
+	}
+	text := strings.TrimSpace(p.line.Text())
+	if p.rangeMode {
+		if text == "" {
+			p.setRange(ctx, time.Time{}, time.Time{})
+			return
+		}
+		a, b, ok := strings.Cut(text, strings.TrimSpace(dateRangeSeparator))
+		if !ok {
+			a, b, ok = strings.Cut(text, " - ")
+		}
+		start, ok1 := p.parse(a)
+		end, ok2 := p.parse(b)
+		if !ok || !ok1 || !ok2 {
+			p.invalid = true
+			ctx.MarkNeedsLayout(p)
+			return
+		}
+		p.setRange(ctx, start, end)
+		return
+	}
+	if text == "" {
+		p.setValue(ctx, time.Time{})
+		return
+	}
+	t, ok := p.parse(text)
+	if !ok {
+		p.invalid = true
+		ctx.MarkNeedsLayout(p)
+		return
+	}
+	p.setValue(ctx, t)
+}
+
+func (p *DatePicker) setValue(ctx *core.Context, t time.Time) {
+	changed := !sameDay(t, p.value) && !(t.IsZero() && p.value.IsZero())
+	p.SetValue(t)
+	ctx.MarkNeedsLayout(p)
+	if changed && p.onChange != nil {
+		p.onChange(p.value)
+	}
+}
+
+func (p *DatePicker) setRange(ctx *core.Context, start, end time.Time) {
+	changed := !sameDay(start, p.start) || !sameDay(end, p.end)
+	p.SetRange(start, end)
+	ctx.MarkNeedsLayout(p)
+	if changed && p.onRange != nil {
+		p.onRange(p.start, p.end)
+	}
+}
+
+// Layout implements core.Widget.
+func (p *DatePicker) Layout(ctx *core.LayoutContext) core.Size {
+	if p.dirty && !p.IsFocused() && (p.popup == nil || !p.popup.IsOpen()) {
+		// Focus has left the field since the text was edited.
+		p.commit(ctx.Context)
+	}
+	th := theme.From(ctx.Context)
+	p.line.Style = th.Typography.Body
+	sample := p.format(time.Date(2000, 12, 28, 0, 0, 0, 0, time.UTC))
+	if p.rangeMode {
+		sample += dateRangeSeparator + sample
+	}
+	w := ctx.MeasureText(sample, p.line.Style).Width + 2*fieldPadding + fieldButton
+	return ctx.Constraints.Constrain(core.Sz(max(w, 120), fieldHeight))
+}
+
+// SetBounds implements core.Widget.
+func (p *DatePicker) SetBounds(r core.Rect) {
+	p.WidgetBase.SetBounds(r)
+	p.button = core.R(r.Right()-fieldButton, r.Y, fieldButton, r.Height)
+	p.line.Rect = core.R(r.X+fieldPadding, r.Y, r.Width-2*fieldPadding-fieldButton, r.Height)
+}
+
+// Paint implements core.Widget.
+func (p *DatePicker) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	focused := p.IsFocused() || p.popup != nil && p.popup.IsOpen()
+	paintField(ctx, p.Bounds(), focused, p.invalid)
+	p.line.Paint(ctx, p.IsFocused())
+
+	// Calendar glyph.
+	c := p.button.Center()
+	g := core.R(c.X-7, c.Y-6, 14, 13)
+	color := th.Colors.OnSurfaceVariant
+	ctx.Canvas.DrawRoundedRect(g, 2, core.Stroked(color, 1.5))
+	ctx.Canvas.DrawRect(core.R(g.X, g.Y+3, g.Width, 1.5), core.Filled(color))
+}
+
+func (p *DatePicker) openCalendar(ctx *core.Context) {
+	if p.popup != nil && p.popup.IsOpen() {
+		return
+	}
+	p.commit(ctx)
+	cal := NewCalendar().Locale(p.locale).Limits(p.min, p.max).RangeMode(p.rangeMode)
+	if p.rangeMode {
+		cal.SetRange(p.start, p.end)
+	} else {
+		cal.SetSelected(p.value)
+	}
+	if cal.Selected().IsZero() && cal.start.IsZero() {
+		cal.moveCursor(ctx, dateOf(time.Now()))
+	}
+	o := &core.Overlay{
+		Content:      newPopupFrame(cal),
+		Placement:    core.PlaceAnchored(p.Bounds(), core.SideBelow),
+		LightDismiss: true,
+		Popup:        true,
+		Owner:        p,
+	}
+	cal.OnSelect(func(t time.Time) {
+		ctx.CloseOverlay(o)
+		p.setValue(ctx, t)
+	})
+	cal.OnRangeSelect(func(start, end time.Time) {
+		ctx.CloseOverlay(o)
+		p.setRange(ctx, start, end)
+	})
+	p.popup = o
+	ctx.ShowOverlay(o)
+	ctx.RequestFocus(cal)
+}
+
+func (p *DatePicker) closeCalendar(ctx *core.Context) {
+	if p.popup != nil {
+		ctx.CloseOverlay(p.popup)
+	}
+}
+
+// step moves the date by days.
+func (p *DatePicker) step(ctx *core.Context, days int) {
+	if p.rangeMode {
+		return
+	}
+	p.commit(ctx)
+	t := p.value
+	if t.IsZero() {
+		t = dateOf(time.Now())
+	} else {
+		t = t.AddDate(0, 0, days)
+	}
+	if p.selectable(t) {
+		p.setValue(ctx, t)
+		p.line.SelectAll()
+	}
+}
+
+// HandleEvent implements core.Widget.
+func (p *DatePicker) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	switch e := ev.(type) {
+	case event.MouseEvent:
+		if e.Type == event.MouseDown && e.Button == event.ButtonLeft {
+			if p.button.Contains(e.Position) {
+				if p.popup != nil && p.popup.IsOpen() {
+					p.closeCalendar(ctx)
+				} else {
+					p.openCalendar(ctx)
+				}
+				return core.Handled
+			}
+			ctx.RequestFocus(p)
+		}
+		if p.line.HandleEvent(ctx, p, ev) != textedit.Ignored {
+			return core.Handled
+		}
+	case event.KeyEvent:
+		if !p.IsFocused() || e.Type != event.KeyPress {
+			return core.Ignored
+		}
+		switch {
+		case e.Key == event.KeyDown && e.Modifiers == event.ModAlt, e.Key == event.KeyF4 && e.Modifiers == 0:
+			p.openCalendar(ctx)
+			return core.Handled
+		case e.Key == event.KeyEnter:
+			p.commit(ctx)
+			return core.Handled
+		case e.Key == event.KeyEscape && p.dirty:
+			p.syncText()
+			ctx.MarkNeedsLayout(p)
+			return core.Handled
+		case (e.Key == event.KeyUp || e.Key == event.KeyDown) && e.Modifiers == 0 && !p.rangeMode:
+			if e.Key == event.KeyUp {
+				p.step(ctx, 1)
+			} else {
+				p.step(ctx, -1)
+			}
+			return core.Handled
+		case e.Key == event.KeyTab:
+			p.commit(ctx)
+			return core.Ignored
+		}
+		if r := p.line.HandleEvent(ctx, p, ev); r != textedit.Ignored {
+			if r == textedit.Changed {
+				p.dirty, p.invalid = true, false
+			}
+			return core.Handled
+		}
+	case event.TextEvent:
+		if !p.IsFocused() {
+			return core.Ignored
+		}
+		if p.line.HandleEvent(ctx, p, ev) == textedit.Changed {
+			p.dirty, p.invalid = true, false
+		}
+		return core.Handled
+	}
+	return core.Ignored
+}
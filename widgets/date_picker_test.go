@@ -0,0 +1,156 @@
+package widgets
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+)
+
+// typeText replaces the text of the focused field w with s.
+func typeText(ctx *core.Context, w core.Widget, s string) {
+	w.HandleEvent(ctx, press(event.KeyA, event.ModCtrl))
+	w.HandleEvent(ctx, event.TextEvent{Text: s})
+}
+
+func TestDatePickerEntry(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    time.Time
+		invalid bool
+		changed bool
+	}{
+		{"valid", "2024-03-20", day(time.March, 20), false, true},
+		{"padded", " 2024-03-20 ", day(time.March, 20), false, true},
+		{"same day", "2024-03-15", day(time.March, 15), false, false},
+		{"malformed", "March 20", day(time.March, 15), true, false},
+		{"outside the limits", "2024-05-01", day(time.March, 15), true, false},
+		{"cleared", "", time.Time{}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewDatePicker().Limits(day(time.January, 1), day(time.April, 30))
+			p.SetValue(day(time.March, 15))
+			changed := false
+			p.OnChange(func(time.Time) { changed = true })
+			ctx := layoutAt(p, core.R(0, 0, 200, 32))
+			ctx.RequestFocus(p)
+			typeText(ctx, p, tt.text)
+			if tt.text == "" {
+				p.HandleEvent(ctx, press(event.KeyBackspace, 0))
+			}
+			p.HandleEvent(ctx, press(event.KeyEnter, 0))
+			if !sameDay(p.Value(), tt.want) && !(p.Value().IsZero() && tt.want.IsZero()) {
+				t.Errorf("Value() = %v, want %v", p.Value(), tt.want)
+			}
+			if p.IsInvalid() != tt.invalid || changed != tt.changed {
+				t.Errorf("invalid %v, changed %v, want %v, %v", p.IsInvalid(), changed, tt.invalid, tt.changed)
+			}
+		})
+	}
+}
+
+func TestDatePickerKeys(t *testing.T) {
+	p := NewDatePicker().Limits(time.Time{}, day(time.March, 16))
+	p.SetValue(day(time.March, 15))
+	ctx := layoutAt(p, core.R(0, 0, 200, 32))
+	ctx.RequestFocus(p)
+	p.HandleEvent(ctx, press(event.KeyUp, 0))
+	p.HandleEvent(ctx, press(event.KeyUp, 0)) // Past the limit.
+	if !sameDay(p.Value(), day(time.March, 16)) || p.line.Text() != "2024-03-16" {
+		t.Errorf("Value() = %v showing %q, want March 16", p.Value(), p.line.Text())
+	}
+	typeText(ctx, p, "bad")
+	p.HandleEvent(ctx, press(event.KeyEscape, 0))
+	if p.line.Text() != "2024-03-16" || p.IsInvalid() {
+		t.Errorf("Escape left %q", p.line.Text())
+	}
+
+	// Typed text is committed when focus leaves the field.
+	typeText(ctx, p, "2024-03-01")
+	ctx.RequestFocus(nil)
+	ctx.LayoutRoot(p, core.R(0, 0, 200, 32))
+	if !sameDay(p.Value(), day(time.March, 1)) {
+		t.Errorf("Value() = %v after focus left, want March 1", p.Value())
+	}
+}
+
+func TestDatePickerCalendar(t *testing.T) {
+	tests := []struct {
+		name string
+		open func(ctx *core.Context, p *DatePicker)
+	}{
+		{"F4", func(ctx *core.Context, p *DatePicker) { p.HandleEvent(ctx, press(event.KeyF4, 0)) }},
+		{"Alt+Down", func(ctx *core.Context, p *DatePicker) { p.HandleEvent(ctx, press(event.KeyDown, event.ModAlt)) }},
+		{"button", func(ctx *core.Context, p *DatePicker) {
+			p.HandleEvent(ctx, event.MouseEvent{Type: event.MouseDown, Position: p.button.Center(), Button: event.ButtonLeft})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewDatePicker()
+			p.SetValue(day(time.March, 15))
+			var got time.Time
+			p.OnChange(func(t time.Time) { got = t })
+			ctx := layoutAt(p, core.R(0, 0, 200, 32))
+			ctx.RequestFocus(p)
+			tt.open(ctx, p)
+			layoutOverlays(ctx)
+			cal, ok := ctx.Focused().(*Calendar)
+			if !ok {
+				t.Fatalf("focus on %T, want the calendar", ctx.Focused())
+			}
+			if cal.Bounds().Y < p.Bounds().Bottom() {
+				t.Errorf("calendar at %v, want below the field", cal.Bounds())
+			}
+			if !sameDay(cal.Selected(), day(time.March, 15)) {
+				t.Errorf("the calendar selects %v", cal.Selected())
+			}
+			cal.HandleEvent(ctx, press(event.KeyRight, 0))
+			cal.HandleEvent(ctx, press(event.KeyEnter, 0))
+			if !sameDay(got, day(time.March, 16)) || len(ctx.Overlays()) != 0 {
+				t.Errorf("picked %v with %d overlays open", got, len(ctx.Overlays()))
+			}
+		})
+	}
+}
+
+func TestDatePickerRange(t *testing.T) {
+	tests := []struct {
+		name       string
+		text       string
+		start, end time.Time
+		invalid    bool
+	}{
+		{"dash", "2024-03-01 – 2024-03-05", day(time.March, 1), day(time.March, 5), false},
+		{"hyphen", "2024-03-01 - 2024-03-05", day(time.March, 1), day(time.March, 5), false},
+		{"reversed", "2024-03-05 – 2024-03-01", day(time.March, 1), day(time.March, 5), false},
+		{"one date", "2024-03-05", time.Time{}, time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewDatePicker().RangeMode(true)
+			calls := 0
+			p.OnRangeChange(func(start, end time.Time) { calls++ })
+			ctx := layoutAt(p, core.R(0, 0, 300, 32))
+			ctx.RequestFocus(p)
+			typeText(ctx, p, tt.text)
+			p.HandleEvent(ctx, press(event.KeyEnter, 0))
+			start, end := p.Range()
+			if tt.invalid {
+				if !p.IsInvalid() || !start.IsZero() || calls != 0 {
+					t.Errorf("invalid text gave %v, %v", start, end)
+				}
+				return
+			}
+			if !sameDay(start, tt.start) || !sameDay(end, tt.end) || calls != 1 {
+				t.Errorf("Range() = %v, %v with %d calls", start, end, calls)
+			}
+			if want := "2024-03-01" + dateRangeSeparator + "2024-03-05"; p.line.Text() != want {
+				t.Errorf("showing %q, want %q", p.line.Text(), want)
+			}
+		})
+	}
+}
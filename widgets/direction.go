@@ -0,0 +1,32 @@
+package widgets
+
+import (
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+)
+
+// mirrorKey returns k with the left and right arrow keys swapped in
+// right-to-left text, so that widgets can handle the arrow toward the end
+// edge as KeyRight whatever the direction.
+func mirrorKey(dir core.Direction, k event.Key) event.Key {
+	if dir.IsRTL() {
+		switch k {
+		case event.KeyLeft:
+			return event.KeyRight
+		case event.KeyRight:
+			return event.KeyLeft
+		}
+	}
+	return k
+}
+
+// placeBelow returns a Placement that drops content down from anchor,
+// aligned with its start edge.
+func placeBelow(anchor core.Rect, dir core.Direction) func(core.Size, core.Rect) core.Point {
+	if !dir.IsRTL() {
+		return core.PlaceAnchored(anchor, core.SideBelow)
+	}
+	return func(s core.Size, area core.Rect) core.Point {
+		return core.PlaceAnchored(core.R(anchor.Right()-s.Width, anchor.Y, s.Width, anchor.Height), core.SideBelow)(s, area)
+	}
+}
@@ -0,0 +1,11 @@
+// Package widgets provides the standard widget library.
+//
+// Widgets are created with NewXxx constructors and configured with
+// chainable, Tailwind-style builder methods:
+//
+//	title := widgets.NewText("Hello").FontSize(24).Weight(core.WeightBold)
+//
+// Colors and text styles default to the theme installed in the
+// core.Context, so a widget only carries the values that were explicitly
+// overridden.
+package widgets
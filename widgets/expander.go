@@ -0,0 +1,273 @@
+package widgets
+
+import (
+	"time"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/theme"
+)
+
+const (
+	expanderHeaderHeight float32 = 40
+	expanderPadding      float32 = 12
+	expanderChevron      float32 = 24
+)
+
+// expanderTween is how long expanding or collapsing takes.
+const expanderTween = 200 * time.Millisecond
+
+// Expander is a header that shows or hides its content when clicked, or
+// when Space or Enter is pressed while it is focused. The content slides
+// in and out below the header.
+//
+// The content is built by a function on first expand rather than up
+// front, so that a form with many collapsed sections only pays for the
+// ones the user opens. Once built it is kept, along with its state.
+type Expander struct {
+	core.WidgetBase
+	core.FocusState
+
+	title    string
+	build    func() core.Widget
+	content  core.Widget
+	expanded bool
+	onToggle func(expanded bool)
+	group    *Accordion
+
+	header   core.Rect
+	hover    bool
+	pressed  bool
+	contentH float32
+
+	// pos is the openness from 0 (collapsed) to 1 (expanded), animated
+	// from from after a toggle at changedAt.
+	pos       float32
+	from      float32
+	changedAt time.Time
+}
+
+// NewExpander returns a collapsed expander titled title. build is called
+// to create the content the first time the expander is shown expanded.
+func NewExpander(title string, build func() core.Widget) *Expander {
+	return &Expander{title: title, build: build}
+}
+
+// Title returns the header text.
+func (e *Expander) Title() string {
+	return e.title
+}
+
+// SetTitle replaces the header text.
+func (e *Expander) SetTitle(title string) {
+	e.title = title
+}
+
+// Expanded sets whether the content is shown, without animating or
+// calling OnToggle.
+func (e *Expander) Expanded(expanded bool) *Expander {
+	e.expanded = expanded
+	e.pos = 0
+	if expanded {
+		e.pos = 1
+		if e.group != nil {
+			e.group.collapseOthers(e, false)
+		}
+	}
+	return e
+}
+
+// IsExpanded reports whether the content is shown, or being expanded.
+func (e *Expander) IsExpanded() bool {
+	return e.expanded
+}
+
+// OnToggle registers fn to be called when the user expands or collapses
+// the expander, including when an Accordion collapses it because another
+// one was expanded.
+func (e *Expander) OnToggle(fn func(expanded bool)) *Expander {
+	e.onToggle = fn
+	return e
+}
+
+// Content returns the content, or nil if it has not been built yet.
+func (e *Expander) Content() core.Widget {
+	return e.content
+}
+
+// Toggle expands or collapses the expander as if it had been clicked.
+func (e *Expander) Toggle() {
+	e.setExpanded(!e.expanded)
+}
+
+func (e *Expander) setExpanded(expanded bool) {
+	if expanded == e.expanded {
+		return
+	}
+	e.expanded = expanded
+	e.from = e.pos
+	e.changedAt = time.Time{}
+	if expanded && e.group != nil {
+		e.group.collapseOthers(e, true)
+	}
+	if e.onToggle != nil {
+		e.onToggle(expanded)
+	}
+}
+
+// Layout implements core.Widget.
+func (e *Expander) Layout(ctx *core.LayoutContext) core.Size {
+	target := float32(0)
+	if e.expanded {
+		target = 1
+	}
+	if e.pos != target {
+		if e.changedAt.IsZero() {
+			e.changedAt = ctx.Now()
+		}
+		t := float32(ctx.Now().Sub(e.changedAt)) / float32(expanderTween)
+		if t >= 1 || ctx.ReducedMotion() {
+			e.pos = target
+		} else {
+			e.pos = e.from + (target-e.from)*easeOut(max(t, 0))
+			ctx.MarkNeedsLayout(e)
+		}
+	}
+	if e.pos > 0 && e.content == nil && e.build != nil {
+		e.content = e.build()
+	}
+
+	style := theme.From(ctx.Context).Typography.Body
+	header := max(expanderHeaderHeight, style.LineHeight()+16)
+	width := expanderChevron + ctx.MeasureText(e.title, style).Width + expanderPadding
+	if ctx.Constraints.HasBoundedWidth() {
+		width = ctx.Constraints.MaxWidth
+	}
+	e.contentH = 0
+	if e.pos > 0 && e.content != nil {
+		c := core.Constraints{MaxWidth: core.Infinity, MaxHeight: core.Infinity}
+		if ctx.Constraints.HasBoundedWidth() {
+			c.MaxWidth = max(0, width-2*expanderPadding)
+		}
+		sz := ctx.Measure(e.content, c)
+		e.contentH = sz.Height + expanderPadding
+		if !ctx.Constraints.HasBoundedWidth() {
+			width = max(width, sz.Width+2*expanderPadding)
+		}
+		e.SetChildren(e.content)
+	} else {
+		e.SetChildren()
+	}
+	e.header = core.Rect{Height: header}
+	return ctx.Constraints.Constrain(core.Sz(width, header+e.contentH*e.pos))
+}
+
+// SetBounds implements core.Widget.
+func (e *Expander) SetBounds(r core.Rect) {
+	e.WidgetBase.SetBounds(r)
+	e.header = core.R(r.X, r.Y, r.Width, min(e.header.Height, r.Height))
+	if e.pos > 0 && e.content != nil {
+		// The content slides down from under the header.
+		h := e.contentH - expanderPadding
+		y := e.header.Bottom() - e.contentH*(1-e.pos)
+		e.content.SetBounds(core.R(r.X+expanderPadding, y, max(0, r.Width-2*expanderPadding), h))
+	}
+}
+
+// Paint implements core.Widget.
+func (e *Expander) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	h := e.header
+	switch {
+	case e.pressed && e.hover:
+		cv.DrawRect(h, core.Filled(th.Colors.OnSurface.WithAlpha(0.12)))
+	case e.hover:
+		cv.DrawRect(h, core.Filled(th.Colors.OnSurface.WithAlpha(0.06)))
+	}
+	if e.IsFocused() {
+		cv.DrawRoundedRect(h.Inset(core.UniformInsets(toggleRing/2)), th.Radii.Small, core.Stroked(th.Colors.Primary, toggleRing))
+	}
+	dir := ctx.Direction()
+	chevron := dir.Mirror(core.R(h.X, h.Y, expanderChevron, h.Height), h)
+	paintDisclosure(ctx, chevron, e.expanded, th.Colors.OnSurfaceVariant)
+	style := theme.TextStyle(th.Typography.Body, th.Colors.OnSurface)
+	title := h.Inset(core.DirectionalInsets{Start: expanderChevron, End: expanderPadding}.Resolve(dir))
+	x := title.X
+	if dir.IsRTL() {
+		x = title.Right() - ctx.MeasureText(e.title, style).Width
+	}
+	cv.Save()
+	cv.Clip(title)
+	cv.DrawText(e.title, core.Pt(x, h.Y+(h.Height-style.LineHeight())/2), style)
+	cv.Restore()
+
+	if e.pos > 0 && e.content != nil {
+		b := e.Bounds()
+		cv.Save()
+		cv.Clip(core.R(b.X, h.Bottom(), b.Width, max(0, b.Bottom()-h.Bottom())))
+		e.content.Paint(ctx)
+		cv.Restore()
+	}
+}
+
+// HandleEvent implements core.Widget.
+func (e *Expander) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	switch ev := ev.(type) {
+	case event.MouseEvent:
+		switch ev.Type {
+		case event.MouseEnter, event.MouseMove:
+			if in := e.header.Contains(ev.Position); in != e.hover {
+				e.hover = in
+				ctx.Repaint(e)
+			}
+		case event.MouseLeave:
+			if e.hover {
+				e.hover = false
+				ctx.Repaint(e)
+			}
+		case event.MouseDown:
+			if ev.Button != event.ButtonLeft || !e.header.Contains(ev.Position) {
+				return core.Ignored
+			}
+			e.pressed = true
+			ctx.RequestFocus(e)
+			ctx.CapturePointer(e)
+			ctx.Repaint(e)
+			return core.Handled
+		case event.MouseUp:
+			if !e.pressed || ev.Button != event.ButtonLeft {
+				return core.Ignored
+			}
+			e.pressed = false
+			ctx.ReleasePointer()
+			if e.header.Contains(ev.Position) {
+				e.Toggle()
+			}
+			e.relayout(ctx)
+			return core.Handled
+		}
+	case event.KeyEvent:
+		if !e.IsFocused() || ev.Type != event.KeyPress || ev.Modifiers != 0 {
+			return core.Ignored
+		}
+		if ev.Key == event.KeySpace || ev.Key == event.KeyEnter {
+			e.Toggle()
+			e.relayout(ctx)
+			return core.Handled
+		}
+	}
+	return core.Ignored
+}
+
+// relayout lays out e again after a toggle, along with the expanders of
+// its accordion, which the toggle may have collapsed.
+func (e *Expander) relayout(ctx *core.Context) {
+	if e.group == nil {
+		ctx.MarkNeedsLayout(e)
+		return
+	}
+	for _, it := range e.group.items {
+		ctx.MarkNeedsLayout(it)
+	}
+}
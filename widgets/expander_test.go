@@ -0,0 +1,181 @@
+package widgets
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+)
+
+// section returns a collapsed expander with content 100 high, and how
+// many times the content was built.
+func section(title string) (*Expander, *int) {
+	built := new(int)
+	e := NewExpander(title, func() core.Widget {
+		*built++
+		return &fixed{height: 100}
+	})
+	return e, built
+}
+
+func TestExpanderToggle(t *testing.T) {
+	e, built := section("Advanced")
+	var toggled []bool
+	e.OnToggle(func(expanded bool) { toggled = append(toggled, expanded) })
+	ctx := core.NewContext()
+	ctx.SetReducedMotion(true)
+	bounds := core.R(0, 0, 300, 400)
+	ctx.LayoutRoot(e, bounds)
+	if *built != 0 || e.Content() != nil {
+		t.Fatal("the content was built while collapsed")
+	}
+	header := e.header.Center()
+
+	tests := []struct {
+		name     string
+		input    func()
+		expanded bool
+	}{
+		{"click", func() { click(ctx, e, header) }, true},
+		{"click again", func() { click(ctx, e, header) }, false},
+		{"Space", func() { e.HandleEvent(ctx, press(event.KeySpace, 0)) }, true},
+		{"Enter", func() { e.HandleEvent(ctx, press(event.KeyEnter, 0)) }, false},
+		{"Shift+Enter", func() { e.HandleEvent(ctx, press(event.KeyEnter, event.ModShift)) }, false},
+		{"click below the header", func() { click(ctx, e, core.Pt(10, 200)) }, false},
+		{"Toggle", e.Toggle, true},
+	}
+	for _, tt := range tests {
+		tt.input()
+		ctx.LayoutRoot(e, bounds)
+		if e.IsExpanded() != tt.expanded {
+			t.Errorf("%s: expanded %v, want %v", tt.name, e.IsExpanded(), tt.expanded)
+		}
+	}
+	if !ctx.IsFocused(e) {
+		t.Error("a click did not focus the header")
+	}
+	if want := []bool{true, false, true, false, true}; !slices.Equal(toggled, want) {
+		t.Errorf("toggled %v, want %v", toggled, want)
+	}
+	// The content is built once and kept while collapsed.
+	content := e.Content()
+	e.Toggle()
+	e.Toggle()
+	ctx.LayoutRoot(e, bounds)
+	if *built != 1 || e.Content() != content {
+		t.Errorf("the content was built %d times", *built)
+	}
+
+	// Expanded sets the state quietly.
+	e.Expanded(false)
+	if e.IsExpanded() || e.pos != 0 || len(toggled) != 7 {
+		t.Errorf("Expanded(false) left it expanded %v, toggled %d times", e.IsExpanded(), len(toggled))
+	}
+	e.SetTitle("More")
+	if e.Title() != "More" {
+		t.Errorf("title %q", e.Title())
+	}
+}
+
+func TestExpanderMouse(t *testing.T) {
+	e, _ := section("Advanced")
+	ctx := layoutAt(e, core.R(0, 0, 300, 400))
+	header := e.header.Center()
+	e.HandleEvent(ctx, event.MouseEvent{Type: event.MouseMove, Position: header})
+	if !e.hover {
+		t.Error("hovering the header did not highlight it")
+	}
+	e.HandleEvent(ctx, event.MouseEvent{Type: event.MouseLeave})
+	if e.hover {
+		t.Error("the hover stayed after the pointer left")
+	}
+	e.HandleEvent(ctx, leftMouse(event.MouseDown, header))
+	if ctx.PointerCapture() != e || !e.pressed {
+		t.Fatal("a press on the header was not captured")
+	}
+	e.HandleEvent(ctx, leftMouse(event.MouseUp, core.Pt(10, 390)))
+	if e.IsExpanded() || ctx.PointerCapture() != nil {
+		t.Error("releasing off the header toggled it")
+	}
+	right := event.MouseEvent{Type: event.MouseDown, Button: event.ButtonRight, Position: header}
+	if r := e.HandleEvent(ctx, right); r != core.Ignored {
+		t.Error("a right press was handled")
+	}
+	ctx.RequestFocus(nil)
+	if r := e.HandleEvent(ctx, press(event.KeySpace, 0)); r != core.Ignored || e.IsExpanded() {
+		t.Error("an unfocused expander handled Space")
+	}
+}
+
+func TestExpanderLayout(t *testing.T) {
+	e, _ := section("Advanced")
+	ctx := core.NewContext()
+	t0 := time.Now()
+	ctx.SetNow(t0)
+	bounds := core.R(0, 0, 300, 400)
+	lc := &core.LayoutContext{Context: ctx}
+	collapsed := lc.Measure(e, core.Loose(core.Sz(300, 400)))
+	if collapsed.Width != 300 || collapsed.Height != e.header.Height || collapsed.Height < expanderHeaderHeight {
+		t.Errorf("collapsed size %v", collapsed)
+	}
+	if len(e.Children()) != 0 {
+		t.Error("a collapsed expander has children")
+	}
+	title := lc.Measure(NewExpander("Advanced", nil), core.Unbounded())
+	if title.Width <= expanderChevron+expanderPadding {
+		t.Errorf("unbounded size %v, want room for the title", title)
+	}
+
+	full := collapsed.Height + 100 + expanderPadding
+	tests := []struct {
+		name   string
+		at     time.Duration
+		height func(float32) bool
+	}{
+		{"start", 0, func(h float32) bool { return h == collapsed.Height }},
+		{"halfway", expanderTween / 2, func(h float32) bool { return h > (collapsed.Height+full)/2 && h < full }},
+		{"done", expanderTween, func(h float32) bool { return h == full }},
+	}
+	e.Toggle()
+	for _, tt := range tests {
+		ctx.SetNow(t0.Add(tt.at))
+		ctx.LayoutRoot(e, bounds)
+		if h := lc.Measure(e, core.Loose(core.Sz(300, 400))).Height; !tt.height(h) {
+			t.Errorf("%s: height %v between %v and %v", tt.name, h, collapsed.Height, full)
+		}
+	}
+	ctx.LayoutRoot(e, core.R(0, 0, 300, full))
+	want := core.R(expanderPadding, e.header.Bottom(), 300-2*expanderPadding, 100)
+	if got := e.Content().Bounds(); got != want || len(e.Children()) != 1 {
+		t.Errorf("the content at %v, want %v", got, want)
+	}
+
+	// Halfway back the content slides up under the header.
+	e.Toggle()
+	ctx.SetNow(t0.Add(2 * expanderTween))
+	ctx.LayoutRoot(e, bounds)
+	ctx.SetNow(t0.Add(2*expanderTween + expanderTween/2))
+	ctx.LayoutRoot(e, bounds)
+	if got := e.Content().Bounds(); got.Y >= e.header.Bottom() || got.Height != 100 {
+		t.Errorf("collapsing, the content at %v", got)
+	}
+}
+
+func TestExpanderPaint(t *testing.T) {
+	e := NewExpander("Advanced", func() core.Widget { return NewText("inside") }).Expanded(true)
+	ctx := layoutAt(e, core.R(0, 0, 300, 200))
+	cv := &textCanvas{}
+	e.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+	if !equalStrings(cv.texts, []string{"Advanced", "inside"}) {
+		t.Errorf("drew %q", cv.texts)
+	}
+	e.Expanded(false)
+	ctx.LayoutRoot(e, core.R(0, 0, 300, 200))
+	cv = &textCanvas{}
+	e.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+	if !equalStrings(cv.texts, []string{"Advanced"}) {
+		t.Errorf("collapsed drew %q", cv.texts)
+	}
+}
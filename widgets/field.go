@@ -0,0 +1,74 @@
+package widgets
+
+import (
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/theme"
+)
+
+// Metrics shared by the text-entry widgets.
+const (
+	fieldHeight  float32 = 32
+	fieldPadding float32 = 8
+	fieldButton  float32 = 28
+)
+
+// paintField draws the outlined box behind a text-entry widget.
+func paintField(ctx *core.PaintContext, r core.Rect, focused, invalid bool) {
+	th := theme.From(ctx.Context)
+	stroke, width := th.Colors.Outline, float32(1)
+	switch {
+	case invalid:
+		stroke, width = th.Colors.Error, 2
+	case focused:
+		stroke, width = th.Colors.Primary, 2
+	}
+	ctx.Canvas.DrawRoundedRect(r, th.Radii.Small, core.RectStyle{Fill: th.Colors.Surface, Stroke: stroke, StrokeWidth: width})
+}
+
+// paintChevronDown draws the drop-down arrow of a field's button.
+func paintChevronDown(ctx *core.PaintContext, r core.Rect, color core.Color) {
+	c := r.Center()
+	const s = 4
+	p := core.NewPath().MoveTo(core.Pt(c.X-s, c.Y-s/2)).LineTo(core.Pt(c.X, c.Y+s/2)).LineTo(core.Pt(c.X+s, c.Y-s/2))
+	ctx.Canvas.DrawPath(p, core.PathStyle{Stroke: color, StrokeWidth: 1.5, LineCap: core.CapRound})
+}
+
+// paintChevronUp draws an upward arrow, as on the increment button of a
+// spin field.
+func paintChevronUp(ctx *core.PaintContext, r core.Rect, color core.Color) {
+	c := r.Center()
+	const s = 4
+	p := core.NewPath().MoveTo(core.Pt(c.X-s, c.Y+s/2)).LineTo(core.Pt(c.X, c.Y-s/2)).LineTo(core.Pt(c.X+s, c.Y+s/2))
+	ctx.Canvas.DrawPath(p, core.PathStyle{Stroke: color, StrokeWidth: 1.5, LineCap: core.CapRound})
+}
+
+// popupFrame is the surface drop-down popups of fields are drawn on.
+type popupFrame struct {
+	core.WidgetBase
+	content core.Widget
+}
+
+const popupPadding float32 = 4
+
+func newPopupFrame(content core.Widget) *popupFrame {
+	f := &popupFrame{content: content}
+	f.SetChildren(content)
+	return f
+}
+
+func (f *popupFrame) Layout(ctx *core.LayoutContext) core.Size {
+	pad := core.UniformInsets(popupPadding)
+	size := ctx.Measure(f.content, ctx.Constraints.Loosen().Deflate(pad))
+	return core.Sz(size.Width+pad.Horizontal(), size.Height+pad.Vertical())
+}
+
+func (f *popupFrame) SetBounds(r core.Rect) {
+	f.WidgetBase.SetBounds(r)
+	f.content.SetBounds(r.Inset(core.UniformInsets(popupPadding)))
+}
+
+func (f *popupFrame) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	ctx.Canvas.DrawRoundedRect(f.Bounds(), th.Radii.Small, core.RectStyle{Fill: th.Colors.Surface, Stroke: th.Colors.Outline.WithAlpha(0.5), StrokeWidth: 1})
+	f.content.Paint(ctx)
+}
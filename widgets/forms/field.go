@@ -0,0 +1,244 @@
+package forms
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gogpu/ui/state"
+)
+
+// Validator reports why a value is not acceptable, or nil if it is.
+type Validator[T any] func(v T) error
+
+// AsyncValidator is a Validator that may block, for example on a server
+// request. ctx is cancelled when the value changes before it returns.
+type AsyncValidator[T any] func(ctx context.Context, v T) error
+
+// FieldState is the part of a Field that does not depend on its value
+// type, as used by Row.
+type FieldState interface {
+	// Name returns the name the field was created with.
+	Name() string
+
+	// Form returns the form the field belongs to.
+	Form() *Form
+
+	// Dirty holds whether the value differs from the initial one.
+	Dirty() *state.Signal[bool]
+
+	// Touched holds whether the user has visited and left the input.
+	Touched() *state.Signal[bool]
+
+	// Validating holds whether a background check is running.
+	Validating() *state.Signal[bool]
+
+	// Error holds the message of the first failing validator, or the
+	// empty string while the value is valid.
+	Error() *state.Signal[string]
+
+	// ShownError returns the error to show the user: the validation error
+	// once the field is touched or the form was submitted.
+	ShownError() string
+
+	// Touch marks the field as touched.
+	Touch()
+}
+
+// Field is a value of a form, with its validators.
+type Field[T comparable] struct {
+	form       *Form
+	name       string
+	value      *state.Signal[T]
+	dirty      *state.Signal[bool]
+	touched    *state.Signal[bool]
+	validating *state.Signal[bool]
+	err        *state.Signal[string]
+
+	mu      sync.Mutex
+	initial T
+	checks  []Validator[T]
+	async   AsyncValidator[T]
+	delay   time.Duration
+	gen     int    // incremented on every check, to drop stale results
+	stop    func() // cancels the running background check
+}
+
+// NewField adds a field holding initial to form.
+func NewField[T comparable](form *Form, name string, initial T) *Field[T] {
+	f := &Field[T]{
+		form:       form,
+		name:       name,
+		value:      state.New(initial),
+		dirty:      state.New(false),
+		touched:    state.New(false),
+		validating: state.New(false),
+		err:        state.New(""),
+		initial:    initial,
+	}
+	f.value.Subscribe(f.check)
+	form.add(f)
+	return f
+}
+
+// Validate adds validators, which run in order on every change until one
+// fails.
+func (f *Field[T]) Validate(fns ...Validator[T]) *Field[T] {
+	f.mu.Lock()
+	f.checks = append(f.checks, fns...)
+	f.mu.Unlock()
+	f.check(f.value.Get())
+	return f
+}
+
+// ValidateAsync sets a background validator, run once the other
+// validators pass and the value has not changed for delay. Its result is
+// applied on the UI goroutine of the window showing the form.
+func (f *Field[T]) ValidateAsync(delay time.Duration, fn AsyncValidator[T]) *Field[T] {
+	f.mu.Lock()
+	f.async, f.delay = fn, delay
+	f.mu.Unlock()
+	f.check(f.value.Get())
+	return f
+}
+
+// Name implements FieldState.
+func (f *Field[T]) Name() string {
+	return f.name
+}
+
+// Form implements FieldState.
+func (f *Field[T]) Form() *Form {
+	return f.form
+}
+
+// Value holds the value, for binding to an input widget.
+func (f *Field[T]) Value() *state.Signal[T] {
+	return f.value
+}
+
+// Initial returns the value the field is reset to.
+func (f *Field[T]) Initial() T {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.initial
+}
+
+// Dirty implements FieldState.
+func (f *Field[T]) Dirty() *state.Signal[bool] {
+	return f.dirty
+}
+
+// Touched implements FieldState.
+func (f *Field[T]) Touched() *state.Signal[bool] {
+	return f.touched
+}
+
+// Validating implements FieldState.
+func (f *Field[T]) Validating() *state.Signal[bool] {
+	return f.validating
+}
+
+// Error implements FieldState.
+func (f *Field[T]) Error() *state.Signal[string] {
+	return f.err
+}
+
+// ShownError implements FieldState.
+func (f *Field[T]) ShownError() string {
+	if !f.touched.Get() && !f.form.submitted.Get() {
+		return ""
+	}
+	return f.err.Get()
+}
+
+// Touch implements FieldState.
+func (f *Field[T]) Touch() {
+	f.touched.Set(true)
+}
+
+func (f *Field[T]) reset() {
+	f.touched.Set(false)
+	f.value.Set(f.Initial())
+}
+
+func (f *Field[T]) markPristine() {
+	f.mu.Lock()
+	f.initial = f.value.Get()
+	f.mu.Unlock()
+	f.dirty.Set(false)
+}
+
+func (f *Field[T]) status() (valid, pending, dirty bool) {
+	return f.err.Get() == "", f.validating.Get(), f.dirty.Get()
+}
+
+// check validates v, the new value, starting the background validator if
+// the others pass.
+func (f *Field[T]) check(v T) {
+	f.mu.Lock()
+	f.gen++
+	gen := f.gen
+	if f.stop != nil {
+		f.stop()
+		f.stop = nil
+	}
+	dirty := v != f.initial
+	checks, async, delay := f.checks, f.async, f.delay
+	f.mu.Unlock()
+
+	msg := ""
+	for _, fn := range checks {
+		if err := fn(v); err != nil {
+			msg = err.Error()
+			break
+		}
+	}
+	pending := msg == "" && async != nil
+	f.dirty.Set(dirty)
+	f.err.Set(msg)
+	f.validating.Set(pending)
+	if pending {
+		f.checkAsync(gen, v, async, delay)
+	}
+	f.form.update()
+}
+
+func (f *Field[T]) checkAsync(gen int, v T, fn AsyncValidator[T], delay time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	timer := time.AfterFunc(delay, func() {
+		err := fn(ctx, v)
+		cancel()
+		// The value may have changed again on the UI goroutine meanwhile,
+		// which the result is checked against there.
+		f.form.post(func() {
+			f.mu.Lock()
+			current := f.gen == gen
+			if current {
+				f.stop = nil
+			}
+			f.mu.Unlock()
+			if !current {
+				return
+			}
+			msg := ""
+			if err != nil {
+				msg = err.Error()
+			}
+			f.err.Set(msg)
+			f.validating.Set(false)
+			f.form.update()
+		})
+	})
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.gen != gen {
+		timer.Stop()
+		cancel()
+		return
+	}
+	f.stop = func() {
+		timer.Stop()
+		cancel()
+	}
+}
@@ -0,0 +1,172 @@
+package forms
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gogpu/ui/core"
+)
+
+// runPosted waits until something is posted to ctx and runs it.
+func runPosted(t *testing.T, ctx *core.Context) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !ctx.HasPosted() {
+		if time.Now().After(deadline) {
+			t.Fatal("nothing was posted")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	ctx.RunPosted()
+}
+
+// shown returns a form attached to a new context, as if a row showed it.
+func shown() (*Form, *core.Context) {
+	form := New()
+	ctx := core.NewContext()
+	form.attach(ctx)
+	return form, ctx
+}
+
+func TestFieldValidate(t *testing.T) {
+	form := New()
+	name := NewField(form, "name", "ada").Validate(Required[string](""), MinLength(3, ""), MaxLength(5, ""))
+	if name.Name() != "name" || name.Form() != form || name.Initial() != "ada" {
+		t.Fatalf("field %q of %p starting at %q", name.Name(), name.Form(), name.Initial())
+	}
+	tests := []struct {
+		value string
+		err   string
+		dirty bool
+	}{
+		{"ada", "", false},
+		{"", "Required", true},
+		{"ad", "At least 3 characters", true},
+		{"lovelace", "At most 5 characters", true},
+		{"grace", "", true},
+		{"ada", "", false},
+	}
+	for _, tt := range tests {
+		name.Value().Set(tt.value)
+		if got := name.Error().Get(); got != tt.err || name.Dirty().Get() != tt.dirty {
+			t.Errorf("%q: error %q, dirty %v; want %q and %v", tt.value, got, name.Dirty().Get(), tt.err, tt.dirty)
+		}
+		if form.Valid().Get() != (tt.err == "") || form.Dirty().Get() != tt.dirty {
+			t.Errorf("%q: the form is valid %v, dirty %v", tt.value, form.Valid().Get(), form.Dirty().Get())
+		}
+	}
+
+	// Validators added later check the current value at once.
+	name.Validate(Pattern(regexpDigits, "digits only"))
+	if got := name.Error().Get(); got != "digits only" {
+		t.Errorf("a new validator gives %q", got)
+	}
+	if fields := form.Fields(); len(fields) != 1 || fields[0] != FieldState(name) {
+		t.Errorf("the form has the fields %v", fields)
+	}
+}
+
+func TestFieldShownError(t *testing.T) {
+	form := New()
+	name := NewField(form, "name", "").Validate(Required[string](""))
+	if name.Error().Get() != "Required" || name.ShownError() != "" {
+		t.Fatalf("an untouched field shows %q", name.ShownError())
+	}
+	name.Touch()
+	if !name.Touched().Get() || name.ShownError() != "Required" {
+		t.Errorf("a touched field shows %q", name.ShownError())
+	}
+
+	other := NewField(form, "other", 0).Validate(Range(1, 3, ""))
+	form.Submit()
+	if got := other.ShownError(); got != "Between 1 and 3" {
+		t.Errorf("after submitting an untouched field shows %q", got)
+	}
+	form.Reset()
+	if name.Touched().Get() || name.ShownError() != "" || other.ShownError() != "" {
+		t.Error("Reset kept the errors shown")
+	}
+}
+
+func TestFieldAsync(t *testing.T) {
+	form, ctx := shown()
+	var checked []string
+	name := NewField(form, "name", "").Validate(Required[string]("")).
+		ValidateAsync(0, func(_ context.Context, v string) error {
+			checked = append(checked, v)
+			if v == "taken" {
+				return errors.New("Taken")
+			}
+			return nil
+		})
+	if name.Validating().Get() || ctx.HasPosted() {
+		t.Fatal("the background check ran on a value the other validators reject")
+	}
+	tests := []struct {
+		value string
+		err   string
+	}{
+		{"taken", "Taken"},
+		{"free", ""},
+	}
+	for _, tt := range tests {
+		name.Value().Set(tt.value)
+		if !name.Validating().Get() || !form.Validating().Get() || form.Valid().Get() {
+			t.Errorf("%q: checking %v, the form valid %v", tt.value, name.Validating().Get(), form.Valid().Get())
+		}
+		runPosted(t, ctx)
+		if name.Validating().Get() || form.Validating().Get() || name.Error().Get() != tt.err || form.Valid().Get() != (tt.err == "") {
+			t.Errorf("%q: checked to %q, still checking %v", tt.value, name.Error().Get(), name.Validating().Get())
+		}
+	}
+	if len(checked) != 2 {
+		t.Errorf("checked %q", checked)
+	}
+}
+
+func TestFieldAsyncStale(t *testing.T) {
+	form, ctx := shown()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	cancelled := make(chan bool, 1)
+	name := NewField(form, "name", "").ValidateAsync(0, func(c context.Context, v string) error {
+		if v != "slow" {
+			return nil
+		}
+		close(started)
+		<-release
+		cancelled <- c.Err() != nil
+		return errors.New("stale")
+	})
+	runPosted(t, ctx) // the check of the initial value
+
+	name.Value().Set("slow")
+	<-started
+	name.Value().Set("fast")
+	runPosted(t, ctx)
+	if name.Validating().Get() || name.Error().Get() != "" {
+		t.Fatalf("after the second check: checking %v with %q", name.Validating().Get(), name.Error().Get())
+	}
+	close(release)
+	if !<-cancelled {
+		t.Error("the stale check was not cancelled")
+	}
+	runPosted(t, ctx)
+	if name.Error().Get() != "" || name.Validating().Get() {
+		t.Errorf("the stale result was applied: %q", name.Error().Get())
+	}
+
+	// A check waiting out its delay is dropped when the value changes.
+	late := NewField(form, "late", "").Validate(Required[string]("")).
+		ValidateAsync(time.Hour, func(context.Context, string) error { return errors.New("never") })
+	late.Value().Set("x")
+	if !late.Validating().Get() || form.Valid().Get() {
+		t.Error("a delayed check is not pending")
+	}
+	late.Value().Set("")
+	if late.Validating().Get() || form.Validating().Get() || late.stop != nil {
+		t.Error("clearing the value left the check pending")
+	}
+}
@@ -0,0 +1,216 @@
+// Package forms groups input widgets into forms that validate what the
+// user enters and track its submission.
+//
+// A Form owns its fields. Each Field holds its value in a signal that an
+// input widget binds to, and tracks whether the value differs from the
+// initial one (dirty), whether the user has visited the input (touched)
+// and why the value is rejected. Validators run on every change; slow
+// checks, such as asking a server whether a user name is taken, run in the
+// background and are cancelled when the value changes again:
+//
+//	form := forms.New().OnSubmit(save)
+//	name := forms.NewField(form, "name", "").
+//	    Validate(forms.Required[string](""), forms.MinLength(3, "")).
+//	    ValidateAsync(300*time.Millisecond, checkAvailable)
+//	row := forms.NewRow(name, "User name", widgets.NewTextField().Bind(name.Value()))
+//	submit := forms.NewSubmitButton(form, "Create account")
+//
+// A Row shows a field's label, its input and its error message, which
+// appears once the user leaves the input or tries to submit. A
+// SubmitButton is disabled while the form is invalid, validating or being
+// submitted.
+package forms
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/state"
+)
+
+// field is implemented by Field for every value type.
+type field interface {
+	FieldState
+	reset()
+	markPristine()
+	status() (valid, pending, dirty bool)
+}
+
+// Form groups fields and tracks their combined state.
+type Form struct {
+	mu       sync.Mutex
+	fields   []field
+	onSubmit func(ctx context.Context) error
+	ui       atomic.Pointer[core.Context] // Of the window showing the form.
+
+	valid      *state.Signal[bool]
+	dirty      *state.Signal[bool]
+	validating *state.Signal[bool]
+	submitting *state.Signal[bool]
+	submitted  *state.Signal[bool]
+	submitErr  *state.Signal[string]
+}
+
+// New returns an empty form, which is valid.
+func New() *Form {
+	return &Form{
+		valid:      state.New(true),
+		dirty:      state.New(false),
+		validating: state.New(false),
+		submitting: state.New(false),
+		submitted:  state.New(false),
+		submitErr:  state.New(""),
+	}
+}
+
+// OnSubmit sets the function that submits the form. It runs on its own
+// goroutine, so it may block on the network; it should reach the UI only
+// through signals or core.Context.Post. A returned error is shown by
+// SubmitError and keeps the fields dirty.
+func (f *Form) OnSubmit(fn func(ctx context.Context) error) *Form {
+	f.onSubmit = fn
+	return f
+}
+
+// Fields returns the fields in the order they were added.
+func (f *Form) Fields() []FieldState {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make([]FieldState, len(f.fields))
+	for i, fd := range f.fields {
+		out[i] = fd
+	}
+	return out
+}
+
+// Valid holds whether every field is valid, with no check still running.
+func (f *Form) Valid() *state.Signal[bool] {
+	return f.valid
+}
+
+// Dirty holds whether any field differs from its initial value.
+func (f *Form) Dirty() *state.Signal[bool] {
+	return f.dirty
+}
+
+// Validating holds whether a background check of any field is running.
+func (f *Form) Validating() *state.Signal[bool] {
+	return f.validating
+}
+
+// Submitting holds whether the OnSubmit function is running.
+func (f *Form) Submitting() *state.Signal[bool] {
+	return f.submitting
+}
+
+// Submitted holds whether the user has tried to submit the form, after
+// which every field shows its error whether touched or not.
+func (f *Form) Submitted() *state.Signal[bool] {
+	return f.submitted
+}
+
+// SubmitError holds the message of the error returned by the last
+// submission, or the empty string.
+func (f *Form) SubmitError() *state.Signal[string] {
+	return f.submitErr
+}
+
+// CanSubmit reports whether Submit would start a submission.
+func (f *Form) CanSubmit() bool {
+	return f.valid.Get() && !f.submitting.Get()
+}
+
+// Submit shows the errors of every field and, if the form is valid, calls
+// the OnSubmit function in the background. After a successful submission
+// the current values become the initial ones, so the form is no longer
+// dirty. Submit reports whether a submission was started.
+func (f *Form) Submit() bool {
+	f.submitted.Set(true)
+	if !f.CanSubmit() {
+		return false
+	}
+	f.submitErr.Set("")
+	if f.onSubmit == nil {
+		f.markPristine()
+		return true
+	}
+	f.submitting.Set(true)
+	go func() {
+		err := f.onSubmit(context.Background())
+		f.post(func() {
+			if err != nil {
+				f.submitErr.Set(err.Error())
+			} else {
+				f.markPristine()
+			}
+			f.submitting.Set(false)
+		})
+	}()
+	return true
+}
+
+// attach records the context of the window showing the form, for the
+// results of background work to reach the form on its UI goroutine. The
+// rows and submit buttons of the form call it as they are laid out.
+func (f *Form) attach(ctx *core.Context) {
+	f.ui.Store(ctx)
+}
+
+// post runs fn, which changes the state of the form, on the UI goroutine
+// of the window showing the form, so that it does not race with the
+// changes the user makes there. It runs fn at once while the form is not
+// shown.
+func (f *Form) post(fn func()) {
+	if ctx := f.ui.Load(); ctx != nil {
+		ctx.Post(fn)
+		return
+	}
+	fn()
+}
+
+// Reset restores every field to its initial value and forgets that the
+// fields were touched and the form submitted.
+func (f *Form) Reset() {
+	for _, fd := range f.snapshot() {
+		fd.reset()
+	}
+	f.submitted.Set(false)
+	f.submitErr.Set("")
+	f.update()
+}
+
+func (f *Form) markPristine() {
+	for _, fd := range f.snapshot() {
+		fd.markPristine()
+	}
+	f.update()
+}
+
+func (f *Form) snapshot() []field {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]field(nil), f.fields...)
+}
+
+func (f *Form) add(fd field) {
+	f.mu.Lock()
+	f.fields = append(f.fields, fd)
+	f.mu.Unlock()
+	f.update()
+}
+
+// update recomputes the combined state after a field changed.
+func (f *Form) update() {
+	valid, validating, dirty := true, false, false
+	for _, fd := range f.snapshot() {
+		v, p, d := fd.status()
+		valid = valid && v && !p
+		validating = validating || p
+		dirty = dirty || d
+	}
+	f.valid.Set(valid)
+	f.validating.Set(validating)
+	f.dirty.Set(dirty)
+}
@@ -0,0 +1,72 @@
+package forms
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+)
+
+var regexpDigits = regexp.MustCompile(`^[0-9]+$`)
+
+func TestFormSubmit(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		submit    func(context.Context) error
+		started   bool
+		posted    bool
+		dirty     bool
+		submitErr string
+	}{
+		{"invalid", "", func(context.Context) error { return nil }, false, false, true, ""},
+		{"no handler", "ada", nil, true, false, false, ""},
+		{"succeeds", "ada", func(context.Context) error { return nil }, true, true, false, ""},
+		{"fails", "ada", func(context.Context) error { return errors.New("offline") }, true, true, true, "offline"},
+	}
+	for _, tt := range tests {
+		form, ctx := shown()
+		form.OnSubmit(tt.submit)
+		name := NewField(form, "name", "x").Validate(Required[string](""))
+		name.Value().Set(tt.value)
+		if got := form.Submit(); got != tt.started || !form.Submitted().Get() {
+			t.Errorf("%s: Submit = %v, submitted %v", tt.name, got, form.Submitted().Get())
+		}
+		if tt.posted {
+			if !form.Submitting().Get() || form.CanSubmit() || form.Submit() {
+				t.Errorf("%s: a running submission can be started again", tt.name)
+			}
+			runPosted(t, ctx)
+		}
+		if form.Submitting().Get() || form.Dirty().Get() != tt.dirty || form.SubmitError().Get() != tt.submitErr {
+			t.Errorf("%s: submitting %v, dirty %v, error %q", tt.name, form.Submitting().Get(), form.Dirty().Get(), form.SubmitError().Get())
+		}
+		if !tt.dirty && name.Initial() != tt.value {
+			t.Errorf("%s: after submitting the initial value is %q", tt.name, name.Initial())
+		}
+	}
+}
+
+func TestFormReset(t *testing.T) {
+	form := New()
+	if !form.Valid().Get() || form.Dirty().Get() || len(form.Fields()) != 0 {
+		t.Fatal("an empty form is not valid and pristine")
+	}
+	name := NewField(form, "name", "ada").Validate(Required[string](""))
+	age := NewField(form, "age", 30).Validate(Range(0, 150, ""))
+	name.Value().Set("")
+	age.Value().Set(200)
+	name.Touch()
+	form.Submit()
+	form.SubmitError().Set("offline")
+	if form.Valid().Get() || !form.Dirty().Get() {
+		t.Fatal("the changes did not make the form invalid and dirty")
+	}
+	form.Reset()
+	if name.Value().Get() != "ada" || age.Value().Get() != 30 || name.Touched().Get() {
+		t.Errorf("reset to %q and %d", name.Value().Get(), age.Value().Get())
+	}
+	if !form.Valid().Get() || form.Dirty().Get() || form.Submitted().Get() || form.SubmitError().Get() != "" {
+		t.Error("Reset kept the state of the form")
+	}
+}
@@ -0,0 +1,150 @@
+package forms
+
+import (
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/theme"
+)
+
+const rowGap float32 = 4
+
+// Row lays out a field's label above its input and a message below: the
+// field's error, a note while it is being checked, or a hint.
+//
+// The field is touched when focus leaves the input, so its error appears
+// only after the user has had a chance to fill it in. The message line is
+// always reserved, so errors appearing do not move the rows below.
+type Row struct {
+	core.WidgetBase
+
+	field   FieldState
+	label   string
+	hint    string
+	input   core.Widget
+	cancels []func()
+	within  bool // focus was inside the input at the last layout
+
+	labelH    float32
+	msgH      float32
+	inputSize core.Size
+}
+
+// NewRow returns a row showing input for field under label, which may be
+// empty.
+func NewRow(field FieldState, label string, input core.Widget) *Row {
+	r := &Row{field: field, label: label, input: input}
+	r.SetChildren(input)
+	return r
+}
+
+// Hint sets a note shown under the input while there is no error.
+func (r *Row) Hint(s string) *Row {
+	r.hint = s
+	return r
+}
+
+// Input returns the input widget.
+func (r *Row) Input() core.Widget {
+	return r.input
+}
+
+// message returns the text under the input and whether it is an error.
+func (r *Row) message() (string, bool) {
+	if err := r.field.ShownError(); err != "" {
+		return err, true
+	}
+	if r.field.Validating().Get() {
+		return "Checking…", false
+	}
+	return r.hint, false
+}
+
+// Release stops following the state of the field, for when the row
+// leaves the tree. Laid out again, the row follows it from then.
+func (r *Row) Release() {
+	for _, cancel := range r.cancels {
+		cancel()
+	}
+	r.cancels = nil
+}
+
+// Layout implements core.Widget.
+func (r *Row) Layout(ctx *core.LayoutContext) core.Size {
+	r.field.Form().attach(ctx.Context)
+	if r.cancels == nil {
+		c := ctx.Context
+		relayout := func() { c.MarkNeedsLayout(r) }
+		redraw := func(bool) { c.Post(relayout) }
+		r.cancels = []func(){
+			r.field.Error().Subscribe(func(string) { c.Post(relayout) }),
+			r.field.Touched().Subscribe(redraw),
+			r.field.Validating().Subscribe(redraw),
+			r.field.Form().Submitted().Subscribe(redraw),
+		}
+	}
+	r.trackFocus(ctx.Context)
+
+	th := theme.From(ctx.Context)
+	r.labelH = 0
+	if r.label != "" {
+		r.labelH = th.Typography.Label.LineHeight() + rowGap
+	}
+	r.msgH = rowGap + th.Typography.Caption.LineHeight()
+	c := ctx.Constraints.Loosen()
+	if c.HasBoundedHeight() {
+		c.MaxHeight = max(0, c.MaxHeight-r.labelH-r.msgH)
+	}
+	in := ctx.Measure(r.input, c)
+	r.inputSize = in
+	w := in.Width
+	if r.label != "" {
+		w = max(w, ctx.MeasureText(r.label, th.Typography.Label).Width)
+	}
+	return ctx.Constraints.Constrain(core.Sz(w, r.labelH+in.Height+r.msgH))
+}
+
+// trackFocus touches the field once focus has left the input. Focus
+// changes only repaint, so it runs on paint as well as on layout.
+func (r *Row) trackFocus(ctx *core.Context) {
+	within := core.PathTo(r.input, ctx.Focused()) != nil
+	if r.within && !within {
+		r.field.Touch()
+	}
+	r.within = within
+}
+
+// SetBounds implements core.Widget.
+func (r *Row) SetBounds(b core.Rect) {
+	r.WidgetBase.SetBounds(b)
+	r.input.SetBounds(core.R(b.X, b.Y+r.labelH, r.inputSize.Width, r.inputSize.Height))
+}
+
+// Paint implements core.Widget.
+func (r *Row) Paint(ctx *core.PaintContext) {
+	r.trackFocus(ctx.Context)
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	b := r.Bounds()
+	msg, isErr := r.message()
+	if r.label != "" {
+		color := th.Colors.OnSurfaceVariant
+		if isErr {
+			color = th.Colors.Error
+		}
+		cv.DrawText(r.label, core.Pt(b.X, b.Y), theme.TextStyle(th.Typography.Label, color))
+	}
+	r.input.Paint(ctx)
+	in := r.input.Bounds()
+	if isErr {
+		cv.DrawRoundedRect(in, th.Radii.Small, core.Stroked(th.Colors.Error, 1))
+	}
+	if msg != "" {
+		color := th.Colors.OnSurfaceVariant
+		if isErr {
+			color = th.Colors.Error
+		}
+		cv.Save()
+		cv.Clip(core.R(b.X, in.Bottom(), b.Width, r.msgH))
+		cv.DrawText(msg, core.Pt(b.X, in.Bottom()+rowGap), theme.TextStyle(th.Typography.Caption, color))
+		cv.Restore()
+	}
+}
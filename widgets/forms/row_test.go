@@ -0,0 +1,123 @@
+package forms
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/theme"
+	"github.com/gogpu/ui/widgets"
+)
+
+// textCanvas records the text drawn on it.
+type textCanvas struct {
+	core.Recording
+	texts []string
+}
+
+func (c *textCanvas) DrawText(text string, pos core.Point, st core.TextStyle) {
+	c.texts = append(c.texts, text)
+	c.Recording.DrawText(text, pos, st)
+}
+
+// paintedTexts paints w and returns the text it drew.
+func paintedTexts(ctx *core.Context, w core.Widget) []string {
+	cv := &textCanvas{}
+	w.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+	return cv.texts
+}
+
+func TestRowLayout(t *testing.T) {
+	form := New()
+	name := NewField(form, "name", "")
+	input := widgets.NewTextField()
+	row := NewRow(name, "User name", input)
+	ctx := core.NewContext()
+	th := theme.From(ctx)
+	ctx.LayoutRoot(row, core.R(0, 0, 300, 200))
+	labelH := th.Typography.Label.LineHeight() + rowGap
+	msgH := rowGap + th.Typography.Caption.LineHeight()
+	if got := input.Bounds(); got.Y != labelH || got.Width == 0 {
+		t.Errorf("the input at %v, want below the label", got)
+	}
+	lc := &core.LayoutContext{Context: ctx}
+	if got := lc.Measure(row, core.Loose(core.Sz(300, 200))); got.Height != labelH+input.Bounds().Height+msgH {
+		t.Errorf("height %v, want room for the label, input and message", got.Height)
+	}
+	// Without a label the input is at the top.
+	bare := NewRow(name, "", widgets.NewTextField())
+	ctx.LayoutRoot(bare, core.R(0, 0, 300, 200))
+	if got := bare.Input().Bounds(); got.Y != 0 {
+		t.Errorf("without a label the input at %v", got)
+	}
+	if form.ui.Load() != ctx {
+		t.Error("laying out the row did not attach the form")
+	}
+}
+
+func TestRowRelease(t *testing.T) {
+	form := New()
+	name := NewField(form, "name", "")
+	row := NewRow(name, "Name", widgets.NewTextField())
+	ctx := core.NewContext()
+	bounds := core.R(0, 0, 300, 200)
+	ctx.LayoutRoot(row, bounds)
+	row.Release()
+	name.Touch()
+	if ctx.HasPosted() {
+		t.Error("released, the row still follows the field")
+	}
+	ctx.Invalidate()
+	ctx.LayoutRoot(row, bounds)
+	name.Touched().Set(false)
+	if !ctx.HasPosted() {
+		t.Error("laid out again, the row does not follow the field")
+	}
+}
+
+func TestRowMessage(t *testing.T) {
+	form, _ := shown()
+	name := NewField(form, "name", "").Validate(Required[string]("Enter a name"))
+	input := widgets.NewTextField().Bind(name.Value())
+	row := NewRow(name, "Name", input).Hint("As on your passport")
+	ctx := core.NewContext()
+	bounds := core.R(0, 0, 300, 200)
+	ctx.LayoutRoot(row, bounds)
+	if got := paintedTexts(ctx, row); !slices.Equal(got, []string{"Name", "As on your passport"}) {
+		t.Errorf("untouched drew %q", got)
+	}
+
+	// Leaving the input touches the field and shows its error.
+	// Moving focus only repaints, which is enough.
+	ctx.RequestFocus(input)
+	paintedTexts(ctx, row)
+	if name.Touched().Get() {
+		t.Fatal("focusing the input touched the field")
+	}
+	ctx.RequestFocus(nil)
+	paintedTexts(ctx, row)
+	if !name.Touched().Get() {
+		t.Fatal("leaving the input did not touch the field")
+	}
+	runPosted(t, ctx)
+	ctx.LayoutRoot(row, bounds)
+	if got := paintedTexts(ctx, row); !slices.Equal(got, []string{"Name", "Enter a name"}) {
+		t.Errorf("touched drew %q", got)
+	}
+	if msg, isErr := row.message(); msg != "Enter a name" || !isErr {
+		t.Errorf("the message is %q, an error %v", msg, isErr)
+	}
+
+	// The row is laid out again as the error changes.
+	name.Value().Set("ada")
+	runPosted(t, ctx)
+	ctx.LayoutRoot(row, bounds)
+	if got := paintedTexts(ctx, row); !slices.Equal(got, []string{"Name", "ada", "As on your passport"}) {
+		t.Errorf("valid drew %q", got)
+	}
+
+	name.validating.Set(true)
+	if msg, isErr := row.message(); msg != "Checking…" || isErr {
+		t.Errorf("while checking the message is %q", msg)
+	}
+}
@@ -0,0 +1,151 @@
+package forms
+
+import (
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/theme"
+)
+
+const (
+	buttonHeight   float32 = 36
+	buttonPadding  float32 = 16
+	buttonMinWidth float32 = 80
+)
+
+// SubmitButton submits its form when clicked, or when Space or Enter is
+// pressed while it is focused. It is disabled while the form cannot be
+// submitted: a field is invalid or still being checked, or a submission
+// is running. Pressing it while the form is invalid reveals the errors of
+// the fields the user has not touched yet.
+type SubmitButton struct {
+	core.WidgetBase
+	core.FocusState
+
+	form      *Form
+	label     string
+	busyLabel string
+	cancels   []func()
+
+	hover   bool
+	pressed bool
+}
+
+// NewSubmitButton returns a button submitting form.
+func NewSubmitButton(form *Form, label string) *SubmitButton {
+	return &SubmitButton{form: form, label: label}
+}
+
+// BusyLabel sets the label shown while the form is being submitted, such
+// as "Saving…". By default the label does not change.
+func (b *SubmitButton) BusyLabel(s string) *SubmitButton {
+	b.busyLabel = s
+	return b
+}
+
+func (b *SubmitButton) text() string {
+	if b.busyLabel != "" && b.form.submitting.Get() {
+		return b.busyLabel
+	}
+	return b.label
+}
+
+// Release stops following the state of the form, for when the button
+// leaves the tree. Laid out again, the button follows it from then.
+func (b *SubmitButton) Release() {
+	for _, cancel := range b.cancels {
+		cancel()
+	}
+	b.cancels = nil
+}
+
+// Layout implements core.Widget.
+func (b *SubmitButton) Layout(ctx *core.LayoutContext) core.Size {
+	b.form.attach(ctx.Context)
+	if b.cancels == nil {
+		c := ctx.Context
+		redraw := func(bool) { c.Post(func() { c.Repaint(b) }) }
+		b.cancels = []func(){b.form.valid.Subscribe(redraw), b.form.submitting.Subscribe(redraw)}
+	}
+	style := theme.From(ctx.Context).Typography.Label
+	w := max(ctx.MeasureText(b.label, style).Width, ctx.MeasureText(b.busyLabel, style).Width)
+	return ctx.Constraints.Constrain(core.Sz(max(buttonMinWidth, w+2*buttonPadding), buttonHeight))
+}
+
+// Paint implements core.Widget.
+func (b *SubmitButton) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	r := b.Bounds()
+	radius := th.Radii.Small
+	if th.Design == theme.DesignMaterial {
+		radius = r.Height / 2
+	}
+	enabled := b.form.CanSubmit()
+	fill, text := th.Colors.Primary, th.Colors.OnPrimary
+	if !enabled {
+		fill, text = th.Colors.OnSurface.WithAlpha(0.12), th.Colors.OnSurfaceVariant.WithAlpha(0.5)
+	}
+	cv.DrawRoundedRect(r, radius, core.Filled(fill))
+	if enabled {
+		switch {
+		case b.pressed && b.hover:
+			cv.DrawRoundedRect(r, radius, core.Filled(th.Colors.OnPrimary.WithAlpha(0.12)))
+		case b.hover:
+			cv.DrawRoundedRect(r, radius, core.Filled(th.Colors.OnPrimary.WithAlpha(0.08)))
+		}
+	}
+	if b.IsFocused() {
+		ring := r.Inset(core.UniformInsets(-3))
+		cv.DrawRoundedRect(ring, radius+3, core.Stroked(th.Colors.Primary, 2))
+	}
+	s := b.text()
+	ts := theme.TextStyle(th.Typography.Label, text)
+	sz := ctx.MeasureText(s, ts)
+	cv.DrawText(s, core.Pt(r.X+(r.Width-sz.Width)/2, r.Y+(r.Height-ts.LineHeight())/2), ts)
+}
+
+func (b *SubmitButton) submit(ctx *core.Context) {
+	b.form.Submit()
+	ctx.MarkNeedsLayout(b)
+}
+
+// HandleEvent implements core.Widget.
+func (b *SubmitButton) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	switch e := ev.(type) {
+	case event.MouseEvent:
+		switch e.Type {
+		case event.MouseEnter:
+			b.hover = true
+			ctx.Repaint(b)
+		case event.MouseLeave:
+			b.hover = false
+			ctx.Repaint(b)
+		case event.MouseDown:
+			if e.Button != event.ButtonLeft {
+				return core.Ignored
+			}
+			b.pressed = true
+			ctx.RequestFocus(b)
+			ctx.CapturePointer(b)
+			ctx.Repaint(b)
+			return core.Handled
+		case event.MouseUp:
+			if !b.pressed || e.Button != event.ButtonLeft {
+				return core.Ignored
+			}
+			b.pressed = false
+			ctx.ReleasePointer()
+			ctx.Repaint(b)
+			if b.Bounds().Contains(e.Position) {
+				b.submit(ctx)
+			}
+			return core.Handled
+		}
+	case event.KeyEvent:
+		if b.IsFocused() && e.Type == event.KeyPress && e.Modifiers == 0 && (e.Key == event.KeySpace || e.Key == event.KeyEnter) {
+			b.submit(ctx)
+			return core.Handled
+		}
+	}
+	return core.Ignored
+}
@@ -0,0 +1,173 @@
+package forms
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/theme"
+)
+
+func leftMouse(typ event.MouseEventType, p core.Point) event.MouseEvent {
+	return event.MouseEvent{Type: typ, Button: event.ButtonLeft, Position: p}
+}
+
+// fillCanvas records the fills of the rounded rectangles drawn on it.
+type fillCanvas struct {
+	textCanvas
+	fills []core.Color
+}
+
+func (c *fillCanvas) DrawRoundedRect(r core.Rect, radius float32, st core.RectStyle) {
+	c.fills = append(c.fills, st.Fill)
+	c.Recording.DrawRoundedRect(r, radius, st)
+}
+
+func TestSubmitButtonLayout(t *testing.T) {
+	lc := &core.LayoutContext{Context: core.NewContext()}
+	style := theme.From(lc.Context).Typography.Label
+	long := "Create a new account"
+	tests := []struct {
+		name   string
+		button *SubmitButton
+		width  float32
+	}{
+		{"short", NewSubmitButton(New(), "OK"), buttonMinWidth},
+		{"long", NewSubmitButton(New(), long), lc.MeasureText(long, style).Width + 2*buttonPadding},
+		{"long busy label", NewSubmitButton(New(), "OK").BusyLabel(long), lc.MeasureText(long, style).Width + 2*buttonPadding},
+	}
+	for _, tt := range tests {
+		if got := lc.Measure(tt.button, core.Unbounded()); got != core.Sz(tt.width, buttonHeight) {
+			t.Errorf("%s: size %v, want %v wide", tt.name, got, tt.width)
+		}
+	}
+}
+
+func TestSubmitButtonSubmit(t *testing.T) {
+	form, ctx := shown()
+	release := make(chan struct{})
+	form.OnSubmit(func(context.Context) error {
+		<-release
+		return nil
+	})
+	name := NewField(form, "name", "").Validate(Required[string](""))
+	b := NewSubmitButton(form, "Save").BusyLabel("Saving…")
+	bounds := core.R(0, 0, 100, buttonHeight)
+	ctx.LayoutRoot(b, bounds)
+	paint := func() *fillCanvas {
+		cv := &fillCanvas{}
+		b.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+		return cv
+	}
+	th := theme.From(ctx)
+	if cv := paint(); !slices.Equal(cv.texts, []string{"Save"}) || cv.fills[0] == th.Colors.Primary {
+		t.Errorf("an invalid form drew %q on %v", cv.texts, cv.fills)
+	}
+
+	// A click on the disabled button reveals the errors.
+	center := b.Bounds().Center()
+	b.HandleEvent(ctx, leftMouse(event.MouseDown, center))
+	b.HandleEvent(ctx, leftMouse(event.MouseUp, center))
+	if !form.Submitted().Get() || name.ShownError() != "Required" || form.Submitting().Get() {
+		t.Errorf("the click submitted %v, shows %q", form.Submitted().Get(), name.ShownError())
+	}
+
+	name.Value().Set("ada")
+	if cv := paint(); cv.fills[0] != th.Colors.Primary {
+		t.Errorf("a valid form drew the button in %v", cv.fills[0])
+	}
+	ctx.RequestFocus(b)
+	if r := b.HandleEvent(ctx, event.KeyEvent{Type: event.KeyPress, Key: event.KeyEnter}); r != core.Handled || !form.Submitting().Get() {
+		t.Fatal("Enter did not submit")
+	}
+	if cv := paint(); !slices.Equal(cv.texts, []string{"Saving…"}) {
+		t.Errorf("submitting drew %q", cv.texts)
+	}
+	close(release)
+	for form.Submitting().Get() {
+		runPosted(t, ctx)
+	}
+	if form.Submitting().Get() || form.Dirty().Get() || name.Initial() != "ada" {
+		t.Error("the submission did not finish")
+	}
+	if cv := paint(); !slices.Equal(cv.texts, []string{"Save"}) {
+		t.Errorf("after submitting drew %q", cv.texts)
+	}
+}
+
+func TestSubmitButtonRelease(t *testing.T) {
+	form := New()
+	b := NewSubmitButton(form, "Save")
+	ctx := core.NewContext()
+	bounds := core.R(0, 0, 100, buttonHeight)
+	ctx.LayoutRoot(b, bounds)
+	b.Release()
+	form.valid.Set(false)
+	if ctx.HasPosted() {
+		t.Error("released, the button still follows the form")
+	}
+	ctx.Invalidate()
+	ctx.LayoutRoot(b, bounds)
+	form.valid.Set(true)
+	if !ctx.HasPosted() {
+		t.Error("laid out again, the button does not follow the form")
+	}
+}
+
+func TestSubmitButtonEvents(t *testing.T) {
+	form := New()
+	var submits int
+	b := NewSubmitButton(form, "Save")
+	ctx := core.NewContext()
+	ctx.LayoutRoot(b, core.R(0, 0, 100, buttonHeight))
+	count := func() {
+		if form.Submitted().Get() {
+			submits++
+			form.submitted.Set(false)
+		}
+	}
+	center := b.Bounds().Center()
+
+	tests := []struct {
+		name   string
+		events []core.Event
+		res    core.EventResult
+		submit bool
+	}{
+		{"click", []core.Event{leftMouse(event.MouseDown, center), leftMouse(event.MouseUp, center)}, core.Handled, true},
+		{"released off", []core.Event{leftMouse(event.MouseDown, center), leftMouse(event.MouseUp, core.Pt(500, 5))}, core.Handled, false},
+		{"right press", []core.Event{event.MouseEvent{Type: event.MouseDown, Button: event.ButtonRight, Position: center}}, core.Ignored, false},
+		{"release alone", []core.Event{leftMouse(event.MouseUp, center)}, core.Ignored, false},
+		{"Space", []core.Event{event.KeyEvent{Type: event.KeyPress, Key: event.KeySpace}}, core.Handled, true},
+		{"Ctrl+Enter", []core.Event{event.KeyEvent{Type: event.KeyPress, Key: event.KeyEnter, Modifiers: event.ModCtrl}}, core.Ignored, false},
+	}
+	for _, tt := range tests {
+		var res core.EventResult
+		for _, ev := range tt.events {
+			res = b.HandleEvent(ctx, ev)
+		}
+		before := submits
+		count()
+		if res != tt.res || (submits > before) != tt.submit {
+			t.Errorf("%s: %v, submitted %v", tt.name, res, submits > before)
+		}
+		if ctx.PointerCapture() != nil {
+			t.Errorf("%s: the pointer is still captured", tt.name)
+		}
+	}
+
+	b.HandleEvent(ctx, event.MouseEvent{Type: event.MouseEnter})
+	if !b.hover {
+		t.Error("entering did not hover the button")
+	}
+	b.HandleEvent(ctx, event.MouseEvent{Type: event.MouseLeave})
+	if b.hover {
+		t.Error("the hover stayed after the pointer left")
+	}
+	ctx.RequestFocus(nil)
+	if r := b.HandleEvent(ctx, event.KeyEvent{Type: event.KeyPress, Key: event.KeySpace}); r != core.Ignored {
+		t.Error("an unfocused button handled Space")
+	}
+}
@@ -0,0 +1,79 @@
+package forms
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/gogpu/ui/grapheme"
+)
+
+// Required rejects the zero value, such as an empty string, with msg or
+// "Required" if msg is empty.
+func Required[T comparable](msg string) Validator[T] {
+	err := errors.New(orDefault(msg, "Required"))
+	return func(v T) error {
+		var zero T
+		if v == zero {
+			return err
+		}
+		return nil
+	}
+}
+
+// MinLength rejects non-empty strings of fewer than n characters, counted
+// as grapheme clusters so an emoji sequence is one character. Empty
+// strings pass, so that optional fields can have a minimum; combine it
+// with Required otherwise.
+func MinLength(n int, msg string) Validator[string] {
+	err := errors.New(orDefault(msg, fmt.Sprintf("At least %d characters", n)))
+	return func(v string) error {
+		if v != "" && grapheme.Count([]rune(v)) < n {
+			return err
+		}
+		return nil
+	}
+}
+
+// MaxLength rejects strings of more than n characters, counted as
+// grapheme clusters.
+func MaxLength(n int, msg string) Validator[string] {
+	err := errors.New(orDefault(msg, fmt.Sprintf("At most %d characters", n)))
+	return func(v string) error {
+		if grapheme.Count([]rune(v)) > n {
+			return err
+		}
+		return nil
+	}
+}
+
+// Pattern rejects non-empty strings that re does not match, with msg or
+// "Invalid format" if msg is empty.
+func Pattern(re *regexp.Regexp, msg string) Validator[string] {
+	err := errors.New(orDefault(msg, "Invalid format"))
+	return func(v string) error {
+		if v != "" && !re.MatchString(v) {
+			return err
+		}
+		return nil
+	}
+}
+
+// Range rejects values outside lo to hi, inclusive.
+func Range[T cmp.Ordered](lo, hi T, msg string) Validator[T] {
+	err := errors.New(orDefault(msg, fmt.Sprintf("Between %v and %v", lo, hi)))
+	return func(v T) error {
+		if v < lo || v > hi {
+			return err
+		}
+		return nil
+	}
+}
+
+func orDefault(msg, def string) string {
+	if msg == "" {
+		return def
+	}
+	return msg
+}
@@ -0,0 +1,63 @@
+package forms
+
+import "testing"
+
+// errText returns the message of err, or the empty string if it is nil.
+func errText(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+func TestStringValidators(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   Validator[string]
+		v    string
+		want string
+	}{
+		{"required empty", Required[string](""), "", "Required"},
+		{"required custom", Required[string]("Enter a name"), "", "Enter a name"},
+		{"required set", Required[string](""), "x", ""},
+		{"min short", MinLength(3, ""), "ab", "At least 3 characters"},
+		{"min enough", MinLength(3, ""), "abc", ""},
+		{"min empty", MinLength(3, ""), "", ""},
+		{"min graphemes", MinLength(2, "too short"), "👍🏽", "too short"},
+		{"max long", MaxLength(2, ""), "abc", "At most 2 characters"},
+		{"max graphemes", MaxLength(1, ""), "👨‍👩‍👧", ""},
+		{"max empty", MaxLength(0, ""), "", ""},
+		{"pattern mismatch", Pattern(regexpDigits, ""), "12a", "Invalid format"},
+		{"pattern match", Pattern(regexpDigits, "digits only"), "123", ""},
+		{"pattern empty", Pattern(regexpDigits, ""), "", ""},
+	}
+	for _, tt := range tests {
+		if got := errText(tt.fn(tt.v)); got != tt.want {
+			t.Errorf("%s: %q gives %q, want %q", tt.name, tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestRange(t *testing.T) {
+	tests := []struct {
+		v    int
+		want string
+	}{
+		{0, "Between 1 and 10"},
+		{1, ""},
+		{10, ""},
+		{11, "Between 1 and 10"},
+	}
+	fn := Range(1, 10, "")
+	for _, tt := range tests {
+		if got := errText(fn(tt.v)); got != tt.want {
+			t.Errorf("%d gives %q, want %q", tt.v, got, tt.want)
+		}
+	}
+	if got := errText(Range(0.5, 1.5, "out of range")(2)); got != "out of range" {
+		t.Errorf("a custom message gives %q", got)
+	}
+	if got := errText(Required[int]("")(0)); got != "Required" {
+		t.Errorf("a zero int gives %q", got)
+	}
+}
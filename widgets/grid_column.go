@@ -0,0 +1,558 @@
+package widgets
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/internal/scroll"
+	"github.com/gogpu/ui/theme"
+)
+
+// CellAlign selects the horizontal alignment of cell content.
+type CellAlign uint8
+
+// Cell alignments.
+const (
+	CellAlignStart CellAlign = iota
+	CellAlignCenter
+	CellAlignEnd
+)
+
+// SortDirection is the sort order of a column.
+type SortDirection uint8
+
+// Sort directions.
+const (
+	SortNone SortDirection = iota
+	SortAscending
+	SortDescending
+)
+
+const (
+	defaultColumnWidth    float32 = 120
+	defaultMinColumnWidth float32 = 32
+	defaultHeaderHeight   float32 = 30
+	resizeGrip            float32 = 4
+	reorderThreshold      float32 = 6
+	cellPadding           float32 = 8
+)
+
+// Column describes a column of a DataGrid or TreeTable.
+type Column struct {
+	// Key identifies the column when asking the row provider for values.
+	Key string
+
+	// Title is the header label.
+	Title string
+
+	// Width is the current width. Zero selects a default width.
+	Width float32
+
+	// MinWidth and MaxWidth bound interactive resizing. Zero MaxWidth means
+	// unbounded.
+	MinWidth, MaxWidth float32
+
+	// Sortable enables sorting by clicking the header.
+	Sortable bool
+
+	// Frozen keeps the column pinned at the start edge while the rest of
+	// the grid scrolls horizontally.
+	Frozen bool
+
+	// Align selects the alignment of cell text.
+	Align CellAlign
+
+	// Format converts a cell value to display text. The default uses
+	// fmt.Sprint.
+	Format func(value any) string
+
+	// Compare orders two cell values for sorting. The default compares
+	// numbers, strings and times natively and everything else by its
+	// formatted text.
+	Compare func(a, b any) int
+
+	// Paint, if set, replaces the default text rendering of cells.
+	Paint func(ctx *core.PaintContext, cell core.Rect, value any)
+}
+
+func (c *Column) format(v any) string {
+	if c.Format != nil {
+		return c.Format(v)
+	}
+	if v == nil {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
+
+func (c *Column) compare(a, b any) int {
+	if c.Compare != nil {
+		return c.Compare(a, b)
+	}
+	return compareValues(a, b, c.format)
+}
+
+func (c *Column) clampWidth(w float32) float32 {
+	lo := c.MinWidth
+	if lo <= 0 {
+		lo = defaultMinColumnWidth
+	}
+	hi := c.MaxWidth
+	if hi <= 0 {
+		hi = core.Infinity
+	}
+	return core.Clamp(w, lo, hi)
+}
+
+// compareValues orders values of common types without reflection.
+func compareValues(a, b any, format func(any) string) int {
+	switch x := a.(type) {
+	case int:
+		if y, ok := b.(int); ok {
+			return cmp.Compare(x, y)
+		}
+	case int64:
+		if y, ok := b.(int64); ok {
+			return cmp.Compare(x, y)
+		}
+	case uint64:
+		if y, ok := b.(uint64); ok {
+			return cmp.Compare(x, y)
+		}
+	case float64:
+		if y, ok := b.(float64); ok {
+			return cmp.Compare(x, y)
+		}
+	case float32:
+		if y, ok := b.(float32); ok {
+			return cmp.Compare(x, y)
+		}
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(strings.ToLower(x), strings.ToLower(y))
+		}
+	case time.Time:
+		if y, ok := b.(time.Time); ok {
+			return x.Compare(y)
+		}
+	case bool:
+		if y, ok := b.(bool); ok {
+			switch {
+			case x == y:
+				return 0
+			case !x:
+				return -1
+			default:
+				return 1
+			}
+		}
+	}
+	return strings.Compare(format(a), format(b))
+}
+
+// columnSet is the column model shared by DataGrid and TreeTable: display
+// order, frozen partition, sort state and the header interactions for
+// sorting, resizing and reordering. Columns run from the start edge of
+// the direction dir, which the owner sets on every layout.
+type columnSet struct {
+	cols    []*Column
+	sortKey string
+	sortDir SortDirection
+	dir     core.Direction
+
+	// Header interaction state. press is the column index under a pending
+	// button press, -1 if none.
+	press      int
+	pressX     float32
+	resizing   int
+	resizeW    float32
+	reordering bool
+	dragX      float32
+	dropIndex  int
+	hoverEdge  int
+}
+
+func newColumnSet(cols []Column) columnSet {
+	cs := columnSet{press: -1, resizing: -1, hoverEdge: -1}
+	cs.setColumns(cols)
+	return cs
+}
+
+// setColumns copies cols, moving frozen columns to the front while keeping
+// the relative order within each group.
+func (cs *columnSet) setColumns(cols []Column) {
+	cs.cols = cs.cols[:0]
+	for _, frozen := range []bool{true, false} {
+		for i := range cols {
+			if cols[i].Frozen == frozen {
+				c := cols[i]
+				if c.Width <= 0 {
+					c.Width = defaultColumnWidth
+				}
+				c.Width = c.clampWidth(c.Width)
+				cs.cols = append(cs.cols, &c)
+			}
+		}
+	}
+}
+
+func (cs *columnSet) index(key string) int {
+	for i, c := range cs.cols {
+		if c.Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func (cs *columnSet) frozenCount() int {
+	n := 0
+	for n < len(cs.cols) && cs.cols[n].Frozen {
+		n++
+	}
+	return n
+}
+
+func (cs *columnSet) frozenWidth() float32 {
+	var w float32
+	for _, c := range cs.cols[:cs.frozenCount()] {
+		w += c.Width
+	}
+	return w
+}
+
+// scrollableWidth returns the total width of the non-frozen columns.
+func (cs *columnSet) scrollableWidth() float32 {
+	var w float32
+	for _, c := range cs.cols[cs.frozenCount():] {
+		w += c.Width
+	}
+	return w
+}
+
+// offset returns the distance of column i from the start edge, given the
+// horizontal scroll offset of the non-frozen columns.
+func (cs *columnSet) offset(i int, scrollX float32) float32 {
+	nf := cs.frozenCount()
+	var x float32
+	if i >= nf {
+		x = cs.frozenWidth() - scrollX
+		for _, c := range cs.cols[nf:i] {
+			x += c.Width
+		}
+		return x
+	}
+	for _, c := range cs.cols[:i] {
+		x += c.Width
+	}
+	return x
+}
+
+// columnX returns the x position of column i within area, given the
+// horizontal scroll offset of the non-frozen columns.
+func (cs *columnSet) columnX(i int, area core.Rect, scrollX float32) float32 {
+	w := cs.cols[i].Width
+	return cs.dir.Mirror(core.R(area.X+cs.offset(i, scrollX), area.Y, w, area.Height), area).X
+}
+
+// edges returns the x positions of the start and the end edge of cell.
+func (cs *columnSet) edges(cell core.Rect) (start, end float32) {
+	if cs.dir.IsRTL() {
+		return cell.Right(), cell.X
+	}
+	return cell.X, cell.Right()
+}
+
+// endLine returns a line one pixel wide inside the end edge of cell.
+func (cs *columnSet) endLine(cell core.Rect) core.Rect {
+	if cs.dir.IsRTL() {
+		return core.R(cell.X, cell.Y, 1, cell.Height)
+	}
+	return core.R(cell.Right()-1, cell.Y, 1, cell.Height)
+}
+
+// frozenArea and scrollArea split area into the part showing the frozen
+// columns, along the start edge, and the part the others scroll in.
+func (cs *columnSet) frozenArea(area core.Rect) core.Rect {
+	return cs.dir.Mirror(core.R(area.X, area.Y, min(cs.frozenWidth(), area.Width), area.Height), area)
+}
+
+func (cs *columnSet) scrollArea(area core.Rect) core.Rect {
+	fw := min(cs.frozenWidth(), area.Width)
+	return cs.dir.Mirror(core.R(area.X+fw, area.Y, area.Width-fw, area.Height), area)
+}
+
+// underFrozen reports whether x lies under the frozen columns of area or
+// before them, where the scrolled columns are hidden.
+func (cs *columnSet) underFrozen(x float32, area core.Rect) bool {
+	if cs.dir.IsRTL() {
+		return x > area.Right()-cs.frozenWidth()
+	}
+	return x < area.X+cs.frozenWidth()
+}
+
+// barOffset converts between a horizontal scroll offset, measured from
+// the start edge, and the offset of a horizontal scrollbar, measured from
+// the left. It is its own inverse.
+func (cs *columnSet) barOffset(bar *scroll.Bar, off float32) float32 {
+	if cs.dir.IsRTL() {
+		return bar.MaxOffset() - off
+	}
+	return off
+}
+
+// columnAt returns the column under x, or -1.
+func (cs *columnSet) columnAt(x float32, area core.Rect, scrollX float32) int {
+	nf := cs.frozenCount()
+	for i := range cs.cols {
+		if i >= nf && cs.underFrozen(x, area) {
+			break
+		}
+		cx := cs.columnX(i, area, scrollX)
+		if x >= cx && x < cx+cs.cols[i].Width {
+			return i
+		}
+	}
+	return -1
+}
+
+// edgeAt returns the column whose end edge is within the resize grip of
+// x, or -1.
+func (cs *columnSet) edgeAt(x float32, area core.Rect, scrollX float32) int {
+	nf := cs.frozenCount()
+	for i := range cs.cols {
+		cx := cs.columnX(i, area, scrollX)
+		_, end := cs.edges(core.R(cx, area.Y, cs.cols[i].Width, area.Height))
+		if i >= nf && cs.underFrozen(end, area) {
+			continue
+		}
+		if x >= end-resizeGrip && x <= end+resizeGrip {
+			return i
+		}
+	}
+	return -1
+}
+
+// cycleSort advances the sort state of column i: ascending, descending,
+// then unsorted.
+func (cs *columnSet) cycleSort(i int) {
+	key := cs.cols[i].Key
+	if cs.sortKey != key {
+		cs.sortKey, cs.sortDir = key, SortAscending
+		return
+	}
+	switch cs.sortDir {
+	case SortAscending:
+		cs.sortDir = SortDescending
+	case SortDescending:
+		cs.sortKey, cs.sortDir = "", SortNone
+	default:
+		cs.sortDir = SortAscending
+	}
+}
+
+// move relocates column from to position to, staying within its frozen
+// group. It reports whether the order changed.
+func (cs *columnSet) move(from, to int) bool {
+	nf := cs.frozenCount()
+	lo, hi := 0, nf-1
+	if from >= nf {
+		lo, hi = nf, len(cs.cols)-1
+	}
+	to = min(max(to, lo), hi)
+	if to == from {
+		return false
+	}
+	c := cs.cols[from]
+	copy(cs.cols[from:], cs.cols[from+1:])
+	cs.cols = cs.cols[:len(cs.cols)-1]
+	cs.cols = append(cs.cols[:to], append([]*Column{c}, cs.cols[to:]...)...)
+	return true
+}
+
+// headerChange reports what a header interaction changed.
+type headerChange uint8
+
+const (
+	headerNoChange headerChange = iota
+	headerSorted
+	headerResized
+	headerReordered
+	headerHandled
+)
+
+// handleHeader processes pointer input for the header strip in area.
+func (cs *columnSet) handleHeader(ctx *core.Context, owner core.Widget, me event.MouseEvent, area core.Rect, scrollX float32) headerChange {
+	switch me.Type {
+	case event.MouseDown:
+		if me.Button != event.ButtonLeft || !area.Contains(me.Position) {
+			return headerNoChange
+		}
+		if e := cs.edgeAt(me.Position.X, area, scrollX); e >= 0 {
+			cs.resizing, cs.pressX, cs.resizeW = e, me.Position.X, cs.cols[e].Width
+			ctx.CapturePointer(owner)
+			return headerHandled
+		}
+		if c := cs.columnAt(me.Position.X, area, scrollX); c >= 0 {
+			cs.press, cs.pressX = c, me.Position.X
+			ctx.CapturePointer(owner)
+			return headerHandled
+		}
+	case event.MouseMove:
+		if cs.resizing >= 0 {
+			c := cs.cols[cs.resizing]
+			d := me.Position.X - cs.pressX
+			if cs.dir.IsRTL() {
+				d = -d
+			}
+			c.Width = c.clampWidth(cs.resizeW + d)
+			ctx.MarkNeedsLayout(owner)
+			return headerResized
+		}
+		if cs.press >= 0 {
+			if !cs.reordering && abs32(me.Position.X-cs.pressX) > reorderThreshold {
+				cs.reordering = true
+			}
+			if cs.reordering {
+				cs.dragX = me.Position.X
+				cs.dropIndex = cs.dropIndexAt(me.Position.X, area, scrollX)
+				ctx.Repaint(owner)
+			}
+			return headerHandled
+		}
+		if edge := cs.edgeAt(me.Position.X, area, scrollX); area.Contains(me.Position) && edge != cs.hoverEdge {
+			cs.hoverEdge = edge
+			ctx.Repaint(owner)
+		} else if !area.Contains(me.Position) && cs.hoverEdge >= 0 {
+			cs.hoverEdge = -1
+			ctx.Repaint(owner)
+		}
+	case event.MouseUp:
+		result := headerNoChange
+		switch {
+		case cs.resizing >= 0:
+			result = headerResized
+		case cs.reordering:
+			if cs.move(cs.press, cs.dropIndex) {
+				result = headerReordered
+			} else {
+				result = headerHandled
+			}
+		case cs.press >= 0:
+			if cs.cols[cs.press].Sortable {
+				cs.cycleSort(cs.press)
+				result = headerSorted
+			} else {
+				result = headerHandled
+			}
+		default:
+			return headerNoChange
+		}
+		cs.press, cs.resizing, cs.reordering = -1, -1, false
+		ctx.ReleasePointer()
+		// Sorting, resizing and reordering change the view and the width
+		// of the owner.
+		ctx.MarkNeedsLayout(owner)
+		return result
+	}
+	return headerNoChange
+}
+
+// dropIndexAt returns the index the dragged column would move to if
+// dropped at x.
+func (cs *columnSet) dropIndexAt(x float32, area core.Rect, scrollX float32) int {
+	for i := range cs.cols {
+		mid := cs.columnX(i, area, scrollX) + cs.cols[i].Width/2
+		if (x < mid) != cs.dir.IsRTL() {
+			if i > cs.press {
+				return i - 1
+			}
+			return i
+		}
+	}
+	return len(cs.cols) - 1
+}
+
+// paintHeader draws the header strip in area.
+func (cs *columnSet) paintHeader(ctx *core.PaintContext, area core.Rect, scrollX float32) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	cv.DrawRect(area, core.Filled(th.Colors.SurfaceVariant))
+	style := theme.TextStyle(th.Typography.Label, th.Colors.OnSurface)
+	nf := cs.frozenCount()
+	paintRange := func(from, to int, clip core.Rect) {
+		cv.Save()
+		cv.Clip(clip)
+		for i := from; i < to; i++ {
+			c := cs.cols[i]
+			cell := core.R(cs.columnX(i, area, scrollX), area.Y, c.Width, area.Height)
+			title := c.Title
+			if c.Key == cs.sortKey {
+				switch cs.sortDir {
+				case SortAscending:
+					title += " ▲"
+				case SortDescending:
+					title += " ▼"
+				}
+			}
+			drawCellText(ctx, cell, title, style, c.Align)
+			edge := th.Colors.Outline.WithAlpha(0.4)
+			if i == cs.hoverEdge || i == cs.resizing {
+				edge = th.Colors.Primary
+			}
+			cv.DrawRect(cs.endLine(cell), core.Filled(edge))
+		}
+		cv.Restore()
+	}
+	paintRange(nf, len(cs.cols), cs.scrollArea(area))
+	paintRange(0, nf, cs.frozenArea(area))
+	if cs.reordering {
+		drop := core.R(cs.columnX(cs.dropIndex, area, scrollX), area.Y, cs.cols[cs.dropIndex].Width, area.Height)
+		x, end := cs.edges(drop)
+		if cs.dropIndex > cs.press {
+			x = end
+		}
+		cv.DrawRect(core.R(x-1, area.Y, 2, area.Height), core.Filled(th.Colors.Primary))
+		ghost := core.R(cs.dragX-cs.cols[cs.press].Width/2, area.Y, cs.cols[cs.press].Width, area.Height)
+		cv.DrawRect(ghost, core.Filled(th.Colors.Primary.WithAlpha(0.15)))
+	}
+	cv.DrawRect(core.R(area.X, area.Bottom()-1, area.Width, 1), core.Filled(th.Colors.Outline.WithAlpha(0.6)))
+}
+
+// drawCellText draws text vertically centered in cell with horizontal
+// padding and alignment, clipped to the cell. The start and end edges
+// follow the direction of ctx.
+func drawCellText(ctx *core.PaintContext, cell core.Rect, text string, style core.TextStyle, align CellAlign) {
+	if text == "" {
+		return
+	}
+	size := ctx.MeasureText(text, style)
+	inner := cell.Inset(core.SymmetricInsets(cellPadding, 0))
+	x := inner.X
+	rtl := ctx.Direction().IsRTL()
+	switch {
+	case align == CellAlignCenter:
+		x += (inner.Width - size.Width) / 2
+	case (align == CellAlignEnd) != rtl:
+		x += inner.Width - size.Width
+	}
+	y := cell.Y + (cell.Height-size.Height)/2
+	ctx.Canvas.Save()
+	ctx.Canvas.Clip(inner)
+	ctx.Canvas.DrawText(text, core.Pt(x, y), style)
+	ctx.Canvas.Restore()
+}
+
+func abs32(v float32) float32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
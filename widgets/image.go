@@ -0,0 +1,409 @@
+package widgets
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // decoders for Image
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/theme"
+)
+
+// DefaultImageCacheBudget is the memory, in bytes of decoded pixels, that
+// the images of a window may keep cached.
+const DefaultImageCacheBudget = 64 << 20
+
+// ImageFit is how an Image fills its bounds.
+type ImageFit uint8
+
+// Fit modes.
+const (
+	// ImageContain scales the image to fit inside the bounds, keeping its
+	// aspect ratio, and centers it.
+	ImageContain ImageFit = iota
+	// ImageCover scales the image to cover the bounds, keeping its aspect
+	// ratio, and crops what overflows.
+	ImageCover
+	// ImageFill stretches the image to the bounds.
+	ImageFill
+)
+
+// ImageSource is where an Image loads from. Key identifies the image in
+// the window's cache, so images with the same key are loaded and decoded
+// once; Open returns the encoded data, in any format registered with the
+// image package. PNG, JPEG and GIF are registered.
+type ImageSource struct {
+	Key  string
+	Open func() (io.ReadCloser, error)
+}
+
+// FileImage returns the source of the image file at path.
+func FileImage(path string) ImageSource {
+	return ImageSource{Key: "file:" + path, Open: func() (io.ReadCloser, error) {
+		return os.Open(path)
+	}}
+}
+
+// URLImage returns the source of the image at an http or https URL.
+func URLImage(url string) ImageSource {
+	return ImageSource{Key: url, Open: func() (io.ReadCloser, error) {
+		resp, err := http.Get(url)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("widgets: loading %s: %s", url, resp.Status)
+		}
+		return resp.Body, nil
+	}}
+}
+
+// ReaderImage returns the source of the image read from r. r is read once,
+// so once the image is evicted from the cache only the Images already
+// showing it keep it.
+func ReaderImage(key string, r io.Reader) ImageSource {
+	var read atomic.Bool
+	return ImageSource{Key: key, Open: func() (io.ReadCloser, error) {
+		if read.Swap(true) {
+			return nil, errors.New("widgets: image " + key + " was already read")
+		}
+		return io.NopCloser(r), nil
+	}}
+}
+
+// Image displays an image decoded in the background from an ImageSource,
+// or one given directly with SetImage.
+//
+// While the image loads a placeholder is shown, by default a plain tinted
+// box, and if it fails the error widget is. Without an explicit Size an
+// Image takes the size of the image in logical pixels, scaled down to fit
+// the constraints, and the size of the placeholder until it is loaded.
+type Image struct {
+	core.WidgetBase
+
+	src         ImageSource
+	img         image.Image
+	err         error
+	fit         ImageFit
+	size        core.Size
+	placeholder core.Widget
+	errWidget   core.Widget
+
+	gen     int // incremented when the source changes, to drop stale loads
+	started bool
+}
+
+// NewImage returns an Image loading from src.
+func NewImage(src ImageSource) *Image {
+	return &Image{src: src}
+}
+
+// SetSource starts showing the image from src.
+func (m *Image) SetSource(src ImageSource) {
+	m.src, m.img, m.err = src, nil, nil
+	m.gen++
+	m.started = false
+	m.updateChildren()
+}
+
+// SetImage shows img, which is already decoded.
+func (m *Image) SetImage(img image.Image) {
+	m.src, m.img, m.err = ImageSource{}, img, nil
+	m.gen++
+	m.updateChildren()
+}
+
+// Image returns the image shown, or nil while it loads or if it failed.
+func (m *Image) Image() image.Image {
+	return m.img
+}
+
+// Err returns why the image failed to load, or nil.
+func (m *Image) Err() error {
+	return m.err
+}
+
+// Fit sets how the image fills its bounds. The default is ImageContain.
+func (m *Image) Fit(fit ImageFit) *Image {
+	m.fit = fit
+	return m
+}
+
+// Size sets the preferred size, which is otherwise the image's.
+func (m *Image) Size(w, h float32) *Image {
+	m.size = core.Sz(w, h)
+	return m
+}
+
+// Placeholder sets the widget shown while the image loads.
+func (m *Image) Placeholder(w core.Widget) *Image {
+	m.placeholder = w
+	m.updateChildren()
+	return m
+}
+
+// Error sets the widget shown if the image fails to load.
+func (m *Image) Error(w core.Widget) *Image {
+	m.errWidget = w
+	m.updateChildren()
+	return m
+}
+
+// shown returns the child shown instead of the image, or nil.
+func (m *Image) shown() core.Widget {
+	switch {
+	case m.img != nil:
+		return nil
+	case m.err != nil:
+		return m.errWidget
+	default:
+		return m.placeholder
+	}
+}
+
+func (m *Image) updateChildren() {
+	if w := m.shown(); w != nil {
+		m.SetChildren(w)
+	} else {
+		m.SetChildren()
+	}
+}
+
+// load starts loading the source, or takes it from the cache.
+func (m *Image) load(ctx *core.Context) {
+	m.started = true
+	if m.src.Open == nil {
+		return
+	}
+	cache := imageCacheFrom(ctx)
+	if img, ok := cache.get(m.src.Key); ok {
+		m.img = img
+		m.updateChildren()
+		return
+	}
+	gen := m.gen
+	cache.load(m.src, func(img image.Image, err error) {
+		ctx.Post(func() {
+			if m.gen != gen {
+				return
+			}
+			m.img, m.err = img, err
+			m.updateChildren()
+			ctx.MarkNeedsLayout(m)
+		})
+	})
+}
+
+// Layout implements core.Widget.
+func (m *Image) Layout(ctx *core.LayoutContext) core.Size {
+	if !m.started {
+		m.load(ctx.Context)
+	}
+	var child core.Size
+	if w := m.shown(); w != nil {
+		child = ctx.Measure(w, ctx.Constraints)
+	}
+	switch {
+	case m.size != (core.Size{}):
+		return ctx.Constraints.Constrain(m.size)
+	case m.img != nil:
+		b := m.img.Bounds()
+		w, h := float32(b.Dx()), float32(b.Dy())
+		s := float32(1)
+		if c := ctx.Constraints; c.HasBoundedWidth() && w > c.MaxWidth {
+			s = c.MaxWidth / w
+		}
+		if c := ctx.Constraints; c.MaxHeight < h*s {
+			s = c.MaxHeight / h
+		}
+		return ctx.Constraints.Constrain(core.Sz(w*s, h*s))
+	}
+	return ctx.Constraints.Constrain(child)
+}
+
+// SetBounds implements core.Widget.
+func (m *Image) SetBounds(r core.Rect) {
+	m.WidgetBase.SetBounds(r)
+	if w := m.shown(); w != nil {
+		w.SetBounds(r)
+	}
+}
+
+// Paint implements core.Widget.
+func (m *Image) Paint(ctx *core.PaintContext) {
+	b := m.Bounds()
+	if m.img == nil {
+		if w := m.shown(); w != nil {
+			w.Paint(ctx)
+			return
+		}
+		th := theme.From(ctx.Context)
+		ctx.Canvas.DrawRoundedRect(b, th.Radii.Small, core.Filled(th.Colors.SurfaceVariant.WithAlpha(0.6)))
+		return
+	}
+	ib := m.img.Bounds()
+	iw, ih := float32(ib.Dx()), float32(ib.Dy())
+	if iw <= 0 || ih <= 0 || b.Width <= 0 || b.Height <= 0 {
+		return
+	}
+	r := fitRect(b, iw, ih, m.fit)
+	cv := ctx.Canvas
+	if m.fit == ImageCover {
+		cv.Save()
+		cv.Clip(b)
+		cv.DrawImage(m.img, r)
+		cv.Restore()
+		return
+	}
+	cv.DrawImage(m.img, r)
+}
+
+// fitRect returns where content of size w by h is drawn to fill b with fit.
+func fitRect(b core.Rect, w, h float32, fit ImageFit) core.Rect {
+	if fit == ImageFill {
+		return b
+	}
+	s := min(b.Width/w, b.Height/h)
+	if fit == ImageCover {
+		s = max(b.Width/w, b.Height/h)
+	}
+	w, h = w*s, h*s
+	return core.R(b.X+(b.Width-w)/2, b.Y+(b.Height-h)/2, w, h)
+}
+
+type imageCacheKey struct{}
+
+// SetImageCacheBudget sets how much memory, in bytes of decoded pixels,
+// the images of the window may keep cached; see DefaultImageCacheBudget.
+// The least recently used images are evicted first.
+func SetImageCacheBudget(ctx *core.Context, bytes int64) {
+	c := imageCacheFrom(ctx)
+	c.mu.Lock()
+	c.budget = bytes
+	c.evict()
+	c.mu.Unlock()
+}
+
+// ShareImageCache makes the images of the window of ctx cached with those
+// of the window of with, for applications with several windows, so that
+// an image shown in both is loaded and decoded once. The budget is that
+// of the shared cache.
+func ShareImageCache(ctx, with *core.Context) {
+	ctx.SetValue(imageCacheKey{}, imageCacheFrom(with))
+}
+
+func imageCacheFrom(ctx *core.Context) *imageCache {
+	if c, ok := ctx.Value(imageCacheKey{}).(*imageCache); ok {
+		return c
+	}
+	c := &imageCache{
+		budget:  DefaultImageCacheBudget,
+		lru:     list.New(),
+		entries: make(map[string]*list.Element),
+		loading: make(map[string][]func(image.Image, error)),
+	}
+	ctx.SetValue(imageCacheKey{}, c)
+	return c
+}
+
+// imageCache keeps decoded images by key, the most recently used at the
+// front of lru. Loads of the same key in flight at once are shared.
+type imageCache struct {
+	mu      sync.Mutex
+	budget  int64
+	used    int64
+	lru     *list.List // of *cachedImage
+	entries map[string]*list.Element
+	loading map[string][]func(image.Image, error)
+}
+
+type cachedImage struct {
+	key  string
+	img  image.Image
+	size int64
+}
+
+func (c *imageCache) get(key string) (image.Image, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.lru.MoveToFront(e)
+	return e.Value.(*cachedImage).img, true
+}
+
+// load decodes src in the background and calls done, from the loading
+// goroutine, with the image and caches it, or with the error.
+func (c *imageCache) load(src ImageSource, done func(image.Image, error)) {
+	c.mu.Lock()
+	waiting, busy := c.loading[src.Key]
+	c.loading[src.Key] = append(waiting, done)
+	c.mu.Unlock()
+	if busy {
+		return
+	}
+	go func() {
+		img, err := decodeImage(src)
+		c.mu.Lock()
+		if err == nil {
+			c.put(src.Key, img)
+		}
+		waiting := c.loading[src.Key]
+		delete(c.loading, src.Key)
+		c.mu.Unlock()
+		for _, fn := range waiting {
+			fn(img, err)
+		}
+	}()
+}
+
+func decodeImage(src ImageSource) (image.Image, error) {
+	rc, err := src.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	img, _, err := image.Decode(rc)
+	return img, err
+}
+
+// put caches img; the lock must be held. Images larger than the whole
+// budget are not cached.
+func (c *imageCache) put(key string, img image.Image) {
+	b := img.Bounds()
+	size := int64(b.Dx()) * int64(b.Dy()) * 4
+	if size > c.budget {
+		return
+	}
+	if e, ok := c.entries[key]; ok {
+		c.used -= e.Value.(*cachedImage).size
+		c.lru.Remove(e)
+	}
+	c.entries[key] = c.lru.PushFront(&cachedImage{key, img, size})
+	c.used += size
+	c.evict()
+}
+
+// evict drops the least recently used images until the cache fits its
+// budget; the lock must be held.
+func (c *imageCache) evict() {
+	for c.used > c.budget && c.lru.Len() > 0 {
+		e := c.lru.Back()
+		ci := e.Value.(*cachedImage)
+		c.lru.Remove(e)
+		delete(c.entries, ci.key)
+		c.used -= ci.size
+	}
+}
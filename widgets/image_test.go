@@ -0,0 +1,264 @@
+package widgets
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+)
+
+// pngData returns a w by h PNG.
+func pngData(t *testing.T, w, h int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, w, h))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// countedImage returns a source of data under key that counts its opens.
+func countedImage(key string, data []byte, opens *atomic.Int32) ImageSource {
+	return ImageSource{Key: key, Open: func() (io.ReadCloser, error) {
+		opens.Add(1)
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}}
+}
+
+func TestImageLoad(t *testing.T) {
+	var opens atomic.Int32
+	placeholder := NewCanvas(nil).Size(30, 30)
+	m := NewImage(countedImage("a", pngData(t, 40, 20), &opens)).Placeholder(placeholder)
+	lc := &core.LayoutContext{Context: core.NewContext()}
+	ctx := lc.Context
+	if got := lc.Measure(m, core.Unbounded()); got != core.Sz(30, 30) || len(m.Children()) != 1 {
+		t.Errorf("loading, the image is %v with %d children, want the placeholder", got, len(m.Children()))
+	}
+	runPosted(t, ctx)
+	if m.Image() == nil || m.Err() != nil || len(m.Children()) != 0 {
+		t.Fatalf("loaded %v, %v", m.Image(), m.Err())
+	}
+	tests := []struct {
+		c    core.Constraints
+		m    *Image
+		want core.Size
+	}{
+		{core.Unbounded(), m, core.Sz(40, 20)},
+		{core.Loose(core.Sz(20, 100)), m, core.Sz(20, 10)},
+		{core.Loose(core.Sz(100, 5)), m, core.Sz(10, 5)},
+		{core.Unbounded(), NewImage(ImageSource{}).Size(7, 8), core.Sz(7, 8)},
+	}
+	for _, tt := range tests {
+		if got := lc.Measure(tt.m, tt.c); got != tt.want {
+			t.Errorf("size in %v = %v, want %v", tt.c, got, tt.want)
+		}
+	}
+
+	// A second image of the same key comes from the cache right away.
+	other := NewImage(countedImage("a", nil, &opens))
+	lc.Measure(other, core.Unbounded())
+	if other.Image() != m.Image() || opens.Load() != 1 || ctx.HasPosted() {
+		t.Errorf("the cached image was opened %d times", opens.Load())
+	}
+}
+
+func TestImageSharedLoads(t *testing.T) {
+	var opens atomic.Int32
+	release := make(chan struct{})
+	data := pngData(t, 2, 2)
+	src := ImageSource{Key: "slow", Open: func() (io.ReadCloser, error) {
+		opens.Add(1)
+		<-release
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}}
+	a, b := NewImage(src), NewImage(src)
+	ctx := layoutAt(a, core.R(0, 0, 10, 10))
+	c := core.NewContext()
+	ShareImageCache(c, ctx)
+	c.LayoutRoot(b, core.R(0, 0, 10, 10))
+	close(release)
+	runPosted(t, ctx)
+	runPosted(t, c)
+	if a.Image() == nil || a.Image() != b.Image() || opens.Load() != 1 {
+		t.Errorf("two windows loading an image at once opened it %d times", opens.Load())
+	}
+}
+
+func TestImageErrors(t *testing.T) {
+	failed := errors.New("offline")
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.png")
+	if err := os.WriteFile(path, pngData(t, 3, 1), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/a.png" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(pngData(t, 5, 1))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name  string
+		src   ImageSource
+		width int
+		check func(error) bool
+	}{
+		{"file", FileImage(path), 3, nil},
+		{"missing file", FileImage(filepath.Join(dir, "b.png")), 0, func(err error) bool { return errors.Is(err, fs.ErrNotExist) }},
+		{"url", URLImage(srv.URL + "/a.png"), 5, nil},
+		{"missing url", URLImage(srv.URL + "/b.png"), 0, func(err error) bool { return err != nil }},
+		{"open fails", ImageSource{Key: "x", Open: func() (io.ReadCloser, error) { return nil, failed }}, 0, func(err error) bool { return err == failed }},
+		{"not an image", ReaderImage("y", bytes.NewReader([]byte("text"))), 0, func(err error) bool { return errors.Is(err, image.ErrFormat) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errWidget := NewCanvas(nil)
+			m := NewImage(tt.src).Error(errWidget)
+			ctx := layoutAt(m, core.R(0, 0, 10, 10))
+			runPosted(t, ctx)
+			if tt.check == nil {
+				if m.Err() != nil || m.Image().Bounds().Dx() != tt.width {
+					t.Errorf("loaded %v, %v", m.Image(), m.Err())
+				}
+				return
+			}
+			if m.Image() != nil || !tt.check(m.Err()) {
+				t.Errorf("got %v, %v", m.Image(), m.Err())
+			}
+			if ch := m.Children(); len(ch) != 1 || ch[0] != errWidget {
+				t.Errorf("children %v, want the error widget", ch)
+			}
+		})
+	}
+
+	src := ReaderImage("z", bytes.NewReader(pngData(t, 1, 1)))
+	if _, err := decodeImage(src); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := decodeImage(src); err == nil {
+		t.Error("a reader was read twice")
+	}
+}
+
+func TestImageStaleLoad(t *testing.T) {
+	release := make(chan struct{})
+	data := pngData(t, 4, 4)
+	m := NewImage(ImageSource{Key: "late", Open: func() (io.ReadCloser, error) {
+		<-release
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}})
+	ctx := layoutAt(m, core.R(0, 0, 10, 10))
+	set := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	m.SetImage(set)
+	close(release)
+	runPosted(t, ctx)
+	if m.Image() != set {
+		t.Error("a load finishing after SetImage replaced the image")
+	}
+
+	m.SetSource(ImageSource{})
+	ctx.LayoutRoot(m, core.R(0, 0, 10, 10))
+	if m.Image() != nil || m.Err() != nil || ctx.HasPosted() {
+		t.Errorf("an empty source shows %v, %v", m.Image(), m.Err())
+	}
+}
+
+func TestFitRect(t *testing.T) {
+	b := core.R(10, 10, 100, 50)
+	tests := []struct {
+		fit  ImageFit
+		w, h float32
+		want core.Rect
+	}{
+		{ImageContain, 20, 20, core.R(35, 10, 50, 50)},
+		{ImageContain, 400, 100, core.R(10, 22.5, 100, 25)},
+		{ImageCover, 20, 20, core.R(10, -15, 100, 100)},
+		{ImageFill, 20, 20, b},
+	}
+	for _, tt := range tests {
+		if got := fitRect(b, tt.w, tt.h, tt.fit); got != tt.want {
+			t.Errorf("fitRect(%v by %v, %d) = %v, want %v", tt.w, tt.h, tt.fit, got, tt.want)
+		}
+	}
+}
+
+func TestImagePaint(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 20, 20))
+	tests := []struct {
+		name string
+		m    *Image
+		want []string
+	}{
+		{"contain", NewImage(ImageSource{}).Fit(ImageContain), []string{"image 35 10 50 50"}},
+		{"cover", NewImage(ImageSource{}).Fit(ImageCover), []string{"clip 10 10 100 50", "image 10 -15 100 100"}},
+		{"fill", NewImage(ImageSource{}).Fit(ImageFill), []string{"image 10 10 100 50"}},
+	}
+	for _, tt := range tests {
+		tt.m.SetImage(img)
+		ctx := layoutAt(tt.m, core.R(10, 10, 100, 50))
+		cv := &opCanvas{}
+		tt.m.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+		if !equalStrings(cv.ops, tt.want) {
+			t.Errorf("%s: drew %q, want %q", tt.name, cv.ops, tt.want)
+		}
+	}
+
+	// Without a placeholder a tinted box stands in for the image.
+	m := NewImage(ImageSource{})
+	ctx := layoutAt(m, core.R(0, 0, 10, 10))
+	rec := &core.Recording{}
+	m.Paint(&core.PaintContext{Context: ctx, Canvas: rec})
+	if rec.Len() != 1 {
+		t.Errorf("the placeholder box drew %d operations", rec.Len())
+	}
+	m.SetImage(image.NewRGBA(image.Rect(0, 0, 0, 0)))
+	rec.Reset()
+	m.Paint(&core.PaintContext{Context: ctx, Canvas: rec})
+	if rec.Len() != 0 {
+		t.Errorf("an empty image drew %d operations", rec.Len())
+	}
+}
+
+func TestImageCache(t *testing.T) {
+	ctx := core.NewContext()
+	c := imageCacheFrom(ctx)
+	img := image.NewRGBA(image.Rect(0, 0, 10, 10)) // 400 bytes
+	SetImageCacheBudget(ctx, 1000)
+	c.put("a", img)
+	c.put("b", img)
+	c.get("a")
+	c.put("c", img)
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		if _, ok := c.get(key); ok != want {
+			t.Errorf("%q cached %v, want %v", key, ok, want)
+		}
+	}
+	c.put("c", img)
+	if c.used != 800 || c.lru.Len() != 2 {
+		t.Errorf("caching c again uses %d bytes in %d entries", c.used, c.lru.Len())
+	}
+	c.put("big", image.NewRGBA(image.Rect(0, 0, 20, 20)))
+	if _, ok := c.get("big"); ok || c.used != 800 {
+		t.Error("an image larger than the budget was cached")
+	}
+	SetImageCacheBudget(ctx, 500)
+	if c.lru.Len() != 1 || c.used != 400 {
+		t.Errorf("shrinking the budget left %d entries", c.lru.Len())
+	}
+	if imageCacheFrom(ctx) != c || imageCacheFrom(core.NewContext()) == c {
+		t.Error("the cache is not kept per window")
+	}
+}
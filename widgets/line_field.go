@@ -0,0 +1,136 @@
+package widgets
+
+import (
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/internal/textedit"
+	"github.com/gogpu/ui/theme"
+)
+
+// lineField is the small text box composite widgets embed for typed
+// values, such as the hex and channel fields of a ColorPicker. Edits are
+// committed with Enter or when focus leaves; Escape reverts them. The owner
+// shows its model with setText before laying the field out.
+type lineField struct {
+	core.WidgetBase
+	core.FocusState
+
+	line    textedit.Line
+	width   float32
+	height  float32
+	dirty   bool
+	invalid bool
+
+	// trailing is the width kept free at the right edge for the owner's
+	// decorations, such as a unit suffix or spin buttons. Presses there
+	// pass through to the owner.
+	trailing float32
+
+	// commit applies the typed text and reports whether it was valid.
+	commit func(ctx *core.Context, text string) bool
+	// step, if set, handles Up and Down with delta +1 and -1.
+	step func(ctx *core.Context, delta int)
+}
+
+func newLineField(width float32, commit func(ctx *core.Context, text string) bool) *lineField {
+	return &lineField{width: width, height: fieldHeight, commit: commit}
+}
+
+// setText shows s unless the user is editing or the typed text was
+// rejected and is waiting to be corrected.
+func (f *lineField) setText(s string) {
+	if f.dirty || f.invalid {
+		return
+	}
+	f.line.SetText(s)
+}
+
+func (f *lineField) apply(ctx *core.Context) {
+	if !f.dirty {
+		return
+	}
+	f.dirty = false
+	f.invalid = !f.commit(ctx, f.line.Text())
+	ctx.Repaint(f)
+}
+
+func (f *lineField) Layout(ctx *core.LayoutContext) core.Size {
+	if f.dirty && !f.IsFocused() {
+		f.apply(ctx.Context)
+	}
+	f.line.Style = theme.From(ctx.Context).Typography.Body
+	return ctx.Constraints.Constrain(core.Sz(f.width, f.height))
+}
+
+func (f *lineField) SetBounds(r core.Rect) {
+	f.WidgetBase.SetBounds(r)
+	pad := min(fieldPadding, r.Width/4)
+	f.line.Rect = core.R(r.X+pad, r.Y, max(0, r.Width-2*pad-f.trailing), r.Height)
+}
+
+// HitTest implements core.HitTester.
+func (f *lineField) HitTest(p core.Point) bool {
+	b := f.Bounds()
+	return p.X < b.Right()-f.trailing
+}
+
+func (f *lineField) Paint(ctx *core.PaintContext) {
+	paintField(ctx, f.Bounds(), f.IsFocused(), f.invalid)
+	f.line.Paint(ctx, f.IsFocused())
+}
+
+func (f *lineField) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	switch e := ev.(type) {
+	case event.MouseEvent:
+		if e.Type == event.MouseDown && e.Button == event.ButtonLeft {
+			if !f.IsFocused() {
+				ctx.RequestFocus(f)
+				f.line.SelectAll()
+				ctx.Repaint(f)
+				return core.Handled
+			}
+		}
+		if f.line.HandleEvent(ctx, f, ev) != textedit.Ignored {
+			return core.Handled
+		}
+	case event.KeyEvent:
+		if !f.IsFocused() || e.Type != event.KeyPress {
+			return core.Ignored
+		}
+		switch {
+		case e.Key == event.KeyEnter:
+			f.apply(ctx)
+			f.line.SelectAll()
+			return core.Handled
+		case e.Key == event.KeyEscape && (f.dirty || f.invalid):
+			f.dirty, f.invalid = false, false
+			// The owner shows its model again when it lays the field out.
+			ctx.MarkNeedsLayout(f)
+			return core.Handled
+		case (e.Key == event.KeyUp || e.Key == event.KeyDown) && e.Modifiers == 0 && f.step != nil:
+			f.apply(ctx)
+			if e.Key == event.KeyUp {
+				f.step(ctx, 1)
+			} else {
+				f.step(ctx, -1)
+			}
+			f.line.SelectAll()
+			return core.Handled
+		}
+		if r := f.line.HandleEvent(ctx, f, ev); r != textedit.Ignored {
+			if r == textedit.Changed {
+				f.dirty = true
+			}
+			return core.Handled
+		}
+	case event.TextEvent:
+		if !f.IsFocused() {
+			return core.Ignored
+		}
+		if f.line.HandleEvent(ctx, f, ev) == textedit.Changed {
+			f.dirty = true
+		}
+		return core.Handled
+	}
+	return core.Ignored
+}
@@ -0,0 +1,284 @@
+package widgets
+
+import (
+	"time"
+
+	"github.com/gogpu/ui/anim"
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/internal/lottie"
+)
+
+// Lottie plays a Lottie animation, the Bodymovin JSON that designers
+// export from After Effects, drawing each frame as paths at whatever size
+// it is given.
+//
+// Shape, solid and precomposition layers are drawn with their transforms,
+// parenting and opacity; paths, rectangles, ellipses and stars with solid
+// and gradient fills and strokes, and trimmed paths. Masks, mattes,
+// effects, expressions, images and text are ignored.
+//
+// Without an explicit Size a Lottie takes the animation's width and
+// height, scaled down to fit the constraints. It plays the whole
+// animation unless given a Segment, once unless it loops, and starts
+// when first laid out with Autoplay. Frames play on the window's
+// animation clock, so a Lottie costs nothing while paused.
+type Lottie struct {
+	core.WidgetBase
+
+	anim     *lottie.Animation
+	fit      ImageFit
+	size     core.Size
+	loop     bool
+	autoplay bool
+	speed    float32
+	from, to float32 // The segment played, in frames.
+	onEnd    func(ctx *core.Context)
+
+	ctl     *anim.Controller
+	playing bool
+	pos     float32 // The progress through the segment until ctl exists.
+
+	shapes      []lottie.Shape
+	shapesFrame float32
+	shapesSize  core.Size
+}
+
+// NewLottie returns a Lottie playing the animation in data.
+func NewLottie(data []byte) (*Lottie, error) {
+	a, err := lottie.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	return &Lottie{anim: a, speed: 1, from: a.In, to: a.Out}, nil
+}
+
+// Fit sets how the animation fills its bounds. The default is
+// ImageContain.
+func (l *Lottie) Fit(fit ImageFit) *Lottie {
+	l.fit = fit
+	return l
+}
+
+// Size sets the preferred size, which is otherwise the animation's.
+func (l *Lottie) Size(w, h float32) *Lottie {
+	l.size = core.Sz(w, h)
+	return l
+}
+
+// Loop sets whether playback starts over at the end of the segment.
+func (l *Lottie) Loop(on bool) *Lottie {
+	l.loop = on
+	l.sync()
+	return l
+}
+
+// Autoplay sets whether playback starts when the Lottie is first laid
+// out.
+func (l *Lottie) Autoplay(on bool) *Lottie {
+	l.autoplay = on
+	return l
+}
+
+// Speed sets how fast the animation plays, 1 by default; 2 plays it
+// twice as fast, and a negative speed plays it backwards, from the end
+// of the segment to the start.
+func (l *Lottie) Speed(s float32) *Lottie {
+	if s == 0 {
+		s = 1
+	}
+	if (s < 0) != (l.speed < 0) {
+		l.setProgress(1 - l.progress())
+	}
+	l.speed = s
+	l.sync()
+	return l
+}
+
+// OnEnd sets fn to be called when playback reaches the end of the
+// segment without looping.
+func (l *Lottie) OnEnd(fn func(ctx *core.Context)) *Lottie {
+	l.onEnd = fn
+	return l
+}
+
+// Segment limits playback to the part of the animation from start to
+// end, and moves to its start.
+func (l *Lottie) Segment(start, end time.Duration) *Lottie {
+	a := l.anim
+	l.from = min(max(a.In+float32(start.Seconds())*a.FrameRate, a.In), a.Out)
+	l.to = min(max(a.In+float32(end.Seconds())*a.FrameRate, l.from), a.Out)
+	l.setProgress(0)
+	l.sync()
+	return l
+}
+
+// SegmentMarker limits playback to the segment of the animation's marker
+// of the given name, as Segment does, and reports whether it has one.
+func (l *Lottie) SegmentMarker(name string) bool {
+	for _, m := range l.anim.Markers {
+		if m.Name == name {
+			l.from, l.to = m.Start, min(m.Start+m.Duration, l.anim.Out)
+			l.setProgress(0)
+			l.sync()
+			return true
+		}
+	}
+	return false
+}
+
+// Markers returns the names of the animation's markers.
+func (l *Lottie) Markers() []string {
+	names := make([]string, len(l.anim.Markers))
+	for i, m := range l.anim.Markers {
+		names[i] = m.Name
+	}
+	return names
+}
+
+// Play starts or resumes playback, from the start of the segment if it
+// had ended.
+func (l *Lottie) Play() {
+	l.playing = true
+	if !l.loop && l.progress() >= 1 {
+		l.setProgress(0)
+	}
+	l.sync()
+}
+
+// Pause pauses playback.
+func (l *Lottie) Pause() {
+	l.playing = false
+	l.sync()
+}
+
+// Playing reports whether the animation is playing.
+func (l *Lottie) Playing() bool {
+	return l.playing
+}
+
+// Seek moves playback to t from the start of the animation, within the
+// segment.
+func (l *Lottie) Seek(t time.Duration) {
+	f := l.anim.In + float32(t.Seconds())*l.anim.FrameRate
+	var p float32
+	if l.to > l.from {
+		p = (f - l.from) / (l.to - l.from)
+	}
+	if l.speed < 0 {
+		p = 1 - p
+	}
+	l.setProgress(p)
+	l.sync()
+}
+
+// Position returns the time of the frame shown from the start of the
+// animation.
+func (l *Lottie) Position() time.Duration {
+	return l.seconds(l.frame() - l.anim.In)
+}
+
+// Duration returns the length of the whole animation.
+func (l *Lottie) Duration() time.Duration {
+	return l.seconds(l.anim.Out - l.anim.In)
+}
+
+func (l *Lottie) seconds(frames float32) time.Duration {
+	return time.Duration(float64(frames) / float64(l.anim.FrameRate) * float64(time.Second))
+}
+
+// frame returns the frame shown.
+func (l *Lottie) frame() float32 {
+	p := l.progress()
+	if l.speed < 0 {
+		p = 1 - p
+	}
+	return l.from + (l.to-l.from)*p
+}
+
+func (l *Lottie) progress() float32 {
+	if l.ctl != nil {
+		return l.ctl.Progress()
+	}
+	return l.pos
+}
+
+func (l *Lottie) setProgress(p float32) {
+	l.pos = min(max(p, 0), 1)
+	if l.ctl != nil {
+		l.ctl.SetProgress(l.pos)
+	}
+}
+
+// sync makes the controller follow the playback settings.
+func (l *Lottie) sync() {
+	if l.ctl == nil {
+		return
+	}
+	span := (l.to - l.from) / l.anim.FrameRate / max(l.speed, -l.speed)
+	l.ctl.Duration(time.Duration(float64(span) * float64(time.Second)))
+	switch {
+	case !l.playing:
+		l.ctl.Stop()
+	case l.loop:
+		l.ctl.Repeat(false)
+	default:
+		l.ctl.AnimateTo(1)
+	}
+}
+
+// Layout implements core.Widget.
+func (l *Lottie) Layout(ctx *core.LayoutContext) core.Size {
+	if l.ctl == nil {
+		cx := ctx.Context
+		l.ctl = anim.NewController(anim.ClockOf(cx), 0)
+		l.ctl.SetProgress(l.pos)
+		l.ctl.Listen(func(float32) { cx.Repaint(l) })
+		l.ctl.OnEnd(func() {
+			l.playing = false
+			if l.onEnd != nil {
+				l.onEnd(cx)
+			}
+		})
+		if l.autoplay {
+			l.playing = true
+		}
+		l.sync()
+	}
+	if l.size != (core.Size{}) {
+		return ctx.Constraints.Constrain(l.size)
+	}
+	w, h := l.anim.Width, l.anim.Height
+	k := float32(1)
+	if c := ctx.Constraints; c.HasBoundedWidth() && w > c.MaxWidth {
+		k = c.MaxWidth / w
+	}
+	if c := ctx.Constraints; c.MaxHeight < h*k {
+		k = c.MaxHeight / h
+	}
+	return ctx.Constraints.Constrain(core.Sz(w*k, h*k))
+}
+
+// Paint implements core.Widget.
+func (l *Lottie) Paint(ctx *core.PaintContext) {
+	b := l.Bounds()
+	if b.Width <= 0 || b.Height <= 0 {
+		return
+	}
+	r := fitRect(b, l.anim.Width, l.anim.Height, l.fit)
+	// The end of a segment is where what follows it starts, so it is shown
+	// from just before.
+	f := min(l.frame(), l.to-0.01)
+	if l.shapes == nil || f != l.shapesFrame || r.Size() != l.shapesSize {
+		l.shapes, l.shapesFrame, l.shapesSize = l.anim.Render(f, r.Size()), f, r.Size()
+	}
+	cv := ctx.Canvas
+	cv.Save()
+	if l.fit == ImageCover {
+		cv.Clip(b)
+	}
+	cv.Translate(r.X, r.Y)
+	for _, s := range l.shapes {
+		cv.DrawPath(s.Path, s.Style)
+	}
+	cv.Restore()
+}
@@ -0,0 +1,152 @@
+package widgets
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogpu/ui/anim"
+	"github.com/gogpu/ui/core"
+)
+
+// testLottie is a 100x50 animation of two seconds at 30 frames a second,
+// a red rectangle filling its left half, with null layers and shapes a
+// malformed export left in.
+const testLottie = `{"fr":30,"ip":0,"op":60,"w":100,"h":50,"layers":[null,
+	{"ty":4,"ip":0,"op":60,"ks":{},"shapes":[null,
+		{"ty":"rc","p":{"k":[25,25]},"s":{"k":[50,50]}},
+		{"ty":"fl","c":{"k":[1,0,0,1]}}]}],
+	"markers":[{"cm":"intro","tm":0,"dr":15},{"cm":"loop","tm":15,"dr":45}]}`
+
+func newLottie(t *testing.T) *Lottie {
+	t.Helper()
+	l, err := NewLottie([]byte(testLottie))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func TestLottieSize(t *testing.T) {
+	if _, err := NewLottie([]byte(`{"layers":[]}`)); err == nil {
+		t.Error("NewLottie of a file that is not an animation succeeded")
+	}
+	tests := []struct {
+		name string
+		l    *Lottie
+		c    core.Constraints
+		want core.Size
+	}{
+		{"animation size", newLottie(t), core.Unbounded(), core.Sz(100, 50)},
+		{"narrow", newLottie(t), core.Loose(core.Sz(50, 100)), core.Sz(50, 25)},
+		{"short", newLottie(t), core.Loose(core.Sz(100, 10)), core.Sz(20, 10)},
+		{"sized", newLottie(t).Size(16, 16), core.Unbounded(), core.Sz(16, 16)},
+	}
+	for _, tt := range tests {
+		lc := &core.LayoutContext{Context: core.NewContext()}
+		if got := lc.Measure(tt.l, tt.c); got != tt.want {
+			t.Errorf("%s: size %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLottiePaint(t *testing.T) {
+	tests := []struct {
+		name   string
+		fit    ImageFit
+		bounds core.Rect
+		want   core.Rect
+	}{
+		{"contained", ImageContain, core.R(0, 0, 100, 100), core.R(0, 0, 50, 50)},
+		{"filled", ImageFill, core.R(0, 0, 100, 100), core.R(0, 0, 50, 100)},
+	}
+	for _, tt := range tests {
+		l := newLottie(t).Fit(tt.fit)
+		ctx := layoutAt(l, tt.bounds)
+		cv := &pathCanvas{}
+		l.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+		if len(cv.paths) != 1 || cv.styles[0].Fill != (core.Color{R: 1, A: 1}) {
+			t.Fatalf("%s: drew %d paths in %v, want one red", tt.name, len(cv.paths), cv.styles)
+		}
+		// Frames are rendered at the size of the fitted rectangle.
+		if got := cv.paths[0].Bounds(); got != tt.want {
+			t.Errorf("%s: the rectangle at %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLottieSeek(t *testing.T) {
+	l := newLottie(t)
+	if l.Duration() != 2*time.Second || l.Position() != 0 {
+		t.Errorf("duration %v at %v, want 2s at 0", l.Duration(), l.Position())
+	}
+	l.Seek(time.Second)
+	if l.Position() != time.Second {
+		t.Errorf("Seek(1s): at %v", l.Position())
+	}
+	l.Seek(5 * time.Second)
+	if l.Position() != 2*time.Second {
+		t.Errorf("Seek(5s): at %v, want the end", l.Position())
+	}
+	l.Segment(500*time.Millisecond, 1500*time.Millisecond)
+	if l.Position() != 500*time.Millisecond {
+		t.Errorf("Segment(): at %v, want its start", l.Position())
+	}
+	l.Seek(0)
+	if l.Position() != 500*time.Millisecond {
+		t.Errorf("Seek(0) in a segment: at %v, want its start", l.Position())
+	}
+	// Turning playback around keeps the frame shown.
+	l.Speed(-1)
+	if l.Position() != 500*time.Millisecond {
+		t.Errorf("Speed(-1): at %v, want 500ms", l.Position())
+	}
+	l.Seek(time.Second)
+	if l.Position() != time.Second {
+		t.Errorf("Seek(1s) played backwards: at %v", l.Position())
+	}
+
+	l = newLottie(t)
+	if got := l.Markers(); !equalStrings(got, []string{"intro", "loop"}) {
+		t.Errorf("Markers() = %q", got)
+	}
+	if !l.SegmentMarker("loop") || l.Position() != 500*time.Millisecond {
+		t.Errorf("SegmentMarker(loop): at %v, want 500ms", l.Position())
+	}
+	if l.SegmentMarker("outro") {
+		t.Error("SegmentMarker of a missing marker succeeded")
+	}
+}
+
+func TestLottiePlay(t *testing.T) {
+	l := newLottie(t).Autoplay(true)
+	ended := 0
+	l.OnEnd(func(*core.Context) { ended++ })
+	ctx := layoutAt(l, core.R(0, 0, 100, 50))
+	if !l.Playing() {
+		t.Fatal("an autoplaying Lottie is not playing once laid out")
+	}
+	clock := anim.ClockOf(ctx)
+	start := time.Unix(0, 0)
+	clock.Tick(start)
+	clock.Tick(start.Add(500 * time.Millisecond))
+	if l.Position() != 500*time.Millisecond {
+		t.Errorf("after 500ms at %v", l.Position())
+	}
+	l.Pause()
+	clock.Tick(start.Add(time.Second))
+	if l.Playing() || l.Position() != 500*time.Millisecond {
+		t.Errorf("paused: playing %v at %v", l.Playing(), l.Position())
+	}
+	l.Speed(2)
+	l.Play()
+	clock.Tick(start.Add(2 * time.Second))
+	clock.Tick(start.Add(3 * time.Second))
+	if l.Playing() || l.Position() != 2*time.Second || ended != 1 {
+		t.Errorf("at the end: playing %v at %v, ended %d times", l.Playing(), l.Position(), ended)
+	}
+	// Played again after the end, it starts over.
+	l.Play()
+	if l.Position() != 0 {
+		t.Errorf("played again at %v, want the start", l.Position())
+	}
+}
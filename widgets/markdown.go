@@ -0,0 +1,570 @@
+package widgets
+
+import (
+	"image"
+	"strconv"
+	"strings"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/internal/markdown"
+	"github.com/gogpu/ui/internal/scroll"
+	"github.com/gogpu/ui/richtext"
+	"github.com/gogpu/ui/theme"
+)
+
+// Markdown metrics.
+const (
+	markdownWidth   float32 = 480 // when the width is unbounded
+	markdownGap     float32 = 12  // between blocks
+	markdownTight   float32 = 4   // between the items of a tight list
+	markdownQuote   float32 = 16  // indentation of quotes
+	markdownPadding float32 = 8   // inside code blocks and table cells
+	markdownCellMin float32 = 48  // narrowest a table column shrinks to
+)
+
+// ImageLoader loads the image at src for a Markdown widget and calls done
+// with it, or with nil if it cannot be loaded. done may be called from any
+// goroutine.
+type ImageLoader func(src string, done func(img image.Image))
+
+// Markdown displays a CommonMark document: headings, paragraphs, block
+// quotes, nested lists, code blocks, thematic breaks, GitHub-style tables,
+// and inline emphasis, code, links and images.
+//
+// Code blocks are highlighted by the Tokenizer that Highlight returns for
+// their language; Go is highlighted by default. Images are fetched through
+// the ImageLoader set with Images, and show their alt text until then.
+// Clicking a link reports it to OnLink. Content taller than the widget
+// scrolls.
+type Markdown struct {
+	core.WidgetBase
+
+	source    string
+	blocks    []*markdown.Block
+	onLink    func(url string)
+	highlight func(lang string) Tokenizer
+	loader    ImageLoader
+	images    map[string]*mdImage
+
+	valid   bool    // the lines, codes and boxes are laid out
+	width   float32 // they were laid out for
+	lines   []rtLine
+	codes   []mdCode
+	boxes   []mdBox
+	height  float32
+	offset  float32
+	bar     scroll.Bar
+	pressed string // the link the pointer went down on
+}
+
+// mdImage is the loading state of an image source.
+type mdImage struct {
+	data    image.Image
+	loading bool
+}
+
+// mdCode is a laid out code block. rect is relative to the content.
+type mdCode struct {
+	rect   core.Rect
+	lines  [][]rune
+	tokens [][]Token
+}
+
+// mdFlow is what blocks inherit from the blocks containing them.
+type mdFlow struct {
+	muted bool // in a quote
+	depth int  // of list nesting
+}
+
+// mdBox is a rectangle drawn under the text: a code block or header row
+// background, a quote bar, a rule or a table cell border.
+type mdBox struct {
+	rect   core.Rect
+	radius float32
+	style  core.RectStyle
+}
+
+// NewMarkdown returns a widget displaying the CommonMark document source.
+func NewMarkdown(source string) *Markdown {
+	m := &Markdown{highlight: defaultHighlight}
+	m.SetSource(source)
+	return m
+}
+
+func defaultHighlight(lang string) Tokenizer {
+	switch strings.ToLower(lang) {
+	case "go", "golang":
+		return GoSyntax
+	}
+	return nil
+}
+
+// Source returns the displayed document.
+func (m *Markdown) Source() string {
+	return m.source
+}
+
+// SetSource replaces the displayed document.
+func (m *Markdown) SetSource(source string) {
+	m.source = source
+	m.blocks = markdown.Parse(source)
+	m.valid = false
+}
+
+// OnLink sets the callback invoked with the URL of a clicked link.
+func (m *Markdown) OnLink(fn func(url string)) *Markdown {
+	m.onLink = fn
+	return m
+}
+
+// Highlight sets the function choosing the Tokenizer for code blocks in a
+// language, named by the block's info string. A nil Tokenizer leaves the
+// code plain.
+func (m *Markdown) Highlight(fn func(lang string) Tokenizer) *Markdown {
+	m.highlight = fn
+	m.valid = false
+	return m
+}
+
+// Images sets the loader fetching the images of the document. Each source
+// is loaded once.
+func (m *Markdown) Images(loader ImageLoader) *Markdown {
+	m.loader = loader
+	m.valid = false
+	return m
+}
+
+// Layout implements core.Widget.
+func (m *Markdown) Layout(ctx *core.LayoutContext) core.Size {
+	width := markdownWidth
+	if ctx.Constraints.HasBoundedWidth() {
+		width = ctx.Constraints.MaxWidth
+	}
+	m.layoutContent(ctx.Context, width)
+	if m.height > ctx.Constraints.MaxHeight {
+		m.layoutContent(ctx.Context, max(0, width-scroll.Thickness))
+	}
+	size := ctx.Constraints.Constrain(core.Sz(width, m.height))
+	m.offset = core.Clamp(m.offset, 0, max(0, m.height-size.Height))
+	return size
+}
+
+// layoutContent lays the document out width wide, unless it already is.
+func (m *Markdown) layoutContent(ctx *core.Context, width float32) {
+	if m.valid && m.width == width {
+		return
+	}
+	m.valid, m.width = true, width
+	m.lines, m.codes, m.boxes = m.lines[:0], m.codes[:0], m.boxes[:0]
+	m.height = m.layoutBlocks(ctx, m.blocks, 0, 0, width, markdownGap, mdFlow{})
+}
+
+// layoutBlocks lays out blocks from (x, y), width wide and gap apart, and
+// returns the y below them.
+func (m *Markdown) layoutBlocks(ctx *core.Context, blocks []*markdown.Block, x, y, width, gap float32, f mdFlow) float32 {
+	th := theme.From(ctx)
+	body := th.Typography.Body
+	body.Color = th.Colors.OnSurface
+	if f.muted {
+		body.Color = th.Colors.OnSurfaceVariant
+	}
+	for i, b := range blocks {
+		if i > 0 {
+			y += gap
+		}
+		switch b.Kind {
+		case markdown.Paragraph:
+			m.lines, y = wrapSpans(ctx, m.lines, m.displaySpans(ctx, b.Spans, width), body, width, rtLine{indent: x, y: y})
+		case markdown.Heading:
+			st := headingStyle(th, b.Level)
+			st.Color = body.Color
+			m.lines, y = wrapSpans(ctx, m.lines, m.displaySpans(ctx, b.Spans, width), st, width, rtLine{indent: x, y: y})
+		case markdown.Quote:
+			top := y
+			y = m.layoutBlocks(ctx, b.Children, x+markdownQuote, y, width-markdownQuote, markdownGap, mdFlow{muted: true, depth: f.depth})
+			m.boxes = append(m.boxes, mdBox{rect: core.R(x, top, 3, y-top), style: core.Filled(th.Colors.Outline)})
+		case markdown.List:
+			y = m.layoutList(ctx, b, x, y, width, f)
+		case markdown.Code:
+			y = m.layoutCode(ctx, b, x, y, width)
+		case markdown.Rule:
+			m.boxes = append(m.boxes, mdBox{rect: core.R(x, y, width, 1), style: core.Filled(th.Colors.Outline)})
+			y++
+		case markdown.Table:
+			y = m.layoutTable(ctx, b, x, y, width, body)
+		}
+	}
+	return y
+}
+
+// layoutList lays out a list, with the markers right-aligned in the
+// indentation before the items.
+func (m *Markdown) layoutList(ctx *core.Context, b *markdown.Block, x, y, width float32, f mdFlow) float32 {
+	th := theme.From(ctx)
+	st := theme.TextStyle(th.Typography.Body, th.Colors.OnSurfaceVariant)
+	markers := make([]string, len(b.Children))
+	indent := richTextIndent
+	for i := range markers {
+		if b.Ordered {
+			markers[i] = strconv.Itoa(b.Start+i) + "."
+		} else {
+			markers[i] = richTextBullets[f.depth%len(richTextBullets)]
+		}
+		indent = max(indent, ctx.MeasureText(markers[i], st).Width+10)
+	}
+	gap := markdownGap
+	if b.Tight {
+		gap = markdownTight
+	}
+	for i, item := range b.Children {
+		if i > 0 {
+			y += gap
+		}
+		first := len(m.lines)
+		top := y
+		y = m.layoutBlocks(ctx, item.Children, x+indent, y, width-indent, gap, mdFlow{muted: f.muted, depth: f.depth + 1})
+		h := st.LineHeight()
+		if first < len(m.lines) && m.lines[first].y == top {
+			h = m.lines[first].h
+		}
+		y = max(y, top+h)
+		w := ctx.MeasureText(markers[i], st).Width
+		m.lines = append(m.lines, rtLine{
+			indent: x + indent - w - 6, y: top, h: h,
+			runs: []rtRun{{text: markers[i], w: w, style: st}},
+		})
+	}
+	return y
+}
+
+// layoutCode lays out a code block, tokenizing it for highlighting.
+func (m *Markdown) layoutCode(ctx *core.Context, b *markdown.Block, x, y, width float32) float32 {
+	th := theme.From(ctx)
+	var tok Tokenizer
+	if m.highlight != nil {
+		tok = m.highlight(b.Lang)
+	}
+	code := mdCode{}
+	state := 0
+	for _, line := range strings.Split(b.Text, "\n") {
+		rs := expandTabs([]rune(line), 4)
+		code.lines = append(code.lines, rs)
+		var toks []Token
+		if tok != nil {
+			toks, state = tok.Tokenize(rs, state)
+		}
+		code.tokens = append(code.tokens, toks)
+	}
+	h := float32(len(code.lines))*th.Typography.Mono.LineHeight() + 2*markdownPadding
+	code.rect = core.R(x, y, width, h)
+	m.codes = append(m.codes, code)
+	m.boxes = append(m.boxes, mdBox{rect: code.rect, radius: 4, style: core.Filled(th.Colors.SurfaceVariant)})
+	return y + h
+}
+
+// expandTabs replaces the tabs in line with spaces, to stops tab apart.
+func expandTabs(line []rune, tab int) []rune {
+	var out []rune
+	for _, r := range line {
+		if r == '\t' {
+			for n := tab - len(out)%tab; n > 0; n-- {
+				out = append(out, ' ')
+			}
+			continue
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// layoutTable lays out a table. Columns take their natural widths when
+// they fit and shrink in proportion, wrapping their cells, when they do
+// not.
+func (m *Markdown) layoutTable(ctx *core.Context, b *markdown.Block, x, y, width float32, body core.TextStyle) float32 {
+	th := theme.From(ctx)
+	header := body
+	header.Weight = core.WeightSemiBold
+	styleOf := func(row int) core.TextStyle {
+		if row == 0 {
+			return header
+		}
+		return body
+	}
+	cols := len(b.Align)
+	widths := make([]float32, cols)
+	var total float32
+	for r, row := range b.Rows {
+		for c, cell := range row {
+			var w float32
+			for _, s := range m.displaySpans(ctx, cell, width) {
+				if s.Image != nil {
+					iw, _ := s.Image.Size()
+					w += iw
+				} else {
+					w += ctx.MeasureText(s.Text, runStyle(th, styleOf(r), s)).Width
+				}
+			}
+			widths[c] = max(widths[c], w+2*markdownPadding)
+		}
+	}
+	for _, w := range widths {
+		total += w
+	}
+	if total > width {
+		// No column shrinks below markdownCellMin, or its natural width if
+		// that is less; the columns held there leave the rest of the width
+		// to the others.
+		held := make([]bool, cols)
+		floor := func(w float32) float32 { return min(w, markdownCellMin) }
+		var scale float32
+		for changed := true; changed; {
+			changed = false
+			free, flex := width, float32(0)
+			for c, w := range widths {
+				if held[c] {
+					free -= floor(w)
+				} else {
+					flex += w
+				}
+			}
+			scale = free / flex
+			for c, w := range widths {
+				if !held[c] && w*scale < floor(w) {
+					held[c], changed = true, true
+				}
+			}
+		}
+		total = 0
+		for c, w := range widths {
+			if held[c] {
+				widths[c] = floor(w)
+			} else {
+				widths[c] = w * scale
+			}
+			total += widths[c]
+		}
+	}
+	for r, row := range b.Rows {
+		var rowH float32
+		cx := x
+		for c, cell := range row {
+			avail := widths[c] - 2*markdownPadding
+			start := len(m.lines)
+			var bottom float32
+			m.lines, bottom = wrapSpans(ctx, m.lines, m.displaySpans(ctx, cell, avail), styleOf(r), avail, rtLine{indent: cx + markdownPadding, y: y + markdownPadding})
+			for k := start; k < len(m.lines); k++ {
+				l := &m.lines[k]
+				var lw float32
+				if n := len(l.runs); n > 0 {
+					lw = l.runs[n-1].x + trimmedWidth(ctx, l, &l.runs[n-1])
+				}
+				switch b.Align[c] {
+				case markdown.AlignCenter:
+					l.indent += max(0, avail-lw) / 2
+				case markdown.AlignRight:
+					l.indent += max(0, avail-lw)
+				}
+			}
+			rowH = max(rowH, bottom-y+markdownPadding)
+			cx += widths[c]
+		}
+		if r == 0 {
+			m.boxes = append(m.boxes, mdBox{rect: core.R(x, y, total, rowH), style: core.Filled(th.Colors.SurfaceVariant)})
+		}
+		cx = x
+		for _, w := range widths {
+			m.boxes = append(m.boxes, mdBox{rect: core.R(cx, y, w, rowH), style: core.Stroked(th.Colors.Outline, 1)})
+			cx += w
+		}
+		y += rowH
+	}
+	return y
+}
+
+// displaySpans returns spans as they are displayed in lines width wide:
+// loaded images shrink to fit, images being loaded are placeholders, and
+// other images are replaced by their alt text. It starts loading the
+// images not yet requested.
+func (m *Markdown) displaySpans(ctx *core.Context, spans []richtext.Span, width float32) []richtext.Span {
+	out := spans
+	copied := false
+	for i, s := range spans {
+		if s.Image == nil {
+			continue
+		}
+		if !copied {
+			out, copied = append([]richtext.Span(nil), spans...), true
+		}
+		img := m.image(ctx, s.Image.Src)
+		switch {
+		case img.data != nil:
+			shown := &richtext.Image{Src: s.Image.Src, Alt: s.Image.Alt, Data: img.data}
+			if w := float32(img.data.Bounds().Dx()); w > width {
+				shown.Width = width
+			}
+			out[i].Image = shown
+		case img.loading:
+			out[i].Image = &richtext.Image{Src: s.Image.Src, Alt: s.Image.Alt}
+		default:
+			out[i] = richtext.Span{Text: s.Image.Alt, Style: s.Style | richtext.Italic, Link: s.Link}
+		}
+	}
+	return out
+}
+
+// image returns the loading state of the image at src, starting to load
+// it on first use.
+func (m *Markdown) image(ctx *core.Context, src string) *mdImage {
+	if img, ok := m.images[src]; ok {
+		return img
+	}
+	if m.images == nil {
+		m.images = map[string]*mdImage{}
+	}
+	img := &mdImage{loading: m.loader != nil}
+	m.images[src] = img
+	if m.loader != nil {
+		m.loader(src, func(data image.Image) {
+			ctx.Post(func() {
+				img.data, img.loading = data, false
+				m.valid = false
+				ctx.MarkNeedsLayout(m)
+			})
+		})
+	}
+	return img
+}
+
+// linkAt returns the URL of the link at p in window coordinates, if any.
+func (m *Markdown) linkAt(p core.Point) string {
+	b := m.Bounds()
+	x, y := p.X-b.X, p.Y-b.Y+m.offset
+	for i := range m.lines {
+		l := &m.lines[i]
+		if y < l.y || y >= l.y+l.h {
+			continue
+		}
+		for _, r := range l.runs {
+			if r.span.Link != "" && x >= l.indent+r.x && x < l.indent+r.x+r.w {
+				return r.span.Link
+			}
+		}
+	}
+	return ""
+}
+
+// SetBounds implements core.Widget.
+func (m *Markdown) SetBounds(r core.Rect) {
+	m.WidgetBase.SetBounds(r)
+	m.bar.Track = core.R(r.Right()-scroll.Thickness, r.Y, scroll.Thickness, r.Height)
+	m.bar.Viewport = r.Height
+	m.bar.Content = m.height
+}
+
+// Paint implements core.Widget.
+func (m *Markdown) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	b := m.Bounds()
+	cv.Save()
+	cv.Clip(b)
+	origin := core.Pt(b.X, b.Y-m.offset)
+	visible := func(y, h float32) bool {
+		return origin.Y+y+h >= b.Y && origin.Y+y <= b.Bottom()
+	}
+	for _, box := range m.boxes {
+		if !visible(box.rect.Y, box.rect.Height) {
+			continue
+		}
+		r := box.rect.Translate(origin)
+		if box.radius > 0 {
+			cv.DrawRoundedRect(r, box.radius, box.style)
+		} else {
+			cv.DrawRect(r, box.style)
+		}
+	}
+	for i := range m.lines {
+		if l := &m.lines[i]; visible(l.y, l.h) {
+			paintRuns(ctx, l, origin)
+		}
+	}
+	mono := th.Typography.Mono
+	lh := mono.LineHeight()
+	for _, c := range m.codes {
+		if !visible(c.rect.Y, c.rect.Height) {
+			continue
+		}
+		r := c.rect.Translate(origin)
+		cv.Save()
+		cv.Clip(r.Inset(core.UniformInsets(markdownPadding / 2)))
+		for i, line := range c.lines {
+			y := r.Y + markdownPadding + float32(i)*lh
+			if y+lh < b.Y || y > b.Bottom() {
+				continue
+			}
+			x := r.X + markdownPadding
+			col := 0
+			draw := func(to int, color core.Color) {
+				if to <= col {
+					return
+				}
+				s := string(line[col:to])
+				st := theme.TextStyle(mono, color)
+				cv.DrawText(s, core.Pt(x, y), st)
+				x += ctx.MeasureText(s, st).Width
+				col = to
+			}
+			for _, t := range c.tokens[i] {
+				draw(t.Start, th.Colors.OnSurface)
+				draw(t.End, tokenColor(th, t.Kind))
+			}
+			draw(len(line), th.Colors.OnSurface)
+		}
+		cv.Restore()
+	}
+	cv.Restore()
+	m.bar.Paint(ctx, m.offset)
+}
+
+// HandleEvent implements core.Widget.
+func (m *Markdown) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	if off, ok := m.bar.HandleEvent(ctx, m, ev, m.offset); ok {
+		if off != m.offset {
+			m.offset = off
+			ctx.Repaint(m)
+		}
+		return core.Handled
+	}
+	switch ev := ev.(type) {
+	case event.ScrollEvent:
+		if !m.bar.Visible() {
+			return core.Ignored
+		}
+		m.offset = core.Clamp(m.offset+ev.Delta.Y, 0, m.bar.MaxOffset())
+		ctx.Repaint(m)
+		return core.Handled
+	case event.MouseEvent:
+		if ev.Button != event.ButtonLeft || m.onLink == nil {
+			return core.Ignored
+		}
+		switch ev.Type {
+		case event.MouseDown:
+			if m.pressed = m.linkAt(ev.Position); m.pressed != "" {
+				ctx.CapturePointer(m)
+				return core.Handled
+			}
+		case event.MouseUp:
+			if m.pressed == "" {
+				return core.Ignored
+			}
+			ctx.ReleasePointer()
+			if link := m.linkAt(ev.Position); link == m.pressed {
+				m.onLink(link)
+			}
+			m.pressed = ""
+			return core.Handled
+		}
+	}
+	return core.Ignored
+}
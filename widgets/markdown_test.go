@@ -0,0 +1,230 @@
+package widgets
+
+import (
+	"image"
+	"strings"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/internal/scroll"
+	"github.com/gogpu/ui/richtext"
+	"github.com/gogpu/ui/theme"
+)
+
+var markdownBounds = core.R(0, 0, 300, 400)
+
+// mdLine returns the first laid out line of m reading text.
+func mdLine(m *Markdown, text string) *rtLine {
+	for i, s := range lineTexts(m.lines) {
+		if strings.TrimSpace(s) == text {
+			return &m.lines[i]
+		}
+	}
+	return nil
+}
+
+func TestMarkdownSize(t *testing.T) {
+	m := NewMarkdown("# Title\n\nSome text.")
+	lc := &core.LayoutContext{Context: core.NewContext()}
+	size := lc.Measure(m, core.Loose(core.Sz(200, 1000)))
+	if size.Width != 200 || size.Height != m.height || m.height == 0 {
+		t.Errorf("size %v for content %v high", size, m.height)
+	}
+	unbounded := lc.Measure(m, core.Constraints{MaxWidth: core.Infinity, MaxHeight: core.Infinity})
+	if unbounded.Width != markdownWidth {
+		t.Errorf("width %v without bounds, want %v", unbounded.Width, markdownWidth)
+	}
+	title, text := mdLine(m, "Title"), mdLine(m, "Some text.")
+	if title == nil || text == nil || text.y < title.y+title.h+markdownGap {
+		t.Fatalf("lines %q", lineTexts(m.lines))
+	}
+	if title.runs[0].style.Size <= text.runs[0].style.Size {
+		t.Error("the heading is not larger than the text")
+	}
+
+	m.SetSource("other")
+	if m.Source() != "other" || m.valid {
+		t.Error("SetSource kept the old layout")
+	}
+}
+
+func TestMarkdownLists(t *testing.T) {
+	m := NewMarkdown("3. a\n4. b\n\n- c\n  - d\n\n> quoted")
+	layoutAt(m, markdownBounds)
+	for _, marker := range []string{"3.", "4.", "•", "◦"} {
+		if mdLine(m, marker) == nil {
+			t.Errorf("no %q marker in %q", marker, lineTexts(m.lines))
+		}
+	}
+	a, three := mdLine(m, "a"), mdLine(m, "3.")
+	if a == nil || three.y != a.y || three.indent >= a.indent {
+		t.Error("the marker is not before its item")
+	}
+	if d, c := mdLine(m, "d"), mdLine(m, "c"); d.indent <= c.indent {
+		t.Errorf("the nested item at %v is not indented past %v", d.indent, c.indent)
+	}
+	th := theme.From(nil)
+	if q := mdLine(m, "quoted"); q.runs[0].style.Color != th.Colors.OnSurfaceVariant || q.indent != markdownQuote {
+		t.Errorf("quoted text at %v in %v", q.indent, q.runs[0].style.Color)
+	}
+}
+
+func TestMarkdownCode(t *testing.T) {
+	tests := []struct {
+		name      string
+		src       string
+		highlight func(string) Tokenizer
+		line      string
+		tokens    bool
+	}{
+		{"go", "```go\nfunc f() {}\n```", nil, "func f() {}", true},
+		{"unknown language", "```text\nfunc f() {}\n```", nil, "func f() {}", false},
+		{"custom highlighting", "```text\nfunc f()\n```", func(string) Tokenizer { return GoSyntax }, "func f()", true},
+		{"tabs", "```\n\tx\n```", nil, "    x", false},
+	}
+	for _, tt := range tests {
+		m := NewMarkdown(tt.src)
+		if tt.highlight != nil {
+			m.Highlight(tt.highlight)
+		}
+		layoutAt(m, markdownBounds)
+		if len(m.codes) != 1 {
+			t.Fatalf("%s: %d code blocks", tt.name, len(m.codes))
+		}
+		c := m.codes[0]
+		if string(c.lines[0]) != tt.line || (len(c.tokens[0]) > 0) != tt.tokens {
+			t.Errorf("%s: line %q with tokens %v", tt.name, string(c.lines[0]), c.tokens[0])
+		}
+		if c.rect.Width != markdownBounds.Width {
+			t.Errorf("%s: the block is %v wide", tt.name, c.rect.Width)
+		}
+	}
+}
+
+func TestMarkdownTable(t *testing.T) {
+	m := NewMarkdown("| left | right |\n|:-----|------:|\n| a | b |")
+	layoutAt(m, markdownBounds)
+	a, b := mdLine(m, "a"), mdLine(m, "b")
+	right := mdLine(m, "right")
+	if a == nil || b == nil || right == nil {
+		t.Fatalf("lines %q", lineTexts(m.lines))
+	}
+	if got, want := b.indent+b.runs[0].w, right.indent+right.runs[0].w; got < want-0.01 || got > want+0.01 {
+		t.Errorf("right-aligned cell ends at %v, want %v under its header", got, want)
+	}
+	if mdLine(m, "left").runs[0].style.Weight != core.WeightSemiBold {
+		t.Error("the header row is not emphasized")
+	}
+
+	// A table wider than the widget shrinks its columns and wraps them.
+	wide := NewMarkdown("| " + strings.Repeat("word ", 30) + "| x |\n|---|---|\n| a | b |")
+	layoutAt(wide, markdownBounds)
+	var cellsRight float32
+	for _, box := range wide.boxes {
+		cellsRight = max(cellsRight, box.rect.Right())
+	}
+	if cellsRight > markdownBounds.Width+0.01 || cellsRight < markdownBounds.Width-0.01 {
+		t.Errorf("the table reaches %v, want the width %v", cellsRight, markdownBounds.Width)
+	}
+	if n := strings.Count(strings.Join(lineTexts(wide.lines), "\n"), "word"); n != 30 || len(wide.lines) < 5 {
+		t.Errorf("%d lines holding %d words", len(wide.lines), n)
+	}
+
+	// Too narrow for every column's minimum, the columns keep it.
+	layoutAt(wide, core.R(0, 0, 60, 400))
+	for _, box := range wide.boxes {
+		if box.rect.Width < min(markdownCellMin, 16) {
+			t.Errorf("a column shrank to %v", box.rect.Width)
+		}
+	}
+}
+
+func TestMarkdownImages(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 1000, 500))
+	var loads []string
+	var done []func(image.Image)
+	m := NewMarkdown("![a](x.png) ![b](x.png) ![c](bad.png)").Images(func(src string, d func(image.Image)) {
+		loads = append(loads, src)
+		done = append(done, d)
+	})
+	ctx := layoutAt(m, markdownBounds)
+	if len(loads) != 2 {
+		t.Fatalf("loaded %v, want each source once", loads)
+	}
+	for _, s := range m.lines[0].runs {
+		if s.span.Image == nil && strings.TrimSpace(s.text) != "" || s.span.Image != nil && s.span.Image.Data != nil {
+			t.Errorf("run %+v while loading, want a placeholder", s.span)
+		}
+	}
+	done[0](img)
+	done[1](nil)
+	ctx.RunPosted()
+	ctx.LayoutRoot(m, markdownBounds)
+	var shown []richtext.Span
+	for _, l := range m.lines {
+		for _, r := range l.runs {
+			shown = append(shown, r.span)
+		}
+	}
+	if first := shown[0]; first.Image == nil || first.Image.Data != img || first.Image.Width != markdownBounds.Width {
+		t.Errorf("loaded image shown as %+v, want shrunk to the width", first)
+	}
+	last := shown[len(shown)-1]
+	if last.Image != nil || last.Text != "c" || !last.Style.Has(richtext.Italic) {
+		t.Errorf("failed image shown as %+v, want its alt text", last)
+	}
+
+	plain := NewMarkdown("![alt](y.png)")
+	layoutAt(plain, markdownBounds)
+	if got := lineTexts(plain.lines); got[0] != "alt" {
+		t.Errorf("without a loader the image shows %q", got)
+	}
+}
+
+func TestMarkdownLinks(t *testing.T) {
+	m := NewMarkdown("see [docs](http://d) here")
+	ctx := layoutAt(m, markdownBounds)
+	if r := m.HandleEvent(ctx, leftMouse(event.MouseDown, core.Pt(1, 1))); r != core.Ignored {
+		t.Errorf("a press without OnLink = %v", r)
+	}
+	var opened []string
+	m.OnLink(func(url string) { opened = append(opened, url) })
+	var link core.Point
+	for _, r := range m.lines[0].runs {
+		if r.span.Link != "" {
+			link = core.Pt(m.lines[0].indent+r.x+r.w/2, m.lines[0].y+m.lines[0].h/2)
+		}
+	}
+	m.HandleEvent(ctx, leftMouse(event.MouseDown, link))
+	m.HandleEvent(ctx, leftMouse(event.MouseUp, link))
+	m.HandleEvent(ctx, leftMouse(event.MouseDown, link))
+	m.HandleEvent(ctx, leftMouse(event.MouseUp, core.Pt(1, 1)))
+	if len(opened) != 1 || opened[0] != "http://d" {
+		t.Errorf("opened %v, want the link once", opened)
+	}
+	if r := m.HandleEvent(ctx, leftMouse(event.MouseDown, core.Pt(1, 1))); r != core.Ignored {
+		t.Errorf("a press beside the link = %v", r)
+	}
+}
+
+func TestMarkdownScroll(t *testing.T) {
+	short := NewMarkdown("a")
+	ctx := layoutAt(short, markdownBounds)
+	if r := short.HandleEvent(ctx, event.ScrollEvent{Delta: core.Pt(0, 50)}); r != core.Ignored {
+		t.Errorf("scrolling content that fits = %v", r)
+	}
+	long := NewMarkdown(strings.Repeat("paragraph\n\n", 100))
+	ctx = layoutAt(long, markdownBounds)
+	if long.width != markdownBounds.Width-scroll.Thickness {
+		t.Errorf("laid out %v wide, want room for the scroll bar", long.width)
+	}
+	long.HandleEvent(ctx, event.ScrollEvent{Delta: core.Pt(0, 50)})
+	if long.offset != 50 {
+		t.Errorf("offset %v, want 50", long.offset)
+	}
+	long.HandleEvent(ctx, event.ScrollEvent{Delta: core.Pt(0, 1e6)})
+	if long.offset != long.height-markdownBounds.Height {
+		t.Errorf("offset %v past the end", long.offset)
+	}
+}
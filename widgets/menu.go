@@ -0,0 +1,485 @@
+package widgets
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/shortcuts"
+	"github.com/gogpu/ui/theme"
+)
+
+// MenuItem is an entry of a menu: a command, a checkable option, a
+// submenu or a separator.
+//
+// An ampersand in Label marks the following letter as the item's mnemonic,
+// which is underlined and selects the item when typed while the menu is
+// open ("&Save"); "&&" produces a literal ampersand.
+type MenuItem struct {
+	// Label is the item's text, including an optional mnemonic marker.
+	Label string
+
+	// Accelerator is the shortcut displayed right-aligned, such as
+	// "Ctrl+S". It is display text only; binding the key is up to the
+	// application.
+	Accelerator string
+
+	// CommandID names the command of the window's shortcut registry the
+	// item performs. Without an Accelerator the item shows the command's
+	// binding, and without OnClick it runs the command with the registry
+	// of the window or the App.
+	CommandID string
+
+	// Checkable items toggle Checked when activated and show a check mark.
+	Checkable bool
+
+	// Checked is the state of a checkable item.
+	Checked bool
+
+	// Disabled items are shown dimmed and cannot be activated.
+	Disabled bool
+
+	// Items makes the item a submenu.
+	Items []*MenuItem
+
+	// OnClick is called when the item is activated, after the menu closed.
+	OnClick func()
+
+	separator bool
+}
+
+// NewMenuItem returns a command item.
+func NewMenuItem(label string, onClick func()) *MenuItem {
+	return &MenuItem{Label: label, OnClick: onClick}
+}
+
+// NewCheckItem returns a checkable item. onToggle receives the new state.
+func NewCheckItem(label string, checked bool, onToggle func(checked bool)) *MenuItem {
+	it := &MenuItem{Label: label, Checkable: true, Checked: checked}
+	if onToggle != nil {
+		it.OnClick = func() { onToggle(it.Checked) }
+	}
+	return it
+}
+
+// NewSubmenu returns an item that opens items in a cascading menu.
+func NewSubmenu(label string, items ...*MenuItem) *MenuItem {
+	return &MenuItem{Label: label, Items: items}
+}
+
+// MenuSeparator returns a separator line.
+func MenuSeparator() *MenuItem {
+	return &MenuItem{separator: true}
+}
+
+// Shortcut sets the displayed accelerator.
+func (it *MenuItem) Shortcut(accel string) *MenuItem {
+	it.Accelerator = accel
+	return it
+}
+
+// Command sets the ID of the shortcut command the item performs.
+func (it *MenuItem) Command(id string) *MenuItem {
+	it.CommandID = id
+	return it
+}
+
+// Enabled enables or disables the item.
+func (it *MenuItem) Enabled(enabled bool) *MenuItem {
+	it.Disabled = !enabled
+	return it
+}
+
+// IsSeparator reports whether the item is a separator.
+func (it *MenuItem) IsSeparator() bool {
+	return it.separator
+}
+
+func (it *MenuItem) selectable() bool {
+	return !it.separator && !it.Disabled
+}
+
+// accelerator returns the shortcut the item shows.
+func (it *MenuItem) accelerator(ctx *core.Context) string {
+	if it.Accelerator != "" || it.CommandID == "" {
+		return it.Accelerator
+	}
+	return shortcuts.Label(ctx, it.CommandID)
+}
+
+// parseMnemonic strips the ampersand markers from label and returns the
+// display text, the lower-cased mnemonic (0 if none) and its rune index.
+func parseMnemonic(label string) (text string, mnemonic rune, at int) {
+	var b strings.Builder
+	at = -1
+	runes := []rune(label)
+	n := 0
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if r == '&' && i+1 < len(runes) {
+			i++
+			r = runes[i]
+			if r != '&' && mnemonic == 0 {
+				mnemonic, at = unicode.ToLower(r), n
+			}
+		}
+		b.WriteRune(r)
+		n++
+	}
+	return b.String(), mnemonic, at
+}
+
+// keyRune returns the lower-case letter or digit for a key, or 0.
+func keyRune(k event.Key) rune {
+	switch {
+	case k >= event.KeyA && k <= event.KeyZ:
+		return 'a' + rune(k-event.KeyA)
+	case k >= event.Key0 && k <= event.Key9:
+		return '0' + rune(k-event.Key0)
+	}
+	return 0
+}
+
+// drawMnemonicText draws a label at pos, underlining its mnemonic.
+func drawMnemonicText(ctx *core.PaintContext, label string, pos core.Point, style core.TextStyle) core.Size {
+	text, _, at := parseMnemonic(label)
+	size := ctx.MeasureText(text, style)
+	ctx.Canvas.DrawText(text, pos, style)
+	if at >= 0 {
+		runes := []rune(text)
+		x := ctx.MeasureText(string(runes[:at]), style).Width
+		w := ctx.MeasureText(string(runes[at]), style).Width
+		y := pos.Y + style.Size*1.1
+		ctx.Canvas.DrawRect(core.R(pos.X+x, y, w, 1), core.Filled(style.Color))
+	}
+	return size
+}
+
+const (
+	menuItemHeight      float32 = 26
+	menuSeparatorHeight float32 = 9
+	menuCheckWidth      float32 = 26
+	menuArrowWidth      float32 = 18
+	menuAccelGap        float32 = 32
+	menuMinWidth        float32 = 160
+	menuPadding         float32 = 4
+)
+
+// menuSession is a chain of open cascading menus.
+type menuSession struct {
+	root    *menuPopup
+	onClose func()
+
+	// onNavigate, if set, moves to the neighbouring top-level menu when
+	// Left or Right is pressed at the ends of the chain.
+	onNavigate func(ctx *core.Context, delta int)
+}
+
+func (s *menuSession) close(ctx *core.Context) {
+	if s.root != nil && s.root.overlay != nil {
+		ctx.CloseOverlay(s.root.overlay)
+	}
+}
+
+// openMenu shows items as a popup placed by placement and focuses it.
+func openMenu(ctx *core.Context, items []*MenuItem, placement func(core.Size, core.Rect) core.Point, owner core.Widget, keyboard bool) *menuSession {
+	s := &menuSession{}
+	s.root = newMenuPopup(s, nil, items)
+	s.root.show(ctx, placement, owner, keyboard)
+	return s
+}
+
+// menuPopup is the overlay widget that displays one level of a menu.
+type menuPopup struct {
+	core.WidgetBase
+	core.FocusState
+
+	session *menuSession
+	parent  *menuPopup
+	child   *menuPopup
+	from    *MenuItem // The submenu item that opened this level.
+	items   []*MenuItem
+	overlay *core.Overlay
+	rows    []core.Rect
+	hover   int
+	accelW  float32
+	dir     core.Direction
+}
+
+func newMenuPopup(s *menuSession, parent *menuPopup, items []*MenuItem) *menuPopup {
+	return &menuPopup{session: s, parent: parent, items: items, hover: -1}
+}
+
+func (m *menuPopup) show(ctx *core.Context, placement func(core.Size, core.Rect) core.Point, owner core.Widget, keyboard bool) {
+	m.overlay = &core.Overlay{
+		Content:      m,
+		Placement:    placement,
+		LightDismiss: true,
+		Popup:        true,
+		Owner:        owner,
+		OnClose: func() {
+			if m.parent != nil && m.parent.child == m {
+				m.parent.child = nil
+			}
+			if m.parent == nil && m.session.onClose != nil {
+				m.session.onClose()
+			}
+		},
+	}
+	ctx.ShowOverlay(m.overlay)
+	ctx.RequestFocus(m)
+	if keyboard {
+		m.moveHover(1)
+	}
+}
+
+// Layout implements core.Widget.
+func (m *menuPopup) Layout(ctx *core.LayoutContext) core.Size {
+	m.dir = ctx.Direction()
+	th := theme.From(ctx.Context)
+	style := th.Typography.Body
+	var labelW, h float32
+	m.accelW = 0
+	for _, it := range m.items {
+		if it.separator {
+			h += menuSeparatorHeight
+			continue
+		}
+		text, _, _ := parseMnemonic(it.Label)
+		labelW = max(labelW, ctx.MeasureText(text, style).Width)
+		if accel := it.accelerator(ctx.Context); accel != "" {
+			m.accelW = max(m.accelW, ctx.MeasureText(accel, style).Width)
+		}
+		h += menuItemHeight
+	}
+	w := menuCheckWidth + labelW + menuArrowWidth
+	if m.accelW > 0 {
+		w += menuAccelGap + m.accelW
+	}
+	return ctx.Constraints.Constrain(core.Sz(max(menuMinWidth, w), h+2*menuPadding))
+}
+
+// SetBounds implements core.Widget.
+func (m *menuPopup) SetBounds(r core.Rect) {
+	m.WidgetBase.SetBounds(r)
+	m.rows = m.rows[:0]
+	y := r.Y + menuPadding
+	for _, it := range m.items {
+		h := menuItemHeight
+		if it.separator {
+			h = menuSeparatorHeight
+		}
+		m.rows = append(m.rows, core.R(r.X+menuPadding, y, r.Width-2*menuPadding, h))
+		y += h
+	}
+}
+
+// Paint implements core.Widget.
+func (m *menuPopup) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	b := m.Bounds()
+	core.DrawShadow(cv, b, th.Radii.Small, th.Elevation(theme.ElevationMenu)...)
+	cv.DrawRoundedRect(b, th.Radii.Small, core.RectStyle{Fill: th.Colors.Surface, Stroke: th.Colors.Outline.WithAlpha(0.5), StrokeWidth: 1})
+	for i, it := range m.items {
+		r := m.rows[i]
+		if it.separator {
+			cv.DrawRect(core.R(r.X+4, r.Y+r.Height/2, r.Width-8, 1), core.Filled(th.Colors.Outline.WithAlpha(0.4)))
+			continue
+		}
+		color := th.Colors.OnSurface
+		if it.Disabled {
+			color = th.Colors.OnSurfaceVariant.WithAlpha(0.5)
+		}
+		if i == m.hover && it.selectable() {
+			cv.DrawRoundedRect(r, th.Radii.Small, core.Filled(th.Colors.Selection))
+		}
+		style := theme.TextStyle(th.Typography.Body, color)
+		ty := r.Y + (r.Height-style.LineHeight())/2
+		// at returns the left edge of a part w wide at x from the start
+		// of the row.
+		at := func(x, w float32) float32 {
+			return m.dir.Mirror(core.R(r.X+x, r.Y, w, r.Height), r).X
+		}
+		if it.Checkable && it.Checked {
+			c := core.Pt(at(0, menuCheckWidth)+menuCheckWidth/2, r.Y+r.Height/2)
+			check := core.NewPath().
+				MoveTo(core.Pt(c.X-4, c.Y)).LineTo(core.Pt(c.X-1, c.Y+3)).LineTo(core.Pt(c.X+4, c.Y-4))
+			cv.DrawPath(check, core.PathStyle{Stroke: color, StrokeWidth: 1.5, LineCap: core.CapRound, LineJoin: core.JoinRound})
+		}
+		text, _, _ := parseMnemonic(it.Label)
+		w := ctx.MeasureText(text, style).Width
+		drawMnemonicText(ctx, it.Label, core.Pt(at(menuCheckWidth, w), ty), style)
+		if text := it.accelerator(ctx.Context); text != "" {
+			accel := theme.TextStyle(th.Typography.Body, th.Colors.OnSurfaceVariant)
+			w := ctx.MeasureText(text, accel).Width
+			cv.DrawText(text, core.Pt(at(r.Width-menuArrowWidth-w, w), ty), accel)
+		}
+		if len(it.Items) > 0 {
+			paintDisclosure(ctx, core.R(at(r.Width-menuArrowWidth, menuArrowWidth), r.Y, menuArrowWidth, r.Height), false, color)
+		}
+	}
+}
+
+func (m *menuPopup) rowAt(p core.Point) int {
+	for i, r := range m.rows {
+		if r.Contains(p) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *menuPopup) moveHover(delta int) {
+	n := len(m.items)
+	if n == 0 {
+		return
+	}
+	i := m.hover
+	for range n {
+		if i < 0 && delta < 0 {
+			i = n
+		}
+		i = (i + delta + n) % n
+		if m.items[i].selectable() {
+			m.hover = i
+			return
+		}
+	}
+}
+
+func (m *menuPopup) openChild(ctx *core.Context, i int, keyboard bool) {
+	it := m.items[i]
+	if m.child != nil {
+		if m.child.from == it {
+			if keyboard {
+				ctx.RequestFocus(m.child)
+				m.child.moveHover(1)
+			}
+			return
+		}
+		ctx.CloseOverlay(m.child.overlay)
+	}
+	if len(it.Items) == 0 || it.Disabled {
+		return
+	}
+	m.child = newMenuPopup(m.session, m, it.Items)
+	m.child.from = it
+	// Submenus cascade toward the end edge.
+	anchor := m.rows[i].Inset(core.Insets{Top: -menuPadding, Right: -menuPadding - 2})
+	side := core.SideRight
+	if m.dir.IsRTL() {
+		anchor = m.rows[i].Inset(core.Insets{Top: -menuPadding, Left: -menuPadding - 2})
+		side = core.SideLeft
+	}
+	m.child.show(ctx, core.PlaceAnchored(anchor, side), m, keyboard)
+	if !keyboard {
+		// Hovering opens the submenu but leaves the keyboard here.
+		ctx.RequestFocus(m)
+	}
+}
+
+func (m *menuPopup) activate(ctx *core.Context, i int) {
+	it := m.items[i]
+	if !it.selectable() {
+		return
+	}
+	if len(it.Items) > 0 {
+		m.openChild(ctx, i, true)
+		return
+	}
+	if it.Checkable {
+		it.Checked = !it.Checked
+	}
+	m.session.close(ctx)
+	switch {
+	case it.OnClick != nil:
+		it.OnClick()
+	case it.CommandID != "":
+		shortcuts.Of(ctx).Run(it.CommandID)
+	}
+}
+
+// HandleEvent implements core.Widget.
+func (m *menuPopup) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	switch e := ev.(type) {
+	case event.MouseEvent:
+		switch e.Type {
+		case event.MouseMove:
+			i := m.rowAt(e.Position)
+			if i != m.hover && (i < 0 || m.items[i].selectable()) {
+				m.hover = i
+				if i >= 0 {
+					m.openChild(ctx, i, false)
+				}
+				ctx.Repaint(m)
+			}
+		case event.MouseUp:
+			if e.Button != event.ButtonLeft && e.Button != event.ButtonRight {
+				break
+			}
+			if i := m.rowAt(e.Position); i >= 0 && len(m.items[i].Items) == 0 {
+				m.activate(ctx, i)
+			}
+		}
+		return core.Handled
+	case event.ScrollEvent:
+		return core.Handled
+	case event.KeyEvent:
+		if e.Type != event.KeyPress {
+			return core.Ignored
+		}
+		return m.handleKey(ctx, e)
+	}
+	return core.Ignored
+}
+
+func (m *menuPopup) handleKey(ctx *core.Context, e event.KeyEvent) core.EventResult {
+	switch mirrorKey(m.dir, e.Key) {
+	case event.KeyUp:
+		m.moveHover(-1)
+	case event.KeyDown:
+		m.moveHover(1)
+	case event.KeyHome:
+		m.hover = -1
+		m.moveHover(1)
+	case event.KeyEnd:
+		m.hover = -1
+		m.moveHover(-1)
+	case event.KeyRight:
+		if m.hover >= 0 && len(m.items[m.hover].Items) > 0 {
+			m.openChild(ctx, m.hover, true)
+		} else if m.session.onNavigate != nil {
+			m.session.onNavigate(ctx, 1)
+		}
+	case event.KeyLeft:
+		if m.parent != nil {
+			ctx.CloseOverlay(m.overlay)
+		} else if m.session.onNavigate != nil {
+			m.session.onNavigate(ctx, -1)
+		}
+	case event.KeyEnter, event.KeySpace:
+		if m.hover >= 0 {
+			m.activate(ctx, m.hover)
+		}
+	case event.KeyEscape:
+		ctx.CloseOverlay(m.overlay)
+	case event.KeyTab:
+		// Keep focus from leaving the menu.
+	default:
+		r := keyRune(e.Key)
+		if r == 0 || e.Modifiers.Has(event.ModCtrl) {
+			return core.Ignored
+		}
+		for i, it := range m.items {
+			if _, mn, _ := parseMnemonic(it.Label); mn == r && it.selectable() {
+				m.hover = i
+				m.activate(ctx, i)
+				break
+			}
+		}
+	}
+	ctx.Repaint(m)
+	return core.Handled
+}
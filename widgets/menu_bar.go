@@ -0,0 +1,222 @@
+package widgets
+
+import (
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/theme"
+)
+
+const (
+	menuBarHeight  float32 = 28
+	menuBarPadding float32 = 10
+)
+
+// MenuBar is a horizontal bar of drop-down menus with cascading submenus.
+//
+// Each top-level entry is a submenu item created with NewSubmenu. Menus
+// open on click; while one is open, hovering another title switches to it.
+// Alt plus a title's mnemonic letter opens that menu from anywhere in the
+// window and F10 opens the first one. Inside a menu the arrow keys
+// navigate, Left and Right move between top-level menus, letters select
+// items by mnemonic and Escape closes the menu.
+//
+// Menus are shown as popup overlays, so they extend past the window's
+// client area when the platform provides popup surfaces. In right-to-left
+// text the titles run from the right, menus drop down aligned with the
+// right edge of their title and submenus cascade to the left.
+type MenuBar struct {
+	core.WidgetBase
+
+	menus   []*MenuItem
+	widths  []float32
+	titles  []core.Rect
+	open    int
+	hover   int
+	session *menuSession
+	dir     core.Direction
+}
+
+// NewMenuBar returns a menu bar with the given top-level menus.
+func NewMenuBar(menus ...*MenuItem) *MenuBar {
+	return &MenuBar{menus: menus, open: -1, hover: -1}
+}
+
+// Menus returns the top-level menus.
+func (b *MenuBar) Menus() []*MenuItem {
+	return b.menus
+}
+
+// SetMenus replaces the top-level menus.
+func (b *MenuBar) SetMenus(menus ...*MenuItem) {
+	b.menus = menus
+	b.open, b.hover = -1, -1
+}
+
+// IsOpen reports whether one of the bar's menus is open.
+func (b *MenuBar) IsOpen() bool {
+	return b.open >= 0
+}
+
+// Layout implements core.Widget.
+func (b *MenuBar) Layout(ctx *core.LayoutContext) core.Size {
+	b.dir = ctx.Direction()
+	style := theme.From(ctx.Context).Typography.Body
+	b.widths = b.widths[:0]
+	var x float32
+	for _, m := range b.menus {
+		text, _, _ := parseMnemonic(m.Label)
+		w := ctx.MeasureText(text, style).Width + 2*menuBarPadding
+		b.widths = append(b.widths, w)
+		x += w
+	}
+	size := core.Sz(x, menuBarHeight)
+	if ctx.Constraints.HasBoundedWidth() {
+		size.Width = ctx.Constraints.MaxWidth
+	}
+	return ctx.Constraints.Constrain(size)
+}
+
+// SetBounds implements core.Widget.
+func (b *MenuBar) SetBounds(r core.Rect) {
+	b.WidgetBase.SetBounds(r)
+	b.titles = b.titles[:0]
+	x := r.X
+	for _, w := range b.widths {
+		b.titles = append(b.titles, b.dir.Mirror(core.R(x, r.Y, w, r.Height), r))
+		x += w
+	}
+}
+
+// Paint implements core.Widget.
+func (b *MenuBar) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	cv.DrawRect(b.Bounds(), core.Filled(th.Colors.SurfaceVariant))
+	for i, m := range b.menus {
+		r := b.titles[i]
+		switch {
+		case i == b.open:
+			cv.DrawRect(r, core.Filled(th.Colors.Selection))
+		case i == b.hover:
+			cv.DrawRect(r, core.Filled(th.Colors.OnSurface.WithAlpha(0.06)))
+		}
+		color := th.Colors.OnSurface
+		if m.Disabled {
+			color = th.Colors.OnSurfaceVariant.WithAlpha(0.5)
+		}
+		style := theme.TextStyle(th.Typography.Body, color)
+		drawMnemonicText(ctx, m.Label, core.Pt(r.X+menuBarPadding, r.Y+(r.Height-style.LineHeight())/2), style)
+	}
+}
+
+func (b *MenuBar) titleAt(p core.Point) int {
+	for i, r := range b.titles {
+		if r.Contains(p) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (b *MenuBar) openMenu(ctx *core.Context, i int, keyboard bool) {
+	if b.session != nil {
+		s := b.session
+		b.session = nil
+		s.close(ctx)
+	}
+	b.open = -1
+	if i < 0 || i >= len(b.menus) || b.menus[i].Disabled {
+		ctx.Repaint(b)
+		return
+	}
+	b.open = i
+	s := openMenu(ctx, b.menus[i].Items, placeBelow(b.titles[i], b.dir), b, keyboard)
+	s.onClose = func() {
+		if b.session == s {
+			b.session, b.open = nil, -1
+		}
+	}
+	s.onNavigate = func(ctx *core.Context, delta int) {
+		n := len(b.menus)
+		next := b.open
+		for range n {
+			next = (next + delta + n) % n
+			if !b.menus[next].Disabled {
+				break
+			}
+		}
+		b.openMenu(ctx, next, true)
+	}
+	b.session = s
+	ctx.Repaint(b)
+}
+
+func (b *MenuBar) close(ctx *core.Context) {
+	if b.session != nil {
+		b.session.close(ctx)
+	}
+}
+
+// HandleEvent implements core.Widget.
+func (b *MenuBar) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	e, ok := ev.(event.MouseEvent)
+	if !ok {
+		return core.Ignored
+	}
+	switch e.Type {
+	case event.MouseMove:
+		i := b.titleAt(e.Position)
+		if i != b.hover {
+			b.hover = i
+			ctx.Repaint(b)
+		}
+		if i >= 0 && b.open >= 0 && i != b.open {
+			b.openMenu(ctx, i, false)
+		}
+	case event.MouseLeave:
+		if b.hover >= 0 {
+			b.hover = -1
+			ctx.Repaint(b)
+		}
+	case event.MouseDown:
+		i := b.titleAt(e.Position)
+		if i < 0 {
+			b.close(ctx)
+			return core.Handled
+		}
+		if i == b.open {
+			b.close(ctx)
+		} else {
+			b.openMenu(ctx, i, false)
+		}
+		return core.Handled
+	}
+	return core.Ignored
+}
+
+// HandleShortcut implements core.ShortcutHandler, opening menus on Alt
+// plus their mnemonic and on F10.
+func (b *MenuBar) HandleShortcut(ctx *core.Context, ev core.Event) core.EventResult {
+	e, ok := ev.(event.KeyEvent)
+	if !ok || e.Type != event.KeyPress {
+		return core.Ignored
+	}
+	if e.Key == event.KeyF10 && e.Modifiers == 0 && len(b.menus) > 0 {
+		b.openMenu(ctx, 0, true)
+		return core.Handled
+	}
+	if e.Modifiers != event.ModAlt {
+		return core.Ignored
+	}
+	r := keyRune(e.Key)
+	if r == 0 {
+		return core.Ignored
+	}
+	for i, m := range b.menus {
+		if _, mn, _ := parseMnemonic(m.Label); mn == r && !m.Disabled {
+			b.openMenu(ctx, i, true)
+			return core.Handled
+		}
+	}
+	return core.Ignored
+}
@@ -0,0 +1,319 @@
+package widgets
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+)
+
+// press returns a key press of k with mods.
+func press(k event.Key, mods event.Modifiers) event.KeyEvent {
+	return event.KeyEvent{Type: event.KeyPress, Key: k, Modifiers: mods}
+}
+
+// layoutOverlays lays the open overlays of ctx out as the window does and
+// returns the menu popups among them, from bottom to top.
+func layoutOverlays(ctx *core.Context) []*menuPopup {
+	area := core.R(0, 0, 800, 600)
+	lc := &core.LayoutContext{Context: ctx}
+	var popups []*menuPopup
+	for _, o := range ctx.Overlays() {
+		size := lc.Measure(o.Content, core.Loose(area.Size()))
+		p := o.Placement(size, area)
+		o.Content.SetBounds(core.R(p.X, p.Y, size.Width, size.Height))
+		if m, ok := o.Content.(*menuPopup); ok {
+			popups = append(popups, m)
+		}
+	}
+	return popups
+}
+
+// hovered returns the label of the item hovered in m, or "".
+func hovered(m *menuPopup) string {
+	if m.hover < 0 {
+		return ""
+	}
+	return m.items[m.hover].Label
+}
+
+func TestParseMnemonic(t *testing.T) {
+	tests := []struct {
+		label    string
+		text     string
+		mnemonic rune
+		at       int
+	}{
+		{"&Save", "Save", 's', 0},
+		{"Save &As", "Save As", 'a', 5},
+		{"Plain", "Plain", 0, -1},
+		{"Fish && &Chips", "Fish & Chips", 'c', 7},
+		{"&A &B", "A B", 'a', 0},
+		{"Trailing&", "Trailing&", 0, -1},
+		{"&Ünicode", "Ünicode", 'ü', 0},
+	}
+	for _, tt := range tests {
+		text, mn, at := parseMnemonic(tt.label)
+		if text != tt.text || mn != tt.mnemonic || at != tt.at {
+			t.Errorf("parseMnemonic(%q) = %q, %q, %d, want %q, %q, %d", tt.label, text, mn, at, tt.text, tt.mnemonic, tt.at)
+		}
+	}
+}
+
+func TestMenuKeys(t *testing.T) {
+	tests := []struct {
+		name  string
+		keys  []event.Key
+		hover string
+	}{
+		{"opened by keyboard", nil, "&New"},
+		{"down skips separators and disabled items", []event.Key{event.KeyDown}, "&Save"},
+		{"down wraps", []event.Key{event.KeyDown, event.KeyDown}, "&New"},
+		{"up wraps", []event.Key{event.KeyUp}, "&Save"},
+		{"end", []event.Key{event.KeyEnd}, "&Save"},
+		{"home", []event.Key{event.KeyEnd, event.KeyHome}, "&New"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := []*MenuItem{
+				NewMenuItem("&New", nil),
+				MenuSeparator(),
+				NewMenuItem("&Open", nil).Enabled(false),
+				NewMenuItem("&Save", nil),
+			}
+			ctx := core.NewContext()
+			openMenu(ctx, items, core.PlaceAt(core.Pt(10, 10)), nil, true)
+			m := layoutOverlays(ctx)[0]
+			for _, k := range tt.keys {
+				m.HandleEvent(ctx, press(k, 0))
+			}
+			if got := hovered(m); got != tt.hover {
+				t.Errorf("hover = %q, want %q", got, tt.hover)
+			}
+		})
+	}
+}
+
+func TestMenuActivate(t *testing.T) {
+	tests := []struct {
+		name    string
+		ev      core.Event
+		clicked string
+	}{
+		{"enter", press(event.KeyEnter, 0), "open"},
+		{"mnemonic", press(event.KeyW, 0), "wrap"},
+		{"disabled mnemonic", press(event.KeyD, 0), ""},
+		{"escape", press(event.KeyEscape, 0), ""},
+		{"click", event.MouseEvent{Type: event.MouseUp, Position: core.Pt(50, 10+menuPadding+menuItemHeight+5), Button: event.ButtonLeft}, "wrap"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := core.NewContext()
+			var clicked []string
+			var openWhenClicked bool
+			record := func(name string) {
+				clicked = append(clicked, name)
+				openWhenClicked = len(ctx.Overlays()) > 0
+			}
+			wrap := NewCheckItem("&Wrap", false, func(bool) { record("wrap") })
+			items := []*MenuItem{
+				NewMenuItem("&Open", func() { record("open") }),
+				wrap,
+				NewMenuItem("&Delete", func() { record("delete") }).Enabled(false),
+			}
+			var closed bool
+			s := openMenu(ctx, items, core.PlaceAt(core.Pt(10, 10)), nil, true)
+			s.onClose = func() { closed = true }
+			m := layoutOverlays(ctx)[0]
+			m.HandleEvent(ctx, tt.ev)
+			if got := strings.Join(clicked, " "); got != tt.clicked {
+				t.Errorf("clicked %q, want %q", got, tt.clicked)
+			}
+			if openWhenClicked {
+				t.Error("OnClick ran before the menu closed")
+			}
+			if wrap.Checked != (tt.clicked == "wrap") {
+				t.Errorf("Checked = %v", wrap.Checked)
+			}
+			if want := tt.name != "disabled mnemonic"; closed != want {
+				t.Errorf("closed = %v, want %v", closed, want)
+			}
+		})
+	}
+}
+
+func TestMenuSubmenu(t *testing.T) {
+	ctx := core.NewContext()
+	items := []*MenuItem{
+		NewSubmenu("&Recent", NewMenuItem("a", nil), NewMenuItem("b", nil)),
+		NewMenuItem("&Quit", nil),
+	}
+	openMenu(ctx, items, core.PlaceAt(core.Pt(10, 10)), nil, true)
+	root := layoutOverlays(ctx)[0]
+
+	root.HandleEvent(ctx, press(event.KeyRight, 0))
+	popups := layoutOverlays(ctx)
+	if len(popups) != 2 {
+		t.Fatalf("%d menus open after Right, want 2", len(popups))
+	}
+	child := popups[1]
+	if !ctx.IsFocused(child) || hovered(child) != "a" {
+		t.Errorf("the submenu has focus %v and hovers %q", ctx.IsFocused(child), hovered(child))
+	}
+	if got, want := child.Bounds().X, root.rows[0].Right(); got < want {
+		t.Errorf("the submenu is at x %v, left of its item at %v", got, want)
+	}
+	child.HandleEvent(ctx, press(event.KeyLeft, 0))
+	if len(ctx.Overlays()) != 1 || !ctx.IsFocused(root) {
+		t.Errorf("Left left %d menus open", len(ctx.Overlays()))
+	}
+
+	// Hovering opens the submenu but keeps the keyboard in its parent, and
+	// hovering another item closes it.
+	move := func(row int) {
+		root.HandleEvent(ctx, event.MouseEvent{Type: event.MouseMove, Position: root.rows[row].Center()})
+		layoutOverlays(ctx)
+	}
+	move(1)
+	move(0)
+	if len(ctx.Overlays()) != 2 || !ctx.IsFocused(root) {
+		t.Errorf("hover: %d menus open, parent focused %v", len(ctx.Overlays()), ctx.IsFocused(root))
+	}
+	move(1)
+	if len(ctx.Overlays()) != 1 {
+		t.Errorf("hovering another item left %d menus open", len(ctx.Overlays()))
+	}
+
+	// Closing the root closes its submenus.
+	move(0)
+	ctx.CloseOverlay(root.overlay)
+	if len(ctx.Overlays()) != 0 {
+		t.Errorf("%d menus open after closing the root", len(ctx.Overlays()))
+	}
+}
+
+func TestMenuSubmenuRightToLeft(t *testing.T) {
+	ctx := core.NewContext()
+	ctx.SetDirection(core.RightToLeft)
+	items := []*MenuItem{NewSubmenu("&Recent", NewMenuItem("a", nil)), NewMenuItem("&Quit", nil)}
+	openMenu(ctx, items, core.PlaceAt(core.Pt(400, 10)), nil, true)
+	root := layoutOverlays(ctx)[0]
+
+	root.HandleEvent(ctx, press(event.KeyRight, 0))
+	if len(ctx.Overlays()) != 1 {
+		t.Fatalf("%d menus open after Right, want 1", len(ctx.Overlays()))
+	}
+	root.HandleEvent(ctx, press(event.KeyLeft, 0))
+	popups := layoutOverlays(ctx)
+	if len(popups) != 2 {
+		t.Fatalf("%d menus open after Left, want 2", len(popups))
+	}
+	child := popups[1]
+	if got, want := child.Bounds().Right(), root.rows[0].X; got > want {
+		t.Errorf("the submenu ends at x %v, right of its item at %v", got, want)
+	}
+	child.HandleEvent(ctx, press(event.KeyRight, 0))
+	if len(ctx.Overlays()) != 1 || !ctx.IsFocused(root) {
+		t.Errorf("Right left %d menus open", len(ctx.Overlays()))
+	}
+}
+
+// newMenuBar returns a bar of the menus File, Edit (disabled) and View,
+// laid out at the top of the window.
+func newMenuBar() (*MenuBar, *core.Context) {
+	b := NewMenuBar(
+		NewSubmenu("&File", NewMenuItem("&Open", nil)),
+		NewSubmenu("&Edit", NewMenuItem("&Undo", nil)).Enabled(false),
+		NewSubmenu("&View", NewMenuItem("&Zoom", nil)),
+	)
+	ctx := layoutAt(b, core.R(0, 0, 400, menuBarHeight))
+	return b, ctx
+}
+
+func TestMenuBarMouse(t *testing.T) {
+	b, ctx := newMenuBar()
+	mouse := func(typ event.MouseEventType, title int) {
+		b.HandleEvent(ctx, event.MouseEvent{Type: typ, Position: b.titles[title].Center(), Button: event.ButtonLeft})
+	}
+	mouse(event.MouseDown, 0)
+	if b.open != 0 || len(ctx.Overlays()) != 1 {
+		t.Fatalf("open = %d with %d overlays after a click on File", b.open, len(ctx.Overlays()))
+	}
+	if p := layoutOverlays(ctx)[0]; p.Bounds().Y != menuBarHeight || hovered(p) != "" {
+		t.Errorf("the menu is at %v hovering %q", p.Bounds(), hovered(p))
+	}
+	mouse(event.MouseMove, 1) // Disabled: the open menu closes.
+	if b.IsOpen() {
+		t.Errorf("open = %d over a disabled title", b.open)
+	}
+	mouse(event.MouseDown, 0)
+	mouse(event.MouseMove, 2)
+	if b.open != 2 || len(ctx.Overlays()) != 1 {
+		t.Errorf("open = %d with %d overlays after hovering View", b.open, len(ctx.Overlays()))
+	}
+	mouse(event.MouseDown, 2)
+	if b.IsOpen() || len(ctx.Overlays()) != 0 {
+		t.Errorf("a second click left menu %d open", b.open)
+	}
+	mouse(event.MouseMove, 0)
+	if b.IsOpen() {
+		t.Error("hovering opened a menu while none was open")
+	}
+}
+
+func TestMenuBarKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		shortcut event.KeyEvent
+		handled  bool
+		keys     []event.Key
+		open     int
+	}{
+		{"F10", press(event.KeyF10, 0), true, nil, 0},
+		{"Alt and a mnemonic", press(event.KeyV, event.ModAlt), true, nil, 2},
+		{"disabled mnemonic", press(event.KeyE, event.ModAlt), false, nil, -1},
+		{"no Alt", press(event.KeyV, 0), false, nil, -1},
+		{"right skips disabled", press(event.KeyF10, 0), true, []event.Key{event.KeyRight}, 2},
+		{"left wraps", press(event.KeyF10, 0), true, []event.Key{event.KeyLeft}, 2},
+		{"escape", press(event.KeyF10, 0), true, []event.Key{event.KeyEscape}, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, ctx := newMenuBar()
+			if got := b.HandleShortcut(ctx, tt.shortcut); (got == core.Handled) != tt.handled {
+				t.Errorf("HandleShortcut() = %v", got)
+			}
+			for _, k := range tt.keys {
+				popups := layoutOverlays(ctx)
+				popups[len(popups)-1].HandleEvent(ctx, press(k, 0))
+			}
+			if b.open != tt.open {
+				t.Errorf("open = %d, want %d", b.open, tt.open)
+			}
+			if popups := layoutOverlays(ctx); b.open >= 0 && (len(popups) != 1 || !ctx.IsFocused(popups[0]) || hovered(popups[0]) == "") {
+				t.Errorf("%d menus open; want one, focused, with an item hovered", len(popups))
+			}
+		})
+	}
+}
+
+func TestMenuBarRightToLeft(t *testing.T) {
+	b := NewMenuBar(
+		NewSubmenu("&File", NewMenuItem("&Open", nil)),
+		NewSubmenu("&View", NewMenuItem("&Zoom", nil)),
+	)
+	ctx := layoutRTL(b, core.R(0, 0, 400, menuBarHeight))
+	if b.titles[0].Right() != 400 || b.titles[1].Right() != b.titles[0].X {
+		t.Errorf("titles at %v, want from the right edge", b.titles)
+	}
+	b.HandleShortcut(ctx, press(event.KeyF10, 0))
+	popup := layoutOverlays(ctx)[0]
+	if got := popup.Bounds().Right(); got != b.titles[0].Right() {
+		t.Errorf("the menu ends at x %v, want under its title ending at %v", got, b.titles[0].Right())
+	}
+	popup.HandleEvent(ctx, press(event.KeyLeft, 0))
+	if b.open != 1 {
+		t.Errorf("open = %d after Left, want the next menu", b.open)
+	}
+}
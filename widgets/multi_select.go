@@ -0,0 +1,457 @@
+package widgets
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/internal/textedit"
+	"github.com/gogpu/ui/state"
+	"github.com/gogpu/ui/theme"
+)
+
+// MultiSelect chip metrics.
+const (
+	chipHeight  float32 = 24
+	chipPadding float32 = 8
+	chipRemove  float32 = 16 // width of the remove button
+	chipGap     float32 = 4
+	chipInput   float32 = 60 // narrowest search field beside the chips
+)
+
+// multiSelectAll is the label of the first row of the list, which selects
+// or clears every listed option.
+const multiSelectAll = "Select all"
+
+// MultiSelect picks any number of options from a list. The chosen options
+// are shown as chips inside the field, each with a button that removes it;
+// the field grows to wrap the chips across lines.
+//
+// Typing filters the drop-down list, which starts with a row selecting or
+// clearing every option listed. Up and Down move through the list, Enter
+// toggles the highlighted option and Backspace in an empty search removes
+// the last chip.
+type MultiSelect struct {
+	core.WidgetBase
+	core.FocusState
+
+	line        textedit.Line
+	placeholder string
+	width       float32
+	options     []string
+	selected    map[string]bool
+	onChange    func([]string)
+
+	style  core.TextStyle
+	chips  []chip
+	input  core.Rect // search field, relative to the origin
+	button core.Rect
+
+	list     *optionList
+	filtered []string // options listed, without the select-all row
+	popup    *core.Overlay
+
+	sig    *state.Signal[[]string]
+	cancel func()
+	last   []string
+}
+
+// chip is a selected option drawn in the field.
+type chip struct {
+	value string
+	rel   core.Rect // relative to the field's origin
+	rect  core.Rect
+}
+
+// NewMultiSelect returns a MultiSelect of options with nothing selected.
+func NewMultiSelect(options ...string) *MultiSelect {
+	m := &MultiSelect{options: options, width: textFieldWidth, selected: map[string]bool{}}
+	m.list = newOptionList(func(ctx *core.Context, i int) { m.toggle(ctx, i) })
+	m.list.checked = m.isChecked
+	return m
+}
+
+// Options replaces the options, dropping selected values that are no
+// longer among them.
+func (m *MultiSelect) Options(options ...string) *MultiSelect {
+	m.options = options
+	m.SetSelected(m.Selected())
+	return m
+}
+
+// Placeholder sets the hint shown while nothing is selected or typed.
+func (m *MultiSelect) Placeholder(s string) *MultiSelect {
+	m.placeholder = s
+	return m
+}
+
+// Width sets the field's width.
+func (m *MultiSelect) Width(w float32) *MultiSelect {
+	m.width = w
+	return m
+}
+
+// Bind keeps the selection in sync with sig in both directions.
+func (m *MultiSelect) Bind(sig *state.Signal[[]string]) *MultiSelect {
+	m.Release()
+	m.sig = sig
+	return m
+}
+
+// Release stops following the bound signal, for when the field leaves
+// the tree. Laid out again, the field follows the signal from its value
+// then.
+func (m *MultiSelect) Release() {
+	if m.cancel != nil {
+		m.cancel()
+		m.cancel = nil
+	}
+}
+
+// OnChange registers fn to be called when the user changes the selection.
+func (m *MultiSelect) OnChange(fn func([]string)) *MultiSelect {
+	m.onChange = fn
+	return m
+}
+
+// Selected returns the selected options in the order of the options.
+func (m *MultiSelect) Selected() []string {
+	var out []string
+	for _, o := range m.options {
+		if m.selected[o] {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+// SetSelected selects values, ignoring any that are not options, without
+// calling OnChange.
+func (m *MultiSelect) SetSelected(values []string) {
+	clear(m.selected)
+	for _, v := range values {
+		if slices.Contains(m.options, v) {
+			m.selected[v] = true
+		}
+	}
+	m.last = m.Selected()
+	if m.sig != nil {
+		m.sig.Set(m.last)
+	}
+}
+
+func (m *MultiSelect) changed(ctx *core.Context) {
+	m.SetSelected(m.Selected())
+	ctx.MarkNeedsLayout(m)
+	if m.onChange != nil {
+		m.onChange(m.last)
+	}
+}
+
+// hasAll reports whether the list starts with the select-all row.
+func (m *MultiSelect) hasAll() bool {
+	return len(m.filtered) > 1
+}
+
+func (m *MultiSelect) allChecked() bool {
+	for _, o := range m.filtered {
+		if !m.selected[o] {
+			return false
+		}
+	}
+	return true
+}
+
+// isChecked reports whether row i of the list shows a checkmark.
+func (m *MultiSelect) isChecked(i int) bool {
+	if m.hasAll() {
+		if i == 0 {
+			return m.allChecked()
+		}
+		i--
+	}
+	return m.selected[m.filtered[i]]
+}
+
+// toggle flips row i of the list.
+func (m *MultiSelect) toggle(ctx *core.Context, i int) {
+	if m.hasAll() {
+		if i == 0 {
+			all := m.allChecked()
+			for _, o := range m.filtered {
+				m.selected[o] = !all
+			}
+			m.changed(ctx)
+			return
+		}
+		i--
+	}
+	if i < 0 || i >= len(m.filtered) {
+		return
+	}
+	o := m.filtered[i]
+	m.selected[o] = !m.selected[o]
+	m.changed(ctx)
+}
+
+// remove deselects v.
+func (m *MultiSelect) remove(ctx *core.Context, v string) {
+	delete(m.selected, v)
+	m.changed(ctx)
+}
+
+// refilter lists the options matching the search text, prefix matches
+// first.
+func (m *MultiSelect) refilter() {
+	q := strings.ToLower(strings.TrimSpace(m.line.Text()))
+	var prefix, contains []string
+	for _, o := range m.options {
+		lo := strings.ToLower(o)
+		switch {
+		case strings.HasPrefix(lo, q):
+			prefix = append(prefix, o)
+		case strings.Contains(lo, q):
+			contains = append(contains, o)
+		}
+	}
+	m.filtered = append(prefix, contains...)
+	items := m.filtered
+	if m.hasAll() {
+		items = append([]string{multiSelectAll}, m.filtered...)
+	}
+	m.list.setItems(items)
+	if m.hasAll() && q != "" {
+		m.list.highlight = 1 // The first match, not select-all.
+	}
+	m.list.message = "No matches"
+}
+
+func (m *MultiSelect) isOpen() bool {
+	return m.popup != nil && m.popup.IsOpen()
+}
+
+func (m *MultiSelect) open(ctx *core.Context) {
+	m.refilter()
+	ctx.MarkNeedsLayout(m.list)
+	if m.isOpen() {
+		return
+	}
+	m.popup = &core.Overlay{
+		Content:      newPopupFrame(m.list),
+		Placement:    core.PlaceAnchored(m.Bounds(), core.SideBelow),
+		LightDismiss: true,
+		Popup:        true,
+		Owner:        m,
+	}
+	ctx.ShowOverlay(m.popup)
+}
+
+func (m *MultiSelect) close(ctx *core.Context) {
+	if m.isOpen() {
+		ctx.CloseOverlay(m.popup)
+	}
+}
+
+// Layout implements core.Widget.
+func (m *MultiSelect) Layout(ctx *core.LayoutContext) core.Size {
+	if m.sig != nil {
+		if m.cancel == nil {
+			c := ctx.Context
+			m.cancel = m.sig.Subscribe(func([]string) { c.Post(func() { c.MarkNeedsLayout(m) }) })
+		}
+		if v := m.sig.Get(); !slices.Equal(v, m.last) {
+			m.SetSelected(v)
+		}
+	}
+	if !m.IsFocused() && m.isOpen() {
+		c := ctx.Context
+		ctx.Post(func() { m.close(c) })
+	}
+	th := theme.From(ctx.Context)
+	m.line.Style = th.Typography.Body
+	m.style = th.Typography.Label
+
+	// Flow the chips into lines, leaving room for the search field after
+	// the last one.
+	inner := max(0, m.width-2*fieldPadding-fieldButton)
+	top := (fieldHeight - chipHeight) / 2
+	x, y := float32(0), top
+	m.chips = m.chips[:0]
+	for _, v := range m.Selected() {
+		w := min(inner, ctx.MeasureText(v, m.style).Width+chipPadding+chipRemove)
+		if x > 0 && x+w > inner {
+			x, y = 0, y+chipHeight+chipGap
+		}
+		m.chips = append(m.chips, chip{value: v, rel: core.R(fieldPadding+x, y, w, chipHeight)})
+		x += w + chipGap
+	}
+	if x > 0 && x+chipInput > inner {
+		x, y = 0, y+chipHeight+chipGap
+	}
+	m.input = core.R(fieldPadding+x, y-top, max(0, inner-x), fieldHeight)
+	m.line.Placeholder = ""
+	if len(m.chips) == 0 {
+		m.line.Placeholder = m.placeholder
+	}
+	return ctx.Constraints.Constrain(core.Sz(m.width, y-top+fieldHeight))
+}
+
+// SetBounds implements core.Widget.
+func (m *MultiSelect) SetBounds(r core.Rect) {
+	m.WidgetBase.SetBounds(r)
+	for i := range m.chips {
+		m.chips[i].rect = m.chips[i].rel.Translate(r.Origin())
+	}
+	m.line.Rect = m.input.Translate(r.Origin())
+	m.button = core.R(r.Right()-fieldButton, r.Y, fieldButton, fieldHeight)
+	m.list.minWidth = r.Width - 2*popupPadding
+	if m.isOpen() {
+		// Follow the field as chips make it grow.
+		m.popup.Placement = core.PlaceAnchored(r, core.SideBelow)
+	}
+}
+
+// Paint implements core.Widget.
+func (m *MultiSelect) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	paintField(ctx, m.Bounds(), m.IsFocused() || m.isOpen(), false)
+	for _, c := range m.chips {
+		cv.DrawRoundedRect(c.rect, chipHeight/2, core.Filled(th.Colors.SurfaceVariant))
+		cv.Save()
+		cv.Clip(core.R(c.rect.X, c.rect.Y, max(0, c.rect.Width-chipRemove), c.rect.Height))
+		ty := (chipHeight - m.style.LineHeight()) / 2
+		cv.DrawText(c.value, core.Pt(c.rect.X+chipPadding, c.rect.Y+ty), theme.TextStyle(m.style, th.Colors.OnSurfaceVariant))
+		cv.Restore()
+		paintCross(ctx, chipRemoveRect(c.rect), th.Colors.OnSurfaceVariant)
+	}
+	m.line.Paint(ctx, m.IsFocused())
+	paintChevronDown(ctx, m.button, th.Colors.OnSurfaceVariant)
+}
+
+// chipRemoveRect returns the remove button of a chip.
+func chipRemoveRect(r core.Rect) core.Rect {
+	return core.R(r.Right()-chipRemove-2, r.Y, chipRemove, r.Height)
+}
+
+// paintCross draws a small ×, as on the remove button of a chip.
+func paintCross(ctx *core.PaintContext, r core.Rect, color core.Color) {
+	c := r.Center()
+	const s = 3.5
+	p := core.NewPath().MoveTo(core.Pt(c.X-s, c.Y-s)).LineTo(core.Pt(c.X+s, c.Y+s)).
+		MoveTo(core.Pt(c.X+s, c.Y-s)).LineTo(core.Pt(c.X-s, c.Y+s))
+	ctx.Canvas.DrawPath(p, core.PathStyle{Stroke: color, StrokeWidth: 1.5, LineCap: core.CapRound})
+}
+
+// HandleEvent implements core.Widget.
+func (m *MultiSelect) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	switch e := ev.(type) {
+	case event.MouseEvent:
+		if e.Type == event.MouseDown && e.Button == event.ButtonLeft {
+			ctx.RequestFocus(m)
+			for _, c := range m.chips {
+				if chipRemoveRect(c.rect).Contains(e.Position) {
+					m.remove(ctx, c.value)
+					return core.Handled
+				}
+			}
+			if m.button.Contains(e.Position) || !m.line.Rect.Contains(e.Position) {
+				if m.isOpen() {
+					m.close(ctx)
+				} else {
+					m.open(ctx)
+				}
+				return core.Handled
+			}
+			m.open(ctx)
+		}
+		if m.line.HandleEvent(ctx, m, ev) != textedit.Ignored {
+			return core.Handled
+		}
+	case event.KeyEvent:
+		if !m.IsFocused() || e.Type != event.KeyPress {
+			return core.Ignored
+		}
+		open := m.isOpen()
+		switch {
+		case e.Key == event.KeyDown && e.Modifiers == event.ModAlt, e.Key == event.KeyF4 && e.Modifiers == 0:
+			if open {
+				m.close(ctx)
+			} else {
+				m.open(ctx)
+			}
+			return core.Handled
+		case (e.Key == event.KeyDown || e.Key == event.KeyUp) && e.Modifiers == 0:
+			if !open {
+				m.open(ctx)
+				return core.Handled
+			}
+			if e.Key == event.KeyDown {
+				m.list.move(1)
+			} else {
+				m.list.move(-1)
+			}
+			ctx.Repaint(m.list)
+			return core.Handled
+		case (e.Key == event.KeyPageDown || e.Key == event.KeyPageUp) && open:
+			if e.Key == event.KeyPageDown {
+				m.list.move(optionRows)
+			} else {
+				m.list.move(-optionRows)
+			}
+			ctx.Repaint(m.list)
+			return core.Handled
+		case e.Key == event.KeyEnter:
+			if !open {
+				m.open(ctx)
+			} else if m.list.highlight >= 0 {
+				m.toggle(ctx, m.list.highlight)
+			}
+			return core.Handled
+		case e.Key == event.KeyEscape:
+			if open {
+				m.close(ctx)
+				return core.Handled
+			}
+			if m.line.Len() > 0 {
+				m.line.SetText("")
+				ctx.MarkNeedsLayout(m)
+				return core.Handled
+			}
+			return core.Ignored
+		case e.Key == event.KeyBackspace && m.line.Len() == 0:
+			if s := m.Selected(); len(s) > 0 {
+				m.remove(ctx, s[len(s)-1])
+				if open {
+					m.refilter()
+				}
+			}
+			return core.Handled
+		case e.Key == event.KeyTab:
+			m.close(ctx)
+			return core.Ignored
+		}
+		if r := m.line.HandleEvent(ctx, m, ev); r != textedit.Ignored {
+			if r == textedit.Changed {
+				m.open(ctx)
+			}
+			return core.Handled
+		}
+	case event.TextEvent:
+		if !m.IsFocused() {
+			return core.Ignored
+		}
+		if m.line.HandleEvent(ctx, m, ev) == textedit.Changed {
+			m.open(ctx)
+		}
+		return core.Handled
+	}
+	return core.Ignored
+}
+
+// Blur clears the search text when focus leaves the field.
+func (m *MultiSelect) Blur() {
+	m.FocusState.Blur()
+	m.line.SetText("")
+}
@@ -0,0 +1,151 @@
+package widgets
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/state"
+)
+
+// newMultiSelect returns a focused MultiSelect of fruits laid out at its
+// own height, and the selections it reported.
+func newMultiSelect() (*MultiSelect, *core.Context, *[][]string) {
+	m := NewMultiSelect(fruits...)
+	var changes [][]string
+	m.OnChange(func(s []string) { changes = append(changes, s) })
+	ctx := core.NewContext()
+	relayout(ctx, m)
+	ctx.RequestFocus(m)
+	return m, ctx, &changes
+}
+
+// relayout lays m out at the height it asks for.
+func relayout(ctx *core.Context, m *MultiSelect) {
+	lc := &core.LayoutContext{Context: ctx}
+	size := lc.Measure(m, core.Loose(core.Sz(m.width, 1000)))
+	ctx.Invalidate()
+	ctx.LayoutRoot(m, core.R(0, 0, size.Width, size.Height))
+}
+
+func TestMultiSelectList(t *testing.T) {
+	m, ctx, changes := newMultiSelect()
+	m.HandleEvent(ctx, press(event.KeyDown, 0))
+	if !m.isOpen() || len(m.list.items) != len(fruits)+1 || m.list.items[0] != multiSelectAll {
+		t.Fatalf("open %v with %v", m.isOpen(), m.list.items)
+	}
+	m.HandleEvent(ctx, press(event.KeyDown, 0))
+	m.HandleEvent(ctx, press(event.KeyEnter, 0)) // Apple
+	m.HandleEvent(ctx, press(event.KeyDown, 0))
+	m.HandleEvent(ctx, press(event.KeyDown, 0))
+	m.HandleEvent(ctx, press(event.KeyEnter, 0)) // Cherry
+	if got := m.Selected(); !slices.Equal(got, []string{"Apple", "Cherry"}) {
+		t.Errorf("Selected() = %v", got)
+	}
+	if !m.isChecked(1) || m.isChecked(2) || m.isChecked(0) {
+		t.Error("the checkmarks do not follow the selection")
+	}
+	m.toggle(ctx, 0) // Select all.
+	if len(m.Selected()) != len(fruits) || !m.isChecked(0) {
+		t.Errorf("select all gave %v", m.Selected())
+	}
+	m.toggle(ctx, 0) // Clear all.
+	if len(m.Selected()) != 0 || len(*changes) != 4 {
+		t.Errorf("clear all gave %v after %d changes", m.Selected(), len(*changes))
+	}
+	if !m.isOpen() {
+		t.Error("picking closed the list")
+	}
+}
+
+func TestMultiSelectSearch(t *testing.T) {
+	tests := []struct {
+		query     string
+		items     []string
+		highlight int
+	}{
+		{"ap", []string{multiSelectAll, "Apple", "Pineapple", "Grape"}, 1},
+		{"ban", []string{"Banana"}, 0},
+		{"kiwi", nil, -1},
+	}
+	for _, tt := range tests {
+		m, ctx, _ := newMultiSelect()
+		m.HandleEvent(ctx, event.TextEvent{Text: tt.query})
+		if !slices.Equal(m.list.items, tt.items) || m.list.highlight != tt.highlight {
+			t.Errorf("searching %q lists %v with %d highlighted", tt.query, m.list.items, m.list.highlight)
+		}
+	}
+
+	// Select all applies to the listed options only.
+	m, ctx, _ := newMultiSelect()
+	m.HandleEvent(ctx, event.TextEvent{Text: "ap"})
+	m.toggle(ctx, 0)
+	if got := m.Selected(); !slices.Equal(got, []string{"Apple", "Pineapple", "Grape"}) {
+		t.Errorf("Selected() = %v", got)
+	}
+	m.HandleEvent(ctx, press(event.KeyEscape, 0))
+	m.HandleEvent(ctx, press(event.KeyEscape, 0))
+	if m.isOpen() || m.line.Text() != "" {
+		t.Errorf("Escape left the list open %v with %q", m.isOpen(), m.line.Text())
+	}
+	m.HandleEvent(ctx, event.TextEvent{Text: "x"})
+	m.Blur()
+	if m.line.Text() != "" {
+		t.Error("the search text stayed after focus left")
+	}
+}
+
+func TestMultiSelectChips(t *testing.T) {
+	m, ctx, changes := newMultiSelect()
+	oneLine := m.Bounds().Height
+	m.SetSelected([]string{"Pineapple", "Apple", "Cherry", "Banana"})
+	relayout(ctx, m)
+	if len(m.chips) != 4 || m.chips[0].value != "Apple" {
+		t.Fatalf("chips %v, want in the order of the options", m.chips)
+	}
+	if m.Bounds().Height <= oneLine || m.chips[3].rect.Y <= m.chips[0].rect.Y {
+		t.Errorf("the chips did not wrap: height %v", m.Bounds().Height)
+	}
+	last := m.chips[len(m.chips)-1].rect
+	if in := m.line.Rect; in.Y+in.Height/2 < last.Y || in.X < last.Right() && in.Y < last.Bottom() {
+		t.Errorf("search field at %v overlaps the last chip at %v", in, last)
+	}
+
+	m.HandleEvent(ctx, leftMouse(event.MouseDown, chipRemoveRect(m.chips[1].rect).Center()))
+	if got := m.Selected(); !slices.Equal(got, []string{"Apple", "Cherry", "Pineapple"}) {
+		t.Errorf("removing Banana left %v", got)
+	}
+	m.HandleEvent(ctx, press(event.KeyBackspace, 0))
+	if got := m.Selected(); !slices.Equal(got, []string{"Apple", "Cherry"}) {
+		t.Errorf("Backspace left %v", got)
+	}
+	if len(*changes) != 2 || m.isOpen() {
+		t.Errorf("%d changes, open %v", len(*changes), m.isOpen())
+	}
+	m.Options("Cherry", "Kiwi")
+	if got := m.Selected(); !slices.Equal(got, []string{"Cherry"}) {
+		t.Errorf("Options() kept %v", got)
+	}
+}
+
+func TestMultiSelectBind(t *testing.T) {
+	sig := state.NewFunc([]string{"Grape"}, slices.Equal[[]string])
+	m := NewMultiSelect(fruits...).Bind(sig)
+	ctx := core.NewContext()
+	relayout(ctx, m)
+	if got := m.Selected(); !slices.Equal(got, []string{"Grape"}) {
+		t.Fatalf("Selected() = %v, want the signal's", got)
+	}
+	ctx.RequestFocus(m)
+	m.HandleEvent(ctx, press(event.KeyBackspace, 0))
+	if len(sig.Get()) != 0 {
+		t.Errorf("signal holds %v after removing the chip", sig.Get())
+	}
+	sig.Set([]string{"Banana", "Lemon"})
+	ctx.RunPosted()
+	ctx.LayoutRoot(m, m.Bounds())
+	if got := m.Selected(); !slices.Equal(got, []string{"Banana"}) || len(m.chips) != 1 {
+		t.Errorf("Selected() = %v after the signal changed", got)
+	}
+}
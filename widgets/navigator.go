@@ -0,0 +1,474 @@
+package widgets
+
+import (
+	"time"
+
+	"github.com/gogpu/ui/anim"
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+)
+
+const (
+	navTransition          = 300 * time.Millisecond
+	navEdge        float32 = 24  // Width of the edge a back swipe starts in.
+	navFlick       float32 = 600 // Release speed committing a back swipe, in px/s.
+	navSharedShift float32 = 30  // Distance shared-axis transitions move pages.
+)
+
+// PageTransition is where a page is in a transition of a Navigator.
+type PageTransition struct {
+	Bounds   core.Rect
+	Shown    float32 // How far the page is shown, from 0 to 1.
+	Entering bool    // The page is becoming the one shown.
+	Back     bool    // The transition goes back to an earlier page.
+	RTL      bool    // Moves toward the leading edge go right.
+}
+
+// Transition moves and fades a page of a Navigator in or out. It
+// transforms cv, which the Navigator has saved, and returns the canvas
+// to paint the page into.
+type Transition func(cv core.Canvas, t PageTransition) core.Canvas
+
+// Standard transitions. The entering page is painted over the leaving
+// one, and over it going back.
+var (
+	// NoTransition swaps the pages at once.
+	NoTransition Transition = nil
+
+	// SlideTransition slides the new page in from the trailing edge over
+	// the old one, which moves a third of the way out; going back the old
+	// page slides off toward the trailing edge.
+	SlideTransition Transition = func(cv core.Canvas, t PageTransition) core.Canvas {
+		dx := (1 - t.Shown) * t.Bounds.Width
+		if t.Entering == t.Back {
+			dx = -dx / 3
+		}
+		if t.RTL {
+			dx = -dx
+		}
+		cv.Translate(dx, 0)
+		return cv
+	}
+
+	// FadeTransition cross-fades the pages.
+	FadeTransition Transition = func(cv core.Canvas, t PageTransition) core.Canvas {
+		return core.Fade(cv, t.Shown)
+	}
+
+	// SharedAxisX moves the pages a short way along the horizontal axis
+	// as the old one fades out and then the new one in, for pages with a
+	// spatial relationship, such as steps of a flow.
+	SharedAxisX Transition = func(cv core.Canvas, t PageTransition) core.Canvas {
+		d := shared(t)
+		if t.RTL {
+			d = -d
+		}
+		cv.Translate(d, 0)
+		return core.Fade(cv, sharedFade(t))
+	}
+
+	// SharedAxisY is the vertical SharedAxisX, for pages stacked one
+	// over the other, such as the results of a search.
+	SharedAxisY Transition = func(cv core.Canvas, t PageTransition) core.Canvas {
+		cv.Translate(0, shared(t))
+		return core.Fade(cv, sharedFade(t))
+	}
+
+	// SharedAxisZ scales the pages about their center as they fade, for
+	// pages a level deeper into the content, such as details of an item.
+	SharedAxisZ Transition = func(cv core.Canvas, t PageTransition) core.Canvas {
+		hidden := 1 - t.Shown
+		s := 1 + hidden/10
+		if t.Entering != t.Back {
+			s = 1 - hidden/5
+		}
+		c := t.Bounds.Center()
+		cv.Translate(c.X, c.Y)
+		z := core.Zoom(cv, s)
+		z.Translate(-c.X, -c.Y)
+		return core.Fade(z, sharedFade(t))
+	}
+)
+
+// shared returns how far a shared-axis transition moves a page, toward
+// the leading edge for pages leaving forward and entering back.
+func shared(t PageTransition) float32 {
+	d := (1 - t.Shown) * navSharedShift
+	if t.Entering == t.Back {
+		return -d
+	}
+	return d
+}
+
+// sharedFade returns the opacity of a page of a shared-axis transition:
+// the leaving page fades out over the first 35% and the entering one in
+// over the rest.
+func sharedFade(t PageTransition) float32 {
+	if t.Entering {
+		return core.Clamp((t.Shown-0.35)/0.65, 0, 1)
+	}
+	return core.Clamp((t.Shown-0.65)/0.35, 0, 1)
+}
+
+// Navigator shows the top of a stack of pages, and animates between them
+// when pages are pushed and popped:
+//
+//	nav := widgets.NewNavigator(home).Transition(widgets.SharedAxisZ)
+//	nav.Push(details) // from a callback of home
+//	nav.Pop()
+//
+// A change during a transition starts the next one from the page that
+// was entering, and one undoing it, such as a pop right after a push,
+// turns the transition around where it is, as does one redoing it after
+// that. A back gesture drives the transition to the page below, with
+// BeginBack, UpdateBack and EndBack, for predictive back of platforms
+// that have it; one starting at the leading edge of the navigator does
+// the same with the pointer. The back mouse button pops.
+type Navigator struct {
+	core.WidgetBase
+
+	stack    []core.Widget
+	trans    Transition
+	duration time.Duration
+	swipe    bool
+	onChange func(top core.Widget)
+
+	ctx      *core.Context
+	ctl      *anim.Controller
+	from, to core.Widget // Of the running transition.
+	back     bool
+	target   float32 // The progress the controller goes to.
+	gesture  bool    // A back gesture drives the progress.
+
+	swiping  bool
+	pressPos core.Point
+	tracker  anim.VelocityTracker
+}
+
+// NewNavigator returns a navigator showing root, with SlideTransition
+// and back swipes.
+func NewNavigator(root core.Widget) *Navigator {
+	n := &Navigator{stack: []core.Widget{root}, trans: SlideTransition, duration: navTransition, swipe: true}
+	n.SetChildren(root)
+	return n
+}
+
+// Transition sets how pages enter and leave. NoTransition swaps them at
+// once.
+func (n *Navigator) Transition(t Transition) *Navigator {
+	n.trans = t
+	return n
+}
+
+// Duration sets how long transitions take, 300 ms by default.
+func (n *Navigator) Duration(d time.Duration) *Navigator {
+	n.duration = d
+	if n.ctl != nil {
+		n.ctl.Duration(d)
+	}
+	return n
+}
+
+// BackSwipe sets whether dragging from the leading edge goes back.
+func (n *Navigator) BackSwipe(on bool) *Navigator {
+	n.swipe = on
+	return n
+}
+
+// OnChange sets fn to be called with the new top page whenever the stack
+// changes.
+func (n *Navigator) OnChange(fn func(top core.Widget)) *Navigator {
+	n.onChange = fn
+	return n
+}
+
+// Top returns the page shown once transitions end.
+func (n *Navigator) Top() core.Widget {
+	if len(n.stack) == 0 {
+		return nil
+	}
+	return n.stack[len(n.stack)-1]
+}
+
+// Depth returns the number of pages in the stack.
+func (n *Navigator) Depth() int {
+	return len(n.stack)
+}
+
+// IsTransitioning reports whether a transition is running or a back
+// gesture is driving one.
+func (n *Navigator) IsTransitioning() bool {
+	return n.to != nil
+}
+
+// Push shows page over the current one.
+func (n *Navigator) Push(page core.Widget) {
+	from := n.Top()
+	n.stack = append(n.stack, page)
+	n.start(from, page, false)
+}
+
+// Pop goes back to the page below the top, and reports whether there was
+// one.
+func (n *Navigator) Pop() bool {
+	if len(n.stack) < 2 || n.gesture {
+		return false
+	}
+	from := n.Top()
+	n.stack = n.stack[:len(n.stack)-1]
+	n.start(from, n.Top(), true)
+	return true
+}
+
+// Replace shows page in place of the top one.
+func (n *Navigator) Replace(page core.Widget) {
+	if len(n.stack) == 0 {
+		n.Push(page)
+		return
+	}
+	from := n.Top()
+	n.stack[len(n.stack)-1] = page
+	n.start(from, page, false)
+}
+
+// Reset replaces the stack with pages, without a transition.
+func (n *Navigator) Reset(pages ...core.Widget) {
+	n.stack = append([]core.Widget(nil), pages...)
+	n.settle()
+	n.changed()
+}
+
+// start runs the transition from from to to.
+func (n *Navigator) start(from, to core.Widget, back bool) {
+	n.gesture = false
+	switch {
+	case n.ctl == nil || n.trans == nil || from == nil || from == to:
+		n.settle()
+	case n.from == to && n.to == from:
+		// Undone while running: turn around where it is.
+		n.animate(0)
+	case n.from == from && n.to == to:
+		// Redone while turning around: turn back toward the page.
+		n.animate(1)
+	default:
+		n.from, n.to, n.back = from, to, back
+		n.ctl.SetProgress(0)
+		n.animate(1)
+	}
+	n.changed()
+}
+
+// animate runs the transition to progress target, 1 showing the page
+// entering and 0 the page leaving.
+func (n *Navigator) animate(target float32) {
+	n.target = target
+	n.ctl.AnimateTo(target)
+}
+
+// settle ends any transition.
+func (n *Navigator) settle() {
+	n.from, n.to, n.gesture = nil, nil, false
+	if n.ctl != nil {
+		n.ctl.Stop()
+	}
+}
+
+func (n *Navigator) changed() {
+	if top := n.Top(); top != nil {
+		n.SetChildren(top)
+	} else {
+		n.SetChildren()
+	}
+	if n.ctx != nil {
+		n.ctx.MarkNeedsLayout(n)
+	}
+	if n.onChange != nil {
+		n.onChange(n.Top())
+	}
+}
+
+// BeginBack starts a back gesture, showing the top page at the start of
+// the transition to the one below, and reports whether there is one.
+func (n *Navigator) BeginBack() bool {
+	if len(n.stack) < 2 || n.ctl == nil || n.trans == nil {
+		return false
+	}
+	n.from, n.to, n.back, n.gesture = n.Top(), n.stack[len(n.stack)-2], true, true
+	n.ctl.SetProgress(0)
+	if n.ctx != nil {
+		n.ctx.MarkNeedsLayout(n)
+	}
+	return true
+}
+
+// UpdateBack moves a back gesture to progress, from 0 to 1.
+func (n *Navigator) UpdateBack(progress float32) {
+	if n.gesture {
+		n.ctl.SetProgress(progress)
+	}
+}
+
+// EndBack ends a back gesture, popping the top page if commit is set and
+// animating back to it otherwise.
+func (n *Navigator) EndBack(commit bool) {
+	if !n.gesture {
+		return
+	}
+	n.gesture = false
+	if !commit {
+		n.animate(0)
+		return
+	}
+	n.stack = n.stack[:len(n.stack)-1]
+	n.animate(1)
+	n.changed()
+}
+
+// Layout implements core.Widget.
+func (n *Navigator) Layout(ctx *core.LayoutContext) core.Size {
+	if n.ctx == nil {
+		cx := ctx.Context
+		n.ctx = cx
+		n.ctl = anim.NewController(anim.ClockOf(cx), n.duration).Curve(anim.Standard)
+		n.ctl.Listen(func(float32) { cx.Repaint(n) })
+		n.ctl.OnEnd(func() {
+			n.from, n.to = nil, nil
+			cx.MarkNeedsLayout(n)
+		})
+	}
+	top := n.Top()
+	size := ctx.Constraints.Max()
+	if !ctx.Constraints.HasBoundedWidth() || !ctx.Constraints.HasBoundedHeight() {
+		var natural core.Size
+		if top != nil {
+			natural = ctx.Measure(top, ctx.Constraints.Loosen())
+		}
+		if !ctx.Constraints.HasBoundedWidth() {
+			size.Width = natural.Width
+		}
+		if !ctx.Constraints.HasBoundedHeight() {
+			size.Height = natural.Height
+		}
+	}
+	size = ctx.Constraints.Constrain(size)
+	for _, p := range n.pages() {
+		ctx.Measure(p, core.Tight(size))
+	}
+	return size
+}
+
+// pages returns the pages shown, from the bottom.
+func (n *Navigator) pages() []core.Widget {
+	switch {
+	case n.to == nil:
+		if top := n.Top(); top != nil {
+			return []core.Widget{top}
+		}
+		return nil
+	case n.back:
+		return []core.Widget{n.to, n.from}
+	}
+	return []core.Widget{n.from, n.to}
+}
+
+// SetBounds implements core.Widget.
+func (n *Navigator) SetBounds(r core.Rect) {
+	n.WidgetBase.SetBounds(r)
+	for _, p := range n.pages() {
+		p.SetBounds(r)
+	}
+}
+
+// Paint implements core.Widget.
+func (n *Navigator) Paint(ctx *core.PaintContext) {
+	pages := n.pages()
+	if n.to == nil {
+		for _, p := range pages {
+			p.Paint(ctx)
+		}
+		return
+	}
+	// Gestures follow the finger, without the curve.
+	v := n.ctl.Value()
+	if n.gesture {
+		v = n.ctl.Progress()
+	}
+	cv := ctx.Canvas
+	cv.Save()
+	cv.Clip(n.Bounds())
+	for _, p := range pages {
+		t := PageTransition{Bounds: n.Bounds(), Shown: 1 - v, Back: n.back, RTL: ctx.Direction().IsRTL()}
+		if p == n.to {
+			t.Shown, t.Entering = v, true
+		}
+		cv.Save()
+		pc := *ctx
+		pc.Canvas = n.trans(cv, t)
+		p.Paint(&pc)
+		cv.Restore()
+	}
+	cv.Restore()
+}
+
+// HandleEvent implements core.Widget.
+func (n *Navigator) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	e, ok := ev.(event.MouseEvent)
+	if !ok {
+		return core.Ignored
+	}
+	b := n.Bounds()
+	switch e.Type {
+	case event.MouseDown:
+		if e.Button == event.ButtonBack {
+			if n.Pop() {
+				return core.Handled
+			}
+			return core.Ignored
+		}
+		edge := e.Position.X - b.X
+		if ctx.Direction().IsRTL() {
+			edge = b.Right() - e.Position.X
+		}
+		if !n.swipe || e.Button != event.ButtonLeft || edge > navEdge || !n.BeginBack() {
+			return core.Ignored
+		}
+		n.swiping, n.pressPos = true, e.Position
+		n.tracker.Reset()
+		n.tracker.Add(time.Now(), e.Position)
+		ctx.CapturePointer(n)
+		return core.Handled
+	case event.MouseMove:
+		if !n.swiping {
+			return core.Ignored
+		}
+		n.tracker.Add(time.Now(), e.Position)
+		n.UpdateBack(n.swipeProgress(ctx, e.Position))
+		return core.Handled
+	case event.MouseUp:
+		if !n.swiping || e.Button != event.ButtonLeft {
+			return core.Ignored
+		}
+		n.swiping = false
+		ctx.ReleasePointer()
+		v := n.tracker.Velocity().X
+		if ctx.Direction().IsRTL() {
+			v = -v
+		}
+		n.EndBack(v > navFlick || (v > -navFlick && n.swipeProgress(ctx, e.Position) > 0.5))
+		return core.Handled
+	}
+	return core.Ignored
+}
+
+// swipeProgress returns how far a back swipe at p has gone.
+func (n *Navigator) swipeProgress(ctx *core.Context, p core.Point) float32 {
+	w := n.Bounds().Width
+	if w <= 0 {
+		return 0
+	}
+	d := p.X - n.pressPos.X
+	if ctx.Direction().IsRTL() {
+		d = -d
+	}
+	return core.Clamp(d/w, 0, 1)
+}
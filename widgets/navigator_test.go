@@ -0,0 +1,194 @@
+package widgets
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gogpu/ui/anim"
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+)
+
+// navigated returns a navigator of home laid out in ctx, with linear
+// transitions of 100 ms, and a func ticking its clock at ms.
+func navigated(home core.Widget) (*Navigator, *core.Context, func(ms int)) {
+	n := NewNavigator(home).Duration(100 * time.Millisecond)
+	ctx := core.NewContext()
+	ctx.LayoutRoot(n, core.R(0, 0, 300, 200))
+	n.ctl.Curve(anim.Linear)
+	clock := anim.ClockOf(ctx)
+	at := func(ms int) { clock.Tick(time.Unix(0, 0).Add(time.Duration(ms) * time.Millisecond)) }
+	at(0)
+	return n, ctx, at
+}
+
+func TestNavigatorStack(t *testing.T) {
+	home, a, b := &fixed{}, &fixed{}, &fixed{}
+	n := NewNavigator(home)
+	var tops []core.Widget
+	n.OnChange(func(top core.Widget) { tops = append(tops, top) })
+
+	tests := []struct {
+		name  string
+		op    func()
+		stack []core.Widget
+	}{
+		{"push", func() { n.Push(a) }, []core.Widget{home, a}},
+		{"push again", func() { n.Push(b) }, []core.Widget{home, a, b}},
+		{"pop", func() { n.Pop() }, []core.Widget{home, a}},
+		{"replace", func() { n.Replace(b) }, []core.Widget{home, b}},
+		{"reset", func() { n.Reset(a) }, []core.Widget{a}},
+		{"pop the last", func() {
+			if n.Pop() {
+				t.Error("the last page was popped")
+			}
+		}, []core.Widget{a}},
+		{"reset to nothing", func() { n.Reset() }, nil},
+		{"replace nothing", func() { n.Replace(home) }, []core.Widget{home}},
+	}
+	for _, tt := range tests {
+		tt.op()
+		if !slices.Equal(n.stack, tt.stack) || n.Depth() != len(tt.stack) {
+			t.Errorf("%s: stack %v, want %v", tt.name, n.stack, tt.stack)
+		}
+		if top := n.Top(); !slices.Equal(n.Children(), n.pages()) || (top != nil && n.Children()[0] != top) {
+			t.Errorf("%s: children %v, want the top page", tt.name, n.Children())
+		}
+		if n.IsTransitioning() {
+			t.Errorf("%s: transitioning before a layout", tt.name)
+		}
+	}
+	want := []core.Widget{a, b, a, b, a, nil, home}
+	if !slices.Equal(tops, want) {
+		t.Errorf("OnChange with %v, want %v", tops, want)
+	}
+}
+
+func TestNavigatorTransition(t *testing.T) {
+	home, page := &fixed{}, &fixed{}
+	n, ctx, at := navigated(home)
+	n.Push(page)
+	at(0)
+	if !n.IsTransitioning() || !slices.Equal(n.pages(), []core.Widget{home, page}) {
+		t.Fatalf("pushed, showing %v", n.pages())
+	}
+	at(50)
+	ctx.LayoutRoot(n, n.Bounds())
+	if got := n.ctl.Progress(); got != 0.5 {
+		t.Errorf("progress %v halfway", got)
+	}
+	if page.Bounds() != n.Bounds() || home.Bounds() != n.Bounds() {
+		t.Errorf("pages at %v and %v, want both over the navigator", home.Bounds(), page.Bounds())
+	}
+	at(100)
+	if n.IsTransitioning() || !slices.Equal(n.pages(), []core.Widget{page}) {
+		t.Errorf("ended, showing %v", n.pages())
+	}
+
+	// Going back, the page leaving is painted over the one entering.
+	n.Pop()
+	if !slices.Equal(n.pages(), []core.Widget{home, page}) || !n.back {
+		t.Errorf("popped, showing %v", n.pages())
+	}
+	n.Transition(NoTransition).Push(page)
+	if n.IsTransitioning() {
+		t.Error("NoTransition runs a transition")
+	}
+}
+
+func TestNavigatorTurnAround(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(n *Navigator, page, other core.Widget)
+		want   float32 // Progress 10 ms after the change.
+	}{
+		{"pop", func(n *Navigator, _, _ core.Widget) { n.Pop() }, 0.3},
+		{"pop and push again", func(n *Navigator, page, _ core.Widget) {
+			n.Pop()
+			n.Push(page)
+		}, 0.5},
+		{"pop and push another", func(n *Navigator, _, other core.Widget) {
+			n.Pop()
+			n.Push(other)
+		}, 0.1},
+		{"push another", func(n *Navigator, _, other core.Widget) { n.Push(other) }, 0.1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home, page, other := &fixed{}, &fixed{}, &fixed{}
+			n, _, at := navigated(home)
+			n.Push(page)
+			at(0)
+			at(40)
+			tt.change(n, page, other)
+			at(40)
+			at(50)
+			if got := n.ctl.Progress(); got < tt.want-1e-3 || got > tt.want+1e-3 {
+				t.Errorf("progress %v, want %v", got, tt.want)
+			}
+			at(1000)
+			if n.IsTransitioning() {
+				t.Error("transitioning after it should have ended")
+			}
+		})
+	}
+}
+
+func TestNavigatorBack(t *testing.T) {
+	home, page := &fixed{}, &fixed{}
+	n, ctx, at := navigated(home)
+	n.Push(page)
+	at(0)
+	at(100)
+
+	// Let go before committing, the gesture returns to the top page.
+	if !n.BeginBack() || !slices.Equal(n.pages(), []core.Widget{home, page}) {
+		t.Fatalf("back gesture showing %v", n.pages())
+	}
+	if n.Pop() {
+		t.Error("popped during a back gesture")
+	}
+	n.UpdateBack(0.4)
+	n.EndBack(false)
+	at(100)
+	at(110)
+	if got := n.ctl.Progress(); got < 0.3-1e-3 || got > 0.3+1e-3 {
+		t.Errorf("progress %v returning, want 0.3", got)
+	}
+
+	// A pop while returning turns around where it is.
+	n.Pop()
+	at(110)
+	at(120)
+	if got := n.ctl.Progress(); got < 0.4-1e-3 || got > 0.4+1e-3 {
+		t.Errorf("progress %v popped while returning, want 0.4", got)
+	}
+	at(1000)
+	if n.IsTransitioning() || n.Top() != home {
+		t.Errorf("top %v after the pop, want home", n.Top())
+	}
+
+	// A committed gesture pops, as does the back button.
+	n.Push(page)
+	at(1000)
+	at(1100)
+	n.BeginBack()
+	n.UpdateBack(0.7)
+	n.EndBack(true)
+	if n.Top() != home || !n.IsTransitioning() {
+		t.Errorf("top %v after a committed gesture, want home on its way", n.Top())
+	}
+	at(1100)
+	at(1200)
+	n.Push(page)
+	at(1200)
+	at(1300)
+	n.HandleEvent(ctx, event.MouseEvent{Type: event.MouseDown, Button: event.ButtonBack})
+	if n.Top() != home {
+		t.Error("the back button did not pop")
+	}
+	if n.BeginBack() {
+		t.Error("a back gesture began with a single page")
+	}
+}
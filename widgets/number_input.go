@@ -0,0 +1,426 @@
+package widgets
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/state"
+	"github.com/gogpu/ui/theme"
+)
+
+// NumberInput metrics and timing.
+const (
+	numberWidth   float32 = 120
+	numberButtons float32 = 20
+	numberScrub   float32 = 2 // pixels of drag per step
+	numberDelay           = 400 * time.Millisecond
+	numberRepeat          = 60 * time.Millisecond
+)
+
+// NumberLocale describes how a NumberInput writes numbers.
+type NumberLocale struct {
+	// Decimal separates the integer and fractional parts.
+	Decimal rune
+	// Group separates thousands, or is zero for no grouping.
+	Group rune
+}
+
+// DefaultNumberLocale writes numbers the English way, as in 1,234.5.
+var DefaultNumberLocale = NumberLocale{Decimal: '.', Group: ','}
+
+// format returns v with prec decimals.
+func (l NumberLocale) format(v float64, prec int) string {
+	s := strconv.FormatFloat(v, 'f', prec, 64)
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	whole, frac, _ := strings.Cut(s, ".")
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, r := range whole {
+		if l.Group != 0 && i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteRune(l.Group)
+		}
+		b.WriteRune(r)
+	}
+	if frac != "" {
+		b.WriteRune(l.Decimal)
+		b.WriteString(frac)
+	}
+	return b.String()
+}
+
+// parse reads a number written with or without group separators. Spaces,
+// including the narrow no-break spaces some locales group with, are
+// ignored.
+func (l NumberLocale) parse(s string) (float64, bool) {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == l.Group && l.Group != 0, unicode.IsSpace(r):
+		case r == l.Decimal:
+			b.WriteByte('.')
+		case r == '-', r == '+', r >= '0' && r <= '9', r == 'e' || r == 'E':
+			b.WriteRune(r)
+		case r == '−': // minus sign
+			b.WriteByte('-')
+		default:
+			return 0, false
+		}
+	}
+	v, err := strconv.ParseFloat(b.String(), 64)
+	if err != nil || math.IsInf(v, 0) || math.IsNaN(v) {
+		return 0, false
+	}
+	return v, true
+}
+
+// NumberInput is a numeric field with increment and decrement buttons, as
+// found in the property panels of CAD and design tools.
+//
+// The value is kept within the limits and shown with thousands separators
+// and an optional unit suffix, such as px, % or mm. Typed numbers are
+// committed with Enter or when focus leaves; text that does not parse marks
+// the field invalid and keeps the previous value. Up and Down step by the
+// step, and holding a spin button repeats it. Dragging the label
+// horizontally scrubs the value by a step every two pixels, or ten steps
+// with Shift held.
+type NumberInput struct {
+	core.WidgetBase
+
+	field     *lineField
+	label     string
+	unit      string
+	locale    NumberLocale
+	min, max  float64
+	step      float64
+	precision int
+	value     float64
+	onChange  func(float64)
+
+	sig    *state.Signal[float64]
+	cancel func()
+
+	style      core.TextStyle
+	labelRect  core.Rect
+	up, down   core.Rect
+	unitPos    core.Point
+	pressed    int // +1 or -1 while a spin button is held
+	repeat     int // generation of the auto-repeat timer
+	scrubbing  bool
+	scrubX     float32
+	scrubValue float64
+}
+
+// NewNumberInput returns an unbounded NumberInput with a step of 1, set to
+// zero.
+func NewNumberInput() *NumberInput {
+	n := &NumberInput{
+		locale:    DefaultNumberLocale,
+		min:       -math.MaxFloat64,
+		max:       math.MaxFloat64,
+		step:      1,
+		precision: -1,
+	}
+	n.field = newLineField(numberWidth, n.commit)
+	n.field.step = func(ctx *core.Context, delta int) { n.stepBy(ctx, float64(delta)) }
+	return n
+}
+
+// Label sets the text shown before the field, which can be dragged to
+// scrub the value.
+func (n *NumberInput) Label(s string) *NumberInput {
+	n.label = s
+	return n
+}
+
+// Limits restricts the value to min through max.
+func (n *NumberInput) Limits(min, max float64) *NumberInput {
+	if max < min {
+		min, max = max, min
+	}
+	n.min, n.max = min, max
+	n.value = n.clamp(n.value)
+	return n
+}
+
+// Step sets the amount the buttons, arrow keys and scrubbing change the
+// value by.
+func (n *NumberInput) Step(step float64) *NumberInput {
+	if step > 0 {
+		n.step = step
+	}
+	return n
+}
+
+// Precision sets the number of decimals shown. A negative precision, the
+// default, uses as many as the step has.
+func (n *NumberInput) Precision(digits int) *NumberInput {
+	n.precision = min(digits, 15)
+	return n
+}
+
+// Unit sets the suffix shown after the number, such as "px", "%" or "mm".
+// Typed text may include or omit it.
+func (n *NumberInput) Unit(unit string) *NumberInput {
+	n.unit = unit
+	return n
+}
+
+// Locale sets the decimal and thousands separators.
+func (n *NumberInput) Locale(l NumberLocale) *NumberInput {
+	n.locale = l
+	return n
+}
+
+// Width sets the width of the field, excluding the label.
+func (n *NumberInput) Width(w float32) *NumberInput {
+	n.field.width = w
+	return n
+}
+
+// Bind keeps the value in sync with sig in both directions.
+func (n *NumberInput) Bind(sig *state.Signal[float64]) *NumberInput {
+	n.Release()
+	n.sig = sig
+	return n
+}
+
+// Release stops following the bound signal, for when the input leaves
+// the tree. Laid out again, the input follows the signal from its value
+// then.
+func (n *NumberInput) Release() {
+	if n.cancel != nil {
+		n.cancel()
+		n.cancel = nil
+	}
+}
+
+// OnChange registers fn to be called when the user changes the value.
+func (n *NumberInput) OnChange(fn func(float64)) *NumberInput {
+	n.onChange = fn
+	return n
+}
+
+// Value returns the current value.
+func (n *NumberInput) Value() float64 {
+	return n.value
+}
+
+// SetValue sets the value without calling OnChange. It is clamped to the
+// limits and rounded to the precision.
+func (n *NumberInput) SetValue(v float64) {
+	n.value = n.clamp(v)
+	if n.sig != nil {
+		n.sig.Set(n.value)
+	}
+}
+
+// IsInvalid reports whether the typed text was rejected.
+func (n *NumberInput) IsInvalid() bool {
+	return n.field.invalid
+}
+
+func (n *NumberInput) digits() int {
+	if n.precision >= 0 {
+		return n.precision
+	}
+	prec := 0
+	for f := n.step; math.Abs(f-math.Round(f)) > 1e-9 && prec < 6; f *= 10 {
+		prec++
+	}
+	return prec
+}
+
+func (n *NumberInput) clamp(v float64) float64 {
+	scale := math.Pow10(n.digits())
+	if r := math.Round(v*scale) / scale; !math.IsInf(r, 0) {
+		v = r
+	}
+	return math.Max(n.min, math.Min(v, n.max))
+}
+
+func (n *NumberInput) text() string {
+	return n.locale.format(n.value, n.digits())
+}
+
+func (n *NumberInput) change(ctx *core.Context, v float64) {
+	old := n.value
+	n.SetValue(v)
+	ctx.MarkNeedsLayout(n)
+	if n.value != old && n.onChange != nil {
+		n.onChange(n.value)
+	}
+}
+
+// commit parses text typed into the field.
+func (n *NumberInput) commit(ctx *core.Context, text string) bool {
+	text = strings.TrimSpace(text)
+	if n.unit != "" && len(text) >= len(n.unit) && strings.EqualFold(text[len(text)-len(n.unit):], n.unit) {
+		text = text[:len(text)-len(n.unit)]
+	}
+	v, ok := n.locale.parse(text)
+	if !ok {
+		return false
+	}
+	n.change(ctx, v)
+	n.field.line.SetText(n.text())
+	return true
+}
+
+func (n *NumberInput) stepBy(ctx *core.Context, steps float64) {
+	n.field.apply(ctx)
+	n.change(ctx, n.value+steps*n.step)
+	n.field.invalid = false
+	n.field.line.SetText(n.text())
+}
+
+// Children implements core.Parent.
+func (n *NumberInput) Children() []core.Widget {
+	return []core.Widget{n.field}
+}
+
+// Layout implements core.Widget.
+func (n *NumberInput) Layout(ctx *core.LayoutContext) core.Size {
+	if n.sig != nil {
+		if n.cancel == nil {
+			c := ctx.Context
+			n.cancel = n.sig.Subscribe(func(float64) { c.Post(func() { c.MarkNeedsLayout(n) }) })
+		}
+		// Showing the signal's value must not rewrite it rounded.
+		if v := n.sig.Get(); v != n.value {
+			n.value = n.clamp(v)
+		}
+	}
+	th := theme.From(ctx.Context)
+	n.style = th.Typography.Body
+	n.field.setText(n.text())
+	n.field.trailing = numberButtons
+	if n.unit != "" {
+		n.field.trailing += ctx.MeasureText(n.unit, n.style).Width + th.Spacing.S
+	}
+	fs := ctx.Measure(n.field, core.Loose(core.Sz(n.field.width, fieldHeight)))
+	w := fs.Width
+	n.labelRect = core.Rect{}
+	if n.label != "" {
+		n.labelRect.Width = ctx.MeasureText(n.label, n.style).Width + th.Spacing.M
+		w += n.labelRect.Width
+	}
+	return ctx.Constraints.Constrain(core.Sz(w, fs.Height))
+}
+
+// SetBounds implements core.Widget.
+func (n *NumberInput) SetBounds(r core.Rect) {
+	n.WidgetBase.SetBounds(r)
+	n.labelRect = core.R(r.X, r.Y, n.labelRect.Width, r.Height)
+	fr := core.R(n.labelRect.Right(), r.Y, r.Width-n.labelRect.Width, r.Height)
+	n.field.SetBounds(fr)
+	bx := fr.Right() - numberButtons
+	n.up = core.R(bx, fr.Y, numberButtons, fr.Height/2)
+	n.down = core.R(bx, fr.Y+fr.Height/2, numberButtons, fr.Height/2)
+	n.unitPos = core.Pt(n.field.line.Rect.Right()+2, fr.Y+(fr.Height-n.style.LineHeight())/2)
+}
+
+// Paint implements core.Widget.
+func (n *NumberInput) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	if n.label != "" {
+		color := th.Colors.OnSurfaceVariant
+		if n.scrubbing {
+			color = th.Colors.Primary
+		}
+		y := n.labelRect.Y + (n.labelRect.Height-n.style.LineHeight())/2
+		cv.DrawText(n.label, core.Pt(n.labelRect.X, y), theme.TextStyle(n.style, color))
+	}
+	n.field.Paint(ctx)
+	if n.unit != "" {
+		cv.DrawText(n.unit, n.unitPos, theme.TextStyle(n.style, th.Colors.OnSurfaceVariant))
+	}
+	for i, b := range []core.Rect{n.up, n.down} {
+		if n.pressed == 1-2*i {
+			cv.DrawRect(b.Inset(core.UniformInsets(1)), core.Filled(th.Colors.OnSurface.WithAlpha(0.12)))
+		}
+	}
+	cv.DrawRect(core.R(n.up.X, n.up.Y+4, 1, n.up.Height*2-8), core.Filled(th.Colors.Outline.WithAlpha(0.4)))
+	color := th.Colors.OnSurfaceVariant
+	paintChevronUp(ctx, n.up.Translate(core.Pt(0, 1)), color)
+	paintChevronDown(ctx, n.down.Translate(core.Pt(0, -1)), color)
+}
+
+// autoRepeat steps again while the spin button pressed in generation gen
+// is held.
+func (n *NumberInput) autoRepeat(ctx *core.Context, gen int, delay time.Duration) {
+	time.AfterFunc(delay, func() {
+		ctx.Post(func() {
+			if n.repeat != gen || n.pressed == 0 {
+				return
+			}
+			n.stepBy(ctx, float64(n.pressed))
+			n.autoRepeat(ctx, gen, numberRepeat)
+		})
+	})
+}
+
+// HandleEvent implements core.Widget. It handles the spin buttons and
+// scrubbing; the field handles text entry.
+func (n *NumberInput) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	e, ok := ev.(event.MouseEvent)
+	if !ok {
+		return core.Ignored
+	}
+	switch e.Type {
+	case event.MouseDown:
+		if e.Button != event.ButtonLeft {
+			return core.Ignored
+		}
+		switch {
+		case n.up.Contains(e.Position), n.down.Contains(e.Position):
+			n.pressed = -1
+			if n.up.Contains(e.Position) {
+				n.pressed = 1
+			}
+			ctx.RequestFocus(n.field)
+			n.stepBy(ctx, float64(n.pressed))
+			n.field.line.SelectAll()
+			n.repeat++
+			n.autoRepeat(ctx, n.repeat, numberDelay)
+		case n.labelRect.Contains(e.Position) && n.label != "":
+			n.field.apply(ctx)
+			n.scrubbing = true
+			n.scrubX, n.scrubValue = e.Position.X, n.value
+		default:
+			return core.Ignored
+		}
+		ctx.CapturePointer(n)
+		ctx.Repaint(n)
+		return core.Handled
+	case event.MouseMove:
+		if !n.scrubbing {
+			return core.Ignored
+		}
+		steps := math.Round(float64((e.Position.X - n.scrubX) / numberScrub))
+		if e.Modifiers&event.ModShift != 0 {
+			steps *= 10
+		}
+		n.change(ctx, n.scrubValue+steps*n.step)
+		n.field.invalid = false
+		n.field.line.SetText(n.text())
+		return core.Handled
+	case event.MouseUp:
+		if n.pressed == 0 && !n.scrubbing {
+			return core.Ignored
+		}
+		n.pressed, n.scrubbing = 0, false
+		ctx.ReleasePointer()
+		ctx.Repaint(n)
+		return core.Handled
+	}
+	return core.Ignored
+}
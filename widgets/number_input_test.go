@@ -0,0 +1,235 @@
+package widgets
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/state"
+)
+
+// germanNumbers writes numbers as in 1.234,5.
+var germanNumbers = NumberLocale{Decimal: ',', Group: '.'}
+
+func TestNumberLocaleFormat(t *testing.T) {
+	tests := []struct {
+		locale NumberLocale
+		v      float64
+		prec   int
+		want   string
+	}{
+		{DefaultNumberLocale, 0, 0, "0"},
+		{DefaultNumberLocale, 999, 0, "999"},
+		{DefaultNumberLocale, 1234.5, 1, "1,234.5"},
+		{DefaultNumberLocale, -1234567, 0, "-1,234,567"},
+		{germanNumbers, 1234.5, 2, "1.234,50"},
+		{NumberLocale{Decimal: '.'}, 1234.5, 1, "1234.5"},
+	}
+	for _, tt := range tests {
+		if got := tt.locale.format(tt.v, tt.prec); got != tt.want {
+			t.Errorf("format(%v, %d) = %q, want %q", tt.v, tt.prec, got, tt.want)
+		}
+	}
+}
+
+func TestNumberLocaleParse(t *testing.T) {
+	tests := []struct {
+		locale NumberLocale
+		in     string
+		want   float64
+		ok     bool
+	}{
+		{DefaultNumberLocale, "1,234.5", 1234.5, true},
+		{DefaultNumberLocale, " 1 234 ", 1234, true},
+		{DefaultNumberLocale, "1 234", 1234, true},
+		{DefaultNumberLocale, "−5", -5, true},
+		{DefaultNumberLocale, "1e3", 1000, true},
+		{germanNumbers, "1.234,5", 1234.5, true},
+		{DefaultNumberLocale, "12px", 0, false},
+		{DefaultNumberLocale, "", 0, false},
+		{DefaultNumberLocale, "1e999", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := tt.locale.parse(tt.in)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parse(%q) = %v, %v, want %v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestNumberInputCommit(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    float64
+		shown   string
+		invalid bool
+	}{
+		{"plain", "42", 42, "42.0", false},
+		{"with the unit", "12.25 px", 12.3, "12.3", false},
+		{"unit in capitals", "3PX", 3, "3.0", false},
+		{"grouped", "1,000", 100, "100.0", false},
+		{"below the limits", "-20", -10, "-10.0", false},
+		{"garbage", "ten", 5, "ten", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := NewNumberInput().Limits(-10, 100).Step(0.1).Unit("px")
+			n.SetValue(5)
+			changes := 0
+			n.OnChange(func(float64) { changes++ })
+			ctx := layoutAt(n, core.R(0, 0, 160, 32))
+			ctx.RequestFocus(n.field)
+			typeText(ctx, n.field, tt.text)
+			n.field.HandleEvent(ctx, press(event.KeyEnter, 0))
+			if n.Value() != tt.want || n.IsInvalid() != tt.invalid {
+				t.Errorf("Value() = %v, invalid %v, want %v, %v", n.Value(), n.IsInvalid(), tt.want, tt.invalid)
+			}
+			if got := n.field.line.Text(); got != tt.shown {
+				t.Errorf("field shows %q, want %q", got, tt.shown)
+			}
+			if (changes == 1) == tt.invalid {
+				t.Errorf("%d changes", changes)
+			}
+		})
+	}
+}
+
+func TestNumberInputPrecision(t *testing.T) {
+	tests := []struct {
+		step float64
+		prec int
+		v    float64
+		want float64
+		text string
+	}{
+		{1, -1, 2.6, 3, "3"},
+		{0.25, -1, 1.234, 1.23, "1.23"},
+		{1, 3, 1.23456, 1.235, "1.235"},
+		{0.1, 0, 1.6, 2, "2"},
+	}
+	for _, tt := range tests {
+		n := NewNumberInput().Step(tt.step).Precision(tt.prec)
+		n.SetValue(tt.v)
+		if n.Value() != tt.want || n.text() != tt.text {
+			t.Errorf("step %v, precision %d: %v shown as %q, want %v as %q", tt.step, tt.prec, n.Value(), n.text(), tt.want, tt.text)
+		}
+	}
+}
+
+func TestNumberInputKeys(t *testing.T) {
+	n := NewNumberInput().Limits(0, 10).Step(2)
+	n.SetValue(4)
+	ctx := layoutAt(n, core.R(0, 0, 160, 32))
+	ctx.RequestFocus(n.field)
+	n.field.HandleEvent(ctx, press(event.KeyUp, 0))
+	if n.Value() != 6 || n.field.line.Text() != "6" {
+		t.Errorf("Up gave %v showing %q", n.Value(), n.field.line.Text())
+	}
+	// Typed text is committed before stepping from it.
+	typeText(ctx, n.field, "9")
+	n.field.HandleEvent(ctx, press(event.KeyUp, 0))
+	if n.Value() != 10 {
+		t.Errorf("Up after typing 9 gave %v, want 10", n.Value())
+	}
+	typeText(ctx, n.field, "x")
+	n.field.HandleEvent(ctx, press(event.KeyDown, 0))
+	if n.Value() != 8 || n.IsInvalid() || n.field.line.Text() != "8" {
+		t.Errorf("Down after invalid text gave %v showing %q", n.Value(), n.field.line.Text())
+	}
+}
+
+func TestNumberInputSpinButtons(t *testing.T) {
+	n := NewNumberInput()
+	var got []float64
+	n.OnChange(func(v float64) { got = append(got, v) })
+	ctx := layoutAt(n, core.R(0, 0, 160, 32))
+	if path := core.HitTest(n, n.up.Center()); path[len(path)-1] != n {
+		t.Errorf("the up button is covered by %T", path[len(path)-1])
+	}
+	n.HandleEvent(ctx, leftMouse(event.MouseDown, n.up.Center()))
+	if n.Value() != 1 || ctx.Focused() != n.field {
+		t.Fatalf("Value() = %v after pressing up", n.Value())
+	}
+	runPosted(t, ctx) // The first repeat.
+	runPosted(t, ctx)
+	n.HandleEvent(ctx, leftMouse(event.MouseUp, n.up.Center()))
+	if n.Value() != 3 || len(got) != 3 {
+		t.Errorf("Value() = %v with changes %v after holding up", n.Value(), got)
+	}
+	if ctx.PointerCapture() != nil {
+		t.Error("the button kept the pointer")
+	}
+	runPosted(t, ctx) // A repeat already due is dropped.
+	if n.Value() != 3 {
+		t.Errorf("Value() = %v, the repeat outlived the press", n.Value())
+	}
+	n.HandleEvent(ctx, leftMouse(event.MouseDown, n.down.Center()))
+	n.HandleEvent(ctx, leftMouse(event.MouseUp, n.down.Center()))
+	if n.Value() != 2 {
+		t.Errorf("Value() = %v after pressing down", n.Value())
+	}
+}
+
+func TestNumberInputScrub(t *testing.T) {
+	tests := []struct {
+		name string
+		dx   float32
+		mods event.Modifiers
+		want float64
+	}{
+		{"right", 10, 0, 55},
+		{"left", -6, 0, 47},
+		{"shift", 4, event.ModShift, 70},
+		{"clamped", -400, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := NewNumberInput().Label("Width").Limits(0, 100)
+			n.SetValue(50)
+			ctx := layoutAt(n, core.R(0, 0, 240, 32))
+			start := n.labelRect.Center()
+			if n.HandleEvent(ctx, leftMouse(event.MouseDown, start)) != core.Handled || !n.scrubbing {
+				t.Fatal("pressing the label did not start scrubbing")
+			}
+			n.HandleEvent(ctx, event.MouseEvent{Type: event.MouseMove, Position: start.Add(core.Pt(tt.dx, 0)), Modifiers: tt.mods})
+			n.HandleEvent(ctx, leftMouse(event.MouseUp, start))
+			if n.Value() != tt.want || n.scrubbing {
+				t.Errorf("Value() = %v, want %v", n.Value(), tt.want)
+			}
+		})
+	}
+
+	n := NewNumberInput()
+	ctx := layoutAt(n, core.R(0, 0, 160, 32))
+	if r := n.HandleEvent(ctx, leftMouse(event.MouseDown, core.Pt(1, 16))); r != core.Ignored {
+		t.Errorf("a press on the field of an input without a label = %v", r)
+	}
+}
+
+func TestNumberInputBind(t *testing.T) {
+	sig := state.New(7.0)
+	n := NewNumberInput().Bind(sig)
+	ctx := layoutAt(n, core.R(0, 0, 160, 32))
+	if n.Value() != 7 || n.field.line.Text() != "7" {
+		t.Fatalf("Value() = %v, want the signal's 7", n.Value())
+	}
+	ctx.RequestFocus(n.field)
+	n.field.HandleEvent(ctx, press(event.KeyUp, 0))
+	if sig.Get() != 8 {
+		t.Errorf("signal holds %v after stepping", sig.Get())
+	}
+	ctx.RequestFocus(nil)
+	sig.Set(1234)
+	ctx.RunPosted()
+	ctx.LayoutRoot(n, core.R(0, 0, 160, 32))
+	if n.field.line.Text() != "1,234" {
+		t.Errorf("field shows %q after the signal changed", n.field.line.Text())
+	}
+	sig.Set(0.25)
+	ctx.RunPosted()
+	ctx.LayoutRoot(n, core.R(0, 0, 160, 32))
+	if n.field.line.Text() != "0" || sig.Get() != 0.25 {
+		t.Errorf("showing 0.25 rounded set the signal to %v", sig.Get())
+	}
+}
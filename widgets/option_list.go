@@ -0,0 +1,181 @@
+package widgets
+
+import (
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/internal/scroll"
+	"github.com/gogpu/ui/theme"
+)
+
+// Option list metrics.
+const (
+	optionRow      float32 = 32
+	optionRows             = 8 // visible rows before the list scrolls
+	optionMinWidth float32 = 120
+	optionCheck    float32 = 28 // width of the checkmark column
+)
+
+// optionList is the scrolling list of choices in the drop-down of a
+// ComboBox or MultiSelect. It never takes focus: the field that opened it
+// keeps the keyboard and moves the highlight with move.
+type optionList struct {
+	core.WidgetBase
+
+	items     []string
+	highlight int
+	minWidth  float32
+
+	// message is shown instead of the items while there are none, such
+	// as "No matches" or "Loading…".
+	message string
+	// checked, if set, adds a column of checkmarks.
+	checked func(i int) bool
+	// pick is called when an item is clicked.
+	pick func(ctx *core.Context, i int)
+
+	offset float32
+	bar    scroll.Bar
+	style  core.TextStyle
+}
+
+func newOptionList(pick func(ctx *core.Context, i int)) *optionList {
+	return &optionList{pick: pick, highlight: -1}
+}
+
+// setItems replaces the items, highlighting the first one.
+func (l *optionList) setItems(items []string) {
+	l.items = items
+	l.offset = 0
+	l.highlight = -1
+	if len(items) > 0 {
+		l.highlight = 0
+	}
+}
+
+// move moves the highlight by delta rows, stopping at the ends.
+func (l *optionList) move(delta int) {
+	if len(l.items) == 0 {
+		return
+	}
+	l.highlight = max(0, min(l.highlight+delta, len(l.items)-1))
+	l.scrollToHighlight()
+}
+
+func (l *optionList) scrollToHighlight() {
+	if l.highlight < 0 {
+		return
+	}
+	top := float32(l.highlight) * optionRow
+	view := l.Bounds().Height
+	switch {
+	case top < l.offset:
+		l.offset = top
+	case top+optionRow > l.offset+view:
+		l.offset = top + optionRow - view
+	}
+	l.offset = core.Clamp(l.offset, 0, l.bar.MaxOffset())
+}
+
+func (l *optionList) Layout(ctx *core.LayoutContext) core.Size {
+	l.style = theme.From(ctx.Context).Typography.Body
+	w := max(l.minWidth, optionMinWidth)
+	check := float32(0)
+	if l.checked != nil {
+		check = optionCheck
+	}
+	for _, it := range l.items {
+		w = max(w, ctx.MeasureText(it, l.style).Width+2*fieldPadding+check+scroll.Thickness)
+	}
+	if len(l.items) == 0 {
+		w = max(w, ctx.MeasureText(l.message, l.style).Width+2*fieldPadding)
+	}
+	rows := max(1, min(len(l.items), optionRows))
+	return ctx.Constraints.Constrain(core.Sz(w, float32(rows)*optionRow))
+}
+
+func (l *optionList) SetBounds(r core.Rect) {
+	l.WidgetBase.SetBounds(r)
+	l.bar.Track = core.R(r.Right()-scroll.Thickness, r.Y, scroll.Thickness, r.Height)
+	l.bar.Viewport = r.Height
+	l.bar.Content = float32(len(l.items)) * optionRow
+	l.offset = core.Clamp(l.offset, 0, l.bar.MaxOffset())
+}
+
+func (l *optionList) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	b := l.Bounds()
+	ty := (optionRow - l.style.LineHeight()) / 2
+	if len(l.items) == 0 {
+		cv.DrawText(l.message, core.Pt(b.X+fieldPadding, b.Y+ty), theme.TextStyle(l.style, th.Colors.OnSurfaceVariant))
+		return
+	}
+	cv.Save()
+	cv.Clip(b)
+	first := int(l.offset / optionRow)
+	for i := first; i < len(l.items); i++ {
+		y := b.Y + float32(i)*optionRow - l.offset
+		if y > b.Bottom() {
+			break
+		}
+		row := core.R(b.X, y, b.Width, optionRow)
+		if i == l.highlight {
+			cv.DrawRoundedRect(row, th.Radii.Small, core.Filled(th.Colors.Selection))
+		}
+		x := b.X + fieldPadding
+		if l.checked != nil {
+			if l.checked(i) {
+				c := core.Pt(x+7, y+optionRow/2)
+				mark := core.NewPath().MoveTo(core.Pt(c.X-5, c.Y)).LineTo(core.Pt(c.X-1.5, c.Y+3.5)).LineTo(core.Pt(c.X+5, c.Y-4))
+				cv.DrawPath(mark, core.PathStyle{Stroke: th.Colors.Primary, StrokeWidth: 2, LineCap: core.CapRound, LineJoin: core.JoinRound})
+			}
+			x += optionCheck - fieldPadding/2
+		}
+		cv.DrawText(l.items[i], core.Pt(x, y+ty), theme.TextStyle(l.style, th.Colors.OnSurface))
+	}
+	l.bar.Paint(ctx, l.offset)
+	cv.Restore()
+}
+
+func (l *optionList) itemAt(p core.Point) int {
+	if !l.Bounds().Contains(p) {
+		return -1
+	}
+	i := int((p.Y - l.Bounds().Y + l.offset) / optionRow)
+	if i < 0 || i >= len(l.items) {
+		return -1
+	}
+	return i
+}
+
+func (l *optionList) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	if off, ok := l.bar.HandleEvent(ctx, l, ev, l.offset); ok {
+		l.offset = off
+		ctx.Repaint(l)
+		return core.Handled
+	}
+	switch e := ev.(type) {
+	case event.MouseEvent:
+		switch e.Type {
+		case event.MouseMove:
+			if i := l.itemAt(e.Position); i >= 0 && i != l.highlight {
+				l.highlight = i
+				ctx.Repaint(l)
+			}
+			return core.Handled
+		case event.MouseDown:
+			if e.Button != event.ButtonLeft {
+				return core.Handled
+			}
+			if i := l.itemAt(e.Position); i >= 0 && l.pick != nil {
+				l.pick(ctx, i)
+			}
+			return core.Handled
+		}
+	case event.ScrollEvent:
+		l.offset = core.Clamp(l.offset+e.Delta.Y, 0, l.bar.MaxOffset())
+		ctx.Repaint(l)
+		return core.Handled
+	}
+	return core.Ignored
+}
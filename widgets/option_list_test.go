@@ -0,0 +1,94 @@
+package widgets
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+)
+
+// newOptionListOf returns a list of n items laid out under the limit of
+// visible rows, and the indexes it picks.
+func newOptionListOf(n int) (*optionList, *core.Context, *[]int) {
+	var picked []int
+	l := newOptionList(func(_ *core.Context, i int) { picked = append(picked, i) })
+	items := make([]string, n)
+	for i := range items {
+		items[i] = fmt.Sprintf("item %d", i)
+	}
+	l.setItems(items)
+	ctx := layoutAt(l, core.R(0, 0, 200, optionRows*optionRow))
+	return l, ctx, &picked
+}
+
+func TestOptionListMove(t *testing.T) {
+	tests := []struct {
+		moves     []int
+		highlight int
+		offset    float32
+	}{
+		{[]int{1}, 1, 0},
+		{[]int{-1}, 0, 0},
+		{[]int{optionRows}, optionRows, optionRow},
+		{[]int{100}, 19, 12 * optionRow},
+		{[]int{100, -15}, 4, 4 * optionRow},
+	}
+	for _, tt := range tests {
+		l, _, _ := newOptionListOf(20)
+		for _, d := range tt.moves {
+			l.move(d)
+		}
+		if l.highlight != tt.highlight || l.offset != tt.offset {
+			t.Errorf("moves %v: highlight %d at offset %v, want %d at %v", tt.moves, l.highlight, l.offset, tt.highlight, tt.offset)
+		}
+	}
+
+	empty := newOptionList(nil)
+	empty.setItems(nil)
+	empty.move(1)
+	if empty.highlight != -1 {
+		t.Errorf("an empty list highlights %d", empty.highlight)
+	}
+}
+
+func TestOptionListMouse(t *testing.T) {
+	l, ctx, picked := newOptionListOf(20)
+	l.HandleEvent(ctx, leftMouse(event.MouseMove, core.Pt(20, 2*optionRow+5)))
+	if l.highlight != 2 {
+		t.Errorf("hovering the third row highlights %d", l.highlight)
+	}
+	l.HandleEvent(ctx, event.ScrollEvent{Delta: core.Pt(0, 3*optionRow)})
+	if l.offset != 3*optionRow {
+		t.Errorf("offset %v after scrolling three rows", l.offset)
+	}
+	l.HandleEvent(ctx, leftMouse(event.MouseDown, core.Pt(20, 5)))
+	if len(*picked) != 1 || (*picked)[0] != 3 {
+		t.Errorf("clicking the top row picked %v, want [3]", *picked)
+	}
+	l.HandleEvent(ctx, event.ScrollEvent{Delta: core.Pt(0, 1000)})
+	if l.offset != 12*optionRow {
+		t.Errorf("offset %v, want clamped to %v", l.offset, 12*optionRow)
+	}
+	if i := l.itemAt(core.Pt(20, optionRows*optionRow+1)); i != -1 {
+		t.Errorf("itemAt below the list = %d", i)
+	}
+}
+
+func TestOptionListSize(t *testing.T) {
+	tests := []struct {
+		n      int
+		height float32
+	}{
+		{0, optionRow},
+		{3, 3 * optionRow},
+		{20, optionRows * optionRow},
+	}
+	for _, tt := range tests {
+		l, _, _ := newOptionListOf(tt.n)
+		lc := &core.LayoutContext{Context: core.NewContext()}
+		if got := lc.Measure(l, core.Loose(core.Sz(400, 600))).Height; got != tt.height {
+			t.Errorf("%d items: height %v, want %v", tt.n, got, tt.height)
+		}
+	}
+}
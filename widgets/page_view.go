@@ -0,0 +1,416 @@
+package widgets
+
+import (
+	"math"
+	"time"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/theme"
+)
+
+const (
+	pageDotSize     float32 = 8
+	pageDotGap      float32 = 8
+	pageDotsHeight  float32 = 24
+	pageDragSlop    float32 = 6
+	pageFlickMin    float32 = 24
+	pageScrollStep  float32 = 40
+	pageEdgeDamping float32 = 3
+)
+
+const (
+	// pageTween is how long snapping to a page takes.
+	pageTween = 300 * time.Millisecond
+
+	// pageFlick is the longest press that still turns the page with a short
+	// swipe; slower drags must pass the middle of the page.
+	pageFlick = 250 * time.Millisecond
+)
+
+// PageView shows one full-size page at a time and moves between them with
+// a horizontal swipe: dragging the pointer, or a sideways touchpad scroll.
+// A release snaps to the nearest page, or to the next one after a quick
+// flick. Dots along the bottom show the current page and jump to a page
+// when clicked.
+//
+// While focused, the Left and Right arrows turn the page and Home and End
+// go to the first and last. A looping view continues from the last page to
+// the first and back; otherwise dragging past either end resists and
+// springs back.
+//
+// In right-to-left text the pages run from right to left: the next page
+// comes in from the left, and the Left arrow turns to it.
+//
+// Only the current page and, while moving, its neighbor are laid out, so
+// galleries with many pages stay cheap.
+type PageView struct {
+	core.WidgetBase
+	core.FocusState
+
+	pages    []core.Widget
+	loop     bool
+	dots     bool
+	onChange func(index int)
+
+	// slot is the page being shown or snapped to. It counts pages without
+	// wrapping, so that a looping view can animate past the last page;
+	// the current page is slot modulo the page count.
+	slot int
+
+	// pos is the slot shown at the start edge, fractional while moving,
+	// animated from from after a page change at changedAt.
+	pos       float32
+	from      float32
+	changedAt time.Time
+
+	pressed   bool
+	dragging  bool
+	pressPos  core.Point
+	pressAt   time.Time
+	pressBase float32 // pos when the drag started
+	scrolled  float32
+	dotRects  []core.Rect
+	dir       core.Direction
+}
+
+// NewPageView returns a view of pages showing the first, with page dots.
+func NewPageView(pages ...core.Widget) *PageView {
+	return &PageView{pages: pages, dots: true}
+}
+
+// Pages returns the pages.
+func (v *PageView) Pages() []core.Widget {
+	return v.pages
+}
+
+// SetPages replaces the pages, keeping the current index if it is still
+// valid.
+func (v *PageView) SetPages(pages ...core.Widget) {
+	i := min(v.Page(), max(0, len(pages)-1))
+	v.pages = pages
+	v.slot, v.pos, v.from = i, float32(i), float32(i)
+}
+
+// Loop sets whether the last page is followed by the first.
+func (v *PageView) Loop(loop bool) *PageView {
+	v.loop = loop
+	return v
+}
+
+// ShowIndicators sets whether the page dots are shown.
+func (v *PageView) ShowIndicators(show bool) *PageView {
+	v.dots = show
+	return v
+}
+
+// OnPageChange registers fn to be called when the current page changes,
+// as the view starts moving to it.
+func (v *PageView) OnPageChange(fn func(index int)) *PageView {
+	v.onChange = fn
+	return v
+}
+
+// Page returns the index of the current page.
+func (v *PageView) Page() int {
+	return v.wrap(v.slot)
+}
+
+// SetPage moves to the page at index, animating unless motion is reduced.
+func (v *PageView) SetPage(index int) {
+	if index < 0 || index >= len(v.pages) {
+		return
+	}
+	v.moveTo(v.slot + index - v.Page())
+}
+
+// Next moves to the following page, wrapping around in a looping view.
+func (v *PageView) Next() {
+	v.moveTo(v.slot + 1)
+}
+
+// Prev moves to the preceding page, wrapping around in a looping view.
+func (v *PageView) Prev() {
+	v.moveTo(v.slot - 1)
+}
+
+func (v *PageView) looping() bool {
+	return v.loop && len(v.pages) > 1
+}
+
+// wrap returns the page shown in slot.
+func (v *PageView) wrap(slot int) int {
+	n := len(v.pages)
+	if n == 0 {
+		return 0
+	}
+	return (slot%n + n) % n
+}
+
+func (v *PageView) moveTo(slot int) {
+	if len(v.pages) == 0 {
+		return
+	}
+	if !v.looping() {
+		slot = min(max(slot, 0), len(v.pages)-1)
+	}
+	old := v.Page()
+	v.slot = slot
+	v.from = v.pos
+	v.changedAt = time.Time{}
+	if v.Page() != old && v.onChange != nil {
+		v.onChange(v.Page())
+	}
+}
+
+// visible returns the slots at least partly shown.
+func (v *PageView) visible() []int {
+	if len(v.pages) == 0 {
+		return nil
+	}
+	lo := int(math.Floor(float64(v.pos)))
+	slots := []int{lo}
+	if float32(lo) != v.pos {
+		slots = append(slots, lo+1)
+	}
+	if !v.looping() {
+		for i := len(slots) - 1; i >= 0; i-- {
+			if slots[i] < 0 || slots[i] >= len(v.pages) {
+				slots = append(slots[:i], slots[i+1:]...)
+			}
+		}
+	}
+	return slots
+}
+
+// Layout implements core.Widget.
+func (v *PageView) Layout(ctx *core.LayoutContext) core.Size {
+	v.dir = ctx.Direction()
+	if !v.dragging && v.pos != float32(v.slot) {
+		if v.changedAt.IsZero() {
+			v.changedAt = ctx.Now()
+		}
+		t := float32(ctx.Now().Sub(v.changedAt)) / float32(pageTween)
+		if t >= 1 || ctx.ReducedMotion() {
+			v.pos = float32(v.slot)
+		} else {
+			v.pos = v.from + (float32(v.slot)-v.from)*easeOut(max(t, 0))
+			ctx.MarkNeedsLayout(v)
+		}
+	}
+	if v.pos == float32(v.slot) && v.looping() && !v.pressed {
+		// Keep the slot near zero once the view has settled.
+		v.slot = v.Page()
+		v.pos, v.from = float32(v.slot), float32(v.slot)
+	}
+
+	size := ctx.Constraints.Max()
+	if !ctx.Constraints.HasBoundedWidth() || !ctx.Constraints.HasBoundedHeight() {
+		var natural core.Size
+		if len(v.pages) > 0 {
+			natural = ctx.Measure(v.pages[v.Page()], ctx.Constraints.Loosen())
+		}
+		if !ctx.Constraints.HasBoundedWidth() {
+			size.Width = natural.Width
+		}
+		if !ctx.Constraints.HasBoundedHeight() {
+			size.Height = natural.Height
+		}
+	}
+	size = ctx.Constraints.Constrain(size)
+	var children []core.Widget
+	for _, s := range v.visible() {
+		p := v.pages[v.wrap(s)]
+		ctx.Measure(p, core.Tight(size))
+		children = append(children, p)
+	}
+	v.SetChildren(children...)
+	return size
+}
+
+// SetBounds implements core.Widget.
+func (v *PageView) SetBounds(r core.Rect) {
+	v.WidgetBase.SetBounds(r)
+	for _, s := range v.visible() {
+		x := r.X + (float32(s)-v.pos)*r.Width
+		v.pages[v.wrap(s)].SetBounds(v.dir.Mirror(core.R(x, r.Y, r.Width, r.Height), r))
+	}
+	v.dotRects = v.dotRects[:0]
+	if !v.dots || len(v.pages) < 2 {
+		return
+	}
+	n := float32(len(v.pages))
+	w := n*pageDotSize + (n-1)*pageDotGap
+	x := r.X + (r.Width-w)/2
+	y := r.Bottom() - pageDotsHeight
+	for range v.pages {
+		// The clickable area reaches halfway into the gaps.
+		v.dotRects = append(v.dotRects, v.dir.Mirror(core.R(x-pageDotGap/2, y, pageDotSize+pageDotGap, pageDotsHeight), r))
+		x += pageDotSize + pageDotGap
+	}
+}
+
+// Paint implements core.Widget.
+func (v *PageView) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	cv.Save()
+	cv.Clip(v.Bounds())
+	for _, c := range v.Children() {
+		c.Paint(ctx)
+	}
+	cv.Restore()
+
+	if len(v.dotRects) == 0 {
+		return
+	}
+	// The active dot slides between pages as the view moves.
+	at := v.pos - float32(v.slot) + float32(v.Page())
+	for i, r := range v.dotRects {
+		d := core.R(r.X+pageDotGap/2, r.Y+(r.Height-pageDotSize)/2, pageDotSize, pageDotSize)
+		near := 1 - min(1, abs32(at-float32(i)))
+		if v.looping() && i == 0 {
+			near = max(near, 1-min(1, abs32(at-float32(len(v.pages)))))
+		}
+		c := th.Colors.OnSurfaceVariant.WithAlpha(0.4).Lerp(th.Colors.Primary, near)
+		cv.DrawRoundedRect(d, pageDotSize/2, core.Filled(c))
+	}
+	if v.IsFocused() {
+		ring := v.dotRects[0].Union(v.dotRects[len(v.dotRects)-1]).Inset(core.SymmetricInsets(0, 2))
+		cv.DrawRoundedRect(ring, ring.Height/2, core.Stroked(th.Colors.Primary, toggleRing))
+	}
+}
+
+// forward returns the horizontal distance dx measured toward the end
+// edge.
+func (v *PageView) forward(dx float32) float32 {
+	if v.dir.IsRTL() {
+		return -dx
+	}
+	return dx
+}
+
+// drag moves the pages with the pointer, resisting past the ends of a
+// view that does not loop.
+func (v *PageView) drag(p core.Point) {
+	w := v.Bounds().Width
+	if w <= 0 {
+		return
+	}
+	pos := v.pressBase - v.forward(p.X-v.pressPos.X)/w
+	if !v.looping() {
+		last := float32(len(v.pages) - 1)
+		if pos < 0 {
+			pos /= pageEdgeDamping
+		} else if pos > last {
+			pos = last + (pos-last)/pageEdgeDamping
+		}
+	}
+	v.pos = pos
+}
+
+// release snaps to a page after a drag.
+func (v *PageView) release(ctx *core.Context, p core.Point) {
+	dx := v.forward(p.X - v.pressPos.X)
+	slot := int(math.Round(float64(v.pos)))
+	if ctx.Now().Sub(v.pressAt) <= pageFlick && abs32(dx) >= pageFlickMin {
+		base := int(math.Round(float64(v.pressBase)))
+		if dx < 0 {
+			slot = base + 1
+		} else {
+			slot = base - 1
+		}
+	}
+	v.moveTo(slot)
+}
+
+// HandleEvent implements core.Widget.
+func (v *PageView) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	if len(v.pages) == 0 {
+		return core.Ignored
+	}
+	switch e := ev.(type) {
+	case event.MouseEvent:
+		switch e.Type {
+		case event.MouseDown:
+			if e.Button != event.ButtonLeft {
+				return core.Ignored
+			}
+			for i, r := range v.dotRects {
+				if r.Contains(e.Position) {
+					v.SetPage(i)
+					ctx.MarkNeedsLayout(v)
+					return core.Handled
+				}
+			}
+			v.pressed, v.dragging = true, false
+			v.pressPos, v.pressAt = e.Position, ctx.Now()
+			ctx.CapturePointer(v)
+			return core.Handled
+		case event.MouseMove:
+			if !v.pressed {
+				return core.Ignored
+			}
+			if !v.dragging && abs32(e.Position.X-v.pressPos.X) >= pageDragSlop {
+				// Take over from any running animation where it is.
+				v.dragging = true
+				v.pressBase = v.pos
+			}
+			if v.dragging {
+				v.drag(e.Position)
+				ctx.MarkNeedsLayout(v)
+			}
+			return core.Handled
+		case event.MouseUp:
+			if !v.pressed || e.Button != event.ButtonLeft {
+				return core.Ignored
+			}
+			v.pressed = false
+			ctx.ReleasePointer()
+			if v.dragging {
+				v.dragging = false
+				v.release(ctx, e.Position)
+				ctx.MarkNeedsLayout(v)
+			}
+			return core.Handled
+		}
+	case event.ScrollEvent:
+		if abs32(e.Delta.X) <= abs32(e.Delta.Y) {
+			return core.Ignored
+		}
+		if v.pos != float32(v.slot) {
+			// Let the current move finish before turning again.
+			return core.Handled
+		}
+		v.scrolled += v.forward(e.Delta.X)
+		if abs32(v.scrolled) >= pageScrollStep || !e.Precise {
+			if v.scrolled > 0 {
+				v.Next()
+			} else {
+				v.Prev()
+			}
+			v.scrolled = 0
+			ctx.MarkNeedsLayout(v)
+		}
+		return core.Handled
+	case event.KeyEvent:
+		if !v.IsFocused() || e.Type != event.KeyPress || e.Modifiers != 0 {
+			return core.Ignored
+		}
+		switch mirrorKey(v.dir, e.Key) {
+		case event.KeyLeft:
+			v.Prev()
+		case event.KeyRight:
+			v.Next()
+		case event.KeyHome:
+			v.SetPage(0)
+		case event.KeyEnd:
+			v.SetPage(len(v.pages) - 1)
+		default:
+			return core.Ignored
+		}
+		ctx.MarkNeedsLayout(v)
+		return core.Handled
+	}
+	return core.Ignored
+}
@@ -0,0 +1,321 @@
+package widgets
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+)
+
+// pages returns n pages 50 high.
+func pages(n int) []core.Widget {
+	p := make([]core.Widget, n)
+	for i := range p {
+		p[i] = &fixed{height: 50}
+	}
+	return p
+}
+
+func TestPageViewNavigate(t *testing.T) {
+	tests := []struct {
+		name    string
+		loop    bool
+		moves   func(v *PageView)
+		page    int
+		changes []int
+	}{
+		{"next", false, func(v *PageView) { v.Next(); v.Next() }, 2, []int{1, 2}},
+		{"past the end", false, func(v *PageView) { v.SetPage(2); v.Next() }, 2, []int{2}},
+		{"before the start", false, (*PageView).Prev, 0, nil},
+		{"out of range", false, func(v *PageView) { v.SetPage(3); v.SetPage(-1) }, 0, nil},
+		{"loop forward", true, func(v *PageView) { v.SetPage(2); v.Next() }, 0, []int{2, 0}},
+		{"loop back", true, (*PageView).Prev, 2, []int{2}},
+		{"same page", true, func(v *PageView) { v.SetPage(0) }, 0, nil},
+	}
+	for _, tt := range tests {
+		var changes []int
+		v := NewPageView(pages(3)...).Loop(tt.loop).OnPageChange(func(i int) { changes = append(changes, i) })
+		tt.moves(v)
+		if v.Page() != tt.page || !slices.Equal(changes, tt.changes) {
+			t.Errorf("%s: on page %d after changes %v, want %d and %v", tt.name, v.Page(), changes, tt.page, tt.changes)
+		}
+	}
+
+	// A single page does not loop.
+	single := NewPageView(pages(1)...).Loop(true)
+	single.Next()
+	if single.slot != 0 {
+		t.Errorf("a single looping page moved to slot %d", single.slot)
+	}
+
+	v := NewPageView(pages(3)...)
+	v.SetPage(2)
+	v.SetPages(pages(2)...)
+	if v.Page() != 1 || v.pos != 1 || len(v.Pages()) != 2 {
+		t.Errorf("after dropping the current page on page %d at %v", v.Page(), v.pos)
+	}
+	empty := NewPageView()
+	empty.Next()
+	if empty.Page() != 0 || empty.visible() != nil {
+		t.Error("an empty view moved")
+	}
+}
+
+func TestPageViewLayout(t *testing.T) {
+	p := pages(3)
+	v := NewPageView(p...)
+	ctx := core.NewContext()
+	t0 := time.Now()
+	ctx.SetNow(t0)
+	bounds := core.R(0, 0, 300, 200)
+	ctx.LayoutRoot(v, bounds)
+	if len(v.Children()) != 1 || p[0].Bounds() != bounds {
+		t.Fatalf("%d pages laid out, the first at %v", len(v.Children()), p[0].Bounds())
+	}
+	lc := &core.LayoutContext{Context: ctx}
+	if got := lc.Measure(NewPageView(pages(2)...), core.Loose(core.Sz(300, core.Infinity))); got != core.Sz(300, 50) {
+		t.Errorf("with unbounded height the size is %v, want the page's", got)
+	}
+
+	v.Next()
+	ctx.LayoutRoot(v, bounds)
+	ctx.SetNow(t0.Add(pageTween / 2))
+	ctx.LayoutRoot(v, bounds)
+	if v.pos <= 0.5 || v.pos >= 1 || len(v.Children()) != 2 {
+		t.Fatalf("halfway at %v with %d pages", v.pos, len(v.Children()))
+	}
+	if a, b := p[0].Bounds(), p[1].Bounds(); a.Right() != b.X || a.X >= 0 || b.Width != 300 {
+		t.Errorf("moving, the pages at %v and %v", a, b)
+	}
+	ctx.SetNow(t0.Add(pageTween))
+	ctx.LayoutRoot(v, bounds)
+	if v.pos != 1 || len(v.Children()) != 1 || p[1].Bounds() != bounds {
+		t.Errorf("settled at %v with %d pages", v.pos, len(v.Children()))
+	}
+
+	// A looping view moves on from the last page to the first, then
+	// settles on slot zero.
+	v.Loop(true)
+	ctx.SetReducedMotion(true)
+	v.SetPage(2)
+	ctx.LayoutRoot(v, bounds)
+	ctx.SetReducedMotion(false)
+	ctx.SetNow(t0)
+	v.Next()
+	ctx.LayoutRoot(v, bounds)
+	ctx.SetNow(t0.Add(pageTween / 2))
+	ctx.LayoutRoot(v, bounds)
+	if !slices.Equal(v.visible(), []int{2, 3}) || p[0].Bounds().X <= p[2].Bounds().X {
+		t.Errorf("looping, slots %v shown with the first page at %v", v.visible(), p[0].Bounds())
+	}
+	ctx.SetNow(t0.Add(pageTween))
+	ctx.LayoutRoot(v, bounds)
+	if v.slot != 0 || v.pos != 0 || p[0].Bounds() != bounds {
+		t.Errorf("settled at slot %d, %v", v.slot, v.pos)
+	}
+}
+
+func TestPageViewDrag(t *testing.T) {
+	tests := []struct {
+		name  string
+		loop  bool
+		start int
+		dx    float32
+		hold  time.Duration
+		pos   float32 // while dragging
+		page  int     // after release
+	}{
+		{"within the slop", false, 1, -pageDragSlop + 1, time.Second, 1, 1},
+		{"short and slow", false, 1, -60, time.Second, 1.2, 1},
+		{"past the middle", false, 1, -180, time.Second, 1.6, 2},
+		{"flick forward", false, 1, -30, pageFlick / 2, 1.1, 2},
+		{"flick back", false, 1, 30, pageFlick / 2, 0.9, 0},
+		{"before the start", false, 0, 150, time.Second, -0.5 / pageEdgeDamping, 0},
+		{"past the end", false, 2, -150, time.Second, 2 + 0.5/pageEdgeDamping, 2},
+		{"looping before the start", true, 0, 30, pageFlick / 2, -0.1, 2},
+	}
+	for _, tt := range tests {
+		v := NewPageView(pages(3)...).Loop(tt.loop)
+		v.SetPage(tt.start)
+		ctx := core.NewContext()
+		ctx.SetReducedMotion(true)
+		t0 := time.Now()
+		ctx.SetNow(t0)
+		bounds := core.R(0, 0, 300, 200)
+		ctx.LayoutRoot(v, bounds)
+
+		from := core.Pt(150, 50)
+		to := core.Pt(from.X+tt.dx, from.Y)
+		v.HandleEvent(ctx, leftMouse(event.MouseDown, from))
+		if ctx.PointerCapture() != v {
+			t.Fatalf("%s: a press was not captured", tt.name)
+		}
+		v.HandleEvent(ctx, event.MouseEvent{Type: event.MouseMove, Position: to})
+		ctx.LayoutRoot(v, bounds)
+		if abs32(v.pos-tt.pos) > 1e-5 {
+			t.Errorf("%s: dragged to %v, want %v", tt.name, v.pos, tt.pos)
+		}
+		ctx.SetNow(t0.Add(tt.hold))
+		v.HandleEvent(ctx, leftMouse(event.MouseUp, to))
+		ctx.LayoutRoot(v, bounds)
+		if v.Page() != tt.page || v.pos != float32(v.slot) || ctx.PointerCapture() != nil {
+			t.Errorf("%s: released onto page %d at %v, want %d", tt.name, v.Page(), v.pos, tt.page)
+		}
+	}
+
+	v := NewPageView(pages(3)...)
+	ctx := layoutAt(v, core.R(0, 0, 300, 200))
+	if r := v.HandleEvent(ctx, event.MouseEvent{Type: event.MouseMove, Position: core.Pt(10, 10)}); r != core.Ignored {
+		t.Error("a move without a press was handled")
+	}
+	if r := v.HandleEvent(ctx, event.MouseEvent{Type: event.MouseDown, Button: event.ButtonRight}); r != core.Ignored {
+		t.Error("a right press was handled")
+	}
+	if r := v.HandleEvent(ctx, leftMouse(event.MouseUp, core.Pt(10, 10))); r != core.Ignored {
+		t.Error("a release without a press was handled")
+	}
+}
+
+func TestPageViewScroll(t *testing.T) {
+	v := NewPageView(pages(3)...)
+	ctx := core.NewContext()
+	ctx.SetReducedMotion(true)
+	bounds := core.R(0, 0, 300, 200)
+	ctx.LayoutRoot(v, bounds)
+	tests := []struct {
+		name string
+		ev   event.ScrollEvent
+		res  core.EventResult
+		page int
+	}{
+		{"vertical", event.ScrollEvent{Delta: core.Pt(10, 20), Precise: true}, core.Ignored, 0},
+		{"touchpad", event.ScrollEvent{Delta: core.Pt(pageScrollStep/2, 0), Precise: true}, core.Handled, 0},
+		{"touchpad further", event.ScrollEvent{Delta: core.Pt(pageScrollStep/2, 0), Precise: true}, core.Handled, 1},
+		{"wheel", event.ScrollEvent{Delta: core.Pt(1, 0)}, core.Handled, 2},
+		{"wheel back", event.ScrollEvent{Delta: core.Pt(-1, 0)}, core.Handled, 1},
+	}
+	for _, tt := range tests {
+		if r := v.HandleEvent(ctx, tt.ev); r != tt.res || v.Page() != tt.page {
+			t.Errorf("%s: %v on page %d, want %v and %d", tt.name, r, v.Page(), tt.res, tt.page)
+		}
+		ctx.LayoutRoot(v, bounds)
+	}
+
+	// The page does not turn again while it is moving.
+	ctx.SetReducedMotion(false)
+	v.Next()
+	ctx.LayoutRoot(v, bounds)
+	if r := v.HandleEvent(ctx, event.ScrollEvent{Delta: core.Pt(1, 0)}); r != core.Handled || v.slot != 2 {
+		t.Errorf("scrolling while moving = %v, to slot %d", r, v.slot)
+	}
+}
+
+func TestPageViewKeys(t *testing.T) {
+	v := NewPageView(pages(3)...)
+	ctx := core.NewContext()
+	ctx.SetReducedMotion(true)
+	bounds := core.R(0, 0, 300, 200)
+	ctx.LayoutRoot(v, bounds)
+	if r := v.HandleEvent(ctx, press(event.KeyRight, 0)); r != core.Ignored {
+		t.Error("an unfocused view handled a key")
+	}
+	ctx.RequestFocus(v)
+	tests := []struct {
+		key  event.Key
+		page int
+	}{
+		{event.KeyRight, 1},
+		{event.KeyEnd, 2},
+		{event.KeyRight, 2},
+		{event.KeyLeft, 1},
+		{event.KeyHome, 0},
+	}
+	for _, tt := range tests {
+		if r := v.HandleEvent(ctx, press(tt.key, 0)); r != core.Handled || v.Page() != tt.page {
+			t.Errorf("after %v on page %d, want %d", tt.key, v.Page(), tt.page)
+		}
+	}
+	if r := v.HandleEvent(ctx, press(event.KeyRight, event.ModShift)); r != core.Ignored {
+		t.Error("Shift+Right was handled")
+	}
+	if r := v.HandleEvent(ctx, press(event.KeyDown, 0)); r != core.Ignored {
+		t.Error("Down was handled")
+	}
+}
+
+func TestPageViewRightToLeft(t *testing.T) {
+	v := NewPageView(pages(3)...)
+	v.SetPage(1)
+	ctx := core.NewContext()
+	ctx.SetReducedMotion(true)
+	ctx.SetDirection(core.RightToLeft)
+	bounds := core.R(0, 0, 300, 200)
+	ctx.LayoutRoot(v, bounds)
+
+	// Dragged right, the next page comes in from the left.
+	v.HandleEvent(ctx, leftMouse(event.MouseDown, core.Pt(100, 50)))
+	v.HandleEvent(ctx, event.MouseEvent{Type: event.MouseMove, Position: core.Pt(280, 50)})
+	ctx.LayoutRoot(v, bounds)
+	if abs32(v.pos-1.6) > 1e-5 {
+		t.Errorf("dragged to %v, want 1.6", v.pos)
+	}
+	if got := v.pages[2].Bounds().X; got != -120 {
+		t.Errorf("the next page at x %v, want -120", got)
+	}
+	v.HandleEvent(ctx, leftMouse(event.MouseUp, core.Pt(280, 50)))
+	ctx.LayoutRoot(v, bounds)
+	if v.Page() != 2 {
+		t.Errorf("released onto page %d, want 2", v.Page())
+	}
+
+	ctx.RequestFocus(v)
+	v.HandleEvent(ctx, press(event.KeyRight, 0))
+	if v.Page() != 1 {
+		t.Errorf("after Right on page %d, want 1", v.Page())
+	}
+	if first, last := v.dotRects[0], v.dotRects[2]; first.X <= last.X {
+		t.Errorf("the first dot at %v, want right of the last at %v", first, last)
+	}
+}
+
+func TestPageViewDots(t *testing.T) {
+	v := NewPageView(pages(3)...)
+	ctx := core.NewContext()
+	ctx.SetReducedMotion(true)
+	bounds := core.R(0, 0, 300, 200)
+	ctx.LayoutRoot(v, bounds)
+	if len(v.dotRects) != 3 {
+		t.Fatalf("%d dots", len(v.dotRects))
+	}
+	d := v.dotRects
+	if d[1].Center().X != 150 || d[0].Right() != d[1].X || d[1].Bottom() != 200 {
+		t.Errorf("the dots at %v, want centered along the bottom", d)
+	}
+	if r := v.HandleEvent(ctx, leftMouse(event.MouseDown, d[2].Center())); r != core.Handled || v.Page() != 2 || ctx.PointerCapture() != nil {
+		t.Errorf("a click on the last dot = %v, on page %d", r, v.Page())
+	}
+	ctx.LayoutRoot(v, bounds)
+
+	paint := func() int {
+		cv := &core.Recording{}
+		v.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+		return cv.Len()
+	}
+	withDots := paint()
+	ctx.RequestFocus(v)
+	if got := paint(); got != withDots+1 {
+		t.Errorf("focused drew %d ops, want %d with the ring", got, withDots+1)
+	}
+	v.ShowIndicators(false)
+	ctx.LayoutRoot(v, core.R(0, 0, 300, 201))
+	if got := paint(); got != withDots-3 || len(v.dotRects) != 0 {
+		t.Errorf("without dots drew %d ops, want %d", got, withDots-3)
+	}
+	single := NewPageView(pages(1)...)
+	layoutAt(single, bounds)
+	if len(single.dotRects) != 0 {
+		t.Error("a single page shows a dot")
+	}
+}
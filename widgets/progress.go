@@ -0,0 +1,237 @@
+package widgets
+
+import (
+	"math"
+	"time"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/state"
+	"github.com/gogpu/ui/theme"
+)
+
+// Progress indicator metrics and timing.
+const (
+	progressHeight = 4
+	progressLength = 200
+	progressTween  = 250 * time.Millisecond
+	progressCycle  = 1800 * time.Millisecond
+
+	spinnerSize   = 24
+	spinnerStroke = 3
+	spinnerTurn   = 1400 * time.Millisecond
+	spinnerCycle  = 5 * spinnerTurn / 4
+)
+
+// phase returns how far now is through a repeating period, from 0 to 1.
+// It is derived from the frame clock rather than a start time so that all
+// indicators of a kind move in step.
+func phase(now time.Time, period time.Duration) float64 {
+	return float64(now.UnixNano()%int64(period)) / float64(period)
+}
+
+// ProgressBar is a horizontal bar showing how much of a task is done.
+//
+// The value runs from 0 to 1 and changes glide to the new length. A buffer
+// value, drawn lighter behind the bar, shows a secondary amount such as
+// how much of a stream has been downloaded ahead of playback. An
+// indeterminate bar sweeps continuously instead, for tasks of unknown
+// length, and keeps the window drawing frames while it is shown.
+type ProgressBar struct {
+	core.WidgetBase
+
+	value, buffer float64
+	indeterminate bool
+	sig           *state.Signal[float64]
+	cancel        func()
+
+	// Animation of the bar from shownFrom to value.
+	shownFrom float64
+	shown     float64
+	changedAt time.Time
+}
+
+// NewProgressBar returns an empty determinate ProgressBar.
+func NewProgressBar() *ProgressBar {
+	return &ProgressBar{}
+}
+
+// Indeterminate switches to an endlessly sweeping bar for tasks whose
+// length is not known.
+func (p *ProgressBar) Indeterminate(on bool) *ProgressBar {
+	p.indeterminate = on
+	return p
+}
+
+// Bind makes the bar show the value of sig.
+func (p *ProgressBar) Bind(sig *state.Signal[float64]) *ProgressBar {
+	p.Release()
+	p.sig = sig
+	return p
+}
+
+// Release stops following the bound signal, for when the bar leaves
+// the tree. Laid out again, the bar follows the signal from its value
+// then.
+func (p *ProgressBar) Release() {
+	if p.cancel != nil {
+		p.cancel()
+		p.cancel = nil
+	}
+}
+
+// Value returns the progress from 0 to 1.
+func (p *ProgressBar) Value() float64 {
+	return p.value
+}
+
+// SetValue sets the progress, clamped to 0 to 1.
+func (p *ProgressBar) SetValue(v float64) {
+	v = math.Max(0, math.Min(v, 1))
+	if v == p.value {
+		return
+	}
+	p.shownFrom, p.value = p.shown, v
+	p.changedAt = time.Time{}
+	if p.sig != nil {
+		p.sig.Set(v)
+	}
+}
+
+// Buffer returns the secondary value from 0 to 1.
+func (p *ProgressBar) Buffer() float64 {
+	return p.buffer
+}
+
+// SetBuffer sets the secondary value, clamped to 0 to 1.
+func (p *ProgressBar) SetBuffer(v float64) {
+	p.buffer = math.Max(0, math.Min(v, 1))
+}
+
+// Layout implements core.Widget.
+func (p *ProgressBar) Layout(ctx *core.LayoutContext) core.Size {
+	if p.sig != nil {
+		if p.cancel == nil {
+			c := ctx.Context
+			p.cancel = p.sig.Subscribe(func(float64) { c.Post(func() { c.MarkNeedsLayout(p) }) })
+		}
+		p.SetValue(p.sig.Get())
+	}
+	switch {
+	case p.indeterminate:
+	case p.shown != p.value:
+		if p.changedAt.IsZero() {
+			p.changedAt = ctx.Now()
+		}
+		t := float32(ctx.Now().Sub(p.changedAt)) / float32(progressTween)
+		if t >= 1 || ctx.ReducedMotion() {
+			p.shown = p.value
+		} else {
+			p.shown = p.shownFrom + (p.value-p.shownFrom)*float64(easeOut(max(t, 0)))
+			ctx.MarkNeedsLayout(p)
+		}
+	}
+	w := float32(progressLength)
+	if ctx.Constraints.HasBoundedWidth() {
+		w = ctx.Constraints.MaxWidth
+	}
+	return ctx.Constraints.Constrain(core.Sz(w, progressHeight))
+}
+
+// Paint implements core.Widget.
+func (p *ProgressBar) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	b := p.Bounds()
+	r := b.Height / 2
+	cv.DrawRoundedRect(b, r, core.Filled(th.Colors.SurfaceVariant))
+	span := func(from, to float64, color core.Color) {
+		from, to = math.Max(from, 0), math.Min(to, 1)
+		if to <= from {
+			return
+		}
+		x0, x1 := b.X+float32(from)*b.Width, b.X+float32(to)*b.Width
+		cv.DrawRoundedRect(core.R(x0, b.Y, x1-x0, b.Height), r, core.Filled(color))
+	}
+	if p.indeterminate {
+		ctx.Repaint(p)
+		cv.Save()
+		cv.Clip(b)
+		if ctx.ReducedMotion() {
+			// A slow pulse instead of a sweep.
+			a := 0.35 + 0.25*math.Sin(2*math.Pi*phase(ctx.Now(), 2*progressCycle))
+			span(0, 1, th.Colors.Primary.WithAlpha(float32(a)))
+		} else {
+			// Two segments chase each other across the track.
+			t := phase(ctx.Now(), progressCycle)
+			head := float64(easeOut(float32(t)))*1.6 - 0.2
+			span(head-0.3-0.2*t, head, th.Colors.Primary)
+			t2 := math.Mod(t+0.5, 1)
+			head2 := float64(easeOut(float32(t2)))*1.6 - 0.2
+			span(head2-0.15, head2, th.Colors.Primary)
+		}
+		cv.Restore()
+		return
+	}
+	span(0, p.buffer, th.Colors.Primary.WithAlpha(0.3))
+	span(0, p.shown, th.Colors.Primary)
+}
+
+// Spinner is a circular indicator of activity of unknown length. While it
+// is drawn it keeps the window repainting it, so it spins even when
+// nothing else changes.
+type Spinner struct {
+	core.WidgetBase
+
+	size float32
+}
+
+// NewSpinner returns a Spinner of the default size.
+func NewSpinner() *Spinner {
+	return &Spinner{size: spinnerSize}
+}
+
+// Size sets the spinner's diameter.
+func (s *Spinner) Size(d float32) *Spinner {
+	s.size = max(d, 2*spinnerStroke)
+	return s
+}
+
+// Layout implements core.Widget.
+func (s *Spinner) Layout(ctx *core.LayoutContext) core.Size {
+	return ctx.Constraints.Constrain(core.Sz(s.size, s.size))
+}
+
+// Paint implements core.Widget.
+func (s *Spinner) Paint(ctx *core.PaintContext) {
+	ctx.Repaint(s)
+	th := theme.From(ctx.Context)
+	b := s.Bounds()
+	d := min(b.Width, b.Height)
+	c := b.Center()
+	r := core.R(c.X-d/2, c.Y-d/2, d, d).Inset(core.UniformInsets(spinnerStroke / 2))
+
+	now := ctx.Now()
+	start := 2 * math.Pi * phase(now, spinnerTurn)
+	sweep := 0.75 * 2 * math.Pi
+	if !ctx.ReducedMotion() {
+		// The arc grows and shrinks while the whole spinner turns; the tail
+		// catches up during the shrink so the arc never reverses.
+		t := phase(now, spinnerCycle)
+		grow := float64(easeInOut(float32(math.Min(2*t, 1))))
+		shrink := float64(easeInOut(float32(math.Max(2*t-1, 0))))
+		const minSweep, maxSweep = 0.1 * math.Pi, 1.5 * math.Pi
+		sweep = minSweep + (maxSweep-minSweep)*(grow-shrink)
+		cycles := float64(now.UnixNano() / int64(spinnerCycle))
+		start += math.Mod((maxSweep-minSweep)*(cycles+shrink), 2*math.Pi)
+	}
+	path := core.NewPath().AddArc(r, start-math.Pi/2, sweep)
+	ctx.Canvas.DrawPath(path, core.PathStyle{Stroke: th.Colors.Primary, StrokeWidth: spinnerStroke, LineCap: core.CapRound})
+}
+
+func easeInOut(t float32) float32 {
+	if t < 0.5 {
+		return 4 * t * t * t
+	}
+	u := -2*t + 2
+	return 1 - u*u*u/2
+}
@@ -0,0 +1,175 @@
+package widgets
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/state"
+)
+
+var progressBounds = core.R(0, 0, 100, progressHeight)
+
+// paintAt paints w at time now and returns what it drew. It reports
+// whether w asked to be painted again.
+func paintAt(ctx *core.Context, w core.Widget, now time.Time) (*core.Recording, bool) {
+	ctx.SetNow(now)
+	ctx.ClearRedraw()
+	rec := &core.Recording{}
+	w.Paint(&core.PaintContext{Context: ctx, Canvas: rec})
+	return rec, ctx.NeedsRedraw()
+}
+
+func TestProgressBarSetValue(t *testing.T) {
+	tests := []struct {
+		value, buffer   float64
+		wantV, wantBuff float64
+	}{
+		{0.4, 0.6, 0.4, 0.6},
+		{-1, -1, 0, 0},
+		{2, 3, 1, 1},
+	}
+	for _, tt := range tests {
+		p := NewProgressBar()
+		p.SetValue(tt.value)
+		p.SetBuffer(tt.buffer)
+		if p.Value() != tt.wantV || p.Buffer() != tt.wantBuff {
+			t.Errorf("SetValue(%v), SetBuffer(%v) gave %v, %v", tt.value, tt.buffer, p.Value(), p.Buffer())
+		}
+	}
+}
+
+func TestProgressBarGlides(t *testing.T) {
+	ctx := core.NewContext()
+	t0 := time.Now()
+	ctx.SetNow(t0)
+	p := NewProgressBar()
+	ctx.LayoutRoot(p, progressBounds)
+	p.SetValue(0.5)
+	ctx.Invalidate()
+	ctx.ClearRedraw()
+	ctx.LayoutRoot(p, progressBounds)
+	if p.shown != 0 || !ctx.NeedsRedraw() {
+		t.Fatalf("shown %v at the start of the change", p.shown)
+	}
+	ctx.SetNow(t0.Add(progressTween / 2))
+	ctx.LayoutRoot(p, progressBounds)
+	if p.shown <= 0.25 || p.shown >= 0.5 {
+		t.Errorf("shown %v halfway through, want eased past the middle", p.shown)
+	}
+	ctx.SetNow(t0.Add(progressTween))
+	ctx.ClearRedraw()
+	ctx.LayoutRoot(p, progressBounds)
+	if p.shown != 0.5 || ctx.NeedsRedraw() {
+		t.Errorf("shown %v at the end, redraw %v", p.shown, ctx.NeedsRedraw())
+	}
+
+	// A change during a change starts from where the bar is drawn.
+	p.SetValue(1)
+	ctx.SetNow(t0.Add(progressTween * 3 / 2))
+	ctx.Invalidate()
+	ctx.LayoutRoot(p, progressBounds)
+	p.SetValue(0)
+	if p.shownFrom != 0.5 {
+		t.Errorf("the next change starts from %v, want 0.5", p.shownFrom)
+	}
+
+	ctx.SetReducedMotion(true)
+	p.SetValue(0.8)
+	ctx.Invalidate()
+	ctx.LayoutRoot(p, progressBounds)
+	if p.shown != 0.8 {
+		t.Errorf("shown %v with reduced motion, want the value at once", p.shown)
+	}
+}
+
+func TestProgressBarBind(t *testing.T) {
+	sig := state.New(0.25)
+	p := NewProgressBar().Bind(sig)
+	ctx := core.NewContext()
+	ctx.SetReducedMotion(true)
+	ctx.LayoutRoot(p, progressBounds)
+	if p.Value() != 0.25 {
+		t.Errorf("Value() = %v, want the signal's 0.25", p.Value())
+	}
+	sig.Set(0.75)
+	ctx.RunPosted()
+	ctx.LayoutRoot(p, progressBounds)
+	if p.Value() != 0.75 || p.shown != 0.75 {
+		t.Errorf("Value() = %v after the signal changed, want 0.75", p.Value())
+	}
+	p.SetValue(2)
+	if sig.Get() != 1 {
+		t.Errorf("SetValue stored %v in the signal", sig.Get())
+	}
+}
+
+func TestActivityIndicatorsAnimate(t *testing.T) {
+	tests := []struct {
+		name   string
+		w      core.Widget
+		bounds core.Rect
+	}{
+		{"indeterminate bar", NewProgressBar().Indeterminate(true), progressBounds},
+		{"spinner", NewSpinner(), core.R(0, 0, spinnerSize, spinnerSize)},
+	}
+	for _, tt := range tests {
+		for _, reduced := range []bool{false, true} {
+			ctx := core.NewContext()
+			ctx.SetReducedMotion(reduced)
+			ctx.LayoutRoot(tt.w, tt.bounds)
+			t0 := time.Unix(0, 0)
+			a, again := paintAt(ctx, tt.w, t0)
+			if !again {
+				t.Errorf("%s: painting did not ask for the next frame", tt.name)
+			}
+			b, _ := paintAt(ctx, tt.w, t0.Add(100*time.Millisecond))
+			if a.Equal(b) {
+				t.Errorf("%s, reduced motion %v: the same drawing 100ms later", tt.name, reduced)
+			}
+		}
+	}
+
+	p := NewProgressBar()
+	p.SetValue(0.5)
+	ctx := core.NewContext()
+	ctx.LayoutRoot(p, progressBounds)
+	if _, again := paintAt(ctx, p, time.Now()); again {
+		t.Error("a determinate bar keeps asking for frames")
+	}
+}
+
+func TestSpinnerSize(t *testing.T) {
+	tests := []struct {
+		d, want float32
+	}{
+		{40, 40},
+		{1, 2 * spinnerStroke},
+	}
+	for _, tt := range tests {
+		if s := NewSpinner().Size(tt.d); s.size != tt.want {
+			t.Errorf("Size(%v) gave %v, want %v", tt.d, s.size, tt.want)
+		}
+	}
+}
+
+func TestPhase(t *testing.T) {
+	tests := []struct {
+		at   time.Duration
+		want float64
+	}{
+		{0, 0},
+		{progressCycle / 4, 0.25},
+		{progressCycle * 5 / 2, 0.5},
+	}
+	for _, tt := range tests {
+		if got := phase(time.Unix(0, int64(tt.at)), progressCycle); got != tt.want {
+			t.Errorf("phase at %v = %v, want %v", tt.at, got, tt.want)
+		}
+	}
+	for _, f := range []func(float32) float32{easeOut, easeInOut} {
+		if f(0) != 0 || f(1) != 1 || f(0.5) <= 0 || f(0.5) >= 1 {
+			t.Errorf("an easing does not run from 0 to 1: %v, %v, %v", f(0), f(0.5), f(1))
+		}
+	}
+}
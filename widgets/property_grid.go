@@ -0,0 +1,720 @@
+package widgets
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/internal/scroll"
+	"github.com/gogpu/ui/state"
+	"github.com/gogpu/ui/theme"
+)
+
+// PropertyGrid metrics.
+const (
+	propertyRowHeight    float32 = 32
+	propertyHeaderHeight float32 = 28
+	propertyGridWidth    float32 = 360
+	propertySplitGrab    float32 = 4
+	propertyMinColumn    float32 = 60
+)
+
+// Property is a row of a PropertyGrid: a name, the category it is grouped
+// under, if any, and the widget editing the value.
+type Property struct {
+	Name     string
+	Category string
+	Editor   core.Widget
+
+	watch   []func() (cancel func()) // Subscribe a reflected row to the signals of its struct.
+	cancels []func()                 // End those subscriptions.
+}
+
+// follow makes the subscriptions of a reflected row, unless it has them.
+func (p *Property) follow() {
+	if p.cancels != nil {
+		return
+	}
+	for _, w := range p.watch {
+		p.cancels = append(p.cancels, w())
+	}
+}
+
+// release ends the subscriptions of the row and of its editor.
+func (p *Property) release() {
+	for _, cancel := range p.cancels {
+		cancel()
+	}
+	p.cancels = nil
+	if r, ok := p.Editor.(interface{ Release() }); ok {
+		r.Release()
+	}
+}
+
+// PropertyGrid is an inspector: a two-column list of named values, each
+// with its own editor, grouped under collapsible category headers.
+//
+// Rows come either from a schema, Properties given to Add, or from
+// reflection over a struct with Reflect, which picks the editor by the
+// type of each field: a Switch for bool, a TextField for string, a
+// restricted ComboBox for a string with options, a NumberInput for
+// numbers and a color swatch opening a ColorPicker for core.Color.
+// Fields may hold the value or a *state.Signal of it; edits are written
+// back through the signal, so other widgets bound to it follow.
+//
+// Rows without a category come first. The divider between the columns
+// can be dragged.
+type PropertyGrid struct {
+	core.WidgetBase
+
+	props     []Property
+	collapsed map[string]bool
+	split     float32 // the name column's share of the width
+	onChange  func(name string)
+	refresh   []func()
+
+	items   []propertyItem
+	visible []core.Widget
+	content float32
+	scrollY float32
+	bar     scroll.Bar
+	body    core.Rect
+	pad     float32
+	drag    bool
+	hover   int
+}
+
+// propertyItem is a laid out row: a category header, or the property at
+// index prop.
+type propertyItem struct {
+	header string
+	prop   int
+	height float32
+	editor float32 // the height of the editor
+}
+
+// NewPropertyGrid returns a PropertyGrid showing props.
+func NewPropertyGrid(props ...Property) *PropertyGrid {
+	return &PropertyGrid{props: props, collapsed: make(map[string]bool), split: 0.4, hover: -1}
+}
+
+// Add appends rows.
+func (g *PropertyGrid) Add(props ...Property) *PropertyGrid {
+	g.props = append(g.props, props...)
+	return g
+}
+
+// Properties returns the rows shown.
+func (g *PropertyGrid) Properties() []Property {
+	return g.props
+}
+
+// Clear removes every row, ending the subscriptions of the reflected ones
+// to the signals of their struct.
+func (g *PropertyGrid) Clear() {
+	g.Release()
+	g.props, g.refresh = nil, nil
+	g.SetChildren()
+}
+
+// Release stops following the signals of the rows and of their editors,
+// for when the grid leaves the tree. Laid out again, the grid follows the
+// signals from their values then.
+func (g *PropertyGrid) Release() {
+	for i := range g.props {
+		g.props[i].release()
+	}
+}
+
+// SetObject replaces the rows with those of the struct v points to, as
+// Clear followed by Reflect, for an inspector following the selection.
+func (g *PropertyGrid) SetObject(v any) *PropertyGrid {
+	g.Clear()
+	return g.Reflect(v)
+}
+
+// NameWidth sets the share of the width, between 0 and 1, taken by the
+// column of names. The default is 0.4.
+func (g *PropertyGrid) NameWidth(fraction float32) *PropertyGrid {
+	g.split = core.Clamp(fraction, 0.1, 0.9)
+	return g
+}
+
+// OnChange sets the function called with the name of a reflected field
+// after its value changes.
+func (g *PropertyGrid) OnChange(fn func(name string)) *PropertyGrid {
+	g.onChange = fn
+	return g
+}
+
+// SetCollapsed collapses or expands the rows of a category.
+func (g *PropertyGrid) SetCollapsed(category string, collapsed bool) {
+	g.collapsed[category] = collapsed
+}
+
+// Reflect appends a row for every exported field of the struct v points
+// to. It panics if v is not a non-nil pointer to a struct.
+//
+// The prop tag of a field refines its row, as a comma-separated list
+// starting with the name, which otherwise is derived from the field's:
+//
+//	Width   float64 `prop:"Width,category=Layout,min=0,max=1000,step=1"`
+//	Align   string  `prop:",options=left|center|right"`
+//	Opacity *state.Signal[float64] `prop:",min=0,max=1,step=0.05,precision=2"`
+//	Secret  string  `prop:"-"`
+//
+// Fields of struct types other than core.Color are flattened, their rows
+// under a category named after the field. Fields held by value are read
+// when added and after Refresh.
+func (g *PropertyGrid) Reflect(v any) *PropertyGrid {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("widgets: PropertyGrid.Reflect of %T, not a pointer to a struct", v))
+	}
+	g.reflectStruct(rv.Elem(), "")
+	return g
+}
+
+// Refresh reads the fields held by value again, after the app changed
+// them directly.
+func (g *PropertyGrid) Refresh() {
+	for _, fn := range g.refresh {
+		fn()
+	}
+}
+
+// propertyTag is a parsed prop tag.
+type propertyTag struct {
+	name, category string
+	options        []string
+	min, max, step float64
+	limited        bool
+	precision      int
+}
+
+func parsePropertyTag(tag string) (propertyTag, bool) {
+	t := propertyTag{precision: -1}
+	if tag == "-" {
+		return t, false
+	}
+	parts := strings.Split(tag, ",")
+	t.name = strings.TrimSpace(parts[0])
+	t.min, t.max = math.Inf(-1), math.Inf(1)
+	for _, p := range parts[1:] {
+		key, val, _ := strings.Cut(strings.TrimSpace(p), "=")
+		num, err := strconv.ParseFloat(val, 64)
+		switch key {
+		case "category":
+			t.category = val
+		case "options":
+			t.options = strings.Split(val, "|")
+		case "min":
+			if err == nil {
+				t.min, t.limited = num, true
+			}
+		case "max":
+			if err == nil {
+				t.max, t.limited = num, true
+			}
+		case "step":
+			if err == nil {
+				t.step = num
+			}
+		case "precision":
+			if err == nil {
+				t.precision = int(num)
+			}
+		}
+	}
+	return t, true
+}
+
+// fieldLabel turns a Go field name such as BorderWidth into Border Width.
+func fieldLabel(name string) string {
+	var b strings.Builder
+	rs := []rune(name)
+	for i, r := range rs {
+		if i > 0 && unicode.IsUpper(r) && (unicode.IsLower(rs[i-1]) || i+1 < len(rs) && unicode.IsLower(rs[i+1])) {
+			b.WriteByte(' ')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+var colorType = reflect.TypeFor[core.Color]()
+
+func (g *PropertyGrid) reflectStruct(sv reflect.Value, category string) {
+	st := sv.Type()
+	for i := range st.NumField() {
+		f := st.Field(i)
+		// The exported fields of an embedded struct are promoted even
+		// when its type is not.
+		if !f.IsExported() && !(f.Anonymous && f.Type.Kind() == reflect.Struct) {
+			continue
+		}
+		tag, ok := parsePropertyTag(f.Tag.Get("prop"))
+		if !ok {
+			continue
+		}
+		if tag.name == "" {
+			tag.name = fieldLabel(f.Name)
+		}
+		if tag.category == "" {
+			tag.category = category
+		}
+		fv := sv.Field(i)
+		if f.Type.Kind() == reflect.Struct && f.Type != colorType {
+			sub := tag.category
+			if !f.Anonymous {
+				sub = tag.name
+			}
+			g.reflectStruct(fv, sub)
+			continue
+		}
+		if ed, watch := g.fieldEditor(fv, tag); ed != nil {
+			g.props = append(g.props, Property{Name: tag.name, Category: tag.category, Editor: ed, watch: watch})
+		}
+	}
+}
+
+// fieldEditor returns the editor of a field, or nil for unsupported types,
+// and the functions making its subscriptions, which the grid makes while
+// it is in the tree.
+func (g *PropertyGrid) fieldEditor(fv reflect.Value, tag propertyTag) (ed core.Widget, watch []func() func()) {
+	changed := func() {
+		if g.onChange != nil {
+			g.onChange(tag.name)
+		}
+	}
+	keep := func(subscribe func() (cancel func())) {
+		watch = append(watch, subscribe)
+	}
+	if fv.Kind() == reflect.Pointer && fv.IsNil() {
+		return nil, nil
+	}
+	switch sig := fv.Interface().(type) {
+	case *state.Signal[bool]:
+		keep(func() func() { return sig.Subscribe(func(bool) { changed() }) })
+		return NewSwitch("").Bind(sig), watch
+	case *state.Signal[string]:
+		keep(func() func() { return sig.Subscribe(func(string) { changed() }) })
+		return stringEditor(sig, tag), watch
+	case *state.Signal[float64]:
+		keep(func() func() { return sig.Subscribe(func(float64) { changed() }) })
+		return numberEditor(tag, false).Bind(sig), watch
+	case *state.Signal[int]:
+		keep(func() func() { return sig.Subscribe(func(int) { changed() }) })
+		return numberEditor(tag, true).Bind(g.mirror(keep,
+			func() float64 { return float64(sig.Get()) },
+			func(v float64) { sig.Set(int(math.Round(v))) },
+			func(update func()) {
+				keep(func() func() {
+					update()
+					return sig.Subscribe(func(int) { update() })
+				})
+			},
+		)), watch
+	case *state.Signal[core.Color]:
+		keep(func() func() { return sig.Subscribe(func(core.Color) { changed() }) })
+		return newColorWell(sig), watch
+	}
+	if !fv.CanSet() {
+		return nil, nil
+	}
+	// Fields held by value are edited through a signal mirroring them.
+	switch {
+	case fv.Type() == colorType:
+		sig := state.New(fv.Interface().(core.Color))
+		g.refresh = append(g.refresh, func() { sig.Set(fv.Interface().(core.Color)) })
+		keep(func() func() { return sig.Subscribe(func(c core.Color) { fv.Set(reflect.ValueOf(c)); changed() }) })
+		return newColorWell(sig), watch
+	case fv.Kind() == reflect.Bool:
+		sig := state.New(fv.Bool())
+		g.refresh = append(g.refresh, func() { sig.Set(fv.Bool()) })
+		keep(func() func() { return sig.Subscribe(func(b bool) { fv.SetBool(b); changed() }) })
+		return NewSwitch("").Bind(sig), watch
+	case fv.Kind() == reflect.String:
+		sig := state.New(fv.String())
+		g.refresh = append(g.refresh, func() { sig.Set(fv.String()) })
+		keep(func() func() { return sig.Subscribe(func(s string) { fv.SetString(s); changed() }) })
+		return stringEditor(sig, tag), watch
+	case fv.CanFloat():
+		return numberEditor(tag, false).Bind(g.mirror(keep,
+			fv.Float,
+			func(v float64) { fv.SetFloat(v); changed() },
+			func(update func()) { g.refresh = append(g.refresh, update) },
+		)), watch
+	case fv.CanInt():
+		return numberEditor(tag, true).Bind(g.mirror(keep,
+			func() float64 { return float64(fv.Int()) },
+			func(v float64) { fv.SetInt(int64(math.Round(v))); changed() },
+			func(update func()) { g.refresh = append(g.refresh, update) },
+		)), watch
+	case fv.CanUint():
+		return numberEditor(tag, true).Bind(g.mirror(keep,
+			func() float64 { return float64(fv.Uint()) },
+			func(v float64) { fv.SetUint(uint64(max(0, math.Round(v)))); changed() },
+			func(update func()) { g.refresh = append(g.refresh, update) },
+		)), watch
+	}
+	return nil, nil
+}
+
+// mirror returns a float64 signal for a numeric value of another type:
+// edits are stored with set, and watch registers the update that reads
+// the value again with get when it changes. keep is given the function
+// making the subscription storing the edits.
+func (g *PropertyGrid) mirror(keep func(subscribe func() (cancel func())), get func() float64, set func(float64), watch func(update func())) *state.Signal[float64] {
+	sig := state.New(get())
+	keep(func() func() {
+		return sig.Subscribe(func(v float64) {
+			if v != get() {
+				set(v)
+			}
+		})
+	})
+	watch(func() { sig.Set(get()) })
+	return sig
+}
+
+// stringEditor returns the editor of a string bound to sig.
+func stringEditor(sig *state.Signal[string], tag propertyTag) core.Widget {
+	if len(tag.options) > 0 {
+		return NewComboBox(tag.options...).Restricted(true).Bind(sig)
+	}
+	return NewTextField().Bind(sig)
+}
+
+func numberEditor(tag propertyTag, integer bool) *NumberInput {
+	n := NewNumberInput()
+	if tag.limited {
+		n.Limits(tag.min, tag.max)
+	}
+	if tag.step > 0 {
+		n.Step(tag.step)
+	}
+	switch {
+	case integer:
+		n.Precision(0)
+	case tag.precision >= 0:
+		n.Precision(tag.precision)
+	}
+	return n
+}
+
+// build lays the rows out in order: the rows without a category, then
+// every category in order of first appearance.
+func (g *PropertyGrid) build() {
+	g.items = g.items[:0]
+	var categories []string
+	rows := make(map[string][]int)
+	for i, p := range g.props {
+		if _, ok := rows[p.Category]; !ok && p.Category != "" {
+			categories = append(categories, p.Category)
+		}
+		rows[p.Category] = append(rows[p.Category], i)
+	}
+	for _, i := range rows[""] {
+		g.items = append(g.items, propertyItem{prop: i})
+	}
+	for _, c := range categories {
+		g.items = append(g.items, propertyItem{header: c, prop: -1, height: propertyHeaderHeight})
+		if g.collapsed[c] {
+			continue
+		}
+		for _, i := range rows[c] {
+			g.items = append(g.items, propertyItem{prop: i})
+		}
+	}
+}
+
+// columns returns the x of the divider and the width of the value column
+// within the body.
+func (g *PropertyGrid) columns(body core.Rect, pad float32) (divider, value float32) {
+	divider = body.X + core.Clamp(body.Width*g.split, propertyMinColumn, max(propertyMinColumn, body.Width-propertyMinColumn))
+	return divider, max(0, body.Right()-divider-2*pad)
+}
+
+// Layout implements core.Widget.
+func (g *PropertyGrid) Layout(ctx *core.LayoutContext) core.Size {
+	th := theme.From(ctx.Context)
+	for i := range g.props {
+		g.props[i].follow()
+	}
+	g.build()
+	c := ctx.Constraints
+	w := propertyGridWidth
+	if c.HasBoundedWidth() {
+		w = c.MaxWidth
+	}
+	g.pad = th.Spacing.M
+	_, vw := g.columns(core.R(0, 0, w-scroll.Thickness, 0), g.pad)
+	g.visible = g.visible[:0]
+	g.content = 0
+	for i := range g.items {
+		it := &g.items[i]
+		if it.prop >= 0 {
+			ed := g.props[it.prop].Editor
+			sz := ctx.Measure(ed, core.Constraints{MaxWidth: vw, MaxHeight: core.Infinity})
+			it.editor = sz.Height
+			it.height = max(propertyRowHeight, sz.Height+2*th.Spacing.S)
+			g.visible = append(g.visible, ed)
+		}
+		g.content += it.height
+	}
+	g.SetChildren(g.visible...)
+	h := g.content
+	if c.HasBoundedHeight() {
+		h = c.MaxHeight
+	}
+	return c.Constrain(core.Sz(w, h))
+}
+
+// SetBounds implements core.Widget.
+func (g *PropertyGrid) SetBounds(r core.Rect) {
+	g.WidgetBase.SetBounds(r)
+	g.body = core.R(r.X, r.Y, max(0, r.Width-scroll.Thickness), r.Height)
+	g.bar.Track = core.R(g.body.Right(), r.Y, scroll.Thickness, r.Height)
+	g.bar.Viewport = r.Height
+	g.bar.Content = g.content
+	g.scrollY = core.Clamp(g.scrollY, 0, g.bar.MaxOffset())
+	g.arrange()
+}
+
+// arrange places the editors for the scroll offset.
+func (g *PropertyGrid) arrange() {
+	divider, vw := g.columns(g.body, g.pad)
+	y := g.body.Y - g.scrollY
+	for _, it := range g.items {
+		if it.prop >= 0 {
+			ed := g.props[it.prop].Editor
+			ed.SetBounds(core.R(divider+g.pad, y+(it.height-it.editor)/2, vw, it.editor))
+		}
+		y += it.height
+	}
+}
+
+// Paint implements core.Widget.
+func (g *PropertyGrid) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	b := g.Bounds()
+	cv.Save()
+	cv.Clip(b)
+	cv.DrawRect(b, core.Filled(th.Colors.Surface))
+	pad := g.pad
+	divider, _ := g.columns(g.body, pad)
+	name := theme.TextStyle(th.Typography.Body, th.Colors.OnSurfaceVariant)
+	header := theme.TextStyle(th.Typography.Label, th.Colors.OnSurface)
+	line := core.Filled(th.Colors.Outline.WithAlpha(0.25))
+	y := g.body.Y - g.scrollY
+	for i, it := range g.items {
+		r := core.R(g.body.X, y, g.body.Width, it.height)
+		y += it.height
+		if r.Bottom() < b.Y || r.Y > b.Bottom() {
+			continue
+		}
+		if it.prop < 0 {
+			cv.DrawRect(r, core.Filled(th.Colors.SurfaceVariant))
+			paintDisclosure(ctx, core.R(r.X+pad/2, r.Y, disclosureWidth, r.Height), !g.collapsed[it.header], th.Colors.OnSurfaceVariant)
+			sz := ctx.MeasureText(it.header, header)
+			cv.DrawText(it.header, core.Pt(r.X+pad/2+disclosureWidth+th.Spacing.S, r.Y+(r.Height-sz.Height)/2), header)
+			continue
+		}
+		if i == g.hover {
+			cv.DrawRect(r, core.Filled(th.Colors.OnSurface.WithAlpha(0.04)))
+		}
+		indent := pad
+		if g.props[it.prop].Category != "" {
+			indent += disclosureWidth
+		}
+		cv.Save()
+		cv.Clip(core.R(r.X, r.Y, divider-r.X-th.Spacing.S, r.Height))
+		label := g.props[it.prop].Name
+		sz := ctx.MeasureText(label, name)
+		cv.DrawText(label, core.Pt(r.X+indent, r.Y+(r.Height-sz.Height)/2), name)
+		cv.Restore()
+		cv.DrawRect(core.R(r.X, r.Bottom()-1, r.Width, 1), line)
+		cv.DrawRect(core.R(divider, r.Y, 1, r.Height), line)
+		g.props[it.prop].Editor.Paint(ctx)
+	}
+	g.bar.Paint(ctx, g.scrollY)
+	cv.Restore()
+}
+
+// itemAt returns the index of the row at p, or -1.
+func (g *PropertyGrid) itemAt(p core.Point) int {
+	if !g.body.Contains(p) {
+		return -1
+	}
+	y := g.body.Y - g.scrollY
+	for i, it := range g.items {
+		if p.Y >= y && p.Y < y+it.height {
+			return i
+		}
+		y += it.height
+	}
+	return -1
+}
+
+// HandleEvent implements core.Widget. Events the editors do not handle
+// bubble here.
+func (g *PropertyGrid) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	if off, ok := g.bar.HandleEvent(ctx, g, ev, g.scrollY); ok {
+		if off != g.scrollY {
+			g.scrollY = off
+			g.arrange()
+			ctx.Repaint(g)
+		}
+		return core.Handled
+	}
+	switch e := ev.(type) {
+	case event.ScrollEvent:
+		old := g.scrollY
+		g.scrollY = core.Clamp(g.scrollY+e.Delta.Y, 0, g.bar.MaxOffset())
+		if old != g.scrollY {
+			g.arrange()
+			ctx.Repaint(g)
+			return core.Handled
+		}
+	case event.MouseEvent:
+		return g.handleMouse(ctx, e)
+	}
+	return core.Ignored
+}
+
+func (g *PropertyGrid) handleMouse(ctx *core.Context, e event.MouseEvent) core.EventResult {
+	divider, _ := g.columns(g.body, g.pad)
+	switch e.Type {
+	case event.MouseMove:
+		if g.drag {
+			if g.body.Width > 0 {
+				g.split = core.Clamp((e.Position.X-g.body.X)/g.body.Width, 0.1, 0.9)
+				g.arrange()
+				ctx.Repaint(g)
+			}
+			return core.Handled
+		}
+		if i := g.itemAt(e.Position); i != g.hover {
+			g.hover = i
+			ctx.Repaint(g)
+		}
+	case event.MouseLeave:
+		if g.hover >= 0 {
+			g.hover = -1
+			ctx.Repaint(g)
+		}
+	case event.MouseDown:
+		if e.Button != event.ButtonLeft {
+			return core.Ignored
+		}
+		i := g.itemAt(e.Position)
+		if i < 0 {
+			return core.Ignored
+		}
+		if it := g.items[i]; it.prop < 0 {
+			g.collapsed[it.header] = !g.collapsed[it.header]
+			ctx.MarkNeedsLayout(g)
+			return core.Handled
+		}
+		if math.Abs(float64(e.Position.X-divider)) <= float64(propertySplitGrab) {
+			g.drag = true
+			ctx.CapturePointer(g)
+			return core.Handled
+		}
+	case event.MouseUp:
+		if g.drag {
+			g.drag = false
+			ctx.ReleasePointer()
+			return core.Handled
+		}
+	}
+	return core.Ignored
+}
+
+// colorWell edits a color held by a signal: a swatch with its hex code
+// that opens a ColorPicker below it.
+type colorWell struct {
+	core.WidgetBase
+	core.FocusState
+
+	sig    *state.Signal[core.Color]
+	cancel func()
+	popup  *core.Overlay
+}
+
+func newColorWell(sig *state.Signal[core.Color]) *colorWell {
+	return &colorWell{sig: sig}
+}
+
+// Release ends the subscription to the signal. Laid out again, the well
+// subscribes again.
+func (s *colorWell) Release() {
+	if s.cancel != nil {
+		s.cancel()
+		s.cancel = nil
+	}
+}
+
+func (s *colorWell) Layout(ctx *core.LayoutContext) core.Size {
+	if s.cancel == nil {
+		c := ctx.Context
+		s.cancel = s.sig.Subscribe(func(core.Color) { c.Post(func() { c.Repaint(s) }) })
+	}
+	return ctx.Constraints.Constrain(core.Sz(ctx.Constraints.MaxWidth, fieldHeight))
+}
+
+func (s *colorWell) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	b := s.Bounds()
+	c := s.sig.Get()
+	sw := core.R(b.X, b.Y+(b.Height-colorSwatch)/2, colorSwatch, colorSwatch)
+	paintChecker(ctx, sw)
+	cv.DrawRoundedRect(sw, th.Radii.Small, core.RectStyle{Fill: c, Stroke: th.Colors.Outline, StrokeWidth: 1})
+	st := theme.TextStyle(th.Typography.Mono, th.Colors.OnSurface)
+	text := formatHexColor(c, c.A < 1)
+	sz := ctx.MeasureText(text, st)
+	cv.DrawText(text, core.Pt(sw.Right()+th.Spacing.M, b.Y+(b.Height-sz.Height)/2), st)
+	if s.IsFocused() {
+		cv.DrawRoundedRect(sw.Inset(core.UniformInsets(-2)), th.Radii.Small+2, core.Stroked(th.Colors.Primary, 2))
+	}
+}
+
+func (s *colorWell) open(ctx *core.Context) {
+	if s.popup != nil && s.popup.IsOpen() {
+		return
+	}
+	picker := NewColorPicker(s.sig.Get()).ShowAlpha(true).OnChange(s.sig.Set)
+	s.popup = &core.Overlay{
+		Content:      newPopupFrame(picker),
+		Placement:    core.PlaceAnchored(s.Bounds(), core.SideBelow),
+		LightDismiss: true,
+		Popup:        true,
+		Owner:        s,
+	}
+	ctx.ShowOverlay(s.popup)
+}
+
+func (s *colorWell) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	switch e := ev.(type) {
+	case event.MouseEvent:
+		if e.Type == event.MouseDown && e.Button == event.ButtonLeft {
+			ctx.RequestFocus(s)
+			s.open(ctx)
+			return core.Handled
+		}
+	case event.KeyEvent:
+		if e.Type == event.KeyPress && s.IsFocused() && (e.Key == event.KeySpace || e.Key == event.KeyEnter) {
+			s.open(ctx)
+			return core.Handled
+		}
+	}
+	return core.Ignored
+}
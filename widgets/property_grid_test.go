@@ -0,0 +1,452 @@
+package widgets
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/state"
+	"github.com/gogpu/ui/theme"
+)
+
+func TestParsePropertyTag(t *testing.T) {
+	inf := math.Inf(1)
+	tests := []struct {
+		tag  string
+		want propertyTag
+		ok   bool
+	}{
+		{"", propertyTag{min: -inf, max: inf, precision: -1}, true},
+		{"-", propertyTag{precision: -1}, false},
+		{" Width ,category=Layout", propertyTag{name: "Width", category: "Layout", min: -inf, max: inf, precision: -1}, true},
+		{",min=0,max=10,step=0.5,precision=2", propertyTag{min: 0, max: 10, step: 0.5, limited: true, precision: 2}, true},
+		{",max=1", propertyTag{min: -inf, max: 1, limited: true, precision: -1}, true},
+		{",min=low,step=,precision=x,bogus", propertyTag{min: -inf, max: inf, precision: -1}, true},
+		{",options=left|center|right", propertyTag{options: []string{"left", "center", "right"}, min: -inf, max: inf, precision: -1}, true},
+	}
+	for _, tt := range tests {
+		got, ok := parsePropertyTag(tt.tag)
+		if ok != tt.ok || got.name != tt.want.name || got.category != tt.want.category || !equalStrings(got.options, tt.want.options) ||
+			got.min != tt.want.min || got.max != tt.want.max || got.step != tt.want.step || got.limited != tt.want.limited || got.precision != tt.want.precision {
+			t.Errorf("parsePropertyTag(%q) = %+v, %v, want %+v, %v", tt.tag, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestFieldLabel(t *testing.T) {
+	tests := []struct{ name, want string }{
+		{"Width", "Width"},
+		{"BorderWidth", "Border Width"},
+		{"URL", "URL"},
+		{"HTMLParser", "HTML Parser"},
+		{"ID2", "ID2"},
+		{"X", "X"},
+	}
+	for _, tt := range tests {
+		if got := fieldLabel(tt.name); got != tt.want {
+			t.Errorf("fieldLabel(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+type inspected struct {
+	Visible     bool
+	Title       string `prop:"Caption"`
+	Align       string `prop:",options=left|right"`
+	Opacity     float64
+	Count       int
+	Size        uint8
+	Tint        core.Color
+	Secret      string `prop:"-"`
+	Tags        []string
+	Border      struct{ Width float32 }
+	Enabled     *state.Signal[bool]
+	Name        *state.Signal[string]
+	Scale       *state.Signal[float64] `prop:",category=Layout,min=0,max=4"`
+	Steps       *state.Signal[int]     `prop:",category=Layout"`
+	Accent      *state.Signal[core.Color]
+	Missing     *state.Signal[bool]
+	hidden      bool
+	placeholder struct{}
+}
+
+func newInspected() *inspected {
+	return &inspected{
+		Title:   "box",
+		Align:   "left",
+		Opacity: 0.5,
+		Count:   3,
+		Size:    7,
+		Enabled: state.New(true),
+		Name:    state.New("n"),
+		Scale:   state.New(1.0),
+		Steps:   state.New(2),
+		Accent:  state.New(core.Hex(0x336699)),
+	}
+}
+
+func TestPropertyGridReflect(t *testing.T) {
+	g := NewPropertyGrid().Reflect(newInspected())
+	tests := []struct {
+		name, category string
+		editor         string
+	}{
+		{"Visible", "", "*widgets.Switch"},
+		{"Caption", "", "*widgets.TextField"},
+		{"Align", "", "*widgets.ComboBox"},
+		{"Opacity", "", "*widgets.NumberInput"},
+		{"Count", "", "*widgets.NumberInput"},
+		{"Size", "", "*widgets.NumberInput"},
+		{"Tint", "", "*widgets.colorWell"},
+		{"Width", "Border", "*widgets.NumberInput"},
+		{"Enabled", "", "*widgets.Switch"},
+		{"Name", "", "*widgets.TextField"},
+		{"Scale", "Layout", "*widgets.NumberInput"},
+		{"Steps", "Layout", "*widgets.NumberInput"},
+		{"Accent", "", "*widgets.colorWell"},
+	}
+	props := g.Properties()
+	if len(props) != len(tests) {
+		for _, p := range props {
+			t.Log(p.Name)
+		}
+		t.Fatalf("%d rows, want %d", len(props), len(tests))
+	}
+	for i, tt := range tests {
+		p := props[i]
+		if p.Name != tt.name || p.Category != tt.category || typeName(p.Editor) != tt.editor {
+			t.Errorf("row %d is %q in %q edited by %s, want %q in %q by %s", i, p.Name, p.Category, typeName(p.Editor), tt.name, tt.category, tt.editor)
+		}
+	}
+	if n := props[10].Editor.(*NumberInput); n.min != 0 || n.max != 4 {
+		t.Errorf("Scale is limited to %v..%v, want the tag's 0..4", n.min, n.max)
+	}
+	if n := props[4].Editor.(*NumberInput); n.digits() != 0 {
+		t.Errorf("an int is shown with %d digits", n.digits())
+	}
+
+	// An embedded struct keeps the category of its parent, even when its
+	// type is not exported.
+	type base struct{ ID int }
+	type derived struct {
+		base
+		Extra struct {
+			base
+		} `prop:"Extras"`
+	}
+	props = NewPropertyGrid().Reflect(&derived{}).Properties()
+	if len(props) != 2 || props[0].Category != "" || props[1].Name != "ID" || props[1].Category != "Extras" {
+		t.Errorf("embedded structs reflected as %+v", props)
+	}
+
+	for _, v := range []any{inspected{}, (*inspected)(nil), new(int)} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Reflect(%T) did not panic", v)
+				}
+			}()
+			NewPropertyGrid().Reflect(v)
+		}()
+	}
+}
+
+func typeName(w core.Widget) string {
+	switch w.(type) {
+	case *Switch:
+		return "*widgets.Switch"
+	case *TextField:
+		return "*widgets.TextField"
+	case *ComboBox:
+		return "*widgets.ComboBox"
+	case *NumberInput:
+		return "*widgets.NumberInput"
+	case *colorWell:
+		return "*widgets.colorWell"
+	}
+	return "other"
+}
+
+func TestPropertyGridEdits(t *testing.T) {
+	v := newInspected()
+	var changed []string
+	g := NewPropertyGrid().OnChange(func(name string) { changed = append(changed, name) }).Reflect(v)
+	ctx := layoutAt(g, core.R(0, 0, 400, 600))
+	if v.Opacity != 0.5 {
+		t.Errorf("showing Opacity rounded it to %v", v.Opacity)
+	}
+	props := g.Properties()
+	editor := func(name string) core.Widget {
+		for _, p := range props {
+			if p.Name == name {
+				return p.Editor
+			}
+		}
+		t.Fatalf("no row %q", name)
+		return nil
+	}
+
+	editor("Visible").(*Switch).SetOn(true)
+	editor("Opacity").(*NumberInput).SetValue(2)
+	editor("Count").(*NumberInput).SetValue(5.4)
+	editor("Size").(*NumberInput).SetValue(-3)
+	editor("Tint").(*colorWell).sig.Set(core.Hex(0xff0000))
+	editor("Width").(*NumberInput).SetValue(2)
+	if !v.Visible || v.Opacity != 2 || v.Count != 5 || v.Size != 0 || v.Tint != core.Hex(0xff0000) || v.Border.Width != 2 {
+		t.Errorf("the edits wrote %+v", v)
+	}
+	editor("Steps").(*NumberInput).SetValue(6.6)
+	editor("Scale").(*NumberInput).SetValue(9)
+	editor("Enabled").(*Switch).SetOn(false)
+	if v.Steps.Get() != 7 || v.Scale.Get() != 4 || v.Enabled.Get() {
+		t.Errorf("the signals hold %v, %v, %v", v.Steps.Get(), v.Scale.Get(), v.Enabled.Get())
+	}
+	want := []string{"Visible", "Opacity", "Count", "Size", "Tint", "Width", "Steps", "Scale", "Enabled"}
+	if !equalStrings(changed, want) {
+		t.Errorf("OnChange got %q, want %q", changed, want)
+	}
+
+	// Changes made elsewhere show in the editors.
+	changed = nil
+	v.Steps.Set(1)
+	v.Name.Set("renamed")
+	v.Count, v.Title = 9, "new"
+	g.Refresh()
+	ctx.RunPosted()
+	ctx.LayoutRoot(g, core.R(0, 0, 400, 600))
+	if got := editor("Steps").(*NumberInput).Value(); got != 1 {
+		t.Errorf("Steps shows %v after its signal changed", got)
+	}
+	if got := editor("Name").(*TextField).Text(); got != "renamed" {
+		t.Errorf("Name shows %q after its signal changed", got)
+	}
+	if got := editor("Count").(*NumberInput).Value(); got != 9 {
+		t.Errorf("Count shows %v after Refresh", got)
+	}
+	if got := editor("Caption").(*TextField).Text(); got != "new" {
+		t.Errorf("Caption shows %q after Refresh", got)
+	}
+	if !equalStrings(changed, []string{"Steps", "Name", "Caption"}) {
+		t.Errorf("OnChange got %q", changed)
+	}
+
+	// SetObject ends the subscriptions to the old struct's signals.
+	changed = nil
+	g.SetObject(&struct{ Other bool }{})
+	ctx.LayoutRoot(g, core.R(0, 0, 400, 600))
+	v.Steps.Set(3)
+	v.Accent.Set(core.White)
+	v.Enabled.Set(true)
+	v.Name.Set("gone")
+	v.Scale.Set(2)
+	if len(changed) != 0 || len(g.Properties()) != 1 {
+		t.Errorf("after SetObject OnChange got %q with %d rows", changed, len(g.Properties()))
+	}
+	if got := props[12].Editor.(*colorWell); got.cancel != nil {
+		t.Error("the color well still follows its signal")
+	}
+}
+
+func TestPropertyGridLayout(t *testing.T) {
+	row := func(name, category string) Property {
+		return Property{Name: name, Category: category, Editor: NewSwitch("")}
+	}
+	g := NewPropertyGrid(row("a", "One"), row("b", ""), row("c", "Two"), row("d", "One"))
+	lc := &core.LayoutContext{Context: core.NewContext()}
+	rowHeight := max(propertyRowHeight, lc.Measure(NewSwitch(""), core.Unbounded()).Height+2*theme.From(lc.Context).Spacing.S)
+	if got := lc.Measure(g, core.Unbounded()); got != core.Sz(360, 2*propertyHeaderHeight+4*rowHeight) {
+		t.Errorf("unbounded size %v", got)
+	}
+	var order []string
+	for _, it := range g.items {
+		if it.prop < 0 {
+			order = append(order, "["+it.header+"]")
+		} else {
+			order = append(order, g.props[it.prop].Name)
+		}
+	}
+	if want := []string{"b", "[One]", "a", "d", "[Two]", "c"}; !equalStrings(order, want) {
+		t.Errorf("rows in order %q, want %q", order, want)
+	}
+
+	ctx := layoutAt(g, core.R(0, 0, 400, 100))
+	if got := g.Bounds(); got.Height != 100 {
+		t.Errorf("bounded height %v", got.Height)
+	}
+	divider, vw := g.columns(g.body, g.pad)
+	if ed := g.props[1].Editor.Bounds(); ed.X != divider+g.pad || ed.Width != vw {
+		t.Errorf("the editor at %v, want in the value column from %v", ed, divider)
+	}
+
+	// Scrolling is clamped to the content.
+	g.HandleEvent(ctx, event.ScrollEvent{Position: core.Pt(10, 10), Delta: core.Pt(0, 1000)})
+	if max := g.content - 100; g.scrollY != max {
+		t.Errorf("scrolled to %v, want the end at %v", g.scrollY, max)
+	}
+	if r := g.HandleEvent(ctx, event.ScrollEvent{Position: core.Pt(10, 10), Delta: core.Pt(0, 10)}); r != core.Ignored {
+		t.Error("scrolling past the end was handled")
+	}
+	g.HandleEvent(ctx, event.ScrollEvent{Position: core.Pt(10, 10), Delta: core.Pt(0, -1000)})
+	if g.scrollY != 0 {
+		t.Errorf("scrolled back to %v", g.scrollY)
+	}
+
+	// A click on a header collapses its category.
+	ctx.LayoutRoot(g, core.R(0, 0, 400, 400))
+	header := core.Pt(100, rowHeight+propertyHeaderHeight/2)
+	if r := g.HandleEvent(ctx, leftMouse(event.MouseDown, header)); r != core.Handled || !g.collapsed["One"] {
+		t.Fatalf("a click on the header = %v", r)
+	}
+	ctx.LayoutRoot(g, core.R(0, 0, 400, 400))
+	if g.content != 2*propertyHeaderHeight+2*rowHeight || len(g.Children()) != 2 {
+		t.Errorf("collapsed, the content is %v high with %d editors", g.content, len(g.Children()))
+	}
+	g.SetCollapsed("One", false)
+	ctx.LayoutRoot(g, core.R(0, 0, 400, 400))
+	if len(g.Children()) != 4 {
+		t.Errorf("expanded, %d editors", len(g.Children()))
+	}
+
+	// Hovering a row highlights it until the pointer leaves.
+	g.HandleEvent(ctx, event.MouseEvent{Type: event.MouseMove, Position: core.Pt(10, rowHeight/2)})
+	if g.hover != 0 {
+		t.Errorf("hovering the first row sets %d", g.hover)
+	}
+	g.HandleEvent(ctx, event.MouseEvent{Type: event.MouseLeave})
+	if g.hover != -1 {
+		t.Errorf("after leaving the hover is %d", g.hover)
+	}
+}
+
+func TestPropertyGridDivider(t *testing.T) {
+	g := NewPropertyGrid(Property{Name: "a", Editor: NewSwitch("")})
+	ctx := layoutAt(g, core.R(0, 0, 400, 200))
+	divider, _ := g.columns(g.body, g.pad)
+	y := propertyRowHeight / 2
+	if r := g.HandleEvent(ctx, event.MouseEvent{Type: event.MouseDown, Button: event.ButtonRight, Position: core.Pt(divider, y)}); r != core.Ignored || g.drag {
+		t.Error("a right press started a drag")
+	}
+	if r := g.HandleEvent(ctx, leftMouse(event.MouseDown, core.Pt(divider+3, y))); r != core.Handled || ctx.PointerCapture() != g {
+		t.Fatalf("a press on the divider = %v", r)
+	}
+	g.HandleEvent(ctx, leftMouse(event.MouseMove, core.Pt(g.body.Width/2, 500)))
+	if g.split != 0.5 {
+		t.Errorf("dragged to a split of %v, want 0.5", g.split)
+	}
+	if ed := g.props[0].Editor.Bounds(); ed.X != g.body.Width/2+g.pad {
+		t.Errorf("the editor stayed at %v", ed.X)
+	}
+	g.HandleEvent(ctx, leftMouse(event.MouseMove, core.Pt(-50, y)))
+	if g.split != 0.1 {
+		t.Errorf("dragged past the left to %v", g.split)
+	}
+	g.HandleEvent(ctx, leftMouse(event.MouseUp, core.Pt(0, y)))
+	if g.drag || ctx.PointerCapture() != nil {
+		t.Error("the pointer is still captured after the release")
+	}
+
+	// The columns keep a minimum width whatever the split.
+	if d, _ := g.columns(g.body, g.pad); d != propertyMinColumn {
+		t.Errorf("the divider at %v, want the minimum %v", d, propertyMinColumn)
+	}
+	if got := g.NameWidth(2).split; got != 0.9 {
+		t.Errorf("NameWidth(2) set %v", got)
+	}
+}
+
+func TestColorWell(t *testing.T) {
+	sig := state.New(core.Hex(0x336699))
+	w := newColorWell(sig)
+	ctx := layoutAt(w, core.R(0, 0, 200, 32))
+	cv := &textCanvas{}
+	w.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+	if !equalStrings(cv.texts, []string{"#336699"}) {
+		t.Errorf("drew %q", cv.texts)
+	}
+
+	space := event.KeyEvent{Type: event.KeyPress, Key: event.KeySpace}
+	if r := w.HandleEvent(ctx, space); r != core.Ignored {
+		t.Error("an unfocused well opened on Space")
+	}
+	if r := w.HandleEvent(ctx, leftMouse(event.MouseDown, core.Pt(5, 5))); r != core.Handled || len(ctx.Overlays()) != 1 || !ctx.IsFocused(w) {
+		t.Fatalf("a click = %v with %d overlays", r, len(ctx.Overlays()))
+	}
+	w.HandleEvent(ctx, space)
+	if len(ctx.Overlays()) != 1 {
+		t.Error("Space opened a second picker")
+	}
+	ctx.CloseOverlay(w.popup)
+	if r := w.HandleEvent(ctx, space); r != core.Handled || len(ctx.Overlays()) != 1 {
+		t.Error("Space did not open the picker again")
+	}
+
+	// The picker edits the signal.
+	picker := w.popup.Content.(*popupFrame).content.(*ColorPicker)
+	picker.onChange(core.Hex(0xff0000))
+	if sig.Get() != core.Hex(0xff0000) {
+		t.Errorf("the picker set %v", sig.Get())
+	}
+	if !ctx.HasPosted() {
+		t.Error("a change of the signal did not ask for a repaint")
+	}
+	w.Release()
+	ctx.RunPosted()
+	sig.Set(core.White)
+	if ctx.HasPosted() {
+		t.Error("a released well still follows the signal")
+	}
+	ctx.Invalidate()
+	ctx.LayoutRoot(w, core.R(0, 0, 200, 32))
+	sig.Set(core.Black)
+	if !ctx.HasPosted() {
+		t.Error("laid out again, the well does not follow the signal")
+	}
+}
+
+func TestPropertyGridPaint(t *testing.T) {
+	g := NewPropertyGrid().Add(
+		Property{Name: "Width", Category: "Layout", Editor: NewSwitch("")},
+		Property{Name: "Title", Editor: NewSwitch("")},
+	)
+	ctx := layoutAt(g, core.R(0, 0, 400, 300))
+	paint := func() []string {
+		cv := &textCanvas{}
+		g.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+		return cv.texts
+	}
+	if got := paint(); !equalStrings(got, []string{"Title", "Layout", "Width"}) {
+		t.Errorf("drew %q", got)
+	}
+	g.SetCollapsed("Layout", true)
+	ctx.LayoutRoot(g, core.R(0, 0, 400, 300))
+	if got := paint(); !equalStrings(got, []string{"Title", "Layout"}) {
+		t.Errorf("collapsed, drew %q", got)
+	}
+}
+
+func TestPropertyGridRelease(t *testing.T) {
+	v := newInspected()
+	var changed []string
+	g := NewPropertyGrid().OnChange(func(name string) { changed = append(changed, name) }).Reflect(v)
+	ctx := layoutAt(g, core.R(0, 0, 400, 600))
+	g.Release()
+	v.Steps.Set(4)
+	v.Name.Set("released")
+	if len(changed) != 0 || ctx.HasPosted() {
+		t.Errorf("released, OnChange got %q, posted %v", changed, ctx.HasPosted())
+	}
+
+	// Laid out again, the grid shows the values the signals hold then and
+	// follows them.
+	ctx.Invalidate()
+	ctx.LayoutRoot(g, core.R(0, 0, 400, 600))
+	steps := g.Properties()[11].Editor.(*NumberInput)
+	if got := steps.Value(); got != 4 {
+		t.Errorf("Steps shows %v, want the signal's 4", got)
+	}
+	v.Steps.Set(5)
+	if !equalStrings(changed, []string{"Steps"}) || !ctx.HasPosted() {
+		t.Errorf("laid out again, OnChange got %q, posted %v", changed, ctx.HasPosted())
+	}
+}
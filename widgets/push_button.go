@@ -0,0 +1,106 @@
+package widgets
+
+import (
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/theme"
+)
+
+const (
+	pushButtonHeight   float32 = 36
+	pushButtonPadding  float32 = 16
+	pushButtonMinWidth float32 = 80
+)
+
+// pushButton is the text button composite widgets show for their actions,
+// such as the Back and Next buttons of a Wizard. A primary button is
+// filled with the primary color, the others are outlined.
+type pushButton struct {
+	core.WidgetBase
+	core.FocusState
+
+	label    string
+	primary  bool
+	disabled bool
+	onClick  func(ctx *core.Context)
+	press    togglePress
+
+	// size is the measured size, kept for the owner to arrange.
+	size core.Size
+}
+
+func newPushButton(label string, primary bool, onClick func(ctx *core.Context)) *pushButton {
+	return &pushButton{label: label, primary: primary, onClick: onClick}
+}
+
+// Layout implements core.Widget.
+func (b *pushButton) Layout(ctx *core.LayoutContext) core.Size {
+	style := theme.From(ctx.Context).Typography.Label
+	w := max(pushButtonMinWidth, ctx.MeasureText(b.label, style).Width+2*pushButtonPadding)
+	return ctx.Constraints.Constrain(core.Sz(w, pushButtonHeight))
+}
+
+// Paint implements core.Widget.
+func (b *pushButton) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	r := b.Bounds()
+	radius := th.Radii.Small
+	if th.Design == theme.DesignMaterial {
+		radius = r.Height / 2
+	}
+	fill, text := core.Color{}, th.Colors.Primary
+	if b.primary {
+		fill, text = th.Colors.Primary, th.Colors.OnPrimary
+	}
+	if b.disabled {
+		text = th.Colors.OnSurfaceVariant.WithAlpha(0.5)
+		if b.primary {
+			fill = th.Colors.OnSurface.WithAlpha(0.12)
+		}
+	}
+	style := core.RectStyle{Fill: fill}
+	if !b.primary {
+		style.Stroke, style.StrokeWidth = th.Colors.Outline, 1
+	}
+	ctx.Canvas.DrawRoundedRect(r, radius, style)
+	if !b.disabled {
+		var a float32
+		switch {
+		case b.press.pressed && b.press.hover:
+			a = 0.12
+		case b.press.hover:
+			a = 0.08
+		}
+		if a > 0 {
+			layer := th.Colors.Primary
+			if b.primary {
+				layer = th.Colors.OnPrimary
+			}
+			ctx.Canvas.DrawRoundedRect(r, radius, core.Filled(layer.WithAlpha(a)))
+		}
+	}
+	if b.IsFocused() {
+		ring := r.Inset(core.UniformInsets(-toggleInset))
+		ctx.Canvas.DrawRoundedRect(ring, radius+toggleInset, core.Stroked(th.Colors.Primary, toggleRing))
+	}
+	ts := theme.TextStyle(th.Typography.Label, text)
+	sz := ctx.MeasureText(b.label, ts)
+	ctx.Canvas.DrawText(b.label, core.Pt(r.X+(r.Width-sz.Width)/2, r.Y+(r.Height-ts.LineHeight())/2), ts)
+}
+
+// HandleEvent implements core.Widget.
+func (b *pushButton) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	if b.disabled {
+		return core.Ignored
+	}
+	activate := func() {
+		if b.onClick != nil {
+			b.onClick(ctx)
+		}
+	}
+	if e, ok := ev.(event.KeyEvent); ok && b.IsFocused() && e.Type == event.KeyPress && e.Key == event.KeyEnter && e.Modifiers == 0 {
+		activate()
+		return core.Handled
+	}
+	return b.press.handle(ctx, b, b.IsFocused(), ev, activate)
+}
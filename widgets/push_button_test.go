@@ -0,0 +1,55 @@
+package widgets
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/theme"
+)
+
+func TestPushButton(t *testing.T) {
+	var clicks int
+	b := newPushButton("OK", true, func(*core.Context) { clicks++ })
+	lc := &core.LayoutContext{Context: core.NewContext()}
+	if got := lc.Measure(b, core.Unbounded()); got != core.Sz(pushButtonMinWidth, pushButtonHeight) {
+		t.Errorf("a short label takes %v, want the minimum width", got)
+	}
+	long := newPushButton("A much longer label", false, nil)
+	textW := lc.MeasureText(long.label, theme.From(lc.Context).Typography.Label).Width
+	if got := lc.Measure(long, core.Unbounded()); got.Width != textW+2*pushButtonPadding {
+		t.Errorf("a long label takes %v, want %v", got.Width, textW+2*pushButtonPadding)
+	}
+
+	ctx := layoutAt(b, core.R(0, 0, 80, 36))
+	b.HandleEvent(ctx, leftMouse(event.MouseDown, core.Pt(10, 10)))
+	b.HandleEvent(ctx, leftMouse(event.MouseUp, core.Pt(10, 10)))
+	if clicks != 1 || !ctx.IsFocused(b) {
+		t.Errorf("a click clicked %d times, focused %v", clicks, ctx.IsFocused(b))
+	}
+	tests := []struct {
+		ev     event.KeyEvent
+		clicks int
+	}{
+		{press(event.KeyEnter, 0), 2},
+		{press(event.KeySpace, 0), 3},
+		{press(event.KeyEnter, event.ModShift), 3},
+		{press(event.KeyA, 0), 3},
+	}
+	for _, tt := range tests {
+		b.HandleEvent(ctx, tt.ev)
+		if clicks != tt.clicks {
+			t.Errorf("after %v+%v %d clicks, want %d", tt.ev.Modifiers, tt.ev.Key, clicks, tt.clicks)
+		}
+	}
+
+	b.disabled = true
+	if r := b.HandleEvent(ctx, press(event.KeyEnter, 0)); r != core.Ignored || clicks != 3 {
+		t.Error("a disabled button was activated")
+	}
+	cv := &textCanvas{}
+	b.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+	if !equalStrings(cv.texts, []string{"OK"}) {
+		t.Errorf("drew %q", cv.texts)
+	}
+}
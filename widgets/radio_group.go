@@ -0,0 +1,270 @@
+package widgets
+
+import (
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/state"
+	"github.com/gogpu/ui/theme"
+)
+
+// radioSize is the diameter of a radio button.
+const radioSize = 20
+
+// RadioGroup is a set of mutually exclusive options, each with a radio
+// button and a label. T is the type of the option values, typically an
+// enumeration.
+//
+// The group takes focus as a whole. The arrow keys move the selection to
+// the previous or next option, wrapping around, and Space selects the
+// highlighted option when nothing is selected yet.
+type RadioGroup[T comparable] struct {
+	core.WidgetBase
+	core.FocusState
+
+	values     []T
+	rows       []toggleRow
+	selected   int
+	cursor     int
+	horizontal bool
+	onChange   func(T)
+
+	sig    *state.Signal[T]
+	cancel func()
+
+	sizes   []core.Size
+	options []core.Rect
+	gap     float32
+	hover   int
+	pressed int
+}
+
+// NewRadioGroup returns an empty vertical RadioGroup.
+func NewRadioGroup[T comparable]() *RadioGroup[T] {
+	return &RadioGroup[T]{selected: -1, hover: -1, pressed: -1}
+}
+
+// Option appends an option with the given value and label.
+func (g *RadioGroup[T]) Option(value T, label string) *RadioGroup[T] {
+	g.values = append(g.values, value)
+	g.rows = append(g.rows, toggleRow{label: label})
+	return g
+}
+
+// Horizontal lays the options out in a row instead of a column.
+func (g *RadioGroup[T]) Horizontal(on bool) *RadioGroup[T] {
+	g.horizontal = on
+	return g
+}
+
+// Bind keeps the selection in sync with sig in both directions. A value
+// that matches no option clears the selection.
+func (g *RadioGroup[T]) Bind(sig *state.Signal[T]) *RadioGroup[T] {
+	g.Release()
+	g.sig = sig
+	return g
+}
+
+// Release stops following the bound signal, for when the group leaves
+// the tree. Laid out again, the group follows the signal from its value
+// then.
+func (g *RadioGroup[T]) Release() {
+	if g.cancel != nil {
+		g.cancel()
+		g.cancel = nil
+	}
+}
+
+// OnChange registers fn to be called when the user selects an option.
+func (g *RadioGroup[T]) OnChange(fn func(T)) *RadioGroup[T] {
+	g.onChange = fn
+	return g
+}
+
+// Value returns the selected value, and false if nothing is selected.
+func (g *RadioGroup[T]) Value() (T, bool) {
+	if g.selected < 0 {
+		var zero T
+		return zero, false
+	}
+	return g.values[g.selected], true
+}
+
+// SetValue selects the option with value v without calling OnChange. A
+// value that matches no option clears the selection.
+func (g *RadioGroup[T]) SetValue(v T) {
+	g.selected = g.indexOf(v)
+	if g.selected >= 0 {
+		g.cursor = g.selected
+	}
+	if g.sig != nil {
+		g.sig.Set(v)
+	}
+}
+
+func (g *RadioGroup[T]) indexOf(v T) int {
+	for i, o := range g.values {
+		if o == v {
+			return i
+		}
+	}
+	return -1
+}
+
+func (g *RadioGroup[T]) choose(ctx *core.Context, i int) {
+	g.cursor = i
+	ctx.Repaint(g)
+	if i == g.selected {
+		return
+	}
+	g.SetValue(g.values[i])
+	if g.onChange != nil {
+		g.onChange(g.values[i])
+	}
+}
+
+// Layout implements core.Widget.
+func (g *RadioGroup[T]) Layout(ctx *core.LayoutContext) core.Size {
+	if g.sig != nil {
+		if g.cancel == nil {
+			c := ctx.Context
+			g.cancel = g.sig.Subscribe(func(T) { c.Post(func() { c.MarkNeedsLayout(g) }) })
+		}
+		if v := g.sig.Get(); g.indexOf(v) != g.selected {
+			g.SetValue(v)
+		}
+	}
+	g.gap = 0
+	if g.horizontal {
+		g.gap = theme.From(ctx.Context).Spacing.L
+	}
+	g.sizes = g.sizes[:0]
+	var size core.Size
+	for i := range g.rows {
+		rs := g.rows[i].measure(ctx, core.Sz(radioSize, radioSize))
+		g.sizes = append(g.sizes, rs)
+		if g.horizontal {
+			if i > 0 {
+				size.Width += g.gap
+			}
+			size.Width += rs.Width
+			size.Height = max(size.Height, rs.Height)
+		} else {
+			size.Width = max(size.Width, rs.Width)
+			size.Height += rs.Height
+		}
+	}
+	return ctx.Constraints.Constrain(size)
+}
+
+// SetBounds implements core.Widget.
+func (g *RadioGroup[T]) SetBounds(r core.Rect) {
+	g.WidgetBase.SetBounds(r)
+	g.options = g.options[:0]
+	x, y := r.X, r.Y
+	for i, rs := range g.sizes {
+		var b core.Rect
+		if g.horizontal {
+			b = core.R(x, r.Y, rs.Width, r.Height)
+			x += rs.Width + g.gap
+		} else {
+			b = core.R(r.X, y, r.Width, rs.Height)
+			y += rs.Height
+		}
+		g.rows[i].arrange(b)
+		g.options = append(g.options, b)
+	}
+}
+
+// Paint implements core.Widget.
+func (g *RadioGroup[T]) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	for i := range g.options {
+		row := &g.rows[i]
+		b := row.control
+		paintToggleState(ctx, b, radioSize/2, i == g.hover, g.IsFocused() && i == g.cursor)
+		selected := i == g.selected
+		switch {
+		case !selected:
+			stroke := float32(2)
+			if th.Design != theme.DesignMaterial {
+				stroke = 1
+			}
+			cv.DrawRoundedRect(b.Inset(core.UniformInsets(stroke/2)), radioSize/2-stroke/2, core.Stroked(th.Colors.OnSurfaceVariant, stroke))
+		case th.Design == theme.DesignMaterial:
+			cv.DrawRoundedRect(b.Inset(core.UniformInsets(1)), radioSize/2-1, core.Stroked(th.Colors.Primary, 2))
+			cv.DrawRoundedRect(b.Inset(core.UniformInsets(5)), radioSize/2-5, core.Filled(th.Colors.Primary))
+		default:
+			dot := float32(6)
+			if th.Design == theme.DesignCupertino {
+				dot = 6.5
+			}
+			cv.DrawRoundedRect(b, radioSize/2, core.Filled(th.Colors.Primary))
+			cv.DrawRoundedRect(b.Inset(core.UniformInsets(dot)), radioSize/2-dot, core.Filled(th.Colors.OnPrimary))
+		}
+		row.paintLabel(ctx)
+	}
+}
+
+func (g *RadioGroup[T]) optionAt(p core.Point) int {
+	for i, b := range g.options {
+		if b.Contains(p) {
+			return i
+		}
+	}
+	return -1
+}
+
+// HandleEvent implements core.Widget.
+func (g *RadioGroup[T]) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	switch e := ev.(type) {
+	case event.MouseEvent:
+		switch e.Type {
+		case event.MouseMove, event.MouseEnter:
+			if i := g.optionAt(e.Position); i != g.hover {
+				g.hover = i
+				ctx.Repaint(g)
+			}
+		case event.MouseLeave:
+			g.hover = -1
+			ctx.Repaint(g)
+		case event.MouseDown:
+			i := g.optionAt(e.Position)
+			if e.Button != event.ButtonLeft || i < 0 {
+				return core.Ignored
+			}
+			g.pressed = i
+			ctx.RequestFocus(g)
+			ctx.CapturePointer(g)
+			return core.Handled
+		case event.MouseUp:
+			if g.pressed < 0 || e.Button != event.ButtonLeft {
+				return core.Ignored
+			}
+			i := g.pressed
+			g.pressed = -1
+			ctx.ReleasePointer()
+			if g.optionAt(e.Position) == i {
+				g.choose(ctx, i)
+			}
+			return core.Handled
+		}
+	case event.KeyEvent:
+		if !g.IsFocused() || e.Type != event.KeyPress || e.Modifiers != 0 || len(g.values) == 0 {
+			return core.Ignored
+		}
+		n := len(g.values)
+		switch e.Key {
+		case event.KeyUp, event.KeyLeft:
+			g.choose(ctx, (g.cursor-1+n)%n)
+		case event.KeyDown, event.KeyRight:
+			g.choose(ctx, (g.cursor+1)%n)
+		case event.KeySpace:
+			g.choose(ctx, g.cursor)
+		default:
+			return core.Ignored
+		}
+		return core.Handled
+	}
+	return core.Ignored
+}
@@ -0,0 +1,115 @@
+package widgets
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/state"
+)
+
+type align int
+
+const (
+	alignLeft align = iota
+	alignCenter
+	alignRight
+)
+
+func newAlignGroup() *RadioGroup[align] {
+	return NewRadioGroup[align]().
+		Option(alignLeft, "Left").
+		Option(alignCenter, "Center").
+		Option(alignRight, "Right")
+}
+
+func TestRadioGroupKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		initial []align
+		keys    []event.Key
+		want    align
+		changes int
+	}{
+		{"down", []align{alignLeft}, []event.Key{event.KeyDown}, alignCenter, 1},
+		{"right twice", []align{alignLeft}, []event.Key{event.KeyRight, event.KeyRight}, alignRight, 2},
+		{"wraps forward", []align{alignRight}, []event.Key{event.KeyDown}, alignLeft, 1},
+		{"wraps back", []align{alignLeft}, []event.Key{event.KeyUp}, alignRight, 1},
+		{"space selects the first", nil, []event.Key{event.KeySpace}, alignLeft, 1},
+		{"space on the selection", []align{alignCenter}, []event.Key{event.KeySpace}, alignCenter, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newAlignGroup()
+			for _, v := range tt.initial {
+				g.SetValue(v)
+			}
+			changes := 0
+			g.OnChange(func(align) { changes++ })
+			ctx := layoutAt(g, core.R(0, 0, 200, 120))
+			ctx.RequestFocus(g)
+			for _, k := range tt.keys {
+				if r := g.HandleEvent(ctx, press(k, 0)); r != core.Handled {
+					t.Fatalf("key %v: %v", k, r)
+				}
+			}
+			if v, ok := g.Value(); !ok || v != tt.want || changes != tt.changes {
+				t.Errorf("Value() = %v, %v with %d changes, want %v with %d", v, ok, changes, tt.want, tt.changes)
+			}
+		})
+	}
+}
+
+func TestRadioGroupMouse(t *testing.T) {
+	for _, horizontal := range []bool{false, true} {
+		g := newAlignGroup().Horizontal(horizontal)
+		ctx := layoutAt(g, core.R(0, 0, 400, 120))
+		if len(g.options) != 3 {
+			t.Fatalf("%d options laid out", len(g.options))
+		}
+		a, b := g.options[0], g.options[1]
+		if horizontal && (b.X <= a.Right() || b.Y != a.Y) || !horizontal && (b.Y != a.Bottom() || b.X != a.X) {
+			t.Errorf("horizontal %v: options at %v and %v", horizontal, a, b)
+		}
+		g.HandleEvent(ctx, leftMouse(event.MouseMove, b.Center()))
+		if g.hover != 1 {
+			t.Errorf("hovering option %d, want 1", g.hover)
+		}
+		click(ctx, g, b.Center())
+		if v, _ := g.Value(); v != alignCenter || !g.IsFocused() {
+			t.Errorf("horizontal %v: clicking the second option selected %v", horizontal, v)
+		}
+		g.HandleEvent(ctx, leftMouse(event.MouseDown, g.options[2].Center()))
+		g.HandleEvent(ctx, leftMouse(event.MouseUp, g.options[0].Center()))
+		if v, _ := g.Value(); v != alignCenter || ctx.PointerCapture() != nil {
+			t.Errorf("a press released over another option selected %v", v)
+		}
+	}
+}
+
+func TestRadioGroupBind(t *testing.T) {
+	sig := state.New(alignRight)
+	g := newAlignGroup().Bind(sig)
+	ctx := layoutAt(g, core.R(0, 0, 200, 120))
+	if v, ok := g.Value(); !ok || v != alignRight {
+		t.Fatalf("Value() = %v, %v, want the signal's value", v, ok)
+	}
+	ctx.RequestFocus(g)
+	g.HandleEvent(ctx, press(event.KeyDown, 0))
+	if sig.Get() != alignLeft {
+		t.Errorf("signal holds %v, want alignLeft", sig.Get())
+	}
+	sig.Set(align(7))
+	ctx.RunPosted()
+	ctx.LayoutRoot(g, core.R(0, 0, 200, 120))
+	if _, ok := g.Value(); ok {
+		t.Error("an unknown value left an option selected")
+	}
+
+	empty := NewRadioGroup[string]()
+	ctx = layoutAt(empty, core.R(0, 0, 200, 120))
+	ctx.RequestFocus(empty)
+	if r := empty.HandleEvent(ctx, press(event.KeyDown, 0)); r != core.Ignored {
+		t.Errorf("Down in an empty group = %v", r)
+	}
+}
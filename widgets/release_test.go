@@ -0,0 +1,101 @@
+package widgets
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/state"
+)
+
+// TestRelease checks that a bound widget stops following its signal when
+// released and follows it again when laid out again.
+func TestRelease(t *testing.T) {
+	flip := func(sig *state.Signal[bool]) func() {
+		return func() { sig.Update(func(on bool) bool { return !on }) }
+	}
+	inc := func(sig *state.Signal[float64]) func() {
+		return func() { sig.Update(func(v float64) float64 { return v + 0.1 }) }
+	}
+	extend := func(sig *state.Signal[string]) func() {
+		return func() { sig.Update(func(s string) string { return s + "a" }) }
+	}
+	tests := []struct {
+		name  string
+		build func() (w core.Widget, change func())
+	}{
+		{"badge", func() (core.Widget, func()) {
+			sig := state.New(1)
+			return NewBadge(NewText("")).Bind(sig), func() { sig.Update(func(n int) int { return n + 1 }) }
+		}},
+		{"checkbox", func() (core.Widget, func()) {
+			sig := state.New(false)
+			return NewCheckbox("").Bind(sig), flip(sig)
+		}},
+		{"chip", func() (core.Widget, func()) {
+			sig := state.New(false)
+			return NewChip("").Bind(sig), flip(sig)
+		}},
+		{"switch", func() (core.Widget, func()) {
+			sig := state.New(false)
+			return NewSwitch("").Bind(sig), flip(sig)
+		}},
+		{"combo box", func() (core.Widget, func()) {
+			sig := state.New("")
+			return NewComboBox("a", "b").Bind(sig), extend(sig)
+		}},
+		{"text field", func() (core.Widget, func()) {
+			sig := state.New("")
+			return NewTextField().Bind(sig), extend(sig)
+		}},
+		{"multi-select", func() (core.Widget, func()) {
+			sig := state.NewFunc([]string{}, nil)
+			return NewMultiSelect("a", "b").Bind(sig), func() { sig.Set([]string{"a"}) }
+		}},
+		{"number input", func() (core.Widget, func()) {
+			sig := state.New(0.0)
+			return NewNumberInput().Bind(sig), inc(sig)
+		}},
+		{"progress bar", func() (core.Widget, func()) {
+			sig := state.New(0.0)
+			return NewProgressBar().Bind(sig), inc(sig)
+		}},
+		{"radio group", func() (core.Widget, func()) {
+			sig := state.New(0)
+			return NewRadioGroup[int]().Option(0, "a").Option(1, "b").Bind(sig), func() { sig.Update(func(v int) int { return 1 - v }) }
+		}},
+		{"slider", func() (core.Widget, func()) {
+			sig := state.New(0.0)
+			return NewSlider(0, 1).Bind(sig), inc(sig)
+		}},
+		{"range slider", func() (core.Widget, func()) {
+			lo, hi := state.New(0.0), state.New(0.5)
+			return NewRangeSlider(0, 1).Bind(lo, hi), inc(hi)
+		}},
+		{"status bar", func() (core.Widget, func()) {
+			sig := state.New("")
+			return NewStatusBar().Add(StatusLeft, NewStatusItem("").Bind(sig)), extend(sig)
+		}},
+		{"property grid", func() (core.Widget, func()) {
+			v := &struct{ Steps *state.Signal[int] }{state.New(1)}
+			return NewPropertyGrid().Reflect(v), func() { v.Steps.Update(func(n int) int { return n + 1 }) }
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, change := tt.build()
+			bounds := core.R(0, 0, 300, 200)
+			ctx := layoutAt(w, bounds)
+			w.(interface{ Release() }).Release()
+			change()
+			if ctx.HasPosted() {
+				t.Error("released, the widget still follows the signal")
+			}
+			ctx.Invalidate()
+			ctx.LayoutRoot(w, bounds)
+			change()
+			if !ctx.HasPosted() {
+				t.Error("laid out again, the widget does not follow the signal")
+			}
+		})
+	}
+}
@@ -0,0 +1,340 @@
+package widgets
+
+import (
+	"time"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/theme"
+)
+
+const (
+	reorderHandleWidth float32 = 28
+	reorderDragSlop    float32 = 4
+	reorderEdge        float32 = 32
+	reorderScrollSpeed float32 = 600 // Pixels per second at the very edge.
+)
+
+// reorderShift is how long the other rows take to make room for the
+// dragged one.
+const reorderShift = 150 * time.Millisecond
+
+// ReorderableList is a VirtualList whose items the user rearranges by
+// dragging. Each row shows a grip at its leading edge; pressing it and
+// dragging lifts the row, the rows it passes slide aside to open a gap
+// where it would land, and dragging near the top or bottom edge scrolls.
+// On release OnReorder reports the move, and the application moves the
+// item in its data.
+//
+// Rows are built on demand exactly as in a VirtualList, which List returns
+// for scrolling and sizing. After a move the rows between the two
+// positions are rebuilt, since their indexes changed.
+type ReorderableList struct {
+	core.WidgetBase
+
+	list      *VirtualList
+	build     ItemBuilder
+	handles   bool
+	onReorder func(from, to int)
+
+	press    *reorderRow
+	pressPos core.Point
+	drag     *reorderRow
+	grab     float32 // Pointer distance below the top of the dragged row.
+	pointer  core.Point
+	to       int
+	scrollAt time.Time
+}
+
+// reorderRow wraps the content of a row with its drag handle and the
+// offset it is shifted by while another row is dragged past it.
+type reorderRow struct {
+	core.WidgetBase
+
+	owner   *ReorderableList
+	index   int
+	content core.Widget
+
+	shift, shiftFrom, shiftTo float32
+	shiftAt                   time.Time
+}
+
+// NewReorderableList returns a list of count items built on demand by
+// build.
+func NewReorderableList(count int, build ItemBuilder) *ReorderableList {
+	l := &ReorderableList{build: build, handles: true, to: -1}
+	l.list = NewVirtualList(count, l.buildRow)
+	l.SetChildren(l.list)
+	return l
+}
+
+// List returns the underlying VirtualList, for scrolling, item height
+// estimates and changing the item count.
+func (l *ReorderableList) List() *VirtualList {
+	return l.list
+}
+
+// DragHandles sets whether rows are dragged by their grip only, the
+// default, or from anywhere content does not handle the press itself.
+func (l *ReorderableList) DragHandles(show bool) *ReorderableList {
+	l.handles = show
+	l.list.Refresh()
+	return l
+}
+
+// OnReorder registers fn to be called when the user drops the item at
+// from so that it ends up at index to.
+func (l *ReorderableList) OnReorder(fn func(from, to int)) *ReorderableList {
+	l.onReorder = fn
+	return l
+}
+
+// IsDragging reports whether an item is being dragged.
+func (l *ReorderableList) IsDragging() bool {
+	return l.drag != nil
+}
+
+func (l *ReorderableList) buildRow(i int) core.Widget {
+	if l.drag != nil && l.drag.index == i {
+		// The dragged row scrolled out and back in; keep the lifted one.
+		return l.drag
+	}
+	r := &reorderRow{owner: l, index: i, content: l.build(i)}
+	r.SetChildren(r.content)
+	return r
+}
+
+func (l *ReorderableList) handleWidth() float32 {
+	if l.handles {
+		return reorderHandleWidth
+	}
+	return 0
+}
+
+// Layout implements core.Widget.
+func (l *ReorderableList) Layout(ctx *core.LayoutContext) core.Size {
+	if l.drag != nil {
+		l.autoScroll(ctx.Context)
+	}
+	size := ctx.Measure(l.list, ctx.Constraints)
+	if l.drag != nil {
+		l.to = l.dropIndex()
+	}
+	h := float32(0)
+	if l.drag != nil {
+		h = l.list.extents.Size(l.drag.index)
+	}
+	animating := false
+	for _, c := range l.list.Children() {
+		r := c.(*reorderRow)
+		var target float32
+		if l.drag != nil {
+			from := l.drag.index
+			switch {
+			case r.index > from && r.index <= l.to:
+				target = -h
+			case r.index < from && r.index >= l.to:
+				target = h
+			}
+		}
+		if r.animate(ctx, target) {
+			animating = true
+		}
+	}
+	if animating {
+		ctx.MarkNeedsLayout(l)
+	}
+	return size
+}
+
+// autoScroll scrolls the list while the pointer is held near its top or
+// bottom edge, faster the closer it is.
+func (l *ReorderableList) autoScroll(ctx *core.Context) {
+	now := ctx.Now()
+	b := l.Bounds()
+	var speed float32
+	switch {
+	case l.pointer.Y < b.Y+reorderEdge:
+		speed = -reorderScrollSpeed * min(1, (b.Y+reorderEdge-l.pointer.Y)/reorderEdge)
+	case l.pointer.Y > b.Bottom()-reorderEdge:
+		speed = reorderScrollSpeed * min(1, (l.pointer.Y-b.Bottom()+reorderEdge)/reorderEdge)
+	}
+	if speed == 0 {
+		l.scrollAt = time.Time{}
+		return
+	}
+	if !l.scrollAt.IsZero() {
+		l.list.ScrollBy(speed * float32(now.Sub(l.scrollAt).Seconds()))
+	}
+	l.scrollAt = now
+	// Keep scrolling while the pointer rests at the edge.
+	ctx.MarkNeedsLayout(l.list)
+	ctx.MarkNeedsLayout(l)
+}
+
+// dropIndex returns where the dragged row would land: the row under its
+// center, in the layout without the gap.
+func (l *ReorderableList) dropIndex() int {
+	ext := l.list.extents
+	h := ext.Size(l.drag.index)
+	y := l.pointer.Y - l.Bounds().Y + l.list.offset - l.grab + h/2
+	return min(max(ext.IndexAt(y), 0), l.list.count-1)
+}
+
+// SetBounds implements core.Widget.
+func (l *ReorderableList) SetBounds(r core.Rect) {
+	l.WidgetBase.SetBounds(r)
+	l.list.SetBounds(r)
+	for _, c := range l.list.Children() {
+		row := c.(*reorderRow)
+		if row.shift != 0 && row != l.drag {
+			row.SetBounds(row.Bounds().Translate(core.Pt(0, row.shift)))
+		}
+	}
+	if l.drag != nil {
+		h := l.list.extents.Size(l.drag.index)
+		y := core.Clamp(l.pointer.Y-l.grab, r.Y-h/2, r.Bottom()-h/2)
+		l.drag.SetBounds(core.R(r.X, y, l.list.rowWidth, h))
+	}
+}
+
+// Paint implements core.Widget.
+func (l *ReorderableList) Paint(ctx *core.PaintContext) {
+	l.list.Paint(ctx)
+	if l.drag == nil {
+		return
+	}
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	cv.Save()
+	cv.Clip(l.Bounds())
+	b := l.drag.Bounds()
+	cv.DrawRoundedRect(b, th.Radii.Small, core.RectStyle{Fill: th.Colors.Surface, Stroke: th.Colors.Outline, StrokeWidth: 1})
+	cv.DrawRoundedRect(b, th.Radii.Small, core.Filled(th.Colors.Primary.WithAlpha(0.08)))
+	l.drag.paintRow(ctx)
+	cv.Restore()
+}
+
+// pressRow starts tracking a press on row that may become a drag.
+func (l *ReorderableList) pressRow(ctx *core.Context, row *reorderRow, p core.Point) {
+	l.press, l.pressPos = row, p
+	ctx.CapturePointer(l)
+}
+
+// HandleEvent implements core.Widget.
+func (l *ReorderableList) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	e, ok := ev.(event.MouseEvent)
+	if !ok || l.press == nil {
+		return core.Ignored
+	}
+	switch e.Type {
+	case event.MouseMove:
+		l.pointer = e.Position
+		if l.drag == nil {
+			d := e.Position.Y - l.pressPos.Y
+			if abs32(d) < reorderDragSlop && abs32(e.Position.X-l.pressPos.X) < reorderDragSlop {
+				return core.Handled
+			}
+			l.drag = l.press
+			l.grab = l.pressPos.Y - l.press.Bounds().Y
+			l.to = l.drag.index
+		}
+		ctx.MarkNeedsLayout(l)
+		return core.Handled
+	case event.MouseUp:
+		if e.Button != event.ButtonLeft {
+			return core.Ignored
+		}
+		ctx.ReleasePointer()
+		drag, to := l.drag, l.to
+		l.press, l.drag, l.to = nil, nil, -1
+		l.scrollAt = time.Time{}
+		if drag == nil {
+			return core.Handled
+		}
+		from := drag.index
+		if to >= 0 && to != from {
+			for i := min(from, to); i <= max(from, to); i++ {
+				l.list.InvalidateItem(i)
+			}
+			if l.onReorder != nil {
+				l.onReorder(from, to)
+			}
+		}
+		ctx.MarkNeedsLayout(l.list)
+		return core.Handled
+	}
+	return core.Ignored
+}
+
+// animate moves the row's shift toward target, and reports whether it
+// has not reached it.
+func (r *reorderRow) animate(ctx *core.LayoutContext, target float32) bool {
+	if target != r.shiftTo {
+		r.shiftFrom, r.shiftTo = r.shift, target
+		r.shiftAt = ctx.Now()
+	}
+	if r.shift == r.shiftTo {
+		return false
+	}
+	t := float32(ctx.Now().Sub(r.shiftAt)) / float32(reorderShift)
+	if t >= 1 || ctx.ReducedMotion() {
+		r.shift = r.shiftTo
+		return false
+	}
+	r.shift = r.shiftFrom + (r.shiftTo-r.shiftFrom)*easeOut(max(t, 0))
+	return true
+}
+
+// Layout implements core.Widget.
+func (r *reorderRow) Layout(ctx *core.LayoutContext) core.Size {
+	hw := r.owner.handleWidth()
+	c := ctx.Constraints.Deflate(core.Insets{Left: hw})
+	sz := ctx.Measure(r.content, c)
+	return ctx.Constraints.Constrain(core.Sz(sz.Width+hw, sz.Height))
+}
+
+// SetBounds implements core.Widget.
+func (r *reorderRow) SetBounds(b core.Rect) {
+	r.WidgetBase.SetBounds(b)
+	r.content.SetBounds(b.Inset(core.Insets{Left: r.owner.handleWidth()}))
+}
+
+// Paint implements core.Widget. The dragged row is painted by the list,
+// above the others.
+func (r *reorderRow) Paint(ctx *core.PaintContext) {
+	if r.owner.drag == r {
+		return
+	}
+	r.paintRow(ctx)
+}
+
+func (r *reorderRow) paintRow(ctx *core.PaintContext) {
+	if r.owner.handles {
+		th := theme.From(ctx.Context)
+		b := r.Bounds()
+		cx, cy := b.X+reorderHandleWidth/2, b.Y+b.Height/2
+		dot := core.Filled(th.Colors.OnSurfaceVariant)
+		for row := -1; row <= 1; row++ {
+			for col := -1; col <= 1; col += 2 {
+				x, y := cx+float32(col)*2.5, cy+float32(row)*5
+				ctx.Canvas.DrawRoundedRect(core.R(x-1.25, y-1.25, 2.5, 2.5), 1.25, dot)
+			}
+		}
+	}
+	r.content.Paint(ctx)
+}
+
+// HandleEvent implements core.Widget. It picks the row up on a press on
+// the grip, or anywhere the content leaves unhandled without grips.
+func (r *reorderRow) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	e, ok := ev.(event.MouseEvent)
+	if !ok || e.Type != event.MouseDown || e.Button != event.ButtonLeft {
+		return core.Ignored
+	}
+	if r.owner.handles && e.Position.X >= r.Bounds().X+reorderHandleWidth {
+		return core.Ignored
+	}
+	r.owner.pressRow(ctx, r, e.Position)
+	return core.Handled
+}
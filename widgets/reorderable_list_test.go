@@ -0,0 +1,242 @@
+package widgets
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+)
+
+// reorderable returns a list of count rows 20 high, the indexes it built
+// and the moves it reported.
+func reorderable(count int) (*ReorderableList, *[]int, *[][2]int) {
+	var built []int
+	var moves [][2]int
+	l := NewReorderableList(count, func(i int) core.Widget {
+		built = append(built, i)
+		return &fixed{height: 20}
+	}).OnReorder(func(from, to int) { moves = append(moves, [2]int{from, to}) })
+	l.List().EstimatedItemHeight(20)
+	return l, &built, &moves
+}
+
+// reorderRowAt returns the realized row of l at index.
+func reorderRowAt(l *ReorderableList, index int) *reorderRow {
+	for _, c := range l.List().Children() {
+		if r := c.(*reorderRow); r.index == index {
+			return r
+		}
+	}
+	return nil
+}
+
+// shifts returns the shift of each realized row of l.
+func shifts(l *ReorderableList) []float32 {
+	var s []float32
+	for _, c := range l.List().Children() {
+		s = append(s, c.(*reorderRow).shift)
+	}
+	return s
+}
+
+func TestReorderableListDrag(t *testing.T) {
+	tests := []struct {
+		name   string
+		from   int
+		dy     float32
+		to     int
+		shifts []float32
+	}{
+		{"down", 1, 45, 3, []float32{0, 0, -20, -20, 0}},
+		{"up", 3, -50, 1, []float32{0, 20, 20, 0, 0}},
+		{"in place", 2, 5, 2, []float32{0, 0, 0, 0, 0}},
+		{"past the end", 1, 500, 4, []float32{0, 0, -20, -20, -20}},
+		{"past the start", 2, -500, 0, []float32{20, 20, 0, 0, 0}},
+	}
+	for _, tt := range tests {
+		l, built, moves := reorderable(5)
+		ctx := core.NewContext()
+		ctx.SetReducedMotion(true)
+		bounds := core.R(0, 0, 200, 100)
+		ctx.LayoutRoot(l, bounds)
+		*built = nil
+
+		grip := core.Pt(10, float32(tt.from)*20+10)
+		if r := reorderRowAt(l, tt.from).HandleEvent(ctx, leftMouse(event.MouseDown, grip)); r != core.Handled || ctx.PointerCapture() != l {
+			t.Fatalf("%s: a press on the grip = %v", tt.name, r)
+		}
+		l.HandleEvent(ctx, event.MouseEvent{Type: event.MouseMove, Position: grip.Add(core.Pt(0, tt.dy))})
+		ctx.LayoutRoot(l, bounds)
+		if !l.IsDragging() || l.to != tt.to || !slices.Equal(shifts(l), tt.shifts) {
+			t.Errorf("%s: dragging to %d with shifts %v, want %d and %v", tt.name, l.to, shifts(l), tt.to, tt.shifts)
+		}
+		l.HandleEvent(ctx, leftMouse(event.MouseUp, grip.Add(core.Pt(0, tt.dy))))
+		ctx.LayoutRoot(l, bounds)
+		var want [][2]int
+		if tt.to != tt.from {
+			want = [][2]int{{tt.from, tt.to}}
+		}
+		if l.IsDragging() || ctx.PointerCapture() != nil || !slices.Equal(*moves, want) {
+			t.Errorf("%s: dropped with moves %v, want %v", tt.name, *moves, want)
+		}
+		// The rows between the two positions are built again.
+		slices.Sort(*built)
+		var rebuilt []int
+		for i := min(tt.from, tt.to); i <= max(tt.from, tt.to) && tt.from != tt.to; i++ {
+			rebuilt = append(rebuilt, i)
+		}
+		if !slices.Equal(*built, rebuilt) {
+			t.Errorf("%s: rebuilt %v, want %v", tt.name, *built, rebuilt)
+		}
+		if !slices.Equal(shifts(l), make([]float32, 5)) {
+			t.Errorf("%s: after the drop the shifts are %v", tt.name, shifts(l))
+		}
+	}
+}
+
+func TestReorderableListPress(t *testing.T) {
+	l, _, moves := reorderable(5)
+	ctx := layoutAt(l, core.R(0, 0, 200, 100))
+	row := reorderRowAt(l, 1)
+	if r := row.HandleEvent(ctx, leftMouse(event.MouseDown, core.Pt(100, 30))); r != core.Ignored {
+		t.Error("a press off the grip picked the row up")
+	}
+	if r := row.HandleEvent(ctx, event.MouseEvent{Type: event.MouseDown, Button: event.ButtonRight, Position: core.Pt(10, 30)}); r != core.Ignored {
+		t.Error("a right press picked the row up")
+	}
+	if r := l.HandleEvent(ctx, event.MouseEvent{Type: event.MouseMove, Position: core.Pt(10, 80)}); r != core.Ignored {
+		t.Error("a move without a press was handled")
+	}
+
+	// Moving within the slop does not start a drag.
+	row.HandleEvent(ctx, leftMouse(event.MouseDown, core.Pt(10, 30)))
+	l.HandleEvent(ctx, event.MouseEvent{Type: event.MouseMove, Position: core.Pt(12, 32)})
+	if l.IsDragging() {
+		t.Error("a move within the slop started a drag")
+	}
+	if r := l.HandleEvent(ctx, event.MouseEvent{Type: event.MouseUp, Button: event.ButtonRight}); r != core.Ignored {
+		t.Error("a right release was handled")
+	}
+	l.HandleEvent(ctx, leftMouse(event.MouseUp, core.Pt(12, 32)))
+	if len(*moves) != 0 || ctx.PointerCapture() != nil {
+		t.Errorf("a click moved %v", *moves)
+	}
+
+	// Without grips a row is picked up from anywhere.
+	l.DragHandles(false)
+	ctx.LayoutRoot(l, core.R(0, 0, 200, 100))
+	row = reorderRowAt(l, 1)
+	if got := row.content.Bounds(); got.X != 0 {
+		t.Errorf("without grips the content starts at %v", got.X)
+	}
+	if r := row.HandleEvent(ctx, leftMouse(event.MouseDown, core.Pt(100, 30))); r != core.Handled {
+		t.Error("without grips a press off the leading edge did not pick the row up")
+	}
+}
+
+func TestReorderableListAnimation(t *testing.T) {
+	l, _, _ := reorderable(5)
+	ctx := core.NewContext()
+	t0 := time.Now()
+	ctx.SetNow(t0)
+	bounds := core.R(0, 0, 200, 100)
+	ctx.LayoutRoot(l, bounds)
+	reorderRowAt(l, 0).HandleEvent(ctx, leftMouse(event.MouseDown, core.Pt(10, 10)))
+	l.HandleEvent(ctx, event.MouseEvent{Type: event.MouseMove, Position: core.Pt(10, 35)})
+	ctx.LayoutRoot(l, bounds)
+
+	// The row passed slides up over the tween.
+	passed := reorderRowAt(l, 1)
+	ctx.SetNow(t0.Add(reorderShift / 2))
+	ctx.LayoutRoot(l, bounds)
+	if passed.shift >= -10 || passed.shift <= -20 {
+		t.Errorf("halfway the row passed is shifted %v", passed.shift)
+	}
+	ctx.SetNow(t0.Add(reorderShift))
+	ctx.LayoutRoot(l, bounds)
+	if passed.shift != -20 || passed.Bounds().Y != 0 {
+		t.Errorf("the row passed is shifted %v, at %v", passed.shift, passed.Bounds())
+	}
+	// The dragged row follows the pointer, kept within the list.
+	if got := l.drag.Bounds(); got != core.R(0, 25, l.List().rowWidth, 20) {
+		t.Errorf("the dragged row at %v", got)
+	}
+	l.HandleEvent(ctx, event.MouseEvent{Type: event.MouseMove, Position: core.Pt(10, 1000)})
+	ctx.LayoutRoot(l, bounds)
+	if got := l.drag.Bounds(); got.Y != 90 {
+		t.Errorf("dragged below the list the row is at %v", got)
+	}
+}
+
+func TestReorderableListAutoScroll(t *testing.T) {
+	l, _, _ := reorderable(100)
+	ctx := core.NewContext()
+	ctx.SetReducedMotion(true)
+	t0 := time.Now()
+	ctx.SetNow(t0)
+	bounds := core.R(0, 0, 200, 100)
+	ctx.LayoutRoot(l, bounds)
+	reorderRowAt(l, 1).HandleEvent(ctx, leftMouse(event.MouseDown, core.Pt(10, 30)))
+	l.HandleEvent(ctx, event.MouseEvent{Type: event.MouseMove, Position: core.Pt(10, 100)})
+	ctx.LayoutRoot(l, bounds)
+	if l.List().ScrollOffset() != 0 {
+		t.Fatal("the list scrolled before any time passed")
+	}
+	ctx.SetNow(t0.Add(100 * time.Millisecond))
+	ctx.LayoutRoot(l, bounds)
+	if got := l.List().ScrollOffset(); got != reorderScrollSpeed/10 {
+		t.Errorf("at the bottom edge scrolled %v in a tenth of a second, want %v", got, reorderScrollSpeed/10)
+	}
+	if l.to != 8 {
+		t.Errorf("scrolled, the row would land at %d", l.to)
+	}
+
+	// Halfway into the edge it scrolls at half the speed, and away from
+	// the edges it stops.
+	l.HandleEvent(ctx, event.MouseEvent{Type: event.MouseMove, Position: core.Pt(10, reorderEdge/2)})
+	offset := l.List().ScrollOffset()
+	ctx.SetNow(t0.Add(200 * time.Millisecond))
+	ctx.LayoutRoot(l, bounds)
+	if got := offset - l.List().ScrollOffset(); got != reorderScrollSpeed/20 {
+		t.Errorf("near the top scrolled back %v, want %v", got, reorderScrollSpeed/20)
+	}
+	l.HandleEvent(ctx, event.MouseEvent{Type: event.MouseMove, Position: core.Pt(10, 50)})
+	offset = l.List().ScrollOffset()
+	ctx.SetNow(t0.Add(300 * time.Millisecond))
+	ctx.LayoutRoot(l, bounds)
+	if l.List().ScrollOffset() != offset || !l.scrollAt.IsZero() {
+		t.Error("the list kept scrolling away from the edges")
+	}
+}
+
+func TestReorderableListPaint(t *testing.T) {
+	l := NewReorderableList(3, func(i int) core.Widget { return NewText(strconv.Itoa(i)) })
+	ctx := core.NewContext()
+	ctx.SetReducedMotion(true)
+	bounds := core.R(0, 0, 200, 200)
+	ctx.LayoutRoot(l, bounds)
+	paint := func() []string {
+		cv := &textCanvas{}
+		l.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+		return cv.texts
+	}
+	if got := paint(); !equalStrings(got, []string{"0", "1", "2"}) {
+		t.Errorf("drew %q", got)
+	}
+	first := reorderRowAt(l, 0)
+	grip := core.Pt(10, first.Bounds().Center().Y)
+	first.HandleEvent(ctx, leftMouse(event.MouseDown, grip))
+	l.HandleEvent(ctx, event.MouseEvent{Type: event.MouseMove, Position: grip.Add(core.Pt(0, 10))})
+	ctx.LayoutRoot(l, bounds)
+	if got := paint(); !equalStrings(got, []string{"1", "2", "0"}) {
+		t.Errorf("dragging the first row drew %q, want it on top", got)
+	}
+
+	// A row that scrolls out and back in while dragged stays lifted.
+	if got := l.buildRow(0); got != first {
+		t.Error("building the dragged row again made a new one")
+	}
+}
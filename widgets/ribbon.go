@@ -0,0 +1,538 @@
+package widgets
+
+import (
+	"slices"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/theme"
+)
+
+const (
+	ribbonTabHeight      float32 = 28
+	ribbonTabPadding     float32 = 14
+	ribbonContextHeight  float32 = 18
+	ribbonIconSize       float32 = 32
+	ribbonButtonMinWidth float32 = 52
+	ribbonGroupPadding   float32 = 4
+	ribbonLabelGap       float32 = 4
+)
+
+// RibbonTab is a page of a Ribbon.
+type RibbonTab struct {
+	// Title is the label shown in the tab strip.
+	Title string
+
+	// Groups are the tab's groups of commands, left to right.
+	Groups []*RibbonGroup
+
+	context *RibbonContext
+}
+
+// NewRibbonTab returns a tab with the given groups.
+func NewRibbonTab(title string, groups ...*RibbonGroup) *RibbonTab {
+	return &RibbonTab{Title: title, Groups: groups}
+}
+
+// RibbonGroup is a captioned group of commands on a RibbonTab.
+type RibbonGroup struct {
+	// Title is the caption shown below the group.
+	Title string
+
+	// Items are the group's buttons and separators.
+	Items []*ToolItem
+}
+
+// NewRibbonGroup returns a group with the given items.
+func NewRibbonGroup(title string, items ...*ToolItem) *RibbonGroup {
+	return &RibbonGroup{Title: title, Items: items}
+}
+
+// RibbonContext is a set of contextual tabs, such as the tabs for editing
+// a selected picture, shown after the regular tabs under a colored
+// header while the context applies. Create one with Ribbon.AddContext.
+type RibbonContext struct {
+	// Title is the text of the header above the tabs.
+	Title string
+
+	// Color tints the header and the tabs' titles.
+	Color core.Color
+
+	tabs    []*RibbonTab
+	visible bool
+}
+
+// Tabs returns the context's tabs.
+func (c *RibbonContext) Tabs() []*RibbonTab {
+	return c.tabs
+}
+
+// IsVisible reports whether the context's tabs are shown.
+func (c *RibbonContext) IsVisible() bool {
+	return c.visible
+}
+
+// ribbonGroup is the layout of a RibbonGroup on the selected tab.
+type ribbonGroup struct {
+	group     *RibbonGroup
+	widths    []float32
+	full      float32
+	compact   float32
+	collapsed bool
+	rect      core.Rect
+	itemsX    float32
+}
+
+// Ribbon is an Office-style command area: a strip of tabs, each showing
+// captioned groups of large buttons below it.
+//
+// The buttons are ToolItems, with the same toggles, exclusive groups,
+// tooltips and keyboard behavior as in a Toolbar. When the groups of a tab
+// do not fit, they are collapsed, starting from the last, into single
+// buttons opening a menu of their items.
+//
+// Contextual tabs, added with AddContext, are hidden until ShowContext
+// shows them at the end of the strip under a header in their context's
+// color.
+//
+// Double-clicking a tab or pressing Ctrl+F1 minimizes the ribbon to its
+// tab strip, and again restores it. Ctrl+PageUp and Ctrl+PageDown switch
+// tabs while the ribbon has focus.
+type Ribbon struct {
+	core.WidgetBase
+	core.FocusState
+
+	tabs      []*RibbonTab
+	contexts  []*RibbonContext
+	selected  *RibbonTab
+	minimized bool
+	onSelect  func(tab *RibbonTab)
+
+	strip     []*RibbonTab
+	tabWidths []float32
+	tabRects  []core.Rect
+	header    float32
+	bodyH     float32
+	body      core.Rect
+	groups    []ribbonGroup
+	hoverTab  int
+	input     toolInput
+}
+
+// NewRibbon returns a ribbon with the given tabs, selecting the first.
+func NewRibbon(tabs ...*RibbonTab) *Ribbon {
+	r := &Ribbon{tabs: tabs, hoverTab: -1}
+	if len(tabs) > 0 {
+		r.selected = tabs[0]
+	}
+	r.input.reset()
+	return r
+}
+
+// Tabs returns the regular tabs.
+func (r *Ribbon) Tabs() []*RibbonTab {
+	return r.tabs
+}
+
+// AddTab appends a regular tab.
+func (r *Ribbon) AddTab(tab *RibbonTab) {
+	r.tabs = append(r.tabs, tab)
+	if r.selected == nil {
+		r.selected = tab
+	}
+}
+
+// AddContext adds a hidden set of contextual tabs and returns it.
+func (r *Ribbon) AddContext(title string, color core.Color, tabs ...*RibbonTab) *RibbonContext {
+	c := &RibbonContext{Title: title, Color: color, tabs: tabs}
+	for _, tab := range tabs {
+		tab.context = c
+	}
+	r.contexts = append(r.contexts, c)
+	return c
+}
+
+// ShowContext shows or hides the tabs of c. Hiding the context of the
+// selected tab selects the first tab.
+func (r *Ribbon) ShowContext(c *RibbonContext, visible bool) {
+	c.visible = visible
+	if !visible && r.selected != nil && r.selected.context == c {
+		if tabs := r.visibleTabs(); len(tabs) > 0 {
+			r.Select(tabs[0])
+		} else {
+			r.selected = nil
+		}
+	}
+}
+
+// Selected returns the selected tab, or nil if there are none.
+func (r *Ribbon) Selected() *RibbonTab {
+	return r.selected
+}
+
+// Select selects tab if it is shown.
+func (r *Ribbon) Select(tab *RibbonTab) {
+	if tab == r.selected || !slices.Contains(r.visibleTabs(), tab) {
+		return
+	}
+	r.selected = tab
+	r.input.reset()
+	if r.onSelect != nil {
+		r.onSelect(tab)
+	}
+}
+
+// OnSelect sets the function called when another tab is selected.
+func (r *Ribbon) OnSelect(fn func(tab *RibbonTab)) *Ribbon {
+	r.onSelect = fn
+	return r
+}
+
+// Minimize shows only the tab strip, or restores the groups.
+func (r *Ribbon) Minimize(minimized bool) *Ribbon {
+	r.minimized = minimized
+	r.input.reset()
+	return r
+}
+
+// IsMinimized reports whether only the tab strip is shown.
+func (r *Ribbon) IsMinimized() bool {
+	return r.minimized
+}
+
+func (r *Ribbon) visibleTabs() []*RibbonTab {
+	tabs := slices.Clip(r.tabs)
+	for _, c := range r.contexts {
+		if c.visible {
+			tabs = append(tabs, c.tabs...)
+		}
+	}
+	return tabs
+}
+
+// allItems returns the items of every tab, the scope of toggle groups.
+func (r *Ribbon) allItems() []*ToolItem {
+	var items []*ToolItem
+	add := func(tabs []*RibbonTab) {
+		for _, tab := range tabs {
+			for _, g := range tab.Groups {
+				items = append(items, g.Items...)
+			}
+		}
+	}
+	add(r.tabs)
+	for _, c := range r.contexts {
+		add(c.tabs)
+	}
+	return items
+}
+
+// Layout implements core.Widget.
+func (r *Ribbon) Layout(ctx *core.LayoutContext) core.Size {
+	th := theme.From(ctx.Context)
+	label, caption := th.Typography.Label, th.Typography.Caption
+	r.strip = r.visibleTabs()
+	if !slices.Contains(r.strip, r.selected) {
+		r.selected = nil
+		if len(r.strip) > 0 {
+			r.selected = r.strip[0]
+		}
+	}
+	r.tabWidths = r.tabWidths[:0]
+	stripW := ribbonGroupPadding
+	for _, tab := range r.strip {
+		w := ctx.MeasureText(tab.Title, label).Width + 2*ribbonTabPadding
+		r.tabWidths = append(r.tabWidths, w)
+		stripW += w
+	}
+	r.header = 0
+	if slices.ContainsFunc(r.contexts, func(c *RibbonContext) bool { return c.visible && len(c.tabs) > 0 }) {
+		r.header = ribbonContextHeight
+	}
+
+	r.groups = r.groups[:0]
+	var groupsW float32
+	if r.selected != nil && !r.minimized {
+		icon := core.Tight(core.Sz(ribbonIconSize, ribbonIconSize))
+		for _, g := range r.selected.Groups {
+			rg := ribbonGroup{group: g}
+			var w float32
+			for _, it := range g.Items {
+				iw := toolSeparatorWidth
+				if !it.separator {
+					if it.Icon != nil {
+						ctx.Measure(it.Icon, icon)
+					}
+					iw = max(ribbonButtonMinWidth, ctx.MeasureText(it.Label, label).Width+2*toolButtonPadding)
+				}
+				rg.widths = append(rg.widths, iw)
+				w += iw
+			}
+			title := ctx.MeasureText(g.Title, caption).Width + 2*toolButtonPadding
+			rg.full = max(w, title) + 2*ribbonGroupPadding + 1
+			rg.compact = min(rg.full, max(ribbonButtonMinWidth, title)+2*ribbonGroupPadding+1)
+			r.groups = append(r.groups, rg)
+			groupsW += rg.full
+		}
+	}
+	r.bodyH = 0
+	if !r.minimized {
+		button := 2*toolButtonPadding + ribbonIconSize + ribbonLabelGap + label.LineHeight()
+		r.bodyH = 2*ribbonGroupPadding + button + caption.LineHeight()
+	}
+	size := core.Sz(max(stripW, groupsW), r.header+ribbonTabHeight+r.bodyH)
+	if ctx.Constraints.HasBoundedWidth() {
+		size.Width = ctx.Constraints.MaxWidth
+	}
+	return ctx.Constraints.Constrain(size)
+}
+
+// SetBounds implements core.Widget.
+func (r *Ribbon) SetBounds(b core.Rect) {
+	r.WidgetBase.SetBounds(b)
+	y := b.Y + r.header
+	x := b.X + ribbonGroupPadding
+	r.tabRects = r.tabRects[:0]
+	for _, w := range r.tabWidths {
+		r.tabRects = append(r.tabRects, core.R(x, y, w, ribbonTabHeight))
+		x += w
+	}
+	r.body = core.R(b.X, y+ribbonTabHeight, b.Width, r.bodyH)
+	r.input.targets = r.input.targets[:0]
+
+	var total float32
+	for i := range r.groups {
+		r.groups[i].collapsed = false
+		total += r.groups[i].full
+	}
+	for i := len(r.groups) - 1; i >= 0 && total > b.Width; i-- {
+		g := &r.groups[i]
+		g.collapsed = true
+		total -= g.full - g.compact
+	}
+	var tips []core.Widget
+	placeIcon := func(icon core.Widget, br core.Rect) {
+		icon.SetBounds(core.R(br.X+(br.Width-ribbonIconSize)/2, br.Y+toolButtonPadding, ribbonIconSize, ribbonIconSize))
+	}
+	gx := r.body.X
+	captionH := r.bodyH - 2*ribbonGroupPadding - 2*toolButtonPadding - ribbonIconSize - ribbonLabelGap
+	for i := range r.groups {
+		g := &r.groups[i]
+		w := g.full
+		if g.collapsed {
+			w = g.compact
+		}
+		g.rect = core.R(gx, r.body.Y, w, r.body.Height)
+		gx += w
+		ix, iy := g.rect.X+ribbonGroupPadding, g.rect.Y+ribbonGroupPadding
+		ih := max(0, g.rect.Height-2*ribbonGroupPadding-captionH)
+		if g.collapsed {
+			tg := toolTarget{rect: core.R(ix, iy, w-2*ribbonGroupPadding-1, ih), menu: g.group.Items}
+			for _, it := range g.group.Items {
+				if it.Icon != nil {
+					tg.icon = it.Icon
+					placeIcon(it.Icon, tg.rect)
+					break
+				}
+			}
+			r.input.targets = append(r.input.targets, tg)
+			continue
+		}
+		// Center the buttons when the caption is wider than they are.
+		var itemsW float32
+		for _, iw := range g.widths {
+			itemsW += iw
+		}
+		ix += (w - 2*ribbonGroupPadding - 1 - itemsW) / 2
+		g.itemsX = ix
+		for j, it := range g.group.Items {
+			br := core.R(ix, iy, g.widths[j], ih)
+			ix += g.widths[j]
+			if it.separator {
+				continue
+			}
+			if it.Icon != nil {
+				placeIcon(it.Icon, br)
+			}
+			r.input.targets = append(r.input.targets, toolTarget{rect: br, item: it})
+			if tip := it.tipArea(br); tip != nil {
+				tips = append(tips, tip)
+			}
+		}
+	}
+	r.SetChildren(tips...)
+}
+
+func (r *Ribbon) tabAt(p core.Point) int {
+	for i, tr := range r.tabRects {
+		if tr.Contains(p) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *Ribbon) tabColor(th *theme.Theme, tab *RibbonTab) core.Color {
+	if tab.context != nil {
+		return tab.context.Color
+	}
+	return th.Colors.Primary
+}
+
+// Paint implements core.Widget.
+func (r *Ribbon) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	b := r.Bounds()
+	bg := th.Colors.Surface
+	cv.DrawRect(core.R(b.X, b.Y, b.Width, r.header+ribbonTabHeight), core.Filled(th.Colors.SurfaceVariant))
+	r.paintContexts(ctx)
+
+	label := theme.TextStyle(th.Typography.Label, th.Colors.OnSurface)
+	for i, tab := range r.strip {
+		tr := r.tabRects[i]
+		style := label
+		switch {
+		case tab == r.selected:
+			if !r.minimized {
+				cv.DrawRect(tr, core.Filled(bg))
+			}
+			style.Color = r.tabColor(th, tab)
+			cv.DrawRect(core.R(tr.X+ribbonTabPadding/2, tr.Bottom()-2, tr.Width-ribbonTabPadding, 2), core.Filled(style.Color))
+		case i == r.hoverTab:
+			cv.DrawRect(tr, core.Filled(th.Colors.OnSurface.WithAlpha(0.06)))
+		}
+		cv.DrawText(tab.Title, core.Pt(tr.X+ribbonTabPadding, tr.Y+(tr.Height-style.LineHeight())/2), style)
+	}
+	if r.minimized {
+		return
+	}
+
+	cv.DrawRect(r.body, core.Filled(bg))
+	cv.DrawRect(core.R(r.body.X, r.body.Bottom()-1, r.body.Width, 1), core.Filled(th.Colors.Outline.WithAlpha(0.4)))
+	caption := theme.TextStyle(th.Typography.Caption, th.Colors.OnSurfaceVariant)
+	for _, g := range r.groups {
+		gr := g.rect
+		tw := ctx.MeasureText(g.group.Title, caption).Width
+		cv.DrawText(g.group.Title, core.Pt(gr.X+(gr.Width-1-tw)/2, gr.Bottom()-ribbonGroupPadding-caption.LineHeight()), caption)
+		cv.DrawRect(core.R(gr.Right()-1, gr.Y+ribbonGroupPadding, 1, gr.Height-2*ribbonGroupPadding), core.Filled(th.Colors.Outline.WithAlpha(0.4)))
+		if !g.collapsed {
+			for j, it := range g.group.Items {
+				if it.separator {
+					x := g.itemsX + g.widthsBefore(j) + toolSeparatorWidth/2
+					cv.DrawRect(core.R(x, gr.Y+ribbonGroupPadding+4, 1, gr.Height-2*ribbonGroupPadding-8), core.Filled(th.Colors.Outline.WithAlpha(0.4)))
+				}
+			}
+		}
+	}
+	for i, tg := range r.input.targets {
+		r.input.paintState(ctx, i, r.IsFocused())
+		tr := tg.rect
+		color := toolLabelColor(th, tg.item)
+		ly := tr.Y + toolButtonPadding + ribbonIconSize + ribbonLabelGap
+		if tg.item == nil {
+			if tg.icon != nil {
+				tg.icon.Paint(ctx)
+			}
+			paintDisclosure(ctx, core.R(tr.X, ly, tr.Width, label.LineHeight()), true, color)
+			continue
+		}
+		it := tg.item
+		paintToolIcon(ctx, it, bg)
+		style := theme.TextStyle(th.Typography.Label, color)
+		w := ctx.MeasureText(it.Label, style).Width
+		cv.DrawText(it.Label, core.Pt(tr.X+(tr.Width-w)/2, ly), style)
+	}
+}
+
+// widthsBefore returns the width of the items before item j.
+func (g *ribbonGroup) widthsBefore(j int) float32 {
+	var w float32
+	for _, iw := range g.widths[:j] {
+		w += iw
+	}
+	return w
+}
+
+// paintContexts draws the headers of the visible contexts above their
+// tabs.
+func (r *Ribbon) paintContexts(ctx *core.PaintContext) {
+	if r.header == 0 {
+		return
+	}
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	style := theme.TextStyle(th.Typography.Caption, th.Colors.OnSurface)
+	for _, c := range r.contexts {
+		first := slices.IndexFunc(r.strip, func(t *RibbonTab) bool { return t.context == c })
+		if !c.visible || first < 0 {
+			continue
+		}
+		last := first + len(c.tabs) - 1
+		x0, x1 := r.tabRects[first].X, r.tabRects[last].Right()
+		band := core.R(x0, r.Bounds().Y, x1-x0, r.header)
+		cv.DrawRect(band, core.Filled(c.Color.WithAlpha(0.25)))
+		cv.DrawRect(core.R(x0, band.Bottom(), x1-x0, ribbonTabHeight), core.Filled(c.Color.WithAlpha(0.08)))
+		cv.Save()
+		cv.Clip(band)
+		w := ctx.MeasureText(c.Title, style).Width
+		cv.DrawText(c.Title, core.Pt(band.X+max(ribbonGroupPadding, (band.Width-w)/2), band.Y+(band.Height-style.LineHeight())/2), style)
+		cv.Restore()
+	}
+}
+
+// HandleEvent implements core.Widget.
+func (r *Ribbon) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	switch e := ev.(type) {
+	case event.MouseEvent:
+		i := r.tabAt(e.Position)
+		switch e.Type {
+		case event.MouseEnter, event.MouseMove:
+			if i != r.hoverTab {
+				r.hoverTab = i
+				ctx.Repaint(r)
+			}
+		case event.MouseLeave:
+			if r.hoverTab >= 0 {
+				r.hoverTab = -1
+				ctx.Repaint(r)
+			}
+		case event.MouseDown:
+			if i < 0 || e.Button != event.ButtonLeft {
+				break
+			}
+			r.input.closeMenu(ctx)
+			r.Select(r.strip[i])
+			if e.ClickCount == 2 {
+				r.Minimize(!r.minimized)
+			}
+			ctx.MarkNeedsLayout(r)
+			return core.Handled
+		}
+	case event.KeyEvent:
+		if r.IsFocused() && e.Type == event.KeyPress && e.Modifiers == event.ModCtrl &&
+			(e.Key == event.KeyPageUp || e.Key == event.KeyPageDown) && len(r.strip) > 0 {
+			delta := 1
+			if e.Key == event.KeyPageUp {
+				delta = -1
+			}
+			n := len(r.strip)
+			r.Select(r.strip[(slices.Index(r.strip, r.selected)+delta+n)%n])
+			ctx.MarkNeedsLayout(r)
+			return core.Handled
+		}
+	}
+	return r.input.handle(ctx, r, r.IsFocused(), ev, r.allItems())
+}
+
+// HandleShortcut implements core.ShortcutHandler, minimizing or restoring
+// the ribbon on Ctrl+F1.
+func (r *Ribbon) HandleShortcut(ctx *core.Context, ev core.Event) core.EventResult {
+	e, ok := ev.(event.KeyEvent)
+	if !ok || e.Type != event.KeyPress || e.Key != event.KeyF1 || e.Modifiers != event.ModCtrl {
+		return core.Ignored
+	}
+	r.Minimize(!r.minimized)
+	ctx.MarkNeedsLayout(r)
+	return core.Handled
+}
@@ -0,0 +1,206 @@
+package widgets
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+)
+
+// newRibbon returns a ribbon with the tabs Home and View, and a hidden
+// context with the tab Format.
+func newRibbon() (*Ribbon, *RibbonContext) {
+	r := NewRibbon(
+		NewRibbonTab("Home",
+			NewRibbonGroup("Clipboard", NewToolButton("Paste", icon(), nil), ToolSeparator(), NewToolButton("Cut", icon(), nil)),
+			NewRibbonGroup("Font", NewToolButton("Bold", icon(), nil), NewToolButton("Italic", icon(), nil)),
+		),
+		NewRibbonTab("View", NewRibbonGroup("Zoom", NewToolButton("Zoom", nil, nil))),
+	)
+	c := r.AddContext("Picture Tools", core.Hex(0x8844cc), NewRibbonTab("Format", NewRibbonGroup("Size", NewToolButton("Crop", nil, nil))))
+	return r, c
+}
+
+func tabTitles(tabs []*RibbonTab) []string {
+	var titles []string
+	for _, tab := range tabs {
+		titles = append(titles, tab.Title)
+	}
+	return titles
+}
+
+func TestRibbonTabs(t *testing.T) {
+	r, c := newRibbon()
+	var selected []string
+	r.OnSelect(func(tab *RibbonTab) { selected = append(selected, tab.Title) })
+	if r.Selected() != r.Tabs()[0] || c.IsVisible() {
+		t.Fatalf("selected %q, context visible %v", r.Selected().Title, c.IsVisible())
+	}
+	format := c.Tabs()[0]
+	r.Select(format)
+	if r.Selected() != r.Tabs()[0] {
+		t.Error("a hidden contextual tab was selected")
+	}
+	r.ShowContext(c, true)
+	if got := tabTitles(r.visibleTabs()); !equalStrings(got, []string{"Home", "View", "Format"}) {
+		t.Errorf("tabs shown %q", got)
+	}
+	r.Select(format)
+	r.Select(format)
+	r.ShowContext(c, false)
+	if r.Selected() != r.Tabs()[0] || !equalStrings(selected, []string{"Format", "Home"}) {
+		t.Errorf("selected %q, want the first tab after hiding the context", selected)
+	}
+	if got := tabTitles(r.Tabs()); len(got) != 2 {
+		t.Errorf("regular tabs %q", got)
+	}
+
+	empty := NewRibbon()
+	if empty.Selected() != nil {
+		t.Error("an empty ribbon has a selection")
+	}
+	empty.AddTab(NewRibbonTab("First"))
+	empty.AddTab(NewRibbonTab("Second"))
+	if empty.Selected().Title != "First" {
+		t.Errorf("AddTab selected %q", empty.Selected().Title)
+	}
+}
+
+func TestRibbonLayout(t *testing.T) {
+	r, c := newRibbon()
+	lc := &core.LayoutContext{Context: core.NewContext()}
+	full := lc.Measure(r, core.Unbounded())
+	if r.header != 0 || len(r.groups) != 2 || full.Height != ribbonTabHeight+r.bodyH {
+		t.Errorf("size %v with a header of %v", full, r.header)
+	}
+	r.ShowContext(c, true)
+	if got := lc.Measure(r, core.Unbounded()); got.Height != full.Height+ribbonContextHeight {
+		t.Errorf("with a context the height is %v, want %v", got.Height, full.Height+ribbonContextHeight)
+	}
+	r.Minimize(true)
+	if got := lc.Measure(r, core.Unbounded()); got.Height != ribbonContextHeight+ribbonTabHeight || !r.IsMinimized() {
+		t.Errorf("minimized the height is %v", got.Height)
+	}
+	r.Minimize(false)
+	r.ShowContext(c, false)
+
+	// The groups collapse from the last when they do not fit.
+	ctx := layoutAt(r, core.R(0, 0, full.Width, 200))
+	if r.groups[0].collapsed || r.groups[1].collapsed || len(r.input.targets) != 4 {
+		t.Fatalf("at full width %d targets", len(r.input.targets))
+	}
+	clipboard := r.groups[0]
+	ctx.LayoutRoot(r, core.R(0, 0, full.Width-1, 200))
+	if r.groups[0].collapsed || !r.groups[1].collapsed || len(r.input.targets) != 3 {
+		t.Errorf("a little narrower: collapsed %v %v", r.groups[0].collapsed, r.groups[1].collapsed)
+	}
+	font := r.input.targets[2]
+	if font.item != nil || len(font.menu) != 2 || font.icon != r.Selected().Groups[1].Items[0].Icon {
+		t.Errorf("the collapsed group is %+v", font)
+	}
+	ctx.LayoutRoot(r, core.R(0, 0, clipboard.compact, 200))
+	if !r.groups[0].collapsed || len(r.input.targets) != 2 {
+		t.Errorf("narrow: %d targets", len(r.input.targets))
+	}
+
+	r.HandleEvent(ctx, leftMouse(event.MouseDown, r.input.targets[0].rect.Center()))
+	popups := layoutOverlays(ctx)
+	if len(popups) != 1 || len(popups[0].items) != 3 || popups[0].items[2].Label != "Cut" {
+		t.Fatalf("the collapsed group opened %+v", popups)
+	}
+	// Selecting a tab closes the menu.
+	r.HandleEvent(ctx, leftMouse(event.MouseDown, r.tabRects[1].Center()))
+	if len(ctx.Overlays()) != 0 || r.Selected().Title != "View" {
+		t.Errorf("%d overlays after selecting %q", len(ctx.Overlays()), r.Selected().Title)
+	}
+}
+
+func TestRibbonEvents(t *testing.T) {
+	r, c := newRibbon()
+	ctx := layoutAt(r, core.R(0, 0, 800, 200))
+	tab := func(i int) core.Point { return r.tabRects[i].Center() }
+
+	r.HandleEvent(ctx, event.MouseEvent{Type: event.MouseMove, Position: tab(1)})
+	if r.hoverTab != 1 {
+		t.Errorf("hovering the second tab set %d", r.hoverTab)
+	}
+	r.HandleEvent(ctx, event.MouseEvent{Type: event.MouseLeave})
+	if r.hoverTab != -1 {
+		t.Errorf("after leaving the hover is %d", r.hoverTab)
+	}
+
+	if res := r.HandleEvent(ctx, leftMouse(event.MouseDown, tab(1))); res != core.Handled || r.Selected().Title != "View" {
+		t.Errorf("a click on View = %v, selected %q", res, r.Selected().Title)
+	}
+	double := leftMouse(event.MouseDown, tab(0))
+	double.ClickCount = 2
+	r.HandleEvent(ctx, double)
+	if !r.IsMinimized() || r.Selected().Title != "Home" {
+		t.Errorf("a double click minimized %v, selected %q", r.IsMinimized(), r.Selected().Title)
+	}
+	ctrlF1 := press(event.KeyF1, event.ModCtrl)
+	if res := r.HandleShortcut(ctx, ctrlF1); res != core.Handled || r.IsMinimized() {
+		t.Error("Ctrl+F1 did not restore the ribbon")
+	}
+	if res := r.HandleShortcut(ctx, press(event.KeyF1, 0)); res != core.Ignored {
+		t.Error("F1 alone toggled the ribbon")
+	}
+
+	r.ShowContext(c, true)
+	ctx.LayoutRoot(r, core.R(0, 0, 800, 200))
+	if res := r.HandleEvent(ctx, press(event.KeyPageDown, event.ModCtrl)); res != core.Ignored {
+		t.Error("an unfocused ribbon switched tabs")
+	}
+	ctx.RequestFocus(r)
+	tests := []struct {
+		key  event.Key
+		want string
+	}{
+		{event.KeyPageDown, "View"},
+		{event.KeyPageDown, "Format"},
+		{event.KeyPageDown, "Home"},
+		{event.KeyPageUp, "Format"},
+	}
+	for _, tt := range tests {
+		r.HandleEvent(ctx, press(tt.key, event.ModCtrl))
+		if got := r.Selected().Title; got != tt.want {
+			t.Errorf("Ctrl+%v selected %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestRibbonToggleGroups(t *testing.T) {
+	left := (&ToolItem{Label: "Left", Checked: true}).InGroup("align")
+	right := (&ToolItem{Label: "Right"}).InGroup("align")
+	r := NewRibbon(NewRibbonTab("A", NewRibbonGroup("", left)))
+	r.AddContext("C", core.Black, NewRibbonTab("B", NewRibbonGroup("", right)))
+	r.ShowContext(r.contexts[0], true)
+	r.Select(r.contexts[0].Tabs()[0])
+	ctx := layoutAt(r, core.R(0, 0, 800, 200))
+	p := r.input.targets[0].rect.Center()
+	r.HandleEvent(ctx, leftMouse(event.MouseDown, p))
+	r.HandleEvent(ctx, leftMouse(event.MouseUp, p))
+	if !right.Checked || left.Checked {
+		t.Errorf("Left %v, Right %v: the group does not span the tabs", left.Checked, right.Checked)
+	}
+}
+
+func TestRibbonPaint(t *testing.T) {
+	r, c := newRibbon()
+	r.ShowContext(c, true)
+	ctx := layoutAt(r, core.R(0, 0, 800, 200))
+	paint := func() []string {
+		cv := &textCanvas{}
+		r.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+		return cv.texts
+	}
+	want := []string{"Picture Tools", "Home", "View", "Format", "Clipboard", "Font", "Paste", "Cut", "Bold", "Italic"}
+	if got := paint(); !equalStrings(got, want) {
+		t.Errorf("drew %q, want %q", got, want)
+	}
+	r.Minimize(true)
+	ctx.LayoutRoot(r, core.R(0, 0, 800, 200))
+	if got := paint(); !equalStrings(got, want[:4]) {
+		t.Errorf("minimized drew %q", got)
+	}
+}
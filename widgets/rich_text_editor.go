@@ -0,0 +1,758 @@
+package widgets
+
+import (
+	"strconv"
+	"unicode"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/grapheme"
+	"github.com/gogpu/ui/internal/scroll"
+	"github.com/gogpu/ui/richtext"
+	"github.com/gogpu/ui/theme"
+)
+
+// RichTextEditor metrics.
+const (
+	richTextWidth  float32 = 400
+	richTextHeight float32 = 240
+	richTextIndent float32 = 24 // per list level
+	richTextGap    float32 = 6  // between blocks
+	richTextLevels         = 6  // deepest list nesting
+)
+
+// richTextBullets are the list markers of successive nesting levels.
+var richTextBullets = [...]string{"•", "◦", "▪"}
+
+// RichTextEditor edits formatted text: paragraphs, headings and nested
+// bulleted and numbered lists holding bold, italic, underlined and linked
+// text and inline images. The content is a richtext.Document, which the
+// application can serialize to HTML or Markdown.
+//
+// Ctrl+B, Ctrl+I and Ctrl+U toggle the formats of the selection, or of the
+// text about to be typed. In a list, Tab and Shift+Tab change the nesting
+// level, Enter on an empty item ends the list and Backspace at the start
+// of an item outdents it. Ctrl+click on a link reports it to OnLink.
+// Formats, block kinds, links and images can also be applied from a
+// toolbar with ToggleStyle, SetBlockKind, SetLink and InsertImage.
+type RichTextEditor struct {
+	core.WidgetBase
+	core.FocusState
+
+	doc           *richtext.Document
+	caret, anchor richtext.Pos
+	goalX         float32 // x kept while moving up and down, or -1
+	pending       bool    // typing uses pendingStyle
+	pendingStyle  richtext.Style
+	placeholder   string
+	onChange      func()
+	onLink        func(url string)
+
+	lines    []rtLine
+	height   float32 // of the laid out content
+	content  core.Rect
+	offset   float32
+	bar      scroll.Bar
+	reveal   bool // scroll the caret into view on the next layout
+	dragging bool
+}
+
+// NewRichTextEditor returns an editor holding an empty document.
+func NewRichTextEditor() *RichTextEditor {
+	return &RichTextEditor{doc: richtext.New(), goalX: -1}
+}
+
+// Placeholder sets the hint shown while the document is empty.
+func (e *RichTextEditor) Placeholder(s string) *RichTextEditor {
+	e.placeholder = s
+	return e
+}
+
+// OnChange registers fn to be called after every edit.
+func (e *RichTextEditor) OnChange(fn func()) *RichTextEditor {
+	e.onChange = fn
+	return e
+}
+
+// OnLink registers fn to be called with the URL of a link the user
+// Ctrl+clicks.
+func (e *RichTextEditor) OnLink(fn func(url string)) *RichTextEditor {
+	e.onLink = fn
+	return e
+}
+
+// Document returns a copy of the edited document.
+func (e *RichTextEditor) Document() *richtext.Document {
+	return e.doc.Clone()
+}
+
+// SetDocument replaces the content with a copy of d, without calling
+// OnChange, and puts the caret at the start.
+func (e *RichTextEditor) SetDocument(d *richtext.Document) {
+	e.doc = richtext.New(d.Clone().Blocks...)
+	e.caret, e.anchor = e.doc.Start(), e.doc.Start()
+	e.pending = false
+	e.offset = 0
+}
+
+// Selection returns the selected range, start first.
+func (e *RichTextEditor) Selection() (from, to richtext.Pos) {
+	if e.caret.Less(e.anchor) {
+		return e.caret, e.anchor
+	}
+	return e.anchor, e.caret
+}
+
+func (e *RichTextEditor) hasSelection() bool {
+	return e.caret != e.anchor
+}
+
+// HasStyle reports whether the selection, or the text about to be typed,
+// has the formats in s, as for the pressed state of a toolbar button.
+func (e *RichTextEditor) HasStyle(s richtext.Style) bool {
+	if !e.hasSelection() {
+		return e.typingStyle().Has(s)
+	}
+	from, to := e.Selection()
+	return e.doc.HasStyle(from, to, s)
+}
+
+// ToggleStyle toggles the formats in s on the selection or, if nothing is
+// selected, for the text about to be typed.
+func (e *RichTextEditor) ToggleStyle(s richtext.Style) {
+	if !e.hasSelection() {
+		e.pendingStyle = e.typingStyle() ^ s
+		e.pending = true
+		return
+	}
+	from, to := e.Selection()
+	e.doc.ToggleStyle(from, to, s)
+	e.edited(nil)
+}
+
+// SetBlockKind changes the blocks touched by the selection to kind. level
+// is the heading level or list nesting depth.
+func (e *RichTextEditor) SetBlockKind(kind richtext.BlockKind, level int) {
+	from, to := e.Selection()
+	e.doc.SetKind(from.Block, to.Block, kind, min(level, richTextLevels-1))
+	e.edited(nil)
+}
+
+// SetLink links the selection to url, or unlinks it if url is empty. With
+// nothing selected, the link under the caret is changed.
+func (e *RichTextEditor) SetLink(url string) {
+	from, to := e.Selection()
+	if from == to {
+		from, to = e.linkAround(from)
+	}
+	e.doc.SetLink(from, to, url)
+	e.edited(nil)
+}
+
+// linkAround returns the extent of the link at p, or an empty range.
+func (e *RichTextEditor) linkAround(p richtext.Pos) (richtext.Pos, richtext.Pos) {
+	link := e.doc.LinkAt(p)
+	if link == "" && p.Offset > 0 {
+		link = e.doc.LinkAt(richtext.Pos{Block: p.Block, Offset: p.Offset - 1})
+	}
+	if link == "" {
+		return p, p
+	}
+	from, to := p, p
+	for from.Offset > 0 && e.doc.LinkAt(richtext.Pos{Block: p.Block, Offset: from.Offset - 1}) == link {
+		from.Offset--
+	}
+	for to.Offset < e.doc.Blocks[p.Block].Len() && e.doc.LinkAt(to) == link {
+		to.Offset++
+	}
+	return from, to
+}
+
+// InsertImage replaces the selection with img.
+func (e *RichTextEditor) InsertImage(img *richtext.Image) {
+	e.deleteSelection()
+	e.caret = e.doc.InsertImage(e.caret, img, "")
+	e.anchor = e.caret
+	e.edited(nil)
+}
+
+// typingStyle returns the formats text typed at the caret takes on.
+func (e *RichTextEditor) typingStyle() richtext.Style {
+	if e.pending {
+		return e.pendingStyle
+	}
+	s, _ := e.doc.FormatAt(e.caret)
+	return s
+}
+
+// edited records a change to the document. ctx is nil when the change came
+// from the application rather than the user.
+func (e *RichTextEditor) edited(ctx *core.Context) {
+	e.goalX = -1
+	e.reveal = true
+	if ctx != nil {
+		ctx.MarkNeedsLayout(e)
+	}
+	if e.onChange != nil {
+		e.onChange()
+	}
+}
+
+func (e *RichTextEditor) deleteSelection() bool {
+	if !e.hasSelection() {
+		return false
+	}
+	from, to := e.Selection()
+	e.caret = e.doc.Delete(from, to)
+	e.anchor = e.caret
+	return true
+}
+
+// insert replaces the selection with text typed by the user.
+func (e *RichTextEditor) insert(ctx *core.Context, text string) {
+	style := e.typingStyle()
+	e.deleteSelection()
+	// Typing continues a link only inside it, not at its end.
+	_, link := e.doc.FormatAt(e.caret)
+	if link != "" && e.doc.LinkAt(e.caret) != link {
+		link = ""
+	}
+	e.caret = e.doc.Insert(e.caret, text, style, link)
+	e.anchor = e.caret
+	e.pending = false
+	e.edited(ctx)
+}
+
+// moveTo moves the caret, extending the selection if extend is set.
+func (e *RichTextEditor) moveTo(ctx *core.Context, p richtext.Pos, extend bool) {
+	e.caret = e.doc.Clamp(p)
+	if !extend {
+		e.anchor = e.caret
+	}
+	e.pending = false
+	e.reveal = true
+	ctx.MarkNeedsLayout(e)
+}
+
+// blockRunes returns a rune for each position of b, with U+FFFC standing
+// for images.
+func blockRunes(b *richtext.Block) []rune {
+	var rs []rune
+	for _, s := range b.Spans {
+		if s.Image != nil {
+			rs = append(rs, '\uFFFC')
+		} else {
+			rs = append(rs, []rune(s.Text)...)
+		}
+	}
+	return rs
+}
+
+// step returns the position one character, or one word, before or after
+// p, crossing into the neighboring block at either end.
+func (e *RichTextEditor) step(p richtext.Pos, forward, word bool) richtext.Pos {
+	text := blockRunes(&e.doc.Blocks[p.Block])
+	n := e.doc.Blocks[p.Block].Len()
+	isWord := func(i int) bool {
+		return i < len(text) && (unicode.IsLetter(text[i]) || unicode.IsDigit(text[i]))
+	}
+	switch {
+	case forward && p.Offset >= n:
+		if p.Block+1 < len(e.doc.Blocks) {
+			return richtext.Pos{Block: p.Block + 1}
+		}
+		return p
+	case !forward && p.Offset == 0:
+		if p.Block > 0 {
+			return richtext.Pos{Block: p.Block - 1, Offset: e.doc.Blocks[p.Block-1].Len()}
+		}
+		return p
+	case !word:
+		if forward {
+			p.Offset = grapheme.Next(text, p.Offset)
+		} else {
+			p.Offset = grapheme.Prev(text, p.Offset)
+		}
+		return p
+	case forward:
+		for p.Offset < n && !isWord(p.Offset) {
+			p.Offset++
+		}
+		for p.Offset < n && isWord(p.Offset) {
+			p.Offset++
+		}
+	default:
+		for p.Offset > 0 && !isWord(p.Offset-1) {
+			p.Offset--
+		}
+		for p.Offset > 0 && isWord(p.Offset-1) {
+			p.Offset--
+		}
+	}
+	return p
+}
+
+// Layout implements core.Widget.
+func (e *RichTextEditor) Layout(ctx *core.LayoutContext) core.Size {
+	size := ctx.Constraints.Constrain(core.Sz(richTextWidth, richTextHeight))
+	e.layoutLines(ctx.Context, max(0, size.Width-2*fieldPadding-scroll.Thickness))
+	if e.reveal {
+		e.reveal = false
+		view := size.Height - 2*fieldPadding
+		c := e.caretRect(ctx.Context)
+		switch {
+		case c.Y < e.offset:
+			e.offset = c.Y
+		case c.Bottom() > e.offset+view:
+			e.offset = c.Bottom() - view
+		}
+	}
+	e.offset = core.Clamp(e.offset, 0, max(0, e.height-(size.Height-2*fieldPadding)))
+	return size
+}
+
+// blockStyle returns the base text style of a block.
+func blockStyle(th *theme.Theme, b *richtext.Block) core.TextStyle {
+	if b.Kind == richtext.Heading {
+		return headingStyle(th, b.Level)
+	}
+	st := th.Typography.Body
+	st.Color = th.Colors.OnSurface
+	return st
+}
+
+// layoutLines wraps the document into lines width wide.
+func (e *RichTextEditor) layoutLines(ctx *core.Context, width float32) {
+	th := theme.From(ctx)
+	e.lines = e.lines[:0]
+	var numbers []int
+	y := float32(0)
+	for bi := range e.doc.Blocks {
+		b := &e.doc.Blocks[bi]
+		base := blockStyle(th, b)
+		var indent float32
+		marker := ""
+		if b.Kind.IsList() {
+			indent = richTextIndent * float32(b.Level+1)
+			numbers = numbers[:min(len(numbers), b.Level+1)]
+			for len(numbers) < b.Level+1 {
+				numbers = append(numbers, 0)
+			}
+			if b.Kind == richtext.NumberedItem {
+				numbers[b.Level]++
+				marker = strconv.Itoa(numbers[b.Level]) + "."
+			} else {
+				numbers[b.Level] = 0
+				marker = richTextBullets[b.Level%len(richTextBullets)]
+			}
+		} else {
+			numbers = numbers[:0]
+		}
+		line := rtLine{block: bi, indent: indent, marker: marker, y: y}
+		e.lines, y = wrapSpans(ctx, e.lines, b.Spans, base, width-indent, line)
+		y += richTextGap
+	}
+	e.height = max(0, y-richTextGap)
+}
+
+// lineOf returns the index of the line holding p.
+func (e *RichTextEditor) lineOf(p richtext.Pos) int {
+	found := 0
+	for i, l := range e.lines {
+		if l.block > p.Block {
+			break
+		}
+		if l.block == p.Block && l.start <= p.Offset {
+			found = i
+		} else if l.block < p.Block {
+			found = i
+		}
+	}
+	return found
+}
+
+// xOf returns the x of offset off within line l, relative to the content.
+func (e *RichTextEditor) xOf(ctx *core.Context, l *rtLine, off int) float32 {
+	x := l.indent
+	for _, r := range l.runs {
+		switch {
+		case off >= r.end:
+			x = l.indent + r.x + r.w
+			continue
+		case off <= r.start:
+			return l.indent + r.x
+		case r.span.Image != nil:
+			return l.indent + r.x
+		}
+		prefix := string([]rune(r.text)[:off-r.start])
+		return l.indent + r.x + ctx.MeasureText(prefix, r.style).Width
+	}
+	return x
+}
+
+// offsetAt returns the offset in line l nearest to x, relative to the
+// content.
+func (e *RichTextEditor) offsetAt(ctx *core.Context, l *rtLine, x float32) int {
+	x -= l.indent
+	for _, r := range l.runs {
+		if x >= r.x+r.w {
+			continue
+		}
+		if r.span.Image != nil {
+			if x < r.x+r.w/2 {
+				return r.start
+			}
+			return r.end
+		}
+		rs := []rune(r.text)
+		prev := r.x
+		for i := 0; i < len(rs); {
+			j := grapheme.Next(rs, i)
+			next := r.x + ctx.MeasureText(string(rs[:j]), r.style).Width
+			if x < (prev+next)/2 {
+				return r.start + i
+			}
+			prev, i = next, j
+		}
+		return r.end
+	}
+	// Past the end of a wrapped line the caret goes before the break,
+	// not after it, so it stays on this line.
+	if i := e.lineIndex(l); i+1 < len(e.lines) && e.lines[i+1].block == l.block && l.end > l.start {
+		return l.end - 1
+	}
+	return l.end
+}
+
+func (e *RichTextEditor) lineIndex(l *rtLine) int {
+	for i := range e.lines {
+		if &e.lines[i] == l {
+			return i
+		}
+	}
+	return -1
+}
+
+// posAt returns the position nearest to p in window coordinates.
+func (e *RichTextEditor) posAt(ctx *core.Context, p core.Point) richtext.Pos {
+	y := p.Y - e.content.Y + e.offset
+	x := p.X - e.content.X
+	i := 0
+	for i+1 < len(e.lines) && y >= e.lines[i+1].y {
+		i++
+	}
+	l := &e.lines[i]
+	return richtext.Pos{Block: l.block, Offset: e.offsetAt(ctx, l, x)}
+}
+
+// caretRect returns the caret relative to the content.
+func (e *RichTextEditor) caretRect(ctx *core.Context) core.Rect {
+	if len(e.lines) == 0 {
+		return core.Rect{}
+	}
+	l := &e.lines[e.lineOf(e.caret)]
+	return core.R(e.xOf(ctx, l, e.caret.Offset), l.y, 1.5, l.h)
+}
+
+// SetBounds implements core.Widget.
+func (e *RichTextEditor) SetBounds(r core.Rect) {
+	e.WidgetBase.SetBounds(r)
+	e.content = r.Inset(core.UniformInsets(fieldPadding))
+	e.content.Width = max(0, e.content.Width-scroll.Thickness)
+	e.bar.Track = core.R(r.Right()-scroll.Thickness-2, r.Y+2, scroll.Thickness, r.Height-4)
+	e.bar.Viewport = e.content.Height
+	e.bar.Content = e.height
+}
+
+// Paint implements core.Widget.
+func (e *RichTextEditor) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	paintField(ctx, e.Bounds(), e.IsFocused(), false)
+	cv.Save()
+	cv.Clip(e.content)
+	origin := core.Pt(e.content.X, e.content.Y-e.offset)
+	if e.placeholder != "" && len(e.doc.Blocks) == 1 && e.doc.Blocks[0].Len() == 0 {
+		st := blockStyle(th, &e.doc.Blocks[0])
+		cv.DrawText(e.placeholder, origin, theme.TextStyle(st, th.Colors.OnSurfaceVariant.WithAlpha(0.6)))
+	}
+	from, to := e.Selection()
+	for i := range e.lines {
+		l := &e.lines[i]
+		top := origin.Y + l.y
+		if top+l.h < e.content.Y || top > e.content.Bottom() {
+			continue
+		}
+		if e.hasSelection() && e.IsFocused() {
+			start := richtext.Pos{Block: l.block, Offset: l.start}
+			end := richtext.Pos{Block: l.block, Offset: l.end}
+			if start.Less(to) && from.Less(end) || from == start && l.start == l.end && from.Less(to) {
+				s, t := l.start, l.end
+				if from.Block == l.block && from.Offset > s {
+					s = from.Offset
+				}
+				if to.Block == l.block && to.Offset < t {
+					t = to.Offset
+				}
+				x0, x1 := e.xOf(ctx.Context, l, s), e.xOf(ctx.Context, l, t)
+				if to.Block > l.block && l.end == e.doc.Blocks[l.block].Len() {
+					x1 += 4 // The selected break between blocks.
+				}
+				cv.DrawRect(core.R(origin.X+x0, top, x1-x0, l.h), core.Filled(th.Colors.Selection))
+			}
+		}
+		if l.marker != "" && l.start == 0 {
+			st := blockStyle(th, &e.doc.Blocks[l.block])
+			w := ctx.MeasureText(l.marker, st).Width
+			cv.DrawText(l.marker, core.Pt(origin.X+l.indent-w-6, top+l.h-st.LineHeight()), theme.TextStyle(st, th.Colors.OnSurfaceVariant))
+		}
+		paintRuns(ctx, l, origin)
+	}
+	if e.IsFocused() {
+		c := e.caretRect(ctx.Context).Translate(origin)
+		cv.DrawRect(c, core.Filled(th.Colors.Primary))
+	}
+	cv.Restore()
+	e.bar.Paint(ctx, e.offset)
+}
+
+// HandleEvent implements core.Widget.
+func (e *RichTextEditor) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	if off, ok := e.bar.HandleEvent(ctx, e, ev, e.offset); ok {
+		if off != e.offset {
+			e.offset = off
+			ctx.Repaint(e)
+		}
+		return core.Handled
+	}
+	switch ev := ev.(type) {
+	case event.MouseEvent:
+		return e.handleMouse(ctx, ev)
+	case event.ScrollEvent:
+		e.offset = core.Clamp(e.offset+ev.Delta.Y, 0, e.bar.MaxOffset())
+		ctx.Repaint(e)
+		return core.Handled
+	case event.KeyEvent:
+		if !e.IsFocused() || ev.Type != event.KeyPress {
+			return core.Ignored
+		}
+		return e.handleKey(ctx, ev)
+	case event.TextEvent:
+		if !e.IsFocused() {
+			return core.Ignored
+		}
+		text := []rune(ev.Text)
+		kept := text[:0]
+		for _, r := range text {
+			if !unicode.IsControl(r) {
+				kept = append(kept, r)
+			}
+		}
+		if len(kept) > 0 {
+			e.insert(ctx, string(kept))
+		}
+		return core.Handled
+	}
+	return core.Ignored
+}
+
+func (e *RichTextEditor) handleMouse(ctx *core.Context, ev event.MouseEvent) core.EventResult {
+	switch ev.Type {
+	case event.MouseDown:
+		if ev.Button != event.ButtonLeft || len(e.lines) == 0 {
+			return core.Ignored
+		}
+		ctx.RequestFocus(e)
+		p := e.posAt(ctx, ev.Position)
+		if ev.Modifiers.Has(event.ModCtrl) || ev.Modifiers.Has(event.ModSuper) {
+			if link := e.doc.LinkAt(p); link != "" && e.onLink != nil {
+				e.onLink(link)
+				return core.Handled
+			}
+		}
+		switch ev.ClickCount {
+		case 2:
+			text := blockRunes(&e.doc.Blocks[p.Block])
+			s, t := p.Offset, p.Offset
+			word := func(i int) bool {
+				return i >= 0 && i < len(text) && (unicode.IsLetter(text[i]) || unicode.IsDigit(text[i]))
+			}
+			for word(s - 1) {
+				s--
+			}
+			for word(t) {
+				t++
+			}
+			e.anchor = richtext.Pos{Block: p.Block, Offset: s}
+			e.moveTo(ctx, richtext.Pos{Block: p.Block, Offset: t}, true)
+		case 3:
+			e.anchor = richtext.Pos{Block: p.Block}
+			e.moveTo(ctx, richtext.Pos{Block: p.Block, Offset: e.doc.Blocks[p.Block].Len()}, true)
+		default:
+			e.moveTo(ctx, p, ev.Modifiers.Has(event.ModShift))
+		}
+		e.goalX = -1
+		e.dragging = true
+		ctx.CapturePointer(e)
+		return core.Handled
+	case event.MouseMove:
+		if e.dragging {
+			e.moveTo(ctx, e.posAt(ctx, ev.Position), true)
+			return core.Handled
+		}
+	case event.MouseUp:
+		if e.dragging {
+			e.dragging = false
+			ctx.ReleasePointer()
+			return core.Handled
+		}
+	}
+	return core.Ignored
+}
+
+func (e *RichTextEditor) handleKey(ctx *core.Context, ev event.KeyEvent) core.EventResult {
+	extend := ev.Modifiers.Has(event.ModShift)
+	cmd := ev.Modifiers.Has(event.ModCtrl) || ev.Modifiers.Has(event.ModSuper)
+	word := ev.Modifiers.Has(event.ModCtrl) || ev.Modifiers.Has(event.ModAlt)
+	block := &e.doc.Blocks[e.caret.Block]
+	switch ev.Key {
+	case event.KeyLeft, event.KeyRight:
+		forward := ev.Key == event.KeyRight
+		from, to := e.Selection()
+		switch {
+		case e.hasSelection() && !extend && !word && forward:
+			e.moveTo(ctx, to, false)
+		case e.hasSelection() && !extend && !word:
+			e.moveTo(ctx, from, false)
+		default:
+			e.moveTo(ctx, e.step(e.caret, forward, word), extend)
+		}
+		e.goalX = -1
+	case event.KeyUp, event.KeyDown, event.KeyPageUp, event.KeyPageDown:
+		e.moveVertically(ctx, ev.Key, extend)
+	case event.KeyHome, event.KeyEnd:
+		var p richtext.Pos
+		switch {
+		case cmd && ev.Key == event.KeyHome:
+			p = e.doc.Start()
+		case cmd:
+			p = e.doc.End()
+		default:
+			i := e.lineOf(e.caret)
+			l := &e.lines[i]
+			p = richtext.Pos{Block: l.block, Offset: l.start}
+			if ev.Key == event.KeyEnd {
+				p.Offset = l.end
+				if i+1 < len(e.lines) && e.lines[i+1].block == l.block && l.end > l.start {
+					p.Offset-- // Before the break, on this line.
+				}
+			}
+		}
+		e.moveTo(ctx, p, extend)
+		e.goalX = -1
+	case event.KeyBackspace, event.KeyDelete:
+		forward := ev.Key == event.KeyDelete
+		switch {
+		case e.deleteSelection():
+		case !forward && e.caret.Offset == 0 && block.Kind != richtext.Paragraph:
+			// At the start of a list item or heading Backspace first
+			// outdents it, then turns it into a paragraph.
+			if block.Kind.IsList() && block.Level > 0 {
+				e.doc.SetKind(e.caret.Block, e.caret.Block, block.Kind, block.Level-1)
+			} else {
+				e.doc.SetKind(e.caret.Block, e.caret.Block, richtext.Paragraph, 0)
+			}
+		default:
+			to := e.step(e.caret, forward, word)
+			if to == e.caret {
+				return core.Handled
+			}
+			e.caret = e.doc.Delete(e.caret, to)
+			e.anchor = e.caret
+		}
+		e.edited(ctx)
+	case event.KeyEnter:
+		e.deleteSelection()
+		if block.Kind.IsList() && block.Len() == 0 {
+			// Enter on an empty item ends the list.
+			e.doc.SetKind(e.caret.Block, e.caret.Block, richtext.Paragraph, 0)
+		} else {
+			e.caret = e.doc.Split(e.caret)
+			e.anchor = e.caret
+		}
+		e.edited(ctx)
+	case event.KeyTab:
+		if !block.Kind.IsList() || cmd {
+			return core.Ignored
+		}
+		from, to := e.Selection()
+		delta := 1
+		if extend {
+			delta = -1
+		}
+		for i := from.Block; i <= to.Block; i++ {
+			b := &e.doc.Blocks[i]
+			if b.Kind.IsList() {
+				e.doc.SetKind(i, i, b.Kind, max(0, min(b.Level+delta, richTextLevels-1)))
+			}
+		}
+		e.edited(ctx)
+	case event.KeyA, event.KeyB, event.KeyI, event.KeyU:
+		if !cmd || ev.Modifiers.Has(event.ModAlt) || extend {
+			return core.Ignored
+		}
+		switch ev.Key {
+		case event.KeyA:
+			e.anchor = e.doc.Start()
+			e.moveTo(ctx, e.doc.End(), true)
+		case event.KeyB:
+			e.ToggleStyle(richtext.Bold)
+		case event.KeyI:
+			e.ToggleStyle(richtext.Italic)
+		case event.KeyU:
+			e.ToggleStyle(richtext.Underline)
+		}
+		ctx.MarkNeedsLayout(e)
+	default:
+		return core.Ignored
+	}
+	return core.Handled
+}
+
+// moveVertically moves the caret a line, or a page, up or down, keeping
+// its x across lines of different lengths.
+func (e *RichTextEditor) moveVertically(ctx *core.Context, key event.Key, extend bool) {
+	i := e.lineOf(e.caret)
+	if e.goalX < 0 {
+		e.goalX = e.xOf(ctx, &e.lines[i], e.caret.Offset)
+	}
+	goal := e.goalX
+	target := i
+	switch key {
+	case event.KeyUp:
+		target--
+	case event.KeyDown:
+		target++
+	case event.KeyPageUp, event.KeyPageDown:
+		y := e.lines[i].y - e.content.Height
+		if key == event.KeyPageDown {
+			y = e.lines[i].y + e.content.Height
+		}
+		target = 0
+		for target+1 < len(e.lines) && e.lines[target+1].y <= y {
+			target++
+		}
+	}
+	var p richtext.Pos
+	switch {
+	case target < 0:
+		p = e.doc.Start()
+	case target >= len(e.lines):
+		p = e.doc.End()
+	default:
+		l := &e.lines[target]
+		p = richtext.Pos{Block: l.block, Offset: e.offsetAt(ctx, l, goal)}
+	}
+	e.moveTo(ctx, p, extend)
+	e.goalX = goal
+}
@@ -0,0 +1,338 @@
+package widgets
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/richtext"
+)
+
+var richTextBounds = core.R(0, 0, richTextWidth, richTextHeight)
+
+// newRichTextEditor returns a focused editor holding doc, laid out, and a
+// count of its changes.
+func newRichTextEditor(doc *richtext.Document) (*RichTextEditor, *core.Context, *int) {
+	e := NewRichTextEditor()
+	e.SetDocument(doc)
+	changes := 0
+	e.OnChange(func() { changes++ })
+	ctx := layoutAt(e, richTextBounds)
+	ctx.RequestFocus(e)
+	return e, ctx, &changes
+}
+
+// send delivers evs to e, laying it out again after each as the window
+// would.
+func send(ctx *core.Context, e *RichTextEditor, evs ...core.Event) {
+	for _, ev := range evs {
+		e.HandleEvent(ctx, ev)
+		ctx.LayoutRoot(e, richTextBounds)
+	}
+}
+
+// pointAt returns the window point of position p in a laid out editor.
+func pointAt(ctx *core.Context, e *RichTextEditor, p richtext.Pos) core.Point {
+	l := &e.lines[e.lineOf(p)]
+	return core.Pt(e.content.X+e.xOf(ctx, l, p.Offset), e.content.Y+l.y+l.h/2)
+}
+
+func item(kind richtext.BlockKind, level int, s string) richtext.Block {
+	return richtext.Block{Kind: kind, Level: level, Spans: []richtext.Span{{Text: s}}}
+}
+
+func TestRichTextEditorTyping(t *testing.T) {
+	e, ctx, changes := newRichTextEditor(richtext.FromText(""))
+	send(ctx, e,
+		event.TextEvent{Text: "a\tb"},
+		press(event.KeyB, event.ModCtrl),
+		event.TextEvent{Text: "c"},
+	)
+	if !e.HasStyle(richtext.Bold) {
+		t.Error("Ctrl+B did not carry on to the text after it")
+	}
+	send(ctx, e, press(event.KeyB, event.ModCtrl), event.TextEvent{Text: "d"})
+	want := []richtext.Span{{Text: "ab"}, {Text: "c", Style: richtext.Bold}, {Text: "d"}}
+	if got := e.Document().Blocks[0].Spans; !equalSpans(got, want) {
+		t.Errorf("spans %+v, want %+v", got, want)
+	}
+	if *changes != 3 {
+		t.Errorf("%d changes, want one for each typed text", *changes)
+	}
+
+	// Moving the caret drops a format picked for typing.
+	send(ctx, e, press(event.KeyI, event.ModCtrl), press(event.KeyLeft, 0))
+	if e.HasStyle(richtext.Italic) {
+		t.Error("the pending format outlived a caret move")
+	}
+	if r := e.HandleEvent(ctx, press(event.KeyB, event.ModCtrl|event.ModShift)); r != core.Ignored {
+		t.Errorf("Ctrl+Shift+B = %v, want Ignored", r)
+	}
+}
+
+func equalSpans(a, b []richtext.Span) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Text != b[i].Text || a[i].Style != b[i].Style || a[i].Link != b[i].Link {
+			return false
+		}
+	}
+	return true
+}
+
+func TestRichTextEditorSelection(t *testing.T) {
+	e, ctx, _ := newRichTextEditor(richtext.FromText("one two\nthree"))
+	send(ctx, e, press(event.KeyA, event.ModCtrl))
+	if from, to := e.Selection(); from != (richtext.Pos{}) || to != (richtext.Pos{Block: 1, Offset: 5}) {
+		t.Fatalf("Ctrl+A selected %v to %v", from, to)
+	}
+	send(ctx, e, press(event.KeyU, event.ModCtrl))
+	if d := e.Document(); !d.HasStyle(d.Start(), d.End(), richtext.Underline) || !e.HasStyle(richtext.Underline) {
+		t.Error("Ctrl+U did not underline the selection")
+	}
+	send(ctx, e, event.TextEvent{Text: "x"})
+	if got := e.Document().PlainText(); got != "x" {
+		t.Errorf("typing over the selection left %q", got)
+	}
+	if !e.Document().HasStyle(richtext.Pos{}, richtext.Pos{Offset: 1}, richtext.Underline) {
+		t.Error("text typed over the selection lost its format")
+	}
+}
+
+func TestRichTextEditorKeys(t *testing.T) {
+	tests := []struct {
+		name  string
+		start richtext.Pos
+		keys  []event.KeyEvent
+		want  richtext.Pos
+	}{
+		{"right into the next block", richtext.Pos{Offset: 7}, []event.KeyEvent{press(event.KeyRight, 0)}, richtext.Pos{Block: 1}},
+		{"left into the previous block", richtext.Pos{Block: 1}, []event.KeyEvent{press(event.KeyLeft, 0)}, richtext.Pos{Offset: 7}},
+		{"word right", richtext.Pos{}, []event.KeyEvent{press(event.KeyRight, event.ModCtrl)}, richtext.Pos{Offset: 3}},
+		{"word left", richtext.Pos{Offset: 7}, []event.KeyEvent{press(event.KeyLeft, event.ModCtrl)}, richtext.Pos{Offset: 4}},
+		{"end", richtext.Pos{Offset: 2}, []event.KeyEvent{press(event.KeyEnd, 0)}, richtext.Pos{Offset: 7}},
+		{"home", richtext.Pos{Block: 1, Offset: 3}, []event.KeyEvent{press(event.KeyHome, 0)}, richtext.Pos{Block: 1}},
+		{"document end", richtext.Pos{}, []event.KeyEvent{press(event.KeyEnd, event.ModCtrl)}, richtext.Pos{Block: 2, Offset: 2}},
+		{"down keeps x", richtext.Pos{Offset: 5}, []event.KeyEvent{press(event.KeyDown, 0), press(event.KeyDown, 0)}, richtext.Pos{Block: 2, Offset: 2}},
+		{"down and up", richtext.Pos{Offset: 5}, []event.KeyEvent{press(event.KeyDown, 0), press(event.KeyDown, 0), press(event.KeyUp, 0)}, richtext.Pos{Block: 1, Offset: 5}},
+		{"up from the first line", richtext.Pos{Offset: 5}, []event.KeyEvent{press(event.KeyUp, 0)}, richtext.Pos{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, ctx, _ := newRichTextEditor(richtext.FromText("one two\nthree four\nfi"))
+			e.HandleEvent(ctx, leftMouse(event.MouseDown, pointAt(ctx, e, tt.start)))
+			e.HandleEvent(ctx, leftMouse(event.MouseUp, pointAt(ctx, e, tt.start)))
+			ctx.LayoutRoot(e, richTextBounds)
+			if from, _ := e.Selection(); from != tt.start {
+				t.Fatalf("clicking put the caret at %v", from)
+			}
+			for _, k := range tt.keys {
+				send(ctx, e, k)
+			}
+			if from, to := e.Selection(); from != tt.want || to != tt.want {
+				t.Errorf("caret at %v to %v, want %v", from, to, tt.want)
+			}
+		})
+	}
+}
+
+func TestRichTextEditorDelete(t *testing.T) {
+	tests := []struct {
+		name string
+		at   richtext.Pos
+		key  event.KeyEvent
+		want string
+	}{
+		{"backspace", richtext.Pos{Offset: 3}, press(event.KeyBackspace, 0), "on two\nthree"},
+		{"backspace joins", richtext.Pos{Block: 1}, press(event.KeyBackspace, 0), "one twothree"},
+		{"delete joins", richtext.Pos{Offset: 7}, press(event.KeyDelete, 0), "one twothree"},
+		{"delete a word", richtext.Pos{}, press(event.KeyDelete, event.ModCtrl), " two\nthree"},
+		{"nothing before the start", richtext.Pos{}, press(event.KeyBackspace, 0), "one two\nthree"},
+	}
+	for _, tt := range tests {
+		e, ctx, _ := newRichTextEditor(richtext.FromText("one two\nthree"))
+		e.caret, e.anchor = tt.at, tt.at
+		send(ctx, e, tt.key)
+		if got := e.Document().PlainText(); got != tt.want {
+			t.Errorf("%s: %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRichTextEditorLists(t *testing.T) {
+	type kind struct {
+		kind  richtext.BlockKind
+		level int
+	}
+	tests := []struct {
+		name   string
+		blocks []richtext.Block
+		at     richtext.Pos
+		keys   []event.KeyEvent
+		want   []kind
+	}{
+		{"enter continues the list", []richtext.Block{item(richtext.BulletItem, 1, "a")}, richtext.Pos{Offset: 1},
+			[]event.KeyEvent{press(event.KeyEnter, 0)}, []kind{{richtext.BulletItem, 1}, {richtext.BulletItem, 1}}},
+		{"enter on an empty item ends it", []richtext.Block{item(richtext.NumberedItem, 0, "a")}, richtext.Pos{Offset: 1},
+			[]event.KeyEvent{press(event.KeyEnter, 0), press(event.KeyEnter, 0)}, []kind{{richtext.NumberedItem, 0}, {richtext.Paragraph, 0}}},
+		{"enter after a heading", []richtext.Block{item(richtext.Heading, 1, "a")}, richtext.Pos{Offset: 1},
+			[]event.KeyEvent{press(event.KeyEnter, 0)}, []kind{{richtext.Heading, 1}, {richtext.Paragraph, 0}}},
+		{"tab indents", []richtext.Block{item(richtext.BulletItem, 0, "a")}, richtext.Pos{},
+			[]event.KeyEvent{press(event.KeyTab, 0), press(event.KeyTab, 0)}, []kind{{richtext.BulletItem, 2}}},
+		{"tab stops at the deepest level", []richtext.Block{item(richtext.BulletItem, richTextLevels-1, "a")}, richtext.Pos{},
+			[]event.KeyEvent{press(event.KeyTab, 0)}, []kind{{richtext.BulletItem, richTextLevels - 1}}},
+		{"shift tab outdents", []richtext.Block{item(richtext.BulletItem, 1, "a")}, richtext.Pos{},
+			[]event.KeyEvent{press(event.KeyTab, event.ModShift), press(event.KeyTab, event.ModShift)}, []kind{{richtext.BulletItem, 0}}},
+		{"backspace outdents, then ends the item", []richtext.Block{item(richtext.BulletItem, 1, "a")}, richtext.Pos{},
+			[]event.KeyEvent{press(event.KeyBackspace, 0), press(event.KeyBackspace, 0)}, []kind{{richtext.Paragraph, 0}}},
+		{"backspace ends a heading", []richtext.Block{item(richtext.Heading, 2, "a")}, richtext.Pos{},
+			[]event.KeyEvent{press(event.KeyBackspace, 0)}, []kind{{richtext.Paragraph, 0}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, ctx, _ := newRichTextEditor(richtext.New(tt.blocks...))
+			e.caret, e.anchor = tt.at, tt.at
+			for _, k := range tt.keys {
+				send(ctx, e, k)
+			}
+			d := e.Document()
+			if len(d.Blocks) != len(tt.want) {
+				t.Fatalf("%d blocks, want %d", len(d.Blocks), len(tt.want))
+			}
+			for i, w := range tt.want {
+				if b := d.Blocks[i]; b.Kind != w.kind || b.Level != w.level {
+					t.Errorf("block %d is %v at level %d, want %v at %d", i, b.Kind, b.Level, w.kind, w.level)
+				}
+			}
+		})
+	}
+
+	e, ctx, _ := newRichTextEditor(richtext.FromText("a"))
+	if r := e.HandleEvent(ctx, press(event.KeyTab, 0)); r != core.Ignored {
+		t.Errorf("Tab in a paragraph = %v, want Ignored", r)
+	}
+	e.SetBlockKind(richtext.BulletItem, 99)
+	if b := e.Document().Blocks[0]; b.Kind != richtext.BulletItem || b.Level != richTextLevels-1 {
+		t.Errorf("SetBlockKind gave %v at level %d", b.Kind, b.Level)
+	}
+}
+
+func TestRichTextEditorMarkers(t *testing.T) {
+	e, _, _ := newRichTextEditor(richtext.New(
+		item(richtext.NumberedItem, 0, "a"),
+		item(richtext.NumberedItem, 0, "b"),
+		item(richtext.BulletItem, 1, "c"),
+		item(richtext.NumberedItem, 0, "d"),
+		item(richtext.Paragraph, 0, "e"),
+		item(richtext.NumberedItem, 0, "f"),
+	))
+	want := []string{"1.", "2.", "◦", "3.", "", "1."}
+	for i, l := range e.lines {
+		if l.marker != want[i] {
+			t.Errorf("line %d marked %q, want %q", i, l.marker, want[i])
+		}
+	}
+	if e.lines[2].indent != 2*richTextIndent || e.lines[4].indent != 0 {
+		t.Errorf("indents %v and %v", e.lines[2].indent, e.lines[4].indent)
+	}
+}
+
+func TestRichTextEditorMouse(t *testing.T) {
+	e, ctx, _ := newRichTextEditor(richtext.FromText("one two three\nfour"))
+	mid := pointAt(ctx, e, richtext.Pos{Offset: 5})
+	send(ctx, e, event.MouseEvent{Type: event.MouseDown, Position: mid, Button: event.ButtonLeft, ClickCount: 2})
+	if from, to := e.Selection(); from.Offset != 4 || to.Offset != 7 {
+		t.Errorf("double click selected %v to %v, want the word", from, to)
+	}
+	send(ctx, e,
+		leftMouse(event.MouseUp, mid),
+		event.MouseEvent{Type: event.MouseDown, Position: mid, Button: event.ButtonLeft, ClickCount: 3},
+	)
+	if from, to := e.Selection(); from.Offset != 0 || to.Offset != 13 {
+		t.Errorf("triple click selected %v to %v, want the block", from, to)
+	}
+	send(ctx, e, leftMouse(event.MouseUp, mid))
+
+	// A drag selects from where it started.
+	send(ctx, e,
+		leftMouse(event.MouseDown, pointAt(ctx, e, richtext.Pos{Offset: 2})),
+		leftMouse(event.MouseMove, pointAt(ctx, e, richtext.Pos{Block: 1, Offset: 2})),
+		leftMouse(event.MouseUp, core.Pt(0, 0)),
+	)
+	if from, to := e.Selection(); from != (richtext.Pos{Offset: 2}) || to != (richtext.Pos{Block: 1, Offset: 2}) {
+		t.Errorf("dragging selected %v to %v", from, to)
+	}
+	if ctx.PointerCapture() != nil {
+		t.Error("the drag kept the pointer")
+	}
+}
+
+func TestRichTextEditorLinks(t *testing.T) {
+	d := richtext.FromText("see docs here")
+	d.SetLink(richtext.Pos{Offset: 4}, richtext.Pos{Offset: 8}, "http://a")
+	e, ctx, _ := newRichTextEditor(d)
+	var opened []string
+	e.OnLink(func(url string) { opened = append(opened, url) })
+
+	at := pointAt(ctx, e, richtext.Pos{Offset: 5})
+	send(ctx, e, event.MouseEvent{Type: event.MouseDown, Position: at, Button: event.ButtonLeft, Modifiers: event.ModCtrl})
+	if len(opened) != 1 || opened[0] != "http://a" {
+		t.Errorf("Ctrl+click opened %v", opened)
+	}
+	send(ctx, e, leftMouse(event.MouseDown, at), leftMouse(event.MouseUp, at))
+	if len(opened) != 1 {
+		t.Error("a plain click opened the link")
+	}
+
+	// With nothing selected, SetLink changes the whole link at the caret.
+	e.SetLink("http://b")
+	for off := 4; off < 8; off++ {
+		if got := e.doc.LinkAt(richtext.Pos{Offset: off}); got != "http://b" {
+			t.Errorf("offset %d links to %q", off, got)
+		}
+	}
+	// Typing at the end of a link does not extend it.
+	e.caret, e.anchor = richtext.Pos{Offset: 8}, richtext.Pos{Offset: 8}
+	send(ctx, e, event.TextEvent{Text: "!"})
+	if got := e.doc.LinkAt(richtext.Pos{Offset: 8}); got != "" {
+		t.Errorf("text typed after the link links to %q", got)
+	}
+	e.caret, e.anchor = richtext.Pos{Offset: 6}, richtext.Pos{Offset: 6}
+	send(ctx, e, event.TextEvent{Text: "x"})
+	if got := e.doc.LinkAt(richtext.Pos{Offset: 6}); got != "http://b" {
+		t.Errorf("text typed inside the link links to %q", got)
+	}
+}
+
+func TestRichTextEditorImage(t *testing.T) {
+	e, ctx, changes := newRichTextEditor(richtext.FromText("ab"))
+	e.anchor, e.caret = richtext.Pos{Offset: 1}, richtext.Pos{Offset: 2}
+	e.InsertImage(&richtext.Image{Alt: "cat", Width: 30, Height: 30})
+	ctx.Invalidate()
+	ctx.LayoutRoot(e, richTextBounds)
+	if b := e.Document().Blocks[0]; b.Text() != "acat" || b.Len() != 2 {
+		t.Errorf("block %q of %d positions, want the image in place of the selection", b.Text(), b.Len())
+	}
+	if *changes != 1 || e.lines[0].h < 30 {
+		t.Errorf("%d changes, line %v high", *changes, e.lines[0].h)
+	}
+}
+
+func TestRichTextEditorScroll(t *testing.T) {
+	e, ctx, _ := newRichTextEditor(richtext.FromText("x"))
+	for range 40 {
+		send(ctx, e, press(event.KeyEnter, 0))
+	}
+	if e.offset == 0 {
+		t.Fatal("the editor did not scroll to the caret")
+	}
+	if c := e.caretRect(ctx); c.Bottom() > e.offset+e.content.Height {
+		t.Errorf("caret at %v, below the view from %v", c, e.offset)
+	}
+	send(ctx, e, press(event.KeyHome, event.ModCtrl))
+	if e.offset != 0 {
+		t.Errorf("offset %v at the start", e.offset)
+	}
+}
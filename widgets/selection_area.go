@@ -0,0 +1,260 @@
+package widgets
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+)
+
+// SelectionArea makes all the Text inside it selectable as one, so a
+// drag can select from the middle of one paragraph to the middle of
+// another, the way text is selected on a web page. Text is ordered as it
+// is painted. Widgets that handle the mouse themselves, such as buttons,
+// keep doing so, and a SelectionArea inside another has its own
+// selection.
+//
+// Dragging selects, Shift+click extends the selection, double-click
+// selects a word and triple-click a paragraph. Once clicked, Shift and
+// the arrow keys extend the selection, moving on to the next or previous
+// text at either end, Ctrl+A selects everything and Ctrl+C copies the
+// selection, with a newline between the texts.
+type SelectionArea struct {
+	core.WidgetBase
+
+	child core.Widget
+	texts []*Text
+
+	anchor, focus selectionPoint
+	dragging      bool
+}
+
+// selectionPoint is a position in one of the texts of a SelectionArea.
+type selectionPoint struct {
+	text   *Text
+	offset int
+}
+
+// NewSelectionArea returns a SelectionArea around child.
+func NewSelectionArea(child core.Widget) *SelectionArea {
+	a := &SelectionArea{child: child}
+	a.SetChildren(child)
+	return a
+}
+
+// SelectedText returns the selected text, or "" if none is.
+func (a *SelectionArea) SelectedText() string {
+	start, end := a.ordered()
+	if start < 0 {
+		return ""
+	}
+	var parts []string
+	for _, t := range a.texts[start : end+1] {
+		parts = append(parts, t.SelectedText())
+	}
+	return strings.Join(parts, "\n")
+}
+
+// ClearSelection clears the selection.
+func (a *SelectionArea) ClearSelection() {
+	a.anchor, a.focus = selectionPoint{}, selectionPoint{}
+	a.apply()
+}
+
+// Layout implements core.Widget. The texts are collected after the child
+// is laid out, as containers that build their children in Layout may
+// have added some.
+func (a *SelectionArea) Layout(ctx *core.LayoutContext) core.Size {
+	size := ctx.Measure(a.child, ctx.Constraints)
+	old := a.texts
+	a.texts = nil
+	core.Walk(a.child, func(w core.Widget) bool {
+		switch w := w.(type) {
+		case *SelectionArea:
+			return false
+		case *Text:
+			w.area = a
+			a.texts = append(a.texts, w)
+		}
+		return true
+	})
+	for _, t := range old {
+		if t.area == a && !slices.Contains(a.texts, t) {
+			t.area = nil
+		}
+	}
+	if !slices.Contains(a.texts, a.anchor.text) || !slices.Contains(a.texts, a.focus.text) {
+		a.anchor, a.focus = selectionPoint{}, selectionPoint{}
+	}
+	a.apply()
+	return size
+}
+
+// SetBounds implements core.Widget.
+func (a *SelectionArea) SetBounds(r core.Rect) {
+	a.WidgetBase.SetBounds(r)
+	a.child.SetBounds(r)
+}
+
+// Paint implements core.Widget.
+func (a *SelectionArea) Paint(ctx *core.PaintContext) {
+	a.child.Paint(ctx)
+}
+
+// HandleEvent implements core.Widget.
+func (a *SelectionArea) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	switch ev := ev.(type) {
+	case event.MouseEvent:
+		switch ev.Type {
+		case event.MouseDown:
+			if ev.Button != event.ButtonLeft || !a.Bounds().Contains(ev.Position) {
+				return core.Ignored
+			}
+			p, ok := a.pointAt(ctx, ev.Position)
+			if !ok {
+				return core.Ignored
+			}
+			ctx.RequestFocus(a)
+			switch ev.ClickCount {
+			case 0, 1:
+				if !ev.Modifiers.Has(event.ModShift) || a.anchor.text == nil {
+					a.anchor = p
+				}
+				a.focus = p
+				a.dragging = true
+				ctx.CapturePointer(a)
+			case 2:
+				start, end := p.text.editor().WordAt(p.offset)
+				a.anchor, a.focus = selectionPoint{p.text, start}, selectionPoint{p.text, end}
+			default:
+				start, end := p.text.paragraphAt(p.offset)
+				a.anchor, a.focus = selectionPoint{p.text, start}, selectionPoint{p.text, end}
+			}
+		case event.MouseMove:
+			if !a.dragging {
+				return core.Ignored
+			}
+			if p, ok := a.pointAt(ctx, ev.Position); ok {
+				a.focus = p
+			}
+		case event.MouseUp:
+			if !a.dragging {
+				return core.Ignored
+			}
+			a.dragging = false
+			ctx.ReleasePointer()
+		default:
+			return core.Ignored
+		}
+	case event.KeyEvent:
+		if !ctx.IsFocused(a) || ev.Type != event.KeyPress {
+			return core.Ignored
+		}
+		switch {
+		case isCopy(ev):
+			if text := a.SelectedText(); text != "" {
+				clipboardFrom(ctx).WriteText(text)
+			}
+			return core.Handled
+		case isSelectAll(ev):
+			if len(a.texts) == 0 {
+				return core.Ignored
+			}
+			last := a.texts[len(a.texts)-1]
+			a.anchor, a.focus = selectionPoint{a.texts[0], 0}, selectionPoint{last, last.editor().Len()}
+		case ev.Modifiers.Has(event.ModShift) && a.focus.text != nil:
+			if !a.move(ctx, ev) {
+				return core.Ignored
+			}
+		default:
+			return core.Ignored
+		}
+	default:
+		return core.Ignored
+	}
+	a.apply()
+	ctx.Repaint(a)
+	return core.Handled
+}
+
+// pointAt returns the position nearest p in the text nearest p: the
+// closest text vertically, and of those the closest horizontally, so a
+// drag into the gap between paragraphs selects to the end of the one
+// above or the start of the one below.
+func (a *SelectionArea) pointAt(ctx *core.Context, p core.Point) (selectionPoint, bool) {
+	var best *Text
+	var bestY, bestX float32
+	for _, t := range a.texts {
+		b := t.Bounds()
+		dy := max(b.Y-p.Y, p.Y-b.Bottom(), 0)
+		dx := max(b.X-p.X, p.X-b.Right(), 0)
+		if best == nil || dy < bestY || dy == bestY && dx < bestX {
+			best, bestY, bestX = t, dy, dx
+		}
+	}
+	if best == nil {
+		return selectionPoint{}, false
+	}
+	return selectionPoint{best, best.offsetAt(ctx, p)}, true
+}
+
+// move moves the focus end of the selection for a Shift-modified key,
+// into the neighbouring text when it would leave the one it is in.
+func (a *SelectionArea) move(ctx *core.Context, ev event.KeyEvent) bool {
+	t := a.focus.text
+	if i, ok := t.moveCaret(ctx, a.focus.offset, ev); ok {
+		a.focus.offset = i
+		return true
+	}
+	k := slices.Index(a.texts, t)
+	switch ev.Key {
+	case event.KeyLeft, event.KeyUp:
+		if k > 0 {
+			prev := a.texts[k-1]
+			a.focus = selectionPoint{prev, prev.editor().Len()}
+			return true
+		}
+	case event.KeyRight, event.KeyDown:
+		if k+1 < len(a.texts) {
+			a.focus = selectionPoint{a.texts[k+1], 0}
+			return true
+		}
+	}
+	return false
+}
+
+// ordered returns the indices of the first and last texts the selection
+// covers, or -1 and -2 when there is no selection, which covers none.
+func (a *SelectionArea) ordered() (start, end int) {
+	i, j := slices.Index(a.texts, a.anchor.text), slices.Index(a.texts, a.focus.text)
+	if i < 0 || j < 0 {
+		return -1, -2
+	}
+	return min(i, j), max(i, j)
+}
+
+// apply sets the selection of each text to the part of the area's
+// selection in it.
+func (a *SelectionArea) apply() {
+	start, end := a.ordered()
+	from, to := a.anchor, a.focus
+	if slices.Index(a.texts, from.text) > slices.Index(a.texts, to.text) {
+		from, to = to, from
+	}
+	for k, t := range a.texts {
+		e := t.editor()
+		switch {
+		case k < start || k > end:
+			e.SetCaret(0, false)
+		case start == end:
+			e.Select(from.offset, to.offset)
+		case k == start:
+			e.Select(from.offset, e.Len())
+		case k == end:
+			e.Select(0, to.offset)
+		default:
+			e.SelectAll()
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package widgets
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/layout"
+)
+
+// textPoint returns the point of t at selection position i.
+func textPoint(ctx *core.Context, t *Text, i int) core.Point {
+	t.editor()
+	n := t.lineOf(i)
+	b := t.Bounds()
+	return core.Pt(b.X+t.caretX(ctx, n, i), b.Y+t.para.Lines[n].Y+2)
+}
+
+// selectable returns an area of three texts, the second of spans, laid
+// out with a clipboard.
+func selectable() (*SelectionArea, []*Text, *core.Context, *fakeClipboard) {
+	texts := []*Text{
+		NewText("first paragraph"),
+		NewRichText(TextSpan{Text: "second "}, TextSpan{Text: "bold", Style: SpanStyle{Weight: core.WeightBold}}, TextSpan{Text: " text"}),
+		NewText("third"),
+	}
+	a := NewSelectionArea(layout.NewVStack(texts[0], texts[1], texts[2]).Spacing(10))
+	ctx := layoutAt(a, core.R(0, 0, 400, 300))
+	c := &fakeClipboard{}
+	SetClipboard(ctx, c)
+	return a, texts, ctx, c
+}
+
+func TestSelectionAreaDrag(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to [2]int // Texts and positions in them.
+		want     string
+	}{
+		{"within a text", [2]int{0, 6}, [2]int{0, 9}, "par"},
+		{"across spans", [2]int{1, 3}, [2]int{1, 9}, "ond bo"},
+		{"into the next text", [2]int{0, 6}, [2]int{1, 10}, "paragraph\nsecond bol"},
+		{"across texts", [2]int{0, 6}, [2]int{2, 2}, "paragraph\nsecond bold text\nth"},
+		{"backwards", [2]int{1, 3}, [2]int{0, 12}, "aph\nsec"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, texts, ctx, clip := selectable()
+			from := textPoint(ctx, texts[tt.from[0]], tt.from[1])
+			to := textPoint(ctx, texts[tt.to[0]], tt.to[1])
+			a.HandleEvent(ctx, leftMouse(event.MouseDown, from))
+			if ctx.PointerCapture() != a || !ctx.IsFocused(a) {
+				t.Fatal("a press did not start a drag")
+			}
+			a.HandleEvent(ctx, event.MouseEvent{Type: event.MouseMove, Position: to})
+			a.HandleEvent(ctx, leftMouse(event.MouseUp, to))
+			if got := a.SelectedText(); got != tt.want {
+				t.Errorf("SelectedText() = %q, want %q", got, tt.want)
+			}
+			a.HandleEvent(ctx, press(event.KeyC, event.ModCtrl))
+			if clip.text != tt.want {
+				t.Errorf("copied %q, want %q", clip.text, tt.want)
+			}
+			if !texts[tt.from[0]].showsSelection(ctx) {
+				t.Error("the selection is not shown")
+			}
+		})
+	}
+}
+
+func TestSelectionAreaClicks(t *testing.T) {
+	a, texts, ctx, _ := selectable()
+	click := func(text, i, count int, mods event.Modifiers) {
+		p := textPoint(ctx, texts[text], i)
+		a.HandleEvent(ctx, event.MouseEvent{Type: event.MouseDown, Button: event.ButtonLeft, Position: p, ClickCount: count, Modifiers: mods})
+		a.HandleEvent(ctx, event.MouseEvent{Type: event.MouseUp, Button: event.ButtonLeft, Position: p})
+	}
+	tests := []struct {
+		name  string
+		click func()
+		want  string
+	}{
+		{"click", func() { click(0, 2, 1, 0) }, ""},
+		{"shift+click extends", func() { click(2, 3, 1, event.ModShift) }, "rst paragraph\nsecond bold text\nthi"},
+		{"double click selects a word", func() { click(1, 8, 2, 0) }, "bold"},
+		{"triple click selects a paragraph", func() { click(1, 8, 3, 0) }, "second bold text"},
+		{"shift+click extends from the start of a paragraph", func() { click(0, 0, 1, event.ModShift) }, "first paragraph\n"},
+	}
+	for _, tt := range tests {
+		tt.click()
+		if got := a.SelectedText(); got != tt.want {
+			t.Errorf("%s: SelectedText() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+	a.ClearSelection()
+	if got := a.SelectedText(); got != "" || texts[1].SelectedText() != "" {
+		t.Errorf("after ClearSelection the selection is %q", got)
+	}
+
+	// A press outside the texts' area but inside the area selects from
+	// the nearest text.
+	if r := a.HandleEvent(ctx, leftMouse(event.MouseDown, core.Pt(390, 290))); r != core.Handled || a.focus.text != texts[2] {
+		t.Errorf("a press below the texts = %v in %v", r, a.focus.text)
+	}
+}
+
+func TestSelectionAreaKeys(t *testing.T) {
+	a, texts, ctx, _ := selectable()
+	if r := a.HandleEvent(ctx, press(event.KeyA, event.ModCtrl)); r != core.Ignored {
+		t.Error("an unfocused area handled a key")
+	}
+	end := texts[0].editor().Len()
+	p := textPoint(ctx, texts[0], end-1)
+	a.HandleEvent(ctx, leftMouse(event.MouseDown, p))
+	a.HandleEvent(ctx, leftMouse(event.MouseUp, p))
+	tests := []struct {
+		key  event.Key
+		mods event.Modifiers
+		want string
+	}{
+		{event.KeyRight, event.ModShift, "h"},
+		{event.KeyRight, event.ModShift, "h\n"}, // Into the next text.
+		{event.KeyRight, event.ModShift, "h\ns"},
+		{event.KeyEnd, event.ModShift, "h\nsecond bold text"},
+		{event.KeyDown, event.ModShift, "h\nsecond bold text\n"},
+		{event.KeyLeft, event.ModShift, "h\nsecond bold text"},
+		{event.KeyA, event.ModCtrl, "first paragraph\nsecond bold text\nthird"},
+	}
+	for _, tt := range tests {
+		if r := a.HandleEvent(ctx, press(tt.key, tt.mods)); r != core.Handled {
+			t.Errorf("%v was not handled", tt.key)
+		}
+		if got := a.SelectedText(); got != tt.want {
+			t.Errorf("after %v: SelectedText() = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+	if r := a.HandleEvent(ctx, press(event.KeyRight, 0)); r != core.Ignored {
+		t.Error("Right without Shift was handled")
+	}
+}
+
+func TestSelectionAreaTexts(t *testing.T) {
+	outer, inner := NewText("outer"), NewText("inner")
+	nested := NewSelectionArea(inner)
+	a := NewSelectionArea(layout.NewVStack(outer, nested))
+	stack := a.child.(*layout.Stack)
+	ctx := layoutAt(a, core.R(0, 0, 400, 300))
+	if len(a.texts) != 1 || a.texts[0] != outer || len(nested.texts) != 1 || inner.area != nested {
+		t.Fatalf("the area has %d texts and the nested one %d", len(a.texts), len(nested.texts))
+	}
+
+	// A text leaving the area leaves its selection behind.
+	ctx.RequestFocus(a)
+	a.HandleEvent(ctx, press(event.KeyA, event.ModCtrl))
+	if a.SelectedText() != "outer" {
+		t.Fatalf("SelectedText() = %q, want outer", a.SelectedText())
+	}
+	stack.Remove(outer)
+	ctx.Invalidate()
+	ctx.LayoutRoot(a, core.R(0, 0, 400, 300))
+	if outer.area != nil || a.SelectedText() != "" {
+		t.Errorf("the removed text is in area %v, with %q selected", outer.area, a.SelectedText())
+	}
+}
+
+func TestTextSelect(t *testing.T) {
+	txt := NewText("hello wide world").Selectable(true)
+	ctx := layoutAt(txt, core.R(0, 0, 400, 40))
+	clip := &fakeClipboard{}
+	SetClipboard(ctx, clip)
+	txt.HandleEvent(ctx, leftMouse(event.MouseDown, textPoint(ctx, txt, 1)))
+	txt.HandleEvent(ctx, event.MouseEvent{Type: event.MouseMove, Position: textPoint(ctx, txt, 4)})
+	txt.HandleEvent(ctx, leftMouse(event.MouseUp, textPoint(ctx, txt, 4)))
+	if got := txt.SelectedText(); got != "ell" || !txt.showsSelection(ctx) {
+		t.Errorf("dragged over %q, shown %v", got, txt.showsSelection(ctx))
+	}
+	txt.HandleEvent(ctx, press(event.KeyRight, event.ModShift|event.ModCtrl))
+	if got := txt.SelectedText(); got != "ello" {
+		t.Errorf("Ctrl+Shift+Right extended to %q", got)
+	}
+	txt.HandleEvent(ctx, press(event.KeyEnd, event.ModShift))
+	txt.HandleEvent(ctx, press(event.KeyC, event.ModCtrl))
+	if clip.text != "ello wide world" {
+		t.Errorf("copied %q", clip.text)
+	}
+	txt.HandleEvent(ctx, event.MouseEvent{Type: event.MouseDown, Button: event.ButtonLeft, Position: textPoint(ctx, txt, 8), ClickCount: 2})
+	if got := txt.SelectedText(); got != "wide" {
+		t.Errorf("a double click selected %q", got)
+	}
+
+	plain := NewText("plain")
+	ctx = layoutAt(plain, core.R(0, 0, 400, 40))
+	if r := plain.HandleEvent(ctx, leftMouse(event.MouseDown, textPoint(ctx, plain, 1))); r != core.Ignored {
+		t.Error("text that is not selectable handled a press")
+	}
+}
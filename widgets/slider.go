@@ -0,0 +1,563 @@
+package widgets
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/state"
+	"github.com/gogpu/ui/theme"
+)
+
+// Slider metrics.
+const (
+	sliderExtent = 32  // cross-axis size
+	sliderLength = 200 // main-axis size when unconstrained
+	sliderTrack  = 4
+	sliderKnob   = 16
+	sliderTick   = 6
+)
+
+// Slider selects a number between a minimum and a maximum by dragging a
+// thumb along a track.
+//
+// The thumb is focusable: the arrow keys move it by the step, Page Up and
+// Page Down by ten steps, Home and End to the ends. While it is dragged a
+// bubble above the thumb shows the value. A horizontal slider has its
+// minimum at the start edge, so in right-to-left text it fills from the
+// right and the left arrow key increases the value. A vertical slider has
+// its minimum at the bottom.
+type Slider struct {
+	slider
+	onChange func(float64)
+}
+
+// NewSlider returns a horizontal Slider from min to max, set to min.
+func NewSlider(min, max float64) *Slider {
+	s := &Slider{}
+	s.init(s, min, max, 1)
+	s.notify = func() {
+		if s.onChange != nil {
+			s.onChange(s.thumbs[0].value)
+		}
+	}
+	return s
+}
+
+// Step makes the value snap to multiples of step from the minimum. Zero
+// makes the slider continuous.
+func (s *Slider) Step(step float64) *Slider {
+	s.setStep(step)
+	return s
+}
+
+// Ticks draws a tick mark every interval along the track. Pass the same
+// interval to Step to snap onto the marks.
+func (s *Slider) Ticks(every float64) *Slider {
+	s.ticks = max(0, every)
+	return s
+}
+
+// Vertical lays the slider out along the vertical axis.
+func (s *Slider) Vertical(on bool) *Slider {
+	s.vertical = on
+	return s
+}
+
+// ValueFormat sets how the value is shown in the drag bubble.
+func (s *Slider) ValueFormat(fn func(float64) string) *Slider {
+	s.format = fn
+	return s
+}
+
+// Bind keeps the value in sync with sig in both directions: setting the
+// signal moves the thumb and user changes are stored in the signal.
+func (s *Slider) Bind(sig *state.Signal[float64]) *Slider {
+	s.bind(0, sig)
+	return s
+}
+
+// OnChange registers fn to be called when the user changes the value.
+func (s *Slider) OnChange(fn func(float64)) *Slider {
+	s.onChange = fn
+	return s
+}
+
+// Value returns the current value.
+func (s *Slider) Value() float64 {
+	return s.thumbs[0].value
+}
+
+// SetValue sets the value without calling OnChange. It is clamped to the
+// range and snapped to the step.
+func (s *Slider) SetValue(v float64) {
+	s.thumbs[0].value = s.snap(v)
+	if sig := s.thumbs[0].sig; sig != nil {
+		sig.Set(s.thumbs[0].value)
+	}
+}
+
+// RangeSlider selects an interval with two thumbs that cannot pass each
+// other. It supports the same steps, ticks, orientation and keyboard
+// control as Slider.
+type RangeSlider struct {
+	slider
+	onChange func(lo, hi float64)
+}
+
+// NewRangeSlider returns a horizontal RangeSlider from min to max covering
+// the whole range.
+func NewRangeSlider(min, max float64) *RangeSlider {
+	s := &RangeSlider{}
+	s.init(s, min, max, 2)
+	s.thumbs[1].value = s.max
+	s.notify = func() {
+		if s.onChange != nil {
+			s.onChange(s.thumbs[0].value, s.thumbs[1].value)
+		}
+	}
+	return s
+}
+
+// Step makes both values snap to multiples of step from the minimum.
+func (s *RangeSlider) Step(step float64) *RangeSlider {
+	s.setStep(step)
+	return s
+}
+
+// Ticks draws a tick mark every interval along the track.
+func (s *RangeSlider) Ticks(every float64) *RangeSlider {
+	s.ticks = max(0, every)
+	return s
+}
+
+// Vertical lays the slider out along the vertical axis.
+func (s *RangeSlider) Vertical(on bool) *RangeSlider {
+	s.vertical = on
+	return s
+}
+
+// ValueFormat sets how values are shown in the drag bubble.
+func (s *RangeSlider) ValueFormat(fn func(float64) string) *RangeSlider {
+	s.format = fn
+	return s
+}
+
+// Bind keeps the two ends in sync with lo and hi. Either may be nil.
+func (s *RangeSlider) Bind(lo, hi *state.Signal[float64]) *RangeSlider {
+	s.bind(0, lo)
+	s.bind(1, hi)
+	return s
+}
+
+// OnChange registers fn to be called when the user moves either end.
+func (s *RangeSlider) OnChange(fn func(lo, hi float64)) *RangeSlider {
+	s.onChange = fn
+	return s
+}
+
+// Values returns the two ends, lo <= hi.
+func (s *RangeSlider) Values() (lo, hi float64) {
+	return s.thumbs[0].value, s.thumbs[1].value
+}
+
+// SetValues sets both ends without calling OnChange, swapping them if
+// they are out of order.
+func (s *RangeSlider) SetValues(lo, hi float64) {
+	lo, hi = s.snap(lo), s.snap(hi)
+	if hi < lo {
+		lo, hi = hi, lo
+	}
+	for i, v := range []float64{lo, hi} {
+		t := s.thumbs[i]
+		t.value = v
+		if t.sig != nil {
+			t.sig.Set(v)
+		}
+	}
+}
+
+// slider is the shared implementation of Slider and RangeSlider.
+type slider struct {
+	core.WidgetBase
+
+	self     core.Widget
+	min, max float64
+	step     float64
+	ticks    float64
+	vertical bool
+	dir      core.Direction
+	format   func(float64) string
+	notify   func()
+
+	thumbs []*sliderThumb
+	track  core.Rect
+	drag   *sliderThumb
+}
+
+func (s *slider) init(self core.Widget, min, max float64, n int) {
+	if max < min {
+		min, max = max, min
+	}
+	s.self, s.min, s.max = self, min, max
+	for i := range n {
+		s.thumbs = append(s.thumbs, &sliderThumb{slider: s, index: i, value: min})
+	}
+}
+
+func (s *slider) setStep(step float64) {
+	s.step = max(0, step)
+	for _, t := range s.thumbs {
+		t.value = s.snap(t.value)
+	}
+}
+
+func (s *slider) bind(i int, sig *state.Signal[float64]) {
+	t := s.thumbs[i]
+	t.release()
+	t.sig = sig
+}
+
+// Release stops following the bound signals, for when the slider leaves
+// the tree. Laid out again, the slider follows the signals from their
+// values then.
+func (s *slider) Release() {
+	for _, t := range s.thumbs {
+		t.release()
+	}
+}
+
+// snap clamps v to the range and rounds it to the step.
+func (s *slider) snap(v float64) float64 {
+	if s.step > 0 {
+		v = s.min + math.Round((v-s.min)/s.step)*s.step
+		// Drop the binary noise of accumulating a decimal step.
+		scale := math.Pow10(s.precision())
+		v = math.Round(v*scale) / scale
+	}
+	return math.Max(s.min, math.Min(v, s.max))
+}
+
+// keyStep returns the distance moved by one arrow key press.
+func (s *slider) keyStep() float64 {
+	if s.step > 0 {
+		return s.step
+	}
+	return (s.max - s.min) / 100
+}
+
+func (s *slider) text(v float64) string {
+	if s.format != nil {
+		return s.format(v)
+	}
+	prec := 2
+	if s.step > 0 {
+		prec = s.precision()
+	}
+	return strconv.FormatFloat(v, 'f', prec, 64)
+}
+
+// precision returns the number of decimals in the step.
+func (s *slider) precision() int {
+	prec := 0
+	for f := s.step; math.Abs(f-math.Round(f)) > 1e-9 && prec < 6; f *= 10 {
+		prec++
+	}
+	return prec
+}
+
+// fraction returns where v lies between the minimum and the maximum.
+func (s *slider) fraction(v float64) float32 {
+	if s.max == s.min {
+		return 0
+	}
+	return float32((v - s.min) / (s.max - s.min))
+}
+
+// pos returns the point on the track's center line for v.
+func (s *slider) pos(v float64) core.Point {
+	f := s.fraction(v)
+	c := s.track.Center()
+	if s.vertical {
+		return core.Pt(c.X, s.track.Bottom()-f*s.track.Height)
+	}
+	if s.dir.IsRTL() {
+		f = 1 - f
+	}
+	return core.Pt(s.track.X+f*s.track.Width, c.Y)
+}
+
+// valueAt returns the value under p.
+func (s *slider) valueAt(p core.Point) float64 {
+	var f float32
+	if s.vertical {
+		if s.track.Height > 0 {
+			f = (s.track.Bottom() - p.Y) / s.track.Height
+		}
+	} else if s.track.Width > 0 {
+		f = (p.X - s.track.X) / s.track.Width
+		if s.dir.IsRTL() {
+			f = 1 - f
+		}
+	}
+	return s.min + float64(core.Clamp(f, 0, 1))*(s.max-s.min)
+}
+
+// change moves thumb t to v, keeping range thumbs in order, and reports
+// the edit.
+func (s *slider) change(ctx *core.Context, t *sliderThumb, v float64) {
+	v = s.snap(v)
+	if t.index > 0 {
+		v = math.Max(v, s.thumbs[t.index-1].value)
+	}
+	if t.index < len(s.thumbs)-1 {
+		v = math.Min(v, s.thumbs[t.index+1].value)
+	}
+	if v == t.value {
+		return
+	}
+	s.repaint(ctx)
+	t.value = v
+	if t.sig != nil {
+		t.sig.Set(v)
+	}
+	s.SetBounds(s.Bounds())
+	s.repaint(ctx)
+	s.notify()
+}
+
+// repaint paints the slider again, with the bubble of the dragged thumb
+// where it is now, which may lie outside the slider.
+func (s *slider) repaint(ctx *core.Context) {
+	ctx.Repaint(s.self)
+	if s.drag != nil {
+		ctx.InvalidateRect(s.bubble(ctx, s.drag))
+	}
+}
+
+// Children implements core.Parent.
+func (s *slider) Children() []core.Widget {
+	children := make([]core.Widget, len(s.thumbs))
+	for i, t := range s.thumbs {
+		children[i] = t
+	}
+	return children
+}
+
+// Layout implements core.Widget.
+func (s *slider) Layout(ctx *core.LayoutContext) core.Size {
+	s.dir = ctx.Direction()
+	for _, t := range s.thumbs {
+		if t.sig == nil {
+			continue
+		}
+		if t.cancel == nil {
+			c := ctx.Context
+			t.cancel = t.sig.Subscribe(func(float64) { c.Post(func() { c.MarkNeedsLayout(s.self) }) })
+		}
+		t.value = s.snap(t.sig.Get())
+	}
+	for i := 1; i < len(s.thumbs); i++ {
+		s.thumbs[i].value = math.Max(s.thumbs[i].value, s.thumbs[i-1].value)
+	}
+	cs := ctx.Constraints
+	if s.vertical {
+		h := float32(sliderLength)
+		if cs.HasBoundedHeight() {
+			h = cs.MaxHeight
+		}
+		return cs.Constrain(core.Sz(sliderExtent, h))
+	}
+	w := float32(sliderLength)
+	if cs.HasBoundedWidth() {
+		w = cs.MaxWidth
+	}
+	return cs.Constrain(core.Sz(w, sliderExtent))
+}
+
+// SetBounds implements core.Widget.
+func (s *slider) SetBounds(r core.Rect) {
+	s.WidgetBase.SetBounds(r)
+	const pad = sliderKnob / 2
+	if s.vertical {
+		s.track = core.R(r.X+(r.Width-sliderTrack)/2, r.Y+pad, sliderTrack, max(0, r.Height-2*pad))
+	} else {
+		s.track = core.R(r.X+pad, r.Y+(r.Height-sliderTrack)/2, max(0, r.Width-2*pad), sliderTrack)
+	}
+	for _, t := range s.thumbs {
+		p := s.pos(t.value)
+		t.SetBounds(core.R(p.X-sliderKnob/2, p.Y-sliderKnob/2, sliderKnob, sliderKnob))
+	}
+}
+
+// Paint implements core.Widget.
+func (s *slider) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	cv.DrawRoundedRect(s.track, sliderTrack/2, core.Filled(th.Colors.SurfaceVariant))
+
+	lo, hi := s.pos(s.min), s.pos(s.thumbs[0].value)
+	if len(s.thumbs) > 1 {
+		lo, hi = hi, s.pos(s.thumbs[len(s.thumbs)-1].value)
+	}
+	active := core.RectFromPoints(
+		core.Pt(min(lo.X, hi.X), min(lo.Y, hi.Y)),
+		core.Pt(max(lo.X, hi.X), max(lo.Y, hi.Y)),
+	).Inset(core.SymmetricInsets(-sliderTrack/2, -sliderTrack/2))
+	cv.DrawRoundedRect(active, sliderTrack/2, core.Filled(th.Colors.Primary))
+
+	if s.ticks > 0 && s.max > s.min && (s.max-s.min)/s.ticks <= 1000 {
+		tick := core.Filled(th.Colors.OnSurfaceVariant.WithAlpha(0.6))
+		for v := s.min; v <= s.max+s.ticks/1e6; v += s.ticks {
+			p := s.pos(v)
+			if s.vertical {
+				cv.DrawRect(core.R(s.track.Right()+3, p.Y-0.5, sliderTick, 1), tick)
+			} else {
+				cv.DrawRect(core.R(p.X-0.5, s.track.Bottom()+3, 1, sliderTick), tick)
+			}
+		}
+	}
+
+	for _, t := range s.thumbs {
+		t.Paint(ctx)
+	}
+	if s.drag != nil {
+		s.paintBubble(ctx, s.drag)
+	}
+}
+
+// paintBubble shows the value of t above it, or beside a vertical thumb.
+func (s *slider) paintBubble(ctx *core.PaintContext, t *sliderThumb) {
+	th := theme.From(ctx.Context)
+	style := theme.TextStyle(th.Typography.Label, th.Colors.OnPrimary)
+	r := s.bubble(ctx.Context, t)
+	pad := th.Spacing.S
+	ctx.Canvas.DrawRoundedRect(r, th.Radii.Small, core.Filled(th.Colors.Primary))
+	ctx.Canvas.DrawText(s.text(t.value), core.Pt(r.X+pad, r.Y+pad/2), style)
+}
+
+// bubble returns the bounds of the bubble showing the value of t.
+func (s *slider) bubble(ctx *core.Context, t *sliderThumb) core.Rect {
+	th := theme.From(ctx)
+	style := theme.TextStyle(th.Typography.Label, th.Colors.OnPrimary)
+	size := ctx.MeasureText(s.text(t.value), style)
+	pad := th.Spacing.S
+	w, h := size.Width+2*pad, style.LineHeight()+pad
+	b := t.Bounds()
+	if s.vertical {
+		return core.R(b.Right()+th.Spacing.S, b.Center().Y-h/2, w, h)
+	}
+	return core.R(b.Center().X-w/2, b.Y-h-th.Spacing.S, w, h)
+}
+
+// HandleEvent implements core.Widget. A press moves the nearest thumb to
+// the pointer and starts dragging it.
+func (s *slider) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	e, ok := ev.(event.MouseEvent)
+	if !ok {
+		return core.Ignored
+	}
+	switch e.Type {
+	case event.MouseDown:
+		if e.Button != event.ButtonLeft {
+			return core.Ignored
+		}
+		v := s.valueAt(e.Position)
+		t := s.thumbs[0]
+		for _, o := range s.thumbs[1:] {
+			// Ties go to the upper thumb when the pointer is above it, so
+			// two thumbs at the same value can still be pulled apart.
+			if d, best := math.Abs(o.value-v), math.Abs(t.value-v); d < best || d == best && v > o.value {
+				t = o
+			}
+		}
+		s.drag = t
+		ctx.RequestFocus(t)
+		ctx.CapturePointer(s.self)
+		s.change(ctx, t, v)
+		s.repaint(ctx)
+		return core.Handled
+	case event.MouseMove:
+		if s.drag == nil {
+			return core.Ignored
+		}
+		s.change(ctx, s.drag, s.valueAt(e.Position))
+		return core.Handled
+	case event.MouseUp:
+		if s.drag == nil {
+			return core.Ignored
+		}
+		s.repaint(ctx)
+		s.drag = nil
+		ctx.ReleasePointer()
+		return core.Handled
+	}
+	return core.Ignored
+}
+
+// sliderThumb is a focusable thumb of a slider.
+type sliderThumb struct {
+	core.WidgetBase
+	core.FocusState
+
+	slider *slider
+	index  int
+	value  float64
+	sig    *state.Signal[float64]
+	cancel func()
+}
+
+// release ends the subscription to the bound signal.
+func (t *sliderThumb) release() {
+	if t.cancel != nil {
+		t.cancel()
+		t.cancel = nil
+	}
+}
+
+func (t *sliderThumb) Layout(*core.LayoutContext) core.Size {
+	return core.Sz(sliderKnob, sliderKnob)
+}
+
+func (t *sliderThumb) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	b := t.Bounds()
+	if t.IsFocused() || t.slider.drag == t {
+		ctx.Canvas.DrawRoundedRect(b.Inset(core.UniformInsets(-4)), sliderKnob/2+4, core.Filled(th.Colors.Primary.WithAlpha(0.2)))
+	}
+	ctx.Canvas.DrawRoundedRect(b, sliderKnob/2, core.RectStyle{Fill: th.Colors.Primary, Stroke: th.Colors.Surface, StrokeWidth: 2})
+}
+
+// HandleEvent moves the thumb from the keyboard. Pointer events bubble to
+// the slider.
+func (t *sliderThumb) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	e, ok := ev.(event.KeyEvent)
+	if !ok || e.Type != event.KeyPress || !t.IsFocused() || e.Modifiers != 0 {
+		return core.Ignored
+	}
+	s := t.slider
+	v := t.value
+	key := e.Key
+	if !s.vertical {
+		key = mirrorKey(s.dir, key)
+	}
+	switch key {
+	case event.KeyLeft, event.KeyDown:
+		v -= s.keyStep()
+	case event.KeyRight, event.KeyUp:
+		v += s.keyStep()
+	case event.KeyPageDown:
+		v -= 10 * s.keyStep()
+	case event.KeyPageUp:
+		v += 10 * s.keyStep()
+	case event.KeyHome:
+		v = s.min
+	case event.KeyEnd:
+		v = s.max
+	default:
+		return core.Ignored
+	}
+	s.change(ctx, t, v)
+	return core.Handled
+}
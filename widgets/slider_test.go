@@ -0,0 +1,239 @@
+package widgets
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/state"
+)
+
+// sliderBounds puts a 200 wide track from x = 8 to 208 under the slider.
+var sliderBounds = core.R(0, 0, 200+sliderKnob, sliderExtent)
+
+// trackAt returns the point of a horizontal slider laid out in
+// sliderBounds at fraction f of its track.
+func trackAt(f float32) core.Point {
+	return core.Pt(sliderKnob/2+f*200, sliderExtent/2)
+}
+
+func TestSliderSnap(t *testing.T) {
+	tests := []struct {
+		name     string
+		min, max float64
+		step     float64
+		in, want float64
+		text     string
+	}{
+		{"continuous", 0, 1, 0, 0.123, 0.123, "0.12"},
+		{"clamped low", 0, 1, 0, -5, 0, "0.00"},
+		{"clamped high", 0, 1, 0, 5, 1, "1.00"},
+		{"whole steps", 0, 100, 5, 12, 10, "10"},
+		{"whole steps up", 0, 100, 5, 13, 15, "15"},
+		{"decimal step", 0, 1, 0.1, 0.3, 0.3, "0.3"},
+		{"from the minimum", 1, 10, 2, 4, 5, "5"},
+		{"reversed range", 10, 0, 0, 20, 10, "10.00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSlider(tt.min, tt.max).Step(tt.step)
+			s.SetValue(tt.in)
+			if got := s.Value(); got != tt.want {
+				t.Errorf("Value() = %v, want %v", got, tt.want)
+			}
+			if got := s.text(s.Value()); got != tt.text {
+				t.Errorf("text() = %q, want %q", got, tt.text)
+			}
+		})
+	}
+}
+
+func TestSliderKeys(t *testing.T) {
+	tests := []struct {
+		key  event.Key
+		step float64
+		want float64
+	}{
+		{event.KeyRight, 0, 51},
+		{event.KeyUp, 5, 55},
+		{event.KeyLeft, 5, 45},
+		{event.KeyDown, 0, 49},
+		{event.KeyPageUp, 0, 60},
+		{event.KeyPageDown, 2, 30},
+		{event.KeyHome, 5, 0},
+		{event.KeyEnd, 5, 100},
+	}
+	for _, tt := range tests {
+		s := NewSlider(0, 100).Step(tt.step)
+		s.SetValue(50)
+		var got []float64
+		s.OnChange(func(v float64) { got = append(got, v) })
+		ctx := layoutAt(s, sliderBounds)
+		thumb := s.thumbs[0]
+		ctx.RequestFocus(thumb)
+		if r := thumb.HandleEvent(ctx, press(tt.key, 0)); r != core.Handled {
+			t.Fatalf("key %v: %v", tt.key, r)
+		}
+		if s.Value() != tt.want || len(got) != 1 || got[0] != tt.want {
+			t.Errorf("key %v: Value() = %v with changes %v, want %v", tt.key, s.Value(), got, tt.want)
+		}
+		if c := thumb.Bounds().Center(); c != s.pos(tt.want) {
+			t.Errorf("key %v: thumb at %v, want %v", tt.key, c, s.pos(tt.want))
+		}
+	}
+
+	s := NewSlider(0, 100)
+	ctx := layoutAt(s, sliderBounds)
+	ctx.RequestFocus(s.thumbs[0])
+	if r := s.thumbs[0].HandleEvent(ctx, press(event.KeyLeft, 0)); r != core.Handled {
+		t.Errorf("Left at the minimum = %v, want Handled", r)
+	}
+	if r := s.thumbs[0].HandleEvent(ctx, press(event.KeyRight, event.ModShift)); r != core.Ignored {
+		t.Errorf("Shift+Right = %v, want Ignored", r)
+	}
+}
+
+func TestSliderDrag(t *testing.T) {
+	s := NewSlider(0, 100)
+	var got []float64
+	s.OnChange(func(v float64) { got = append(got, v) })
+	ctx := layoutAt(s, sliderBounds)
+	s.HandleEvent(ctx, leftMouse(event.MouseDown, trackAt(0.25)))
+	if ctx.PointerCapture() != s || ctx.Focused() != s.thumbs[0] {
+		t.Fatal("pressing the track did not start a drag")
+	}
+	s.HandleEvent(ctx, leftMouse(event.MouseMove, trackAt(0.75)))
+	s.HandleEvent(ctx, leftMouse(event.MouseMove, core.Pt(500, 0))) // Past the end.
+	s.HandleEvent(ctx, leftMouse(event.MouseUp, core.Pt(500, 0)))
+	if want := []float64{25, 75, 100}; len(got) != len(want) || got[0] != 25 || got[1] != 75 || got[2] != 100 {
+		t.Errorf("changes %v, want %v", got, want)
+	}
+	if ctx.PointerCapture() != nil || s.drag != nil {
+		t.Error("the drag did not end")
+	}
+	if r := s.HandleEvent(ctx, leftMouse(event.MouseMove, trackAt(0))); r != core.Ignored || s.Value() != 100 {
+		t.Error("moving without a drag changed the value")
+	}
+}
+
+func TestSliderRightToLeft(t *testing.T) {
+	s := NewSlider(0, 100)
+	ctx := layoutRTL(s, sliderBounds)
+	if got := s.pos(0); got != trackAt(1) {
+		t.Errorf("the minimum at %v, want the end of the track at %v", got, trackAt(1))
+	}
+	s.HandleEvent(ctx, leftMouse(event.MouseDown, trackAt(0.25)))
+	s.HandleEvent(ctx, leftMouse(event.MouseUp, trackAt(0.25)))
+	if s.Value() != 75 {
+		t.Errorf("Value() = %v a quarter from the left, want 75", s.Value())
+	}
+	thumb := s.thumbs[0]
+	ctx.RequestFocus(thumb)
+	thumb.HandleEvent(ctx, press(event.KeyLeft, 0))
+	if s.Value() != 76 {
+		t.Errorf("Value() = %v after Left, want 76", s.Value())
+	}
+	thumb.HandleEvent(ctx, press(event.KeyRight, 0))
+	thumb.HandleEvent(ctx, press(event.KeyRight, 0))
+	if s.Value() != 74 {
+		t.Errorf("Value() = %v after Right twice, want 74", s.Value())
+	}
+}
+
+func TestSliderVertical(t *testing.T) {
+	s := NewSlider(0, 100).Vertical(true)
+	ctx := layoutAt(s, core.R(0, 0, sliderExtent, 200+sliderKnob))
+	s.HandleEvent(ctx, leftMouse(event.MouseDown, core.Pt(sliderExtent/2, sliderKnob/2+50)))
+	if s.Value() != 75 {
+		t.Errorf("Value() = %v a quarter down the track, want 75", s.Value())
+	}
+	if c := s.thumbs[0].Bounds().Center(); c.X != sliderExtent/2 || c.Y != sliderKnob/2+50 {
+		t.Errorf("thumb at %v", c)
+	}
+}
+
+func TestRangeSlider(t *testing.T) {
+	tests := []struct {
+		name   string
+		lo, hi float64
+		press  float32
+		moveTo float32
+		wantLo float64
+		wantHi float64
+	}{
+		{"nearest is low", 20, 80, 0.3, 0.1, 10, 80},
+		{"nearest is high", 20, 80, 0.7, 0.9, 20, 90},
+		{"cannot pass", 20, 80, 0.3, 0.9, 80, 80},
+		{"ties above go up", 50, 50, 0.6, 0.7, 50, 70},
+		{"ties below go down", 50, 50, 0.4, 0.3, 30, 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewRangeSlider(0, 100).Step(1)
+			s.SetValues(tt.lo, tt.hi)
+			calls := 0
+			s.OnChange(func(lo, hi float64) { calls++ })
+			ctx := layoutAt(s, sliderBounds)
+			s.HandleEvent(ctx, leftMouse(event.MouseDown, trackAt(tt.press)))
+			s.HandleEvent(ctx, leftMouse(event.MouseMove, trackAt(tt.moveTo)))
+			s.HandleEvent(ctx, leftMouse(event.MouseUp, trackAt(tt.moveTo)))
+			if lo, hi := s.Values(); lo != tt.wantLo || hi != tt.wantHi {
+				t.Errorf("Values() = %v, %v, want %v, %v", lo, hi, tt.wantLo, tt.wantHi)
+			}
+			if calls == 0 {
+				t.Error("OnChange was not called")
+			}
+		})
+	}
+
+	s := NewRangeSlider(0, 10).Step(1)
+	if lo, hi := s.Values(); lo != 0 || hi != 10 {
+		t.Errorf("a new range covers %v to %v", lo, hi)
+	}
+	s.SetValues(7.4, 2.6)
+	if lo, hi := s.Values(); lo != 3 || hi != 7 {
+		t.Errorf("SetValues(7.4, 2.6) gave %v, %v, want 3, 7", lo, hi)
+	}
+}
+
+func TestSliderBind(t *testing.T) {
+	sig := state.New(0.5)
+	s := NewSlider(0, 1).Step(0.1).Bind(sig)
+	ctx := layoutAt(s, sliderBounds)
+	if s.Value() != 0.5 {
+		t.Errorf("Value() = %v, want the signal's 0.5", s.Value())
+	}
+	sig.Set(0.8)
+	ctx.RunPosted()
+	ctx.LayoutRoot(s, sliderBounds)
+	if s.Value() != 0.8 || s.thumbs[0].Bounds().Center() != s.pos(0.8) {
+		t.Errorf("Value() = %v after the signal changed, want 0.8", s.Value())
+	}
+	ctx.RequestFocus(s.thumbs[0])
+	s.thumbs[0].HandleEvent(ctx, press(event.KeyRight, 0))
+	if sig.Get() != 0.9 {
+		t.Errorf("signal holds %v after a key press, want 0.9", sig.Get())
+	}
+	s.SetValue(2)
+	if sig.Get() != 1 {
+		t.Errorf("SetValue stored %v in the signal, want 1", sig.Get())
+	}
+
+	// Binding another signal drops the first one.
+	other := state.New(0.2)
+	s.Bind(other)
+	ctx.Invalidate()
+	ctx.LayoutRoot(s, sliderBounds)
+	ctx.RunPosted()
+	sig.Set(0.4)
+	if ctx.HasPosted() || s.Value() != 0.2 {
+		t.Errorf("the old signal still drives the slider, at %v", s.Value())
+	}
+
+	lo, hi := state.New(10.0), state.New(5.0)
+	r := NewRangeSlider(0, 100).Bind(lo, hi)
+	layoutAt(r, sliderBounds)
+	if a, b := r.Values(); a != 10 || b != 10 {
+		t.Errorf("Values() = %v, %v bound to crossed signals, want 10, 10", a, b)
+	}
+}
@@ -0,0 +1,219 @@
+package widgets
+
+import (
+	"unicode"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/richtext"
+	"github.com/gogpu/ui/theme"
+)
+
+// This file lays out and paints runs of richtext spans, for the widgets
+// that display formatted text.
+
+// rtLine is a laid out line of a block. y is relative to the top of the
+// content.
+type rtLine struct {
+	block      int
+	start, end int
+	y, h       float32
+	indent     float32
+	marker     string
+	runs       []rtRun
+}
+
+// rtRun is a stretch of a line drawn in one format.
+type rtRun struct {
+	start, end int
+	text       string
+	x, w       float32
+	style      core.TextStyle
+	span       richtext.Span
+}
+
+// rtCell is one position of a block: a rune of a span's text, or the
+// span's image.
+type rtCell struct {
+	r    rune
+	span int
+}
+
+// headingStyle returns the text style of a heading of level 1 to 6.
+func headingStyle(th *theme.Theme, level int) core.TextStyle {
+	st := th.Typography.Body
+	st.Color = th.Colors.OnSurface
+	scale := [...]float32{1.6, 1.35, 1.15, 1, 0.9, 0.85}[min(max(level, 1), 6)-1]
+	st.Size *= scale
+	st.Weight = core.WeightSemiBold
+	return st
+}
+
+// runStyle returns the text style of a span in a block of style base.
+func runStyle(th *theme.Theme, base core.TextStyle, s richtext.Span) core.TextStyle {
+	if s.Style.Has(richtext.Bold) {
+		base.Weight = core.WeightBold
+	}
+	if s.Style.Has(richtext.Italic) {
+		base.Italic = true
+	}
+	if s.Style.Has(richtext.Code) {
+		base.Family = th.Typography.Mono.Family
+		base.Size *= th.Typography.Mono.Size / th.Typography.Body.Size
+	}
+	if s.Link != "" {
+		base.Color = th.Colors.Primary
+	}
+	return base
+}
+
+// wrapSpans breaks spans into lines at most width wide, at spaces and
+// around images, and appends them to lines. A newline in the text always
+// breaks the line. The first line is a copy of line; the lines after it
+// keep its block and indent. wrapSpans returns the lines and the y below
+// the last of them.
+func wrapSpans(ctx *core.Context, lines []rtLine, spans []richtext.Span, base core.TextStyle, width float32, line rtLine) ([]rtLine, float32) {
+	th := theme.From(ctx)
+	var cells []rtCell
+	for si, s := range spans {
+		if s.Image != nil {
+			cells = append(cells, rtCell{span: si})
+			continue
+		}
+		for _, r := range s.Text {
+			cells = append(cells, rtCell{r: r, span: si})
+		}
+	}
+	y := line.y
+	line.h = base.LineHeight()
+	x := float32(0)
+	flush := func(end int) {
+		line.end = end
+		lines = append(lines, line)
+		y += line.h
+		line = rtLine{block: line.block, indent: line.indent, start: end, y: y, h: base.LineHeight()}
+		x = 0
+	}
+	for ws := 0; ws < len(cells); {
+		// A word runs to the next space or image, with the spaces after
+		// it; an image is a word of its own.
+		we := ws + 1
+		if spans[cells[ws].span].Image == nil {
+			for we < len(cells) && spans[cells[we].span].Image == nil && !unicode.IsSpace(cells[we].r) {
+				we++
+			}
+		}
+		body := we
+		brk := false
+		for we < len(cells) && spans[cells[we].span].Image == nil && unicode.IsSpace(cells[we].r) && !brk {
+			brk = cells[we].r == '\n'
+			we++
+		}
+		var runs []rtRun
+		var ww, bodyW float32
+		for i := ws; i < we; {
+			j := i + 1
+			for j < we && cells[j].span == cells[i].span {
+				j++
+			}
+			s := spans[cells[i].span]
+			run := rtRun{start: i, end: j, span: s, style: runStyle(th, base, s)}
+			if s.Image != nil {
+				run.w, _ = s.Image.Size()
+			} else {
+				rs := make([]rune, j-i)
+				for k := range rs {
+					if rs[k] = cells[i+k].r; rs[k] == '\n' {
+						rs[k] = ' '
+					}
+				}
+				run.text = string(rs)
+				run.w = ctx.MeasureText(run.text, run.style).Width
+			}
+			if i < body {
+				// Only the characters before the trailing spaces count
+				// toward fitting the line.
+				bodyW = ww + run.w
+				if j > body && s.Image == nil {
+					bodyW = ww + ctx.MeasureText(string([]rune(run.text)[:body-i]), run.style).Width
+				}
+			}
+			ww += run.w
+			runs = append(runs, run)
+			i = j
+		}
+		if x > 0 && x+bodyW > width {
+			flush(ws)
+		}
+		for _, run := range runs {
+			run.x = x
+			x += run.w
+			h := run.style.LineHeight()
+			if run.span.Image != nil {
+				_, h = run.span.Image.Size()
+			}
+			line.h = max(line.h, h)
+			if n := len(line.runs); n > 0 && line.runs[n-1].span == run.span && run.span.Image == nil {
+				last := &line.runs[n-1]
+				last.end, last.text, last.w = run.end, last.text+run.text, last.w+run.w
+				continue
+			}
+			line.runs = append(line.runs, run)
+		}
+		if brk && we < len(cells) {
+			flush(we)
+		}
+		ws = we
+	}
+	flush(len(cells))
+	return lines, y
+}
+
+// trimmedWidth returns the width of run r without the trailing spaces it
+// has as the last run of line l.
+func trimmedWidth(ctx *core.Context, l *rtLine, r *rtRun) float32 {
+	if r.end != l.end {
+		return r.w
+	}
+	rs := []rune(r.text)
+	n := len(rs)
+	for n > 0 && unicode.IsSpace(rs[n-1]) {
+		n--
+	}
+	if n == len(rs) {
+		return r.w
+	}
+	return ctx.MeasureText(string(rs[:n]), r.style).Width
+}
+
+// paintRuns draws the text and images of line l, whose content starts at
+// origin.
+func paintRuns(ctx *core.PaintContext, l *rtLine, origin core.Point) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	top := origin.Y + l.y
+	for i := range l.runs {
+		r := &l.runs[i]
+		x := origin.X + l.indent + r.x
+		if img := r.span.Image; img != nil {
+			w, h := img.Size()
+			rect := core.R(x, top+l.h-h, w, h)
+			if img.Data != nil {
+				cv.DrawImage(img.Data, rect)
+			} else {
+				cv.DrawRect(rect, core.RectStyle{Fill: th.Colors.SurfaceVariant, Stroke: th.Colors.Outline, StrokeWidth: 1})
+			}
+			continue
+		}
+		y := top + l.h - r.style.LineHeight()
+		if r.span.Style.Has(richtext.Code) {
+			w := trimmedWidth(ctx.Context, l, r)
+			cv.DrawRoundedRect(core.R(x-2, y, w+4, r.style.LineHeight()), 3, core.Filled(th.Colors.SurfaceVariant))
+		}
+		cv.DrawText(r.text, core.Pt(x, y), r.style)
+		if r.span.Style.Has(richtext.Underline) || r.span.Link != "" {
+			// Leave the trailing spaces of a line undecorated.
+			w := trimmedWidth(ctx.Context, l, r)
+			cv.DrawRect(core.R(x, y+r.style.Size*1.1, w, 1), core.Filled(r.style.Color))
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package widgets
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/richtext"
+	"github.com/gogpu/ui/theme"
+)
+
+// lineTexts returns the text of each of lines.
+func lineTexts(lines []rtLine) []string {
+	texts := make([]string, len(lines))
+	for i, l := range lines {
+		for _, r := range l.runs {
+			texts[i] += r.text
+		}
+	}
+	return texts
+}
+
+func TestWrapSpans(t *testing.T) {
+	ctx := core.NewContext()
+	th := theme.From(ctx)
+	base := th.Typography.Body
+	width := ctx.MeasureText("aaa bbb", base).Width
+	img := &richtext.Image{Width: 10, Height: 50}
+	tests := []struct {
+		name  string
+		spans []richtext.Span
+		width float32
+		want  []string
+	}{
+		{"fits", []richtext.Span{{Text: "aaa bbb"}}, width, []string{"aaa bbb"}},
+		{"wraps at spaces", []richtext.Span{{Text: "aaa bbb ccc"}}, width, []string{"aaa bbb ", "ccc"}},
+		{"trailing spaces do not wrap", []richtext.Span{{Text: "aaa bbb   "}}, width, []string{"aaa bbb   "}},
+		{"a long word stays whole", []richtext.Span{{Text: "aaaaaaaaaaaa b"}}, width, []string{"aaaaaaaaaaaa ", "b"}},
+		{"newlines break", []richtext.Span{{Text: "a\nb"}}, width, []string{"a ", "b"}},
+		{"styles within a word", []richtext.Span{{Text: "aa"}, {Text: "a", Style: richtext.Bold}, {Text: " bbb ccc"}}, width * 1.1, []string{"aaa bbb ", "ccc"}},
+		{"images are words", []richtext.Span{{Text: "aaa"}, {Image: img}, {Text: "b"}}, ctx.MeasureText("aaa", base).Width + 5, []string{"aaa", "b"}},
+		{"empty", nil, width, []string{""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, bottom := wrapSpans(ctx, nil, tt.spans, base, tt.width, rtLine{block: 3, indent: 7, y: 10})
+			got := lineTexts(lines)
+			if len(got) != len(tt.want) {
+				t.Fatalf("lines %q, want %q", got, tt.want)
+			}
+			y := float32(10)
+			for i, l := range lines {
+				if got[i] != tt.want[i] {
+					t.Errorf("line %d is %q, want %q", i, got[i], tt.want[i])
+				}
+				if l.block != 3 || l.indent != 7 || l.y != y {
+					t.Errorf("line %d of block %d at %v, %v", i, l.block, l.indent, l.y)
+				}
+				if i > 0 && l.start != lines[i-1].end {
+					t.Errorf("line %d starts at %d after a line ending at %d", i, l.start, lines[i-1].end)
+				}
+				y += l.h
+			}
+			if bottom != y {
+				t.Errorf("bottom %v, want %v", bottom, y)
+			}
+		})
+	}
+
+	lines, _ := wrapSpans(ctx, nil, []richtext.Span{{Text: "a"}, {Image: img}}, base, 100, rtLine{})
+	if len(lines[0].runs) != 2 || lines[0].h != 50 {
+		t.Errorf("a line with an image is %v high, want the image's 50", lines[0].h)
+	}
+}
+
+func TestTrimmedWidth(t *testing.T) {
+	ctx := core.NewContext()
+	base := theme.From(ctx).Typography.Body
+	lines, _ := wrapSpans(ctx, nil, []richtext.Span{{Text: "ab  ", Link: "u"}, {Text: "cd  "}}, base, 1000, rtLine{})
+	l := &lines[0]
+	if got, want := trimmedWidth(ctx, l, &l.runs[0]), l.runs[0].w; got != want {
+		t.Errorf("the first run is %v wide, want all of %v", got, want)
+	}
+	if got, want := trimmedWidth(ctx, l, &l.runs[1]), ctx.MeasureText("cd", base).Width; got != want {
+		t.Errorf("the last run is %v wide, want %v without its spaces", got, want)
+	}
+}
+
+func TestRunStyle(t *testing.T) {
+	th := theme.Light()
+	base := th.Typography.Body
+	tests := []struct {
+		span  richtext.Span
+		check func(core.TextStyle) bool
+	}{
+		{richtext.Span{}, func(s core.TextStyle) bool { return s == base }},
+		{richtext.Span{Style: richtext.Bold}, func(s core.TextStyle) bool { return s.Weight == core.WeightBold }},
+		{richtext.Span{Style: richtext.Italic}, func(s core.TextStyle) bool { return s.Italic }},
+		{richtext.Span{Style: richtext.Code}, func(s core.TextStyle) bool {
+			return s.Family == th.Typography.Mono.Family && s.Size == th.Typography.Mono.Size
+		}},
+		{richtext.Span{Link: "u"}, func(s core.TextStyle) bool { return s.Color == th.Colors.Primary }},
+	}
+	for _, tt := range tests {
+		if s := runStyle(th, base, tt.span); !tt.check(s) {
+			t.Errorf("runStyle(%+v) = %+v", tt.span, s)
+		}
+	}
+	if headingStyle(th, 0) != headingStyle(th, 1) || headingStyle(th, 9) != headingStyle(th, 6) {
+		t.Error("heading levels are not clamped to 1 to 6")
+	}
+	if headingStyle(th, 1).Size <= headingStyle(th, 2).Size {
+		t.Error("a level 1 heading is not larger than level 2")
+	}
+}
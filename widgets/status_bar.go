@@ -0,0 +1,386 @@
+package widgets
+
+import (
+	"slices"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/state"
+	"github.com/gogpu/ui/theme"
+)
+
+const (
+	statusBarHeight    float32 = 24
+	statusItemPadding  float32 = 8
+	statusContentWidth float32 = 160
+)
+
+// StatusSection is the part of a StatusBar an item is placed in.
+type StatusSection int
+
+// Status bar sections.
+const (
+	StatusLeft StatusSection = iota
+	StatusCenter
+	StatusRight
+)
+
+// StatusItem is an entry of a StatusBar: a text, or any widget such as a
+// ProgressBar.
+type StatusItem struct {
+	text     string
+	content  core.Widget
+	sig      *state.Signal[string]
+	cancel   func()
+	priority int
+	tipText  string
+	onClick  func()
+	width    float32
+
+	tip      tipCache
+	view     core.Widget
+	measured float32
+	contentH float32
+	rect     core.Rect
+	shown    bool
+}
+
+// NewStatusItem returns an item showing text.
+func NewStatusItem(text string) *StatusItem {
+	return &StatusItem{text: text}
+}
+
+// NewStatusWidget returns an item showing content.
+func NewStatusWidget(content core.Widget) *StatusItem {
+	return &StatusItem{content: content}
+}
+
+// Text returns the item's text.
+func (it *StatusItem) Text() string {
+	return it.text
+}
+
+// SetText replaces the item's text.
+func (it *StatusItem) SetText(s string) {
+	it.text = s
+}
+
+// Bind makes the item show the value of sig.
+func (it *StatusItem) Bind(sig *state.Signal[string]) *StatusItem {
+	it.release()
+	it.sig = sig
+	return it
+}
+
+// release ends the subscription to the bound signal.
+func (it *StatusItem) release() {
+	if it.cancel != nil {
+		it.cancel()
+		it.cancel = nil
+	}
+}
+
+// Priority sets how long the item stays when the bar is too narrow: items
+// of lower priority are hidden first, and of equal priority the later
+// ones. The default is 0.
+func (it *StatusItem) Priority(p int) *StatusItem {
+	it.priority = p
+	return it
+}
+
+// Tooltip sets the text of the item's tooltip.
+func (it *StatusItem) Tooltip(text string) *StatusItem {
+	it.tipText = text
+	return it
+}
+
+// OnClick makes the item clickable, calling fn when it is clicked.
+func (it *StatusItem) OnClick(fn func()) *StatusItem {
+	it.onClick = fn
+	return it
+}
+
+// Width fixes the item's width, so that changing text such as a cursor
+// position does not shift its neighbors. Text that does not fit is
+// clipped. By default texts take their own width and widgets up to 160.
+func (it *StatusItem) Width(w float32) *StatusItem {
+	it.width = w
+	return it
+}
+
+// IsShown reports whether the item was shown by the last layout, rather
+// than hidden for lack of room.
+func (it *StatusItem) IsShown() bool {
+	return it.shown
+}
+
+// StatusBar is the strip along the bottom of a window showing status
+// texts and small widgets in a left, a center and a right section. In
+// right-to-left text the bar is mirrored: the left section is on the
+// right, and items run from right to left.
+//
+// When the window is too narrow for every item, items are hidden in order
+// of priority until the rest fit; see StatusItem.Priority. Items can have
+// tooltips, and clickable items are highlighted under the pointer.
+type StatusBar struct {
+	core.WidgetBase
+
+	sections [3][]*StatusItem
+	hover    *StatusItem
+	press    *StatusItem
+	dir      core.Direction
+}
+
+// NewStatusBar returns an empty status bar.
+func NewStatusBar() *StatusBar {
+	return &StatusBar{}
+}
+
+// Add appends items to section.
+func (b *StatusBar) Add(section StatusSection, items ...*StatusItem) *StatusBar {
+	b.sections[section] = append(b.sections[section], items...)
+	return b
+}
+
+// Items returns the items of section.
+func (b *StatusBar) Items(section StatusSection) []*StatusItem {
+	return b.sections[section]
+}
+
+// Remove removes item from the bar.
+func (b *StatusBar) Remove(item *StatusItem) {
+	for i, s := range b.sections {
+		b.sections[i] = slices.DeleteFunc(s, func(x *StatusItem) bool { return x == item })
+	}
+	item.release()
+	if b.hover == item {
+		b.hover = nil
+	}
+	if b.press == item {
+		b.press = nil
+	}
+}
+
+// Release stops following the signals the items are bound to, for when
+// the bar leaves the tree. Laid out again, the bar follows the signals
+// from their values then.
+func (b *StatusBar) Release() {
+	for _, s := range b.sections {
+		for _, it := range s {
+			it.release()
+		}
+	}
+}
+
+// Layout implements core.Widget.
+func (b *StatusBar) Layout(ctx *core.LayoutContext) core.Size {
+	b.dir = ctx.Direction()
+	style := theme.From(ctx.Context).Typography.Caption
+	h := max(statusBarHeight, style.LineHeight()+8)
+	var total float32
+	for _, s := range b.sections {
+		for _, it := range s {
+			if it.sig != nil {
+				if it.cancel == nil {
+					c := ctx.Context
+					it.cancel = it.sig.Subscribe(func(string) { c.Post(func() { c.MarkNeedsLayout(b) }) })
+				}
+				it.text = it.sig.Get()
+			}
+			it.view = it.tip.wrap(it.content, it.tipText)
+			switch {
+			case it.content != nil:
+				w := statusContentWidth
+				if it.width > 0 {
+					w = it.width
+				}
+				c := core.Constraints{MaxWidth: w, MaxHeight: h - 4}
+				if it.width > 0 {
+					c.MinWidth = w
+				}
+				sz := ctx.Measure(it.view, c)
+				it.measured, it.contentH = sz.Width+2*statusItemPadding, sz.Height
+			case it.width > 0:
+				it.measured = it.width + 2*statusItemPadding
+			default:
+				it.measured = ctx.MeasureText(it.text, style).Width + 2*statusItemPadding
+			}
+			total += it.measured
+		}
+	}
+	size := core.Sz(total, h)
+	if ctx.Constraints.HasBoundedWidth() {
+		size.Width = ctx.Constraints.MaxWidth
+	}
+	return ctx.Constraints.Constrain(size)
+}
+
+// SetBounds implements core.Widget.
+func (b *StatusBar) SetBounds(r core.Rect) {
+	b.WidgetBase.SetBounds(r)
+	var all []*StatusItem
+	var total float32
+	for _, s := range b.sections {
+		for _, it := range s {
+			it.shown = true
+			all = append(all, it)
+			total += it.measured
+		}
+	}
+	// Hide the lowest priorities first and, among equals, the later items.
+	order := slices.Clone(all)
+	slices.SortStableFunc(order, func(x, y *StatusItem) int { return x.priority - y.priority })
+	for total > r.Width && len(order) > 0 {
+		j := 0
+		for j+1 < len(order) && order[j+1].priority == order[0].priority {
+			j++
+		}
+		order[j].shown = false
+		total -= order[j].measured
+		order = slices.Delete(order, j, j+1)
+	}
+
+	width := func(s []*StatusItem) float32 {
+		var w float32
+		for _, it := range s {
+			if it.shown {
+				w += it.measured
+			}
+		}
+		return w
+	}
+	place := func(s []*StatusItem, x float32) {
+		for _, it := range s {
+			if !it.shown {
+				it.rect = core.Rect{}
+				continue
+			}
+			it.rect = b.dir.Mirror(core.R(x, r.Y, it.measured, r.Height), r)
+			x += it.measured
+		}
+	}
+	left, center, right := b.sections[StatusLeft], b.sections[StatusCenter], b.sections[StatusRight]
+	lw, cw, rw := width(left), width(center), width(right)
+	place(left, r.X)
+	place(right, r.Right()-rw)
+	cx := core.Clamp(r.X+(r.Width-cw)/2, r.X+lw, max(r.X+lw, r.Right()-rw-cw))
+	place(center, cx)
+
+	var children []core.Widget
+	for _, it := range all {
+		if !it.shown || it.view == nil {
+			continue
+		}
+		if it.content != nil {
+			inner := it.rect.Inset(core.Insets{Left: statusItemPadding, Right: statusItemPadding})
+			inner.Y += (inner.Height - it.contentH) / 2
+			inner.Height = it.contentH
+			it.view.SetBounds(inner)
+		} else {
+			it.view.SetBounds(it.rect)
+		}
+		children = append(children, it.view)
+	}
+	b.SetChildren(children...)
+}
+
+// Paint implements core.Widget.
+func (b *StatusBar) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	r := b.Bounds()
+	line := core.Filled(th.Colors.Outline.WithAlpha(0.4))
+	cv.DrawRect(r, core.Filled(th.Colors.SurfaceVariant))
+	cv.DrawRect(core.R(r.X, r.Y, r.Width, 1), line)
+	style := theme.TextStyle(th.Typography.Caption, th.Colors.OnSurfaceVariant)
+	for _, s := range b.sections {
+		var prev *StatusItem
+		for _, it := range s {
+			if !it.shown {
+				continue
+			}
+			ir := it.rect
+			if prev != nil {
+				x := ir.X
+				if b.dir.IsRTL() {
+					x = ir.Right() - 1
+				}
+				cv.DrawRect(core.R(x, ir.Y+5, 1, ir.Height-10), line)
+			}
+			prev = it
+			if it.onClick != nil {
+				switch {
+				case it == b.press && it == b.hover:
+					cv.DrawRect(ir, core.Filled(th.Colors.OnSurface.WithAlpha(0.12)))
+				case it == b.hover:
+					cv.DrawRect(ir, core.Filled(th.Colors.OnSurface.WithAlpha(0.06)))
+				}
+			}
+			if it.content != nil {
+				it.content.Paint(ctx)
+				continue
+			}
+			cv.Save()
+			cv.Clip(ir.Inset(core.Insets{Left: statusItemPadding, Right: statusItemPadding}))
+			x := ir.X + statusItemPadding
+			if b.dir.IsRTL() {
+				x = ir.Right() - statusItemPadding - ctx.MeasureText(it.text, style).Width
+			}
+			cv.DrawText(it.text, core.Pt(x, ir.Y+(ir.Height-style.LineHeight())/2), style)
+			cv.Restore()
+		}
+	}
+}
+
+func (b *StatusBar) itemAt(p core.Point) *StatusItem {
+	for _, s := range b.sections {
+		for _, it := range s {
+			if it.shown && it.rect.Contains(p) {
+				return it
+			}
+		}
+	}
+	return nil
+}
+
+// HandleEvent implements core.Widget.
+func (b *StatusBar) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	e, ok := ev.(event.MouseEvent)
+	if !ok {
+		return core.Ignored
+	}
+	switch e.Type {
+	case event.MouseEnter, event.MouseMove:
+		if it := b.itemAt(e.Position); it != b.hover {
+			b.hover = it
+			ctx.Repaint(b)
+		}
+	case event.MouseLeave:
+		if b.hover != nil {
+			b.hover = nil
+			ctx.Repaint(b)
+		}
+	case event.MouseDown:
+		it := b.itemAt(e.Position)
+		if e.Button != event.ButtonLeft || it == nil || it.onClick == nil {
+			return core.Ignored
+		}
+		b.press = it
+		ctx.CapturePointer(b)
+		ctx.Repaint(b)
+		return core.Handled
+	case event.MouseUp:
+		it := b.press
+		if it == nil || e.Button != event.ButtonLeft {
+			return core.Ignored
+		}
+		b.press = nil
+		ctx.ReleasePointer()
+		ctx.Repaint(b)
+		if it.rect.Contains(e.Position) {
+			it.onClick()
+		}
+		return core.Handled
+	}
+	return core.Ignored
+}
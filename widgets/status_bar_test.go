@@ -0,0 +1,199 @@
+package widgets
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/state"
+)
+
+// statusItem returns an item w wide plus the padding.
+func statusItem(text string, w float32) *StatusItem {
+	return NewStatusItem(text).Width(w)
+}
+
+func itemRects(items ...*StatusItem) []core.Rect {
+	var rects []core.Rect
+	for _, it := range items {
+		rects = append(rects, it.rect)
+	}
+	return rects
+}
+
+func TestStatusBarLayout(t *testing.T) {
+	const p = statusItemPadding
+	a, b := statusItem("a", 20), statusItem("b", 30)
+	c := statusItem("c", 40)
+	d := statusItem("d", 10)
+	bar := NewStatusBar().Add(StatusLeft, a, b).Add(StatusCenter, c).Add(StatusRight, d)
+	lc := &core.LayoutContext{Context: core.NewContext()}
+	if got := lc.Measure(bar, core.Unbounded()); got.Width != 100+8*p || got.Height < statusBarHeight {
+		t.Errorf("size %v", got)
+	}
+
+	layoutAt(bar, core.R(0, 0, 400, 24))
+	want := []core.Rect{core.R(0, 0, 20+2*p, 24), core.R(20+2*p, 0, 30+2*p, 24), core.R(200-(20+p), 0, 40+2*p, 24), core.R(400-10-2*p, 0, 10+2*p, 24)}
+	if got := itemRects(a, b, c, d); !equalRects(got, want) {
+		t.Errorf("items at %v, want %v", got, want)
+	}
+
+	// In right-to-left text the sections and their items swap sides.
+	layoutRTL(bar, core.R(0, 0, 400, 24))
+	for i, r := range want {
+		want[i].X = 400 - r.Right()
+	}
+	if got := itemRects(a, b, c, d); !equalRects(got, want) {
+		t.Errorf("right to left: items at %v, want %v", got, want)
+	}
+
+	// The center section moves aside rather than cover the others.
+	wide := statusItem("wide", 200)
+	bar = NewStatusBar().Add(StatusLeft, wide).Add(StatusCenter, c)
+	layoutAt(bar, core.R(0, 0, 400, 24))
+	if c.rect.X != wide.rect.Right() {
+		t.Errorf("the center item at %v overlaps the left one ending at %v", c.rect.X, wide.rect.Right())
+	}
+
+	// A widget is centered vertically in its item.
+	w := NewStatusWidget(&fixed{height: 10}).Width(50)
+	bar = NewStatusBar().Add(StatusRight, w)
+	layoutAt(bar, core.R(0, 0, 400, 24))
+	if got := w.content.Bounds(); got != core.R(400-50-p, 7, 50, 10) {
+		t.Errorf("the widget at %v", got)
+	}
+	if got := lc.Measure(NewStatusBar().Add(StatusLeft, NewStatusWidget(&fixed{height: 10})), core.Unbounded()); got.Width != statusContentWidth+2*p {
+		t.Errorf("a widget without a width takes %v", got.Width)
+	}
+}
+
+func equalRects(a, b []core.Rect) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestStatusBarPriority(t *testing.T) {
+	const p = statusItemPadding
+	items := []*StatusItem{
+		statusItem("0", 20).Priority(1),
+		statusItem("1", 20),
+		statusItem("2", 20),
+		statusItem("3", 20).Priority(2),
+	}
+	bar := NewStatusBar().Add(StatusLeft, items[:2]...).Add(StatusRight, items[2:]...)
+	tests := []struct {
+		width float32
+		shown []bool
+	}{
+		{4 * (20 + 2*p), []bool{true, true, true, true}},
+		{4*(20+2*p) - 1, []bool{true, true, false, true}},
+		{2 * (20 + 2*p), []bool{true, false, false, true}},
+		{20 + 2*p, []bool{false, false, false, true}},
+		{0, []bool{false, false, false, false}},
+	}
+	for _, tt := range tests {
+		ctx := layoutAt(bar, core.R(0, 0, tt.width, 24))
+		var got []bool
+		for _, it := range items {
+			got = append(got, it.IsShown())
+		}
+		if !equalBools(got, tt.shown) {
+			t.Errorf("at %v wide shown %v, want %v", tt.width, got, tt.shown)
+		}
+		cv := &textCanvas{}
+		bar.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+		var want []string
+		for i, it := range items {
+			if tt.shown[i] {
+				want = append(want, it.Text())
+			}
+		}
+		if !equalStrings(cv.texts, want) {
+			t.Errorf("at %v wide drew %q", tt.width, cv.texts)
+		}
+	}
+}
+
+func TestStatusBarItems(t *testing.T) {
+	sig := state.New("Ln 1")
+	pos := NewStatusItem("").Bind(sig)
+	msg := NewStatusItem("Ready").Tooltip("status")
+	bar := NewStatusBar().Add(StatusLeft, msg).Add(StatusRight, pos)
+	ctx := layoutAt(bar, core.R(0, 0, 400, 24))
+	if pos.Text() != "Ln 1" {
+		t.Errorf("the bound item shows %q", pos.Text())
+	}
+	if kids := bar.Children(); len(kids) != 1 || kids[0].Bounds() != msg.rect {
+		t.Errorf("children %v, want the tooltip area over the item", kids)
+	}
+	sig.Set("Ln 20")
+	runPosted(t, ctx)
+	ctx.LayoutRoot(bar, core.R(0, 0, 400, 24))
+	if pos.Text() != "Ln 20" {
+		t.Errorf("after the signal changed the item shows %q", pos.Text())
+	}
+	msg.SetText("Saved")
+	if msg.Text() != "Saved" {
+		t.Errorf("SetText kept %q", msg.Text())
+	}
+
+	if got := bar.Items(StatusRight); len(got) != 1 || got[0] != pos {
+		t.Errorf("right items %v", got)
+	}
+	bar.Remove(pos)
+	if len(bar.Items(StatusRight)) != 0 {
+		t.Error("the item was not removed")
+	}
+	sig.Set("Ln 30")
+	if ctx.HasPosted() {
+		t.Error("a removed item still follows its signal")
+	}
+}
+
+func TestStatusBarClick(t *testing.T) {
+	var clicks int
+	btn := statusItem("btn", 40).OnClick(func() { clicks++ })
+	plain := statusItem("plain", 40)
+	bar := NewStatusBar().Add(StatusLeft, btn, plain)
+	ctx := layoutAt(bar, core.R(0, 0, 400, 24))
+	in, out := btn.rect.Center(), plain.rect.Center()
+
+	bar.HandleEvent(ctx, event.MouseEvent{Type: event.MouseMove, Position: in})
+	if bar.hover != btn {
+		t.Error("hovering the item did not highlight it")
+	}
+	if r := bar.HandleEvent(ctx, leftMouse(event.MouseDown, out)); r != core.Ignored {
+		t.Error("a press on an item without OnClick was handled")
+	}
+	if r := bar.HandleEvent(ctx, leftMouse(event.MouseDown, in)); r != core.Handled || ctx.PointerCapture() != bar {
+		t.Fatal("a press on the item was not captured")
+	}
+	bar.HandleEvent(ctx, leftMouse(event.MouseUp, in))
+	if clicks != 1 || ctx.PointerCapture() != nil {
+		t.Errorf("a click clicked %d times", clicks)
+	}
+	bar.HandleEvent(ctx, leftMouse(event.MouseDown, in))
+	bar.HandleEvent(ctx, leftMouse(event.MouseUp, out))
+	if clicks != 1 {
+		t.Error("releasing off the item clicked it")
+	}
+	if r := bar.HandleEvent(ctx, leftMouse(event.MouseUp, in)); r != core.Ignored {
+		t.Error("a release without a press was handled")
+	}
+	bar.HandleEvent(ctx, event.MouseEvent{Type: event.MouseLeave})
+	if bar.hover != nil {
+		t.Error("the highlight stayed after the pointer left")
+	}
+	bar.HandleEvent(ctx, leftMouse(event.MouseDown, in))
+	bar.Remove(btn)
+	if bar.press != nil || bar.hover != nil {
+		t.Error("the removed item is still pressed")
+	}
+}
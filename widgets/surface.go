@@ -0,0 +1,116 @@
+package widgets
+
+import (
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/theme"
+)
+
+// Surface paints its child on a rounded rectangle of the theme's surface
+// color or a gradient, with an optional border, raised off what lies
+// behind it by the shadows of an elevation:
+//
+//	card := widgets.NewSurface(content).Elevation(theme.ElevationRaised)
+//
+// The shadows follow the theme's design, so the same elevation looks
+// native under Material, Fluent and Cupertino. They are drawn outside the
+// bounds and are not included in the size.
+type Surface struct {
+	core.WidgetBase
+
+	child     core.Widget
+	elevation int
+	radius    float32
+	hasRadius bool
+	color     core.Color
+	hasColor  bool
+	gradient  *core.Gradient
+
+	border         core.Color
+	borderGradient *core.Gradient
+	borderWidth    float32
+}
+
+// NewSurface returns a flat surface behind child with the theme's medium
+// radius.
+func NewSurface(child core.Widget) *Surface {
+	s := &Surface{child: child}
+	s.SetChildren(child)
+	return s
+}
+
+// Elevation raises the surface to level, from theme.ElevationFlat to
+// theme.ElevationMax.
+func (s *Surface) Elevation(level int) *Surface {
+	s.elevation = level
+	return s
+}
+
+// Radius replaces the theme's corner radius.
+func (s *Surface) Radius(r float32) *Surface {
+	s.radius, s.hasRadius = max(r, 0), true
+	return s
+}
+
+// Color replaces the theme's surface color.
+func (s *Surface) Color(c core.Color) *Surface {
+	s.color, s.hasColor = c, true
+	return s
+}
+
+// Gradient fills the surface with g in place of its color, usually a
+// Relative gradient so that it stretches with the surface. Nil removes
+// it.
+func (s *Surface) Gradient(g *core.Gradient) *Surface {
+	s.gradient = g
+	return s
+}
+
+// Border draws a border of c and width inside the edge of the surface.
+func (s *Surface) Border(c core.Color, width float32) *Surface {
+	s.border, s.borderGradient, s.borderWidth = c, nil, max(width, 0)
+	return s
+}
+
+// BorderGradient draws a border of g and width inside the edge of the
+// surface.
+func (s *Surface) BorderGradient(g *core.Gradient, width float32) *Surface {
+	s.border, s.borderGradient, s.borderWidth = g.Average(), g, max(width, 0)
+	return s
+}
+
+// Layout implements core.Widget.
+func (s *Surface) Layout(ctx *core.LayoutContext) core.Size {
+	return ctx.Measure(s.child, ctx.Constraints)
+}
+
+// SetBounds implements core.Widget.
+func (s *Surface) SetBounds(r core.Rect) {
+	s.WidgetBase.SetBounds(r)
+	s.child.SetBounds(r)
+}
+
+// Paint implements core.Widget.
+func (s *Surface) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	r := s.Bounds()
+	radius, fill := th.Radii.Medium, th.Colors.Surface
+	if s.hasRadius {
+		radius = s.radius
+	}
+	if s.hasColor {
+		fill = s.color
+	}
+	core.DrawShadow(ctx.Canvas, r, radius, th.Elevation(s.elevation)...)
+	st := core.Filled(fill)
+	if s.gradient != nil {
+		st = core.GradientFilled(s.gradient)
+	}
+	ctx.Canvas.DrawRoundedRect(r, radius, st)
+	if w := s.borderWidth; w > 0 {
+		// The stroke is centered on its path, so the path is inset by
+		// half the width to keep the border inside.
+		in := r.Inset(core.UniformInsets(w / 2))
+		ctx.Canvas.DrawRoundedRect(in, max(radius-w/2, 0), core.RectStyle{Stroke: s.border, StrokeGradient: s.borderGradient, StrokeWidth: w})
+	}
+	s.child.Paint(ctx)
+}
@@ -0,0 +1,49 @@
+package widgets
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/theme"
+)
+
+func TestSurfacePaint(t *testing.T) {
+	th := theme.Light()
+	r := core.R(10, 10, 100, 60)
+	red := core.RGB(255, 0, 0)
+	g := core.LinearGradient(core.Pt(0, 0), core.Pt(1, 0), core.Stop(0, red), core.Stop(1, core.White))
+	g.Relative = true
+	tests := []struct {
+		name string
+		s    *Surface
+		want func(cv *core.Recording)
+	}{
+		{"flat", NewSurface(&fixed{}), func(cv *core.Recording) {
+			cv.DrawRoundedRect(r, th.Radii.Medium, core.Filled(th.Colors.Surface))
+		}},
+		{"raised", NewSurface(&fixed{}).Elevation(theme.ElevationMenu).Radius(-1).Color(red), func(cv *core.Recording) {
+			core.DrawShadow(cv, r, 0, th.Elevation(theme.ElevationMenu)...)
+			cv.DrawRoundedRect(r, 0, core.Filled(red))
+		}},
+		{"bordered", NewSurface(&fixed{}).Radius(8).Gradient(g).Border(red, 2), func(cv *core.Recording) {
+			cv.DrawRoundedRect(r, 8, core.GradientFilled(g))
+			cv.DrawRoundedRect(core.R(11, 11, 98, 58), 7, core.RectStyle{Stroke: red, StrokeWidth: 2})
+		}},
+		{"gradient border", NewSurface(&fixed{}).Radius(1).BorderGradient(g, 4), func(cv *core.Recording) {
+			cv.DrawRoundedRect(r, 1, core.Filled(th.Colors.Surface))
+			cv.DrawRoundedRect(core.R(12, 12, 96, 56), 0, core.RectStyle{Stroke: g.Average(), StrokeGradient: g, StrokeWidth: 4})
+		}},
+	}
+	for _, tt := range tests {
+		ctx := layoutAt(tt.s, r)
+		if tt.s.child.Bounds() != r {
+			t.Errorf("%s: child at %v, want over the surface", tt.name, tt.s.child.Bounds())
+		}
+		var got, want core.Recording
+		tt.s.Paint(&core.PaintContext{Context: ctx, Canvas: &got})
+		tt.want(&want)
+		if !got.Equal(&want) {
+			t.Errorf("%s: painted %d operations unlike the %d wanted", tt.name, got.Len(), want.Len())
+		}
+	}
+}
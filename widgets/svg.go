@@ -0,0 +1,116 @@
+package widgets
+
+import (
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/internal/svg"
+	"github.com/gogpu/ui/theme"
+)
+
+// SVG displays a vector image parsed from an SVG document, drawn as paths
+// at whatever size it is given, so icons and illustrations stay sharp at
+// any scale.
+//
+// Shapes, paths, groups, transforms, <use>, opacity and inline styles are
+// supported; gradients are drawn in the color of their first stop, and
+// text, filters, masks and clipping are ignored. Paints of currentColor
+// take the color set with Color, by default the theme's OnSurface, so
+// monochrome icons follow the theme.
+//
+// Without an explicit Size an SVG takes the document's width and height,
+// scaled down to fit the constraints. The paths are scaled once per size
+// drawn and reused until the size changes.
+type SVG struct {
+	core.WidgetBase
+
+	doc   *svg.Document
+	color core.Color
+	fit   ImageFit
+	size  core.Size
+
+	scaled     []svg.Shape
+	scaledSize core.Size
+}
+
+// NewSVG returns an SVG showing the document in data.
+func NewSVG(data []byte) (*SVG, error) {
+	doc, err := svg.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	return &SVG{doc: doc}, nil
+}
+
+// Color sets the color that currentColor paints take.
+func (s *SVG) Color(c core.Color) *SVG {
+	s.color = c
+	return s
+}
+
+// Fit sets how the image fills its bounds. The default is ImageContain.
+func (s *SVG) Fit(fit ImageFit) *SVG {
+	s.fit = fit
+	return s
+}
+
+// Size sets the preferred size, which is otherwise the document's.
+func (s *SVG) Size(w, h float32) *SVG {
+	s.size = core.Sz(w, h)
+	return s
+}
+
+// Layout implements core.Widget.
+func (s *SVG) Layout(ctx *core.LayoutContext) core.Size {
+	if s.size != (core.Size{}) {
+		return ctx.Constraints.Constrain(s.size)
+	}
+	w, h := s.doc.Width, s.doc.Height
+	k := float32(1)
+	if c := ctx.Constraints; c.HasBoundedWidth() && w > c.MaxWidth {
+		k = c.MaxWidth / w
+	}
+	if c := ctx.Constraints; c.MaxHeight < h*k {
+		k = c.MaxHeight / h
+	}
+	return ctx.Constraints.Constrain(core.Sz(w*k, h*k))
+}
+
+// Paint implements core.Widget.
+func (s *SVG) Paint(ctx *core.PaintContext) {
+	b := s.Bounds()
+	vb := s.doc.ViewBox
+	if b.Width <= 0 || b.Height <= 0 {
+		return
+	}
+	r := fitRect(b, vb.Width, vb.Height, s.fit)
+	if r.Size() != s.scaledSize || s.scaled == nil {
+		s.scaledSize = r.Size()
+		s.scaled = s.doc.Scaled(r.Size())
+	}
+	current := s.color
+	if current == (core.Color{}) {
+		current = theme.From(ctx.Context).Colors.OnSurface
+	}
+	resolve := func(c core.Color, isCurrent bool) core.Color {
+		if isCurrent {
+			return current.WithAlpha(current.A * c.A)
+		}
+		return c
+	}
+	cv := ctx.Canvas
+	cv.Save()
+	if s.fit == ImageCover {
+		cv.Clip(b)
+	}
+	cv.Translate(r.X, r.Y)
+	for _, sh := range s.scaled {
+		cv.DrawPath(sh.Path, core.PathStyle{
+			Fill:        resolve(sh.Fill, sh.FillCurrent),
+			Stroke:      resolve(sh.Stroke, sh.StrokeCurrent),
+			StrokeWidth: sh.StrokeWidth,
+			FillRule:    sh.FillRule,
+			LineCap:     sh.Cap,
+			LineJoin:    sh.Join,
+		})
+	}
+	cv.Restore()
+}
@@ -0,0 +1,105 @@
+package widgets
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/theme"
+)
+
+const testSVG = `<svg viewBox="0 0 20 10" width="40" height="20">
+	<rect width="20" height="10" fill="currentColor" fill-opacity="0.5"/>
+	<path d="M0 0 L20 10" fill="none" stroke="#ff0000" stroke-width="2"/>
+</svg>`
+
+// pathCanvas records the styles paths are drawn with.
+type pathCanvas struct {
+	opCanvas
+	paths  []*core.Path
+	styles []core.PathStyle
+}
+
+func (c *pathCanvas) DrawPath(p *core.Path, st core.PathStyle) {
+	c.paths = append(c.paths, p)
+	c.styles = append(c.styles, st)
+	c.Recording.DrawPath(p, st)
+}
+
+func newSVG(t *testing.T) *SVG {
+	t.Helper()
+	s, err := NewSVG([]byte(testSVG))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestSVGSize(t *testing.T) {
+	if _, err := NewSVG([]byte("<html/>")); err == nil {
+		t.Error("NewSVG of a document that is not SVG succeeded")
+	}
+	tests := []struct {
+		name string
+		s    *SVG
+		c    core.Constraints
+		want core.Size
+	}{
+		{"document size", newSVG(t), core.Unbounded(), core.Sz(40, 20)},
+		{"narrow", newSVG(t), core.Loose(core.Sz(20, 100)), core.Sz(20, 10)},
+		{"short", newSVG(t), core.Loose(core.Sz(100, 5)), core.Sz(10, 5)},
+		{"sized", newSVG(t).Size(16, 16), core.Unbounded(), core.Sz(16, 16)},
+	}
+	for _, tt := range tests {
+		lc := &core.LayoutContext{Context: core.NewContext()}
+		if got := lc.Measure(tt.s, tt.c); got != tt.want {
+			t.Errorf("%s: size %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSVGPaint(t *testing.T) {
+	s := newSVG(t)
+	ctx := layoutAt(s, core.R(10, 10, 80, 80))
+	paint := func() *pathCanvas {
+		cv := &pathCanvas{}
+		s.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+		return cv
+	}
+	cv := paint()
+	if !equalStrings(cv.ops, []string{"translate 10 30"}) || len(cv.paths) != 2 {
+		t.Fatalf("drew %q with %d paths, want the image centered", cv.ops, len(cv.paths))
+	}
+	if got := cv.paths[0].Bounds(); got != core.R(0, 0, 80, 40) {
+		t.Errorf("the image is %v, want it scaled to fit", got)
+	}
+	onSurface := theme.From(ctx).Colors.OnSurface
+	if got := cv.styles[0].Fill; got != onSurface.WithAlpha(onSurface.A*0.5) {
+		t.Errorf("currentColor filled %v, want the theme's OnSurface at half alpha", got)
+	}
+	if st := cv.styles[1]; st.Stroke != core.Hex(0xff0000) || st.StrokeWidth != 8 || st.Fill.A != 0 {
+		t.Errorf("the line is drawn %+v", st)
+	}
+	if again := paint(); again.paths[0] != cv.paths[0] {
+		t.Error("the scaled paths were not reused")
+	}
+
+	s.Color(core.Hex(0x00ff00))
+	if got := paint().styles[0].Fill; got != core.Hex(0x00ff00).WithAlpha(0.5) {
+		t.Errorf("currentColor filled %v, want the set color", got)
+	}
+
+	s.Fit(ImageCover)
+	cv = paint()
+	if !equalStrings(cv.ops, []string{"clip 10 10 80 80", "translate -30 10"}) || cv.paths[0].Bounds() != core.R(0, 0, 160, 80) {
+		t.Errorf("covering drew %q at %v", cv.ops, cv.paths[0].Bounds())
+	}
+	s.Fit(ImageFill)
+	if got := paint().paths[0].Bounds(); got != core.R(0, 0, 80, 80) {
+		t.Errorf("filling drew %v", got)
+	}
+
+	ctx.LayoutRoot(s, core.R(0, 0, 0, 10))
+	if cv := paint(); len(cv.paths) != 0 {
+		t.Errorf("an empty SVG drew %d paths", len(cv.paths))
+	}
+}
@@ -0,0 +1,195 @@
+package widgets
+
+import (
+	"time"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/state"
+	"github.com/gogpu/ui/theme"
+)
+
+// switchSlide is how long the thumb takes to move across the track.
+const switchSlide = 150 * time.Millisecond
+
+// Switch is a labeled on/off toggle drawn as a thumb on a track. It is
+// toggled by clicking it or pressing Space while it is focused.
+type Switch struct {
+	core.WidgetBase
+	core.FocusState
+
+	row      toggleRow
+	press    togglePress
+	on       bool
+	onChange func(bool)
+
+	sig    *state.Signal[bool]
+	cancel func()
+
+	// pos is the thumb position from 0 (off) to 1 (on), animated from
+	// from after a toggle at toggledAt.
+	pos       float32
+	from      float32
+	toggledAt time.Time
+}
+
+// NewSwitch returns a Switch that is off, with the given label, which may
+// be empty.
+func NewSwitch(label string) *Switch {
+	return &Switch{row: toggleRow{label: label}}
+}
+
+// Bind keeps the state in sync with sig in both directions.
+func (s *Switch) Bind(sig *state.Signal[bool]) *Switch {
+	s.Release()
+	s.sig = sig
+	return s
+}
+
+// Release stops following the bound signal, for when the switch leaves
+// the tree. Laid out again, the switch follows the signal from its value
+// then.
+func (s *Switch) Release() {
+	if s.cancel != nil {
+		s.cancel()
+		s.cancel = nil
+	}
+}
+
+// OnChange registers fn to be called when the user toggles the switch.
+func (s *Switch) OnChange(fn func(bool)) *Switch {
+	s.onChange = fn
+	return s
+}
+
+// Label returns the label.
+func (s *Switch) Label() string {
+	return s.row.label
+}
+
+// SetLabel replaces the label.
+func (s *Switch) SetLabel(label string) {
+	s.row.label = label
+}
+
+// On reports whether the switch is on.
+func (s *Switch) On() bool {
+	return s.on
+}
+
+// SetOn sets the state without calling OnChange.
+func (s *Switch) SetOn(on bool) {
+	if on == s.on {
+		return
+	}
+	s.on = on
+	s.from, s.toggledAt = s.pos, time.Time{}
+	if s.sig != nil {
+		s.sig.Set(on)
+	}
+}
+
+func (s *Switch) toggle(ctx *core.Context) {
+	s.SetOn(!s.on)
+	ctx.MarkNeedsLayout(s)
+	if s.onChange != nil {
+		s.onChange(s.on)
+	}
+}
+
+// switchMetrics returns the track size and the thumb diameters when off
+// and on.
+func switchMetrics(d theme.Design) (track core.Size, off, on float32) {
+	switch d {
+	case theme.DesignFluent:
+		return core.Sz(40, 20), 12, 12
+	case theme.DesignCupertino:
+		return core.Sz(51, 31), 27, 27
+	}
+	return core.Sz(52, 32), 16, 24
+}
+
+// Layout implements core.Widget.
+func (s *Switch) Layout(ctx *core.LayoutContext) core.Size {
+	if s.sig != nil {
+		if s.cancel == nil {
+			c := ctx.Context
+			s.cancel = s.sig.Subscribe(func(bool) { c.Post(func() { c.MarkNeedsLayout(s) }) })
+		}
+		s.SetOn(s.sig.Get())
+	}
+	target := float32(0)
+	if s.on {
+		target = 1
+	}
+	if s.pos != target {
+		if s.toggledAt.IsZero() {
+			s.toggledAt = ctx.Now()
+		}
+		t := float32(ctx.Now().Sub(s.toggledAt)) / float32(switchSlide)
+		if t >= 1 || ctx.ReducedMotion() {
+			s.pos = target
+		} else {
+			s.pos = s.from + (target-s.from)*easeOut(max(t, 0))
+			ctx.MarkNeedsLayout(s)
+		}
+	}
+	track, _, _ := switchMetrics(theme.From(ctx.Context).Design)
+	return ctx.Constraints.Constrain(s.row.measure(ctx, track))
+}
+
+// SetBounds implements core.Widget.
+func (s *Switch) SetBounds(r core.Rect) {
+	s.WidgetBase.SetBounds(r)
+	s.row.arrange(r)
+}
+
+// Paint implements core.Widget.
+func (s *Switch) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	b := s.row.control
+	r := b.Height / 2
+	_, offD, onD := switchMetrics(th.Design)
+	d := offD + (onD-offD)*s.pos
+	if s.press.pressed && th.Design == theme.DesignMaterial {
+		d = 28
+	}
+	inset := (b.Height - d) / 2
+	travel := b.Width - 2*inset - d
+	thumb := core.R(b.X+inset+travel*s.pos, b.Y+inset, d, d)
+
+	onColor, offColor := th.Colors.Primary, th.Colors.SurfaceVariant
+	thumbOn, thumbOff := th.Colors.OnPrimary, th.Colors.Outline
+	switch th.Design {
+	case theme.DesignFluent:
+		offColor = th.Colors.Surface
+		thumbOff = th.Colors.OnSurfaceVariant
+	case theme.DesignCupertino:
+		thumbOn, thumbOff = core.White, core.White
+	}
+
+	if th.Design == theme.DesignMaterial {
+		paintToggleState(ctx, thumb, d/2, s.press.hover, s.IsFocused())
+	} else {
+		paintToggleState(ctx, b, r, s.press.hover, s.IsFocused())
+	}
+	cv.DrawRoundedRect(b, r, core.Filled(offColor.Lerp(onColor, s.pos)))
+	if th.Design != theme.DesignCupertino && s.pos < 1 {
+		width := float32(2)
+		if th.Design == theme.DesignFluent {
+			width = 1
+		}
+		cv.DrawRoundedRect(b.Inset(core.UniformInsets(width/2)), r-width/2, core.Stroked(th.Colors.Outline.WithAlpha(1-s.pos), width))
+	}
+	style := core.Filled(thumbOff.Lerp(thumbOn, s.pos))
+	if th.Design == theme.DesignCupertino {
+		style.Stroke, style.StrokeWidth = core.Black.WithAlpha(0.12), 0.5
+	}
+	cv.DrawRoundedRect(thumb, d/2, style)
+	s.row.paintLabel(ctx)
+}
+
+// HandleEvent implements core.Widget.
+func (s *Switch) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	return s.press.handle(ctx, s, s.IsFocused(), ev, func() { s.toggle(ctx) })
+}
@@ -0,0 +1,88 @@
+package widgets
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/state"
+	"github.com/gogpu/ui/theme"
+)
+
+func TestSwitchSlides(t *testing.T) {
+	ctx := core.NewContext()
+	t0 := time.Now()
+	ctx.SetNow(t0)
+	s := NewSwitch("Wi-Fi")
+	var got []bool
+	s.OnChange(func(on bool) { got = append(got, on) })
+	bounds := core.R(0, 0, 200, 40)
+	ctx.LayoutRoot(s, bounds)
+	click(ctx, s, s.row.control.Center())
+	if !s.On() || len(got) != 1 || !got[0] {
+		t.Fatalf("On() = %v with changes %v after a click", s.On(), got)
+	}
+	ctx.LayoutRoot(s, bounds)
+	ctx.SetNow(t0.Add(switchSlide / 2))
+	ctx.LayoutRoot(s, bounds)
+	if s.pos <= 0.5 || s.pos >= 1 {
+		t.Errorf("thumb at %v halfway through, want eased past the middle", s.pos)
+	}
+	ctx.SetNow(t0.Add(switchSlide))
+	ctx.LayoutRoot(s, bounds)
+	if s.pos != 1 {
+		t.Errorf("thumb at %v after sliding", s.pos)
+	}
+
+	ctx.SetReducedMotion(true)
+	ctx.RequestFocus(s)
+	s.HandleEvent(ctx, press(event.KeySpace, 0))
+	ctx.LayoutRoot(s, bounds)
+	if s.On() || s.pos != 0 {
+		t.Errorf("On() = %v with the thumb at %v, want off at once", s.On(), s.pos)
+	}
+}
+
+func TestSwitchBind(t *testing.T) {
+	sig := state.New(true)
+	s := NewSwitch("").Bind(sig)
+	ctx := core.NewContext()
+	ctx.SetReducedMotion(true)
+	ctx.LayoutRoot(s, core.R(0, 0, 100, 40))
+	if !s.On() || s.pos != 1 {
+		t.Fatalf("On() = %v, want the signal's value", s.On())
+	}
+	click(ctx, s, s.row.control.Center())
+	if sig.Get() {
+		t.Error("toggling did not update the signal")
+	}
+	sig.Set(true)
+	ctx.RunPosted()
+	ctx.LayoutRoot(s, core.R(0, 0, 100, 40))
+	if !s.On() {
+		t.Error("setting the signal did not turn the switch on")
+	}
+}
+
+func TestSwitchMetrics(t *testing.T) {
+	tests := []struct {
+		design theme.Design
+		track  core.Size
+	}{
+		{theme.DesignMaterial, core.Sz(52, 32)},
+		{theme.DesignFluent, core.Sz(40, 20)},
+		{theme.DesignCupertino, core.Sz(51, 31)},
+	}
+	for _, tt := range tests {
+		th := *theme.Light()
+		th.Design = tt.design
+		ctx := core.NewContext()
+		theme.Set(ctx, &th)
+		s := NewSwitch("")
+		ctx.LayoutRoot(s, core.R(0, 0, 100, 40))
+		if s.row.control.Size() != tt.track {
+			t.Errorf("design %d: track %v, want %v", tt.design, s.row.control.Size(), tt.track)
+		}
+	}
+}
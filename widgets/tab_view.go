@@ -0,0 +1,594 @@
+package widgets
+
+import (
+	"slices"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/theme"
+)
+
+// Tab is a page of a TabView.
+type Tab struct {
+	// Title is the label shown in the tab strip.
+	Title string
+
+	// Content is the widget shown while the tab is selected.
+	Content core.Widget
+
+	// Closable shows a close button on the tab.
+	Closable bool
+}
+
+const (
+	defaultTabStripHeight float32 = 32
+	minTabWidth           float32 = 72
+	maxTabWidth           float32 = 220
+	tabPadding            float32 = 12
+	tabCloseSize          float32 = 16
+	tabArrowWidth         float32 = 20
+	tabDetachDistance     float32 = 40
+)
+
+// TabView shows one of several pages selected through a tab strip.
+//
+// Tabs can be closed with their close button or a middle click, reordered
+// by dragging, and torn off by dragging them away from the strip, which
+// calls the OnDetach handler so the application can move the tab into a new
+// window. When tabs do not fit, the strip scrolls with the mouse wheel or
+// the arrow buttons, and an overflow button lists every tab through
+// OnOverflow.
+//
+// Ctrl+Tab and Ctrl+Shift+Tab (or Ctrl+PageDown/PageUp) cycle tabs while
+// focus is inside the view. In right-to-left text the tabs run from the
+// right, with the scroll and overflow buttons on the left.
+type TabView struct {
+	core.WidgetBase
+
+	tabs        []*Tab
+	selected    int
+	stripHeight float32
+
+	strip     core.Rect
+	content   core.Rect
+	tabRects  []core.Rect
+	stripW    float32
+	scrollX   float32
+	overflow  bool
+	leftBtn   core.Rect
+	rightBtn  core.Rect
+	listBtn   core.Rect
+	hoverTab  int
+	hoverX    int
+	press     int
+	pressPos  core.Point
+	dragging  bool
+	detaching bool
+	dragPos   core.Point
+	dropIndex int
+	dir       core.Direction
+
+	onSelect   func(index int)
+	onClose    func(index int) bool
+	onReorder  func(from, to int)
+	onDetach   func(tab *Tab, pos core.Point) bool
+	onOverflow func(anchor core.Rect)
+}
+
+// NewTabView returns a tab view with the given tabs, selecting the first.
+func NewTabView(tabs ...*Tab) *TabView {
+	return &TabView{
+		tabs:        tabs,
+		stripHeight: defaultTabStripHeight,
+		hoverTab:    -1,
+		hoverX:      -1,
+		press:       -1,
+	}
+}
+
+// StripHeight sets the height of the tab strip.
+func (v *TabView) StripHeight(h float32) *TabView {
+	v.stripHeight = max(0, h)
+	return v
+}
+
+// OnSelect registers fn to be called when the selected tab changes.
+func (v *TabView) OnSelect(fn func(index int)) *TabView {
+	v.onSelect = fn
+	return v
+}
+
+// OnClose registers fn to be called before a tab is closed by the user.
+// Returning false keeps the tab open, for example to prompt about unsaved
+// changes.
+func (v *TabView) OnClose(fn func(index int) bool) *TabView {
+	v.onClose = fn
+	return v
+}
+
+// OnReorder registers fn to be called after the user drags a tab from one
+// position to another.
+func (v *TabView) OnReorder(fn func(from, to int)) *TabView {
+	v.onReorder = fn
+	return v
+}
+
+// OnDetach registers fn to be called when a tab is dragged out of the
+// strip and released. pos is the release position in window coordinates.
+// If fn returns true the tab is removed from this view; fn typically opens
+// it in a new window.
+func (v *TabView) OnDetach(fn func(tab *Tab, pos core.Point) bool) *TabView {
+	v.onDetach = fn
+	return v
+}
+
+// OnOverflow registers fn to be called when the overflow button is
+// pressed. anchor is the button's rectangle, for positioning a menu that
+// lists every tab.
+func (v *TabView) OnOverflow(fn func(anchor core.Rect)) *TabView {
+	v.onOverflow = fn
+	return v
+}
+
+// Tabs returns the tabs in display order.
+func (v *TabView) Tabs() []*Tab {
+	return v.tabs
+}
+
+// Selected returns the index of the selected tab, or -1 if there are none.
+func (v *TabView) Selected() int {
+	if len(v.tabs) == 0 {
+		return -1
+	}
+	return v.selected
+}
+
+// Select selects the tab at index and scrolls it into view.
+func (v *TabView) Select(index int) {
+	if index < 0 || index >= len(v.tabs) {
+		return
+	}
+	changed := index != v.selected
+	v.selected = index
+	v.scrollToTab(index)
+	if changed && v.onSelect != nil {
+		v.onSelect(index)
+	}
+}
+
+// AddTab appends tab and selects it.
+func (v *TabView) AddTab(tab *Tab) {
+	v.InsertTab(len(v.tabs), tab)
+}
+
+// InsertTab inserts tab at index and selects it.
+func (v *TabView) InsertTab(index int, tab *Tab) {
+	index = min(max(index, 0), len(v.tabs))
+	v.tabs = slices.Insert(v.tabs, index, tab)
+	v.selected = index
+	if v.onSelect != nil {
+		v.onSelect(index)
+	}
+}
+
+// RemoveTab removes the tab at index without consulting OnClose.
+func (v *TabView) RemoveTab(index int) *Tab {
+	if index < 0 || index >= len(v.tabs) {
+		return nil
+	}
+	tab := v.tabs[index]
+	v.tabs = slices.Delete(v.tabs, index, index+1)
+	if v.selected > index || v.selected >= len(v.tabs) {
+		v.selected = max(0, v.selected-1)
+	}
+	if v.onSelect != nil && len(v.tabs) > 0 {
+		v.onSelect(v.selected)
+	}
+	return tab
+}
+
+// CloseTab closes the tab at index if OnClose allows it.
+func (v *TabView) CloseTab(index int) bool {
+	if index < 0 || index >= len(v.tabs) {
+		return false
+	}
+	if v.onClose != nil && !v.onClose(index) {
+		return false
+	}
+	v.RemoveTab(index)
+	return true
+}
+
+// MoveTab moves the tab at from to position to.
+func (v *TabView) MoveTab(from, to int) {
+	if from < 0 || from >= len(v.tabs) {
+		return
+	}
+	to = min(max(to, 0), len(v.tabs)-1)
+	if from == to {
+		return
+	}
+	selected := v.tabs[v.selected]
+	tab := v.tabs[from]
+	v.tabs = slices.Delete(v.tabs, from, from+1)
+	v.tabs = slices.Insert(v.tabs, to, tab)
+	v.selected = slices.Index(v.tabs, selected)
+	if v.onReorder != nil {
+		v.onReorder(from, to)
+	}
+}
+
+func (v *TabView) tabWidth(ctx *core.Context, tab *Tab, style core.TextStyle) float32 {
+	w := ctx.MeasureText(tab.Title, style).Width + 2*tabPadding
+	if tab.Closable {
+		w += tabCloseSize + 4
+	}
+	return core.Clamp(w, minTabWidth, maxTabWidth)
+}
+
+// Layout implements core.Widget.
+func (v *TabView) Layout(ctx *core.LayoutContext) core.Size {
+	v.dir = ctx.Direction()
+	th := theme.From(ctx.Context)
+	style := th.Typography.Label
+	widths := make([]float32, len(v.tabs))
+	v.stripW = 0
+	for i, tab := range v.tabs {
+		widths[i] = v.tabWidth(ctx.Context, tab, style)
+		v.stripW += widths[i]
+	}
+	v.tabRects = v.tabRects[:0]
+	var x float32
+	for _, w := range widths {
+		v.tabRects = append(v.tabRects, core.R(x, 0, w, v.stripHeight))
+		x += w
+	}
+
+	c := ctx.Constraints
+	contentC := c.Deflate(core.Insets{Top: v.stripHeight})
+	var contentSize core.Size
+	v.SetChildren()
+	if tab := v.selectedTab(); tab != nil && tab.Content != nil {
+		contentSize = ctx.Measure(tab.Content, contentC)
+		v.SetChildren(tab.Content)
+	}
+	size := core.Size{Width: max(v.stripW, contentSize.Width), Height: v.stripHeight + contentSize.Height}
+	if c.HasBoundedWidth() {
+		size.Width = c.MaxWidth
+	}
+	return c.Constrain(size)
+}
+
+func (v *TabView) selectedTab() *Tab {
+	if v.selected >= 0 && v.selected < len(v.tabs) {
+		return v.tabs[v.selected]
+	}
+	return nil
+}
+
+// SetBounds implements core.Widget.
+func (v *TabView) SetBounds(r core.Rect) {
+	v.WidgetBase.SetBounds(r)
+	v.strip = core.R(r.X, r.Y, r.Width, v.stripHeight)
+	v.content = core.R(r.X, r.Y+v.stripHeight, r.Width, max(0, r.Height-v.stripHeight))
+	v.overflow = v.stripW > r.Width
+	avail := r.Width
+	if v.overflow {
+		avail -= 3 * tabArrowWidth
+		right := v.strip.Right()
+		v.listBtn = v.dir.Mirror(core.R(right-tabArrowWidth, r.Y, tabArrowWidth, v.stripHeight), v.strip)
+		v.rightBtn = v.dir.Mirror(core.R(right-2*tabArrowWidth, r.Y, tabArrowWidth, v.stripHeight), v.strip)
+		v.leftBtn = v.dir.Mirror(core.R(right-3*tabArrowWidth, r.Y, tabArrowWidth, v.stripHeight), v.strip)
+	}
+	v.scrollX = core.Clamp(v.scrollX, 0, max(0, v.stripW-avail))
+	if tab := v.selectedTab(); tab != nil && tab.Content != nil {
+		tab.Content.SetBounds(v.content)
+	}
+}
+
+func (v *TabView) tabsArea() core.Rect {
+	a := v.strip
+	if v.overflow {
+		a.Width = max(0, a.Width-3*tabArrowWidth)
+	}
+	return v.dir.Mirror(a, v.strip)
+}
+
+// tabRect returns the rectangle of tab i in window coordinates.
+func (v *TabView) tabRect(i int) core.Rect {
+	return v.dir.Mirror(v.tabRects[i].Translate(core.Pt(v.strip.X-v.scrollX, v.strip.Y)), v.strip)
+}
+
+func (v *TabView) closeRect(i int) core.Rect {
+	return v.closeIn(v.tabRect(i))
+}
+
+// closeIn returns the close button of a tab at r, near its end edge.
+func (v *TabView) closeIn(r core.Rect) core.Rect {
+	cr := core.R(r.Right()-tabPadding/2-tabCloseSize, r.Y+(r.Height-tabCloseSize)/2, tabCloseSize, tabCloseSize)
+	return v.dir.Mirror(cr, r)
+}
+
+func (v *TabView) scrollToTab(i int) {
+	if i < 0 || i >= len(v.tabRects) {
+		return
+	}
+	t := v.tabRects[i]
+	avail := v.tabsArea().Width
+	if t.X < v.scrollX {
+		v.scrollX = t.X
+	} else if t.Right() > v.scrollX+avail {
+		v.scrollX = t.Right() - avail
+	}
+}
+
+func (v *TabView) tabAt(p core.Point) int {
+	if !v.tabsArea().Contains(p) {
+		return -1
+	}
+	for i := range v.tabRects {
+		if v.tabRect(i).Contains(p) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Paint implements core.Widget.
+func (v *TabView) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	cv.DrawRect(v.strip, core.Filled(th.Colors.SurfaceVariant))
+
+	area := v.tabsArea()
+	cv.Save()
+	cv.Clip(area)
+	for i, tab := range v.tabs {
+		if v.dragging && i == v.press {
+			continue
+		}
+		v.paintTab(ctx, i, tab, v.tabRect(i))
+	}
+	if v.dragging && v.press >= 0 {
+		x := v.dropIndicatorX()
+		cv.DrawRect(core.R(x-1, v.strip.Y+4, 2, v.strip.Height-8), core.Filled(th.Colors.Primary))
+	}
+	cv.Restore()
+
+	if v.overflow {
+		label := theme.TextStyle(th.Typography.Label, th.Colors.OnSurfaceVariant)
+		// The arrows point toward the start and the end they scroll to.
+		back, forward := "‹", "›"
+		if v.dir.IsRTL() {
+			back, forward = forward, back
+		}
+		for _, b := range []struct {
+			r core.Rect
+			s string
+		}{{v.leftBtn, back}, {v.rightBtn, forward}, {v.listBtn, "⌄"}} {
+			drawCellText(ctx, b.r, b.s, label, CellAlignCenter)
+		}
+	}
+	cv.DrawRect(core.R(v.strip.X, v.strip.Bottom()-1, v.strip.Width, 1), core.Filled(th.Colors.Outline.WithAlpha(0.5)))
+
+	if tab := v.selectedTab(); tab != nil && tab.Content != nil {
+		cv.Save()
+		cv.Clip(v.content)
+		tab.Content.Paint(ctx)
+		cv.Restore()
+	}
+
+	if v.dragging && v.press >= 0 && v.press < len(v.tabs) {
+		// The dragged tab follows the pointer, on top of everything.
+		r := v.tabRect(v.press)
+		r.X = v.dragPos.X - r.Width/2
+		if v.detaching {
+			r.Y = v.dragPos.Y - r.Height/2
+		}
+		cv.DrawRect(r, core.Filled(th.Colors.Surface.WithAlpha(0.9)))
+		v.paintTab(ctx, v.press, v.tabs[v.press], r)
+	}
+}
+
+func (v *TabView) paintTab(ctx *core.PaintContext, i int, tab *Tab, r core.Rect) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	selected := i == v.selected
+	switch {
+	case selected:
+		cv.DrawRect(r, core.Filled(th.Colors.Surface))
+		cv.DrawRect(core.R(r.X, r.Bottom()-2, r.Width, 2), core.Filled(th.Colors.Primary))
+	case i == v.hoverTab:
+		cv.DrawRect(r, core.Filled(th.Colors.OnSurface.WithAlpha(0.05)))
+	}
+	color := th.Colors.OnSurfaceVariant
+	if selected {
+		color = th.Colors.OnSurface
+	}
+	textArea := r
+	if tab.Closable {
+		textArea.Width -= tabCloseSize + 4
+		textArea = v.dir.Mirror(textArea, r)
+	}
+	drawCellText(ctx, textArea.Inset(core.SymmetricInsets(tabPadding-cellPadding, 0)), tab.Title, theme.TextStyle(th.Typography.Label, color), CellAlignStart)
+	if tab.Closable && (selected || i == v.hoverTab) {
+		cr := v.closeIn(r)
+		if i == v.hoverX {
+			cv.DrawRoundedRect(cr, 3, core.Filled(th.Colors.OnSurface.WithAlpha(0.1)))
+		}
+		c := cr.Center()
+		const s = 3.5
+		x := core.NewPath().
+			MoveTo(core.Pt(c.X-s, c.Y-s)).LineTo(core.Pt(c.X+s, c.Y+s)).
+			MoveTo(core.Pt(c.X+s, c.Y-s)).LineTo(core.Pt(c.X-s, c.Y+s))
+		cv.DrawPath(x, core.PathStyle{Stroke: color, StrokeWidth: 1.5, LineCap: core.CapRound})
+	}
+}
+
+func (v *TabView) dropIndicatorX() float32 {
+	r := v.tabRect(v.dropIndex)
+	if (v.dropIndex > v.press) != v.dir.IsRTL() {
+		return r.Right()
+	}
+	return r.X
+}
+
+func (v *TabView) dropIndexAt(x float32) int {
+	for i := range v.tabRects {
+		r := v.tabRect(i)
+		if (x < r.X+r.Width/2) != v.dir.IsRTL() {
+			if i > v.press {
+				return i - 1
+			}
+			return i
+		}
+	}
+	return len(v.tabs) - 1
+}
+
+// HandleEvent implements core.Widget.
+func (v *TabView) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	switch e := ev.(type) {
+	case event.MouseEvent:
+		return v.handleMouse(ctx, e)
+	case event.ScrollEvent:
+		if v.overflow && v.strip.Contains(e.Position) {
+			d := e.Delta.X
+			if d == 0 {
+				d = e.Delta.Y
+			}
+			v.scrollX = core.Clamp(v.scrollX+d, 0, max(0, v.stripW-v.tabsArea().Width))
+			ctx.Repaint(v)
+			return core.Handled
+		}
+	case event.KeyEvent:
+		if e.Type != event.KeyPress || !e.Modifiers.Has(event.ModCtrl) || len(v.tabs) == 0 {
+			return core.Ignored
+		}
+		n := len(v.tabs)
+		switch {
+		case e.Key == event.KeyTab && e.Modifiers.Has(event.ModShift), e.Key == event.KeyPageUp:
+			v.Select((v.selected + n - 1) % n)
+		case e.Key == event.KeyTab, e.Key == event.KeyPageDown:
+			v.Select((v.selected + 1) % n)
+		default:
+			return core.Ignored
+		}
+		ctx.MarkNeedsLayout(v)
+		return core.Handled
+	}
+	return core.Ignored
+}
+
+func (v *TabView) handleMouse(ctx *core.Context, e event.MouseEvent) core.EventResult {
+	switch e.Type {
+	case event.MouseMove:
+		if v.press >= 0 {
+			v.dragMove(ctx, e.Position)
+			return core.Handled
+		}
+		hover, hoverX := v.tabAt(e.Position), -1
+		if hover >= 0 && v.tabs[hover].Closable && v.closeRect(hover).Contains(e.Position) {
+			hoverX = hover
+		}
+		if hover != v.hoverTab || hoverX != v.hoverX {
+			v.hoverTab, v.hoverX = hover, hoverX
+			ctx.Repaint(v)
+		}
+	case event.MouseLeave:
+		if v.hoverTab >= 0 {
+			v.hoverTab, v.hoverX = -1, -1
+			ctx.Repaint(v)
+		}
+	case event.MouseDown:
+		return v.pressStrip(ctx, e)
+	case event.MouseUp:
+		if v.press < 0 {
+			return core.Ignored
+		}
+		v.release(ctx, e)
+		return core.Handled
+	}
+	return core.Ignored
+}
+
+func (v *TabView) pressStrip(ctx *core.Context, e event.MouseEvent) core.EventResult {
+	if !v.strip.Contains(e.Position) {
+		return core.Ignored
+	}
+	if v.overflow {
+		step := v.tabsArea().Width / 2
+		switch {
+		case v.leftBtn.Contains(e.Position):
+			v.scrollX = max(0, v.scrollX-step)
+			ctx.Repaint(v)
+			return core.Handled
+		case v.rightBtn.Contains(e.Position):
+			v.scrollX = min(max(0, v.stripW-v.tabsArea().Width), v.scrollX+step)
+			ctx.Repaint(v)
+			return core.Handled
+		case v.listBtn.Contains(e.Position):
+			if v.onOverflow != nil {
+				v.onOverflow(v.listBtn)
+			}
+			return core.Handled
+		}
+	}
+	i := v.tabAt(e.Position)
+	if i < 0 {
+		return core.Handled
+	}
+	if e.Button == event.ButtonMiddle && v.tabs[i].Closable {
+		v.CloseTab(i)
+		ctx.MarkNeedsLayout(v)
+		return core.Handled
+	}
+	if e.Button == event.ButtonRight {
+		v.Select(i)
+		ctx.MarkNeedsLayout(v)
+		return core.Ignored // Let a context menu see the press.
+	}
+	if e.Button != event.ButtonLeft {
+		return core.Handled
+	}
+	if v.tabs[i].Closable && v.closeRect(i).Contains(e.Position) {
+		v.CloseTab(i)
+		v.hoverTab, v.hoverX = -1, -1
+		ctx.MarkNeedsLayout(v)
+		return core.Handled
+	}
+	v.Select(i)
+	v.press, v.pressPos, v.dropIndex = i, e.Position, i
+	ctx.CapturePointer(v)
+	ctx.MarkNeedsLayout(v)
+	return core.Handled
+}
+
+func (v *TabView) dragMove(ctx *core.Context, p core.Point) {
+	if !v.dragging && abs32(p.X-v.pressPos.X) < reorderThreshold && abs32(p.Y-v.pressPos.Y) < reorderThreshold {
+		return
+	}
+	v.dragging = true
+	v.dragPos = p
+	v.detaching = v.onDetach != nil &&
+		(p.Y < v.strip.Y-tabDetachDistance || p.Y > v.strip.Bottom()+tabDetachDistance)
+	if !v.detaching {
+		v.dropIndex = v.dropIndexAt(p.X)
+	}
+	ctx.Repaint(v)
+}
+
+func (v *TabView) release(ctx *core.Context, e event.MouseEvent) {
+	from := v.press
+	switch {
+	case v.dragging && v.detaching:
+		if v.onDetach(v.tabs[from], e.Position) {
+			v.RemoveTab(from)
+		}
+	case v.dragging:
+		v.MoveTab(from, v.dropIndex)
+	}
+	v.press, v.dragging, v.detaching = -1, false, false
+	ctx.ReleasePointer()
+	ctx.MarkNeedsLayout(v)
+}
@@ -0,0 +1,204 @@
+package widgets
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+)
+
+// tabs returns closable tabs titled by the letters of titles.
+func tabs(titles string) []*Tab {
+	var out []*Tab
+	for _, r := range titles {
+		out = append(out, &Tab{Title: string(r), Content: &fixed{height: 10}, Closable: true})
+	}
+	return out
+}
+
+// titles returns the titles of the tabs of v and the selected one.
+func titles(v *TabView) (string, string) {
+	var b strings.Builder
+	for _, t := range v.Tabs() {
+		b.WriteString(t.Title)
+	}
+	if v.Selected() < 0 {
+		return b.String(), ""
+	}
+	return b.String(), v.Tabs()[v.Selected()].Title
+}
+
+func TestTabViewEditing(t *testing.T) {
+	tests := []struct {
+		name           string
+		edit           func(v *TabView)
+		order, current string
+	}{
+		{"select", func(v *TabView) { v.Select(2) }, "abcd", "c"},
+		{"select out of range", func(v *TabView) { v.Select(9) }, "abcd", "a"},
+		{"add", func(v *TabView) { v.AddTab(&Tab{Title: "e"}) }, "abcde", "e"},
+		{"insert", func(v *TabView) { v.InsertTab(1, &Tab{Title: "e"}) }, "aebcd", "e"},
+		{"remove before the selection", func(v *TabView) { v.Select(2); v.RemoveTab(0) }, "bcd", "c"},
+		{"remove the last selected", func(v *TabView) { v.Select(3); v.RemoveTab(3) }, "abc", "c"},
+		{"move keeps the selection", func(v *TabView) { v.Select(1); v.MoveTab(0, 3) }, "bcda", "b"},
+		{"close", func(v *TabView) { v.CloseTab(1) }, "acd", "a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewTabView(tabs("abcd")...)
+			tt.edit(v)
+			if order, current := titles(v); order != tt.order || current != tt.current {
+				t.Errorf("tabs = %q selecting %q, want %q selecting %q", order, current, tt.order, tt.current)
+			}
+		})
+	}
+	if got := NewTabView().Selected(); got != -1 {
+		t.Errorf("Selected() = %d without tabs, want -1", got)
+	}
+}
+
+func TestTabViewOnClose(t *testing.T) {
+	v := NewTabView(tabs("ab")...).OnClose(func(i int) bool { return i != 0 })
+	if v.CloseTab(0) {
+		t.Error("CloseTab(0) = true when OnClose refused")
+	}
+	if !v.CloseTab(1) {
+		t.Error("CloseTab(1) = false")
+	}
+	if order, _ := titles(v); order != "a" {
+		t.Errorf("tabs = %q, want a", order)
+	}
+}
+
+func TestTabViewMouse(t *testing.T) {
+	// The tabs are the minimum width, minTabWidth each.
+	mid := func(i int) float32 { return float32(i)*minTabWidth + minTabWidth/2 }
+	tests := []struct {
+		name           string
+		steps          []event.MouseEvent
+		detach         bool
+		order, current string
+		detached       string
+	}{
+		{
+			name:  "click selects",
+			steps: []event.MouseEvent{{Type: event.MouseDown, Position: core.Pt(mid(2), 10), Button: event.ButtonLeft}, {Type: event.MouseUp, Position: core.Pt(mid(2), 10)}},
+			order: "abc", current: "c",
+		},
+		{
+			name:  "middle click closes",
+			steps: []event.MouseEvent{{Type: event.MouseDown, Position: core.Pt(mid(1), 10), Button: event.ButtonMiddle}},
+			order: "ac", current: "a",
+		},
+		{
+			name: "drag reorders",
+			steps: []event.MouseEvent{
+				{Type: event.MouseDown, Position: core.Pt(mid(0), 10), Button: event.ButtonLeft},
+				{Type: event.MouseMove, Position: core.Pt(mid(2)+10, 12)},
+				{Type: event.MouseUp, Position: core.Pt(mid(2)+10, 12)},
+			},
+			order: "bca", current: "a",
+		},
+		{
+			name: "drag away detaches",
+			steps: []event.MouseEvent{
+				{Type: event.MouseDown, Position: core.Pt(mid(1), 10), Button: event.ButtonLeft},
+				{Type: event.MouseMove, Position: core.Pt(mid(1), 200)},
+				{Type: event.MouseUp, Position: core.Pt(mid(1), 200)},
+			},
+			detach: true,
+			order:  "ac", current: "c", detached: "b",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewTabView(tabs("abc")...)
+			var detached string
+			if tt.detach {
+				v.OnDetach(func(tab *Tab, _ core.Point) bool {
+					detached = tab.Title
+					return true
+				})
+			}
+			ctx := layoutAt(v, core.R(0, 0, 400, 300))
+			for _, e := range tt.steps {
+				v.HandleEvent(ctx, e)
+				ctx.LayoutRoot(v, core.R(0, 0, 400, 300))
+			}
+			if order, current := titles(v); order != tt.order || current != tt.current {
+				t.Errorf("tabs = %q selecting %q, want %q selecting %q", order, current, tt.order, tt.current)
+			}
+			if detached != tt.detached {
+				t.Errorf("detached %q, want %q", detached, tt.detached)
+			}
+		})
+	}
+}
+
+func TestTabViewRightToLeft(t *testing.T) {
+	v := NewTabView(tabs("abc")...)
+	ctx := layoutRTL(v, core.R(0, 0, 400, 300))
+	if r := v.tabRect(0); r.Right() != 400 || v.tabRect(1).Right() != r.X {
+		t.Errorf("tabs at %v and %v, want from the right edge", r, v.tabRect(1))
+	}
+	if c, r := v.closeRect(0), v.tabRect(0); c.X >= r.Center().X {
+		t.Errorf("the close button at %v, want at the left of its tab at %v", c, r)
+	}
+
+	// Dragged left, the first tab moves to the end.
+	at := func(i int) core.Point { return core.Pt(400-float32(i)*minTabWidth-minTabWidth/2, 10) }
+	for _, e := range []event.MouseEvent{
+		{Type: event.MouseDown, Position: at(0), Button: event.ButtonLeft},
+		{Type: event.MouseMove, Position: at(2).Add(core.Pt(-10, 2))},
+		{Type: event.MouseUp, Position: at(2).Add(core.Pt(-10, 2))},
+	} {
+		v.HandleEvent(ctx, e)
+		ctx.LayoutRoot(v, core.R(0, 0, 400, 300))
+	}
+	if order, current := titles(v); order != "bca" || current != "a" {
+		t.Errorf("tabs = %q selecting %q, want bca selecting a", order, current)
+	}
+}
+
+func TestTabViewKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     event.Key
+		mods    event.Modifiers
+		current string
+	}{
+		{"next", event.KeyTab, event.ModCtrl, "b"},
+		{"previous wraps", event.KeyTab, event.ModCtrl | event.ModShift, "c"},
+		{"page down", event.KeyPageDown, event.ModCtrl, "b"},
+		{"without Ctrl", event.KeyTab, 0, "a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewTabView(tabs("abc")...)
+			ctx := layoutAt(v, core.R(0, 0, 400, 300))
+			v.HandleEvent(ctx, event.KeyEvent{Type: event.KeyPress, Key: tt.key, Modifiers: tt.mods})
+			if _, current := titles(v); current != tt.current {
+				t.Errorf("selected %q, want %q", current, tt.current)
+			}
+		})
+	}
+}
+
+func TestTabViewOverflow(t *testing.T) {
+	v := NewTabView(tabs("abcdefgh")...)
+	var anchors []core.Rect
+	v.OnOverflow(func(anchor core.Rect) { anchors = append(anchors, anchor) })
+	ctx := layoutAt(v, core.R(0, 0, 300, 200))
+	if !v.overflow {
+		t.Fatal("eight tabs fit in 300px")
+	}
+	v.Select(7)
+	if v.scrollX == 0 {
+		t.Error("selecting the last tab did not scroll it into view")
+	}
+	v.HandleEvent(ctx, event.MouseEvent{Type: event.MouseDown, Position: core.Pt(300-tabArrowWidth/2, 10), Button: event.ButtonLeft})
+	if len(anchors) != 1 || anchors[0] != v.listBtn {
+		t.Errorf("overflow anchors = %v, want [%v]", anchors, v.listBtn)
+	}
+}
@@ -0,0 +1,861 @@
+package widgets
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/internal/scroll"
+	"github.com/gogpu/ui/internal/vt"
+	"github.com/gogpu/ui/theme"
+)
+
+// Terminal metrics.
+const (
+	terminalCols             = 80
+	terminalRows             = 24
+	terminalPadding  float32 = 4
+	terminalReadSize         = 32 << 10
+)
+
+// DefaultTerminalPalette is the palette of the 16 basic ANSI colors: black,
+// red, green, yellow, blue, magenta, cyan and white, then their bright
+// variants.
+var DefaultTerminalPalette = [16]core.Color{
+	core.Hex(0x000000), core.Hex(0xCD3131), core.Hex(0x0DBC79), core.Hex(0xE5E510),
+	core.Hex(0x2472C8), core.Hex(0xBC3FBC), core.Hex(0x11A8CD), core.Hex(0xE5E5E5),
+	core.Hex(0x666666), core.Hex(0xF14C4C), core.Hex(0x23D18B), core.Hex(0xF5F543),
+	core.Hex(0x3B8EEA), core.Hex(0xD670D6), core.Hex(0x29B8DB), core.Hex(0xFFFFFF),
+}
+
+// Terminal displays a VT100/xterm-compatible terminal: a grid of colored
+// cells that a program draws on with escape sequences, with the lines that
+// scroll off the top kept in a scrollback. 256-color and 24-bit colors,
+// the alternate screen, bracketed paste and mouse reporting are supported.
+//
+// Attach connects the terminal to a program, typically through the master
+// side of a pty: what the program writes is read in the background and
+// displayed, and keys, pastes and replies to status requests are written
+// back. OnResize reports the size in cells, for the pty to follow.
+//
+// Dragging selects text, a double click a word and a triple click a line;
+// Ctrl+Shift+C copies the selection and Ctrl+Shift+V or Shift+Insert
+// pastes. The wheel and Shift+PageUp and PageDown scroll back, unless the
+// program reports the mouse; Shift bypasses mouse reporting.
+type Terminal struct {
+	core.WidgetBase
+	core.FocusState
+
+	vt       *vt.Terminal
+	rw       io.ReadWriter
+	palette  [16]core.Color
+	onResize func(cols, rows int)
+	onTitle  func(title string)
+	onBell   func()
+	onExit   func(err error)
+	title    string
+
+	// The reader goroutine appends to pending and posts a drain when none
+	// is posted yet.
+	mu      sync.Mutex
+	pending []byte
+	readErr error
+	posted  bool
+	gen     int // incremented by Attach, to stop the previous reader
+	reading bool
+
+	style        core.TextStyle
+	cellW, cellH float32
+	grid         core.Rect
+	bar          scroll.Bar
+	view         int     // lines scrolled back from the bottom
+	scrollRest   float32 // wheel distance short of a line
+
+	// The selection is between two cells in stable line numbers, which
+	// count lines from the first one ever pushed into the scrollback and
+	// so do not change as output scrolls.
+	anchor, caret termPos
+	selected      bool
+	selecting     bool
+	reported      event.MouseButton // the button reported as held
+	lastCell      termPos           // the last cell reported for motion
+}
+
+type termPos struct{ line, col int }
+
+func (a termPos) before(b termPos) bool {
+	return a.line < b.line || a.line == b.line && a.col < b.col
+}
+
+// NewTerminal returns a Terminal of 80 by 24 cells that is not attached to
+// a program.
+func NewTerminal() *Terminal {
+	t := &Terminal{vt: vt.New(terminalCols, terminalRows), palette: DefaultTerminalPalette}
+	t.vt.Reply = t.send
+	t.vt.Bell = func() {
+		if t.onBell != nil {
+			t.onBell()
+		}
+	}
+	return t
+}
+
+// Attach connects the terminal to a program: its output is read from rw
+// in the background, and input is written to rw. Reading starts when the
+// terminal is first laid out and stops when rw reports an error, such as
+// io.EOF when the program exits.
+func (t *Terminal) Attach(rw io.ReadWriter) *Terminal {
+	t.mu.Lock()
+	t.gen++
+	t.reading = false
+	t.pending, t.readErr = nil, nil
+	t.mu.Unlock()
+	t.rw = rw
+	return t
+}
+
+// OnResize sets the function called with the size in cells when it
+// changes.
+func (t *Terminal) OnResize(fn func(cols, rows int)) *Terminal {
+	t.onResize = fn
+	return t
+}
+
+// OnTitle sets the function called when the program sets the title.
+func (t *Terminal) OnTitle(fn func(title string)) *Terminal {
+	t.onTitle = fn
+	return t
+}
+
+// OnBell sets the function called when the program rings the bell.
+func (t *Terminal) OnBell(fn func()) *Terminal {
+	t.onBell = fn
+	return t
+}
+
+// OnExit sets the function called when reading from the attached program
+// fails, with io.EOF when it exits normally.
+func (t *Terminal) OnExit(fn func(err error)) *Terminal {
+	t.onExit = fn
+	return t
+}
+
+// Palette sets the 16 basic colors; see DefaultTerminalPalette.
+func (t *Terminal) Palette(p [16]core.Color) *Terminal {
+	t.palette = p
+	return t
+}
+
+// Scrollback sets how many lines are kept above the screen. The default
+// is 10000.
+func (t *Terminal) Scrollback(lines int) *Terminal {
+	t.vt.MaxScrollback = max(0, lines)
+	return t
+}
+
+// Size returns the size in cells.
+func (t *Terminal) Size() (cols, rows int) {
+	return t.vt.Size()
+}
+
+// Title returns the title last set by the program.
+func (t *Terminal) Title() string {
+	return t.title
+}
+
+// Write feeds program output to the terminal directly, for terminals that
+// are not attached. It must be called on the UI goroutine.
+func (t *Terminal) Write(p []byte) (int, error) {
+	pushed := t.vt.Pushed()
+	t.vt.Write(p)
+	if t.view > 0 {
+		t.view = min(t.view+t.vt.Pushed()-pushed, t.vt.Scrollback())
+	}
+	if title := t.vt.Title(); title != t.title {
+		t.title = title
+		if t.onTitle != nil {
+			t.onTitle(title)
+		}
+	}
+	return len(p), nil
+}
+
+// Paste sends text to the program as if typed, marking it as a paste if
+// the program asked for that.
+func (t *Terminal) Paste(text string) {
+	if t.vt.BracketedPaste() {
+		text = "\x1b[200~" + strings.ReplaceAll(text, "\x1b[201~", "") + "\x1b[201~"
+	} else {
+		text = strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\r"), "\n", "\r")
+	}
+	t.input([]byte(text))
+}
+
+// SelectedText returns the selected text, or "" without a selection.
+func (t *Terminal) SelectedText() string {
+	if !t.selected {
+		return ""
+	}
+	from, to := t.selection()
+	base := t.base()
+	var sb strings.Builder
+	for ln := from.line; ln <= to.line; ln++ {
+		i := ln - base
+		if i < 0 || i >= t.vt.Len() {
+			continue
+		}
+		l := t.vt.Line(i)
+		start, end := 0, len(l.Cells)
+		if ln == from.line {
+			start = from.col
+		}
+		if ln == to.line {
+			end = min(end, to.col)
+		}
+		var row strings.Builder
+		for _, c := range l.Cells[min(start, len(l.Cells)):max(start, end)] {
+			switch {
+			case c.Tail:
+			case c.Rune == 0:
+				row.WriteByte(' ')
+			default:
+				row.WriteRune(c.Rune)
+			}
+		}
+		if l.Wrapped && ln != to.line {
+			sb.WriteString(row.String())
+			continue
+		}
+		sb.WriteString(strings.TrimRight(row.String(), " "))
+		if ln != to.line {
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String()
+}
+
+// base returns the stable number of the first line of the scrollback.
+func (t *Terminal) base() int {
+	return t.vt.Pushed() - t.vt.Scrollback()
+}
+
+// selection returns the selection in order.
+func (t *Terminal) selection() (from, to termPos) {
+	if t.caret.before(t.anchor) {
+		return t.caret, t.anchor
+	}
+	return t.anchor, t.caret
+}
+
+// send writes b to the program.
+func (t *Terminal) send(b []byte) {
+	if t.rw != nil {
+		t.rw.Write(b)
+	}
+}
+
+// input sends what the user typed, scrolling back to the bottom.
+func (t *Terminal) input(b []byte) {
+	t.view = 0
+	t.send(b)
+}
+
+// read copies the program's output into pending until rw fails.
+func (t *Terminal) read(ctx *core.Context, rw io.Reader, gen int) {
+	buf := make([]byte, terminalReadSize)
+	for {
+		n, err := rw.Read(buf)
+		t.mu.Lock()
+		if t.gen != gen {
+			t.mu.Unlock()
+			return
+		}
+		t.pending = append(t.pending, buf[:n]...)
+		if err != nil {
+			t.readErr = err
+		}
+		post := !t.posted
+		t.posted = true
+		t.mu.Unlock()
+		if post {
+			ctx.Post(func() { t.drain(ctx) })
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// drain feeds pending output to the emulator on the UI goroutine.
+func (t *Terminal) drain(ctx *core.Context) {
+	t.mu.Lock()
+	data, err := t.pending, t.readErr
+	t.pending, t.readErr, t.posted = nil, nil, false
+	t.mu.Unlock()
+	t.Write(data)
+	if err != nil && t.onExit != nil {
+		t.onExit(err)
+	}
+	ctx.Repaint(t)
+}
+
+// Layout implements core.Widget.
+func (t *Terminal) Layout(ctx *core.LayoutContext) core.Size {
+	th := theme.From(ctx.Context)
+	t.style = th.Typography.Mono
+	t.cellW = ctx.MeasureText("M", t.style).Width
+	t.cellH = t.style.LineHeight()
+	if t.rw != nil {
+		t.mu.Lock()
+		start := !t.reading
+		t.reading = true
+		gen := t.gen
+		t.mu.Unlock()
+		if start {
+			go t.read(ctx.Context, t.rw, gen)
+		}
+	}
+	cols, rows := t.vt.Size()
+	return ctx.Constraints.Constrain(core.Sz(
+		float32(cols)*t.cellW+2*terminalPadding+scroll.Thickness,
+		float32(rows)*t.cellH+2*terminalPadding,
+	))
+}
+
+// SetBounds implements core.Widget. The grid takes as many cells as fit.
+func (t *Terminal) SetBounds(r core.Rect) {
+	t.WidgetBase.SetBounds(r)
+	t.grid = core.R(r.X+terminalPadding, r.Y+terminalPadding,
+		max(0, r.Width-2*terminalPadding-scroll.Thickness), max(0, r.Height-2*terminalPadding))
+	if t.cellW > 0 && t.cellH > 0 {
+		cols := max(1, int(t.grid.Width/t.cellW))
+		rows := max(1, int(t.grid.Height/t.cellH))
+		if c, r := t.vt.Size(); c != cols || r != rows {
+			t.vt.Resize(cols, rows)
+			t.view = min(t.view, t.vt.Scrollback())
+			if t.onResize != nil {
+				t.onResize(cols, rows)
+			}
+		}
+	}
+	t.bar.Track = core.R(r.Right()-scroll.Thickness, r.Y, scroll.Thickness, r.Height)
+	t.updateBar()
+}
+
+func (t *Terminal) updateBar() {
+	_, rows := t.vt.Size()
+	t.bar.Viewport = float32(rows) * t.cellH
+	t.bar.Content = float32(t.vt.Len()) * t.cellH
+}
+
+// offset returns the scroll offset of the view in pixels.
+func (t *Terminal) offset() float32 {
+	return float32(t.vt.Scrollback()-t.view) * t.cellH
+}
+
+// scrollTo scrolls the view so the top line is at offset pixels.
+func (t *Terminal) scrollTo(offset float32) {
+	top := int(offset/t.cellH + 0.5)
+	t.view = max(0, min(t.vt.Scrollback()-top, t.vt.Scrollback()))
+}
+
+// color256 returns color i of the 256-color palette.
+func (t *Terminal) color256(i uint8) core.Color {
+	switch {
+	case i < 16:
+		return t.palette[i]
+	case i < 232:
+		levels := [6]uint8{0, 95, 135, 175, 215, 255}
+		i -= 16
+		return core.RGB(levels[i/36], levels[i/6%6], levels[i%6])
+	default:
+		g := 8 + 10*(i-232)
+		return core.RGB(g, g, g)
+	}
+}
+
+// colors returns the colors a cell of style s is drawn in.
+func (t *Terminal) colors(th *theme.Theme, s vt.Style) (fg, bg core.Color) {
+	fg, bg = th.Colors.OnSurface, th.Colors.Surface
+	if i, ok := s.FG.Index(); ok {
+		if i < 8 && s.Attr&vt.Bold != 0 {
+			i += 8
+		}
+		fg = t.color256(i)
+	} else if r, g, b, ok := s.FG.RGB(); ok {
+		fg = core.RGB(r, g, b)
+	}
+	if i, ok := s.BG.Index(); ok {
+		bg = t.color256(i)
+	} else if r, g, b, ok := s.BG.RGB(); ok {
+		bg = core.RGB(r, g, b)
+	}
+	if s.Attr&vt.Inverse != 0 {
+		fg, bg = bg, fg
+	}
+	if s.Attr&vt.Faint != 0 {
+		fg = fg.WithAlpha(fg.A * 0.6)
+	}
+	if s.Attr&vt.Hidden != 0 {
+		fg = bg
+	}
+	return fg, bg
+}
+
+// textStyle returns the font a cell of style s is drawn in.
+func (t *Terminal) textStyle(s vt.Style, fg core.Color) core.TextStyle {
+	st := theme.TextStyle(t.style, fg)
+	if s.Attr&vt.Bold != 0 {
+		st.Weight = core.WeightBold
+	}
+	st.Italic = s.Attr&vt.Italic != 0
+	return st
+}
+
+// Paint implements core.Widget.
+func (t *Terminal) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	b := t.Bounds()
+	cv.DrawRect(b, core.Filled(th.Colors.Surface))
+	_, rows := t.vt.Size()
+	top := t.vt.Scrollback() - t.view
+	base := t.base()
+	from, to := t.selection()
+
+	cv.Save()
+	cv.Clip(b)
+	for row := range rows {
+		i := top + row
+		if i >= t.vt.Len() {
+			break
+		}
+		l := t.vt.Line(i)
+		y := t.grid.Y + float32(row)*t.cellH
+		ln := base + i
+		selected := func(col int) bool {
+			p := termPos{ln, col}
+			return t.selected && !p.before(from) && p.before(to)
+		}
+
+		// Backgrounds, merged into runs of one color.
+		for c := 0; c < len(l.Cells); {
+			_, bg := t.colors(th, l.Cells[c].Style)
+			if selected(c) {
+				bg = th.Colors.Selection
+			}
+			e := c + 1
+			for e < len(l.Cells) {
+				_, next := t.colors(th, l.Cells[e].Style)
+				if selected(e) != selected(c) || !selected(c) && next != bg {
+					break
+				}
+				e++
+			}
+			if bg != th.Colors.Surface {
+				cv.DrawRect(core.R(t.grid.X+float32(c)*t.cellW, y, float32(e-c)*t.cellW, t.cellH), core.Filled(bg))
+			}
+			c = e
+		}
+
+		// Text, in runs of one style so a monospaced font keeps the grid;
+		// wide characters are drawn on their own.
+		for c := 0; c < len(l.Cells); {
+			cell := l.Cells[c]
+			if cell.Tail {
+				c++
+				continue
+			}
+			var run strings.Builder
+			e := c
+			for e < len(l.Cells) && l.Cells[e].Style == cell.Style && !l.Cells[e].Tail {
+				r := l.Cells[e].Rune
+				if r == 0 {
+					r = ' '
+				}
+				run.WriteRune(r)
+				e++
+				if l.Cells[e-1].Wide {
+					break
+				}
+			}
+			t.paintText(ctx, th, run.String(), cell.Style, core.Pt(t.grid.X+float32(c)*t.cellW, y), e-c)
+			c = e
+		}
+	}
+
+	if x, y, visible := t.vt.Cursor(); visible && t.view == 0 {
+		t.paintCursor(ctx, th, x, y)
+	}
+	cv.Restore()
+	t.updateBar()
+	t.bar.Paint(ctx, t.offset())
+}
+
+// paintText draws text of style s over cells cells at p.
+func (t *Terminal) paintText(ctx *core.PaintContext, th *theme.Theme, text string, s vt.Style, p core.Point, cells int) {
+	fg, _ := t.colors(th, s)
+	if strings.TrimSpace(text) != "" && s.Attr&vt.Hidden == 0 {
+		ctx.Canvas.DrawText(text, p, t.textStyle(s, fg))
+	}
+	w := float32(cells) * t.cellW
+	if s.Attr&vt.Underline != 0 {
+		ctx.Canvas.DrawRect(core.R(p.X, p.Y+t.cellH-1.5, w, 1), core.Filled(fg))
+	}
+	if s.Attr&vt.Strike != 0 {
+		ctx.Canvas.DrawRect(core.R(p.X, p.Y+t.cellH/2, w, 1), core.Filled(fg))
+	}
+}
+
+// paintCursor draws the cursor at cell x, y of the screen: in the shape
+// the program asked for when focused, and as an outline otherwise.
+func (t *Terminal) paintCursor(ctx *core.PaintContext, th *theme.Theme, x, y int) {
+	cols, _ := t.vt.Size()
+	l := t.vt.Line(t.vt.Scrollback() + y)
+	var cell vt.Cell
+	if x < len(l.Cells) {
+		cell = l.Cells[x]
+	}
+	w := t.cellW
+	if cell.Wide && x+1 < cols {
+		w *= 2
+	}
+	fg, bg := t.colors(th, cell.Style)
+	r := core.R(t.grid.X+float32(x)*t.cellW, t.grid.Y+float32(y)*t.cellH, w, t.cellH)
+	cv := ctx.Canvas
+	if !t.IsFocused() {
+		cv.DrawRect(r.Inset(core.UniformInsets(0.5)), core.Stroked(fg, 1))
+		return
+	}
+	switch t.vt.CursorShape() {
+	case vt.CursorUnderline:
+		cv.DrawRect(core.R(r.X, r.Bottom()-2, r.Width, 2), core.Filled(fg))
+	case vt.CursorBar:
+		cv.DrawRect(core.R(r.X, r.Y, 2, r.Height), core.Filled(fg))
+	default:
+		cv.DrawRect(r, core.Filled(fg))
+		if cell.Rune > ' ' {
+			cv.DrawText(string(cell.Rune), r.Origin(), t.textStyle(cell.Style, bg))
+		}
+	}
+}
+
+// cellAt returns the cell under p in stable line numbers, with the column
+// rounded to the nearest cell boundary if between is set.
+func (t *Terminal) cellAt(p core.Point, between bool) termPos {
+	cols, rows := t.vt.Size()
+	row := max(0, min(int((p.Y-t.grid.Y)/t.cellH), rows-1))
+	x := (p.X - t.grid.X) / t.cellW
+	if between {
+		x += 0.5
+	}
+	col := max(0, min(int(x), cols))
+	if !between {
+		col = min(col, cols-1)
+	}
+	return termPos{t.base() + t.vt.Scrollback() - t.view + row, col}
+}
+
+// HandleEvent implements core.Widget.
+func (t *Terminal) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	if off, ok := t.bar.HandleEvent(ctx, t, ev, t.offset()); ok {
+		t.scrollTo(off)
+		ctx.Repaint(t)
+		return core.Handled
+	}
+	switch ev := ev.(type) {
+	case event.MouseEvent:
+		return t.handleMouse(ctx, ev)
+	case event.ScrollEvent:
+		if mode, _ := t.vt.Mouse(); mode != vt.MouseOff && !ev.Modifiers.Has(event.ModShift) {
+			button := 64
+			if ev.Delta.Y > 0 {
+				button = 65
+			}
+			t.reportMouse(button, t.cellAt(ev.Position, false), ev.Modifiers, true)
+			return core.Handled
+		}
+		t.scrollRest -= ev.Delta.Y / t.cellH
+		lines := int(t.scrollRest)
+		t.scrollRest -= float32(lines)
+		t.view = max(0, min(t.view+lines, t.vt.Scrollback()))
+		ctx.Repaint(t)
+		return core.Handled
+	case event.KeyEvent:
+		if !t.IsFocused() || ev.Type != event.KeyPress {
+			return core.Ignored
+		}
+		return t.handleKey(ctx, ev)
+	case event.TextEvent:
+		if !t.IsFocused() || ev.Text == "" {
+			return core.Ignored
+		}
+		t.input([]byte(ev.Text))
+		ctx.Repaint(t)
+		return core.Handled
+	}
+	return core.Ignored
+}
+
+func (t *Terminal) handleMouse(ctx *core.Context, ev event.MouseEvent) core.EventResult {
+	mode, _ := t.vt.Mouse()
+	report := mode != vt.MouseOff && !ev.Modifiers.Has(event.ModShift)
+	switch ev.Type {
+	case event.MouseDown:
+		ctx.RequestFocus(t)
+		if report {
+			button, ok := mouseButtonCode(ev.Button)
+			if !ok {
+				return core.Ignored
+			}
+			t.reported = ev.Button
+			t.lastCell = t.cellAt(ev.Position, false)
+			t.reportMouse(button, t.lastCell, ev.Modifiers, true)
+			ctx.CapturePointer(t)
+			return core.Handled
+		}
+		if ev.Button != event.ButtonLeft {
+			return core.Ignored
+		}
+		p := t.cellAt(ev.Position, true)
+		switch {
+		case ev.ClickCount == 2:
+			t.anchor, t.caret = t.word(t.cellAt(ev.Position, false))
+		case ev.ClickCount >= 3:
+			t.anchor, t.caret = termPos{p.line, 0}, termPos{p.line + 1, 0}
+		case ev.Modifiers.Has(event.ModShift) && t.selected:
+			t.caret = p
+		default:
+			t.anchor, t.caret = p, p
+		}
+		t.selected = t.anchor != t.caret
+		t.selecting = true
+		ctx.CapturePointer(t)
+		ctx.Repaint(t)
+		return core.Handled
+	case event.MouseMove:
+		if t.selecting {
+			t.caret = t.cellAt(ev.Position, true)
+			t.selected = t.anchor != t.caret
+			ctx.Repaint(t)
+			return core.Handled
+		}
+		if mode == vt.MouseMotion || mode == vt.MouseDrags && t.reported != event.ButtonNone {
+			p := t.cellAt(ev.Position, false)
+			if p == t.lastCell {
+				return core.Handled
+			}
+			t.lastCell = p
+			button, ok := mouseButtonCode(t.reported)
+			if !ok {
+				button = 3
+			}
+			t.reportMouse(button+32, p, ev.Modifiers, true)
+			return core.Handled
+		}
+	case event.MouseUp:
+		if t.selecting {
+			t.selecting = false
+			ctx.ReleasePointer()
+			return core.Handled
+		}
+		if t.reported != event.ButtonNone && ev.Button == t.reported {
+			button, _ := mouseButtonCode(t.reported)
+			t.reported = event.ButtonNone
+			t.reportMouse(button, t.cellAt(ev.Position, false), ev.Modifiers, false)
+			ctx.ReleasePointer()
+			return core.Handled
+		}
+	}
+	return core.Ignored
+}
+
+// word returns the bounds of the word around p.
+func (t *Terminal) word(p termPos) (from, to termPos) {
+	i := p.line - t.base()
+	if i < 0 || i >= t.vt.Len() {
+		return p, p
+	}
+	cells := t.vt.Line(i).Cells
+	class := func(c int) int {
+		r := cells[c].Rune
+		switch {
+		case r == 0 || r == ' ':
+			return 0
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_-.~/", r):
+			return 1
+		default:
+			return 2
+		}
+	}
+	if p.col >= len(cells) {
+		return p, p
+	}
+	k := class(p.col)
+	s, e := p.col, p.col+1
+	for s > 0 && class(s-1) == k {
+		s--
+	}
+	for e < len(cells) && class(e) == k {
+		e++
+	}
+	return termPos{p.line, s}, termPos{p.line, e}
+}
+
+// mouseButtonCode returns the number xterm reports button b as.
+func mouseButtonCode(b event.MouseButton) (int, bool) {
+	switch b {
+	case event.ButtonLeft:
+		return 0, true
+	case event.ButtonMiddle:
+		return 1, true
+	case event.ButtonRight:
+		return 2, true
+	}
+	return 0, false
+}
+
+// reportMouse sends a mouse event with xterm's button code at cell p.
+func (t *Terminal) reportMouse(button int, p termPos, m event.Modifiers, press bool) {
+	if m.Has(event.ModAlt) {
+		button += 8
+	}
+	if m.Has(event.ModCtrl) {
+		button += 16
+	}
+	x := p.col + 1
+	y := p.line - t.base() - t.vt.Scrollback() + t.view + 1 // the row in view
+	if _, sgr := t.vt.Mouse(); sgr {
+		final := 'M'
+		if !press {
+			final = 'm'
+		}
+		t.send(fmt.Appendf(nil, "\x1b[<%d;%d;%d%c", button, x, y, final))
+		return
+	}
+	if !press {
+		button = 3
+	}
+	if x > 223 || y > 223 {
+		return // beyond what the X10 encoding can express
+	}
+	t.send([]byte{0x1b, '[', 'M', byte(32 + button), byte(32 + x), byte(32 + y)})
+}
+
+func (t *Terminal) handleKey(ctx *core.Context, ev event.KeyEvent) core.EventResult {
+	m := ev.Modifiers
+	switch {
+	case ev.Key == event.KeyC && m.Has(event.ModCtrl) && m.Has(event.ModShift):
+		if text := t.SelectedText(); text != "" {
+			clipboardFrom(ctx).WriteText(text)
+		}
+		return core.Handled
+	case ev.Key == event.KeyV && m.Has(event.ModCtrl) && m.Has(event.ModShift),
+		ev.Key == event.KeyInsert && m == event.ModShift:
+		clipboardFrom(ctx).ReadText(func(text string, ok bool) {
+			if ok {
+				ctx.Post(func() {
+					t.Paste(text)
+					ctx.Repaint(t)
+				})
+			}
+		})
+		return core.Handled
+	case (ev.Key == event.KeyPageUp || ev.Key == event.KeyPageDown) && m == event.ModShift:
+		_, rows := t.vt.Size()
+		page := max(1, rows-1)
+		if ev.Key == event.KeyPageDown {
+			page = -page
+		}
+		t.view = max(0, min(t.view+page, t.vt.Scrollback()))
+		ctx.Repaint(t)
+		return core.Handled
+	}
+	b := t.keySequence(ev)
+	if b == nil {
+		return core.Ignored
+	}
+	t.input(b)
+	ctx.Repaint(t)
+	return core.Handled
+}
+
+// Function keys and the keys of the editing pad, with the final of their
+// CSI sequence: a letter, or the number before ~.
+var terminalKeys = map[event.Key]struct {
+	final byte
+	num   int
+}{
+	event.KeyUp: {'A', 0}, event.KeyDown: {'B', 0}, event.KeyRight: {'C', 0}, event.KeyLeft: {'D', 0},
+	event.KeyHome: {'H', 0}, event.KeyEnd: {'F', 0},
+	event.KeyF1: {'P', 0}, event.KeyF2: {'Q', 0}, event.KeyF3: {'R', 0}, event.KeyF4: {'S', 0},
+	event.KeyInsert: {'~', 2}, event.KeyDelete: {'~', 3}, event.KeyPageUp: {'~', 5}, event.KeyPageDown: {'~', 6},
+	event.KeyF5: {'~', 15}, event.KeyF6: {'~', 17}, event.KeyF7: {'~', 18}, event.KeyF8: {'~', 19},
+	event.KeyF9: {'~', 20}, event.KeyF10: {'~', 21}, event.KeyF11: {'~', 23}, event.KeyF12: {'~', 24},
+}
+
+// keySequence returns what xterm sends for a key press, or nil for keys
+// that produce text, which arrives as a TextEvent.
+func (t *Terminal) keySequence(ev event.KeyEvent) []byte {
+	m := ev.Modifiers
+	ctrl, alt, shift := m.Has(event.ModCtrl), m.Has(event.ModAlt), m.Has(event.ModShift)
+	if k, ok := terminalKeys[ev.Key]; ok {
+		mod := 1
+		if shift {
+			mod++
+		}
+		if alt {
+			mod += 2
+		}
+		if ctrl {
+			mod += 4
+		}
+		switch {
+		case k.num > 0 && mod > 1:
+			return fmt.Appendf(nil, "\x1b[%d;%d~", k.num, mod)
+		case k.num > 0:
+			return fmt.Appendf(nil, "\x1b[%d~", k.num)
+		case mod > 1:
+			return fmt.Appendf(nil, "\x1b[1;%d%c", mod, k.final)
+		case k.final >= 'P' || t.vt.AppCursor():
+			return []byte{0x1b, 'O', k.final}
+		default:
+			return []byte{0x1b, '[', k.final}
+		}
+	}
+	var b []byte
+	switch {
+	case ev.Key == event.KeyEnter:
+		b = []byte{'\r'}
+	case ev.Key == event.KeyTab && shift:
+		return []byte("\x1b[Z")
+	case ev.Key == event.KeyTab:
+		b = []byte{'\t'}
+	case ev.Key == event.KeyBackspace && ctrl:
+		b = []byte{0x08}
+	case ev.Key == event.KeyBackspace:
+		b = []byte{0x7f}
+	case ev.Key == event.KeyEscape:
+		b = []byte{0x1b}
+	case ctrl && ev.Key >= event.KeyA && ev.Key <= event.KeyZ:
+		b = []byte{byte(ev.Key-event.KeyA) + 1}
+	case ctrl && ev.Key == event.KeySpace:
+		b = []byte{0}
+	case alt && !ctrl && ev.Key >= event.KeyA && ev.Key <= event.KeyZ:
+		c := byte(ev.Key-event.KeyA) + 'a'
+		if shift {
+			c -= 'a' - 'A'
+		}
+		b = []byte{c}
+	default:
+		return nil
+	}
+	if alt {
+		b = append([]byte{0x1b}, b...)
+	}
+	return b
+}
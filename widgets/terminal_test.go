@@ -0,0 +1,574 @@
+package widgets
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/internal/scroll"
+	"github.com/gogpu/ui/internal/vt"
+	"github.com/gogpu/ui/theme"
+)
+
+// fakePty stands in for the master side of a pty: what the program
+// writes to prog is read by the terminal, and what the terminal writes is
+// collected.
+type fakePty struct {
+	out  *io.PipeReader
+	prog *io.PipeWriter
+
+	mu sync.Mutex
+	in []byte
+}
+
+func newFakePty(t *testing.T) *fakePty {
+	r, w := io.Pipe()
+	t.Cleanup(func() { w.Close() })
+	return &fakePty{out: r, prog: w}
+}
+
+func (p *fakePty) Read(b []byte) (int, error) { return p.out.Read(b) }
+
+func (p *fakePty) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.in = append(p.in, b...)
+	return len(b), nil
+}
+
+// sent returns what the terminal wrote since the last call.
+func (p *fakePty) sent() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s := string(p.in)
+	p.in = nil
+	return s
+}
+
+// newTerminal returns a focused Terminal attached to a fake pty and laid
+// out to cols by rows cells.
+func newTerminal(t *testing.T, cols, rows int) (*Terminal, *core.Context, *fakePty) {
+	pty := newFakePty(t)
+	term := NewTerminal().Attach(pty)
+	ctx := core.NewContext()
+	lc := &core.LayoutContext{Context: ctx}
+	lc.Measure(term, core.Unbounded())
+	ctx.LayoutRoot(term, terminalBounds(term, cols, rows))
+	ctx.RequestFocus(term)
+	return term, ctx, pty
+}
+
+// terminalBounds returns bounds that fit cols by rows cells of term.
+func terminalBounds(term *Terminal, cols, rows int) core.Rect {
+	return core.R(0, 0,
+		float32(cols)*term.cellW+2*terminalPadding+scroll.Thickness+0.5,
+		float32(rows)*term.cellH+2*terminalPadding+0.5)
+}
+
+// cellPoint returns a point at the left of cell col on row of the view.
+func cellPoint(term *Terminal, col, row int) core.Point {
+	return core.Pt(term.grid.X+(float32(col)+0.25)*term.cellW, term.grid.Y+(float32(row)+0.5)*term.cellH)
+}
+
+// termMouse is a mouse event on a cell of a Terminal.
+type termMouse struct {
+	typ      event.MouseEventType
+	col, row int
+	button   event.MouseButton
+	mods     event.Modifiers
+	clicks   int
+}
+
+func (m termMouse) event(term *Terminal) event.MouseEvent {
+	button := m.button
+	if button == event.ButtonNone && m.typ != event.MouseMove {
+		button = event.ButtonLeft
+	}
+	return event.MouseEvent{Type: m.typ, Position: cellPoint(term, m.col, m.row), Button: button, Modifiers: m.mods, ClickCount: max(1, m.clicks)}
+}
+
+func TestTerminalSize(t *testing.T) {
+	term := NewTerminal()
+	ctx := core.NewContext()
+	lc := &core.LayoutContext{Context: ctx}
+	s := lc.Measure(term, core.Unbounded())
+	if want := 80*term.cellW + 2*terminalPadding + scroll.Thickness; s.Width != want || s.Height != 24*term.cellH+2*terminalPadding {
+		t.Errorf("natural size %v, want 80 by 24 cells", s)
+	}
+
+	var sizes [][2]int
+	term.OnResize(func(cols, rows int) { sizes = append(sizes, [2]int{cols, rows}) })
+	ctx.LayoutRoot(term, terminalBounds(term, 20, 5))
+	ctx.Invalidate()
+	ctx.LayoutRoot(term, terminalBounds(term, 20, 5))
+	ctx.Invalidate()
+	ctx.LayoutRoot(term, core.R(0, 0, 1, 1))
+	if len(sizes) != 2 || sizes[0] != [2]int{20, 5} || sizes[1] != [2]int{1, 1} {
+		t.Errorf("resized to %v, want 20x5 then 1x1", sizes)
+	}
+	if cols, rows := term.Size(); cols != 1 || rows != 1 {
+		t.Errorf("Size() = %d, %d", cols, rows)
+	}
+}
+
+func TestTerminalTitle(t *testing.T) {
+	term := NewTerminal()
+	var titles []string
+	term.OnTitle(func(title string) { titles = append(titles, title) })
+	term.Write([]byte("\x1b]2;one\x07\x1b]2;one\x07"))
+	term.Write([]byte("\x1b]0;two\x07"))
+	if term.Title() != "two" || len(titles) != 2 || titles[0] != "one" {
+		t.Errorf("Title() = %q after titles %q", term.Title(), titles)
+	}
+
+	rings := 0
+	term.OnBell(func() { rings++ })
+	term.Write([]byte("\a"))
+	if rings != 1 {
+		t.Errorf("the bell rang %d times", rings)
+	}
+}
+
+func TestTerminalKeys(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup string
+		key   event.Key
+		mods  event.Modifiers
+		want  string
+	}{
+		{"up", "", event.KeyUp, 0, "\x1b[A"},
+		{"application up", "\x1b[?1h", event.KeyUp, 0, "\x1bOA"},
+		{"ctrl left", "", event.KeyLeft, event.ModCtrl, "\x1b[1;5D"},
+		{"shift alt end", "", event.KeyEnd, event.ModShift | event.ModAlt, "\x1b[1;4F"},
+		{"home", "", event.KeyHome, 0, "\x1b[H"},
+		{"delete", "", event.KeyDelete, 0, "\x1b[3~"},
+		{"shift delete", "", event.KeyDelete, event.ModShift, "\x1b[3;2~"},
+		{"page down", "", event.KeyPageDown, 0, "\x1b[6~"},
+		{"F1", "", event.KeyF1, 0, "\x1bOP"},
+		{"ctrl F1", "", event.KeyF1, event.ModCtrl, "\x1b[1;5P"},
+		{"F5", "", event.KeyF5, 0, "\x1b[15~"},
+		{"F12", "", event.KeyF12, 0, "\x1b[24~"},
+		{"enter", "", event.KeyEnter, 0, "\r"},
+		{"tab", "", event.KeyTab, 0, "\t"},
+		{"shift tab", "", event.KeyTab, event.ModShift, "\x1b[Z"},
+		{"backspace", "", event.KeyBackspace, 0, "\x7f"},
+		{"ctrl backspace", "", event.KeyBackspace, event.ModCtrl, "\b"},
+		{"escape", "", event.KeyEscape, 0, "\x1b"},
+		{"ctrl c", "", event.KeyC, event.ModCtrl, "\x03"},
+		{"ctrl shift z", "", event.KeyZ, event.ModCtrl | event.ModShift, "\x1a"},
+		{"ctrl space", "", event.KeySpace, event.ModCtrl, "\x00"},
+		{"alt b", "", event.KeyB, event.ModAlt, "\x1bb"},
+		{"alt shift b", "", event.KeyB, event.ModAlt | event.ModShift, "\x1bB"},
+		{"alt enter", "", event.KeyEnter, event.ModAlt, "\x1b\r"},
+		{"ctrl alt a", "", event.KeyA, event.ModCtrl | event.ModAlt, "\x1b\x01"},
+		{"a letter arrives as text", "", event.KeyA, 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			term, ctx, pty := newTerminal(t, 20, 5)
+			term.Write([]byte(tt.setup))
+			r := term.HandleEvent(ctx, press(tt.key, tt.mods))
+			if got := pty.sent(); got != tt.want {
+				t.Errorf("sent %q, want %q", got, tt.want)
+			}
+			if (r == core.Handled) != (tt.want != "") {
+				t.Errorf("HandleEvent = %v", r)
+			}
+		})
+	}
+
+	term, ctx, pty := newTerminal(t, 20, 5)
+	ctx.RequestFocus(nil)
+	if r := term.HandleEvent(ctx, press(event.KeyEnter, 0)); r != core.Ignored || pty.sent() != "" {
+		t.Error("an unfocused terminal took a key")
+	}
+	if r := term.HandleEvent(ctx, event.TextEvent{Text: "x"}); r != core.Ignored || pty.sent() != "" {
+		t.Error("an unfocused terminal took text")
+	}
+	ctx.RequestFocus(term)
+	term.HandleEvent(ctx, event.TextEvent{Text: "héllo"})
+	if got := pty.sent(); got != "héllo" {
+		t.Errorf("typing sent %q", got)
+	}
+}
+
+func TestTerminalPaste(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup string
+		text  string
+		want  string
+	}{
+		{"plain", "", "a\nb\r\nc", "a\rb\rc"},
+		{"bracketed", "\x1b[?2004h", "a\nb", "\x1b[200~a\nb\x1b[201~"},
+		{"bracketed end removed", "\x1b[?2004h", "x\x1b[201~y", "\x1b[200~xy\x1b[201~"},
+		{"bracketing turned off", "\x1b[?2004h\x1b[?2004l", "a\n", "a\r"},
+	}
+	for _, tt := range tests {
+		term, _, pty := newTerminal(t, 20, 5)
+		term.Write([]byte(tt.setup))
+		term.Paste(tt.text)
+		if got := pty.sent(); got != tt.want {
+			t.Errorf("%s: sent %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTerminalSelection(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		mouse  []termMouse
+		want   string
+	}{
+		{"drag", "hello world.txt\r\nsecond line", []termMouse{
+			{typ: event.MouseDown}, {typ: event.MouseMove, col: 5}, {typ: event.MouseUp, col: 5},
+		}, "hello"},
+		{"drag backwards across lines", "hello world.txt\r\nsecond line", []termMouse{
+			{typ: event.MouseDown, col: 3, row: 1}, {typ: event.MouseMove, col: 6}, {typ: event.MouseUp, col: 6},
+		}, "world.txt\nsec"},
+		{"click", "hello", []termMouse{
+			{typ: event.MouseDown, col: 2}, {typ: event.MouseUp, col: 2},
+		}, ""},
+		{"double click", "hello world.txt\r\nsecond line", []termMouse{
+			{typ: event.MouseDown, col: 8, clicks: 2}, {typ: event.MouseUp, col: 8, clicks: 2},
+		}, "world.txt"},
+		{"double click on punctuation", "f(x)", []termMouse{
+			{typ: event.MouseDown, col: 1, clicks: 2},
+		}, "("},
+		{"triple click", "hello world.txt\r\nsecond line", []termMouse{
+			{typ: event.MouseDown, col: 2, row: 1, clicks: 3},
+		}, "second line\n"},
+		{"shift click extends", "hello world.txt", []termMouse{
+			{typ: event.MouseDown}, {typ: event.MouseMove, col: 2}, {typ: event.MouseUp, col: 2},
+			{typ: event.MouseDown, col: 5, mods: event.ModShift}, {typ: event.MouseUp, col: 5},
+		}, "hello"},
+		{"wrapped lines join", strings.Repeat("ab", 12) + "\r\nnext", []termMouse{
+			{typ: event.MouseDown}, {typ: event.MouseMove, col: 2, row: 2}, {typ: event.MouseUp, col: 2, row: 2},
+		}, strings.Repeat("ab", 12) + "\nne"},
+		{"wide characters", "世界 ok", []termMouse{
+			{typ: event.MouseDown}, {typ: event.MouseMove, col: 5}, {typ: event.MouseUp, col: 5},
+		}, "世界"},
+		{"right button", "hello", []termMouse{
+			{typ: event.MouseDown, button: event.ButtonRight}, {typ: event.MouseMove, col: 3},
+		}, ""},
+		{"reporting program", "\x1b[?1000hhello", []termMouse{
+			{typ: event.MouseDown}, {typ: event.MouseMove, col: 5}, {typ: event.MouseUp, col: 5},
+		}, ""},
+		{"shift overrides reporting", "\x1b[?1000hhello", []termMouse{
+			{typ: event.MouseDown, mods: event.ModShift}, {typ: event.MouseMove, col: 5, mods: event.ModShift},
+		}, "hello"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			term, ctx, _ := newTerminal(t, 20, 5)
+			term.Write([]byte(tt.output))
+			for _, m := range tt.mouse {
+				term.HandleEvent(ctx, m.event(term))
+			}
+			if got := term.SelectedText(); got != tt.want {
+				t.Errorf("SelectedText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// The selection stays on its text as output scrolls it up.
+	term, ctx, _ := newTerminal(t, 20, 3)
+	term.Write([]byte("first\r\nsecond"))
+	term.HandleEvent(ctx, termMouse{typ: event.MouseDown, clicks: 3}.event(term))
+	term.Write([]byte("\r\n3\r\n4\r\n5"))
+	if got := term.SelectedText(); got != "first\n" {
+		t.Errorf("after scrolling SelectedText() = %q, want the first line", got)
+	}
+}
+
+func TestTerminalClipboard(t *testing.T) {
+	term, ctx, pty := newTerminal(t, 20, 5)
+	c := &fakeClipboard{}
+	SetClipboard(ctx, c)
+	term.Write([]byte("hello world"))
+
+	term.HandleEvent(ctx, press(event.KeyC, event.ModCtrl|event.ModShift))
+	if c.writes != 0 {
+		t.Error("copying without a selection wrote to the clipboard")
+	}
+	term.HandleEvent(ctx, termMouse{typ: event.MouseDown, col: 7, clicks: 2}.event(term))
+	term.HandleEvent(ctx, press(event.KeyC, event.ModCtrl|event.ModShift))
+	if c.text != "world" || pty.sent() != "" {
+		t.Errorf("copied %q", c.text)
+	}
+
+	for _, k := range []event.KeyEvent{press(event.KeyV, event.ModCtrl|event.ModShift), press(event.KeyInsert, event.ModShift)} {
+		if r := term.HandleEvent(ctx, k); r != core.Handled {
+			t.Errorf("%v = %v", k.Key, r)
+		}
+		c.answer()
+		ctx.RunPosted()
+		if got := pty.sent(); got != "world" {
+			t.Errorf("%v pasted %q", k.Key, got)
+		}
+	}
+	c.text = ""
+	term.HandleEvent(ctx, press(event.KeyV, event.ModCtrl|event.ModShift))
+	c.answer()
+	if ctx.HasPosted() {
+		t.Error("an empty clipboard was pasted")
+	}
+}
+
+func TestTerminalMouseReporting(t *testing.T) {
+	tests := []struct {
+		name  string
+		modes string
+		mouse []termMouse
+		want  string
+	}{
+		{"sgr click", "\x1b[?1000;1006h", []termMouse{
+			{typ: event.MouseDown, col: 2, row: 1}, {typ: event.MouseUp, col: 2, row: 1},
+		}, "\x1b[<0;3;2M\x1b[<0;3;2m"},
+		{"x10 click", "\x1b[?1000h", []termMouse{
+			{typ: event.MouseDown, button: event.ButtonRight}, {typ: event.MouseUp, button: event.ButtonRight},
+		}, "\x1b[M\x22!!\x1b[M#!!"},
+		{"modifiers", "\x1b[?1000;1006h", []termMouse{
+			{typ: event.MouseDown, button: event.ButtonMiddle, mods: event.ModCtrl | event.ModAlt},
+		}, "\x1b[<25;1;1M"},
+		{"clicks only", "\x1b[?1000;1006h", []termMouse{
+			{typ: event.MouseDown}, {typ: event.MouseMove, col: 3},
+		}, "\x1b[<0;1;1M"},
+		{"drags", "\x1b[?1002;1006h", []termMouse{
+			{typ: event.MouseMove, col: 1},
+			{typ: event.MouseDown}, {typ: event.MouseMove}, {typ: event.MouseMove, col: 1},
+		}, "\x1b[<0;1;1M\x1b[<32;2;1M"},
+		{"motion", "\x1b[?1003;1006h", []termMouse{
+			{typ: event.MouseMove, col: 1, row: 2},
+		}, "\x1b[<35;2;3M"},
+		{"release of another button", "\x1b[?1000;1006h", []termMouse{
+			{typ: event.MouseDown}, {typ: event.MouseUp, button: event.ButtonRight},
+		}, "\x1b[<0;1;1M"},
+		{"shift bypasses", "\x1b[?1000;1006h", []termMouse{
+			{typ: event.MouseDown, mods: event.ModShift},
+		}, ""},
+		{"off", "\x1b[?1000h\x1b[?1000l", []termMouse{
+			{typ: event.MouseDown}, {typ: event.MouseUp},
+		}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			term, ctx, pty := newTerminal(t, 20, 5)
+			term.Write([]byte(tt.modes))
+			for _, m := range tt.mouse {
+				term.HandleEvent(ctx, m.event(term))
+			}
+			if got := pty.sent(); got != tt.want {
+				t.Errorf("sent %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	term, ctx, pty := newTerminal(t, 20, 5)
+	term.Write([]byte("\x1b[?1000;1006h"))
+	for _, dy := range []float32{-10, 10} {
+		term.HandleEvent(ctx, event.ScrollEvent{Position: cellPoint(term, 1, 0), Delta: core.Pt(0, dy)})
+	}
+	if got := pty.sent(); got != "\x1b[<64;2;1M\x1b[<65;2;1M" {
+		t.Errorf("the wheel sent %q", got)
+	}
+}
+
+func TestTerminalScrollback(t *testing.T) {
+	term, ctx, pty := newTerminal(t, 20, 5)
+	for i := range 20 {
+		if i > 0 {
+			term.Write([]byte("\r\n"))
+		}
+		term.Write([]byte{'a' + byte(i)})
+	}
+	wheel := func(lines float32, mods event.Modifiers) {
+		term.HandleEvent(ctx, event.ScrollEvent{Delta: core.Pt(0, -lines*term.cellH), Modifiers: mods})
+	}
+
+	wheel(2, 0)
+	if term.view != 2 {
+		t.Errorf("view %d lines back after the wheel, want 2", term.view)
+	}
+	term.Write([]byte("\r\nmore"))
+	if term.view != 3 {
+		t.Errorf("view %d lines back after more output, want 3 to stay on the same text", term.view)
+	}
+	wheel(100, 0)
+	if term.view != term.vt.Scrollback() {
+		t.Errorf("view %d lines back, want the top at %d", term.view, term.vt.Scrollback())
+	}
+	term.HandleEvent(ctx, event.TextEvent{Text: "x"})
+	if term.view != 0 || pty.sent() != "x" {
+		t.Errorf("typing left the view %d lines back", term.view)
+	}
+	wheel(0.4, 0)
+	wheel(0.4, 0)
+	wheel(0.4, 0)
+	if term.view != 1 {
+		t.Errorf("three steps of 0.4 lines scrolled %d lines", term.view)
+	}
+
+	term.HandleEvent(ctx, press(event.KeyPageUp, event.ModShift))
+	if term.view != 5 {
+		t.Errorf("Shift+PageUp scrolled to %d, want a page of 4 more", term.view)
+	}
+	term.HandleEvent(ctx, press(event.KeyPageDown, event.ModShift))
+	term.HandleEvent(ctx, press(event.KeyPageDown, event.ModShift))
+	if term.view != 0 || pty.sent() != "" {
+		t.Errorf("Shift+PageDown left the view %d lines back", term.view)
+	}
+
+	// Clicking the track of the scroll bar above the thumb pages back.
+	term.Paint(&core.PaintContext{Context: ctx, Canvas: &core.Recording{}})
+	term.HandleEvent(ctx, leftMouse(event.MouseDown, core.Pt(term.bar.Track.X+1, term.bar.Track.Y+1)))
+	if term.view != 5 {
+		t.Errorf("the scroll bar scrolled to %d, want a page of 5", term.view)
+	}
+	term.HandleEvent(ctx, press(event.KeyPageDown, event.ModShift))
+	term.HandleEvent(ctx, press(event.KeyPageDown, event.ModShift))
+
+	// A program that reports the mouse gets the wheel, unless Shift is
+	// held.
+	term.Write([]byte("\x1b[?1000h"))
+	wheel(2, 0)
+	if term.view != 0 {
+		t.Error("the wheel scrolled a reporting program")
+	}
+	wheel(2, event.ModShift)
+	if term.view != 2 {
+		t.Errorf("Shift+wheel scrolled %d lines", term.view)
+	}
+
+	// Shrinking the scrollback limits the view.
+	term.Scrollback(1)
+	term.Write([]byte("\r\n"))
+	if term.vt.Scrollback() != 1 || term.view > 1 {
+		t.Errorf("%d lines kept, view %d back", term.vt.Scrollback(), term.view)
+	}
+}
+
+func TestTerminalAttach(t *testing.T) {
+	term, ctx, pty := newTerminal(t, 20, 5)
+	var exit []error
+	term.OnExit(func(err error) { exit = append(exit, err) })
+	go pty.prog.Write([]byte("hello\x1b[6n"))
+	runPosted(t, ctx)
+	if got := term.vt.Line(0).Cells[4].Rune; got != 'o' {
+		t.Errorf("the program's output shows %q at column 4", got)
+	}
+	if got := pty.sent(); got != "\x1b[1;6R" {
+		t.Errorf("replied %q, want the cursor position", got)
+	}
+
+	pty.prog.Close()
+	runPosted(t, ctx)
+	if len(exit) != 1 || exit[0] != io.EOF {
+		t.Errorf("OnExit got %v, want io.EOF", exit)
+	}
+
+	// Attaching again reads from the new program.
+	next := newFakePty(t)
+	term.Attach(next)
+	ctx.Invalidate()
+	ctx.LayoutRoot(term, term.Bounds())
+	go next.prog.Write([]byte("\r\nnext"))
+	runPosted(t, ctx)
+	term.HandleEvent(ctx, event.TextEvent{Text: "x"})
+	if got := term.vt.Line(1).Cells[0].Rune; got != 'n' || next.sent() != "x" {
+		t.Errorf("the new program's output shows %q", got)
+	}
+}
+
+func TestTerminalColors(t *testing.T) {
+	th := theme.From(core.NewContext())
+	term := NewTerminal()
+	p := DefaultTerminalPalette
+	tests := []struct {
+		name   string
+		style  vt.Style
+		fg, bg core.Color
+	}{
+		{"default", vt.Style{}, th.Colors.OnSurface, th.Colors.Surface},
+		{"ansi", vt.Style{FG: vt.Indexed(1), BG: vt.Indexed(4)}, p[1], p[4]},
+		{"bold brightens", vt.Style{FG: vt.Indexed(1), Attr: vt.Bold}, p[9], th.Colors.Surface},
+		{"bold bright", vt.Style{FG: vt.Indexed(9), Attr: vt.Bold}, p[9], th.Colors.Surface},
+		{"cube start", vt.Style{FG: vt.Indexed(16)}, core.RGB(0, 0, 0), th.Colors.Surface},
+		{"cube", vt.Style{FG: vt.Indexed(16 + 36*1 + 6*2 + 3)}, core.RGB(95, 135, 175), th.Colors.Surface},
+		{"cube end", vt.Style{FG: vt.Indexed(231)}, core.RGB(255, 255, 255), th.Colors.Surface},
+		{"gray", vt.Style{BG: vt.Indexed(232)}, th.Colors.OnSurface, core.RGB(8, 8, 8)},
+		{"last gray", vt.Style{BG: vt.Indexed(255)}, th.Colors.OnSurface, core.RGB(238, 238, 238)},
+		{"rgb", vt.Style{FG: vt.RGB(1, 2, 3), BG: vt.RGB(4, 5, 6)}, core.RGB(1, 2, 3), core.RGB(4, 5, 6)},
+		{"inverse", vt.Style{FG: vt.Indexed(1), Attr: vt.Inverse}, th.Colors.Surface, p[1]},
+		{"faint", vt.Style{Attr: vt.Faint}, th.Colors.OnSurface.WithAlpha(th.Colors.OnSurface.A * 0.6), th.Colors.Surface},
+		{"hidden", vt.Style{BG: vt.Indexed(2), Attr: vt.Hidden}, p[2], p[2]},
+	}
+	for _, tt := range tests {
+		if fg, bg := term.colors(th, tt.style); fg != tt.fg || bg != tt.bg {
+			t.Errorf("%s: colors %v on %v, want %v on %v", tt.name, fg, bg, tt.fg, tt.bg)
+		}
+	}
+
+	custom := DefaultTerminalPalette
+	custom[1] = core.Hex(0x123456)
+	term.Palette(custom)
+	if fg, _ := term.colors(th, vt.Style{FG: vt.Indexed(1)}); fg != core.Hex(0x123456) {
+		t.Errorf("the palette gave %v", fg)
+	}
+}
+
+func TestTerminalPaint(t *testing.T) {
+	paint := func(term *Terminal, ctx *core.Context) []string {
+		cv := &textCanvas{}
+		term.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+		var texts []string
+		for _, s := range cv.texts {
+			texts = append(texts, strings.TrimRight(s, " "))
+		}
+		return texts
+	}
+	tests := []struct {
+		name   string
+		output string
+		want   []string
+	}{
+		{"runs of one style", "\x1b[31mred\x1b[m ok", []string{"red", " ok"}},
+		{"wide characters alone", "a世界b", []string{"a世", "界", "b"}},
+		{"hidden", "\x1b[8msecret\x1b[m shown", []string{" shown"}},
+		{"cursor on a character", "ab\x1b[1;1H", []string{"ab", "a"}},
+		{"hidden cursor", "ab\x1b[1;1H\x1b[?25l", []string{"ab"}},
+		{"bar cursor", "ab\x1b[1;1H\x1b[5 q", []string{"ab"}},
+	}
+	for _, tt := range tests {
+		term, ctx, _ := newTerminal(t, 20, 2)
+		term.Write([]byte(tt.output))
+		if got := paint(term, ctx); !equalStrings(got, tt.want) {
+			t.Errorf("%s: drew %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	// The cursor is not drawn while scrolled back.
+	term, ctx, _ := newTerminal(t, 20, 2)
+	term.Write([]byte("a\r\nb\r\nc\x1b[1;1H"))
+	term.HandleEvent(ctx, event.ScrollEvent{Delta: core.Pt(0, -term.cellH)})
+	if got := paint(term, ctx); !equalStrings(got, []string{"a", "b"}) {
+		t.Errorf("scrolled back, drew %q", got)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,643 @@
+package widgets
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/gogpu/ui/bidi"
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/internal/textedit"
+	"github.com/gogpu/ui/paragraph"
+	"github.com/gogpu/ui/theme"
+)
+
+// Text displays text in a single style or, made with NewRichText, a tree
+// of spans mixing styles, links and widgets shown inline, laid out by
+// package paragraph. Text wraps where lines may be broken when the available
+// width is bounded, unless wrapping is disabled with Wrap(false), and
+// can be aligned, justified and hyphenated, and limited to a number of
+// lines ending in an ellipsis or a fade. Selectable text, and all text
+// inside a SelectionArea, can be selected and copied.
+type Text struct {
+	core.WidgetBase
+
+	text      string
+	size      float32
+	weight    core.FontWeight
+	family    string
+	color     core.Color
+	hasColor  bool
+	noWrap    bool
+	maxLines  int
+	align     paragraph.Align
+	overflow  paragraph.Overflow
+	hyphenate bool
+	locale    string
+
+	spans   []TextSpan
+	runs    spanRuns
+	pressed *TextSpan // The link pressed, until the button is released.
+
+	selectable bool
+	area       *SelectionArea // The enclosing SelectionArea, which handles selection.
+
+	para *paragraph.Paragraph
+
+	// sel holds the text shown and the selection in it. It is rebuilt
+	// from the paragraph when stale.
+	sel      textedit.Editor
+	bytes    []int // The byte offset in text of each position in sel.
+	starts   []int // The position in sel of each line.
+	stale    bool
+	dragging bool
+}
+
+// NewText returns a Text widget displaying s.
+func NewText(s string) *Text {
+	return &Text{text: s}
+}
+
+// NewRichText returns a Text widget displaying spans, in the style of
+// the Text overridden by the style of each span.
+func NewRichText(spans ...TextSpan) *Text {
+	t := &Text{}
+	t.SetSpans(spans...)
+	return t
+}
+
+// Text returns the displayed string, with U+FFFC OBJECT REPLACEMENT
+// CHARACTER for each inline widget.
+func (t *Text) Text() string {
+	return t.text
+}
+
+// SetText replaces the displayed string, and any spans.
+func (t *Text) SetText(s string) {
+	t.text = s
+	t.spans = nil
+	t.SetChildren()
+}
+
+// SetSpans replaces the displayed text with spans.
+func (t *Text) SetSpans(spans ...TextSpan) {
+	var b strings.Builder
+	spanText(&b, spans)
+	t.text = b.String()
+	t.spans = spans
+	t.SetChildren(spanWidgets(spans, nil)...)
+}
+
+// FontSize sets the font size. Zero uses the theme's body size.
+func (t *Text) FontSize(size float32) *Text {
+	t.size = size
+	return t
+}
+
+// Weight sets the font weight.
+func (t *Text) Weight(w core.FontWeight) *Text {
+	t.weight = w
+	return t
+}
+
+// Family sets the font family.
+func (t *Text) Family(family string) *Text {
+	t.family = family
+	return t
+}
+
+// Color sets the text color, overriding the theme's OnSurface color.
+func (t *Text) Color(c core.Color) *Text {
+	t.color = c
+	t.hasColor = true
+	return t
+}
+
+// Wrap enables or disables word wrapping. Wrapping is on by default.
+func (t *Text) Wrap(wrap bool) *Text {
+	t.noWrap = !wrap
+	return t
+}
+
+// MaxLines limits the number of displayed lines. Zero means unlimited.
+func (t *Text) MaxLines(n int) *Text {
+	t.maxLines = n
+	return t
+}
+
+// Align sets the alignment of the lines, paragraph.Start by default.
+// Justified text fills the width it is given.
+func (t *Text) Align(a paragraph.Align) *Text {
+	t.align = a
+	return t
+}
+
+// Overflow sets how text cut short by MaxLines, or too wide for a line
+// with wrapping disabled, ends: clipped, as by default, with an ellipsis
+// or faded out.
+func (t *Text) Overflow(o paragraph.Overflow) *Text {
+	t.overflow = o
+	return t
+}
+
+// Hyphenate enables hyphenating words that do not fit at the end of a
+// line, by the hyphenation patterns of the text's locale.
+func (t *Text) Hyphenate(on bool) *Text {
+	t.hyphenate = on
+	return t
+}
+
+// Locale sets the language of the text, such as "en-GB", which it is
+// hyphenated by. American English is the default.
+func (t *Text) Locale(locale string) *Text {
+	t.locale = locale
+	return t
+}
+
+// Selectable lets the text be selected with the mouse, by dragging,
+// double-clicking a word or triple-clicking a paragraph, and extended
+// with Shift and the arrow keys once clicked. Ctrl+C copies the
+// selection.
+func (t *Text) Selectable(on bool) *Text {
+	t.selectable = on
+	return t
+}
+
+// SelectedText returns the selected text, or "" if none is. Inline
+// widgets are left out.
+func (t *Text) SelectedText() string {
+	return strings.ReplaceAll(t.editor().SelectedText(), "\uFFFC", "")
+}
+
+// resolveStyle merges the explicit overrides with the theme defaults.
+func (t *Text) resolveStyle(ctx *core.Context) core.TextStyle {
+	th := theme.From(ctx)
+	style := th.Typography.Body
+	style.Color = th.Colors.OnSurface
+	if t.size > 0 {
+		style.Size = t.size
+	}
+	if t.weight != 0 {
+		style.Weight = t.weight
+	}
+	if t.family != "" {
+		style.Family = t.family
+	}
+	if t.hasColor {
+		style.Color = t.color
+	}
+	return style
+}
+
+// layout lays the text out in style at width, with the inline widgets
+// at the sizes last measured.
+func (t *Text) layout(ctx *core.Context, style core.TextStyle, width float32) *paragraph.Paragraph {
+	opts := paragraph.Options{
+		Width:     width,
+		NoWrap:    t.noWrap,
+		Align:     t.align,
+		Direction: ctx.Direction(),
+		MaxLines:  t.maxLines,
+		Overflow:  t.overflow,
+		Hyphenate: t.hyphenate,
+		Locale:    t.locale,
+	}
+	if t.spans == nil {
+		return paragraph.New(ctx, t.text, style, opts)
+	}
+	t.runs.reset()
+	t.runs.add(t.spans, style, 0, nil)
+	if len(t.runs.runs) == 0 {
+		return paragraph.New(ctx, "", style, opts)
+	}
+	return paragraph.NewRuns(ctx, t.runs.runs, opts)
+}
+
+// measureWidgets lays out the inline widgets for the text to be laid out
+// at width.
+func (t *Text) measureWidgets(ctx *core.LayoutContext, width float32) {
+	t.runs.sizes = t.runs.sizes[:0]
+	for _, w := range t.Children() {
+		t.runs.sizes = append(t.runs.sizes, ctx.Measure(w, core.Loose(core.Sz(width, core.Infinity))))
+	}
+}
+
+// Layout implements core.Widget.
+func (t *Text) Layout(ctx *core.LayoutContext) core.Size {
+	style := t.resolveStyle(ctx.Context)
+	t.measureWidgets(ctx, ctx.Constraints.MaxWidth)
+	t.para = t.layout(ctx.Context, style, ctx.Constraints.MaxWidth)
+	t.stale = true
+	return ctx.Constraints.Constrain(core.Size{Width: t.para.Width, Height: t.para.Height})
+}
+
+// SetBounds implements core.Widget, placing the inline widgets where the
+// text leaves room for them. Widgets cut off by MaxLines get empty bounds
+// and are not drawn.
+func (t *Text) SetBounds(r core.Rect) {
+	t.WidgetBase.SetBounds(r)
+	for i, w := range t.Children() {
+		var box core.Rect
+		if t.para != nil && i < len(t.runs.widgets) {
+			if rects := t.para.Rects(t.runs.widgets[i], r.Width); len(rects) > 0 {
+				box = rects[0].Translate(r.Origin())
+			}
+		}
+		w.SetBounds(box)
+	}
+}
+
+// IntrinsicWidth implements core.IntrinsicSizer: the longest word, or line
+// if wrapping is disabled, and the longest line.
+func (t *Text) IntrinsicWidth(ctx *core.LayoutContext, _ float32) (minWidth, maxWidth float32) {
+	style := t.resolveStyle(ctx.Context)
+	if t.spans != nil {
+		t.measureWidgets(ctx, core.Infinity)
+		maxWidth = t.layout(ctx.Context, style, core.Infinity).Width
+		if t.noWrap {
+			return maxWidth, maxWidth
+		}
+		return t.layout(ctx.Context, style, 0).Width, maxWidth
+	}
+	for _, p := range strings.Split(t.text, "\n") {
+		maxWidth = max(maxWidth, ctx.MeasureText(p, style).Width)
+		if t.noWrap {
+			continue
+		}
+		for _, word := range strings.Fields(p) {
+			minWidth = max(minWidth, ctx.MeasureText(word, style).Width)
+		}
+	}
+	if t.noWrap {
+		minWidth = maxWidth
+	}
+	return minWidth, maxWidth
+}
+
+// IntrinsicHeight implements core.IntrinsicSizer: the height of the text
+// laid out at width.
+func (t *Text) IntrinsicHeight(ctx *core.LayoutContext, width float32) (minHeight, maxHeight float32) {
+	t.measureWidgets(ctx, width)
+	h := t.layout(ctx.Context, t.resolveStyle(ctx.Context), width).Height
+	return h, h
+}
+
+// Baseline implements core.Baseliner.
+func (t *Text) Baseline() (float32, bool) {
+	if t.para == nil || len(t.para.Lines) == 0 {
+		return 0, false
+	}
+	return t.para.Lines[0].Ascent, true
+}
+
+// Paint implements core.Widget.
+func (t *Text) Paint(ctx *core.PaintContext) {
+	if t.para == nil {
+		return
+	}
+	if t.showsSelection(ctx.Context) {
+		t.paintSelection(ctx)
+	}
+	t.para.Paint(ctx.Canvas, t.Bounds())
+	for _, w := range t.Children() {
+		if !w.Bounds().IsEmpty() {
+			w.Paint(ctx)
+		}
+	}
+}
+
+// HandleEvent implements core.Widget.
+func (t *Text) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	if t.tap(ctx, ev) {
+		ctx.Repaint(t)
+		return core.Handled
+	}
+	if !t.selectable || t.area != nil {
+		return core.Ignored
+	}
+	e := t.editor()
+	switch ev := ev.(type) {
+	case event.MouseEvent:
+		switch ev.Type {
+		case event.MouseDown:
+			if ev.Button != event.ButtonLeft || !t.Bounds().Contains(ev.Position) {
+				return core.Ignored
+			}
+			ctx.RequestFocus(t)
+			i := t.offsetAt(ctx, ev.Position)
+			switch ev.ClickCount {
+			case 0, 1:
+				e.SetCaret(i, ev.Modifiers.Has(event.ModShift))
+				t.dragging = true
+				ctx.CapturePointer(t)
+			case 2:
+				e.Select(e.WordAt(i))
+			default:
+				e.Select(t.paragraphAt(i))
+			}
+		case event.MouseMove:
+			if !t.dragging {
+				return core.Ignored
+			}
+			e.SetCaret(t.offsetAt(ctx, ev.Position), true)
+		case event.MouseUp:
+			if !t.dragging {
+				return core.Ignored
+			}
+			t.dragging = false
+			ctx.ReleasePointer()
+		default:
+			return core.Ignored
+		}
+		ctx.Repaint(t)
+		return core.Handled
+	case event.KeyEvent:
+		if !ctx.IsFocused(t) || ev.Type != event.KeyPress {
+			return core.Ignored
+		}
+		switch {
+		case isCopy(ev):
+			if text := t.SelectedText(); text != "" {
+				clipboardFrom(ctx).WriteText(text)
+			}
+		case isSelectAll(ev):
+			e.SelectAll()
+		case ev.Modifiers.Has(event.ModShift):
+			i, ok := t.moveCaret(ctx, e.Caret(), ev)
+			if !ok {
+				return core.Ignored
+			}
+			e.SetCaret(i, true)
+		default:
+			return core.Ignored
+		}
+		ctx.Repaint(t)
+		return core.Handled
+	}
+	return core.Ignored
+}
+
+// tap handles clicks on links, calling OnTap when the button is released
+// over the link it was pressed on. It reports whether it handled ev.
+func (t *Text) tap(ctx *core.Context, ev core.Event) bool {
+	me, ok := ev.(event.MouseEvent)
+	if !ok {
+		return false
+	}
+	switch me.Type {
+	case event.MouseDown:
+		link := t.linkAt(me.Position)
+		if me.Button != event.ButtonLeft || link == nil {
+			return false
+		}
+		t.pressed = link
+		ctx.CapturePointer(t)
+		return true
+	case event.MouseUp:
+		link := t.pressed
+		if link == nil {
+			return false
+		}
+		t.pressed = nil
+		ctx.ReleasePointer()
+		if t.linkAt(me.Position) == link {
+			link.OnTap()
+		}
+		return true
+	}
+	return false
+}
+
+// linkAt returns the span with OnTap whose text is at p, or nil.
+func (t *Text) linkAt(p core.Point) *TextSpan {
+	if t.para == nil || t.spans == nil {
+		return nil
+	}
+	b := t.Bounds()
+	for k, link := range t.runs.links {
+		if link == nil {
+			continue
+		}
+		for _, r := range t.para.Rects(k, b.Width) {
+			if r.Translate(b.Origin()).Contains(p) {
+				return link
+			}
+		}
+	}
+	return nil
+}
+
+// isCopy reports whether ev is the copy shortcut, Ctrl+C or Command+C.
+func isCopy(ev event.KeyEvent) bool {
+	return ev.Key == event.KeyC && (ev.Modifiers == event.ModCtrl || ev.Modifiers == event.ModSuper)
+}
+
+// isSelectAll reports whether ev is Ctrl+A or Command+A.
+func isSelectAll(ev event.KeyEvent) bool {
+	return ev.Key == event.KeyA && (ev.Modifiers == event.ModCtrl || ev.Modifiers == event.ModSuper)
+}
+
+// editor returns the selection, rebuilt from the paragraph if it was
+// laid out again since it was, which clears it if the text shown changed.
+func (t *Text) editor() *textedit.Editor {
+	if !t.stale {
+		return &t.sel
+	}
+	t.stale = false
+	t.bytes, t.starts = t.bytes[:0], t.starts[:0]
+	end := 0
+	if t.para != nil && len(t.para.Lines) > 0 {
+		end = t.para.Lines[len(t.para.Lines)-1].End
+	}
+	shown := t.text[:end]
+	for i := range shown {
+		t.bytes = append(t.bytes, i)
+	}
+	t.bytes = append(t.bytes, end)
+	if t.para != nil {
+		for _, l := range t.para.Lines {
+			t.starts = append(t.starts, t.position(l.Start))
+		}
+	}
+	if shown != t.sel.Text() {
+		t.sel.SetText(shown)
+	}
+	return &t.sel
+}
+
+// position returns the selection position of byte offset i of the text.
+func (t *Text) position(i int) int {
+	return sort.SearchInts(t.bytes, i)
+}
+
+// showsSelection reports whether the selection is drawn, which it is
+// while the text or its SelectionArea has focus.
+func (t *Text) showsSelection(ctx *core.Context) bool {
+	if t.area != nil {
+		return ctx.IsFocused(t.area) && t.editor().HasSelection()
+	}
+	return t.selectable && ctx.IsFocused(t) && t.editor().HasSelection()
+}
+
+// lineOf returns the line that selection position i is on. A position
+// where a line is broken is on the line after it.
+func (t *Text) lineOf(i int) int {
+	n := 0
+	for n+1 < len(t.starts) && t.starts[n+1] <= i {
+		n++
+	}
+	return n
+}
+
+// caretX returns the offset from the left edge of the text of the caret
+// at selection position i on line n.
+func (t *Text) caretX(ctx *core.Context, n, i int) float32 {
+	return t.para.Offset(n, t.Bounds().Width) + t.para.X(ctx, n, t.bytes[i])
+}
+
+// offsetAt returns the selection position nearest p: the start of the
+// text above it and the end below it.
+func (t *Text) offsetAt(ctx *core.Context, p core.Point) int {
+	e := t.editor()
+	b := t.Bounds()
+	switch {
+	case len(t.starts) == 0 || p.Y < b.Y:
+		return 0
+	case p.Y >= b.Y+t.para.Height:
+		return e.Len()
+	}
+	n := t.para.LineAt(p.Y - b.Y)
+	return t.columnAt(ctx, n, p.X-b.X)
+}
+
+// columnAt returns the selection position of the cluster boundary of line
+// n nearest x, measured from the left edge of the text.
+func (t *Text) columnAt(ctx *core.Context, n int, x float32) int {
+	return t.position(t.para.OffsetAt(ctx, n, x-t.para.Offset(n, t.Bounds().Width)))
+}
+
+// paragraphAt returns the bounds of the paragraph around position i.
+func (t *Text) paragraphAt(i int) (start, end int) {
+	text := []rune(t.editor().Text())
+	start, end = i, i
+	for start > 0 && text[start-1] != '\n' {
+		start--
+	}
+	for end < len(text) && text[end] != '\n' {
+		end++
+	}
+	return start, end
+}
+
+// moveCaret returns where the Shift-modified key of ev moves the end of
+// a selection at caret: by cluster or, with Ctrl, by word for Left and Right,
+// to the line's ends for Home and End, and to the nearest position on
+// the line above or below for Up and Down. It reports false for other
+// keys and for moves that would leave the text.
+func (t *Text) moveCaret(ctx *core.Context, caret int, ev event.KeyEvent) (int, bool) {
+	e := t.editor()
+	if len(t.starts) == 0 {
+		return caret, false
+	}
+	n := t.lineOf(caret)
+	switch ev.Key {
+	case event.KeyLeft, event.KeyRight:
+		if ev.Key == event.KeyLeft && caret == 0 || ev.Key == event.KeyRight && caret == e.Len() {
+			return caret, false
+		}
+		moved := *e
+		moved.SetCaret(caret, false)
+		moved.HandleKey(ev)
+		return moved.Caret(), true
+	case event.KeyHome:
+		return t.starts[n], true
+	case event.KeyEnd:
+		l := t.para.Lines[n]
+		return t.position(l.Start + len(strings.TrimRightFunc(t.text[l.Start:l.End], unicode.IsSpace))), true
+	case event.KeyUp, event.KeyDown:
+		to := n - 1
+		if ev.Key == event.KeyDown {
+			to = n + 1
+		}
+		if to < 0 || to >= len(t.starts) {
+			return caret, false
+		}
+		return t.columnAt(ctx, to, t.caretX(ctx, n, caret)), true
+	}
+	return caret, false
+}
+
+// paintSelection highlights the selected part of each line, in pieces
+// where it crosses runs of both directions.
+func (t *Text) paintSelection(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	b := t.Bounds()
+	s, e := t.editor().Selection()
+	start, end := t.bytes[s], t.bytes[e]
+	for n := range t.para.Lines {
+		x := b.X + t.para.Offset(n, b.Width)
+		y := b.Y + t.para.Lines[n].Y
+		for _, r := range t.para.Highlight(ctx.Context, n, start, end) {
+			ctx.Canvas.DrawRect(r.Translate(core.Pt(x, y)), core.Filled(th.Colors.Selection))
+		}
+	}
+}
+
+// drawLine draws a line of text in the reading direction of ctx. Text
+// that mixes left-to-right and right-to-left scripts is reordered by the
+// Unicode bidirectional algorithm and drawn one directional run at a
+// time, left to right, so the canvas only has to lay out each run in the
+// direction of its script.
+func drawLine(ctx *core.PaintContext, line string, pos core.Point, style core.TextStyle) {
+	rtl := ctx.Direction().IsRTL()
+	if !rtl && !bidi.HasRTL(line) {
+		ctx.Canvas.DrawText(line, pos, style)
+		return
+	}
+	base := bidi.LeftToRight
+	if rtl {
+		base = bidi.RightToLeft
+	}
+	text := []rune(line)
+	runs := bidi.Resolve(text, base).Runs(0, len(text))
+	if len(runs) <= 1 {
+		ctx.Canvas.DrawText(line, pos, style)
+		return
+	}
+	for _, r := range runs {
+		s := string(text[r.Start:r.End])
+		ctx.Canvas.DrawText(s, pos, style)
+		pos.X += ctx.MeasureText(s, style).Width
+	}
+}
+
+// wrapText splits text into lines no wider than maxWidth, breaking at
+// spaces. Explicit newlines always break. A single word wider than maxWidth
+// is kept on its own line.
+func wrapText(ctx *core.Context, text string, style core.TextStyle, maxWidth float32) []string {
+	paragraphs := strings.Split(text, "\n")
+	if maxWidth >= core.Infinity {
+		return paragraphs
+	}
+	lines := make([]string, 0, len(paragraphs))
+	for _, p := range paragraphs {
+		words := strings.Fields(p)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+		line := words[0]
+		for _, word := range words[1:] {
+			candidate := line + " " + word
+			if ctx.MeasureText(candidate, style).Width > maxWidth {
+				lines = append(lines, line)
+				line = word
+				continue
+			}
+			line = candidate
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,270 @@
+package widgets
+
+import (
+	"errors"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/internal/textedit"
+	"github.com/gogpu/ui/state"
+	"github.com/gogpu/ui/theme"
+)
+
+// textFieldWidth is the width of a TextField unless set with Width.
+const textFieldWidth float32 = 200
+
+// errIncomplete is reported for text that does not fill a mask.
+var errIncomplete = errors.New("incomplete")
+
+// TextField is a single-line text input.
+//
+// A Mask or Formatter can rewrite the text as the user types, for example
+// to insert the separators of a phone number, without moving the caret
+// relative to what was typed. A validator checks the text after every
+// edit; the field is outlined as invalid once the user has committed a
+// rejected text with Enter or by leaving the field. Text composed with
+// the platform's input method, as for Chinese, Japanese and Korean, is
+// shown underlined at the caret until it is committed.
+type TextField struct {
+	core.WidgetBase
+	core.FocusState
+
+	line      textedit.Line
+	width     float32
+	mask      *Mask
+	validate  func(string) error
+	onChange  func(string)
+	onSubmit  func(string)
+	err       error
+	touched   bool
+	edited    bool
+	lastValue string
+
+	sig    *state.Signal[string]
+	cancel func()
+}
+
+// NewTextField returns an empty TextField.
+func NewTextField() *TextField {
+	return &TextField{width: textFieldWidth}
+}
+
+// Placeholder sets the hint shown while the field is empty.
+func (f *TextField) Placeholder(s string) *TextField {
+	f.line.Placeholder = s
+	return f
+}
+
+// Width sets the field's width.
+func (f *TextField) Width(w float32) *TextField {
+	f.width = w
+	return f
+}
+
+// Mask restricts the text to the pattern of m and inserts its literals as
+// the user types. Text that does not fill the mask is invalid, unless it
+// is empty. A nil mask removes it. Mask replaces any Formatter.
+func (f *TextField) Mask(m *Mask) *TextField {
+	f.mask = m
+	f.line.Format = nil
+	if m != nil {
+		f.line.Format = m.Format
+	}
+	f.reformat()
+	return f
+}
+
+// Formatter sets a function that rewrites the text after every edit. It
+// replaces any Mask.
+func (f *TextField) Formatter(fn Formatter) *TextField {
+	f.mask = nil
+	f.line.Format = fn
+	f.reformat()
+	return f
+}
+
+// Validator sets a function that reports why a text is not acceptable, or
+// nil if it is.
+func (f *TextField) Validator(fn func(string) error) *TextField {
+	f.validate = fn
+	f.check()
+	return f
+}
+
+// Bind keeps the text in sync with sig in both directions.
+func (f *TextField) Bind(sig *state.Signal[string]) *TextField {
+	f.Release()
+	f.sig = sig
+	return f
+}
+
+// Release stops following the bound signal, for when the field leaves
+// the tree. Laid out again, the field follows the signal from its value
+// then.
+func (f *TextField) Release() {
+	if f.cancel != nil {
+		f.cancel()
+		f.cancel = nil
+	}
+}
+
+// OnChange registers fn to be called after every edit.
+func (f *TextField) OnChange(fn func(string)) *TextField {
+	f.onChange = fn
+	return f
+}
+
+// OnSubmit registers fn to be called when the user presses Enter with a
+// valid text.
+func (f *TextField) OnSubmit(fn func(string)) *TextField {
+	f.onSubmit = fn
+	return f
+}
+
+// Text returns the text as shown, including any mask literals.
+func (f *TextField) Text() string {
+	return f.line.Text()
+}
+
+// RawText returns the text without the literals of the mask, or the text
+// itself if there is no mask.
+func (f *TextField) RawText() string {
+	if f.mask == nil {
+		return f.line.Text()
+	}
+	return f.mask.Raw(f.line.Text())
+}
+
+// SetText replaces the text, passing it through the mask or formatter,
+// without calling OnChange.
+func (f *TextField) SetText(s string) {
+	f.line.SetText(s)
+	f.reformat()
+	f.lastValue = f.line.Text()
+	if f.sig != nil {
+		f.sig.Set(f.lastValue)
+	}
+}
+
+// Err returns the validation error for the current text, or nil.
+func (f *TextField) Err() error {
+	return f.err
+}
+
+// IsInvalid reports whether the field is shown as invalid: the text was
+// rejected and the user has committed it.
+func (f *TextField) IsInvalid() bool {
+	return f.touched && f.err != nil
+}
+
+func (f *TextField) reformat() {
+	if f.line.Format != nil {
+		text, caret := f.line.Format(f.line.Text(), f.line.Len())
+		f.line.Replace(text, caret)
+	}
+	f.check()
+}
+
+func (f *TextField) check() {
+	text := f.line.Text()
+	f.err = nil
+	if f.mask != nil && text != "" && !f.mask.Complete(text) {
+		f.err = errIncomplete
+	}
+	if f.err == nil && f.validate != nil {
+		f.err = f.validate(text)
+	}
+}
+
+// edit records a change made by the user.
+func (f *TextField) edit(ctx *core.Context) {
+	f.check()
+	f.edited = true
+	f.lastValue = f.line.Text()
+	if f.sig != nil {
+		f.sig.Set(f.lastValue)
+	}
+	ctx.MarkNeedsLayout(f)
+	if f.onChange != nil {
+		f.onChange(f.lastValue)
+	}
+}
+
+// Layout implements core.Widget.
+func (f *TextField) Layout(ctx *core.LayoutContext) core.Size {
+	if f.sig != nil {
+		if f.cancel == nil {
+			c := ctx.Context
+			f.cancel = f.sig.Subscribe(func(string) { c.Post(func() { c.MarkNeedsLayout(f) }) })
+		}
+		if v := f.sig.Get(); v != f.lastValue {
+			f.SetText(v)
+		}
+	}
+	if f.edited && !f.IsFocused() {
+		// Focus has left the field since it was edited.
+		f.edited, f.touched = false, true
+	}
+	f.line.Style = theme.From(ctx.Context).Typography.Body
+	return ctx.Constraints.Constrain(core.Sz(f.width, fieldHeight))
+}
+
+// SetBounds implements core.Widget.
+func (f *TextField) SetBounds(r core.Rect) {
+	f.WidgetBase.SetBounds(r)
+	f.line.Rect = core.R(r.X+fieldPadding, r.Y, max(0, r.Width-2*fieldPadding), r.Height)
+}
+
+// Paint implements core.Widget.
+func (f *TextField) Paint(ctx *core.PaintContext) {
+	paintField(ctx, f.Bounds(), f.IsFocused(), f.IsInvalid())
+	f.line.Paint(ctx, f.IsFocused())
+}
+
+// HandleEvent implements core.Widget.
+func (f *TextField) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	switch e := ev.(type) {
+	case event.MouseEvent:
+		if e.Type == event.MouseDown && e.Button == event.ButtonLeft {
+			ctx.RequestFocus(f)
+		}
+		if f.line.HandleEvent(ctx, f, ev) != textedit.Ignored {
+			return core.Handled
+		}
+	case event.KeyEvent:
+		if !f.IsFocused() || e.Type != event.KeyPress {
+			return core.Ignored
+		}
+		if f.line.Composing() {
+			return core.Handled
+		}
+		if e.Key == event.KeyEnter {
+			f.edited, f.touched = false, true
+			ctx.MarkNeedsLayout(f)
+			if f.err == nil && f.onSubmit != nil {
+				f.onSubmit(f.line.Text())
+			}
+			return core.Handled
+		}
+		if r := f.line.HandleEvent(ctx, f, ev); r != textedit.Ignored {
+			if r == textedit.Changed {
+				f.edit(ctx)
+			}
+			return core.Handled
+		}
+	case event.TextEvent, event.CompositionEvent:
+		if !f.IsFocused() {
+			return core.Ignored
+		}
+		if f.line.HandleEvent(ctx, f, ev) == textedit.Changed {
+			f.edit(ctx)
+		}
+		return core.Handled
+	}
+	return core.Ignored
+}
+
+// TextInputRect implements core.TextInput.
+func (f *TextField) TextInputRect(ctx *core.Context) core.Rect {
+	return f.line.CaretRect(ctx)
+}
@@ -0,0 +1,162 @@
+package widgets
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/state"
+)
+
+// newTextField returns a focused field laid out in a 200 by 32 box.
+func newTextField(f *TextField) *core.Context {
+	ctx := layoutAt(f, core.R(0, 0, 200, 32))
+	ctx.RequestFocus(f)
+	return ctx
+}
+
+func TestTextFieldMask(t *testing.T) {
+	f := NewTextField().Mask(PhoneMask)
+	var changes []string
+	f.OnChange(func(s string) { changes = append(changes, s) })
+	ctx := newTextField(f)
+	for _, r := range "555x1234" {
+		f.HandleEvent(ctx, event.TextEvent{Text: string(r)})
+	}
+	if f.Text() != "(555) 123-4" || f.RawText() != "5551234" {
+		t.Errorf("Text() = %q, RawText() = %q", f.Text(), f.RawText())
+	}
+	if len(changes) != 7 {
+		t.Errorf("%d changes, want one per accepted digit", len(changes))
+	}
+	// Backspace over "-" deletes the 3 before it.
+	f.line.SetCaret(10, false)
+	f.HandleEvent(ctx, press(event.KeyBackspace, 0))
+	if f.Text() != "(555) 124" {
+		t.Errorf("Text() = %q after Backspace over the separator", f.Text())
+	}
+	if !errors.Is(f.Err(), errIncomplete) {
+		t.Errorf("Err() = %v for a partial number", f.Err())
+	}
+
+	f.SetText("5551234567")
+	if f.Text() != "(555) 123-4567" || f.Err() != nil {
+		t.Errorf("SetText gave %q, %v", f.Text(), f.Err())
+	}
+	f.Mask(nil)
+	if f.RawText() != f.Text() || f.line.Format != nil {
+		t.Error("removing the mask kept it")
+	}
+	f.Formatter(FormatIPv4)
+	if f.Text() != "55.51.234.56" {
+		t.Errorf("Text() = %q after switching to FormatIPv4", f.Text())
+	}
+}
+
+func TestTextFieldValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		commit    func(ctx *core.Context, f *TextField)
+		invalid   bool
+		submitted bool
+	}{
+		{"not committed", "1.2", func(*core.Context, *TextField) {}, false, false},
+		{"enter", "1.2", func(ctx *core.Context, f *TextField) { f.HandleEvent(ctx, press(event.KeyEnter, 0)) }, true, false},
+		{"focus left", "1.2", func(ctx *core.Context, f *TextField) {
+			ctx.RequestFocus(nil)
+			ctx.LayoutRoot(f, f.Bounds())
+		}, true, false},
+		{"valid", "10.0.0.1", func(ctx *core.Context, f *TextField) { f.HandleEvent(ctx, press(event.KeyEnter, 0)) }, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			submitted := ""
+			f := NewTextField().Validator(ValidateIPv4).OnSubmit(func(s string) { submitted = s })
+			ctx := newTextField(f)
+			if f.Err() == nil || f.IsInvalid() {
+				t.Fatal("an empty untouched field should fail quietly")
+			}
+			f.HandleEvent(ctx, event.TextEvent{Text: tt.text})
+			tt.commit(ctx, f)
+			if f.IsInvalid() != tt.invalid {
+				t.Errorf("IsInvalid() = %v, want %v", f.IsInvalid(), tt.invalid)
+			}
+			if (submitted != "") != tt.submitted {
+				t.Errorf("submitted %q", submitted)
+			}
+		})
+	}
+}
+
+func TestTextFieldFocus(t *testing.T) {
+	f := NewTextField()
+	ctx := layoutAt(f, core.R(0, 0, 200, 32))
+	if r := f.HandleEvent(ctx, event.TextEvent{Text: "a"}); r != core.Ignored || f.Text() != "" {
+		t.Error("a field without focus took text")
+	}
+	f.HandleEvent(ctx, leftMouse(event.MouseDown, core.Pt(20, 16)))
+	if !f.IsFocused() {
+		t.Fatal("pressing the field did not focus it")
+	}
+	f.HandleEvent(ctx, leftMouse(event.MouseUp, core.Pt(20, 16)))
+	f.HandleEvent(ctx, event.TextEvent{Text: "ab"})
+	if f.Text() != "ab" {
+		t.Errorf("Text() = %q", f.Text())
+	}
+}
+
+func TestTextFieldBind(t *testing.T) {
+	sig := state.New("5551234567")
+	f := NewTextField().Mask(PhoneMask).Bind(sig)
+	ctx := newTextField(f)
+	if f.Text() != "(555) 123-4567" || sig.Get() != f.Text() {
+		t.Fatalf("Text() = %q, signal %q", f.Text(), sig.Get())
+	}
+	f.HandleEvent(ctx, press(event.KeyBackspace, 0))
+	if sig.Get() != "(555) 123-456" {
+		t.Errorf("signal holds %q after Backspace", sig.Get())
+	}
+	sig.Set("1")
+	ctx.RunPosted()
+	ctx.LayoutRoot(f, f.Bounds())
+	if f.Text() != "(1" {
+		t.Errorf("Text() = %q after the signal changed", f.Text())
+	}
+}
+
+func TestTextFieldComposition(t *testing.T) {
+	var changes []string
+	submitted := false
+	f := NewTextField().OnChange(func(s string) { changes = append(changes, s) }).OnSubmit(func(string) { submitted = true })
+	ctx := newTextField(f)
+	caret := f.TextInputRect(ctx)
+
+	// The candidate window goes by the caret as it moves through the text
+	// composed, and Enter is the input method's.
+	f.HandleEvent(ctx, event.CompositionEvent{Type: event.CompositionStart, Text: "にほん", Cursor: 3})
+	if got := f.TextInputRect(ctx); got.X <= caret.X || got.Y != caret.Y {
+		t.Errorf("input rect %v composing, want after %v", got, caret)
+	}
+	if r := f.HandleEvent(ctx, press(event.KeyEnter, 0)); r != core.Handled || submitted {
+		t.Errorf("Enter while composing = %v, submitted %v", r, submitted)
+	}
+	if f.Text() != "" || len(changes) != 0 {
+		t.Errorf("text %q with %d changes before the commit", f.Text(), len(changes))
+	}
+	f.HandleEvent(ctx, event.CompositionEvent{Type: event.CompositionEnd})
+	f.HandleEvent(ctx, event.TextEvent{Text: "日本"})
+	if f.Text() != "日本" || len(changes) != 1 || submitted {
+		t.Errorf("text %q with changes %q after the commit", f.Text(), changes)
+	}
+	if got := f.TextInputRect(ctx); got.X <= caret.X {
+		t.Errorf("input rect %v after the commit, want after the text", got)
+	}
+
+	// Without focus, compositions are not the field's.
+	ctx.RequestFocus(nil)
+	if r := f.HandleEvent(ctx, event.CompositionEvent{Type: event.CompositionStart, Text: "か", Cursor: 1}); r != core.Ignored || f.line.Composing() {
+		t.Errorf("composition without focus = %v", r)
+	}
+}
@@ -0,0 +1,234 @@
+package widgets
+
+import (
+	"errors"
+	"net/netip"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Formatter rewrites the text of a TextField after every edit. caret is
+// the caret position in runes; the formatter returns the new text and the
+// position the caret belongs at in it, so that rewriting does not make the
+// caret jump.
+type Formatter func(text string, caret int) (string, int)
+
+// significant reports whether r is kept when text is reformatted, as
+// opposed to a separator the formatter inserts.
+func significant(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// Reformat returns a Formatter applying fn, which rewrites a whole string.
+// The caret stays after the same number of letters and digits, so the
+// separators fn inserts or removes do not move it.
+func Reformat(fn func(string) string) Formatter {
+	return func(text string, caret int) (string, int) {
+		n := 0
+		for _, r := range []rune(text)[:caret] {
+			if significant(r) {
+				n++
+			}
+		}
+		out := []rune(fn(text))
+		return string(out), caretAfter(out, n, significant)
+	}
+}
+
+// caretAfter returns the position just after the n-th rune of text that
+// keep accepts, or before the first such rune when n is zero.
+func caretAfter(text []rune, n int, keep func(rune) bool) int {
+	if n == 0 {
+		for i, r := range text {
+			if keep(r) {
+				return i
+			}
+		}
+		return len(text)
+	}
+	for i, r := range text {
+		if keep(r) {
+			n--
+			if n == 0 {
+				return i + 1
+			}
+		}
+	}
+	return len(text)
+}
+
+// Mask is a fixed input pattern such as a phone number or a date. In the
+// pattern, 9 stands for a digit, a for a letter and * for a letter or a
+// digit; any other character is a literal, and a backslash makes the next
+// character literal. Literals are inserted as the user types, and typed
+// characters that do not fit the next slot are dropped.
+type Mask struct {
+	slots []maskSlot
+}
+
+type maskSlot struct {
+	literal rune
+	accept  func(rune) bool
+}
+
+// Common masks.
+var (
+	PhoneMask = NewMask("(999) 999-9999")
+	DateMask  = NewMask("99/99/9999")
+	TimeMask  = NewMask("99:99")
+)
+
+// NewMask returns the mask for pattern.
+func NewMask(pattern string) *Mask {
+	m := &Mask{}
+	rs := []rune(pattern)
+	for i := 0; i < len(rs); i++ {
+		switch r := rs[i]; r {
+		case '9':
+			m.slots = append(m.slots, maskSlot{accept: unicode.IsDigit})
+		case 'a':
+			m.slots = append(m.slots, maskSlot{accept: unicode.IsLetter})
+		case '*':
+			m.slots = append(m.slots, maskSlot{accept: significant})
+		case '\\':
+			if i+1 < len(rs) {
+				i++
+				m.slots = append(m.slots, maskSlot{literal: rs[i]})
+			}
+		default:
+			m.slots = append(m.slots, maskSlot{literal: r})
+		}
+	}
+	return m
+}
+
+// Raw returns the characters of text that fill the mask's slots, without
+// the literals.
+func (m *Mask) Raw(text string) string {
+	raw, _ := m.fill(text, 0)
+	return string(raw)
+}
+
+// Complete reports whether text fills every slot of the mask.
+func (m *Mask) Complete(text string) bool {
+	n := 0
+	for _, s := range m.slots {
+		if s.accept != nil {
+			n++
+		}
+	}
+	raw, _ := m.fill(text, 0)
+	return len(raw) == n
+}
+
+// fill returns the characters of text that fit the slots in order, and
+// how many of them come before caret.
+func (m *Mask) fill(text string, caret int) (raw []rune, before int) {
+	slot := m.nextSlot(0)
+	for i, r := range []rune(text) {
+		if slot < 0 {
+			break
+		}
+		if !m.slots[slot].accept(r) {
+			continue
+		}
+		raw = append(raw, r)
+		if i < caret {
+			before++
+		}
+		slot = m.nextSlot(slot + 1)
+	}
+	return raw, before
+}
+
+func (m *Mask) nextSlot(i int) int {
+	for ; i < len(m.slots); i++ {
+		if m.slots[i].accept != nil {
+			return i
+		}
+	}
+	return -1
+}
+
+// Format implements Formatter. Literals are written up to the last
+// filled slot, so the separator after a group appears once the next
+// character is typed.
+func (m *Mask) Format(text string, caret int) (string, int) {
+	raw, before := m.fill(text, caret)
+	if len(raw) == 0 {
+		return "", 0
+	}
+	var out []rune
+	pos := -1
+	filled := 0
+	end := 0 // length of out up to the last filled slot
+	for _, s := range m.slots {
+		if s.accept == nil {
+			out = append(out, s.literal)
+			continue
+		}
+		if filled == len(raw) {
+			break
+		}
+		if filled == before && pos < 0 {
+			pos = len(out)
+		}
+		out = append(out, raw[filled])
+		filled++
+		end = len(out)
+		if filled == before {
+			pos = len(out)
+		}
+	}
+	out = out[:end]
+	if pos < 0 || pos > end {
+		pos = end
+	}
+	return string(out), pos
+}
+
+// FormatIPv4 is a Formatter for dotted IPv4 addresses. It drops characters
+// other than digits and dots, starts the next group when a group cannot
+// take another digit, and ignores input after the fourth group is full.
+func FormatIPv4(text string, caret int) (string, int) {
+	in := []rune(text)
+	var groups []string
+	cur := ""
+	before := 0
+	for i, r := range in {
+		switch {
+		case r >= '0' && r <= '9':
+			if n, _ := strconv.Atoi(cur + string(r)); len(cur) == 3 || n > 255 || cur == "0" {
+				if len(groups) == 3 {
+					continue
+				}
+				groups, cur = append(groups, cur), ""
+			}
+			cur += string(r)
+			if i < caret {
+				before++
+			}
+		case r == '.' && cur != "" && len(groups) < 3:
+			groups, cur = append(groups, cur), ""
+		}
+	}
+	// A group ended by a typed dot leaves cur empty and the dot showing.
+	out := strings.Join(append(groups, cur), ".")
+	rs := []rune(out)
+	pos := caretAfter(rs, before, func(r rune) bool { return r != '.' })
+	if caret > 0 && caret <= len(in) && in[caret-1] == '.' && pos < len(rs) && rs[pos] == '.' {
+		pos++
+	}
+	return out, pos
+}
+
+// ValidateIPv4 reports an error unless s is a complete dotted IPv4
+// address.
+func ValidateIPv4(s string) error {
+	a, err := netip.ParseAddr(strings.TrimSpace(s))
+	if err != nil || !a.Is4() {
+		return errors.New("not an IPv4 address")
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package widgets
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMaskFormat(t *testing.T) {
+	tests := []struct {
+		name      string
+		mask      *Mask
+		text      string
+		caret     int
+		want      string
+		wantCaret int
+	}{
+		{"empty", PhoneMask, "", 0, "", 0},
+		{"first digit", PhoneMask, "5", 1, "(5", 2},
+		{"group ends without its separator", PhoneMask, "555", 3, "(555", 4},
+		{"separators appear with the next digit", PhoneMask, "5551", 4, "(555) 1", 7},
+		{"retyped in place", PhoneMask, "(555) 1234", 10, "(555) 123-4", 11},
+		{"letters are dropped", PhoneMask, "55a5", 4, "(555", 4},
+		{"full", PhoneMask, "555123456789", 12, "(555) 123-4567", 14},
+		{"caret in the middle", PhoneMask, "(555) 1x23", 7, "(555) 123", 7},
+		{"caret before the first digit", DateMask, "12", 0, "12", 0},
+		{"date", DateMask, "12312024", 8, "12/31/2024", 10},
+		{"letters and escapes", NewMask(`aa\9-*`), "AB7c", 4, "AB9-7", 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, caret := tt.mask.Format(tt.text, tt.caret)
+			if got != tt.want || caret != tt.wantCaret {
+				t.Errorf("Format(%q, %d) = %q, %d, want %q, %d", tt.text, tt.caret, got, caret, tt.want, tt.wantCaret)
+			}
+		})
+	}
+}
+
+func TestMaskRawAndComplete(t *testing.T) {
+	tests := []struct {
+		text     string
+		raw      string
+		complete bool
+	}{
+		{"(555) 123-4567", "5551234567", true},
+		{"(555) 123-4", "5551234", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		if got := PhoneMask.Raw(tt.text); got != tt.raw {
+			t.Errorf("Raw(%q) = %q, want %q", tt.text, got, tt.raw)
+		}
+		if got := PhoneMask.Complete(tt.text); got != tt.complete {
+			t.Errorf("Complete(%q) = %v", tt.text, got)
+		}
+	}
+}
+
+func TestReformat(t *testing.T) {
+	upperDashed := Reformat(func(s string) string {
+		s = strings.ToUpper(strings.ReplaceAll(s, "-", ""))
+		var b strings.Builder
+		for i, r := range s {
+			if i > 0 && i%4 == 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+		}
+		return b.String()
+	})
+	tests := []struct {
+		text      string
+		caret     int
+		want      string
+		wantCaret int
+	}{
+		{"abcde", 5, "ABCD-E", 6},
+		{"abcde", 4, "ABCD-E", 4},
+		{"ab-cdef", 3, "ABCD-EF", 2},
+		{"abcd", 0, "ABCD", 0},
+	}
+	for _, tt := range tests {
+		got, caret := upperDashed(tt.text, tt.caret)
+		if got != tt.want || caret != tt.wantCaret {
+			t.Errorf("Reformat(%q, %d) = %q, %d, want %q, %d", tt.text, tt.caret, got, caret, tt.want, tt.wantCaret)
+		}
+	}
+}
+
+func TestFormatIPv4(t *testing.T) {
+	tests := []struct {
+		text      string
+		caret     int
+		want      string
+		wantCaret int
+	}{
+		{"1921681", 7, "192.168.1", 9},
+		{"192.", 4, "192.", 4},
+		{"256", 3, "25.6", 4},
+		{"01", 2, "0.1", 3},
+		{"1..2", 4, "1.2", 3},
+		{".1", 2, "1", 1},
+		{"1.2.3.4.5", 9, "1.2.3.45", 8},
+		{"255255255255255", 15, "255.255.255.255", 15},
+		{"1a2", 3, "12", 2},
+	}
+	for _, tt := range tests {
+		got, caret := FormatIPv4(tt.text, tt.caret)
+		if got != tt.want || caret != tt.wantCaret {
+			t.Errorf("FormatIPv4(%q, %d) = %q, %d, want %q, %d", tt.text, tt.caret, got, caret, tt.want, tt.wantCaret)
+		}
+	}
+}
+
+func TestValidateIPv4(t *testing.T) {
+	tests := []struct {
+		in string
+		ok bool
+	}{
+		{"192.168.0.1", true},
+		{" 10.0.0.1 ", true},
+		{"192.168.0", false},
+		{"::1", false},
+		{"256.0.0.1", false},
+	}
+	for _, tt := range tests {
+		if err := ValidateIPv4(tt.in); (err == nil) != tt.ok {
+			t.Errorf("ValidateIPv4(%q) = %v", tt.in, err)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package widgets
+
+import (
+	"strings"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/paragraph"
+)
+
+// TextSpan is a piece of rich text for NewRichText: text in a style,
+// a widget shown inline, or a group of spans sharing a style, which each
+// of them may override:
+//
+//	widgets.NewRichText(
+//		widgets.TextSpan{Text: "Read the "},
+//		widgets.TextSpan{Text: "guide", Style: widgets.SpanStyle{Underline: true}, OnTap: openGuide},
+//		widgets.TextSpan{Text: " or press "},
+//		widgets.TextSpan{Widget: widgets.NewChip("F1")},
+//	)
+type TextSpan struct {
+	Text  string
+	Style SpanStyle
+
+	// Children follow Text, in its style unless they set their own.
+	Children []TextSpan
+
+	// Widget, if set, is shown inline in place of Text, at the size it
+	// lays out at, standing on the baseline. It receives events like any
+	// child.
+	Widget core.Widget
+
+	// OnTap, if set, makes the span and its children a link, called
+	// when it is clicked.
+	OnTap func()
+}
+
+// SpanStyle is the style a TextSpan sets. Zero fields keep the style of
+// the enclosing span, or of the Text, and flags add to it.
+type SpanStyle struct {
+	Family string
+	Size   float32
+	Weight core.FontWeight
+	Color  core.Color
+
+	Italic        bool
+	Underline     bool
+	Strikethrough bool
+}
+
+// apply returns st with the fields s sets changed.
+func (s SpanStyle) apply(st core.TextStyle, deco paragraph.Decoration) (core.TextStyle, paragraph.Decoration) {
+	if s.Family != "" {
+		st.Family = s.Family
+	}
+	if s.Size > 0 {
+		st.Size = s.Size
+	}
+	if s.Weight != 0 {
+		st.Weight = s.Weight
+	}
+	if s.Color != (core.Color{}) {
+		st.Color = s.Color
+	}
+	st.Italic = st.Italic || s.Italic
+	if s.Underline {
+		deco |= paragraph.Underline
+	}
+	if s.Strikethrough {
+		deco |= paragraph.Strikethrough
+	}
+	return st, deco
+}
+
+// spanRuns flattens a tree of spans into the runs of a paragraph.
+type spanRuns struct {
+	runs    []paragraph.Run
+	links   []*TextSpan // The span with OnTap each run is part of, or nil.
+	sizes   []core.Size // The size of each inline widget, in order.
+	widgets []int       // The run of each inline widget.
+}
+
+// reset starts a new set of runs, keeping the sizes. The runs are not
+// reused, as a paragraph laid out from them may still be shown.
+func (f *spanRuns) reset() {
+	f.runs, f.links, f.widgets = nil, nil, f.widgets[:0]
+}
+
+// add appends the runs of spans, in a style derived from st and deco,
+// as part of link.
+func (f *spanRuns) add(spans []TextSpan, st core.TextStyle, deco paragraph.Decoration, link *TextSpan) {
+	for i := range spans {
+		s := &spans[i]
+		st, deco := s.Style.apply(st, deco)
+		link := link
+		if s.OnTap != nil {
+			link = s
+		}
+		switch {
+		case s.Widget != nil:
+			var size core.Size
+			if n := len(f.widgets); n < len(f.sizes) {
+				size = f.sizes[n]
+			}
+			f.widgets = append(f.widgets, len(f.runs))
+			f.runs = append(f.runs, paragraph.Run{Style: st, Box: true, Size: size})
+			f.links = append(f.links, link)
+		case s.Text != "":
+			f.runs = append(f.runs, paragraph.Run{Text: s.Text, Style: st, Decoration: deco})
+			f.links = append(f.links, link)
+		}
+		f.add(s.Children, st, deco, link)
+	}
+}
+
+// spanText appends the text of spans to b, with U+FFFC for each widget.
+func spanText(b *strings.Builder, spans []TextSpan) {
+	for _, s := range spans {
+		if s.Widget != nil {
+			b.WriteString("\uFFFC")
+		} else {
+			b.WriteString(s.Text)
+		}
+		spanText(b, s.Children)
+	}
+}
+
+// spanWidgets appends the inline widgets of spans to out, in order.
+func spanWidgets(spans []TextSpan, out []core.Widget) []core.Widget {
+	for _, s := range spans {
+		if s.Widget != nil {
+			out = append(out, s.Widget)
+		}
+		out = spanWidgets(s.Children, out)
+	}
+	return out
+}
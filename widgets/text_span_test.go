@@ -0,0 +1,128 @@
+package widgets
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/paragraph"
+)
+
+func TestSpanRuns(t *testing.T) {
+	red, blue := core.RGB(255, 0, 0), core.RGB(0, 0, 255)
+	base := core.TextStyle{Size: 14, Color: red}
+	var tapped string
+	tap := func(s string) func() { return func() { tapped = s } }
+	spans := []TextSpan{
+		{Text: "plain "},
+		{Text: "bold ", Style: SpanStyle{Weight: core.WeightBold}, Children: []TextSpan{
+			{Text: "big ", Style: SpanStyle{Size: 20, Underline: true}, Children: []TextSpan{
+				{Text: "blue ", Style: SpanStyle{Color: blue, Italic: true}},
+			}},
+			{Text: "still bold "},
+		}},
+		{Text: "link ", OnTap: tap("outer"), Children: []TextSpan{
+			{Text: "in link "},
+			{Text: "inner ", OnTap: tap("inner")},
+			{Text: "after inner "},
+		}},
+		{Text: "after link"},
+		{Children: []TextSpan{{Text: " nested"}}},
+	}
+	type run struct {
+		text   string
+		size   float32
+		weight core.FontWeight
+		color  core.Color
+		italic bool
+		deco   paragraph.Decoration
+		link   string
+	}
+	bold := core.WeightBold
+	want := []run{
+		{"plain ", 14, 0, red, false, 0, ""},
+		{"bold ", 14, bold, red, false, 0, ""},
+		{"big ", 20, bold, red, false, paragraph.Underline, ""},
+		{"blue ", 20, bold, blue, true, paragraph.Underline, ""},
+		{"still bold ", 14, bold, red, false, 0, ""},
+		{"link ", 14, 0, red, false, 0, "outer"},
+		{"in link ", 14, 0, red, false, 0, "outer"},
+		{"inner ", 14, 0, red, false, 0, "inner"},
+		{"after inner ", 14, 0, red, false, 0, "outer"},
+		{"after link", 14, 0, red, false, 0, ""},
+		{" nested", 14, 0, red, false, 0, ""},
+	}
+
+	var f spanRuns
+	f.add(spans, base, 0, nil)
+	var got []run
+	for k, r := range f.runs {
+		link := ""
+		if l := f.links[k]; l != nil {
+			tapped = ""
+			l.OnTap()
+			link = tapped
+		}
+		got = append(got, run{r.Text, r.Style.Size, r.Style.Weight, r.Style.Color, r.Style.Italic, r.Decoration, link})
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("runs:\n%+v\nwant:\n%+v", got, want)
+	}
+}
+
+// tapAt clicks txt at p.
+func tapAt(ctx *core.Context, txt *Text, p core.Point) {
+	txt.HandleEvent(ctx, event.MouseEvent{Type: event.MouseDown, Button: event.ButtonLeft, Position: p, ClickCount: 1})
+	txt.HandleEvent(ctx, event.MouseEvent{Type: event.MouseUp, Button: event.ButtonLeft, Position: p})
+}
+
+func TestRichTextTap(t *testing.T) {
+	var taps []string
+	txt := NewRichText(
+		TextSpan{Text: "Read "},
+		TextSpan{Text: "guide", OnTap: func() { taps = append(taps, "guide") }, Children: []TextSpan{
+			{Text: " book", Style: SpanStyle{Underline: true}},
+		}},
+		TextSpan{Text: " or press nothing here"},
+	)
+	ctx := core.NewContext()
+	lc := &core.LayoutContext{Context: ctx}
+	size := lc.Measure(txt, core.Loose(core.Sz(core.Infinity, core.Infinity)))
+	txt.SetBounds(core.R(10, 20, size.Width, size.Height))
+
+	// center returns the middle of run k of the text.
+	center := func(k int) core.Point {
+		rects := txt.para.Rects(k, size.Width)
+		if len(rects) == 0 {
+			t.Fatalf("run %d has no rects", k)
+		}
+		return rects[0].Translate(core.Pt(10, 20)).Center()
+	}
+	tests := []struct {
+		name string
+		at   core.Point
+		want []string
+	}{
+		{"plain text before", center(0), nil},
+		{"link", center(1), []string{"guide"}},
+		{"child of the link", center(2), []string{"guide"}},
+		{"plain text after", center(3), nil},
+		{"outside", core.Pt(5, 5), nil},
+	}
+	for _, tt := range tests {
+		taps = nil
+		tapAt(ctx, txt, tt.at)
+		if !slices.Equal(taps, tt.want) {
+			t.Errorf("%s: tapped %q, want %q", tt.name, taps, tt.want)
+		}
+	}
+
+	// Released off the link it was pressed on, a click taps nothing.
+	taps = nil
+	txt.HandleEvent(ctx, event.MouseEvent{Type: event.MouseDown, Button: event.ButtonLeft, Position: center(1), ClickCount: 1})
+	txt.HandleEvent(ctx, event.MouseEvent{Type: event.MouseUp, Button: event.ButtonLeft, Position: center(3)})
+	if taps != nil {
+		t.Errorf("released elsewhere: tapped %q", taps)
+	}
+}
@@ -0,0 +1,96 @@
+package widgets
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+)
+
+// measure returns the size of w laid out under loose constraints of max.
+func measure(w core.Widget, max core.Size) core.Size {
+	lc := &core.LayoutContext{Context: core.NewContext()}
+	return lc.Measure(w, core.Loose(max))
+}
+
+func TestTextLayout(t *testing.T) {
+	const words = "one two three four five six seven eight"
+	line := measure(NewText(words), core.Sz(core.Infinity, core.Infinity))
+	tests := []struct {
+		name  string
+		text  *Text
+		width float32
+		check func(core.Size) bool
+	}{
+		{"one line when unbounded", NewText(words), core.Infinity, func(s core.Size) bool { return s == line }},
+		{"wraps when narrow", NewText(words), 60, func(s core.Size) bool { return s.Width <= 60 && s.Height > 2*line.Height }},
+		{"no wrap", NewText(words).Wrap(false), 60, func(s core.Size) bool { return s.Height == line.Height }},
+		{"max lines", NewText(words).MaxLines(2), 60, func(s core.Size) bool { return s.Height < 2.5*line.Height }},
+		{"font size", NewText(words).FontSize(28), core.Infinity, func(s core.Size) bool { return s.Width == 2*line.Width }},
+		{"empty", NewText(""), core.Infinity, func(s core.Size) bool { return s.Width == 0 }},
+	}
+	if line.Width <= 0 || line.Height <= 0 {
+		t.Fatalf("line size = %v", line)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := measure(tt.text, core.Sz(tt.width, core.Infinity)); !tt.check(got) {
+				t.Errorf("size = %v, one line is %v", got, line)
+			}
+		})
+	}
+}
+
+func TestTextSetText(t *testing.T) {
+	txt := NewText("a")
+	short := measure(txt, core.Sz(core.Infinity, core.Infinity))
+	txt.SetText("a longer text")
+	if got := txt.Text(); got != "a longer text" {
+		t.Errorf("Text() = %q", got)
+	}
+	if got := measure(txt, core.Sz(core.Infinity, core.Infinity)); got.Width <= short.Width {
+		t.Errorf("width = %v after SetText, was %v", got.Width, short.Width)
+	}
+	if b, ok := txt.Baseline(); !ok || b <= 0 || b >= short.Height {
+		t.Errorf("Baseline() = %v, %v within a line %v high", b, ok, short.Height)
+	}
+}
+
+func TestTextIntrinsic(t *testing.T) {
+	lc := &core.LayoutContext{Context: core.NewContext()}
+	width := func(s string) float32 {
+		return measure(NewText(s), core.Sz(core.Infinity, core.Infinity)).Width
+	}
+	tests := []struct {
+		name     string
+		text     *Text
+		min, max float32
+	}{
+		{"words", NewText("a bb three"), width("three"), width("a bb three")},
+		{"lines", NewText("a longer line\nshort"), width("longer"), width("a longer line")},
+		{"no wrap", NewText("a bb three").Wrap(false), width("a bb three"), width("a bb three")},
+		{"empty", NewText(""), 0, 0},
+	}
+	for _, tt := range tests {
+		if lo, hi := tt.text.IntrinsicWidth(lc, core.Infinity); lo != tt.min || hi != tt.max {
+			t.Errorf("%s: IntrinsicWidth = %v, %v, want %v, %v", tt.name, lo, hi, tt.min, tt.max)
+		}
+	}
+
+	// The intrinsic height is the height the text is laid out to.
+	const words = "one two three four five six seven eight"
+	for _, tt := range []struct {
+		name  string
+		text  *Text
+		width float32
+	}{
+		{"one line", NewText(words), core.Infinity},
+		{"wrapped", NewText(words), 60},
+		{"no wrap", NewText(words).Wrap(false), 60},
+		{"max lines", NewText(words).MaxLines(2), 60},
+	} {
+		want := measure(tt.text, core.Sz(tt.width, core.Infinity)).Height
+		if lo, hi := tt.text.IntrinsicHeight(lc, tt.width); lo != want || hi != want {
+			t.Errorf("%s: IntrinsicHeight = %v, %v, want %v", tt.name, lo, hi, want)
+		}
+	}
+}
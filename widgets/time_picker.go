@@ -0,0 +1,349 @@
+package widgets
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/theme"
+)
+
+// timeSegmentKind identifies a part of a TimePicker.
+type timeSegmentKind uint8
+
+const (
+	segmentHour timeSegmentKind = iota
+	segmentMinute
+	segmentPeriod
+)
+
+// TimePicker is a time-of-day field made of separately focusable hour,
+// minute and, in 12-hour mode, AM/PM segments.
+//
+// Tab and the Left and Right arrows move between segments. Up and Down
+// step the focused segment, minutes by the minute step, wrapping around.
+// Digits are typed into the focused segment, which advances to the next
+// one once it is complete; A and P choose the period.
+type TimePicker struct {
+	core.WidgetBase
+
+	hour, minute int
+	twelve       bool
+	step         int
+	onChange     func(hour, minute int)
+
+	segments []*timeSegment
+	gap      float32
+	typed    string
+}
+
+// NewTimePicker returns a 24-hour TimePicker set to midnight.
+func NewTimePicker() *TimePicker {
+	p := &TimePicker{step: 1}
+	p.buildSegments()
+	return p
+}
+
+// TwelveHour switches between the 12-hour format with an AM/PM segment and
+// the 24-hour format.
+func (p *TimePicker) TwelveHour(on bool) *TimePicker {
+	p.twelve = on
+	p.buildSegments()
+	return p
+}
+
+// MinuteStep sets the granularity of minutes, for example 15 for quarter
+// hours. Stepping moves by it and typed minutes are rounded to it.
+func (p *TimePicker) MinuteStep(step int) *TimePicker {
+	p.step = max(1, min(step, 60))
+	p.minute = p.snap(p.minute)
+	return p
+}
+
+// OnChange registers fn to be called when the user changes the time. hour
+// is always in 24-hour form.
+func (p *TimePicker) OnChange(fn func(hour, minute int)) *TimePicker {
+	p.onChange = fn
+	return p
+}
+
+// Time returns the hour, from 0 to 23, and the minute.
+func (p *TimePicker) Time() (hour, minute int) {
+	return p.hour, p.minute
+}
+
+// SetTime sets the time without calling OnChange. Out-of-range values wrap.
+func (p *TimePicker) SetTime(hour, minute int) {
+	total := ((hour*60+minute)%(24*60) + 24*60) % (24 * 60)
+	p.hour, p.minute = total/60, p.snap(total%60)
+}
+
+func (p *TimePicker) snap(m int) int {
+	m = (m + p.step/2) / p.step * p.step
+	if m >= 60 {
+		m -= p.step
+	}
+	return m
+}
+
+func (p *TimePicker) buildSegments() {
+	kinds := []timeSegmentKind{segmentHour, segmentMinute}
+	if p.twelve {
+		kinds = append(kinds, segmentPeriod)
+	}
+	p.segments = p.segments[:0]
+	for _, k := range kinds {
+		p.segments = append(p.segments, &timeSegment{picker: p, kind: k})
+	}
+}
+
+// Children implements core.Parent.
+func (p *TimePicker) Children() []core.Widget {
+	children := make([]core.Widget, len(p.segments))
+	for i, s := range p.segments {
+		children[i] = s
+	}
+	return children
+}
+
+func (p *TimePicker) text(k timeSegmentKind) string {
+	switch k {
+	case segmentHour:
+		h := p.hour
+		if p.twelve {
+			h = (h+11)%12 + 1
+		}
+		return fmt.Sprintf("%02d", h)
+	case segmentMinute:
+		return fmt.Sprintf("%02d", p.minute)
+	}
+	if p.hour < 12 {
+		return "AM"
+	}
+	return "PM"
+}
+
+// Layout implements core.Widget.
+func (p *TimePicker) Layout(ctx *core.LayoutContext) core.Size {
+	style := theme.From(ctx.Context).Typography.Body
+	digits := ctx.MeasureText("00", style).Width + 4
+	period := ctx.MeasureText("PM", style).Width + 4
+	p.gap = ctx.MeasureText(":", style).Width + 2
+	w := 2 * fieldPadding
+	for i, s := range p.segments {
+		s.style = style
+		s.width = digits
+		if s.kind == segmentPeriod {
+			s.width = period
+		}
+		if i > 0 {
+			w += p.gap
+		}
+		w += s.width
+	}
+	return ctx.Constraints.Constrain(core.Sz(w, fieldHeight))
+}
+
+// SetBounds implements core.Widget.
+func (p *TimePicker) SetBounds(r core.Rect) {
+	p.WidgetBase.SetBounds(r)
+	x := r.X + fieldPadding
+	for i, s := range p.segments {
+		if i > 0 {
+			x += p.gap
+		}
+		s.SetBounds(core.R(x, r.Y+4, s.width, r.Height-8))
+		x += s.width
+	}
+}
+
+// Paint implements core.Widget.
+func (p *TimePicker) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	focused := false
+	for _, s := range p.segments {
+		focused = focused || s.IsFocused()
+	}
+	paintField(ctx, p.Bounds(), focused, false)
+	for i, s := range p.segments {
+		s.Paint(ctx)
+		if i == 0 {
+			b := s.Bounds()
+			style := theme.TextStyle(s.style, th.Colors.OnSurface)
+			ctx.Canvas.DrawText(":", core.Pt(b.Right()+1, b.Y+(b.Height-style.LineHeight())/2), style)
+		}
+	}
+}
+
+// HandleEvent implements core.Widget. A press on the field outside the
+// segments focuses the hour.
+func (p *TimePicker) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	if e, ok := ev.(event.MouseEvent); ok && e.Type == event.MouseDown && e.Button == event.ButtonLeft && len(p.segments) > 0 {
+		ctx.RequestFocus(p.segments[0])
+		return core.Handled
+	}
+	return core.Ignored
+}
+
+func (p *TimePicker) change(ctx *core.Context, hour, minute int) {
+	old := p.hour*60 + p.minute
+	p.SetTime(hour, minute)
+	ctx.MarkNeedsLayout(p)
+	if p.hour*60+p.minute != old && p.onChange != nil {
+		p.onChange(p.hour, p.minute)
+	}
+}
+
+// focusSegment moves focus by delta segments, reporting whether it moved.
+func (p *TimePicker) focusSegment(ctx *core.Context, from *timeSegment, delta int) bool {
+	for i, s := range p.segments {
+		if s == from {
+			j := i + delta
+			if j < 0 || j >= len(p.segments) {
+				return false
+			}
+			ctx.RequestFocus(p.segments[j])
+			return true
+		}
+	}
+	return false
+}
+
+// stepSegment steps the segment by delta.
+func (p *TimePicker) stepSegment(ctx *core.Context, k timeSegmentKind, delta int) {
+	switch k {
+	case segmentHour:
+		p.change(ctx, p.hour+delta, p.minute)
+	case segmentMinute:
+		m := (p.minute + delta*p.step + 60) % 60
+		p.change(ctx, p.hour, m)
+	case segmentPeriod:
+		p.change(ctx, p.hour+12, p.minute)
+	}
+}
+
+// typeDigit applies a typed digit to the segment and reports whether the
+// segment is complete.
+func (p *TimePicker) typeDigit(ctx *core.Context, k timeSegmentKind, d rune) bool {
+	p.typed += string(d)
+	n, _ := strconv.Atoi(p.typed)
+	switch k {
+	case segmentHour:
+		maxHour := 23
+		if p.twelve {
+			maxHour = 12
+		}
+		if n > maxHour {
+			p.typed = string(d)
+			n = int(d - '0')
+		}
+		h := n
+		if p.twelve {
+			h = n % 12
+			if p.hour >= 12 {
+				h += 12
+			}
+		}
+		p.change(ctx, h, p.minute)
+		return len(p.typed) == 2 || n*10 > maxHour
+	case segmentMinute:
+		if n > 59 {
+			p.typed = string(d)
+			n = int(d - '0')
+		}
+		p.change(ctx, p.hour, n)
+		return len(p.typed) == 2 || n*10 > 59
+	}
+	return false
+}
+
+// timeSegment is one focusable part of a TimePicker.
+type timeSegment struct {
+	core.WidgetBase
+	core.FocusState
+
+	picker *TimePicker
+	kind   timeSegmentKind
+	width  float32
+	style  core.TextStyle
+}
+
+func (s *timeSegment) Layout(ctx *core.LayoutContext) core.Size {
+	return core.Sz(s.width, ctx.Constraints.MaxHeight)
+}
+
+// Blur ends digit entry so the next digit typed starts afresh.
+func (s *timeSegment) Blur() {
+	s.FocusState.Blur()
+	s.picker.typed = ""
+}
+
+func (s *timeSegment) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	b := s.Bounds()
+	color := th.Colors.OnSurface
+	if s.IsFocused() {
+		ctx.Canvas.DrawRoundedRect(b, th.Radii.Small, core.Filled(th.Colors.Primary))
+		color = th.Colors.OnPrimary
+	}
+	style := theme.TextStyle(s.style, color)
+	text := s.picker.text(s.kind)
+	w := ctx.MeasureText(text, style).Width
+	ctx.Canvas.DrawText(text, core.Pt(b.X+(b.Width-w)/2, b.Y+(b.Height-style.LineHeight())/2), style)
+}
+
+func (s *timeSegment) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	p := s.picker
+	switch e := ev.(type) {
+	case event.MouseEvent:
+		if e.Type == event.MouseDown && e.Button == event.ButtonLeft {
+			ctx.RequestFocus(s)
+			return core.Handled
+		}
+	case event.KeyEvent:
+		if e.Type != event.KeyPress || !s.IsFocused() || e.Modifiers&^event.ModShift != 0 {
+			return core.Ignored
+		}
+		switch e.Key {
+		case event.KeyUp, event.KeyDown:
+			p.typed = ""
+			if e.Key == event.KeyUp {
+				p.stepSegment(ctx, s.kind, 1)
+			} else {
+				p.stepSegment(ctx, s.kind, -1)
+			}
+		case event.KeyLeft:
+			if !p.focusSegment(ctx, s, -1) {
+				return core.Ignored
+			}
+		case event.KeyRight:
+			if !p.focusSegment(ctx, s, 1) {
+				return core.Ignored
+			}
+		case event.KeyBackspace:
+			p.typed = ""
+		default:
+			return core.Ignored
+		}
+		return core.Handled
+	case event.TextEvent:
+		if !s.IsFocused() {
+			return core.Ignored
+		}
+		for _, r := range e.Text {
+			switch {
+			case r >= '0' && r <= '9' && s.kind != segmentPeriod:
+				if p.typeDigit(ctx, s.kind, r) {
+					p.typed = ""
+					p.focusSegment(ctx, s, 1)
+				}
+			case s.kind == segmentPeriod && (r == 'a' || r == 'A') && p.hour >= 12,
+				s.kind == segmentPeriod && (r == 'p' || r == 'P') && p.hour < 12:
+				p.stepSegment(ctx, segmentPeriod, 1)
+			}
+		}
+		return core.Handled
+	}
+	return core.Ignored
+}
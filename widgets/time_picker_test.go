@@ -0,0 +1,127 @@
+package widgets
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+)
+
+func TestTimePickerSetTime(t *testing.T) {
+	tests := []struct {
+		name                 string
+		step                 int
+		hour, minute         int
+		wantHour, wantMinute int
+	}{
+		{"plain", 1, 9, 30, 9, 30},
+		{"minutes wrap into hours", 1, 9, 75, 10, 15},
+		{"past midnight", 1, 25, 0, 1, 0},
+		{"negative", 1, 0, -1, 23, 59},
+		{"rounded to the step", 15, 9, 37, 9, 30},
+		{"rounded up to the step", 15, 9, 38, 9, 45},
+		{"never rounded to the next hour", 15, 9, 58, 9, 45},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewTimePicker().MinuteStep(tt.step)
+			p.SetTime(tt.hour, tt.minute)
+			if h, m := p.Time(); h != tt.wantHour || m != tt.wantMinute {
+				t.Errorf("Time() = %d:%02d, want %d:%02d", h, m, tt.wantHour, tt.wantMinute)
+			}
+		})
+	}
+}
+
+func TestTimePickerText(t *testing.T) {
+	tests := []struct {
+		hour                 int
+		twelve               bool
+		hourText, periodText string
+	}{
+		{0, false, "00", "AM"},
+		{13, false, "13", "PM"},
+		{0, true, "12", "AM"},
+		{12, true, "12", "PM"},
+		{15, true, "03", "PM"},
+	}
+	for _, tt := range tests {
+		p := NewTimePicker().TwelveHour(tt.twelve)
+		p.SetTime(tt.hour, 5)
+		if got := p.text(segmentHour); got != tt.hourText {
+			t.Errorf("hour %d: %q, want %q", tt.hour, got, tt.hourText)
+		}
+		if got := p.text(segmentPeriod); got != tt.periodText {
+			t.Errorf("hour %d: period %q, want %q", tt.hour, got, tt.periodText)
+		}
+		if got := p.text(segmentMinute); got != "05" {
+			t.Errorf("minute %q, want 05", got)
+		}
+	}
+	if n := len(NewTimePicker().TwelveHour(true).Children()); n != 3 {
+		t.Errorf("%d segments in 12-hour mode, want 3", n)
+	}
+}
+
+func TestTimePickerInput(t *testing.T) {
+	text := func(s string) event.TextEvent { return event.TextEvent{Text: s} }
+	tests := []struct {
+		name          string
+		twelve        bool
+		step          int
+		events        []core.Event
+		hour, minute  int
+		focusedMinute bool
+	}{
+		{"up", false, 1, []core.Event{press(event.KeyUp, 0)}, 10, 30, false},
+		{"down wraps", false, 1, []core.Event{text("0"), text("0"), press(event.KeyLeft, 0), press(event.KeyDown, 0)}, 23, 30, false},
+		{"minutes step", false, 15, []core.Event{press(event.KeyRight, 0), press(event.KeyUp, 0)}, 9, 45, true},
+		{"minutes wrap", false, 15, []core.Event{press(event.KeyRight, 0), press(event.KeyUp, 0), press(event.KeyUp, 0)}, 9, 0, true},
+		{"two digits advance", false, 1, []core.Event{text("1"), text("7")}, 17, 30, true},
+		{"a large digit advances", false, 1, []core.Event{text("5")}, 5, 30, true},
+		{"a digit too many starts over", false, 1, []core.Event{text("2"), text("5")}, 5, 30, true},
+		{"typing minutes", false, 1, []core.Event{text("08"), text("45")}, 8, 45, true},
+		{"backspace restarts entry", false, 1, []core.Event{text("1"), press(event.KeyBackspace, 0), text("2")}, 2, 30, false},
+		{"twelve hour keeps the period", true, 1, []core.Event{press(event.KeyRight, 0), press(event.KeyRight, 0), text("p"), press(event.KeyLeft, 0), press(event.KeyLeft, 0), text("12")}, 12, 30, true},
+		{"morning", true, 1, []core.Event{text("12")}, 0, 30, true},
+		{"period key", true, 1, []core.Event{press(event.KeyRight, 0), press(event.KeyRight, 0), press(event.KeyDown, 0)}, 21, 30, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewTimePicker().TwelveHour(tt.twelve).MinuteStep(tt.step)
+			p.SetTime(9, 30)
+			changes := 0
+			p.OnChange(func(int, int) { changes++ })
+			ctx := layoutAt(p, core.R(0, 0, 200, 32))
+			p.HandleEvent(ctx, event.MouseEvent{Type: event.MouseDown, Button: event.ButtonLeft})
+			for _, ev := range tt.events {
+				ctx.Focused().HandleEvent(ctx, ev)
+			}
+			if h, m := p.Time(); h != tt.hour || m != tt.minute {
+				t.Errorf("Time() = %d:%02d, want %d:%02d", h, m, tt.hour, tt.minute)
+			}
+			if got := ctx.Focused() == p.segments[segmentMinute]; got != tt.focusedMinute {
+				t.Errorf("minutes focused = %v, want %v", got, tt.focusedMinute)
+			}
+			if changes == 0 {
+				t.Error("OnChange was not called")
+			}
+		})
+	}
+}
+
+func TestTimePickerSegmentEdges(t *testing.T) {
+	p := NewTimePicker()
+	ctx := layoutAt(p, core.R(0, 0, 200, 32))
+	ctx.RequestFocus(p.segments[0])
+	if got := p.segments[0].HandleEvent(ctx, press(event.KeyLeft, 0)); got != core.Ignored {
+		t.Errorf("Left on the first segment = %v, want Ignored", got)
+	}
+	ctx.RequestFocus(p.segments[1])
+	if got := p.segments[1].HandleEvent(ctx, press(event.KeyRight, 0)); got != core.Ignored {
+		t.Errorf("Right on the last segment = %v, want Ignored", got)
+	}
+	if got := p.segments[1].HandleEvent(ctx, press(event.KeyUp, event.ModCtrl)); got != core.Ignored {
+		t.Errorf("Ctrl+Up = %v, want Ignored", got)
+	}
+}
@@ -0,0 +1,504 @@
+package widgets
+
+import (
+	"slices"
+	"time"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/theme"
+)
+
+const (
+	// ToastDefaultDuration is how long a toast stays visible unless
+	// Duration overrides it.
+	ToastDefaultDuration = 4 * time.Second
+
+	toastEnter  = 200 * time.Millisecond
+	toastExit   = 150 * time.Millisecond
+	toastSlide  = 16
+	toastMargin = 16
+	toastGap    = 8
+	toastLimit  = 3
+
+	toastMinWidth   float32 = 288
+	toastMaxWidth   float32 = 480
+	toastPaddingX   float32 = 16
+	toastPaddingY   float32 = 14
+	toastActionPad  float32 = 8
+	toastCloseSize  float32 = 24
+	toastActionsGap float32 = 8
+)
+
+// ToastCorner selects where in the window toasts are stacked.
+type ToastCorner uint8
+
+const (
+	ToastBottomRight ToastCorner = iota
+	ToastBottomLeft
+	ToastBottomCenter
+	ToastTopRight
+	ToastTopLeft
+	ToastTopCenter
+)
+
+func (c ToastCorner) top() bool {
+	return c >= ToastTopRight
+}
+
+type toastAction struct {
+	label   string
+	onClick func()
+	rect    core.Rect
+}
+
+// Toast is a brief notification with optional action buttons, shown with
+// ShowToast in a stack at one corner of the window. Toasts slide in, close
+// themselves after their duration and fade out; the pointer resting on a
+// toast keeps it open. With reduced motion they appear and disappear
+// without animation.
+type Toast struct {
+	core.WidgetBase
+
+	message   string
+	actions   []*toastAction
+	closable  bool
+	duration  time.Duration
+	onDismiss func()
+
+	style     core.TextStyle
+	lines     []string
+	closeRect core.Rect
+	hover     int
+	press     int
+	alpha     float32
+
+	host        *toastHost
+	shownAt     time.Time
+	dismissedAt time.Time
+	dismissed   bool
+	gen         int
+}
+
+// NewToast returns a toast displaying message.
+func NewToast(message string) *Toast {
+	return &Toast{message: message, duration: ToastDefaultDuration, hover: -1, press: -1, alpha: 1}
+}
+
+// Message returns the displayed text.
+func (t *Toast) Message() string {
+	return t.message
+}
+
+// Action adds a button labeled label that dismisses the toast and calls fn.
+func (t *Toast) Action(label string, fn func()) *Toast {
+	t.actions = append(t.actions, &toastAction{label: label, onClick: fn})
+	return t
+}
+
+// Closable adds a close button.
+func (t *Toast) Closable(closable bool) *Toast {
+	t.closable = closable
+	return t
+}
+
+// Duration sets how long the toast stays visible once shown. Zero keeps it
+// until it is dismissed.
+func (t *Toast) Duration(d time.Duration) *Toast {
+	t.duration = d
+	return t
+}
+
+// OnDismiss registers fn to be called when the toast is dismissed for any
+// reason.
+func (t *Toast) OnDismiss(fn func()) *Toast {
+	t.onDismiss = fn
+	return t
+}
+
+// IsDismissed reports whether the toast was dismissed.
+func (t *Toast) IsDismissed() bool {
+	return t.dismissed
+}
+
+// Dismiss closes the toast, or removes it from the queue if it has not
+// been shown yet.
+func (t *Toast) Dismiss() {
+	if t.dismissed {
+		return
+	}
+	t.dismissed = true
+	t.dismissedAt = time.Now()
+	t.gen++
+	if t.onDismiss != nil {
+		t.onDismiss()
+	}
+	if t.host != nil {
+		t.host.dismissed(t)
+	}
+}
+
+// startTimer arms the auto-dismiss timer.
+func (t *Toast) startTimer() {
+	if t.duration <= 0 || t.dismissed || t.host == nil {
+		return
+	}
+	t.gen++
+	gen, ctx := t.gen, t.host.ctx
+	time.AfterFunc(t.duration, func() {
+		ctx.Post(func() {
+			if t.gen == gen {
+				t.Dismiss()
+			}
+		})
+	})
+}
+
+// presence returns how far the toast has entered, from 0 to 1, falling
+// back to 0 as it exits. ok is false once the exit has finished.
+func (t *Toast) presence(ctx *core.Context) (p float32, ok bool) {
+	if ctx.ReducedMotion() {
+		return 1, !t.dismissed
+	}
+	now := ctx.Now()
+	if t.dismissed {
+		p = 1 - float32(now.Sub(t.dismissedAt))/float32(toastExit)
+		if p <= 0 {
+			return 0, false
+		}
+		return easeOut(p), true
+	}
+	p = float32(now.Sub(t.shownAt)) / float32(toastEnter)
+	return easeOut(min(max(p, 0), 1)), true
+}
+
+func easeOut(p float32) float32 {
+	q := 1 - p
+	return 1 - q*q*q
+}
+
+// Layout implements core.Widget.
+func (t *Toast) Layout(ctx *core.LayoutContext) core.Size {
+	th := theme.From(ctx.Context)
+	t.style = theme.TextStyle(th.Typography.Body, th.Colors.Surface)
+	label := th.Typography.Label
+	var extra float32
+	for _, a := range t.actions {
+		w := ctx.MeasureText(a.label, label).Width + 2*toastActionPad
+		a.rect = core.Rect{Width: w, Height: label.LineHeight() + 2*toastActionPad}
+		extra += w + toastActionsGap
+	}
+	if t.closable {
+		extra += toastCloseSize + toastActionsGap
+	}
+	maxWidth := min(ctx.Constraints.MaxWidth, toastMaxWidth)
+	t.lines = wrapText(ctx.Context, t.message, t.style, max(maxWidth-2*toastPaddingX-extra, 0))
+	var text float32
+	for _, line := range t.lines {
+		text = max(text, ctx.MeasureText(line, t.style).Width)
+	}
+	w := min(max(text+extra+2*toastPaddingX, toastMinWidth), maxWidth)
+	h := max(float32(len(t.lines))*t.style.LineHeight(), label.LineHeight()+2*toastActionPad) + 2*toastPaddingY
+	return ctx.Constraints.Constrain(core.Sz(w, h))
+}
+
+// SetBounds implements core.Widget.
+func (t *Toast) SetBounds(r core.Rect) {
+	t.WidgetBase.SetBounds(r)
+	x := r.Right() - toastPaddingX
+	if t.closable {
+		x -= toastCloseSize
+		t.closeRect = core.R(x, r.Y+(r.Height-toastCloseSize)/2, toastCloseSize, toastCloseSize)
+		x -= toastActionsGap
+	}
+	for i := len(t.actions) - 1; i >= 0; i-- {
+		a := t.actions[i]
+		x -= a.rect.Width
+		a.rect.X, a.rect.Y = x, r.Y+(r.Height-a.rect.Height)/2
+		x -= toastActionsGap
+	}
+}
+
+// Paint implements core.Widget.
+func (t *Toast) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	b := t.Bounds()
+	fade := func(c core.Color) core.Color { return c.WithAlpha(c.A * t.alpha) }
+	for _, s := range th.Elevation(theme.ElevationMenu) {
+		s.Color = fade(s.Color)
+		core.DrawShadow(cv, b, th.Radii.Small, s)
+	}
+	cv.DrawRoundedRect(b, th.Radii.Small, core.Filled(fade(th.Colors.OnSurface)))
+	style := t.style
+	style.Color = fade(style.Color)
+	lh := style.LineHeight()
+	y := b.Y + (b.Height-float32(len(t.lines))*lh)/2
+	for i, line := range t.lines {
+		drawLine(ctx, line, core.Pt(b.X+toastPaddingX, y+float32(i)*lh), style)
+	}
+	label := theme.TextStyle(th.Typography.Label, fade(th.Colors.PrimaryContainer))
+	for i, a := range t.actions {
+		if i == t.hover {
+			cv.DrawRoundedRect(a.rect, th.Radii.Small, core.Filled(fade(th.Colors.Surface.WithAlpha(0.12))))
+		}
+		cv.DrawText(a.label, core.Pt(a.rect.X+toastActionPad, a.rect.Y+toastActionPad), label)
+	}
+	if t.closable {
+		if t.hover == len(t.actions) {
+			cv.DrawRoundedRect(t.closeRect, th.Radii.Small, core.Filled(fade(th.Colors.Surface.WithAlpha(0.12))))
+		}
+		c := t.closeRect.Center()
+		const s = 4
+		x := core.NewPath().
+			MoveTo(core.Pt(c.X-s, c.Y-s)).LineTo(core.Pt(c.X+s, c.Y+s)).
+			MoveTo(core.Pt(c.X+s, c.Y-s)).LineTo(core.Pt(c.X-s, c.Y+s))
+		cv.DrawPath(x, core.PathStyle{Stroke: fade(th.Colors.Surface), StrokeWidth: 1.5, LineCap: core.CapRound})
+	}
+}
+
+// buttonAt returns the action index under p, len(actions) for the close
+// button, or -1.
+func (t *Toast) buttonAt(p core.Point) int {
+	for i, a := range t.actions {
+		if a.rect.Contains(p) {
+			return i
+		}
+	}
+	if t.closable && t.closeRect.Contains(p) {
+		return len(t.actions)
+	}
+	return -1
+}
+
+// HandleEvent implements core.Widget.
+func (t *Toast) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	e, ok := ev.(event.MouseEvent)
+	if !ok || t.dismissed {
+		return core.Ignored
+	}
+	switch e.Type {
+	case event.MouseEnter:
+		t.gen++ // Hovering pauses the timer.
+	case event.MouseLeave:
+		t.hover, t.press = -1, -1
+		t.startTimer()
+		ctx.Repaint(t)
+	case event.MouseMove:
+		if i := t.buttonAt(e.Position); i != t.hover {
+			t.hover = i
+			ctx.Repaint(t)
+		}
+	case event.MouseDown:
+		if e.Button != event.ButtonLeft {
+			return core.Ignored
+		}
+		t.press = t.buttonAt(e.Position)
+		if t.press >= 0 {
+			ctx.CapturePointer(t)
+		}
+		return core.Handled
+	case event.MouseUp:
+		if e.Button != event.ButtonLeft || t.press < 0 {
+			return core.Ignored
+		}
+		i := t.press
+		t.press = -1
+		ctx.ReleasePointer()
+		if t.buttonAt(e.Position) != i {
+			return core.Handled
+		}
+		t.Dismiss()
+		if i < len(t.actions) && t.actions[i].onClick != nil {
+			t.actions[i].onClick()
+		}
+		return core.Handled
+	}
+	return core.Ignored
+}
+
+// ShowToast queues t for display. At most three toasts are visible at once;
+// the rest wait until earlier ones are dismissed. A toast's timer starts
+// when it appears.
+func ShowToast(ctx *core.Context, t *Toast) {
+	h := toastHosts(ctx)
+	if t.host != nil || t.dismissed {
+		return
+	}
+	t.host = h
+	h.queue = append(h.queue, t)
+	h.promote()
+}
+
+// SetToastCorner selects the window corner toasts are stacked in. The
+// default is ToastBottomRight.
+func SetToastCorner(ctx *core.Context, corner ToastCorner) {
+	h := toastHosts(ctx)
+	h.stack.corner = corner
+	ctx.Invalidate()
+}
+
+// toastHost is the per-window toast queue.
+type toastHost struct {
+	ctx     *core.Context
+	queue   []*Toast
+	stack   *toastStack
+	overlay *core.Overlay
+}
+
+type toastKey struct{}
+
+func toastHosts(ctx *core.Context) *toastHost {
+	if h, ok := ctx.Value(toastKey{}).(*toastHost); ok {
+		return h
+	}
+	h := &toastHost{ctx: ctx, stack: &toastStack{}}
+	h.overlay = &core.Overlay{Content: h.stack, Placement: h.stack.place}
+	ctx.SetValue(toastKey{}, h)
+	return h
+}
+
+// promote moves queued toasts into the stack while there is room and keeps
+// the overlay above everything else.
+func (h *toastHost) promote() {
+	live := 0
+	for _, t := range h.stack.toasts {
+		if !t.dismissed {
+			live++
+		}
+	}
+	promoted := false
+	for live < toastLimit && len(h.queue) > 0 {
+		h.stack.toasts = append(h.stack.toasts, h.queue[0])
+		h.queue = h.queue[1:]
+		live++
+		promoted = true
+	}
+	if promoted {
+		h.ctx.ShowOverlay(h.overlay)
+	}
+	h.ctx.Invalidate()
+}
+
+func (h *toastHost) dismissed(t *Toast) {
+	h.queue = slices.DeleteFunc(h.queue, func(x *Toast) bool { return x == t })
+	h.promote()
+}
+
+// toastStack lays the visible toasts out from the anchor corner outward.
+type toastStack struct {
+	core.WidgetBase
+	toasts []*Toast
+	corner ToastCorner
+	sizes  []core.Size
+	slots  []float32
+}
+
+func (s *toastStack) place(size core.Size, area core.Rect) core.Point {
+	area = area.Inset(core.UniformInsets(toastMargin))
+	var p core.Point
+	switch s.corner {
+	case ToastBottomRight, ToastTopRight:
+		p.X = area.Right() - size.Width
+	case ToastBottomLeft, ToastTopLeft:
+		p.X = area.X
+	default:
+		p.X = area.X + (area.Width-size.Width)/2
+	}
+	p.Y = area.Bottom() - size.Height
+	if s.corner.top() {
+		p.Y = area.Y
+	}
+	return p
+}
+
+func (s *toastStack) Layout(ctx *core.LayoutContext) core.Size {
+	h := toastHosts(ctx.Context)
+	kept := s.toasts[:0]
+	for _, t := range s.toasts {
+		if _, ok := t.presence(ctx.Context); ok {
+			kept = append(kept, t)
+		}
+	}
+	clear(s.toasts[len(kept):])
+	s.toasts = kept
+	if len(s.toasts) == 0 {
+		// Overlays must not change while the window lays them out.
+		ctx.Post(func() {
+			if len(s.toasts) == 0 {
+				ctx.CloseOverlay(h.overlay)
+			}
+		})
+		return core.Size{}
+	}
+	c := core.Loose(core.Sz(ctx.Constraints.MaxWidth-2*toastMargin, ctx.Constraints.MaxHeight))
+	s.sizes, s.slots = s.sizes[:0], s.slots[:0]
+	var size core.Size
+	animating := false
+	for _, t := range s.toasts {
+		if t.shownAt.IsZero() {
+			t.shownAt = ctx.Now()
+			t.startTimer()
+		}
+		p, _ := t.presence(ctx.Context)
+		animating = animating || p < 1
+		t.alpha = p
+		ts := ctx.Measure(t, c)
+		slot := (ts.Height + toastGap) * p
+		s.sizes = append(s.sizes, ts)
+		s.slots = append(s.slots, slot)
+		size.Width = max(size.Width, ts.Width)
+		size.Height += slot
+	}
+	if animating {
+		ctx.MarkNeedsLayout(s)
+	}
+	return size
+}
+
+func (s *toastStack) SetBounds(r core.Rect) {
+	s.WidgetBase.SetBounds(r)
+	top := s.corner.top()
+	edge := r.Bottom()
+	if top {
+		edge = r.Y
+	}
+	for i, t := range s.toasts {
+		ts := s.sizes[i]
+		x := r.X
+		switch s.corner {
+		case ToastBottomRight, ToastTopRight:
+			x = r.Right() - ts.Width
+		case ToastBottomCenter, ToastTopCenter:
+			x = r.X + (r.Width-ts.Width)/2
+		}
+		slide := (1 - t.alpha) * toastSlide
+		if top {
+			t.SetBounds(core.R(x, edge-slide, ts.Width, ts.Height))
+			edge += s.slots[i]
+		} else {
+			t.SetBounds(core.R(x, edge-ts.Height+slide, ts.Width, ts.Height))
+			edge -= s.slots[i]
+		}
+	}
+}
+
+func (s *toastStack) Children() []core.Widget {
+	children := make([]core.Widget, len(s.toasts))
+	for i, t := range s.toasts {
+		children[i] = t
+	}
+	return children
+}
+
+func (s *toastStack) Paint(ctx *core.PaintContext) {
+	for _, t := range s.toasts {
+		t.Paint(ctx)
+	}
+}
+
+// HitTest implements core.HitTester so the gaps between toasts let input
+// through.
+func (s *toastStack) HitTest(p core.Point) bool {
+	return slices.ContainsFunc(s.toasts, func(t *Toast) bool { return t.Bounds().Contains(p) })
+}
@@ -0,0 +1,198 @@
+package widgets
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+)
+
+// toastContext returns a context without motion, so toasts appear and
+// disappear at once.
+func toastContext() *core.Context {
+	ctx := core.NewContext()
+	ctx.SetReducedMotion(true)
+	return ctx
+}
+
+// visible lays the overlays of ctx out and returns the messages of the
+// toasts on screen, in stacking order.
+func visible(ctx *core.Context) string {
+	layoutOverlays(ctx)
+	var out []string
+	for _, t := range toastHosts(ctx).stack.toasts {
+		out = append(out, t.Message())
+	}
+	return strings.Join(out, " ")
+}
+
+func TestToastQueue(t *testing.T) {
+	ctx := toastContext()
+	var toasts []*Toast
+	var dismissed []string
+	for _, m := range []string{"a", "b", "c", "d", "e"} {
+		toast := NewToast(m).Duration(0).OnDismiss(func() { dismissed = append(dismissed, m) })
+		toasts = append(toasts, toast)
+		ShowToast(ctx, toast)
+	}
+	if got := visible(ctx); got != "a b c" {
+		t.Errorf("visible %q, want the first three", got)
+	}
+	toasts[4].Dismiss() // Still queued.
+	toasts[1].Dismiss()
+	toasts[1].Dismiss()
+	if got := visible(ctx); got != "a c d" {
+		t.Errorf("visible %q after dismissing b, want %q", got, "a c d")
+	}
+	if !slices.Equal(dismissed, []string{"e", "b"}) {
+		t.Errorf("dismissed %v, want [e b]", dismissed)
+	}
+	ShowToast(ctx, toasts[1]) // Dismissed toasts are not shown again.
+	for _, i := range []int{0, 2, 3} {
+		toasts[i].Dismiss()
+	}
+	if got := visible(ctx); got != "" {
+		t.Errorf("visible %q after dismissing all", got)
+	}
+	ctx.RunPosted()
+	if len(ctx.Overlays()) != 0 {
+		t.Error("the toast overlay stayed open without toasts")
+	}
+}
+
+func TestToastCorners(t *testing.T) {
+	tests := []struct {
+		corner ToastCorner
+		check  func(first, second core.Rect) bool
+	}{
+		{ToastBottomRight, func(a, b core.Rect) bool {
+			return a.Right() == 800-toastMargin && a.Bottom() == 600-toastMargin && b.Bottom() == a.Y-toastGap
+		}},
+		{ToastBottomLeft, func(a, b core.Rect) bool {
+			return a.X == toastMargin && a.Bottom() == 600-toastMargin && b.Bottom() == a.Y-toastGap
+		}},
+		{ToastBottomCenter, func(a, b core.Rect) bool { return a.Center().X == 400 && b.Bottom() == a.Y-toastGap }},
+		{ToastTopRight, func(a, b core.Rect) bool {
+			return a.Right() == 800-toastMargin && a.Y == toastMargin && b.Y == a.Bottom()+toastGap
+		}},
+		{ToastTopLeft, func(a, b core.Rect) bool {
+			return a.X == toastMargin && a.Y == toastMargin && b.Y == a.Bottom()+toastGap
+		}},
+		{ToastTopCenter, func(a, b core.Rect) bool { return a.Center().X == 400 && b.Y == a.Bottom()+toastGap }},
+	}
+	for _, tt := range tests {
+		ctx := toastContext()
+		SetToastCorner(ctx, tt.corner)
+		first, second := NewToast("first"), NewToast("second")
+		ShowToast(ctx, first)
+		ShowToast(ctx, second)
+		layoutOverlays(ctx)
+		if a, b := first.Bounds(), second.Bounds(); !tt.check(a, b) {
+			t.Errorf("corner %d: toasts at %v and %v", tt.corner, a, b)
+		}
+	}
+}
+
+func TestToastButtons(t *testing.T) {
+	tests := []struct {
+		name      string
+		press     func(t *Toast) core.Point
+		release   func(t *Toast) core.Point
+		undone    bool
+		dismissed bool
+	}{
+		{"action", func(t *Toast) core.Point { return t.actions[0].rect.Center() }, nil, true, true},
+		{"close", func(t *Toast) core.Point { return t.closeRect.Center() }, nil, false, true},
+		{"released elsewhere", func(t *Toast) core.Point { return t.actions[0].rect.Center() }, func(t *Toast) core.Point { return t.closeRect.Center() }, false, false},
+		{"message", func(t *Toast) core.Point { return core.Pt(t.Bounds().X+toastPaddingX, t.Bounds().Center().Y) }, nil, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := toastContext()
+			undone := false
+			toast := NewToast("Deleted").Action("Undo", func() { undone = true }).Closable(true)
+			ShowToast(ctx, toast)
+			layoutOverlays(ctx)
+			down := tt.press(toast)
+			up := down
+			if tt.release != nil {
+				up = tt.release(toast)
+			}
+			toast.HandleEvent(ctx, event.MouseEvent{Type: event.MouseDown, Position: down, Button: event.ButtonLeft})
+			toast.HandleEvent(ctx, event.MouseEvent{Type: event.MouseUp, Position: up, Button: event.ButtonLeft})
+			if undone != tt.undone || toast.IsDismissed() != tt.dismissed {
+				t.Errorf("undone = %v, dismissed = %v, want %v, %v", undone, toast.IsDismissed(), tt.undone, tt.dismissed)
+			}
+			if ctx.PointerCapture() != nil {
+				t.Error("the toast kept the pointer")
+			}
+		})
+	}
+}
+
+func TestToastTimer(t *testing.T) {
+	tests := []struct {
+		name      string
+		hover     bool
+		dismissed bool
+	}{
+		{"expires", false, true},
+		{"paused by hovering", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := toastContext()
+			toast := NewToast("Saved").Duration(10 * time.Millisecond)
+			ShowToast(ctx, toast)
+			if toast.IsDismissed() {
+				t.Fatal("dismissed before it was shown")
+			}
+			layoutOverlays(ctx) // Appearing starts the timer.
+			if tt.hover {
+				toast.HandleEvent(ctx, event.MouseEvent{Type: event.MouseEnter})
+			}
+			runPosted(t, ctx)
+			if toast.IsDismissed() != tt.dismissed {
+				t.Errorf("IsDismissed() = %v, want %v", toast.IsDismissed(), tt.dismissed)
+			}
+			if tt.hover {
+				toast.HandleEvent(ctx, event.MouseEvent{Type: event.MouseLeave})
+				runPosted(t, ctx)
+				if !toast.IsDismissed() {
+					t.Error("the timer did not restart when the pointer left")
+				}
+			}
+		})
+	}
+}
+
+func TestToastAnimates(t *testing.T) {
+	ctx := core.NewContext()
+	t0 := time.Now()
+	ctx.SetNow(t0)
+	toast := NewToast("Hello").Duration(0)
+	ShowToast(ctx, toast)
+	layoutOverlays(ctx)
+	ctx.SetNow(t0.Add(toastEnter / 2))
+	layoutOverlays(ctx)
+	if toast.alpha <= 0 || toast.alpha >= 1 {
+		t.Errorf("alpha = %v halfway through entering", toast.alpha)
+	}
+	ctx.SetNow(t0.Add(toastEnter))
+	layoutOverlays(ctx)
+	if toast.alpha != 1 {
+		t.Errorf("alpha = %v once entered", toast.alpha)
+	}
+	toast.Dismiss()
+	ctx.SetNow(toast.dismissedAt.Add(toastExit / 2))
+	if got := visible(ctx); got != "Hello" {
+		t.Errorf("visible %q while exiting", got)
+	}
+	ctx.SetNow(toast.dismissedAt.Add(toastExit))
+	if got := visible(ctx); got != "" {
+		t.Errorf("visible %q after exiting", got)
+	}
+}
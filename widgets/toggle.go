@@ -0,0 +1,125 @@
+package widgets
+
+import (
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/theme"
+)
+
+// Metrics shared by Checkbox, RadioGroup and Switch.
+const (
+	toggleGap   = 8  // between the control and its label
+	toggleHalo  = 40 // diameter of the Material state layer
+	toggleRing  = 2  // width of the focus ring
+	toggleInset = 3  // gap between the control and the focus ring
+)
+
+// toggleRow is a control glyph followed by an optional label, the layout
+// shared by the selection controls.
+type toggleRow struct {
+	label   string
+	style   core.TextStyle
+	control core.Rect
+	text    core.Point
+	size    core.Size
+}
+
+func (r *toggleRow) measure(ctx *core.LayoutContext, control core.Size) core.Size {
+	r.style = theme.From(ctx.Context).Typography.Body
+	r.size = control
+	w, h := control.Width, control.Height
+	if r.label != "" {
+		ts := ctx.MeasureText(r.label, r.style)
+		w += toggleGap + ts.Width
+		h = max(h, r.style.LineHeight())
+	}
+	return core.Sz(w, max(h, fieldHeight))
+}
+
+func (r *toggleRow) arrange(b core.Rect) {
+	r.control = core.R(b.X, b.Y+(b.Height-r.size.Height)/2, r.size.Width, r.size.Height)
+	r.text = core.Pt(r.control.Right()+toggleGap, b.Y+(b.Height-r.style.LineHeight())/2)
+}
+
+func (r *toggleRow) paintLabel(ctx *core.PaintContext) {
+	if r.label == "" {
+		return
+	}
+	th := theme.From(ctx.Context)
+	ctx.Canvas.DrawText(r.label, r.text, theme.TextStyle(r.style, th.Colors.OnSurface))
+}
+
+// paintToggleState draws the hover and focus feedback around a control: a
+// translucent state layer for Material, a focus ring for the others. For
+// a switch, Material centers the layer on the thumb; pass the thumb's
+// rectangle as control in that design.
+func paintToggleState(ctx *core.PaintContext, control core.Rect, radius float32, hover, focused bool) {
+	th := theme.From(ctx.Context)
+	if th.Design == theme.DesignMaterial {
+		var a float32
+		switch {
+		case focused:
+			a = 0.12
+		case hover:
+			a = 0.08
+		default:
+			return
+		}
+		d := max(toggleHalo, control.Height+16)
+		c := control.Center()
+		ctx.Canvas.DrawRoundedRect(core.R(c.X-d/2, c.Y-d/2, d, d), d/2, core.Filled(th.Colors.OnSurface.WithAlpha(a)))
+		return
+	}
+	if focused {
+		ring := control.Inset(core.UniformInsets(-toggleInset))
+		ctx.Canvas.DrawRoundedRect(ring, radius+toggleInset, core.Stroked(th.Colors.Primary, toggleRing))
+	}
+}
+
+// togglePress tracks a press on a control that activates when released
+// over it.
+type togglePress struct {
+	hover, pressed bool
+}
+
+// handle implements the pointer and Space-key behavior common to
+// selection controls, calling activate on a completed click or key press.
+func (p *togglePress) handle(ctx *core.Context, self core.Widget, focused bool, ev core.Event, activate func()) core.EventResult {
+	switch e := ev.(type) {
+	case event.MouseEvent:
+		switch e.Type {
+		case event.MouseEnter:
+			p.hover = true
+			ctx.Repaint(self)
+		case event.MouseLeave:
+			p.hover = false
+			ctx.Repaint(self)
+		case event.MouseDown:
+			if e.Button != event.ButtonLeft {
+				return core.Ignored
+			}
+			p.pressed = true
+			ctx.RequestFocus(self)
+			ctx.CapturePointer(self)
+			ctx.Repaint(self)
+			return core.Handled
+		case event.MouseUp:
+			if !p.pressed || e.Button != event.ButtonLeft {
+				return core.Ignored
+			}
+			p.pressed = false
+			ctx.ReleasePointer()
+			ctx.Repaint(self)
+			if self.Bounds().Contains(e.Position) {
+				activate()
+			}
+			return core.Handled
+		}
+	case event.KeyEvent:
+		if focused && e.Type == event.KeyPress && e.Key == event.KeySpace && e.Modifiers == 0 {
+			activate()
+			return core.Handled
+		}
+	}
+	return core.Ignored
+}
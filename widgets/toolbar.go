@@ -0,0 +1,581 @@
+package widgets
+
+import (
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/theme"
+)
+
+const (
+	toolbarPadding     float32 = 4
+	toolButtonPadding  float32 = 6
+	toolIconSize       float32 = 20
+	toolLabelGap       float32 = 6
+	toolSeparatorWidth float32 = 9
+	toolMoreWidth      float32 = 24
+)
+
+// ToolItem is an entry of a Toolbar or Ribbon: a command button, a toggle
+// button or a separator.
+type ToolItem struct {
+	// Label is the button's text. A Toolbar shows it next to the icon
+	// only when labels are enabled or there is no icon; menus listing the
+	// item always show it.
+	Label string
+
+	// Icon is drawn at the icon size of the Toolbar or Ribbon, usually an
+	// SVG or Image.
+	Icon core.Widget
+
+	// Tip is the text of the button's tooltip.
+	Tip string
+
+	// Toggle items flip Checked when activated and are drawn pressed
+	// while checked.
+	Toggle bool
+
+	// Checked is the state of a toggle item.
+	Checked bool
+
+	// Group makes toggle items with the same name exclusive within their
+	// Toolbar or Ribbon: activating one checks it and unchecks the others,
+	// and activating the checked one does nothing.
+	Group string
+
+	// Disabled items are drawn dimmed and cannot be activated.
+	Disabled bool
+
+	// OnClick is called when the item is activated, after Checked was
+	// updated.
+	OnClick func()
+
+	separator bool
+	tip       tipCache
+}
+
+// NewToolButton returns a command button. label or icon may be empty.
+func NewToolButton(label string, icon core.Widget, onClick func()) *ToolItem {
+	return &ToolItem{Label: label, Icon: icon, OnClick: onClick}
+}
+
+// NewToolToggle returns a toggle button. onToggle receives the new state.
+func NewToolToggle(label string, icon core.Widget, checked bool, onToggle func(checked bool)) *ToolItem {
+	it := &ToolItem{Label: label, Icon: icon, Toggle: true, Checked: checked}
+	if onToggle != nil {
+		it.OnClick = func() { onToggle(it.Checked) }
+	}
+	return it
+}
+
+// ToolSeparator returns a separator line.
+func ToolSeparator() *ToolItem {
+	return &ToolItem{separator: true}
+}
+
+// Tooltip sets the text of the button's tooltip.
+func (it *ToolItem) Tooltip(text string) *ToolItem {
+	it.Tip = text
+	return it
+}
+
+// InGroup makes the item a toggle in the exclusive group named group.
+func (it *ToolItem) InGroup(group string) *ToolItem {
+	it.Toggle, it.Group = true, group
+	return it
+}
+
+// Enabled enables or disables the item.
+func (it *ToolItem) Enabled(enabled bool) *ToolItem {
+	it.Disabled = !enabled
+	return it
+}
+
+// IsSeparator reports whether the item is a separator.
+func (it *ToolItem) IsSeparator() bool {
+	return it.separator
+}
+
+func (it *ToolItem) selectable() bool {
+	return !it.separator && !it.Disabled
+}
+
+func (it *ToolItem) menuLabel() string {
+	if it.Label != "" {
+		return it.Label
+	}
+	return it.Tip
+}
+
+// tipArea returns the tooltip area covering r, or nil if the item has no
+// tooltip.
+func (it *ToolItem) tipArea(r core.Rect) core.Widget {
+	a := it.tip.wrap(nil, it.Tip)
+	if a != nil {
+		a.SetBounds(r)
+	}
+	return a
+}
+
+// activateTool applies a click on it, whose toggle group spans all.
+func activateTool(all []*ToolItem, it *ToolItem) {
+	if !it.selectable() {
+		return
+	}
+	switch {
+	case it.Toggle && it.Group != "":
+		if it.Checked {
+			return
+		}
+		for _, o := range all {
+			if o != it && o.Toggle && o.Group == it.Group {
+				o.Checked = false
+			}
+		}
+		it.Checked = true
+	case it.Toggle:
+		it.Checked = !it.Checked
+	}
+	if it.OnClick != nil {
+		it.OnClick()
+	}
+}
+
+// toolMenu returns menu items activating items, whose toggle groups span
+// all.
+func toolMenu(items, all []*ToolItem) []*MenuItem {
+	var menu []*MenuItem
+	for _, it := range items {
+		if it.separator {
+			if len(menu) > 0 && !menu[len(menu)-1].IsSeparator() {
+				menu = append(menu, MenuSeparator())
+			}
+			continue
+		}
+		m := &MenuItem{Label: it.menuLabel(), Checkable: it.Toggle, Checked: it.Checked, Disabled: it.Disabled}
+		m.OnClick = func() { activateTool(all, it) }
+		menu = append(menu, m)
+	}
+	if n := len(menu); n > 0 && menu[n-1].IsSeparator() {
+		menu = menu[:n-1]
+	}
+	return menu
+}
+
+// toolTarget is a pressable area of a Toolbar or Ribbon: an item, or a
+// drop-down button listing the items of menu.
+type toolTarget struct {
+	rect core.Rect
+	item *ToolItem
+	menu []*ToolItem
+	icon core.Widget // Drawn on a drop-down button, if set.
+}
+
+// toolInput implements the pointer and keyboard behavior shared by
+// Toolbar and Ribbon over their current targets, which run from the start
+// edge of the direction dir.
+type toolInput struct {
+	targets []toolTarget
+	hover   int
+	press   int
+	focus   int
+	open    int // Target whose menu is open.
+	session *menuSession
+	dir     core.Direction
+}
+
+func (t *toolInput) reset() {
+	t.hover, t.press, t.focus, t.open = -1, -1, -1, -1
+}
+
+func (t *toolInput) at(p core.Point) int {
+	for i, tg := range t.targets {
+		if tg.rect.Contains(p) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (t *toolInput) enabled(i int) bool {
+	return i >= 0 && i < len(t.targets) && (t.targets[i].item == nil || t.targets[i].item.selectable())
+}
+
+// focusIndex returns the target showing keyboard focus, by default the
+// first enabled one.
+func (t *toolInput) focusIndex() int {
+	if t.enabled(t.focus) {
+		return t.focus
+	}
+	for i := range t.targets {
+		if t.enabled(i) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (t *toolInput) moveFocus(delta int) {
+	n := len(t.targets)
+	i := t.focus
+	if i < 0 || i >= n {
+		i = -1
+		if delta < 0 {
+			i = n
+		}
+	}
+	for range n {
+		i = (i + delta + n) % n
+		if t.enabled(i) {
+			t.focus = i
+			return
+		}
+	}
+}
+
+func (t *toolInput) trigger(ctx *core.Context, owner core.Widget, i int, all []*ToolItem, keyboard bool) {
+	if tg := t.targets[i]; tg.item != nil {
+		activateTool(all, tg.item)
+		ctx.Repaint(owner)
+		return
+	}
+	t.openMenu(ctx, owner, i, all, keyboard)
+}
+
+// openMenu opens the menu of target i, or closes it if it is open.
+func (t *toolInput) openMenu(ctx *core.Context, owner core.Widget, i int, all []*ToolItem, keyboard bool) {
+	open := t.open
+	t.closeMenu(ctx)
+	if open == i {
+		return
+	}
+	s := openMenu(ctx, toolMenu(t.targets[i].menu, all), placeBelow(t.targets[i].rect, t.dir), owner, keyboard)
+	s.onClose = func() {
+		if t.session == s {
+			t.session, t.open = nil, -1
+			ctx.Repaint(owner)
+		}
+	}
+	t.session, t.open = s, i
+	ctx.Repaint(owner)
+}
+
+func (t *toolInput) closeMenu(ctx *core.Context) {
+	if s := t.session; s != nil {
+		t.session, t.open = nil, -1
+		s.close(ctx)
+	}
+}
+
+// handle processes ev for owner, whose toggle groups span all.
+func (t *toolInput) handle(ctx *core.Context, owner core.Widget, focused bool, ev core.Event, all []*ToolItem) core.EventResult {
+	switch e := ev.(type) {
+	case event.MouseEvent:
+		switch e.Type {
+		case event.MouseEnter, event.MouseMove:
+			if i := t.at(e.Position); i != t.hover {
+				t.hover = i
+				ctx.Repaint(owner)
+			}
+		case event.MouseLeave:
+			if t.hover >= 0 {
+				t.hover = -1
+				ctx.Repaint(owner)
+			}
+		case event.MouseDown:
+			if e.Button != event.ButtonLeft {
+				return core.Ignored
+			}
+			i := t.at(e.Position)
+			if t.session != nil && i != t.open {
+				t.closeMenu(ctx)
+			}
+			if !t.enabled(i) {
+				return core.Ignored
+			}
+			if t.targets[i].item == nil {
+				t.openMenu(ctx, owner, i, all, false)
+				return core.Handled
+			}
+			t.press = i
+			ctx.CapturePointer(owner)
+			ctx.Repaint(owner)
+			return core.Handled
+		case event.MouseUp:
+			if t.press < 0 || e.Button != event.ButtonLeft {
+				return core.Ignored
+			}
+			i := t.press
+			t.press = -1
+			ctx.ReleasePointer()
+			ctx.Repaint(owner)
+			if i < len(t.targets) && t.targets[i].rect.Contains(e.Position) {
+				t.trigger(ctx, owner, i, all, false)
+			}
+			return core.Handled
+		}
+	case event.KeyEvent:
+		if !focused || e.Type != event.KeyPress || e.Modifiers != 0 {
+			return core.Ignored
+		}
+		switch key := mirrorKey(t.dir, e.Key); key {
+		case event.KeyLeft, event.KeyRight:
+			t.focus = t.focusIndex()
+			if key == event.KeyLeft {
+				t.moveFocus(-1)
+			} else {
+				t.moveFocus(1)
+			}
+		case event.KeyHome:
+			t.focus = -1
+			t.moveFocus(1)
+		case event.KeyEnd:
+			t.focus = len(t.targets)
+			t.moveFocus(-1)
+		case event.KeyEnter, event.KeySpace:
+			if i := t.focusIndex(); i >= 0 {
+				t.trigger(ctx, owner, i, all, true)
+			}
+		case event.KeyDown:
+			i := t.focusIndex()
+			if i < 0 || t.targets[i].item != nil {
+				return core.Ignored
+			}
+			t.openMenu(ctx, owner, i, all, true)
+		default:
+			return core.Ignored
+		}
+		ctx.Repaint(owner)
+		return core.Handled
+	}
+	return core.Ignored
+}
+
+// paintState draws the background of target i for its checked, pressed
+// and hovered state, and the focus ring if focused.
+func (t *toolInput) paintState(ctx *core.PaintContext, i int, focused bool) {
+	th := theme.From(ctx.Context)
+	tg := t.targets[i]
+	var fill core.Color
+	switch {
+	case t.open == i || (t.press == i && t.hover == i):
+		fill = th.Colors.OnSurface.WithAlpha(0.12)
+	case tg.item != nil && tg.item.Toggle && tg.item.Checked:
+		fill = th.Colors.Selection
+	case t.hover == i && t.enabled(i):
+		fill = th.Colors.OnSurface.WithAlpha(0.06)
+	}
+	if fill != (core.Color{}) {
+		ctx.Canvas.DrawRoundedRect(tg.rect, th.Radii.Small, core.Filled(fill))
+	}
+	if focused && t.focusIndex() == i {
+		ctx.Canvas.DrawRoundedRect(tg.rect.Inset(core.UniformInsets(1)), th.Radii.Small, core.Stroked(th.Colors.Primary, toggleRing))
+	}
+}
+
+// toolLabelColor returns the color of an item's label.
+func toolLabelColor(th *theme.Theme, it *ToolItem) core.Color {
+	if it != nil && it.Disabled {
+		return th.Colors.OnSurfaceVariant.WithAlpha(0.5)
+	}
+	return th.Colors.OnSurface
+}
+
+// paintToolIcon draws an item's icon, veiled in bg if it is disabled.
+func paintToolIcon(ctx *core.PaintContext, it *ToolItem, bg core.Color) {
+	if it.Icon == nil {
+		return
+	}
+	it.Icon.Paint(ctx)
+	if it.Disabled {
+		ctx.Canvas.DrawRect(it.Icon.Bounds(), core.Filled(bg.WithAlpha(0.6)))
+	}
+}
+
+// Toolbar is a horizontal bar of icon buttons, toggle buttons and
+// separators.
+//
+// Buttons show their icon, their label if labels are enabled or there is
+// no icon, and their Tip in a tooltip. Toggle buttons stay pressed while
+// checked, and toggles sharing a Group act as a set of radio buttons.
+// Items that do not fit are moved, from the end, into an overflow menu
+// opened by a button at the end edge. In right-to-left text the buttons
+// run from the right.
+//
+// Clicking a button leaves keyboard focus where it was. The toolbar is a
+// single Tab stop: the arrow keys, Home and End move between its buttons,
+// Space or Enter activates one and Down opens the overflow menu.
+type Toolbar struct {
+	core.WidgetBase
+	core.FocusState
+
+	items    []*ToolItem
+	labels   bool
+	iconSize float32
+
+	widths  []float32
+	rects   []core.Rect
+	total   float32
+	visible int
+	input   toolInput
+}
+
+// NewToolbar returns a toolbar with the given items.
+func NewToolbar(items ...*ToolItem) *Toolbar {
+	t := &Toolbar{items: items, iconSize: toolIconSize}
+	t.input.reset()
+	return t
+}
+
+// Items returns the items.
+func (t *Toolbar) Items() []*ToolItem {
+	return t.items
+}
+
+// SetItems replaces the items.
+func (t *Toolbar) SetItems(items ...*ToolItem) {
+	t.items = items
+	t.input.reset()
+}
+
+// ShowLabels shows the labels of buttons that have an icon.
+func (t *Toolbar) ShowLabels(show bool) *Toolbar {
+	t.labels = show
+	return t
+}
+
+// IconSize sets the size icons are drawn at. The default is 20.
+func (t *Toolbar) IconSize(size float32) *Toolbar {
+	t.iconSize = size
+	return t
+}
+
+func (t *Toolbar) showLabel(it *ToolItem) bool {
+	return it.Label != "" && (t.labels || it.Icon == nil)
+}
+
+// Layout implements core.Widget.
+func (t *Toolbar) Layout(ctx *core.LayoutContext) core.Size {
+	t.input.dir = ctx.Direction()
+	style := theme.From(ctx.Context).Typography.Label
+	t.widths = t.widths[:0]
+	t.total = 0
+	h := t.iconSize
+	for _, it := range t.items {
+		w := toolSeparatorWidth
+		if !it.separator {
+			w = 0
+			if it.Icon != nil {
+				ctx.Measure(it.Icon, core.Tight(core.Sz(t.iconSize, t.iconSize)))
+				w = t.iconSize
+			}
+			if t.showLabel(it) {
+				if w > 0 {
+					w += toolLabelGap
+				}
+				w += ctx.MeasureText(it.Label, style).Width
+				h = max(h, style.LineHeight())
+			}
+			w += 2 * toolButtonPadding
+		}
+		t.widths = append(t.widths, w)
+		t.total += w
+	}
+	size := core.Sz(t.total+2*toolbarPadding, h+2*toolButtonPadding+2*toolbarPadding)
+	if ctx.Constraints.HasBoundedWidth() {
+		size.Width = ctx.Constraints.MaxWidth
+	}
+	return ctx.Constraints.Constrain(size)
+}
+
+// SetBounds implements core.Widget.
+func (t *Toolbar) SetBounds(r core.Rect) {
+	t.WidgetBase.SetBounds(r)
+	inner := r.Inset(core.UniformInsets(toolbarPadding))
+	n := len(t.items)
+	if t.total > inner.Width {
+		avail := inner.Width - toolMoreWidth
+		var x float32
+		n = 0
+		for n < len(t.items) && x+t.widths[n] <= avail {
+			x += t.widths[n]
+			n++
+		}
+		for n > 0 && t.items[n-1].separator {
+			n--
+		}
+	}
+	t.visible = n
+	t.rects = t.rects[:0]
+	t.input.targets = t.input.targets[:0]
+	var tips []core.Widget
+	dir := t.input.dir
+	x := inner.X
+	for i, it := range t.items[:n] {
+		br := dir.Mirror(core.R(x, inner.Y, t.widths[i], inner.Height), inner)
+		x += t.widths[i]
+		t.rects = append(t.rects, br)
+		if it.separator {
+			continue
+		}
+		if it.Icon != nil {
+			it.Icon.SetBounds(dir.Mirror(core.R(br.X+toolButtonPadding, br.Y+(br.Height-t.iconSize)/2, t.iconSize, t.iconSize), br))
+		}
+		t.input.targets = append(t.input.targets, toolTarget{rect: br, item: it})
+		if tip := it.tipArea(br); tip != nil {
+			tips = append(tips, tip)
+		}
+	}
+	if n < len(t.items) {
+		more := dir.Mirror(core.R(inner.Right()-toolMoreWidth, inner.Y, toolMoreWidth, inner.Height), inner)
+		t.input.targets = append(t.input.targets, toolTarget{rect: more, menu: t.items[n:]})
+	}
+	t.SetChildren(tips...)
+}
+
+// Paint implements core.Widget.
+func (t *Toolbar) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	bg := th.Colors.SurfaceVariant
+	cv.DrawRect(t.Bounds(), core.Filled(bg))
+	for i, it := range t.items[:t.visible] {
+		if r := t.rects[i]; it.separator {
+			cv.DrawRect(core.R(r.X+r.Width/2, r.Y+4, 1, r.Height-8), core.Filled(th.Colors.Outline.WithAlpha(0.4)))
+		}
+	}
+	for i, tg := range t.input.targets {
+		t.input.paintState(ctx, i, t.IsFocused())
+		r := tg.rect
+		if tg.item == nil {
+			paintMoreDots(ctx, r, th.Colors.OnSurface)
+			continue
+		}
+		it := tg.item
+		x := r.X + toolButtonPadding
+		if it.Icon != nil {
+			paintToolIcon(ctx, it, bg)
+			x += t.iconSize + toolLabelGap
+		}
+		if t.showLabel(it) {
+			style := theme.TextStyle(th.Typography.Label, toolLabelColor(th, it))
+			w := ctx.MeasureText(it.Label, style).Width
+			x = t.input.dir.Mirror(core.R(x, r.Y, w, r.Height), r).X
+			ctx.Canvas.DrawText(it.Label, core.Pt(x, r.Y+(r.Height-style.LineHeight())/2), style)
+		}
+	}
+}
+
+// paintMoreDots draws the three dots of an overflow button centered in r.
+func paintMoreDots(ctx *core.PaintContext, r core.Rect, c core.Color) {
+	const d, gap = 3, 3
+	cx, cy := r.Center().X, r.Center().Y
+	for i := -1; i <= 1; i++ {
+		x := cx + float32(i)*(d+gap) - d/2
+		ctx.Canvas.DrawRoundedRect(core.R(x, cy-d/2, d, d), d/2, core.Filled(c))
+	}
+}
+
+// HandleEvent implements core.Widget.
+func (t *Toolbar) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	return t.input.handle(ctx, t, t.IsFocused(), ev, t.items)
+}
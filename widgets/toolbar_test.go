@@ -0,0 +1,306 @@
+package widgets
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/theme"
+)
+
+// icon returns a stand-in for an icon widget.
+func icon() core.Widget {
+	return &fixed{height: toolIconSize}
+}
+
+func TestActivateTool(t *testing.T) {
+	var clicks int
+	count := func() { clicks++ }
+	cut := NewToolButton("Cut", nil, count)
+	bold := NewToolToggle("Bold", nil, false, func(bool) { clicks++ })
+	left := &ToolItem{Label: "Left", OnClick: count}
+	left.InGroup("align")
+	right := &ToolItem{Label: "Right", Checked: true, OnClick: count}
+	right.InGroup("align")
+	other := &ToolItem{Label: "Other", Checked: true}
+	other.InGroup("view")
+	off := NewToolButton("Off", nil, count).Enabled(false)
+	all := []*ToolItem{cut, bold, left, right, other, off, ToolSeparator()}
+
+	tests := []struct {
+		name    string
+		item    *ToolItem
+		clicks  int
+		checked []bool // bold, left, right, other
+	}{
+		{"command", cut, 1, []bool{false, false, true, true}},
+		{"toggle on", bold, 1, []bool{true, false, true, true}},
+		{"toggle off", bold, 1, []bool{false, false, true, true}},
+		{"group", left, 1, []bool{false, true, false, true}},
+		{"checked in group", left, 0, []bool{false, true, false, true}},
+		{"disabled", off, 0, []bool{false, true, false, true}},
+		{"separator", all[6], 0, []bool{false, true, false, true}},
+	}
+	for _, tt := range tests {
+		clicks = 0
+		activateTool(all, tt.item)
+		got := []bool{bold.Checked, left.Checked, right.Checked, other.Checked}
+		if clicks != tt.clicks || !equalBools(got, tt.checked) {
+			t.Errorf("%s: %d clicks, checked %v, want %d and %v", tt.name, clicks, got, tt.clicks, tt.checked)
+		}
+	}
+}
+
+func equalBools(a, b []bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestToolMenu(t *testing.T) {
+	bold := NewToolToggle("Bold", nil, true, nil)
+	items := []*ToolItem{
+		ToolSeparator(),
+		NewToolButton("", icon(), nil).Tooltip("Cut"),
+		ToolSeparator(),
+		ToolSeparator(),
+		bold,
+		NewToolButton("Off", nil, nil).Enabled(false),
+		ToolSeparator(),
+	}
+	menu := toolMenu(items, items)
+	var got []string
+	for _, m := range menu {
+		switch {
+		case m.IsSeparator():
+			got = append(got, "-")
+		case m.Checked:
+			got = append(got, "*"+m.Label)
+		case m.Disabled:
+			got = append(got, "("+m.Label+")")
+		default:
+			got = append(got, m.Label)
+		}
+	}
+	if want := []string{"Cut", "-", "*Bold", "(Off)"}; !equalStrings(got, want) {
+		t.Fatalf("menu %q, want %q", got, want)
+	}
+	if !menu[2].Checkable {
+		t.Error("a toggle is not checkable in the menu")
+	}
+	menu[2].OnClick()
+	if bold.Checked {
+		t.Error("the menu item did not toggle the item")
+	}
+}
+
+func TestToolbarLayout(t *testing.T) {
+	tb := NewToolbar(NewToolButton("A", icon(), nil), ToolSeparator(), NewToolButton("Text", nil, nil).Tooltip("tip"))
+	lc := &core.LayoutContext{Context: core.NewContext()}
+	textW := lc.MeasureText("Text", theme.From(lc.Context).Typography.Label).Width
+	want := 2*toolbarPadding + (toolIconSize + 2*toolButtonPadding) + toolSeparatorWidth + (textW + 2*toolButtonPadding)
+	if got := lc.Measure(tb, core.Unbounded()); got.Width != want || got.Height < toolIconSize+2*toolButtonPadding+2*toolbarPadding {
+		t.Errorf("size %v, want %v wide", got, want)
+	}
+
+	ctx := layoutAt(tb, core.R(0, 0, 400, 40))
+	targets := tb.input.targets
+	if len(targets) != 2 || targets[0].rect.X != toolbarPadding || targets[1].rect.X != toolbarPadding+2*toolButtonPadding+toolIconSize+toolSeparatorWidth {
+		t.Errorf("targets %+v", targets)
+	}
+	if got := tb.Items()[0].Icon.Bounds(); got != core.R(toolbarPadding+toolButtonPadding, 10, toolIconSize, toolIconSize) {
+		t.Errorf("the icon at %v", got)
+	}
+	if n := len(tb.Children()); n != 1 {
+		t.Errorf("%d tooltip areas, want one for the item with a tip", n)
+	}
+	cv := &textCanvas{}
+	tb.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+	if !equalStrings(cv.texts, []string{"Text"}) {
+		t.Errorf("drew %q, want only the label of the item without an icon", cv.texts)
+	}
+	tb.ShowLabels(true)
+	ctx.LayoutRoot(tb, core.R(0, 0, 400, 40))
+	cv = &textCanvas{}
+	tb.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+	if !equalStrings(cv.texts, []string{"A", "Text"}) {
+		t.Errorf("with labels drew %q", cv.texts)
+	}
+
+	tb.SetItems(NewToolButton("", icon(), nil))
+	tb.IconSize(16)
+	if got := lc.Measure(tb, core.Unbounded()); got != core.Sz(16+2*toolButtonPadding+2*toolbarPadding, 16+2*toolButtonPadding+2*toolbarPadding) {
+		t.Errorf("with 16 pixel icons the size is %v", got)
+	}
+	if tb.Items()[0].IsSeparator() || !ToolSeparator().IsSeparator() {
+		t.Error("IsSeparator is wrong")
+	}
+}
+
+// overflowing returns a toolbar whose last three items overflow, with the
+// separator before them.
+func overflowing() (*Toolbar, []*ToolItem, *core.Context) {
+	items := make([]*ToolItem, 5)
+	for i := range items {
+		items[i] = NewToolButton(string(rune('A'+i)), icon(), nil)
+	}
+	items[2] = ToolSeparator()
+	tb := NewToolbar(items...)
+	button := toolIconSize + 2*toolButtonPadding
+	ctx := layoutAt(tb, core.R(0, 0, 2*toolbarPadding+toolMoreWidth+2*button+toolSeparatorWidth+10, 40))
+	return tb, items, ctx
+}
+
+func TestToolbarOverflow(t *testing.T) {
+	tb, items, ctx := overflowing()
+	if tb.visible != 2 || len(tb.input.targets) != 3 {
+		t.Fatalf("%d items shown with %d targets, want 2 and the overflow button", tb.visible, len(tb.input.targets))
+	}
+	more := tb.input.targets[2]
+	if more.item != nil || len(more.menu) != 3 || more.menu[0] != items[2] || more.rect.Right() != tb.Bounds().Right()-toolbarPadding {
+		t.Errorf("the overflow button at %v lists %d items", more.rect, len(more.menu))
+	}
+
+	tb.HandleEvent(ctx, leftMouse(event.MouseDown, more.rect.Center()))
+	popups := layoutOverlays(ctx)
+	if len(popups) != 1 || len(popups[0].items) != 2 || popups[0].items[0].Label != "D" {
+		t.Fatalf("the overflow menu is %+v", popups)
+	}
+	if tb.input.open != 2 {
+		t.Errorf("open target %d", tb.input.open)
+	}
+	// A second press closes it.
+	tb.HandleEvent(ctx, leftMouse(event.MouseDown, more.rect.Center()))
+	if len(ctx.Overlays()) != 0 || tb.input.open != -1 {
+		t.Errorf("%d overlays after the second press", len(ctx.Overlays()))
+	}
+
+	// Everything fits again when the toolbar grows.
+	ctx.LayoutRoot(tb, core.R(0, 0, 400, 40))
+	if tb.visible != 5 || len(tb.input.targets) != 4 {
+		t.Errorf("%d items shown with %d targets", tb.visible, len(tb.input.targets))
+	}
+}
+
+func TestToolbarRightToLeft(t *testing.T) {
+	items := make([]*ToolItem, 5)
+	for i := range items {
+		items[i] = NewToolButton(string(rune('A'+i)), icon(), nil)
+	}
+	tb := NewToolbar(items...)
+	button := toolIconSize + 2*toolButtonPadding
+	width := 2*toolbarPadding + toolMoreWidth + 2*button + 10
+	// Away from the left of the window, so the overflow menu has room.
+	ctx := layoutRTL(tb, core.R(300, 0, width, 40))
+	targets := tb.input.targets
+	if len(targets) != 3 || targets[0].rect.Right() != 300+width-toolbarPadding || targets[1].rect.Right() != targets[0].rect.X {
+		t.Fatalf("targets %+v, want from the right edge", targets)
+	}
+	if got := items[0].Icon.Bounds(); got != core.R(300+width-toolbarPadding-toolButtonPadding-toolIconSize, 10, toolIconSize, toolIconSize) {
+		t.Errorf("the icon at %v", got)
+	}
+	more := targets[2]
+	if more.rect.X != 300+toolbarPadding {
+		t.Errorf("the overflow button at %v, want at the left edge", more.rect)
+	}
+
+	ctx.RequestFocus(tb)
+	tb.HandleEvent(ctx, press(event.KeyLeft, 0))
+	if tb.input.focus != 1 {
+		t.Errorf("after Left the focus is on %d, want 1", tb.input.focus)
+	}
+	tb.HandleEvent(ctx, leftMouse(event.MouseDown, more.rect.Center()))
+	if popups := layoutOverlays(ctx); len(popups) != 1 || popups[0].Bounds().Right() != more.rect.Right() {
+		t.Errorf("the overflow menu is %+v, want ending under its button", popups)
+	}
+}
+
+func TestToolbarMouse(t *testing.T) {
+	var clicks int
+	cut := NewToolButton("", icon(), func() { clicks++ })
+	off := NewToolButton("", icon(), func() { clicks++ }).Enabled(false)
+	tb := NewToolbar(cut, off)
+	ctx := layoutAt(tb, core.R(0, 0, 400, 40))
+	in, out := tb.input.targets[0].rect.Center(), tb.input.targets[1].rect.Center()
+
+	tb.HandleEvent(ctx, leftMouse(event.MouseDown, in))
+	if ctx.PointerCapture() != tb || tb.input.press != 0 || clicks != 0 {
+		t.Fatalf("a press captured %v, clicked %d", ctx.PointerCapture(), clicks)
+	}
+	tb.HandleEvent(ctx, leftMouse(event.MouseUp, in))
+	if clicks != 1 || ctx.PointerCapture() != nil {
+		t.Errorf("a click clicked %d times", clicks)
+	}
+	if ctx.Focused() != nil {
+		t.Error("a click took focus")
+	}
+	tb.HandleEvent(ctx, leftMouse(event.MouseDown, in))
+	tb.HandleEvent(ctx, leftMouse(event.MouseUp, out))
+	if clicks != 1 {
+		t.Error("releasing off the button clicked it")
+	}
+	if r := tb.HandleEvent(ctx, leftMouse(event.MouseDown, out)); r != core.Ignored {
+		t.Error("a press on a disabled button was handled")
+	}
+	if r := tb.HandleEvent(ctx, event.MouseEvent{Type: event.MouseDown, Button: event.ButtonRight, Position: in}); r != core.Ignored {
+		t.Error("a right press was handled")
+	}
+
+	tb.HandleEvent(ctx, event.MouseEvent{Type: event.MouseMove, Position: in})
+	if tb.input.hover != 0 {
+		t.Errorf("hovering the button set %d", tb.input.hover)
+	}
+	tb.HandleEvent(ctx, event.MouseEvent{Type: event.MouseLeave})
+	if tb.input.hover != -1 {
+		t.Errorf("after leaving the hover is %d", tb.input.hover)
+	}
+}
+
+func TestToolbarKeys(t *testing.T) {
+	tb, items, ctx := overflowing()
+	items[1].Enabled(false)
+	var clicked []string
+	items[0].OnClick = func() { clicked = append(clicked, "A") }
+	if r := tb.HandleEvent(ctx, press(event.KeyRight, 0)); r != core.Ignored {
+		t.Error("an unfocused toolbar handled a key")
+	}
+	ctx.RequestFocus(tb)
+	tests := []struct {
+		key   event.Key
+		focus int
+	}{
+		{event.KeyRight, 2}, // skips the disabled B to the overflow button
+		{event.KeyRight, 0},
+		{event.KeyLeft, 2},
+		{event.KeyHome, 0},
+		{event.KeyEnd, 2},
+	}
+	for _, tt := range tests {
+		if r := tb.HandleEvent(ctx, press(tt.key, 0)); r != core.Handled || tb.input.focus != tt.focus {
+			t.Errorf("after %v the focus is on %d, want %d", tt.key, tb.input.focus, tt.focus)
+		}
+	}
+	tb.HandleEvent(ctx, press(event.KeyDown, 0))
+	if popups := layoutOverlays(ctx); len(popups) != 1 || popups[0].hover != 0 {
+		t.Fatalf("Down opened %d menus", len(popups))
+	}
+	tb.input.closeMenu(ctx)
+	tb.HandleEvent(ctx, press(event.KeyHome, 0))
+	if r := tb.HandleEvent(ctx, press(event.KeyDown, 0)); r != core.Ignored || len(ctx.Overlays()) != 0 {
+		t.Error("Down on a button opened a menu")
+	}
+	tb.HandleEvent(ctx, press(event.KeySpace, 0))
+	tb.HandleEvent(ctx, press(event.KeyEnter, 0))
+	if !equalStrings(clicked, []string{"A", "A"}) {
+		t.Errorf("Space and Enter clicked %q", clicked)
+	}
+	if r := tb.HandleEvent(ctx, press(event.KeyRight, event.ModShift)); r != core.Ignored {
+		t.Error("Shift+Right was handled")
+	}
+}
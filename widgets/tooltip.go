@@ -0,0 +1,345 @@
+package widgets
+
+import (
+	"slices"
+	"time"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/shortcuts"
+	"github.com/gogpu/ui/theme"
+)
+
+const (
+	// tooltipDelay is how long the pointer must rest before a tooltip
+	// appears.
+	tooltipDelay = 500 * time.Millisecond
+	// tooltipReshowDelay replaces tooltipDelay when another tooltip was
+	// visible within tooltipReshowWindow, so sweeping across a toolbar
+	// shows each tip almost at once.
+	tooltipReshowDelay  = 50 * time.Millisecond
+	tooltipReshowWindow = 1500 * time.Millisecond
+	// tooltipTimeout hides a tooltip that has been shown for this long.
+	tooltipTimeout = 10 * time.Second
+
+	tooltipMaxWidth float32 = 320
+	tooltipOffset   float32 = 18
+)
+
+// TooltipArea is the wrapper returned by Tooltip.
+type TooltipArea struct {
+	core.WidgetBase
+
+	child   core.Widget
+	content core.Widget
+	bubble  *tooltipBubble
+}
+
+// Tooltip wraps child so that content is shown in a tooltip when the
+// pointer rests over it. content can be any widget. Tooltips are shown by
+// a window-wide manager in a passive overlay, so they are never clipped by
+// ancestors and never intercept input; only one is visible at a time.
+//
+// The tooltip appears after a short delay, immediately when moving between
+// tooltips, and disappears when the pointer leaves, a button or key is
+// pressed, the area scrolls or it has been visible for ten seconds.
+func Tooltip(child, content core.Widget) *TooltipArea {
+	a := &TooltipArea{child: child, content: content}
+	a.bubble = &tooltipBubble{content: content}
+	a.bubble.SetChildren(content)
+	a.SetChildren(child)
+	return a
+}
+
+// TooltipText wraps child with a plain text tooltip.
+func TooltipText(child core.Widget, text string) *TooltipArea {
+	a := Tooltip(child, NewText(text).Wrap(true))
+	a.bubble.text = true
+	return a
+}
+
+// Command makes the tooltip show the binding of command id in the
+// window's shortcut registry after its content, as in "Save  Ctrl+S".
+func (a *TooltipArea) Command(id string) *TooltipArea {
+	a.bubble.command = id
+	return a
+}
+
+// Layout implements core.Widget.
+func (a *TooltipArea) Layout(ctx *core.LayoutContext) core.Size {
+	return ctx.Measure(a.child, ctx.Constraints)
+}
+
+// SetBounds implements core.Widget.
+func (a *TooltipArea) SetBounds(r core.Rect) {
+	a.WidgetBase.SetBounds(r)
+	a.child.SetBounds(r)
+}
+
+// Paint implements core.Widget.
+func (a *TooltipArea) Paint(ctx *core.PaintContext) {
+	a.child.Paint(ctx)
+}
+
+// HandleEvent implements core.Widget.
+func (a *TooltipArea) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	m := tooltips(ctx)
+	switch e := ev.(type) {
+	case event.MouseEvent:
+		switch e.Type {
+		case event.MouseEnter:
+			m.enter(ctx, a, e.Position)
+		case event.MouseLeave:
+			m.leave(ctx, a)
+		case event.MouseMove:
+			m.move(e.Position)
+		case event.MouseDown:
+			m.suppress(ctx)
+		}
+	case event.ScrollEvent:
+		m.suppress(ctx)
+	}
+	return core.Ignored
+}
+
+// tipCache keeps the tooltip area of an element drawn by its parent, such
+// as a toolbar button. The parent lists the area among its children, so
+// the tooltip manager sees the pointer entering and leaving the element,
+// while events bubble on to the parent.
+type tipCache struct {
+	area  *TooltipArea
+	text  string
+	child core.Widget
+}
+
+// wrap returns child wrapped in a tooltip area showing text, or child
+// itself if text is empty. A nil child is replaced by an empty widget.
+func (c *tipCache) wrap(child core.Widget, text string) core.Widget {
+	if text == "" {
+		return child
+	}
+	if c.area == nil || c.text != text || c.child != child {
+		slot := child
+		if slot == nil {
+			slot = &tipSlot{}
+		}
+		c.area, c.text, c.child = TooltipText(slot, text), text, child
+	}
+	return c.area
+}
+
+// tipSlot is the empty widget under a tooltip area kept by a tipCache.
+type tipSlot struct {
+	core.WidgetBase
+}
+
+func (*tipSlot) Layout(ctx *core.LayoutContext) core.Size { return ctx.Constraints.Min() }
+func (*tipSlot) Paint(*core.PaintContext)                 {}
+
+// tooltipBubble draws the tooltip background around its content.
+type tooltipBubble struct {
+	core.WidgetBase
+	content core.Widget
+	text    bool
+	command string
+	keys    *Text // The binding of command, if it has one.
+	keysW   float32
+}
+
+const (
+	tooltipPadding float32 = 6
+	tooltipKeysGap float32 = 12
+)
+
+// refresh looks up the binding of the command of the bubble.
+func (b *tooltipBubble) refresh(ctx *core.Context) {
+	label := ""
+	if b.command != "" {
+		label = shortcuts.Label(ctx, b.command)
+	}
+	switch {
+	case label == "":
+		b.keys = nil
+		b.SetChildren(b.content)
+		return
+	case b.keys == nil:
+		b.keys = NewText(label)
+	default:
+		b.keys.SetText(label)
+	}
+	b.SetChildren(b.content, b.keys)
+}
+
+func (b *tooltipBubble) Layout(ctx *core.LayoutContext) core.Size {
+	if t, ok := b.content.(*Text); ok && b.text {
+		th := theme.From(ctx.Context)
+		t.Color(th.Colors.Surface).FontSize(th.Typography.Caption.Size)
+	}
+	pad := core.UniformInsets(tooltipPadding)
+	c := ctx.Constraints.Loosen()
+	c.MaxWidth = min(c.MaxWidth, tooltipMaxWidth)
+	c = c.Deflate(pad)
+	var keys core.Size
+	if b.keys != nil {
+		th := theme.From(ctx.Context)
+		b.keys.Color(th.Colors.Surface.WithAlpha(0.7)).FontSize(th.Typography.Caption.Size)
+		keys = ctx.Measure(b.keys, c)
+		b.keysW = keys.Width
+		c.MaxWidth = max(0, c.MaxWidth-keys.Width-tooltipKeysGap)
+		keys.Width += tooltipKeysGap
+	}
+	size := ctx.Measure(b.content, c)
+	return core.Sz(size.Width+keys.Width+pad.Horizontal(), max(size.Height, keys.Height)+pad.Vertical())
+}
+
+func (b *tooltipBubble) SetBounds(r core.Rect) {
+	b.WidgetBase.SetBounds(r)
+	inner := r.Inset(core.UniformInsets(tooltipPadding))
+	if b.keys == nil {
+		b.content.SetBounds(inner)
+		return
+	}
+	b.content.SetBounds(core.R(inner.X, inner.Y, inner.Width-b.keysW-tooltipKeysGap, inner.Height))
+	b.keys.SetBounds(core.R(inner.Right()-b.keysW, inner.Y, b.keysW, inner.Height))
+}
+
+func (b *tooltipBubble) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	core.DrawShadow(ctx.Canvas, b.Bounds(), th.Radii.Small, th.Elevation(theme.ElevationTooltip)...)
+	ctx.Canvas.DrawRoundedRect(b.Bounds(), th.Radii.Small, core.Filled(th.Colors.OnSurface.WithAlpha(0.92)))
+	b.content.Paint(ctx)
+	if b.keys != nil {
+		b.keys.Paint(ctx)
+	}
+}
+
+// tooltipManager shows at most one tooltip per window.
+type tooltipManager struct {
+	hovered    []*TooltipArea
+	suppressed *TooltipArea
+	shown      *TooltipArea
+	overlay    *core.Overlay
+	pos        core.Point
+	gen        int
+	lastHidden time.Time
+}
+
+type tooltipKey struct{}
+
+func tooltips(ctx *core.Context) *tooltipManager {
+	if m, ok := ctx.Value(tooltipKey{}).(*tooltipManager); ok {
+		return m
+	}
+	m := &tooltipManager{}
+	ctx.SetValue(tooltipKey{}, m)
+	return m
+}
+
+func (m *tooltipManager) enter(ctx *core.Context, a *TooltipArea, p core.Point) {
+	if !slices.Contains(m.hovered, a) {
+		m.hovered = append(m.hovered, a)
+	}
+	m.pos = p
+	m.update(ctx)
+}
+
+func (m *tooltipManager) leave(ctx *core.Context, a *TooltipArea) {
+	m.hovered = slices.DeleteFunc(m.hovered, func(x *TooltipArea) bool { return x == a })
+	if m.suppressed == a {
+		m.suppressed = nil
+	}
+	m.update(ctx)
+}
+
+func (m *tooltipManager) move(p core.Point) {
+	if m.shown == nil {
+		m.pos = p // The tooltip appears where the pointer came to rest.
+	}
+}
+
+// suppress hides the tooltip and keeps it hidden until the pointer leaves
+// the area.
+func (m *tooltipManager) suppress(ctx *core.Context) {
+	m.suppressed = m.target()
+	m.gen++
+	m.hide(ctx)
+}
+
+// target returns the innermost hovered area.
+func (m *tooltipManager) target() *TooltipArea {
+	var best *TooltipArea
+	for _, a := range m.hovered {
+		if best == nil || core.PathTo(best, a) != nil {
+			best = a
+		}
+	}
+	return best
+}
+
+func (m *tooltipManager) update(ctx *core.Context) {
+	t := m.target()
+	if t == m.shown && t != nil {
+		return
+	}
+	m.gen++
+	if m.shown != nil {
+		m.hide(ctx)
+	}
+	if t == nil || t == m.suppressed {
+		return
+	}
+	delay := tooltipDelay
+	if time.Since(m.lastHidden) < tooltipReshowWindow {
+		delay = tooltipReshowDelay
+	}
+	gen := m.gen
+	time.AfterFunc(delay, func() {
+		ctx.Post(func() {
+			if m.gen == gen && m.target() == t {
+				m.show(ctx, t)
+			}
+		})
+	})
+}
+
+func (m *tooltipManager) show(ctx *core.Context, a *TooltipArea) {
+	m.shown = a
+	a.bubble.refresh(ctx)
+	anchor := core.R(m.pos.X, m.pos.Y, 1, tooltipOffset)
+	o := &core.Overlay{
+		Content:   a.bubble,
+		Placement: core.PlaceAnchored(anchor, core.SideBelow),
+		Passive:   true,
+		Popup:     true,
+	}
+	o.OnClose = func() {
+		// The window closes passive overlays on any press.
+		if m.overlay == o {
+			m.overlay, m.shown = nil, nil
+			m.suppressed = a
+			m.lastHidden = time.Now()
+		}
+	}
+	m.overlay = o
+	ctx.ShowOverlay(o)
+	gen := m.gen
+	time.AfterFunc(tooltipTimeout, func() {
+		ctx.Post(func() {
+			if m.gen == gen && m.shown == a {
+				m.suppress(ctx)
+			}
+		})
+	})
+}
+
+func (m *tooltipManager) hide(ctx *core.Context) {
+	if m.shown == nil {
+		return
+	}
+	m.shown = nil
+	m.lastHidden = time.Now()
+	if o := m.overlay; o != nil {
+		m.overlay = nil
+		ctx.CloseOverlay(o)
+	}
+}
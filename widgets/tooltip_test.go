@@ -0,0 +1,157 @@
+package widgets
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+)
+
+// hover delivers a mouse event of typ at p to a.
+func hover(ctx *core.Context, a *TooltipArea, typ event.MouseEventType, p core.Point) {
+	a.HandleEvent(ctx, event.MouseEvent{Type: typ, Position: p})
+}
+
+// tip returns the open tooltip overlay of ctx, or nil.
+func tip(ctx *core.Context) *core.Overlay {
+	for _, o := range ctx.Overlays() {
+		if _, ok := o.Content.(*tooltipBubble); ok {
+			return o
+		}
+	}
+	return nil
+}
+
+func TestTooltipShows(t *testing.T) {
+	a := TooltipText(&fixed{height: 20}, "Save the file")
+	ctx := layoutAt(a, core.R(0, 0, 100, 20))
+	start := time.Now()
+	hover(ctx, a, event.MouseEnter, core.Pt(10, 5))
+	hover(ctx, a, event.MouseMove, core.Pt(20, 8))
+	runPosted(t, ctx)
+	if d := time.Since(start); d < tooltipDelay {
+		t.Errorf("shown after %v, before the delay", d)
+	}
+	o := tip(ctx)
+	if o == nil {
+		t.Fatal("no tooltip shown")
+	}
+	if !o.Passive {
+		t.Error("the tooltip takes input")
+	}
+	layoutOverlays(ctx)
+	if got := o.Bounds().Origin(); got != core.Pt(20, 8+tooltipOffset) {
+		t.Errorf("tooltip at %v, want below where the pointer rested", got)
+	}
+	if o.Bounds().Width > tooltipMaxWidth {
+		t.Errorf("tooltip %v wide, wider than %v", o.Bounds().Width, tooltipMaxWidth)
+	}
+
+	// Moving on to another tooltip shows it almost at once.
+	hover(ctx, a, event.MouseLeave, core.Pt(-1, -1))
+	if tip(ctx) != nil {
+		t.Error("the tooltip stayed after the pointer left")
+	}
+	b := TooltipText(&fixed{height: 20}, "Open")
+	start = time.Now()
+	hover(ctx, b, event.MouseEnter, core.Pt(10, 5))
+	runPosted(t, ctx)
+	if d := time.Since(start); d >= tooltipDelay {
+		t.Errorf("the next tooltip took %v", d)
+	}
+	if o := tip(ctx); o == nil || o.Content != b.bubble {
+		t.Error("the next tooltip is not shown")
+	}
+}
+
+func TestTooltipCancelled(t *testing.T) {
+	tests := []struct {
+		name  string
+		after func(ctx *core.Context, a *TooltipArea)
+	}{
+		{"left early", func(ctx *core.Context, a *TooltipArea) { hover(ctx, a, event.MouseLeave, core.Pt(-1, -1)) }},
+		{"pressed", func(ctx *core.Context, a *TooltipArea) { hover(ctx, a, event.MouseDown, core.Pt(10, 5)) }},
+		{"scrolled", func(ctx *core.Context, a *TooltipArea) { a.HandleEvent(ctx, event.ScrollEvent{Delta: core.Pt(0, 10)}) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := TooltipText(&fixed{height: 20}, "Save")
+			ctx := layoutAt(a, core.R(0, 0, 100, 20))
+			hover(ctx, a, event.MouseEnter, core.Pt(10, 5))
+			tt.after(ctx, a)
+			runPosted(t, ctx)
+			if tip(ctx) != nil {
+				t.Error("the tooltip was shown")
+			}
+		})
+	}
+}
+
+func TestTooltipSuppressedUntilLeave(t *testing.T) {
+	a := TooltipText(&fixed{height: 20}, "Save")
+	ctx := layoutAt(a, core.R(0, 0, 100, 20))
+	hover(ctx, a, event.MouseEnter, core.Pt(10, 5))
+	runPosted(t, ctx)
+	hover(ctx, a, event.MouseDown, core.Pt(10, 5))
+	if tip(ctx) != nil {
+		t.Fatal("a press left the tooltip shown")
+	}
+	hover(ctx, a, event.MouseEnter, core.Pt(10, 5)) // Still inside.
+	if ctx.HasPosted() || tip(ctx) != nil {
+		t.Error("the tooltip came back before the pointer left")
+	}
+	hover(ctx, a, event.MouseLeave, core.Pt(-1, -1))
+	hover(ctx, a, event.MouseEnter, core.Pt(10, 5))
+	runPosted(t, ctx)
+	if tip(ctx) == nil {
+		t.Error("the tooltip did not show after the pointer came back")
+	}
+}
+
+func TestTooltipInnermost(t *testing.T) {
+	inner := TooltipText(&fixed{height: 20}, "inner")
+	outer := TooltipText(inner, "outer")
+	ctx := layoutAt(outer, core.R(0, 0, 100, 20))
+	hover(ctx, outer, event.MouseEnter, core.Pt(10, 5))
+	runPosted(t, ctx)
+	// Wait for one timer at a time: posts of timers started together may
+	// arrive in any order.
+	hover(ctx, inner, event.MouseEnter, core.Pt(10, 5))
+	runPosted(t, ctx)
+	if o := tip(ctx); o == nil || o.Content != inner.bubble {
+		t.Fatal("the inner tooltip is not shown")
+	}
+	hover(ctx, inner, event.MouseLeave, core.Pt(-1, -1))
+	runPosted(t, ctx)
+	if o := tip(ctx); o == nil || o.Content != outer.bubble {
+		t.Error("the outer tooltip is not shown after leaving the inner one")
+	}
+}
+
+func TestTipCache(t *testing.T) {
+	var c tipCache
+	child := &fixed{height: 20}
+	if got := c.wrap(child, ""); got != child {
+		t.Error("a child without a tooltip was wrapped")
+	}
+	a := c.wrap(child, "hint")
+	if ta, ok := a.(*TooltipArea); !ok || ta.child != child {
+		t.Fatalf("wrap returned %T", a)
+	}
+	if c.wrap(child, "hint") != a {
+		t.Error("the area was not reused")
+	}
+	b := c.wrap(child, "other")
+	if b == a {
+		t.Error("the area was reused for another text")
+	}
+	d := c.wrap(nil, "other")
+	if ta := d.(*TooltipArea); d == b || ta.child == nil {
+		t.Error("a nil child was not given a slot")
+	}
+	lc := &core.LayoutContext{Context: core.NewContext()}
+	if got := lc.Measure(d, core.Loose(core.Sz(50, 50))); got != core.Sz(0, 0) {
+		t.Errorf("the empty slot takes %v", got)
+	}
+}
@@ -0,0 +1,370 @@
+package widgets
+
+import (
+	"slices"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+)
+
+// TreeNode is a node of the hierarchy displayed by TreeView and TreeTable.
+//
+// Nodes with Lazy set have children that are fetched by the view's
+// ChildLoader the first time they are expanded.
+type TreeNode struct {
+	// Label is the text displayed for the node.
+	Label string
+
+	// Value is application data attached to the node.
+	Value any
+
+	// Children are the node's children. For lazy nodes they are filled in
+	// by the loader.
+	Children []*TreeNode
+
+	// Lazy marks a node whose children are loaded on first expansion.
+	Lazy bool
+
+	parent   *TreeNode
+	expanded bool
+	load     loadState
+	err      error
+}
+
+type loadState uint8
+
+const (
+	loadIdle loadState = iota
+	loadPending
+	loadDone
+	loadFailed
+)
+
+// NewTreeNode returns a node with the given label and children.
+func NewTreeNode(label string, children ...*TreeNode) *TreeNode {
+	n := &TreeNode{Label: label}
+	n.Add(children...)
+	return n
+}
+
+// Add appends children to the node.
+func (n *TreeNode) Add(children ...*TreeNode) *TreeNode {
+	for _, c := range children {
+		c.parent = n
+	}
+	n.Children = append(n.Children, children...)
+	return n
+}
+
+// Parent returns the node's parent, or nil for a root.
+func (n *TreeNode) Parent() *TreeNode {
+	return n.parent
+}
+
+// Expanded reports whether the node's children are shown.
+func (n *TreeNode) Expanded() bool {
+	return n.expanded
+}
+
+// Loading reports whether the node's children are being loaded.
+func (n *TreeNode) Loading() bool {
+	return n.load == loadPending
+}
+
+// LoadError returns the error from the last failed load, or nil.
+func (n *TreeNode) LoadError() error {
+	return n.err
+}
+
+// HasChildren reports whether the node has, or may have, children.
+func (n *TreeNode) HasChildren() bool {
+	return len(n.Children) > 0 || (n.Lazy && n.load != loadDone)
+}
+
+// Reload discards the children of a lazy node so that they are fetched
+// again on the next expansion.
+func (n *TreeNode) Reload() {
+	if n.Lazy {
+		n.Children = nil
+		n.load = loadIdle
+		n.err = nil
+		n.expanded = false
+	}
+}
+
+// ChildLoader fetches the children of a lazy node. It runs on a background
+// goroutine; the result is applied on the UI goroutine.
+type ChildLoader func(node *TreeNode) ([]*TreeNode, error)
+
+type treeRow struct {
+	node  *TreeNode
+	depth int
+}
+
+// treeModel is the expansion, lazy loading and selection logic shared by
+// TreeView and TreeTable. It exposes the visible nodes as a flat row list.
+type treeModel struct {
+	owner    core.Widget // The view showing the tree.
+	roots    []*TreeNode
+	rows     []treeRow
+	dirty    bool
+	loader   ChildLoader
+	multi    bool
+	selected map[*TreeNode]bool
+	cursor   *TreeNode
+	anchor   *TreeNode
+
+	// order, if set, sorts siblings for display without changing the
+	// Children slices.
+	order func(a, b *TreeNode) int
+
+	onSelect   func(nodes []*TreeNode)
+	onActivate func(node *TreeNode)
+	onExpand   func(node *TreeNode, expanded bool)
+}
+
+func newTreeModel(owner core.Widget, roots []*TreeNode) treeModel {
+	for _, r := range roots {
+		r.parent = nil
+	}
+	return treeModel{owner: owner, roots: roots, dirty: true, selected: make(map[*TreeNode]bool)}
+}
+
+func (m *treeModel) flatten() {
+	if !m.dirty {
+		return
+	}
+	m.dirty = false
+	m.rows = m.rows[:0]
+	var walk func(nodes []*TreeNode, parent *TreeNode, depth int)
+	walk = func(nodes []*TreeNode, parent *TreeNode, depth int) {
+		if m.order != nil {
+			nodes = slices.Clone(nodes)
+			slices.SortStableFunc(nodes, m.order)
+		}
+		for _, n := range nodes {
+			n.parent = parent
+			m.rows = append(m.rows, treeRow{node: n, depth: depth})
+			if n.expanded {
+				walk(n.Children, n, depth+1)
+			}
+		}
+	}
+	walk(m.roots, nil, 0)
+	if m.cursor != nil && m.rowOf(m.cursor) < 0 {
+		// The cursor was hidden by a collapse; move it to the nearest
+		// visible ancestor.
+		for p := m.cursor.parent; p != nil; p = p.parent {
+			if m.rowOf(p) >= 0 {
+				m.cursor = p
+				break
+			}
+		}
+	}
+}
+
+func (m *treeModel) rowOf(n *TreeNode) int {
+	for i, r := range m.rows {
+		if r.node == n {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *treeModel) setExpanded(ctx *core.Context, n *TreeNode, expanded bool) {
+	if n == nil || n.expanded == expanded || (expanded && !n.HasChildren()) {
+		return
+	}
+	n.expanded = expanded
+	m.dirty = true
+	if expanded && n.Lazy && (n.load == loadIdle || n.load == loadFailed) {
+		m.loadChildren(ctx, n)
+	}
+	if m.onExpand != nil {
+		m.onExpand(n, expanded)
+	}
+	ctx.MarkNeedsLayout(m.owner)
+}
+
+func (m *treeModel) loadChildren(ctx *core.Context, n *TreeNode) {
+	if m.loader == nil {
+		n.load = loadDone
+		return
+	}
+	n.load, n.err = loadPending, nil
+	loader := m.loader
+	go func() {
+		children, err := loader(n)
+		ctx.Post(func() {
+			if n.load != loadPending {
+				return // Reloaded in the meantime.
+			}
+			if err != nil {
+				n.load, n.err = loadFailed, err
+				n.expanded = false
+			} else {
+				n.load = loadDone
+				n.Children = nil
+				n.Add(children...)
+			}
+			m.dirty = true
+			ctx.MarkNeedsLayout(m.owner)
+		})
+	}()
+}
+
+func (m *treeModel) selection() []*TreeNode {
+	m.flatten()
+	var out []*TreeNode
+	for _, r := range m.rows {
+		if m.selected[r.node] {
+			out = append(out, r.node)
+		}
+	}
+	return out
+}
+
+func (m *treeModel) notifySelect() {
+	if m.onSelect != nil {
+		m.onSelect(m.selection())
+	}
+}
+
+// selectNode applies a click or keyboard selection to n. Shift extends
+// from the anchor; Ctrl toggles in multi-select mode.
+func (m *treeModel) selectNode(n *TreeNode, mods event.Modifiers) {
+	m.flatten()
+	m.cursor = n
+	toggle := mods.Has(event.ModCtrl) || mods.Has(event.ModSuper)
+	switch {
+	case m.multi && mods.Has(event.ModShift) && m.anchor != nil:
+		a, b := m.rowOf(m.anchor), m.rowOf(n)
+		if a < 0 {
+			a = b
+		}
+		if !toggle {
+			clear(m.selected)
+		}
+		for i := min(a, b); i <= max(a, b); i++ {
+			m.selected[m.rows[i].node] = true
+		}
+	case m.multi && toggle:
+		if m.selected[n] {
+			delete(m.selected, n)
+		} else {
+			m.selected[n] = true
+		}
+		m.anchor = n
+	default:
+		clear(m.selected)
+		m.selected[n] = true
+		m.anchor = n
+	}
+	m.notifySelect()
+}
+
+// handleKey implements tree keyboard navigation. It returns whether the
+// key was consumed.
+func (m *treeModel) handleKey(ctx *core.Context, e event.KeyEvent, pageRows int) bool {
+	m.flatten()
+	if len(m.rows) == 0 {
+		return false
+	}
+	cur := m.rowOf(m.cursor)
+	next := cur
+	move := func(i int) {
+		next = min(max(i, 0), len(m.rows)-1)
+	}
+	switch e.Key {
+	case event.KeyUp:
+		move(cur - 1)
+	case event.KeyDown:
+		move(cur + 1)
+	case event.KeyPageUp:
+		move(cur - pageRows)
+	case event.KeyPageDown:
+		move(cur + pageRows)
+	case event.KeyHome:
+		move(0)
+	case event.KeyEnd:
+		move(len(m.rows) - 1)
+	case event.KeyLeft:
+		if cur < 0 {
+			move(0)
+			break
+		}
+		n := m.rows[cur].node
+		if n.expanded {
+			m.setExpanded(ctx, n, false)
+			return true
+		}
+		if n.parent != nil {
+			move(m.rowOf(n.parent))
+		}
+	case event.KeyRight:
+		if cur < 0 {
+			move(0)
+			break
+		}
+		n := m.rows[cur].node
+		if !n.expanded && n.HasChildren() {
+			m.setExpanded(ctx, n, true)
+			return true
+		}
+		if n.expanded && len(n.Children) > 0 {
+			move(cur + 1)
+		}
+	case event.KeySpace:
+		if cur >= 0 {
+			m.selectNode(m.rows[cur].node, e.Modifiers|event.ModCtrl)
+			ctx.Repaint(m.owner)
+		}
+		return true
+	case event.KeyEnter:
+		if cur >= 0 {
+			m.activate(ctx, m.rows[cur].node)
+		}
+		return true
+	default:
+		return false
+	}
+	if next >= 0 && next != cur {
+		n := m.rows[next].node
+		if e.Modifiers.Has(event.ModCtrl) && m.multi {
+			m.cursor = n // Move the cursor without changing the selection.
+		} else {
+			m.selectNode(n, e.Modifiers)
+		}
+		ctx.Repaint(m.owner)
+	}
+	return true
+}
+
+// activate runs the activation callback, or toggles expansion if none is
+// registered.
+func (m *treeModel) activate(ctx *core.Context, n *TreeNode) {
+	if m.onActivate != nil {
+		m.onActivate(n)
+		return
+	}
+	m.setExpanded(ctx, n, !n.expanded)
+}
+
+// paintDisclosure draws an expand/collapse chevron centered in r. The
+// collapsed chevron points toward the end edge.
+func paintDisclosure(ctx *core.PaintContext, r core.Rect, expanded bool, c core.Color) {
+	cx, cy := r.Center().X, r.Center().Y
+	const s = 4
+	p := core.NewPath()
+	switch {
+	case expanded:
+		p.MoveTo(core.Pt(cx-s, cy-s/2)).LineTo(core.Pt(cx+s, cy-s/2)).LineTo(core.Pt(cx, cy+s/2+1))
+	case ctx.Direction().IsRTL():
+		p.MoveTo(core.Pt(cx+s/2, cy-s)).LineTo(core.Pt(cx-s/2-1, cy)).LineTo(core.Pt(cx+s/2, cy+s))
+	default:
+		p.MoveTo(core.Pt(cx-s/2, cy-s)).LineTo(core.Pt(cx+s/2+1, cy)).LineTo(core.Pt(cx-s/2, cy+s))
+	}
+	p.Close()
+	ctx.Canvas.DrawPath(p, core.PathStyle{Fill: c})
+}
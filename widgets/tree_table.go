@@ -0,0 +1,401 @@
+package widgets
+
+import (
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/internal/scroll"
+	"github.com/gogpu/ui/theme"
+)
+
+// TreeValueFunc returns the value of the cell for node in the column with
+// key.
+type TreeValueFunc func(node *TreeNode, key string) any
+
+// TreeTable combines a TreeView hierarchy with DataGrid columns, like the
+// variables and watch panels of a debugger. One column, by default the
+// first, shows the indented node hierarchy; the others show values from a
+// TreeValueFunc.
+//
+// Columns use the DataGrid column model: they can be resized, reordered
+// and frozen, and clicking a sortable header sorts siblings at every level
+// without flattening the hierarchy. Rows are virtualized. In right-to-left
+// text the table is mirrored like a DataGrid and the hierarchy is indented
+// from the right.
+type TreeTable struct {
+	core.WidgetBase
+	core.FocusState
+
+	model     treeModel
+	columns   columnSet
+	value     TreeValueFunc
+	treeKey   string
+	rowHeight float32
+	indent    float32
+
+	scrollX, scrollY float32
+	vbar, hbar       scroll.Bar
+	header, body     core.Rect
+
+	onColumns func(columns []Column)
+}
+
+// NewTreeTable returns a tree table showing roots with the given columns.
+func NewTreeTable(roots []*TreeNode, value TreeValueFunc, columns ...Column) *TreeTable {
+	t := &TreeTable{
+		columns:   newColumnSet(columns),
+		value:     value,
+		rowHeight: defaultTreeRowHeight,
+		indent:    defaultTreeIndent,
+	}
+	t.model = newTreeModel(t, roots)
+	if len(columns) > 0 {
+		t.treeKey = columns[0].Key
+	}
+	return t
+}
+
+// TreeColumn selects the column that displays the hierarchy.
+func (t *TreeTable) TreeColumn(key string) *TreeTable {
+	t.treeKey = key
+	return t
+}
+
+// LoadChildren sets the loader for lazy nodes.
+func (t *TreeTable) LoadChildren(loader ChildLoader) *TreeTable {
+	t.model.loader = loader
+	return t
+}
+
+// MultiSelect enables or disables multiple selection.
+func (t *TreeTable) MultiSelect(multi bool) *TreeTable {
+	t.model.multi = multi
+	return t
+}
+
+// RowHeight sets the height of every row.
+func (t *TreeTable) RowHeight(h float32) *TreeTable {
+	if h > 0 {
+		t.rowHeight = h
+	}
+	return t
+}
+
+// OnSelectionChange registers fn to be called with the selected nodes.
+func (t *TreeTable) OnSelectionChange(fn func(nodes []*TreeNode)) *TreeTable {
+	t.model.onSelect = fn
+	return t
+}
+
+// OnActivate registers fn to be called when a row is double-clicked or
+// Enter is pressed. Without a handler activation toggles expansion.
+func (t *TreeTable) OnActivate(fn func(node *TreeNode)) *TreeTable {
+	t.model.onActivate = fn
+	return t
+}
+
+// OnColumnsChange registers fn to be called after the user resizes or
+// reorders columns.
+func (t *TreeTable) OnColumnsChange(fn func(columns []Column)) *TreeTable {
+	t.onColumns = fn
+	return t
+}
+
+// Columns returns a copy of the columns in display order.
+func (t *TreeTable) Columns() []Column {
+	out := make([]Column, len(t.columns.cols))
+	for i, c := range t.columns.cols {
+		out[i] = *c
+	}
+	return out
+}
+
+// Selected returns the selected nodes in display order.
+func (t *TreeTable) Selected() []*TreeNode {
+	return t.model.selection()
+}
+
+// Refresh re-reads the hierarchy and values after they changed.
+func (t *TreeTable) Refresh() {
+	t.model.dirty = true
+}
+
+// Expand expands n, loading its children if it is lazy.
+func (t *TreeTable) Expand(ctx *core.Context, n *TreeNode) {
+	t.model.setExpanded(ctx, n, true)
+}
+
+// Collapse collapses n.
+func (t *TreeTable) Collapse(ctx *core.Context, n *TreeNode) {
+	t.model.setExpanded(ctx, n, false)
+}
+
+// SortBy sorts siblings by the column with key. SortNone restores the
+// natural order.
+func (t *TreeTable) SortBy(key string, dir SortDirection) {
+	if dir == SortNone {
+		key = ""
+	}
+	t.columns.sortKey, t.columns.sortDir = key, dir
+	t.applySort()
+}
+
+func (t *TreeTable) applySort() {
+	t.model.dirty = true
+	i := t.columns.index(t.columns.sortKey)
+	if i < 0 || t.columns.sortDir == SortNone {
+		t.model.order = nil
+		return
+	}
+	col := t.columns.cols[i]
+	desc := t.columns.sortDir == SortDescending
+	t.model.order = func(a, b *TreeNode) int {
+		c := col.compare(t.cellValue(a, col.Key), t.cellValue(b, col.Key))
+		if desc {
+			return -c
+		}
+		return c
+	}
+}
+
+func (t *TreeTable) cellValue(n *TreeNode, key string) any {
+	if t.value != nil {
+		return t.value(n, key)
+	}
+	if key == t.treeKey {
+		return n.Label
+	}
+	return nil
+}
+
+// Layout implements core.Widget.
+func (t *TreeTable) Layout(ctx *core.LayoutContext) core.Size {
+	t.columns.dir = ctx.Direction()
+	t.model.flatten()
+	size := core.Size{
+		Width:  t.columns.frozenWidth() + t.columns.scrollableWidth() + scroll.Thickness,
+		Height: defaultHeaderHeight + float32(len(t.model.rows))*t.rowHeight + scroll.Thickness,
+	}
+	c := ctx.Constraints
+	if c.HasBoundedWidth() {
+		size.Width = c.MaxWidth
+	}
+	if c.HasBoundedHeight() {
+		size.Height = c.MaxHeight
+	}
+	return c.Constrain(size)
+}
+
+// SetBounds implements core.Widget.
+func (t *TreeTable) SetBounds(r core.Rect) {
+	t.WidgetBase.SetBounds(r)
+	track, rest := scroll.EndTrack(r, t.columns.dir)
+	t.header = core.R(rest.X, r.Y, rest.Width, defaultHeaderHeight)
+	t.body = core.R(rest.X, t.header.Bottom(), rest.Width, max(0, r.Height-defaultHeaderHeight-scroll.Thickness))
+
+	t.vbar.Track = core.R(track.X, t.body.Y, track.Width, t.body.Height)
+	t.vbar.Viewport = t.body.Height
+	t.vbar.Content = float32(len(t.model.rows)) * t.rowHeight
+
+	area := t.columns.scrollArea(t.body)
+	t.hbar.Horizontal = true
+	t.hbar.Track = core.R(area.X, t.body.Bottom(), area.Width, scroll.Thickness)
+	t.hbar.Viewport = t.hbar.Track.Width
+	t.hbar.Content = t.columns.scrollableWidth()
+
+	t.scrollX = core.Clamp(t.scrollX, 0, t.hbar.MaxOffset())
+	t.scrollY = core.Clamp(t.scrollY, 0, t.vbar.MaxOffset())
+}
+
+// Paint implements core.Widget.
+func (t *TreeTable) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	cv.Save()
+	cv.Clip(t.Bounds())
+	cv.DrawRect(t.Bounds(), core.Filled(th.Colors.Surface))
+
+	nf := t.columns.frozenCount()
+	frozen := t.columns.frozenArea(t.body)
+	t.paintRows(ctx, nf, len(t.columns.cols), t.columns.scrollArea(t.body))
+	t.paintRows(ctx, 0, nf, frozen)
+	if nf > 0 {
+		cv.DrawRect(t.columns.endLine(core.R(frozen.X, t.header.Y, frozen.Width, t.header.Height+t.body.Height)), core.Filled(th.Colors.Outline))
+	}
+	t.columns.paintHeader(ctx, t.header, t.scrollX)
+	t.vbar.Paint(ctx, t.scrollY)
+	t.hbar.Paint(ctx, t.columns.barOffset(&t.hbar, t.scrollX))
+	cv.Restore()
+}
+
+func (t *TreeTable) paintRows(ctx *core.PaintContext, from, to int, area core.Rect) {
+	if from >= to || area.IsEmpty() {
+		return
+	}
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	cv.Save()
+	cv.Clip(area)
+	style := theme.TextStyle(th.Typography.Body, th.Colors.OnSurface)
+	first := max(0, int(t.scrollY/t.rowHeight))
+	last := min(len(t.model.rows), int((t.scrollY+t.body.Height)/t.rowHeight)+1)
+	for i := first; i < last; i++ {
+		row := t.model.rows[i]
+		y := t.body.Y + float32(i)*t.rowHeight - t.scrollY
+		if t.model.selected[row.node] {
+			cv.DrawRect(core.R(area.X, y, area.Width, t.rowHeight), core.Filled(th.Colors.Selection))
+		}
+		for c := from; c < to; c++ {
+			col := t.columns.cols[c]
+			cell := core.R(t.columns.columnX(c, t.body, t.scrollX), y, col.Width, t.rowHeight)
+			if cell.Right() < area.X || cell.X > area.Right() {
+				continue
+			}
+			if col.Key == t.treeKey {
+				disclosure, text := t.treeCell(cell, row.depth)
+				if row.node.HasChildren() {
+					paintDisclosure(ctx, disclosure, row.node.expanded, th.Colors.OnSurfaceVariant)
+				}
+				label := col.format(t.cellValue(row.node, col.Key))
+				if row.node.Loading() {
+					label += "  Loading…"
+				}
+				drawCellText(ctx, text, label, style, CellAlignStart)
+				continue
+			}
+			value := t.cellValue(row.node, col.Key)
+			if col.Paint != nil {
+				col.Paint(ctx, cell, value)
+			} else {
+				drawCellText(ctx, cell, col.format(value), style, col.Align)
+			}
+		}
+		if row.node == t.model.cursor && t.IsFocused() {
+			cv.DrawRect(core.R(area.X, y, area.Width, t.rowHeight).Inset(core.UniformInsets(0.5)), core.Stroked(th.Colors.Primary, 1))
+		}
+	}
+	cv.Restore()
+}
+
+// treeCell splits cell of the hierarchy column in a row at depth into the
+// disclosure chevron, indented from the start edge, and the label after it.
+func (t *TreeTable) treeCell(cell core.Rect, depth int) (disclosure, text core.Rect) {
+	indent := float32(depth) * t.indent
+	disclosure = core.R(cell.X+indent, cell.Y, disclosureWidth, cell.Height)
+	x := disclosure.Right() - cellPadding
+	text = core.R(x, cell.Y, max(0, cell.Right()-x), cell.Height)
+	return t.columns.dir.Mirror(disclosure, cell), t.columns.dir.Mirror(text, cell)
+}
+
+func (t *TreeTable) rowAt(p core.Point) int {
+	if !t.body.Contains(p) {
+		return -1
+	}
+	i := int((p.Y - t.body.Y + t.scrollY) / t.rowHeight)
+	if i < 0 || i >= len(t.model.rows) {
+		return -1
+	}
+	return i
+}
+
+func (t *TreeTable) scrollToCursor() {
+	i := t.model.rowOf(t.model.cursor)
+	if i < 0 {
+		return
+	}
+	top := float32(i) * t.rowHeight
+	if top < t.scrollY {
+		t.scrollY = top
+	} else if top+t.rowHeight > t.scrollY+t.body.Height {
+		t.scrollY = top + t.rowHeight - t.body.Height
+	}
+	t.scrollY = max(0, t.scrollY)
+}
+
+// HandleEvent implements core.Widget.
+func (t *TreeTable) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	if off, ok := t.vbar.HandleEvent(ctx, t, ev, t.scrollY); ok {
+		if off != t.scrollY {
+			t.scrollY = off
+			ctx.Repaint(t)
+		}
+		return core.Handled
+	}
+	if off, ok := t.hbar.HandleEvent(ctx, t, ev, t.columns.barOffset(&t.hbar, t.scrollX)); ok {
+		if off = t.columns.barOffset(&t.hbar, off); off != t.scrollX {
+			t.scrollX = off
+			ctx.Repaint(t)
+		}
+		return core.Handled
+	}
+	switch e := ev.(type) {
+	case event.ScrollEvent:
+		dx, dy := e.Delta.X, e.Delta.Y
+		if e.Modifiers.Has(event.ModShift) && dx == 0 {
+			dx, dy = dy, 0
+		}
+		oldX, oldY := t.scrollX, t.scrollY
+		t.scrollX = core.Clamp(t.scrollX+dx, 0, t.hbar.MaxOffset())
+		t.scrollY = core.Clamp(t.scrollY+dy, 0, t.vbar.MaxOffset())
+		if oldX != t.scrollX || oldY != t.scrollY {
+			ctx.Repaint(t)
+			return core.Handled
+		}
+	case event.MouseEvent:
+		return t.handleMouse(ctx, e)
+	case event.KeyEvent:
+		e.Key = mirrorKey(t.columns.dir, e.Key)
+		if e.Type == event.KeyPress && t.IsFocused() &&
+			t.model.handleKey(ctx, e, max(1, int(t.body.Height/t.rowHeight)-1)) {
+			t.model.flatten()
+			t.scrollToCursor()
+			return core.Handled
+		}
+	}
+	return core.Ignored
+}
+
+func (t *TreeTable) handleMouse(ctx *core.Context, e event.MouseEvent) core.EventResult {
+	switch t.columns.handleHeader(ctx, t, e, t.header, t.scrollX) {
+	case headerSorted:
+		t.applySort()
+		return core.Handled
+	case headerResized, headerReordered:
+		if e.Type == event.MouseUp && t.onColumns != nil {
+			t.onColumns(t.Columns())
+		}
+		return core.Handled
+	case headerHandled:
+		return core.Handled
+	}
+	if e.Type != event.MouseDown || !t.body.Contains(e.Position) {
+		return core.Ignored
+	}
+	ctx.RequestFocus(t)
+	i := t.rowAt(e.Position)
+	if i < 0 {
+		if e.Button == event.ButtonRight {
+			return core.Ignored
+		}
+		return core.Handled
+	}
+	row := t.model.rows[i]
+	if tc := t.columns.index(t.treeKey); tc >= 0 && row.node.HasChildren() {
+		cell := core.R(t.columns.columnX(tc, t.body, t.scrollX), e.Position.Y, t.columns.cols[tc].Width, 1)
+		if disclosure, _ := t.treeCell(cell, row.depth); e.Position.X >= disclosure.X && e.Position.X < disclosure.Right() {
+			t.model.setExpanded(ctx, row.node, !row.node.expanded)
+			return core.Handled
+		}
+	}
+	if e.Button == event.ButtonRight && t.model.selected[row.node] {
+		return core.Ignored // Keep the selection for a context menu.
+	}
+	t.model.selectNode(row.node, e.Modifiers)
+	if e.ClickCount == 2 && e.Button == event.ButtonLeft {
+		t.model.activate(ctx, row.node)
+	}
+	ctx.Repaint(t)
+	if e.Button == event.ButtonRight {
+		return core.Ignored
+	}
+	return core.Handled
+}
@@ -0,0 +1,116 @@
+package widgets
+
+import (
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+)
+
+// sizes returns a tree of directories and files with sizes, expanded.
+func sizes() ([]*TreeNode, TreeValueFunc) {
+	size := map[string]int{"src": 30, "main.go": 10, "util.go": 20, "README": 5, "LICENSE": 7}
+	src := NewTreeNode("src", NewTreeNode("util.go"), NewTreeNode("main.go"))
+	src.expanded = true
+	roots := []*TreeNode{src, NewTreeNode("README"), NewTreeNode("LICENSE")}
+	return roots, func(n *TreeNode, key string) any {
+		if key == "size" {
+			return size[n.Label]
+		}
+		return n.Label
+	}
+}
+
+func TestTreeTableSort(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+		dir  SortDirection
+		want string
+	}{
+		{"unsorted", "", SortNone, "src .util.go .main.go README LICENSE"},
+		{"by name", "name", SortAscending, "LICENSE README src .main.go .util.go"},
+		{"by size", "size", SortAscending, "README LICENSE src .main.go .util.go"},
+		{"by size descending", "size", SortDescending, "src .util.go .main.go LICENSE README"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roots, value := sizes()
+			table := NewTreeTable(roots, value, Column{Key: "name"}, Column{Key: "size"})
+			table.SortBy(tt.key, tt.dir)
+			if got := shown(&table.model); got != tt.want {
+				t.Errorf("rows = %q, want %q", got, tt.want)
+			}
+			if got := labels(roots[0].Children); got != "util.go main.go" {
+				t.Errorf("sorting changed the children to %q", got)
+			}
+		})
+	}
+}
+
+func TestTreeTableHeaderSorts(t *testing.T) {
+	roots, value := sizes()
+	tt := NewTreeTable(roots, value, Column{Key: "name", Width: 100, Sortable: true}, Column{Key: "size", Width: 60, Sortable: true})
+	ctx := layoutAt(tt, core.R(0, 0, 300, 300))
+	for _, typ := range []event.MouseEventType{event.MouseDown, event.MouseUp} {
+		tt.HandleEvent(ctx, event.MouseEvent{Type: typ, Position: core.Pt(130, 10), Button: event.ButtonLeft})
+	}
+	if got, want := shown(&tt.model), "README LICENSE src .main.go .util.go"; got != want {
+		t.Errorf("rows = %q, want %q", got, want)
+	}
+}
+
+func TestTreeTableTreeColumn(t *testing.T) {
+	// The disclosure is drawn in the tree column, here the second one.
+	roots, value := sizes()
+	roots[0].expanded = false
+	tt := NewTreeTable(roots, value, Column{Key: "size", Width: 60}, Column{Key: "name", Width: 100}).
+		TreeColumn("name").RowHeight(20)
+	ctx := layoutAt(tt, core.R(0, 0, 300, 300))
+	y := tt.body.Y + 10
+	tt.HandleEvent(ctx, event.MouseEvent{Type: event.MouseDown, Position: core.Pt(5, y), Button: event.ButtonLeft})
+	if roots[0].Expanded() {
+		t.Fatal("a click in the first column expanded the node")
+	}
+	if got := labels(tt.Selected()); got != "src" {
+		t.Errorf("Selected() = %q, want src", got)
+	}
+	tt.HandleEvent(ctx, event.MouseEvent{Type: event.MouseDown, Position: core.Pt(65, y), Button: event.ButtonLeft})
+	if !roots[0].Expanded() {
+		t.Error("a click on the disclosure did not expand the node")
+	}
+}
+
+func TestTreeTableRightToLeft(t *testing.T) {
+	// The disclosure is at the right of the tree column, left of the first.
+	roots, value := sizes()
+	roots[0].expanded = false
+	tt := NewTreeTable(roots, value, Column{Key: "size", Width: 60}, Column{Key: "name", Width: 100}).
+		TreeColumn("name").RowHeight(20)
+	ctx := layoutRTL(tt, core.R(0, 0, 300, 300))
+	y := tt.body.Y + 10
+	tt.HandleEvent(ctx, event.MouseEvent{Type: event.MouseDown, Position: core.Pt(295, y), Button: event.ButtonLeft})
+	if roots[0].Expanded() {
+		t.Fatal("a click in the first column expanded the node")
+	}
+	tt.HandleEvent(ctx, event.MouseEvent{Type: event.MouseDown, Position: core.Pt(235, y), Button: event.ButtonLeft})
+	if !roots[0].Expanded() {
+		t.Error("a click on the disclosure did not expand the node")
+	}
+	ctx.RequestFocus(tt)
+	tt.HandleEvent(ctx, press(event.KeyRight, 0))
+	if roots[0].Expanded() {
+		t.Error("Right did not collapse the node")
+	}
+}
+
+func TestTreeTableCellValue(t *testing.T) {
+	n := NewTreeNode("x")
+	tt := NewTreeTable([]*TreeNode{n}, nil, Column{Key: "name"}, Column{Key: "size"})
+	if got := tt.cellValue(n, "name"); got != "x" {
+		t.Errorf("tree column value = %v, want the label", got)
+	}
+	if got := tt.cellValue(n, "size"); got != nil {
+		t.Errorf("value = %v without a TreeValueFunc, want nil", got)
+	}
+}
@@ -0,0 +1,330 @@
+package widgets
+
+import (
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/internal/scroll"
+	"github.com/gogpu/ui/theme"
+)
+
+const (
+	defaultTreeRowHeight float32 = 24
+	defaultTreeIndent    float32 = 16
+	disclosureWidth      float32 = 16
+)
+
+// TreeView displays a hierarchy of TreeNodes with expand/collapse, single
+// or multiple selection and keyboard navigation.
+//
+// Children of nodes marked Lazy are fetched by the ChildLoader on a
+// background goroutine the first time the node is expanded; the node shows
+// a loading hint until the result arrives.
+//
+// Keyboard: Up/Down move the selection (Shift extends, Ctrl moves the
+// cursor only), Left collapses or jumps to the parent, Right expands or
+// enters the first child, Home/End and PageUp/PageDown jump, Space toggles
+// the cursor row in multi-select mode and Enter activates it.
+//
+// Only visible rows are painted, so deep and wide trees remain cheap.
+type TreeView struct {
+	core.WidgetBase
+	core.FocusState
+
+	model     treeModel
+	rowHeight float32
+	indent    float32
+	paintNode func(ctx *core.PaintContext, content core.Rect, node *TreeNode)
+
+	scrollY float32
+	bar     scroll.Bar
+	body    core.Rect
+	hover   *TreeNode
+}
+
+// NewTreeView returns a tree view showing roots.
+func NewTreeView(roots ...*TreeNode) *TreeView {
+	t := &TreeView{
+		rowHeight: defaultTreeRowHeight,
+		indent:    defaultTreeIndent,
+	}
+	t.model = newTreeModel(t, roots)
+	return t
+}
+
+// LoadChildren sets the loader for lazy nodes.
+func (t *TreeView) LoadChildren(loader ChildLoader) *TreeView {
+	t.model.loader = loader
+	return t
+}
+
+// MultiSelect enables or disables multiple selection.
+func (t *TreeView) MultiSelect(multi bool) *TreeView {
+	t.model.multi = multi
+	return t
+}
+
+// RowHeight sets the height of every row.
+func (t *TreeView) RowHeight(h float32) *TreeView {
+	if h > 0 {
+		t.rowHeight = h
+	}
+	return t
+}
+
+// Indent sets the horizontal indentation per depth level.
+func (t *TreeView) Indent(px float32) *TreeView {
+	t.indent = max(0, px)
+	return t
+}
+
+// PaintNode replaces the default label rendering. content is the row area
+// to the right of the disclosure chevron.
+func (t *TreeView) PaintNode(fn func(ctx *core.PaintContext, content core.Rect, node *TreeNode)) *TreeView {
+	t.paintNode = fn
+	return t
+}
+
+// OnSelectionChange registers fn to be called with the selected nodes, in
+// display order, whenever the selection changes.
+func (t *TreeView) OnSelectionChange(fn func(nodes []*TreeNode)) *TreeView {
+	t.model.onSelect = fn
+	return t
+}
+
+// OnActivate registers fn to be called when a node is double-clicked or
+// Enter is pressed. Without a handler activation toggles expansion.
+func (t *TreeView) OnActivate(fn func(node *TreeNode)) *TreeView {
+	t.model.onActivate = fn
+	return t
+}
+
+// OnExpand registers fn to be called when a node is expanded or collapsed.
+func (t *TreeView) OnExpand(fn func(node *TreeNode, expanded bool)) *TreeView {
+	t.model.onExpand = fn
+	return t
+}
+
+// Roots returns the root nodes.
+func (t *TreeView) Roots() []*TreeNode {
+	return t.model.roots
+}
+
+// SetRoots replaces the displayed hierarchy and clears the selection.
+func (t *TreeView) SetRoots(roots ...*TreeNode) {
+	loader, multi := t.model.loader, t.model.multi
+	onSelect, onActivate, onExpand := t.model.onSelect, t.model.onActivate, t.model.onExpand
+	t.model = newTreeModel(t, roots)
+	t.model.loader, t.model.multi = loader, multi
+	t.model.onSelect, t.model.onActivate, t.model.onExpand = onSelect, onActivate, onExpand
+}
+
+// Refresh re-reads the hierarchy after nodes were added or removed.
+func (t *TreeView) Refresh() {
+	t.model.dirty = true
+}
+
+// Expand expands n, loading its children if it is lazy.
+func (t *TreeView) Expand(ctx *core.Context, n *TreeNode) {
+	t.model.setExpanded(ctx, n, true)
+}
+
+// Collapse collapses n.
+func (t *TreeView) Collapse(ctx *core.Context, n *TreeNode) {
+	t.model.setExpanded(ctx, n, false)
+}
+
+// Selected returns the selected nodes in display order.
+func (t *TreeView) Selected() []*TreeNode {
+	return t.model.selection()
+}
+
+// Select makes n the only selected node, expanding its ancestors and
+// scrolling it into view.
+func (t *TreeView) Select(ctx *core.Context, n *TreeNode) {
+	for p := n.parent; p != nil; p = p.parent {
+		t.model.setExpanded(ctx, p, true)
+	}
+	t.model.selectNode(n, 0)
+	t.scrollToCursor()
+}
+
+// Layout implements core.Widget.
+func (t *TreeView) Layout(ctx *core.LayoutContext) core.Size {
+	t.model.flatten()
+	size := core.Size{Width: 200, Height: float32(len(t.model.rows)) * t.rowHeight}
+	c := ctx.Constraints
+	if c.HasBoundedWidth() {
+		size.Width = c.MaxWidth
+	}
+	if c.HasBoundedHeight() {
+		size.Height = c.MaxHeight
+	}
+	return c.Constrain(size)
+}
+
+// SetBounds implements core.Widget.
+func (t *TreeView) SetBounds(r core.Rect) {
+	t.WidgetBase.SetBounds(r)
+	t.body = core.R(r.X, r.Y, max(0, r.Width-scroll.Thickness), r.Height)
+	t.bar.Track = core.R(t.body.Right(), r.Y, scroll.Thickness, r.Height)
+	t.bar.Viewport = r.Height
+	t.bar.Content = float32(len(t.model.rows)) * t.rowHeight
+	t.scrollY = core.Clamp(t.scrollY, 0, t.bar.MaxOffset())
+}
+
+// Paint implements core.Widget.
+func (t *TreeView) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	cv.Save()
+	cv.Clip(t.Bounds())
+	cv.DrawRect(t.Bounds(), core.Filled(th.Colors.Surface))
+	style := theme.TextStyle(th.Typography.Body, th.Colors.OnSurface)
+	hint := theme.TextStyle(th.Typography.Caption, th.Colors.OnSurfaceVariant)
+	errStyle := theme.TextStyle(th.Typography.Caption, th.Colors.Error)
+
+	first := max(0, int(t.scrollY/t.rowHeight))
+	last := min(len(t.model.rows), int((t.scrollY+t.body.Height)/t.rowHeight)+1)
+	for i := first; i < last; i++ {
+		row := t.model.rows[i]
+		n := row.node
+		r := core.R(t.body.X, t.body.Y+float32(i)*t.rowHeight-t.scrollY, t.body.Width, t.rowHeight)
+		switch {
+		case t.model.selected[n]:
+			cv.DrawRect(r, core.Filled(th.Colors.Selection))
+		case n == t.hover:
+			cv.DrawRect(r, core.Filled(th.Colors.OnSurface.WithAlpha(0.05)))
+		}
+		if n == t.model.cursor && t.IsFocused() {
+			cv.DrawRect(r.Inset(core.UniformInsets(0.5)), core.Stroked(th.Colors.Primary, 1))
+		}
+		x := r.X + float32(row.depth)*t.indent
+		if n.HasChildren() {
+			paintDisclosure(ctx, core.R(x, r.Y, disclosureWidth, r.Height), n.expanded, th.Colors.OnSurfaceVariant)
+		}
+		content := core.R(x+disclosureWidth, r.Y, max(0, r.Right()-x-disclosureWidth), r.Height)
+		if t.paintNode != nil {
+			t.paintNode(ctx, content, n)
+			continue
+		}
+		size := ctx.MeasureText(n.Label, style)
+		ty := r.Y + (r.Height-size.Height)/2
+		cv.DrawText(n.Label, core.Pt(content.X+2, ty), style)
+		switch {
+		case n.Loading():
+			cv.DrawText("Loading…", core.Pt(content.X+size.Width+10, ty+1), hint)
+		case n.err != nil:
+			cv.DrawText(n.err.Error(), core.Pt(content.X+size.Width+10, ty+1), errStyle)
+		}
+	}
+	t.bar.Paint(ctx, t.scrollY)
+	cv.Restore()
+}
+
+func (t *TreeView) rowAt(p core.Point) int {
+	if !t.body.Contains(p) {
+		return -1
+	}
+	i := int((p.Y - t.body.Y + t.scrollY) / t.rowHeight)
+	if i < 0 || i >= len(t.model.rows) {
+		return -1
+	}
+	return i
+}
+
+func (t *TreeView) scrollToCursor() {
+	t.model.flatten()
+	i := t.model.rowOf(t.model.cursor)
+	if i < 0 {
+		return
+	}
+	top := float32(i) * t.rowHeight
+	if top < t.scrollY {
+		t.scrollY = top
+	} else if top+t.rowHeight > t.scrollY+t.body.Height {
+		t.scrollY = top + t.rowHeight - t.body.Height
+	}
+	t.scrollY = max(0, t.scrollY)
+}
+
+// HandleEvent implements core.Widget.
+func (t *TreeView) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	if off, ok := t.bar.HandleEvent(ctx, t, ev, t.scrollY); ok {
+		if off != t.scrollY {
+			t.scrollY = off
+			ctx.Repaint(t)
+		}
+		return core.Handled
+	}
+	switch e := ev.(type) {
+	case event.ScrollEvent:
+		old := t.scrollY
+		t.scrollY = core.Clamp(t.scrollY+e.Delta.Y, 0, t.bar.MaxOffset())
+		if old != t.scrollY {
+			ctx.Repaint(t)
+			return core.Handled
+		}
+	case event.MouseEvent:
+		return t.handleMouse(ctx, e)
+	case event.KeyEvent:
+		if e.Type != event.KeyPress || !t.IsFocused() {
+			return core.Ignored
+		}
+		if t.model.handleKey(ctx, e, max(1, int(t.body.Height/t.rowHeight)-1)) {
+			t.model.flatten()
+			t.scrollToCursor()
+			return core.Handled
+		}
+	}
+	return core.Ignored
+}
+
+func (t *TreeView) handleMouse(ctx *core.Context, e event.MouseEvent) core.EventResult {
+	switch e.Type {
+	case event.MouseMove:
+		var n *TreeNode
+		if i := t.rowAt(e.Position); i >= 0 {
+			n = t.model.rows[i].node
+		}
+		if n != t.hover {
+			t.hover = n
+			ctx.Repaint(t)
+		}
+	case event.MouseLeave:
+		if t.hover != nil {
+			t.hover = nil
+			ctx.Repaint(t)
+		}
+	case event.MouseDown:
+		if !t.body.Contains(e.Position) {
+			return core.Ignored
+		}
+		ctx.RequestFocus(t)
+		i := t.rowAt(e.Position)
+		if i < 0 {
+			if e.Button == event.ButtonRight {
+				return core.Ignored
+			}
+			return core.Handled
+		}
+		row := t.model.rows[i]
+		chevronX := t.body.X + float32(row.depth)*t.indent
+		if row.node.HasChildren() && e.Position.X >= chevronX && e.Position.X < chevronX+disclosureWidth {
+			t.model.setExpanded(ctx, row.node, !row.node.expanded)
+			return core.Handled
+		}
+		if e.Button == event.ButtonRight && t.model.selected[row.node] {
+			return core.Ignored // Keep the selection for a context menu.
+		}
+		t.model.selectNode(row.node, e.Modifiers)
+		if e.ClickCount == 2 && e.Button == event.ButtonLeft {
+			t.model.activate(ctx, row.node)
+		}
+		ctx.Repaint(t)
+		if e.Button == event.ButtonRight {
+			return core.Ignored
+		}
+		return core.Handled
+	}
+	return core.Ignored
+}
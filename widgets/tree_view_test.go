@@ -0,0 +1,214 @@
+package widgets
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+)
+
+// sampleTree returns the roots a(b, c(d)) and e.
+func sampleTree() []*TreeNode {
+	return []*TreeNode{
+		NewTreeNode("a", NewTreeNode("b"), NewTreeNode("c", NewTreeNode("d"))),
+		NewTreeNode("e"),
+	}
+}
+
+// shown returns the labels of the visible rows of m, indented by depth.
+func shown(m *treeModel) string {
+	m.flatten()
+	var out []string
+	for _, r := range m.rows {
+		out = append(out, strings.Repeat(".", r.depth)+r.node.Label)
+	}
+	return strings.Join(out, " ")
+}
+
+// labels returns the labels of nodes.
+func labels(nodes []*TreeNode) string {
+	var out []string
+	for _, n := range nodes {
+		out = append(out, n.Label)
+	}
+	return strings.Join(out, " ")
+}
+
+// runPosted waits for the functions posted to ctx from other goroutines
+// and runs them.
+func runPosted(t *testing.T, ctx *core.Context) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !ctx.HasPosted() {
+		if time.Now().After(deadline) {
+			t.Fatal("nothing was posted")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	ctx.RunPosted()
+}
+
+func TestTreeViewExpand(t *testing.T) {
+	roots := sampleTree()
+	tv := NewTreeView(roots...)
+	ctx := core.NewContext()
+	var expanded []string
+	tv.OnExpand(func(n *TreeNode, on bool) {
+		if on {
+			expanded = append(expanded, n.Label)
+		}
+	})
+	if got := shown(&tv.model); got != "a e" {
+		t.Errorf("rows = %q, want %q", got, "a e")
+	}
+	tv.Expand(ctx, roots[0])
+	tv.Expand(ctx, roots[0].Children[1])
+	if got, want := shown(&tv.model), "a .b .c ..d e"; got != want {
+		t.Errorf("rows = %q, want %q", got, want)
+	}
+	tv.Expand(ctx, roots[1]) // No children.
+	if got := strings.Join(expanded, " "); got != "a c" {
+		t.Errorf("expanded %q, want %q", got, "a c")
+	}
+	tv.Collapse(ctx, roots[0])
+	if got := shown(&tv.model); got != "a e" {
+		t.Errorf("rows = %q after Collapse, want %q", got, "a e")
+	}
+	if d := roots[0].Children[1].Children[0]; d.Parent() != roots[0].Children[1] {
+		t.Errorf("Parent() = %v", d.Parent())
+	}
+}
+
+func TestTreeViewLazy(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		rows string
+	}{
+		{"loaded", nil, "lazy .x .y"},
+		{"failed", errors.New("offline"), "lazy"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &TreeNode{Label: "lazy", Lazy: true}
+			calls := 0
+			tv := NewTreeView(node).LoadChildren(func(n *TreeNode) ([]*TreeNode, error) {
+				calls++
+				if tt.err != nil {
+					return nil, tt.err
+				}
+				return []*TreeNode{NewTreeNode("x"), NewTreeNode("y")}, nil
+			})
+			ctx := core.NewContext()
+			if !node.HasChildren() {
+				t.Fatal("a lazy node has no children before it is loaded")
+			}
+			tv.Expand(ctx, node)
+			if !node.Loading() {
+				t.Error("Loading() = false while loading")
+			}
+			runPosted(t, ctx)
+			if got := shown(&tv.model); got != tt.rows {
+				t.Errorf("rows = %q, want %q", got, tt.rows)
+			}
+			if !errors.Is(node.LoadError(), tt.err) {
+				t.Errorf("LoadError() = %v, want %v", node.LoadError(), tt.err)
+			}
+			if tt.err == nil {
+				// A loaded node is not loaded again until Reload.
+				tv.Collapse(ctx, node)
+				tv.Expand(ctx, node)
+				if node.Loading() || calls != 1 {
+					t.Errorf("loaded %d times", calls)
+				}
+				node.Reload()
+				if node.Expanded() || len(node.Children) != 0 || !node.HasChildren() {
+					t.Error("Reload kept the children")
+				}
+			}
+		})
+	}
+}
+
+func TestTreeViewKeys(t *testing.T) {
+	key := func(k event.Key) event.KeyEvent { return event.KeyEvent{Type: event.KeyPress, Key: k} }
+	tests := []struct {
+		name   string
+		keys   []event.Key
+		cursor string
+		rows   string
+	}{
+		{"down", []event.Key{event.KeyDown}, "e", "a e"},
+		{"right expands", []event.Key{event.KeyRight}, "a", "a .b .c e"},
+		{"right again enters", []event.Key{event.KeyRight, event.KeyRight}, "b", "a .b .c e"},
+		{"left goes to the parent", []event.Key{event.KeyRight, event.KeyRight, event.KeyLeft}, "a", "a .b .c e"},
+		{"left collapses", []event.Key{event.KeyRight, event.KeyLeft}, "a", "a e"},
+		{"end", []event.Key{event.KeyRight, event.KeyEnd}, "e", "a .b .c e"},
+		{"enter toggles", []event.Key{event.KeyEnter}, "a", "a .b .c e"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roots := sampleTree()
+			tv := NewTreeView(roots...)
+			ctx := layoutAt(tv, core.R(0, 0, 200, 200))
+			ctx.RequestFocus(tv)
+			tv.Select(ctx, roots[0])
+			for _, k := range tt.keys {
+				tv.HandleEvent(ctx, key(k))
+			}
+			if got := tv.model.cursor.Label; got != tt.cursor {
+				t.Errorf("cursor = %q, want %q", got, tt.cursor)
+			}
+			if got := shown(&tv.model); got != tt.rows {
+				t.Errorf("rows = %q, want %q", got, tt.rows)
+			}
+		})
+	}
+}
+
+func TestTreeViewMultiSelect(t *testing.T) {
+	roots := sampleTree()
+	tv := NewTreeView(roots...).MultiSelect(true)
+	ctx := core.NewContext()
+	var notified []string
+	tv.OnSelectionChange(func(nodes []*TreeNode) { notified = append(notified, labels(nodes)) })
+	tv.Select(ctx, roots[0].Children[1].Children[0]) // d, expanding a and c.
+	tv.model.selectNode(roots[0], 0)
+	tv.model.selectNode(roots[0].Children[1], event.ModShift)
+	tv.model.selectNode(roots[1], event.ModCtrl)
+	tv.model.selectNode(roots[0].Children[0], event.ModCtrl)
+	want := []string{"d", "a", "a b c", "a b c e", "a c e"}
+	if got := strings.Join(notified, "|"); got != strings.Join(want, "|") {
+		t.Errorf("selections = %q, want %q", got, strings.Join(want, "|"))
+	}
+	if got := labels(tv.Selected()); got != "a c e" {
+		t.Errorf("Selected() = %q", got)
+	}
+}
+
+func TestTreeViewClick(t *testing.T) {
+	roots := sampleTree()
+	tv := NewTreeView(roots...).RowHeight(20).Indent(16)
+	ctx := layoutAt(tv, core.R(0, 0, 200, 200))
+	var activated []string
+	tv.OnActivate(func(n *TreeNode) { activated = append(activated, n.Label) })
+	down := func(x, y float32, clicks int) {
+		tv.HandleEvent(ctx, event.MouseEvent{Type: event.MouseDown, Position: core.Pt(x, y), Button: event.ButtonLeft, ClickCount: clicks})
+	}
+	down(5, 10, 1) // The disclosure of a.
+	if !roots[0].Expanded() {
+		t.Fatal("a click on the disclosure did not expand the node")
+	}
+	ctx.LayoutRoot(tv, core.R(0, 0, 200, 200))
+	down(100, 50, 1)
+	down(100, 50, 2)
+	if got := labels(tv.Selected()); got != "c" {
+		t.Errorf("Selected() = %q, want c", got)
+	}
+	if got := strings.Join(activated, " "); got != "c" {
+		t.Errorf("activated %q, want c", got)
+	}
+}
@@ -0,0 +1,776 @@
+package widgets
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/theme"
+)
+
+// Video metrics.
+const (
+	videoWidth      float32 = 320
+	videoHeight     float32 = 180
+	videoBar        float32 = 40
+	videoButton     float32 = 24
+	videoTrack      float32 = 4
+	videoThumb      float32 = 6
+	videoKeyStep            = 5 * time.Second
+	videoAudioLead          = 200 * time.Millisecond
+	videoAudioPoll          = 50 * time.Millisecond
+	videoAudioChunk         = 1024
+)
+
+// VideoFrame is a decoded frame and the time it is presented at, from the
+// start of the video.
+type VideoFrame struct {
+	Image image.Image
+	Time  time.Duration
+}
+
+// VideoDecoder decodes the frames of a video. Implementations wrap a
+// platform decoder, such as Media Foundation, AVFoundation or GStreamer,
+// or a codec written in Go. A Video calls its methods from one background
+// goroutine.
+type VideoDecoder interface {
+	// NextFrame decodes the frame following the last one decoded, or the
+	// first from the position of the last Seek, and returns io.EOF after
+	// the last frame.
+	NextFrame() (VideoFrame, error)
+	// Seek moves to t, so that the next frame decoded is presented at or
+	// before t; decoders may go back to a key frame.
+	Seek(t time.Duration) error
+	// Duration returns the length of the video, or 0 if it is unknown.
+	Duration() time.Duration
+	// Close releases the decoder.
+	Close() error
+}
+
+// VideoAudio is implemented by decoders of videos with sound. Seek moves
+// the audio with the video.
+type VideoAudio interface {
+	// AudioFormat returns the sample rate and the number of channels.
+	AudioFormat() (rate, channels int)
+	// ReadAudio decodes interleaved samples in [-1, 1] into buf, returning
+	// how many it wrote, and io.EOF after the last.
+	ReadAudio(buf []float32) (int, error)
+}
+
+// AudioOutput plays the sound of a Video, typically on the platform's
+// audio device. It is called from the Video's background goroutine.
+type AudioOutput interface {
+	// Open prepares to play samples of the given format.
+	Open(rate, channels int) error
+	// Write queues interleaved samples. The Video keeps a short lead of
+	// queued sound ahead of the picture.
+	Write(samples []float32)
+	// SetPaused pauses or resumes playing what is queued.
+	SetPaused(paused bool)
+	// Flush drops what is queued, when the video seeks.
+	Flush()
+	// Close releases the output.
+	Close()
+}
+
+// VideoHost is implemented by platform integrations that can decode
+// videos with the platform's media framework.
+type VideoHost interface {
+	// OpenVideo opens the video at uri, a file path or a URL.
+	OpenVideo(uri string) (VideoDecoder, error)
+}
+
+type videoHostKey struct{}
+
+// SetVideoHost installs the host opening the videos of every Video in the
+// window given by URI.
+func SetVideoHost(ctx *core.Context, h VideoHost) {
+	ctx.SetValue(videoHostKey{}, h)
+}
+
+// ErrNoVideoHost is the error of a Video playing a URI in a window without
+// a VideoHost.
+var ErrNoVideoHost = errors.New("widgets: no video host")
+
+// VideoSource is what a Video plays: a decoder supplied by the app, or
+// otherwise the video at URI, opened by the window's VideoHost.
+type VideoSource struct {
+	URI     string
+	Decoder VideoDecoder
+}
+
+// Video plays a video decoded in the background, drawing each frame as an
+// image when it is due and sending the sound, if any, to an AudioOutput.
+//
+// Unless hidden with Controls, a bar with a play button, a seek track and
+// the time is shown while the pointer is over the video or it is paused.
+// A click elsewhere on the video toggles playback, and when focused Space
+// does, Left and Right seek by five seconds and Home goes to the start.
+//
+// Without an explicit Size a Video takes the size of its frames, scaled
+// down to fit the constraints. The decoder is opened when the Video is
+// first laid out, and the first frame shown as a poster; call Close when
+// it is no longer needed.
+type Video struct {
+	core.WidgetBase
+	core.FocusState
+
+	src      VideoSource
+	out      AudioOutput
+	fit      ImageFit
+	size     core.Size
+	loop     bool
+	autoplay bool
+	noBar    bool
+	onEnd    func(ctx *core.Context)
+
+	gen     int
+	started bool
+	ctl     *videoControl
+	frame   image.Image
+	pos     time.Duration
+	dur     time.Duration
+	playing bool
+	err     error
+
+	hover      bool
+	drag       bool
+	button     core.Rect
+	track      core.Rect
+	barVisible bool
+}
+
+// NewVideo returns a Video playing src.
+func NewVideo(src VideoSource) *Video {
+	return &Video{src: src}
+}
+
+// SetSource stops the video shown and starts showing src.
+func (v *Video) SetSource(src VideoSource) {
+	v.Close()
+	v.src = src
+	v.started = false
+	v.frame, v.pos, v.dur, v.err = nil, 0, 0, nil
+}
+
+// AudioOutput sets where the sound goes. Without one the video is silent.
+// It takes effect when the video is next opened.
+func (v *Video) AudioOutput(out AudioOutput) *Video {
+	v.out = out
+	return v
+}
+
+// Autoplay starts playback as soon as the video is opened.
+func (v *Video) Autoplay(on bool) *Video {
+	v.autoplay = on
+	return v
+}
+
+// Loop makes playback start over at the end.
+func (v *Video) Loop(on bool) *Video {
+	v.loop = on
+	return v
+}
+
+// Controls shows or hides the playback bar. It is shown by default.
+func (v *Video) Controls(show bool) *Video {
+	v.noBar = !show
+	return v
+}
+
+// Fit sets how frames fill the bounds. The default is ImageContain.
+func (v *Video) Fit(fit ImageFit) *Video {
+	v.fit = fit
+	return v
+}
+
+// Size sets the preferred size, which is otherwise the frames'.
+func (v *Video) Size(w, h float32) *Video {
+	v.size = core.Sz(w, h)
+	return v
+}
+
+// OnEnd sets the function called when playback reaches the end without
+// looping.
+func (v *Video) OnEnd(fn func(ctx *core.Context)) *Video {
+	v.onEnd = fn
+	return v
+}
+
+// Play starts or resumes playback, from the start if it had ended.
+func (v *Video) Play() {
+	v.playing = true
+	v.control(func(c *videoControl) { c.playing = true })
+}
+
+// Pause pauses playback.
+func (v *Video) Pause() {
+	v.playing = false
+	v.control(func(c *videoControl) { c.playing = false })
+}
+
+// Playing reports whether the video is playing.
+func (v *Video) Playing() bool {
+	return v.playing
+}
+
+// Seek moves playback to t.
+func (v *Video) Seek(t time.Duration) {
+	t = max(0, t)
+	if v.dur > 0 {
+		t = min(t, v.dur)
+	}
+	v.pos = t
+	v.control(func(c *videoControl) { c.seek, c.seeking = t, true })
+}
+
+// Position returns the time of the frame shown.
+func (v *Video) Position() time.Duration {
+	return v.pos
+}
+
+// Duration returns the length of the video, or 0 until it is known or if
+// the decoder does not know it.
+func (v *Video) Duration() time.Duration {
+	return v.dur
+}
+
+// Err returns why the video could not be opened or decoded, or nil.
+func (v *Video) Err() error {
+	return v.err
+}
+
+// Close stops playback and releases the decoder and audio output.
+func (v *Video) Close() {
+	v.gen++
+	v.playing = false
+	if v.ctl != nil {
+		v.ctl.update(func(c *videoControl) { c.closed = true })
+		v.ctl = nil
+	}
+}
+
+// control changes the state the player goroutine follows, or the one it
+// starts in if it is not running yet.
+func (v *Video) control(fn func(c *videoControl)) {
+	if v.ctl == nil {
+		v.ctl = newVideoControl()
+	}
+	v.ctl.update(fn)
+}
+
+func (v *Video) start(ctx *core.Context) {
+	v.started = true
+	if v.ctl == nil {
+		v.ctl = newVideoControl()
+	}
+	if v.autoplay {
+		v.Play()
+	}
+	p := &videoPlayer{ctx: ctx, v: v, gen: v.gen, ctl: v.ctl, out: v.out, loop: v.loop}
+	dec := v.src.Decoder
+	h, _ := ctx.Value(videoHostKey{}).(VideoHost)
+	switch {
+	case dec != nil:
+	case v.src.URI == "":
+		v.err = errors.New("widgets: empty video source")
+		return
+	case h == nil:
+		v.err = ErrNoVideoHost
+		return
+	}
+	go func() {
+		if dec == nil {
+			var err error
+			if dec, err = h.OpenVideo(v.src.URI); err != nil {
+				p.post(func(v *Video) { v.err = err })
+				return
+			}
+		}
+		p.dec = dec
+		p.run()
+	}()
+}
+
+// Layout implements core.Widget.
+func (v *Video) Layout(ctx *core.LayoutContext) core.Size {
+	if !v.started {
+		v.start(ctx.Context)
+	}
+	if v.size != (core.Size{}) {
+		return ctx.Constraints.Constrain(v.size)
+	}
+	w, h := videoWidth, videoHeight
+	if v.frame != nil {
+		b := v.frame.Bounds()
+		w, h = float32(b.Dx()), float32(b.Dy())
+	}
+	s := float32(1)
+	if c := ctx.Constraints; c.HasBoundedWidth() && w > c.MaxWidth {
+		s = c.MaxWidth / w
+	}
+	if c := ctx.Constraints; c.MaxHeight < h*s {
+		s = c.MaxHeight / h
+	}
+	return ctx.Constraints.Constrain(core.Sz(w*s, h*s))
+}
+
+// Paint implements core.Widget.
+func (v *Video) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	b := v.Bounds()
+	cv.Save()
+	cv.Clip(b)
+	cv.DrawRect(b, core.Filled(core.Black))
+	if v.frame != nil {
+		fb := v.frame.Bounds()
+		if fb.Dx() > 0 && fb.Dy() > 0 {
+			cv.DrawImage(v.frame, fitRect(b, float32(fb.Dx()), float32(fb.Dy()), v.fit))
+		}
+	}
+	if v.err != nil {
+		st := theme.TextStyle(th.Typography.Body, th.Colors.Error)
+		text := "Video unavailable"
+		sz := ctx.MeasureText(text, st)
+		cv.DrawText(text, core.Pt(b.X+(b.Width-sz.Width)/2, b.Y+(b.Height-sz.Height)/2), st)
+	}
+	v.barVisible = !v.noBar && v.err == nil && (v.hover || v.drag || !v.playing)
+	if v.barVisible {
+		v.paintBar(ctx, th, b)
+	}
+	if v.IsFocused() {
+		cv.DrawRect(b, core.RectStyle{Stroke: th.Colors.Primary, StrokeWidth: 2})
+	}
+	cv.Restore()
+}
+
+// paintBar draws the playback bar along the bottom of b.
+func (v *Video) paintBar(ctx *core.PaintContext, th *theme.Theme, b core.Rect) {
+	cv := ctx.Canvas
+	bar := core.R(b.X, b.Bottom()-videoBar, b.Width, videoBar)
+	cv.DrawRect(bar, core.Filled(core.Black.WithAlpha(0.55)))
+	pad := th.Spacing.M
+	v.button = core.R(bar.X+pad, bar.Y+(videoBar-videoButton)/2, videoButton, videoButton)
+	icon := core.NewPath()
+	ib := v.button.Inset(core.UniformInsets(6))
+	if v.playing {
+		w := ib.Width / 3
+		icon.AddRect(core.R(ib.X, ib.Y, w, ib.Height)).AddRect(core.R(ib.Right()-w, ib.Y, w, ib.Height))
+	} else {
+		icon.MoveTo(ib.Origin()).LineTo(core.Pt(ib.Right(), ib.Y+ib.Height/2)).LineTo(core.Pt(ib.X, ib.Bottom())).Close()
+	}
+	cv.DrawPath(icon, core.PathStyle{Fill: core.White})
+
+	st := theme.TextStyle(th.Typography.Caption, core.White)
+	text := formatVideoTime(v.pos)
+	if v.dur > 0 {
+		text += " / " + formatVideoTime(v.dur)
+	}
+	sz := ctx.MeasureText(text, st)
+	tx := bar.Right() - pad - sz.Width
+	cv.DrawText(text, core.Pt(tx, bar.Y+(videoBar-sz.Height)/2), st)
+
+	x0, x1 := v.button.Right()+pad+videoThumb, tx-pad-videoThumb
+	cy := bar.Y + videoBar/2
+	v.track = core.R(x0, cy-videoTrack/2, max(0, x1-x0), videoTrack)
+	if v.track.Width <= 0 || v.dur <= 0 {
+		return
+	}
+	cv.DrawRoundedRect(v.track, videoTrack/2, core.Filled(core.White.WithAlpha(0.3)))
+	f := float32(min(1, v.pos.Seconds()/v.dur.Seconds()))
+	done := v.track
+	done.Width *= f
+	cv.DrawRoundedRect(done, videoTrack/2, core.Filled(th.Colors.Primary))
+	if v.hover || v.drag {
+		x := done.Right()
+		thumb := core.NewPath().AddEllipse(core.R(x-videoThumb, cy-videoThumb, 2*videoThumb, 2*videoThumb))
+		cv.DrawPath(thumb, core.PathStyle{Fill: th.Colors.Primary})
+	}
+}
+
+// formatVideoTime writes t as m:ss, or h:mm:ss from an hour.
+func formatVideoTime(t time.Duration) string {
+	s := int(t.Seconds())
+	if s >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+	}
+	return fmt.Sprintf("%d:%02d", s/60, s%60)
+}
+
+// seekTo seeks to the time under x on the track.
+func (v *Video) seekTo(ctx *core.Context, x float32) {
+	f := (x - v.track.X) / v.track.Width
+	f = max(0, min(1, f))
+	v.Seek(time.Duration(float64(f) * float64(v.dur)))
+	ctx.Repaint(v)
+}
+
+// HandleEvent implements core.Widget.
+func (v *Video) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	switch e := ev.(type) {
+	case event.MouseEvent:
+		return v.handleMouse(ctx, e)
+	case event.KeyEvent:
+		if e.Type != event.KeyPress || !v.IsFocused() || e.Modifiers != 0 {
+			return core.Ignored
+		}
+		switch e.Key {
+		case event.KeySpace:
+			v.toggle()
+		case event.KeyLeft:
+			v.Seek(v.pos - videoKeyStep)
+		case event.KeyRight:
+			v.Seek(v.pos + videoKeyStep)
+		case event.KeyHome:
+			v.Seek(0)
+		default:
+			return core.Ignored
+		}
+		ctx.Repaint(v)
+		return core.Handled
+	}
+	return core.Ignored
+}
+
+func (v *Video) handleMouse(ctx *core.Context, e event.MouseEvent) core.EventResult {
+	switch e.Type {
+	case event.MouseEnter, event.MouseMove:
+		if !v.hover {
+			v.hover = true
+			ctx.Repaint(v)
+		}
+		if v.drag {
+			v.seekTo(ctx, e.Position.X)
+			return core.Handled
+		}
+	case event.MouseLeave:
+		v.hover = false
+		ctx.Repaint(v)
+	case event.MouseDown:
+		if e.Button != event.ButtonLeft {
+			return core.Ignored
+		}
+		ctx.RequestFocus(v)
+		switch {
+		case v.barVisible && v.button.Contains(e.Position):
+			v.toggle()
+		case v.barVisible && v.dur > 0 && v.track.Inset(core.UniformInsets(-2*videoThumb)).Contains(e.Position):
+			v.drag = true
+			ctx.CapturePointer(v)
+			v.seekTo(ctx, e.Position.X)
+		case v.barVisible && e.Position.Y >= v.Bounds().Bottom()-videoBar:
+		default:
+			v.toggle()
+		}
+		ctx.Repaint(v)
+		return core.Handled
+	case event.MouseUp:
+		if v.drag {
+			v.drag = false
+			ctx.ReleasePointer()
+			ctx.Repaint(v)
+			return core.Handled
+		}
+	}
+	return core.Ignored
+}
+
+func (v *Video) toggle() {
+	if v.playing {
+		v.Pause()
+	} else {
+		v.Play()
+	}
+}
+
+// videoControl is the state the player goroutine follows, set from the UI
+// goroutine. Seeks coalesce, so dragging through the track only decodes
+// where the player catches up with the pointer.
+type videoControl struct {
+	mu      sync.Mutex
+	playing bool
+	seek    time.Duration
+	seeking bool
+	closed  bool
+	wake    chan struct{}
+}
+
+func newVideoControl() *videoControl {
+	return &videoControl{wake: make(chan struct{}, 1)}
+}
+
+func (c *videoControl) update(fn func(c *videoControl)) {
+	c.mu.Lock()
+	fn(c)
+	c.mu.Unlock()
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+}
+
+// videoPlayer runs on the background goroutine of a Video, decoding
+// frames and sound and timing them against its clock.
+type videoPlayer struct {
+	ctx  *core.Context
+	v    *Video
+	gen  int
+	ctl  *videoControl
+	dec  VideoDecoder
+	loop bool
+
+	playing bool
+	ended   bool
+	base    time.Duration // the clock when playback last started or paused
+	since   time.Time
+	next    *VideoFrame
+
+	audio     VideoAudio
+	out       AudioOutput
+	rate      int
+	channels  int
+	buf       []float32
+	audioBase time.Duration // the clock at the last seek
+	written   int64         // sample frames queued since audioBase
+	audioDone bool
+}
+
+// post runs fn on the UI goroutine, unless the Video moved on to another
+// source or was closed.
+func (p *videoPlayer) post(fn func(v *Video)) {
+	p.ctx.Post(func() {
+		if p.v.gen == p.gen {
+			fn(p.v)
+		}
+	})
+}
+
+func (p *videoPlayer) fail(err error) {
+	p.post(func(v *Video) { v.err, v.playing = err, false })
+}
+
+func (p *videoPlayer) clock() time.Duration {
+	if p.playing {
+		return p.base + time.Since(p.since)
+	}
+	return p.base
+}
+
+func (p *videoPlayer) show(f VideoFrame) {
+	p.post(func(v *Video) {
+		v.frame = f.Image
+		if !v.drag {
+			v.pos = f.Time
+		}
+	})
+}
+
+func (p *videoPlayer) run() {
+	defer p.dec.Close()
+	p.openAudio()
+	if p.out != nil {
+		defer p.out.Close()
+	}
+	dur := p.dec.Duration()
+	p.post(func(v *Video) { v.dur = dur })
+	switch f, err := p.dec.NextFrame(); {
+	case err == nil:
+		p.show(f)
+		p.base, p.audioBase = f.Time, f.Time
+	case err != io.EOF:
+		p.fail(err)
+		return
+	}
+	for {
+		p.ctl.mu.Lock()
+		playing, seek, seeking, closed := p.ctl.playing, p.ctl.seek, p.ctl.seeking, p.ctl.closed
+		p.ctl.seeking = false
+		p.ctl.mu.Unlock()
+		if closed {
+			return
+		}
+		if seeking {
+			if err := p.seek(seek); err != nil {
+				p.fail(err)
+				return
+			}
+		}
+		if playing != p.playing {
+			if playing && p.ended {
+				if err := p.seek(0); err != nil {
+					p.fail(err)
+					return
+				}
+			}
+			p.base, p.since, p.playing = p.clock(), time.Now(), playing
+			if p.out != nil {
+				p.out.SetPaused(!playing)
+			}
+		}
+		wait := time.Duration(-1)
+		if p.playing {
+			p.fillAudio()
+			if p.next == nil {
+				f, err := p.dec.NextFrame()
+				if err == io.EOF {
+					if err := p.finish(); err != nil {
+						p.fail(err)
+						return
+					}
+					continue
+				}
+				if err != nil {
+					p.fail(err)
+					return
+				}
+				p.next = &f
+			}
+			if d := p.next.Time - p.clock(); d > 0 {
+				wait = d
+				if p.audio != nil && !p.audioDone {
+					wait = min(wait, videoAudioPoll)
+				}
+			} else if err := p.present(); err != nil {
+				p.fail(err)
+				return
+			} else {
+				continue
+			}
+		}
+		p.sleep(wait)
+	}
+}
+
+// present shows the frame that is due, skipping the frames that are late
+// already so that playback keeps time.
+func (p *videoPlayer) present() error {
+	cur := *p.next
+	p.next = nil
+	for {
+		f, err := p.dec.NextFrame()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if f.Time > p.clock() {
+			p.next = &f
+			break
+		}
+		cur = f
+	}
+	p.show(cur)
+	return nil
+}
+
+// sleep waits for d, or until the UI changes the control state; a
+// negative d waits for the latter only.
+func (p *videoPlayer) sleep(d time.Duration) {
+	if d < 0 {
+		<-p.ctl.wake
+		return
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-p.ctl.wake:
+	case <-t.C:
+	}
+}
+
+// finish handles the end of the video: it starts over when looping and
+// otherwise pauses.
+func (p *videoPlayer) finish() error {
+	if p.loop {
+		return p.seek(0)
+	}
+	p.base, p.playing, p.ended = p.clock(), false, true
+	if p.out != nil {
+		p.out.SetPaused(true)
+	}
+	p.ctl.update(func(c *videoControl) { c.playing = false })
+	p.post(func(v *Video) {
+		v.playing = false
+		if v.onEnd != nil {
+			v.onEnd(p.ctx)
+		}
+	})
+	return nil
+}
+
+// seek moves to t and shows the frame presented at t.
+func (p *videoPlayer) seek(t time.Duration) error {
+	if err := p.dec.Seek(t); err != nil {
+		return err
+	}
+	p.next = nil
+	var cur *VideoFrame
+	for {
+		f, err := p.dec.NextFrame()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if f.Time > t {
+			p.next = &f
+			break
+		}
+		cur = &f
+	}
+	if cur == nil {
+		cur, p.next = p.next, nil
+	}
+	if cur != nil {
+		p.show(*cur)
+	}
+	p.base, p.since, p.ended = t, time.Now(), false
+	p.audioBase, p.written, p.audioDone = t, 0, false
+	if p.out != nil {
+		p.out.Flush()
+	}
+	return nil
+}
+
+func (p *videoPlayer) openAudio() {
+	a, ok := p.dec.(VideoAudio)
+	if !ok || p.out == nil {
+		p.out = nil
+		return
+	}
+	p.rate, p.channels = a.AudioFormat()
+	if p.rate <= 0 || p.channels <= 0 || p.out.Open(p.rate, p.channels) != nil {
+		p.out = nil
+		return
+	}
+	p.audio = a
+	p.buf = make([]float32, videoAudioChunk*p.channels)
+	p.out.SetPaused(true)
+}
+
+// fillAudio queues sound up to a short lead ahead of the clock.
+func (p *videoPlayer) fillAudio() {
+	if p.audio == nil || p.audioDone {
+		return
+	}
+	target := p.clock() - p.audioBase + videoAudioLead
+	for time.Duration(p.written)*time.Second/time.Duration(p.rate) < target {
+		n, err := p.audio.ReadAudio(p.buf)
+		if n > 0 {
+			p.out.Write(p.buf[:n])
+			p.written += int64(n / p.channels)
+		}
+		if err != nil || n == 0 {
+			p.audioDone = true
+			return
+		}
+	}
+}
@@ -0,0 +1,444 @@
+package widgets
+
+import (
+	"errors"
+	"image"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+)
+
+// fakeDecoder decodes frames of 20 by 10 pixels, step apart.
+type fakeDecoder struct {
+	mu     sync.Mutex
+	frames int
+	step   time.Duration
+	dur    time.Duration
+	i      int
+	seeks  []time.Duration
+	closed bool
+}
+
+func newFakeDecoder(frames int, step time.Duration) *fakeDecoder {
+	return &fakeDecoder{frames: frames, step: step, dur: time.Duration(frames) * step}
+}
+
+var errDecode = errors.New("corrupt frame")
+
+func (d *fakeDecoder) NextFrame() (VideoFrame, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.i >= d.frames {
+		return VideoFrame{}, io.EOF
+	}
+	f := VideoFrame{Image: image.NewRGBA(image.Rect(0, 0, 20, 10)), Time: time.Duration(d.i) * d.step}
+	d.i++
+	return f, nil
+}
+
+func (d *fakeDecoder) Seek(t time.Duration) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.seeks = append(d.seeks, t)
+	d.i = min(int(t/d.step), d.frames-1)
+	return nil
+}
+
+func (d *fakeDecoder) Duration() time.Duration { return d.dur }
+
+func (d *fakeDecoder) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.closed = true
+	return nil
+}
+
+func (d *fakeDecoder) state() (seeks []time.Duration, closed bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]time.Duration(nil), d.seeks...), d.closed
+}
+
+// soundDecoder is a fakeDecoder with endless mono sound at 1000 Hz.
+type soundDecoder struct{ *fakeDecoder }
+
+func (soundDecoder) AudioFormat() (rate, channels int) { return 1000, 1 }
+
+func (soundDecoder) ReadAudio(buf []float32) (int, error) {
+	n := min(len(buf), 100)
+	clear(buf[:n])
+	return n, nil
+}
+
+// fakeAudio records what it is given.
+type fakeAudio struct {
+	mu      sync.Mutex
+	format  [2]int
+	samples int
+	paused  []bool
+	flushes int
+	closed  bool
+}
+
+func (a *fakeAudio) Open(rate, channels int) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.format = [2]int{rate, channels}
+	return nil
+}
+
+func (a *fakeAudio) Write(samples []float32) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.samples += len(samples)
+}
+
+func (a *fakeAudio) SetPaused(paused bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.paused = append(a.paused, paused)
+}
+
+func (a *fakeAudio) Flush() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.flushes++
+}
+
+func (a *fakeAudio) Close() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.closed = true
+}
+
+// waitFor runs the functions posted to ctx until cond holds.
+func waitFor(t *testing.T, ctx *core.Context, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		ctx.RunPosted()
+		if cond() {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+type videoHost struct {
+	dec VideoDecoder
+	err error
+}
+
+func (h videoHost) OpenVideo(uri string) (VideoDecoder, error) {
+	return h.dec, h.err
+}
+
+var videoBounds = core.R(0, 0, 200, 100)
+
+func TestFormatVideoTime(t *testing.T) {
+	tests := []struct {
+		t    time.Duration
+		want string
+	}{
+		{0, "0:00"},
+		{1500 * time.Millisecond, "0:01"},
+		{75 * time.Second, "1:15"},
+		{59*time.Minute + 59*time.Second, "59:59"},
+		{time.Hour + 2*time.Minute + 3*time.Second, "1:02:03"},
+	}
+	for _, tt := range tests {
+		if got := formatVideoTime(tt.t); got != tt.want {
+			t.Errorf("formatVideoTime(%v) = %q, want %q", tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestVideoErrors(t *testing.T) {
+	opened := errors.New("no such file")
+	tests := []struct {
+		name string
+		src  VideoSource
+		host VideoHost
+		err  error
+	}{
+		{"empty", VideoSource{}, nil, nil},
+		{"no host", VideoSource{URI: "a.mp4"}, nil, ErrNoVideoHost},
+		{"open fails", VideoSource{URI: "a.mp4"}, videoHost{err: opened}, opened},
+		{"decoding fails", VideoSource{Decoder: &errFirstDecoder{}}, nil, errDecode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := core.NewContext()
+			if tt.host != nil {
+				SetVideoHost(ctx, tt.host)
+			}
+			v := NewVideo(tt.src)
+			ctx.LayoutRoot(v, videoBounds)
+			waitFor(t, ctx, "the error", func() bool { return v.Err() != nil })
+			if tt.err != nil && v.Err() != tt.err {
+				t.Errorf("Err() = %v, want %v", v.Err(), tt.err)
+			}
+			cv := &textCanvas{}
+			v.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+			if !equalStrings(cv.texts, []string{"Video unavailable"}) {
+				t.Errorf("drew %q, want the error without the bar", cv.texts)
+			}
+		})
+	}
+}
+
+// errFirstDecoder fails to decode its first frame.
+type errFirstDecoder struct{ fakeDecoder }
+
+func (d *errFirstDecoder) NextFrame() (VideoFrame, error) { return VideoFrame{}, errDecode }
+
+func TestVideoPoster(t *testing.T) {
+	dec := newFakeDecoder(10, 10*time.Millisecond)
+	ctx := core.NewContext()
+	SetVideoHost(ctx, videoHost{dec: dec})
+	v := NewVideo(VideoSource{URI: "a.mp4"})
+	lc := &core.LayoutContext{Context: ctx}
+	if got := lc.Measure(v, core.Unbounded()); got != core.Sz(320, 180) {
+		t.Errorf("size before the first frame %v", got)
+	}
+	waitFor(t, ctx, "the poster", func() bool { return v.frame != nil })
+	if v.Playing() || v.Duration() != 100*time.Millisecond || v.Position() != 0 {
+		t.Errorf("playing %v at %v of %v", v.Playing(), v.Position(), v.Duration())
+	}
+	tests := []struct {
+		c    core.Constraints
+		want core.Size
+	}{
+		{core.Unbounded(), core.Sz(20, 10)},
+		{core.Loose(core.Sz(10, 100)), core.Sz(10, 5)},
+		{core.Loose(core.Sz(100, 2)), core.Sz(4, 2)},
+	}
+	for _, tt := range tests {
+		if got := lc.Measure(v, tt.c); got != tt.want {
+			t.Errorf("size in %v = %v, want %v", tt.c, got, tt.want)
+		}
+	}
+	if got := lc.Measure(NewVideo(VideoSource{}).Size(50, 40), core.Unbounded()); got != core.Sz(50, 40) {
+		t.Errorf("a sized video is %v", got)
+	}
+
+	v.Close()
+	waitFor(t, ctx, "the decoder to close", func() bool { _, closed := dec.state(); return closed })
+}
+
+func TestVideoPlayback(t *testing.T) {
+	dec := newFakeDecoder(5, 5*time.Millisecond)
+	ended := 0
+	v := NewVideo(VideoSource{Decoder: dec}).Autoplay(true).OnEnd(func(*core.Context) { ended++ })
+	ctx := layoutAt(v, videoBounds)
+	if !v.Playing() {
+		t.Fatal("Autoplay did not start playback")
+	}
+	waitFor(t, ctx, "the end", func() bool { return ended == 1 })
+	if v.Playing() || v.Position() != 20*time.Millisecond {
+		t.Errorf("at the end playing %v at %v", v.Playing(), v.Position())
+	}
+
+	// Playing again starts over.
+	v.Play()
+	waitFor(t, ctx, "the second end", func() bool { return ended == 2 })
+	if seeks, _ := dec.state(); len(seeks) != 1 || seeks[0] != 0 {
+		t.Errorf("seeks %v, want one to the start", seeks)
+	}
+	v.Close()
+
+	// A looping video seeks back to the start instead of ending.
+	dec = newFakeDecoder(3, 2*time.Millisecond)
+	v = NewVideo(VideoSource{Decoder: dec}).Loop(true).OnEnd(func(*core.Context) { ended++ })
+	ctx = layoutAt(v, videoBounds)
+	v.Play()
+	waitFor(t, ctx, "the loops", func() bool { seeks, _ := dec.state(); return len(seeks) >= 2 })
+	v.Close()
+	if ended != 2 || v.Playing() {
+		t.Errorf("a looping video ended, playing %v", v.Playing())
+	}
+	waitFor(t, ctx, "the decoder to close", func() bool { _, closed := dec.state(); return closed })
+}
+
+func TestVideoSeek(t *testing.T) {
+	dec := newFakeDecoder(10, 10*time.Millisecond)
+	v := NewVideo(VideoSource{Decoder: dec})
+	ctx := layoutAt(v, videoBounds)
+	waitFor(t, ctx, "the duration", func() bool { return v.Duration() > 0 })
+	tests := []struct {
+		to, want time.Duration
+	}{
+		{45 * time.Millisecond, 40 * time.Millisecond},
+		{-time.Second, 0},
+		{time.Second, 90 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		v.Seek(tt.to)
+		// The last frame is presented at 90ms; seeks clamp to the duration.
+		if v.Position() != max(0, min(tt.to, 100*time.Millisecond)) {
+			t.Errorf("Seek(%v) went to %v", tt.to, v.Position())
+		}
+		waitFor(t, ctx, "the frame at "+tt.want.String(), func() bool { return v.Position() == tt.want })
+	}
+	v.Close()
+}
+
+func TestVideoReplacedSource(t *testing.T) {
+	first := newFakeDecoder(100, time.Millisecond)
+	v := NewVideo(VideoSource{Decoder: first}).Autoplay(true)
+	ctx := layoutAt(v, videoBounds)
+	waitFor(t, ctx, "the first video to play", func() bool { return v.Position() > 0 })
+	second := newFakeDecoder(1, time.Second)
+	second.dur = 0
+	v.SetSource(VideoSource{Decoder: second})
+	if v.Playing() || v.frame != nil || v.Position() != 0 {
+		t.Error("SetSource kept the state of the first video")
+	}
+	waitFor(t, ctx, "the first decoder to close", func() bool { _, closed := first.state(); return closed })
+	ctx.RunPosted()
+	if v.frame != nil {
+		t.Error("a frame of the replaced video was shown")
+	}
+	ctx.LayoutRoot(v, videoBounds)
+	waitFor(t, ctx, "the second poster", func() bool { return v.frame != nil })
+	if v.Duration() != 0 || v.Position() != 0 {
+		t.Errorf("the second video is at %v of %v", v.Position(), v.Duration())
+	}
+	v.Close()
+}
+
+func TestVideoAudio(t *testing.T) {
+	out := &fakeAudio{}
+	dec := soundDecoder{newFakeDecoder(50, 10*time.Millisecond)}
+	v := NewVideo(VideoSource{Decoder: dec}).AudioOutput(out)
+	ctx := layoutAt(v, videoBounds)
+	waitFor(t, ctx, "the poster", func() bool { return v.frame != nil })
+	v.Play()
+	queued := func() int {
+		out.mu.Lock()
+		defer out.mu.Unlock()
+		return out.samples
+	}
+	waitFor(t, ctx, "sound", func() bool { return queued() >= 200 })
+	v.Seek(100 * time.Millisecond)
+	waitFor(t, ctx, "the seek", func() bool {
+		out.mu.Lock()
+		defer out.mu.Unlock()
+		return out.flushes == 1
+	})
+	v.Close()
+	waitFor(t, ctx, "the output to close", func() bool {
+		out.mu.Lock()
+		defer out.mu.Unlock()
+		return out.closed
+	})
+	out.mu.Lock()
+	defer out.mu.Unlock()
+	if out.format != [2]int{1000, 1} {
+		t.Errorf("opened at %v", out.format)
+	}
+	if len(out.paused) < 2 || !out.paused[0] || out.paused[1] {
+		t.Errorf("paused %v, want paused until played", out.paused)
+	}
+	// The lead keeps well under a second of sound queued.
+	if out.samples > 1000 {
+		t.Errorf("%d samples queued", out.samples)
+	}
+}
+
+func TestVideoControls(t *testing.T) {
+	dec := newFakeDecoder(1, time.Millisecond)
+	dec.dur = time.Minute
+	v := NewVideo(VideoSource{Decoder: dec})
+	ctx := layoutAt(v, videoBounds)
+	waitFor(t, ctx, "the duration", func() bool { return v.Duration() > 0 })
+	paint := func() *textCanvas {
+		cv := &textCanvas{}
+		v.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+		return cv
+	}
+	if cv := paint(); !equalStrings(cv.texts, []string{"0:00 / 1:00"}) {
+		t.Fatalf("a paused video drew %q, want the bar", cv.texts)
+	}
+
+	click(ctx, v, v.button.Center())
+	if !v.Playing() || !ctx.IsFocused(v) {
+		t.Errorf("the play button left playing %v, focused %v", v.Playing(), ctx.IsFocused(v))
+	}
+	if cv := paint(); len(cv.texts) != 0 {
+		t.Errorf("playing without the pointer over it drew the bar %q", cv.texts)
+	}
+	click(ctx, v, core.Pt(100, 20))
+	if v.Playing() {
+		t.Error("a click on the picture did not pause")
+	}
+	paint()
+
+	// Dragging on the track seeks to the pointer.
+	v.HandleEvent(ctx, leftMouse(event.MouseDown, core.Pt(v.track.X+v.track.Width/2, v.track.Center().Y)))
+	if d := v.Position() - 30*time.Second; ctx.PointerCapture() != v || d < -time.Millisecond || d > time.Millisecond {
+		t.Errorf("pressing the middle of the track went to %v", v.Position())
+	}
+	v.HandleEvent(ctx, event.MouseEvent{Type: event.MouseMove, Position: core.Pt(v.track.Right()+50, 0)})
+	if v.Position() != time.Minute {
+		t.Errorf("dragging past the end went to %v", v.Position())
+	}
+	v.HandleEvent(ctx, leftMouse(event.MouseUp, core.Pt(0, 0)))
+	if ctx.PointerCapture() != nil || v.drag {
+		t.Error("the drag did not end")
+	}
+	// The rest of the bar does nothing.
+	click(ctx, v, core.Pt(v.track.Right()+2, v.track.Center().Y-videoBar/2+4))
+	if v.Playing() {
+		t.Error("a click on the bar toggled playback")
+	}
+	if r := v.HandleEvent(ctx, event.MouseEvent{Type: event.MouseDown, Button: event.ButtonRight, Position: core.Pt(100, 20)}); r != core.Ignored {
+		t.Errorf("a right click = %v", r)
+	}
+
+	keys := []struct {
+		key     event.Key
+		pos     time.Duration
+		playing bool
+	}{
+		{event.KeyHome, 0, false},
+		{event.KeyRight, 5 * time.Second, false},
+		{event.KeyRight, 10 * time.Second, false},
+		{event.KeyLeft, 5 * time.Second, false},
+		{event.KeySpace, 5 * time.Second, true},
+		{event.KeySpace, 5 * time.Second, false},
+	}
+	for _, k := range keys {
+		if r := v.HandleEvent(ctx, press(k.key, 0)); r != core.Handled || v.Position() != k.pos || v.Playing() != k.playing {
+			t.Errorf("%v = %v, at %v, playing %v", k.key, r, v.Position(), v.Playing())
+		}
+	}
+	if r := v.HandleEvent(ctx, press(event.KeyA, 0)); r != core.Ignored {
+		t.Errorf("A = %v", r)
+	}
+	if r := v.HandleEvent(ctx, press(event.KeySpace, event.ModCtrl)); r != core.Ignored {
+		t.Errorf("Ctrl+Space = %v", r)
+	}
+	ctx.RequestFocus(nil)
+	if r := v.HandleEvent(ctx, press(event.KeySpace, 0)); r != core.Ignored {
+		t.Errorf("Space without focus = %v", r)
+	}
+
+	v.Controls(false)
+	v.HandleEvent(ctx, event.MouseEvent{Type: event.MouseEnter})
+	if cv := paint(); len(cv.texts) != 0 || !v.hover {
+		t.Errorf("a video without controls drew %q", cv.texts)
+	}
+	v.HandleEvent(ctx, event.MouseEvent{Type: event.MouseLeave})
+	if v.hover {
+		t.Error("leaving kept the hover")
+	}
+	v.Close()
+}
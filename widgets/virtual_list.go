@@ -0,0 +1,454 @@
+package widgets
+
+import (
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	ilayout "github.com/gogpu/ui/internal/layout"
+	"github.com/gogpu/ui/internal/scroll"
+	"github.com/gogpu/ui/layout"
+)
+
+// ItemBuilder builds the widget for the item at index.
+type ItemBuilder func(index int) core.Widget
+
+// ScrollAlign selects where ScrollToIndex places the target item.
+type ScrollAlign uint8
+
+// Scroll alignments.
+const (
+	// ScrollNearest scrolls the minimum distance that makes the item fully
+	// visible, and does nothing if it already is.
+	ScrollNearest ScrollAlign = iota
+
+	// ScrollStart aligns the item's top with the viewport top.
+	ScrollStart
+
+	// ScrollCenter centers the item in the viewport.
+	ScrollCenter
+
+	// ScrollEnd aligns the item's bottom with the viewport bottom.
+	ScrollEnd
+)
+
+const (
+	defaultEstimatedRowHeight float32 = 24
+	defaultOverscan           float32 = 120
+	lineScrollStep            float32 = 40
+)
+
+// VirtualList is a vertically scrolling list that only builds and lays out
+// the rows inside its viewport plus a small overscan margin.
+//
+// Row heights may vary. Rows that have never been realized are assumed to
+// have the estimated height; once a row is measured its height is
+// remembered, so the scroll range converges as the user scrolls. The list
+// keeps the first visible row anchored while estimates are replaced by
+// measurements, so content does not jump.
+//
+//	list := widgets.NewVirtualList(len(entries), func(i int) core.Widget {
+//	    return widgets.NewText(entries[i])
+//	}).EstimatedItemHeight(20)
+//
+// The list should be given a bounded height; under unbounded constraints it
+// sizes itself to the estimated total height and realizes every row.
+type VirtualList struct {
+	core.WidgetBase
+	core.FocusState
+
+	count    int
+	build    ItemBuilder
+	extents  *ilayout.Extents
+	estimate float32
+	overscan float32
+
+	offset    float32
+	viewport  core.Size
+	rowWidth  float32
+	dir       core.Direction
+	rows      map[int]core.Widget
+	first     int
+	last      int
+	pending   *scrollRequest
+	bar       scroll.Bar
+	onRange   func(first, last int)
+	lastFirst int
+	lastLast  int
+	isHeader  func(index int) bool
+	sticky    int // Index of the pinned header row, or -1.
+	stagger   *layout.Stagger
+}
+
+type scrollRequest struct {
+	index int
+	align ScrollAlign
+}
+
+// NewVirtualList returns a list of count items built on demand by build.
+func NewVirtualList(count int, build ItemBuilder) *VirtualList {
+	l := &VirtualList{
+		count:     max(0, count),
+		build:     build,
+		estimate:  defaultEstimatedRowHeight,
+		overscan:  defaultOverscan,
+		rows:      make(map[int]core.Widget),
+		lastFirst: -1,
+		lastLast:  -1,
+		sticky:    -1,
+	}
+	l.extents = ilayout.NewExtents(l.count, l.estimate)
+	return l
+}
+
+// EstimatedItemHeight sets the height assumed for rows that have not been
+// measured yet. A good estimate keeps the scrollbar stable.
+func (l *VirtualList) EstimatedItemHeight(h float32) *VirtualList {
+	if h > 0 {
+		l.estimate = h
+		l.extents.SetEstimate(h)
+	}
+	return l
+}
+
+// Overscan sets how many pixels beyond each viewport edge are realized, so
+// short scrolls do not expose unbuilt rows.
+func (l *VirtualList) Overscan(px float32) *VirtualList {
+	l.overscan = max(0, px)
+	return l
+}
+
+// OnVisibleRangeChange registers fn to be called after layout whenever the
+// range of visible rows changes. last is exclusive.
+func (l *VirtualList) OnVisibleRangeChange(fn func(first, last int)) *VirtualList {
+	l.onRange = fn
+	return l
+}
+
+// StickyHeaders marks the rows for which isHeader returns true as section
+// headers. The header of the section at the top of the viewport stays
+// docked there until the next header pushes it out.
+func (l *VirtualList) StickyHeaders(isHeader func(index int) bool) *VirtualList {
+	l.isHeader = isHeader
+	return l
+}
+
+// Stagger makes the rows built enter through st, which scrolling
+// cancels, so that the rows shown first enter one after another.
+func (l *VirtualList) Stagger(st *layout.Stagger) *VirtualList {
+	l.stagger = st
+	return l
+}
+
+// Count returns the number of items.
+func (l *VirtualList) Count() int {
+	return l.count
+}
+
+// SetCount changes the number of items. Measurements of items that remain
+// are kept; call Refresh if existing items changed as well.
+func (l *VirtualList) SetCount(n int) {
+	n = max(0, n)
+	if n == l.count {
+		return
+	}
+	l.count = n
+	l.extents.Resize(n)
+	for i := range l.rows {
+		if i >= n {
+			delete(l.rows, i)
+		}
+	}
+}
+
+// InvalidateItem discards the realized widget and measured height of the
+// item at index, so it is rebuilt on the next layout.
+func (l *VirtualList) InvalidateItem(index int) {
+	if index < 0 || index >= l.count {
+		return
+	}
+	delete(l.rows, index)
+	l.extents.Forget(index)
+}
+
+// Refresh discards every realized row and measurement.
+func (l *VirtualList) Refresh() {
+	clear(l.rows)
+	l.extents.Reset(l.count)
+}
+
+// ScrollOffset returns the distance in pixels from the top of the content
+// to the top of the viewport.
+func (l *VirtualList) ScrollOffset() float32 {
+	return l.offset
+}
+
+// SetScrollOffset scrolls to the given content offset.
+func (l *VirtualList) SetScrollOffset(y float32) {
+	l.pending = nil
+	old := l.offset
+	l.offset = l.clampOffset(y)
+	if l.offset != old && l.stagger != nil {
+		l.stagger.Cancel()
+	}
+}
+
+// ScrollBy scrolls by dy pixels and reports whether the offset changed.
+func (l *VirtualList) ScrollBy(dy float32) bool {
+	old := l.offset
+	l.SetScrollOffset(l.offset + dy)
+	return l.offset != old
+}
+
+// ScrollToIndex scrolls so that the item at index is positioned according
+// to align. The request is resolved during the next layout, once the
+// viewport size and the item's real height are known.
+func (l *VirtualList) ScrollToIndex(index int, align ScrollAlign) {
+	if l.count == 0 {
+		return
+	}
+	l.pending = &scrollRequest{index: min(max(index, 0), l.count-1), align: align}
+}
+
+// VisibleRange returns the indexes of the rows intersecting the viewport
+// after the last layout. last is exclusive.
+func (l *VirtualList) VisibleRange() (first, last int) {
+	if l.count == 0 {
+		return 0, 0
+	}
+	top := l.extents.IndexAt(l.offset)
+	bottom := l.extents.IndexAt(l.offset + l.viewport.Height - 0.5)
+	return top, bottom + 1
+}
+
+func (l *VirtualList) clampOffset(y float32) float32 {
+	return core.Clamp(y, 0, max(0, l.extents.Total()-l.viewport.Height))
+}
+
+func (l *VirtualList) resolvePending() {
+	req := l.pending
+	top := l.extents.Offset(req.index)
+	h := l.extents.Size(req.index)
+	vh := l.viewport.Height
+	switch req.align {
+	case ScrollStart:
+		l.offset = top
+	case ScrollCenter:
+		l.offset = top + h/2 - vh/2
+	case ScrollEnd:
+		l.offset = top + h - vh
+	case ScrollNearest:
+		if top < l.offset {
+			l.offset = top
+		} else if top+h > l.offset+vh {
+			l.offset = top + h - vh
+		}
+	}
+	l.offset = l.clampOffset(l.offset)
+}
+
+// Layout implements core.Widget.
+func (l *VirtualList) Layout(ctx *core.LayoutContext) core.Size {
+	l.dir = ctx.Direction()
+	c := ctx.Constraints
+	width := c.MinWidth
+	if c.HasBoundedWidth() {
+		width = c.MaxWidth
+	}
+	rowWidth := max(0, width-scroll.Thickness)
+	if rowWidth != l.rowWidth {
+		// Wrapped content changes height with width; old measurements are
+		// no longer valid.
+		l.rowWidth = rowWidth
+		l.extents.Reset(l.count)
+	}
+	height := l.extents.Total()
+	if c.HasBoundedHeight() {
+		height = c.MaxHeight
+	}
+	l.viewport = c.Constrain(core.Size{Width: width, Height: height})
+
+	if l.pending != nil {
+		// Resolve against estimates, realize the target so its real height
+		// is known, then resolve again.
+		l.resolvePending()
+		l.realize(ctx)
+		l.resolvePending()
+		l.pending = nil
+	}
+	l.offset = l.clampOffset(l.offset)
+	l.realize(ctx)
+
+	if l.onRange != nil {
+		if first, last := l.VisibleRange(); first != l.lastFirst || last != l.lastLast {
+			l.lastFirst, l.lastLast = first, last
+			l.onRange(first, last)
+		}
+	}
+	return l.viewport
+}
+
+// realize builds and measures the rows covering the viewport and overscan,
+// releasing rows that scrolled out.
+func (l *VirtualList) realize(ctx *core.LayoutContext) {
+	if l.count == 0 {
+		l.first, l.last = 0, 0
+		l.SetChildren()
+		clear(l.rows)
+		return
+	}
+	rowC := core.Constraints{MinWidth: l.rowWidth, MaxWidth: l.rowWidth, MaxHeight: core.Infinity}
+	live := make(map[int]core.Widget, len(l.rows))
+	measure := func(i int) {
+		w, ok := l.rows[i]
+		if !ok {
+			w = l.build(i)
+			if l.stagger != nil {
+				w = l.stagger.Item(i, w)
+			}
+		}
+		live[i] = w
+		l.extents.Set(i, ctx.Measure(w, rowC).Height)
+	}
+
+	// Measure the overscan above the anchor row first; then re-derive the
+	// offset so the anchor stays put even if those rows changed height.
+	anchor := l.extents.IndexAt(l.offset)
+	delta := l.offset - l.extents.Offset(anchor)
+	first := l.extents.IndexAt(l.offset - l.overscan)
+	for i := first; i < anchor; i++ {
+		measure(i)
+	}
+	l.offset = l.extents.Offset(anchor) + delta
+
+	last := anchor
+	for ; last < l.count; last++ {
+		if l.extents.Offset(last) >= l.offset+l.viewport.Height+l.overscan {
+			break
+		}
+		measure(last)
+	}
+
+	// Near the end of the content the clamp can pull the viewport up past
+	// the rows realized so far.
+	l.offset = l.clampOffset(l.offset)
+	for first > 0 && l.extents.Offset(first) > l.offset-l.overscan {
+		first--
+		measure(first)
+		l.offset = l.clampOffset(l.offset)
+	}
+
+	// The header of the top section is kept realized even when it scrolled
+	// out, and comes last so it paints and hit-tests above the rows.
+	l.sticky = -1
+	if l.isHeader != nil {
+		for h := l.extents.IndexAt(l.offset); h >= 0; h-- {
+			if l.isHeader(h) {
+				if h < first {
+					measure(h)
+				}
+				l.sticky = h
+				break
+			}
+		}
+	}
+
+	l.rows = live
+	l.first, l.last = first, last
+	children := make([]core.Widget, 0, last-first+1)
+	for i := first; i < last; i++ {
+		if i != l.sticky {
+			children = append(children, live[i])
+		}
+	}
+	if l.sticky >= 0 {
+		children = append(children, live[l.sticky])
+	}
+	l.SetChildren(children...)
+}
+
+// SetBounds implements core.Widget and positions the realized rows.
+func (l *VirtualList) SetBounds(r core.Rect) {
+	l.WidgetBase.SetBounds(r)
+	track, area := scroll.EndTrack(r, l.dir)
+	for i := l.first; i < l.last; i++ {
+		y := r.Y + l.extents.Offset(i) - l.offset
+		l.rows[i].SetBounds(core.R(area.X, y, l.rowWidth, l.extents.Size(i)))
+	}
+	if h := l.sticky; h >= 0 {
+		height := l.extents.Size(h)
+		y := max(r.Y+l.extents.Offset(h)-l.offset, r.Y)
+		for n := h + 1; n < l.last; n++ {
+			if l.isHeader(n) {
+				y = min(y, r.Y+l.extents.Offset(n)-l.offset-height)
+				break
+			}
+		}
+		l.rows[h].SetBounds(core.R(area.X, y, l.rowWidth, height))
+	}
+	l.bar.Track = track
+	l.bar.Viewport = r.Height
+	l.bar.Content = l.extents.Total()
+}
+
+// Paint implements core.Widget.
+func (l *VirtualList) Paint(ctx *core.PaintContext) {
+	ctx.Canvas.Save()
+	ctx.Canvas.Clip(l.Bounds())
+	core.PaintChildren(ctx, l.Children())
+	l.bar.Paint(ctx, l.offset)
+	ctx.Canvas.Restore()
+}
+
+// HandleEvent implements core.Widget. It scrolls on wheel input, on
+// scrollbar drags and, while focused, on arrow, page, Home and End keys.
+func (l *VirtualList) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	if off, ok := l.bar.HandleEvent(ctx, l, ev, l.offset); ok {
+		if off != l.offset {
+			l.SetScrollOffset(off)
+			ctx.MarkNeedsLayout(l)
+		}
+		return core.Handled
+	}
+	switch e := ev.(type) {
+	case event.ScrollEvent:
+		if l.ScrollBy(e.Delta.Y) {
+			ctx.MarkNeedsLayout(l)
+			return core.Handled
+		}
+	case event.MouseEvent:
+		if e.Type == event.MouseDown {
+			ctx.RequestFocus(l)
+		}
+	case event.KeyEvent:
+		if e.Type != event.KeyPress || !l.IsFocused() {
+			return core.Ignored
+		}
+		var changed bool
+		switch e.Key {
+		case event.KeyUp:
+			changed = l.ScrollBy(-lineScrollStep)
+		case event.KeyDown:
+			changed = l.ScrollBy(lineScrollStep)
+		case event.KeyPageUp:
+			changed = l.ScrollBy(-l.viewport.Height)
+		case event.KeyPageDown:
+			changed = l.ScrollBy(l.viewport.Height)
+		case event.KeyHome:
+			l.ScrollToIndex(0, ScrollStart)
+			changed = true
+		case event.KeyEnd:
+			l.ScrollToIndex(l.count-1, ScrollEnd)
+			changed = true
+		default:
+			return core.Ignored
+		}
+		if changed {
+			if l.stagger != nil {
+				// Home and End scroll in the next layout.
+				l.stagger.Cancel()
+			}
+			ctx.MarkNeedsLayout(l)
+		}
+		return core.Handled
+	}
+	return core.Ignored
+}
@@ -0,0 +1,178 @@
+package widgets
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/internal/scroll"
+)
+
+// fixed is a leaf of a fixed height that fills the width it is given.
+type fixed struct {
+	core.WidgetBase
+	height float32
+}
+
+func (f *fixed) Layout(ctx *core.LayoutContext) core.Size {
+	return ctx.Constraints.Constrain(core.Sz(ctx.Constraints.MaxWidth, f.height))
+}
+
+func (f *fixed) Paint(*core.PaintContext) {}
+
+// rows returns a list of count rows of height h each, estimated right, and
+// the indexes it built, in order.
+func rows(count int, h float32) (*VirtualList, *[]int) {
+	var built []int
+	l := NewVirtualList(count, func(i int) core.Widget {
+		built = append(built, i)
+		return &fixed{height: h}
+	}).EstimatedItemHeight(h)
+	return l, &built
+}
+
+// layoutAt lays w out to fill bounds and returns its context.
+func layoutAt(w core.Widget, bounds core.Rect) *core.Context {
+	ctx := core.NewContext()
+	ctx.LayoutRoot(w, bounds)
+	return ctx
+}
+
+// layoutRTL lays w out in bounds in right-to-left text.
+func layoutRTL(w core.Widget, bounds core.Rect) *core.Context {
+	ctx := core.NewContext()
+	ctx.SetDirection(core.RightToLeft)
+	ctx.LayoutRoot(w, bounds)
+	return ctx
+}
+
+func TestVirtualListRealizesViewport(t *testing.T) {
+	l, built := rows(1000, 20)
+	l.Overscan(0)
+	layoutAt(l, core.R(0, 0, 200, 100))
+	if got := len(*built); got != 5 {
+		t.Errorf("built %d rows, want 5", got)
+	}
+	if first, last := l.VisibleRange(); first != 0 || last != 5 {
+		t.Errorf("VisibleRange() = %d, %d, want 0, 5", first, last)
+	}
+	kids := l.Children()
+	if got, want := kids[2].Bounds(), core.R(0, 40, 200-scroll.Thickness, 20); got != want {
+		t.Errorf("row 2 bounds = %v, want %v", got, want)
+	}
+}
+
+func TestVirtualListScroll(t *testing.T) {
+	tests := []struct {
+		name        string
+		scroll      func(l *VirtualList)
+		offset      float32
+		first, last int
+	}{
+		{"offset", func(l *VirtualList) { l.SetScrollOffset(110) }, 110, 5, 11},
+		{"clamped below", func(l *VirtualList) { l.SetScrollOffset(-50) }, 0, 0, 5},
+		{"clamped above", func(l *VirtualList) { l.SetScrollOffset(1e6) }, 1900, 95, 100},
+		{"start", func(l *VirtualList) { l.ScrollToIndex(50, ScrollStart) }, 1000, 50, 55},
+		{"center", func(l *VirtualList) { l.ScrollToIndex(50, ScrollCenter) }, 960, 48, 53},
+		{"end", func(l *VirtualList) { l.ScrollToIndex(50, ScrollEnd) }, 920, 46, 51},
+		{"nearest below", func(l *VirtualList) { l.ScrollToIndex(50, ScrollNearest) }, 920, 46, 51},
+		{"nearest visible", func(l *VirtualList) { l.ScrollToIndex(2, ScrollNearest) }, 0, 0, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, _ := rows(100, 20)
+			ctx := layoutAt(l, core.R(0, 0, 200, 100))
+			tt.scroll(l)
+			ctx.LayoutRoot(l, core.R(0, 0, 200, 100))
+			if got := l.ScrollOffset(); got != tt.offset {
+				t.Errorf("ScrollOffset() = %v, want %v", got, tt.offset)
+			}
+			if first, last := l.VisibleRange(); first != tt.first || last != tt.last {
+				t.Errorf("VisibleRange() = %d, %d, want %d, %d", first, last, tt.first, tt.last)
+			}
+		})
+	}
+}
+
+func TestVirtualListMeasuresRows(t *testing.T) {
+	// Rows taller than the estimate grow the content as they are measured.
+	l := NewVirtualList(10, func(i int) core.Widget { return &fixed{height: 50} }).
+		EstimatedItemHeight(10).Overscan(0)
+	layoutAt(l, core.R(0, 0, 100, 100))
+	if first, last := l.VisibleRange(); first != 0 || last != 2 {
+		t.Errorf("VisibleRange() = %d, %d, want 0, 2", first, last)
+	}
+	if got := l.extents.Total(); got != 2*50+8*10 {
+		t.Errorf("total = %v, want %v", got, 2*50+8*10)
+	}
+}
+
+func TestVirtualListRebuild(t *testing.T) {
+	l, built := rows(10, 20)
+	ctx := layoutAt(l, core.R(0, 0, 100, 100))
+	*built = nil
+	ctx.LayoutRoot(l, core.R(0, 0, 100, 100))
+	if len(*built) != 0 {
+		t.Errorf("relayout rebuilt rows %v", *built)
+	}
+	l.InvalidateItem(3)
+	ctx.LayoutRoot(l, core.R(0, 0, 100, 100))
+	if !slices.Equal(*built, []int{3}) {
+		t.Errorf("InvalidateItem rebuilt %v, want [3]", *built)
+	}
+	*built = nil
+	l.SetCount(2)
+	ctx.LayoutRoot(l, core.R(0, 0, 100, 100))
+	if l.Count() != 2 || len(l.Children()) != 2 || len(*built) != 0 {
+		t.Errorf("SetCount(2): %d rows, %d children, rebuilt %v", l.Count(), len(l.Children()), *built)
+	}
+	l.Refresh()
+	ctx.LayoutRoot(l, core.R(0, 0, 100, 100))
+	if !slices.Equal(*built, []int{0, 1}) {
+		t.Errorf("Refresh rebuilt %v, want [0 1]", *built)
+	}
+}
+
+func TestVirtualListRangeChange(t *testing.T) {
+	var got [][2]int
+	l, _ := rows(100, 20)
+	l.OnVisibleRangeChange(func(first, last int) { got = append(got, [2]int{first, last}) })
+	ctx := layoutAt(l, core.R(0, 0, 100, 100))
+	ctx.LayoutRoot(l, core.R(0, 0, 100, 100))
+	l.SetScrollOffset(40)
+	ctx.LayoutRoot(l, core.R(0, 0, 100, 100))
+	if want := [][2]int{{0, 5}, {2, 7}}; !slices.Equal(got, want) {
+		t.Errorf("ranges = %v, want %v", got, want)
+	}
+}
+
+func TestVirtualListEvents(t *testing.T) {
+	tests := []struct {
+		name   string
+		ev     core.Event
+		offset float32
+	}{
+		{"wheel", event.ScrollEvent{Delta: core.Pt(0, 30)}, 130},
+		{"down", event.KeyEvent{Type: event.KeyPress, Key: event.KeyDown}, 100 + lineScrollStep},
+		{"up", event.KeyEvent{Type: event.KeyPress, Key: event.KeyUp}, 100 - lineScrollStep},
+		{"page down", event.KeyEvent{Type: event.KeyPress, Key: event.KeyPageDown}, 200},
+		{"home", event.KeyEvent{Type: event.KeyPress, Key: event.KeyHome}, 0},
+		{"end", event.KeyEvent{Type: event.KeyPress, Key: event.KeyEnd}, 1900},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, _ := rows(100, 20)
+			ctx := layoutAt(l, core.R(0, 0, 200, 100))
+			ctx.RequestFocus(l)
+			l.SetScrollOffset(100)
+			if got := l.HandleEvent(ctx, tt.ev); got != core.Handled {
+				t.Fatalf("HandleEvent() = %v, want Handled", got)
+			}
+			ctx.LayoutRoot(l, core.R(0, 0, 200, 100))
+			if got := l.ScrollOffset(); got != tt.offset {
+				t.Errorf("ScrollOffset() = %v, want %v", got, tt.offset)
+			}
+		})
+	}
+}
@@ -0,0 +1,341 @@
+package widgets
+
+import (
+	"errors"
+	"image"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/theme"
+)
+
+// WebView default size.
+const (
+	webViewWidth  float32 = 640
+	webViewHeight float32 = 480
+)
+
+// WebViewHost is implemented by platform integrations that can embed a
+// browser engine, such as WebView2 on Windows, WKWebView on macOS and
+// WebKitGTK on Linux.
+//
+// Every page loaded in a view gets a bridge object, window.gogpu: its
+// postMessage(text) sends text to the app, and a function assigned to its
+// onmessage property receives the app's messages as its argument.
+type WebViewHost interface {
+	// NewWebView creates a view, hidden until it is first shown, that
+	// reports to events.
+	NewWebView(events WebViewEvents) (NativeWebView, error)
+}
+
+// WebViewEvents are the callbacks of a NativeWebView. The host may call
+// them from any goroutine.
+type WebViewEvents struct {
+	// Message receives the text posted by the page through the bridge.
+	Message func(text string)
+	// Navigated reports the URL of the page once it starts loading.
+	Navigated func(url string)
+	// TitleChanged reports the title of the page.
+	TitleChanged func(title string)
+	// Repaint reports that a composited view has a new frame.
+	Repaint func()
+}
+
+// NativeWebView is a browser view created by a WebViewHost.
+//
+// A view is either a native child window placed over the client area,
+// which is drawn above everything else and receives its own input, or
+// rendered offscreen and drawn into the window's frame by the widget, in
+// which case it also implements CompositedWebView.
+type NativeWebView interface {
+	// Show places the view to cover r, in window coordinates.
+	Show(r core.Rect)
+	// Hide hides the view, keeping its page loaded.
+	Hide()
+	// Navigate loads the page at url.
+	Navigate(url string)
+	// LoadHTML loads a page from source.
+	LoadHTML(html string)
+	// Eval runs a script in the page.
+	Eval(script string)
+	// PostMessage sends text to the page through the bridge.
+	PostMessage(text string)
+	// Close releases the view.
+	Close()
+}
+
+// CompositedWebView is implemented by views rendered offscreen. They are
+// clipped, overlapped and scrolled like any widget, and get their input
+// from the widget.
+type CompositedWebView interface {
+	NativeWebView
+	// Frame returns the last rendered frame of the page, or nil before
+	// the first.
+	Frame() image.Image
+	// HandleEvent delivers input, with pointer positions relative to the
+	// view's top-left corner, and reports whether the page consumed it.
+	HandleEvent(ev core.Event) bool
+}
+
+type webViewHostKey struct{}
+
+// SetWebViewHost installs the host creating the views of every WebView in
+// the window. Without one a WebView shows only a placeholder.
+func SetWebViewHost(ctx *core.Context, h WebViewHost) {
+	ctx.SetValue(webViewHostKey{}, h)
+}
+
+// ErrNoWebViewHost is the error of a WebView in a window without a
+// WebViewHost.
+var ErrNoWebViewHost = errors.New("widgets: no web view host")
+
+// WebView hosts web content in a browser view provided by the platform
+// integration; see WebViewHost.
+//
+// The view is created when the widget is first laid out; until then, and
+// if creating it fails, a placeholder is drawn. Loads, scripts and
+// messages issued before are delivered once it exists. A view that is not
+// painted in a frame, because the widget left the tree or was hidden, is
+// hidden with it.
+type WebView struct {
+	core.WidgetBase
+	core.FocusState
+
+	view    NativeWebView
+	err     error
+	created bool
+	pending []func(NativeWebView)
+
+	url, title string
+	size       core.Size
+	onMessage  func(ctx *core.Context, text string)
+	onNavigate func(ctx *core.Context, url string)
+	onTitle    func(ctx *core.Context, title string)
+
+	shown     core.Rect
+	visible   bool
+	painted   bool
+	scheduled bool
+}
+
+// NewWebView returns a WebView loading url, which may be empty.
+func NewWebView(url string) *WebView {
+	v := &WebView{size: core.Sz(webViewWidth, webViewHeight)}
+	if url != "" {
+		v.Navigate(url)
+	}
+	return v
+}
+
+// Navigate loads the page at url.
+func (v *WebView) Navigate(url string) {
+	v.url = url
+	v.do(func(n NativeWebView) { n.Navigate(url) })
+}
+
+// LoadHTML loads a page from source.
+func (v *WebView) LoadHTML(html string) {
+	v.do(func(n NativeWebView) { n.LoadHTML(html) })
+}
+
+// Eval runs a script in the page.
+func (v *WebView) Eval(script string) {
+	v.do(func(n NativeWebView) { n.Eval(script) })
+}
+
+// PostMessage sends text to the page, whose window.gogpu.onmessage
+// function receives it.
+func (v *WebView) PostMessage(text string) {
+	v.do(func(n NativeWebView) { n.PostMessage(text) })
+}
+
+// URL returns the URL of the page shown.
+func (v *WebView) URL() string {
+	return v.url
+}
+
+// Title returns the title of the page shown.
+func (v *WebView) Title() string {
+	return v.title
+}
+
+// Err returns why the view could not be created, or nil.
+func (v *WebView) Err() error {
+	return v.err
+}
+
+// OnMessage sets the function receiving the text the page posts with
+// window.gogpu.postMessage.
+func (v *WebView) OnMessage(fn func(ctx *core.Context, text string)) *WebView {
+	v.onMessage = fn
+	return v
+}
+
+// OnNavigate sets the function called when a page starts loading, whether
+// the app or the user navigated.
+func (v *WebView) OnNavigate(fn func(ctx *core.Context, url string)) *WebView {
+	v.onNavigate = fn
+	return v
+}
+
+// OnTitle sets the function called when the page's title changes.
+func (v *WebView) OnTitle(fn func(ctx *core.Context, title string)) *WebView {
+	v.onTitle = fn
+	return v
+}
+
+// Size sets the preferred size. The default is 640 by 480.
+func (v *WebView) Size(w, h float32) *WebView {
+	v.size = core.Sz(w, h)
+	return v
+}
+
+// Close releases the view. A closed WebView shows its placeholder.
+func (v *WebView) Close() {
+	if v.view != nil {
+		v.view.Close()
+		v.view = nil
+	}
+	v.pending = nil
+	v.created, v.visible = true, false
+}
+
+// do runs fn on the view, or once it is created.
+func (v *WebView) do(fn func(NativeWebView)) {
+	switch {
+	case v.view != nil:
+		fn(v.view)
+	case !v.created:
+		v.pending = append(v.pending, fn)
+	}
+}
+
+func (v *WebView) create(ctx *core.Context) {
+	v.created = true
+	h, ok := ctx.Value(webViewHostKey{}).(WebViewHost)
+	if !ok {
+		v.err, v.pending = ErrNoWebViewHost, nil
+		return
+	}
+	post := func(fn func()) {
+		ctx.Post(func() {
+			if v.view != nil {
+				fn()
+			}
+		})
+	}
+	view, err := h.NewWebView(WebViewEvents{
+		Message: func(text string) {
+			post(func() {
+				if v.onMessage != nil {
+					v.onMessage(ctx, text)
+				}
+			})
+		},
+		Navigated: func(url string) {
+			post(func() {
+				v.url = url
+				if v.onNavigate != nil {
+					v.onNavigate(ctx, url)
+				}
+			})
+		},
+		TitleChanged: func(title string) {
+			post(func() {
+				v.title = title
+				if v.onTitle != nil {
+					v.onTitle(ctx, title)
+				}
+			})
+		},
+		Repaint: func() { ctx.Post(func() { ctx.Repaint(v) }) },
+	})
+	if err != nil {
+		v.err, v.pending = err, nil
+		return
+	}
+	v.view = view
+	for _, fn := range v.pending {
+		fn(view)
+	}
+	v.pending = nil
+}
+
+// Layout implements core.Widget.
+func (v *WebView) Layout(ctx *core.LayoutContext) core.Size {
+	if !v.created {
+		v.create(ctx.Context)
+	}
+	return ctx.Constraints.Constrain(v.size)
+}
+
+// Paint implements core.Widget.
+func (v *WebView) Paint(ctx *core.PaintContext) {
+	b := v.Bounds()
+	if v.view == nil {
+		th := theme.From(ctx.Context)
+		ctx.Canvas.DrawRect(b, core.Filled(th.Colors.SurfaceVariant))
+		st := theme.TextStyle(th.Typography.Body, th.Colors.OnSurfaceVariant)
+		text := "Web content unavailable"
+		sz := ctx.MeasureText(text, st)
+		ctx.Canvas.DrawText(text, core.Pt(b.X+(b.Width-sz.Width)/2, b.Y+(b.Height-sz.Height)/2), st)
+		return
+	}
+	if !v.visible || v.shown != b {
+		v.view.Show(b)
+		v.shown, v.visible = b, true
+	}
+	v.painted = true
+	if !v.scheduled {
+		v.scheduled = true
+		ctx.AfterFrame(func() { v.afterFrame(ctx.Context) })
+	}
+	if c, ok := v.view.(CompositedWebView); ok {
+		if img := c.Frame(); img != nil {
+			ctx.Canvas.DrawImage(img, b)
+		}
+	}
+}
+
+// afterFrame hides the view if it was not painted in the frame, and
+// otherwise checks again after the next one.
+func (v *WebView) afterFrame(ctx *core.Context) {
+	if v.painted {
+		v.painted = false
+		ctx.AfterFrame(func() { v.afterFrame(ctx) })
+		return
+	}
+	v.scheduled = false
+	if v.view != nil && v.visible {
+		v.view.Hide()
+		v.visible = false
+	}
+}
+
+// HandleEvent implements core.Widget.
+func (v *WebView) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	c, ok := v.view.(CompositedWebView)
+	if !ok {
+		return core.Ignored
+	}
+	o := v.Bounds().Origin()
+	switch e := ev.(type) {
+	case event.MouseEvent:
+		if e.Type == event.MouseDown {
+			ctx.RequestFocus(v)
+		}
+		e.Position = e.Position.Sub(o)
+		ev = e
+	case event.ScrollEvent:
+		e.Position = e.Position.Sub(o)
+		ev = e
+	case event.KeyEvent, event.TextEvent:
+		if !v.IsFocused() {
+			return core.Ignored
+		}
+	}
+	if c.HandleEvent(ev) {
+		return core.Handled
+	}
+	return core.Ignored
+}
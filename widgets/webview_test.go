@@ -0,0 +1,266 @@
+package widgets
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+)
+
+// fakeWebView records the calls made to it.
+type fakeWebView struct {
+	calls []string
+}
+
+func (v *fakeWebView) Show(r core.Rect) {
+	v.calls = append(v.calls, fmt.Sprintf("show %v %v %v %v", r.X, r.Y, r.Width, r.Height))
+}
+func (v *fakeWebView) Hide()                   { v.calls = append(v.calls, "hide") }
+func (v *fakeWebView) Navigate(url string)     { v.calls = append(v.calls, "navigate "+url) }
+func (v *fakeWebView) LoadHTML(html string)    { v.calls = append(v.calls, "html "+html) }
+func (v *fakeWebView) Eval(script string)      { v.calls = append(v.calls, "eval "+script) }
+func (v *fakeWebView) PostMessage(text string) { v.calls = append(v.calls, "post "+text) }
+func (v *fakeWebView) Close()                  { v.calls = append(v.calls, "close") }
+
+// take returns the calls made since the last take.
+func (v *fakeWebView) take() []string {
+	calls := v.calls
+	v.calls = nil
+	return calls
+}
+
+// compositedView is a fakeWebView rendered offscreen.
+type compositedView struct {
+	fakeWebView
+	frame   image.Image
+	got     []core.Event
+	consume bool
+}
+
+func (v *compositedView) Frame() image.Image { return v.frame }
+
+func (v *compositedView) HandleEvent(ev core.Event) bool {
+	v.got = append(v.got, ev)
+	return v.consume
+}
+
+// fakeWebHost creates the view it is given, keeping its events, or fails
+// with err.
+type fakeWebHost struct {
+	view   NativeWebView
+	err    error
+	events WebViewEvents
+}
+
+func (h *fakeWebHost) NewWebView(events WebViewEvents) (NativeWebView, error) {
+	if h.err != nil {
+		return nil, h.err
+	}
+	h.events = events
+	return h.view, nil
+}
+
+// hostedWebView returns a context with a host creating view.
+func hostedWebView(view NativeWebView) (*core.Context, *fakeWebHost) {
+	ctx := core.NewContext()
+	h := &fakeWebHost{view: view}
+	SetWebViewHost(ctx, h)
+	return ctx, h
+}
+
+// paintFrame paints v as a window frame does, and runs the functions
+// waiting for the end of the frame.
+func paintFrame(ctx *core.Context, v *WebView) *textCanvas {
+	cv := &textCanvas{}
+	v.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+	ctx.RunAfterFrame()
+	return cv
+}
+
+func TestWebViewPlaceholder(t *testing.T) {
+	failed := errors.New("no engine")
+	tests := []struct {
+		name string
+		ctx  *core.Context
+		err  error
+	}{
+		{"no host", core.NewContext(), ErrNoWebViewHost},
+		{"host fails", func() *core.Context {
+			ctx := core.NewContext()
+			SetWebViewHost(ctx, &fakeWebHost{err: failed})
+			return ctx
+		}(), failed},
+	}
+	for _, tt := range tests {
+		v := NewWebView("https://example.com")
+		tt.ctx.LayoutRoot(v, core.R(0, 0, 300, 200))
+		if v.Err() != tt.err || v.URL() != "https://example.com" {
+			t.Errorf("%s: Err() = %v, URL() = %q", tt.name, v.Err(), v.URL())
+		}
+		if cv := paintFrame(tt.ctx, v); !equalStrings(cv.texts, []string{"Web content unavailable"}) {
+			t.Errorf("%s: drew %q, want the placeholder", tt.name, cv.texts)
+		}
+		v.Navigate("https://example.org")
+		v.Eval("1")
+		if len(v.pending) != 0 || v.URL() != "https://example.org" {
+			t.Errorf("%s: %d calls kept for a view that cannot exist", tt.name, len(v.pending))
+		}
+	}
+
+	lc := &core.LayoutContext{Context: core.NewContext()}
+	if got := lc.Measure(NewWebView(""), core.Unbounded()); got != core.Sz(640, 480) {
+		t.Errorf("default size %v", got)
+	}
+	if got := lc.Measure(NewWebView("").Size(100, 50), core.Unbounded()); got != core.Sz(100, 50) {
+		t.Errorf("size %v, want 100 by 50", got)
+	}
+}
+
+func TestWebViewCalls(t *testing.T) {
+	view := &fakeWebView{}
+	ctx, _ := hostedWebView(view)
+	v := NewWebView("a")
+	v.LoadHTML("<p>")
+	v.Eval("x()")
+	v.PostMessage("hi")
+	if len(view.calls) != 0 {
+		t.Fatalf("calls %q before the view exists", view.calls)
+	}
+	ctx.LayoutRoot(v, core.R(0, 0, 300, 200))
+	if got, want := view.take(), []string{"navigate a", "html <p>", "eval x()", "post hi"}; !equalStrings(got, want) {
+		t.Errorf("on creation the view got %q, want %q", got, want)
+	}
+	v.Navigate("b")
+	if got := view.take(); !equalStrings(got, []string{"navigate b"}) || v.URL() != "b" {
+		t.Errorf("navigating called %q", got)
+	}
+	ctx.LayoutRoot(v, core.R(0, 0, 300, 200))
+	if len(view.calls) != 0 {
+		t.Errorf("laying out again called %q", view.calls)
+	}
+
+	v.Close()
+	v.Eval("y()")
+	if got := view.take(); !equalStrings(got, []string{"close"}) || v.Err() != nil {
+		t.Errorf("after Close the view got %q", got)
+	}
+	if cv := paintFrame(ctx, v); len(cv.texts) != 1 {
+		t.Error("a closed view does not show the placeholder")
+	}
+}
+
+func TestWebViewEvents(t *testing.T) {
+	view := &fakeWebView{}
+	ctx, host := hostedWebView(view)
+	var got []string
+	v := NewWebView("").
+		OnMessage(func(_ *core.Context, text string) { got = append(got, "message "+text) }).
+		OnNavigate(func(_ *core.Context, url string) { got = append(got, "navigated "+url) }).
+		OnTitle(func(_ *core.Context, title string) { got = append(got, "title "+title) })
+	ctx.LayoutRoot(v, core.R(0, 0, 300, 200))
+
+	go func() {
+		host.events.Message("ping")
+		host.events.Navigated("https://example.com/x")
+		host.events.TitleChanged("X")
+		host.events.Repaint()
+	}()
+	for len(got) < 3 {
+		runPosted(t, ctx)
+	}
+	if want := []string{"message ping", "navigated https://example.com/x", "title X"}; !equalStrings(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if v.URL() != "https://example.com/x" || v.Title() != "X" {
+		t.Errorf("URL %q, title %q", v.URL(), v.Title())
+	}
+
+	// Events arriving after Close are dropped.
+	host.events.Message("late")
+	v.Close()
+	ctx.RunPosted()
+	if len(got) != 3 {
+		t.Errorf("got %q after Close", got[3:])
+	}
+}
+
+func TestWebViewVisibility(t *testing.T) {
+	view := &fakeWebView{}
+	ctx, _ := hostedWebView(view)
+	v := NewWebView("")
+	ctx.LayoutRoot(v, core.R(10, 20, 300, 200))
+	paintFrame(ctx, v)
+	if got := view.take(); !equalStrings(got, []string{"show 10 20 300 200"}) {
+		t.Fatalf("the first paint called %q", got)
+	}
+	paintFrame(ctx, v)
+	if len(view.calls) != 0 {
+		t.Errorf("painting in place called %q", view.calls)
+	}
+	ctx.LayoutRoot(v, core.R(0, 0, 100, 100))
+	paintFrame(ctx, v)
+	if got := view.take(); !equalStrings(got, []string{"show 0 0 100 100"}) {
+		t.Errorf("moving called %q", got)
+	}
+
+	// A frame that does not paint the widget hides the view.
+	ctx.RunAfterFrame()
+	if got := view.take(); !equalStrings(got, []string{"hide"}) {
+		t.Errorf("a frame without the widget called %q", got)
+	}
+	ctx.RunAfterFrame()
+	if len(view.calls) != 0 {
+		t.Errorf("the hidden view got %q", view.calls)
+	}
+	paintFrame(ctx, v)
+	if got := view.take(); !equalStrings(got, []string{"show 0 0 100 100"}) {
+		t.Errorf("painting again called %q", got)
+	}
+}
+
+func TestWebViewComposited(t *testing.T) {
+	view := &compositedView{}
+	ctx, _ := hostedWebView(view)
+	v := NewWebView("")
+	ctx.LayoutRoot(v, core.R(10, 20, 300, 200))
+	if cv := paintFrame(ctx, v); cv.Len() != 0 {
+		t.Errorf("drew %d operations before the first frame of the page", cv.Len())
+	}
+	view.frame = image.NewRGBA(image.Rect(0, 0, 300, 200))
+	cv := &opCanvas{}
+	v.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+	if !equalStrings(cv.ops, []string{"image 10 20 300 200"}) {
+		t.Errorf("drew %q, want the page's frame", cv.ops)
+	}
+
+	key := event.KeyEvent{Type: event.KeyPress, Key: event.KeyA}
+	if r := v.HandleEvent(ctx, key); r != core.Ignored || len(view.got) != 0 {
+		t.Error("an unfocused view got a key")
+	}
+	view.consume = true
+	if r := v.HandleEvent(ctx, event.MouseEvent{Type: event.MouseDown, Position: core.Pt(15, 30)}); r != core.Handled || !ctx.IsFocused(v) {
+		t.Errorf("a press = %v, focused %v", r, ctx.IsFocused(v))
+	}
+	if got := view.got[0].(event.MouseEvent).Position; got != core.Pt(5, 10) {
+		t.Errorf("the page got the press at %v, want it local", got)
+	}
+	v.HandleEvent(ctx, event.ScrollEvent{Position: core.Pt(20, 20), Delta: core.Pt(0, 1)})
+	if got := view.got[1].(event.ScrollEvent).Position; got != core.Pt(10, 0) {
+		t.Errorf("the page got the scroll at %v, want it local", got)
+	}
+	view.consume = false
+	if r := v.HandleEvent(ctx, key); r != core.Ignored || view.got[2] != key {
+		t.Errorf("a key the page did not consume = %v", r)
+	}
+
+	// A native view gets its input from the platform.
+	native := NewWebView("")
+	ctx, _ = hostedWebView(&fakeWebView{})
+	ctx.LayoutRoot(native, core.R(0, 0, 10, 10))
+	if r := native.HandleEvent(ctx, event.MouseEvent{Type: event.MouseDown}); r != core.Ignored {
+		t.Errorf("a native view handled a press: %v", r)
+	}
+}
@@ -0,0 +1,482 @@
+package widgets
+
+import (
+	"strconv"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/theme"
+)
+
+const (
+	wizardHeaderHeight float32 = 48
+	wizardStepCircle   float32 = 24
+	wizardLabelGap     float32 = 8
+	wizardMinConnector float32 = 24
+)
+
+// WizardStep is a page of a Wizard.
+type WizardStep struct {
+	// Title names the step in the progress header.
+	Title string
+
+	// Content is the widget shown while the step is current.
+	Content core.Widget
+
+	// Validate, if set, gates moving past the step: an error keeps the
+	// wizard on the step and is shown next to the buttons.
+	Validate func() error
+}
+
+// NewWizardStep returns a step showing content.
+func NewWizardStep(title string, content core.Widget) *WizardStep {
+	return &WizardStep{Title: title, Content: content}
+}
+
+// Validator sets the function gating moving past the step.
+func (s *WizardStep) Validator(fn func() error) *WizardStep {
+	s.Validate = fn
+	return s
+}
+
+// WizardHeader selects how a Wizard shows its progress.
+type WizardHeader uint8
+
+// Wizard header styles.
+const (
+	// WizardHeaderAuto uses numbered steps under the Material design and
+	// a breadcrumb under the others.
+	WizardHeaderAuto WizardHeader = iota
+	// WizardHeaderNumbered shows a Material stepper: numbered circles,
+	// checked once completed, joined by lines.
+	WizardHeaderNumbered
+	// WizardHeaderBreadcrumb shows a Fluent breadcrumb of step titles.
+	WizardHeaderBreadcrumb
+)
+
+// Wizard walks the user through an ordered list of steps, showing one at
+// a time below a progress header, with Back and Next buttons below it.
+//
+// Next runs the step's validator and stays on the step if it fails; on the
+// last step the button reads Finish and calls OnFinish. Steps already
+// reached can be revisited by clicking them in the header; jumping
+// forward again validates the current step first. A Cancel button is
+// shown when OnCancel is set.
+type Wizard struct {
+	core.WidgetBase
+
+	steps    []*WizardStep
+	current  int
+	reached  int
+	header   WizardHeader
+	err      error
+	onStep   func(index int)
+	onFinish func()
+	onCancel func()
+
+	back, next, cancel *pushButton
+
+	headerRect  core.Rect
+	contentRect core.Rect
+	footerRect  core.Rect
+	labelWidths []float32
+	stepRects   []core.Rect
+	showLabels  bool
+	numbered    bool
+	pad         float32
+	hover       int
+}
+
+// NewWizard returns a wizard with the given steps, starting at the first.
+func NewWizard(steps ...*WizardStep) *Wizard {
+	w := &Wizard{steps: steps, hover: -1}
+	w.back = newPushButton("Back", false, func(ctx *core.Context) {
+		w.Back()
+		ctx.MarkNeedsLayout(w)
+	})
+	w.next = newPushButton("Next", true, func(ctx *core.Context) {
+		w.Next()
+		ctx.MarkNeedsLayout(w)
+	})
+	w.cancel = newPushButton("Cancel", false, func(ctx *core.Context) {
+		if w.onCancel != nil {
+			w.onCancel()
+		}
+	})
+	return w
+}
+
+// Header sets how progress is shown. The default is WizardHeaderAuto.
+func (w *Wizard) Header(h WizardHeader) *Wizard {
+	w.header = h
+	return w
+}
+
+// OnStep sets the function called when another step becomes current.
+func (w *Wizard) OnStep(fn func(index int)) *Wizard {
+	w.onStep = fn
+	return w
+}
+
+// OnFinish sets the function called when Finish is pressed on the last
+// step and it validates.
+func (w *Wizard) OnFinish(fn func()) *Wizard {
+	w.onFinish = fn
+	return w
+}
+
+// OnCancel shows a Cancel button calling fn.
+func (w *Wizard) OnCancel(fn func()) *Wizard {
+	w.onCancel = fn
+	return w
+}
+
+// Steps returns the steps.
+func (w *Wizard) Steps() []*WizardStep {
+	return w.steps
+}
+
+// Current returns the index of the current step.
+func (w *Wizard) Current() int {
+	return w.current
+}
+
+// Err returns the error of the last failed validation of the current
+// step, or nil.
+func (w *Wizard) Err() error {
+	return w.err
+}
+
+// validate runs the current step's validator.
+func (w *Wizard) validate() bool {
+	w.err = nil
+	if s := w.steps[w.current]; s.Validate != nil {
+		w.err = s.Validate()
+	}
+	return w.err == nil
+}
+
+func (w *Wizard) setCurrent(i int) {
+	w.current = i
+	w.reached = max(w.reached, i)
+	if w.onStep != nil {
+		w.onStep(i)
+	}
+}
+
+// Next validates the current step and moves to the following one, or
+// finishes on the last step. It reports whether the step validated.
+func (w *Wizard) Next() bool {
+	if len(w.steps) == 0 || !w.validate() {
+		return false
+	}
+	if w.current == len(w.steps)-1 {
+		if w.onFinish != nil {
+			w.onFinish()
+		}
+		return true
+	}
+	w.setCurrent(w.current + 1)
+	return true
+}
+
+// Back moves to the previous step without validating.
+func (w *Wizard) Back() {
+	if w.current > 0 {
+		w.err = nil
+		w.setCurrent(w.current - 1)
+	}
+}
+
+// GoTo makes step i current if it has been reached before, validating
+// the current step when moving forward, and reports whether it moved.
+func (w *Wizard) GoTo(i int) bool {
+	if i < 0 || i > w.reached || i == w.current {
+		return false
+	}
+	if i > w.current && !w.validate() {
+		return false
+	}
+	w.err = nil
+	w.setCurrent(i)
+	return true
+}
+
+func (w *Wizard) isNumbered(th *theme.Theme) bool {
+	switch w.header {
+	case WizardHeaderNumbered:
+		return true
+	case WizardHeaderBreadcrumb:
+		return false
+	}
+	return th.Design == theme.DesignMaterial
+}
+
+// Layout implements core.Widget.
+func (w *Wizard) Layout(ctx *core.LayoutContext) core.Size {
+	th := theme.From(ctx.Context)
+	pad := th.Spacing.L
+	w.pad = pad
+	w.numbered = w.isNumbered(th)
+	style := th.Typography.Label
+	w.labelWidths = w.labelWidths[:0]
+	for _, s := range w.steps {
+		w.labelWidths = append(w.labelWidths, ctx.MeasureText(s.Title, style).Width)
+	}
+
+	last := w.current == len(w.steps)-1
+	w.next.label = "Next"
+	if last {
+		w.next.label = "Finish"
+	}
+	w.back.disabled = w.current == 0
+	w.next.disabled = len(w.steps) == 0
+	buttons := core.Loose(core.Sz(core.Infinity, pushButtonHeight))
+	for _, b := range []*pushButton{w.back, w.next, w.cancel} {
+		b.size = ctx.Measure(b, buttons)
+	}
+	var children []core.Widget
+
+	c := ctx.Constraints
+	footerH := pushButtonHeight + 2*pad
+	chrome := core.Insets{Top: wizardHeaderHeight + pad, Bottom: footerH, Left: pad, Right: pad}
+	var content core.Size
+	if len(w.steps) > 0 {
+		if cw := w.steps[w.current].Content; cw != nil {
+			content = ctx.Measure(cw, c.Loosen().Deflate(chrome))
+			children = append(children, cw)
+		}
+	}
+	if w.onCancel != nil {
+		children = append(children, w.cancel)
+	}
+	children = append(children, w.back, w.next)
+	w.SetChildren(children...)
+
+	size := core.Sz(content.Width+chrome.Horizontal(), content.Height+chrome.Vertical())
+	if c.HasBoundedWidth() {
+		size.Width = c.MaxWidth
+	}
+	return c.Constrain(size)
+}
+
+// SetBounds implements core.Widget.
+func (w *Wizard) SetBounds(r core.Rect) {
+	w.WidgetBase.SetBounds(r)
+	pad := w.pad
+	footerH := pushButtonHeight + 2*pad
+	w.headerRect = core.R(r.X, r.Y, r.Width, wizardHeaderHeight)
+	w.footerRect = core.R(r.X, r.Bottom()-footerH, r.Width, footerH)
+	w.contentRect = core.R(r.X+pad, w.headerRect.Bottom()+pad, max(0, r.Width-2*pad), max(0, w.footerRect.Y-w.headerRect.Bottom()-pad))
+	if len(w.steps) > 0 {
+		if cw := w.steps[w.current].Content; cw != nil {
+			cw.SetBounds(w.contentRect)
+		}
+	}
+
+	y := w.footerRect.Y + pad
+	x := w.footerRect.Right() - pad
+	place := func(b *pushButton) {
+		x -= b.size.Width
+		b.SetBounds(core.R(x, y, b.size.Width, pushButtonHeight))
+		x -= pad / 2
+	}
+	place(w.next)
+	place(w.back)
+	if w.onCancel != nil {
+		x -= pad
+		place(w.cancel)
+	}
+
+	w.arrangeHeader()
+}
+
+// buttonsX returns the left edge of the leftmost button.
+func (w *Wizard) buttonsX() float32 {
+	if w.onCancel != nil {
+		return w.cancel.Bounds().X
+	}
+	return w.back.Bounds().X
+}
+
+func (w *Wizard) arrangeHeader() {
+	h := w.headerRect.Inset(core.Insets{Left: w.pad, Right: w.pad})
+	n := len(w.steps)
+	w.stepRects = w.stepRects[:0]
+	if n == 0 {
+		return
+	}
+	if !w.numbered {
+		x := h.X
+		for i, lw := range w.labelWidths {
+			if i > 0 {
+				x += breadcrumbSeparator
+			}
+			w.stepRects = append(w.stepRects, core.R(x, h.Y, lw+2*breadcrumbPadding, h.Height))
+			x += lw + 2*breadcrumbPadding
+		}
+		return
+	}
+	var total float32
+	for _, lw := range w.labelWidths {
+		total += wizardStepCircle + wizardLabelGap + lw
+	}
+	w.showLabels = total+float32(n-1)*wizardMinConnector <= h.Width
+	widths := make([]float32, n)
+	total = 0
+	for i, lw := range w.labelWidths {
+		widths[i] = wizardStepCircle
+		if w.showLabels || i == w.current {
+			widths[i] += wizardLabelGap + lw
+		}
+		total += widths[i]
+	}
+	gap := wizardMinConnector
+	if n > 1 {
+		gap = max(wizardMinConnector, (h.Width-total)/float32(n-1))
+	}
+	x := h.X
+	for _, iw := range widths {
+		w.stepRects = append(w.stepRects, core.R(x, h.Y, iw, h.Height))
+		x += iw + gap
+	}
+}
+
+// Paint implements core.Widget.
+func (w *Wizard) Paint(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	line := core.Filled(th.Colors.Outline.WithAlpha(0.4))
+	cv.DrawRect(core.R(w.headerRect.X, w.headerRect.Bottom()-1, w.headerRect.Width, 1), line)
+	cv.DrawRect(core.R(w.footerRect.X, w.footerRect.Y, w.footerRect.Width, 1), line)
+	if w.numbered {
+		w.paintNumbered(ctx)
+	} else {
+		w.paintBreadcrumb(ctx)
+	}
+	if w.err != nil {
+		style := theme.TextStyle(th.Typography.Body, th.Colors.Error)
+		msg := w.err.Error()
+		f := w.footerRect
+		cv.Save()
+		cv.Clip(core.R(f.X, f.Y, max(0, w.buttonsX()-f.X-w.pad), f.Height))
+		cv.DrawText(msg, core.Pt(f.X+w.pad, f.Y+(f.Height-style.LineHeight())/2), style)
+		cv.Restore()
+	}
+	core.PaintChildren(ctx, w.Children())
+}
+
+func (w *Wizard) paintNumbered(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	cy := w.headerRect.Y + w.headerRect.Height/2
+	label := th.Typography.Label
+	for i, s := range w.steps {
+		r := w.stepRects[i]
+		if i > 0 {
+			prev := w.stepRects[i-1]
+			x0, x1 := prev.Right()+wizardLabelGap, r.X-wizardLabelGap
+			if x1 > x0 {
+				cv.DrawRect(core.R(x0, cy, x1-x0, 1), core.Filled(th.Colors.Outline))
+			}
+		}
+		circle := core.R(r.X, cy-wizardStepCircle/2, wizardStepCircle, wizardStepCircle)
+		done := i != w.current && i <= w.reached
+		textColor := th.Colors.OnSurfaceVariant
+		switch {
+		case i == w.current || done:
+			cv.DrawRoundedRect(circle, wizardStepCircle/2, core.Filled(th.Colors.Primary))
+			textColor = th.Colors.OnSurface
+		default:
+			cv.DrawRoundedRect(circle, wizardStepCircle/2, core.Stroked(th.Colors.Outline, 1))
+		}
+		c := circle.Center()
+		if done {
+			check := core.NewPath().MoveTo(core.Pt(c.X-5, c.Y)).LineTo(core.Pt(c.X-1.5, c.Y+3.5)).LineTo(core.Pt(c.X+5, c.Y-4))
+			cv.DrawPath(check, core.PathStyle{Stroke: th.Colors.OnPrimary, StrokeWidth: 2, LineCap: core.CapRound, LineJoin: core.JoinRound})
+		} else {
+			num := strconv.Itoa(i + 1)
+			color := th.Colors.OnSurfaceVariant
+			if i == w.current {
+				color = th.Colors.OnPrimary
+			}
+			ns := theme.TextStyle(th.Typography.Caption, color)
+			sz := ctx.MeasureText(num, ns)
+			cv.DrawText(num, core.Pt(c.X-sz.Width/2, c.Y-ns.LineHeight()/2), ns)
+		}
+		if w.showLabels || i == w.current {
+			st := theme.TextStyle(label, textColor)
+			if i == w.hover {
+				st.Color = th.Colors.Primary
+			}
+			cv.DrawText(s.Title, core.Pt(circle.Right()+wizardLabelGap, cy-st.LineHeight()/2), st)
+		}
+	}
+}
+
+func (w *Wizard) paintBreadcrumb(ctx *core.PaintContext) {
+	th := theme.From(ctx.Context)
+	cv := ctx.Canvas
+	cv.Save()
+	cv.Clip(w.headerRect)
+	for i, s := range w.steps {
+		r := w.stepRects[i]
+		if i > 0 {
+			paintDisclosure(ctx, core.R(r.X-breadcrumbSeparator, r.Y, breadcrumbSeparator, r.Height), false, th.Colors.OnSurfaceVariant)
+		}
+		color := th.Colors.OnSurfaceVariant
+		style := th.Typography.Label
+		switch {
+		case i == w.current:
+			color = th.Colors.OnSurface
+			style = th.Typography.Title
+			style.Size = th.Typography.Label.Size
+			style.Weight = core.WeightBold
+		case i == w.hover:
+			color = th.Colors.Primary
+		case i <= w.reached:
+			color = th.Colors.OnSurface
+		}
+		st := theme.TextStyle(style, color)
+		cv.DrawText(s.Title, core.Pt(r.X+breadcrumbPadding, r.Y+(r.Height-st.LineHeight())/2), st)
+	}
+	cv.Restore()
+}
+
+// stepAt returns the header step under p that can be clicked, or -1.
+func (w *Wizard) stepAt(p core.Point) int {
+	for i, r := range w.stepRects {
+		if r.Contains(p) && i != w.current && i <= w.reached {
+			return i
+		}
+	}
+	return -1
+}
+
+// HandleEvent implements core.Widget.
+func (w *Wizard) HandleEvent(ctx *core.Context, ev core.Event) core.EventResult {
+	e, ok := ev.(event.MouseEvent)
+	if !ok {
+		return core.Ignored
+	}
+	switch e.Type {
+	case event.MouseEnter, event.MouseMove:
+		if i := w.stepAt(e.Position); i != w.hover {
+			w.hover = i
+			ctx.Repaint(w)
+		}
+	case event.MouseLeave:
+		if w.hover >= 0 {
+			w.hover = -1
+			ctx.Repaint(w)
+		}
+	case event.MouseDown:
+		if i := w.stepAt(e.Position); i >= 0 && e.Button == event.ButtonLeft {
+			w.GoTo(i)
+			w.hover = -1
+			ctx.MarkNeedsLayout(w)
+			return core.Handled
+		}
+	}
+	return core.Ignored
+}
@@ -0,0 +1,218 @@
+package widgets
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/theme"
+)
+
+// newWizard returns a wizard of three steps whose second fails to validate
+// while *invalid is set, and the steps it went to.
+func newWizard() (*Wizard, *bool, *[]int) {
+	invalid := new(bool)
+	var went []int
+	w := NewWizard(
+		NewWizardStep("Account", &fixed{height: 100}),
+		NewWizardStep("Profile", &fixed{height: 200}).Validator(func() error {
+			if *invalid {
+				return errors.New("name required")
+			}
+			return nil
+		}),
+		NewWizardStep("Done", nil),
+	).OnStep(func(i int) { went = append(went, i) })
+	return w, invalid, &went
+}
+
+func TestWizardSteps(t *testing.T) {
+	w, invalid, went := newWizard()
+	var finished int
+	w.OnFinish(func() { finished++ })
+	if w.Current() != 0 || len(w.Steps()) != 3 {
+		t.Fatalf("starts at %d of %d", w.Current(), len(w.Steps()))
+	}
+	w.Back()
+	if w.GoTo(1) || w.Current() != 0 {
+		t.Error("went to a step not reached yet")
+	}
+	if !w.Next() || w.Current() != 1 {
+		t.Fatal("Next did not move on")
+	}
+	*invalid = true
+	if w.Next() || w.Current() != 1 || w.Err() == nil || w.Err().Error() != "name required" {
+		t.Errorf("Next past an invalid step moved to %d with %v", w.Current(), w.Err())
+	}
+	w.Back()
+	if w.Current() != 0 || w.Err() != nil {
+		t.Errorf("Back moved to %d, kept %v", w.Current(), w.Err())
+	}
+	// Jumping forward validates the step left; jumping back does not.
+	if !w.GoTo(1) {
+		t.Error("a reached step could not be revisited")
+	}
+	*invalid = false
+	w.Next()
+	*invalid = true
+	if !w.GoTo(0) || w.GoTo(0) {
+		t.Error("GoTo back failed, or went to the current step")
+	}
+	if !w.GoTo(2) || w.Current() != 2 {
+		t.Error("GoTo the last step reached failed from a step without a validator")
+	}
+	if !w.GoTo(1) || w.GoTo(2) || w.Current() != 1 {
+		t.Errorf("jumping forward past an invalid step moved to %d", w.Current())
+	}
+	*invalid = false
+	w.Next()
+	if !w.Next() || finished != 1 || w.Current() != 2 {
+		t.Errorf("Next on the last step finished %d times", finished)
+	}
+	if want := []int{1, 0, 1, 2, 0, 2, 1, 2}; !slices.Equal(*went, want) {
+		t.Errorf("went to %v, want %v", *went, want)
+	}
+
+	if NewWizard().Next() {
+		t.Error("Next on an empty wizard succeeded")
+	}
+}
+
+func TestWizardLayout(t *testing.T) {
+	w, _, _ := newWizard()
+	lc := &core.LayoutContext{Context: core.NewContext()}
+	pad := theme.From(lc.Context).Spacing.L
+	if got := lc.Measure(w, core.Unbounded()); got.Height != wizardHeaderHeight+pad+100+pushButtonHeight+2*pad {
+		t.Errorf("size %v, want room for the header, content and buttons", got)
+	}
+
+	ctx := layoutAt(w, core.R(0, 0, 600, 400))
+	content := w.Steps()[0].Content
+	if got, want := content.Bounds(), core.R(pad, wizardHeaderHeight+pad, 600-2*pad, 400-wizardHeaderHeight-3*pad-pushButtonHeight); got != want {
+		t.Errorf("the content at %v, want %v", got, want)
+	}
+	if n, b := w.next.Bounds(), w.back.Bounds(); n.Right() != 600-pad || b.Right() != n.X-pad/2 || n.Bottom() != 400-pad {
+		t.Errorf("Next at %v, Back at %v", n, b)
+	}
+	if !w.back.disabled || w.next.label != "Next" || len(w.Children()) != 3 {
+		t.Errorf("on the first step Back disabled %v, Next reads %q, %d children", w.back.disabled, w.next.label, len(w.Children()))
+	}
+
+	var cancelled bool
+	w.OnCancel(func() { cancelled = true })
+	w.Next()
+	w.Next()
+	ctx.LayoutRoot(w, core.R(0, 0, 600, 400))
+	if w.back.disabled || w.next.label != "Finish" || len(w.Children()) != 3 {
+		t.Errorf("on the last step Back disabled %v, Next reads %q, %d children", w.back.disabled, w.next.label, len(w.Children()))
+	}
+	if c := w.cancel.Bounds(); c.Right() != w.back.Bounds().X-pad-pad/2 {
+		t.Errorf("Cancel at %v", c)
+	}
+	w.cancel.onClick(ctx)
+	if !cancelled {
+		t.Error("Cancel did not call OnCancel")
+	}
+
+	// The buttons move between the steps.
+	w.back.onClick(ctx)
+	ctx.LayoutRoot(w, core.R(0, 0, 600, 400))
+	if w.Current() != 1 || w.Steps()[1].Content.Bounds().Width == 0 {
+		t.Errorf("Back moved to %d", w.Current())
+	}
+	w.next.onClick(ctx)
+	if w.Current() != 2 {
+		t.Errorf("Next moved to %d", w.Current())
+	}
+}
+
+func TestWizardHeader(t *testing.T) {
+	tests := []struct {
+		name     string
+		design   theme.Design
+		header   WizardHeader
+		numbered bool
+	}{
+		{"material", theme.DesignMaterial, WizardHeaderAuto, true},
+		{"fluent", theme.DesignFluent, WizardHeaderAuto, false},
+		{"forced numbers", theme.DesignFluent, WizardHeaderNumbered, true},
+		{"forced breadcrumb", theme.DesignMaterial, WizardHeaderBreadcrumb, false},
+	}
+	for _, tt := range tests {
+		w, _, _ := newWizard()
+		w.Header(tt.header)
+		ctx := core.NewContext()
+		th := theme.Light()
+		th.Design = tt.design
+		theme.Set(ctx, th)
+		ctx.LayoutRoot(w, core.R(0, 0, 600, 400))
+		if w.numbered != tt.numbered || len(w.stepRects) != 3 {
+			t.Errorf("%s: numbered %v with %d steps", tt.name, w.numbered, len(w.stepRects))
+		}
+		cv := &textCanvas{}
+		w.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+		want := []string{"1", "Account", "2", "Profile", "3", "Done", "Back", "Next"}
+		if !tt.numbered {
+			want = []string{"Account", "Profile", "Done", "Back", "Next"}
+		}
+		if !equalStrings(cv.texts, want) {
+			t.Errorf("%s: drew %q, want %q", tt.name, cv.texts, want)
+		}
+	}
+
+	// A narrow stepper shows only the current step's title.
+	w, _, _ := newWizard()
+	w.Header(WizardHeaderNumbered)
+	ctx := layoutAt(w, core.R(0, 0, 150, 400))
+	w.Next()
+	ctx.LayoutRoot(w, core.R(0, 0, 150, 400))
+	if w.showLabels || w.stepRects[0].Width != wizardStepCircle || w.stepRects[1].Width == wizardStepCircle {
+		t.Errorf("labels shown %v, steps %v", w.showLabels, w.stepRects)
+	}
+	cv := &textCanvas{}
+	w.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+	if want := []string{"2", "Profile", "3", "Back", "Next"}; !equalStrings(cv.texts, want) {
+		t.Errorf("drew %q, want the first step checked and only the current title %q", cv.texts, want)
+	}
+}
+
+func TestWizardInput(t *testing.T) {
+	w, invalid, _ := newWizard()
+	ctx := layoutAt(w, core.R(0, 0, 600, 400))
+	w.Next()
+	w.Next()
+	ctx.LayoutRoot(w, core.R(0, 0, 600, 400))
+	step := func(i int) core.Point { return w.stepRects[i].Center() }
+
+	w.HandleEvent(ctx, event.MouseEvent{Type: event.MouseMove, Position: step(2)})
+	if w.hover != -1 {
+		t.Error("the current step is hovered")
+	}
+	w.HandleEvent(ctx, event.MouseEvent{Type: event.MouseMove, Position: step(0)})
+	if w.hover != 0 {
+		t.Errorf("hovering a reached step set %d", w.hover)
+	}
+	w.HandleEvent(ctx, event.MouseEvent{Type: event.MouseLeave})
+	if w.hover != -1 {
+		t.Error("the hover stayed after the pointer left")
+	}
+	if r := w.HandleEvent(ctx, leftMouse(event.MouseDown, step(1))); r != core.Handled || w.Current() != 1 {
+		t.Errorf("a click on the second step = %v, at %d", r, w.Current())
+	}
+	*invalid = true
+	ctx.LayoutRoot(w, core.R(0, 0, 600, 400))
+	w.HandleEvent(ctx, leftMouse(event.MouseDown, step(2)))
+	if w.Current() != 1 || w.Err() == nil {
+		t.Errorf("a click past an invalid step moved to %d", w.Current())
+	}
+	cv := &textCanvas{}
+	w.Paint(&core.PaintContext{Context: ctx, Canvas: cv})
+	if !slices.Contains(cv.texts, "name required") {
+		t.Errorf("drew %q without the error", cv.texts)
+	}
+	if r := w.HandleEvent(ctx, press(event.KeyEnter, 0)); r != core.Ignored {
+		t.Error("the wizard handled a key")
+	}
+}
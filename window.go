@@ -0,0 +1,917 @@
+package ui
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/gogpu/ui/anim"
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/dnd"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/font"
+	"github.com/gogpu/ui/gesture"
+	"github.com/gogpu/ui/render/raster"
+	"github.com/gogpu/ui/shortcuts"
+	"github.com/gogpu/ui/theme"
+	"github.com/gogpu/ui/widgets"
+)
+
+// Window drives a widget tree: it routes input events, runs the layout and
+// arrange passes and paints into a canvas supplied by the platform layer.
+//
+// Window does not own a native window. A platform integration (such as the
+// gogpu windowing backend) feeds it input with HandleEvent, resizes it with
+// Resize and calls Frame whenever it needs a new image.
+type Window struct {
+	ctx    *core.Context
+	root   core.Widget
+	size   core.Size
+	hover  []core.Widget
+	touch  map[int]core.Widget // The widget each touch went down on.
+	host   PopupHost
+	popups map[*core.Overlay]core.Rect
+	dir    core.Direction
+
+	drop    core.Widget   // The target of a drag from another application.
+	refused []core.Widget // The widgets that refused it.
+
+	im     InputMethod
+	imRect core.Rect
+	imOn   bool
+
+	partial bool
+	full    bool        // Set to paint the whole window in the next frame.
+	damage  []core.Rect // The regions painted by the last frame.
+	moved   []core.Rect // Where overlays moved from and to, for the next frame.
+
+	capture core.LayerCanvas
+
+	aa       core.Antialiasing
+	backend  Backend
+	cpu      *raster.Canvas // Of RenderFrame.
+	cpuScale float32
+	fonts    *font.Manager
+	measured bool // Set by WithTextMeasurer.
+
+	mode      RenderMode
+	fps       float64
+	vsync     VSync
+	lastFrame time.Time
+
+	seq        uint64
+	frames     [statsHistory]FrameStats // By frame number, for ReportRender.
+	stats      FrameStats
+	onStats    func(FrameStats)
+	reportFrom uint64 // The first frame delivered by ReportRender, once called.
+
+	app   *App
+	owner *Window
+}
+
+// PopupHost is implemented by platform integrations that can show overlays
+// in separate native surfaces, letting menus extend past the window's
+// client area.
+//
+// The integration paints such a surface with Window.PaintOverlay and
+// forwards its input to Window.HandleEvent in window coordinates, so an
+// overlay behaves the same whether or not it has its own surface.
+type PopupHost interface {
+	// WorkArea returns the usable screen area in window coordinates.
+	WorkArea() core.Rect
+
+	// ShowPopup shows, or moves, the surface for o so that it covers r,
+	// given in window coordinates.
+	ShowPopup(o *core.Overlay, r core.Rect)
+
+	// HidePopup removes the surface for o.
+	HidePopup(o *core.Overlay)
+}
+
+// InputMethod is implemented by platform integrations with an input
+// method, for typing text composed of several keystrokes, such as
+// Chinese, Japanese and Korean. The integration delivers the composition
+// as event.CompositionEvent and the committed text as event.TextEvent.
+type InputMethod interface {
+	// SetTextInput turns the input method on while a core.TextInput has
+	// focus, with its caret at r in window coordinates, where the
+	// candidate window belongs, and off when none has. It is called
+	// after a frame when either changes.
+	SetTextInput(r core.Rect, on bool)
+}
+
+// Option configures a Window.
+type Option func(*Window)
+
+// WithTheme installs t as the window's theme.
+func WithTheme(t *theme.Theme) Option {
+	return func(w *Window) {
+		theme.Set(w.ctx, t)
+	}
+}
+
+// WithScaleFactor sets the initial ratio of device to logical pixels.
+func WithScaleFactor(s float32) Option {
+	return func(w *Window) {
+		w.ctx.SetScaleFactor(s)
+	}
+}
+
+// WithReducedMotion sets the initial motion preference; see
+// core.Context.SetReducedMotion.
+func WithReducedMotion(reduced bool) Option {
+	return func(w *Window) {
+		w.ctx.SetReducedMotion(reduced)
+	}
+}
+
+// WithDirection sets the reading direction of the window's content; see
+// Window.SetDirection.
+func WithDirection(d core.Direction) Option {
+	return func(w *Window) {
+		w.dir = d
+	}
+}
+
+// WithLocale sets the reading direction of the window's content from a
+// locale tag such as "ar-EG"; see core.DirectionFor.
+func WithLocale(locale string) Option {
+	return WithDirection(core.DirectionFor(locale))
+}
+
+// WithTextMeasurer installs the measurer used for text layout.
+func WithTextMeasurer(m core.TextMeasurer) Option {
+	return func(w *Window) {
+		w.ctx.SetTextMeasurer(m)
+		w.measured = true
+	}
+}
+
+// WithPartialRedraw makes each frame paint only the regions of the window
+// that changed, for platform integrations whose surface keeps its content
+// between frames. The integration presents just those regions, from
+// Window.Damage, and calls Window.RepaintAll whenever the surface loses
+// its content. Without it every frame paints the whole window.
+func WithPartialRedraw() Option {
+	return func(w *Window) {
+		w.partial = true
+	}
+}
+
+// WithAntialiasing sets how the window's canvas smooths edges; see
+// Window.SetAntialiasing. Given to NewApp, it applies to every window of
+// the application.
+func WithAntialiasing(a core.Antialiasing) Option {
+	return func(w *Window) {
+		w.aa = a
+	}
+}
+
+// WithPopupHost lets overlays marked Popup extend past the client area
+// using surfaces provided by h.
+func WithPopupHost(h PopupHost) Option {
+	return func(w *Window) {
+		w.host = h
+	}
+}
+
+// WithInputMethod lets text inputs be typed into with the input method
+// of im.
+func WithInputMethod(im InputMethod) Option {
+	return func(w *Window) {
+		w.im = im
+	}
+}
+
+// WithEyeDropper lets color pickers sample colors from the screen using d.
+func WithEyeDropper(d widgets.EyeDropper) Option {
+	return func(w *Window) {
+		widgets.SetEyeDropper(w.ctx, d)
+	}
+}
+
+// WithClipboard lets widgets copy to and paste from the system clipboard
+// through c.
+func WithClipboard(c widgets.Clipboard) Option {
+	return func(w *Window) {
+		widgets.SetClipboard(w.ctx, c)
+	}
+}
+
+// WithDragHost lets drags leave the window for other applications
+// through h; see dnd.Host.
+func WithDragHost(h dnd.Host) Option {
+	return func(w *Window) {
+		dnd.SetHost(w.ctx, h)
+	}
+}
+
+// WithWebViewHost lets WebView widgets embed browser views created by h.
+func WithWebViewHost(h widgets.WebViewHost) Option {
+	return func(w *Window) {
+		widgets.SetWebViewHost(w.ctx, h)
+	}
+}
+
+// WithVideoHost lets Video widgets play files and URLs with the decoders
+// of h.
+func WithVideoHost(h widgets.VideoHost) Option {
+	return func(w *Window) {
+		widgets.SetVideoHost(w.ctx, h)
+	}
+}
+
+// NewWindow returns a Window displaying root.
+func NewWindow(root core.Widget, opts ...Option) *Window {
+	w := &Window{ctx: core.NewContext(), root: root}
+	// Made before any layout, so that relayout boundaries, which keep the
+	// context values they were laid out with, find the same clock,
+	// gesture router and shortcut registry.
+	anim.ClockOf(w.ctx)
+	gesture.RouterOf(w.ctx)
+	shortcuts.Of(w.ctx)
+	w.ctx.SetPathAt(func(p core.Point) []core.Widget {
+		path, _ := w.hitTest(p)
+		return path
+	})
+	for _, opt := range opts {
+		opt(w)
+	}
+	w.ctx.Invalidate()
+	return w
+}
+
+// Context returns the window's widget context.
+func (w *Window) Context() *core.Context {
+	return w.ctx
+}
+
+// Root returns the root widget.
+func (w *Window) Root() core.Widget {
+	return w.root
+}
+
+// SetRoot replaces the root widget.
+func (w *Window) SetRoot(root core.Widget) {
+	w.root = root
+	w.hover = nil
+	w.ctx.RequestFocus(nil)
+	w.ctx.ReleasePointer()
+	w.ctx.Invalidate()
+}
+
+// Shortcuts returns the registry of the key bindings and commands of the
+// window, which falls back to that of its App.
+func (w *Window) Shortcuts() *shortcuts.Registry {
+	return shortcuts.Of(w.ctx)
+}
+
+// App returns the application that opened the window, or nil for a
+// window made with NewWindow.
+func (w *Window) App() *App {
+	return w.app
+}
+
+// Owner returns the window the window was opened above, or nil.
+func (w *Window) Owner() *Window {
+	return w.owner
+}
+
+// Active reports whether the window receives keyboard input.
+func (w *Window) Active() bool {
+	return w.ctx.WindowActive()
+}
+
+// SetActive records whether the native window receives keyboard input,
+// for platform integrations to call when it gains or loses it. Keyboard
+// focus stays on its widget while the window is inactive.
+func (w *Window) SetActive(active bool) {
+	w.ctx.SetWindowActive(active)
+	if w.app != nil {
+		w.app.activated(w, active)
+	}
+}
+
+// Size returns the window's logical size.
+func (w *Window) Size() core.Size {
+	return w.size
+}
+
+// Resize sets the window's logical size.
+func (w *Window) Resize(size core.Size) {
+	if size != w.size {
+		w.size = size
+		w.ctx.Invalidate()
+	}
+}
+
+// ScaleFactor returns the ratio of device to logical pixels.
+func (w *Window) ScaleFactor() float32 {
+	return w.ctx.ScaleFactor()
+}
+
+// SetScaleFactor sets the ratio of device to logical pixels, for platform
+// integrations to call when the window moves to a monitor of another
+// scale or the user changes it. Fractional scales, such as the 1.25 and
+// 1.5 of Windows or the fractional scales of Wayland, are laid out in
+// logical pixels like any other and painted at the device scale, with
+// edges and text baselines on device pixels. The next frame paints the
+// whole window, and must be given a canvas of the new scale.
+func (w *Window) SetScaleFactor(s float32) {
+	if s != w.ctx.ScaleFactor() {
+		w.ctx.SetScaleFactor(s)
+		w.full = true
+	}
+}
+
+// Antialiasing returns how the window's canvas smooths edges.
+func (w *Window) Antialiasing() core.Antialiasing {
+	return w.aa
+}
+
+// SetAntialiasing sets how the window's canvas smooths edges, analytic by
+// default. Each frame sets it on canvases implementing
+// core.AntialiasCanvas. Platform integrations create the surface with
+// the sample count of a.SampleCount and make it again when that changes.
+func (w *Window) SetAntialiasing(a core.Antialiasing) {
+	if a != w.aa {
+		w.aa = a
+		w.RepaintAll()
+	}
+}
+
+// DeviceSize returns the size of the window in device pixels, which the
+// surface it is painted into should have.
+func (w *Window) DeviceSize() (width, height int) {
+	s := w.ctx.ScaleFactor()
+	return int(math.Ceil(float64(w.size.Width * s))), int(math.Ceil(float64(w.size.Height * s)))
+}
+
+// Direction returns the reading direction of the window's content.
+func (w *Window) Direction() core.Direction {
+	return w.dir
+}
+
+// SetDirection sets the reading direction of the window's content, left
+// to right by default. Right to left mirrors the arrangement of rows,
+// directional icons and scrollbar placement.
+func (w *Window) SetDirection(d core.Direction) {
+	if d != w.dir {
+		w.dir = d
+		w.ctx.Invalidate()
+	}
+}
+
+// NeedsFrame reports whether something requested a redraw since the last
+// frame, or an animation is running; see NextFrame.
+func (w *Window) NeedsFrame() bool {
+	return w.ctx.NeedsRedraw() || w.ctx.HasPosted() || w.ctx.Ticking()
+}
+
+// Layout runs the layout and arrange passes at the current time.
+func (w *Window) Layout() {
+	w.layout(time.Now())
+}
+
+func (w *Window) layout(now time.Time) {
+	w.ctx.SetNow(now)
+	w.ctx.SetSizeClass(core.SizeClassFor(w.size.Width))
+	w.ctx.SetDirection(w.dir)
+	if w.root == nil {
+		return
+	}
+	w.ctx.LayoutRoot(w.root, w.clientRect())
+	w.layoutOverlays()
+}
+
+func (w *Window) clientRect() core.Rect {
+	return core.Rect{Width: w.size.Width, Height: w.size.Height}
+}
+
+func (w *Window) layoutOverlays() {
+	client := w.clientRect()
+	lc := &core.LayoutContext{Context: w.ctx}
+	open := w.ctx.Overlays()
+	for _, o := range open {
+		if o.Content == nil {
+			continue
+		}
+		area := client
+		if o.Popup && w.host != nil {
+			area = w.host.WorkArea()
+		}
+		size := lc.Measure(o.Content, core.Loose(area.Size()))
+		pos := area.Center().Sub(core.Pt(size.Width/2, size.Height/2))
+		if o.Placement != nil {
+			pos = o.Placement(size, area)
+		}
+		r := core.Rect{X: pos.X, Y: pos.Y, Width: size.Width, Height: size.Height}
+		if old := o.Content.Bounds(); old != r {
+			// Overlays follow what they are placed by, such as the
+			// pointer, without repainting themselves for it.
+			w.moved = append(w.moved, old, r)
+		}
+		o.Content.SetBounds(r)
+		if o.Popup && w.host != nil {
+			if r.Intersect(client) != r {
+				if w.popups == nil {
+					w.popups = make(map[*core.Overlay]core.Rect)
+				}
+				if old, ok := w.popups[o]; !ok || old != r {
+					w.host.ShowPopup(o, r)
+				}
+				w.popups[o] = r
+			} else if _, ok := w.popups[o]; ok {
+				w.host.HidePopup(o)
+				delete(w.popups, o)
+			}
+		}
+	}
+	for o := range w.popups {
+		if !o.IsOpen() {
+			w.host.HidePopup(o)
+			delete(w.popups, o)
+		}
+	}
+}
+
+// Frame lays out the tree and paints it into canvas. Overlays hosted in
+// their own surfaces are left to PaintOverlay. With WithPartialRedraw,
+// only the region bounding the changes is painted, over the theme's background,
+// and the rest of canvas is left as the last frame drew it.
+func (w *Window) Frame(canvas core.Canvas) {
+	w.seq++
+	start := time.Now()
+	w.ctx.SetNow(start)
+	w.ctx.RunTickers(start)
+	w.ctx.RunPosted()
+	w.ctx.ClearRedraw()
+	defer w.ctx.RunAfterFrame()
+	w.lastFrame = start
+	w.layout(w.lastFrame)
+	laid := time.Now()
+	defer w.recordFrame(start, laid)
+	w.damage = w.damage[:0]
+	if w.root == nil {
+		return
+	}
+	client := w.clientRect()
+	rects, all := w.ctx.Damage()
+	if !w.partial || all || w.full {
+		w.damage = append(w.damage, client)
+	} else {
+		w.damage = mergeDamage(w.damage, append(rects[:len(rects):len(rects)], w.moved...), client)
+	}
+	w.full, w.moved = false, w.moved[:0]
+	if len(w.damage) == 0 {
+		w.updateInputMethod()
+		return
+	}
+	if ac, ok := canvas.(core.AntialiasCanvas); ok {
+		ac.SetAntialiasing(w.aa)
+	}
+	// The tree is painted once, clipped to the region bounding the
+	// damage, rather than once for each of its regions.
+	area := w.damage[0]
+	for _, r := range w.damage[1:] {
+		area = area.Union(r)
+	}
+	pc := &core.PaintContext{Context: w.ctx, Canvas: canvas}
+	canvas.Save()
+	canvas.Clip(area)
+	if w.partial {
+		canvas.DrawRect(area, core.Filled(theme.From(w.ctx).Colors.Background))
+	}
+	w.root.Paint(pc)
+	w.paintOverlays(pc)
+	canvas.Restore()
+	w.updateInputMethod()
+}
+
+// paintOverlays paints the overlays inside the window, each over its
+// scrim.
+func (w *Window) paintOverlays(pc *core.PaintContext) {
+	client := w.clientRect()
+	for _, o := range w.ctx.Overlays() {
+		if bc, ok := pc.Canvas.(core.BackdropCanvas); ok && !o.ScrimFilter.IsZero() {
+			w.ctx.AddBackdrop(client)
+			bc.DrawBackdrop(client, 0, o.ScrimFilter)
+		}
+		if !o.Scrim.IsTransparent() {
+			pc.Canvas.DrawRect(client, core.Filled(o.Scrim))
+		}
+		if _, hosted := w.popups[o]; !hosted && o.Content != nil {
+			o.Content.Paint(pc)
+		}
+	}
+}
+
+// Damage returns the regions of the window the last Frame changed, in
+// window coordinates: the whole window unless WithPartialRedraw is set.
+// The frame painted the region bounding them, drawing the rest of it as
+// it was. It is empty for a frame that painted nothing.
+func (w *Window) Damage() []core.Rect {
+	return w.damage
+}
+
+// RepaintAll makes the next frame paint the whole window, for platform
+// integrations using WithPartialRedraw whose surface lost its content,
+// such as after it was recreated.
+func (w *Window) RepaintAll() {
+	w.full = true
+	w.ctx.InvalidateRect(w.clientRect())
+}
+
+// maxDamageRects is the most regions a frame reports separately; more are
+// reported as the one region bounding them.
+const maxDamageRects = 4
+
+// mergeDamage appends the regions of rects within client to out, merging
+// overlapping ones, and the whole of client when the region bounding
+// them covers most of it, as the frame paints all of that region.
+func mergeDamage(out, rects []core.Rect, client core.Rect) []core.Rect {
+	for _, r := range rects {
+		r = r.Intersect(client)
+		if r.IsEmpty() {
+			continue
+		}
+		// Merging two regions may make the union overlap one merged
+		// before, so it is merged again until nothing overlaps.
+		for i := 0; i < len(out); {
+			if o := out[i]; !o.Intersect(r).IsEmpty() {
+				r = r.Union(o)
+				out = slices.Delete(out, i, i+1)
+				i = 0
+				continue
+			}
+			i++
+		}
+		out = append(out, r)
+	}
+	if len(out) > maxDamageRects {
+		u := out[0]
+		for _, r := range out[1:] {
+			u = u.Union(r)
+		}
+		out = append(out[:0], u)
+	}
+	if len(out) == 0 {
+		return out
+	}
+	u := out[0]
+	for _, r := range out[1:] {
+		u = u.Union(r)
+	}
+	if u.Width*u.Height > client.Width*client.Height/2 {
+		out = append(out[:0], client)
+	}
+	return out
+}
+
+// updateInputMethod tells the input method whether a text input has
+// focus and where its caret is.
+func (w *Window) updateInputMethod() {
+	if w.im == nil {
+		return
+	}
+	var r core.Rect
+	ti, on := w.ctx.Focused().(core.TextInput)
+	if on = on && w.pathTo(w.ctx.Focused()) != nil; on {
+		r = ti.TextInputRect(w.ctx)
+	}
+	if on != w.imOn || r != w.imRect {
+		w.imOn, w.imRect = on, r
+		w.im.SetTextInput(r, on)
+	}
+}
+
+// PaintOverlay paints an overlay shown through the PopupHost into canvas,
+// whose coordinate system must match the window's.
+func (w *Window) PaintOverlay(o *core.Overlay, canvas core.Canvas) {
+	if !o.IsOpen() || o.Content == nil {
+		return
+	}
+	o.Content.Paint(&core.PaintContext{Context: w.ctx, Canvas: canvas})
+}
+
+// pathTo returns the path to target through the main tree or an overlay.
+func (w *Window) pathTo(target core.Widget) []core.Widget {
+	if target == nil {
+		return nil
+	}
+	overlays := w.ctx.Overlays()
+	for i := len(overlays) - 1; i >= 0; i-- {
+		if path := core.PathTo(overlays[i].Content, target); path != nil {
+			return path
+		}
+	}
+	return core.PathTo(w.root, target)
+}
+
+// hitTest returns the path under p and the index of the overlay it lies
+// in, or -1 for the main tree. Points outside a modal overlay hit nothing
+// and report the layer beneath it, so a press there can light-dismiss it.
+func (w *Window) hitTest(p core.Point) ([]core.Widget, int) {
+	overlays := w.ctx.Overlays()
+	for i := len(overlays) - 1; i >= 0; i-- {
+		o := overlays[i]
+		if c := o.Content; c != nil && !o.Passive {
+			if path := core.HitTest(c, p); len(path) > 0 {
+				return path, i
+			}
+		}
+		if o.Modal {
+			return nil, i - 1
+		}
+	}
+	return core.HitTest(w.root, p), -1
+}
+
+func (w *Window) closePassive() {
+	for _, o := range slices.Clone(w.ctx.Overlays()) {
+		if o.Passive {
+			w.ctx.CloseOverlay(o)
+		}
+	}
+}
+
+// topOverlay returns the topmost overlay that takes input, or nil.
+func (w *Window) topOverlay() *core.Overlay {
+	overlays := w.ctx.Overlays()
+	for i := len(overlays) - 1; i >= 0; i-- {
+		if !overlays[i].Passive {
+			return overlays[i]
+		}
+	}
+	return nil
+}
+
+// modal returns the index of the topmost modal overlay, or -1.
+func (w *Window) modal() int {
+	overlays := w.ctx.Overlays()
+	for i := len(overlays) - 1; i >= 0; i-- {
+		if overlays[i].Modal {
+			return i
+		}
+	}
+	return -1
+}
+
+// focusScope returns the subtree keyboard focus is confined to, the
+// topmost modal overlay's content or else the main tree, and the index of
+// that overlay or -1.
+func (w *Window) focusScope() (core.Widget, int) {
+	if i := w.modal(); i >= 0 {
+		return w.ctx.Overlays()[i].Content, i
+	}
+	return w.root, -1
+}
+
+// lightDismiss closes the light-dismiss overlays above layer that a press
+// at p falls outside of.
+func (w *Window) lightDismiss(layer int, p core.Point) {
+	for {
+		overlays := w.ctx.Overlays()
+		top := len(overlays) - 1
+		for top > layer && overlays[top].Passive {
+			top--
+		}
+		if top <= layer {
+			return
+		}
+		o := overlays[top]
+		if !o.LightDismiss || (o.Owner != nil && o.Owner.Bounds().Contains(p)) {
+			return
+		}
+		w.ctx.CloseOverlay(o)
+	}
+}
+
+// HandleEvent routes ev into the widget tree and its overlays.
+//
+// Pointer events go to the widget holding pointer capture, or else to the
+// deepest widget under the pointer in the topmost overlay or the main tree,
+// and bubble toward the root. A press outside light-dismiss overlays closes
+// them first. Moving the pointer also delivers MouseEnter and MouseLeave to
+// widgets entering or leaving the hovered path. A touch goes to the widget
+// it went down on until it is lifted, as though captured, and every
+// pointer event is then offered to the routes added with
+// core.Context.AddPointerRoute. Drags from other applications go to the
+// target that took them, as event.DragEvent describes; integrations
+// accept the drop where HandleEvent returns Handled for them.
+//
+// Keyboard, text and composition events bubble from the focused widget.
+// Keys nobody handles close the top overlay on Escape if it allows that,
+// move focus on Tab and Shift+Tab, run the command their chord is bound
+// to in the shortcut scopes around the focus, the window or the App, as
+// package shortcuts describes, and are then offered to every
+// core.ShortcutHandler.
+//
+// While a modal overlay is open, nothing beneath it receives input: pointer
+// events outside it are dropped, keys go to its content when focus lies
+// elsewhere, and Tab cycles focus within it.
+func (w *Window) HandleEvent(ev core.Event) core.EventResult {
+	if w.root == nil {
+		return core.Ignored
+	}
+	switch e := ev.(type) {
+	case event.DragEvent:
+		return w.handleDrag(e)
+	case core.PointerEvent:
+		result := w.handlePointer(e)
+		w.ctx.RoutePointer(e)
+		return result
+	case event.KeyEvent, event.TextEvent, event.CompositionEvent:
+		return w.handleKey(ev)
+	default:
+		return core.Dispatch(w.ctx, []core.Widget{w.root}, ev)
+	}
+}
+
+func (w *Window) handlePointer(ev core.PointerEvent) core.EventResult {
+	if c := w.ctx.PointerCapture(); c != nil {
+		if path := w.pathTo(c); path != nil {
+			return core.Dispatch(w.ctx, path, ev)
+		}
+		w.ctx.ReleasePointer()
+	}
+	if te, ok := ev.(event.TouchEvent); ok && te.Type != event.TouchDown {
+		target, ok := w.touch[te.ID]
+		if !ok {
+			return core.Ignored
+		}
+		if te.Type != event.TouchMove {
+			delete(w.touch, te.ID)
+		}
+		return core.Dispatch(w.ctx, w.pathTo(target), ev)
+	}
+	path, layer := w.hitTest(ev.PointerPosition())
+	switch e := ev.(type) {
+	case event.MouseEvent:
+		switch e.Type {
+		case event.MouseDown:
+			w.closePassive()
+			w.lightDismiss(layer, e.Position)
+		case event.MouseMove:
+			w.updateHover(path, e)
+		}
+	case event.TouchEvent:
+		w.closePassive()
+		w.lightDismiss(layer, e.Position)
+		if len(path) > 0 {
+			if w.touch == nil {
+				w.touch = make(map[int]core.Widget)
+			}
+			w.touch[e.ID] = path[len(path)-1]
+		}
+	}
+	return core.Dispatch(w.ctx, path, ev)
+}
+
+// handleDrag routes a drag from another application to its target, as
+// event.DragEvent describes, and reports whether there is one.
+func (w *Window) handleDrag(e event.DragEvent) core.EventResult {
+	if e.Type == event.DragLeave {
+		w.endDrag(e)
+		return core.Ignored
+	}
+	path, _ := w.hitTest(e.Position)
+	t := w.drop
+	if !slices.Contains(path, t) {
+		t = nil
+	}
+	// Widgets deeper than the target, or all without one, are offered the
+	// drag as it comes over them, unless they refused it before.
+	for i := len(path) - 1; i >= 0 && path[i] != t; i-- {
+		if slices.Contains(w.refused, path[i]) {
+			continue
+		}
+		enter := e
+		enter.Type = event.DragEnter
+		if path[i].HandleEvent(w.ctx, enter) == core.Handled {
+			t = path[i]
+			break
+		}
+		w.refused = append(w.refused, path[i])
+	}
+	if t != w.drop {
+		if w.drop != nil {
+			leave := e
+			leave.Type = event.DragLeave
+			w.drop.HandleEvent(w.ctx, leave)
+		}
+		w.drop = t
+	}
+	if t == nil {
+		if e.Type == event.Drop {
+			w.endDrag(e)
+		}
+		return core.Ignored
+	}
+	if e.Type == event.Drop {
+		w.drop, w.refused = nil, nil
+		return t.HandleEvent(w.ctx, e)
+	}
+	over := e
+	over.Type = event.DragOver
+	t.HandleEvent(w.ctx, over)
+	return core.Handled
+}
+
+// endDrag ends a drag from another application without a drop.
+func (w *Window) endDrag(e event.DragEvent) {
+	if w.drop != nil {
+		leave := e
+		leave.Type = event.DragLeave
+		w.drop.HandleEvent(w.ctx, leave)
+	}
+	w.drop, w.refused = nil, nil
+}
+
+func (w *Window) handleKey(ev core.Event) core.EventResult {
+	if ke, ok := ev.(event.KeyEvent); ok && ke.Type == event.KeyPress {
+		w.closePassive()
+	}
+	overlays := w.ctx.Overlays()
+	scope, modal := w.focusScope()
+	path := w.pathTo(w.ctx.Focused())
+	if modal >= 0 && !slices.ContainsFunc(overlays[modal:], func(o *core.Overlay) bool {
+		return core.PathTo(o.Content, w.ctx.Focused()) != nil
+	}) {
+		path = nil
+	}
+	if path == nil {
+		path = []core.Widget{scope}
+	}
+	if core.Dispatch(w.ctx, path, ev) == core.Handled {
+		return core.Handled
+	}
+	ke, ok := ev.(event.KeyEvent)
+	if !ok || ke.Type != event.KeyPress {
+		return core.Ignored
+	}
+	// The handlers may have opened or closed overlays.
+	overlays = w.ctx.Overlays()
+	scope, modal = w.focusScope()
+	switch {
+	case ke.Key == event.KeyEscape:
+		if top := w.topOverlay(); top != nil && (top.LightDismiss || top.CloseOnEscape) {
+			w.ctx.CloseOverlay(top)
+			return core.Handled
+		}
+	case ke.Key == event.KeyTab && (ke.Modifiers == 0 || ke.Modifiers == event.ModShift):
+		if core.FocusNext(w.ctx, scope, ke.Modifiers == event.ModShift) {
+			return core.Handled
+		}
+	}
+	// Chords bound in the scopes around the focus, then in the window
+	// and the application unless a modal overlay holds the focus.
+	regs := shortcuts.Scopes(path)
+	if modal < 0 {
+		regs = append(regs, shortcuts.Of(w.ctx))
+	}
+	if shortcuts.Resolve(ke, regs...) {
+		return core.Handled
+	}
+	result := core.Ignored
+	visit := func(wd core.Widget) bool {
+		if result == core.Handled {
+			return false
+		}
+		if sh, ok := wd.(core.ShortcutHandler); ok {
+			result = sh.HandleShortcut(w.ctx, ev)
+		}
+		return true
+	}
+	for i := len(overlays) - 1; i >= max(modal, 0); i-- {
+		core.Walk(overlays[i].Content, visit)
+	}
+	if modal < 0 {
+		core.Walk(w.root, visit)
+	}
+	return result
+}
+
+// PointerLeft must be called when the pointer leaves the native window so
+// hovered widgets receive MouseLeave.
+func (w *Window) PointerLeft() {
+	w.updateHover(nil, event.MouseEvent{Type: event.MouseLeave, Position: core.Pt(-1, -1)})
+}
+
+func (w *Window) updateHover(path []core.Widget, me event.MouseEvent) {
+	for _, old := range w.hover {
+		if !slices.Contains(path, old) {
+			leave := me
+			leave.Type = event.MouseLeave
+			old.HandleEvent(w.ctx, leave)
+		}
+	}
+	for _, cur := range path {
+		if !slices.Contains(w.hover, cur) {
+			enter := me
+			enter.Type = event.MouseEnter
+			cur.HandleEvent(w.ctx, enter)
+		}
+	}
+	w.hover = path
+}
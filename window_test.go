@@ -0,0 +1,569 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gogpu/ui/core"
+	"github.com/gogpu/ui/dnd"
+	"github.com/gogpu/ui/event"
+	"github.com/gogpu/ui/widgets"
+)
+
+// pane splits its width evenly between its children, paints a rect and
+// records the events it is given.
+type pane struct {
+	core.WidgetBase
+	got []string
+}
+
+func newPane(children ...core.Widget) *pane {
+	p := &pane{}
+	p.SetChildren(children...)
+	return p
+}
+
+func (p *pane) Layout(ctx *core.LayoutContext) core.Size {
+	size := ctx.Constraints.Constrain(core.Sz(ctx.Constraints.MaxWidth, ctx.Constraints.MaxHeight))
+	if n := len(p.Children()); n > 0 {
+		for _, c := range p.Children() {
+			ctx.Measure(c, core.Tight(core.Sz(size.Width/float32(n), size.Height)))
+		}
+	}
+	return size
+}
+
+func (p *pane) SetBounds(r core.Rect) {
+	p.WidgetBase.SetBounds(r)
+	if n := len(p.Children()); n > 0 {
+		w := r.Width / float32(n)
+		for i, c := range p.Children() {
+			c.SetBounds(core.R(r.X+float32(i)*w, r.Y, w, r.Height))
+		}
+	}
+}
+
+func (p *pane) Paint(ctx *core.PaintContext) {
+	ctx.Canvas.DrawRect(p.Bounds(), core.Filled(core.White))
+	core.PaintChildren(ctx, p.Children())
+}
+
+func (p *pane) HandleEvent(_ *core.Context, ev core.Event) core.EventResult {
+	p.got = append(p.got, ev.String())
+	return core.Ignored
+}
+
+func TestWindowFrame(t *testing.T) {
+	a, b := newPane(), newPane()
+	root := newPane(a, b)
+	w := NewWindow(root)
+	w.Resize(core.Sz(200, 100))
+	if !w.NeedsFrame() {
+		t.Error("NeedsFrame() = false before the first frame")
+	}
+	var rec core.Recording
+	w.Frame(&rec)
+	if got, want := b.Bounds(), core.R(100, 0, 100, 100); got != want {
+		t.Errorf("bounds = %v, want %v", got, want)
+	}
+	if rec.Len() == 0 {
+		t.Error("the frame drew nothing")
+	}
+	if w.NeedsFrame() {
+		t.Error("NeedsFrame() = true after a frame")
+	}
+	w.Resize(core.Sz(400, 100))
+	w.Frame(&rec)
+	if got, want := b.Bounds(), core.R(200, 0, 200, 100); got != want {
+		t.Errorf("bounds after Resize = %v, want %v", got, want)
+	}
+}
+
+func TestWindowRoutesPointer(t *testing.T) {
+	tests := []struct {
+		name  string
+		at    core.Point
+		child int
+	}{
+		{"left", core.Pt(50, 50), 0},
+		{"right", core.Pt(150, 50), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kids := []*pane{newPane(), newPane()}
+			root := newPane(kids[0], kids[1])
+			w := NewWindow(root)
+			w.Resize(core.Sz(200, 100))
+			w.Layout()
+			w.HandleEvent(event.MouseEvent{Type: event.MouseDown, Position: tt.at})
+			for j, k := range kids {
+				if hit := len(k.got) > 0; hit != (j == tt.child) {
+					t.Errorf("child %d got %v", j, k.got)
+				}
+			}
+			if len(root.got) == 0 {
+				t.Error("the event did not bubble to the root")
+			}
+		})
+	}
+}
+
+func TestWindowRunsPosted(t *testing.T) {
+	w := NewWindow(newPane())
+	w.Resize(core.Sz(100, 100))
+	w.Frame(&core.Recording{})
+	ran := false
+	w.Context().Post(func() { ran = true })
+	if !w.NeedsFrame() {
+		t.Error("NeedsFrame() = false with a function posted")
+	}
+	w.Frame(&core.Recording{})
+	if !ran {
+		t.Error("the frame did not run the posted function")
+	}
+}
+
+func TestWindowOverlays(t *testing.T) {
+	tests := []struct {
+		name   string
+		ev     core.Event
+		open   bool
+		inside bool // Whether the overlay's content got the event.
+	}{
+		{"press inside", event.MouseEvent{Type: event.MouseDown, Position: core.Pt(60, 60)}, true, true},
+		{"press on the owner", event.MouseEvent{Type: event.MouseDown, Position: core.Pt(10, 10)}, true, false},
+		{"press outside", event.MouseEvent{Type: event.MouseDown, Position: core.Pt(150, 10)}, false, false},
+		{"escape", event.KeyEvent{Type: event.KeyPress, Key: event.KeyEscape}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			owner, other := newPane(), newPane()
+			w := NewWindow(newPane(owner, other))
+			w.Resize(core.Sz(200, 100))
+			w.Layout()
+			content := newPane()
+			o := &core.Overlay{
+				Content:      content,
+				Placement:    func(core.Size, core.Rect) core.Point { return core.Pt(50, 50) },
+				LightDismiss: true,
+				Owner:        owner,
+			}
+			w.Context().ShowOverlay(o)
+			w.Layout()
+			if got := content.Bounds().Origin(); got != core.Pt(50, 50) {
+				t.Fatalf("overlay at %v, want (50, 50)", got)
+			}
+			w.HandleEvent(tt.ev)
+			if o.IsOpen() != tt.open {
+				t.Errorf("IsOpen() = %v, want %v", o.IsOpen(), tt.open)
+			}
+			if got := len(content.got) > 0; got != tt.inside {
+				t.Errorf("the overlay got %v", content.got)
+			}
+		})
+	}
+}
+
+// menuKey is a pane that handles Alt+M as a shortcut.
+type menuKey struct {
+	pane
+	shortcuts int
+}
+
+func (m *menuKey) HandleShortcut(_ *core.Context, ev core.Event) core.EventResult {
+	if e, ok := ev.(event.KeyEvent); ok && e.Key == event.KeyM && e.Modifiers == event.ModAlt {
+		m.shortcuts++
+		return core.Handled
+	}
+	return core.Ignored
+}
+
+func TestWindowShortcutHandlers(t *testing.T) {
+	bar := &menuKey{}
+	focused := newPane()
+	w := NewWindow(newPane(bar, focused))
+	w.Resize(core.Sz(200, 100))
+	w.Layout()
+	w.Context().RequestFocus(focused)
+	alt := func(k event.Key) core.EventResult {
+		return w.HandleEvent(event.KeyEvent{Type: event.KeyPress, Key: k, Modifiers: event.ModAlt})
+	}
+	if got := alt(event.KeyM); got != core.Handled || bar.shortcuts != 1 {
+		t.Errorf("Alt+M = %v, handled %d times", got, bar.shortcuts)
+	}
+	if len(focused.got) == 0 {
+		t.Error("the focused widget was not offered the key first")
+	}
+	if got := alt(event.KeyN); got != core.Ignored {
+		t.Errorf("Alt+N = %v, want Ignored", got)
+	}
+}
+
+func TestWindowPassiveOverlay(t *testing.T) {
+	tests := []struct {
+		name string
+		ev   core.Event
+		open bool
+	}{
+		{"move", event.MouseEvent{Type: event.MouseMove, Position: core.Pt(60, 60)}, true},
+		{"press", event.MouseEvent{Type: event.MouseDown, Position: core.Pt(60, 60)}, false},
+		{"key", event.KeyEvent{Type: event.KeyPress, Key: event.KeyA}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := newPane()
+			w := NewWindow(root)
+			w.Resize(core.Sz(200, 100))
+			content := newPane()
+			o := &core.Overlay{
+				Content:   content,
+				Placement: func(core.Size, core.Rect) core.Point { return core.Pt(50, 50) },
+				Passive:   true,
+			}
+			w.Context().ShowOverlay(o)
+			w.Layout()
+			w.HandleEvent(tt.ev)
+			if o.IsOpen() != tt.open {
+				t.Errorf("IsOpen() = %v, want %v", o.IsOpen(), tt.open)
+			}
+			if len(content.got) > 0 {
+				t.Errorf("the passive overlay got %v", content.got)
+			}
+			if _, ok := tt.ev.(event.MouseEvent); ok && len(root.got) == 0 {
+				t.Error("the event did not pass through to the tree")
+			}
+		})
+	}
+}
+
+// nativeView is a web view that only tracks whether it is shown.
+type nativeView struct{ shown bool }
+
+func (v *nativeView) Show(core.Rect)     { v.shown = true }
+func (v *nativeView) Hide()              { v.shown = false }
+func (v *nativeView) Navigate(string)    {}
+func (v *nativeView) LoadHTML(string)    {}
+func (v *nativeView) Eval(string)        {}
+func (v *nativeView) PostMessage(string) {}
+func (v *nativeView) Close()             {}
+
+type viewHost struct{ view *nativeView }
+
+func (h viewHost) NewWebView(widgets.WebViewEvents) (widgets.NativeWebView, error) {
+	return h.view, nil
+}
+
+func TestWindowHidesUnpaintedWebView(t *testing.T) {
+	view := &nativeView{}
+	wv := widgets.NewWebView("")
+	w := NewWindow(newPane(wv), WithWebViewHost(viewHost{view}))
+	w.Resize(core.Sz(200, 100))
+	w.Frame(&core.Recording{})
+	if !view.shown || wv.Err() != nil {
+		t.Fatalf("the view is not shown after a frame: %v", wv.Err())
+	}
+	w.Frame(&core.Recording{})
+	if !view.shown {
+		t.Error("a second frame hid the view")
+	}
+	w.SetRoot(newPane())
+	w.Frame(&core.Recording{})
+	if view.shown {
+		t.Error("the view stayed shown after leaving the tree")
+	}
+}
+
+type failingVideoHost struct{ err error }
+
+func (h failingVideoHost) OpenVideo(string) (widgets.VideoDecoder, error) {
+	return nil, h.err
+}
+
+func TestWindowVideoHost(t *testing.T) {
+	failed := errors.New("no codec")
+	v := widgets.NewVideo(widgets.VideoSource{URI: "clip.mp4"})
+	w := NewWindow(v, WithVideoHost(failingVideoHost{failed}))
+	w.Resize(core.Sz(200, 100))
+	deadline := time.Now().Add(time.Second)
+	for v.Err() == nil && time.Now().Before(deadline) {
+		w.Frame(&core.Recording{})
+		time.Sleep(time.Millisecond)
+	}
+	if v.Err() != failed {
+		t.Errorf("Err() = %v, want the host's error", v.Err())
+	}
+}
+
+// classPane is a pane that records the size class it is laid out in.
+type classPane struct {
+	pane
+	class core.SizeClass
+}
+
+func (p *classPane) Layout(ctx *core.LayoutContext) core.Size {
+	p.class = ctx.SizeClass()
+	return p.pane.Layout(ctx)
+}
+
+func TestWindowSizeClass(t *testing.T) {
+	root := &classPane{}
+	w := NewWindow(root)
+	tests := []struct {
+		width float32
+		want  core.SizeClass
+	}{
+		{900, core.SizeExpanded},
+		{400, core.SizeCompact},
+		{700, core.SizeMedium},
+	}
+	for _, tt := range tests {
+		w.Resize(core.Sz(tt.width, 100))
+		w.Layout()
+		if root.class != tt.want {
+			t.Errorf("at %v: laid out in %v, want %v", tt.width, root.class, tt.want)
+		}
+	}
+}
+
+// fileTarget returns a drop target of drags from other applications on
+// a pane, logging what it is told as name, which accepts drags of files
+// if files is set and of text otherwise, and counts how often it is
+// asked.
+func fileTarget(log *[]string, name string, files bool, asked *int) *dnd.DropTarget[*event.DragData] {
+	add := func(s string) { *log = append(*log, name+" "+s) }
+	return dnd.NewDropTarget(newPane(), func(d *event.DragData, p core.Point) { add("drop " + d.Files[0]) }).
+		Accept(func(d *event.DragData) bool {
+			*asked++
+			return d.Has(event.FormatFiles) == files
+		}).
+		OnEnter(func(*event.DragData) { add("enter") }).
+		OnLeave(func() { add("leave") })
+}
+
+func TestWindowDrag(t *testing.T) {
+	files := &event.DragData{Formats: []string{event.FormatFiles}, Files: []string{"a.txt"}}
+	drag := func(typ event.DragEventType, x float32) event.DragEvent {
+		return event.DragEvent{Type: typ, Position: core.Pt(x, 50), Data: files}
+	}
+	tests := []struct {
+		name   string
+		events []event.DragEvent
+		want   []string
+		result core.EventResult // Of the last event.
+	}{
+		{
+			"dropped",
+			[]event.DragEvent{drag(event.DragEnter, 50), drag(event.DragOver, 60), drag(event.Drop, 60)},
+			[]string{"files enter", "files leave", "files drop a.txt"},
+			core.Handled,
+		},
+		{
+			"moved to a refusing target",
+			[]event.DragEvent{drag(event.DragEnter, 50), drag(event.DragOver, 150), drag(event.Drop, 150)},
+			[]string{"files enter", "files leave"},
+			core.Ignored,
+		},
+		{
+			"left the window",
+			[]event.DragEvent{drag(event.DragEnter, 50), drag(event.DragLeave, 50)},
+			[]string{"files enter", "files leave"},
+			core.Ignored,
+		},
+		{
+			"over a refusing target",
+			[]event.DragEvent{drag(event.DragEnter, 150), drag(event.DragOver, 160), drag(event.DragOver, 170)},
+			nil,
+			core.Ignored,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var log []string
+			var asked [2]int
+			w := NewWindow(newPane(fileTarget(&log, "files", true, &asked[0]), fileTarget(&log, "text", false, &asked[1])))
+			w.Resize(core.Sz(200, 100))
+			w.Layout()
+			var result core.EventResult
+			for _, e := range tt.events {
+				result = w.HandleEvent(e)
+			}
+			if !slices.Equal(log, tt.want) || result != tt.result {
+				t.Errorf("got %q and %v, want %q and %v", log, result, tt.want, tt.result)
+			}
+			// A target that refused the drag is not asked again.
+			if asked[1] > 1 {
+				t.Errorf("the refusing target was asked %d times", asked[1])
+			}
+		})
+	}
+}
+
+// TestWindowDragAgain checks that a target that refused a drag is asked
+// again about the next.
+func TestWindowDragAgain(t *testing.T) {
+	var log []string
+	var asked int
+	w := NewWindow(newPane(fileTarget(&log, "text", false, &asked)))
+	w.Resize(core.Sz(100, 100))
+	w.Layout()
+	files := &event.DragData{Formats: []string{event.FormatFiles}, Files: []string{"a.txt"}}
+	for range 2 {
+		w.HandleEvent(event.DragEvent{Type: event.DragEnter, Position: core.Pt(50, 50), Data: files})
+		w.HandleEvent(event.DragEvent{Type: event.DragOver, Position: core.Pt(60, 50), Data: files})
+		w.HandleEvent(event.DragEvent{Type: event.DragLeave, Position: core.Pt(60, 50), Data: files})
+	}
+	if asked != 2 || len(log) != 0 {
+		t.Errorf("asked %d times, got %q, want asked once a drag", asked, log)
+	}
+}
+
+// TestWindowDragInApp drags a payload from a source to a target of the
+// window with the mouse.
+func TestWindowDragInApp(t *testing.T) {
+	var got []string
+	src := dnd.NewDragSource(newPane(), func() string { return "card" }).
+		OnDragEnd(func(dropped bool) { got = append(got, fmt.Sprint("end ", dropped)) })
+	tgt := dnd.NewDropTarget(newPane(), func(s string, p core.Point) { got = append(got, "drop "+s) })
+	w := NewWindow(newPane(src, tgt))
+	w.Resize(core.Sz(200, 100))
+	w.Layout()
+	w.HandleEvent(event.MouseEvent{Type: event.MouseDown, Button: event.ButtonLeft, Position: core.Pt(10, 50)})
+	w.HandleEvent(event.MouseEvent{Type: event.MouseMove, Position: core.Pt(60, 50)})
+	if !src.Dragging() || len(w.ctx.Overlays()) != 1 {
+		t.Fatalf("dragging %v with %d overlays, want the preview shown", src.Dragging(), len(w.ctx.Overlays()))
+	}
+	w.HandleEvent(event.MouseEvent{Type: event.MouseMove, Position: core.Pt(150, 50)})
+	if !tgt.Hovered() {
+		t.Error("the target under the drag is not hovered")
+	}
+	w.HandleEvent(event.MouseEvent{Type: event.MouseUp, Button: event.ButtonLeft, Position: core.Pt(150, 50)})
+	if want := []string{"drop card", "end true"}; !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// Escape cancels a drag.
+	got = nil
+	w.HandleEvent(event.MouseEvent{Type: event.MouseDown, Button: event.ButtonLeft, Position: core.Pt(10, 50)})
+	w.HandleEvent(event.MouseEvent{Type: event.MouseMove, Position: core.Pt(150, 50)})
+	w.HandleEvent(event.KeyEvent{Type: event.KeyPress, Key: event.KeyEscape})
+	w.HandleEvent(event.MouseEvent{Type: event.MouseUp, Button: event.ButtonLeft, Position: core.Pt(150, 50)})
+	if want := []string{"end false"}; !slices.Equal(got, want) || src.Dragging() {
+		t.Errorf("cancelled: got %q, want %q", got, want)
+	}
+}
+
+// inputMethod records the calls of the window.
+type inputMethod struct {
+	calls []string
+	rect  core.Rect
+}
+
+func (im *inputMethod) SetTextInput(r core.Rect, on bool) {
+	im.calls = append(im.calls, fmt.Sprint(on))
+	im.rect = r
+}
+
+func TestWindowInputMethod(t *testing.T) {
+	field := widgets.NewTextField()
+	other := newPane()
+	im := &inputMethod{}
+	w := NewWindow(newPane(field, other), WithInputMethod(im))
+	w.Resize(core.Sz(400, 100))
+	frame := func() { w.Frame(&core.Recording{}) }
+	frame()
+	if len(im.calls) != 0 {
+		t.Errorf("calls %v without a text input focused", im.calls)
+	}
+
+	// Focused, a text input turns the input method on at its caret, which
+	// moves as text is composed.
+	w.Context().RequestFocus(field)
+	frame()
+	frame()
+	if !slices.Equal(im.calls, []string{"true"}) || im.rect != field.TextInputRect(w.Context()) {
+		t.Fatalf("calls %v at %v, want on once at the caret", im.calls, im.rect)
+	}
+	caret := im.rect
+	w.HandleEvent(event.CompositionEvent{Type: event.CompositionStart, Text: "かな", Cursor: 2})
+	frame()
+	if len(im.calls) != 2 || im.rect.X <= caret.X || im.rect.Y != caret.Y {
+		t.Errorf("calls %v at %v, want moved past %v", im.calls, im.rect, caret)
+	}
+	w.HandleEvent(event.CompositionEvent{Type: event.CompositionEnd})
+	w.HandleEvent(event.TextEvent{Text: "仮名"})
+	if field.Text() != "仮名" {
+		t.Errorf("text %q, want the commit", field.Text())
+	}
+
+	// Focus elsewhere turns it off.
+	w.Context().RequestFocus(other)
+	frame()
+	if got := im.calls[len(im.calls)-1]; got != "false" || im.rect != (core.Rect{}) {
+		t.Errorf("calls %v at %v, want off", im.calls, im.rect)
+	}
+}
+
+func TestWindowScaleFactor(t *testing.T) {
+	w := NewWindow(newPane(newPane(), newPane()), WithPartialRedraw())
+	w.Resize(core.Sz(201, 101))
+	var rec core.Recording
+	w.Frame(&rec)
+	if x, y := w.DeviceSize(); x != 201 || y != 101 {
+		t.Errorf("device size %dx%d at 1", x, y)
+	}
+
+	// Moved to a monitor of another scale, the window is painted whole at
+	// a size rounded up to device pixels.
+	w.SetScaleFactor(1.25)
+	if !w.NeedsFrame() || w.ScaleFactor() != 1.25 {
+		t.Error("no frame asked for at the new scale")
+	}
+	if x, y := w.DeviceSize(); x != 252 || y != 127 {
+		t.Errorf("device size %dx%d at 1.25, want 252x127", x, y)
+	}
+	w.Frame(&rec)
+	if got := w.Damage(); len(got) != 1 || got[0] != core.R(0, 0, 201, 101) {
+		t.Errorf("damage %v at the new scale, want the whole window", got)
+	}
+	w.SetScaleFactor(1.25)
+	if w.NeedsFrame() {
+		t.Error("the same scale asked for a frame")
+	}
+}
+
+// smoothed is a canvas recording the antialiasing it is given.
+type smoothed struct {
+	core.Recording
+	aa core.Antialiasing
+}
+
+func (c *smoothed) SetAntialiasing(a core.Antialiasing) { c.aa = a }
+
+func TestWindowAntialiasing(t *testing.T) {
+	msaa := core.Antialiasing{Mode: core.AntialiasMSAA, Samples: 8}
+	w := NewWindow(newPane(), WithPartialRedraw(), WithAntialiasing(msaa))
+	w.Resize(core.Sz(100, 100))
+	cv := &smoothed{}
+	w.Frame(cv)
+	if cv.aa != msaa || w.Antialiasing() != msaa {
+		t.Errorf("frame antialiased %+v, want %+v", cv.aa, msaa)
+	}
+
+	// Changed, it paints the whole window again with the new one.
+	none := core.Antialiasing{Mode: core.AntialiasNone}
+	w.SetAntialiasing(none)
+	w.Frame(cv)
+	if cv.aa != none {
+		t.Errorf("frame antialiased %+v, want %+v", cv.aa, none)
+	}
+	if got := w.Damage(); len(got) != 1 || got[0] != core.R(0, 0, 100, 100) {
+		t.Errorf("damage %v, want the whole window", got)
+	}
+	w.SetAntialiasing(none)
+	if w.NeedsFrame() {
+		t.Error("the same antialiasing asked for a frame")
+	}
+}